@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gitsigning signs and verifies the signatures of Git commits and tags
+// the way gittuf does, so that tools such as CI plugins and server-side hooks
+// can check Git signatures without invoking gittuf's CLI. GPG and SSH
+// signatures, Sigstore signatures issued by gitsign, and S/MIME signatures are
+// supported. The API is experimental and may change between releases.
+package gitsigning
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+var (
+	// ErrVerificationFailed indicates that a signature wasn't issued by the
+	// key it was verified with.
+	ErrVerificationFailed = gitinterface.ErrIncorrectVerificationKey
+
+	// ErrUnknownSigningMethod indicates that the signature format or the
+	// key's type isn't supported.
+	ErrUnknownSigningMethod = gitinterface.ErrUnknownSigningMethod
+
+	// ErrUnableToSign indicates that the signing program or agent didn't
+	// return a signature.
+	ErrUnableToSign = gitinterface.ErrUnableToSign
+)
+
+const gpgPublicKeyHeader = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+
+// Key is a key that Git signatures are verified with, as declared in gittuf
+// policy metadata. Sigstore and S/MIME keys identify the signer instead of
+// containing a public key.
+type Key struct {
+	key *tuf.Key
+}
+
+// LoadKey loads the key specified like keys added to gittuf policy metadata
+// using the CLI: the path to a PEM encoded or SSH public key, or a key
+// prefixed with "gpg:", "gpg-agent:", "ssh-agent:", "fulcio:", or "smime:",
+// such as "fulcio:jane.doe@example.com::https://github.com/login/oauth".
+func LoadKey(spec string) (*Key, error) {
+	key, err := common.LoadPublicKey(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{key: key}, nil
+}
+
+// ParseKey parses an armored GPG public key, a PEM encoded public key, or a
+// key serialized as in gittuf policy metadata.
+func ParseKey(data []byte) (*Key, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(gpgPublicKeyHeader)) {
+		key, err := gpg.LoadGPGKeyFromBytes(data)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Key{key: key}, nil
+	}
+
+	key, err := tuf.LoadKeyFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{key: key}, nil
+}
+
+// ID returns the key's ID in gittuf policy metadata.
+func (k *Key) ID() string {
+	return k.key.KeyID
+}
+
+// Type returns the key's type, such as "gpg", "ed25519", "sigstore-oidc", or
+// "smime".
+func (k *Key) Type() string {
+	return k.key.KeyType
+}
+
+// SignOptions configure Sign. Options that aren't set fall back to the user's
+// Git config, like when gittuf signs RSL entries.
+type SignOptions struct {
+	// Format is the format of the signature, one of "gpg", "ssh", or "x509",
+	// like Git's gpg.format option.
+	Format string
+
+	// Key is the signing key, like Git's user.signingKey option. Keys held in
+	// the SSH agent or by gpg-agent may be selected using the "ssh-agent:"
+	// and "gpg-agent:" prefixes.
+	Key string
+
+	// Program is the signing program, like Git's gpg.program option and its
+	// per-format variants.
+	Program string
+
+	// PrivateKey is a PEM encoded GPG or SSH private key that's used to sign
+	// without invoking a signing program or reading the user's Git config. If
+	// it's set, the other options are ignored.
+	PrivateKey []byte
+}
+
+// Sign signs the contents of a Git commit or tag, which exclude the signature,
+// and returns the armored signature to embed in the object's gpgsig header or
+// append to the tag's message.
+func Sign(contents []byte, opts SignOptions) ([]byte, error) {
+	signature, err := gitinterface.SignGitObject(contents, gitinterface.SigningOptions{
+		Format:     opts.Format,
+		Key:        opts.Key,
+		Program:    opts.Program,
+		PrivateKey: opts.PrivateKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(signature), nil
+}
+
+// Verify verifies the signature of the contents of a Git commit or tag, which
+// exclude the signature, using the key. A nil error means the signature was
+// issued by the key. Errors wrap ErrVerificationFailed if it wasn't. Sigstore
+// signatures are verified using Sigstore's public services.
+func Verify(ctx context.Context, key *Key, contents, signature []byte) error {
+	return gitinterface.VerifySignature(ctx, key.key, contents, signature)
+}
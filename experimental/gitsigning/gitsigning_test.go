@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitsigning
+
+import (
+	"context"
+	"testing"
+
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	contents := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nInitial commit\n")
+
+	tests := map[string]struct {
+		privateKey []byte
+		publicKey  []byte
+		keyType    string
+	}{
+		"GPG key": {
+			privateKey: artifacts.GPGKey1Private,
+			publicKey:  artifacts.GPGKey1Public,
+			keyType:    "gpg",
+		},
+		"SSH key": {
+			privateKey: artifacts.SSHED25519Private,
+			publicKey:  artifacts.SSHED25519Public,
+			keyType:    "ed25519",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, err := ParseKey(test.publicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.keyType, key.Type())
+			assert.NotEmpty(t, key.ID())
+
+			signature, err := Sign(contents, SignOptions{PrivateKey: test.privateKey})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Nil(t, Verify(context.Background(), key, contents, signature))
+
+			err = Verify(context.Background(), key, []byte("modified contents"), signature)
+			assert.ErrorIs(t, err, ErrVerificationFailed)
+		})
+	}
+
+	t.Run("signature by another key", func(t *testing.T) {
+		key, err := ParseKey(artifacts.SSHED25519Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signature, err := Sign(contents, SignOptions{PrivateKey: artifacts.GPGKey1Private})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = Verify(context.Background(), key, contents, signature)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+	})
+}
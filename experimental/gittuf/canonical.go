@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gittuf
+
+import "github.com/gittuf/gittuf/internal/canonicaljson"
+
+// CanonicalizeJSON returns the canonical serialization of the JSON document,
+// which is the form gittuf signs policy metadata and attestations in. The
+// serialization follows the JSON Canonicalization Scheme (RFC 8785), and
+// documents that contain duplicate object members or integers that can't be
+// represented exactly by an IEEE 754 double are rejected.
+func CanonicalizeJSON(data []byte) ([]byte, error) {
+	return canonicaljson.Canonicalize(data)
+}
+
+// MarshalCanonicalJSON serializes v using encoding/json and returns its
+// canonical serialization. See CanonicalizeJSON.
+func MarshalCanonicalJSON(v any) ([]byte, error) {
+	return canonicaljson.Marshal(v)
+}
@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gittuf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalizeJSON(t *testing.T) {
+	canonical, err := CanonicalizeJSON([]byte(`{"version": 1, "type": "root", "message": "<ok>"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"message":"<ok>","type":"root","version":1}`, string(canonical))
+
+	_, err = CanonicalizeJSON([]byte(`{"a": 1, "a": 2}`))
+	assert.NotNil(t, err)
+}
+
+func TestMarshalCanonicalJSON(t *testing.T) {
+	canonical, err := MarshalCanonicalJSON(struct {
+		Version int    `json:"version"`
+		Type    string `json:"type"`
+	}{Version: 1, Type: "root"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"root","version":1}`, string(canonical))
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gittuf
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
+)
+
+// The errors below identify the conditions that cause gittuf operations to
+// fail. Errors returned by the API wrap them, so they must be checked using
+// errors.Is rather than by comparing errors directly. An error may wrap more
+// than one of them: a change signed by a key that isn't in the policy wraps
+// both ErrUnauthorizedSigner and ErrUnknownKey.
+var (
+	// ErrUnauthorizedSigner indicates that a change was not signed by the
+	// keys the policy trusts for it.
+	ErrUnauthorizedSigner = policy.ErrUnauthorizedSignature
+
+	// ErrUnsignedObject indicates that a commit or tag that must be signed
+	// was not signed.
+	ErrUnsignedObject = policy.ErrUnsignedObject
+
+	// ErrUnknownKey indicates that a commit or tag was signed by a key that
+	// isn't declared anywhere in the policy.
+	ErrUnknownKey = policy.ErrUnknownSigningKey
+
+	// ErrMetadataExpired indicates that the metadata of a role in the policy
+	// has expired. Errors wrapping it are of type *MetadataExpiredError.
+	ErrMetadataExpired = errors.New("policy metadata has expired")
+
+	// ErrRSLDiverged indicates that the local and remote RSLs have diverged,
+	// so that neither contains the other.
+	ErrRSLDiverged = repository.ErrRSLDiverged
+
+	// ErrRSLBranchDetected indicates that the RSL is not linear, which may
+	// be caused by a remote serving diverging RSLs to different clients.
+	ErrRSLBranchDetected = rsl.ErrRSLBranchDetected
+
+	// ErrPolicyNotFound indicates that the repository has no gittuf policy.
+	ErrPolicyNotFound = policy.ErrPolicyNotFound
+)
+
+// MetadataExpiredError is returned when the metadata of a role in the policy
+// has expired. It wraps ErrMetadataExpired.
+type MetadataExpiredError struct {
+	// Role is the name of the role whose metadata has expired, such as
+	// "root" or "targets".
+	Role string
+
+	// Expires is the expiry date of the role's metadata.
+	Expires time.Time
+}
+
+func (e *MetadataExpiredError) Error() string {
+	return fmt.Sprintf("%s: metadata of role '%s' expired on %s", ErrMetadataExpired.Error(), e.Role, e.Expires.Format(time.RFC3339))
+}
+
+func (e *MetadataExpiredError) Unwrap() error {
+	return ErrMetadataExpired
+}
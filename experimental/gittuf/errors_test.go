@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gittuf
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	t.Run("internal errors match exported errors", func(t *testing.T) {
+		err := fmt.Errorf("verifying Git namespace policies failed, %w: %w", policy.ErrUnauthorizedSignature, policy.ErrUnknownSigningKey)
+		assert.ErrorIs(t, err, ErrUnauthorizedSigner)
+		assert.ErrorIs(t, err, ErrUnknownKey)
+		assert.False(t, errors.Is(err, ErrUnsignedObject))
+	})
+
+	t.Run("metadata expired", func(t *testing.T) {
+		expires := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		var err error = &MetadataExpiredError{Role: "targets", Expires: expires}
+
+		assert.ErrorIs(t, err, ErrMetadataExpired)
+		assert.Equal(t, "policy metadata has expired: metadata of role 'targets' expired on 2024-01-01T00:00:00Z", err.Error())
+
+		var expiredErr *MetadataExpiredError
+		assert.True(t, errors.As(fmt.Errorf("verifying: %w", err), &expiredErr))
+		assert.Equal(t, "targets", expiredErr.Role)
+	})
+}
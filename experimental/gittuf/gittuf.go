@@ -0,0 +1,327 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gittuf exposes gittuf's verification, policy inspection, RSL, and
+// attestation operations to Go programs that embed gittuf instead of invoking
+// its CLI. The API is experimental and may change between releases.
+package gittuf
+
+import (
+	"context"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// DefaultPolicyRef is the policy ref inspected by default, containing the
+// policy that's applied in the repository. Pass "policy-staging" to inspect
+// changes that haven't been applied yet.
+const DefaultPolicyRef = "policy"
+
+// Repository is a Git repository that uses gittuf.
+type Repository struct {
+	r *repository.Repository
+}
+
+// LoadRepository returns the repository at the specified path, which may be a
+// bare repository or any directory in a repository's working tree.
+func LoadRepository(path string) (*Repository, error) {
+	r, err := repository.LoadRepositoryAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repository{r: r}, nil
+}
+
+// VerifyRefOption configures VerifyRef.
+type VerifyRefOption func(*verifyRefOptions)
+
+type verifyRefOptions struct {
+	latestOnly  bool
+	checkExpiry bool
+	explanation *Explanation
+}
+
+// WithLatestOnly limits verification to the latest RSL entry for the ref,
+// rather than every entry since the ref was first recorded.
+func WithLatestOnly() VerifyRefOption {
+	return func(o *verifyRefOptions) {
+		o.latestOnly = true
+	}
+}
+
+// WithExpiryCheck additionally requires the metadata of the roles in the
+// policy applied in the repository to not have expired. If a role's metadata
+// has expired, a *MetadataExpiredError is returned.
+func WithExpiryCheck() VerifyRefOption {
+	return func(o *verifyRefOptions) {
+		o.checkExpiry = true
+	}
+}
+
+// WithExplanation records the RSL entries that failed verification, and the
+// signature checks performed for each, in the specified explanation.
+func WithExplanation(explanation *Explanation) VerifyRefOption {
+	return func(o *verifyRefOptions) {
+		o.explanation = explanation
+	}
+}
+
+// Explanation describes why a ref failed verification.
+type Explanation struct {
+	// FailedEntries contains the RSL entries that failed verification, in
+	// the order they were verified.
+	FailedEntries []*EntryExplanation
+}
+
+// EntryExplanation describes an RSL entry that failed verification.
+type EntryExplanation struct {
+	RefName  string
+	EntryID  string
+	TargetID string
+	Error    string
+
+	// Checks contains the signature checks performed for the entry.
+	Checks []*CheckExplanation
+}
+
+// CheckExplanation describes the signature check performed for a Git object
+// in a protected namespace, such as "git:refs/heads/main" or "file:README.md".
+type CheckExplanation struct {
+	Namespace string
+	ObjectID  string
+	Signature string
+
+	// AttestationKeyIDs contains the key IDs of the signatures on the
+	// reference authorization found for the change, if any.
+	AttestationKeyIDs []string
+
+	// Rules contains the rules that protect the namespace, in the order they
+	// were evaluated.
+	Rules []*RuleExplanation
+}
+
+// RuleExplanation describes the evaluation of a rule. Rejection explains why
+// the rule's conditions weren't met, and is empty if they were.
+type RuleExplanation struct {
+	Name      string
+	KeyIDs    []string
+	Threshold int
+	Rejection string
+}
+
+// VerifyRef verifies the ref against the repository's gittuf policy. The ref
+// may be specified using its absolute name, such as refs/heads/main, or its
+// short name. A nil error means the ref was verified. Errors wrap the
+// exported error variables, such as ErrUnauthorizedSigner, identifying why
+// verification failed.
+func (r *Repository) VerifyRef(ctx context.Context, refName string, opts ...VerifyRefOption) error {
+	options := &verifyRefOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	if err := r.verifyRef(ctx, refName, options); err != nil {
+		return err
+	}
+
+	if options.checkExpiry {
+		return r.checkExpiry(ctx)
+	}
+
+	return nil
+}
+
+func (r *Repository) verifyRef(ctx context.Context, refName string, options *verifyRefOptions) error {
+	if options.explanation == nil {
+		return r.r.VerifyRef(ctx, refName, options.latestOnly)
+	}
+
+	explanation := &policy.Explanation{}
+	err := r.r.VerifyRef(policy.ContextWithExplanation(ctx, explanation), refName, options.latestOnly)
+
+	options.explanation.FailedEntries = nil
+	for _, entry := range explanation.FailedEntries {
+		entryExplanation := &EntryExplanation{
+			RefName:  entry.RefName,
+			EntryID:  entry.EntryID,
+			TargetID: entry.TargetID,
+			Error:    entry.Error,
+		}
+		for _, check := range entry.Checks {
+			checkExplanation := &CheckExplanation{
+				Namespace:         check.Namespace,
+				ObjectID:          check.ObjectID,
+				Signature:         check.Signature,
+				AttestationKeyIDs: check.AttestationKeyIDs,
+			}
+			for _, rule := range check.Rules {
+				checkExplanation.Rules = append(checkExplanation.Rules, &RuleExplanation{
+					Name:      rule.Name,
+					KeyIDs:    rule.KeyIDs,
+					Threshold: rule.Threshold,
+					Rejection: rule.Rejection,
+				})
+			}
+			entryExplanation.Checks = append(entryExplanation.Checks, checkExplanation)
+		}
+		options.explanation.FailedEntries = append(options.explanation.FailedEntries, entryExplanation)
+	}
+
+	return err
+}
+
+// checkExpiry returns a *MetadataExpiredError if the metadata of a role in the
+// applied policy has expired.
+func (r *Repository) checkExpiry(ctx context.Context) error {
+	roles, err := r.ListPolicyRoles(ctx, DefaultPolicyRef)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		if !role.Expires.IsZero() && role.Expires.Before(time.Now()) {
+			return &MetadataExpiredError{Role: role.Name, Expires: role.Expires}
+		}
+	}
+
+	return nil
+}
+
+// PolicyRole describes the root of trust or a top level role in the policy.
+type PolicyRole struct {
+	Name      string
+	KeyIDs    []string
+	Threshold int
+
+	// Expires is the expiry date of the role's metadata. It is zero if the
+	// role's metadata hasn't been initialized.
+	Expires time.Time
+}
+
+// ListPolicyRoles returns the root of trust and the top level roles declared
+// in the policy at the specified policy ref, such as DefaultPolicyRef.
+func (r *Repository) ListPolicyRoles(ctx context.Context, policyRef string) ([]*PolicyRole, error) {
+	roles, err := r.r.ListPolicyRoles(ctx, policyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	policyRoles := make([]*PolicyRole, 0, len(roles))
+	for _, role := range roles {
+		policyRoles = append(policyRoles, &PolicyRole{
+			Name:      role.Name,
+			KeyIDs:    role.KeyIDs,
+			Threshold: role.Threshold,
+			Expires:   role.Expires,
+		})
+	}
+
+	return policyRoles, nil
+}
+
+// Rule is a rule in the policy, protecting Git refs or files.
+type Rule struct {
+	Name  string
+	Paths []string
+
+	// KeyIDs are the keys authorized to make changes protected by the rule,
+	// of which Threshold must sign a change for it to be authorized.
+	KeyIDs    []string
+	Threshold int
+
+	// Depth is the rule's delegation depth, with zero for rules declared in
+	// the primary rule file.
+	Depth int
+}
+
+// ListRules returns the rules in the policy at the specified policy ref, such
+// as DefaultPolicyRef, in the order they're evaluated.
+func (r *Repository) ListRules(ctx context.Context, policyRef string) ([]*Rule, error) {
+	delegations, err := r.r.ListRules(ctx, policyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*Rule, 0, len(delegations))
+	for _, delegation := range delegations {
+		rules = append(rules, &Rule{
+			Name:      delegation.Delegation.Name,
+			Paths:     delegation.Delegation.Paths,
+			KeyIDs:    delegation.Delegation.KeyIDs,
+			Threshold: delegation.Delegation.Threshold,
+			Depth:     delegation.Depth,
+		})
+	}
+
+	return rules, nil
+}
+
+// RSLEntry is an entry in the repository's reference state log (RSL). Entries
+// either record the state of a ref, or annotate earlier entries.
+type RSLEntry struct {
+	ID string
+
+	// RefName and TargetID are set for entries recording the state of a
+	// ref.
+	RefName  string
+	TargetID string
+
+	// AnnotatedEntryIDs, Skip, and Message are set for annotations. Skip
+	// indicates the annotated entries must not be used.
+	AnnotatedEntryIDs []string
+	Skip              bool
+	Message           string
+}
+
+// IsAnnotation returns true if the entry annotates earlier entries.
+func (e *RSLEntry) IsAnnotation() bool {
+	return e.RefName == ""
+}
+
+// ListRSLEntries returns up to limit entries of the RSL, starting with the
+// latest one. All entries are returned if limit is not positive.
+func (r *Repository) ListRSLEntries(limit int) ([]*RSLEntry, error) {
+	entries, err := r.r.ListRSLEntries(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	rslEntries := make([]*RSLEntry, 0, len(entries))
+	for _, entry := range entries {
+		switch entry := entry.(type) {
+		case *rsl.ReferenceEntry:
+			rslEntries = append(rslEntries, &RSLEntry{
+				ID:       entry.ID.String(),
+				RefName:  entry.RefName,
+				TargetID: entry.TargetID.String(),
+			})
+		case *rsl.AnnotationEntry:
+			rslEntry := &RSLEntry{ID: entry.ID.String(), Skip: entry.Skip, Message: entry.Message}
+			for _, id := range entry.RSLEntryIDs {
+				rslEntry.AnnotatedEntryIDs = append(rslEntry.AnnotatedEntryIDs, id.String())
+			}
+			rslEntries = append(rslEntries, rslEntry)
+		}
+	}
+
+	return rslEntries, nil
+}
+
+// ListAttestations returns the DSSE envelopes of the repository's current
+// attestations, keyed by their paths in the attestations namespace.
+func (r *Repository) ListAttestations() (map[string]*sslibdsse.Envelope, error) {
+	return r.r.ListAttestations()
+}
+
+// Approve records the signer's approval of merging the feature ref into the
+// target ref as a reference authorization attestation. If the target ref is a
+// tag, the feature ref is the revision the tag is expected to point to. If
+// expiresIn is non-zero, it limits how long a newly created authorization can
+// be used for.
+func (r *Repository) Approve(ctx context.Context, signer sslibdsse.SignerVerifier, targetRef, featureRef string, expiresIn time.Duration, signCommit bool) error {
+	return r.r.Approve(ctx, signer, targetRef, featureRef, expiresIn, signCommit)
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gittuf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRepository(t *testing.T) {
+	_, err := LoadRepository(t.TempDir())
+	assert.ErrorIs(t, err, git.ErrRepositoryNotExists)
+
+	tmpDir := t.TempDir()
+	if _, err := git.PlainInit(tmpDir, false); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := LoadRepository(tmpDir)
+	assert.Nil(t, err)
+	assert.NotNil(t, repo)
+}
+
+func TestListRSLEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	r, err := git.PlainInit(tmpDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := LoadRepository(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := repo.ListRSLEntries(0)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	targetID := plumbing.NewHash("8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f")
+	if err := rsl.NewReferenceEntry("refs/heads/main", targetID).Commit(r, false); err != nil {
+		t.Fatal(err)
+	}
+	latestEntry, err := rsl.GetLatestEntry(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.NewAnnotationEntry([]plumbing.Hash{latestEntry.GetID()}, true, "revoked").Commit(r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = repo.ListRSLEntries(0)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+
+	assert.True(t, entries[0].IsAnnotation())
+	assert.Equal(t, []string{latestEntry.GetID().String()}, entries[0].AnnotatedEntryIDs)
+	assert.True(t, entries[0].Skip)
+	assert.Equal(t, "revoked", entries[0].Message)
+
+	assert.False(t, entries[1].IsAnnotation())
+	assert.Equal(t, "refs/heads/main", entries[1].RefName)
+	assert.Equal(t, targetID.String(), entries[1].TargetID)
+
+	entries, err = repo.ListRSLEntries(1)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestVerifyRefWithoutPolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := git.PlainInit(tmpDir, false); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := LoadRepository(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	explanation := &Explanation{}
+	err = repo.VerifyRef(context.Background(), "refs/heads/main", WithLatestOnly(), WithExplanation(explanation))
+	assert.NotNil(t, err)
+	assert.Empty(t, explanation.FailedEntries)
+
+	attestations, err := repo.ListAttestations()
+	assert.Nil(t, err)
+	assert.Empty(t, attestations)
+}
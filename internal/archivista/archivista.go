@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package archivista
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	uploadEndpoint   = "upload"
+	downloadEndpoint = "download"
+	queryEndpoint    = "query"
+
+	subjectDigestQuery = `query($digest: String!) {
+  dsses(where: {hasStatementWith: {hasSubjectsWith: {hasSubjectDigestsWith: {value: $digest}}}}) {
+    edges {
+      node {
+        gitoidSha256
+      }
+    }
+  }
+}`
+)
+
+var (
+	ErrUnexpectedResponse = errors.New("unexpected response from Archivista")
+	ErrInvalidEnvelope    = errors.New("envelope stored in Archivista is not a valid DSSE envelope")
+)
+
+type contextKey struct{}
+
+// Client is used to interact with an Archivista instance, which stores in-toto
+// attestations wrapped in DSSE envelopes. Each stored envelope is identified
+// using its gitoid.
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client for the Archivista instance at the specified URL.
+func NewClient(archivistaURL string) *Client {
+	return &Client{
+		url:        strings.TrimSuffix(archivistaURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+}
+
+// ContextWithClient returns a copy of the context that carries the specified
+// client. This is used to make an Archivista instance available during
+// verification.
+func ContextWithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, contextKey{}, client)
+}
+
+// ClientFromContext returns the client carried by the context, if any.
+func ClientFromContext(ctx context.Context) *Client {
+	client, ok := ctx.Value(contextKey{}).(*Client)
+	if !ok {
+		return nil
+	}
+
+	return client
+}
+
+// URL returns the location of the Archivista instance.
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Store uploads the envelope to Archivista and returns its gitoid.
+func (c *Client) Store(ctx context.Context, env *sslibdsse.Envelope) (string, error) {
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return "", err
+	}
+
+	responseBytes, err := c.do(ctx, http.MethodPost, uploadEndpoint, envBytes)
+	if err != nil {
+		return "", err
+	}
+
+	response := struct {
+		Gitoid string `json:"gitoid"`
+	}{}
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return "", errors.Join(ErrUnexpectedResponse, err)
+	}
+
+	return response.Gitoid, nil
+}
+
+// Download fetches the envelope identified by the gitoid from Archivista.
+func (c *Client) Download(ctx context.Context, gitoid string) (*sslibdsse.Envelope, error) {
+	responseBytes, err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%s", downloadEndpoint, url.PathEscape(gitoid)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(responseBytes, env); err != nil {
+		return nil, errors.Join(ErrInvalidEnvelope, err)
+	}
+
+	return env, nil
+}
+
+// SearchBySubjectDigest returns the gitoids of all envelopes stored in
+// Archivista whose statement has a subject with the specified digest value.
+func (c *Client) SearchBySubjectDigest(ctx context.Context, digest string) ([]string, error) {
+	query := map[string]any{
+		"query":     subjectDigestQuery,
+		"variables": map[string]string{"digest": digest},
+	}
+	queryBytes, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBytes, err := c.do(ctx, http.MethodPost, queryEndpoint, queryBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	response := struct {
+		Data struct {
+			Dsses struct {
+				Edges []struct {
+					Node struct {
+						GitoidSha256 string `json:"gitoidSha256"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"dsses"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{}
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return nil, errors.Join(ErrUnexpectedResponse, err)
+	}
+
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedResponse, response.Errors[0].Message)
+	}
+
+	gitoids := make([]string, 0, len(response.Data.Dsses.Edges))
+	for _, edge := range response.Data.Dsses.Edges {
+		gitoids = append(gitoids, edge.Node.GitoidSha256)
+	}
+
+	return gitoids, nil
+}
+
+func (c *Client) do(ctx context.Context, method, endpoint string, body []byte) ([]byte, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", c.url, endpoint), bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	responseBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d: %s", ErrUnexpectedResponse, response.StatusCode, strings.TrimSpace(string(responseBytes)))
+	}
+
+	return responseBytes, nil
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package archivista
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	stored := map[string][]byte{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/upload":
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stored["test-gitoid"] = body
+			w.Write([]byte(`{"gitoid": "test-gitoid"}`)) //nolint:errcheck
+		case r.Method == http.MethodGet && r.URL.Path == "/download/test-gitoid":
+			w.Write(stored["test-gitoid"]) //nolint:errcheck
+		case r.Method == http.MethodPost && r.URL.Path == "/query":
+			query := struct {
+				Variables map[string]string `json:"variables"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+				t.Fatal(err)
+			}
+			if query.Variables["digest"] == "abcdef" {
+				w.Write([]byte(`{"data": {"dsses": {"edges": [{"node": {"gitoidSha256": "test-gitoid"}}]}}}`)) //nolint:errcheck
+			} else {
+				w.Write([]byte(`{"data": {"dsses": {"edges": []}}}`)) //nolint:errcheck
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(server.URL + "/")
+	assert.Equal(t, server.URL, client.URL())
+
+	env := &sslibdsse.Envelope{
+		PayloadType: "application/vnd.in-toto+json",
+		Payload:     "e30=",
+		Signatures:  []sslibdsse.Signature{},
+	}
+
+	gitoid, err := client.Store(ctx, env)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-gitoid", gitoid)
+
+	downloadedEnv, err := client.Download(ctx, gitoid)
+	assert.Nil(t, err)
+	assert.Equal(t, env, downloadedEnv)
+
+	gitoids, err := client.SearchBySubjectDigest(ctx, "abcdef")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"test-gitoid"}, gitoids)
+
+	gitoids, err = client.SearchBySubjectDigest(ctx, "012345")
+	assert.Nil(t, err)
+	assert.Empty(t, gitoids)
+
+	_, err = client.Download(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrUnexpectedResponse)
+}
+
+func TestContextWithClient(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, ClientFromContext(ctx))
+
+	client := NewClient("http://localhost:8082")
+	ctx = ContextWithClient(ctx, client)
+	assert.Equal(t, client, ClientFromContext(ctx))
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+
+	"github.com/gittuf/gittuf/internal/archivista"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// Envelopes returns all the attestations tracked in the current state. The
+// key for each envelope is its path in the attestations namespace.
+func (a *Attestations) Envelopes(repo *git.Repository) (map[string]*sslibdsse.Envelope, error) {
+	allContents, err := a.EnvelopeContents(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	envelopes := map[string]*sslibdsse.Envelope{}
+	for envPath, envBytes := range allContents {
+		env := &sslibdsse.Envelope{}
+		if err := json.Unmarshal(envBytes, env); err != nil {
+			return nil, err
+		}
+
+		envelopes[envPath] = env
+	}
+
+	return envelopes, nil
+}
+
+// EnvelopeContents returns the contents of all the attestations tracked in the
+// current state, keyed by their paths in the attestations namespace as with
+// Envelopes. The contents are only decompressed and not parsed, so that they
+// can be inspected even if they are not valid envelopes.
+func (a *Attestations) EnvelopeContents(repo *git.Repository) (map[string][]byte, error) {
+	contents := map[string][]byte{}
+
+	for treeName, blobIDs := range map[string]map[string]plumbing.Hash{
+		referenceAuthorizationsTreeEntryName:       a.referenceAuthorizations,
+		githubPullRequestAttestationsTreeEntryName: a.githubPullRequestAttestations,
+		gerritChangeAttestationsTreeEntryName:      a.gerritChangeAttestations,
+		bitbucketPullRequestsTreeEntryName:         a.bitbucketPullRequestAttestations,
+		reviewApprovalsTreeEntryName:               a.reviewApprovalAttestations,
+		pushEventAttestationsTreeEntryName:         a.pushEventAttestations,
+		ciRunAttestationsTreeEntryName:             a.ciRunAttestations,
+		hookExecutionAttestationsTreeEntryName:     a.hookExecutionAttestations,
+		rebuildAttestationsTreeEntryName:           a.rebuildAttestations,
+		lfsObjectsAttestationsTreeEntryName:        a.lfsObjectsAttestations,
+		statementsTreeEntryName:                    a.statementAttestations,
+		policyJustificationsTreeEntryName:          a.policyJustifications,
+		tombstonesTreeEntryName:                    a.tombstones,
+	} {
+		for blobPath, blobID := range blobIDs {
+			envBytes, err := readBlob(repo, blobID)
+			if err != nil {
+				return nil, err
+			}
+
+			contents[path.Join(treeName, blobPath)] = envBytes
+		}
+	}
+
+	return contents, nil
+}
+
+// FetchReferenceAuthorizationFromArchivista searches the Archivista instance
+// for a reference authorization matching the specified parameters. Anyone can
+// store envelopes for a subject, so envelopes that can't be decoded or whose
+// contents do not match the parameters are ignored, and only errors fetching
+// envelopes are returned. If more than one matching envelope is found, their
+// signatures are combined.
+func FetchReferenceAuthorizationFromArchivista(ctx context.Context, client *archivista.Client, refName, fromRevisionID, targetID string) (*sslibdsse.Envelope, error) {
+	gitoids, err := client.SearchBySubjectDigest(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var authorization *sslibdsse.Envelope
+	for _, gitoid := range gitoids {
+		env, err := client.Download(ctx, gitoid)
+		if err != nil {
+			if errors.Is(err, archivista.ErrInvalidEnvelope) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if err := validateReferenceAuthorization(env, refName, fromRevisionID, targetID); err != nil {
+			// Not the authorization we're looking for, this could be some
+			// other attestation for the same subject or a malformed one
+			continue
+		}
+
+		if authorization == nil {
+			authorization = env
+			continue
+		}
+
+		if authorization.Payload == env.Payload {
+			authorization.Signatures = append(authorization.Signatures, env.Signatures...)
+		}
+	}
+
+	if authorization == nil {
+		return nil, ErrAuthorizationNotFound
+	}
+
+	return authorization, nil
+}
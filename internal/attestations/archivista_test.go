@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/archivista"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelopes(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	mainZeroZero := createReferenceAuthorizationAttestationEnvelopes(t, testRef, testID, testID)
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+	if err := attestations.SetReferenceAuthorization(repo, mainZeroZero, testRef, testID, testID); err != nil {
+		t.Fatal(err)
+	}
+
+	envelopes, err := attestations.Envelopes(repo)
+	assert.Nil(t, err)
+	assert.Len(t, envelopes, 1)
+	assert.Equal(t, mainZeroZero, envelopes[referenceAuthorizationsTreeEntryName+"/"+ReferenceAuthorizationPath(testRef, testID, testID)])
+}
+
+func TestFetchReferenceAuthorizationFromArchivista(t *testing.T) {
+	testRef := "refs/heads/main"
+	testAnotherRef := "refs/heads/feature"
+	testID := plumbing.ZeroHash.String()
+	mainZeroZero := createReferenceAuthorizationAttestationEnvelopes(t, testRef, testID, testID)
+	featureZeroZero := createReferenceAuthorizationAttestationEnvelopes(t, testAnotherRef, testID, testID)
+
+	undecodable := &sslibdsse.Envelope{PayloadType: "application/vnd.in-toto+json", Payload: "not base64"}
+
+	stored := map[string]any{"main": mainZeroZero, "feature": featureZeroZero, "undecodable": undecodable}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/query":
+			w.Write([]byte(`{"data": {"dsses": {"edges": [{"node": {"gitoidSha256": "garbage"}}, {"node": {"gitoidSha256": "undecodable"}}, {"node": {"gitoidSha256": "feature"}}, {"node": {"gitoidSha256": "main"}}]}}}`)) //nolint:errcheck
+		case "/download/garbage":
+			w.Write([]byte("garbage")) //nolint:errcheck
+		case "/download/main", "/download/feature", "/download/undecodable":
+			envBytes, err := json.Marshal(stored[r.URL.Path[len("/download/"):]])
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write(envBytes) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := archivista.NewClient(server.URL)
+
+	env, err := FetchReferenceAuthorizationFromArchivista(context.Background(), client, testRef, testID, testID)
+	assert.Nil(t, err)
+	assert.Equal(t, mainZeroZero, env)
+
+	_, err = FetchReferenceAuthorizationFromArchivista(context.Background(), client, "refs/heads/unknown", testID, testID)
+	assert.ErrorIs(t, err, ErrAuthorizationNotFound)
+}
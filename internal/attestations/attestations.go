@@ -5,6 +5,7 @@ package attestations
 import (
 	"errors"
 
+	"github.com/gittuf/gittuf/internal/compression"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/go-git/go-git/v5"
@@ -17,6 +18,19 @@ const (
 	Ref                                        = "refs/gittuf/attestations"
 	referenceAuthorizationsTreeEntryName       = "reference-authorizations"
 	githubPullRequestAttestationsTreeEntryName = "github-pull-requests"
+	gerritChangeAttestationsTreeEntryName      = "gerrit-changes"
+	bitbucketPullRequestsTreeEntryName         = "bitbucket-pull-requests"
+	reviewApprovalsTreeEntryName               = "review-approvals"
+	pushEventAttestationsTreeEntryName         = "push-events"
+	ciRunAttestationsTreeEntryName             = "ci-runs"
+	hookExecutionAttestationsTreeEntryName     = "hook-executions"
+	rebuildAttestationsTreeEntryName           = "rebuilds"
+	lfsObjectsAttestationsTreeEntryName        = "lfs-objects"
+	statementsTreeEntryName                    = "statements"
+	policyJustificationsTreeEntryName          = "policy-justifications"
+	tombstonesTreeEntryName                    = "tombstones"
+	rekorEntriesTreeEntryName                  = "rekor-entries"
+	timestampsTreeEntryName                    = "timestamps"
 	initialCommitMessage                       = "Initial commit"
 	defaultCommitMessage                       = "Update attestations"
 )
@@ -61,6 +75,100 @@ type Attestations struct {
 	// `<ref-path>/<commit-id>`, where `ref-path` is the absolute ref path, and
 	// `commit-id` is the ID of the merged commit.
 	githubPullRequestAttestations map[string]plumbing.Hash
+
+	// gerritChangeAttestations maps each Gerrit change submitted to a branch
+	// to the blob ID of the attestation recording the change and its
+	// approvals. The key is a path of the form `<ref-path>/<commit-id>`, where
+	// `ref-path` is the absolute ref path of the branch, and `commit-id` is the
+	// ID of the submitted commit.
+	gerritChangeAttestations map[string]plumbing.Hash
+
+	// bitbucketPullRequestAttestations maps each Bitbucket Data Center pull
+	// request merged into a branch to the blob ID of the attestation
+	// recording the pull request and its approvals. The key is a path of the
+	// form `<ref-path>/<commit-id>`, where `ref-path` is the absolute ref path
+	// of the branch, and `commit-id` is the ID of the merged commit.
+	bitbucketPullRequestAttestations map[string]plumbing.Hash
+
+	// reviewApprovalAttestations maps each change reviewed on a code review
+	// platform, such as a GitHub pull request or a GitLab merge request, to
+	// the blob ID of the attestation recording the approvals it received. The
+	// key is a path of the form `<ref-path>/<target-id>`, where `ref-path` is
+	// the absolute ref path of the target branch, and `target-id` is the ID
+	// the branch is moved to when the change is merged.
+	reviewApprovalAttestations map[string]plumbing.Hash
+
+	// pushEventAttestations maps each push event to the blob ID of the
+	// attestation describing it. The key is the ID of the RSL entry recorded
+	// for the push.
+	pushEventAttestations map[string]plumbing.Hash
+
+	// ciRunAttestations maps each CI run to the blob ID of the attestation
+	// describing it. The key is a path of the form
+	// `<commit-id>/<provider>/<run-id>`, where `commit-id` is the commit the
+	// run was for.
+	ciRunAttestations map[string]plumbing.Hash
+
+	// hookExecutionAttestations maps each hook execution to the blob ID of the
+	// attestation recording its result. The key is a path of the form
+	// `<ref-path>/<target-id>/<hook-name>`, where `ref-path` is the absolute
+	// ref path and `target-id` is the ID the ref pointed to when the hook was
+	// executed.
+	hookExecutionAttestations map[string]plumbing.Hash
+
+	// rebuildAttestations maps each artifact rebuilt from a commit to the blob
+	// ID of the attestation signed by the rebuilders. The key is a path of the
+	// form `<commit-id>/<artifact-name>/<artifact-digest>`, so rebuilders that
+	// disagree about the artifact's digest sign different attestations.
+	rebuildAttestations map[string]plumbing.Hash
+
+	// lfsObjectsAttestations maps each commit to the blob ID of the attestation
+	// recording the Git LFS objects referenced by the commit. The key is the ID
+	// of the commit.
+	lfsObjectsAttestations map[string]plumbing.Hash
+
+	// statementAttestations maps each in-toto statement attached to a change
+	// to a Git reference to the blob ID of the statement. The key is a path of
+	// the form `<ref-path>/<target-id>/<payload-digest>`, where `ref-path` is
+	// the absolute ref path, `target-id` is the ID the ref was moved to, and
+	// `payload-digest` is the SHA-256 digest of the statement.
+	statementAttestations map[string]plumbing.Hash
+
+	// policyJustifications maps each policy commit to the blob ID of the
+	// attestation justifying the policy change. The key is the ID of the
+	// policy commit.
+	policyJustifications map[string]plumbing.Hash
+
+	// tombstones maps each pruning of the attestations namespace to the blob
+	// ID of the tombstone recording the pruned attestations. The key is the ID
+	// of the namespace's commit that the attestations were pruned from.
+	tombstones map[string]plumbing.Hash
+
+	// rekorEntries maps the Rekor log entries recorded for attestations to
+	// the blob ID of the entry. The key is a path of the form
+	// `<payload-digest>/<uuid>`, where `payload-digest` is the SHA-256 digest
+	// of the attestation's payload and `uuid` identifies the entry in Rekor.
+	// Unlike the other blobs, the entries are not DSSE envelopes.
+	rekorEntries map[string]plumbing.Hash
+
+	// timestamps maps the timestamps recorded for signatures of RSL entries
+	// and policy metadata to the blob ID of the timestamp. The key is the
+	// SHA-256 digest of the signature. Like the Rekor entries, the timestamps
+	// are not DSSE envelopes.
+	timestamps map[string]plumbing.Hash
+
+	// compression is the algorithm used to compress the blobs written for
+	// new attestations. It is set using SetCompression, as it is determined
+	// by the repository's root of trust.
+	compression string
+}
+
+// SetCompression sets the algorithm used to compress large attestations when
+// they are written to the repository. Compression is disabled if algorithm is
+// empty. Attestations are always decompressed when they are read, regardless
+// of the algorithm set.
+func (a *Attestations) SetCompression(algorithm string) {
+	a.compression = algorithm
 }
 
 // LoadCurrentAttestations inspects the repository's attestations namespace and
@@ -103,15 +211,55 @@ func LoadAttestationsForEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (
 	}
 
 	var (
-		authorizationsTreeID     plumbing.Hash
-		githubPullRequestsTreeID plumbing.Hash
+		authorizationsTreeID        plumbing.Hash
+		githubPullRequestsTreeID    plumbing.Hash
+		gerritChangesTreeID         plumbing.Hash
+		bitbucketPullRequestsTreeID plumbing.Hash
+		reviewApprovalsTreeID       plumbing.Hash
+		pushEventsTreeID            plumbing.Hash
+		ciRunsTreeID                plumbing.Hash
+		hookExecutionsTreeID        plumbing.Hash
+		rebuildsTreeID              plumbing.Hash
+		lfsObjectsTreeID            plumbing.Hash
+		statementsTreeID            plumbing.Hash
+		policyJustificationsTreeID  plumbing.Hash
+		tombstonesTreeID            plumbing.Hash
+		rekorEntriesTreeID          plumbing.Hash
+		timestampsTreeID            plumbing.Hash
 	)
 
 	for _, e := range attestationsRootTree.Entries {
-		if e.Name == referenceAuthorizationsTreeEntryName {
+		switch e.Name {
+		case referenceAuthorizationsTreeEntryName:
 			authorizationsTreeID = e.Hash
-		} else if e.Name == githubPullRequestAttestationsTreeEntryName {
+		case githubPullRequestAttestationsTreeEntryName:
 			githubPullRequestsTreeID = e.Hash
+		case gerritChangeAttestationsTreeEntryName:
+			gerritChangesTreeID = e.Hash
+		case bitbucketPullRequestsTreeEntryName:
+			bitbucketPullRequestsTreeID = e.Hash
+		case reviewApprovalsTreeEntryName:
+			reviewApprovalsTreeID = e.Hash
+		case pushEventAttestationsTreeEntryName:
+			pushEventsTreeID = e.Hash
+		case ciRunAttestationsTreeEntryName:
+			ciRunsTreeID = e.Hash
+		case hookExecutionAttestationsTreeEntryName:
+			hookExecutionsTreeID = e.Hash
+		case rebuildAttestationsTreeEntryName:
+			rebuildsTreeID = e.Hash
+		case lfsObjectsAttestationsTreeEntryName:
+			lfsObjectsTreeID = e.Hash
+		case statementsTreeEntryName:
+			statementsTreeID = e.Hash
+		case policyJustificationsTreeEntryName:
+			policyJustificationsTreeID = e.Hash
+		case tombstonesTreeEntryName:
+			tombstonesTreeID = e.Hash
+		case rekorEntriesTreeEntryName:
+			rekorEntriesTreeID = e.Hash
+		case timestampsTreeEntryName:
+			timestampsTreeID = e.Hash
 		}
 	}
 
@@ -126,8 +274,21 @@ func LoadAttestationsForEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (
 	}
 
 	attestations := &Attestations{
-		referenceAuthorizations:       map[string]plumbing.Hash{},
-		githubPullRequestAttestations: map[string]plumbing.Hash{},
+		referenceAuthorizations:          map[string]plumbing.Hash{},
+		githubPullRequestAttestations:    map[string]plumbing.Hash{},
+		gerritChangeAttestations:         map[string]plumbing.Hash{},
+		bitbucketPullRequestAttestations: map[string]plumbing.Hash{},
+		reviewApprovalAttestations:       map[string]plumbing.Hash{},
+		pushEventAttestations:            map[string]plumbing.Hash{},
+		ciRunAttestations:                map[string]plumbing.Hash{},
+		hookExecutionAttestations:        map[string]plumbing.Hash{},
+		rebuildAttestations:              map[string]plumbing.Hash{},
+		lfsObjectsAttestations:           map[string]plumbing.Hash{},
+		statementAttestations:            map[string]plumbing.Hash{},
+		policyJustifications:             map[string]plumbing.Hash{},
+		tombstones:                       map[string]plumbing.Hash{},
+		rekorEntries:                     map[string]plumbing.Hash{},
+		timestamps:                       map[string]plumbing.Hash{},
 	}
 
 	attestations.referenceAuthorizations, err = gitinterface.GetAllFilesInTree(authorizationsTree)
@@ -140,6 +301,164 @@ func LoadAttestationsForEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (
 		return nil, err
 	}
 
+	if !gerritChangesTreeID.IsZero() {
+		gerritChangesTree, err := gitinterface.GetTree(repo, gerritChangesTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.gerritChangeAttestations, err = gitinterface.GetAllFilesInTree(gerritChangesTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !bitbucketPullRequestsTreeID.IsZero() {
+		bitbucketPullRequestsTree, err := gitinterface.GetTree(repo, bitbucketPullRequestsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.bitbucketPullRequestAttestations, err = gitinterface.GetAllFilesInTree(bitbucketPullRequestsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !reviewApprovalsTreeID.IsZero() {
+		reviewApprovalsTree, err := gitinterface.GetTree(repo, reviewApprovalsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.reviewApprovalAttestations, err = gitinterface.GetAllFilesInTree(reviewApprovalsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Push event attestations were added later, so older states may not
+	// have a tree for them
+	if !pushEventsTreeID.IsZero() {
+		pushEventsTree, err := gitinterface.GetTree(repo, pushEventsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.pushEventAttestations, err = gitinterface.GetAllFilesInTree(pushEventsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !ciRunsTreeID.IsZero() {
+		ciRunsTree, err := gitinterface.GetTree(repo, ciRunsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.ciRunAttestations, err = gitinterface.GetAllFilesInTree(ciRunsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !hookExecutionsTreeID.IsZero() {
+		hookExecutionsTree, err := gitinterface.GetTree(repo, hookExecutionsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.hookExecutionAttestations, err = gitinterface.GetAllFilesInTree(hookExecutionsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !rebuildsTreeID.IsZero() {
+		rebuildsTree, err := gitinterface.GetTree(repo, rebuildsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.rebuildAttestations, err = gitinterface.GetAllFilesInTree(rebuildsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !lfsObjectsTreeID.IsZero() {
+		lfsObjectsTree, err := gitinterface.GetTree(repo, lfsObjectsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.lfsObjectsAttestations, err = gitinterface.GetAllFilesInTree(lfsObjectsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !statementsTreeID.IsZero() {
+		statementsTree, err := gitinterface.GetTree(repo, statementsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.statementAttestations, err = gitinterface.GetAllFilesInTree(statementsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !policyJustificationsTreeID.IsZero() {
+		policyJustificationsTree, err := gitinterface.GetTree(repo, policyJustificationsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.policyJustifications, err = gitinterface.GetAllFilesInTree(policyJustificationsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !tombstonesTreeID.IsZero() {
+		tombstonesTree, err := gitinterface.GetTree(repo, tombstonesTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.tombstones, err = gitinterface.GetAllFilesInTree(tombstonesTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !rekorEntriesTreeID.IsZero() {
+		rekorEntriesTree, err := gitinterface.GetTree(repo, rekorEntriesTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.rekorEntries, err = gitinterface.GetAllFilesInTree(rekorEntriesTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !timestampsTreeID.IsZero() {
+		timestampsTree, err := gitinterface.GetTree(repo, timestampsTreeID)
+		if err != nil {
+			return nil, err
+		}
+
+		attestations.timestamps, err = gitinterface.GetAllFilesInTree(timestampsTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return attestations, nil
 }
 
@@ -176,6 +495,149 @@ func (a *Attestations) Commit(repo *git.Repository, commitMessage string, signCo
 		Hash: githubPullRequestsTreeID,
 	})
 
+	// Add Gerrit changes tree
+	gerritChangesTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.gerritChangeAttestations)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: gerritChangeAttestationsTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: gerritChangesTreeID,
+	})
+
+	// Add Bitbucket pull requests tree
+	bitbucketPullRequestsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.bitbucketPullRequestAttestations)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: bitbucketPullRequestsTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: bitbucketPullRequestsTreeID,
+	})
+
+	// Add review approvals tree
+	reviewApprovalsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.reviewApprovalAttestations)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: reviewApprovalsTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: reviewApprovalsTreeID,
+	})
+
+	// Add push events tree
+	pushEventsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.pushEventAttestations)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: pushEventAttestationsTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: pushEventsTreeID,
+	})
+
+	// Add CI runs tree
+	ciRunsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.ciRunAttestations)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: ciRunAttestationsTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: ciRunsTreeID,
+	})
+
+	// Add hook executions tree
+	hookExecutionsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.hookExecutionAttestations)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: hookExecutionAttestationsTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: hookExecutionsTreeID,
+	})
+
+	// Add rebuilds tree
+	rebuildsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.rebuildAttestations)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: rebuildAttestationsTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: rebuildsTreeID,
+	})
+
+	// Add Git LFS objects tree
+	lfsObjectsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.lfsObjectsAttestations)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: lfsObjectsAttestationsTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: lfsObjectsTreeID,
+	})
+
+	// Add statements tree
+	statementsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.statementAttestations)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: statementsTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: statementsTreeID,
+	})
+
+	// Add policy justifications tree
+	policyJustificationsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.policyJustifications)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: policyJustificationsTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: policyJustificationsTreeID,
+	})
+
+	// Add tombstones tree
+	tombstonesTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.tombstones)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: tombstonesTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: tombstonesTreeID,
+	})
+
+	// Add Rekor entries tree
+	rekorEntriesTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.rekorEntries)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: rekorEntriesTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: rekorEntriesTreeID,
+	})
+
+	// Add timestamps tree
+	timestampsTreeID, err := treeBuilder.WriteRootTreeFromBlobIDs(a.timestamps)
+	if err != nil {
+		return err
+	}
+	attestationsTreeEntries = append(attestationsTreeEntries, object.TreeEntry{
+		Name: timestampsTreeEntryName,
+		Mode: filemode.Dir,
+		Hash: timestampsTreeID,
+	})
+
 	attestationsTreeID, err := gitinterface.WriteTree(repo, attestationsTreeEntries)
 	if err != nil {
 		return err
@@ -200,3 +662,25 @@ func (a *Attestations) Commit(repo *git.Repository, commitMessage string, signCo
 
 	return nil
 }
+
+// writeBlob writes the contents of an attestation to the repository,
+// compressing it if compression is enabled.
+func (a *Attestations) writeBlob(repo *git.Repository, contents []byte) (plumbing.Hash, error) {
+	contents, err := compression.Compress(a.compression, contents)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return gitinterface.WriteBlob(repo, contents)
+}
+
+// readBlob reads the contents of an attestation from the repository,
+// decompressing it if necessary.
+func readBlob(repo *git.Repository, blobID plumbing.Hash) ([]byte, error) {
+	contents, err := gitinterface.ReadBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return compression.Decompress(contents)
+}
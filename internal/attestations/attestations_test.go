@@ -3,10 +3,14 @@
 package attestations
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/compression"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/lfs"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	"github.com/go-git/go-billy/v5/memfs"
@@ -312,9 +316,22 @@ func TestAttestationsCommit(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert.Equal(t, 2, len(rootTree.Entries))
-	assert.Equal(t, githubPullRequestAttestationsTreeEntryName, rootTree.Entries[0].Name)
-	assert.Equal(t, referenceAuthorizationsTreeEntryName, rootTree.Entries[1].Name)
+	assert.Equal(t, 15, len(rootTree.Entries))
+	assert.Equal(t, bitbucketPullRequestsTreeEntryName, rootTree.Entries[0].Name)
+	assert.Equal(t, ciRunAttestationsTreeEntryName, rootTree.Entries[1].Name)
+	assert.Equal(t, gerritChangeAttestationsTreeEntryName, rootTree.Entries[2].Name)
+	assert.Equal(t, githubPullRequestAttestationsTreeEntryName, rootTree.Entries[3].Name)
+	assert.Equal(t, hookExecutionAttestationsTreeEntryName, rootTree.Entries[4].Name)
+	assert.Equal(t, lfsObjectsAttestationsTreeEntryName, rootTree.Entries[5].Name)
+	assert.Equal(t, policyJustificationsTreeEntryName, rootTree.Entries[6].Name)
+	assert.Equal(t, pushEventAttestationsTreeEntryName, rootTree.Entries[7].Name)
+	assert.Equal(t, rebuildAttestationsTreeEntryName, rootTree.Entries[8].Name)
+	assert.Equal(t, referenceAuthorizationsTreeEntryName, rootTree.Entries[9].Name)
+	assert.Equal(t, rekorEntriesTreeEntryName, rootTree.Entries[10].Name)
+	assert.Equal(t, reviewApprovalsTreeEntryName, rootTree.Entries[11].Name)
+	assert.Equal(t, statementsTreeEntryName, rootTree.Entries[12].Name)
+	assert.Equal(t, timestampsTreeEntryName, rootTree.Entries[13].Name)
+	assert.Equal(t, tombstonesTreeEntryName, rootTree.Entries[14].Name)
 
 	// We don't need to check every level of the tree because we do it in the
 	// tree builder API
@@ -322,3 +339,45 @@ func TestAttestationsCommit(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, attestations.referenceAuthorizations, authorizations)
 }
+
+func TestAttestationsSetCompression(t *testing.T) {
+	commitID := "abcdef1234567890abcdef1234567890abcdef12"
+	objects := &LFSObjects{CommitID: commitID, Objects: []*lfs.Pointer{}}
+	for i := 0; i < 100; i++ {
+		objects.Objects = append(objects.Objects, &lfs.Pointer{Path: fmt.Sprintf("assets/%d.png", i), OID: "dd0401f025a48d86243d4bd336483566b84d4ab7d1b15eb612c88ad02cee59db", Size: 10})
+	}
+
+	statement, err := NewLFSObjectsAttestation(objects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+	attestations.SetCompression(compression.AlgorithmZstd)
+
+	if err := attestations.SetLFSObjectsAttestation(repo, env, commitID); err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := gitinterface.ReadBlob(repo, attestations.lfsObjectsAttestations[commitID])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, bytes.HasPrefix(contents, []byte("gittuf-compression: zstd\n")))
+
+	// Compressed attestations are read regardless of the compression set
+	attestations.SetCompression("")
+
+	storedEnv, err := attestations.GetLFSObjectsAttestationFor(repo, commitID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+}
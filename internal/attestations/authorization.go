@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"sort"
+	"strings"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/go-git/go-git/v5"
@@ -22,6 +24,7 @@ const (
 	targetRefKey                        = "targetRef"
 	fromRevisionIDKey                   = "fromRevisionID"
 	targetTreeIDKey                     = "targetTreeID"
+	targetIDKey                         = "targetID"
 )
 
 var (
@@ -31,11 +34,14 @@ var (
 
 // ReferenceAuthorization is a lightweight record of a detached authorization in
 // a gittuf repository. It is meant to be used as a "predicate" in an in-toto
-// attestation.
+// attestation. For branches, the authorized change is identified using the
+// expected Git tree, while for tags, it is identified using the ID of the
+// object the tag is expected to point to.
 type ReferenceAuthorization struct {
 	TargetRef      string `json:"targetRef"`
 	FromRevisionID string `json:"fromRevisionID"`
-	TargetTreeID   string `json:"targetTreeID"`
+	TargetTreeID   string `json:"targetTreeID,omitempty"`
+	TargetID       string `json:"targetID,omitempty"`
 }
 
 // NewReferenceAuthorization creates a new reference authorization for the
@@ -50,31 +56,22 @@ func NewReferenceAuthorization(targetRef, fromRevisionID, targetTreeID string) (
 		TargetTreeID:   targetTreeID,
 	}
 
-	predicateBytes, err := json.Marshal(predicate)
-	if err != nil {
-		return nil, err
-	}
-
-	predicateInterface := &map[string]any{}
-	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
-		return nil, err
-	}
+	return newReferenceAuthorizationStatement(predicate, map[string]string{digestGitTreeKey: targetTreeID})
+}
 
-	predicateStruct, err := structpb.NewStruct(*predicateInterface)
-	if err != nil {
-		return nil, err
+// NewReferenceAuthorizationForTag creates a new reference authorization for
+// creating the tag `tagRef` pointing to `targetID`. As tags are not expected to
+// be updated after creation, the from revision is always the zero hash. The
+// authorization is embedded in an in-toto "statement" and returned with the
+// appropriate "predicate type" set.
+func NewReferenceAuthorizationForTag(tagRef, targetID string) (*ita.Statement, error) {
+	predicate := &ReferenceAuthorization{
+		TargetRef:      tagRef,
+		FromRevisionID: plumbing.ZeroHash.String(),
+		TargetID:       targetID,
 	}
 
-	return &ita.Statement{
-		Type: ita.StatementTypeUri,
-		Subject: []*ita.ResourceDescriptor{
-			{
-				Digest: map[string]string{digestGitTreeKey: targetTreeID},
-			},
-		},
-		PredicateType: ReferenceAuthorizationPredicateType,
-		Predicate:     predicateStruct,
-	}, nil
+	return newReferenceAuthorizationStatement(predicate, map[string]string{digestGitCommitKey: targetID})
 }
 
 // SetReferenceAuthorization writes the new reference authorization attestation
@@ -89,7 +86,7 @@ func (a *Attestations) SetReferenceAuthorization(repo *git.Repository, env *ssli
 		return err
 	}
 
-	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	blobID, err := a.writeBlob(repo, envBytes)
 	if err != nil {
 		return err
 	}
@@ -123,7 +120,7 @@ func (a *Attestations) GetReferenceAuthorizationFor(repo *git.Repository, refNam
 		return nil, ErrAuthorizationNotFound
 	}
 
-	envBytes, err := gitinterface.ReadBlob(repo, blobID)
+	envBytes, err := readBlob(repo, blobID)
 	if err != nil {
 		return nil, err
 	}
@@ -140,13 +137,74 @@ func (a *Attestations) GetReferenceAuthorizationFor(repo *git.Repository, refNam
 	return env, nil
 }
 
+// ListReferenceAuthorizationsFor returns the changes to the branch refName that
+// have reference authorizations, sorted by their paths. Only the target ref,
+// from revision, and target tree of each change are set; the authorizations
+// themselves must be fetched using GetReferenceAuthorizationFor.
+func (a *Attestations) ListReferenceAuthorizationsFor(refName string) []*ReferenceAuthorization {
+	authPaths := []string{}
+	for authPath := range a.referenceAuthorizations {
+		authPaths = append(authPaths, authPath)
+	}
+	sort.Strings(authPaths)
+
+	changes := []*ReferenceAuthorization{}
+	for _, authPath := range authPaths {
+		change, found := strings.CutPrefix(authPath, refName+"/")
+		if !found || strings.Contains(change, "/") {
+			continue
+		}
+
+		fromRevisionID, targetTreeID, found := strings.Cut(change, "-")
+		if !found {
+			continue
+		}
+
+		changes = append(changes, &ReferenceAuthorization{
+			TargetRef:      refName,
+			FromRevisionID: fromRevisionID,
+			TargetTreeID:   targetTreeID,
+		})
+	}
+
+	return changes
+}
+
 // ReferenceAuthorizationPath constructs the expected path on-disk for the
 // reference authorization attestation.
 func ReferenceAuthorizationPath(refName, fromID, toID string) string {
 	return path.Join(refName, fmt.Sprintf("%s-%s", fromID, toID))
 }
 
-func validateReferenceAuthorization(env *sslibdsse.Envelope, targetRef, fromRevisionID, targetTreeID string) error {
+func newReferenceAuthorizationStatement(predicate *ReferenceAuthorization, subjectDigest map[string]string) (*ita.Statement, error) {
+	predicateBytes, err := json.Marshal(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Digest: subjectDigest,
+			},
+		},
+		PredicateType: ReferenceAuthorizationPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+func validateReferenceAuthorization(env *sslibdsse.Envelope, targetRef, fromRevisionID, targetID string) error {
 	payload, err := env.DecodeB64Payload()
 	if err != nil {
 		return err
@@ -157,14 +215,29 @@ func validateReferenceAuthorization(env *sslibdsse.Envelope, targetRef, fromRevi
 		return err
 	}
 
-	if attestation.Subject[0].Digest[digestGitTreeKey] != targetTreeID {
+	if attestation.PredicateType != ReferenceAuthorizationPredicateType || len(attestation.Subject) == 0 {
 		return ErrInvalidAuthorization
 	}
 
 	predicate := attestation.Predicate.AsMap()
 
-	if predicate[targetTreeIDKey] != targetTreeID {
-		return ErrInvalidAuthorization
+	if strings.HasPrefix(targetRef, gitinterface.TagRefPrefix) {
+		// For tags, the authorization is for the object the tag points to
+		if attestation.Subject[0].Digest[digestGitCommitKey] != targetID {
+			return ErrInvalidAuthorization
+		}
+
+		if predicate[targetIDKey] != targetID {
+			return ErrInvalidAuthorization
+		}
+	} else {
+		if attestation.Subject[0].Digest[digestGitTreeKey] != targetID {
+			return ErrInvalidAuthorization
+		}
+
+		if predicate[targetTreeIDKey] != targetID {
+			return ErrInvalidAuthorization
+		}
 	}
 
 	if predicate[fromRevisionIDKey] != fromRevisionID {
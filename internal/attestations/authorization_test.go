@@ -40,6 +40,33 @@ func TestNewReferenceAuthorization(t *testing.T) {
 	assert.Equal(t, predicate[fromRevisionIDKey], testID)
 }
 
+func TestNewReferenceAuthorizationForTag(t *testing.T) {
+	testRef := "refs/tags/v1"
+	testID := plumbing.ZeroHash.String()
+	testTargetID := "abcdef1234567890abcdef1234567890abcdef12"
+
+	authorization, err := NewReferenceAuthorizationForTag(testRef, testTargetID)
+	assert.Nil(t, err)
+
+	// Check value of statement type
+	assert.Equal(t, ita.StatementTypeUri, authorization.Type)
+
+	// Check subject contents
+	assert.Equal(t, 1, len(authorization.Subject))
+	assert.Contains(t, authorization.Subject[0].Digest, digestGitCommitKey)
+	assert.Equal(t, authorization.Subject[0].Digest[digestGitCommitKey], testTargetID)
+
+	// Check predicate type
+	assert.Equal(t, ReferenceAuthorizationPredicateType, authorization.PredicateType)
+
+	// Check predicate
+	predicate := authorization.Predicate.AsMap()
+	assert.Equal(t, predicate[targetRefKey], testRef)
+	assert.Equal(t, predicate[targetIDKey], testTargetID)
+	assert.Equal(t, predicate[fromRevisionIDKey], testID)
+	assert.NotContains(t, predicate, targetTreeIDKey)
+}
+
 func TestSetReferenceAuthorization(t *testing.T) {
 	testRef := "refs/heads/main"
 	testAnotherRef := "refs/heads/feature"
@@ -138,6 +165,35 @@ func TestGetReferenceAuthorizationFor(t *testing.T) {
 	assert.Equal(t, featureZeroZero, featureAuth)
 }
 
+func TestListReferenceAuthorizationsFor(t *testing.T) {
+	testRef := "refs/heads/main"
+	testNestedRef := "refs/heads/main/feature"
+	testID := plumbing.ZeroHash.String()
+	testTreeID := "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+	assert.Empty(t, attestations.ListReferenceAuthorizationsFor(testRef))
+
+	for _, change := range [][]string{{testRef, testID, testTreeID}, {testRef, testID, testID}, {testNestedRef, testID, testID}} {
+		env := createReferenceAuthorizationAttestationEnvelopes(t, change[0], change[1], change[2])
+		if err := attestations.SetReferenceAuthorization(repo, env, change[0], change[1], change[2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expectedChanges := []*ReferenceAuthorization{
+		{TargetRef: testRef, FromRevisionID: testID, TargetTreeID: testID},
+		{TargetRef: testRef, FromRevisionID: testID, TargetTreeID: testTreeID},
+	}
+	assert.Equal(t, expectedChanges, attestations.ListReferenceAuthorizationsFor(testRef))
+	assert.Equal(t, []*ReferenceAuthorization{{TargetRef: testNestedRef, FromRevisionID: testID, TargetTreeID: testID}}, attestations.ListReferenceAuthorizationsFor(testNestedRef))
+}
+
 func TestValidateReferenceAuthorization(t *testing.T) {
 	testRef := "refs/heads/main"
 	testAnotherRef := "refs/heads/feature"
@@ -153,6 +209,29 @@ func TestValidateReferenceAuthorization(t *testing.T) {
 
 	err = validateReferenceAuthorization(mainZeroZero, testAnotherRef, testID, testID)
 	assert.ErrorIs(t, err, ErrInvalidAuthorization)
+
+	t.Run("tag authorization", func(t *testing.T) {
+		testTagRef := "refs/tags/v1"
+		testTargetID := "abcdef1234567890abcdef1234567890abcdef12"
+
+		authorization, err := NewReferenceAuthorizationForTag(testTagRef, testTargetID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = validateReferenceAuthorization(env, testTagRef, testID, testTargetID)
+		assert.Nil(t, err)
+
+		err = validateReferenceAuthorization(env, testTagRef, testID, testID)
+		assert.ErrorIs(t, err, ErrInvalidAuthorization)
+
+		err = validateReferenceAuthorization(env, "refs/tags/v2", testID, testTargetID)
+		assert.ErrorIs(t, err, ErrInvalidAuthorization)
+	})
 }
 
 func createReferenceAuthorizationAttestationEnvelopes(t *testing.T, refName, fromID, toID string) *sslibdsse.Envelope {
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+
+	"github.com/gittuf/gittuf/internal/bitbucket"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const BitbucketPullRequestPredicateType = "https://gittuf.dev/bitbucket-pull-request/v0.1"
+
+var (
+	ErrBitbucketPullRequestNotFound = errors.New("requested Bitbucket pull request attestation not found")
+	ErrInvalidBitbucketPullRequest  = errors.New("Bitbucket pull request attestation does not match expected details") //nolint:stylecheck
+)
+
+// NewBitbucketPullRequestAttestation creates a new attestation recording the
+// Bitbucket Data Center pull request merged as commitID, including the
+// approvals the pull request received. The pull request is embedded in an
+// in-toto "statement" and returned with the appropriate "predicate type" set.
+func NewBitbucketPullRequestAttestation(pullRequest *bitbucket.PullRequest, commitID string) (*ita.Statement, error) {
+	pullRequestBytes, err := json.Marshal(pullRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	predicate := map[string]any{}
+	if err := json.Unmarshal(pullRequestBytes, &predicate); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Uri:    pullRequest.URL,
+				Digest: map[string]string{digestGitCommitKey: commitID},
+			},
+		},
+		PredicateType: BitbucketPullRequestPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// BitbucketPullRequestAttestationPath constructs the expected path on-disk for
+// the Bitbucket pull request attestation.
+func BitbucketPullRequestAttestationPath(refName, commitID string) string {
+	return path.Join(refName, commitID)
+}
+
+// SetBitbucketPullRequestAttestation writes the new Bitbucket pull request
+// attestation to the object store and tracks it in the current attestations
+// state.
+func (a *Attestations) SetBitbucketPullRequestAttestation(repo *git.Repository, env *sslibdsse.Envelope, refName, commitID string) error {
+	if err := validateBitbucketPullRequestAttestation(env, refName, commitID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.bitbucketPullRequestAttestations == nil {
+		a.bitbucketPullRequestAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.bitbucketPullRequestAttestations[BitbucketPullRequestAttestationPath(refName, commitID)] = blobID
+	return nil
+}
+
+// GetBitbucketPullRequestAttestationFor returns the Bitbucket pull request
+// attestation (with its signatures) for the pull request merged into refName
+// as commitID.
+func (a *Attestations) GetBitbucketPullRequestAttestationFor(repo *git.Repository, refName, commitID string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.bitbucketPullRequestAttestations[BitbucketPullRequestAttestationPath(refName, commitID)]
+	if !has {
+		return nil, ErrBitbucketPullRequestNotFound
+	}
+
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateBitbucketPullRequestAttestation(env, refName, commitID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func validateBitbucketPullRequestAttestation(env *sslibdsse.Envelope, refName, commitID string) error {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return err
+	}
+
+	if attestation.PredicateType != BitbucketPullRequestPredicateType || len(attestation.Subject) == 0 {
+		return ErrInvalidBitbucketPullRequest
+	}
+
+	if attestation.Subject[0].Digest[digestGitCommitKey] != commitID {
+		return ErrInvalidBitbucketPullRequest
+	}
+
+	predicateBytes, err := json.Marshal(attestation.Predicate.AsMap())
+	if err != nil {
+		return err
+	}
+
+	pullRequest := &bitbucket.PullRequest{}
+	if err := json.Unmarshal(predicateBytes, pullRequest); err != nil {
+		return err
+	}
+
+	if pullRequest.RefName() != refName {
+		return ErrInvalidBitbucketPullRequest
+	}
+
+	return nil
+}
+
+// splitBitbucketPullRequestAttestationPath is the inverse of
+// BitbucketPullRequestAttestationPath.
+func splitBitbucketPullRequestAttestationPath(pullRequestPath string) (string, string, error) {
+	refName, commitID := path.Split(pullRequestPath)
+	if refName == "" || commitID == "" {
+		return "", "", ErrUnknownAttestationPath
+	}
+
+	return path.Clean(refName), commitID, nil
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/bitbucket"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func testBitbucketPullRequest() *bitbucket.PullRequest {
+	return &bitbucket.PullRequest{
+		URL:        "https://bitbucket.example.com/projects/PRJ/repos/repo/pull-requests/42",
+		Project:    "PRJ",
+		Repository: "repo",
+		ID:         42,
+		State:      "MERGED",
+		FromRef:    "refs/heads/feature",
+		ToRef:      "refs/heads/main",
+		Author:     bitbucket.User{Name: "jane"},
+		Approvals: []bitbucket.Approval{
+			{User: bitbucket.User{Name: "john", Principal: "john@example.com"}, Status: bitbucket.ReviewerStatusApproved},
+		},
+	}
+}
+
+func TestNewBitbucketPullRequestAttestation(t *testing.T) {
+	commitID := "abcdef1234567890abcdef1234567890abcdef12"
+
+	attestation, err := NewBitbucketPullRequestAttestation(testBitbucketPullRequest(), commitID)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, "https://bitbucket.example.com/projects/PRJ/repos/repo/pull-requests/42", attestation.Subject[0].Uri)
+	assert.Equal(t, commitID, attestation.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, BitbucketPullRequestPredicateType, attestation.PredicateType)
+
+	predicate := attestation.Predicate.AsMap()
+	assert.Equal(t, "refs/heads/main", predicate["toRef"])
+	assert.Equal(t, float64(42), predicate["id"])
+	assert.Equal(t, []any{
+		map[string]any{
+			"status": bitbucket.ReviewerStatusApproved,
+			"user":   map[string]any{"name": "john", "principal": "john@example.com"},
+		},
+	}, predicate["approvals"])
+}
+
+func TestSetAndGetBitbucketPullRequestAttestation(t *testing.T) {
+	refName := "refs/heads/main"
+	commitID := "abcdef1234567890abcdef1234567890abcdef12"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := NewBitbucketPullRequestAttestation(testBitbucketPullRequest(), commitID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	_, err = attestations.GetBitbucketPullRequestAttestationFor(repo, refName, commitID)
+	assert.ErrorIs(t, err, ErrBitbucketPullRequestNotFound)
+
+	err = attestations.SetBitbucketPullRequestAttestation(repo, env, refName, plumbing.ZeroHash.String())
+	assert.ErrorIs(t, err, ErrInvalidBitbucketPullRequest)
+
+	err = attestations.SetBitbucketPullRequestAttestation(repo, env, "refs/heads/feature", commitID)
+	assert.ErrorIs(t, err, ErrInvalidBitbucketPullRequest)
+
+	err = attestations.SetBitbucketPullRequestAttestation(repo, env, refName, commitID)
+	assert.Nil(t, err)
+
+	storedEnv, err := attestations.GetBitbucketPullRequestAttestationFor(repo, refName, commitID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	attestationPath := bitbucketPullRequestsTreeEntryName + "/" + BitbucketPullRequestAttestationPath(refName, commitID)
+	assert.Nil(t, ValidateEnvelope(attestationPath, env))
+
+	storedEnv, err = attestations.GetEnvelope(repo, attestationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+}
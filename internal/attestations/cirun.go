@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+
+	"github.com/gittuf/gittuf/internal/ci"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	CIRunPredicateType = "https://gittuf.dev/ci-run/v0.1"
+
+	ciProviderKey = "provider"
+	ciRunIDKey    = "runID"
+	commitIDKey   = "commitID"
+)
+
+var (
+	ErrCIRunNotFound = errors.New("requested CI run attestation not found")
+	ErrInvalidCIRun  = errors.New("CI run attestation does not match expected details")
+)
+
+// CIRun records the details of a CI run and is meant to be used as a
+// "predicate" in an in-toto attestation. When the attestation is signed
+// using Sigstore, the certificate issued by Fulcio for the run's identity is
+// recorded as well.
+type CIRun struct {
+	ci.Environment
+	SigningCertificate string `json:"signingCertificate,omitempty"`
+}
+
+// NewCIRunAttestation creates a new CI run attestation for the provided
+// environment. The CI run is embedded in an in-toto "statement" and returned
+// with the appropriate "predicate type" set. The subject of the statement is
+// the commit the run was for.
+func NewCIRunAttestation(env *ci.Environment, signingCertificate string) (*ita.Statement, error) {
+	if env.CommitID == "" || !isValidPathComponent(env.Provider) || !isValidPathComponent(env.RunID) {
+		return nil, ErrInvalidCIRun
+	}
+
+	predicateBytes, err := json.Marshal(&CIRun{Environment: *env, SigningCertificate: signingCertificate})
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Digest: map[string]string{digestGitCommitKey: env.CommitID},
+			},
+		},
+		PredicateType: CIRunPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// CIRunAttestationPath constructs the expected path on-disk for the CI run
+// attestation.
+func CIRunAttestationPath(commitID, provider, runID string) string {
+	return path.Join(commitID, provider, runID)
+}
+
+// SetCIRunAttestation writes the new CI run attestation to the object store
+// and tracks it in the current attestations state.
+func (a *Attestations) SetCIRunAttestation(repo *git.Repository, env *sslibdsse.Envelope, commitID, provider, runID string) error {
+	if err := validateCIRunAttestation(env, commitID, provider, runID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.ciRunAttestations == nil {
+		a.ciRunAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.ciRunAttestations[CIRunAttestationPath(commitID, provider, runID)] = blobID
+	return nil
+}
+
+// GetCIRunAttestationFor returns the CI run attestation (with its signatures)
+// for the specified commit and run.
+func (a *Attestations) GetCIRunAttestationFor(repo *git.Repository, commitID, provider, runID string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.ciRunAttestations[CIRunAttestationPath(commitID, provider, runID)]
+	if !has {
+		return nil, ErrCIRunNotFound
+	}
+
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateCIRunAttestation(env, commitID, provider, runID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func validateCIRunAttestation(env *sslibdsse.Envelope, commitID, provider, runID string) error {
+	if !isValidPathComponent(provider) || !isValidPathComponent(runID) {
+		return ErrInvalidCIRun
+	}
+
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return err
+	}
+
+	if attestation.PredicateType != CIRunPredicateType || len(attestation.Subject) == 0 {
+		return ErrInvalidCIRun
+	}
+
+	if attestation.Subject[0].Digest[digestGitCommitKey] != commitID {
+		return ErrInvalidCIRun
+	}
+
+	predicate := attestation.Predicate.AsMap()
+	if predicate[commitIDKey] != commitID || predicate[ciProviderKey] != provider || predicate[ciRunIDKey] != runID {
+		return ErrInvalidCIRun
+	}
+
+	return nil
+}
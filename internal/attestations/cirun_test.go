@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/ci"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCIRunAttestation(t *testing.T) {
+	env := &ci.Environment{
+		Provider: ci.GitHubActionsProvider,
+		Workflow: "gittuf/gittuf/.github/workflows/ci.yml@refs/heads/main",
+		RunID:    "1234-1",
+		Trigger:  "push",
+		RefName:  "refs/heads/main",
+		CommitID: "abcdef1234567890abcdef1234567890abcdef12",
+	}
+
+	attestation, err := NewCIRunAttestation(env, "")
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, env.CommitID, attestation.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, CIRunPredicateType, attestation.PredicateType)
+
+	predicate := attestation.Predicate.AsMap()
+	assert.Equal(t, env.Provider, predicate[ciProviderKey])
+	assert.Equal(t, env.RunID, predicate[ciRunIDKey])
+	assert.Equal(t, env.Workflow, predicate["workflow"])
+	assert.Equal(t, env.Trigger, predicate["trigger"])
+	assert.NotContains(t, predicate, "signingCertificate")
+
+	attestation, err = NewCIRunAttestation(env, "certificate")
+	assert.Nil(t, err)
+	assert.Equal(t, "certificate", attestation.Predicate.AsMap()["signingCertificate"])
+
+	_, err = NewCIRunAttestation(&ci.Environment{Provider: ci.GitLabCIProvider, RunID: "1234"}, "")
+	assert.ErrorIs(t, err, ErrInvalidCIRun)
+
+	_, err = NewCIRunAttestation(&ci.Environment{Provider: ci.GitLabCIProvider, RunID: "../1234", CommitID: env.CommitID}, "")
+	assert.ErrorIs(t, err, ErrInvalidCIRun)
+}
+
+func TestSetAndGetCIRunAttestation(t *testing.T) {
+	commitID := "abcdef1234567890abcdef1234567890abcdef12"
+	runID := "1234"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := NewCIRunAttestation(&ci.Environment{Provider: ci.GitLabCIProvider, RunID: runID, CommitID: commitID}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	_, err = attestations.GetCIRunAttestationFor(repo, commitID, ci.GitLabCIProvider, runID)
+	assert.ErrorIs(t, err, ErrCIRunNotFound)
+
+	err = attestations.SetCIRunAttestation(repo, env, plumbing.ZeroHash.String(), ci.GitLabCIProvider, runID)
+	assert.ErrorIs(t, err, ErrInvalidCIRun)
+
+	err = attestations.SetCIRunAttestation(repo, env, commitID, ci.BuildkiteProvider, runID)
+	assert.ErrorIs(t, err, ErrInvalidCIRun)
+
+	err = attestations.SetCIRunAttestation(repo, env, commitID, ci.GitLabCIProvider, runID)
+	assert.Nil(t, err)
+
+	storedEnv, err := attestations.GetCIRunAttestationFor(repo, commitID, ci.GitLabCIProvider, runID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	attestationPath := ciRunAttestationsTreeEntryName + "/" + CIRunAttestationPath(commitID, ci.GitLabCIProvider, runID)
+	assert.Nil(t, ValidateEnvelope(attestationPath, env))
+
+	storedEnv, err = attestations.GetEnvelope(repo, attestationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+}
@@ -0,0 +1,314 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var (
+	ErrUnknownAttestationPath = errors.New("unknown attestation path")
+	ErrAttestationNotFound    = errors.New("requested attestation not found")
+)
+
+// ValidateEnvelope checks that the envelope's contents match the attestation
+// path it is stored at. The path must be of the form returned by Envelopes,
+// i.e., it must include the name of the attestation type's tree.
+func ValidateEnvelope(attestationPath string, env *sslibdsse.Envelope) error {
+	treeName, blobPath, err := splitAttestationPath(attestationPath)
+	if err != nil {
+		return err
+	}
+
+	switch treeName {
+	case referenceAuthorizationsTreeEntryName:
+		refName, fromID, toID, err := splitReferenceAuthorizationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return validateReferenceAuthorization(env, refName, fromID, toID)
+	case githubPullRequestAttestationsTreeEntryName:
+		// GitHub pull request attestations are not validated against their
+		// path when they are set either
+		return nil
+	case gerritChangeAttestationsTreeEntryName:
+		refName, commitID, err := splitGerritChangeAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return validateGerritChangeAttestation(env, refName, commitID)
+	case bitbucketPullRequestsTreeEntryName:
+		refName, commitID, err := splitBitbucketPullRequestAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return validateBitbucketPullRequestAttestation(env, refName, commitID)
+	case reviewApprovalsTreeEntryName:
+		refName, targetID, err := splitReviewApprovalAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return validateReviewApprovalAttestation(env, refName, targetID)
+	case pushEventAttestationsTreeEntryName:
+		return validatePushEventAttestation(env, blobPath)
+	case ciRunAttestationsTreeEntryName:
+		commitID, provider, runID, err := splitCIRunAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return validateCIRunAttestation(env, commitID, provider, runID)
+	case hookExecutionAttestationsTreeEntryName:
+		refName, targetID, hookName, err := splitHookExecutionPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return validateHookExecutionAttestation(env, refName, targetID, hookName)
+	case rebuildAttestationsTreeEntryName:
+		commitID, artifactName, artifactDigest, err := splitRebuildAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return validateRebuildAttestation(env, commitID, artifactName, artifactDigest)
+	case lfsObjectsAttestationsTreeEntryName:
+		return validateLFSObjectsAttestation(env, blobPath)
+	case statementsTreeEntryName:
+		refName, targetID, payloadDigest, err := splitStatementAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return validateStatementAttestation(env, refName, targetID, payloadDigest)
+	case policyJustificationsTreeEntryName:
+		return validatePolicyJustification(env, blobPath)
+	case tombstonesTreeEntryName:
+		return validateTombstone(env, blobPath)
+	}
+
+	return ErrUnknownAttestationPath
+}
+
+// GetEnvelope returns the attestation (with its signatures) stored at the
+// specified path. The path must be of the form returned by Envelopes.
+func (a *Attestations) GetEnvelope(repo *git.Repository, attestationPath string) (*sslibdsse.Envelope, error) {
+	treeName, blobPath, err := splitAttestationPath(attestationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobIDs map[string]plumbing.Hash
+	switch treeName {
+	case referenceAuthorizationsTreeEntryName:
+		blobIDs = a.referenceAuthorizations
+	case githubPullRequestAttestationsTreeEntryName:
+		blobIDs = a.githubPullRequestAttestations
+	case gerritChangeAttestationsTreeEntryName:
+		blobIDs = a.gerritChangeAttestations
+	case bitbucketPullRequestsTreeEntryName:
+		blobIDs = a.bitbucketPullRequestAttestations
+	case reviewApprovalsTreeEntryName:
+		blobIDs = a.reviewApprovalAttestations
+	case pushEventAttestationsTreeEntryName:
+		blobIDs = a.pushEventAttestations
+	case ciRunAttestationsTreeEntryName:
+		blobIDs = a.ciRunAttestations
+	case hookExecutionAttestationsTreeEntryName:
+		blobIDs = a.hookExecutionAttestations
+	case rebuildAttestationsTreeEntryName:
+		blobIDs = a.rebuildAttestations
+	case lfsObjectsAttestationsTreeEntryName:
+		blobIDs = a.lfsObjectsAttestations
+	case statementsTreeEntryName:
+		blobIDs = a.statementAttestations
+	case policyJustificationsTreeEntryName:
+		blobIDs = a.policyJustifications
+	case tombstonesTreeEntryName:
+		blobIDs = a.tombstones
+	default:
+		return nil, ErrUnknownAttestationPath
+	}
+
+	blobID, has := blobIDs[blobPath]
+	if !has {
+		return nil, ErrAttestationNotFound
+	}
+
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// SetEnvelope writes the envelope to the repository and records it at the
+// specified attestation path, replacing any existing attestation at that path.
+// The path must be of the form returned by Envelopes.
+func (a *Attestations) SetEnvelope(repo *git.Repository, attestationPath string, env *sslibdsse.Envelope) error {
+	treeName, blobPath, err := splitAttestationPath(attestationPath)
+	if err != nil {
+		return err
+	}
+
+	switch treeName {
+	case referenceAuthorizationsTreeEntryName:
+		refName, fromID, toID, err := splitReferenceAuthorizationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return a.SetReferenceAuthorization(repo, env, refName, fromID, toID)
+	case githubPullRequestAttestationsTreeEntryName:
+		refName, commitID := path.Split(blobPath)
+		if refName == "" || commitID == "" {
+			return ErrUnknownAttestationPath
+		}
+
+		return a.SetGitHubPullRequestAuthorization(repo, env, path.Clean(refName), commitID)
+	case gerritChangeAttestationsTreeEntryName:
+		refName, commitID, err := splitGerritChangeAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return a.SetGerritChangeAttestation(repo, env, refName, commitID)
+	case bitbucketPullRequestsTreeEntryName:
+		refName, commitID, err := splitBitbucketPullRequestAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return a.SetBitbucketPullRequestAttestation(repo, env, refName, commitID)
+	case reviewApprovalsTreeEntryName:
+		refName, targetID, err := splitReviewApprovalAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return a.SetReviewApprovalAttestation(repo, env, refName, targetID)
+	case pushEventAttestationsTreeEntryName:
+		return a.SetPushEventAttestation(repo, env, blobPath)
+	case ciRunAttestationsTreeEntryName:
+		commitID, provider, runID, err := splitCIRunAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return a.SetCIRunAttestation(repo, env, commitID, provider, runID)
+	case hookExecutionAttestationsTreeEntryName:
+		refName, targetID, hookName, err := splitHookExecutionPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return a.SetHookExecutionAttestation(repo, env, refName, targetID, hookName)
+	case rebuildAttestationsTreeEntryName:
+		commitID, artifactName, artifactDigest, err := splitRebuildAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		return a.SetRebuildAttestation(repo, env, commitID, artifactName, artifactDigest)
+	case lfsObjectsAttestationsTreeEntryName:
+		return a.SetLFSObjectsAttestation(repo, env, blobPath)
+	case statementsTreeEntryName:
+		refName, targetID, payloadDigest, err := splitStatementAttestationPath(blobPath)
+		if err != nil {
+			return err
+		}
+
+		// The statement is stored at the path for its payload's digest, so
+		// it must match the path's digest
+		if err := validateStatementAttestation(env, refName, targetID, payloadDigest); err != nil {
+			return err
+		}
+
+		return a.SetStatementAttestation(repo, env, refName, targetID)
+	case policyJustificationsTreeEntryName:
+		return a.SetPolicyJustification(repo, env, blobPath)
+	case tombstonesTreeEntryName:
+		return a.SetTombstone(repo, env, blobPath)
+	}
+
+	return ErrUnknownAttestationPath
+}
+
+func splitAttestationPath(attestationPath string) (string, string, error) {
+	if path.Clean(attestationPath) != attestationPath {
+		return "", "", ErrUnknownAttestationPath
+	}
+
+	treeName, blobPath, found := strings.Cut(attestationPath, "/")
+	if !found || blobPath == "" {
+		return "", "", ErrUnknownAttestationPath
+	}
+
+	return treeName, blobPath, nil
+}
+
+// splitReferenceAuthorizationPath is the inverse of
+// ReferenceAuthorizationPath.
+func splitReferenceAuthorizationPath(authPath string) (string, string, string, error) {
+	refName, change := path.Split(authPath)
+	fromID, toID, found := strings.Cut(change, "-")
+	if refName == "" || !found {
+		return "", "", "", ErrUnknownAttestationPath
+	}
+
+	return path.Clean(refName), fromID, toID, nil
+}
+
+// splitCIRunAttestationPath is the inverse of CIRunAttestationPath.
+func splitCIRunAttestationPath(ciRunPath string) (string, string, string, error) {
+	components := strings.Split(ciRunPath, "/")
+	if len(components) != 3 {
+		return "", "", "", ErrUnknownAttestationPath
+	}
+
+	return components[0], components[1], components[2], nil
+}
+
+// splitRebuildAttestationPath is the inverse of RebuildAttestationPath.
+func splitRebuildAttestationPath(rebuildPath string) (string, string, string, error) {
+	components := strings.Split(rebuildPath, "/")
+	if len(components) != 3 {
+		return "", "", "", ErrUnknownAttestationPath
+	}
+
+	return components[0], components[1], components[2], nil
+}
+
+// splitHookExecutionPath is the inverse of HookExecutionPath.
+func splitHookExecutionPath(hookExecutionPath string) (string, string, string, error) {
+	refPath, hookName := path.Split(hookExecutionPath)
+	refName, targetID := path.Split(path.Clean(refPath))
+	if refName == "" || targetID == "" || hookName == "" {
+		return "", "", "", ErrUnknownAttestationPath
+	}
+
+	return path.Clean(refName), targetID, hookName, nil
+}
+
+// isValidPathComponent checks that the value can be used as a single component
+// of an attestation's path.
+func isValidPathComponent(value string) bool {
+	return value != "" && value != "." && value != ".." && !strings.Contains(value, "/")
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetEnvelope(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	mainZeroZero := createReferenceAuthorizationAttestationEnvelopes(t, testRef, testID, testID)
+	authPath := referenceAuthorizationsTreeEntryName + "/" + ReferenceAuthorizationPath(testRef, testID, testID)
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		attestations := &Attestations{}
+		err := attestations.SetEnvelope(repo, authPath, mainZeroZero)
+		assert.Nil(t, err)
+
+		env, err := attestations.GetReferenceAuthorizationFor(repo, testRef, testID, testID)
+		assert.Nil(t, err)
+		assert.Equal(t, mainZeroZero, env)
+
+		envelopes, err := attestations.Envelopes(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, mainZeroZero, envelopes[authPath])
+
+		env, err = attestations.GetEnvelope(repo, authPath)
+		assert.Nil(t, err)
+		assert.Equal(t, mainZeroZero, env)
+
+		_, err = attestations.GetEnvelope(repo, pushEventAttestationsTreeEntryName+"/"+testID)
+		assert.ErrorIs(t, err, ErrAttestationNotFound)
+	})
+
+	t.Run("mismatched contents", func(t *testing.T) {
+		attestations := &Attestations{}
+		err := attestations.SetEnvelope(repo, referenceAuthorizationsTreeEntryName+"/"+ReferenceAuthorizationPath("refs/heads/feature", testID, testID), mainZeroZero)
+		assert.ErrorIs(t, err, ErrInvalidAuthorization)
+	})
+
+	t.Run("unknown paths", func(t *testing.T) {
+		attestations := &Attestations{}
+		for _, attestationPath := range []string{
+			"unknown/" + ReferenceAuthorizationPath(testRef, testID, testID),
+			referenceAuthorizationsTreeEntryName,
+			referenceAuthorizationsTreeEntryName + "/../" + ReferenceAuthorizationPath(testRef, testID, testID),
+			referenceAuthorizationsTreeEntryName + "/" + testRef,
+		} {
+			err := attestations.SetEnvelope(repo, attestationPath, mainZeroZero)
+			assert.ErrorIs(t, err, ErrUnknownAttestationPath, attestationPath)
+		}
+	})
+}
+
+func TestValidateEnvelope(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	mainZeroZero := createReferenceAuthorizationAttestationEnvelopes(t, testRef, testID, testID)
+
+	err := ValidateEnvelope(referenceAuthorizationsTreeEntryName+"/"+ReferenceAuthorizationPath(testRef, testID, testID), mainZeroZero)
+	assert.Nil(t, err)
+
+	err = ValidateEnvelope(referenceAuthorizationsTreeEntryName+"/"+ReferenceAuthorizationPath(testRef, testID, "abcdef"), mainZeroZero)
+	assert.ErrorIs(t, err, ErrInvalidAuthorization)
+
+	err = ValidateEnvelope(pushEventAttestationsTreeEntryName+"/"+testID, mainZeroZero)
+	assert.ErrorIs(t, err, ErrInvalidPushEvent)
+
+	err = ValidateEnvelope("unknown/"+testID, mainZeroZero)
+	assert.ErrorIs(t, err, ErrUnknownAttestationPath)
+}
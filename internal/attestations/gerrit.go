@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+
+	"github.com/gittuf/gittuf/internal/gerrit"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const GerritChangePredicateType = "https://gittuf.dev/gerrit-change/v0.1"
+
+var (
+	ErrGerritChangeNotFound = errors.New("requested Gerrit change attestation not found")
+	ErrInvalidGerritChange  = errors.New("Gerrit change attestation does not match expected details") //nolint:stylecheck
+)
+
+// NewGerritChangeAttestation creates a new attestation recording the Gerrit
+// change submitted as commitID, including the approvals the change received.
+// The change is embedded in an in-toto "statement" and returned with the
+// appropriate "predicate type" set.
+func NewGerritChangeAttestation(change *gerrit.Change, commitID string) (*ita.Statement, error) {
+	changeBytes, err := json.Marshal(change)
+	if err != nil {
+		return nil, err
+	}
+
+	predicate := map[string]any{}
+	if err := json.Unmarshal(changeBytes, &predicate); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Uri:    change.URL,
+				Digest: map[string]string{digestGitCommitKey: commitID},
+			},
+		},
+		PredicateType: GerritChangePredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// GerritChangeAttestationPath constructs the expected path on-disk for the
+// Gerrit change attestation.
+func GerritChangeAttestationPath(refName, commitID string) string {
+	return path.Join(refName, commitID)
+}
+
+// SetGerritChangeAttestation writes the new Gerrit change attestation to the
+// object store and tracks it in the current attestations state.
+func (a *Attestations) SetGerritChangeAttestation(repo *git.Repository, env *sslibdsse.Envelope, refName, commitID string) error {
+	if err := validateGerritChangeAttestation(env, refName, commitID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.gerritChangeAttestations == nil {
+		a.gerritChangeAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.gerritChangeAttestations[GerritChangeAttestationPath(refName, commitID)] = blobID
+	return nil
+}
+
+// GetGerritChangeAttestationFor returns the Gerrit change attestation (with
+// its signatures) for the change submitted to refName as commitID.
+func (a *Attestations) GetGerritChangeAttestationFor(repo *git.Repository, refName, commitID string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.gerritChangeAttestations[GerritChangeAttestationPath(refName, commitID)]
+	if !has {
+		return nil, ErrGerritChangeNotFound
+	}
+
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateGerritChangeAttestation(env, refName, commitID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func validateGerritChangeAttestation(env *sslibdsse.Envelope, refName, commitID string) error {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return err
+	}
+
+	if attestation.PredicateType != GerritChangePredicateType || len(attestation.Subject) == 0 {
+		return ErrInvalidGerritChange
+	}
+
+	if attestation.Subject[0].Digest[digestGitCommitKey] != commitID {
+		return ErrInvalidGerritChange
+	}
+
+	predicateBytes, err := json.Marshal(attestation.Predicate.AsMap())
+	if err != nil {
+		return err
+	}
+
+	change := &gerrit.Change{}
+	if err := json.Unmarshal(predicateBytes, change); err != nil {
+		return err
+	}
+
+	if change.RefName() != refName {
+		return ErrInvalidGerritChange
+	}
+
+	return nil
+}
+
+// splitGerritChangeAttestationPath is the inverse of
+// GerritChangeAttestationPath.
+func splitGerritChangeAttestationPath(changePath string) (string, string, error) {
+	refName, commitID := path.Split(changePath)
+	if refName == "" || commitID == "" {
+		return "", "", ErrUnknownAttestationPath
+	}
+
+	return path.Clean(refName), commitID, nil
+}
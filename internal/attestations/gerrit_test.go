@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gerrit"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func testGerritChange() *gerrit.Change {
+	return &gerrit.Change{
+		URL:      "https://gerrit.example.com/c/gittuf/+/1234",
+		Project:  "gittuf",
+		Branch:   "main",
+		ChangeID: "I8473b95934b5732ac55d26311a706c9c2bde9940",
+		Number:   1234,
+		PatchSet: 3,
+		Status:   "MERGED",
+		Owner:    gerrit.Account{Username: "jane"},
+		Approvals: []gerrit.Approval{
+			{Label: "Code-Review", Value: 2, Account: gerrit.Account{Username: "john"}},
+		},
+	}
+}
+
+func TestNewGerritChangeAttestation(t *testing.T) {
+	commitID := "abcdef1234567890abcdef1234567890abcdef12"
+
+	attestation, err := NewGerritChangeAttestation(testGerritChange(), commitID)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, "https://gerrit.example.com/c/gittuf/+/1234", attestation.Subject[0].Uri)
+	assert.Equal(t, commitID, attestation.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, GerritChangePredicateType, attestation.PredicateType)
+
+	predicate := attestation.Predicate.AsMap()
+	assert.Equal(t, "main", predicate["branch"])
+	assert.Equal(t, float64(1234), predicate["number"])
+	assert.Equal(t, []any{
+		map[string]any{
+			"label":   "Code-Review",
+			"value":   float64(2),
+			"account": map[string]any{"username": "john"},
+		},
+	}, predicate["approvals"])
+}
+
+func TestSetAndGetGerritChangeAttestation(t *testing.T) {
+	refName := "refs/heads/main"
+	commitID := "abcdef1234567890abcdef1234567890abcdef12"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := NewGerritChangeAttestation(testGerritChange(), commitID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	_, err = attestations.GetGerritChangeAttestationFor(repo, refName, commitID)
+	assert.ErrorIs(t, err, ErrGerritChangeNotFound)
+
+	err = attestations.SetGerritChangeAttestation(repo, env, refName, plumbing.ZeroHash.String())
+	assert.ErrorIs(t, err, ErrInvalidGerritChange)
+
+	err = attestations.SetGerritChangeAttestation(repo, env, "refs/heads/feature", commitID)
+	assert.ErrorIs(t, err, ErrInvalidGerritChange)
+
+	err = attestations.SetGerritChangeAttestation(repo, env, refName, commitID)
+	assert.Nil(t, err)
+
+	storedEnv, err := attestations.GetGerritChangeAttestationFor(repo, refName, commitID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	attestationPath := gerritChangeAttestationsTreeEntryName + "/" + GerritChangeAttestationPath(refName, commitID)
+	assert.Nil(t, ValidateEnvelope(attestationPath, env))
+
+	storedEnv, err = attestations.GetEnvelope(repo, attestationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+}
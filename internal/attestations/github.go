@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"path"
 
-	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/google/go-github/v61/github"
@@ -56,7 +55,7 @@ func (a *Attestations) SetGitHubPullRequestAuthorization(repo *git.Repository, e
 		return err
 	}
 
-	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	blobID, err := a.writeBlob(repo, envBytes)
 	if err != nil {
 		return err
 	}
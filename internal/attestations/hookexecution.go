@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	HookExecutionPredicateType = "https://gittuf.dev/hook-execution/v0.1"
+
+	HookResultPass = "pass"
+	HookResultFail = "fail"
+
+	hookNameKey   = "hookName"
+	hookResultKey = "result"
+	refNameKey    = "refName"
+)
+
+var (
+	ErrHookExecutionNotFound = errors.New("requested hook execution attestation not found")
+	ErrInvalidHookExecution  = errors.New("hook execution attestation does not match expected details")
+)
+
+// HookExecution records that a hook was executed for a Git reference and the
+// result of the execution. It is meant to be used as a "predicate" in an
+// in-toto attestation.
+type HookExecution struct {
+	// HookName identifies the hook, such as `secret-scan`.
+	HookName string `json:"hookName"`
+
+	// HookDigest is the SHA-256 digest of the hook that was executed,
+	// identifying the version of the hook.
+	HookDigest string `json:"hookDigest"`
+
+	// RefName and TargetID identify the state of the reference the hook was
+	// executed for.
+	RefName  string `json:"refName"`
+	TargetID string `json:"targetID"`
+
+	// ExitCode and Result record the outcome of the execution. Result is
+	// `pass` if the hook exited successfully and `fail` otherwise.
+	ExitCode int    `json:"exitCode"`
+	Result   string `json:"result"`
+}
+
+// NewHookExecutionAttestation creates a new hook execution attestation for the
+// provided information. The hook execution is embedded in an in-toto
+// "statement" and returned with the appropriate "predicate type" set. The
+// subject of the statement is the target of the reference the hook was
+// executed for.
+func NewHookExecutionAttestation(hookExecution *HookExecution) (*ita.Statement, error) {
+	if !isValidPathComponent(hookExecution.HookName) || hookExecution.RefName == "" || hookExecution.TargetID == "" {
+		return nil, ErrInvalidHookExecution
+	}
+
+	predicateBytes, err := json.Marshal(hookExecution)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Digest: map[string]string{digestGitCommitKey: hookExecution.TargetID},
+			},
+		},
+		PredicateType: HookExecutionPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// HookExecutionPath constructs the expected path on-disk for the hook
+// execution attestation.
+func HookExecutionPath(refName, targetID, hookName string) string {
+	return path.Join(refName, targetID, hookName)
+}
+
+// SetHookExecutionAttestation writes the new hook execution attestation to
+// the object store and tracks it in the current attestations state. An
+// existing attestation for the same hook and reference state is replaced.
+func (a *Attestations) SetHookExecutionAttestation(repo *git.Repository, env *sslibdsse.Envelope, refName, targetID, hookName string) error {
+	if err := validateHookExecutionAttestation(env, refName, targetID, hookName); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.hookExecutionAttestations == nil {
+		a.hookExecutionAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.hookExecutionAttestations[HookExecutionPath(refName, targetID, hookName)] = blobID
+	return nil
+}
+
+// GetHookExecutionAttestationFor returns the hook execution attestation (with
+// its signatures) for the specified hook and reference state.
+func (a *Attestations) GetHookExecutionAttestationFor(repo *git.Repository, refName, targetID, hookName string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.hookExecutionAttestations[HookExecutionPath(refName, targetID, hookName)]
+	if !has {
+		return nil, ErrHookExecutionNotFound
+	}
+
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateHookExecutionAttestation(env, refName, targetID, hookName); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// GetHookExecutionResult returns the recorded result of the hook execution
+// attestation.
+func GetHookExecutionResult(env *sslibdsse.Envelope) (string, error) {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return "", err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return "", err
+	}
+
+	if attestation.PredicateType != HookExecutionPredicateType {
+		return "", ErrInvalidHookExecution
+	}
+
+	result, ok := attestation.Predicate.AsMap()[hookResultKey].(string)
+	if !ok {
+		return "", ErrInvalidHookExecution
+	}
+
+	return result, nil
+}
+
+func validateHookExecutionAttestation(env *sslibdsse.Envelope, refName, targetID, hookName string) error {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return err
+	}
+
+	if attestation.PredicateType != HookExecutionPredicateType || len(attestation.Subject) == 0 {
+		return ErrInvalidHookExecution
+	}
+
+	if attestation.Subject[0].Digest[digestGitCommitKey] != targetID {
+		return ErrInvalidHookExecution
+	}
+
+	predicate := attestation.Predicate.AsMap()
+	if predicate[refNameKey] != refName || predicate[targetIDKey] != targetID || predicate[hookNameKey] != hookName {
+		return ErrInvalidHookExecution
+	}
+
+	return nil
+}
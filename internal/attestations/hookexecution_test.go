@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHookExecutionAttestation(t *testing.T) {
+	hookExecution := &HookExecution{
+		HookName:   "secret-scan",
+		HookDigest: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		RefName:    "refs/heads/main",
+		TargetID:   "abcdef1234567890abcdef1234567890abcdef12",
+		ExitCode:   1,
+		Result:     HookResultFail,
+	}
+
+	attestation, err := NewHookExecutionAttestation(hookExecution)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, hookExecution.TargetID, attestation.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, HookExecutionPredicateType, attestation.PredicateType)
+
+	predicate := attestation.Predicate.AsMap()
+	assert.Equal(t, hookExecution.HookName, predicate[hookNameKey])
+	assert.Equal(t, hookExecution.HookDigest, predicate["hookDigest"])
+	assert.Equal(t, float64(1), predicate["exitCode"])
+	assert.Equal(t, HookResultFail, predicate[hookResultKey])
+
+	_, err = NewHookExecutionAttestation(&HookExecution{HookName: "scan/secrets", RefName: "refs/heads/main", TargetID: hookExecution.TargetID})
+	assert.ErrorIs(t, err, ErrInvalidHookExecution)
+}
+
+func TestSetAndGetHookExecutionAttestation(t *testing.T) {
+	refName := "refs/heads/main"
+	targetID := "abcdef1234567890abcdef1234567890abcdef12"
+	hookName := "secret-scan"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := NewHookExecutionAttestation(&HookExecution{HookName: hookName, RefName: refName, TargetID: targetID, Result: HookResultPass})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	_, err = attestations.GetHookExecutionAttestationFor(repo, refName, targetID, hookName)
+	assert.ErrorIs(t, err, ErrHookExecutionNotFound)
+
+	err = attestations.SetHookExecutionAttestation(repo, env, refName, plumbing.ZeroHash.String(), hookName)
+	assert.ErrorIs(t, err, ErrInvalidHookExecution)
+
+	err = attestations.SetHookExecutionAttestation(repo, env, refName, targetID, "lint")
+	assert.ErrorIs(t, err, ErrInvalidHookExecution)
+
+	err = attestations.SetHookExecutionAttestation(repo, env, refName, targetID, hookName)
+	assert.Nil(t, err)
+
+	storedEnv, err := attestations.GetHookExecutionAttestationFor(repo, refName, targetID, hookName)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	result, err := GetHookExecutionResult(storedEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, HookResultPass, result)
+
+	attestationPath := hookExecutionAttestationsTreeEntryName + "/" + HookExecutionPath(refName, targetID, hookName)
+	storedEnv, err = attestations.GetEnvelope(repo, attestationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+	assert.Nil(t, ValidateEnvelope(attestationPath, env))
+}
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gittuf/gittuf/internal/lfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const LFSObjectsPredicateType = "https://gittuf.dev/lfs-objects/v0.1"
+
+var (
+	ErrLFSObjectsNotFound = errors.New("requested Git LFS objects attestation not found")
+	ErrInvalidLFSObjects  = errors.New("Git LFS objects attestation does not match expected details") //nolint:stylecheck
+)
+
+// LFSObjects records the set of Git LFS objects referenced by a commit, and is
+// meant to be used as a "predicate" in an in-toto attestation. It is typically
+// recorded for releases, after the objects were checked to exist and match
+// their pointers.
+type LFSObjects struct {
+	// CommitID is the ID of the commit that references the objects.
+	CommitID string `json:"commitID"`
+
+	// Objects are the pointers in the commit's tree, sorted by path.
+	Objects []*lfs.Pointer `json:"objects"`
+}
+
+// NewLFSObjectsAttestation creates a new Git LFS objects attestation for the
+// provided information. The objects are embedded in an in-toto "statement" and
+// returned with the appropriate "predicate type" set. The subject of the
+// statement is the commit referencing the objects.
+func NewLFSObjectsAttestation(objects *LFSObjects) (*ita.Statement, error) {
+	if !isValidPathComponent(objects.CommitID) || objects.Objects == nil {
+		return nil, ErrInvalidLFSObjects
+	}
+
+	predicateBytes, err := json.Marshal(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Digest: map[string]string{digestGitCommitKey: objects.CommitID},
+			},
+		},
+		PredicateType: LFSObjectsPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// SetLFSObjectsAttestation writes the new Git LFS objects attestation to the
+// object store and tracks it in the current attestations state.
+func (a *Attestations) SetLFSObjectsAttestation(repo *git.Repository, env *sslibdsse.Envelope, commitID string) error {
+	if err := validateLFSObjectsAttestation(env, commitID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.lfsObjectsAttestations == nil {
+		a.lfsObjectsAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.lfsObjectsAttestations[commitID] = blobID
+	return nil
+}
+
+// GetLFSObjectsAttestationFor returns the Git LFS objects attestation (with
+// its signatures) for the specified commit.
+func (a *Attestations) GetLFSObjectsAttestationFor(repo *git.Repository, commitID string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.lfsObjectsAttestations[commitID]
+	if !has {
+		return nil, ErrLFSObjectsNotFound
+	}
+
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateLFSObjectsAttestation(env, commitID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// GetLFSObjects returns the Git LFS objects recorded in the attestation.
+func GetLFSObjects(env *sslibdsse.Envelope) (*LFSObjects, error) {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return nil, err
+	}
+
+	if attestation.PredicateType != LFSObjectsPredicateType {
+		return nil, ErrInvalidLFSObjects
+	}
+
+	predicateBytes, err := json.Marshal(attestation.Predicate.AsMap())
+	if err != nil {
+		return nil, err
+	}
+
+	objects := &LFSObjects{}
+	if err := json.Unmarshal(predicateBytes, objects); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func validateLFSObjectsAttestation(env *sslibdsse.Envelope, commitID string) error {
+	if !isValidPathComponent(commitID) {
+		return ErrInvalidLFSObjects
+	}
+
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return err
+	}
+
+	if attestation.PredicateType != LFSObjectsPredicateType || len(attestation.Subject) == 0 {
+		return ErrInvalidLFSObjects
+	}
+
+	if attestation.Subject[0].Digest[digestGitCommitKey] != commitID {
+		return ErrInvalidLFSObjects
+	}
+
+	if attestation.Predicate.AsMap()[commitIDKey] != commitID {
+		return ErrInvalidLFSObjects
+	}
+
+	return nil
+}
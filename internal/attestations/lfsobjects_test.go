@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/lfs"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLFSObjectsAttestation(t *testing.T) {
+	objects := &LFSObjects{
+		CommitID: "abcdef1234567890abcdef1234567890abcdef12",
+		Objects: []*lfs.Pointer{
+			{Path: "assets/logo.png", OID: "dd0401f025a48d86243d4bd336483566b84d4ab7d1b15eb612c88ad02cee59db", Size: 10},
+		},
+	}
+
+	attestation, err := NewLFSObjectsAttestation(objects)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, objects.CommitID, attestation.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, LFSObjectsPredicateType, attestation.PredicateType)
+	assert.Equal(t, objects.CommitID, attestation.Predicate.AsMap()[commitIDKey])
+
+	// A commit may not reference any objects
+	_, err = NewLFSObjectsAttestation(&LFSObjects{CommitID: objects.CommitID, Objects: []*lfs.Pointer{}})
+	assert.Nil(t, err)
+
+	_, err = NewLFSObjectsAttestation(&LFSObjects{CommitID: objects.CommitID})
+	assert.ErrorIs(t, err, ErrInvalidLFSObjects)
+}
+
+func TestSetAndGetLFSObjectsAttestation(t *testing.T) {
+	commitID := "abcdef1234567890abcdef1234567890abcdef12"
+	objects := &LFSObjects{
+		CommitID: commitID,
+		Objects: []*lfs.Pointer{
+			{Path: "assets/logo.png", OID: "dd0401f025a48d86243d4bd336483566b84d4ab7d1b15eb612c88ad02cee59db", Size: 10},
+			{Path: "assets/video.mp4", OID: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", Size: 0},
+		},
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := NewLFSObjectsAttestation(objects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	_, err = attestations.GetLFSObjectsAttestationFor(repo, commitID)
+	assert.ErrorIs(t, err, ErrLFSObjectsNotFound)
+
+	err = attestations.SetLFSObjectsAttestation(repo, env, plumbing.ZeroHash.String())
+	assert.ErrorIs(t, err, ErrInvalidLFSObjects)
+
+	err = attestations.SetLFSObjectsAttestation(repo, env, commitID)
+	assert.Nil(t, err)
+
+	storedEnv, err := attestations.GetLFSObjectsAttestationFor(repo, commitID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	storedObjects, err := GetLFSObjects(storedEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, objects, storedObjects)
+
+	attestationPath := lfsObjectsAttestationsTreeEntryName + "/" + commitID
+	storedEnv, err = attestations.GetEnvelope(repo, attestationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+	assert.Nil(t, ValidateEnvelope(attestationPath, env))
+}
@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	PolicyJustificationPredicateType = "https://gittuf.dev/policy-justification/v0.1"
+
+	policyCommitIDKey = "policyCommitID"
+)
+
+var (
+	ErrPolicyJustificationNotFound = errors.New("requested policy justification not found")
+	ErrInvalidPolicyJustification  = errors.New("policy justification does not match expected details")
+)
+
+// PolicyJustification records why a change was made to the policy, and is
+// meant to be used as a "predicate" in an in-toto attestation. The reason is
+// required, while the ticket and approver are optional.
+type PolicyJustification struct {
+	// PolicyCommitID is the ID of the policy commit that the justification is
+	// for.
+	PolicyCommitID string `json:"policyCommitID"`
+
+	// Reason describes why the policy was changed.
+	Reason string `json:"reason"`
+
+	// Ticket links to the issue or change request tracking the policy change.
+	Ticket string `json:"ticket,omitempty"`
+
+	// Approver identifies the person who approved the policy change.
+	Approver string `json:"approver,omitempty"`
+}
+
+// NewPolicyJustificationAttestation creates a new policy justification
+// attestation for the provided information. The justification is embedded in
+// an in-toto "statement" and returned with the appropriate "predicate type"
+// set. The subject of the statement is the policy commit.
+func NewPolicyJustificationAttestation(justification *PolicyJustification) (*ita.Statement, error) {
+	if !isValidPathComponent(justification.PolicyCommitID) || justification.Reason == "" {
+		return nil, ErrInvalidPolicyJustification
+	}
+
+	predicateBytes, err := json.Marshal(justification)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Digest: map[string]string{digestGitCommitKey: justification.PolicyCommitID},
+			},
+		},
+		PredicateType: PolicyJustificationPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// SetPolicyJustification writes the new policy justification to the object
+// store and tracks it in the current attestations state.
+func (a *Attestations) SetPolicyJustification(repo *git.Repository, env *sslibdsse.Envelope, policyCommitID string) error {
+	if err := validatePolicyJustification(env, policyCommitID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.policyJustifications == nil {
+		a.policyJustifications = map[string]plumbing.Hash{}
+	}
+
+	a.policyJustifications[policyCommitID] = blobID
+	return nil
+}
+
+// GetPolicyJustificationFor returns the policy justification (with its
+// signatures) for the specified policy commit.
+func (a *Attestations) GetPolicyJustificationFor(repo *git.Repository, policyCommitID string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.policyJustifications[policyCommitID]
+	if !has {
+		return nil, ErrPolicyJustificationNotFound
+	}
+
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validatePolicyJustification(env, policyCommitID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// GetPolicyJustification returns the justification recorded in the policy
+// justification attestation.
+func GetPolicyJustification(env *sslibdsse.Envelope) (*PolicyJustification, error) {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return nil, err
+	}
+
+	if attestation.PredicateType != PolicyJustificationPredicateType {
+		return nil, ErrInvalidPolicyJustification
+	}
+
+	predicateBytes, err := json.Marshal(attestation.Predicate.AsMap())
+	if err != nil {
+		return nil, err
+	}
+
+	justification := &PolicyJustification{}
+	if err := json.Unmarshal(predicateBytes, justification); err != nil {
+		return nil, err
+	}
+
+	return justification, nil
+}
+
+func validatePolicyJustification(env *sslibdsse.Envelope, policyCommitID string) error {
+	if !isValidPathComponent(policyCommitID) {
+		return ErrInvalidPolicyJustification
+	}
+
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return err
+	}
+
+	if attestation.PredicateType != PolicyJustificationPredicateType || len(attestation.Subject) == 0 {
+		return ErrInvalidPolicyJustification
+	}
+
+	if attestation.Subject[0].Digest[digestGitCommitKey] != policyCommitID {
+		return ErrInvalidPolicyJustification
+	}
+
+	if attestation.Predicate.AsMap()[policyCommitIDKey] != policyCommitID {
+		return ErrInvalidPolicyJustification
+	}
+
+	return nil
+}
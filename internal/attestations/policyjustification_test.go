@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPolicyJustificationAttestation(t *testing.T) {
+	justification := &PolicyJustification{
+		PolicyCommitID: "abcdef1234567890abcdef1234567890abcdef12",
+		Reason:         "Rotate Alice's key",
+		Ticket:         "https://github.com/gittuf/gittuf/issues/1",
+		Approver:       "Bob",
+	}
+
+	attestation, err := NewPolicyJustificationAttestation(justification)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, justification.PolicyCommitID, attestation.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, PolicyJustificationPredicateType, attestation.PredicateType)
+
+	predicate := attestation.Predicate.AsMap()
+	assert.Equal(t, justification.PolicyCommitID, predicate[policyCommitIDKey])
+	assert.Equal(t, justification.Reason, predicate["reason"])
+	assert.Equal(t, justification.Ticket, predicate["ticket"])
+	assert.Equal(t, justification.Approver, predicate["approver"])
+
+	// The ticket and approver are optional
+	attestation, err = NewPolicyJustificationAttestation(&PolicyJustification{PolicyCommitID: justification.PolicyCommitID, Reason: justification.Reason})
+	assert.Nil(t, err)
+	assert.NotContains(t, attestation.Predicate.AsMap(), "ticket")
+	assert.NotContains(t, attestation.Predicate.AsMap(), "approver")
+
+	_, err = NewPolicyJustificationAttestation(&PolicyJustification{PolicyCommitID: justification.PolicyCommitID})
+	assert.ErrorIs(t, err, ErrInvalidPolicyJustification)
+}
+
+func TestSetAndGetPolicyJustification(t *testing.T) {
+	policyCommitID := "abcdef1234567890abcdef1234567890abcdef12"
+	justification := &PolicyJustification{
+		PolicyCommitID: policyCommitID,
+		Reason:         "Rotate Alice's key",
+		Ticket:         "https://github.com/gittuf/gittuf/issues/1",
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := NewPolicyJustificationAttestation(justification)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	_, err = attestations.GetPolicyJustificationFor(repo, policyCommitID)
+	assert.ErrorIs(t, err, ErrPolicyJustificationNotFound)
+
+	err = attestations.SetPolicyJustification(repo, env, plumbing.ZeroHash.String())
+	assert.ErrorIs(t, err, ErrInvalidPolicyJustification)
+
+	err = attestations.SetPolicyJustification(repo, env, policyCommitID)
+	assert.Nil(t, err)
+
+	storedEnv, err := attestations.GetPolicyJustificationFor(repo, policyCommitID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	storedJustification, err := GetPolicyJustification(storedEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, justification, storedJustification)
+
+	attestationPath := policyJustificationsTreeEntryName + "/" + policyCommitID
+	storedEnv, err = attestations.GetEnvelope(repo, attestationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+	assert.Nil(t, ValidateEnvelope(attestationPath, env))
+}
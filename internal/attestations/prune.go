@@ -0,0 +1,428 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	TombstonePredicateType = "https://gittuf.dev/tombstone/v0.1"
+
+	// PruneReasonSuperseded indicates the attestation is about a state of a
+	// Git reference that the reference has since moved on from, as recorded
+	// in the RSL.
+	PruneReasonSuperseded = "superseded"
+
+	// PruneReasonUnreachable indicates the attestation is about an object
+	// that is not reachable from any of the repository's references.
+	PruneReasonUnreachable = "unreachable"
+
+	prunedFromKey = "prunedFrom"
+)
+
+var (
+	ErrTombstoneNotFound = errors.New("requested tombstone not found")
+	ErrInvalidTombstone  = errors.New("tombstone does not match expected details")
+)
+
+// Tombstone records the attestations removed from the current state of the
+// attestations namespace when it was pruned, and is meant to be used as a
+// "predicate" in an in-toto attestation. The pruned attestations remain
+// available in the commit the namespace was pruned from.
+type Tombstone struct {
+	// PrunedFrom is the ID of the attestations namespace's commit that the
+	// attestations were pruned from.
+	PrunedFrom string `json:"prunedFrom"`
+
+	// Attestations lists the attestations that were pruned.
+	Attestations []*PrunedAttestation `json:"attestations"`
+}
+
+// PrunedAttestation identifies an attestation that was pruned and the reason
+// it was pruned.
+type PrunedAttestation struct {
+	Path   string `json:"path"`
+	BlobID string `json:"blobID"`
+	Reason string `json:"reason"`
+}
+
+// NewTombstone creates a new tombstone for the attestations pruned from the
+// specified commit of the attestations namespace. The tombstone is embedded in
+// an in-toto "statement" and returned with the appropriate "predicate type"
+// set.
+func NewTombstone(prunedFrom string, pruned []*PrunedAttestation) (*ita.Statement, error) {
+	if !isValidPathComponent(prunedFrom) {
+		return nil, ErrInvalidTombstone
+	}
+
+	predicateBytes, err := json.Marshal(&Tombstone{PrunedFrom: prunedFrom, Attestations: pruned})
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Digest: map[string]string{digestGitCommitKey: prunedFrom},
+			},
+		},
+		PredicateType: TombstonePredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// SetTombstone writes the new tombstone to the object store and tracks it in
+// the current attestations state.
+func (a *Attestations) SetTombstone(repo *git.Repository, env *sslibdsse.Envelope, prunedFrom string) error {
+	if err := validateTombstone(env, prunedFrom); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.tombstones == nil {
+		a.tombstones = map[string]plumbing.Hash{}
+	}
+
+	a.tombstones[prunedFrom] = blobID
+	return nil
+}
+
+// GetTombstoneFor returns the tombstone (with its signatures) recorded when
+// the attestations were pruned from the specified commit.
+func (a *Attestations) GetTombstoneFor(repo *git.Repository, prunedFrom string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.tombstones[prunedFrom]
+	if !has {
+		return nil, ErrTombstoneNotFound
+	}
+
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateTombstone(env, prunedFrom); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// Prune removes the attestations that are no longer needed from the current
+// state, returning the attestations that were removed. An attestation is
+// pruned if it is superseded, i.e., it is about a state of a Git reference
+// that the RSL records the reference moving on from, or if it is about an
+// object that is not reachable from any of the repository's references.
+// Tombstones and push event attestations, which refer to RSL entries, and
+// policy justifications, which record the history of the policy, are never
+// pruned.
+//
+// The pruned attestations are only removed from the current state. Verifying
+// earlier RSL entries uses the attestations recorded at the time, so the
+// pruned attestations remain available for verification.
+func (a *Attestations) Prune(repo *git.Repository) ([]*PrunedAttestation, error) {
+	reachable, err := getReachableObjects(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := map[string]*refHistory{}
+	getHistory := func(refName string) (*refHistory, error) {
+		if history, has := histories[refName]; has {
+			return history, nil
+		}
+
+		history, err := loadRefHistory(repo, refName)
+		if err != nil {
+			return nil, err
+		}
+		histories[refName] = history
+		return history, nil
+	}
+
+	pruned := []*PrunedAttestation{}
+	prune := func(treeName string, blobIDs map[string]plumbing.Hash, blobPath, reason string) {
+		pruned = append(pruned, &PrunedAttestation{
+			Path:   path.Join(treeName, blobPath),
+			BlobID: blobIDs[blobPath].String(),
+			Reason: reason,
+		})
+		delete(blobIDs, blobPath)
+	}
+
+	for authPath := range a.referenceAuthorizations {
+		refName, fromID, _, err := splitReferenceAuthorizationPath(authPath)
+		if err != nil {
+			return nil, err
+		}
+
+		history, err := getHistory(refName)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case history.supersedes(fromID):
+			prune(referenceAuthorizationsTreeEntryName, a.referenceAuthorizations, authPath, PruneReasonSuperseded)
+		case fromID != plumbing.ZeroHash.String() && !reachable[plumbing.NewHash(fromID)]:
+			prune(referenceAuthorizationsTreeEntryName, a.referenceAuthorizations, authPath, PruneReasonUnreachable)
+		}
+	}
+
+	for hookExecutionPath := range a.hookExecutionAttestations {
+		refName, targetID, _, err := splitHookExecutionPath(hookExecutionPath)
+		if err != nil {
+			return nil, err
+		}
+
+		history, err := getHistory(refName)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case history.supersedes(targetID):
+			prune(hookExecutionAttestationsTreeEntryName, a.hookExecutionAttestations, hookExecutionPath, PruneReasonSuperseded)
+		case !reachable[plumbing.NewHash(targetID)]:
+			prune(hookExecutionAttestationsTreeEntryName, a.hookExecutionAttestations, hookExecutionPath, PruneReasonUnreachable)
+		}
+	}
+
+	for statementPath := range a.statementAttestations {
+		refName, targetID, _, err := splitStatementAttestationPath(statementPath)
+		if err != nil {
+			return nil, err
+		}
+
+		history, err := getHistory(refName)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case history.supersedes(targetID):
+			prune(statementsTreeEntryName, a.statementAttestations, statementPath, PruneReasonSuperseded)
+		case !reachable[plumbing.NewHash(targetID)]:
+			prune(statementsTreeEntryName, a.statementAttestations, statementPath, PruneReasonUnreachable)
+		}
+	}
+
+	for pullRequestPath := range a.githubPullRequestAttestations {
+		_, commitID := path.Split(pullRequestPath)
+		if !reachable[plumbing.NewHash(commitID)] {
+			prune(githubPullRequestAttestationsTreeEntryName, a.githubPullRequestAttestations, pullRequestPath, PruneReasonUnreachable)
+		}
+	}
+
+	for changePath := range a.gerritChangeAttestations {
+		_, commitID := path.Split(changePath)
+		if !reachable[plumbing.NewHash(commitID)] {
+			prune(gerritChangeAttestationsTreeEntryName, a.gerritChangeAttestations, changePath, PruneReasonUnreachable)
+		}
+	}
+
+	for pullRequestPath := range a.bitbucketPullRequestAttestations {
+		_, commitID := path.Split(pullRequestPath)
+		if !reachable[plumbing.NewHash(commitID)] {
+			prune(bitbucketPullRequestsTreeEntryName, a.bitbucketPullRequestAttestations, pullRequestPath, PruneReasonUnreachable)
+		}
+	}
+
+	for reviewPath := range a.reviewApprovalAttestations {
+		_, targetID := path.Split(reviewPath)
+		if !reachable[plumbing.NewHash(targetID)] {
+			prune(reviewApprovalsTreeEntryName, a.reviewApprovalAttestations, reviewPath, PruneReasonUnreachable)
+		}
+	}
+
+	for ciRunPath := range a.ciRunAttestations {
+		commitID, _, _, err := splitCIRunAttestationPath(ciRunPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reachable[plumbing.NewHash(commitID)] {
+			prune(ciRunAttestationsTreeEntryName, a.ciRunAttestations, ciRunPath, PruneReasonUnreachable)
+		}
+	}
+
+	for rebuildPath := range a.rebuildAttestations {
+		commitID, _, _, err := splitRebuildAttestationPath(rebuildPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reachable[plumbing.NewHash(commitID)] {
+			prune(rebuildAttestationsTreeEntryName, a.rebuildAttestations, rebuildPath, PruneReasonUnreachable)
+		}
+	}
+
+	for commitID := range a.lfsObjectsAttestations {
+		if !reachable[plumbing.NewHash(commitID)] {
+			prune(lfsObjectsAttestationsTreeEntryName, a.lfsObjectsAttestations, commitID, PruneReasonUnreachable)
+		}
+	}
+
+	sort.Slice(pruned, func(i, j int) bool {
+		return pruned[i].Path < pruned[j].Path
+	})
+
+	return pruned, nil
+}
+
+// refHistory tracks the targets recorded in the RSL for a Git reference.
+type refHistory struct {
+	latest  string
+	targets map[string]bool
+}
+
+// supersedes indicates if the RSL records the reference moving on from the
+// specified target. A zero target, used when the reference is created, is
+// superseded once the RSL has any entry for the reference.
+func (h *refHistory) supersedes(targetID string) bool {
+	if h.latest == "" || h.latest == targetID {
+		return false
+	}
+
+	return targetID == plumbing.ZeroHash.String() || h.targets[targetID]
+}
+
+func loadRefHistory(repo *git.Repository, refName string) (*refHistory, error) {
+	history := &refHistory{targets: map[string]bool{}}
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(repo, refName)
+	for err == nil {
+		if history.latest == "" {
+			history.latest = entry.TargetID.String()
+		}
+		history.targets[entry.TargetID.String()] = true
+
+		entry, _, err = rsl.GetLatestReferenceEntryForRefBefore(repo, refName, entry.ID)
+	}
+	if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// getReachableObjects returns the commits and tags reachable from the
+// repository's references, excluding gittuf's own namespaces.
+func getReachableObjects(repo *git.Repository) (map[plumbing.Hash]bool, error) {
+	reachable := map[plumbing.Hash]bool{}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	defer refs.Close()
+
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference || strings.HasPrefix(ref.Name().String(), "refs/gittuf/") {
+			return nil
+		}
+
+		objectID := ref.Hash()
+		if tag, err := repo.TagObject(objectID); err == nil {
+			reachable[objectID] = true
+
+			commit, err := tag.Commit()
+			if err != nil {
+				// The tag does not point to a commit
+				return nil //nolint:nilerr
+			}
+			objectID = commit.Hash
+		}
+
+		if reachable[objectID] {
+			return nil
+		}
+
+		commit, err := gitinterface.GetCommit(repo, objectID)
+		if err != nil {
+			if errors.Is(err, plumbing.ErrObjectNotFound) {
+				// The reference does not point to a commit
+				return nil
+			}
+			return err
+		}
+
+		return object.NewCommitPreorderIter(commit, reachable, nil).ForEach(func(c *object.Commit) error {
+			reachable[c.Hash] = true
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reachable, nil
+}
+
+func validateTombstone(env *sslibdsse.Envelope, prunedFrom string) error {
+	if !isValidPathComponent(prunedFrom) {
+		return ErrInvalidTombstone
+	}
+
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return err
+	}
+
+	if attestation.PredicateType != TombstonePredicateType || len(attestation.Subject) == 0 {
+		return ErrInvalidTombstone
+	}
+
+	if attestation.Subject[0].Digest[digestGitCommitKey] != prunedFrom {
+		return ErrInvalidTombstone
+	}
+
+	if attestation.Predicate.AsMap()[prunedFromKey] != prunedFrom {
+		return ErrInvalidTombstone
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTombstone(t *testing.T) {
+	prunedFrom := "abcdef1234567890abcdef1234567890abcdef12"
+	pruned := []*PrunedAttestation{
+		{
+			Path:   "ci-runs/1234567890abcdef1234567890abcdef12345678/github/1",
+			BlobID: plumbing.ZeroHash.String(),
+			Reason: PruneReasonUnreachable,
+		},
+	}
+
+	attestation, err := NewTombstone(prunedFrom, pruned)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, prunedFrom, attestation.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, TombstonePredicateType, attestation.PredicateType)
+
+	predicate := attestation.Predicate.AsMap()
+	assert.Equal(t, prunedFrom, predicate[prunedFromKey])
+	assert.Equal(t, []any{map[string]any{"path": pruned[0].Path, "blobID": pruned[0].BlobID, "reason": pruned[0].Reason}}, predicate["attestations"])
+
+	_, err = NewTombstone("", pruned)
+	assert.ErrorIs(t, err, ErrInvalidTombstone)
+}
+
+func TestSetAndGetTombstone(t *testing.T) {
+	prunedFrom := "abcdef1234567890abcdef1234567890abcdef12"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := NewTombstone(prunedFrom, []*PrunedAttestation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	_, err = attestations.GetTombstoneFor(repo, prunedFrom)
+	assert.ErrorIs(t, err, ErrTombstoneNotFound)
+
+	err = attestations.SetTombstone(repo, env, plumbing.ZeroHash.String())
+	assert.ErrorIs(t, err, ErrInvalidTombstone)
+
+	err = attestations.SetTombstone(repo, env, prunedFrom)
+	assert.Nil(t, err)
+
+	storedEnv, err := attestations.GetTombstoneFor(repo, prunedFrom)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	attestationPath := tombstonesTreeEntryName + "/" + prunedFrom
+	storedEnv, err = attestations.GetEnvelope(repo, attestationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+	assert.Nil(t, ValidateEnvelope(attestationPath, env))
+}
+
+func TestPrune(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, artifacts.GPGKey1Private)
+	for _, commitID := range commitIDs[1:] {
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitID), artifacts.GPGKey1Private)
+	}
+
+	// The RSL records main moving from the second commit to the third, while
+	// the first commit is reachable but was never recorded
+	firstID := commitIDs[0].String()
+	secondID := commitIDs[1].String()
+	latestID := commitIDs[2].String()
+	unknownID := "abcdef1234567890abcdef1234567890abcdef12"
+
+	blobID, err := gitinterface.WriteBlob(repo, []byte("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{
+		referenceAuthorizations: map[string]plumbing.Hash{
+			ReferenceAuthorizationPath(refName, secondID, latestID):                          blobID,
+			ReferenceAuthorizationPath(refName, latestID, unknownID):                         blobID,
+			ReferenceAuthorizationPath("refs/heads/feature", firstID, latestID):              blobID,
+			ReferenceAuthorizationPath("refs/heads/feature", unknownID, latestID):            blobID,
+			ReferenceAuthorizationPath("refs/tags/v1", plumbing.ZeroHash.String(), latestID): blobID,
+			ReferenceAuthorizationPath(refName, plumbing.ZeroHash.String(), firstID):         blobID,
+		},
+		hookExecutionAttestations: map[string]plumbing.Hash{
+			HookExecutionPath(refName, secondID, "secret-scan"): blobID,
+			HookExecutionPath(refName, latestID, "secret-scan"): blobID,
+		},
+		ciRunAttestations: map[string]plumbing.Hash{
+			CIRunAttestationPath(firstID, "github", "1"):   blobID,
+			CIRunAttestationPath(unknownID, "github", "2"): blobID,
+		},
+		rebuildAttestations: map[string]plumbing.Hash{
+			RebuildAttestationPath(latestID, "gittuf-linux-amd64", "aaaa"): blobID,
+		},
+		pushEventAttestations: map[string]plumbing.Hash{
+			unknownID: blobID,
+		},
+	}
+
+	pruned, err := attestations.Prune(repo)
+	assert.Nil(t, err)
+
+	expectedPruned := []*PrunedAttestation{
+		{Path: ciRunAttestationsTreeEntryName + "/" + CIRunAttestationPath(unknownID, "github", "2"), BlobID: blobID.String(), Reason: PruneReasonUnreachable},
+		{Path: hookExecutionAttestationsTreeEntryName + "/" + HookExecutionPath(refName, secondID, "secret-scan"), BlobID: blobID.String(), Reason: PruneReasonSuperseded},
+		{Path: referenceAuthorizationsTreeEntryName + "/" + ReferenceAuthorizationPath("refs/heads/feature", unknownID, latestID), BlobID: blobID.String(), Reason: PruneReasonUnreachable},
+		{Path: referenceAuthorizationsTreeEntryName + "/" + ReferenceAuthorizationPath(refName, plumbing.ZeroHash.String(), firstID), BlobID: blobID.String(), Reason: PruneReasonSuperseded},
+		{Path: referenceAuthorizationsTreeEntryName + "/" + ReferenceAuthorizationPath(refName, secondID, latestID), BlobID: blobID.String(), Reason: PruneReasonSuperseded},
+	}
+	assert.ElementsMatch(t, expectedPruned, pruned)
+
+	assert.Equal(t, 3, len(attestations.referenceAuthorizations))
+	assert.Contains(t, attestations.referenceAuthorizations, ReferenceAuthorizationPath(refName, latestID, unknownID))
+	assert.Contains(t, attestations.referenceAuthorizations, ReferenceAuthorizationPath("refs/heads/feature", firstID, latestID))
+	assert.Contains(t, attestations.referenceAuthorizations, ReferenceAuthorizationPath("refs/tags/v1", plumbing.ZeroHash.String(), latestID))
+	assert.Equal(t, 1, len(attestations.hookExecutionAttestations))
+	assert.Equal(t, 1, len(attestations.ciRunAttestations))
+	assert.Equal(t, 1, len(attestations.rebuildAttestations))
+	assert.Equal(t, 1, len(attestations.pushEventAttestations))
+
+	// Pruning again finds nothing
+	pruned, err = attestations.Prune(repo)
+	assert.Nil(t, err)
+	assert.Empty(t, pruned)
+}
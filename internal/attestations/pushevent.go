@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	PushEventPredicateType = "https://gittuf.dev/push-event/v0.1"
+	rslEntryIDKey          = "rslEntryID"
+
+	// ipv4PrefixLength and ipv6PrefixLength determine how much of the source
+	// address is retained in a push event attestation. The full address is
+	// never recorded.
+	ipv4PrefixLength = 24
+	ipv6PrefixLength = 48
+
+	SourceIPClassLoopback = "loopback"
+	SourceIPClassPrivate  = "private"
+	SourceIPClassPublic   = "public"
+)
+
+var (
+	ErrPushEventNotFound = errors.New("requested push event attestation not found")
+	ErrInvalidPushEvent  = errors.New("push event attestation does not match expected details")
+	ErrInvalidIPAddress  = errors.New("invalid IP address")
+)
+
+// PushEvent records the context in which a push was made. It is linked to the
+// RSL entry recorded for the push, and is meant to be used as a "predicate" in
+// an in-toto attestation. All context fields are optional.
+type PushEvent struct {
+	RSLEntryID     string `json:"rslEntryID"`
+	RefName        string `json:"refName"`
+	TargetID       string `json:"targetID"`
+	SourceIPRange  string `json:"sourceIPRange,omitempty"`
+	SourceIPClass  string `json:"sourceIPClass,omitempty"`
+	ClientHostname string `json:"clientHostname,omitempty"`
+	CIRunURL       string `json:"ciRunURL,omitempty"`
+
+	// Platform and Pusher are set when the push was made on a hosting
+	// platform, such as a merge made using its UI, and identify the platform
+	// and the platform account that made the push.
+	Platform string `json:"platform,omitempty"`
+	Pusher   string `json:"pusher,omitempty"`
+}
+
+// NewPushEventAttestation creates a new push event attestation for the
+// provided information. The push event is embedded in an in-toto "statement"
+// and returned with the appropriate "predicate type" set. The subject of the
+// statement is the RSL entry recorded for the push.
+func NewPushEventAttestation(pushEvent *PushEvent) (*ita.Statement, error) {
+	predicateBytes, err := json.Marshal(pushEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Digest: map[string]string{digestGitCommitKey: pushEvent.RSLEntryID},
+			},
+		},
+		PredicateType: PushEventPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// SourceIPRangeAndClass reduces the source IP address of a push to the network
+// range it belongs to and classifies the address. Only the range is recorded
+// so that push event attestations don't identify individual machines.
+func SourceIPRangeAndClass(address string) (string, string, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return "", "", fmt.Errorf("%w: '%s'", ErrInvalidIPAddress, address)
+	}
+
+	var ipRange *net.IPNet
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ipRange = &net.IPNet{IP: ipv4.Mask(net.CIDRMask(ipv4PrefixLength, 32)), Mask: net.CIDRMask(ipv4PrefixLength, 32)}
+	} else {
+		ipRange = &net.IPNet{IP: ip.Mask(net.CIDRMask(ipv6PrefixLength, 128)), Mask: net.CIDRMask(ipv6PrefixLength, 128)}
+	}
+
+	class := SourceIPClassPublic
+	switch {
+	case ip.IsLoopback():
+		class = SourceIPClassLoopback
+	case ip.IsPrivate():
+		class = SourceIPClassPrivate
+	}
+
+	return ipRange.String(), class, nil
+}
+
+// SetPushEventAttestation writes the new push event attestation to the object
+// store and tracks it in the current attestations state.
+func (a *Attestations) SetPushEventAttestation(repo *git.Repository, env *sslibdsse.Envelope, rslEntryID string) error {
+	if err := validatePushEventAttestation(env, rslEntryID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.pushEventAttestations == nil {
+		a.pushEventAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.pushEventAttestations[rslEntryID] = blobID
+	return nil
+}
+
+// GetPushEventAttestationFor returns the push event attestation (with its
+// signatures) for the specified RSL entry.
+func (a *Attestations) GetPushEventAttestationFor(repo *git.Repository, rslEntryID string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.pushEventAttestations[rslEntryID]
+	if !has {
+		return nil, ErrPushEventNotFound
+	}
+
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validatePushEventAttestation(env, rslEntryID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func validatePushEventAttestation(env *sslibdsse.Envelope, rslEntryID string) error {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return err
+	}
+
+	if attestation.PredicateType != PushEventPredicateType || len(attestation.Subject) == 0 {
+		return ErrInvalidPushEvent
+	}
+
+	if attestation.Subject[0].Digest[digestGitCommitKey] != rslEntryID {
+		return ErrInvalidPushEvent
+	}
+
+	if attestation.Predicate.AsMap()[rslEntryIDKey] != rslEntryID {
+		return ErrInvalidPushEvent
+	}
+
+	return nil
+}
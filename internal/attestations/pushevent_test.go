@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPushEventAttestation(t *testing.T) {
+	pushEvent := &PushEvent{
+		RSLEntryID:     "abcdef1234567890abcdef1234567890abcdef12",
+		RefName:        "refs/heads/main",
+		TargetID:       plumbing.ZeroHash.String(),
+		SourceIPRange:  "192.0.2.0/24",
+		SourceIPClass:  SourceIPClassPublic,
+		ClientHostname: "build-01",
+	}
+
+	attestation, err := NewPushEventAttestation(pushEvent)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, pushEvent.RSLEntryID, attestation.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, PushEventPredicateType, attestation.PredicateType)
+
+	predicate := attestation.Predicate.AsMap()
+	assert.Equal(t, pushEvent.RSLEntryID, predicate[rslEntryIDKey])
+	assert.Equal(t, pushEvent.SourceIPRange, predicate["sourceIPRange"])
+	assert.Equal(t, pushEvent.ClientHostname, predicate["clientHostname"])
+	assert.NotContains(t, predicate, "ciRunURL")
+}
+
+func TestSourceIPRangeAndClass(t *testing.T) {
+	tests := map[string]struct {
+		address       string
+		expectedRange string
+		expectedClass string
+		expectedError error
+	}{
+		"public IPv4": {
+			address:       "192.0.2.113",
+			expectedRange: "192.0.2.0/24",
+			expectedClass: SourceIPClassPublic,
+		},
+		"private IPv4": {
+			address:       "10.1.2.3",
+			expectedRange: "10.1.2.0/24",
+			expectedClass: SourceIPClassPrivate,
+		},
+		"loopback IPv4": {
+			address:       "127.0.0.1",
+			expectedRange: "127.0.0.0/24",
+			expectedClass: SourceIPClassLoopback,
+		},
+		"public IPv6": {
+			address:       "2001:db8:1234:5678::1",
+			expectedRange: "2001:db8:1234::/48",
+			expectedClass: SourceIPClassPublic,
+		},
+		"invalid address": {
+			address:       "not-an-address",
+			expectedError: ErrInvalidIPAddress,
+		},
+	}
+
+	for name, test := range tests {
+		ipRange, class, err := SourceIPRangeAndClass(test.address)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, name)
+			continue
+		}
+
+		assert.Nil(t, err, name)
+		assert.Equal(t, test.expectedRange, ipRange, name)
+		assert.Equal(t, test.expectedClass, class, name)
+	}
+}
+
+func TestSetAndGetPushEventAttestation(t *testing.T) {
+	rslEntryID := "abcdef1234567890abcdef1234567890abcdef12"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := NewPushEventAttestation(&PushEvent{RSLEntryID: rslEntryID, RefName: "refs/heads/main", TargetID: plumbing.ZeroHash.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	_, err = attestations.GetPushEventAttestationFor(repo, rslEntryID)
+	assert.ErrorIs(t, err, ErrPushEventNotFound)
+
+	err = attestations.SetPushEventAttestation(repo, env, plumbing.ZeroHash.String())
+	assert.ErrorIs(t, err, ErrInvalidPushEvent)
+
+	err = attestations.SetPushEventAttestation(repo, env, rslEntryID)
+	assert.Nil(t, err)
+
+	storedEnv, err := attestations.GetPushEventAttestationFor(repo, rslEntryID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+}
@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	RebuildPredicateType = "https://gittuf.dev/rebuild/v0.1"
+
+	artifactNameKey   = "artifactName"
+	artifactDigestKey = "artifactSHA256"
+)
+
+var (
+	ErrRebuildNotFound = errors.New("requested rebuild attestation not found")
+	ErrInvalidRebuild  = errors.New("rebuild attestation does not match expected details")
+)
+
+// Rebuild records the digest of an artifact built from a commit, and is meant
+// to be used as a "predicate" in an in-toto attestation. Independent rebuilders
+// that produce the same artifact sign the same statement, so the number of
+// signatures on a rebuild attestation is the number of rebuilders that agree on
+// the artifact's digest.
+type Rebuild struct {
+	CommitID       string `json:"commitID"`
+	ArtifactName   string `json:"artifactName"`
+	ArtifactSHA256 string `json:"artifactSHA256"`
+}
+
+// NewRebuildAttestation creates a new rebuild attestation for the provided
+// information. The rebuild is embedded in an in-toto "statement" and returned
+// with the appropriate "predicate type" set. The subject of the statement is
+// the commit the artifact was built from.
+func NewRebuildAttestation(rebuild *Rebuild) (*ita.Statement, error) {
+	if rebuild.CommitID == "" || !isValidPathComponent(rebuild.ArtifactName) || !isValidPathComponent(rebuild.ArtifactSHA256) {
+		return nil, ErrInvalidRebuild
+	}
+
+	predicateBytes, err := json.Marshal(rebuild)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateInterface := &map[string]any{}
+	if err := json.Unmarshal(predicateBytes, predicateInterface); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(*predicateInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Digest: map[string]string{digestGitCommitKey: rebuild.CommitID},
+			},
+		},
+		PredicateType: RebuildPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// RebuildAttestationPath constructs the expected path on-disk for the rebuild
+// attestation.
+func RebuildAttestationPath(commitID, artifactName, artifactDigest string) string {
+	return path.Join(commitID, artifactName, artifactDigest)
+}
+
+// SetRebuildAttestation writes the new rebuild attestation to the object store
+// and tracks it in the current attestations state.
+func (a *Attestations) SetRebuildAttestation(repo *git.Repository, env *sslibdsse.Envelope, commitID, artifactName, artifactDigest string) error {
+	if err := validateRebuildAttestation(env, commitID, artifactName, artifactDigest); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.rebuildAttestations == nil {
+		a.rebuildAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.rebuildAttestations[RebuildAttestationPath(commitID, artifactName, artifactDigest)] = blobID
+	return nil
+}
+
+// GetRebuildAttestationFor returns the rebuild attestation (with its
+// signatures) for the specified artifact digest.
+func (a *Attestations) GetRebuildAttestationFor(repo *git.Repository, commitID, artifactName, artifactDigest string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.rebuildAttestations[RebuildAttestationPath(commitID, artifactName, artifactDigest)]
+	if !has {
+		return nil, ErrRebuildNotFound
+	}
+
+	return a.loadRebuildAttestation(repo, blobID, commitID, artifactName, artifactDigest)
+}
+
+// GetRebuildAttestationsFor returns all the rebuild attestations for the
+// artifact built from the specified commit. The attestations are keyed by the
+// artifact digest they attest to. If the rebuilders disagree about the
+// artifact's digest, more than one attestation is returned.
+func (a *Attestations) GetRebuildAttestationsFor(repo *git.Repository, commitID, artifactName string) (map[string]*sslibdsse.Envelope, error) {
+	prefix := path.Join(commitID, artifactName) + "/"
+
+	envelopes := map[string]*sslibdsse.Envelope{}
+	for rebuildPath, blobID := range a.rebuildAttestations {
+		artifactDigest, found := strings.CutPrefix(rebuildPath, prefix)
+		if !found {
+			continue
+		}
+
+		env, err := a.loadRebuildAttestation(repo, blobID, commitID, artifactName, artifactDigest)
+		if err != nil {
+			return nil, err
+		}
+
+		envelopes[artifactDigest] = env
+	}
+
+	return envelopes, nil
+}
+
+func (a *Attestations) loadRebuildAttestation(repo *git.Repository, blobID plumbing.Hash, commitID, artifactName, artifactDigest string) (*sslibdsse.Envelope, error) {
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateRebuildAttestation(env, commitID, artifactName, artifactDigest); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func validateRebuildAttestation(env *sslibdsse.Envelope, commitID, artifactName, artifactDigest string) error {
+	if !isValidPathComponent(artifactName) || !isValidPathComponent(artifactDigest) {
+		return ErrInvalidRebuild
+	}
+
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return err
+	}
+
+	if attestation.PredicateType != RebuildPredicateType || len(attestation.Subject) == 0 {
+		return ErrInvalidRebuild
+	}
+
+	if attestation.Subject[0].Digest[digestGitCommitKey] != commitID {
+		return ErrInvalidRebuild
+	}
+
+	predicate := attestation.Predicate.AsMap()
+	if predicate[commitIDKey] != commitID || predicate[artifactNameKey] != artifactName || predicate[artifactDigestKey] != artifactDigest {
+		return ErrInvalidRebuild
+	}
+
+	return nil
+}
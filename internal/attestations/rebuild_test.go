@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRebuildAttestation(t *testing.T) {
+	rebuild := &Rebuild{
+		CommitID:       "abcdef1234567890abcdef1234567890abcdef12",
+		ArtifactName:   "gittuf-linux-amd64",
+		ArtifactSHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	}
+
+	attestation, err := NewRebuildAttestation(rebuild)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, rebuild.CommitID, attestation.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, RebuildPredicateType, attestation.PredicateType)
+
+	predicate := attestation.Predicate.AsMap()
+	assert.Equal(t, rebuild.CommitID, predicate[commitIDKey])
+	assert.Equal(t, rebuild.ArtifactName, predicate[artifactNameKey])
+	assert.Equal(t, rebuild.ArtifactSHA256, predicate[artifactDigestKey])
+
+	_, err = NewRebuildAttestation(&Rebuild{CommitID: rebuild.CommitID, ArtifactName: "dist/gittuf", ArtifactSHA256: rebuild.ArtifactSHA256})
+	assert.ErrorIs(t, err, ErrInvalidRebuild)
+}
+
+func TestSetAndGetRebuildAttestation(t *testing.T) {
+	commitID := "abcdef1234567890abcdef1234567890abcdef12"
+	artifactName := "gittuf-linux-amd64"
+	artifactDigest := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRebuild := func(digest string) *Rebuild {
+		return &Rebuild{CommitID: commitID, ArtifactName: artifactName, ArtifactSHA256: digest}
+	}
+
+	statement, err := NewRebuildAttestation(newRebuild(artifactDigest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	_, err = attestations.GetRebuildAttestationFor(repo, commitID, artifactName, artifactDigest)
+	assert.ErrorIs(t, err, ErrRebuildNotFound)
+
+	err = attestations.SetRebuildAttestation(repo, env, plumbing.ZeroHash.String(), artifactName, artifactDigest)
+	assert.ErrorIs(t, err, ErrInvalidRebuild)
+
+	err = attestations.SetRebuildAttestation(repo, env, commitID, artifactName, "aaaa")
+	assert.ErrorIs(t, err, ErrInvalidRebuild)
+
+	err = attestations.SetRebuildAttestation(repo, env, commitID, artifactName, artifactDigest)
+	assert.Nil(t, err)
+
+	storedEnv, err := attestations.GetRebuildAttestationFor(repo, commitID, artifactName, artifactDigest)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	// Record a rebuild with a different digest
+	statement, err = NewRebuildAttestation(newRebuild("aaaa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherEnv, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = attestations.SetRebuildAttestation(repo, otherEnv, commitID, artifactName, "aaaa")
+	assert.Nil(t, err)
+
+	rebuilds, err := attestations.GetRebuildAttestationsFor(repo, commitID, artifactName)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]*sslibdsse.Envelope{artifactDigest: env, "aaaa": otherEnv}, rebuilds)
+
+	rebuilds, err = attestations.GetRebuildAttestationsFor(repo, commitID, "gittuf-darwin-arm64")
+	assert.Nil(t, err)
+	assert.Empty(t, rebuilds)
+
+	attestationPath := rebuildAttestationsTreeEntryName + "/" + RebuildAttestationPath(commitID, artifactName, artifactDigest)
+	storedEnv, err = attestations.GetEnvelope(repo, attestationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+	assert.Nil(t, ValidateEnvelope(attestationPath, env))
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// RekorEntryPath constructs the expected path on-disk for the Rekor log entry
+// of an attestation.
+func RekorEntryPath(payloadDigest, uuid string) string {
+	return path.Join(payloadDigest, uuid)
+}
+
+// SetRekorEntry records the Rekor log entry for the attestation in the current
+// attestations state. The entry must record the attestation's payload. Each
+// signer of an attestation may log the attestation separately, so more than
+// one entry can be recorded for an attestation.
+func (a *Attestations) SetRekorEntry(repo *git.Repository, env *sslibdsse.Envelope, entry *rekor.LogEntry) error {
+	if !isValidPathComponent(entry.UUID) {
+		return rekor.ErrInvalidLogEntry
+	}
+
+	if err := entry.MatchesEnvelope(env); err != nil {
+		return err
+	}
+
+	payloadDigest, err := rekor.PayloadDigest(env)
+	if err != nil {
+		return err
+	}
+
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, entryBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.rekorEntries == nil {
+		a.rekorEntries = map[string]plumbing.Hash{}
+	}
+
+	a.rekorEntries[RekorEntryPath(payloadDigest, entry.UUID)] = blobID
+	return nil
+}
+
+// GetRekorEntriesFor returns the Rekor log entries recorded for the
+// attestation. Entries that do not record the attestation's payload are
+// ignored.
+func (a *Attestations) GetRekorEntriesFor(repo *git.Repository, env *sslibdsse.Envelope) ([]*rekor.LogEntry, error) {
+	payloadDigest, err := rekor.PayloadDigest(env)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*rekor.LogEntry{}
+	for entryPath, blobID := range a.rekorEntries {
+		if !strings.HasPrefix(entryPath, payloadDigest+"/") {
+			continue
+		}
+
+		entryBytes, err := readBlob(repo, blobID)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &rekor.LogEntry{}
+		if err := json.Unmarshal(entryBytes, entry); err != nil {
+			return nil, err
+		}
+
+		if entry.MatchesEnvelope(env) != nil {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/memory"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAndGetRekorEntries(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := NewReferenceAuthorization("refs/heads/main", "abcdef1234567890abcdef1234567890abcdef12", "1234567890abcdef1234567890abcdef12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherStatement, err := NewReferenceAuthorization("refs/heads/feature", "abcdef1234567890abcdef1234567890abcdef12", "1234567890abcdef1234567890abcdef12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherEnv, err := dsse.CreateEnvelope(otherStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newEntry := func(uuid string, env *sslibdsse.Envelope) *rekor.LogEntry {
+		payloadDigest, err := rekor.PayloadDigest(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body := fmt.Sprintf(`{"apiVersion": "0.0.1", "kind": "dsse", "spec": {"payloadHash": {"algorithm": "sha256", "value": "%s"}}}`, payloadDigest)
+		return &rekor.LogEntry{
+			UUID: uuid,
+			Body: base64.StdEncoding.EncodeToString([]byte(body)),
+		}
+	}
+
+	attestations := &Attestations{}
+
+	entries, err := attestations.GetRekorEntriesFor(repo, env)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	firstEntry := newEntry("uuid-1", env)
+	secondEntry := newEntry("uuid-2", env)
+	otherEntry := newEntry("uuid-3", otherEnv)
+
+	err = attestations.SetRekorEntry(repo, env, firstEntry)
+	assert.Nil(t, err)
+	err = attestations.SetRekorEntry(repo, env, secondEntry)
+	assert.Nil(t, err)
+	err = attestations.SetRekorEntry(repo, otherEnv, otherEntry)
+	assert.Nil(t, err)
+
+	entries, err = attestations.GetRekorEntriesFor(repo, env)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []*rekor.LogEntry{firstEntry, secondEntry}, entries)
+
+	entries, err = attestations.GetRekorEntriesFor(repo, otherEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, []*rekor.LogEntry{otherEntry}, entries)
+
+	// The entry must record the attestation
+	err = attestations.SetRekorEntry(repo, env, newEntry("uuid-4", otherEnv))
+	assert.ErrorIs(t, err, rekor.ErrInvalidLogEntry)
+
+	err = attestations.SetRekorEntry(repo, env, newEntry("../uuid-5", env))
+	assert.ErrorIs(t, err, rekor.ErrInvalidLogEntry)
+}
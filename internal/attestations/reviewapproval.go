@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+
+	"github.com/gittuf/gittuf/internal/codereview"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const ReviewApprovalPredicateType = "https://gittuf.dev/review-approval/v0.1"
+
+var (
+	ErrReviewApprovalNotFound = errors.New("requested review approval attestation not found")
+	ErrInvalidReviewApproval  = errors.New("review approval attestation does not match expected details")
+)
+
+// NewReviewApprovalAttestation creates a new attestation recording the
+// approvals a change received on a code review platform. The attestation is
+// bound to the review's target branch and target commit, i.e., the commit the
+// branch is moved to when the change is merged. The review is embedded in an
+// in-toto "statement" and returned with the appropriate "predicate type" set.
+func NewReviewApprovalAttestation(review *codereview.Review) (*ita.Statement, error) {
+	reviewBytes, err := json.Marshal(review)
+	if err != nil {
+		return nil, err
+	}
+
+	predicate := map[string]any{}
+	if err := json.Unmarshal(reviewBytes, &predicate); err != nil {
+		return nil, err
+	}
+
+	predicateStruct, err := structpb.NewStruct(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Uri:    review.URL,
+				Digest: map[string]string{digestGitCommitKey: review.TargetID},
+			},
+		},
+		PredicateType: ReviewApprovalPredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// ReviewApprovalAttestationPath constructs the expected path on-disk for the
+// review approval attestation.
+func ReviewApprovalAttestationPath(refName, targetID string) string {
+	return path.Join(refName, targetID)
+}
+
+// SetReviewApprovalAttestation writes the new review approval attestation to
+// the object store and tracks it in the current attestations state. An
+// existing attestation for the same change is replaced, so the approvals can
+// be imported again as the change is reviewed.
+func (a *Attestations) SetReviewApprovalAttestation(repo *git.Repository, env *sslibdsse.Envelope, refName, targetID string) error {
+	if err := validateReviewApprovalAttestation(env, refName, targetID); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.reviewApprovalAttestations == nil {
+		a.reviewApprovalAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.reviewApprovalAttestations[ReviewApprovalAttestationPath(refName, targetID)] = blobID
+	return nil
+}
+
+// GetReviewApprovalAttestationFor returns the review approval attestation
+// (with its signatures) for the change of refName to targetID.
+func (a *Attestations) GetReviewApprovalAttestationFor(repo *git.Repository, refName, targetID string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.reviewApprovalAttestations[ReviewApprovalAttestationPath(refName, targetID)]
+	if !has {
+		return nil, ErrReviewApprovalNotFound
+	}
+
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateReviewApprovalAttestation(env, refName, targetID); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// GetReview returns the review recorded in the review approval attestation.
+func GetReview(env *sslibdsse.Envelope) (*codereview.Review, error) {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return nil, err
+	}
+
+	if attestation.PredicateType != ReviewApprovalPredicateType || len(attestation.Subject) == 0 {
+		return nil, ErrInvalidReviewApproval
+	}
+
+	predicateBytes, err := json.Marshal(attestation.Predicate.AsMap())
+	if err != nil {
+		return nil, err
+	}
+
+	review := &codereview.Review{}
+	if err := json.Unmarshal(predicateBytes, review); err != nil {
+		return nil, err
+	}
+
+	if attestation.Subject[0].Digest[digestGitCommitKey] != review.TargetID {
+		return nil, ErrInvalidReviewApproval
+	}
+
+	return review, nil
+}
+
+func validateReviewApprovalAttestation(env *sslibdsse.Envelope, refName, targetID string) error {
+	review, err := GetReview(env)
+	if err != nil {
+		return err
+	}
+
+	if review.RefName != refName || review.TargetID != targetID {
+		return ErrInvalidReviewApproval
+	}
+
+	return nil
+}
+
+// splitReviewApprovalAttestationPath is the inverse of
+// ReviewApprovalAttestationPath.
+func splitReviewApprovalAttestationPath(reviewPath string) (string, string, error) {
+	refName, targetID := path.Split(reviewPath)
+	if refName == "" || targetID == "" {
+		return "", "", ErrUnknownAttestationPath
+	}
+
+	return path.Clean(refName), targetID, nil
+}
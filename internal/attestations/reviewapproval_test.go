@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/codereview"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func testReview() *codereview.Review {
+	return &codereview.Review{
+		Platform:   codereview.PlatformGitHub,
+		URL:        "https://github.com/gittuf/gittuf/pull/7",
+		Repository: "gittuf/gittuf",
+		Number:     7,
+		State:      "merged",
+		RefName:    "refs/heads/main",
+		HeadCommit: "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f",
+		TargetID:   "abcdef1234567890abcdef1234567890abcdef12",
+		Author:     codereview.User{Name: "jane"},
+		Approvals: []codereview.Approval{
+			{Reviewer: codereview.User{Name: "john", Principal: "john@example.com"}, CommitID: "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+		},
+	}
+}
+
+func TestNewReviewApprovalAttestation(t *testing.T) {
+	review := testReview()
+
+	attestation, err := NewReviewApprovalAttestation(review)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, review.URL, attestation.Subject[0].Uri)
+	assert.Equal(t, review.TargetID, attestation.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, ReviewApprovalPredicateType, attestation.PredicateType)
+
+	predicate := attestation.Predicate.AsMap()
+	assert.Equal(t, "refs/heads/main", predicate["refName"])
+	assert.Equal(t, []any{
+		map[string]any{
+			"commitID": "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f",
+			"reviewer": map[string]any{"name": "john", "principal": "john@example.com"},
+		},
+	}, predicate["approvals"])
+}
+
+func TestSetAndGetReviewApprovalAttestation(t *testing.T) {
+	review := testReview()
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := NewReviewApprovalAttestation(review)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	_, err = attestations.GetReviewApprovalAttestationFor(repo, review.RefName, review.TargetID)
+	assert.ErrorIs(t, err, ErrReviewApprovalNotFound)
+
+	err = attestations.SetReviewApprovalAttestation(repo, env, review.RefName, plumbing.ZeroHash.String())
+	assert.ErrorIs(t, err, ErrInvalidReviewApproval)
+
+	err = attestations.SetReviewApprovalAttestation(repo, env, "refs/heads/feature", review.TargetID)
+	assert.ErrorIs(t, err, ErrInvalidReviewApproval)
+
+	err = attestations.SetReviewApprovalAttestation(repo, env, review.RefName, review.TargetID)
+	assert.Nil(t, err)
+
+	storedEnv, err := attestations.GetReviewApprovalAttestationFor(repo, review.RefName, review.TargetID)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	storedReview, err := GetReview(storedEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, review, storedReview)
+
+	attestationPath := reviewApprovalsTreeEntryName + "/" + ReviewApprovalAttestationPath(review.RefName, review.TargetID)
+	assert.Nil(t, ValidateEnvelope(attestationPath, env))
+
+	storedEnv, err = attestations.GetEnvelope(repo, attestationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+}
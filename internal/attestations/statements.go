@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var (
+	ErrStatementNotFound = errors.New("requested statement not found")
+	ErrInvalidStatement  = errors.New("statement does not match expected details")
+)
+
+// NewStatementAttestation creates an in-toto "statement" with the predicate
+// of the specified type, such as a code review approval or a summary of test
+// results, for a change to a Git reference. Unlike the other attestations, the
+// predicate is opaque to gittuf. The subject of the statement is the target of
+// the reference, so the statement is attached to the RSL entry that records
+// the reference moving to the target.
+func NewStatementAttestation(refName, targetID, predicateType string, predicate map[string]any) (*ita.Statement, error) {
+	if refName == "" || targetID == "" || predicateType == "" {
+		return nil, ErrInvalidStatement
+	}
+
+	predicateStruct, err := structpb.NewStruct(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Name:   refName,
+				Digest: map[string]string{digestGitCommitKey: targetID},
+			},
+		},
+		PredicateType: predicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// StatementAttestationPath constructs the expected path on-disk for the
+// statement. The payload digest is the SHA-256 digest of the envelope's
+// payload, so signers that attest to the same predicate sign the same
+// statement, while different statements of the same type are kept apart.
+func StatementAttestationPath(refName, targetID, payloadDigest string) string {
+	return path.Join(refName, targetID, payloadDigest)
+}
+
+// GetStatementPayloadDigest returns the SHA-256 digest of the envelope's
+// payload that identifies the statement in its path.
+func GetStatementPayloadDigest(env *sslibdsse.Envelope) (string, error) {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(payload)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// GetStatementPredicateType returns the predicate type of the statement in
+// the envelope.
+func GetStatementPredicateType(env *sslibdsse.Envelope) (string, error) {
+	statement, err := decodeStatement(env)
+	if err != nil {
+		return "", err
+	}
+
+	return statement.PredicateType, nil
+}
+
+// SetStatementAttestation writes the new statement to the object store and
+// tracks it in the current attestations state for the change to the
+// reference. An existing envelope for the same statement is replaced, which
+// is used to add signatures to it.
+func (a *Attestations) SetStatementAttestation(repo *git.Repository, env *sslibdsse.Envelope, refName, targetID string) error {
+	payloadDigest, err := GetStatementPayloadDigest(env)
+	if err != nil {
+		return err
+	}
+
+	if err := validateStatementAttestation(env, refName, targetID, payloadDigest); err != nil {
+		return err
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.statementAttestations == nil {
+		a.statementAttestations = map[string]plumbing.Hash{}
+	}
+
+	a.statementAttestations[StatementAttestationPath(refName, targetID, payloadDigest)] = blobID
+	return nil
+}
+
+// GetStatementAttestationFor returns the statement (with its signatures) with
+// the payload digest for the change to the reference.
+func (a *Attestations) GetStatementAttestationFor(repo *git.Repository, refName, targetID, payloadDigest string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.statementAttestations[StatementAttestationPath(refName, targetID, payloadDigest)]
+	if !has {
+		return nil, ErrStatementNotFound
+	}
+
+	return a.loadStatementAttestation(repo, blobID, refName, targetID, payloadDigest)
+}
+
+// GetStatementAttestationsFor returns the statements attached to the change
+// to the reference. If predicateType is set, only statements of that type are
+// returned. The statements are ordered by their payload digests.
+func (a *Attestations) GetStatementAttestationsFor(repo *git.Repository, refName, targetID, predicateType string) ([]*sslibdsse.Envelope, error) {
+	prefix := path.Join(refName, targetID) + "/"
+
+	payloadDigests := []string{}
+	for statementPath := range a.statementAttestations {
+		if payloadDigest, found := strings.CutPrefix(statementPath, prefix); found && isValidPathComponent(payloadDigest) {
+			payloadDigests = append(payloadDigests, payloadDigest)
+		}
+	}
+	sort.Strings(payloadDigests)
+
+	envelopes := []*sslibdsse.Envelope{}
+	for _, payloadDigest := range payloadDigests {
+		env, err := a.loadStatementAttestation(repo, a.statementAttestations[prefix+payloadDigest], refName, targetID, payloadDigest)
+		if err != nil {
+			return nil, err
+		}
+
+		if predicateType != "" {
+			envPredicateType, err := GetStatementPredicateType(env)
+			if err != nil {
+				return nil, err
+			}
+			if envPredicateType != predicateType {
+				continue
+			}
+		}
+
+		envelopes = append(envelopes, env)
+	}
+
+	return envelopes, nil
+}
+
+func (a *Attestations) loadStatementAttestation(repo *git.Repository, blobID plumbing.Hash, refName, targetID, payloadDigest string) (*sslibdsse.Envelope, error) {
+	envBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := validateStatementAttestation(env, refName, targetID, payloadDigest); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func validateStatementAttestation(env *sslibdsse.Envelope, refName, targetID, payloadDigest string) error {
+	if !isValidPathComponent(targetID) || !isValidPathComponent(payloadDigest) {
+		return ErrInvalidStatement
+	}
+
+	if envPayloadDigest, err := GetStatementPayloadDigest(env); err != nil {
+		return err
+	} else if envPayloadDigest != payloadDigest {
+		return ErrInvalidStatement
+	}
+
+	statement, err := decodeStatement(env)
+	if err != nil {
+		return err
+	}
+
+	if statement.PredicateType == "" || len(statement.Subject) == 0 {
+		return ErrInvalidStatement
+	}
+
+	if statement.Subject[0].Name != refName || statement.Subject[0].Digest[digestGitCommitKey] != targetID {
+		return ErrInvalidStatement
+	}
+
+	return nil
+}
+
+func decodeStatement(env *sslibdsse.Envelope) (*ita.Statement, error) {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &ita.Statement{}
+	if err := json.Unmarshal(payload, statement); err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+// splitStatementAttestationPath is the inverse of StatementAttestationPath.
+func splitStatementAttestationPath(statementPath string) (string, string, string, error) {
+	refPath, payloadDigest := path.Split(statementPath)
+	refName, targetID := path.Split(path.Clean(refPath))
+	if refName == "" || targetID == "" || payloadDigest == "" {
+		return "", "", "", ErrUnknownAttestationPath
+	}
+
+	return path.Clean(refName), targetID, payloadDigest, nil
+}
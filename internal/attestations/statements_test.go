@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testCodeReviewPredicateType = "https://gittuf.dev/code-review/v0.1"
+	testTestResultPredicateType = "https://in-toto.io/attestation/test-result/v0.1"
+)
+
+func createStatementEnvelope(t *testing.T, refName, targetID, predicateType string, predicate map[string]any) *sslibdsse.Envelope {
+	t.Helper()
+
+	statement, err := NewStatementAttestation(refName, targetID, predicateType, predicate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return env
+}
+
+func TestNewStatementAttestation(t *testing.T) {
+	refName := "refs/heads/main"
+	targetID := "abcdef1234567890abcdef1234567890abcdef12"
+
+	attestation, err := NewStatementAttestation(refName, targetID, testTestResultPredicateType, map[string]any{"result": "PASSED"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, refName, attestation.Subject[0].Name)
+	assert.Equal(t, targetID, attestation.Subject[0].Digest[digestGitCommitKey])
+	assert.Equal(t, testTestResultPredicateType, attestation.PredicateType)
+	assert.Equal(t, "PASSED", attestation.Predicate.AsMap()["result"])
+
+	_, err = NewStatementAttestation(refName, targetID, "", nil)
+	assert.ErrorIs(t, err, ErrInvalidStatement)
+}
+
+func TestSetAndGetStatementAttestation(t *testing.T) {
+	refName := "refs/heads/main"
+	targetID := "abcdef1234567890abcdef1234567890abcdef12"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reviewEnv := createStatementEnvelope(t, refName, targetID, testCodeReviewPredicateType, map[string]any{"reviewer": "jane.doe@example.com"})
+	testEnv := createStatementEnvelope(t, refName, targetID, testTestResultPredicateType, map[string]any{"result": "PASSED"})
+
+	reviewDigest, err := GetStatementPayloadDigest(reviewEnv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := &Attestations{}
+
+	_, err = attestations.GetStatementAttestationFor(repo, refName, targetID, reviewDigest)
+	assert.ErrorIs(t, err, ErrStatementNotFound)
+
+	err = attestations.SetStatementAttestation(repo, reviewEnv, refName, plumbing.ZeroHash.String())
+	assert.ErrorIs(t, err, ErrInvalidStatement)
+
+	err = attestations.SetStatementAttestation(repo, reviewEnv, "refs/heads/feature", targetID)
+	assert.ErrorIs(t, err, ErrInvalidStatement)
+
+	err = attestations.SetStatementAttestation(repo, reviewEnv, refName, targetID)
+	assert.Nil(t, err)
+	err = attestations.SetStatementAttestation(repo, testEnv, refName, targetID)
+	assert.Nil(t, err)
+
+	storedEnv, err := attestations.GetStatementAttestationFor(repo, refName, targetID, reviewDigest)
+	assert.Nil(t, err)
+	assert.Equal(t, reviewEnv, storedEnv)
+
+	predicateType, err := GetStatementPredicateType(storedEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, testCodeReviewPredicateType, predicateType)
+
+	envelopes, err := attestations.GetStatementAttestationsFor(repo, refName, targetID, "")
+	assert.Nil(t, err)
+	assert.Len(t, envelopes, 2)
+
+	envelopes, err = attestations.GetStatementAttestationsFor(repo, refName, targetID, testTestResultPredicateType)
+	assert.Nil(t, err)
+	assert.Equal(t, []*sslibdsse.Envelope{testEnv}, envelopes)
+
+	envelopes, err = attestations.GetStatementAttestationsFor(repo, refName, plumbing.ZeroHash.String(), "")
+	assert.Nil(t, err)
+	assert.Empty(t, envelopes)
+
+	attestationPath := statementsTreeEntryName + "/" + StatementAttestationPath(refName, targetID, reviewDigest)
+	storedEnv, err = attestations.GetEnvelope(repo, attestationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, reviewEnv, storedEnv)
+	assert.Nil(t, ValidateEnvelope(attestationPath, reviewEnv))
+
+	err = ValidateEnvelope(statementsTreeEntryName+"/"+StatementAttestationPath(refName, targetID, reviewDigest), testEnv)
+	assert.ErrorIs(t, err, ErrInvalidStatement)
+
+	err = (&Attestations{}).SetEnvelope(repo, attestationPath, testEnv)
+	assert.ErrorIs(t, err, ErrInvalidStatement)
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/gittuf/gittuf/internal/timestamp"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var ErrTimestampNotFound = errors.New("requested timestamp not found")
+
+// TimestampPath constructs the expected path on-disk for the timestamp of the
+// signature, which is the SHA-256 digest of the signature.
+func TimestampPath(signature []byte) string {
+	digest := sha256.Sum256(signature)
+	return hex.EncodeToString(digest[:])
+}
+
+// SetTimestamp records the timestamp for the signature in the current
+// attestations state, replacing any existing timestamp for the signature. The
+// timestamp is not verified, as it's verified when it's used.
+func (a *Attestations) SetTimestamp(repo *git.Repository, signature []byte, ts *timestamp.Timestamp) error {
+	timestampBytes, err := json.Marshal(ts)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := a.writeBlob(repo, timestampBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.timestamps == nil {
+		a.timestamps = map[string]plumbing.Hash{}
+	}
+
+	a.timestamps[TimestampPath(signature)] = blobID
+	return nil
+}
+
+// HasTimestampFor indicates if a timestamp is recorded for the signature.
+func (a *Attestations) HasTimestampFor(signature []byte) bool {
+	_, has := a.timestamps[TimestampPath(signature)]
+	return has
+}
+
+// GetTimestampFor returns the timestamp recorded for the signature.
+func (a *Attestations) GetTimestampFor(repo *git.Repository, signature []byte) (*timestamp.Timestamp, error) {
+	blobID, has := a.timestamps[TimestampPath(signature)]
+	if !has {
+		return nil, ErrTimestampNotFound
+	}
+
+	timestampBytes, err := readBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := &timestamp.Timestamp{}
+	if err := json.Unmarshal(timestampBytes, ts); err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/timestamp"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAndGetTimestamp(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature := []byte("signature")
+	ts := &timestamp.Timestamp{RFC3161Token: []byte("token")}
+
+	attestations := &Attestations{}
+
+	assert.False(t, attestations.HasTimestampFor(signature))
+	_, err = attestations.GetTimestampFor(repo, signature)
+	assert.ErrorIs(t, err, ErrTimestampNotFound)
+
+	err = attestations.SetTimestamp(repo, signature, ts)
+	assert.Nil(t, err)
+
+	assert.True(t, attestations.HasTimestampFor(signature))
+	storedTimestamp, err := attestations.GetTimestampFor(repo, signature)
+	assert.Nil(t, err)
+	assert.Equal(t, ts, storedTimestamp)
+
+	assert.False(t, attestations.HasTimestampFor([]byte("other signature")))
+	_, err = attestations.GetTimestampFor(repo, []byte("other signature"))
+	assert.ErrorIs(t, err, ErrTimestampNotFound)
+
+	// The timestamp for a signature can be replaced
+	newTimestamp := &timestamp.Timestamp{RFC3161Token: []byte("new token")}
+	err = attestations.SetTimestamp(repo, signature, newTimestamp)
+	assert.Nil(t, err)
+
+	storedTimestamp, err = attestations.GetTimestampFor(repo, signature)
+	assert.Nil(t, err)
+	assert.Equal(t, newTimestamp, storedTimestamp)
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	ita "github.com/in-toto/attestation/go/v1"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	notBeforeKey = "notBefore"
+	notAfterKey  = "notAfter"
+)
+
+var (
+	ErrInvalidValidityPeriod  = errors.New("attestation's validity period ends before it begins")
+	ErrAttestationNotYetValid = errors.New("attestation is not valid yet")
+	ErrAttestationExpired     = errors.New("attestation has expired")
+)
+
+// SetValidityPeriod records the period during which the attestation in the
+// statement may be used. The period is stored in the statement's predicate as
+// RFC 3339 timestamps using the `notBefore` and `notAfter` keys. A zero value
+// for either bound leaves that side of the period open. As the period is part
+// of the signed payload, it must be set before the statement is signed.
+func SetValidityPeriod(statement *ita.Statement, notBefore, notAfter time.Time) error {
+	if !notBefore.IsZero() && !notAfter.IsZero() && notAfter.Before(notBefore) {
+		return ErrInvalidValidityPeriod
+	}
+
+	if statement.Predicate == nil {
+		statement.Predicate = &structpb.Struct{}
+	}
+	if statement.Predicate.Fields == nil {
+		statement.Predicate.Fields = map[string]*structpb.Value{}
+	}
+
+	if !notBefore.IsZero() {
+		statement.Predicate.Fields[notBeforeKey] = structpb.NewStringValue(notBefore.UTC().Format(time.RFC3339))
+	}
+	if !notAfter.IsZero() {
+		statement.Predicate.Fields[notAfterKey] = structpb.NewStringValue(notAfter.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// HasValidityPeriod indicates if the attestation in the envelope declares a
+// validity period, in which case it may only be used at times that are known
+// to be within the period.
+func HasValidityPeriod(env *sslibdsse.Envelope) (bool, error) {
+	predicate, err := getPredicate(env)
+	if err != nil {
+		return false, err
+	}
+
+	_, hasNotBefore := predicate[notBeforeKey]
+	_, hasNotAfter := predicate[notAfterKey]
+	return hasNotBefore || hasNotAfter, nil
+}
+
+// VerifyValidityPeriod checks that the attestation in the envelope may be used
+// at the specified time. Attestations that do not declare a validity period
+// are always valid.
+func VerifyValidityPeriod(env *sslibdsse.Envelope, at time.Time) error {
+	predicate, err := getPredicate(env)
+	if err != nil {
+		return err
+	}
+
+	if notBeforeValue, has := predicate[notBeforeKey]; has {
+		notBefore, err := parseValidityTimestamp(notBeforeValue)
+		if err != nil {
+			return err
+		}
+
+		if at.Before(notBefore) {
+			return ErrAttestationNotYetValid
+		}
+	}
+
+	if notAfterValue, has := predicate[notAfterKey]; has {
+		notAfter, err := parseValidityTimestamp(notAfterValue)
+		if err != nil {
+			return err
+		}
+
+		if at.After(notAfter) {
+			return ErrAttestationExpired
+		}
+	}
+
+	return nil
+}
+
+func getPredicate(env *sslibdsse.Envelope) (map[string]any, error) {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return nil, err
+	}
+
+	return attestation.Predicate.AsMap(), nil
+}
+
+func parseValidityTimestamp(value any) (time.Time, error) {
+	timestamp, isString := value.(string)
+	if !isString {
+		return time.Time{}, ErrInvalidValidityPeriod
+	}
+
+	return time.Parse(time.RFC3339, timestamp)
+}
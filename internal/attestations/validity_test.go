@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidityPeriod(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	notBefore := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.Add(14 * 24 * time.Hour)
+
+	t.Run("no validity period", func(t *testing.T) {
+		env := createReferenceAuthorizationAttestationEnvelopes(t, testRef, testID, testID)
+
+		hasValidityPeriod, err := HasValidityPeriod(env)
+		assert.Nil(t, err)
+		assert.False(t, hasValidityPeriod)
+
+		err = VerifyValidityPeriod(env, notBefore)
+		assert.Nil(t, err)
+	})
+
+	t.Run("with validity period", func(t *testing.T) {
+		authorization, err := NewReferenceAuthorization(testRef, testID, testID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = SetValidityPeriod(authorization, notBefore, notAfter)
+		assert.Nil(t, err)
+
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The authorization remains valid for its original parameters
+		err = validateReferenceAuthorization(env, testRef, testID, testID)
+		assert.Nil(t, err)
+
+		hasValidityPeriod, err := HasValidityPeriod(env)
+		assert.Nil(t, err)
+		assert.True(t, hasValidityPeriod)
+
+		err = VerifyValidityPeriod(env, notBefore.Add(time.Hour))
+		assert.Nil(t, err)
+
+		err = VerifyValidityPeriod(env, notBefore.Add(-time.Hour))
+		assert.ErrorIs(t, err, ErrAttestationNotYetValid)
+
+		err = VerifyValidityPeriod(env, notAfter.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrAttestationExpired)
+	})
+
+	t.Run("with only expiry", func(t *testing.T) {
+		authorization, err := NewReferenceAuthorization(testRef, testID, testID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = SetValidityPeriod(authorization, time.Time{}, notAfter)
+		assert.Nil(t, err)
+
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = VerifyValidityPeriod(env, notBefore.Add(-time.Hour))
+		assert.Nil(t, err)
+
+		err = VerifyValidityPeriod(env, notAfter.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrAttestationExpired)
+	})
+
+	t.Run("invalid validity period", func(t *testing.T) {
+		authorization, err := NewReferenceAuthorization(testRef, testID, testID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = SetValidityPeriod(authorization, notAfter, notBefore)
+		assert.ErrorIs(t, err, ErrInvalidValidityPeriod)
+	})
+}
@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+)
+
+// ReviewerStatusApproved is the status of reviewers who approved a pull
+// request.
+const ReviewerStatusApproved = "APPROVED"
+
+var (
+	ErrUnexpectedResponse = errors.New("unexpected response from Bitbucket")
+	ErrInvalidIdentityMap = errors.New("invalid Bitbucket identity map")
+)
+
+// User identifies a Bitbucket Data Center user. Principal is the gittuf
+// principal the user is mapped to, if any.
+type User struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Email       string `json:"email,omitempty"`
+	Principal   string `json:"principal,omitempty"`
+}
+
+// Approval is a reviewer's approval of a pull request.
+type Approval struct {
+	User   User   `json:"user"`
+	Status string `json:"status"`
+}
+
+// PullRequest summarizes a Bitbucket Data Center pull request and the
+// approvals it received. It is recorded in Bitbucket pull request
+// attestations.
+type PullRequest struct {
+	URL         string     `json:"url"`
+	Project     string     `json:"project"`
+	Repository  string     `json:"repository"`
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	State       string     `json:"state"`
+	FromRef     string     `json:"fromRef"`
+	FromCommit  string     `json:"fromCommit"`
+	ToRef       string     `json:"toRef"`
+	ToCommit    string     `json:"toCommit"`
+	MergeCommit string     `json:"mergeCommit,omitempty"`
+	Author      User       `json:"author"`
+	Approvals   []Approval `json:"approvals"`
+}
+
+// RefName returns the absolute name of the branch the pull request targets.
+func (p *PullRequest) RefName() string {
+	if strings.HasPrefix(p.ToRef, gitinterface.RefPrefix) {
+		return p.ToRef
+	}
+
+	return gitinterface.BranchRefPrefix + p.ToRef
+}
+
+// IdentityMap maps the names of Bitbucket users to the IDs of the gittuf
+// principals, such as key IDs, that they correspond to. It's encoded as a JSON
+// object.
+type IdentityMap map[string]string
+
+// LoadIdentityMap parses a JSON encoded identity map.
+func LoadIdentityMap(contents []byte) (IdentityMap, error) {
+	identities := IdentityMap{}
+	if err := json.Unmarshal(contents, &identities); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIdentityMap, err)
+	}
+
+	for name, principal := range identities {
+		if name == "" || principal == "" {
+			return nil, fmt.Errorf("%w: user names and principals must not be empty", ErrInvalidIdentityMap)
+		}
+	}
+
+	return identities, nil
+}
+
+// MapIdentities sets the principal of the pull request's author and
+// approvers using the identity map. Users missing in the map are left
+// unmapped.
+func (p *PullRequest) MapIdentities(identities IdentityMap) {
+	p.Author.Principal = identities[p.Author.Name]
+	for i := range p.Approvals {
+		p.Approvals[i].User.Principal = identities[p.Approvals[i].User.Name]
+	}
+}
+
+// Client is a minimal client for the parts of Bitbucket Data Center's REST API
+// used by gittuf.
+type Client struct {
+	baseURL     string
+	tokenSource credentials.Source
+	httpClient  *http.Client
+}
+
+// NewClient returns a Client for the Bitbucket Data Center instance at baseURL.
+// Requests are authenticated using the HTTP access tokens obtained from
+// tokenSource. If no token is available, only pull requests visible to
+// anonymous users can be inspected.
+func NewClient(baseURL string, tokenSource credentials.Source) *Client {
+	return &Client{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		tokenSource: tokenSource,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type userInfo struct {
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+type refInfo struct {
+	ID           string `json:"id"`
+	LatestCommit string `json:"latestCommit"`
+}
+
+type participantInfo struct {
+	User   userInfo `json:"user"`
+	Status string   `json:"status"`
+}
+
+type pullRequestInfo struct {
+	ID           int               `json:"id"`
+	Title        string            `json:"title"`
+	State        string            `json:"state"`
+	FromRef      refInfo           `json:"fromRef"`
+	ToRef        refInfo           `json:"toRef"`
+	Author       participantInfo   `json:"author"`
+	Reviewers    []participantInfo `json:"reviewers"`
+	Participants []participantInfo `json:"participants"`
+	Properties   struct {
+		MergeCommit *struct {
+			ID string `json:"id"`
+		} `json:"mergeCommit"`
+	} `json:"properties"`
+	Links struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+// GetPullRequest returns the pull request with the specified ID in the
+// repository. Only the reviewers and participants who approved the pull
+// request are included in its approvals.
+func (c *Client) GetPullRequest(ctx context.Context, projectKey, repositorySlug string, pullRequestID int) (*PullRequest, error) {
+	info := &pullRequestInfo{}
+	if err := c.get(ctx, fmt.Sprintf("rest/api/latest/projects/%s/repos/%s/pull-requests/%d", url.PathEscape(projectKey), url.PathEscape(repositorySlug), pullRequestID), info); err != nil {
+		return nil, err
+	}
+
+	pullRequest := &PullRequest{
+		URL:        fmt.Sprintf("%s/projects/%s/repos/%s/pull-requests/%d", c.baseURL, projectKey, repositorySlug, info.ID),
+		Project:    projectKey,
+		Repository: repositorySlug,
+		ID:         info.ID,
+		Title:      info.Title,
+		State:      info.State,
+		FromRef:    info.FromRef.ID,
+		FromCommit: info.FromRef.LatestCommit,
+		ToRef:      info.ToRef.ID,
+		ToCommit:   info.ToRef.LatestCommit,
+		Author:     newUser(info.Author.User),
+		Approvals:  []Approval{},
+	}
+	if len(info.Links.Self) > 0 {
+		pullRequest.URL = info.Links.Self[0].Href
+	}
+	if info.Properties.MergeCommit != nil {
+		pullRequest.MergeCommit = info.Properties.MergeCommit.ID
+	}
+
+	approvers := map[string]bool{}
+	for _, participant := range append(info.Reviewers, info.Participants...) {
+		if participant.Status != ReviewerStatusApproved || approvers[participant.User.Name] {
+			continue
+		}
+		approvers[participant.User.Name] = true
+
+		pullRequest.Approvals = append(pullRequest.Approvals, Approval{
+			User:   newUser(participant.User),
+			Status: participant.Status,
+		})
+	}
+
+	sort.Slice(pullRequest.Approvals, func(i, j int) bool {
+		return pullRequest.Approvals[i].User.Name < pullRequest.Approvals[j].User.Name
+	})
+
+	return pullRequest, nil
+}
+
+func newUser(info userInfo) User {
+	return User{
+		Name:        info.Name,
+		Slug:        info.Slug,
+		DisplayName: info.DisplayName,
+		Email:       info.EmailAddress,
+	}
+}
+
+func (c *Client) get(ctx context.Context, path string, response any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+path, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	if c.tokenSource != nil {
+		token, err := c.tokenSource.Token(ctx)
+		if err != nil {
+			return err
+		}
+		if token != nil {
+			request.Header.Set("Authorization", "Bearer "+token.Value)
+		}
+	}
+
+	resp, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: GET %s returned %s: %s", ErrUnexpectedResponse, path, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(response)
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPullRequestResponse = `{
+  "id": 42,
+  "title": "Add feature",
+  "state": "MERGED",
+  "fromRef": {"id": "refs/heads/feature", "latestCommit": "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+  "toRef": {"id": "refs/heads/main", "latestCommit": "1b2c3d4e5f60718293a4b5c6d7e8f9012a3b4c5d"},
+  "author": {"user": {"name": "jane", "slug": "jane", "displayName": "Jane Doe", "emailAddress": "jane@example.com"}, "status": "UNAPPROVED"},
+  "reviewers": [
+    {"user": {"name": "john", "slug": "john", "displayName": "John Doe", "emailAddress": "john@example.com"}, "status": "APPROVED"},
+    {"user": {"name": "bob", "slug": "bob", "displayName": "Bob"}, "status": "NEEDS_WORK"}
+  ],
+  "participants": [
+    {"user": {"name": "alice", "slug": "alice", "displayName": "Alice"}, "status": "APPROVED"},
+    {"user": {"name": "john", "slug": "john", "displayName": "John Doe", "emailAddress": "john@example.com"}, "status": "APPROVED"}
+  ],
+  "properties": {"mergeCommit": {"id": "c0ffee0000000000000000000000000000000000"}},
+  "links": {"self": [{"href": "https://bitbucket.example.com/projects/PRJ/repos/repo/pull-requests/42"}]}
+}`
+
+func TestPullRequestRefName(t *testing.T) {
+	assert.Equal(t, "refs/heads/main", (&PullRequest{ToRef: "main"}).RefName())
+	assert.Equal(t, "refs/heads/main", (&PullRequest{ToRef: "refs/heads/main"}).RefName())
+}
+
+func TestClientGetPullRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/latest/projects/PRJ/repos/repo/pull-requests/42" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer http-access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(testPullRequestResponse)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	t.Run("authenticated", func(t *testing.T) {
+		client := NewClient(server.URL+"/", credentials.Static("http-access-token"))
+
+		pullRequest, err := client.GetPullRequest(context.Background(), "PRJ", "repo", 42)
+		assert.Nil(t, err)
+		assert.Equal(t, "https://bitbucket.example.com/projects/PRJ/repos/repo/pull-requests/42", pullRequest.URL)
+		assert.Equal(t, "refs/heads/main", pullRequest.RefName())
+		assert.Equal(t, "MERGED", pullRequest.State)
+		assert.Equal(t, "c0ffee0000000000000000000000000000000000", pullRequest.MergeCommit)
+		assert.Equal(t, "jane", pullRequest.Author.Name)
+		assert.Equal(t, []Approval{
+			{User: User{Name: "alice", Slug: "alice", DisplayName: "Alice"}, Status: ReviewerStatusApproved},
+			{User: User{Name: "john", Slug: "john", DisplayName: "John Doe", Email: "john@example.com"}, Status: ReviewerStatusApproved},
+		}, pullRequest.Approvals)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		client := NewClient(server.URL, credentials.Env("GITTUF_TEST_UNSET_BITBUCKET_TOKEN"))
+
+		_, err := client.GetPullRequest(context.Background(), "PRJ", "repo", 42)
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client := NewClient(server.URL, credentials.Static("http-access-token"))
+
+		_, err := client.GetPullRequest(context.Background(), "PRJ", "repo", 7)
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+}
+
+func TestIdentityMap(t *testing.T) {
+	identities, err := LoadIdentityMap([]byte(`{"john": "SHA256:john-key", "jane": "jane@example.com"}`))
+	assert.Nil(t, err)
+
+	pullRequest := &PullRequest{
+		Author: User{Name: "jane"},
+		Approvals: []Approval{
+			{User: User{Name: "alice"}, Status: ReviewerStatusApproved},
+			{User: User{Name: "john"}, Status: ReviewerStatusApproved},
+		},
+	}
+	pullRequest.MapIdentities(identities)
+	assert.Equal(t, "jane@example.com", pullRequest.Author.Principal)
+	assert.Equal(t, "", pullRequest.Approvals[0].User.Principal)
+	assert.Equal(t, "SHA256:john-key", pullRequest.Approvals[1].User.Principal)
+
+	_, err = LoadIdentityMap([]byte(`["john"]`))
+	assert.ErrorIs(t, err, ErrInvalidIdentityMap)
+
+	_, err = LoadIdentityMap([]byte(`{"john": ""}`))
+	assert.ErrorIs(t, err, ErrInvalidIdentityMap)
+}
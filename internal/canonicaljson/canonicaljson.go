@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package canonicaljson implements the canonical JSON serialization gittuf uses
+// for signed payloads, such as policy metadata and attestations. The
+// serialization follows the JSON Canonicalization Scheme (RFC 8785), so that
+// the same document is always serialized to the same bytes, regardless of the
+// implementation or version of gittuf that created it:
+//
+//   - no insignificant whitespace is emitted,
+//   - object members are sorted by their names, compared as arrays of UTF-16
+//     code units,
+//   - strings are emitted as UTF-8, escaping only the quotation mark, the
+//     reverse solidus, and control characters, using the two character
+//     escapes \b, \t, \n, \f, and \r where available and lowercase \u00xx
+//     escapes otherwise,
+//   - numbers are emitted as they are serialized by ECMAScript, so integers
+//     are emitted without a fraction or exponent, and
+//   - the literals true, false, and null are emitted as is.
+//
+// In addition, documents must be valid UTF-8, objects must not contain
+// duplicate member names, and integers must be represented exactly by an IEEE
+// 754 double, which holds for all integers whose absolute value doesn't exceed
+// 2^53.
+package canonicaljson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxSafeInteger is the largest integer below which all integers can be
+// represented exactly by an IEEE 754 double.
+const maxSafeInteger = 1 << 53
+
+var (
+	ErrInvalidJSON       = errors.New("invalid JSON document")
+	ErrInvalidUTF8       = errors.New("JSON document is not valid UTF-8")
+	ErrDuplicateMember   = errors.New("JSON object contains duplicate member")
+	ErrUnsupportedNumber = errors.New("JSON number cannot be represented exactly")
+)
+
+// Marshal returns the canonical JSON serialization of v. v is first serialized
+// using encoding/json, so the struct tags and custom marshalers of v are
+// honored.
+func Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return Canonicalize(data)
+}
+
+// Canonicalize returns the canonical serialization of the JSON document.
+func Canonicalize(data []byte) ([]byte, error) {
+	if !utf8.Valid(data) {
+		return nil, ErrInvalidUTF8
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var buf bytes.Buffer
+	if err := encodeValue(decoder, &buf); err != nil {
+		return nil, err
+	}
+
+	// The document must contain a single value
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: unexpected data after value", ErrInvalidJSON)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// member is a member of a JSON object, with its value already serialized.
+type member struct {
+	name  string
+	value []byte
+}
+
+func encodeValue(decoder *json.Decoder, buf *bytes.Buffer) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		switch token {
+		case '{':
+			return encodeObject(decoder, buf)
+		case '[':
+			return encodeArray(decoder, buf)
+		default:
+			return fmt.Errorf("%w: unexpected '%s'", ErrInvalidJSON, token)
+		}
+	case string:
+		encodeString(token, buf)
+	case json.Number:
+		number, err := encodeNumber(token)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(number)
+	case bool:
+		buf.WriteString(strconv.FormatBool(token))
+	case nil:
+		buf.WriteString("null")
+	}
+
+	return nil
+}
+
+func encodeObject(decoder *json.Decoder, buf *bytes.Buffer) error {
+	members := []member{}
+	names := map[string]bool{}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+		}
+
+		name, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("%w: object member name is not a string", ErrInvalidJSON)
+		}
+		if names[name] {
+			return fmt.Errorf("%w: '%s'", ErrDuplicateMember, name)
+		}
+		names[name] = true
+
+		var value bytes.Buffer
+		if err := encodeValue(decoder, &value); err != nil {
+			return err
+		}
+
+		members = append(members, member{name: name, value: value.Bytes()})
+	}
+
+	// Consume the closing delimiter
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return lessUTF16(members[i].name, members[j].name)
+	})
+
+	buf.WriteByte('{')
+	for i, member := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodeString(member.name, buf)
+		buf.WriteByte(':')
+		buf.Write(member.value)
+	}
+	buf.WriteByte('}')
+
+	return nil
+}
+
+func encodeArray(decoder *json.Decoder, buf *bytes.Buffer) error {
+	buf.WriteByte('[')
+	for i := 0; decoder.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encodeValue(decoder, buf); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+
+	// Consume the closing delimiter
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+
+	return nil
+}
+
+func encodeString(s string, buf *bytes.Buffer) {
+	const hex = "0123456789abcdef"
+
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\r':
+			buf.WriteString(`\r`)
+		default:
+			if c < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[c>>4])
+				buf.WriteByte(hex[c&0xf])
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+func encodeNumber(number json.Number) (string, error) {
+	literal := number.String()
+	isInteger := !strings.ContainsAny(literal, ".eE")
+
+	if isInteger {
+		if value, err := strconv.ParseInt(literal, 10, 64); err == nil && value <= maxSafeInteger && value >= -maxSafeInteger {
+			return strconv.FormatInt(value, 10), nil
+		}
+	}
+
+	value, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w: '%s'", ErrUnsupportedNumber, literal)
+	}
+
+	formatted := formatFloat(value)
+	if isInteger && formatted != literal {
+		// The integer was rounded, such as 2^53 + 1
+		return "", fmt.Errorf("%w: '%s'", ErrUnsupportedNumber, literal)
+	}
+
+	return formatted, nil
+}
+
+// formatFloat formats the number as ECMAScript's Number.prototype.toString
+// does.
+func formatFloat(value float64) string {
+	if value == 0 {
+		// This also drops the sign of negative zero
+		return "0"
+	}
+
+	format := byte('f')
+	if abs := math.Abs(value); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+
+	formatted := strconv.FormatFloat(value, format, -1, 64)
+	if format == 'e' {
+		// ECMAScript doesn't pad the exponent, so 1e-07 is formatted as 1e-7
+		if n := len(formatted); n >= 4 && formatted[n-4] == 'e' && formatted[n-3] == '-' && formatted[n-2] == '0' {
+			formatted = formatted[:n-2] + formatted[n-1:]
+		}
+	}
+
+	return formatted
+}
+
+// lessUTF16 compares the strings as arrays of UTF-16 code units, as required
+// for sorting object members.
+func lessUTF16(a, b string) bool {
+	unitsA := utf16.Encode([]rune(a))
+	unitsB := utf16.Encode([]rune(b))
+
+	for i := 0; i < len(unitsA) && i < len(unitsB); i++ {
+		if unitsA[i] != unitsB[i] {
+			return unitsA[i] < unitsB[i]
+		}
+	}
+
+	return len(unitsA) < len(unitsB)
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package canonicaljson
+
+import (
+	"encoding/json"
+	"testing"
+
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalizeVectors(t *testing.T) {
+	vectors := struct {
+		Vectors []struct {
+			Name      string `json:"name"`
+			Input     string `json:"input"`
+			Canonical string `json:"canonical"`
+		} `json:"vectors"`
+		Invalid []struct {
+			Name  string `json:"name"`
+			Input string `json:"input"`
+		} `json:"invalid"`
+	}{}
+	if err := json.Unmarshal(artifacts.CanonicalJSONVectors, &vectors); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, vector := range vectors.Vectors {
+		t.Run(vector.Name, func(t *testing.T) {
+			canonical, err := Canonicalize([]byte(vector.Input))
+			assert.Nil(t, err)
+			assert.Equal(t, vector.Canonical, string(canonical))
+
+			// Canonicalization is idempotent
+			canonical, err = Canonicalize(canonical)
+			assert.Nil(t, err)
+			assert.Equal(t, vector.Canonical, string(canonical))
+		})
+	}
+
+	for _, vector := range vectors.Invalid {
+		t.Run(vector.Name, func(t *testing.T) {
+			_, err := Canonicalize([]byte(vector.Input))
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	tests := map[string]struct {
+		input         []byte
+		expectedError error
+	}{
+		"invalid UTF-8": {
+			input:         []byte("\"\xff\""),
+			expectedError: ErrInvalidUTF8,
+		},
+		"duplicate member": {
+			input:         []byte(`{"a": {"b": 1, "b": 1}}`),
+			expectedError: ErrDuplicateMember,
+		},
+		"unsafe integer": {
+			input:         []byte(`[-9007199254740993]`),
+			expectedError: ErrUnsupportedNumber,
+		},
+		"not JSON": {
+			input:         []byte(`test payload`),
+			expectedError: ErrInvalidJSON,
+		},
+		"empty": {
+			input:         []byte{},
+			expectedError: ErrInvalidJSON,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Canonicalize(test.input)
+			assert.ErrorIs(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	type payload struct {
+		Type    string            `json:"type"`
+		Version int               `json:"version"`
+		Message string            `json:"message,omitempty"`
+		Keys    map[string]string `json:"keys"`
+	}
+
+	canonical, err := Marshal(&payload{
+		Type:    "root",
+		Version: 1,
+		Message: "<a> & \"b\"\n",
+		Keys:    map[string]string{"z": "1", "a": "2"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"keys":{"a":"2","z":"1"},"message":"<a> & \"b\"\n","type":"root","version":1}`, string(canonical))
+
+	_, err = Marshal(func() {})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ci
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	GitHubActionsProvider = "github-actions"
+	GitLabCIProvider      = "gitlab-ci"
+	BuildkiteProvider     = "buildkite"
+)
+
+var ErrNoCIEnvironment = errors.New("unable to detect supported CI environment")
+
+// Environment describes the CI run gittuf is invoked in.
+type Environment struct {
+	// Provider identifies the CI system, such as GitHub Actions.
+	Provider string `json:"provider"`
+
+	// Repository is the repository the CI run is for, as identified by the CI
+	// system.
+	Repository string `json:"repository,omitempty"`
+
+	// Workflow identifies the pipeline definition that is executing.
+	Workflow string `json:"workflow,omitempty"`
+
+	// RunID uniquely identifies the run within the CI system.
+	RunID string `json:"runID"`
+
+	// RunURL links to the run in the CI system's web interface.
+	RunURL string `json:"runURL,omitempty"`
+
+	// Trigger is the event that started the run, such as a push.
+	Trigger string `json:"trigger,omitempty"`
+
+	// RefName is the absolute path of the Git reference the run is for.
+	RefName string `json:"refName,omitempty"`
+
+	// CommitID is the ID of the commit the run is for.
+	CommitID string `json:"commitID"`
+}
+
+// Detect inspects the environment variables set by supported CI systems to
+// describe the current CI run. Currently, GitHub Actions, GitLab CI, and
+// Buildkite are supported. ErrNoCIEnvironment is returned if gittuf is not
+// running in any of these.
+func Detect() (*Environment, error) {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return detectGitHubActions(), nil
+	case os.Getenv("GITLAB_CI") == "true":
+		return detectGitLabCI(), nil
+	case os.Getenv("BUILDKITE") == "true":
+		return detectBuildkite(), nil
+	}
+
+	return nil, ErrNoCIEnvironment
+}
+
+func detectGitHubActions() *Environment {
+	runID := os.Getenv("GITHUB_RUN_ID")
+	if attempt := os.Getenv("GITHUB_RUN_ATTEMPT"); attempt != "" {
+		runID = fmt.Sprintf("%s-%s", runID, attempt)
+	}
+
+	workflow := os.Getenv("GITHUB_WORKFLOW_REF")
+	if workflow == "" {
+		workflow = os.Getenv("GITHUB_WORKFLOW")
+	}
+
+	return &Environment{
+		Provider:   GitHubActionsProvider,
+		Repository: os.Getenv("GITHUB_REPOSITORY"),
+		Workflow:   workflow,
+		RunID:      runID,
+		RunURL:     fmt.Sprintf("%s/%s/actions/runs/%s", os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID")),
+		Trigger:    os.Getenv("GITHUB_EVENT_NAME"),
+		RefName:    os.Getenv("GITHUB_REF"),
+		CommitID:   os.Getenv("GITHUB_SHA"),
+	}
+}
+
+func detectGitLabCI() *Environment {
+	var refName string
+	if tag := os.Getenv("CI_COMMIT_TAG"); tag != "" {
+		refName = plumbing.NewTagReferenceName(tag).String()
+	} else if branch := os.Getenv("CI_COMMIT_REF_NAME"); branch != "" {
+		refName = plumbing.NewBranchReferenceName(branch).String()
+	}
+
+	workflow := os.Getenv("CI_CONFIG_PATH")
+	if job := os.Getenv("CI_JOB_NAME"); job != "" {
+		workflow = strings.TrimPrefix(fmt.Sprintf("%s:%s", workflow, job), ":")
+	}
+
+	return &Environment{
+		Provider:   GitLabCIProvider,
+		Repository: os.Getenv("CI_PROJECT_PATH"),
+		Workflow:   workflow,
+		RunID:      os.Getenv("CI_JOB_ID"),
+		RunURL:     os.Getenv("CI_JOB_URL"),
+		Trigger:    os.Getenv("CI_PIPELINE_SOURCE"),
+		RefName:    refName,
+		CommitID:   os.Getenv("CI_COMMIT_SHA"),
+	}
+}
+
+func detectBuildkite() *Environment {
+	var refName string
+	if tag := os.Getenv("BUILDKITE_TAG"); tag != "" {
+		refName = plumbing.NewTagReferenceName(tag).String()
+	} else if branch := os.Getenv("BUILDKITE_BRANCH"); branch != "" {
+		refName = plumbing.NewBranchReferenceName(branch).String()
+	}
+
+	return &Environment{
+		Provider:   BuildkiteProvider,
+		Repository: os.Getenv("BUILDKITE_REPO"),
+		Workflow:   os.Getenv("BUILDKITE_PIPELINE_SLUG"),
+		RunID:      os.Getenv("BUILDKITE_BUILD_ID"),
+		RunURL:     os.Getenv("BUILDKITE_BUILD_URL"),
+		Trigger:    os.Getenv("BUILDKITE_SOURCE"),
+		RefName:    refName,
+		CommitID:   os.Getenv("BUILDKITE_COMMIT"),
+	}
+}
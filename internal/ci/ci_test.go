@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func clearCIEnvironment(t *testing.T) {
+	t.Helper()
+
+	// gittuf's own tests may run in CI
+	for _, key := range []string{"GITHUB_ACTIONS", "GITLAB_CI", "BUILDKITE", "GITHUB_RUN_ATTEMPT", "GITHUB_WORKFLOW_REF", "CI_COMMIT_TAG", "BUILDKITE_TAG"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Run("no CI environment", func(t *testing.T) {
+		clearCIEnvironment(t)
+
+		_, err := Detect()
+		assert.ErrorIs(t, err, ErrNoCIEnvironment)
+	})
+
+	t.Run("GitHub Actions", func(t *testing.T) {
+		clearCIEnvironment(t)
+		t.Setenv("GITHUB_ACTIONS", "true")
+		t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+		t.Setenv("GITHUB_REPOSITORY", "gittuf/gittuf")
+		t.Setenv("GITHUB_WORKFLOW_REF", "gittuf/gittuf/.github/workflows/ci.yml@refs/heads/main")
+		t.Setenv("GITHUB_RUN_ID", "1234")
+		t.Setenv("GITHUB_RUN_ATTEMPT", "2")
+		t.Setenv("GITHUB_EVENT_NAME", "push")
+		t.Setenv("GITHUB_REF", "refs/heads/main")
+		t.Setenv("GITHUB_SHA", "abcdef")
+
+		env, err := Detect()
+		assert.Nil(t, err)
+		assert.Equal(t, &Environment{
+			Provider:   GitHubActionsProvider,
+			Repository: "gittuf/gittuf",
+			Workflow:   "gittuf/gittuf/.github/workflows/ci.yml@refs/heads/main",
+			RunID:      "1234-2",
+			RunURL:     "https://github.com/gittuf/gittuf/actions/runs/1234",
+			Trigger:    "push",
+			RefName:    "refs/heads/main",
+			CommitID:   "abcdef",
+		}, env)
+	})
+
+	t.Run("GitLab CI", func(t *testing.T) {
+		clearCIEnvironment(t)
+		t.Setenv("GITLAB_CI", "true")
+		t.Setenv("CI_PROJECT_PATH", "gittuf/gittuf")
+		t.Setenv("CI_CONFIG_PATH", ".gitlab-ci.yml")
+		t.Setenv("CI_JOB_NAME", "build")
+		t.Setenv("CI_JOB_ID", "42")
+		t.Setenv("CI_JOB_URL", "https://gitlab.com/gittuf/gittuf/-/jobs/42")
+		t.Setenv("CI_PIPELINE_SOURCE", "merge_request_event")
+		t.Setenv("CI_COMMIT_TAG", "v1.0.0")
+		t.Setenv("CI_COMMIT_SHA", "abcdef")
+
+		env, err := Detect()
+		assert.Nil(t, err)
+		assert.Equal(t, &Environment{
+			Provider:   GitLabCIProvider,
+			Repository: "gittuf/gittuf",
+			Workflow:   ".gitlab-ci.yml:build",
+			RunID:      "42",
+			RunURL:     "https://gitlab.com/gittuf/gittuf/-/jobs/42",
+			Trigger:    "merge_request_event",
+			RefName:    "refs/tags/v1.0.0",
+			CommitID:   "abcdef",
+		}, env)
+	})
+
+	t.Run("Buildkite", func(t *testing.T) {
+		clearCIEnvironment(t)
+		t.Setenv("BUILDKITE", "true")
+		t.Setenv("BUILDKITE_REPO", "git@github.com:gittuf/gittuf.git")
+		t.Setenv("BUILDKITE_PIPELINE_SLUG", "gittuf")
+		t.Setenv("BUILDKITE_BUILD_ID", "0190-build")
+		t.Setenv("BUILDKITE_BUILD_URL", "https://buildkite.com/gittuf/gittuf/builds/7")
+		t.Setenv("BUILDKITE_SOURCE", "webhook")
+		t.Setenv("BUILDKITE_BRANCH", "main")
+		t.Setenv("BUILDKITE_COMMIT", "abcdef")
+
+		env, err := Detect()
+		assert.Nil(t, err)
+		assert.Equal(t, &Environment{
+			Provider:   BuildkiteProvider,
+			Repository: "git@github.com:gittuf/gittuf.git",
+			Workflow:   "gittuf",
+			RunID:      "0190-build",
+			RunURL:     "https://buildkite.com/gittuf/gittuf/builds/7",
+			Trigger:    "webhook",
+			RefName:    "refs/heads/main",
+			CommitID:   "abcdef",
+		}, env)
+	})
+}
@@ -11,7 +11,8 @@ import (
 )
 
 type options struct {
-	force bool
+	force  bool
+	server bool
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -22,6 +23,13 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		false,
 		"overwrite hooks, if they already exist",
 	)
+
+	cmd.Flags().BoolVar(
+		&o.server,
+		"server",
+		false,
+		"add a pre-receive hook that rejects pushes failing verification to a repository hosted on a server, such as a Gitea or Forgejo repository",
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
@@ -30,14 +38,19 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	err = repo.UpdateHook(repository.HookPrePush, prePushScript, o.force)
+	hookType, script := repository.HookPrePush, prePushScript
+	if o.server {
+		hookType, script = repository.HookPreReceive, preReceiveScript
+	}
+
+	err = repo.UpdateHook(hookType, script, o.force)
 	var hookErr *repository.ErrHookExists
 	if errors.As(err, &hookErr) {
 		fmt.Fprintf(
 			cmd.ErrOrStderr(),
 			"'%s' already exists. Use --force flag or merge existing hook and the following script manually:\n\n%s\n",
 			string(hookErr.HookType),
-			prePushScript,
+			script,
 		)
 	}
 	return err
@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package addhooks
+
+var preReceiveScript = []byte(`#!/bin/sh
+set -e
+
+if ! command -v gittuf > /dev/null
+then
+    echo "gittuf could not be found"
+    echo "Download from: https://github.com/gittuf/gittuf/releases/latest"
+    exit 1
+fi
+
+exec gittuf --non-interactive verify-push
+`)
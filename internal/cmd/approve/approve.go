@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package approve
+
+import (
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	signingKey string
+	expiresIn  time.Duration
+	rekorURL   string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use for approving the change",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+
+	cmd.Flags().DurationVar(
+		&o.expiresIn,
+		"expires-in",
+		0,
+		"duration after which the approval can no longer be used, e.g. 336h for 14 days, which is only honored for RSL entries with a trusted timestamp (only applies when the authorization is created)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to log created attestation to",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	return repo.Approve(ctx, signer, args[0], args[1], o.expiresIn, true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "approve <ref> <target>",
+		Short:             "Approve a proposed change to a Git reference",
+		Long:              "This command allows users to approve merging the changes in the target ref into the specified ref. If the specified ref is a tag (refs/tags/<name>), the approval is for creating the tag pointing to the target revision. Approvals are recorded as reference authorization attestations and count towards the threshold of the rules protecting the ref, so they can be issued by users who never push to the repository.",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRefs, common.CompleteRefs),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
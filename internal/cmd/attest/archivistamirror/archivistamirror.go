@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package archivistamirror
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	gitoids, err := repo.MirrorAttestationsToArchivista(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(gitoids))
+	for path := range gitoids {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		fmt.Printf("%s: %s\n", path, gitoids[path])
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "archivista-mirror <url>",
+		Short:             "Mirror the repository's attestations to an Archivista instance",
+		Long:              "This command allows users to upload the repository's current attestations to an Archivista instance, making them available to in-toto and witness deployments. The gitoid assigned to each attestation is printed.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attest
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/attest/archivistamirror"
+	"github.com/gittuf/gittuf/internal/cmd/attest/countersign"
+	"github.com/gittuf/gittuf/internal/cmd/attest/exportbundle"
+	"github.com/gittuf/gittuf/internal/cmd/attest/fromci"
+	"github.com/gittuf/gittuf/internal/cmd/attest/importbundle"
+	"github.com/gittuf/gittuf/internal/cmd/attest/lfsobjects"
+	"github.com/gittuf/gittuf/internal/cmd/attest/prune"
+	"github.com/gittuf/gittuf/internal/cmd/attest/pushevent"
+	"github.com/gittuf/gittuf/internal/cmd/attest/rebuild"
+	"github.com/gittuf/gittuf/internal/cmd/attest/reviewapprovals"
+	"github.com/gittuf/gittuf/internal/cmd/attest/runhook"
+	"github.com/gittuf/gittuf/internal/cmd/attest/statement"
+	"github.com/gittuf/gittuf/internal/cmd/attest/verify"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "attest",
+		Aliases:           []string{"attestations"},
+		Short:             "Tools to manage the repository's attestations",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(archivistamirror.New())
+	cmd.AddCommand(countersign.New())
+	cmd.AddCommand(exportbundle.New())
+	cmd.AddCommand(fromci.New())
+	cmd.AddCommand(importbundle.New())
+	cmd.AddCommand(lfsobjects.New())
+	cmd.AddCommand(prune.New())
+	cmd.AddCommand(pushevent.New())
+	cmd.AddCommand(rebuild.New())
+	cmd.AddCommand(reviewapprovals.New())
+	cmd.AddCommand(runhook.New())
+	cmd.AddCommand(statement.New())
+	cmd.AddCommand(verify.New())
+
+	return cmd
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package countersign
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	signingKey string
+	rekorURL   string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign attestation",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to log created attestation to",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	return repo.CounterSignAttestation(ctx, signer, args[0], true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "counter-sign <path>",
+		Short:             "Add a signature to an existing attestation",
+		Long:              "This command allows users to add their signature to an existing attestation, identified by its path in the attestations namespace (for example, 'push-events/<rsl-entry-id>'). Multiple principals can sign the same statement this way, and each signer is counted once when the attestation is used to meet a threshold.",
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
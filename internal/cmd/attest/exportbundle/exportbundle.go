@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package exportbundle
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	paths []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(
+		&o.paths,
+		"path",
+		[]string{},
+		"path of attestation in the attestations namespace to include in the bundle (all attestations are included if not specified)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	bundleContents, err := repo.ExportAttestationsBundle(cmd.Context(), o.paths...)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(args[0], bundleContents, 0o644) //nolint:gosec
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "export-bundle <file>",
+		Short:             "Export attestations and the policy needed to verify them into a bundle",
+		Long:              "This command allows users to export the repository's attestations along with the current policy into a portable bundle. The bundle can be carried to a disconnected network, verified, and imported using 'gittuf attest import-bundle', supporting approval workflows that span air-gapped environments.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
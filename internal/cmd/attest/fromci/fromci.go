@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fromci
+
+import (
+	"github.com/gittuf/gittuf/internal/ci"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/signerverifier/sigstore"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	signingKey string
+	fulcioURL  string
+	rekorURL   string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign attestation instead of the CI environment's OIDC identity",
+	)
+
+	cmd.Flags().StringVar(
+		&o.fulcioURL,
+		"fulcio-url",
+		"",
+		"URL of Fulcio instance to request signing certificate from (defaults to the Fulcio instance of the configured Sigstore instance)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to log created attestation to",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	ciEnv, err := ci.Detect()
+	if err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	var (
+		signer             sslibdsse.SignerVerifier
+		signingCertificate string
+	)
+	if o.signingKey != "" && o.signingKey != common.SigstoreSigningKey {
+		signer, err = common.LoadSignerForKey(o.signingKey)
+		if err != nil {
+			return err
+		}
+	} else {
+		token, err := sigstore.AmbientToken(cmd.Context())
+		if err != nil {
+			return err
+		}
+		fulcioURL := o.fulcioURL
+		if fulcioURL == "" {
+			instance, err := gitinterface.GetSigstoreInstance()
+			if err != nil {
+				return err
+			}
+			fulcioURL = instance.FulcioURL
+		}
+
+		sigstoreSigner, err := sigstore.NewSigner(cmd.Context(), fulcioURL, token)
+		if err != nil {
+			return err
+		}
+		signer = sigstoreSigner
+		signingCertificate = string(sigstoreSigner.CertificateChain())
+	}
+
+	ctx := cmd.Context()
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	return repo.AddCIRunAttestation(ctx, signer, ciEnv, signingCertificate, true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "from-ci",
+		Short:             "Record an attestation for the current CI run",
+		Long:              "This command allows users to record an attestation describing the CI run it is invoked in. GitHub Actions, GitLab CI, and Buildkite are detected automatically, and the workflow, run ID, trigger, and commit of the run are recorded. By default, the attestation is signed using the CI environment's OIDC identity with a certificate issued by Fulcio. Alternatively, a signing key can be specified.",
+		Args:              cobra.NoArgs,
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package importbundle
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	verifyOnly bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.verifyOnly,
+		"verify-only",
+		false,
+		"verify the bundle without importing its attestations",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	bundleContents, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	if o.verifyOnly {
+		paths, err = repo.VerifyAttestationsBundle(cmd.Context(), bundleContents)
+	} else {
+		paths, err = repo.ImportAttestationsBundle(cmd.Context(), bundleContents, true)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		fmt.Println(path)
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "import-bundle <file>",
+		Short: "Verify and import attestations from a bundle",
+		Long:  "This command allows users to verify a bundle created using 'gittuf attest export-bundle' and import its attestations into the repository. The bundle's policy must be trusted by the repository's current policy, and each attestation must be signed by a key trusted in the bundle's policy. The paths of the verified attestations are printed.",
+		Args:  cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if o.verifyOnly {
+				return nil
+			}
+			return common.CheckIfSigningViable(cmd, args)
+		},
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package lfsobjects
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	signingKey string
+	rekorURL   string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign attestation",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to log created attestation to",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	return repo.AddLFSObjectsAttestation(ctx, signer, args[0], true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "lfs-objects <revision>",
+		Short:             "Record an attestation for the Git LFS objects referenced by a revision",
+		Long:              "This command allows users to record a signed attestation of the set of Git LFS objects referenced by the specified revision, typically a release. The objects must have been fetched, and are checked to match their pointers before the attestation is signed. Signers that attest to the same objects sign the same attestation. When verifying a ref with --verify-lfs, the attestation must meet the predicate policy for Git LFS objects attestations.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRefs),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package prune
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+var ErrSigningKeyNotSpecified = errors.New("signing key must be specified to prune attestations")
+
+type options struct {
+	signingKey string
+	dryRun     bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign tombstone for pruned attestations",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.dryRun,
+		"dry-run",
+		false,
+		"list the attestations that would be pruned without removing them",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	var pruned []*attestations.PrunedAttestation
+	if o.dryRun {
+		pruned, err = repo.FindPrunableAttestations()
+		if err != nil {
+			return err
+		}
+	} else {
+		if o.signingKey == "" {
+			return ErrSigningKeyNotSpecified
+		}
+
+		signer, err := common.LoadSignerForKey(o.signingKey)
+		if err != nil {
+			return err
+		}
+
+		pruned, err = repo.PruneAttestations(cmd.Context(), signer, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, attestation := range pruned {
+		fmt.Printf("%s (%s)\n", attestation.Path, attestation.Reason)
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove superseded and unreachable attestations",
+		Long:  "This command allows users to remove attestations that are no longer needed from the current state of the repository's attestations, keeping it from growing without bound. Attestations about a state of a Git reference that the reference has since moved on from, as recorded in the RSL, are superseded. Attestations about objects that are not reachable from any of the repository's references are also removed. A tombstone listing the pruned attestations is signed and recorded. Pruned attestations remain available in the history of the attestations namespace, so earlier RSL entries can still be verified. The paths of the pruned attestations are printed.",
+		Args:  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if o.dryRun {
+				return nil
+			}
+			return common.CheckIfSigningViable(cmd, args)
+		},
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
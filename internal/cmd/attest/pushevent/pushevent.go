@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pushevent
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	signingKey     string
+	sourceIP       string
+	clientHostname string
+	ciRunURL       string
+	detect         bool
+	rekorURL       string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign attestation",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.sourceIP,
+		"source-ip",
+		"",
+		"IP address the push originated from (only its network range is recorded)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.clientHostname,
+		"hostname",
+		"",
+		"hostname of the client that made the push",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ciRunURL,
+		"ci-run-url",
+		"",
+		"URL of the CI run that made the push",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.detect,
+		"detect",
+		false,
+		"detect the client hostname and CI run URL from the environment when not specified",
+	)
+
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to log created attestation to",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	if o.detect {
+		if o.clientHostname == "" {
+			o.clientHostname, err = os.Hostname()
+			if err != nil {
+				return err
+			}
+		}
+
+		if o.ciRunURL == "" {
+			o.ciRunURL = detectCIRunURL()
+		}
+	}
+
+	ctx := cmd.Context()
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	return repo.AddPushEventAttestation(ctx, signer, args[0], o.sourceIP, o.clientHostname, o.ciRunURL, true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "push-event <ref>",
+		Short:             "Record the context of a push as an attestation",
+		Long:              "This command allows users to record a signed attestation describing the push that created the latest RSL entry for the specified ref. The attestation can include the network range of the source IP address, the client hostname, and the URL of the CI run that made the push, giving incident responders more context than is available in commit metadata.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRefs),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
+
+// detectCIRunURL identifies the URL of the current CI run using the
+// environment variables set by common CI systems.
+func detectCIRunURL() string {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return fmt.Sprintf("%s/%s/actions/runs/%s", os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"))
+	}
+
+	for _, key := range []string{"CI_JOB_URL", "BUILD_URL", "CIRCLE_BUILD_URL"} {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
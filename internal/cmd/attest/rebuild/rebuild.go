@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rebuild
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	signingKey     string
+	artifactName   string
+	artifactPath   string
+	artifactSHA256 string
+	rekorURL       string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign attestation",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.artifactName,
+		"artifact-name",
+		"",
+		"name of artifact that was rebuilt",
+	)
+	cmd.MarkFlagRequired("artifact-name") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.artifactPath,
+		"artifact",
+		"",
+		"path to rebuilt artifact to compute SHA-256 digest of",
+	)
+
+	cmd.Flags().StringVar(
+		&o.artifactSHA256,
+		"artifact-sha256",
+		"",
+		"SHA-256 digest of rebuilt artifact",
+	)
+
+	cmd.MarkFlagsOneRequired("artifact", "artifact-sha256")
+	cmd.MarkFlagsMutuallyExclusive("artifact", "artifact-sha256")
+
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to log created attestation to",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	artifactDigest := o.artifactSHA256
+	if o.artifactPath != "" {
+		artifact, err := os.Open(o.artifactPath)
+		if err != nil {
+			return err
+		}
+		defer artifact.Close() //nolint:errcheck
+
+		hash := sha256.New()
+		if _, err := io.Copy(hash, artifact); err != nil {
+			return err
+		}
+		artifactDigest = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	ctx := cmd.Context()
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	return repo.AddRebuildAttestation(ctx, signer, args[0], o.artifactName, artifactDigest, true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "rebuild <revision>",
+		Short:             "Record an attestation for an artifact rebuilt from a revision",
+		Long:              "This command allows users to record a signed attestation of the SHA-256 digest of an artifact they independently built from the specified revision. Rebuilders that obtain the same digest sign the same attestation. Policy rules can require a threshold of agreeing rebuilders for an artifact before a tag is authorized.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRefs),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reviewapprovals
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/codereview"
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/gittuf/gittuf/internal/gerrit"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// gitHubTokenKey and gitLabTokenKey are the environment variables that
+	// contain the access tokens for GitHub and GitLab, used unless a
+	// credential is configured for the github or gitlab integration.
+	gitHubTokenKey = "GITHUB_TOKEN"        //nolint:gosec
+	gitLabTokenKey = "GITTUF_GITLAB_TOKEN" //nolint:gosec
+
+	// gerritUsernameKey and gerritPasswordKey are the environment variables
+	// that contain the credentials used to authenticate to Gerrit.
+	gerritUsernameKey = "GITTUF_GERRIT_USERNAME"
+	gerritPasswordKey = "GITTUF_GERRIT_PASSWORD" //nolint:gosec
+
+	defaultGitLabURL = "https://gitlab.com"
+)
+
+type options struct {
+	platform    string
+	url         string
+	repository  string
+	number      int
+	commitID    string
+	identityMap string
+	signingKey  string
+	rekorURL    string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.platform,
+		"platform",
+		"",
+		fmt.Sprintf("code review platform the change was reviewed on (%s, %s, or %s)", codereview.PlatformGitHub, codereview.PlatformGitLab, codereview.PlatformGerrit),
+	)
+	cmd.MarkFlagRequired("platform") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.url,
+		"url",
+		"",
+		"URL of the code review platform's instance (default is github.com or gitlab.com, required for Gerrit)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.repository,
+		"repository",
+		"",
+		"repository the change was reviewed in, of form {owner}/{repo} for GitHub or the project path for GitLab",
+	)
+
+	cmd.Flags().IntVar(
+		&o.number,
+		"number",
+		0,
+		"number of the pull request, merge request, or Gerrit change",
+	)
+	cmd.MarkFlagRequired("number") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.commitID,
+		"commit",
+		"",
+		"commit to record the approvals for (default is the commit the change was merged as, or its latest commit if it's not merged)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.identityMap,
+		"identity-map",
+		"",
+		"path to a JSON object mapping code review platform user names to gittuf principals",
+	)
+
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign attestation",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to log created attestation to",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	identities := codereview.IdentityMap{}
+	if o.identityMap != "" {
+		contents, err := os.ReadFile(o.identityMap)
+		if err != nil {
+			return err
+		}
+		identities, err = codereview.LoadIdentityMap(contents)
+		if err != nil {
+			return err
+		}
+	}
+
+	review, err := o.getReview(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	return repo.AddReviewApprovalAttestation(ctx, signer, review, identities, true)
+}
+
+// getReview fetches the change from the code review platform.
+func (o *options) getReview(cmd *cobra.Command) (*codereview.Review, error) {
+	switch o.platform {
+	case codereview.PlatformGitHub, codereview.PlatformGitLab:
+		config, err := common.LoadConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		var review *codereview.Review
+		if o.platform == codereview.PlatformGitHub {
+			owner, repository, found := strings.Cut(o.repository, "/")
+			if !found || owner == "" || repository == "" || strings.Contains(repository, "/") {
+				return nil, fmt.Errorf("invalid format for repository, must be {owner}/{repo}")
+			}
+
+			tokenSource, err := credentials.Load(config, "github", gitHubTokenKey)
+			if err != nil {
+				return nil, err
+			}
+
+			review, err = codereview.NewGitHubClient(o.url, tokenSource).GetPullRequest(cmd.Context(), owner, repository, o.number)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			if o.repository == "" {
+				return nil, fmt.Errorf("the project path of the merge request must be set using --repository")
+			}
+
+			tokenSource, err := credentials.Load(config, "gitlab", gitLabTokenKey)
+			if err != nil {
+				return nil, err
+			}
+
+			baseURL := o.url
+			if baseURL == "" {
+				baseURL = defaultGitLabURL
+			}
+
+			review, err = codereview.NewGitLabClient(baseURL, tokenSource).GetMergeRequest(cmd.Context(), o.repository, o.number)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if o.commitID != "" {
+			review.TargetID = o.commitID
+		}
+		return review, nil
+	case codereview.PlatformGerrit:
+		if o.url == "" {
+			return nil, fmt.Errorf("the URL of the Gerrit instance must be set using --url")
+		}
+
+		client := gerrit.NewClient(o.url, os.Getenv(gerritUsernameKey), os.Getenv(gerritPasswordKey))
+		change, err := client.GetChange(cmd.Context(), strconv.Itoa(o.number))
+		if err != nil {
+			return nil, err
+		}
+
+		commitID := o.commitID
+		if commitID == "" {
+			commitID = change.Revision
+		}
+		return codereview.FromGerritChange(change, commitID), nil
+	}
+
+	return nil, fmt.Errorf("unknown code review platform '%s'", o.platform)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "review-approvals",
+		Short:             "Record the approvals a change received in code review in an attestation",
+		Long:              fmt.Sprintf(`This command allows users to import the approvals a GitHub pull request, GitLab merge request, or Gerrit change received in code review into a signed attestation, so that policy rules can require approvals using 'gittuf policy set-required-approvals'. The attestation is recorded for the change's target branch and for the commit the change was merged as, or the change's latest commit if it's not merged yet, unless --commit is set. Only approvals of the change's latest commit are recorded. GitLab doesn't record which commit was approved, so GitLab projects should remove approvals when commits are added to merge requests. Gerrit changes are approved by Code-Review +2 votes. If --identity-map is set, the users who authored and approved the change are mapped to the gittuf principals listed in the file, which contains a JSON object of user names to principal IDs; approvals by unmapped users don't count towards policy. GitHub and GitLab are accessed using the tokens in the %s and %s environment variables respectively, or the credentials configured for the github and gitlab integrations in the gittuf config. Gerrit is accessed using the HTTP credentials in the %s and %s environment variables.`, gitHubTokenKey, gitLabTokenKey, gerritUsernameKey, gerritPasswordKey),
+		Args:              cobra.NoArgs,
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runhook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	signingKey string
+	hookName   string
+	refName    string
+	rekorURL   string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign attestation",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.hookName,
+		"hook-name",
+		"",
+		"name of hook to record in attestation",
+	)
+	cmd.MarkFlagRequired("hook-name") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.refName,
+		"ref",
+		"HEAD",
+		"ref the hook is executed for",
+	)
+
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to log created attestation to",
+	)
+
+	cmd.RegisterFlagCompletionFunc("ref", common.CompleteRefs) //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	hookPath, err := exec.LookPath(args[0])
+	if err != nil {
+		return err
+	}
+	hookContents, err := os.ReadFile(hookPath)
+	if err != nil {
+		return err
+	}
+	hookDigest := sha256.Sum256(hookContents)
+
+	hook := exec.CommandContext(cmd.Context(), hookPath, args[1:]...) //nolint:gosec
+	hook.Stdin = cmd.InOrStdin()
+	hook.Stdout = cmd.OutOrStdout()
+	hook.Stderr = cmd.ErrOrStderr()
+
+	exitCode := 0
+	if err := hook.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return err
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	ctx := cmd.Context()
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	if err := repo.AddHookExecutionAttestation(ctx, signer, o.refName, o.hookName, hex.EncodeToString(hookDigest[:]), exitCode, true); err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return fmt.Errorf("hook '%s' exited with code %d", o.hookName, exitCode)
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "run-hook [flags] -- <hook> [args...]",
+		Short:             "Run a hook and record its result as an attestation",
+		Long:              "This command allows users to run a hook, such as a secret scanner invoked before a push, and record a signed attestation of its result for the current state of the specified ref. The attestation identifies the version of the hook using the SHA-256 digest of the hook executable. Policy rules can require a passing result for a hook before a change to a protected ref is authorized. The result is recorded even if the hook fails, and the command exits with an error in that case.",
+		Args:              cobra.MinimumNArgs(1),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
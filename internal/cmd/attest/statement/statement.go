@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package statement
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	signingKey    string
+	predicateType string
+	predicatePath string
+	rekorURL      string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign attestation",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.predicateType,
+		"predicate-type",
+		"",
+		"type of the statement's predicate, such as a code review approval",
+	)
+	cmd.MarkFlagRequired("predicate-type") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.predicatePath,
+		"predicate",
+		"",
+		"path to JSON object to use as the statement's predicate",
+	)
+
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to log created attestation to",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	predicate := map[string]any{}
+	if o.predicatePath != "" {
+		predicateBytes, err := os.ReadFile(o.predicatePath)
+		if err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal(predicateBytes, &predicate); err != nil {
+			return fmt.Errorf("predicate must be a JSON object: %w", err)
+		}
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	return repo.AddStatementAttestation(ctx, signer, args[0], o.predicateType, predicate, true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "statement <ref>",
+		Short:             "Record an in-toto statement for the current state of a ref",
+		Long:              "This command allows users to record a signed in-toto statement, such as a code review approval or test results, for the current state of the specified ref. The statement's predicate is read from a JSON file and is not interpreted by gittuf. Signers that attest to the same predicate sign the same statement. Policy rules can require statements of specific predicate types before a change to the ref is authorized, meeting the predicate policy for each type.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRefs),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
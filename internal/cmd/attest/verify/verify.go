@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	if err := repo.VerifyStatements(cmd.Context(), args[0]); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Statements for '%s' meet the policy.\n", args[0])
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "verify <ref>",
+		Short:             "Verify the in-toto statements recorded for the current state of a ref",
+		Long:              "This command allows users to check that the in-toto statements recorded for the current state of the specified ref meet the statements required by the latest policy, before the change is pushed. The statements must meet the requirements of at least one rule protecting the ref that requires statements. The requirements are enforced again when the ref's RSL entry is verified.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRefs),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bitbucket
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/bitbucket/hookscript"
+	"github.com/gittuf/gittuf/internal/cmd/bitbucket/recordpullrequest"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "bitbucket",
+		Short:             "Tools to integrate gittuf with Bitbucket Data Center",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(hookscript.New())
+	cmd.AddCommand(recordpullrequest.New())
+
+	return cmd
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hookscript
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// preReceiveScript is run by Bitbucket Data Center in the repository's
+// directory, with the proposed ref updates on stdin. Dry runs, such as those
+// Bitbucket performs to check whether a pull request can be merged, don't
+// include the pushed objects, so they're skipped; the merge itself is
+// verified.
+var preReceiveScript = []byte(`#!/bin/sh
+set -e
+
+if [ "$BB_IS_DRY_RUN" = "true" ]
+then
+    exit 0
+fi
+
+if ! command -v gittuf > /dev/null
+then
+    echo "gittuf could not be found on the Bitbucket node"
+    echo "Download from: https://github.com/gittuf/gittuf/releases/latest"
+    exit 1
+fi
+
+exec gittuf --non-interactive verify-push
+`)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook-script",
+		Short: "Print a pre-receive hook script for Bitbucket Data Center",
+		Long:  `This command allows users to enforce gittuf policies on repositories hosted on Bitbucket Data Center. It prints a pre-receive hook script that verifies every push using "gittuf verify-push". The script can be uploaded using Bitbucket's hook scripts REST API (rest/api/latest/hook-scripts), and enabled as a PRE hook for a project or repository. gittuf must be installed on every Bitbucket node.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_, err := cmd.OutOrStdout().Write(preReceiveScript)
+			return err
+		},
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
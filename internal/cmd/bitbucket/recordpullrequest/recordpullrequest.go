@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package recordpullrequest
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/bitbucket"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+// tokenKey is the environment variable that contains the Bitbucket HTTP access
+// token, so that it isn't exposed in the command line. It is used unless a
+// credential is configured for the bitbucket integration.
+const tokenKey = "GITTUF_BITBUCKET_TOKEN" //nolint:gosec
+
+type options struct {
+	bitbucketURL  string
+	project       string
+	repository    string
+	pullRequestID int
+	commitID      string
+	identityMap   string
+	signingKey    string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.bitbucketURL,
+		"bitbucket-url",
+		"",
+		"URL of the Bitbucket Data Center instance",
+	)
+	cmd.MarkFlagRequired("bitbucket-url") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.project,
+		"project",
+		"",
+		"key of the Bitbucket project the repository belongs to",
+	)
+	cmd.MarkFlagRequired("project") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.repository,
+		"repository",
+		"",
+		"slug of the Bitbucket repository",
+	)
+	cmd.MarkFlagRequired("repository") //nolint:errcheck
+
+	cmd.Flags().IntVar(
+		&o.pullRequestID,
+		"pull-request",
+		0,
+		"ID of the pull request to record",
+	)
+	cmd.MarkFlagRequired("pull-request") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.commitID,
+		"commit",
+		"",
+		"commit to record the pull request for (default is the pull request's merge commit)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.identityMap,
+		"identity-map",
+		"",
+		"path to a JSON object mapping Bitbucket user names to gittuf principals",
+	)
+
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign the Bitbucket pull request attestation",
+	)
+	cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	identities := bitbucket.IdentityMap{}
+	if o.identityMap != "" {
+		contents, err := os.ReadFile(o.identityMap)
+		if err != nil {
+			return err
+		}
+		identities, err = bitbucket.LoadIdentityMap(contents)
+		if err != nil {
+			return err
+		}
+	}
+
+	config, err := common.LoadConfig()
+	if err != nil {
+		return err
+	}
+	tokenSource, err := credentials.Load(config, "bitbucket", tokenKey)
+	if err != nil {
+		return err
+	}
+
+	client := bitbucket.NewClient(o.bitbucketURL, tokenSource)
+	return repo.AddBitbucketPullRequestAttestation(cmd.Context(), signer, client, identities, o.project, o.repository, o.pullRequestID, o.commitID, true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "record-pull-request",
+		Short:             "Record a Bitbucket Data Center pull request and its approvals in an attestation",
+		Long:              fmt.Sprintf(`This command allows users to record a pull request merged on Bitbucket Data Center, and the approvals it received, in a signed attestation. The pull request is fetched using Bitbucket's REST API, and is recorded for its merge commit unless --commit is set. If --identity-map is set, the Bitbucket users who authored and approved the pull request are mapped to the gittuf principals listed in the file, which contains a JSON object of Bitbucket user names to principal IDs. The REST API is accessed anonymously unless the %s environment variable contains an HTTP access token, or a credential is configured for the bitbucket integration in the gittuf config.`, tokenKey),
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -3,7 +3,12 @@
 package clone
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/progress"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/spf13/cobra"
@@ -12,6 +17,7 @@ import (
 type options struct {
 	branch           string
 	expectedRootKeys common.PublicKeys
+	progress         bool
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -27,6 +33,12 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"root-key",
 		"set of initial root of trust keys for the repository (supported values: paths to SSH keys, GPG key fingerprints, Sigstore/Fulcio identities)",
 	)
+	cmd.Flags().BoolVar(
+		&o.progress,
+		"progress",
+		false,
+		"report verification progress on stderr",
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
@@ -35,9 +47,25 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 		dir = args[1]
 	}
 
-	expectedRootKeys := make([]*tuf.Key, len(o.expectedRootKeys))
+	rootKeyPaths := []string(o.expectedRootKeys)
+	if len(rootKeyPaths) == 0 {
+		pinnedRootKeys, err := gitinterface.GetConfigValue(pinnedRootKeysConfigKey(args[0]))
+		if err != nil {
+			return err
+		}
+		for _, keyPath := range strings.Split(pinnedRootKeys, ",") {
+			if keyPath = strings.TrimSpace(keyPath); keyPath != "" {
+				rootKeyPaths = append(rootKeyPaths, keyPath)
+			}
+		}
+	}
+	if len(rootKeyPaths) == 0 {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: no root keys were specified or pinned for the repository, trusting the root keys found in the clone on first use")
+	}
+
+	expectedRootKeys := make([]*tuf.Key, len(rootKeyPaths))
 
-	for index, keyPath := range o.expectedRootKeys {
+	for index, keyPath := range rootKeyPaths {
 		key, err := common.LoadPublicKey(keyPath)
 		if err != nil {
 			return err
@@ -46,15 +74,27 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 		expectedRootKeys[index] = key
 	}
 
-	_, err := repository.Clone(cmd.Context(), args[0], dir, o.branch, expectedRootKeys)
+	ctx := cmd.Context()
+	if o.progress {
+		ctx = progress.ContextWithReporter(ctx, progress.NewReporter(cmd.ErrOrStderr()))
+	}
+
+	_, err := repository.Clone(ctx, args[0], dir, o.branch, expectedRootKeys)
 	return err
 }
 
+// pinnedRootKeysConfigKey returns the Git config key that pins the root keys
+// for the repository at remoteURL.
+func pinnedRootKeysConfigKey(remoteURL string) string {
+	return fmt.Sprintf("gittuf.%s.rootkeys", remoteURL)
+}
+
 func New() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
 		Use:               "clone",
 		Short:             "Clone repository and its gittuf references",
+		Long:              `This command allows users to clone a repository and its gittuf references. The RSL and the checked out reference are verified against the repository's policy before the working tree is created, and the clone is removed if verification fails. The policy is bootstrapped from the root keys specified using --root-key. If no root keys are specified, the root keys pinned for the repository URL using the Git config option 'gittuf.<url>.rootKeys' are used, as a comma separated list of the values accepted by --root-key. If neither is available, the root keys found in the clone are trusted on first use.`,
 		Args:              cobra.MinimumNArgs(1),
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/githubactions"
+	"github.com/gittuf/gittuf/internal/policy"
+)
+
+const (
+	actionsVerifiedResult = ":white_check_mark: Verified"
+	actionsFailedResult   = ":x: Failed"
+)
+
+// ActionsResult returns the text used in job summaries for a verification
+// result.
+func ActionsResult(verified bool) string {
+	if verified {
+		return actionsVerifiedResult
+	}
+	return actionsFailedResult
+}
+
+// ReportStatusesToActions reports the statuses returned by verifying commits or
+// tags to the GitHub Actions job gittuf is running in, if any. The job summary
+// lists the status of each object, each object that failed verification is
+// annotated, and the `verified` output is set to whether all the objects were
+// verified.
+func ReportStatusesToActions(actions *githubactions.Reporter, command string, ids []string, status map[string]string) error {
+	if actions == nil {
+		return nil
+	}
+
+	allVerified := true
+	summary := &strings.Builder{}
+	fmt.Fprintf(summary, "### gittuf %s\n\n| Object | Result | Status |\n| --- | --- | --- |\n", command)
+	for _, id := range ids {
+		verified := policy.IsVerifiedStatus(status[id])
+		if !verified {
+			allVerified = false
+			actions.Error(fmt.Sprintf("gittuf verification failed for %s", id), status[id])
+		}
+
+		fmt.Fprintf(summary, "| `%s` | %s | %s |\n", githubactions.TableCell(id), ActionsResult(verified), githubactions.TableCell(status[id]))
+	}
+
+	return errors.Join(
+		actions.SetOutput("verified", strconv.FormatBool(allVerified)),
+		actions.AddSummary(summary.String()),
+	)
+}
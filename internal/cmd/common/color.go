@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"github.com/gittuf/gittuf/internal/i18n"
+)
+
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+
+	colorGreen = "\033[32m"
+	colorRed   = "\033[31m"
+	colorReset = "\033[0m"
+)
+
+var ErrUnknownColorMode = errors.New("unknown color mode")
+
+// colorEnabled indicates if verification results are colored. It's set once
+// for the invocation by SetColorMode.
+var colorEnabled = false
+
+// SetColorMode sets whether verification results are colored. In the auto
+// mode, results are colored when standard output is a terminal and the
+// NO_COLOR environment variable isn't set.
+func SetColorMode(mode string) error {
+	switch mode {
+	case ColorAlways:
+		colorEnabled = true
+	case ColorNever:
+		colorEnabled = false
+	case ColorAuto:
+		colorEnabled = os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+	default:
+		return fmt.Errorf("%w '%s', must be one of '%s', '%s', or '%s'", ErrUnknownColorMode, mode, ColorAuto, ColorAlways, ColorNever)
+	}
+
+	return nil
+}
+
+// Badge returns a marker for the result of a verification or check, colored
+// if enabled. Both markers have the same width so that they can be used to
+// align output.
+func Badge(ok bool) string {
+	okBadge, failBadge := "["+i18n.T("ok")+"]", "["+i18n.T("fail")+"]"
+	width := max(utf8.RuneCountInString(okBadge), utf8.RuneCountInString(failBadge))
+
+	if ok {
+		return colorize(fmt.Sprintf("%-*s", width, okBadge), colorGreen)
+	}
+
+	return colorize(fmt.Sprintf("%-*s", width, failBadge), colorRed)
+}
+
+func colorize(text, color string) string {
+	if !colorEnabled {
+		return text
+	}
+
+	return color + text + colorReset
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
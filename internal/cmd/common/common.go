@@ -3,23 +3,57 @@
 package common
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/identity"
+	"github.com/gittuf/gittuf/internal/passphrase"
+	"github.com/gittuf/gittuf/internal/plugin"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpgagent"
+	"github.com/gittuf/gittuf/internal/signerverifier/kms"
+	"github.com/gittuf/gittuf/internal/signerverifier/sigstore"
+	"github.com/gittuf/gittuf/internal/signerverifier/sshagent"
 	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
 	"github.com/gittuf/gittuf/internal/tuf"
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
 )
 
 const (
-	GPGKeyPrefix = "gpg:"
-	FulcioPrefix = "fulcio:"
+	GPGKeyPrefix    = "gpg:"
+	FulcioPrefix    = "fulcio:"
+	PluginKeyPrefix = "plugin:"
+
+	// SMIMEPrefix identifies the email address S/MIME certificates are
+	// issued for, such as "smime:jane.doe@example.com". Signatures are
+	// verified using the trust store set with --smime-trust-store.
+	SMIMEPrefix = "smime:"
+
+	// SSHAgentKeyPrefix identifies keys held in the SSH agent, selected by
+	// their SHA256 fingerprint or public key, such as
+	// "ssh-agent:SHA256:<fingerprint>".
+	SSHAgentKeyPrefix = sshagent.KeyPrefix
+
+	// GPGAgentKeyPrefix identifies GPG keys held by gpg-agent, selected by
+	// their fingerprint, such as "gpg-agent:<fingerprint>". Their public
+	// keys are read from GnuPG's public keyring without invoking gpg.
+	GPGAgentKeyPrefix = gpgagent.KeyPrefix
+
+	// SigstoreSigningKey is the signing key used to sign without a key using
+	// the CI environment's OIDC identity, with a certificate issued by
+	// Sigstore's public Fulcio instance.
+	SigstoreSigningKey = "sigstore"
 )
 
 // PublicKeys is a custom type to represent a list of paths
@@ -41,8 +75,11 @@ func (p *PublicKeys) Type() string {
 	return "public-keys"
 }
 
-// LoadPublicKey returns a tuf.Key object for a PGP / Sigstore Fulcio / SSH
-// (on-disk) key for use in gittuf metadata.
+// LoadPublicKey returns a tuf.Key object for a PGP / gpg-agent / Sigstore
+// Fulcio / S/MIME / signer plugin / SSH agent / SSH (on-disk) key for use in
+// gittuf metadata. On-disk
+// keys may be PEM encoded or in the SSH authorized_keys format, which is also
+// used for SSH keys held in FIDO2 security keys.
 func LoadPublicKey(key string) (*tuf.Key, error) {
 	var keyObj *tuf.Key
 
@@ -60,8 +97,20 @@ func LoadPublicKey(key string) (*tuf.Key, error) {
 		if err != nil {
 			return nil, err
 		}
+	case strings.HasPrefix(key, GPGAgentKeyPrefix):
+		publicKey, err := gpgagent.ExportPublicKey(strings.TrimPrefix(key, GPGAgentKeyPrefix))
+		if err != nil {
+			return nil, err
+		}
+
+		keyObj, err = gpg.LoadGPGKeyFromBytes(publicKey)
+		if err != nil {
+			return nil, err
+		}
 	case strings.HasPrefix(key, FulcioPrefix):
-		keyID := strings.TrimPrefix(key, FulcioPrefix)
+		// Identities are normalized so that canonically equivalent
+		// identities result in the same key ID
+		keyID := identity.Normalize(strings.TrimPrefix(key, FulcioPrefix))
 		ks := strings.Split(keyID, "::")
 		if len(ks) != 2 {
 			return nil, fmt.Errorf("incorrect format for fulcio identity")
@@ -76,6 +125,43 @@ func LoadPublicKey(key string) (*tuf.Key, error) {
 				Issuer:   ks[1],
 			},
 		}
+	case strings.HasPrefix(key, SMIMEPrefix):
+		// Email addresses are compared case insensitively, so the key ID
+		// is lower case
+		email := identity.Normalize(strings.TrimSpace(strings.TrimPrefix(key, SMIMEPrefix)))
+		if !strings.Contains(email, "@") {
+			return nil, fmt.Errorf("incorrect format for S/MIME identity, expected an email address")
+		}
+
+		keyObj = &sslibsv.SSLibKey{
+			KeyID:   strings.ToLower(email),
+			KeyType: signerverifier.SMIMEKeyType,
+			Scheme:  signerverifier.SMIMEKeyScheme,
+			KeyVal: sslibsv.KeyVal{
+				Identity: email,
+			},
+		}
+	case strings.HasPrefix(key, PluginKeyPrefix):
+		signer, err := plugin.LoadSigner(context.Background(), strings.TrimPrefix(key, PluginKeyPrefix))
+		if err != nil {
+			return nil, err
+		}
+
+		keyObj = signer.PublicKey()
+	case strings.HasPrefix(key, SSHAgentKeyPrefix):
+		signer, err := sshagent.NewSigner(strings.TrimPrefix(key, SSHAgentKeyPrefix))
+		if err != nil {
+			return nil, err
+		}
+
+		keyObj = signer.PublicKey()
+	case kms.IsKMSKey(key):
+		signer, err := kms.NewSigner(context.Background(), key)
+		if err != nil {
+			return nil, err
+		}
+
+		keyObj = signer.PublicKey()
 	default:
 		kb, err := os.ReadFile(key)
 		if err != nil {
@@ -84,7 +170,24 @@ func LoadPublicKey(key string) (*tuf.Key, error) {
 
 		keyObj, err = tuf.LoadKeyFromBytes(kb)
 		if err != nil {
-			return nil, err
+			// Public keys in the SSH authorized_keys format, as created by
+			// ssh-keygen, are also supported
+			sshKey, _, _, _, sshErr := ssh.ParseAuthorizedKey(kb)
+			if sshErr != nil {
+				return nil, err
+			}
+			if signerverifier.IsSSHSecurityKeyAlgorithm(sshKey.Type()) {
+				return signerverifier.NewSSHSecurityKey(kb)
+			}
+			cryptoKey, ok := sshKey.(ssh.CryptoPublicKey)
+			if !ok {
+				return nil, err
+			}
+
+			keyObj, err = sslibsv.NewKey(cryptoKey.CryptoPublicKey())
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -93,16 +196,94 @@ func LoadPublicKey(key string) (*tuf.Key, error) {
 
 // LoadSigner loads a signer for the specified key bytes. The key must be
 // encoded either in a standard PEM format. For now, the custom securesystemslib
-// format is also supported.
+// format is also supported. Keys encrypted using a passphrase are decrypted,
+// obtaining the passphrase using the passphrase package.
 func LoadSigner(keyBytes []byte) (sslibdsse.SignerVerifier, error) {
 	signer, err := sslibsv.NewSignerVerifierFromPEM(keyBytes)
 	if err == nil {
 		return signer, nil
 	}
 
+	_, err = ssh.ParseRawPrivateKey(keyBytes)
+	var passphraseMissingErr *ssh.PassphraseMissingError
+	if errors.As(err, &passphraseMissingErr) {
+		return loadEncryptedSigner(keyBytes, passphraseMissingErr.PublicKey)
+	}
+
 	return signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes) //nolint:staticcheck
 }
 
+// loadEncryptedSigner decrypts the encrypted private key and loads a signer
+// for it. The public key is used to identify the key when prompting for its
+// passphrase, if the key's format includes it.
+func loadEncryptedSigner(keyBytes []byte, publicKey ssh.PublicKey) (sslibdsse.SignerVerifier, error) {
+	description := "private key"
+	if publicKey != nil {
+		description += " " + ssh.FingerprintSHA256(publicKey)
+	}
+
+	var signer sslibdsse.SignerVerifier
+	err := passphrase.Decrypt(description, func(keyPassphrase []byte) error {
+		privateKey, err := ssh.ParseRawPrivateKeyWithPassphrase(keyBytes, keyPassphrase)
+		if err != nil {
+			if errors.Is(err, x509.IncorrectPasswordError) {
+				return errors.Join(passphrase.ErrIncorrectPassphrase, err)
+			}
+			return err
+		}
+
+		// OpenSSH ED25519 keys are parsed as pointers
+		if key, isPointer := privateKey.(*ed25519.PrivateKey); isPointer {
+			privateKey = *key
+		}
+
+		// The decrypted key is loaded like an unencrypted PEM key
+		derBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return err
+		}
+		signer, err = sslibsv.NewSignerVerifierFromPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: derBytes}))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+// LoadSignerForKey loads a signer for the specified signing key, which is
+// either the path to a key on disk that's loaded using LoadSigner, the name of
+// a signer plugin prefixed with "plugin:", such as "plugin:kms", a key held
+// in the SSH agent prefixed with "ssh-agent:", such as
+// "ssh-agent:SHA256:<fingerprint>", the URI of a key held in a key
+// management service, such as "awskms:///alias/gittuf-root", or
+// SigstoreSigningKey.
+func LoadSignerForKey(key string) (sslibdsse.SignerVerifier, error) {
+	switch {
+	case key == SigstoreSigningKey:
+		instance, err := gitinterface.GetSigstoreInstance()
+		if err != nil {
+			return nil, err
+		}
+
+		return sigstore.NewAmbientSigner(context.Background(), instance.FulcioURL)
+	case strings.HasPrefix(key, PluginKeyPrefix):
+		return plugin.LoadSigner(context.Background(), strings.TrimPrefix(key, PluginKeyPrefix))
+	case strings.HasPrefix(key, SSHAgentKeyPrefix):
+		return sshagent.NewSigner(strings.TrimPrefix(key, SSHAgentKeyPrefix))
+	case kms.IsKMSKey(key):
+		return kms.NewSigner(context.Background(), key)
+	}
+
+	keyBytes, err := os.ReadFile(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadSigner(keyBytes)
+}
+
 // CheckIfSigningViableWithFlag checks if a signing key was specified via the
 // "signing-key" flag, and then calls CheckIfSigningViable
 func CheckIfSigningViableWithFlag(cmd *cobra.Command, _ []string) error {
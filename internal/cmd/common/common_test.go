@@ -3,12 +3,26 @@
 package common
 
 import (
+	"bytes"
 	"context"
+	"encoding/pem"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/config"
+	"github.com/gittuf/gittuf/internal/githubactions"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/i18n"
+	"github.com/gittuf/gittuf/internal/interactive"
+	"github.com/gittuf/gittuf/internal/passphrase"
+	"github.com/gittuf/gittuf/internal/signerverifier"
 	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestLoadSigner(t *testing.T) {
@@ -28,3 +42,504 @@ func TestLoadSigner(t *testing.T) {
 		assert.Nil(t, err)
 	}
 }
+
+func TestLoadEncryptedSigner(t *testing.T) {
+	for name, keyBytes := range map[string][]byte{"SSH ED25519 key": artifacts.SSHED25519Private, "SSH RSA key": artifacts.SSHRSAPrivate} {
+		privateKey, err := ssh.ParseRawPrivateKey(keyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, err := ssh.MarshalPrivateKeyWithPassphrase(privateKey, "", []byte("secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv(passphrase.EnvKey, "secret")
+		signer, err := LoadSigner(pem.EncodeToMemory(block))
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+
+		_, err = signer.Sign(context.Background(), nil)
+		assert.Nil(t, err)
+
+		t.Setenv(passphrase.EnvKey, "incorrect")
+		_, err = LoadSigner(pem.EncodeToMemory(block))
+		assert.ErrorIs(t, err, passphrase.ErrIncorrectPassphrase, name)
+	}
+}
+
+func TestLoadPublicKey(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	pemKeyPath := filepath.Join(tmpDir, "key.pem")
+	if err := os.WriteFile(pemKeyPath, artifacts.SSHED25519Public, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sshKeyPath := filepath.Join(tmpDir, "key.pub")
+	if err := os.WriteFile(sshKeyPath, artifacts.SSHED25519PublicSSH, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	pemKey, err := LoadPublicKey(pemKeyPath)
+	assert.Nil(t, err)
+
+	sshKey, err := LoadPublicKey(sshKeyPath)
+	assert.Nil(t, err)
+	assert.Equal(t, pemKey.KeyID, sshKey.KeyID)
+
+	key, err := LoadPublicKey(FulcioPrefix + "jane.doe@example.com::https://github.com/login/oauth")
+	assert.Nil(t, err)
+	assert.Equal(t, "jane.doe@example.com", key.KeyVal.Identity)
+
+	key, err = LoadPublicKey(SMIMEPrefix + "Jane.Doe@example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "jane.doe@example.com", key.KeyID)
+	assert.Equal(t, "Jane.Doe@example.com", key.KeyVal.Identity)
+
+	_, err = LoadPublicKey(SMIMEPrefix + "Jane Doe")
+	assert.NotNil(t, err)
+}
+
+func TestDetectGitSigningKey(t *testing.T) {
+	// Run outside a repository so that its config isn't used
+	currentDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(currentDir) //nolint:errcheck
+
+	setGitConfig := func(t *testing.T, config string) {
+		t.Helper()
+
+		configPath := filepath.Join(t.TempDir(), "gitconfig")
+		if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+		t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	}
+
+	t.Run("GPG key", func(t *testing.T) {
+		setGitConfig(t, "[user]\n\temail = jane.doe@example.com\n\tsigningKey = ABCDEF\n")
+
+		signingKey, err := DetectGitSigningKey()
+		assert.Nil(t, err)
+		assert.Equal(t, &GitSigningKey{PublicKey: GPGKeyPrefix + "ABCDEF"}, signingKey)
+	})
+
+	t.Run("GPG key for email", func(t *testing.T) {
+		setGitConfig(t, "[user]\n\temail = jane.doe@example.com\n")
+
+		signingKey, err := DetectGitSigningKey()
+		assert.Nil(t, err)
+		assert.Equal(t, &GitSigningKey{PublicKey: GPGKeyPrefix + "jane.doe@example.com"}, signingKey)
+	})
+
+	t.Run("SSH key", func(t *testing.T) {
+		keyDir := t.TempDir()
+		keyPath := filepath.Join(keyDir, "id_rsa")
+		if err := os.WriteFile(keyPath, artifacts.SSHRSAPrivate, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(keyPath+".pub", artifacts.SSHRSAPublicSSH, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		expectedSigningKey := &GitSigningKey{PublicKey: keyPath + ".pub", PrivateKeyPath: keyPath}
+
+		setGitConfig(t, fmt.Sprintf("[gpg]\n\tformat = ssh\n[user]\n\tsigningKey = %s\n", keyPath))
+		signingKey, err := DetectGitSigningKey()
+		assert.Nil(t, err)
+		assert.Equal(t, expectedSigningKey, signingKey)
+
+		setGitConfig(t, fmt.Sprintf("[gpg]\n\tformat = ssh\n[user]\n\tsigningKey = %s.pub\n", keyPath))
+		signingKey, err = DetectGitSigningKey()
+		assert.Nil(t, err)
+		assert.Equal(t, expectedSigningKey, signingKey)
+
+		_, err = LoadPublicKey(signingKey.PublicKey)
+		assert.Nil(t, err)
+	})
+
+	t.Run("SSH key without public key", func(t *testing.T) {
+		keyPath := filepath.Join(t.TempDir(), "id_rsa")
+		if err := os.WriteFile(keyPath, artifacts.SSHRSAPrivate, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		setGitConfig(t, fmt.Sprintf("[gpg]\n\tformat = ssh\n[user]\n\tsigningKey = %s\n", keyPath))
+		_, err := DetectGitSigningKey()
+		assert.ErrorIs(t, err, ErrGitSigningKeyNotDetected)
+	})
+
+	t.Run("gitsign", func(t *testing.T) {
+		setGitConfig(t, "[gpg]\n\tformat = x509\n[gpg \"x509\"]\n\tprogram = gitsign\n[user]\n\temail = jane.doe@example.com\n")
+
+		signingKey, err := DetectGitSigningKey()
+		assert.Nil(t, err)
+		assert.Equal(t, &GitSigningKey{PublicKey: FulcioPrefix + "jane.doe@example.com::" + DefaultFulcioIssuer}, signingKey)
+	})
+
+	t.Run("X.509 key", func(t *testing.T) {
+		setGitConfig(t, "[gpg]\n\tformat = x509\n[user]\n\temail = jane.doe@example.com\n")
+
+		_, err := DetectGitSigningKey()
+		assert.ErrorIs(t, err, ErrGitSigningKeyNotDetected)
+	})
+}
+
+func TestCheckFormat(t *testing.T) {
+	assert.Nil(t, CheckFormat(FormatText))
+	assert.Nil(t, CheckFormat(FormatJSON))
+	assert.ErrorIs(t, CheckFormat("yaml"), ErrUnknownFormat)
+}
+
+func TestPrompter(t *testing.T) {
+	t.Run("answers and defaults", func(t *testing.T) {
+		in := strings.NewReader("protect-main\n\nmain, release/* ,\n\n5\n2\nyes\n\n")
+		out := &bytes.Buffer{}
+		prompter := NewPrompter(in, out)
+
+		answer, err := prompter.Ask("Rule name", "")
+		assert.Nil(t, err)
+		assert.Equal(t, "protect-main", answer)
+
+		answer, err = prompter.Ask("Policy name", "targets")
+		assert.Nil(t, err)
+		assert.Equal(t, "targets", answer)
+		assert.Contains(t, out.String(), "Policy name [targets]: ")
+
+		items, err := prompter.AskList("Branches")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"main", "release/*"}, items)
+
+		items, err = prompter.AskList("Tags")
+		assert.Nil(t, err)
+		assert.Empty(t, items)
+
+		value, err := prompter.AskInt("Threshold", 1, 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, value)
+		assert.Contains(t, out.String(), "Please enter a number between 1 and 2.")
+
+		confirmed, err := prompter.Confirm("Continue?", false)
+		assert.Nil(t, err)
+		assert.True(t, confirmed)
+
+		confirmed, err = prompter.Confirm("Apply?", true)
+		assert.Nil(t, err)
+		assert.True(t, confirmed)
+	})
+
+	t.Run("required answer", func(t *testing.T) {
+		prompter := NewPrompter(strings.NewReader("\n\nprotect-main"), &bytes.Buffer{})
+
+		answer, err := prompter.AskRequired("Rule name")
+		assert.Nil(t, err)
+		assert.Equal(t, "protect-main", answer)
+	})
+
+	t.Run("no input", func(t *testing.T) {
+		prompter := NewPrompter(strings.NewReader(""), &bytes.Buffer{})
+
+		_, err := prompter.AskRequired("Rule name")
+		assert.ErrorIs(t, err, ErrNoInput)
+
+		_, err = prompter.Confirm("Continue?", true)
+		assert.ErrorIs(t, err, ErrNoInput)
+	})
+
+	t.Run("non-interactive mode", func(t *testing.T) {
+		t.Setenv(interactive.NonInteractiveModeKey, "1")
+		prompter := NewPrompter(strings.NewReader("protect-main\ny\n"), &bytes.Buffer{})
+
+		_, err := prompter.AskRequired("Rule name")
+		assert.ErrorIs(t, err, interactive.ErrInteractionRequired)
+
+		_, err = prompter.Confirm("Continue?", true)
+		assert.ErrorIs(t, err, interactive.ErrInteractionRequired)
+	})
+}
+
+func TestFilterCompletions(t *testing.T) {
+	candidates := []string{"protect-main", "protect-release", "targets"}
+
+	assert.Equal(t, candidates, filterCompletions(candidates, ""))
+	assert.Equal(t, []string{"protect-main", "protect-release"}, filterCompletions(candidates, "protect-"))
+	assert.Empty(t, filterCompletions(candidates, "unknown"))
+}
+
+func TestCompleteArgs(t *testing.T) {
+	completeFirst := func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"first"}, cobra.ShellCompDirectiveNoFileComp
+	}
+	completeSecond := func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"second"}, cobra.ShellCompDirectiveNoFileComp
+	}
+	complete := CompleteArgs(completeFirst, completeSecond)
+
+	completions, _ := complete(nil, []string{}, "")
+	assert.Equal(t, []string{"first"}, completions)
+
+	completions, _ = complete(nil, []string{"a"}, "")
+	assert.Equal(t, []string{"second"}, completions)
+
+	completions, directive := complete(nil, []string{"a", "b"}, "")
+	assert.Empty(t, completions)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
+
+func TestKeyLabels(t *testing.T) {
+	labels := KeyLabels{"abc123": "Jane Doe <jane.doe@example.com>"}
+
+	assert.Equal(t, "Jane Doe <jane.doe@example.com> (abc123)", labels.Describe("abc123"))
+	assert.Equal(t, "def456", labels.Describe("def456"))
+	assert.Equal(t, []string{"Jane Doe <jane.doe@example.com> (abc123)", "def456"}, labels.DescribeAll([]string{"abc123", "def456"}))
+	assert.Equal(t, "good signature from key 'gpg:Jane Doe <jane.doe@example.com> (abc123)'", labels.Annotate("good signature from key 'gpg:abc123'"))
+	assert.Equal(t, "no signature found", labels.Annotate("no signature found"))
+
+	assert.Equal(t, "Jane Doe <jane.doe@example.com>", labels.Name("abc123"))
+	assert.Equal(t, "def456", labels.Name("def456"))
+}
+
+func TestDescribePrincipals(t *testing.T) {
+	labels := KeyLabels{"a": "Alice", "b": "Bob", "c": "Carol"}
+
+	tests := map[string]struct {
+		keyIDs    []string
+		threshold int
+		expected  string
+	}{
+		"no keys":             {keyIDs: []string{}, threshold: 1, expected: "no one"},
+		"one key":             {keyIDs: []string{"a"}, threshold: 1, expected: "Alice"},
+		"any of two keys":     {keyIDs: []string{"a", "b"}, threshold: 1, expected: "Alice or Bob"},
+		"all of two keys":     {keyIDs: []string{"a", "b"}, threshold: 2, expected: "Alice and Bob"},
+		"any of three keys":   {keyIDs: []string{"a", "b", "c"}, threshold: 1, expected: "Alice, Bob, or Carol"},
+		"two of three keys":   {keyIDs: []string{"a", "b", "c"}, threshold: 2, expected: "Alice, Bob, or Carol (2 of 3)"},
+		"all of three keys":   {keyIDs: []string{"a", "b", "c"}, threshold: 3, expected: "Alice, Bob, and Carol"},
+		"key without a label": {keyIDs: []string{"a", "d"}, threshold: 1, expected: "Alice or d"},
+	}
+
+	for name, test := range tests {
+		assert.Equal(t, test.expected, labels.DescribePrincipals(test.keyIDs, test.threshold), fmt.Sprintf("unexpected description in test '%s'", name))
+	}
+}
+
+func TestBadge(t *testing.T) {
+	defer SetColorMode(ColorNever) //nolint:errcheck
+	defer i18n.SetLocale("")
+
+	i18n.SetLocale(i18n.DefaultLocale)
+	assert.Nil(t, SetColorMode(ColorNever))
+	assert.Equal(t, "[ok]  ", Badge(true))
+	assert.Equal(t, "[fail]", Badge(false))
+
+	i18n.SetLocale("de")
+	assert.Equal(t, "[ok]    ", Badge(true))
+	assert.Equal(t, "[Fehler]", Badge(false))
+	i18n.SetLocale(i18n.DefaultLocale)
+
+	assert.Nil(t, SetColorMode(ColorAlways))
+	assert.Equal(t, "\033[32m[ok]  \033[0m", Badge(true))
+	assert.Equal(t, "\033[31m[fail]\033[0m", Badge(false))
+
+	assert.ErrorIs(t, SetColorMode("sometimes"), ErrUnknownColorMode)
+}
+
+func TestReportStatusesToActions(t *testing.T) {
+	assert.Nil(t, ReportStatusesToActions(nil, "verify-commit", []string{"HEAD"}, map[string]string{"HEAD": "no signature found"}))
+
+	tmpDir := t.TempDir()
+	summaryPath := filepath.Join(tmpDir, "summary.md")
+	outputPath := filepath.Join(tmpDir, "output")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryPath)
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+
+	commands := &bytes.Buffer{}
+	status := map[string]string{
+		"v1.0.0": "good signature for RSL entry and tag",
+		"v1.0.1": "no signature found",
+	}
+	err := ReportStatusesToActions(githubactions.Detect(commands), "verify-tag", []string{"v1.0.0", "v1.0.1"}, status)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "::error title=gittuf verification failed for v1.0.1::no signature found\n", commands.String())
+
+	summaryBytes, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "### gittuf verify-tag\n\n| Object | Result | Status |\n| --- | --- | --- |\n| `v1.0.0` | :white_check_mark: Verified | good signature for RSL entry and tag |\n| `v1.0.1` | :x: Failed | no signature found |\n", string(summaryBytes))
+
+	outputBytes, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, strings.HasPrefix(string(outputBytes), "verified<<"))
+	assert.Contains(t, string(outputBytes), "\nfalse\n")
+}
+
+func TestApplyConfig(t *testing.T) {
+	newCommand := func() (*cobra.Command, *string, *string, *string) {
+		var format, rekorURL, archivistaURL string
+		cmd := &cobra.Command{}
+		AddFormatFlag(cmd, &format)
+		cmd.Flags().StringVar(&rekorURL, "rekor-url", "", "")
+		cmd.Flags().StringVar(&archivistaURL, "archivista-url", "", "")
+		return cmd, &format, &rekorURL, &archivistaURL
+	}
+
+	t.Run("config values used as defaults", func(t *testing.T) {
+		cmd, format, rekorURL, archivistaURL := newCommand()
+		if err := cmd.Flags().Parse([]string{"--archivista-url", "https://archivista.example.com"}); err != nil {
+			t.Fatal(err)
+		}
+
+		err := ApplyConfig(cmd, &config.Config{Format: FormatJSON, RekorURL: "https://rekor.example.com", ArchivistaURL: "https://other.example.com"}, "")
+		assert.Nil(t, err)
+		assert.Equal(t, FormatJSON, *format)
+		assert.Equal(t, "https://rekor.example.com", *rekorURL)
+		assert.Equal(t, "https://archivista.example.com", *archivistaURL)
+	})
+
+	t.Run("offline mode", func(t *testing.T) {
+		cmd, format, rekorURL, archivistaURL := newCommand()
+		if err := cmd.Flags().Parse([]string{}); err != nil {
+			t.Fatal(err)
+		}
+
+		err := ApplyConfig(cmd, &config.Config{Format: FormatJSON, RekorURL: "https://rekor.example.com", ArchivistaURL: "https://archivista.example.com", Offline: true}, "")
+		assert.Nil(t, err)
+		assert.Equal(t, FormatJSON, *format)
+		assert.Empty(t, *rekorURL)
+		assert.Empty(t, *archivistaURL)
+	})
+
+	t.Run("offline Sigstore verification", func(t *testing.T) {
+		var offline bool
+		var trustedRootPath string
+		cmd := &cobra.Command{}
+		cmd.Flags().BoolVar(&offline, "offline", false, "")
+		cmd.Flags().StringVar(&trustedRootPath, "sigstore-trusted-root", "", "")
+		if err := cmd.Flags().Parse([]string{}); err != nil {
+			t.Fatal(err)
+		}
+
+		err := ApplyConfig(cmd, &config.Config{Offline: true, SigstoreTrustedRoot: "/etc/sigstore/trusted_root.json"}, "")
+		assert.Nil(t, err)
+		assert.True(t, offline)
+		assert.Equal(t, "/etc/sigstore/trusted_root.json", trustedRootPath)
+	})
+
+	t.Run("notifications", func(t *testing.T) {
+		var notifyWebhook, notifyCommand string
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVar(&notifyWebhook, "notify-webhook", "", "")
+		cmd.Flags().StringVar(&notifyCommand, "notify-command", "", "")
+
+		c := &config.Config{NotifyWebhookURL: "https://hooks.example.com", NotifyCommand: "alert"}
+		err := ApplyConfig(cmd, c, "")
+		assert.Nil(t, err)
+		assert.Equal(t, "https://hooks.example.com", notifyWebhook)
+		assert.Equal(t, "alert", notifyCommand)
+
+		notifyWebhook, notifyCommand = "", ""
+		cmd = &cobra.Command{}
+		cmd.Flags().StringVar(&notifyWebhook, "notify-webhook", "", "")
+		cmd.Flags().StringVar(&notifyCommand, "notify-command", "", "")
+
+		c.Offline = true
+		err = ApplyConfig(cmd, c, "")
+		assert.Nil(t, err)
+		assert.Empty(t, notifyWebhook)
+		assert.Equal(t, "alert", notifyCommand)
+	})
+
+	t.Run("unknown signer", func(t *testing.T) {
+		cmd, _, _, _ := newCommand()
+
+		err := ApplyConfig(cmd, &config.Config{Signer: "abcdef"}, "")
+		assert.ErrorIs(t, err, gitinterface.ErrUnknownSigningMethod)
+	})
+
+	t.Run("signing profile", func(t *testing.T) {
+		c := &config.Config{
+			SigningProfile: "personal",
+			SigningProfiles: map[string]*config.SigningProfile{
+				"personal": {SigningKey: "personal.pem"},
+				"work":     {SigningKey: "work.pem"},
+			},
+		}
+
+		newSigningCommand := func() (*cobra.Command, *string) {
+			var signingKey string
+			cmd := &cobra.Command{}
+			cmd.Flags().StringVar(&signingKey, "signing-key", "", "")
+			cmd.MarkFlagRequired("signing-key") //nolint:errcheck
+			if err := cmd.Flags().Parse([]string{}); err != nil {
+				t.Fatal(err)
+			}
+			return cmd, &signingKey
+		}
+
+		// The default profile is used
+		cmd, signingKey := newSigningCommand()
+		err := ApplyConfig(cmd, c, "")
+		assert.Nil(t, err)
+		assert.Equal(t, "personal.pem", *signingKey)
+		assert.Nil(t, cmd.ValidateRequiredFlags())
+
+		// The selected profile is used
+		cmd, signingKey = newSigningCommand()
+		err = ApplyConfig(cmd, c, "work")
+		assert.Nil(t, err)
+		assert.Equal(t, "work.pem", *signingKey)
+
+		cmd, _ = newSigningCommand()
+		err = ApplyConfig(cmd, c, "ci")
+		assert.ErrorIs(t, err, config.ErrUnknownSigningProfile)
+	})
+}
+
+func TestApplySigningBackend(t *testing.T) {
+	newCommand := func(args ...string) (*cobra.Command, *string, *string) {
+		var signingKey, rekorURL string
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVarP(&signingKey, "signing-key", "k", "", "")
+		cmd.Flags().StringVar(&rekorURL, "rekor-url", "", "")
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return cmd, &signingKey, &rekorURL
+	}
+
+	t.Run("Sigstore backend", func(t *testing.T) {
+		cmd, signingKey, rekorURL := newCommand()
+
+		err := ApplySigningBackend(cmd, gitinterface.SigningBackendSigstore)
+		assert.Nil(t, err)
+		assert.Equal(t, SigstoreSigningKey, *signingKey)
+		assert.Equal(t, signerverifier.RekorServer, *rekorURL)
+	})
+
+	t.Run("flags set explicitly", func(t *testing.T) {
+		cmd, signingKey, rekorURL := newCommand("-k", "key.pem", "--rekor-url", "https://rekor.example.com")
+
+		err := ApplySigningBackend(cmd, gitinterface.SigningBackendSigstore)
+		assert.Nil(t, err)
+		assert.Equal(t, "key.pem", *signingKey)
+		assert.Equal(t, "https://rekor.example.com", *rekorURL)
+	})
+
+	t.Run("Git backend", func(t *testing.T) {
+		cmd, signingKey, rekorURL := newCommand()
+
+		err := ApplySigningBackend(cmd, gitinterface.SigningBackendGit)
+		assert.Nil(t, err)
+		assert.Empty(t, *signingKey)
+		assert.Empty(t, *rekorURL)
+	})
+}
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+// Completions are computed from the staged policy as that's the state that
+// policy and trust commands modify.
+const completionPolicyRef = "policy-staging"
+
+// CompletionFunc is the signature cobra expects for dynamic completions of
+// arguments and flag values.
+type CompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// CompleteRefs completes the names of local branches and tags. Short names are
+// suggested unless the user starts typing a fully qualified ref.
+func CompleteRefs(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	refs, err := repo.ListLocalRefs()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if !strings.HasPrefix(toComplete, gitinterface.RefPrefix) {
+		for index, ref := range refs {
+			ref = strings.TrimPrefix(ref, gitinterface.BranchRefPrefix)
+			refs[index] = strings.TrimPrefix(ref, gitinterface.TagRefPrefix)
+		}
+	}
+
+	return filterCompletions(refs, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteRemotes completes the names of the repository's remotes.
+func CompleteRemotes(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	remotes, err := repo.ListRemotes()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return filterCompletions(remotes, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteRuleNames completes the names of the rules in the policy.
+func CompleteRuleNames(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ruleNames, err := loadRuleNames(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return filterCompletions(ruleNames, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompletePolicyNames completes the names of the policy files that may exist,
+// i.e. the top level policy file and one for each rule.
+func CompletePolicyNames(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ruleNames, err := loadRuleNames(cmd.Context())
+	if err != nil {
+		ruleNames = []string{}
+	}
+
+	policyNames := append([]string{policy.TargetsRoleName}, ruleNames...)
+	return filterCompletions(policyNames, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteRootKeyIDs completes the IDs of the keys trusted for the root of
+// trust.
+func CompleteRootKeyIDs(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeRoleKeyIDs(cmd.Context(), policy.RootRoleName, toComplete)
+}
+
+// CompletePolicyKeyIDs completes the IDs of the keys trusted for the top level
+// policy file.
+func CompletePolicyKeyIDs(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeRoleKeyIDs(cmd.Context(), policy.TargetsRoleName, toComplete)
+}
+
+// CompletePolicyRefs completes the policy refs that can be inspected.
+func CompletePolicyRefs(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return filterCompletions([]string{"policy", "policy-staging"}, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteRemoteAndRefs completes a remote name as the first positional
+// argument and ref names as the rest.
+func CompleteRemoteAndRefs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return CompleteRemotes(cmd, args, toComplete)
+	}
+
+	return CompleteRefs(cmd, args, toComplete)
+}
+
+// CompleteArgs returns a completion function for positional arguments that
+// uses the completion function at the argument's position. Arguments beyond
+// the specified functions are not completed.
+func CompleteArgs(funcs ...CompletionFunc) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= len(funcs) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return funcs[len(args)](cmd, args, toComplete)
+	}
+}
+
+func completeRoleKeyIDs(ctx context.Context, roleName, toComplete string) ([]string, cobra.ShellCompDirective) {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	roles, err := repo.ListPolicyRoles(ctx, completionPolicyRef)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	for _, role := range roles {
+		if role.Name == roleName {
+			return filterCompletions(role.KeyIDs, toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func loadRuleNames(ctx context.Context) ([]string, error) {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := repo.ListRules(ctx, completionPolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleNames := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ruleNames = append(ruleNames, rule.Delegation.Name)
+	}
+
+	return ruleNames, nil
+}
+
+// filterCompletions returns the candidates that start with the text being
+// completed.
+func filterCompletions(candidates []string, toComplete string) []string {
+	completions := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, toComplete) {
+			completions = append(completions, candidate)
+		}
+	}
+
+	return completions
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"github.com/gittuf/gittuf/internal/config"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/spf13/cobra"
+)
+
+// LoadConfig loads the user's config and, if invoked in a repository, the
+// repository's config. The repository's config is shared by all of its
+// worktrees.
+func LoadConfig() (*config.Config, error) {
+	gitDir := ""
+	if repo, err := gitinterface.LoadRepository(); err == nil {
+		gitDir = repo.GetGitCommonDir()
+	}
+
+	return config.Load(gitDir)
+}
+
+// ApplyConfig uses the config's values as the defaults of the corresponding
+// flags of the command. Flags set explicitly on the command line are not
+// changed. In offline mode, the config's Rekor, Archivista, and timestamping
+// instances are not used, neither is the webhook notified of verification failures, and
+// Sigstore signatures are verified offline. The
+// values of the specified signing profile, or of the config's default signing
+// profile if none is specified, take precedence over the config's values.
+func ApplyConfig(cmd *cobra.Command, c *config.Config, signingProfileName string) error {
+	signingProfile, err := c.GetSigningProfile(signingProfileName)
+	if err != nil {
+		return err
+	}
+	if signingProfile == nil {
+		signingProfile = &config.SigningProfile{}
+	}
+
+	defaults := map[string]string{
+		"fulcio-url":            firstNonEmpty(signingProfile.FulcioURL, c.FulcioURL),
+		"sigstore-fulcio-url":   firstNonEmpty(signingProfile.FulcioURL, c.FulcioURL),
+		"signing-key":           signingProfile.SigningKey,
+		"format":                c.Format,
+		"color":                 c.Color,
+		"sigstore-trusted-root": c.SigstoreTrustedRoot,
+		"smime-trust-store":     c.SMIMETrustStore,
+		"notify-command":        c.NotifyCommand,
+	}
+	if c.Offline {
+		defaults["offline"] = "true"
+	} else {
+		defaults["rekor-url"] = c.RekorURL
+		defaults["archivista-url"] = c.ArchivistaURL
+		defaults["notify-webhook"] = c.NotifyWebhookURL
+		defaults["timestamp-authority"] = c.TimestampAuthorityURL
+		defaults["timestamp-rekor-url"] = c.TimestampRekorURL
+	}
+
+	for name, value := range defaults {
+		if value == "" {
+			continue
+		}
+
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		// Setting the flag marks it as changed, so that flags that are
+		// required are satisfied by the config
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return err
+		}
+	}
+
+	if signer := firstNonEmpty(signingProfile.Signer, c.Signer); signer != "" {
+		if err := gitinterface.SetSigningFormat(signer); err != nil {
+			return err
+		}
+	}
+	if signingProfile.GitSigningKey != "" {
+		gitinterface.SetSigningKey(signingProfile.GitSigningKey)
+	}
+	if signingProfile.SigningProgram != "" {
+		gitinterface.SetSigningProgram(signingProfile.SigningProgram)
+	}
+
+	return nil
+}
+
+// ApplySigningBackend uses the defaults of the signing backend for the
+// corresponding flags of the command. For the Sigstore signing backend,
+// attestations are signed using the CI environment's OIDC identity instead of a
+// signing key and are logged to the Rekor instance of the configured Sigstore
+// instance, unless the flags are set explicitly or using the config.
+func ApplySigningBackend(cmd *cobra.Command, backend string) error {
+	if backend != gitinterface.SigningBackendSigstore {
+		return nil
+	}
+
+	instance, err := gitinterface.GetSigstoreInstance()
+	if err != nil {
+		return err
+	}
+
+	defaults := map[string]string{
+		"signing-key": SigstoreSigningKey,
+		"rekor-url":   instance.RekorURL,
+	}
+	for name, value := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+var ErrUnknownFormat = errors.New("unknown output format")
+
+// AddFormatFlag adds the "format" flag to a command that reports information,
+// allowing users to select between human-readable text and JSON output.
+func AddFormatFlag(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVar(
+		format,
+		"format",
+		FormatText,
+		fmt.Sprintf("output format, one of '%s' or '%s'", FormatText, FormatJSON),
+	)
+}
+
+// CheckFormat returns an error if the specified output format is unknown.
+func CheckFormat(format string) error {
+	switch format {
+	case FormatText, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("%w '%s', must be one of '%s' or '%s'", ErrUnknownFormat, format, FormatText, FormatJSON)
+	}
+}
+
+// PrintJSON writes the JSON encoding of v to standard output.
+func PrintJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/repository"
+)
+
+// logger logs the messages of the cmd subsystem.
+var logger = logging.For("cmd")
+
+// KeyLabels maps key IDs to human readable labels, such as the name and email
+// of a GPG key's owner, so that output identifies people rather than
+// fingerprints.
+type KeyLabels map[string]string
+
+// LoadKeyLabels returns the labels for the keys in the repository's applied
+// and staged policies. As labels only decorate output, failing to load them
+// doesn't fail the command, and no labels are returned instead.
+func LoadKeyLabels(ctx context.Context, repo *repository.Repository) KeyLabels {
+	labels, err := repo.KeyLabels(ctx)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Unable to load key labels: %s", err))
+		return KeyLabels{}
+	}
+
+	return labels
+}
+
+// Describe returns the key's label followed by its ID, or only the ID if the
+// key's label is unknown.
+func (l KeyLabels) Describe(keyID string) string {
+	if label, has := l[keyID]; has {
+		return fmt.Sprintf("%s (%s)", label, keyID)
+	}
+
+	return keyID
+}
+
+// DescribeAll returns the descriptions of the specified keys.
+func (l KeyLabels) DescribeAll(keyIDs []string) []string {
+	descriptions := make([]string, 0, len(keyIDs))
+	for _, keyID := range keyIDs {
+		descriptions = append(descriptions, l.Describe(keyID))
+	}
+
+	return descriptions
+}
+
+// Annotate replaces the key IDs that occur in text with their descriptions.
+// This is used for messages that embed key IDs.
+func (l KeyLabels) Annotate(text string) string {
+	replacements := []string{}
+	for keyID := range l {
+		if strings.Contains(text, keyID) {
+			replacements = append(replacements, keyID, l.Describe(keyID))
+		}
+	}
+	if len(replacements) == 0 {
+		return text
+	}
+
+	return strings.NewReplacer(replacements...).Replace(text)
+}
+
+// Name returns the key's label, or its ID if the key's label is unknown.
+func (l KeyLabels) Name(keyID string) string {
+	if label, has := l[keyID]; has {
+		return label
+	}
+
+	return keyID
+}
+
+// DescribePrincipals returns a sentence fragment naming the keys and how many
+// of them must sign, such as "Alice or Bob" for a threshold of one, "Alice and
+// Bob" when all of them must sign, or "Alice, Bob, or Carol (2 of 3)".
+func (l KeyLabels) DescribePrincipals(keyIDs []string, threshold int) string {
+	names := make([]string, 0, len(keyIDs))
+	for _, keyID := range keyIDs {
+		names = append(names, l.Name(keyID))
+	}
+
+	switch {
+	case len(names) == 0:
+		return "no one"
+	case len(names) == 1:
+		return names[0]
+	case threshold >= len(names):
+		return JoinList(names, "and")
+	case threshold <= 1:
+		return JoinList(names, "or")
+	default:
+		return fmt.Sprintf("%s (%d of %d)", JoinList(names, "or"), threshold, len(names))
+	}
+}
+
+// JoinList joins the items into a list using the conjunction, such as "A, B,
+// or C".
+func JoinList(items []string, conjunction string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return fmt.Sprintf("%s %s %s", items[0], conjunction, items[1])
+	}
+
+	return fmt.Sprintf("%s, %s %s", strings.Join(items[:len(items)-1], ", "), conjunction, items[len(items)-1])
+}
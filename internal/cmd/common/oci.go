@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/oci"
+)
+
+const (
+	// OCIUsernameKey and OCIPasswordKey are the environment variables that
+	// contain the credentials used to authenticate to OCI registries, so that
+	// they aren't exposed in the command line.
+	OCIUsernameKey = "GITTUF_OCI_USERNAME"
+	OCIPasswordKey = "GITTUF_OCI_PASSWORD" //nolint:gosec
+)
+
+// NewOCIClient returns a client for OCI registries that uses the credentials
+// in the environment, if any.
+func NewOCIClient() *oci.Client {
+	return oci.NewClient(os.Getenv(OCIUsernameKey), os.Getenv(OCIPasswordKey))
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/interactive"
+)
+
+var ErrNoInput = errors.New("no input provided")
+
+// Prompter asks the user questions on the command line, reading one line of
+// input per answer.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewPrompter returns a Prompter that writes questions to out and reads
+// answers from in.
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// Ask returns the user's answer to the question. If the user doesn't enter
+// anything, defaultValue is returned. In non-interactive mode, the question
+// isn't asked and an error is returned instead.
+func (p *Prompter) Ask(question, defaultValue string) (string, error) {
+	if interactive.InNonInteractiveMode() {
+		return "", fmt.Errorf("%w: %s", interactive.ErrInteractionRequired, question)
+	}
+
+	if defaultValue != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	answer, err := p.readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+// AskRequired is similar to Ask but repeats the question until the user
+// enters an answer.
+func (p *Prompter) AskRequired(question string) (string, error) {
+	for {
+		answer, err := p.Ask(question, "")
+		if err != nil {
+			return "", err
+		}
+		if answer != "" {
+			return answer, nil
+		}
+	}
+}
+
+// AskList returns the comma separated items entered by the user in response
+// to the question.
+func (p *Prompter) AskList(question string) ([]string, error) {
+	answer, err := p.Ask(question, "")
+	if err != nil {
+		return nil, err
+	}
+
+	items := []string{}
+	for _, item := range strings.Split(answer, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// AskInt returns the number entered by the user in response to the question.
+// The question is repeated until the answer is a number between minValue and
+// maxValue, inclusive.
+func (p *Prompter) AskInt(question string, defaultValue, minValue, maxValue int) (int, error) {
+	for {
+		answer, err := p.Ask(question, strconv.Itoa(defaultValue))
+		if err != nil {
+			return 0, err
+		}
+
+		value, err := strconv.Atoi(answer)
+		if err == nil && value >= minValue && value <= maxValue {
+			return value, nil
+		}
+		fmt.Fprintf(p.out, "Please enter a number between %d and %d.\n", minValue, maxValue)
+	}
+}
+
+// Confirm returns the user's answer to a yes or no question.
+func (p *Prompter) Confirm(question string, defaultValue bool) (bool, error) {
+	if interactive.InNonInteractiveMode() {
+		return false, fmt.Errorf("%w: %s", interactive.ErrInteractionRequired, question)
+	}
+
+	options := "y/N"
+	if defaultValue {
+		options = "Y/n"
+	}
+
+	for {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, options)
+
+		answer, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return defaultValue, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// IsInteractive returns true if in is a terminal, which means the user can
+// be asked questions, and gittuf isn't in non-interactive mode.
+func IsInteractive(in io.Reader) bool {
+	if interactive.InNonInteractiveMode() {
+		return false
+	}
+
+	file, ok := in.(*os.File)
+	return ok && isTerminal(file)
+}
+
+// readLine returns the next line of input without surrounding whitespace. A
+// final line without a trailing newline is accepted, but running out of input
+// entirely is an error so that questions aren't repeated forever.
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		if line == "" {
+			return "", ErrNoInput
+		}
+	}
+
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/report"
+	"github.com/spf13/cobra"
+)
+
+const (
+	ReportFormatJSON  = "json"
+	ReportFormatSARIF = "sarif"
+)
+
+var ErrUnknownReportFormat = errors.New("unknown report format")
+
+// AddReportFlags adds the "report" and "report-format" flags to a verification
+// command, allowing users to write a report of the signatures checked to a
+// file for automation such as code scanning dashboards to consume.
+func AddReportFlags(cmd *cobra.Command, file, format *string) {
+	cmd.Flags().StringVar(
+		file,
+		"report",
+		"",
+		"write a report of the signatures checked, including their key IDs, formats, and the rules they matched, to the specified file",
+	)
+
+	cmd.Flags().StringVar(
+		format,
+		"report-format",
+		ReportFormatJSON,
+		fmt.Sprintf("format of the report, one of '%s' or '%s'", ReportFormatJSON, ReportFormatSARIF),
+	)
+}
+
+// CheckReportFormat returns an error if the specified report format is
+// unknown.
+func CheckReportFormat(format string) error {
+	switch format {
+	case ReportFormatJSON, ReportFormatSARIF:
+		return nil
+	default:
+		return fmt.Errorf("%w '%s', must be one of '%s' or '%s'", ErrUnknownReportFormat, format, ReportFormatJSON, ReportFormatSARIF)
+	}
+}
+
+// WriteReport writes the report to the specified file in the specified
+// format. Nothing is written if the report is nil.
+func WriteReport(file, format string, r *report.Report) error {
+	if r == nil {
+		return nil
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if format == ReportFormatSARIF {
+		err = r.WriteSARIF(f)
+	} else {
+		err = r.WriteJSON(f)
+	}
+
+	return errors.Join(err, f.Close())
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+)
+
+// DefaultFulcioIssuer is the OIDC issuer assumed for Sigstore identities
+// detected from the Git config, as the issuer of the certificates gitsign
+// obtains depends on the account the user logs in with.
+const DefaultFulcioIssuer = "https://github.com/login/oauth"
+
+var ErrGitSigningKeyNotDetected = errors.New("unable to identify the public key used by the Git signing configuration")
+
+// GitSigningKey describes the key Git is configured to sign with.
+type GitSigningKey struct {
+	// PublicKey is the public key in the format accepted by LoadPublicKey.
+	PublicKey string
+
+	// PrivateKeyPath is the path of the private key if Git is configured to
+	// sign using an SSH key on disk. It is empty otherwise.
+	PrivateKeyPath string
+}
+
+// DetectGitSigningKey identifies the key used to sign Git objects using the
+// Git config, so that it can be registered in gittuf metadata. GPG keys are
+// identified using user.signingKey, or user.email if no key is configured.
+// SSH keys are identified using the public key next to the configured key on
+// disk. For gitsign, the Sigstore identity is user.email with
+// DefaultFulcioIssuer as the issuer.
+func DetectGitSigningKey() (*GitSigningKey, error) {
+	signingMethod, keyInfo, program, err := gitinterface.GetSigningInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	switch signingMethod {
+	case gitinterface.SigningMethodGPG:
+		if keyInfo == "" {
+			keyInfo, err = gitinterface.GetConfigValue("user.email")
+			if err != nil {
+				return nil, err
+			}
+		}
+		if keyInfo == "" {
+			return nil, fmt.Errorf("%w: neither user.signingKey nor user.email is set", ErrGitSigningKeyNotDetected)
+		}
+
+		return &GitSigningKey{PublicKey: GPGKeyPrefix + keyInfo}, nil
+	case gitinterface.SigningMethodSSH:
+		if keyInfo == "" {
+			return nil, fmt.Errorf("%w: user.signingKey is not set", ErrGitSigningKeyNotDetected)
+		}
+		if strings.HasPrefix(keyInfo, "key::") {
+			return nil, fmt.Errorf("%w: public keys specified literally in user.signingKey or returned by gpg.ssh.defaultKeyCommand are not supported", ErrGitSigningKeyNotDetected)
+		}
+
+		keyPath, err := gitinterface.ExpandHomeDir(keyInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasSuffix(keyPath, ".pub") {
+			signingKey := &GitSigningKey{PublicKey: keyPath}
+			if privateKeyPath := strings.TrimSuffix(keyPath, ".pub"); fileExists(privateKeyPath) {
+				signingKey.PrivateKeyPath = privateKeyPath
+			}
+			return signingKey, nil
+		}
+
+		if !fileExists(keyPath + ".pub") {
+			return nil, fmt.Errorf("%w: public key '%s.pub' not found", ErrGitSigningKeyNotDetected, keyPath)
+		}
+		return &GitSigningKey{PublicKey: keyPath + ".pub", PrivateKeyPath: keyPath}, nil
+	case gitinterface.SigningMethodX509:
+		if !gitinterface.IsGitsign(program) {
+			return nil, fmt.Errorf("%w: only X.509 signatures issued by gitsign are supported", ErrGitSigningKeyNotDetected)
+		}
+
+		identity, err := gitinterface.GetConfigValue("user.email")
+		if err != nil {
+			return nil, err
+		}
+		if identity == "" {
+			return nil, fmt.Errorf("%w: user.email is not set", ErrGitSigningKeyNotDetected)
+		}
+
+		return &GitSigningKey{PublicKey: fmt.Sprintf("%s%s::%s", FulcioPrefix, identity, DefaultFulcioIssuer)}, nil
+	}
+
+	return nil, gitinterface.ErrUnknownSigningMethod
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
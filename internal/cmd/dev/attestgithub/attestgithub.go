@@ -4,15 +4,19 @@ package attestgithub
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/credentials"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
+// tokenKey is the environment variable the GitHub API token is read from when
+// no credential is configured for the github integration.
+const tokenKey = "GITHUB_TOKEN" //nolint:gosec
+
 type options struct {
 	signingKey        string
 	repository        string
@@ -78,20 +82,25 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	keyBytes, err := os.ReadFile(o.signingKey)
+	signer, err := common.LoadSignerForKey(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	config, err := common.LoadConfig()
 	if err != nil {
 		return err
 	}
-	signer, err := common.LoadSigner(keyBytes)
+	tokenSource, err := credentials.Load(config, "github", tokenKey)
 	if err != nil {
 		return err
 	}
 
 	if o.commitID != "" {
-		return repo.AddGitHubPullRequestAttestationForCommit(cmd.Context(), signer, repositoryParts[0], repositoryParts[1], o.commitID, o.baseBranch, true)
+		return repo.AddGitHubPullRequestAttestationForCommit(cmd.Context(), signer, tokenSource, repositoryParts[0], repositoryParts[1], o.commitID, o.baseBranch, true)
 	}
 
-	return repo.AddGitHubPullRequestAttestationForNumber(cmd.Context(), signer, repositoryParts[0], repositoryParts[1], o.pullRequestNumber, true)
+	return repo.AddGitHubPullRequestAttestationForNumber(cmd.Context(), signer, tokenSource, repositoryParts[0], repositoryParts[1], o.pullRequestNumber, true)
 }
 
 func New() *cobra.Command {
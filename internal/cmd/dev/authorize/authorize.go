@@ -4,10 +4,11 @@ package authorize
 
 import (
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
@@ -33,7 +34,7 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"from-ref",
 		"f",
 		"",
-		"ref to authorize merging changes from",
+		"ref to authorize merging changes from, or revision to authorize tagging when authorizing a tag",
 	)
 	cmd.MarkFlagRequired("from-ref") //nolint:errcheck
 
@@ -56,11 +57,7 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	keyBytes, err := os.ReadFile(o.signingKey)
-	if err != nil {
-		return err
-	}
-	signer, err := common.LoadSigner(keyBytes)
+	signer, err := common.LoadSignerForKey(o.signingKey)
 	if err != nil {
 		return err
 	}
@@ -73,6 +70,10 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 		return repo.RemoveReferenceAuthorization(cmd.Context(), signer, args[0], args[1], args[2], true)
 	}
 
+	if strings.HasPrefix(args[0], gitinterface.TagRefPrefix) {
+		return repo.AddReferenceAuthorizationForTag(cmd.Context(), signer, args[0], o.fromRef, true)
+	}
+
 	return repo.AddReferenceAuthorization(cmd.Context(), signer, args[0], o.fromRef, true)
 }
 
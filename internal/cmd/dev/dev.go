@@ -8,6 +8,7 @@ import (
 	"github.com/gittuf/gittuf/internal/cmd/dev/attestgithub"
 	"github.com/gittuf/gittuf/internal/cmd/dev/authorize"
 	"github.com/gittuf/gittuf/internal/cmd/dev/rslrecordat"
+	"github.com/gittuf/gittuf/internal/cmd/dev/validate"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/spf13/cobra"
 )
@@ -23,6 +24,7 @@ func New() *cobra.Command {
 	cmd.AddCommand(authorize.New())
 	cmd.AddCommand(attestgithub.New())
 	cmd.AddCommand(rslrecordat.New())
+	cmd.AddCommand(validate.New())
 
 	return cmd
 }
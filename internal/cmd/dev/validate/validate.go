@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+var ErrProblemsFound = errors.New("problems found")
+
+type options struct {
+	format string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	common.AddFormatFlag(cmd, &o.format)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
+	var (
+		problems []*repository.ValidationProblem
+		err      error
+	)
+	if len(args) > 0 {
+		problems, err = repository.ValidateFiles(args)
+	} else {
+		var repo *repository.Repository
+		repo, err = repository.LoadRepository()
+		if err != nil {
+			return err
+		}
+
+		problems, err = repo.ValidateMetadata(cmd.Context())
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.format == common.FormatJSON {
+		if err := common.PrintJSON(problems); err != nil {
+			return err
+		}
+	} else {
+		for _, problem := range problems {
+			fmt.Println(problem.String())
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %d", ErrProblemsFound, len(problems))
+	}
+
+	if o.format != common.FormatJSON {
+		fmt.Println("No problems found")
+	}
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "validate [file...]",
+		Short:             fmt.Sprintf("Validate gittuf metadata against the published JSON schemas (developer mode only, set %s=1)", dev.DevModeKey),
+		Long:              "This command checks gittuf's policy metadata, RSL entries, and attestations against the JSON schemas published in internal/schema/schemas. By default, the policy metadata in the policy and policy staging references, every entry in the RSL, and the current attestations in the repository are checked. If files are specified, they are checked instead. Each file may contain root or targets metadata, an in-toto statement, a DSSE envelope, or the message of an RSL entry. Each problem is reported with the JSON pointer to the offending value, and the command fails if any problems are found.",
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
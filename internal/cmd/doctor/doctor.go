@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/signerverifier/kms"
+	"github.com/spf13/cobra"
+)
+
+var ErrProblemsFound = errors.New("problems found")
+
+type options struct {
+	remote     string
+	fetch      bool
+	signingKey string
+	format     string
+}
+
+type diagnosticOutput struct {
+	Check   string `json:"check"`
+	OK      bool   `json:"ok"`
+	Problem string `json:"problem,omitempty"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.remote,
+		"remote",
+		"origin",
+		"remote to compare the RSL with",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.fetch,
+		"fetch",
+		false,
+		"fetch the remote's RSL before comparing",
+	)
+
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key used with gittuf's trust and policy commands to check",
+	)
+
+	common.AddFormatFlag(cmd, &o.format)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	if o.fetch {
+		if _, _, err := repo.CheckRemoteRSLForUpdates(cmd.Context(), o.remote); err != nil {
+			return err
+		}
+	}
+
+	diagnostics, err := repo.Doctor(cmd.Context(), o.remote)
+	if err != nil {
+		return err
+	}
+
+	if o.signingKey != "" {
+		diagnostics = append(diagnostics, checkSigningKeyFile(o.signingKey))
+	}
+
+	problems := 0
+	for _, diagnostic := range diagnostics {
+		if !diagnostic.OK() {
+			problems++
+		}
+	}
+
+	if o.format == common.FormatJSON {
+		output := make([]*diagnosticOutput, 0, len(diagnostics))
+		for _, diagnostic := range diagnostics {
+			output = append(output, &diagnosticOutput{
+				Check:   diagnostic.Check,
+				OK:      diagnostic.OK(),
+				Problem: diagnostic.Problem,
+				Fix:     diagnostic.Fix,
+			})
+		}
+		if err := common.PrintJSON(output); err != nil {
+			return err
+		}
+	} else {
+		for _, diagnostic := range diagnostics {
+			if diagnostic.OK() {
+				fmt.Printf("%s %s\n", common.Badge(true), diagnostic.Check)
+				continue
+			}
+
+			fmt.Printf("%s %s: %s\n", common.Badge(false), diagnostic.Check, diagnostic.Problem)
+			fmt.Printf("       Fix: %s\n", diagnostic.Fix)
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%w: %d", ErrProblemsFound, problems)
+	}
+
+	if o.format != common.FormatJSON {
+		fmt.Println("No problems found")
+	}
+	return nil
+}
+
+// checkSigningKeyFile checks that the key passed to gittuf's trust and policy
+// commands can be read and used for signing.
+func checkSigningKeyFile(path string) *repository.Diagnostic {
+	diagnostic := &repository.Diagnostic{Check: "gittuf signing key"}
+
+	if strings.HasPrefix(path, common.PluginKeyPrefix) {
+		if _, err := common.LoadSignerForKey(path); err != nil {
+			diagnostic.Problem = fmt.Sprintf("unable to load signing key '%s': %s", path, err)
+			diagnostic.Fix = "Check that the signer plugin is installed on the PATH and can access its key"
+		}
+		return diagnostic
+	}
+
+	if strings.HasPrefix(path, common.SSHAgentKeyPrefix) {
+		if _, err := common.LoadSignerForKey(path); err != nil {
+			diagnostic.Problem = fmt.Sprintf("unable to load signing key '%s': %s", path, err)
+			diagnostic.Fix = "Check that SSH_AUTH_SOCK is set and that the key is added to the SSH agent with 'ssh-add'"
+		}
+		return diagnostic
+	}
+
+	if kms.IsKMSKey(path) {
+		if _, err := common.LoadSignerForKey(path); err != nil {
+			diagnostic.Problem = fmt.Sprintf("unable to load signing key '%s': %s", path, err)
+			diagnostic.Fix = "Check that the key management service's credentials are set in the environment and that the key is an ECDSA key you can sign with"
+		}
+		return diagnostic
+	}
+
+	keyBytes, err := os.ReadFile(path)
+	if err != nil {
+		diagnostic.Problem = err.Error()
+		diagnostic.Fix = "Check that the key file exists and that you can read it"
+		return diagnostic
+	}
+
+	if _, err := common.LoadSigner(keyBytes); err != nil {
+		diagnostic.Problem = fmt.Sprintf("unable to load signing key '%s': %s", path, err)
+		diagnostic.Fix = "Use an SSH private key, or a private key in securesystemslib's format"
+	}
+
+	return diagnostic
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "doctor",
+		Short:             "Check for common problems with the gittuf setup",
+		Long:              "This command allows users to diagnose common problems that prevent gittuf from working as expected, such as an unsupported Git version, a missing signing program or unreadable signing key, a stale Sigstore trusted root, expired root of trust or policy metadata, and an RSL that has diverged from the remote's. A fix is suggested for each problem found, and the command fails if any problems are found.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gerritsubmit
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/gerrit"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// usernameKey and passwordKey are the environment variables that contain
+	// the credentials used to authenticate to Gerrit's REST API, so that they
+	// aren't exposed in the command line.
+	usernameKey = "GITTUF_GERRIT_USERNAME"
+	passwordKey = "GITTUF_GERRIT_PASSWORD" //nolint:gosec
+)
+
+type options struct {
+	change     string
+	branch     string
+	newRev     string
+	gerritURL  string
+	signingKey string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.change,
+		"change",
+		"",
+		"ID of the submitted change, as passed by Gerrit",
+	)
+	cmd.MarkFlagRequired("change") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.branch,
+		"branch",
+		"",
+		"branch the change was submitted to, as passed by Gerrit",
+	)
+	cmd.MarkFlagRequired("branch") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.newRev,
+		"newrev",
+		"",
+		"commit the change was submitted as, as passed by Gerrit",
+	)
+	cmd.MarkFlagRequired("newrev") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.gerritURL,
+		"gerrit-url",
+		"",
+		"URL of the Gerrit instance to record the change's approvals from",
+	)
+
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to use to sign the Gerrit change attestation",
+	)
+
+	cmd.MarkFlagsRequiredTogether("gerrit-url", "signing-key")
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	// Gerrit runs its hooks with GIT_DIR set to the project's repository
+	repoPath := os.Getenv("GIT_DIR")
+	if repoPath == "" {
+		repoPath = "."
+	}
+
+	repo, err := repository.LoadRepositoryAt(repoPath)
+	if err != nil {
+		return err
+	}
+
+	refName := o.branch
+	if !strings.HasPrefix(refName, gitinterface.RefPrefix) {
+		refName = gitinterface.BranchRefPrefix + refName
+	}
+
+	if err := repo.RecordRSLEntryForReference(refName, true); err != nil {
+		return err
+	}
+
+	if o.gerritURL == "" {
+		return nil
+	}
+
+	signer, err := common.LoadSignerForKey(o.signingKey)
+	if err != nil {
+		return err
+	}
+
+	client := gerrit.NewClient(o.gerritURL, os.Getenv(usernameKey), os.Getenv(passwordKey))
+	return repo.AddGerritChangeAttestation(cmd.Context(), signer, client, o.change, o.newRev, true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "gerrit-submit",
+		Short:             "Record a change submitted in Gerrit in the RSL",
+		Long:              fmt.Sprintf(`This command allows users to record changes submitted in Gerrit as they are submitted. It is meant to be invoked by the change-merged hook of Gerrit's hooks plugin with the arguments Gerrit passes to the hook; arguments that aren't used are ignored. The command records an RSL entry for the branch the change was submitted to. If --gerrit-url is set, the change and the approvals it received are also fetched using Gerrit's REST API and recorded in a signed Gerrit change attestation. The REST API is accessed anonymously unless the %s and %s environment variables contain the username and HTTP password of a Gerrit account.`, usernameKey, passwordKey),
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+		// Gerrit passes hooks details that aren't needed, such as the change
+		// owner and topic
+		FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
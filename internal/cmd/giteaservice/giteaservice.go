@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package giteaservice
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gittuf/gittuf/internal/giteaservice"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+// logger logs the messages of the giteaservice subsystem.
+var logger = logging.For("giteaservice")
+
+const (
+	// tokenKey is the environment variable that contains the Gitea or Forgejo
+	// access token, so that it isn't exposed in the command line.
+	tokenKey = "GITTUF_GITEA_TOKEN" //nolint:gosec
+
+	// webhookSecretKey is the environment variable that contains the secret
+	// of the repositories' webhooks.
+	webhookSecretKey = "GITTUF_GITEA_WEBHOOK_SECRET" //nolint:gosec
+)
+
+type options struct {
+	address  string
+	giteaURL string
+	cacheDir string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.address,
+		"address",
+		":8080",
+		"address to listen for webhook deliveries on",
+	)
+
+	cmd.Flags().StringVar(
+		&o.giteaURL,
+		"gitea-url",
+		"",
+		"URL of the Gitea or Forgejo instance",
+	)
+	cmd.MarkFlagRequired("gitea-url") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.cacheDir,
+		"cache-dir",
+		"",
+		"directory to mirror repositories to for verification (default is gittuf/gitea-service in the user's cache directory)",
+	)
+}
+
+func (o *options) Run(_ *cobra.Command, _ []string) error {
+	cacheDir := o.cacheDir
+	if cacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		cacheDir = filepath.Join(userCacheDir, "gittuf", "gitea-service")
+	}
+
+	registry := metrics.NewRegistry()
+	service, err := giteaservice.NewService(&giteaservice.Config{
+		URL:           o.giteaURL,
+		Token:         os.Getenv(tokenKey),
+		WebhookSecret: []byte(os.Getenv(webhookSecretKey)),
+		CacheDir:      cacheDir,
+		Metrics:       registry,
+	})
+	if err != nil {
+		return fmt.Errorf("%w, set %s and %s", err, tokenKey, webhookSecretKey)
+	}
+
+	logger.Info("Listening for webhook deliveries", "address", o.address)
+	return http.ListenAndServe(o.address, metrics.Handler(registry, metrics.NewHealth(), service)) //nolint:gosec
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "gitea-service",
+		Short:             "Run a service that verifies every push to Gitea or Forgejo repositories against gittuf policy",
+		Long:              fmt.Sprintf(`This command allows users to run a service that continuously enforces gittuf policies on repositories hosted on Gitea or Forgejo. For every push webhook, the service fetches the repository's refs and RSL, verifies the pushed ref against the repository's policy, and sets a commit status with the context '%s' on the pushed commit. Requiring this status in a branch protection rule blocks unverified changes from being merged. The service authenticates using an access token with access to the repositories set in the %s environment variable, and accepts webhooks signed with the secret set in the %s environment variable. To reject pushes that fail verification outright, use 'gittuf add-hooks --server' in the repositories on the server instead. Prometheus metrics describing the verifications performed are served at /metrics, along with the /healthz and /readyz health endpoints.`, giteaservice.StatusContext, tokenKey, webhookSecretKey),
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package githubapp
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/gittuf/gittuf/internal/githubapp"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+// logger logs the messages of the githubapp subsystem.
+var logger = logging.For("githubapp")
+
+// webhookSecretKey is the environment variable that contains the webhook
+// secret of the GitHub App, so that it isn't exposed in the command line.
+const webhookSecretKey = "GITTUF_GITHUB_APP_WEBHOOK_SECRET" //nolint:gosec
+
+type options struct {
+	address        string
+	appID          int64
+	privateKeyPath string
+	cacheDir       string
+	apiURL         string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.address,
+		"address",
+		":8080",
+		"address to listen for webhook deliveries on",
+	)
+
+	cmd.Flags().Int64Var(
+		&o.appID,
+		"app-id",
+		0,
+		"ID of the GitHub App",
+	)
+	cmd.MarkFlagRequired("app-id") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.privateKeyPath,
+		"private-key",
+		"",
+		"path to the private key of the GitHub App",
+	)
+	cmd.MarkFlagRequired("private-key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.cacheDir,
+		"cache-dir",
+		"",
+		"directory to mirror repositories to for verification (default is gittuf/github-app in the user's cache directory)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.apiURL,
+		"github-api-url",
+		"",
+		"API URL of a GitHub Enterprise Server instance",
+	)
+}
+
+func (o *options) Run(_ *cobra.Command, _ []string) error {
+	keyBytes, err := os.ReadFile(o.privateKeyPath)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := credentials.LoadGitHubAppPrivateKey(keyBytes)
+	if err != nil {
+		return err
+	}
+
+	cacheDir := o.cacheDir
+	if cacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		cacheDir = filepath.Join(userCacheDir, "gittuf", "github-app")
+	}
+
+	registry := metrics.NewRegistry()
+	app, err := githubapp.NewApp(&githubapp.Config{
+		AppID:         o.appID,
+		PrivateKey:    privateKey,
+		WebhookSecret: []byte(os.Getenv(webhookSecretKey)),
+		CacheDir:      cacheDir,
+		APIURL:        o.apiURL,
+		Metrics:       registry,
+	})
+	if err != nil {
+		return fmt.Errorf("%w, set %s", err, webhookSecretKey)
+	}
+
+	logger.Info("Listening for webhook deliveries", "address", o.address)
+	return http.ListenAndServe(o.address, metrics.Handler(registry, metrics.NewHealth(), app)) //nolint:gosec
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "github-app",
+		Short:             "Run a GitHub App that verifies every push against gittuf policy",
+		Long:              fmt.Sprintf(`This command allows users to run a GitHub App that continuously enforces gittuf policies on the repositories it's installed on. For every push event, the app fetches the repository's refs and RSL, verifies the pushed ref against the repository's policy, and sets a commit status with the context '%s' on the pushed commit. Requiring this status in a branch protection rule blocks unverified changes from being merged. The app needs read access to contents and write access to commit statuses, and must be subscribed to push events. The app's webhook secret must be set using the %s environment variable. Prometheus metrics describing the verifications performed are served at /metrics, along with the /healthz and /readyz health endpoints.`, githubapp.StatusContext, webhookSecretKey),
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
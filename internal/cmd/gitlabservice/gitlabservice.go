@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitlabservice
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/gittuf/gittuf/internal/gitlabservice"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+// logger logs the messages of the gitlabservice subsystem.
+var logger = logging.For("gitlabservice")
+
+const (
+	// tokenKey is the environment variable that contains the GitLab access
+	// token, so that it isn't exposed in the command line. It is used unless
+	// a credential is configured for the gitlab integration.
+	tokenKey = "GITTUF_GITLAB_TOKEN" //nolint:gosec
+
+	// webhookSecretKey is the environment variable that contains the secret
+	// token of the projects' webhooks.
+	webhookSecretKey = "GITTUF_GITLAB_WEBHOOK_SECRET" //nolint:gosec
+)
+
+type options struct {
+	address   string
+	gitlabURL string
+	cacheDir  string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.address,
+		"address",
+		":8080",
+		"address to listen for webhook deliveries on",
+	)
+
+	cmd.Flags().StringVar(
+		&o.gitlabURL,
+		"gitlab-url",
+		gitlabservice.DefaultURL,
+		"URL of the GitLab instance, for self-managed instances",
+	)
+
+	cmd.Flags().StringVar(
+		&o.cacheDir,
+		"cache-dir",
+		"",
+		"directory to mirror projects to for verification (default is gittuf/gitlab-service in the user's cache directory)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	cacheDir := o.cacheDir
+	if cacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		cacheDir = filepath.Join(userCacheDir, "gittuf", "gitlab-service")
+	}
+
+	config, err := common.LoadConfig()
+	if err != nil {
+		return err
+	}
+	tokenSource, err := credentials.Load(config, "gitlab", tokenKey)
+	if err != nil {
+		return err
+	}
+
+	// The token is obtained once upfront so that misconfigured credentials
+	// are reported before any webhook is received
+	token, err := tokenSource.Token(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return fmt.Errorf("%w, set %s and %s", gitlabservice.ErrMissingToken, tokenKey, webhookSecretKey)
+	}
+
+	registry := metrics.NewRegistry()
+	service, err := gitlabservice.NewService(&gitlabservice.Config{
+		URL:           o.gitlabURL,
+		Credentials:   tokenSource,
+		WebhookSecret: os.Getenv(webhookSecretKey),
+		CacheDir:      cacheDir,
+		Metrics:       registry,
+	})
+	if err != nil {
+		return fmt.Errorf("%w, set %s and %s", err, tokenKey, webhookSecretKey)
+	}
+
+	logger.Info("Listening for webhook deliveries", "address", o.address)
+	return http.ListenAndServe(o.address, metrics.Handler(registry, metrics.NewHealth(), service)) //nolint:gosec
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "gitlab-service",
+		Short:             "Run a service that verifies every push to GitLab projects against gittuf policy",
+		Long:              fmt.Sprintf(`This command allows users to run a service that continuously enforces gittuf policies on GitLab projects, including projects on self-managed instances. For every push or tag push webhook, the service fetches the project's refs and RSL, verifies the pushed ref against the project's policy, and sets a commit status named '%s' on the pushed commit. If verification fails, a note describing the failure is also added to each open merge request from the pushed branch. The service authenticates using an access token with the api scope set in the %s environment variable, or obtained using the credential configured for the gitlab integration in the gittuf config, and accepts webhooks whose secret token matches the %s environment variable. Prometheus metrics describing the verifications performed are served at /metrics, along with the /healthz and /readyz health endpoints.`, gitlabservice.StatusName, tokenKey, webhookSecretKey),
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
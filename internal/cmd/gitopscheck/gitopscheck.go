@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitopscheck
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/verifyservice"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// tokenKey is the environment variable that contains the bearer token
+	// presented to the gittuf API.
+	tokenKey = "GITTUF_SERVE_TOKEN" //nolint:gosec
+
+	// Argo CD sets these environment variables when running config management
+	// plugins.
+	argoCDRevisionKey       = "ARGOCD_APP_REVISION"
+	argoCDTargetRevisionKey = "ARGOCD_APP_SOURCE_TARGET_REVISION"
+)
+
+var (
+	ErrMissingRef     = errors.New("ref must be set using --ref or the ARGOCD_APP_SOURCE_TARGET_REVISION environment variable")
+	ErrSourceNotReady = errors.New("source is not ready to be synced")
+)
+
+type options struct {
+	server     string
+	repository string
+	ref        string
+	revision   string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.server,
+		"server",
+		"http://localhost:8080",
+		"URL of the gittuf API started using 'gittuf serve'",
+	)
+
+	cmd.Flags().StringVar(
+		&o.repository,
+		"repository",
+		"",
+		"name of the repository in the gittuf API",
+	)
+	cmd.MarkFlagRequired("repository") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.ref,
+		"ref",
+		"",
+		"ref the GitOps controller tracks (default is the value of ARGOCD_APP_SOURCE_TARGET_REVISION)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.revision,
+		"revision",
+		"",
+		"revision the GitOps controller is about to sync, as a commit ID or a Flux source revision (default is the value of ARGOCD_APP_REVISION)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	refName := o.ref
+	if refName == "" {
+		refName = os.Getenv(argoCDTargetRevisionKey)
+	}
+	if refName == "" {
+		return ErrMissingRef
+	}
+
+	revision := o.revision
+	if revision == "" {
+		revision = os.Getenv(argoCDRevisionKey)
+	}
+
+	client := verifyservice.NewClient(o.server, os.Getenv(tokenKey))
+	status, err := client.CheckSource(cmd.Context(), o.repository, refName, revision)
+	if err != nil {
+		return err
+	}
+
+	if !status.Ready {
+		return fmt.Errorf("%w: %s (report %s)", ErrSourceNotReady, status.Message, status.ReportID)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s (%s)\n", status.Message, status.Revision)
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "gitops-check",
+		Short:             "Check that a GitOps source revision is verified before it is synced",
+		Long:              fmt.Sprintf(`This command allows users to gate GitOps controllers such as Argo CD and Flux on gittuf verification. It asks the gittuf API started using 'gittuf serve', typically running as a sidecar of the controller, to verify the ref the controller tracks and to check that the revision about to be synced is the ref's verified tip, and fails if it isn't. When run as an Argo CD config management plugin, the ref and revision default to the values Argo CD passes to the plugin. The API's token is read from the %s environment variable. The same check is available to other controllers as GET /v1/repositories/{name}/source-status, which responds with 412 if the source is not ready to be synced.`, tokenKey),
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
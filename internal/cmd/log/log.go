@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+var ErrRefRequired = errors.New("a ref must be specified unless --signer is set")
+
+type options struct {
+	format string
+	signer string
+}
+
+type commitOutput struct {
+	ID          string `json:"id"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	Date        string `json:"date"`
+	Message     string `json:"message"`
+}
+
+type entryOutput struct {
+	ID                string          `json:"id"`
+	Ref               string          `json:"ref"`
+	Target            string          `json:"target"`
+	Skipped           bool            `json:"skipped"`
+	Signer            string          `json:"signer,omitempty"`
+	Approvers         []string        `json:"approvers"`
+	Rule              string          `json:"rule,omitempty"`
+	Verified          bool            `json:"verified"`
+	VerificationError string          `json:"verification_error,omitempty"`
+	Commits           []*commitOutput `json:"commits"`
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	common.AddFormatFlag(cmd, &o.format)
+
+	cmd.Flags().StringVar(
+		&o.signer,
+		"signer",
+		"",
+		"only show changes signed or approved by the specified key ID or Sigstore identity, searching all refs if no ref is specified",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	var entries []*policy.LogEntry
+	if o.signer != "" {
+		target := ""
+		if len(args) > 0 {
+			target = args[0]
+		}
+
+		signer := strings.TrimPrefix(strings.TrimPrefix(o.signer, common.GPGKeyPrefix), common.FulcioPrefix)
+		entries, err = repo.LogForSigner(cmd.Context(), signer, target)
+	} else {
+		if len(args) == 0 {
+			return ErrRefRequired
+		}
+
+		entries, err = repo.Log(cmd.Context(), args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.format == common.FormatJSON {
+		output := make([]*entryOutput, 0, len(entries))
+		for _, entry := range entries {
+			output = append(output, newEntryOutput(entry))
+		}
+
+		return common.PrintJSON(output)
+	}
+
+	labels := common.LoadKeyLabels(cmd.Context(), repo)
+	for _, entry := range entries {
+		header := fmt.Sprintf("entry %s", entry.RSLEntry.ID.String())
+		if entry.Skipped {
+			header += " (skipped)"
+		}
+		fmt.Println(header)
+
+		if o.signer != "" {
+			fmt.Printf("  Ref:        %s\n", entry.RSLEntry.RefName)
+		}
+		fmt.Printf("  Target:     %s\n", entry.RSLEntry.TargetID.String())
+
+		if entry.Signer != "" {
+			fmt.Printf("  Pushed by:  %s\n", labels.Describe(entry.Signer))
+		} else {
+			fmt.Println("  Pushed by:  unknown key")
+		}
+
+		if len(entry.Approvers) > 0 {
+			fmt.Printf("  Approvers:  %s\n", strings.Join(labels.DescribeAll(entry.Approvers), ", "))
+		}
+
+		if entry.Rule != "" {
+			fmt.Printf("  Rule:       %s\n", entry.Rule)
+		} else {
+			fmt.Println("  Rule:       none")
+		}
+
+		if entry.VerificationError != nil {
+			fmt.Printf("  Verified:   no (%s)\n", entry.VerificationError)
+		} else {
+			fmt.Println("  Verified:   yes")
+		}
+
+		for _, commit := range entry.Commits {
+			fmt.Printf("\n    commit %s\n", commit.Hash.String())
+			fmt.Printf("    Author: %s <%s>\n", commit.Author.Name, commit.Author.Email)
+			fmt.Printf("    Date:   %s\n", commit.Author.When.Format(time.RFC1123Z))
+			fmt.Printf("\n        %s\n", strings.Split(strings.TrimSpace(commit.Message), "\n")[0])
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func newEntryOutput(entry *policy.LogEntry) *entryOutput {
+	output := &entryOutput{
+		ID:        entry.RSLEntry.ID.String(),
+		Ref:       entry.RSLEntry.RefName,
+		Target:    entry.RSLEntry.TargetID.String(),
+		Skipped:   entry.Skipped,
+		Signer:    entry.Signer,
+		Approvers: entry.Approvers,
+		Rule:      entry.Rule,
+		Verified:  entry.VerificationError == nil,
+		Commits:   make([]*commitOutput, 0, len(entry.Commits)),
+	}
+	if output.Approvers == nil {
+		output.Approvers = []string{}
+	}
+	if entry.VerificationError != nil {
+		output.VerificationError = entry.VerificationError.Error()
+	}
+
+	for _, commit := range entry.Commits {
+		output.Commits = append(output.Commits, &commitOutput{
+			ID:          commit.Hash.String(),
+			AuthorName:  commit.Author.Name,
+			AuthorEmail: commit.Author.Email,
+			Date:        commit.Author.When.Format(time.RFC3339),
+			Message:     strings.TrimSpace(commit.Message),
+		})
+	}
+
+	return output
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "log [<ref>]",
+		Short:             "Show the verified history of a Git reference",
+		Long:              "This command allows users to read the history of a Git reference through the repository's policy. Each RSL entry for the reference is shown, starting with the latest, along with the key that signed it, the keys that approved the change, the rule that authorized it, whether it passes verification, and the commits it introduced. With --signer, the changes signed or approved by a key or Sigstore identity are shown instead, along with the commits they introduced that are signed by it, which helps investigate a potentially compromised credential. If no ref is specified, the history of all refs is searched.",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRefs),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
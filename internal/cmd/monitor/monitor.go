@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/gittuf/gittuf/internal/monitor"
+	"github.com/gittuf/gittuf/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+// logger logs the messages of the monitor subsystem.
+var logger = logging.For("monitor")
+
+var (
+	ErrAlertRaised = errors.New("monitor raised alerts")
+	errNotChecked  = errors.New("remotes and repositories have not been checked yet")
+)
+
+type options struct {
+	remotes       []string
+	repositories  []string
+	stateDir      string
+	interval      time.Duration
+	once          bool
+	exitOnAlert   bool
+	notifyWebhook string
+	notifyCommand string
+	metricsAddr   string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(
+		&o.remotes,
+		"remote",
+		nil,
+		"URL of remote to monitor, can be specified multiple times for mirrors of the same repository",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.repositories,
+		"repository",
+		nil,
+		"repository to verify the refs of, specified as <name>=<path or URL>, can be specified multiple times",
+	)
+
+	cmd.Flags().StringVar(
+		&o.stateDir,
+		"state-dir",
+		"",
+		"directory to record the witnessed states of the remotes' refs and mirror remote repositories in (default is gittuf/monitor in the user's cache directory)",
+	)
+
+	cmd.Flags().DurationVar(
+		&o.interval,
+		"interval",
+		5*time.Minute,
+		"time to wait between checks of the remotes and repositories",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.once,
+		"once",
+		false,
+		"check the remotes and repositories once and exit",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.exitOnAlert,
+		"exit-on-alert",
+		false,
+		"exit with a non-zero status when an alert is raised",
+	)
+
+	cmd.Flags().StringVar(
+		&o.notifyWebhook,
+		"notify-webhook",
+		"",
+		"URL to POST a JSON payload describing each alert to",
+	)
+
+	cmd.Flags().StringVar(
+		&o.notifyCommand,
+		"notify-command",
+		"",
+		"shell command to invoke with a JSON payload describing each alert on stdin",
+	)
+
+	cmd.Flags().StringVar(
+		&o.metricsAddr,
+		"metrics-address",
+		"",
+		"address to serve Prometheus metrics and health endpoints on, such as :9090",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repositories := make([]*monitor.Repository, 0, len(o.repositories))
+	for _, spec := range o.repositories {
+		repo, err := monitor.ParseRepository(spec)
+		if err != nil {
+			return err
+		}
+		repositories = append(repositories, repo)
+	}
+
+	stateDir := o.stateDir
+	if stateDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		stateDir = filepath.Join(userCacheDir, "gittuf", "monitor")
+	}
+
+	m, err := monitor.New(o.remotes, repositories, stateDir, notify.NewNotifier(o.notifyWebhook, o.notifyCommand))
+	if err != nil {
+		return err
+	}
+
+	checked := &atomic.Bool{}
+	if o.metricsAddr != "" {
+		registry := metrics.NewRegistry()
+		m.RegisterMetrics(registry)
+
+		health := metrics.NewHealth()
+		health.AddReadinessCheck("check", func(_ context.Context) error {
+			if !checked.Load() {
+				return errNotChecked
+			}
+			return nil
+		})
+
+		go func() {
+			logger.Info("Serving metrics", "address", o.metricsAddr)
+			if err := http.ListenAndServe(o.metricsAddr, metrics.Handler(registry, health, nil)); err != nil { //nolint:gosec
+				logger.Error("Unable to serve metrics", "address", o.metricsAddr, "error", err)
+			}
+		}()
+	}
+
+	ctx := cmd.Context()
+	for {
+		alerts, err := m.Check(ctx)
+		checked.Store(true)
+		for _, alert := range alerts {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s [%s] %s %s: %s\n", alert.Time.Format(time.RFC3339), alert.Event, alert.Repository, alert.Ref, alert.Error)
+		}
+
+		if err != nil {
+			if o.once {
+				return err
+			}
+
+			// Remotes may be unreachable temporarily, so the monitor keeps
+			// running
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s Error: %s\n", time.Now().Format(time.RFC3339), err.Error())
+		}
+
+		if o.exitOnAlert && len(alerts) > 0 {
+			return ErrAlertRaised
+		}
+		if o.once {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(o.interval):
+		}
+	}
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "monitor",
+		Short:             "Watch remotes for rollbacks, forks, and policy changes, and verify repositories continuously",
+		Long:              "This command allows users to run a monitor that acts as a witness of one or more remotes of a repository, independent of developers' clients. The monitor periodically fetches the RSL and policy refs of each remote and raises an alert when a remote rolls back a ref or rewrites its history after it was witnessed, when the remotes serve diverging RSLs (a split view), or when a remote's policy changes. For repository hosts that cannot run gittuf in hooks, the monitor can also verify the refs of one or more repositories specified using --repository, either local paths or remote URLs that are mirrored in --state-dir. Each check verifies the branches and tags that changed since the previous check, or all of them when the RSL changes, and raises an alert when a ref is changed without a corresponding RSL entry or fails verification. The states witnessed are recorded in --state-dir so that they're retained across restarts. Alerts are printed and logged, and can also be sent to a webhook or command as JSON payloads. With --exit-on-alert, the monitor exits with a non-zero status once an alert is raised. With --metrics-address, the monitor serves Prometheus metrics at /metrics, including the number of RSL entries each remote lags behind and the results and durations of verifications, along with the /healthz and /readyz health endpoints; the monitor is ready once it has checked the remotes and repositories.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
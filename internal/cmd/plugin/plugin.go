@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/version"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// Prefix is the prefix of the name of an executable that is invoked as a
+	// gittuf subcommand. For example, `gittuf foo` invokes `gittuf-foo`.
+	Prefix = "gittuf-"
+
+	// GitDirEnvKey is set to the GIT_DIR of the repository the plugin is
+	// invoked in. It is not set when gittuf is not invoked in a repository.
+	GitDirEnvKey = "GIT_DIR"
+
+	// ExecutableEnvKey is set to the path of the gittuf binary that invoked
+	// the plugin, so that plugins can call back into the same gittuf.
+	ExecutableEnvKey = "GITTUF_EXECUTABLE"
+
+	// VersionEnvKey is set to the version of the gittuf binary that invoked
+	// the plugin.
+	VersionEnvKey = "GITTUF_VERSION"
+)
+
+// Find returns the path of the plugin executable for the command line args.
+// The first arg is used as the name of the plugin. Built-in commands of
+// rootCmd take precedence over plugins, and flags are never treated as plugin
+// names. If no plugin is found, Find returns false.
+func Find(rootCmd *cobra.Command, args []string) (string, bool) {
+	if len(args) == 0 || args[0] == "" || strings.HasPrefix(args[0], "-") {
+		return "", false
+	}
+
+	// Cobra adds the help and completion commands lazily, make sure they
+	// aren't shadowed either
+	rootCmd.InitDefaultHelpCmd()
+	rootCmd.InitDefaultCompletionCmd()
+
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == args[0] || cmd.HasAlias(args[0]) {
+			return "", false
+		}
+	}
+
+	path, err := exec.LookPath(Prefix + args[0])
+	if err != nil {
+		return "", false
+	}
+
+	return path, true
+}
+
+// Run invokes the plugin executable at path with the args and the standard
+// streams of the current process. The repository context is passed to the
+// plugin via the environment.
+func Run(path string, args []string) error {
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = Environment()
+
+	return cmd.Run()
+}
+
+// Environment returns the environment a plugin is invoked with, which is the
+// environment of the current process with the repository context added.
+func Environment() []string {
+	env := os.Environ()
+
+	if repo, err := gitinterface.LoadRepository(); err == nil {
+		env = append(env, GitDirEnvKey+"="+repo.GetGitDir())
+	}
+
+	if executable, err := os.Executable(); err == nil {
+		env = append(env, ExecutableEnvKey+"="+executable)
+	}
+
+	env = append(env, VersionEnvKey+"="+version.GetVersion())
+
+	return env
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	pluginDir := t.TempDir()
+	writePlugin(t, pluginDir, "foo", "#!/bin/sh\n")
+	writePlugin(t, pluginDir, "bar", "#!/bin/sh\n")
+	writePlugin(t, pluginDir, "help", "#!/bin/sh\n")
+	t.Setenv("PATH", pluginDir)
+
+	rootCmd := &cobra.Command{Use: "gittuf"}
+	rootCmd.AddCommand(&cobra.Command{Use: "bar", Run: func(_ *cobra.Command, _ []string) {}})
+
+	tests := map[string]struct {
+		args         []string
+		expectedPath string
+		expectedOK   bool
+	}{
+		"plugin": {
+			args:         []string{"foo", "arg"},
+			expectedPath: filepath.Join(pluginDir, Prefix+"foo"),
+			expectedOK:   true,
+		},
+		"built-in command shadows plugin": {
+			args: []string{"bar"},
+		},
+		"help command shadows plugin": {
+			args: []string{"help"},
+		},
+		"unknown command": {
+			args: []string{"baz"},
+		},
+		"flag": {
+			args: []string{"--verbose", "foo"},
+		},
+		"no args": {
+			args: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path, ok := Find(rootCmd, test.args)
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expectedPath, path)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	pluginDir := t.TempDir()
+	outputFile := filepath.Join(pluginDir, "output")
+	writePlugin(t, pluginDir, "foo", "#!/bin/sh\necho \"$@\" > "+outputFile+"\necho \"$"+VersionEnvKey+"\" >> "+outputFile+"\n")
+	writePlugin(t, pluginDir, "fail", "#!/bin/sh\nexit 3\n")
+
+	t.Run("successful run", func(t *testing.T) {
+		err := Run(filepath.Join(pluginDir, Prefix+"foo"), []string{"a", "b"})
+		require.Nil(t, err)
+
+		output, err := os.ReadFile(outputFile)
+		require.Nil(t, err)
+
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, "a b", lines[0])
+		assert.NotEmpty(t, lines[1])
+	})
+
+	t.Run("failed run", func(t *testing.T) {
+		err := Run(filepath.Join(pluginDir, Prefix+"fail"), nil)
+		assert.ErrorContains(t, err, "exit status 3")
+	})
+}
+
+func TestEnvironment(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("GIT_DIR", filepath.Join(tmpDir, ".git"))
+
+	env := Environment()
+	assert.Contains(t, env, GitDirEnvKey+"="+filepath.Join(tmpDir, ".git"))
+
+	hasExecutable, hasVersion := false, false
+	for _, value := range env {
+		if strings.HasPrefix(value, ExecutableEnvKey+"=") {
+			hasExecutable = true
+		}
+		if strings.HasPrefix(value, VersionEnvKey+"=") {
+			hasVersion = true
+		}
+	}
+	assert.True(t, hasExecutable)
+	assert.True(t, hasVersion)
+}
+
+func writePlugin(t *testing.T, dir, name, contents string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, Prefix+name), []byte(contents), 0o755); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+}
@@ -3,8 +3,6 @@
 package addkey
 
 import (
-	"os"
-
 	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
 	"github.com/gittuf/gittuf/internal/policy"
@@ -34,6 +32,8 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"authorized public key for rule",
 	)
 	cmd.MarkFlagRequired("authorize-key") //nolint:errcheck
+
+	cmd.RegisterFlagCompletionFunc("policy-name", common.CompletePolicyNames) //nolint:errcheck
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
@@ -42,11 +42,7 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	keyBytes, err := os.ReadFile(o.p.SigningKey)
-	if err != nil {
-		return err
-	}
-	signer, err := common.LoadSigner(keyBytes)
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
 	if err != nil {
 		return err
 	}
@@ -3,8 +3,6 @@
 package addrule
 
 import (
-	"os"
-
 	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
 	"github.com/gittuf/gittuf/internal/policy"
@@ -60,6 +58,8 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		1,
 		"threshold of required valid signatures",
 	)
+
+	cmd.RegisterFlagCompletionFunc("policy-name", common.CompletePolicyNames) //nolint:errcheck
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
@@ -68,11 +68,7 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	keyBytes, err := os.ReadFile(o.p.SigningKey)
-	if err != nil {
-		return err
-	}
-	signer, err := common.LoadSigner(keyBytes)
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
 	if err != nil {
 		return err
 	}
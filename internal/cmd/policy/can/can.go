@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package can
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	targetRef string
+	ref       string
+	path      string
+	format    string
+}
+
+type ruleOutput struct {
+	Name               string                   `json:"name"`
+	AuthorizedKeys     []string                 `json:"authorized_keys"`
+	Threshold          int                      `json:"threshold"`
+	RequiredHooks      []string                 `json:"required_hooks,omitempty"`
+	RequiredRebuilds   []string                 `json:"required_rebuilds,omitempty"`
+	RequiredPredicates []string                 `json:"required_predicates,omitempty"`
+	RequiredApprovals  *tuf.ApprovalRequirement `json:"required_approvals,omitempty"`
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.targetRef,
+		"target-ref",
+		"policy",
+		"specify which policy ref should be inspected",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ref,
+		"ref",
+		"",
+		"Git reference to find the authorized principals for",
+	)
+
+	cmd.Flags().StringVar(
+		&o.path,
+		"path",
+		"",
+		"path of the file, relative to the root of the repository, to find the authorized principals for",
+	)
+
+	common.AddFormatFlag(cmd, &o.format)
+
+	cmd.MarkFlagsMutuallyExclusive("ref", "path")
+	cmd.MarkFlagsOneRequired("ref", "path")
+
+	cmd.RegisterFlagCompletionFunc("target-ref", common.CompletePolicyRefs) //nolint:errcheck
+	cmd.RegisterFlagCompletionFunc("ref", common.CompleteRefs)              //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	var (
+		verifiers []*policy.Verifier
+		target    string
+	)
+	if o.ref != "" {
+		verifiers, err = repo.FindVerifiersForRef(cmd.Context(), o.targetRef, o.ref)
+		target = o.ref
+	} else {
+		verifiers, err = repo.FindVerifiersForFile(cmd.Context(), o.targetRef, o.path)
+		target = o.path
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.format == common.FormatJSON {
+		output := make([]*ruleOutput, 0, len(verifiers))
+		for _, verifier := range verifiers {
+			output = append(output, &ruleOutput{
+				Name:               verifier.Name(),
+				AuthorizedKeys:     keyIDs(verifier),
+				Threshold:          verifier.Threshold(),
+				RequiredHooks:      verifier.RequiredHooks(),
+				RequiredRebuilds:   verifier.RequiredRebuilds(),
+				RequiredPredicates: verifier.RequiredPredicates(),
+				RequiredApprovals:  verifier.RequiredApprovals(),
+			})
+		}
+
+		return common.PrintJSON(output)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(verifiers) == 0 {
+		fmt.Fprintf(out, "'%s' is not protected by any rule, anyone can change it.\n", target)
+		return nil
+	}
+
+	labels := common.LoadKeyLabels(cmd.Context(), repo)
+	if len(verifiers) == 1 {
+		fmt.Fprintf(out, "Changes to '%s' must meet rule '%s':\n", target, verifiers[0].Name())
+	} else {
+		fmt.Fprintf(out, "Changes to '%s' must meet any one of these rules:\n", target)
+	}
+
+	for _, verifier := range verifiers {
+		description := labels.DescribePrincipals(keyIDs(verifier), verifier.Threshold())
+		if len(verifier.RequiredHooks()) > 0 {
+			description += fmt.Sprintf(", with the hooks %s passing", common.JoinList(verifier.RequiredHooks(), "and"))
+		}
+		if len(verifier.RequiredPredicates()) > 0 {
+			description += fmt.Sprintf(", with statements of the types %s", common.JoinList(verifier.RequiredPredicates(), "and"))
+		}
+		if requirement := verifier.RequiredApprovals(); requirement != nil {
+			description += fmt.Sprintf(", with %d approvals in code review from rule '%s'", requirement.Threshold, requirement.Role)
+		}
+		fmt.Fprintf(out, "    %s: %s\n", verifier.Name(), description)
+	}
+
+	return nil
+}
+
+func keyIDs(verifier *policy.Verifier) []string {
+	ids := make([]string, 0, len(verifier.Keys()))
+	for _, key := range verifier.Keys() {
+		ids = append(ids, key.KeyID)
+	}
+
+	return ids
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "can",
+		Short:             "List who is authorized to change a Git reference or file",
+		Long:              "This command allows users to find out who is currently authorized to change a Git reference or a file under the policy. The rules that protect the reference or file are listed along with the principals they trust, such as \"Alice or Bob\" or \"Alice, Bob, or Carol (2 of 3)\". Changes must meet any one of the listed rules.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
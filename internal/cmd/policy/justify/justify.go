@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package justify
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p        *persistent.Options
+	reason   string
+	ticket   string
+	approver string
+	rekorURL string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.reason,
+		"reason",
+		"",
+		"reason for the policy change",
+	)
+	cmd.MarkFlagRequired("reason") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.ticket,
+		"ticket",
+		"",
+		"link to the ticket tracking the policy change",
+	)
+
+	cmd.Flags().StringVar(
+		&o.approver,
+		"approver",
+		"",
+		"person who approved the policy change",
+	)
+
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to log created attestation to",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	return repo.AddPolicyJustification(ctx, signer, o.reason, o.ticket, o.approver, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "justify",
+		Short:             "Record a justification for the staged policy changes",
+		Long:              "This command allows users to record a signed justification for the changes in the staged policy, such as the reason for the change, a link to the ticket tracking it, and who approved it. If the root of trust requires policy changes to be justified, this must be done before running 'gittuf policy apply'. Justifications are listed by 'gittuf policy log'.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -6,12 +6,23 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
 	targetRef string
+	format    string
+}
+
+type ruleOutput struct {
+	Name           string   `json:"name"`
+	Depth          int      `json:"depth"`
+	Paths          []string `json:"paths"`
+	Refs           []string `json:"refs"`
+	AuthorizedKeys []string `json:"authorized_keys"`
+	Threshold      int      `json:"threshold"`
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -21,9 +32,17 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"policy",
 		"specify which policy ref should be inspected",
 	)
+
+	common.AddFormatFlag(cmd, &o.format)
+
+	cmd.RegisterFlagCompletionFunc("target-ref", common.CompletePolicyRefs) //nolint:errcheck
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
@@ -34,9 +53,34 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	if o.format == common.FormatJSON {
+		output := make([]*ruleOutput, 0, len(rules))
+		for _, curRule := range rules {
+			rule := &ruleOutput{
+				Name:           curRule.Delegation.Name,
+				Depth:          curRule.Depth,
+				Paths:          []string{},
+				Refs:           []string{},
+				AuthorizedKeys: curRule.Delegation.Role.KeyIDs,
+				Threshold:      curRule.Delegation.Role.Threshold,
+			}
+			for _, path := range curRule.Delegation.Paths {
+				if strings.HasPrefix(path, "git:") {
+					rule.Refs = append(rule.Refs, path)
+				} else {
+					rule.Paths = append(rule.Paths, path)
+				}
+			}
+			output = append(output, rule)
+		}
+
+		return common.PrintJSON(output)
+	}
+
 	// Iterate through the rules, they are already in order, and the depth tells us how to indent.
 	// The order is a pre-order traversal of the delegation tree, so that the parent is always before the children.
 
+	labels := common.LoadKeyLabels(cmd.Context(), repo)
 	for _, curRule := range rules {
 		fmt.Printf(strings.Repeat("    ", curRule.Depth)+"Rule %s:\n", curRule.Delegation.Name)
 		gitpaths, filepaths := []string{}, []string{}
@@ -62,7 +106,7 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 
 		fmt.Println(strings.Repeat("    ", curRule.Depth+1) + "Authorized keys:")
 		for _, key := range curRule.Delegation.Role.KeyIDs {
-			fmt.Printf(strings.Repeat("    ", curRule.Depth+2)+"%s\n", key)
+			fmt.Printf(strings.Repeat("    ", curRule.Depth+2)+"%s\n", labels.Describe(key))
 		}
 
 		fmt.Println(strings.Repeat("    ", curRule.Depth+1) + fmt.Sprintf("Required valid signatures: %d", curRule.Delegation.Role.Threshold))
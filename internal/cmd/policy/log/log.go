@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	format string
+}
+
+type justificationOutput struct {
+	Reason   string   `json:"reason"`
+	Ticket   string   `json:"ticket,omitempty"`
+	Approver string   `json:"approver,omitempty"`
+	Signers  []string `json:"signers"`
+}
+
+type entryOutput struct {
+	RSLEntryID     string               `json:"rsl_entry_id"`
+	PolicyCommitID string               `json:"policy_commit_id"`
+	Message        string               `json:"message"`
+	Justification  *justificationOutput `json:"justification"`
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	common.AddFormatFlag(cmd, &o.format)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	entries, err := repo.PolicyLog()
+	if err != nil {
+		return err
+	}
+
+	if o.format == common.FormatJSON {
+		output := make([]*entryOutput, 0, len(entries))
+		for _, entry := range entries {
+			entryOutput := &entryOutput{
+				RSLEntryID:     entry.RSLEntryID,
+				PolicyCommitID: entry.PolicyCommitID,
+				Message:        entry.Message,
+			}
+			if entry.Justification != nil {
+				entryOutput.Justification = &justificationOutput{
+					Reason:   entry.Justification.Reason,
+					Ticket:   entry.Justification.Ticket,
+					Approver: entry.Justification.Approver,
+					Signers:  entry.JustificationSigners,
+				}
+			}
+			output = append(output, entryOutput)
+		}
+
+		return common.PrintJSON(output)
+	}
+
+	labels := common.LoadKeyLabels(cmd.Context(), repo)
+	for _, entry := range entries {
+		fmt.Printf("Policy entry %s\n", entry.RSLEntryID)
+		fmt.Printf("    Policy commit: %s\n", entry.PolicyCommitID)
+		fmt.Printf("    Message: %s\n", strings.Split(entry.Message, "\n")[0])
+
+		if entry.Justification == nil {
+			fmt.Println("    Justification: none")
+			continue
+		}
+
+		fmt.Println("    Justification:")
+		fmt.Printf("        Reason: %s\n", entry.Justification.Reason)
+		if entry.Justification.Ticket != "" {
+			fmt.Printf("        Ticket: %s\n", entry.Justification.Ticket)
+		}
+		if entry.Justification.Approver != "" {
+			fmt.Printf("        Approver: %s\n", entry.Justification.Approver)
+		}
+		fmt.Println("        Signed by:")
+		for _, keyID := range entry.JustificationSigners {
+			fmt.Printf("            %s\n", labels.Describe(keyID))
+		}
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "log",
+		Short:             "List applied policy changes and their justifications",
+		Long:              "This command allows users to list the policy states applied in the repository, starting with the latest one, along with the justification recorded for each change using 'gittuf policy justify'.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -5,13 +5,29 @@ package policy
 import (
 	"github.com/gittuf/gittuf/internal/cmd/policy/addkey"
 	"github.com/gittuf/gittuf/internal/cmd/policy/addrule"
+	"github.com/gittuf/gittuf/internal/cmd/policy/can"
 	i "github.com/gittuf/gittuf/internal/cmd/policy/init"
+	"github.com/gittuf/gittuf/internal/cmd/policy/justify"
 	"github.com/gittuf/gittuf/internal/cmd/policy/listrules"
+	"github.com/gittuf/gittuf/internal/cmd/policy/log"
 	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/policy/removepredicatepolicy"
 	"github.com/gittuf/gittuf/internal/cmd/policy/removerule"
+	"github.com/gittuf/gittuf/internal/cmd/policy/rollback"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setallowedsignaturemethods"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setpredicatepolicy"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setrequiredapprovals"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setrequiredevaluators"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setrequiredhooks"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setrequiredpredicates"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setrequiredrebuilds"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setrequireverifiedsubmodule"
+	"github.com/gittuf/gittuf/internal/cmd/policy/show"
 	"github.com/gittuf/gittuf/internal/cmd/policy/sign"
 	"github.com/gittuf/gittuf/internal/cmd/policy/updaterule"
+	"github.com/gittuf/gittuf/internal/cmd/policy/wizard"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/apply"
+	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/oci"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/remote"
 	"github.com/spf13/cobra"
 )
@@ -29,11 +45,27 @@ func New() *cobra.Command {
 	cmd.AddCommand(addkey.New(o))
 	cmd.AddCommand(apply.New())
 	cmd.AddCommand(addrule.New(o))
+	cmd.AddCommand(can.New())
+	cmd.AddCommand(justify.New(o))
 	cmd.AddCommand(listrules.New())
+	cmd.AddCommand(log.New())
+	cmd.AddCommand(oci.New())
 	cmd.AddCommand(remote.New())
+	cmd.AddCommand(removepredicatepolicy.New(o))
 	cmd.AddCommand(removerule.New(o))
+	cmd.AddCommand(rollback.New())
+	cmd.AddCommand(setallowedsignaturemethods.New(o))
+	cmd.AddCommand(setpredicatepolicy.New(o))
+	cmd.AddCommand(setrequiredapprovals.New(o))
+	cmd.AddCommand(setrequiredevaluators.New(o))
+	cmd.AddCommand(setrequiredhooks.New(o))
+	cmd.AddCommand(setrequiredpredicates.New(o))
+	cmd.AddCommand(setrequiredrebuilds.New(o))
+	cmd.AddCommand(setrequireverifiedsubmodule.New(o))
+	cmd.AddCommand(show.New())
 	cmd.AddCommand(sign.New(o))
 	cmd.AddCommand(updaterule.New(o))
+	cmd.AddCommand(wizard.New(o))
 
 	return cmd
 }
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package removepredicatepolicy
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p             *persistent.Options
+	predicateType string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.predicateType,
+		"predicate-type",
+		"",
+		"predicate type of attestations the policy applies to",
+	)
+	cmd.MarkFlagRequired("predicate-type") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.RemovePredicatePolicy(cmd.Context(), signer, o.predicateType, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "remove-predicate-policy",
+		Short:             "Remove the policy for a predicate type",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
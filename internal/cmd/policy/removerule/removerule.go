@@ -3,8 +3,6 @@
 package removerule
 
 import (
-	"os"
-
 	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
 	"github.com/gittuf/gittuf/internal/policy"
@@ -33,6 +31,9 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"name of rule",
 	)
 	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.RegisterFlagCompletionFunc("policy-name", common.CompletePolicyNames) //nolint:errcheck
+	cmd.RegisterFlagCompletionFunc("rule-name", common.CompleteRuleNames)     //nolint:errcheck
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
@@ -41,11 +42,7 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	keyBytes, err := os.ReadFile(o.p.SigningKey)
-	if err != nil {
-		return err
-	}
-	signer, err := common.LoadSigner(keyBytes)
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
 	if err != nil {
 		return err
 	}
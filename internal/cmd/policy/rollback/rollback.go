@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rollback
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	policyCommitID := ""
+	if len(args) > 0 {
+		policyCommitID = args[0]
+	}
+
+	return repo.RollbackPolicy(cmd.Context(), policyCommitID, true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "rollback [<policy-commit>]",
+		Short:             "Revert the policy to a previously applied policy state",
+		Long:              "This command allows users to revert the active policy to a policy state that was previously applied, identified by its policy commit as listed by 'gittuf policy log'. If no policy commit is specified, the policy state applied before the active one is used. The policy history is not rewritten, instead the previous state is staged and applied as a new policy state. Any changes staged but not yet applied are discarded.",
+		Args:              cobra.MaximumNArgs(1),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
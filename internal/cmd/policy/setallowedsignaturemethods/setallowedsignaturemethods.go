@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setallowedsignaturemethods
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	methods    []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file the rule is in",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.methods,
+		"method",
+		[]string{},
+		"signature method allowed for Git signatures counted by the rule, such as 'ssh:algorithm=ed25519' or 'sigstore:issuer=<issuer>'",
+	)
+
+	cmd.RegisterFlagCompletionFunc("policy-name", common.CompletePolicyNames) //nolint:errcheck
+	cmd.RegisterFlagCompletionFunc("rule-name", common.CompleteRuleNames)     //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetAllowedSignatureMethods(cmd.Context(), signer, o.policyName, o.ruleName, o.methods, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "set-allowed-signature-methods",
+		Short:             "Set the signature methods allowed for Git signatures counted by a rule",
+		Long:              "This command allows users to restrict the mechanisms of Git signatures that count towards the threshold of the specified rule, so that otherwise valid signatures by the rule's keys are rejected if they use a disallowed mechanism. Each method is a signature format, one of 'gpg', 'ssh', 'sigstore', or 'smime', optionally followed by a colon and a comma separated list of parameters: 'algorithm' restricts the algorithm of the signing key, such as 'ed25519', 'ed25519-sk', 'ecdsa', or 'rsa', 'hash' the hash algorithm, such as 'sha256' or 'sha512', and 'issuer' the OIDC issuer of Sigstore signatures. Parameters may be repeated to accept several values. Signatures on reference authorizations are not restricted. Specifying no methods removes the restriction. By default, the main policy file is selected.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
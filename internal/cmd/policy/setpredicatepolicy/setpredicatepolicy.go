@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setpredicatepolicy
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p              *persistent.Options
+	predicateType  string
+	authorizedKeys []string
+	threshold      int
+	validator      string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.predicateType,
+		"predicate-type",
+		"",
+		"predicate type of attestations the policy applies to",
+	)
+	cmd.MarkFlagRequired("predicate-type") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.authorizedKeys,
+		"authorize-key",
+		[]string{},
+		"public key trusted to issue attestations of the predicate type",
+	)
+	cmd.MarkFlagRequired("authorize-key") //nolint:errcheck
+
+	cmd.Flags().IntVar(
+		&o.threshold,
+		"threshold",
+		1,
+		"threshold of required valid signatures",
+	)
+
+	cmd.Flags().StringVar(
+		&o.validator,
+		"validator",
+		"",
+		"name of predicate plugin that must accept the attestations' predicates",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	authorizedKeys := []*tuf.Key{}
+	for _, key := range o.authorizedKeys {
+		key, err := common.LoadPublicKey(key)
+		if err != nil {
+			return err
+		}
+
+		authorizedKeys = append(authorizedKeys, key)
+	}
+
+	return repo.SetPredicatePolicy(cmd.Context(), signer, o.predicateType, authorizedKeys, o.threshold, o.validator, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "set-predicate-policy",
+		Short:             "Set the keys trusted to issue attestations of a predicate type",
+		Long:              `This command allows users to specify which keys are trusted to issue attestations of a predicate type and the threshold of signatures required, such as one trusted builder for provenance or two humans for code review. Predicate policies are recorded in the main policy file, and an existing policy for the predicate type is replaced. Note that authorized keys can be specified from disk, from the GPG keyring using the "gpg:<fingerprint>" format, or as a Sigstore identity as "fulcio:<identity>::<issuer>". If a validator is specified using "--validator", the predicate plugin of that name must also accept the predicate of each attestation.`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
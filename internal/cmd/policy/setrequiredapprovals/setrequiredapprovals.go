@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setrequiredapprovals
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p                 *persistent.Options
+	policyName        string
+	ruleName          string
+	approversRuleName string
+	threshold         int
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file the rule is in",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.approversRuleName,
+		"approvers-rule-name",
+		"",
+		"name of rule whose principals may approve changes in code review",
+	)
+
+	cmd.Flags().IntVar(
+		&o.threshold,
+		"threshold",
+		0,
+		"number of principals who must approve changes in code review, 0 removes the requirement",
+	)
+	cmd.MarkFlagRequired("threshold") //nolint:errcheck
+
+	cmd.RegisterFlagCompletionFunc("policy-name", common.CompletePolicyNames)       //nolint:errcheck
+	cmd.RegisterFlagCompletionFunc("rule-name", common.CompleteRuleNames)           //nolint:errcheck
+	cmd.RegisterFlagCompletionFunc("approvers-rule-name", common.CompleteRuleNames) //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetRequiredApprovals(cmd.Context(), signer, o.policyName, o.ruleName, o.approversRuleName, o.threshold, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "set-required-approvals",
+		Short:             "Set the code review approvals that changes authorized by a rule must have",
+		Long:              "This command allows users to require that a change is approved on a code review platform, such as GitHub or GitLab, by the specified number of principals authorized by another rule before it is authorized by the specified rule. The approvals are recorded in review approval attestations using 'gittuf attest review-approvals', which must be signed by keys trusted for the review approval predicate type. The approvers rule must be in the same policy file. A threshold of 0 removes the requirement. By default, the main policy file is selected.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setrequiredevaluators
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p              *persistent.Options
+	policyName     string
+	ruleName       string
+	evaluatorNames []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file the rule is in",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.evaluatorNames,
+		"evaluator",
+		[]string{},
+		"name of rule evaluator plugin that must allow changes authorized by the rule",
+	)
+
+	cmd.RegisterFlagCompletionFunc("policy-name", common.CompletePolicyNames) //nolint:errcheck
+	cmd.RegisterFlagCompletionFunc("rule-name", common.CompleteRuleNames)     //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetRequiredEvaluators(cmd.Context(), signer, o.policyName, o.ruleName, o.evaluatorNames, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "set-required-evaluators",
+		Short:             "Set the rule evaluator plugins that must allow changes authorized by a rule",
+		Long:              "This command allows users to require that rule evaluator plugins, such as a check against an external ticketing system, allow a change before it is authorized by the specified rule. Rule evaluators are executables named 'gittuf-rule-evaluator-<name>' on the PATH of the user verifying the change. Specifying no evaluators removes the requirement. By default, the main policy file is selected.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setrequiredhooks
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	hookNames  []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file the rule is in",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.hookNames,
+		"hook",
+		[]string{},
+		"name of hook that must pass for changes authorized by the rule",
+	)
+
+	cmd.RegisterFlagCompletionFunc("policy-name", common.CompletePolicyNames) //nolint:errcheck
+	cmd.RegisterFlagCompletionFunc("rule-name", common.CompleteRuleNames)     //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetRequiredHooks(cmd.Context(), signer, o.policyName, o.ruleName, o.hookNames, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "set-required-hooks",
+		Short:             "Set the hooks that must pass for changes authorized by a rule",
+		Long:              "This command allows users to require that hooks, such as a secret scanner, were executed successfully before a change is authorized by the specified rule. Hook results are recorded using 'gittuf attest run-hook'. Specifying no hooks removes the requirement. By default, the main policy file is selected.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setrequiredrebuilds
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p             *persistent.Options
+	policyName    string
+	ruleName      string
+	artifactNames []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file the rule is in",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.artifactNames,
+		"artifact",
+		[]string{},
+		"name of artifact that must be reproduced by rebuilders for tags authorized by the rule",
+	)
+
+	cmd.RegisterFlagCompletionFunc("policy-name", common.CompletePolicyNames) //nolint:errcheck
+	cmd.RegisterFlagCompletionFunc("rule-name", common.CompleteRuleNames)     //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetRequiredRebuilds(cmd.Context(), signer, o.policyName, o.ruleName, o.artifactNames, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "set-required-rebuilds",
+		Short:             "Set the artifacts that must be reproduced for tags authorized by a rule",
+		Long:              "This command allows users to require that artifacts built from a tagged commit were reproduced by independent rebuilders before the tag is authorized by the specified rule. Rebuilds are recorded using 'gittuf attest rebuild', and the number of rebuilders that must agree on an artifact's digest is set using a predicate policy for rebuild attestations. Specifying no artifacts removes the requirement. By default, the main policy file is selected.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package show
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/spf13/cobra"
+)
+
+const (
+	gitRuleScheme       = "git:"
+	fileRuleScheme      = "file:"
+	submoduleRuleScheme = "submodule:"
+)
+
+type options struct {
+	targetRef string
+	human     bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.targetRef,
+		"target-ref",
+		"policy",
+		"specify which policy ref should be inspected",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.human,
+		"human",
+		false,
+		"describe the policy in sentences instead of printing the metadata as JSON",
+	)
+
+	cmd.RegisterFlagCompletionFunc("target-ref", common.CompletePolicyRefs) //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	metadata, err := repo.GetPolicyMetadata(cmd.Context(), o.targetRef)
+	if err != nil {
+		return err
+	}
+
+	if !o.human {
+		output := map[string]any{policy.RootRoleName: metadata.Root}
+		for roleName, targetsMetadata := range metadata.Targets {
+			output[roleName] = targetsMetadata
+		}
+
+		return common.PrintJSON(output)
+	}
+
+	labels := common.LoadKeyLabels(cmd.Context(), repo)
+	out := cmd.OutOrStdout()
+
+	describeRoot(out, labels, metadata.Root)
+
+	roleNames := make([]string, 0, len(metadata.Targets))
+	for roleName := range metadata.Targets {
+		if roleName != policy.TargetsRoleName {
+			roleNames = append(roleNames, roleName)
+		}
+	}
+	sort.Strings(roleNames)
+	if _, has := metadata.Targets[policy.TargetsRoleName]; has {
+		// The main policy file is described first
+		roleNames = append([]string{policy.TargetsRoleName}, roleNames...)
+	}
+
+	for _, roleName := range roleNames {
+		fmt.Fprintln(out)
+		describeTargets(out, labels, roleName, metadata.Targets[roleName], metadata.Targets)
+	}
+
+	return nil
+}
+
+func describeRoot(out io.Writer, labels common.KeyLabels, rootMetadata *tuf.RootMetadata) {
+	fmt.Fprintf(out, "Root of trust (version %d, expires %s)\n", rootMetadata.Version, formatExpiry(rootMetadata.Expires))
+
+	if role, has := rootMetadata.Roles[policy.RootRoleName]; has {
+		fmt.Fprintf(out, "    %s must sign changes to the root of trust.\n", labels.DescribePrincipals(role.KeyIDs, role.Threshold))
+	}
+
+	if role, has := rootMetadata.Roles[policy.TargetsRoleName]; has {
+		fmt.Fprintf(out, "    %s must sign changes to the main policy file.\n", labels.DescribePrincipals(role.KeyIDs, role.Threshold))
+	} else {
+		fmt.Fprintln(out, "    No one is trusted to sign the main policy file yet.")
+	}
+
+	if rootMetadata.RequirePolicyJustifications {
+		fmt.Fprintln(out, "    Policy changes must be accompanied by a signed justification.")
+	}
+
+	if rootMetadata.MetadataCompression != "" {
+		fmt.Fprintf(out, "    Large policy metadata and attestations are compressed using %s.\n", rootMetadata.MetadataCompression)
+	}
+
+	keyIDs := make([]string, 0, len(rootMetadata.KeyValidity))
+	for keyID := range rootMetadata.KeyValidity {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+	for _, keyID := range keyIDs {
+		window := rootMetadata.KeyValidity[keyID]
+		switch {
+		case window.NotBefore != "" && window.NotAfter != "":
+			fmt.Fprintf(out, "    %s may only sign between %s and %s.\n", labels.Name(keyID), window.NotBefore, window.NotAfter)
+		case window.NotBefore != "":
+			fmt.Fprintf(out, "    %s may only sign after %s.\n", labels.Name(keyID), window.NotBefore)
+		default:
+			fmt.Fprintf(out, "    %s may only sign until %s.\n", labels.Name(keyID), window.NotAfter)
+		}
+	}
+
+	revokedKeyIDs := make([]string, 0, len(rootMetadata.RevokedKeys))
+	for keyID := range rootMetadata.RevokedKeys {
+		revokedKeyIDs = append(revokedKeyIDs, keyID)
+	}
+	sort.Strings(revokedKeyIDs)
+	for _, keyID := range revokedKeyIDs {
+		if revocation := rootMetadata.RevokedKeys[keyID]; revocation != nil && revocation.Reason != "" {
+			fmt.Fprintf(out, "    %s is revoked: %s.\n", labels.Name(keyID), revocation.Reason)
+		} else {
+			fmt.Fprintf(out, "    %s is revoked.\n", labels.Name(keyID))
+		}
+	}
+
+	switch len(rootMetadata.TimestampAuthorities) {
+	case 0:
+	case 1:
+		fmt.Fprintln(out, "    Signatures may be timestamped by 1 trusted timestamp authority.")
+	default:
+		fmt.Fprintf(out, "    Signatures may be timestamped by %d trusted timestamp authorities.\n", len(rootMetadata.TimestampAuthorities))
+	}
+}
+
+func describeTargets(out io.Writer, labels common.KeyLabels, roleName string, targetsMetadata *tuf.TargetsMetadata, allTargetsMetadata map[string]*tuf.TargetsMetadata) {
+	if roleName == policy.TargetsRoleName {
+		fmt.Fprintf(out, "Main policy file (version %d, expires %s)\n", targetsMetadata.Version, formatExpiry(targetsMetadata.Expires))
+	} else {
+		fmt.Fprintf(out, "Policy file '%s' (version %d, expires %s)\n", roleName, targetsMetadata.Version, formatExpiry(targetsMetadata.Expires))
+	}
+
+	rules := []tuf.Delegation{}
+	if targetsMetadata.Delegations != nil {
+		for _, rule := range targetsMetadata.Delegations.Roles {
+			if rule.Name != policy.AllowRuleName {
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	if len(rules) == 0 && len(targetsMetadata.PredicatePolicies) == 0 {
+		fmt.Fprintln(out, "    No rules are defined.")
+		return
+	}
+
+	for _, rule := range rules {
+		fmt.Fprintf(out, "    Rule '%s': %s may %s.\n", rule.Name, labels.DescribePrincipals(rule.KeyIDs, rule.Threshold), describePatterns(rule.Paths))
+
+		if len(rule.RequiredHooks) > 0 {
+			fmt.Fprintf(out, "        The hooks %s must pass first.\n", common.JoinList(rule.RequiredHooks, "and"))
+		}
+		if len(rule.RequiredRebuilds) > 0 {
+			fmt.Fprintf(out, "        Rebuilders must reproduce %s for tags.\n", common.JoinList(rule.RequiredRebuilds, "and"))
+		}
+		if len(rule.RequiredPredicates) > 0 {
+			fmt.Fprintf(out, "        Statements of the types %s must be attested to.\n", common.JoinList(rule.RequiredPredicates, "and"))
+		}
+		if rule.RequiredApprovals != nil {
+			fmt.Fprintf(out, "        %d principals of rule '%s' must approve in code review.\n", rule.RequiredApprovals.Threshold, rule.RequiredApprovals.Role)
+		}
+		if rule.RequireVerifiedSubmodule {
+			fmt.Fprintln(out, "        Submodules must be updated to commits verified by their own gittuf policy.")
+		}
+		if len(rule.AllowedSignatureMethods) > 0 {
+			methods := make([]string, 0, len(rule.AllowedSignatureMethods))
+			for _, method := range rule.AllowedSignatureMethods {
+				methods = append(methods, policy.FormatSignatureMethod(method))
+			}
+			fmt.Fprintf(out, "        Git signatures must be made using %s.\n", common.JoinList(methods, "or"))
+		}
+		if _, has := allTargetsMetadata[rule.Name]; has {
+			fmt.Fprintf(out, "        Policy file '%s' further restricts these changes.\n", rule.Name)
+		}
+	}
+
+	for _, predicatePolicy := range targetsMetadata.PredicatePolicies {
+		fmt.Fprintf(out, "    %s may issue '%s' attestations.\n", labels.DescribePrincipals(predicatePolicy.KeyIDs, predicatePolicy.Threshold), predicatePolicy.PredicateType)
+	}
+}
+
+// describePatterns returns what a rule's patterns allow, such as "update
+// refs/heads/main and modify files matching src/*".
+func describePatterns(patterns []string) string {
+	descriptions := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		switch {
+		case strings.HasPrefix(pattern, gitRuleScheme):
+			descriptions = append(descriptions, "update "+strings.TrimPrefix(pattern, gitRuleScheme))
+		case strings.HasPrefix(pattern, fileRuleScheme):
+			descriptions = append(descriptions, "modify files matching "+strings.TrimPrefix(pattern, fileRuleScheme))
+		case strings.HasPrefix(pattern, submoduleRuleScheme):
+			descriptions = append(descriptions, "update submodules matching "+strings.TrimPrefix(pattern, submoduleRuleScheme))
+		default:
+			descriptions = append(descriptions, "change "+pattern)
+		}
+	}
+
+	return common.JoinList(descriptions, "and")
+}
+
+// formatExpiry returns the date of the expiry timestamp, or the timestamp as
+// is if it can't be parsed.
+func formatExpiry(expires string) string {
+	expiry, err := time.Parse(time.RFC3339, expires)
+	if err != nil {
+		return expires
+	}
+
+	return expiry.Format(time.DateOnly)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "show",
+		Short:             "Show the metadata of the policy",
+		Long:              "This command allows users to inspect the root of trust and the policy files of the policy. By default, the metadata is printed as JSON. With --human, the policy is described in sentences, such as \"Alice or Bob may update refs/heads/main\", which is helpful when reviewing the policy with people unfamiliar with gittuf's metadata.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package wizard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/interactive"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/spf13/cobra"
+)
+
+const gitRuleScheme = "git:"
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file to add rules to",
+	)
+
+	cmd.RegisterFlagCompletionFunc("policy-name", common.CompletePolicyNames) //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if interactive.InNonInteractiveMode() {
+		return interactive.ErrInteractionRequired
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+	prompter := common.NewPrompter(cmd.InOrStdin(), out)
+
+	if err := repo.InitializeTargets(ctx, signer, o.policyName, true); err == nil {
+		fmt.Fprintf(out, "Initialized policy file '%s'.\n", o.policyName)
+	} else if !errors.Is(err, repository.ErrCannotReinitialize) {
+		return err
+	}
+
+	for {
+		ruleName, authorizedKeys, rulePatterns, threshold, err := askRule(prompter, out)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.AddDelegation(ctx, signer, o.policyName, ruleName, authorizedKeys, rulePatterns, threshold, true); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Added rule '%s' protecting %s.\n", ruleName, strings.Join(rulePatterns, ", "))
+
+		another, err := prompter.Confirm("Add another rule?", false)
+		if err != nil {
+			return err
+		}
+		if !another {
+			break
+		}
+	}
+
+	apply, err := prompter.Confirm("Apply the policy now?", true)
+	if err != nil {
+		return err
+	}
+	if !apply {
+		fmt.Fprintln(out, "The rules are staged, run 'gittuf policy apply' to apply them.")
+		return nil
+	}
+
+	return applyPolicy(cmd, repo, signer, prompter)
+}
+
+// askRule asks the user for the namespaces to protect, the keys of the
+// maintainers who may change them, and the number of maintainers required to
+// approve a change.
+func askRule(prompter *common.Prompter, out io.Writer) (string, []*tuf.Key, []string, int, error) {
+	ruleName, err := prompter.AskRequired("Rule name")
+	if err != nil {
+		return "", nil, nil, 0, err
+	}
+
+	rulePatterns := []string{}
+	for len(rulePatterns) == 0 {
+		branches, err := prompter.AskList("Branches to protect (comma separated, e.g. main, release/*)")
+		if err != nil {
+			return "", nil, nil, 0, err
+		}
+		for _, branch := range branches {
+			rulePatterns = append(rulePatterns, rulePattern(branch, gitinterface.BranchRefPrefix))
+		}
+
+		tags, err := prompter.AskList("Tags to protect (comma separated, e.g. v*)")
+		if err != nil {
+			return "", nil, nil, 0, err
+		}
+		for _, tag := range tags {
+			rulePatterns = append(rulePatterns, rulePattern(tag, gitinterface.TagRefPrefix))
+		}
+
+		if len(rulePatterns) == 0 {
+			fmt.Fprintln(out, "Please specify at least one branch or tag.")
+		}
+	}
+
+	authorizedKeys := []*tuf.Key{}
+	for len(authorizedKeys) == 0 {
+		keyPaths, err := prompter.AskList(`Maintainer keys (comma separated paths to public keys, "gpg:<fingerprint>", or "fulcio:<identity>::<issuer>")`)
+		if err != nil {
+			return "", nil, nil, 0, err
+		}
+
+		for _, keyPath := range keyPaths {
+			key, err := common.LoadPublicKey(keyPath)
+			if err != nil {
+				fmt.Fprintf(out, "Unable to load key '%s': %s\n", keyPath, err.Error())
+				authorizedKeys = []*tuf.Key{}
+				break
+			}
+			authorizedKeys = append(authorizedKeys, key)
+		}
+
+		if len(keyPaths) == 0 {
+			fmt.Fprintln(out, "Please specify at least one maintainer key.")
+		}
+	}
+
+	threshold := 1
+	if len(authorizedKeys) > 1 {
+		threshold, err = prompter.AskInt("Number of maintainers required to approve a change", 1, 1, len(authorizedKeys))
+		if err != nil {
+			return "", nil, nil, 0, err
+		}
+	}
+
+	return ruleName, authorizedKeys, rulePatterns, threshold, nil
+}
+
+// rulePattern returns the rule pattern for the branch or tag. Fully qualified
+// refs and patterns that already use the rule scheme are used as is.
+func rulePattern(name, refPrefix string) string {
+	switch {
+	case strings.HasPrefix(name, gitRuleScheme):
+		return name
+	case strings.HasPrefix(name, "refs/"):
+		return gitRuleScheme + name
+	default:
+		return gitRuleScheme + refPrefix + name
+	}
+}
+
+// applyPolicy applies the staged policy. If the root of trust requires policy
+// changes to be justified, the user is asked for a justification first.
+func applyPolicy(cmd *cobra.Command, repo *repository.Repository, signer sslibdsse.SignerVerifier, prompter *common.Prompter) error {
+	ctx := cmd.Context()
+
+	err := repo.ApplyPolicy(ctx, true)
+	if !errors.Is(err, policy.ErrPolicyJustificationMissing) {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "This repository requires policy changes to be justified.")
+	reason, err := prompter.AskRequired("Reason for the change")
+	if err != nil {
+		return err
+	}
+	ticket, err := prompter.Ask("Link to the ticket tracking the change (optional)", "")
+	if err != nil {
+		return err
+	}
+	approver, err := prompter.Ask("Person who approved the change (optional)", "")
+	if err != nil {
+		return err
+	}
+
+	if err := repo.AddPolicyJustification(ctx, signer, reason, ticket, approver, true); err != nil {
+		return err
+	}
+
+	return repo.ApplyPolicy(ctx, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "wizard",
+		Short:             "Interactively create rules to protect branches and tags",
+		Long:              `This command allows users to set up policy interactively. It asks which branches and tags to protect, the keys of the maintainers who may change them, and how many maintainers must approve a change. The policy file is initialized if needed, and the resulting rules are signed, staged, and optionally applied. Maintainer keys can be specified from disk, from the GPG keyring using the "gpg:<fingerprint>" format, or as a Sigstore identity as "fulcio:<identity>::<issuer>".`,
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
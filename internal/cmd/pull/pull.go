@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pull
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	return repo.Pull(cmd.Context(), args[0], args[1:])
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "pull <remote> [<ref>...]",
+		Short:             "Pull refs and the RSL from the specified remote and verify them",
+		Long:              `This command allows users to pull refs and the RSL from a remote, verifying the new states of the refs against the repository's policy. If verification fails, the refs and the RSL are restored to their prior states. If no refs are specified, the checked out branch is pulled, and the working tree is updated. Only fast-forward updates are pulled, and the working tree must not have uncommitted changes when the checked out branch is updated.`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: common.CompleteRemoteAndRefs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package push
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	return repo.Push(cmd.Context(), args[0], args[1:], true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "push <remote> [<ref>...]",
+		Short:             "Push refs to the specified remote, recording their states in the RSL",
+		Long:              `This command allows users to push refs to a remote together with RSL entries recording their new states, so that the RSL doesn't have to be updated and pushed separately. If no refs are specified, the checked out branch is pushed. The remote's RSL is pulled before the entries are recorded. If someone else updates the remote's RSL before the push completes, the entries are recorded again on top of the remote's RSL and the push is retried.`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: common.CompleteRemoteAndRefs,
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package recordservice
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/recordservice"
+	"github.com/spf13/cobra"
+)
+
+// logger logs the messages of the recordservice subsystem.
+var logger = logging.For("recordservice")
+
+const (
+	// tokenKey is the environment variable that contains the access token,
+	// so that it isn't exposed in the command line.
+	tokenKey = "GITTUF_RECORD_SERVICE_TOKEN" //nolint:gosec
+
+	// webhookSecretKey is the environment variable that contains the secret
+	// of the repositories' webhooks.
+	webhookSecretKey = "GITTUF_RECORD_SERVICE_WEBHOOK_SECRET" //nolint:gosec
+)
+
+type options struct {
+	address    string
+	cacheDir   string
+	signingKey string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.address,
+		"address",
+		":8080",
+		"address to listen for webhook deliveries on",
+	)
+
+	cmd.Flags().StringVar(
+		&o.cacheDir,
+		"cache-dir",
+		"",
+		"directory to mirror repositories to (default is gittuf/record-service in the user's cache directory)",
+	)
+
+	cmd.Flags().StringVarP(
+		&o.signingKey,
+		"signing-key",
+		"k",
+		"",
+		"signing key to sign push event attestations recording the platform and account that made each push",
+	)
+}
+
+func (o *options) Run(_ *cobra.Command, _ []string) error {
+	cacheDir := o.cacheDir
+	if cacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		cacheDir = filepath.Join(userCacheDir, "gittuf", "record-service")
+	}
+
+	config := &recordservice.Config{
+		Token:         os.Getenv(tokenKey),
+		WebhookSecret: []byte(os.Getenv(webhookSecretKey)),
+		CacheDir:      cacheDir,
+	}
+
+	if o.signingKey != "" {
+		signer, err := common.LoadSignerForKey(o.signingKey)
+		if err != nil {
+			return err
+		}
+		config.Signer = signer
+	}
+
+	service, err := recordservice.NewService(config)
+	if err != nil {
+		return fmt.Errorf("%w, set %s and %s", err, tokenKey, webhookSecretKey)
+	}
+
+	logger.Info("Listening for webhook deliveries", "address", o.address)
+	return http.ListenAndServe(o.address, service) //nolint:gosec
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "record-service",
+		Short:             "Run a service that records RSL entries for pushes made on hosting platforms",
+		Long:              fmt.Sprintf(`This command allows users to run a service that records RSL entries for pushes that aren't made by gittuf clients, such as commits created using a hosting platform's UI or merges made by the platform, so that they don't leave gaps in the RSL. The service receives push webhooks from GitHub, GitLab, Gitea, and Forgejo. For every push, it fetches the repository's refs and RSL, records an RSL entry for the pushed ref unless the RSL already records it, and pushes the RSL back to the repository. The entries are signed using the Git signing configuration of the service, which should be a bot identity authorized in the repository's policy. If --signing-key is set, the service also records push event attestations that identify the platform and the account that made each push. The service authenticates using an access token with write access to the repositories set in the %s environment variable, and accepts webhooks signed with the secret set in the %s environment variable.`, tokenKey, webhookSecretKey),
+		Args:              cobra.NoArgs,
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
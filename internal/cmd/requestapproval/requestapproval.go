@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package requestapproval
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	expiresIn time.Duration
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(
+		&o.expiresIn,
+		"expires-in",
+		0,
+		"duration after which the approval can no longer be used, e.g. 336h for 14 days, which is only honored for RSL entries with a trusted timestamp (only applies when the authorization is created)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	fromID, toID, err := repo.RequestApproval(cmd.Context(), args[0], args[1], o.expiresIn, true)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Requested approval for '%s' from '%s' to '%s'\n", args[0], fromID, toID)
+	fmt.Printf("Approvers can run 'gittuf approve %s %s' to approve the change\n", args[0], args[1])
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "request-approval <ref> <target>",
+		Short:             "Request approval for a proposed change to a Git reference",
+		Long:              "This command allows users to request approval for merging the changes in the target ref into the specified ref, or for creating the specified tag (refs/tags/<name>) pointing to the target revision. The request is recorded as an unsigned reference authorization attestation that approvers can sign using 'gittuf approve'.",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRefs, common.CompleteRefs),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -3,28 +3,75 @@
 package root
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 
 	"github.com/gittuf/gittuf/internal/cmd/addhooks"
+	"github.com/gittuf/gittuf/internal/cmd/approve"
+	"github.com/gittuf/gittuf/internal/cmd/attest"
+	"github.com/gittuf/gittuf/internal/cmd/bitbucket"
 	"github.com/gittuf/gittuf/internal/cmd/clone"
+	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/cmd/dev"
+	"github.com/gittuf/gittuf/internal/cmd/doctor"
+	"github.com/gittuf/gittuf/internal/cmd/gerritsubmit"
+	"github.com/gittuf/gittuf/internal/cmd/giteaservice"
+	"github.com/gittuf/gittuf/internal/cmd/githubapp"
+	"github.com/gittuf/gittuf/internal/cmd/gitlabservice"
+	"github.com/gittuf/gittuf/internal/cmd/gitopscheck"
+	"github.com/gittuf/gittuf/internal/cmd/log"
+	"github.com/gittuf/gittuf/internal/cmd/monitor"
 	"github.com/gittuf/gittuf/internal/cmd/policy"
 	"github.com/gittuf/gittuf/internal/cmd/profile"
+	"github.com/gittuf/gittuf/internal/cmd/pull"
+	"github.com/gittuf/gittuf/internal/cmd/push"
+	"github.com/gittuf/gittuf/internal/cmd/recordservice"
+	"github.com/gittuf/gittuf/internal/cmd/requestapproval"
 	"github.com/gittuf/gittuf/internal/cmd/rsl"
+	"github.com/gittuf/gittuf/internal/cmd/serve"
+	"github.com/gittuf/gittuf/internal/cmd/status"
 	"github.com/gittuf/gittuf/internal/cmd/trust"
+	"github.com/gittuf/gittuf/internal/cmd/ui"
 	"github.com/gittuf/gittuf/internal/cmd/verifycommit"
+	"github.com/gittuf/gittuf/internal/cmd/verifymirrors"
+	"github.com/gittuf/gittuf/internal/cmd/verifypush"
 	"github.com/gittuf/gittuf/internal/cmd/verifyref"
+	"github.com/gittuf/gittuf/internal/cmd/verifyrelease"
 	"github.com/gittuf/gittuf/internal/cmd/verifytag"
 	"github.com/gittuf/gittuf/internal/cmd/version"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/interactive"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/passphrase"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/timestamp"
+	"github.com/gittuf/gittuf/internal/timing"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
-	verbose           bool
-	profile           bool
-	cpuProfileFile    string
-	memoryProfileFile string
+	verbose            bool
+	logLevel           string
+	logFormat          string
+	logSubsystems      []string
+	profile            bool
+	cpuProfileFile     string
+	memoryProfileFile  string
+	profileTiming      bool
+	color              string
+	signingProfile     string
+	nonInteractive     bool
+	offline            bool
+	trustedRootPath    string
+	sigstoreInstance   gitinterface.SigstoreInstance
+	smimeTrustStore    string
+	signing            string
+	signatureCache     bool
+	timestampAuthority string
+	timestampRekorURL  string
+	passphraseFile     string
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -35,9 +82,30 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"enable verbose logging",
 	)
 
+	cmd.PersistentFlags().StringVar(
+		&o.logLevel,
+		"log-level",
+		"info",
+		"minimum level of log messages, one of 'debug', 'info', 'warn', or 'error'",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.logFormat,
+		"log-format",
+		logging.FormatText,
+		fmt.Sprintf("format of log messages, one of '%s' or '%s'", logging.FormatText, logging.FormatJSON),
+	)
+
+	cmd.PersistentFlags().StringArrayVar(
+		&o.logSubsystems,
+		"log-subsystem",
+		[]string{},
+		"override the log level of a subsystem, in the form <subsystem>=<level> (subsystems: cmd, credentials, giteaservice, githubapp, gitinterface, gitlabservice, mirrorverifier, monitor, policy, recordservice, remotehelper, repository, sshshell, verifyservice, webhook)",
+	)
+
 	cmd.PersistentFlags().BoolVar(
 		&o.profile,
-		"profile",
+		"profile-pprof",
 		false,
 		"enable CPU and memory profiling",
 	)
@@ -55,19 +123,222 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"memory.prof",
 		"file to store memory profile",
 	)
+
+	cmd.PersistentFlags().BoolVar(
+		&o.profileTiming,
+		"profile-timing",
+		false,
+		"report the time spent in each phase, such as walking the RSL and verifying signatures",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.color,
+		"color",
+		common.ColorAuto,
+		fmt.Sprintf("color verification results, one of '%s', '%s', or '%s'", common.ColorAuto, common.ColorAlways, common.ColorNever),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.signingProfile,
+		"profile",
+		"",
+		"signing profile from the gittuf config to use instead of the default profile",
+	)
+
+	// --signing-profile is the previous name of --profile
+	cmd.PersistentFlags().StringVar(
+		&o.signingProfile,
+		"signing-profile",
+		"",
+		"signing profile from the gittuf config to use instead of the default profile",
+	)
+	cmd.PersistentFlags().MarkHidden("signing-profile") //nolint:errcheck
+
+	cmd.PersistentFlags().BoolVar(
+		&o.nonInteractive,
+		"non-interactive",
+		false,
+		fmt.Sprintf("never prompt, failing with exit code %d instead (can also be enabled by setting %s=1)", interactive.ExitCode, interactive.NonInteractiveModeKey),
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&o.offline,
+		"offline",
+		false,
+		"verify Sigstore signatures without network access, using the Sigstore trusted root cached by Sigstore's TUF client",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.trustedRootPath,
+		"sigstore-trusted-root",
+		"",
+		"path to a Sigstore trusted root (trusted_root.json) to verify Sigstore signatures offline with",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.sigstoreInstance.RekorURL,
+		"sigstore-rekor-url",
+		"",
+		"URL of the Rekor instance used to verify gitsign signatures and to log signatures created by the Sigstore signing backend (defaults to Git's gittuf.rekorURL option or Sigstore's public instance)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.sigstoreInstance.FulcioURL,
+		"sigstore-fulcio-url",
+		"",
+		"URL of the Fulcio instance that issues signing certificates for keyless signing (defaults to Git's gittuf.fulcioURL option or Sigstore's public instance)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.sigstoreInstance.FulcioRoots,
+		"sigstore-fulcio-roots",
+		"",
+		"path to a PEM file with the root and intermediate certificates of the Fulcio instance trusted to verify gitsign signatures (defaults to Git's gittuf.fulcioRoots option or the certificates in Sigstore's TUF repository)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.sigstoreInstance.TUFMirror,
+		"sigstore-tuf-mirror",
+		"",
+		"URL of the TUF repository to obtain the Sigstore trusted root from, such as that of a private Sigstore deployment (defaults to Git's gittuf.sigstoreTUFMirror option)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.sigstoreInstance.TUFRoot,
+		"sigstore-tuf-root",
+		"",
+		"path to the initial root.json trusted for the TUF repository set with --sigstore-tuf-mirror (defaults to Git's gittuf.sigstoreTUFRoot option)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.smimeTrustStore,
+		"smime-trust-store",
+		"",
+		"path to a PEM file or a directory of PEM files with the root certificates trusted to verify S/MIME signatures, which is required to verify them as the system's roots aren't trusted; certificates are checked at the signature's trusted timestamp, or at the current time",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.signing,
+		"signing",
+		"",
+		fmt.Sprintf("backend used to sign RSL entries and attestations, one of '%s' or '%s', which signs using the CI environment's OIDC identity and logs signatures to Rekor (defaults to Git's gittuf.signing option)", gitinterface.SigningBackendGit, gitinterface.SigningBackendSigstore),
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&o.signatureCache,
+		"signature-cache",
+		false,
+		"reuse and record the results of verifying GPG and SSH signatures in the repository's signature cache",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.timestampAuthority,
+		"timestamp-authority",
+		"",
+		"URL of an RFC 3161 timestamp authority to timestamp the signatures of RSL entries and policy metadata with, so that keys with validity windows can be verified",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.timestampRekorURL,
+		"timestamp-rekor-url",
+		"",
+		"URL of a Rekor instance to timestamp the signatures of RSL entries and policy metadata with by logging them, if no timestamp authority is set",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.passphraseFile,
+		"passphrase-file",
+		"",
+		fmt.Sprintf("file to read the passphrase of encrypted signing keys from instead of prompting for it (can also be set using %s)", passphrase.EnvKey),
+	)
 }
 
-func (o *options) PreRunE(_ *cobra.Command, _ []string) error {
+func (o *options) PreRunE(cmd *cobra.Command, _ []string) error {
+	if o.profileTiming {
+		timing.Enable()
+	}
+
 	// Setup logging
-	level := slog.LevelInfo
+	level, err := logging.ParseLevel(o.logLevel)
+	if err != nil {
+		return err
+	}
 
 	if o.verbose {
 		level = slog.LevelDebug
 	}
 
-	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: level,
-	})))
+	subsystemLevels, err := logging.ParseSubsystemLevels(o.logSubsystems)
+	if err != nil {
+		return err
+	}
+
+	if err := logging.Setup(os.Stderr, logging.Options{
+		Level:           level,
+		Format:          o.logFormat,
+		SubsystemLevels: subsystemLevels,
+	}); err != nil {
+		return err
+	}
+
+	if o.nonInteractive {
+		interactive.SetNonInteractive(true)
+	}
+
+	// Apply the user's defaults before the flags are used
+	config, err := common.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if err := common.ApplyConfig(cmd, config, o.signingProfile); err != nil {
+		return err
+	}
+
+	gitinterface.SetSigstoreInstance(o.sigstoreInstance)
+
+	if o.signing != "" {
+		if err := gitinterface.SetSigningBackend(o.signing); err != nil {
+			return err
+		}
+	}
+	signingBackend, err := gitinterface.GetSigningBackend()
+	if err != nil {
+		return err
+	}
+	if err := common.ApplySigningBackend(cmd, signingBackend); err != nil {
+		return err
+	}
+
+	if err := common.SetColorMode(o.color); err != nil {
+		return err
+	}
+
+	if o.offline || o.trustedRootPath != "" {
+		gitinterface.SetSigstoreTrustedRoot(o.trustedRootPath)
+	}
+
+	if o.smimeTrustStore != "" {
+		gitinterface.SetSMIMETrustStore(o.smimeTrustStore)
+	}
+
+	if o.passphraseFile != "" {
+		passphrase.SetFile(o.passphraseFile)
+	}
+
+	switch {
+	case o.timestampAuthority != "":
+		timestamp.SetTimestamper(timestamp.NewAuthorityClient(o.timestampAuthority))
+	case o.timestampRekorURL != "":
+		timestamp.SetTimestamper(timestamp.NewRekorTimestamper(rekor.NewClient(o.timestampRekorURL)))
+	}
+
+	if o.signatureCache {
+		// The cache isn't authenticated, so it's only used when requested.
+		// Commands run outside a repository don't verify its signatures
+		if repo, err := gitinterface.LoadRepository(); err == nil {
+			gitinterface.SetSignatureCacheDir(filepath.Join(repo.GetGitCommonDir(), "gittuf", "cache"))
+		}
+	}
 
 	// Start profiling if flag is set
 	if o.profile {
@@ -83,6 +354,7 @@ func New() *cobra.Command {
 		Use:               "gittuf",
 		Short:             "A security layer for Git repositories, powered by TUF",
 		SilenceUsage:      true,
+		SilenceErrors:     true,
 		DisableAutoGenTag: true,
 		PersistentPreRunE: o.PreRunE,
 	}
@@ -90,13 +362,34 @@ func New() *cobra.Command {
 	o.AddFlags(cmd)
 
 	cmd.AddCommand(addhooks.New())
+	cmd.AddCommand(approve.New())
+	cmd.AddCommand(attest.New())
+	cmd.AddCommand(bitbucket.New())
 	cmd.AddCommand(clone.New())
 	cmd.AddCommand(dev.New())
+	cmd.AddCommand(doctor.New())
+	cmd.AddCommand(gerritsubmit.New())
+	cmd.AddCommand(giteaservice.New())
+	cmd.AddCommand(githubapp.New())
+	cmd.AddCommand(gitlabservice.New())
+	cmd.AddCommand(gitopscheck.New())
+	cmd.AddCommand(log.New())
+	cmd.AddCommand(monitor.New())
 	cmd.AddCommand(trust.New())
 	cmd.AddCommand(policy.New())
+	cmd.AddCommand(pull.New())
+	cmd.AddCommand(push.New())
+	cmd.AddCommand(recordservice.New())
+	cmd.AddCommand(requestapproval.New())
 	cmd.AddCommand(rsl.New())
+	cmd.AddCommand(serve.New())
+	cmd.AddCommand(status.New())
+	cmd.AddCommand(ui.New())
 	cmd.AddCommand(verifycommit.New())
+	cmd.AddCommand(verifymirrors.New())
+	cmd.AddCommand(verifypush.New())
 	cmd.AddCommand(verifyref.New())
+	cmd.AddCommand(verifyrelease.New())
 	cmd.AddCommand(verifytag.New())
 	cmd.AddCommand(version.New())
 
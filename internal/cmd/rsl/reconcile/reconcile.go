@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reconcile
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	format string
+}
+
+type reconcileOutput struct {
+	Remote      string           `json:"remote"`
+	Status      string           `json:"status"`
+	PreviousTip string           `json:"previous_tip"`
+	RemoteTip   string           `json:"remote_tip"`
+	Replayed    []replayedOutput `json:"replayed"`
+	Conflicts   []conflictOutput `json:"conflicts"`
+}
+
+type replayedOutput struct {
+	OriginalID string `json:"original_id"`
+	NewID      string `json:"new_id"`
+	RefName    string `json:"ref,omitempty"`
+}
+
+type conflictOutput struct {
+	EntryID string `json:"entry_id"`
+	RefName string `json:"ref,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	common.AddFormatFlag(cmd, &o.format)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	reconciliation, err := repo.ReconcileRSL(cmd.Context(), args[0], true)
+	if err != nil {
+		return err
+	}
+
+	if o.format == common.FormatJSON {
+		output := &reconcileOutput{
+			Remote:      reconciliation.Remote,
+			Status:      string(reconciliation.Status),
+			PreviousTip: reconciliation.PreviousTip.String(),
+			RemoteTip:   reconciliation.RemoteTip.String(),
+			Replayed:    []replayedOutput{},
+			Conflicts:   []conflictOutput{},
+		}
+		for _, replayed := range reconciliation.Replayed {
+			output.Replayed = append(output.Replayed, replayedOutput{OriginalID: replayed.OriginalID.String(), NewID: replayed.NewID.String(), RefName: replayed.RefName})
+		}
+		for _, conflict := range reconciliation.Conflicts {
+			output.Conflicts = append(output.Conflicts, conflictOutput{EntryID: conflict.EntryID.String(), RefName: conflict.RefName, Reason: conflict.Reason.Error()})
+		}
+		if err := common.PrintJSON(output); err != nil {
+			return err
+		}
+	} else {
+		printReconciliation(reconciliation)
+	}
+
+	if len(reconciliation.Conflicts) > 0 {
+		return repository.ErrRSLConflicts
+	}
+
+	return nil
+}
+
+func printReconciliation(reconciliation *repository.RSLReconciliation) {
+	switch reconciliation.Status {
+	case repository.RSLSyncStatusUpToDate:
+		fmt.Printf("Local RSL is up to date with remote %s\n", reconciliation.Remote)
+		return
+	case repository.RSLSyncStatusAhead:
+		fmt.Printf("Local RSL is ahead of remote %s, push it to update the remote\n", reconciliation.Remote)
+		return
+	case repository.RSLSyncStatusBehind:
+		fmt.Printf("Local RSL was fast-forwarded to remote %s at %s\n", reconciliation.Remote, reconciliation.RemoteTip.String())
+		return
+	}
+
+	fmt.Printf("Local RSL had diverged from remote %s and was reset to %s\n", reconciliation.Remote, reconciliation.RemoteTip.String())
+	fmt.Printf("The local entries remain reachable from the previous RSL tip %s\n", reconciliation.PreviousTip.String())
+
+	for _, replayed := range reconciliation.Replayed {
+		if replayed.RefName == "" {
+			fmt.Printf("Replayed annotation %s as %s\n", replayed.OriginalID.String(), replayed.NewID.String())
+		} else {
+			fmt.Printf("Replayed entry %s for '%s' as %s\n", replayed.OriginalID.String(), replayed.RefName, replayed.NewID.String())
+		}
+	}
+
+	for _, conflict := range reconciliation.Conflicts {
+		if conflict.RefName == "" {
+			fmt.Printf("Conflict: annotation %s was not replayed: %s\n", conflict.EntryID.String(), conflict.Reason.Error())
+		} else {
+			fmt.Printf("Conflict: entry %s for '%s' was not replayed: %s\n", conflict.EntryID.String(), conflict.RefName, conflict.Reason.Error())
+		}
+	}
+
+	if len(reconciliation.Conflicts) == 0 {
+		fmt.Println("Push the RSL to publish the replayed entries")
+	}
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "reconcile <remote>",
+		Short:             "Reconcile the local RSL with a remote's diverged RSL",
+		Long:              "This command fetches the remote's RSL and reconciles the local RSL with it. If the remote's RSL is ahead, the local RSL is fast-forwarded. If the two have diverged, such as when multiple developers push concurrently, the local RSL is reset to the remote's and the local entries the remote doesn't have are recorded again on top of it, signed using the user's key. An entry is not recorded again if the remote's RSL also records changes to the same ref, or if the new entry doesn't meet the policy. Such entries are reported as conflicts that must be resolved manually, for example by integrating the remote's changes to the ref and recording the result, and the command fails. The reconciled RSL is not pushed.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRemotes),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -27,6 +27,7 @@ func New() *cobra.Command {
 		Use:               "record",
 		Short:             "Record latest state of a Git reference in the RSL",
 		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRefs),
 		PreRunE:           common.CheckIfSigningViable,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
@@ -5,14 +5,30 @@ package check
 import (
 	"fmt"
 
+	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
+	format string
+}
+
+type checkOutput struct {
+	Remote      string `json:"remote"`
+	HasUpdates  bool   `json:"has_updates"`
+	HasDiverged bool   `json:"has_diverged"`
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	common.AddFormatFlag(cmd, &o.format)
 }
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
@@ -23,6 +39,10 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if o.format == common.FormatJSON {
+		return common.PrintJSON(&checkOutput{Remote: args[0], HasUpdates: hasUpdates, HasDiverged: hasDiverged})
+	}
+
 	if hasUpdates {
 		fmt.Printf("RSL at remote %s has updates", args[0])
 		if hasDiverged {
@@ -43,9 +63,11 @@ func New() *cobra.Command {
 		Use:               "check <remote>",
 		Short:             "Check remote RSL for updates, for development use only",
 		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRemotes),
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
+	o.AddFlags(cmd)
 
 	return cmd
 }
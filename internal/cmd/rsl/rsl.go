@@ -4,6 +4,7 @@ package rsl
 
 import (
 	"github.com/gittuf/gittuf/internal/cmd/rsl/annotate"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/reconcile"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/record"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/remote"
 	"github.com/spf13/cobra"
@@ -17,6 +18,7 @@ func New() *cobra.Command {
 	}
 
 	cmd.AddCommand(annotate.New())
+	cmd.AddCommand(reconcile.New())
 	cmd.AddCommand(record.New())
 	cmd.AddCommand(remote.New())
 
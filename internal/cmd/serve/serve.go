@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/gittuf/gittuf/internal/verifyservice"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// logger logs the messages of the verifyservice subsystem.
+var logger = logging.For("verifyservice")
+
+// tokenKey is the environment variable that contains the bearer token clients
+// must present, so that it isn't exposed in the command line.
+const tokenKey = "GITTUF_SERVE_TOKEN" //nolint:gosec
+
+var ErrIncompleteTLSConfig = errors.New("both --tls-cert and --tls-key must be set to serve over TLS")
+
+type options struct {
+	address      string
+	grpcAddress  string
+	repositories []string
+	cacheDir     string
+	tlsCert      string
+	tlsKey       string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.address,
+		"address",
+		":8080",
+		"address to listen for API requests on",
+	)
+
+	cmd.Flags().StringVar(
+		&o.grpcAddress,
+		"grpc-address",
+		"",
+		"address to listen for gRPC API requests on (the gRPC API is disabled if not set)",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.repositories,
+		"repository",
+		nil,
+		"repository to serve, specified as <name>=<path or URL>",
+	)
+	cmd.MarkFlagRequired("repository") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.cacheDir,
+		"cache-dir",
+		"",
+		"directory to mirror remote repositories to for verification (default is gittuf/serve in the user's cache directory)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.tlsCert,
+		"tls-cert",
+		"",
+		"path to PEM encoded certificate to serve the APIs over TLS with, as required for Kubernetes admission webhooks",
+	)
+
+	cmd.Flags().StringVar(
+		&o.tlsKey,
+		"tls-key",
+		"",
+		"path to PEM encoded private key of the TLS certificate",
+	)
+}
+
+func (o *options) Run(_ *cobra.Command, _ []string) error {
+	if (o.tlsCert == "") != (o.tlsKey == "") {
+		return ErrIncompleteTLSConfig
+	}
+
+	cacheDir := o.cacheDir
+	if cacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		cacheDir = filepath.Join(userCacheDir, "gittuf", "serve")
+	}
+
+	repositories := make([]*verifyservice.Repository, 0, len(o.repositories))
+	for _, spec := range o.repositories {
+		repo, err := verifyservice.ParseRepository(spec)
+		if err != nil {
+			return err
+		}
+		repositories = append(repositories, repo)
+	}
+
+	token := os.Getenv(tokenKey)
+	registry := metrics.NewRegistry()
+	service, err := verifyservice.NewService(&verifyservice.Config{
+		Repositories: repositories,
+		Token:        token,
+		CacheDir:     cacheDir,
+		Metrics:      registry,
+	})
+	if err != nil {
+		return err
+	}
+
+	if token == "" {
+		logger.Warn(fmt.Sprintf("%s is not set, API requests will not be authenticated", tokenKey))
+	}
+	errs := make(chan error, 2)
+
+	if o.grpcAddress != "" {
+		listener, err := net.Listen("tcp", o.grpcAddress)
+		if err != nil {
+			return err
+		}
+
+		serverOptions := []grpc.ServerOption{}
+		if o.tlsCert != "" {
+			transportCredentials, err := credentials.NewServerTLSFromFile(o.tlsCert, o.tlsKey)
+			if err != nil {
+				return err
+			}
+			serverOptions = append(serverOptions, grpc.Creds(transportCredentials))
+		}
+
+		logger.Info("Listening for gRPC API requests", "address", o.grpcAddress)
+		go func() {
+			errs <- service.NewGRPCServer(serverOptions...).Serve(listener)
+		}()
+	}
+
+	handler := metrics.Handler(registry, metrics.NewHealth(), service)
+	logger.Info("Listening for API requests", "address", o.address)
+	go func() {
+		if o.tlsCert != "" {
+			errs <- http.ListenAndServeTLS(o.address, o.tlsCert, o.tlsKey, handler) //nolint:gosec
+			return
+		}
+		errs <- http.ListenAndServe(o.address, handler) //nolint:gosec
+	}()
+
+	return <-errs
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "serve",
+		Short:             "Run an HTTP API that verifies repositories against gittuf policy on demand",
+		Long:              fmt.Sprintf(`This command allows users to run an HTTP API that other systems, such as deployment pipelines and dashboards, can use to consume gittuf verification results without invoking gittuf directly. Only the repositories specified using --repository can be verified; each is given a name used in the API and either a path on disk or the URL of a remote repository, which is mirrored before each request. The API verifies a ref on demand and returns a report of the result (POST /v1/repositories/{name}/verify), returns the roles and rules of a repository's policy (GET /v1/repositories/{name}/policy), and retrieves the reports of earlier verifications (GET /v1/reports and GET /v1/reports/{id}). If --grpc-address is set, the versioned gRPC API gittuf.v1.VerificationService is also served, which verifies refs, returns policies, and lists a repository's RSL entries and attestations. The API also reviews Kubernetes admissions (POST /v1/admission), so that a validating admission webhook can require objects to be annotated with a gittuf-verified source. If the %s environment variable is set, clients must present its value as a bearer token. Prometheus metrics describing the verifications performed, such as their results, failure reasons, and durations, are served at /metrics, along with the /healthz and /readyz health endpoints; these endpoints are not authenticated.`, tokenKey),
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
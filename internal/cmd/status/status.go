@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+// expiryWarningPeriod is how long before the policy expires users are warned
+// about it.
+const expiryWarningPeriod = 30 * 24 * time.Hour
+
+type options struct {
+	remote string
+	fetch  bool
+	format string
+}
+
+type statusOutput struct {
+	Enabled                bool     `json:"enabled"`
+	RootExpires            string   `json:"root_expires,omitempty"`
+	PolicyExpires          string   `json:"policy_expires,omitempty"`
+	HasStagedPolicyChanges bool     `json:"has_staged_policy_changes"`
+	RSLTip                 string   `json:"rsl_tip,omitempty"`
+	Remote                 string   `json:"remote"`
+	RemoteRSLTip           string   `json:"remote_rsl_tip,omitempty"`
+	RSLSync                string   `json:"rsl_sync"`
+	UnrecordedRefs         []string `json:"unrecorded_refs"`
+	SigningProgram         string   `json:"signing_program,omitempty"`
+	SigningError           string   `json:"signing_error,omitempty"`
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.remote,
+		"remote",
+		"origin",
+		"remote to compare the RSL with",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.fetch,
+		"fetch",
+		false,
+		"fetch the remote's RSL before comparing",
+	)
+
+	common.AddFormatFlag(cmd, &o.format)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	if o.fetch {
+		if _, _, err := repo.CheckRemoteRSLForUpdates(cmd.Context(), o.remote); err != nil {
+			return err
+		}
+	}
+
+	status, err := repo.Status(cmd.Context(), o.remote)
+	if err != nil {
+		return err
+	}
+
+	if o.format == common.FormatJSON {
+		return common.PrintJSON(newStatusOutput(status))
+	}
+
+	ok := true
+
+	if status.SigningError != nil {
+		ok = false
+		fmt.Printf("Signing: not viable: %s\n", status.SigningError)
+	} else {
+		fmt.Printf("Signing: using '%s'\n", status.SigningProgram)
+	}
+
+	if !status.Enabled {
+		fmt.Println("gittuf: not enabled in this repository")
+		return nil
+	}
+	fmt.Println("gittuf: enabled")
+
+	now := time.Now()
+	for _, expiry := range []struct {
+		name    string
+		expires time.Time
+	}{{"Root of trust", status.RootExpires}, {"Policy", status.PolicyExpires}} {
+		switch {
+		case expiry.expires.IsZero():
+			fmt.Printf("%s: not initialized\n", expiry.name)
+		case expiry.expires.Before(now):
+			ok = false
+			fmt.Printf("%s: expired on %s\n", expiry.name, expiry.expires.Format(time.RFC3339))
+		case expiry.expires.Before(now.Add(expiryWarningPeriod)):
+			ok = false
+			fmt.Printf("%s: expires soon, on %s\n", expiry.name, expiry.expires.Format(time.RFC3339))
+		default:
+			fmt.Printf("%s: expires on %s\n", expiry.name, expiry.expires.Format(time.RFC3339))
+		}
+	}
+
+	if status.HasStagedPolicyChanges {
+		fmt.Println("Policy staging: has changes that haven't been applied")
+	}
+
+	switch status.RSLSync {
+	case repository.RSLSyncStatusUnknown:
+		fmt.Printf("RSL: at %s, not yet fetched from '%s'\n", status.RSLTip.String(), status.Remote)
+	case repository.RSLSyncStatusUpToDate:
+		fmt.Printf("RSL: at %s, up to date with '%s'\n", status.RSLTip.String(), status.Remote)
+	case repository.RSLSyncStatusAhead:
+		fmt.Printf("RSL: at %s, ahead of '%s' at %s\n", status.RSLTip.String(), status.Remote, status.RemoteRSLTip.String())
+	case repository.RSLSyncStatusBehind:
+		ok = false
+		fmt.Printf("RSL: at %s, behind '%s' at %s\n", status.RSLTip.String(), status.Remote, status.RemoteRSLTip.String())
+	case repository.RSLSyncStatusDiverged:
+		ok = false
+		fmt.Printf("RSL: at %s, diverged from '%s' at %s\n", status.RSLTip.String(), status.Remote, status.RemoteRSLTip.String())
+	}
+
+	if len(status.UnrecordedRefs) > 0 {
+		ok = false
+		fmt.Println("Changes not recorded in the RSL:")
+		for _, refName := range status.UnrecordedRefs {
+			fmt.Printf("    %s\n", refName)
+		}
+	}
+
+	if ok {
+		fmt.Println("Everything is OK")
+	}
+
+	return nil
+}
+
+func newStatusOutput(status *repository.Status) *statusOutput {
+	output := &statusOutput{
+		Enabled:                status.Enabled,
+		HasStagedPolicyChanges: status.HasStagedPolicyChanges,
+		Remote:                 status.Remote,
+		RSLSync:                string(status.RSLSync),
+		UnrecordedRefs:         status.UnrecordedRefs,
+		SigningProgram:         status.SigningProgram,
+	}
+	if output.UnrecordedRefs == nil {
+		output.UnrecordedRefs = []string{}
+	}
+	if !status.RootExpires.IsZero() {
+		output.RootExpires = status.RootExpires.Format(time.RFC3339)
+	}
+	if !status.PolicyExpires.IsZero() {
+		output.PolicyExpires = status.PolicyExpires.Format(time.RFC3339)
+	}
+	if !status.RSLTip.IsZero() {
+		output.RSLTip = status.RSLTip.String()
+	}
+	if !status.RemoteRSLTip.IsZero() {
+		output.RemoteRSLTip = status.RemoteRSLTip.String()
+	}
+	if status.SigningError != nil {
+		output.SigningError = status.SigningError.Error()
+	}
+
+	return output
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "status",
+		Short:             "Summarize the state of gittuf in the repository",
+		Long:              "This command allows users to check if everything is OK with gittuf in the repository. It reports whether the repository uses gittuf, when the root of trust and policy expire, whether the local RSL is in sync with the remote's RSL as of the last fetch, which local branches and tags have changes not recorded in the RSL, and whether the Git signing configuration is usable.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package addtimestampauthority
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p           *persistent.Options
+	certificate string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.certificate,
+		"certificate",
+		"",
+		"path to PEM encoded root certificate of timestamp authority to add to root of trust",
+	)
+	cmd.MarkFlagRequired("certificate") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	certificatePEM, err := os.ReadFile(o.certificate)
+	if err != nil {
+		return err
+	}
+
+	return repo.AddTimestampAuthority(cmd.Context(), signer, certificatePEM, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "add-timestamp-authority",
+		Short:             "Add a trusted RFC 3161 timestamp authority to gittuf root of trust",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
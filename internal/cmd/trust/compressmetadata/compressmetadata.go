@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package compressmetadata
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/compression"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p       *persistent.Options
+	disable bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.disable,
+		"disable",
+		false,
+		"stop compressing policy metadata and attestations",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	algorithm := compression.AlgorithmZstd
+	if o.disable {
+		algorithm = ""
+	}
+
+	return repo.SetMetadataCompression(cmd.Context(), signer, algorithm, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "compress-metadata",
+		Short:             "Compress large policy metadata and attestations stored in the repository",
+		Long:              "This command allows users to enable zstd compression of large policy metadata and attestations stored in the repository, which reduces the time taken to fetch policies with many principals. The root of trust is never compressed. Only metadata and attestations written after this change are compressed, and earlier versions of gittuf cannot read compressed metadata, so all users of the repository must update gittuf before compression is enabled. Use --disable to stop compressing new metadata and attestations.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -3,19 +3,42 @@
 package init
 
 import (
-	"os"
+	"fmt"
+	"strings"
 
 	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/logging"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
+// logger logs the messages of the cmd subsystem.
+var logger = logging.For("cmd")
+
 type options struct {
-	p *persistent.Options
+	p                  *persistent.Options
+	addGitSigningKey   bool
+	noAddGitSigningKey bool
 }
 
-func (o *options) AddFlags(_ *cobra.Command) {}
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.addGitSigningKey,
+		"add-git-signing-key",
+		false,
+		"add the key Git is configured to sign with as a policy key without asking",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.noAddGitSigningKey,
+		"no-add-git-signing-key",
+		false,
+		"do not offer to add the key Git is configured to sign with as a policy key",
+	)
+
+	cmd.MarkFlagsMutuallyExclusive("add-git-signing-key", "no-add-git-signing-key")
+}
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
 	repo, err := repository.LoadRepository()
@@ -23,16 +46,67 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	gitSigningKey, err := common.DetectGitSigningKey()
 	if err != nil {
-		return err
+		logger.Debug(fmt.Sprintf("Unable to detect Git signing key: %s", err.Error()))
+		gitSigningKey = nil
 	}
-	signer, err := common.LoadSigner(keyBytes)
+
+	signingKeyPath := o.p.SigningKey
+	if signingKeyPath == "" {
+		if gitSigningKey == nil || gitSigningKey.PrivateKeyPath == "" {
+			return fmt.Errorf("required flag \"signing-key\" not set")
+		}
+
+		signingKeyPath = gitSigningKey.PrivateKeyPath
+		fmt.Fprintf(cmd.OutOrStdout(), "Using Git signing key '%s' as the root key\n", signingKeyPath)
+	}
+
+	signer, err := common.LoadSignerForKey(signingKeyPath)
 	if err != nil {
 		return err
 	}
 
-	return repo.InitializeRoot(cmd.Context(), signer, true)
+	if err := repo.InitializeRoot(cmd.Context(), signer, true); err != nil {
+		return err
+	}
+
+	if gitSigningKey == nil || o.noAddGitSigningKey {
+		return nil
+	}
+
+	publicKeyName := gitSigningKey.PublicKey
+	if !o.addGitSigningKey {
+		if !common.IsInteractive(cmd.InOrStdin()) {
+			return nil
+		}
+
+		prompter := common.NewPrompter(cmd.InOrStdin(), cmd.OutOrStdout())
+		add, err := prompter.Confirm(fmt.Sprintf("Git is configured to sign using '%s'. Add it as a policy key?", publicKeyName), true)
+		if err != nil {
+			return err
+		}
+		if !add {
+			return nil
+		}
+
+		if strings.HasPrefix(publicKeyName, common.FulcioPrefix) {
+			// The issuer depends on the account used to log in with gitsign
+			identity, _, _ := strings.Cut(strings.TrimPrefix(publicKeyName, common.FulcioPrefix), "::")
+			issuer, err := prompter.Ask("OIDC issuer of the Sigstore identity", common.DefaultFulcioIssuer)
+			if err != nil {
+				return err
+			}
+			publicKeyName = fmt.Sprintf("%s%s::%s", common.FulcioPrefix, identity, issuer)
+		}
+	}
+
+	publicKey, err := common.LoadPublicKey(publicKeyName)
+	if err != nil {
+		return fmt.Errorf("unable to load Git signing key '%s': %w", publicKeyName, err)
+	}
+
+	return repo.AddTopLevelTargetsKey(cmd.Context(), signer, publicKey, true)
 }
 
 func New(persistent *persistent.Options) *cobra.Command {
@@ -40,7 +114,8 @@ func New(persistent *persistent.Options) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "init",
 		Short:             "Initialize gittuf root of trust for repository",
-		PreRunE:           common.CheckIfSigningViableWithFlag,
+		Long:              "This command allows users to initialize the root of trust for the repository. If --signing-key is not specified and Git is configured to sign using an SSH key on disk, that key is used as the root key. The key Git is configured to sign with (a GPG key, an SSH key, or a gitsign identity) is detected, and when run in a terminal, the user is offered to add it as a policy key so that the changes they sign can be authorized right away.",
+		PreRunE:           common.CheckIfSigningViable,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
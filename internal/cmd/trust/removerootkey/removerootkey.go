@@ -3,7 +3,6 @@
 package removerootkey
 
 import (
-	"os"
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/cmd/common"
@@ -25,6 +24,8 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"ID of Root key to be removed from root of trust",
 	)
 	cmd.MarkFlagRequired("root-key-ID") //nolint:errcheck
+
+	cmd.RegisterFlagCompletionFunc("root-key-ID", common.CompleteRootKeyIDs) //nolint:errcheck
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
@@ -33,11 +34,7 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	rootKeyBytes, err := os.ReadFile(o.p.SigningKey)
-	if err != nil {
-		return err
-	}
-	signer, err := common.LoadSigner(rootKeyBytes)
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package requirepolicyjustifications
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p       *persistent.Options
+	disable bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.disable,
+		"disable",
+		false,
+		"stop requiring justifications for policy changes",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetRequirePolicyJustifications(cmd.Context(), signer, !o.disable, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "require-policy-justifications",
+		Short:             "Require a signed justification for every policy change",
+		Long:              "This command allows users to require that every policy change is accompanied by a signed justification, recorded using 'gittuf policy justify', before it can be applied. The justification must be signed by a key trusted in the policy. Use --disable to remove the requirement; note that this change must itself be justified.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
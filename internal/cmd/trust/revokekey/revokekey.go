@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package revokekey
+
+import (
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p      *persistent.Options
+	keyID  string
+	reason string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.keyID,
+		"key-ID",
+		"",
+		"ID of key to revoke",
+	)
+	cmd.MarkFlagRequired("key-ID") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.reason,
+		"reason",
+		"",
+		"reason the key is revoked",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.RevokeKey(cmd.Context(), signer, strings.ToLower(o.keyID), o.reason, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "revoke-key",
+		Short:             "Revoke a key so that its signatures are rejected",
+		Long:              "This command records in the repository's policy that a key has been revoked. Verification fails if a Git signature was issued by the key, and signatures issued by the key in reference authorizations and policy metadata aren't counted towards thresholds, regardless of when they were issued. Use this for keys that have been compromised; to retire a key without invalidating its prior signatures, use set-key-validity instead.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setkeyvalidity
+
+import (
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p         *persistent.Options
+	keyID     string
+	notBefore string
+	notAfter  string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.keyID,
+		"key-ID",
+		"",
+		"ID of key whose validity window is set",
+	)
+	cmd.MarkFlagRequired("key-ID") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.notBefore,
+		"not-before",
+		"",
+		"RFC 3339 timestamp before which signatures issued by the key are rejected",
+	)
+
+	cmd.Flags().StringVar(
+		&o.notAfter,
+		"not-after",
+		"",
+		"RFC 3339 timestamp after which signatures issued by the key are rejected",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	signer, err := common.LoadSignerForKey(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetKeyValidity(cmd.Context(), signer, strings.ToLower(o.keyID), o.notBefore, o.notAfter, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "set-key-validity",
+		Short:             "Set the period in which a key may issue signatures",
+		Long:              "This command sets the validity window of a key trusted in the repository's policy. Signatures issued by the key are only accepted if they were timestamped within the window by a timestamp authority added using add-timestamp-authority or by the Rekor instance used during verification, so that a key that is compromised after it's rotated out can't be used to sign changes that appear to predate the rotation. Signatures are timestamped when they're created if gittuf is invoked with --timestamp-authority or --timestamp-rekor-url. If neither --not-before nor --not-after is specified, the key's validity window is removed.",
+		PreRunE:           common.CheckIfSigningViableWithFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -5,14 +5,22 @@ package trust
 import (
 	"github.com/gittuf/gittuf/internal/cmd/trust/addpolicykey"
 	"github.com/gittuf/gittuf/internal/cmd/trust/addrootkey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/addtimestampauthority"
+	"github.com/gittuf/gittuf/internal/cmd/trust/compressmetadata"
 	i "github.com/gittuf/gittuf/internal/cmd/trust/init"
 	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
 	"github.com/gittuf/gittuf/internal/cmd/trust/removepolicykey"
 	"github.com/gittuf/gittuf/internal/cmd/trust/removerootkey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/removetimestampauthority"
+	"github.com/gittuf/gittuf/internal/cmd/trust/requirepolicyjustifications"
+	"github.com/gittuf/gittuf/internal/cmd/trust/revokekey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/setkeyvalidity"
 	"github.com/gittuf/gittuf/internal/cmd/trust/sign"
+	"github.com/gittuf/gittuf/internal/cmd/trust/unrevokekey"
 	"github.com/gittuf/gittuf/internal/cmd/trust/updatepolicythreshold"
 	"github.com/gittuf/gittuf/internal/cmd/trust/updaterootthreshold"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/apply"
+	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/oci"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/remote"
 	"github.com/spf13/cobra"
 )
@@ -29,11 +37,19 @@ func New() *cobra.Command {
 	cmd.AddCommand(i.New(o))
 	cmd.AddCommand(addpolicykey.New(o))
 	cmd.AddCommand(addrootkey.New(o))
+	cmd.AddCommand(addtimestampauthority.New(o))
 	cmd.AddCommand(apply.New())
+	cmd.AddCommand(compressmetadata.New(o))
+	cmd.AddCommand(oci.New())
 	cmd.AddCommand(remote.New())
 	cmd.AddCommand(removepolicykey.New(o))
 	cmd.AddCommand(removerootkey.New(o))
+	cmd.AddCommand(removetimestampauthority.New(o))
+	cmd.AddCommand(requirepolicyjustifications.New(o))
+	cmd.AddCommand(revokekey.New(o))
+	cmd.AddCommand(setkeyvalidity.New(o))
 	cmd.AddCommand(sign.New(o))
+	cmd.AddCommand(unrevokekey.New(o))
 	cmd.AddCommand(updatepolicythreshold.New(o))
 	cmd.AddCommand(updaterootthreshold.New(o))
 
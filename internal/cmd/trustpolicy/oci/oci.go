@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package oci
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/oci/pull"
+	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/oci/push"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "oci",
+		Short:             "Tools for distributing policies as OCI artifacts",
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(pull.New())
+	cmd.AddCommand(push.New())
+
+	return cmd
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pull
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/oci"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	expectedRootKeys common.PublicKeys
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().Var(
+		&o.expectedRootKeys,
+		"root-key",
+		"set of expected root of trust keys for the policy (supported values: paths to SSH keys, GPG key fingerprints, Sigstore/Fulcio identities)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	ref, err := oci.ParseReference(args[0])
+	if err != nil {
+		return err
+	}
+
+	expectedRootKeys := make([]*tuf.Key, len(o.expectedRootKeys))
+	for index, keyPath := range o.expectedRootKeys {
+		key, err := common.LoadPublicKey(keyPath)
+		if err != nil {
+			return err
+		}
+
+		expectedRootKeys[index] = key
+	}
+	if len(expectedRootKeys) == 0 {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: no root keys were specified, if the repository has no policy the root keys in the artifact are trusted on first use")
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	artifact, digest, err := common.NewOCIClient().Pull(cmd.Context(), ref, repository.PolicyArtifactType)
+	if err != nil {
+		return err
+	}
+
+	imported, err := repo.ImportPolicyArtifact(cmd.Context(), artifact.Contents, expectedRootKeys, true)
+	if err != nil {
+		return err
+	}
+
+	if imported {
+		fmt.Fprintf(cmd.OutOrStdout(), "Applied policy from %s\n", ref.WithDigest(digest).String())
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "Policy from %s is already applied\n", ref.WithDigest(digest).String())
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "pull <reference>",
+		Short:             "Apply a policy published as an OCI artifact",
+		Long:              fmt.Sprintf(`This command allows users to apply a trust root and policy published as an OCI artifact using 'gittuf policy oci push'. If the repository has a policy, the artifact's policy must be trusted by it. Otherwise, the repository's root of trust is bootstrapped from the artifact, and --root-key should be used to specify the expected root keys. Pinning the artifact by digest, such as 'ghcr.io/example/policy@sha256:...', ensures the expected artifact is applied. The registry is accessed anonymously unless the %s and %s environment variables contain credentials for it.`, common.OCIUsernameKey, common.OCIPasswordKey),
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckIfSigningViable,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
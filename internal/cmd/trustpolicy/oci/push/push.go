@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package push
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/oci"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	ref, err := oci.ParseReference(args[0])
+	if err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	artifactContents, err := repo.ExportPolicyArtifact(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	digest, err := common.NewOCIClient().Push(cmd.Context(), ref, &oci.Artifact{
+		ArtifactType: repository.PolicyArtifactType,
+		MediaType:    repository.PolicyArtifactMediaType,
+		Contents:     artifactContents,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), ref.WithDigest(digest).String())
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "push <reference>",
+		Short:             "Publish the current policy as an OCI artifact",
+		Long:              fmt.Sprintf(`This command allows users to publish the repository's current trust root and policy as an OCI artifact, such as 'ghcr.io/example/policy:latest'. The artifact can be pulled into other repositories using 'gittuf policy oci pull', and its digest, which is printed, can be signed using tools such as cosign. The registry is accessed anonymously unless the %s and %s environment variables contain credentials for it.`, common.OCIUsernameKey, common.OCIPasswordKey),
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
@@ -3,6 +3,7 @@
 package pull
 
 import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
@@ -25,6 +26,7 @@ func New() *cobra.Command {
 		Use:               "pull <remote>",
 		Short:             "Pull policy from the specified remote",
 		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRemotes),
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
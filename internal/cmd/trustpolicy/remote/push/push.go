@@ -3,6 +3,7 @@
 package push
 
 import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
@@ -25,6 +26,7 @@ func New() *cobra.Command {
 		Use:               "push <remote>",
 		Short:             "Push policy to the specified remote",
 		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRemotes),
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
@@ -0,0 +1,260 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// defaultHeight is the number of rows assumed until the terminal
+	// reports its size.
+	defaultHeight = 24
+
+	// chromeHeight is the number of rows used by the title, the blank line
+	// below it, and the status line.
+	chromeHeight = 3
+
+	defaultLoadingMessage = "Loading..."
+	helpMessage           = "↑/↓ move • enter open • esc back • q quit"
+)
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	statusStyle   = lipgloss.NewStyle().Faint(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// view is a screen of the dashboard. List views have items the user can
+// select to open another view, shown below the header lines. Detail views
+// have no items and show their lines, which the user can scroll through.
+type view struct {
+	title  string
+	header []string
+	items  []item
+	lines  []string
+
+	// cursor is the index of the selected item in list views
+	cursor int
+
+	// offset is the index of the first item or line shown
+	offset int
+}
+
+// item is an entry of a list view. Selecting it opens the view returned by
+// open, if it's set. Views are opened in the background, showing the loading
+// message, as they may verify refs.
+type item struct {
+	label   string
+	open    func() (*view, error)
+	loading string
+}
+
+// viewLoadedMsg is sent when the view opened by the user has been loaded.
+type viewLoadedMsg struct {
+	view *view
+}
+
+// loadFailedMsg is sent when the view opened by the user couldn't be loaded.
+type loadFailedMsg struct {
+	err error
+}
+
+// model is the bubbletea model of the dashboard. It holds the stack of views
+// the user drilled down through, with the current view on top.
+type model struct {
+	views   []*view
+	loading string
+	err     error
+	width   int
+	height  int
+}
+
+func newModel(root *view) *model {
+	return &model{views: []*view{root}, height: defaultHeight}
+}
+
+func (m *model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case viewLoadedMsg:
+		m.loading = ""
+		m.views = append(m.views, msg.view)
+	case loadFailedMsg:
+		m.loading = ""
+		m.err = msg.err
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+	}
+
+	m.current().scrollTo(m.bodyHeight())
+	return m, nil
+}
+
+func (m *model) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return tea.Quit
+	}
+
+	// The view being opened is pushed when it's loaded, so the current view
+	// must not change in the meantime
+	if m.loading != "" {
+		return nil
+	}
+
+	current := m.current()
+	switch msg.String() {
+	case "up", "k":
+		current.move(-1)
+	case "down", "j":
+		current.move(1)
+	case "pgup":
+		current.move(-m.bodyHeight())
+	case "pgdown":
+		current.move(m.bodyHeight())
+	case "home", "g":
+		current.move(-current.length())
+	case "end", "G":
+		current.move(current.length())
+	case "enter", "right", "l":
+		if len(current.items) == 0 || current.items[current.cursor].open == nil {
+			break
+		}
+
+		selected := current.items[current.cursor]
+		m.err = nil
+		m.loading = selected.loading
+		if m.loading == "" {
+			m.loading = defaultLoadingMessage
+		}
+		return openView(selected.open)
+	case "esc", "left", "h", "backspace":
+		m.err = nil
+		if len(m.views) > 1 {
+			m.views = m.views[:len(m.views)-1]
+		}
+	}
+
+	m.current().scrollTo(m.bodyHeight())
+	return nil
+}
+
+// openView returns a command that loads the view in the background.
+func openView(open func() (*view, error)) tea.Cmd {
+	return func() tea.Msg {
+		view, err := open()
+		if err != nil {
+			return loadFailedMsg{err: err}
+		}
+		return viewLoadedMsg{view: view}
+	}
+}
+
+func (m *model) View() string {
+	current := m.current()
+
+	titles := make([]string, 0, len(m.views))
+	for _, view := range m.views {
+		titles = append(titles, view.title)
+	}
+
+	rows := []string{titleStyle.Render(strings.Join(titles, " › ")), ""}
+	rows = append(rows, current.header...)
+
+	bodyHeight := m.bodyHeight()
+	switch {
+	case len(current.items) > 0:
+		end := min(current.offset+bodyHeight, len(current.items))
+		for index := current.offset; index < end; index++ {
+			if index == current.cursor {
+				rows = append(rows, selectedStyle.Render("> "+current.items[index].label))
+			} else {
+				rows = append(rows, "  "+current.items[index].label)
+			}
+		}
+	case len(current.lines) > 0:
+		end := min(current.offset+bodyHeight, len(current.lines))
+		rows = append(rows, current.lines[current.offset:end]...)
+	default:
+		rows = append(rows, statusStyle.Render("(none)"))
+	}
+
+	// The status line is kept at the bottom of the screen
+	for len(rows) < m.height-1 {
+		rows = append(rows, "")
+	}
+
+	switch {
+	case m.loading != "":
+		rows = append(rows, statusStyle.Render(m.loading))
+	case m.err != nil:
+		rows = append(rows, errorStyle.Render("Error: "+m.err.Error()))
+	default:
+		rows = append(rows, statusStyle.Render(helpMessage))
+	}
+
+	if m.width > 0 {
+		for index := range rows {
+			rows[index] = lipgloss.NewStyle().MaxWidth(m.width).Render(rows[index])
+		}
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+func (m *model) current() *view {
+	return m.views[len(m.views)-1]
+}
+
+// bodyHeight returns the number of items or lines of the current view that
+// fit on the screen.
+func (m *model) bodyHeight() int {
+	return max(m.height-chromeHeight-len(m.current().header), 1)
+}
+
+// length returns the number of items of list views or lines of detail views.
+func (v *view) length() int {
+	if len(v.items) > 0 {
+		return len(v.items)
+	}
+	return len(v.lines)
+}
+
+// move moves the cursor of list views, or scrolls detail views, by delta.
+func (v *view) move(delta int) {
+	if len(v.items) > 0 {
+		v.cursor = clamp(v.cursor+delta, 0, len(v.items)-1)
+		return
+	}
+
+	v.offset = clamp(v.offset+delta, 0, max(len(v.lines)-1, 0))
+}
+
+// scrollTo scrolls list views so that the selected item is shown on the
+// screen, which fits bodyHeight items.
+func (v *view) scrollTo(bodyHeight int) {
+	if len(v.items) == 0 {
+		return
+	}
+
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+	if v.cursor >= v.offset+bodyHeight {
+		v.offset = v.cursor - bodyHeight + 1
+	}
+}
+
+func clamp(value, lower, upper int) int {
+	return max(lower, min(value, upper))
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel(t *testing.T) {
+	items := make([]item, 0, 30)
+	for i := 0; i < 30; i++ {
+		items = append(items, item{
+			label: fmt.Sprintf("item %d", i),
+			open:  detailView(fmt.Sprintf("Item %d", i), []string{fmt.Sprintf("details of item %d", i)}),
+		})
+	}
+	items = append(items, item{
+		label: "broken",
+		open:  func() (*view, error) { return nil, errors.New("unable to load") },
+	})
+
+	t.Run("move and scroll", func(t *testing.T) {
+		m := newModel(&view{title: "root", items: items})
+		m.Update(tea.WindowSizeMsg{Width: 80, Height: 13}) // 10 rows for items
+
+		press(m, "down")
+		press(m, "j")
+		assert.Equal(t, 2, m.current().cursor)
+		assert.Equal(t, 0, m.current().offset)
+
+		press(m, "pgdown")
+		assert.Equal(t, 12, m.current().cursor)
+		assert.Equal(t, 3, m.current().offset)
+		assert.Contains(t, m.View(), "> item 12")
+		assert.NotContains(t, m.View(), "item 2\n")
+
+		press(m, "G")
+		assert.Equal(t, len(items)-1, m.current().cursor)
+
+		press(m, "down")
+		assert.Equal(t, len(items)-1, m.current().cursor)
+
+		press(m, "home")
+		assert.Equal(t, 0, m.current().cursor)
+		assert.Equal(t, 0, m.current().offset)
+
+		press(m, "up")
+		assert.Equal(t, 0, m.current().cursor)
+	})
+
+	t.Run("open and go back", func(t *testing.T) {
+		m := newModel(&view{title: "root", items: items})
+		press(m, "down")
+
+		cmd := press(m, "enter")
+		assert.NotNil(t, cmd)
+		assert.Equal(t, defaultLoadingMessage, m.loading)
+
+		// Keys other than quitting are ignored while loading
+		assert.Nil(t, press(m, "esc"))
+		assert.Len(t, m.views, 1)
+
+		m.Update(cmd())
+		assert.Empty(t, m.loading)
+		assert.Len(t, m.views, 2)
+		assert.Contains(t, m.View(), "root › Item 1")
+		assert.Contains(t, m.View(), "details of item 1")
+
+		press(m, "esc")
+		assert.Len(t, m.views, 1)
+		assert.Equal(t, 1, m.current().cursor)
+
+		// Going back from the main view has no effect
+		press(m, "esc")
+		assert.Len(t, m.views, 1)
+	})
+
+	t.Run("view fails to load", func(t *testing.T) {
+		m := newModel(&view{title: "root", items: items})
+		press(m, "end")
+
+		cmd := press(m, "enter")
+		m.Update(cmd())
+		assert.Len(t, m.views, 1)
+		assert.ErrorContains(t, m.err, "unable to load")
+		assert.Contains(t, m.View(), "Error: unable to load")
+	})
+
+	t.Run("quit", func(t *testing.T) {
+		m := newModel(&view{title: "root", items: items})
+		cmd := press(m, "q")
+		assert.IsType(t, tea.QuitMsg{}, cmd())
+	})
+}
+
+func press(m *model, key string) tea.Cmd {
+	var msg tea.KeyMsg
+	switch key {
+	case "up":
+		msg = tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	case "pgdown":
+		msg = tea.KeyMsg{Type: tea.KeyPgDown}
+	case "home":
+		msg = tea.KeyMsg{Type: tea.KeyHome}
+	case "end":
+		msg = tea.KeyMsg{Type: tea.KeyEnd}
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+
+	_, cmd := m.Update(msg)
+	return cmd
+}
@@ -0,0 +1,288 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/interactive"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultEntryLimit = 50
+	abbreviatedIDLen  = 8
+)
+
+type options struct {
+	entryLimit int
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(
+		&o.entryLimit,
+		"entries",
+		defaultEntryLimit,
+		"number of RSL entries to list, starting with the latest",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if interactive.InNonInteractiveMode() {
+		return interactive.ErrInteractionRequired
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	d := &dashboard{
+		ctx:        cmd.Context(),
+		repo:       repo,
+		labels:     common.LoadKeyLabels(cmd.Context(), repo),
+		entryLimit: o.entryLimit,
+	}
+
+	program := tea.NewProgram(
+		newModel(d.mainView()),
+		tea.WithAltScreen(),
+		tea.WithContext(cmd.Context()),
+		tea.WithInput(cmd.InOrStdin()),
+		tea.WithOutput(cmd.OutOrStdout()),
+	)
+	_, err = program.Run()
+	return err
+}
+
+// dashboard builds the views of the terminal UI presenting the repository's
+// gittuf state. Each view lists items the user can open to see more detail.
+type dashboard struct {
+	ctx        context.Context
+	repo       *repository.Repository
+	labels     common.KeyLabels
+	entryLimit int
+}
+
+func (d *dashboard) mainView() *view {
+	return &view{
+		title: "gittuf",
+		items: []item{
+			{label: "Policy roles", open: d.rolesView},
+			{label: "Rules", open: d.rulesView},
+			{label: "RSL entries", open: d.rslView},
+			{label: "Refs and verification status", open: d.refsView},
+		},
+	}
+}
+
+func (d *dashboard) rolesView() (*view, error) {
+	roles, err := d.repo.ListPolicyRoles(d.ctx, "policy")
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]item, 0, len(roles))
+	for _, role := range roles {
+		lines := []string{}
+		if role.Expires.IsZero() {
+			lines = append(lines, "Expires:   not initialized")
+		} else {
+			lines = append(lines, fmt.Sprintf("Expires:   %s", role.Expires.Format(time.RFC1123Z)))
+		}
+		lines = append(lines, fmt.Sprintf("Threshold: %d", role.Threshold), "Keys:")
+		for _, keyID := range role.KeyIDs {
+			lines = append(lines, "  "+d.labels.Describe(keyID))
+		}
+
+		items = append(items, item{
+			label: fmt.Sprintf("%s (%d of %d keys)", role.Name, role.Threshold, len(role.KeyIDs)),
+			open:  detailView(fmt.Sprintf("Role %s", role.Name), lines),
+		})
+	}
+
+	return &view{title: "Policy roles", items: items}, nil
+}
+
+func (d *dashboard) rulesView() (*view, error) {
+	rules, err := d.repo.ListRules(d.ctx, "policy")
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]item, 0, len(rules))
+	for _, rule := range rules {
+		delegation := rule.Delegation
+
+		lines := []string{"Protects:"}
+		for _, path := range delegation.Paths {
+			lines = append(lines, "  "+path)
+		}
+		lines = append(lines, "Authorized keys:")
+		for _, keyID := range delegation.Role.KeyIDs {
+			lines = append(lines, "  "+d.labels.Describe(keyID))
+		}
+		lines = append(lines, fmt.Sprintf("Required valid signatures: %d", delegation.Role.Threshold))
+
+		items = append(items, item{
+			label: strings.Repeat("  ", rule.Depth) + delegation.Name,
+			open:  detailView(fmt.Sprintf("Rule %s", delegation.Name), lines),
+		})
+	}
+
+	return &view{title: "Rules", items: items}, nil
+}
+
+func (d *dashboard) rslView() (*view, error) {
+	entries, err := d.repo.ListRSLEntries(d.entryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]item, 0, len(entries))
+	for _, entry := range entries {
+		switch entry := entry.(type) {
+		case *rsl.ReferenceEntry:
+			items = append(items, item{
+				label: fmt.Sprintf("%s %s -> %s", abbreviate(entry.ID.String()), entry.RefName, abbreviate(entry.TargetID.String())),
+				open: detailView(fmt.Sprintf("Entry %s", abbreviate(entry.ID.String())), []string{
+					fmt.Sprintf("ID:     %s", entry.ID.String()),
+					fmt.Sprintf("Ref:    %s", entry.RefName),
+					fmt.Sprintf("Target: %s", entry.TargetID.String()),
+				}),
+			})
+		case *rsl.AnnotationEntry:
+			kind := "annotation"
+			if entry.Skip {
+				kind = "skip annotation"
+			}
+
+			lines := []string{
+				fmt.Sprintf("ID:      %s", entry.ID.String()),
+				fmt.Sprintf("Skip:    %t", entry.Skip),
+				fmt.Sprintf("Message: %s", entry.Message),
+				"Entries:",
+			}
+			for _, id := range entry.RSLEntryIDs {
+				lines = append(lines, "  "+id.String())
+			}
+
+			items = append(items, item{
+				label: fmt.Sprintf("%s %s for %d entries", abbreviate(entry.ID.String()), kind, len(entry.RSLEntryIDs)),
+				open:  detailView(fmt.Sprintf("Annotation %s", abbreviate(entry.ID.String())), lines),
+			})
+		}
+	}
+
+	return &view{title: "RSL entries", items: items}, nil
+}
+
+func (d *dashboard) refsView() (*view, error) {
+	refs, err := d.repo.ListRecordedRefs()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]item, 0, len(refs))
+	for _, refName := range refs {
+		items = append(items, item{
+			label:   refName,
+			open:    func() (*view, error) { return d.refView(refName) },
+			loading: fmt.Sprintf("Verifying %s...", refName),
+		})
+	}
+
+	return &view{title: "Refs", items: items}, nil
+}
+
+func (d *dashboard) refView(refName string) (*view, error) {
+	var status string
+	if err := d.repo.VerifyRef(d.ctx, refName, false); err != nil {
+		status = fmt.Sprintf("%s Verification failed: %s", common.Badge(false), err.Error())
+	} else {
+		status = fmt.Sprintf("%s Verification succeeded", common.Badge(true))
+	}
+
+	logEntries, err := d.repo.Log(d.ctx, refName)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]item, 0, len(logEntries))
+	for _, logEntry := range logEntries {
+		status := "verified"
+		switch {
+		case logEntry.Skipped:
+			status = "skipped"
+		case logEntry.VerificationError != nil:
+			status = "failed verification"
+		}
+
+		lines := []string{fmt.Sprintf("Target:    %s", logEntry.RSLEntry.TargetID.String())}
+		if logEntry.Signer != "" {
+			lines = append(lines, fmt.Sprintf("Pushed by: %s", d.labels.Describe(logEntry.Signer)))
+		} else {
+			lines = append(lines, "Pushed by: unknown key")
+		}
+		if len(logEntry.Approvers) > 0 {
+			lines = append(lines, fmt.Sprintf("Approvers: %s", strings.Join(d.labels.DescribeAll(logEntry.Approvers), ", ")))
+		}
+		if logEntry.Rule != "" {
+			lines = append(lines, fmt.Sprintf("Rule:      %s", logEntry.Rule))
+		} else {
+			lines = append(lines, "Rule:      none")
+		}
+		if logEntry.VerificationError != nil {
+			lines = append(lines, fmt.Sprintf("Verified:  no (%s)", logEntry.VerificationError))
+		} else {
+			lines = append(lines, "Verified:  yes")
+		}
+		lines = append(lines, "Commits:")
+		for _, commit := range logEntry.Commits {
+			lines = append(lines, fmt.Sprintf("  %s %s", abbreviate(commit.Hash.String()), strings.Split(strings.TrimSpace(commit.Message), "\n")[0]))
+		}
+
+		items = append(items, item{
+			label: fmt.Sprintf("%s -> %s (%s)", abbreviate(logEntry.RSLEntry.ID.String()), abbreviate(logEntry.RSLEntry.TargetID.String()), status),
+			open:  detailView(fmt.Sprintf("Entry %s", abbreviate(logEntry.RSLEntry.ID.String())), lines),
+		})
+	}
+
+	return &view{title: refName, header: []string{status, ""}, items: items}, nil
+}
+
+// detailView returns a function opening a view that shows the lines.
+func detailView(title string, lines []string) func() (*view, error) {
+	return func() (*view, error) {
+		return &view{title: title, lines: lines}, nil
+	}
+}
+
+func abbreviate(id string) string {
+	if len(id) <= abbreviatedIDLen {
+		return id
+	}
+	return id[:abbreviatedIDLen]
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "ui",
+		Short:             "Browse the repository's gittuf state interactively",
+		Long:              "This command allows users to browse the repository's policy roles, rules, RSL entries, and the verification status of each ref recorded in the RSL in a terminal UI. Use the arrow keys or j and k to move, enter to open the selected item and see its details, esc to go back, and q to quit. Opening a ref verifies it and lists its history.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -5,24 +5,72 @@ package verifycommit
 import (
 	"fmt"
 
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/githubactions"
+	"github.com/gittuf/gittuf/internal/report"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
-type options struct{}
+type options struct {
+	format       string
+	reportFile   string
+	reportFormat string
+}
 
-func (o *options) AddFlags(_ *cobra.Command) {}
+type statusOutput struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	common.AddFormatFlag(cmd, &o.format)
+	common.AddReportFlags(cmd, &o.reportFile, &o.reportFormat)
+}
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+	if err := common.CheckReportFormat(o.reportFormat); err != nil {
+		return err
+	}
+
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
 	}
 
-	status := repo.VerifyCommit(cmd.Context(), args...)
+	ctx := cmd.Context()
+	var verificationReport *report.Report
+	if o.reportFile != "" {
+		verificationReport = report.New("verify-commit")
+		ctx = report.ContextWithReport(ctx, verificationReport)
+	}
+
+	status := repo.VerifyCommit(ctx, args...)
+
+	verificationReport.Finish(nil)
+	if err := common.WriteReport(o.reportFile, o.reportFormat, verificationReport); err != nil {
+		return fmt.Errorf("unable to write report: %w", err)
+	}
+
+	if err := common.ReportStatusesToActions(githubactions.Detect(cmd.ErrOrStderr()), "verify-commit", args, status); err != nil {
+		return fmt.Errorf("unable to report results to GitHub Actions: %w", err)
+	}
+
+	if o.format == common.FormatJSON {
+		output := make([]*statusOutput, 0, len(args))
+		for _, id := range args {
+			output = append(output, &statusOutput{ID: id, Status: status[id]})
+		}
+
+		return common.PrintJSON(output)
+	}
 
+	labels := common.LoadKeyLabels(cmd.Context(), repo)
 	for _, id := range args {
-		fmt.Printf("%s: %s\n", id, status[id])
+		fmt.Printf("%s: %s\n", id, labels.Annotate(status[id]))
 	}
 
 	return nil
@@ -34,6 +82,7 @@ func New() *cobra.Command {
 		Use:               "verify-commit",
 		Short:             "Verify commit signatures using gittuf metadata",
 		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: common.CompleteRefs,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifymirrors
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/gittuf/gittuf/internal/mirrorverifier"
+	"github.com/spf13/cobra"
+)
+
+// logger logs the messages of the mirrorverifier subsystem.
+var logger = logging.For("mirrorverifier")
+
+type options struct {
+	address  string
+	mirrors  []string
+	refs     []string
+	interval time.Duration
+	maxAge   time.Duration
+	cacheDir string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.address,
+		"address",
+		":9090",
+		"address to serve the status page and metrics on",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.mirrors,
+		"mirror",
+		nil,
+		"mirror to verify, specified as <name>=<URL>",
+	)
+	cmd.MarkFlagRequired("mirror") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.refs,
+		"ref",
+		nil,
+		"ref to verify in every mirror (default is all branches and tags)",
+	)
+
+	cmd.Flags().DurationVar(
+		&o.interval,
+		"interval",
+		15*time.Minute,
+		"time to wait between verifications of the mirrors",
+	)
+
+	cmd.Flags().DurationVar(
+		&o.maxAge,
+		"max-age",
+		0,
+		"how long a successful verification is considered fresh (default is twice the interval)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.cacheDir,
+		"cache-dir",
+		"",
+		"directory to fetch mirrors to for verification (default is gittuf/verify-mirrors in the user's cache directory)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	cacheDir := o.cacheDir
+	if cacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		cacheDir = filepath.Join(userCacheDir, "gittuf", "verify-mirrors")
+	}
+
+	maxAge := o.maxAge
+	if maxAge == 0 {
+		maxAge = 2 * o.interval
+	}
+
+	mirrors := make([]*mirrorverifier.Mirror, 0, len(o.mirrors))
+	for _, spec := range o.mirrors {
+		mirror, err := mirrorverifier.ParseMirror(spec)
+		if err != nil {
+			return err
+		}
+		mirrors = append(mirrors, mirror)
+	}
+
+	daemon, err := mirrorverifier.New(&mirrorverifier.Config{
+		Mirrors:  mirrors,
+		Refs:     o.refs,
+		MaxAge:   maxAge,
+		CacheDir: cacheDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	go daemon.Run(cmd.Context(), o.interval)
+
+	logger.Info("Serving status page and metrics", "address", o.address)
+	return http.ListenAndServe(o.address, metrics.Handler(nil, metrics.NewHealth(), daemon)) //nolint:gosec
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "verify-mirrors",
+		Short:             "Continuously verify mirrors of repositories and report which are verified and fresh",
+		Long:              "This command allows users to run a daemon that periodically fetches and fully verifies a set of repositories, such as the upstreams an organization consumes or its mirrors of them. Each mirror is specified using --mirror with a name and URL. Every interval, the daemon fetches each mirror and verifies the refs specified using --ref against the mirror's gittuf policy, or all of the mirror's branches and tags if none are specified. A mirror is fresh if it was verified successfully within --max-age. The daemon serves a status page (GET /), the statuses of the mirrors as JSON (GET /status), Prometheus metrics (GET /metrics), and the /healthz and /readyz health endpoints on --address.",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
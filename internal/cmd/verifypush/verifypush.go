@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifypush
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/i18n"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+var ErrPushRejected = errors.New("push rejected by gittuf policy")
+
+type options struct {
+	format string
+}
+
+type updateOutput struct {
+	Ref      string `json:"ref"`
+	OldID    string `json:"oldID"`
+	NewID    string `json:"newID"`
+	Verified bool   `json:"verified"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	common.AddFormatFlag(cmd, &o.format)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
+	updates, err := repository.ParseRefUpdates(cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	rejected, err := repo.VerifyPush(cmd.Context(), updates)
+	if err != nil {
+		return err
+	}
+
+	if o.format == common.FormatJSON {
+		output := make([]*updateOutput, 0, len(updates))
+		for _, update := range updates {
+			item := &updateOutput{Ref: update.RefName, OldID: update.OldID.String(), NewID: update.NewID.String(), Verified: true}
+			if err, has := rejected[update.RefName]; has {
+				item.Verified = false
+				item.Error = err.Error()
+			}
+			output = append(output, item)
+		}
+
+		if err := common.PrintJSON(output); err != nil {
+			return err
+		}
+	} else {
+		for _, update := range updates {
+			if err, has := rejected[update.RefName]; has {
+				fmt.Printf("%s %s: %s\n", common.Badge(false), update.RefName, i18n.TranslateError(err))
+				continue
+			}
+
+			fmt.Printf("%s %s\n", common.Badge(true), update.RefName)
+		}
+	}
+
+	if len(rejected) > 0 {
+		return fmt.Errorf("%w: %d of %d ref updates failed verification", ErrPushRejected, len(rejected), len(updates))
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "verify-push",
+		Aliases:           []string{"verify-receive"},
+		Short:             "Verify the ref updates of a push in a Git server's pre-receive hook",
+		Long:              `This command allows users to enforce gittuf policies on a self-hosted Git server. It's intended to be run as the repository's pre-receive hook, where it reads the proposed ref updates of a push from stdin, one "<old-id> <new-id> <ref>" per line. The updates are verified together against the repository's policy as if they had been applied, using the RSL entries pushed alongside them. The pushed objects, which Git holds in a quarantine directory until the push is accepted, are checked first: each must be intact, and each must be reachable from the updated refs so that no unverified objects are added to the repository. As the update hook is invoked separately for each ref, it can't be used to verify a push that updates the RSL. Deleting a ref protected by the policy or a gittuf ref, and rewriting the RSL, are not allowed. If any update fails verification, the command exits with an error and Git rejects the push.`,
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
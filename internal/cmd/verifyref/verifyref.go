@@ -3,16 +3,59 @@
 package verifyref
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/gittuf/gittuf/internal/archivista"
+	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/githubactions"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/i18n"
+	"github.com/gittuf/gittuf/internal/notify"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/progress"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/report"
 	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/trace"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
-	latestOnly bool
-	fromEntry  string
+	latestOnly             bool
+	allowedSigners         bool
+	checkRevocations       bool
+	revocationCertificates []string
+	fromEntry              string
+	archivistaURL          string
+	rekorURL               string
+	format                 string
+	progress               bool
+	explain                bool
+	traceFile              string
+	reportFile             string
+	reportFormat           string
+	notifyWebhook          string
+	notifyCommand          string
+	verifyLFS              bool
+	jobs                   int
+}
+
+type verificationOutput struct {
+	Ref      string `json:"ref"`
+	Verified bool   `json:"verified"`
+	Error    string `json:"error,omitempty"`
+
+	// ShallowAnchor identifies the RSL entry verification started after when
+	// the repository is a shallow clone.
+	ShallowAnchor *policy.AnchorExplanation `json:"shallow_anchor,omitempty"`
+
+	Explanation []*policy.EntryExplanation `json:"explanation,omitempty"`
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -30,24 +73,315 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		fmt.Sprintf("perform verification from specified RSL entry (developer mode only, set %s=1)", dev.DevModeKey),
 	)
 
+	cmd.Flags().BoolVar(
+		&o.allowedSigners,
+		"allowed-signers",
+		false,
+		"require SSH signatures to be issued by keys in Git's gpg.ssh.allowedSignersFile, honoring their validity periods",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.checkRevocations,
+		"check-revocations",
+		false,
+		"reject signatures issued by keys in Git's gpg.ssh.revocationFile or revoked in the GnuPG keyring",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.revocationCertificates,
+		"revocation-certificate",
+		nil,
+		"GPG revocation certificate of a key whose signatures are rejected, implies --check-revocations",
+	)
+
+	cmd.Flags().StringVar(
+		&o.archivistaURL,
+		"archivista-url",
+		"",
+		"Archivista instance to search for attestations missing in the repository",
+	)
+
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to verify attestations were logged to",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.progress,
+		"progress",
+		false,
+		"report verification progress on stderr",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.explain,
+		"explain",
+		false,
+		"explain why verification failed, showing the rules evaluated, the keys they trust, and the signatures found",
+	)
+
+	cmd.Flags().StringVar(
+		&o.traceFile,
+		"trace",
+		"",
+		"write every verification step to the specified file as JSON lines",
+	)
+
+	cmd.Flags().StringVar(
+		&o.notifyWebhook,
+		"notify-webhook",
+		"",
+		"URL to POST a JSON payload describing the failure to if verification fails",
+	)
+
+	cmd.Flags().StringVar(
+		&o.notifyCommand,
+		"notify-command",
+		"",
+		"shell command to invoke with a JSON payload describing the failure on stdin if verification fails",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.verifyLFS,
+		"verify-lfs",
+		false,
+		"verify that the Git LFS objects referenced by the ref exist locally and match their pointers and attestation",
+	)
+
+	cmd.Flags().IntVar(
+		&o.jobs,
+		"jobs",
+		0,
+		"number of signatures to verify concurrently (default is the number of CPUs)",
+	)
+
+	common.AddFormatFlag(cmd, &o.format)
+	common.AddReportFlags(cmd, &o.reportFile, &o.reportFormat)
+
 	cmd.MarkFlagsMutuallyExclusive("latest-only", "from-entry")
 }
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+	if err := common.CheckReportFormat(o.reportFormat); err != nil {
+		return err
+	}
+
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
 	}
 
+	ctx := gitinterface.ContextWithBatchVerifier(cmd.Context(), gitinterface.NewBatchVerifier(o.jobs))
+	if o.allowedSigners {
+		allowedSigners, err := gitinterface.LoadAllowedSignersFromConfig()
+		if err != nil {
+			return err
+		}
+		ctx = gitinterface.ContextWithAllowedSigners(ctx, allowedSigners)
+	}
+	if o.checkRevocations || len(o.revocationCertificates) != 0 {
+		revocations, err := gitinterface.LoadRevocationsFromConfig()
+		if err != nil {
+			return err
+		}
+		for _, path := range o.revocationCertificates {
+			if err := revocations.LoadGPGRevocationCertificate(path); err != nil {
+				return err
+			}
+		}
+		ctx = gitinterface.ContextWithRevocations(ctx, revocations)
+	}
+	if o.archivistaURL != "" {
+		ctx = archivista.ContextWithClient(ctx, archivista.NewClient(o.archivistaURL))
+	}
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+	if o.progress {
+		ctx = progress.ContextWithReporter(ctx, progress.NewReporter(cmd.ErrOrStderr()))
+	}
+
+	var tracer *trace.Tracer
+	if o.traceFile != "" {
+		traceFile, err := os.Create(o.traceFile)
+		if err != nil {
+			return err
+		}
+		defer traceFile.Close() //nolint:errcheck
+
+		tracer = trace.NewTracer(traceFile)
+		ctx = trace.ContextWithTracer(ctx, tracer)
+	}
+
+	var verificationReport *report.Report
+	if o.reportFile != "" {
+		verificationReport = report.New("verify-ref")
+		ctx = report.ContextWithReport(ctx, verificationReport)
+	}
+
+	notifier := notify.NewNotifier(o.notifyWebhook, o.notifyCommand)
+	actions := githubactions.Detect(cmd.ErrOrStderr())
+
+	// The explanation also records the entry verification started after in a
+	// shallow clone, which is always reported
+	explanation := &policy.Explanation{}
+	ctx = policy.ContextWithExplanation(ctx, explanation)
+
 	if o.fromEntry != "" {
 		if !dev.InDevMode() {
 			return dev.ErrNotInDevMode
 		}
 
-		return repo.VerifyRefFromEntry(cmd.Context(), args[0], o.fromEntry)
+		err = repo.VerifyRefFromEntry(ctx, args[0], o.fromEntry)
+	} else {
+		err = repo.VerifyRef(ctx, args[0], o.latestOnly)
+	}
+
+	if err == nil && o.verifyLFS {
+		err = repo.VerifyLFSObjects(ctx, args[0])
+	}
+
+	verificationReport.Finish(err)
+
+	if err != nil && notifier.Enabled() {
+		notification := &notify.Notification{
+			Event:   notify.EventVerificationFailed,
+			Time:    time.Now(),
+			Ref:     args[0],
+			Error:   err.Error(),
+			Details: explanation.FailedEntries,
+		}
+		if gitRepo, repoErr := gitinterface.LoadRepository(); repoErr == nil {
+			notification.Repository = gitRepo.GetGitDir()
+		}
+
+		if notifyErr := notifier.Notify(ctx, notification); notifyErr != nil {
+			err = errors.Join(err, fmt.Errorf("unable to send notification: %w", notifyErr))
+		}
+	}
+
+	if reportErr := reportToActions(actions, args[0], err, explanation); reportErr != nil {
+		err = errors.Join(err, fmt.Errorf("unable to report results to GitHub Actions: %w", reportErr))
+	}
+
+	if o.format == common.FormatJSON {
+		output := &verificationOutput{Ref: args[0], Verified: err == nil, ShallowAnchor: explanation.ShallowAnchor}
+		if err != nil {
+			output.Error = err.Error()
+			if o.explain {
+				output.Explanation = explanation.FailedEntries
+			}
+		}
+		if err := common.PrintJSON(output); err != nil {
+			return err
+		}
+	} else {
+		if anchor := explanation.ShallowAnchor; anchor != nil {
+			fmt.Fprint(cmd.ErrOrStderr(), i18n.Sprintf("Repository is a shallow clone, %s was verified after RSL entry %s (target %s)\n", anchor.RefName, anchor.EntryID, anchor.TargetID))
+		}
+		if err != nil && o.explain {
+			printExplanation(cmd.OutOrStdout(), explanation, common.LoadKeyLabels(ctx, repo))
+		}
+	}
+
+	if reportErr := common.WriteReport(o.reportFile, o.reportFormat, verificationReport); reportErr != nil {
+		err = errors.Join(err, fmt.Errorf("unable to write report: %w", reportErr))
+	}
+
+	if traceErr := tracer.Err(); traceErr != nil {
+		return errors.Join(err, fmt.Errorf("unable to write trace: %w", traceErr))
+	}
+
+	return err
+}
+
+// reportToActions reports the result of verifying the ref to the GitHub Actions
+// job gittuf is running in, if any. Each RSL entry that failed verification is
+// annotated and listed in the job summary with the commit it set the ref to.
+func reportToActions(actions *githubactions.Reporter, refName string, verifyErr error, explanation *policy.Explanation) error {
+	if actions == nil {
+		return nil
+	}
+
+	summary := &strings.Builder{}
+	fmt.Fprintf(summary, "### gittuf verify-ref\n\n| Ref | Result |\n| --- | --- |\n| `%s` | %s |\n", githubactions.TableCell(refName), common.ActionsResult(verifyErr == nil))
+
+	if verifyErr == nil {
+		return errors.Join(
+			actions.SetOutput("verified", "true"),
+			actions.AddSummary(summary.String()),
+		)
+	}
+
+	if len(explanation.FailedEntries) == 0 {
+		actions.Error(fmt.Sprintf("gittuf verification failed for %s", refName), verifyErr.Error())
+		fmt.Fprintf(summary, "\nError: %s\n", githubactions.TableCell(verifyErr.Error()))
+	} else {
+		fmt.Fprint(summary, "\n| RSL entry | Ref | Target | Error |\n| --- | --- | --- | --- |\n")
+		for _, entry := range explanation.FailedEntries {
+			actions.Error(fmt.Sprintf("gittuf verification failed for %s at %s", entry.RefName, entry.TargetID), fmt.Sprintf("RSL entry %s: %s", entry.EntryID, entry.Error))
+			fmt.Fprintf(summary, "| `%s` | `%s` | `%s` | %s |\n", entry.EntryID, githubactions.TableCell(entry.RefName), entry.TargetID, githubactions.TableCell(entry.Error))
+		}
+	}
+
+	return errors.Join(
+		actions.SetOutput("verified", "false"),
+		actions.SetOutput("error", verifyErr.Error()),
+		actions.AddSummary(summary.String()),
+	)
+}
+
+// printExplanation writes the checks performed for each RSL entry that failed
+// verification.
+func printExplanation(w io.Writer, explanation *policy.Explanation, labels common.KeyLabels) {
+	if len(explanation.FailedEntries) == 0 {
+		fmt.Fprintln(w, i18n.T("No RSL entries failed verification, the error below was encountered elsewhere in the verification workflow"))
+		return
 	}
 
-	return repo.VerifyRef(cmd.Context(), args[0], o.latestOnly)
+	for _, entry := range explanation.FailedEntries {
+		fmt.Fprint(w, i18n.Sprintf("RSL entry %s for %s (target %s) failed verification\n", entry.EntryID, entry.RefName, entry.TargetID))
+		fmt.Fprint(w, i18n.Sprintf("  Error: %s\n", i18n.TranslateErrorText(entry.Error)))
+
+		for _, check := range entry.Checks {
+			fmt.Fprint(w, i18n.Sprintf("\n  %s Check for %s\n", common.Badge(check.Verified()), check.Namespace))
+			fmt.Fprint(w, i18n.Sprintf("    Object:                  %s\n", check.ObjectID))
+			fmt.Fprint(w, i18n.Sprintf("    Signature:               %s\n", labels.Annotate(check.Signature)))
+			if len(check.Principals) > 0 {
+				fmt.Fprint(w, i18n.Sprintf("    Principals:              %s\n", strings.Join(check.Principals, ", ")))
+			}
+			if len(check.AttestationKeyIDs) == 0 {
+				fmt.Fprintln(w, i18n.T("    Reference authorization: not found"))
+			} else {
+				fmt.Fprint(w, i18n.Sprintf("    Reference authorization: signed by %s\n", strings.Join(labels.DescribeAll(check.AttestationKeyIDs), ", ")))
+			}
+
+			if len(check.Rules) == 0 {
+				fmt.Fprintln(w, i18n.T("    No rules protect this namespace"))
+				continue
+			}
+
+			for _, rule := range check.Rules {
+				fmt.Fprint(w, i18n.Sprintf("    Rule %s\n", rule.Name))
+				fmt.Fprint(w, i18n.Sprintf("      Threshold:     %d\n", rule.Threshold))
+				fmt.Fprintln(w, i18n.T("      Trusted keys:"))
+				for _, keyID := range rule.KeyIDs {
+					fmt.Fprintf(w, "        %s\n", labels.Describe(keyID))
+				}
+				if rule.Rejection == "" {
+					fmt.Fprintln(w, i18n.T("      Result:        conditions met"))
+				} else {
+					fmt.Fprint(w, i18n.Sprintf("      Result:        rejected, %s\n", i18n.TranslateErrorText(rule.Rejection)))
+				}
+			}
+		}
+		fmt.Fprintln(w)
+	}
 }
 
 func New() *cobra.Command {
@@ -56,6 +390,7 @@ func New() *cobra.Command {
 		Use:               "verify-ref",
 		Short:             "Tools for verifying gittuf policies",
 		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.CompleteArgs(common.CompleteRefs),
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
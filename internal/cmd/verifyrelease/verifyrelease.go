@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifyrelease
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/i18n"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	rekorURL string
+	format   string
+}
+
+type verificationOutput struct {
+	Tag       string   `json:"tag"`
+	Artifacts []string `json:"artifacts"`
+	Verified  bool     `json:"verified"`
+	Error     string   `json:"error,omitempty"`
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to verify release attestations were logged to",
+	)
+
+	common.AddFormatFlag(cmd, &o.format)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
+	repo, err := repository.LoadRepository()
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	version, artifacts := args[0], args[1:]
+	err = repo.VerifyRelease(ctx, version, artifacts)
+
+	if o.format == common.FormatJSON {
+		output := &verificationOutput{Tag: repository.ReleaseTagName(version), Artifacts: artifacts, Verified: err == nil}
+		if err != nil {
+			output.Error = err.Error()
+		}
+		if err := common.PrintJSON(output); err != nil {
+			return err
+		}
+	} else if err == nil {
+		for _, artifact := range artifacts {
+			fmt.Fprint(cmd.OutOrStdout(), i18n.Sprintf("%s %s was built from %s\n", common.Badge(true), artifact, repository.ReleaseTagName(version)))
+		}
+	}
+
+	return err
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "verify-release <version> <artifact>...",
+		Short:             "Verify downloaded release artifacts using the repository's gittuf policy",
+		Long:              `This command verifies release artifacts, such as downloaded gittuf binaries, using the gittuf policy of the repository they were released from, and must be run in a clone of that repository. The release's tag, the version prefixed with "v" if necessary, is verified against the repository's policy and RSL. Each artifact must then have a rebuild attestation for the tagged commit, its file name, and its SHA-256 digest, signed by a threshold of the rebuilders trusted in the predicate policy for rebuild attestations.`,
+		Args:              cobra.MinimumNArgs(2),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -5,24 +5,141 @@ package verifytag
 import (
 	"fmt"
 
+	"github.com/gittuf/gittuf/internal/archivista"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/githubactions"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/report"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
-type options struct{}
+type options struct {
+	allowedSigners         bool
+	checkRevocations       bool
+	revocationCertificates []string
+	archivistaURL          string
+	rekorURL               string
+	format                 string
+	reportFile             string
+	reportFormat           string
+}
+
+type statusOutput struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.allowedSigners,
+		"allowed-signers",
+		false,
+		"require SSH signatures to be issued by keys in Git's gpg.ssh.allowedSignersFile, honoring their validity periods",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.checkRevocations,
+		"check-revocations",
+		false,
+		"reject signatures issued by keys in Git's gpg.ssh.revocationFile or revoked in the GnuPG keyring",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.revocationCertificates,
+		"revocation-certificate",
+		nil,
+		"GPG revocation certificate of a key whose signatures are rejected, implies --check-revocations",
+	)
+
+	cmd.Flags().StringVar(
+		&o.archivistaURL,
+		"archivista-url",
+		"",
+		"Archivista instance to search for attestations missing in the repository",
+	)
 
-func (o *options) AddFlags(_ *cobra.Command) {}
+	cmd.Flags().StringVar(
+		&o.rekorURL,
+		"rekor-url",
+		"",
+		"Rekor instance to verify attestations were logged to",
+	)
+
+	common.AddFormatFlag(cmd, &o.format)
+	common.AddReportFlags(cmd, &o.reportFile, &o.reportFormat)
+}
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+	if err := common.CheckReportFormat(o.reportFormat); err != nil {
+		return err
+	}
+
 	repo, err := repository.LoadRepository()
 	if err != nil {
 		return err
 	}
 
-	status := repo.VerifyTag(cmd.Context(), args)
+	ctx := cmd.Context()
+	if o.allowedSigners {
+		allowedSigners, err := gitinterface.LoadAllowedSignersFromConfig()
+		if err != nil {
+			return err
+		}
+		ctx = gitinterface.ContextWithAllowedSigners(ctx, allowedSigners)
+	}
+	if o.checkRevocations || len(o.revocationCertificates) != 0 {
+		revocations, err := gitinterface.LoadRevocationsFromConfig()
+		if err != nil {
+			return err
+		}
+		for _, path := range o.revocationCertificates {
+			if err := revocations.LoadGPGRevocationCertificate(path); err != nil {
+				return err
+			}
+		}
+		ctx = gitinterface.ContextWithRevocations(ctx, revocations)
+	}
+	if o.archivistaURL != "" {
+		ctx = archivista.ContextWithClient(ctx, archivista.NewClient(o.archivistaURL))
+	}
+	if o.rekorURL != "" {
+		ctx = rekor.ContextWithClient(ctx, rekor.NewClient(o.rekorURL))
+	}
+
+	var verificationReport *report.Report
+	if o.reportFile != "" {
+		verificationReport = report.New("verify-tag")
+		ctx = report.ContextWithReport(ctx, verificationReport)
+	}
+
+	status := repo.VerifyTag(ctx, args)
+
+	verificationReport.Finish(nil)
+	if err := common.WriteReport(o.reportFile, o.reportFormat, verificationReport); err != nil {
+		return fmt.Errorf("unable to write report: %w", err)
+	}
+
+	if err := common.ReportStatusesToActions(githubactions.Detect(cmd.ErrOrStderr()), "verify-tag", args, status); err != nil {
+		return fmt.Errorf("unable to report results to GitHub Actions: %w", err)
+	}
+
+	if o.format == common.FormatJSON {
+		output := make([]*statusOutput, 0, len(args))
+		for _, id := range args {
+			output = append(output, &statusOutput{ID: id, Status: status[id]})
+		}
+
+		return common.PrintJSON(output)
+	}
 
+	labels := common.LoadKeyLabels(cmd.Context(), repo)
 	for _, id := range args {
-		fmt.Printf("%s: %s\n", id, status[id])
+		fmt.Printf("%s: %s\n", id, labels.Annotate(status[id]))
 	}
 
 	return nil
@@ -34,6 +151,7 @@ func New() *cobra.Command {
 		Use:               "verify-tag",
 		Short:             "Verify tag signatures using gittuf metadata",
 		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: common.CompleteRefs,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
@@ -5,20 +5,38 @@ package version
 import (
 	"fmt"
 
+	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/version"
 	"github.com/spf13/cobra"
 )
 
-type options struct{}
+type options struct {
+	format string
+}
+
+type versionOutput struct {
+	Version string `json:"version"`
+	DevMode bool   `json:"dev_mode"`
+}
 
-func (o *options) AddFlags(_ *cobra.Command) {}
+func (o *options) AddFlags(cmd *cobra.Command) {
+	common.AddFormatFlag(cmd, &o.format)
+}
 
 func (o *options) Run(_ *cobra.Command, _ []string) error {
+	if err := common.CheckFormat(o.format); err != nil {
+		return err
+	}
+
 	v := version.GetVersion()
 	if v[0] == 'v' {
 		v = v[1:]
 	}
+
+	if o.format == common.FormatJSON {
+		return common.PrintJSON(&versionOutput{Version: v, DevMode: dev.InDevMode()})
+	}
 	fmt.Printf("gittuf version %s\n", v)
 
 	if dev.InDevMode() {
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package codereview imports the approvals that changes received on code review
+// platforms, such as GitHub pull requests and GitLab merge requests, so that
+// they can be recorded in attestations and required by gittuf policies.
+package codereview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const (
+	PlatformGitHub = "github"
+	PlatformGitLab = "gitlab"
+	PlatformGerrit = "gerrit"
+)
+
+var (
+	ErrUnexpectedResponse = errors.New("unexpected response from code review platform")
+	ErrInvalidIdentityMap = errors.New("invalid code review identity map")
+)
+
+// User identifies a user of a code review platform. Principal is the gittuf
+// principal the user is mapped to, if any.
+type User struct {
+	Name      string `json:"name"`
+	Principal string `json:"principal,omitempty"`
+}
+
+// Approval is a reviewer's approval of a change. CommitID is the commit the
+// reviewer approved.
+type Approval struct {
+	Reviewer User   `json:"reviewer"`
+	CommitID string `json:"commitID"`
+}
+
+// Review summarizes a change submitted for code review and the approvals it
+// received. RefName is the branch the change targets, HeadCommit is the latest
+// commit of the change, and TargetID is the commit the branch is moved to when
+// the change is merged. Only the approvals of HeadCommit are included, so that
+// approvals of earlier versions of the change don't count towards policy.
+type Review struct {
+	Platform   string     `json:"platform"`
+	URL        string     `json:"url"`
+	Repository string     `json:"repository"`
+	Number     int        `json:"number"`
+	Title      string     `json:"title,omitempty"`
+	State      string     `json:"state"`
+	RefName    string     `json:"refName"`
+	HeadCommit string     `json:"headCommit"`
+	TargetID   string     `json:"targetID"`
+	Author     User       `json:"author"`
+	Approvals  []Approval `json:"approvals"`
+}
+
+// IdentityMap maps the names of code review platform users to the IDs of the
+// gittuf principals, such as key IDs, that they correspond to. It's encoded as
+// a JSON object.
+type IdentityMap map[string]string
+
+// LoadIdentityMap parses a JSON encoded identity map.
+func LoadIdentityMap(contents []byte) (IdentityMap, error) {
+	identities := IdentityMap{}
+	if err := json.Unmarshal(contents, &identities); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIdentityMap, err)
+	}
+
+	for name, principal := range identities {
+		if name == "" || principal == "" {
+			return nil, fmt.Errorf("%w: user names and principals must not be empty", ErrInvalidIdentityMap)
+		}
+	}
+
+	return identities, nil
+}
+
+// MapIdentities sets the principal of the review's author and approvers using
+// the identity map. Users missing in the map are left unmapped.
+func (r *Review) MapIdentities(identities IdentityMap) {
+	r.Author.Principal = identities[r.Author.Name]
+	for i := range r.Approvals {
+		r.Approvals[i].Reviewer.Principal = identities[r.Approvals[i].Reviewer.Name]
+	}
+}
+
+// ApprovingPrincipals returns the distinct principals who approved the review's
+// head commit, excluding the review's author. Approvals by users who aren't
+// mapped to principals are ignored.
+func (r *Review) ApprovingPrincipals() []string {
+	seen := map[string]bool{}
+	principals := []string{}
+	for _, approval := range r.Approvals {
+		principal := approval.Reviewer.Principal
+		if principal == "" || seen[principal] || approval.CommitID != r.HeadCommit {
+			continue
+		}
+		if principal == r.Author.Principal {
+			// Authors can't approve their own changes
+			continue
+		}
+
+		seen[principal] = true
+		principals = append(principals, principal)
+	}
+
+	sort.Strings(principals)
+	return principals
+}
+
+// sortApprovals orders the approvals by reviewer so that the attestations
+// recording the same approvals are identical.
+func sortApprovals(approvals []Approval) {
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].Reviewer.Name < approvals[j].Reviewer.Name
+	})
+}
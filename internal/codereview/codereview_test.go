@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package codereview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadIdentityMap(t *testing.T) {
+	identities, err := LoadIdentityMap([]byte(`{"alice": "alice-key", "bob": "bob-key"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, IdentityMap{"alice": "alice-key", "bob": "bob-key"}, identities)
+
+	_, err = LoadIdentityMap([]byte(`["alice"]`))
+	assert.ErrorIs(t, err, ErrInvalidIdentityMap)
+
+	_, err = LoadIdentityMap([]byte(`{"alice": ""}`))
+	assert.ErrorIs(t, err, ErrInvalidIdentityMap)
+}
+
+func TestReviewApprovingPrincipals(t *testing.T) {
+	review := &Review{
+		HeadCommit: "head",
+		Author:     User{Name: "jane"},
+		Approvals: []Approval{
+			{Reviewer: User{Name: "alice"}, CommitID: "head"},
+			{Reviewer: User{Name: "alice-alt"}, CommitID: "head"},
+			{Reviewer: User{Name: "bob"}, CommitID: "head"},
+			{Reviewer: User{Name: "carol"}, CommitID: "head"},
+			{Reviewer: User{Name: "dave"}, CommitID: "old"},
+			{Reviewer: User{Name: "jane-alt"}, CommitID: "head"},
+		},
+	}
+
+	assert.Empty(t, review.ApprovingPrincipals())
+
+	review.MapIdentities(IdentityMap{
+		"jane":      "jane-key",
+		"jane-alt":  "jane-key",
+		"alice":     "alice-key",
+		"alice-alt": "alice-key",
+		"bob":       "bob-key",
+		"dave":      "dave-key",
+	})
+	assert.Equal(t, "jane-key", review.Author.Principal)
+
+	// Unmapped reviewers, stale approvals, and approvals by the author are
+	// ignored, and each principal is counted once
+	assert.Equal(t, []string{"alice-key", "bob-key"}, review.ApprovingPrincipals())
+}
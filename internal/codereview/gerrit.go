@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package codereview
+
+import (
+	"github.com/gittuf/gittuf/internal/gerrit"
+)
+
+const (
+	gerritCodeReviewLabel    = "Code-Review"
+	gerritCodeReviewApproval = 2
+)
+
+// FromGerritChange returns the review of the Gerrit change submitted as
+// commitID. Reviewers approved the change if they voted +2 on its Code-Review
+// label, and their approvals are recorded for the change's current patch set.
+// Reviewers are identified by their Gerrit usernames.
+func FromGerritChange(change *gerrit.Change, commitID string) *Review {
+	review := &Review{
+		Platform:   PlatformGerrit,
+		URL:        change.URL,
+		Repository: change.Project,
+		Number:     change.Number,
+		Title:      change.Subject,
+		State:      change.Status,
+		RefName:    change.RefName(),
+		HeadCommit: change.Revision,
+		TargetID:   commitID,
+		Author:     User{Name: change.Owner.Username},
+		Approvals:  []Approval{},
+	}
+
+	for _, approval := range change.Approvals {
+		if approval.Label != gerritCodeReviewLabel || approval.Value < gerritCodeReviewApproval {
+			continue
+		}
+		if approval.Account.Username == review.Author.Name {
+			continue
+		}
+
+		review.Approvals = append(review.Approvals, Approval{
+			Reviewer: User{Name: approval.Account.Username},
+			CommitID: change.Revision,
+		})
+	}
+	sortApprovals(review.Approvals)
+
+	return review
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package codereview
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gerrit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromGerritChange(t *testing.T) {
+	change := &gerrit.Change{
+		URL:      "https://gerrit.example.com/c/project/+/12",
+		Project:  "project",
+		Branch:   "main",
+		Number:   12,
+		Revision: "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f",
+		Status:   "MERGED",
+		Owner:    gerrit.Account{Username: "jane"},
+		Approvals: []gerrit.Approval{
+			{Label: "Code-Review", Value: 2, Account: gerrit.Account{Username: "bob"}},
+			{Label: "Code-Review", Value: 1, Account: gerrit.Account{Username: "carol"}},
+			{Label: "Code-Review", Value: 2, Account: gerrit.Account{Username: "jane"}},
+			{Label: "Verified", Value: 1, Account: gerrit.Account{Username: "ci"}},
+			{Label: "Code-Review", Value: 2, Account: gerrit.Account{Username: "alice"}},
+		},
+	}
+
+	review := FromGerritChange(change, "c0ffee0000000000000000000000000000000000")
+	assert.Equal(t, PlatformGerrit, review.Platform)
+	assert.Equal(t, "refs/heads/main", review.RefName)
+	assert.Equal(t, "c0ffee0000000000000000000000000000000000", review.TargetID)
+	assert.Equal(t, []Approval{
+		{Reviewer: User{Name: "alice"}, CommitID: change.Revision},
+		{Reviewer: User{Name: "bob"}, CommitID: change.Revision},
+	}, review.Approvals)
+}
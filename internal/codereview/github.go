@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package codereview
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/google/go-github/v61/github"
+)
+
+const (
+	gitHubReviewStateApproved         = "APPROVED"
+	gitHubReviewStateChangesRequested = "CHANGES_REQUESTED"
+	gitHubReviewStateDismissed        = "DISMISSED"
+)
+
+// GitHubClient imports the approvals of GitHub pull requests using GitHub's
+// REST API.
+type GitHubClient struct {
+	baseURL     string
+	tokenSource credentials.Source
+}
+
+// NewGitHubClient returns a GitHubClient for github.com, or for the GitHub
+// Enterprise Server instance at baseURL if it's set. Requests are
+// authenticated using the tokens obtained from tokenSource. If no token is
+// available, only pull requests visible to anonymous users can be inspected.
+func NewGitHubClient(baseURL string, tokenSource credentials.Source) *GitHubClient {
+	return &GitHubClient{baseURL: baseURL, tokenSource: tokenSource}
+}
+
+// GetPullRequest returns the review of the GitHub pull request with the
+// specified number in the repository. A reviewer approved the pull request if
+// their latest review that isn't a comment is an approval. Only approvals of
+// the pull request's head commit are included. The pull request's target is
+// its merge commit if it's merged, and its head commit otherwise.
+func (c *GitHubClient) GetPullRequest(ctx context.Context, owner, repository string, number int) (*Review, error) {
+	client, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequest, _, err := client.PullRequests.Get(ctx, owner, repository, number)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &Review{
+		Platform:   PlatformGitHub,
+		URL:        pullRequest.GetHTMLURL(),
+		Repository: fmt.Sprintf("%s/%s", owner, repository),
+		Number:     pullRequest.GetNumber(),
+		Title:      pullRequest.GetTitle(),
+		State:      pullRequest.GetState(),
+		RefName:    gitinterface.BranchRefPrefix + pullRequest.GetBase().GetRef(),
+		HeadCommit: pullRequest.GetHead().GetSHA(),
+		TargetID:   pullRequest.GetHead().GetSHA(),
+		Author:     User{Name: pullRequest.GetUser().GetLogin()},
+		Approvals:  []Approval{},
+	}
+	if pullRequest.MergedAt != nil {
+		review.State = "merged"
+		review.TargetID = pullRequest.GetMergeCommitSHA()
+	}
+
+	// Reviews are listed in the order they were submitted, so the last
+	// review recorded for a reviewer is their latest
+	latestReviews := map[string]*github.PullRequestReview{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		reviews, resp, err := client.PullRequests.ListReviews(ctx, owner, repository, number, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pullRequestReview := range reviews {
+			switch pullRequestReview.GetState() {
+			case gitHubReviewStateApproved, gitHubReviewStateChangesRequested, gitHubReviewStateDismissed:
+				latestReviews[pullRequestReview.GetUser().GetLogin()] = pullRequestReview
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	for login, pullRequestReview := range latestReviews {
+		if pullRequestReview.GetState() != gitHubReviewStateApproved || pullRequestReview.GetCommitID() != review.HeadCommit {
+			continue
+		}
+		if login == review.Author.Name {
+			continue
+		}
+
+		review.Approvals = append(review.Approvals, Approval{
+			Reviewer: User{Name: login},
+			CommitID: pullRequestReview.GetCommitID(),
+		})
+	}
+	sortApprovals(review.Approvals)
+
+	return review, nil
+}
+
+func (c *GitHubClient) client(ctx context.Context) (*github.Client, error) {
+	client := github.NewClient(nil)
+	if c.baseURL != "" {
+		var err error
+		client, err = client.WithEnterpriseURLs(c.baseURL, c.baseURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.tokenSource == nil {
+		return client, nil
+	}
+
+	token, err := c.tokenSource.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return client, nil
+	}
+
+	return client.WithAuthToken(token.Value), nil
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package codereview
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testGitHubPullRequestResponse = `{
+  "number": 7,
+  "title": "Add feature",
+  "state": "closed",
+  "html_url": "https://github.com/gittuf/gittuf/pull/7",
+  "merged_at": "2024-05-01T10:00:00Z",
+  "merge_commit_sha": "c0ffee0000000000000000000000000000000000",
+  "user": {"login": "jane"},
+  "head": {"ref": "feature", "sha": "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+  "base": {"ref": "main", "sha": "1b2c3d4e5f60718293a4b5c6d7e8f9012a3b4c5d"}
+}`
+
+	testGitHubReviewsFirstPage = `[
+  {"user": {"login": "alice"}, "state": "APPROVED", "commit_id": "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+  {"user": {"login": "bob"}, "state": "APPROVED", "commit_id": "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+  {"user": {"login": "carol"}, "state": "APPROVED", "commit_id": "0000000000000000000000000000000000000001"}
+]`
+
+	testGitHubReviewsSecondPage = `[
+  {"user": {"login": "alice"}, "state": "COMMENTED", "commit_id": "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+  {"user": {"login": "bob"}, "state": "CHANGES_REQUESTED", "commit_id": "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+  {"user": {"login": "jane"}, "state": "APPROVED", "commit_id": "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+  {"user": {"login": "dave"}, "state": "APPROVED", "commit_id": "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"}
+]`
+)
+
+func TestGitHubClientGetPullRequest(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v3/repos/gittuf/gittuf/pulls/7":
+			w.Write([]byte(testGitHubPullRequestResponse)) //nolint:errcheck
+		case "/api/v3/repos/gittuf/gittuf/pulls/7/reviews":
+			if r.URL.Query().Get("page") == "2" {
+				w.Write([]byte(testGitHubReviewsSecondPage)) //nolint:errcheck
+				return
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/gittuf/gittuf/pulls/7/reviews?page=2>; rel="next"`, server.URL))
+			w.Write([]byte(testGitHubReviewsFirstPage)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, credentials.Static("access-token"))
+
+	review, err := client.GetPullRequest(context.Background(), "gittuf", "gittuf", 7)
+	assert.Nil(t, err)
+	assert.Equal(t, PlatformGitHub, review.Platform)
+	assert.Equal(t, "https://github.com/gittuf/gittuf/pull/7", review.URL)
+	assert.Equal(t, "gittuf/gittuf", review.Repository)
+	assert.Equal(t, "merged", review.State)
+	assert.Equal(t, "refs/heads/main", review.RefName)
+	assert.Equal(t, "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f", review.HeadCommit)
+	assert.Equal(t, "c0ffee0000000000000000000000000000000000", review.TargetID)
+	assert.Equal(t, "jane", review.Author.Name)
+
+	// alice's comment doesn't withdraw her approval, bob's later review does,
+	// carol approved an earlier commit, and jane authored the pull request
+	assert.Equal(t, []Approval{
+		{Reviewer: User{Name: "alice"}, CommitID: "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+		{Reviewer: User{Name: "dave"}, CommitID: "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+	}, review.Approvals)
+
+	_, err = client.GetPullRequest(context.Background(), "gittuf", "gittuf", 8)
+	assert.NotNil(t, err)
+}
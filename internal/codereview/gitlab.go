@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package codereview
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+)
+
+const gitLabMergeRequestStateMerged = "merged"
+
+// GitLabClient is a minimal client for the parts of GitLab's REST API used to
+// import the approvals of merge requests.
+type GitLabClient struct {
+	baseURL     string
+	tokenSource credentials.Source
+	httpClient  *http.Client
+}
+
+// NewGitLabClient returns a GitLabClient for the GitLab instance at baseURL.
+// Requests are authenticated using the tokens obtained from tokenSource. If no
+// token is available, only merge requests visible to anonymous users can be
+// inspected.
+func NewGitLabClient(baseURL string, tokenSource credentials.Source) *GitLabClient {
+	return &GitLabClient{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		tokenSource: tokenSource,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type gitLabUser struct {
+	Username string `json:"username"`
+}
+
+type gitLabMergeRequest struct {
+	IID             int        `json:"iid"`
+	Title           string     `json:"title"`
+	State           string     `json:"state"`
+	TargetBranch    string     `json:"target_branch"`
+	SHA             string     `json:"sha"`
+	MergeCommitSHA  string     `json:"merge_commit_sha"`
+	SquashCommitSHA string     `json:"squash_commit_sha"`
+	WebURL          string     `json:"web_url"`
+	Author          gitLabUser `json:"author"`
+}
+
+type gitLabApprovals struct {
+	ApprovedBy []struct {
+		User gitLabUser `json:"user"`
+	} `json:"approved_by"`
+}
+
+// GetMergeRequest returns the review of the merge request with the specified
+// IID in the project, which may be identified by its ID or its path. GitLab
+// doesn't record the commit each approval was given for, so the approvals are
+// recorded for the merge request's head commit. Projects should be configured
+// to remove approvals when commits are added to merge requests. The merge
+// request's target is the commit it was merged as if it's merged, and its head
+// commit otherwise.
+func (c *GitLabClient) GetMergeRequest(ctx context.Context, project string, mergeRequestIID int) (*Review, error) {
+	mergeRequestPath := fmt.Sprintf("projects/%s/merge_requests/%d", url.PathEscape(project), mergeRequestIID)
+
+	mergeRequest := &gitLabMergeRequest{}
+	if err := c.get(ctx, mergeRequestPath, mergeRequest); err != nil {
+		return nil, err
+	}
+
+	approvals := &gitLabApprovals{}
+	if err := c.get(ctx, mergeRequestPath+"/approvals", approvals); err != nil {
+		return nil, err
+	}
+
+	review := &Review{
+		Platform:   PlatformGitLab,
+		URL:        mergeRequest.WebURL,
+		Repository: project,
+		Number:     mergeRequest.IID,
+		Title:      mergeRequest.Title,
+		State:      mergeRequest.State,
+		RefName:    gitinterface.BranchRefPrefix + mergeRequest.TargetBranch,
+		HeadCommit: mergeRequest.SHA,
+		TargetID:   mergeRequest.SHA,
+		Author:     User{Name: mergeRequest.Author.Username},
+		Approvals:  []Approval{},
+	}
+	if mergeRequest.State == gitLabMergeRequestStateMerged {
+		// Merge requests merged by fast-forwarding have neither, and the
+		// target branch is moved to the head commit
+		switch {
+		case mergeRequest.MergeCommitSHA != "":
+			review.TargetID = mergeRequest.MergeCommitSHA
+		case mergeRequest.SquashCommitSHA != "":
+			review.TargetID = mergeRequest.SquashCommitSHA
+		}
+	}
+
+	for _, approval := range approvals.ApprovedBy {
+		if approval.User.Username == review.Author.Name {
+			continue
+		}
+
+		review.Approvals = append(review.Approvals, Approval{
+			Reviewer: User{Name: approval.User.Username},
+			CommitID: mergeRequest.SHA,
+		})
+	}
+	sortApprovals(review.Approvals)
+
+	return review, nil
+}
+
+func (c *GitLabClient) get(ctx context.Context, path string, response any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v4/"+path, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	if c.tokenSource != nil {
+		token, err := c.tokenSource.Token(ctx)
+		if err != nil {
+			return err
+		}
+		if token != nil {
+			if token.JobToken {
+				request.Header.Set("JOB-TOKEN", token.Value)
+			} else {
+				request.Header.Set("PRIVATE-TOKEN", token.Value)
+			}
+		}
+	}
+
+	resp, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: GET %s returned %s: %s", ErrUnexpectedResponse, path, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(response)
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package codereview
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testGitLabMergeRequestResponse = `{
+  "iid": 3,
+  "title": "Add feature",
+  "state": "merged",
+  "target_branch": "main",
+  "sha": "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f",
+  "merge_commit_sha": null,
+  "squash_commit_sha": "c0ffee0000000000000000000000000000000000",
+  "web_url": "https://gitlab.example.com/group/project/-/merge_requests/3",
+  "author": {"username": "jane"}
+}`
+
+	testGitLabApprovalsResponse = `{
+  "approved_by": [
+    {"user": {"username": "bob"}},
+    {"user": {"username": "jane"}},
+    {"user": {"username": "alice"}}
+  ]
+}`
+)
+
+func TestGitLabClientGetMergeRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fproject/merge_requests/3":
+			w.Write([]byte(testGitLabMergeRequestResponse)) //nolint:errcheck
+		case "/api/v4/projects/group%2Fproject/merge_requests/3/approvals":
+			w.Write([]byte(testGitLabApprovalsResponse)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("authenticated", func(t *testing.T) {
+		client := NewGitLabClient(server.URL+"/", credentials.Static("access-token"))
+
+		review, err := client.GetMergeRequest(context.Background(), "group/project", 3)
+		assert.Nil(t, err)
+		assert.Equal(t, PlatformGitLab, review.Platform)
+		assert.Equal(t, "https://gitlab.example.com/group/project/-/merge_requests/3", review.URL)
+		assert.Equal(t, "refs/heads/main", review.RefName)
+		assert.Equal(t, "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f", review.HeadCommit)
+		assert.Equal(t, "c0ffee0000000000000000000000000000000000", review.TargetID)
+		assert.Equal(t, []Approval{
+			{Reviewer: User{Name: "alice"}, CommitID: "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+			{Reviewer: User{Name: "bob"}, CommitID: "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"},
+		}, review.Approvals)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		client := NewGitLabClient(server.URL, nil)
+
+		_, err := client.GetMergeRequest(context.Background(), "group/project", 3)
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/jonboulle/clockwork"
 )
@@ -234,7 +235,7 @@ func AddNTestCommitsToSpecifiedRef(t *testing.T, repo *git.Repository, refName s
 	for i := 1; i <= n; i++ {
 		objects := make([]object.TreeEntry, 0, i)
 		for j := 0; j < i; j++ {
-			objects = append(objects, object.TreeEntry{Name: fmt.Sprintf("%d", j+1), Hash: emptyBlobHash})
+			objects = append(objects, object.TreeEntry{Name: fmt.Sprintf("%d", j+1), Mode: filemode.Regular, Hash: emptyBlobHash})
 		}
 
 		treeHash, err := gitinterface.WriteTree(repo, objects)
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package compression implements the transparent compression of the policy
+// metadata and attestation blobs gittuf stores in Git. Compressed blobs start
+// with a header line that names the compression algorithm, followed by the
+// compressed contents:
+//
+//	gittuf-compression: zstd
+//	<zstd frame>
+//
+// Uncompressed blobs are JSON documents, which can't start with the header, so
+// readers can always tell the two apart. Blobs are only compressed once a
+// repository's root of trust enables compression, as older versions of gittuf
+// can't read compressed blobs.
+package compression
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// AlgorithmZstd compresses blobs using Zstandard.
+	AlgorithmZstd = "zstd"
+
+	// MinSize is the size in bytes below which blobs aren't compressed, as
+	// the savings don't outweigh the cost of decompressing them.
+	MinSize = 4096
+
+	// maxDecompressedSize limits the memory used to decompress a blob, so
+	// that a malicious blob can't exhaust the memory of the verifier.
+	maxDecompressedSize = 256 << 20
+
+	headerPrefix = "gittuf-compression: "
+)
+
+var ErrUnsupportedAlgorithm = errors.New("unsupported compression algorithm")
+
+var (
+	zstdEncoder     *zstd.Encoder
+	zstdDecoder     *zstd.Decoder
+	zstdInitErr     error
+	zstdInitialized sync.Once
+)
+
+// IsSupported returns true if the algorithm can be used to compress blobs.
+func IsSupported(algorithm string) bool {
+	return algorithm == AlgorithmZstd
+}
+
+// Compress compresses the contents of a blob using the specified algorithm,
+// adding the header that identifies it. The contents are returned as is if
+// algorithm is empty or they're smaller than MinSize.
+func Compress(algorithm string, contents []byte) ([]byte, error) {
+	if algorithm == "" || len(contents) < MinSize {
+		return contents, nil
+	}
+
+	if !IsSupported(algorithm) {
+		return nil, fmt.Errorf("%w: '%s'", ErrUnsupportedAlgorithm, algorithm)
+	}
+
+	encoder, _, err := getZstd()
+	if err != nil {
+		return nil, err
+	}
+
+	compressed := []byte(headerPrefix + algorithm + "\n")
+	return encoder.EncodeAll(contents, compressed), nil
+}
+
+// Decompress returns the contents of a blob written using Compress. Blobs that
+// aren't compressed are returned as is.
+func Decompress(contents []byte) ([]byte, error) {
+	if !bytes.HasPrefix(contents, []byte(headerPrefix)) {
+		return contents, nil
+	}
+
+	header, compressed, found := bytes.Cut(contents, []byte("\n"))
+	if !found {
+		return nil, fmt.Errorf("%w: missing compressed contents", ErrUnsupportedAlgorithm)
+	}
+
+	algorithm := string(bytes.TrimPrefix(header, []byte(headerPrefix)))
+	if !IsSupported(algorithm) {
+		return nil, fmt.Errorf("%w: '%s'", ErrUnsupportedAlgorithm, algorithm)
+	}
+
+	_, decoder, err := getZstd()
+	if err != nil {
+		return nil, err
+	}
+
+	return decoder.DecodeAll(compressed, nil)
+}
+
+func getZstd() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdInitialized.Do(func() {
+		zstdEncoder, zstdInitErr = zstd.NewWriter(nil)
+		if zstdInitErr != nil {
+			return
+		}
+
+		zstdDecoder, zstdInitErr = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedSize))
+	})
+
+	return zstdEncoder, zstdDecoder, zstdInitErr
+}
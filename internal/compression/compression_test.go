@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package compression
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompress(t *testing.T) {
+	largeContents := []byte(`{"keys":[` + strings.Repeat(`"52e3b8e73279d6ebdd62a5016e2725ff284f569665eb92ccb145d83817a02997",`, 100) + `""]}`)
+	smallContents := []byte(`{"type":"targets"}`)
+
+	t.Run("large blob", func(t *testing.T) {
+		compressed, err := Compress(AlgorithmZstd, largeContents)
+		assert.Nil(t, err)
+		assert.True(t, bytes.HasPrefix(compressed, []byte("gittuf-compression: zstd\n")))
+		assert.Less(t, len(compressed), len(largeContents))
+
+		decompressed, err := Decompress(compressed)
+		assert.Nil(t, err)
+		assert.Equal(t, largeContents, decompressed)
+	})
+
+	t.Run("small blob", func(t *testing.T) {
+		compressed, err := Compress(AlgorithmZstd, smallContents)
+		assert.Nil(t, err)
+		assert.Equal(t, smallContents, compressed)
+	})
+
+	t.Run("compression disabled", func(t *testing.T) {
+		compressed, err := Compress("", largeContents)
+		assert.Nil(t, err)
+		assert.Equal(t, largeContents, compressed)
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		_, err := Compress("gzip", largeContents)
+		assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	})
+}
+
+func TestDecompress(t *testing.T) {
+	t.Run("uncompressed blob", func(t *testing.T) {
+		contents := []byte(`{"type":"targets"}`)
+		decompressed, err := Decompress(contents)
+		assert.Nil(t, err)
+		assert.Equal(t, contents, decompressed)
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		_, err := Decompress([]byte("gittuf-compression: gzip\ncontents"))
+		assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	})
+
+	t.Run("missing contents", func(t *testing.T) {
+		_, err := Decompress([]byte("gittuf-compression: zstd"))
+		assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	})
+
+	t.Run("corrupt contents", func(t *testing.T) {
+		_, err := Decompress([]byte("gittuf-compression: zstd\nnot zstd"))
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,245 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	configDirName  = "gittuf"
+	configFileName = "config"
+)
+
+var (
+	ErrInvalidConfig         = errors.New("invalid gittuf config")
+	ErrUnknownSigningProfile = errors.New("unknown signing profile")
+)
+
+// Config contains the user's defaults for gittuf commands. It is loaded from
+// the user's config file, ~/.config/gittuf/config, and the repository's config
+// file, $GIT_DIR/gittuf/config, with the latter taking precedence. For linked
+// worktrees, the repository's config file is in the main repository's GIT_DIR.
+// Both files are JSON encoded, and fields that aren't set leave the defaults
+// unchanged.
+type Config struct {
+	// Signer is the format of signatures created by gittuf, one of "gpg",
+	// "ssh", or "x509". It takes precedence over Git's gpg.format option.
+	Signer string `json:"signer,omitempty"`
+
+	// FulcioURL is the Fulcio instance to request signing certificates from.
+	FulcioURL string `json:"fulcio_url,omitempty"`
+
+	// RekorURL is the Rekor instance to log attestations to and to verify
+	// attestations were logged to.
+	RekorURL string `json:"rekor_url,omitempty"`
+
+	// ArchivistaURL is the Archivista instance to search for attestations
+	// missing in the repository.
+	ArchivistaURL string `json:"archivista_url,omitempty"`
+
+	// TimestampAuthorityURL is the RFC 3161 timestamp authority used to
+	// timestamp the signatures of RSL entries and policy metadata.
+	TimestampAuthorityURL string `json:"timestamp_authority_url,omitempty"`
+
+	// TimestampRekorURL is the Rekor instance used to timestamp the
+	// signatures of RSL entries and policy metadata if no timestamp authority
+	// is set.
+	TimestampRekorURL string `json:"timestamp_rekor_url,omitempty"`
+
+	// Offline indicates that the Rekor, Archivista, and timestamping
+	// instances set in the config must not be used, so that commands don't
+	// access the network unless requested explicitly using flags. Sigstore signatures are
+	// verified offline using the Sigstore trusted root.
+	Offline bool `json:"offline,omitempty"`
+
+	// SigstoreTrustedRoot is the path to the Sigstore trusted root used to
+	// verify Sigstore signatures offline. If it isn't set, the trusted root
+	// cached by Sigstore's TUF client is used in offline mode.
+	SigstoreTrustedRoot string `json:"sigstore_trusted_root,omitempty"`
+
+	// SMIMETrustStore is the path to a PEM file or a directory of PEM files
+	// with the root certificates trusted to verify S/MIME signatures. It must
+	// be set to verify S/MIME signatures, as the system's roots aren't used.
+	SMIMETrustStore string `json:"smime_trust_store,omitempty"`
+
+	// Format is the output format of commands that report information.
+	Format string `json:"format,omitempty"`
+
+	// Color indicates if verification results are colored.
+	Color string `json:"color,omitempty"`
+
+	// NotifyWebhookURL is the URL a JSON payload describing the failure is
+	// POSTed to when verification fails.
+	NotifyWebhookURL string `json:"notify_webhook_url,omitempty"`
+
+	// NotifyCommand is a shell command invoked with a JSON payload describing
+	// the failure on stdin when verification fails.
+	NotifyCommand string `json:"notify_command,omitempty"`
+
+	// SigningProfile is the name of the signing profile used when none is
+	// selected for the command.
+	SigningProfile string `json:"signing_profile,omitempty"`
+
+	// SigningProfiles contains the user's signing identities by name.
+	SigningProfiles map[string]*SigningProfile `json:"signing_profiles,omitempty"`
+
+	// Credentials configures how integrations, such as "github", obtain the
+	// tokens used to access the services they fetch attestations and
+	// approvals from.
+	Credentials map[string]*Credential `json:"credentials,omitempty"`
+}
+
+// Credential configures how a token for a service is obtained.
+type Credential struct {
+	// Type is how the token is obtained, one of "env", "github-app",
+	// "gitlab-job-token", or "oidc-exchange".
+	Type string `json:"type"`
+
+	// Env is the environment variable the token is read from, used with the
+	// "env" type.
+	Env string `json:"env,omitempty"`
+
+	// APIURL is the API URL of a GitHub Enterprise Server instance, used with
+	// the "github-app" type.
+	APIURL string `json:"api_url,omitempty"`
+
+	// AppID is the ID of the GitHub App, used with the "github-app" type.
+	AppID int64 `json:"app_id,omitempty"`
+
+	// InstallationID is the ID of the GitHub App's installation whose token
+	// is used, used with the "github-app" type.
+	InstallationID int64 `json:"installation_id,omitempty"`
+
+	// PrivateKey is the path to the GitHub App's PEM encoded private key,
+	// used with the "github-app" type.
+	PrivateKey string `json:"private_key,omitempty"`
+
+	// TokenURL is the URL of the OAuth 2.0 token exchange endpoint that OIDC
+	// identity tokens are exchanged at, used with the "oidc-exchange" type.
+	TokenURL string `json:"token_url,omitempty"`
+
+	// Audience is the audience of the OIDC identity token requested from the
+	// CI environment, used with the "oidc-exchange" type.
+	Audience string `json:"audience,omitempty"`
+
+	// IDTokenEnv is the environment variable the OIDC identity token is read
+	// from instead of being requested from the CI environment, such as a
+	// variable set using GitLab CI's `id_tokens`. It is used with the
+	// "oidc-exchange" type.
+	IDTokenEnv string `json:"id_token_env,omitempty"`
+
+	// Scope is the scope requested for the exchanged token, used with the
+	// "oidc-exchange" type.
+	Scope string `json:"scope,omitempty"`
+}
+
+// SigningProfile is a named signing identity, such as a hardware token used
+// for work repositories or a Sigstore identity used in CI. Its fields take
+// precedence over the corresponding fields of the config.
+type SigningProfile struct {
+	// Signer is the format of signatures created by gittuf, one of "gpg",
+	// "ssh", or "x509".
+	Signer string `json:"signer,omitempty"`
+
+	// GitSigningKey identifies the key used to sign Git objects such as RSL
+	// entries, in the format of Git's user.signingKey option.
+	GitSigningKey string `json:"git_signing_key,omitempty"`
+
+	// SigningProgram is the program used to sign Git objects.
+	SigningProgram string `json:"signing_program,omitempty"`
+
+	// SigningKey is the signing key used to sign metadata and attestations.
+	SigningKey string `json:"signing_key,omitempty"`
+
+	// FulcioURL is the Fulcio instance to request signing certificates from.
+	FulcioURL string `json:"fulcio_url,omitempty"`
+}
+
+// GetSigningProfile returns the signing profile with the specified name. If
+// name is empty, the config's default signing profile is returned, and nil is
+// returned if no default is set.
+func (c *Config) GetSigningProfile(name string) (*SigningProfile, error) {
+	if name == "" {
+		name = c.SigningProfile
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	profile, has := c.SigningProfiles[name]
+	if !has {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownSigningProfile, name)
+	}
+
+	return profile, nil
+}
+
+// Load returns the config in the user's config file, overridden by the config
+// in the repository's config file if gitDir is specified. Config files that
+// don't exist are ignored.
+func Load(gitDir string) (*Config, error) {
+	config := &Config{}
+
+	userConfigPath, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := loadFile(userConfigPath, config); err != nil {
+		return nil, err
+	}
+
+	if gitDir != "" {
+		if err := loadFile(RepositoryConfigPath(gitDir), config); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// UserConfigPath returns the path of the user's config file. If set,
+// XDG_CONFIG_HOME is used instead of ~/.config.
+func UserConfigPath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+
+	return filepath.Join(configHome, configDirName, configFileName), nil
+}
+
+// RepositoryConfigPath returns the path of the config file for the repository
+// with the specified GIT_DIR.
+func RepositoryConfigPath(gitDir string) string {
+	return filepath.Join(gitDir, configDirName, configFileName)
+}
+
+// loadFile decodes the config file at path into config, overwriting the
+// fields set in the file.
+func loadFile(path string, config *Config) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return errors.Join(ErrInvalidConfig, fmt.Errorf("%s: %w", path, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	writeConfig := func(t *testing.T, path, contents string) {
+		t.Helper()
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("no config files", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		config, err := Load(t.TempDir())
+		assert.Nil(t, err)
+		assert.Equal(t, &Config{}, config)
+	})
+
+	t.Run("user config only", func(t *testing.T) {
+		configHome := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+
+		writeConfig(t, filepath.Join(configHome, "gittuf", "config"), `{"signer": "ssh", "rekor_url": "https://rekor.example.com", "offline": true}`)
+
+		config, err := Load("")
+		assert.Nil(t, err)
+		assert.Equal(t, &Config{Signer: "ssh", RekorURL: "https://rekor.example.com", Offline: true}, config)
+	})
+
+	t.Run("repository config overrides user config", func(t *testing.T) {
+		configHome := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+		gitDir := t.TempDir()
+
+		writeConfig(t, filepath.Join(configHome, "gittuf", "config"), `{"signer": "ssh", "format": "json", "offline": true}`)
+		writeConfig(t, RepositoryConfigPath(gitDir), `{"signer": "gpg", "offline": false}`)
+
+		config, err := Load(gitDir)
+		assert.Nil(t, err)
+		assert.Equal(t, &Config{Signer: "gpg", Format: "json", Offline: false}, config)
+	})
+
+	t.Run("repository config selects signing profile", func(t *testing.T) {
+		configHome := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+		gitDir := t.TempDir()
+
+		writeConfig(t, filepath.Join(configHome, "gittuf", "config"), `{"signing_profile": "personal", "signing_profiles": {"personal": {"signer": "ssh"}, "work": {"signer": "gpg"}}}`)
+		writeConfig(t, RepositoryConfigPath(gitDir), `{"signing_profile": "work"}`)
+
+		config, err := Load(gitDir)
+		assert.Nil(t, err)
+
+		profile, err := config.GetSigningProfile("")
+		assert.Nil(t, err)
+		assert.Equal(t, "gpg", profile.Signer)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		configHome := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+
+		writeConfig(t, filepath.Join(configHome, "gittuf", "config"), `{"singer": "ssh"}`)
+
+		_, err := Load("")
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	})
+}
+
+func TestGetSigningProfile(t *testing.T) {
+	work := &SigningProfile{Signer: "gpg", GitSigningKey: "ABCDEF"}
+	ci := &SigningProfile{Signer: "x509", SigningProgram: "gitsign"}
+	config := &Config{
+		SigningProfile:  "work",
+		SigningProfiles: map[string]*SigningProfile{"work": work, "ci": ci},
+	}
+
+	profile, err := config.GetSigningProfile("")
+	assert.Nil(t, err)
+	assert.Equal(t, work, profile)
+
+	profile, err = config.GetSigningProfile("ci")
+	assert.Nil(t, err)
+	assert.Equal(t, ci, profile)
+
+	_, err = config.GetSigningProfile("personal")
+	assert.ErrorIs(t, err, ErrUnknownSigningProfile)
+
+	profile, err = (&Config{}).GetSigningProfile("")
+	assert.Nil(t, err)
+	assert.Nil(t, profile)
+}
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package credentials
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/logging"
+)
+
+// logger logs the messages of the credentials subsystem.
+var logger = logging.For("credentials")
+
+var errInvalidCachedToken = errors.New("invalid cached token")
+
+var cacheDir = defaultCacheDir
+
+// defaultCacheDir returns the directory cached tokens are stored in.
+func defaultCacheDir() (string, error) {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(userCacheDir, "gittuf", "credentials"), nil
+}
+
+type cachedSource struct {
+	source Source
+	path   string
+
+	token *Token
+	mu    sync.Mutex
+}
+
+// Cached returns a source that caches the tokens returned by source until they
+// expire, both in memory and in the user's cache directory so that they are
+// reused across invocations of gittuf. The tokens are cached for the
+// integration and the credential it is configured with, so changing the
+// credential invalidates the cache. Tokens that don't expire are not stored.
+func Cached(source Source, integration string, credential any) (Source, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	credentialBytes, err := json.Marshal(credential)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(append([]byte(integration+"\x00"), credentialBytes...))
+
+	return &cachedSource{
+		source: source,
+		path:   filepath.Join(dir, hex.EncodeToString(digest[:])+".json"),
+	}, nil
+}
+
+func (c *cachedSource) Token(ctx context.Context) (*Token, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != nil && !c.token.Expired() {
+		return c.token, nil
+	}
+
+	if token, err := c.load(); err == nil && !token.Expired() {
+		c.token = token
+		return token, nil
+	}
+
+	token, err := c.source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || token.ExpiresAt.IsZero() {
+		return token, nil
+	}
+
+	c.token = token
+	if err := c.store(token); err != nil {
+		// The token can still be used, it just has to be obtained again by
+		// the next invocation
+		logger.Debug("Unable to cache token: " + err.Error())
+	}
+
+	return token, nil
+}
+
+func (c *cachedSource) load() (*Token, error) {
+	tokenBytes, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &Token{}
+	if err := json.Unmarshal(tokenBytes, token); err != nil {
+		return nil, err
+	}
+	if token.Value == "" || token.ExpiresAt.IsZero() {
+		return nil, errInvalidCachedToken
+	}
+
+	return token, nil
+}
+
+func (c *cachedSource) store(token *Token) error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+
+	tokenBytes, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	// The token is written to a temporary file first so that concurrent
+	// invocations never read a partially written token
+	tmpFile, err := os.CreateTemp(filepath.Dir(c.path), ".token-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmpFile.Write(tokenBytes); err != nil {
+		tmpFile.Close()           //nolint:errcheck
+		os.Remove(tmpFile.Name()) //nolint:errcheck
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpFile.Name()) //nolint:errcheck
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), c.path)
+}
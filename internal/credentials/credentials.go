@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/config"
+)
+
+const (
+	TypeEnv            = "env"
+	TypeGitHubApp      = "github-app"
+	TypeGitLabJobToken = "gitlab-job-token"
+	TypeOIDCExchange   = "oidc-exchange"
+
+	gitLabJobTokenKey = "CI_JOB_TOKEN" //nolint:gosec
+
+	// refreshMargin is how long before a token expires that it is considered
+	// expired, so that it doesn't expire while it is being used.
+	refreshMargin = 5 * time.Minute
+)
+
+var (
+	ErrUnknownCredentialType = errors.New("unknown credential type")
+	ErrInvalidCredential     = errors.New("invalid credential")
+	ErrNoGitLabJobToken      = errors.New("GitLab job token not found, CI_JOB_TOKEN must be set") //nolint:stylecheck
+)
+
+var now = time.Now
+
+// Token is a token used to authenticate to a service's API.
+type Token struct {
+	// Value is the token.
+	Value string `json:"value"`
+
+	// JobToken indicates the token is a GitLab CI job token, which GitLab
+	// expects in the JOB-TOKEN header and with the gitlab-ci-token username.
+	JobToken bool `json:"job_token,omitempty"`
+
+	// ExpiresAt is when the token expires, and is zero for tokens that don't
+	// expire or whose expiry is unknown.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired returns true if the token has expired or is about to expire.
+func (t *Token) Expired() bool {
+	if t.ExpiresAt.IsZero() {
+		return false
+	}
+
+	return !now().Add(refreshMargin).Before(t.ExpiresAt)
+}
+
+// Source obtains tokens for a service. Token returns nil if no token is
+// available, in which case the service is accessed anonymously.
+type Source interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// Load returns the source of tokens configured for the integration, such as
+// "github". If no credential is configured for the integration, the token is
+// read from the environment variable defaultEnv. Short-lived tokens are cached
+// in the user's cache directory until they expire.
+func Load(c *config.Config, integration, defaultEnv string) (Source, error) {
+	credential, has := c.Credentials[integration]
+	if !has {
+		return Env(defaultEnv), nil
+	}
+
+	source, err := FromConfig(credential)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load credential for '%s': %w", integration, err)
+	}
+
+	switch credential.Type {
+	case TypeGitHubApp, TypeOIDCExchange:
+		return Cached(source, integration, credential)
+	default:
+		return source, nil
+	}
+}
+
+// FromConfig returns the source of tokens for the credential.
+func FromConfig(credential *config.Credential) (Source, error) {
+	switch credential.Type {
+	case TypeEnv:
+		if credential.Env == "" {
+			return nil, fmt.Errorf("%w: env must be set", ErrInvalidCredential)
+		}
+		return Env(credential.Env), nil
+
+	case TypeGitHubApp:
+		if credential.AppID == 0 || credential.InstallationID == 0 || credential.PrivateKey == "" {
+			return nil, fmt.Errorf("%w: app_id, installation_id, and private_key must be set", ErrInvalidCredential)
+		}
+
+		keyBytes, err := os.ReadFile(credential.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := LoadGitHubAppPrivateKey(keyBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		return GitHubApp(credential.APIURL, credential.AppID, credential.InstallationID, privateKey), nil
+
+	case TypeGitLabJobToken:
+		return GitLabJobToken(), nil
+
+	case TypeOIDCExchange:
+		if credential.TokenURL == "" {
+			return nil, fmt.Errorf("%w: token_url must be set", ErrInvalidCredential)
+		}
+		if credential.Audience == "" && credential.IDTokenEnv == "" {
+			return nil, fmt.Errorf("%w: audience or id_token_env must be set", ErrInvalidCredential)
+		}
+
+		return OIDCExchange(credential.TokenURL, credential.Audience, credential.IDTokenEnv, credential.Scope), nil
+
+	default:
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownCredentialType, credential.Type)
+	}
+}
+
+type staticSource struct {
+	token *Token
+}
+
+// Static returns a source that always returns the specified token, such as a
+// personal access token.
+func Static(token string) Source {
+	return &staticSource{token: &Token{Value: token}}
+}
+
+func (s *staticSource) Token(_ context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+type envSource struct {
+	key string
+}
+
+// Env returns a source that reads the token from the environment variable, and
+// returns no token if the variable is not set.
+func Env(key string) Source {
+	return &envSource{key: key}
+}
+
+func (e *envSource) Token(_ context.Context) (*Token, error) {
+	value := os.Getenv(e.key)
+	if value == "" {
+		return nil, nil
+	}
+
+	return &Token{Value: value}, nil
+}
+
+type gitLabJobTokenSource struct{}
+
+// GitLabJobToken returns a source for the job token of the GitLab CI job
+// gittuf is running in. Job tokens are valid until the job finishes.
+func GitLabJobToken() Source {
+	return &gitLabJobTokenSource{}
+}
+
+func (g *gitLabJobTokenSource) Token(_ context.Context) (*Token, error) {
+	value := os.Getenv(gitLabJobTokenKey)
+	if value == "" {
+		return nil, ErrNoGitLabJobToken
+	}
+
+	return &Token{Value: value, JobToken: true}, nil
+}
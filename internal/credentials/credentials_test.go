@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package credentials
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingSource struct {
+	token *Token
+	calls int
+}
+
+func (c *countingSource) Token(_ context.Context) (*Token, error) {
+	c.calls++
+	return c.token, nil
+}
+
+func TestLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir = func() (string, error) { return tmpDir, nil }
+	t.Cleanup(func() { cacheDir = defaultCacheDir })
+
+	t.Run("default environment variable", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "")
+		source, err := Load(&config.Config{}, "github", "GITHUB_TOKEN")
+		assert.Nil(t, err)
+
+		token, err := source.Token(context.Background())
+		assert.Nil(t, err)
+		assert.Nil(t, token)
+
+		t.Setenv("GITHUB_TOKEN", "personal-access-token")
+		token, err = source.Token(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, &Token{Value: "personal-access-token"}, token)
+	})
+
+	t.Run("configured environment variable", func(t *testing.T) {
+		t.Setenv("GITTUF_TOKEN", "configured-token")
+		c := &config.Config{Credentials: map[string]*config.Credential{"github": {Type: TypeEnv, Env: "GITTUF_TOKEN"}}}
+
+		source, err := Load(c, "github", "GITHUB_TOKEN")
+		assert.Nil(t, err)
+
+		token, err := source.Token(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, "configured-token", token.Value)
+	})
+
+	t.Run("GitLab job token", func(t *testing.T) {
+		c := &config.Config{Credentials: map[string]*config.Credential{"gitlab": {Type: TypeGitLabJobToken}}}
+
+		source, err := Load(c, "gitlab", "GITTUF_GITLAB_TOKEN")
+		assert.Nil(t, err)
+
+		t.Setenv(gitLabJobTokenKey, "")
+		_, err = source.Token(context.Background())
+		assert.ErrorIs(t, err, ErrNoGitLabJobToken)
+
+		t.Setenv(gitLabJobTokenKey, "job-token")
+		token, err := source.Token(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, &Token{Value: "job-token", JobToken: true}, token)
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		for _, credential := range []*config.Credential{
+			{Type: TypeEnv},
+			{Type: TypeGitHubApp, AppID: 1234},
+			{Type: TypeOIDCExchange, Audience: "gittuf"},
+			{Type: TypeOIDCExchange, TokenURL: "https://sts.example.com/token"},
+		} {
+			_, err := Load(&config.Config{Credentials: map[string]*config.Credential{"github": credential}}, "github", "GITHUB_TOKEN")
+			assert.ErrorIs(t, err, ErrInvalidCredential)
+		}
+
+		_, err := Load(&config.Config{Credentials: map[string]*config.Credential{"github": {Type: "password"}}}, "github", "GITHUB_TOKEN")
+		assert.ErrorIs(t, err, ErrUnknownCredentialType)
+	})
+}
+
+func TestCached(t *testing.T) {
+	currentTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return currentTime }
+	tmpDir := t.TempDir()
+	cacheDir = func() (string, error) { return tmpDir, nil }
+	t.Cleanup(func() {
+		now = time.Now
+		cacheDir = defaultCacheDir
+	})
+
+	credential := &config.Credential{Type: TypeOIDCExchange, TokenURL: "https://sts.example.com/token"}
+	source := &countingSource{token: &Token{Value: "token", ExpiresAt: currentTime.Add(time.Hour)}}
+
+	cached, err := Cached(source, "github", credential)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := cached.Token(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "token", token.Value)
+	assert.Equal(t, 1, source.calls)
+
+	// The token is reused from memory
+	_, err = cached.Token(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, source.calls)
+
+	// The token is reused from the cache directory by later invocations
+	cached, err = Cached(source, "github", credential)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err = cached.Token(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "token", token.Value)
+	assert.Equal(t, 1, source.calls)
+
+	// The cache is not shared with other integrations
+	otherCached, err := Cached(source, "gitlab", credential)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = otherCached.Token(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, source.calls)
+
+	// Tokens about to expire are refreshed
+	currentTime = currentTime.Add(time.Hour - refreshMargin)
+	source.token = &Token{Value: "refreshed-token", ExpiresAt: currentTime.Add(time.Hour)}
+	token, err = cached.Token(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "refreshed-token", token.Value)
+	assert.Equal(t, 3, source.calls)
+}
+
+func TestOIDCExchange(t *testing.T) {
+	currentTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return currentTime }
+	t.Cleanup(func() { now = time.Now })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("grant_type") != tokenExchangeGrantType || r.PostForm.Get("subject_token_type") != idTokenType || r.PostForm.Get("scope") != "repo:read" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("subject_token") != "id-token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"invalid_grant"}`)) //nolint:errcheck
+			return
+		}
+
+		w.Write([]byte(`{"access_token":"exchanged-token","issued_token_type":"urn:ietf:params:oauth:token-type:access_token","token_type":"Bearer","expires_in":3600}`)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	source := OIDCExchange(server.URL, "", "GITTUF_ID_TOKEN", "repo:read")
+
+	t.Setenv("GITTUF_ID_TOKEN", "")
+	_, err := source.Token(context.Background())
+	assert.ErrorIs(t, err, ErrTokenExchangeFailed)
+
+	t.Setenv("GITTUF_ID_TOKEN", "id-token")
+	token, err := source.Token(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, &Token{Value: "exchanged-token", ExpiresAt: currentTime.Add(time.Hour)}, token)
+
+	t.Setenv("GITTUF_ID_TOKEN", "forged-token")
+	_, err = source.Token(context.Background())
+	assert.ErrorIs(t, err, ErrTokenExchangeFailed)
+	assert.Contains(t, err.Error(), "invalid_grant")
+}
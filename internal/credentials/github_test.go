@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package credentials
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadGitHubAppPrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("PKCS #1", func(t *testing.T) {
+		loadedKey, err := LoadGitHubAppPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+		assert.Nil(t, err)
+		assert.True(t, key.Equal(loadedKey))
+	})
+
+	t.Run("PKCS #8", func(t *testing.T) {
+		loadedKey, err := LoadGitHubAppPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes}))
+		assert.Nil(t, err)
+		assert.True(t, key.Equal(loadedKey))
+	})
+
+	t.Run("not PEM encoded", func(t *testing.T) {
+		_, err := LoadGitHubAppPrivateKey([]byte("not a key"))
+		assert.ErrorIs(t, err, ErrInvalidGitHubAppPrivateKey)
+	})
+}
+
+func TestNewGitHubAppJWT(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuedAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	token, err := NewGitHubAppJWT(1234, key, issuedAt)
+	assert.Nil(t, err)
+
+	parts := strings.Split(token, ".")
+	assert.Len(t, parts, 3)
+
+	claimsBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	assert.Nil(t, err)
+	claims := map[string]any{}
+	assert.Nil(t, json.Unmarshal(claimsBytes, &claims))
+	assert.Equal(t, "1234", claims["iss"])
+	assert.Equal(t, float64(issuedAt.Add(-time.Minute).Unix()), claims["iat"])
+	assert.Equal(t, float64(issuedAt.Add(gitHubAppJWTValidity).Unix()), claims["exp"])
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.Nil(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.Nil(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+}
+
+func TestGitHubApp(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Date(2024, time.January, 1, 1, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v3/app/installations/42/access_tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if len(strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")) != 3 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"token": "installation-token", "expires_at": expiresAt}) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	token, err := GitHubApp(server.URL, 1234, 42, key).Token(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "installation-token", token.Value)
+	assert.True(t, expiresAt.Equal(token.ExpiresAt))
+
+	_, err = GitHubApp(server.URL, 1234, 43, key).Token(context.Background())
+	assert.NotNil(t, err)
+}
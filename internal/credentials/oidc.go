@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/sigstore"
+)
+
+const (
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	idTokenType            = "urn:ietf:params:oauth:token-type:id_token"     //nolint:gosec
+	accessTokenType        = "urn:ietf:params:oauth:token-type:access_token" //nolint:gosec
+)
+
+var ErrTokenExchangeFailed = errors.New("unable to exchange OIDC identity token")
+
+type oidcExchangeSource struct {
+	tokenURL   string
+	audience   string
+	idTokenEnv string
+	scope      string
+	httpClient *http.Client
+}
+
+// OIDCExchange returns a source of tokens obtained by exchanging an OIDC
+// identity token from the CI environment at an OAuth 2.0 token exchange
+// endpoint (RFC 8693). The identity token is read from the environment
+// variable idTokenEnv if set, and is otherwise requested for the audience from
+// GitHub Actions or the Buildkite agent.
+func OIDCExchange(tokenURL, audience, idTokenEnv, scope string) Source {
+	return &oidcExchangeSource{
+		tokenURL:   tokenURL,
+		audience:   audience,
+		idTokenEnv: idTokenEnv,
+		scope:      scope,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (o *oidcExchangeSource) Token(ctx context.Context) (*Token, error) {
+	idToken, err := o.identityToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", tokenExchangeGrantType)
+	form.Set("subject_token", idToken)
+	form.Set("subject_token_type", idTokenType)
+	form.Set("requested_token_type", accessTokenType)
+	if o.scope != "" {
+		form.Set("scope", o.scope)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, o.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := o.httpClient.Do(request)
+	if err != nil {
+		return nil, errors.Join(ErrTokenExchangeFailed, err)
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	if response.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return nil, fmt.Errorf("%w: %s returned %s: %s", ErrTokenExchangeFailed, o.tokenURL, response.Status, strings.TrimSpace(string(message)))
+	}
+
+	tokenResponse := struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&tokenResponse); err != nil {
+		return nil, errors.Join(ErrTokenExchangeFailed, err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return nil, fmt.Errorf("%w: %s did not return an access token", ErrTokenExchangeFailed, o.tokenURL)
+	}
+
+	token := &Token{Value: tokenResponse.AccessToken}
+	if tokenResponse.ExpiresIn > 0 {
+		token.ExpiresAt = now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+func (o *oidcExchangeSource) identityToken(ctx context.Context) (string, error) {
+	if o.idTokenEnv == "" {
+		return sigstore.RequestAmbientToken(ctx, o.audience)
+	}
+
+	idToken := os.Getenv(o.idTokenEnv)
+	if idToken == "" {
+		return "", fmt.Errorf("%w: %s is not set", ErrTokenExchangeFailed, o.idTokenEnv)
+	}
+
+	return idToken, nil
+}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+)
+
+// xssiPrefix is prepended by Gerrit to every JSON response of its REST API to
+// prevent cross-site script inclusion.
+const xssiPrefix = ")]}'"
+
+var ErrUnexpectedResponse = errors.New("unexpected response from Gerrit")
+
+// Account identifies a Gerrit user.
+type Account struct {
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// Approval is a vote cast by a reviewer on one of a change's labels, such as
+// Code-Review or Verified.
+type Approval struct {
+	Label   string  `json:"label"`
+	Value   int     `json:"value"`
+	Account Account `json:"account"`
+}
+
+// Change summarizes a Gerrit change and the approvals it received. It is
+// recorded in Gerrit change attestations.
+type Change struct {
+	URL       string     `json:"url"`
+	Project   string     `json:"project"`
+	Branch    string     `json:"branch"`
+	ChangeID  string     `json:"changeID"`
+	Number    int        `json:"number"`
+	PatchSet  int        `json:"patchSet"`
+	Revision  string     `json:"revision"`
+	Subject   string     `json:"subject"`
+	Status    string     `json:"status"`
+	Owner     Account    `json:"owner"`
+	Submitter *Account   `json:"submitter,omitempty"`
+	Approvals []Approval `json:"approvals"`
+}
+
+// RefName returns the absolute name of the branch the change targets.
+func (c *Change) RefName() string {
+	if strings.HasPrefix(c.Branch, gitinterface.RefPrefix) {
+		return c.Branch
+	}
+
+	return gitinterface.BranchRefPrefix + c.Branch
+}
+
+// Client is a minimal client for the parts of Gerrit's REST API used by gittuf.
+type Client struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client for the Gerrit instance at baseURL. If username
+// is set, requests are authenticated using the user's HTTP password.
+// Otherwise, only changes visible to anonymous users can be inspected.
+func NewClient(baseURL, username, password string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type accountInfo struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+type approvalInfo struct {
+	accountInfo
+	Value int `json:"value"`
+}
+
+type changeInfo struct {
+	Project         string       `json:"project"`
+	Branch          string       `json:"branch"`
+	ChangeID        string       `json:"change_id"`
+	Number          int          `json:"_number"`
+	Subject         string       `json:"subject"`
+	Status          string       `json:"status"`
+	Owner           accountInfo  `json:"owner"`
+	Submitter       *accountInfo `json:"submitter"`
+	CurrentRevision string       `json:"current_revision"`
+	Revisions       map[string]struct {
+		Number int `json:"_number"`
+	} `json:"revisions"`
+	Labels map[string]struct {
+		All []approvalInfo `json:"all"`
+	} `json:"labels"`
+}
+
+// GetChange returns the change identified by changeID, which may be any of the
+// identifiers accepted by Gerrit, such as the change number or the
+// `<project>~<branch>~<Change-Id>` triplet passed to Gerrit's hooks. Only the
+// votes cast on the change's labels are included in its approvals; reviewers
+// who haven't voted are omitted.
+func (c *Client) GetChange(ctx context.Context, changeID string) (*Change, error) {
+	query := url.Values{}
+	query.Add("o", "CURRENT_REVISION")
+	query.Add("o", "DETAILED_LABELS")
+	query.Add("o", "DETAILED_ACCOUNTS")
+
+	info := &changeInfo{}
+	if err := c.get(ctx, fmt.Sprintf("changes/%s?%s", url.PathEscape(changeID), query.Encode()), info); err != nil {
+		return nil, err
+	}
+
+	change := &Change{
+		URL:       fmt.Sprintf("%s/c/%s/+/%d", c.baseURL, info.Project, info.Number),
+		Project:   info.Project,
+		Branch:    info.Branch,
+		ChangeID:  info.ChangeID,
+		Number:    info.Number,
+		Revision:  info.CurrentRevision,
+		Subject:   info.Subject,
+		Status:    info.Status,
+		Owner:     Account(info.Owner),
+		Approvals: []Approval{},
+	}
+	if revision, has := info.Revisions[info.CurrentRevision]; has {
+		change.PatchSet = revision.Number
+	}
+	if info.Submitter != nil {
+		submitter := Account(*info.Submitter)
+		change.Submitter = &submitter
+	}
+
+	for label, labelInfo := range info.Labels {
+		for _, vote := range labelInfo.All {
+			if vote.Value == 0 {
+				continue
+			}
+
+			change.Approvals = append(change.Approvals, Approval{
+				Label:   label,
+				Value:   vote.Value,
+				Account: Account(vote.accountInfo),
+			})
+		}
+	}
+
+	sort.Slice(change.Approvals, func(i, j int) bool {
+		if change.Approvals[i].Label != change.Approvals[j].Label {
+			return change.Approvals[i].Label < change.Approvals[j].Label
+		}
+		return change.Approvals[i].Account.Username < change.Approvals[j].Account.Username
+	})
+
+	return change, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, response any) error {
+	endpoint := c.baseURL + "/" + path
+	if c.username != "" {
+		// Authenticated requests are made to the /a/ prefix
+		endpoint = c.baseURL + "/a/" + path
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if c.username != "" {
+		request.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: GET %s returned %s: %s", ErrUnexpectedResponse, path, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes.TrimPrefix(body, []byte(xssiPrefix)), response)
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gerrit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testChangeResponse = `)]}'
+{
+  "project": "gittuf",
+  "branch": "main",
+  "change_id": "I8473b95934b5732ac55d26311a706c9c2bde9940",
+  "_number": 1234,
+  "subject": "Add feature",
+  "status": "MERGED",
+  "owner": {"name": "Jane Doe", "email": "jane@example.com", "username": "jane"},
+  "submitter": {"name": "John Doe", "email": "john@example.com", "username": "john"},
+  "current_revision": "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f",
+  "revisions": {"8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f": {"_number": 3}},
+  "labels": {
+    "Verified": {"all": [{"value": 1, "name": "CI", "username": "ci"}]},
+    "Code-Review": {"all": [
+      {"value": 2, "name": "John Doe", "email": "john@example.com", "username": "john"},
+      {"value": 0, "name": "Jane Doe", "email": "jane@example.com", "username": "jane"},
+      {"value": 1, "name": "Alice", "email": "alice@example.com", "username": "alice"}
+    ]}
+  }
+}
+`
+
+func TestChangeRefName(t *testing.T) {
+	assert.Equal(t, "refs/heads/main", (&Change{Branch: "main"}).RefName())
+	assert.Equal(t, "refs/heads/main", (&Change{Branch: "refs/heads/main"}).RefName())
+}
+
+func TestClientGetChange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/changes/gittuf~main~I8473b95934b5732ac55d26311a706c9c2bde9940":
+			assert.ElementsMatch(t, []string{"CURRENT_REVISION", "DETAILED_LABELS", "DETAILED_ACCOUNTS"}, r.URL.Query()["o"])
+			w.Write([]byte(testChangeResponse)) //nolint:errcheck
+
+		case "/a/changes/1234":
+			if username, password, ok := r.BasicAuth(); !ok || username != "gittuf" || password != "http-password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(testChangeResponse)) //nolint:errcheck
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("anonymous", func(t *testing.T) {
+		client := NewClient(server.URL+"/", "", "")
+
+		change, err := client.GetChange(context.Background(), "gittuf~main~I8473b95934b5732ac55d26311a706c9c2bde9940")
+		assert.Nil(t, err)
+		assert.Equal(t, server.URL+"/c/gittuf/+/1234", change.URL)
+		assert.Equal(t, "refs/heads/main", change.RefName())
+		assert.Equal(t, 1234, change.Number)
+		assert.Equal(t, 3, change.PatchSet)
+		assert.Equal(t, "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f", change.Revision)
+		assert.Equal(t, "jane", change.Owner.Username)
+		assert.Equal(t, "john", change.Submitter.Username)
+		assert.Equal(t, []Approval{
+			{Label: "Code-Review", Value: 1, Account: Account{Name: "Alice", Email: "alice@example.com", Username: "alice"}},
+			{Label: "Code-Review", Value: 2, Account: Account{Name: "John Doe", Email: "john@example.com", Username: "john"}},
+			{Label: "Verified", Value: 1, Account: Account{Name: "CI", Username: "ci"}},
+		}, change.Approvals)
+	})
+
+	t.Run("authenticated", func(t *testing.T) {
+		client := NewClient(server.URL, "gittuf", "http-password")
+
+		change, err := client.GetChange(context.Background(), "1234")
+		assert.Nil(t, err)
+		assert.Equal(t, 1234, change.Number)
+
+		client = NewClient(server.URL, "gittuf", "wrong-password")
+		_, err = client.GetChange(context.Background(), "1234")
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+
+	t.Run("unknown change", func(t *testing.T) {
+		client := NewClient(server.URL, "", "")
+
+		_, err := client.GetChange(context.Background(), "5678")
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+}
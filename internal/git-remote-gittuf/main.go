@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gittuf/gittuf/internal/i18n"
+	"github.com/gittuf/gittuf/internal/remotehelper"
+	"github.com/gittuf/gittuf/internal/repository"
+)
+
+// git-remote-gittuf is invoked by Git for remotes whose URLs have the gittuf::
+// prefix, with the remote's name and its URL without the prefix.
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Fprintf(os.Stderr, "usage: %s <remote> <url>\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	// Git runs remote helpers with GIT_DIR set to the local repository
+	repoPath := os.Getenv("GIT_DIR")
+	if repoPath == "" {
+		repoPath = "."
+	}
+
+	repo, err := repository.LoadRepositoryAt(repoPath)
+	if err == nil {
+		err = remotehelper.New(repo, os.Args[2]).Run(context.Background(), os.Stdin, os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s\n", i18n.T("Error:"), i18n.TranslateError(err))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package giteaservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/gittuf/gittuf/internal/webhook"
+	"github.com/go-git/go-git/v5/plumbing"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+const (
+	// StatusContext is the context of the commit statuses set by the service.
+	// Branch protection rules can require this status to block unverified
+	// changes from being merged.
+	StatusContext = "gittuf"
+
+	// Commit status states used by the service.
+	statePending = "pending"
+	stateSuccess = "success"
+	stateFailure = "failure"
+	stateError   = "error"
+
+	// maxDescriptionLength is the maximum length of the commit status
+	// descriptions set by the service.
+	maxDescriptionLength = 255
+
+	// Forgejo sends both its own headers and the Gitea headers it's
+	// compatible with.
+	giteaEventHeader       = "X-Gitea-Event"
+	giteaSignatureHeader   = "X-Gitea-Signature"
+	forgejoEventHeader     = "X-Forgejo-Event"
+	forgejoSignatureHeader = "X-Forgejo-Signature"
+	pushEvent              = "push"
+
+	// maxPayloadSize is the maximum size of the webhook payloads accepted.
+	maxPayloadSize = 25 << 20
+
+	gittufNamespacePrefix = "refs/gittuf/"
+)
+
+var (
+	ErrMissingURL           = errors.New("Gitea or Forgejo URL must be set")   //nolint:stylecheck
+	ErrMissingToken         = errors.New("Gitea or Forgejo token must be set") //nolint:stylecheck
+	ErrMissingWebhookSecret = errors.New("Gitea or Forgejo webhook secret must be set")
+	ErrUnexpectedResponse   = errors.New("unexpected response from Gitea or Forgejo")
+)
+
+var (
+	syncRepository   = gitinterface.SyncMirror
+	verifyRepository = webhook.VerifyRef
+)
+
+// PushEvent is the payload of Gitea's and Forgejo's push webhooks.
+type PushEvent struct {
+	Ref        string `json:"ref"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	HeadCommit *struct {
+		ID string `json:"id"`
+	} `json:"head_commit"`
+	Repository struct {
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+		CloneURL string `json:"clone_url"`
+		Owner    struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+}
+
+type commitStatus struct {
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description"`
+}
+
+// Config contains the settings of the service.
+type Config struct {
+	// URL is the URL of the Gitea or Forgejo instance.
+	URL string
+
+	// Token is an access token with access to the repositories the service
+	// verifies, used to fetch the repositories and to set commit statuses.
+	Token string
+
+	// WebhookSecret is the secret configured for the repositories' webhooks.
+	WebhookSecret []byte
+
+	// CacheDir is the directory the repositories are mirrored to for
+	// verification.
+	CacheDir string
+
+	// Metrics is the registry the service's verification metrics are
+	// registered with. If nil, metrics are not recorded.
+	Metrics *metrics.Registry
+}
+
+// Service verifies every push to the Gitea or Forgejo repositories that send
+// it push webhooks against the repository's gittuf policy, and reports the
+// result as a commit status on the pushed commit.
+type Service struct {
+	baseURL       string
+	token         string
+	webhookSecret []byte
+	httpClient    *http.Client
+	verifier      *webhook.Verifier
+}
+
+// NewService returns a Service for the specified configuration.
+func NewService(config *Config) (*Service, error) {
+	switch {
+	case config.URL == "":
+		return nil, ErrMissingURL
+	case config.Token == "":
+		return nil, ErrMissingToken
+	case len(config.WebhookSecret) == 0:
+		return nil, ErrMissingWebhookSecret
+	}
+
+	return &Service{
+		baseURL:       strings.TrimSuffix(config.URL, "/"),
+		token:         config.Token,
+		webhookSecret: config.WebhookSecret,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		verifier:      webhook.NewVerifier(config.CacheDir, config.Metrics, verifyRepository),
+	}, nil
+}
+
+// ServeHTTP handles webhook deliveries. Push events are acknowledged
+// immediately and verified in the background, as verification may take longer
+// than Gitea or Forgejo wait for a response. Other events are ignored.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "unable to read payload", http.StatusBadRequest)
+		return
+	}
+
+	signature := r.Header.Get(forgejoSignatureHeader)
+	if signature == "" {
+		signature = r.Header.Get(giteaSignatureHeader)
+	}
+	if !s.validSignature(payload, signature) {
+		http.Error(w, "invalid webhook signature", http.StatusUnauthorized)
+		return
+	}
+
+	eventType := r.Header.Get(forgejoEventHeader)
+	if eventType == "" {
+		eventType = r.Header.Get(giteaEventHeader)
+	}
+	if eventType != pushEvent {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	event := &PushEvent{}
+	if err := json.Unmarshal(payload, event); err != nil {
+		http.Error(w, "invalid push event", http.StatusBadRequest)
+		return
+	}
+
+	s.verifier.Go(event.Repository.FullName, event.Ref, func(ctx context.Context) error {
+		return s.HandlePush(ctx, event)
+	})
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Wait blocks until the verification of all acknowledged pushes completes.
+func (s *Service) Wait() {
+	s.verifier.Wait()
+}
+
+// HandlePush verifies the ref updated by a push event against the
+// repository's gittuf policy, and sets the commit status of the pushed commit
+// to the result. Deleted refs and gittuf's own refs are not verified.
+func (s *Service) HandlePush(ctx context.Context, event *PushEvent) error {
+	if plumbing.NewHash(event.After).IsZero() || strings.HasPrefix(event.Ref, gittufNamespacePrefix) {
+		return nil
+	}
+
+	// Statuses are set on commits, so annotated tags use the tagged commit
+	commitID := event.After
+	if event.HeadCommit != nil && event.HeadCommit.ID != "" {
+		commitID = event.HeadCommit.ID
+	}
+
+	setStatus := func(state, description string) error {
+		if len(description) > maxDescriptionLength {
+			description = description[:maxDescriptionLength-3] + "..."
+		}
+
+		return s.setCommitStatus(ctx, event.Repository.Owner.Login, event.Repository.Name, commitID, &commitStatus{
+			State:       state,
+			Context:     StatusContext,
+			Description: description,
+		})
+	}
+
+	if err := setStatus(statePending, fmt.Sprintf("Verifying %s", event.Ref)); err != nil {
+		return err
+	}
+
+	verifyErr := s.verifier.VerifyRef(ctx, event.Repository.FullName, filepath.FromSlash(event.Repository.FullName)+".git", event.Ref, func(ctx context.Context, path string) error {
+		return syncRepository(ctx, path, event.Repository.CloneURL, &githttp.BasicAuth{Username: "gittuf", Password: s.token})
+	})
+	switch {
+	case errors.Is(verifyErr, webhook.ErrSyncFailed):
+		return errors.Join(verifyErr, setStatus(stateError, "Unable to fetch repository"))
+	case verifyErr != nil:
+		return setStatus(stateFailure, fmt.Sprintf("gittuf verification failed: %s", verifyErr.Error()))
+	}
+
+	return setStatus(stateSuccess, fmt.Sprintf("gittuf verification succeeded for %s", event.Ref))
+}
+
+// validSignature returns true if the signature is the hex encoded HMAC-SHA256
+// of the payload using the webhook secret.
+func (s *Service) validSignature(payload []byte, signature string) bool {
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, s.webhookSecret)
+	mac.Write(payload)
+
+	return hmac.Equal(mac.Sum(nil), signatureBytes)
+}
+
+// setCommitStatus sets the status of the commit in the repository.
+func (s *Service) setCommitStatus(ctx context.Context, owner, name, commitID string, status *commitStatus) error {
+	statusBytes, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	statusURL := fmt.Sprintf("%s/api/v1/repos/%s/%s/statuses/%s", s.baseURL, url.PathEscape(owner), url.PathEscape(name), url.PathEscape(commitID))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, statusURL, bytes.NewReader(statusBytes))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "token "+s.token)
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: setting commit status returned %s: %s", ErrUnexpectedResponse, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package giteaservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/webhook"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testToken    = "access-token"
+	testCommitID = "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"
+)
+
+var testWebhookSecret = []byte("webhook-secret")
+
+// fakeGitea is a minimal Gitea API that records the commit statuses set.
+type fakeGitea struct {
+	mu       sync.Mutex
+	statuses []*commitStatus
+}
+
+func (f *fakeGitea) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "token "+testToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost || r.URL.Path != "/api/v1/repos/owner/repo/statuses/"+testCommitID {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	status := &commitStatus{}
+	if err := json.NewDecoder(r.Body).Decode(status); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.statuses = append(f.statuses, status)
+	f.mu.Unlock()
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("{}")) //nolint:errcheck
+}
+
+func (f *fakeGitea) states() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	states := []string{}
+	for _, status := range f.statuses {
+		states = append(states, status.State)
+	}
+	return states
+}
+
+func TestService(t *testing.T) {
+	var verifyErr error
+	verifiedRefs := []string{}
+	syncRepository = func(_ context.Context, _, cloneURL string, auth transport.AuthMethod) error {
+		assert.Equal(t, "https://git.example.com/owner/repo.git", cloneURL)
+		assert.Equal(t, testToken, auth.(*githttp.BasicAuth).Password)
+		return nil
+	}
+	verifyRepository = func(_ context.Context, _, refName string) error {
+		verifiedRefs = append(verifiedRefs, refName)
+		return verifyErr
+	}
+	t.Cleanup(func() {
+		syncRepository = gitinterface.SyncMirror
+		verifyRepository = webhook.VerifyRef
+	})
+
+	newService := func(t *testing.T) (*Service, *fakeGitea) {
+		t.Helper()
+
+		api := &fakeGitea{}
+		server := httptest.NewServer(api)
+		t.Cleanup(server.Close)
+
+		service, err := NewService(&Config{
+			URL:           server.URL + "/",
+			Token:         testToken,
+			WebhookSecret: testWebhookSecret,
+			CacheDir:      t.TempDir(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return service, api
+	}
+
+	newPushEvent := func(refName string) *PushEvent {
+		event := &PushEvent{Ref: refName, After: testCommitID}
+		event.Repository.Name = "repo"
+		event.Repository.FullName = "owner/repo"
+		event.Repository.CloneURL = "https://git.example.com/owner/repo.git"
+		event.Repository.Owner.Login = "owner"
+		return event
+	}
+
+	t.Run("missing settings", func(t *testing.T) {
+		_, err := NewService(&Config{Token: testToken, WebhookSecret: testWebhookSecret})
+		assert.ErrorIs(t, err, ErrMissingURL)
+
+		_, err = NewService(&Config{URL: "https://git.example.com", WebhookSecret: testWebhookSecret})
+		assert.ErrorIs(t, err, ErrMissingToken)
+
+		_, err = NewService(&Config{URL: "https://git.example.com", Token: testToken})
+		assert.ErrorIs(t, err, ErrMissingWebhookSecret)
+	})
+
+	t.Run("verified push", func(t *testing.T) {
+		service, api := newService(t)
+		verifyErr = nil
+		verifiedRefs = []string{}
+
+		assert.Nil(t, service.HandlePush(context.Background(), newPushEvent("refs/heads/main")))
+		assert.Equal(t, []string{"refs/heads/main"}, verifiedRefs)
+		assert.Equal(t, []string{statePending, stateSuccess}, api.states())
+		assert.Equal(t, StatusContext, api.statuses[1].Context)
+	})
+
+	t.Run("unverified push", func(t *testing.T) {
+		service, api := newService(t)
+		verifyErr = errors.New("verifying Git namespace policies failed, unauthorized signature")
+
+		assert.Nil(t, service.HandlePush(context.Background(), newPushEvent("refs/heads/main")))
+		assert.Equal(t, []string{statePending, stateFailure}, api.states())
+		assert.Contains(t, api.statuses[1].Description, "unauthorized signature")
+	})
+
+	t.Run("unable to fetch", func(t *testing.T) {
+		service, api := newService(t)
+		syncRepository = func(_ context.Context, _, _ string, _ transport.AuthMethod) error {
+			return errors.New("network unreachable")
+		}
+		defer func() {
+			syncRepository = func(_ context.Context, _, _ string, _ transport.AuthMethod) error { return nil }
+		}()
+
+		assert.NotNil(t, service.HandlePush(context.Background(), newPushEvent("refs/heads/main")))
+		assert.Equal(t, []string{statePending, stateError}, api.states())
+	})
+
+	t.Run("ignored pushes", func(t *testing.T) {
+		service, api := newService(t)
+		verifiedRefs = []string{}
+
+		assert.Nil(t, service.HandlePush(context.Background(), newPushEvent("refs/gittuf/reference-state-log")))
+
+		deletion := newPushEvent("refs/heads/feature")
+		deletion.After = plumbing.ZeroHash.String()
+		assert.Nil(t, service.HandlePush(context.Background(), deletion))
+
+		assert.Empty(t, verifiedRefs)
+		assert.Empty(t, api.states())
+	})
+
+	t.Run("webhook deliveries", func(t *testing.T) {
+		service, api := newService(t)
+		verifyErr = nil
+
+		payload, err := json.Marshal(newPushEvent("refs/heads/main"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		deliver := func(eventHeader, eventType string, payload, secret []byte) int {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(payload)
+
+			request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+			request.Header.Set(eventHeader, eventType)
+			request.Header.Set(giteaSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+
+			recorder := httptest.NewRecorder()
+			service.ServeHTTP(recorder, request)
+			return recorder.Code
+		}
+
+		assert.Equal(t, http.StatusUnauthorized, deliver(giteaEventHeader, pushEvent, payload, []byte("wrong-secret")))
+		assert.Equal(t, http.StatusNoContent, deliver(giteaEventHeader, "issues", []byte("{}"), testWebhookSecret))
+
+		assert.Equal(t, http.StatusAccepted, deliver(giteaEventHeader, pushEvent, payload, testWebhookSecret))
+		service.Wait()
+		assert.Equal(t, http.StatusAccepted, deliver(forgejoEventHeader, pushEvent, payload, testWebhookSecret))
+		service.Wait()
+		assert.Equal(t, []string{statePending, stateSuccess, statePending, stateSuccess}, api.states())
+	})
+}
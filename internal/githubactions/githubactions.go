@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package githubactions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	actionsKey = "GITHUB_ACTIONS"
+	summaryKey = "GITHUB_STEP_SUMMARY"
+	outputKey  = "GITHUB_OUTPUT"
+)
+
+// Reporter reports results to the GitHub Actions job gittuf is running in,
+// using annotations, output variables, and the job summary. A nil Reporter
+// discards everything reported to it, so callers can report results without
+// checking whether they are running in GitHub Actions.
+type Reporter struct {
+	commands    io.Writer
+	summaryPath string
+	outputPath  string
+}
+
+// Detect returns a Reporter if gittuf is running in a GitHub Actions job, and
+// nil otherwise. Workflow commands, such as annotations, are written to w,
+// which GitHub Actions reads them from along with the rest of the step's
+// output.
+func Detect(w io.Writer) *Reporter {
+	if os.Getenv(actionsKey) != "true" {
+		return nil
+	}
+
+	return &Reporter{
+		commands:    w,
+		summaryPath: os.Getenv(summaryKey),
+		outputPath:  os.Getenv(outputKey),
+	}
+}
+
+// Error creates an error annotation with the specified title and message,
+// which is shown in the job's log and on the workflow run's summary page.
+func (r *Reporter) Error(title, message string) {
+	if r == nil {
+		return
+	}
+
+	fmt.Fprintf(r.commands, "::error title=%s::%s\n", escapeProperty(title), escapeData(message))
+}
+
+// SetOutput sets the step's output variable name to value, which later steps
+// in the job can refer to.
+func (r *Reporter) SetOutput(name, value string) error {
+	if r == nil || r.outputPath == "" {
+		return nil
+	}
+
+	delimiterBytes := make([]byte, 16)
+	if _, err := rand.Read(delimiterBytes); err != nil {
+		return err
+	}
+	delimiter := "gittuf_" + hex.EncodeToString(delimiterBytes)
+
+	// The delimiter syntax supports multi-line values
+	return appendToFile(r.outputPath, fmt.Sprintf("%s<<%s\n%s\n%s\n", name, delimiter, value, delimiter))
+}
+
+// AddSummary appends the Markdown to the job summary.
+func (r *Reporter) AddSummary(markdown string) error {
+	if r == nil || r.summaryPath == "" {
+		return nil
+	}
+
+	if !strings.HasSuffix(markdown, "\n") {
+		markdown += "\n"
+	}
+
+	return appendToFile(r.summaryPath, markdown)
+}
+
+// TableCell escapes the value so that it can be used in a cell of a Markdown
+// table in the job summary.
+func TableCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func appendToFile(path, contents string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.WriteString(contents); err != nil {
+		file.Close() //nolint:errcheck
+		return err
+	}
+
+	return file.Close()
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(value string) string {
+	value = strings.ReplaceAll(value, "%", "%25")
+	value = strings.ReplaceAll(value, "\r", "%0D")
+	return strings.ReplaceAll(value, "\n", "%0A")
+}
+
+// escapeProperty escapes the value of a workflow command's property.
+func escapeProperty(value string) string {
+	value = escapeData(value)
+	value = strings.ReplaceAll(value, ":", "%3A")
+	return strings.ReplaceAll(value, ",", "%2C")
+}
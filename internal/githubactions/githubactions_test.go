@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package githubactions
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	t.Setenv(actionsKey, "")
+	assert.Nil(t, Detect(&bytes.Buffer{}))
+
+	t.Setenv(actionsKey, "true")
+	t.Setenv(summaryKey, "summary.md")
+	t.Setenv(outputKey, "output")
+	reporter := Detect(&bytes.Buffer{})
+	if assert.NotNil(t, reporter) {
+		assert.Equal(t, "summary.md", reporter.summaryPath)
+		assert.Equal(t, "output", reporter.outputPath)
+	}
+}
+
+func TestReporter(t *testing.T) {
+	t.Run("nil reporter", func(t *testing.T) {
+		var reporter *Reporter
+
+		reporter.Error("title", "message")
+		assert.Nil(t, reporter.SetOutput("verified", "true"))
+		assert.Nil(t, reporter.AddSummary("summary"))
+	})
+
+	t.Run("annotations", func(t *testing.T) {
+		commands := &bytes.Buffer{}
+		reporter := &Reporter{commands: commands}
+
+		reporter.Error("gittuf: refs/heads/main, failed", "unauthorized signature\n100% sure")
+		assert.Equal(t, "::error title=gittuf%3A refs/heads/main%2C failed::unauthorized signature%0A100%25 sure\n", commands.String())
+	})
+
+	t.Run("outputs and summary", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		reporter := &Reporter{
+			commands:    &bytes.Buffer{},
+			summaryPath: filepath.Join(tmpDir, "summary.md"),
+			outputPath:  filepath.Join(tmpDir, "output"),
+		}
+
+		assert.Nil(t, reporter.SetOutput("verified", "false"))
+		assert.Nil(t, reporter.SetOutput("error", "line one\nline two"))
+
+		outputBytes, err := os.ReadFile(reporter.outputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Regexp(t, regexp.MustCompile(`^verified<<(gittuf_[0-9a-f]+)\nfalse\n`+`gittuf_[0-9a-f]+\nerror<<(gittuf_[0-9a-f]+)\nline one\nline two\ngittuf_[0-9a-f]+\n$`), string(outputBytes))
+
+		assert.Nil(t, reporter.AddSummary("### gittuf"))
+		assert.Nil(t, reporter.AddSummary("Verified\n"))
+
+		summaryBytes, err := os.ReadFile(reporter.summaryPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "### gittuf\nVerified\n", string(summaryBytes))
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		reporter := &Reporter{commands: &bytes.Buffer{}}
+
+		assert.Nil(t, reporter.SetOutput("verified", "true"))
+		assert.Nil(t, reporter.AddSummary("summary"))
+	})
+}
+
+func TestTableCell(t *testing.T) {
+	assert.Equal(t, `a \| b c`, TableCell("a | b\nc"))
+}
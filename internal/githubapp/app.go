@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package githubapp
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/webhook"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/go-github/v61/github"
+)
+
+// logger logs the messages of the githubapp subsystem.
+var logger = logging.For("githubapp")
+
+const (
+	// StatusContext is the context of the commit statuses set by the app.
+	// Branch protection rules can require this status to block unverified
+	// pushes from being merged.
+	StatusContext = "gittuf"
+
+	// Commit status states used by the app.
+	statePending = "pending"
+	stateSuccess = "success"
+	stateFailure = "failure"
+	stateError   = "error"
+
+	// maxDescriptionLength is the maximum length of a commit status's
+	// description accepted by GitHub.
+	maxDescriptionLength = 140
+
+	gittufNamespacePrefix = "refs/gittuf/"
+)
+
+var (
+	ErrMissingWebhookSecret = errors.New("GitHub App webhook secret must be set") //nolint:stylecheck
+	ErrMissingInstallation  = errors.New("push event is not associated with an installation of the app")
+)
+
+var (
+	now              = time.Now
+	syncRepository   = gitinterface.SyncMirror
+	verifyRepository = webhook.VerifyRef
+)
+
+// Config contains the settings of the GitHub App.
+type Config struct {
+	// AppID is the ID of the GitHub App.
+	AppID int64
+
+	// PrivateKey is the private key of the GitHub App, used to authenticate
+	// as the app.
+	PrivateKey *rsa.PrivateKey
+
+	// WebhookSecret is the secret used to sign the app's webhook deliveries.
+	WebhookSecret []byte
+
+	// CacheDir is the directory the repositories the app is installed on are
+	// mirrored to for verification.
+	CacheDir string
+
+	// APIURL is the API URL of a GitHub Enterprise Server instance. If empty,
+	// github.com is used.
+	APIURL string
+
+	// Metrics is the registry the app's verification metrics are registered
+	// with. If nil, metrics are not recorded.
+	Metrics *metrics.Registry
+}
+
+// App is a GitHub App that verifies every push to the repositories it's
+// installed on against the repository's gittuf policy, and reports the result
+// as a commit status on the pushed commit.
+type App struct {
+	appID         int64
+	privateKey    *rsa.PrivateKey
+	webhookSecret []byte
+	apiURL        string
+	verifier      *webhook.Verifier
+}
+
+// NewApp returns an App for the specified configuration.
+func NewApp(config *Config) (*App, error) {
+	if len(config.WebhookSecret) == 0 {
+		return nil, ErrMissingWebhookSecret
+	}
+
+	return &App{
+		appID:         config.AppID,
+		privateKey:    config.PrivateKey,
+		webhookSecret: config.WebhookSecret,
+		apiURL:        config.APIURL,
+		verifier:      webhook.NewVerifier(config.CacheDir, config.Metrics, verifyRepository),
+	}, nil
+}
+
+// ServeHTTP handles the app's webhook deliveries. Push events are acknowledged
+// immediately and verified in the background, as verification may take longer
+// than GitHub waits for a response. Other events are ignored.
+func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, err := github.ValidatePayload(r, a.webhookSecret)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Rejecting webhook delivery: %s", err.Error()))
+		http.Error(w, "invalid webhook signature", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := github.ParseWebHook(github.WebHookType(r), payload)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Ignoring webhook delivery: %s", err.Error()))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	pushEvent, isPush := event.(*github.PushEvent)
+	if !isPush {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	a.verifier.Go(pushEvent.GetRepo().GetFullName(), pushEvent.GetRef(), func(ctx context.Context) error {
+		return a.HandlePush(ctx, pushEvent)
+	})
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Wait blocks until the verification of all acknowledged pushes completes.
+func (a *App) Wait() {
+	a.verifier.Wait()
+}
+
+// HandlePush verifies the ref updated by a push event against the repository's
+// gittuf policy, and sets the commit status of the pushed commit to the
+// result. Deleted refs and gittuf's own refs are not verified. Merge queue refs
+// are temporary, and their commits are verified when the queue merges them into
+// the base branch, so they are reported as successful to not block the queue.
+func (a *App) HandlePush(ctx context.Context, event *github.PushEvent) error {
+	refName := event.GetRef()
+	if event.GetDeleted() || strings.HasPrefix(refName, gittufNamespacePrefix) {
+		return nil
+	}
+
+	installationID := event.GetInstallation().GetID()
+	if installationID == 0 {
+		return ErrMissingInstallation
+	}
+
+	client, token, err := a.installationClient(ctx, installationID)
+	if err != nil {
+		return err
+	}
+
+	repo := event.GetRepo()
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+	if owner == "" {
+		// Push events identify the owner by name rather than login
+		owner = repo.GetOwner().GetName()
+	}
+
+	// Statuses are set on commits, so annotated tags use the tagged commit
+	commitID := event.GetHeadCommit().GetID()
+	if commitID == "" {
+		commitID = event.GetAfter()
+	}
+
+	setStatus := func(state, description string) error {
+		if len(description) > maxDescriptionLength {
+			description = description[:maxDescriptionLength-3] + "..."
+		}
+
+		_, _, err := client.Repositories.CreateStatus(ctx, owner, name, commitID, &github.RepoStatus{
+			State:       github.String(state),
+			Description: github.String(description),
+			Context:     github.String(StatusContext),
+		})
+		return err
+	}
+
+	if policy.IsMergeQueueRef(refName) {
+		return setStatus(stateSuccess, "gittuf verifies merge queue commits when they are merged")
+	}
+
+	if err := setStatus(statePending, fmt.Sprintf("Verifying %s", refName)); err != nil {
+		return err
+	}
+
+	err = a.verifier.VerifyRef(ctx, repo.GetFullName(), filepath.FromSlash(repo.GetFullName())+".git", refName, func(ctx context.Context, path string) error {
+		return syncRepository(ctx, path, repo.GetCloneURL(), &githttp.BasicAuth{Username: "x-access-token", Password: token})
+	})
+	switch {
+	case errors.Is(err, webhook.ErrSyncFailed):
+		return errors.Join(err, setStatus(stateError, "Unable to fetch repository"))
+	case err != nil:
+		return setStatus(stateFailure, fmt.Sprintf("gittuf verification failed: %s", err.Error()))
+	}
+
+	return setStatus(stateSuccess, fmt.Sprintf("gittuf verification succeeded for %s", refName))
+}
@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/webhook"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/go-github/v61/github"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testInstallationID    = 1
+	testInstallationToken = "installation-token"
+	testCommitID          = "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"
+)
+
+var testWebhookSecret = []byte("webhook-secret")
+
+// fakeGitHub is a minimal GitHub API that issues installation tokens and
+// records the commit statuses set.
+type fakeGitHub struct {
+	mu       sync.Mutex
+	statuses []*github.RepoStatus
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/api/v3/app/installations/1/access_tokens":
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"token": testInstallationToken}) //nolint:errcheck
+
+	case r.URL.Path == "/api/v3/repos/owner/repo/statuses/"+testCommitID:
+		if r.Header.Get("Authorization") != "Bearer "+testInstallationToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		status := &github.RepoStatus{}
+		if err := json.NewDecoder(r.Body).Decode(status); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		f.mu.Lock()
+		f.statuses = append(f.statuses, status)
+		f.mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(status) //nolint:errcheck
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeGitHub) states() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	states := []string{}
+	for _, status := range f.statuses {
+		states = append(states, status.GetState())
+	}
+	return states
+}
+
+func TestApp(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var verifyErr error
+	verifiedRefs := []string{}
+	syncRepository = func(_ context.Context, _, _ string, auth transport.AuthMethod) error {
+		assert.Equal(t, testInstallationToken, auth.(*githttp.BasicAuth).Password)
+		return nil
+	}
+	verifyRepository = func(_ context.Context, _, refName string) error {
+		verifiedRefs = append(verifiedRefs, refName)
+		return verifyErr
+	}
+	t.Cleanup(func() {
+		syncRepository = gitinterface.SyncMirror
+		verifyRepository = webhook.VerifyRef
+	})
+
+	newApp := func(t *testing.T) (*App, *fakeGitHub) {
+		t.Helper()
+
+		api := &fakeGitHub{}
+		server := httptest.NewServer(api)
+		t.Cleanup(server.Close)
+
+		app, err := NewApp(&Config{
+			AppID:         1234,
+			PrivateKey:    key,
+			WebhookSecret: testWebhookSecret,
+			CacheDir:      t.TempDir(),
+			APIURL:        server.URL,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return app, api
+	}
+
+	newPushEvent := func(refName string) *github.PushEvent {
+		return &github.PushEvent{
+			Ref:          github.String(refName),
+			After:        github.String(testCommitID),
+			HeadCommit:   &github.HeadCommit{ID: github.String(testCommitID)},
+			Installation: &github.Installation{ID: github.Int64(testInstallationID)},
+			Repo: &github.PushEventRepository{
+				Name:     github.String("repo"),
+				FullName: github.String("owner/repo"),
+				Owner:    &github.User{Name: github.String("owner")},
+				CloneURL: github.String("https://github.com/owner/repo.git"),
+			},
+		}
+	}
+
+	t.Run("missing webhook secret", func(t *testing.T) {
+		_, err := NewApp(&Config{AppID: 1234, PrivateKey: key})
+		assert.ErrorIs(t, err, ErrMissingWebhookSecret)
+	})
+
+	t.Run("verified push", func(t *testing.T) {
+		app, api := newApp(t)
+		verifyErr = nil
+		verifiedRefs = []string{}
+
+		assert.Nil(t, app.HandlePush(context.Background(), newPushEvent("refs/heads/main")))
+		assert.Equal(t, []string{"refs/heads/main"}, verifiedRefs)
+		assert.Equal(t, []string{statePending, stateSuccess}, api.states())
+		assert.Equal(t, StatusContext, api.statuses[1].GetContext())
+	})
+
+	t.Run("unverified push", func(t *testing.T) {
+		app, api := newApp(t)
+		verifyErr = errors.New("verifying Git namespace policies failed, " + strings.Repeat("unauthorized signature ", 10))
+		verifiedRefs = []string{}
+
+		assert.Nil(t, app.HandlePush(context.Background(), newPushEvent("refs/heads/main")))
+		assert.Equal(t, []string{statePending, stateFailure}, api.states())
+		assert.Len(t, api.statuses[1].GetDescription(), maxDescriptionLength)
+	})
+
+	t.Run("unable to fetch", func(t *testing.T) {
+		app, api := newApp(t)
+		syncRepository = func(_ context.Context, _, _ string, _ transport.AuthMethod) error {
+			return errors.New("network unreachable")
+		}
+		defer func() {
+			syncRepository = func(_ context.Context, _, _ string, _ transport.AuthMethod) error { return nil }
+		}()
+
+		assert.NotNil(t, app.HandlePush(context.Background(), newPushEvent("refs/heads/main")))
+		assert.Equal(t, []string{statePending, stateError}, api.states())
+	})
+
+	t.Run("ignored pushes", func(t *testing.T) {
+		app, api := newApp(t)
+		verifiedRefs = []string{}
+
+		assert.Nil(t, app.HandlePush(context.Background(), newPushEvent("refs/gittuf/reference-state-log")))
+
+		deletion := newPushEvent("refs/heads/feature")
+		deletion.Deleted = github.Bool(true)
+		assert.Nil(t, app.HandlePush(context.Background(), deletion))
+
+		assert.Empty(t, verifiedRefs)
+		assert.Empty(t, api.states())
+	})
+
+	t.Run("merge queue push", func(t *testing.T) {
+		app, api := newApp(t)
+		verifiedRefs = []string{}
+
+		assert.Nil(t, app.HandlePush(context.Background(), newPushEvent("refs/heads/gh-readonly-queue/main/pr-1-8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f")))
+		assert.Empty(t, verifiedRefs)
+		assert.Equal(t, []string{stateSuccess}, api.states())
+	})
+
+	t.Run("webhook deliveries", func(t *testing.T) {
+		app, api := newApp(t)
+		verifyErr = nil
+
+		payload, err := json.Marshal(newPushEvent("refs/heads/main"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		deliver := func(eventType string, payload []byte, secret []byte) int {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(payload)
+
+			request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("X-GitHub-Event", eventType)
+			request.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+			recorder := httptest.NewRecorder()
+			app.ServeHTTP(recorder, request)
+			return recorder.Code
+		}
+
+		assert.Equal(t, http.StatusUnauthorized, deliver("push", payload, []byte("wrong-secret")))
+		assert.Equal(t, http.StatusNoContent, deliver("ping", []byte(`{"zen":"Keep it logically awesome."}`), testWebhookSecret))
+
+		assert.Equal(t, http.StatusAccepted, deliver("push", payload, testWebhookSecret))
+		app.Wait()
+		assert.Equal(t, []string{statePending, stateSuccess}, api.states())
+	})
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/google/go-github/v61/github"
+)
+
+// installationClient returns a client authenticated as the specified
+// installation of the app, and the installation's access token, which is also
+// used to fetch the installation's repositories.
+func (a *App) installationClient(ctx context.Context, installationID int64) (*github.Client, string, error) {
+	appToken, err := credentials.NewGitHubAppJWT(a.appID, a.privateKey, now())
+	if err != nil {
+		return nil, "", err
+	}
+
+	appClient, err := a.newClient(appToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	installationToken, _, err := appClient.Apps.CreateInstallationToken(ctx, installationID, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to authenticate as installation %d: %w", installationID, err)
+	}
+
+	client, err := a.newClient(installationToken.GetToken())
+	if err != nil {
+		return nil, "", err
+	}
+
+	return client, installationToken.GetToken(), nil
+}
+
+func (a *App) newClient(token string) (*github.Client, error) {
+	client := github.NewClient(nil).WithAuthToken(token)
+	if a.apiURL == "" {
+		return client, nil
+	}
+
+	return client.WithEnterpriseURLs(a.apiURL, a.apiURL)
+}
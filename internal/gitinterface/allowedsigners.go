@@ -0,0 +1,287 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hiddeco/sshsig"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	ErrAllowedSignersFileNotSet = errors.New("gpg.ssh.allowedSignersFile is not set in git config")
+	ErrInvalidAllowedSigners    = errors.New("invalid allowed signers file")
+	ErrSSHKeyNotAllowed         = errors.New("SSH key is not an allowed signer at the time of the signature")
+)
+
+type allowedSignersContextKey struct{}
+
+// AllowedSigners contains the entries of an SSH allowed signers file, which Git
+// uses to map the keys of SSH signatures to the identities of their signers,
+// or principals. See the "ALLOWED SIGNERS" section of ssh-keygen(1) for the
+// format of the file.
+type AllowedSigners struct {
+	entries []*allowedSigner
+}
+
+type allowedSigner struct {
+	principals  []string
+	publicKey   ssh.PublicKey
+	namespaces  []string
+	validAfter  time.Time
+	validBefore time.Time
+
+	// certAuthority is true if the key is trusted to issue certificates
+	// rather than to sign directly
+	certAuthority bool
+}
+
+// LoadAllowedSignersFromConfig loads the allowed signers file configured in
+// Git's gpg.ssh.allowedSignersFile option.
+func LoadAllowedSignersFromConfig() (*AllowedSigners, error) {
+	path, err := GetConfigValue("gpg.ssh.allowedsignersfile")
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return nil, ErrAllowedSignersFileNotSet
+	}
+
+	path, err = ExpandHomeDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadAllowedSigners(path)
+}
+
+// LoadAllowedSigners loads the allowed signers file at the specified path.
+func LoadAllowedSigners(path string) (*AllowedSigners, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	allowedSigners, err := ParseAllowedSigners(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w '%s'", err, path)
+	}
+
+	return allowedSigners, nil
+}
+
+// ParseAllowedSigners parses the contents of an allowed signers file. Each
+// line contains a comma separated list of principals, optional options, and a
+// public key. Empty lines and lines starting with "#" are ignored.
+func ParseAllowedSigners(reader io.Reader) (*AllowedSigners, error) {
+	allowedSigners := &AllowedSigners{}
+
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		entry, err := parseAllowedSigner(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidAllowedSigners, lineNumber, err)
+		}
+		allowedSigners.entries = append(allowedSigners.entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return allowedSigners, nil
+}
+
+// FindPrincipals returns the principals the public key is allowed to sign Git
+// objects as at the specified time, honoring the namespaces, valid-after, and
+// valid-before options of each entry. This matches the principals Git reports
+// for SSH signatures using `ssh-keygen -Y find-principals`.
+func (a *AllowedSigners) FindPrincipals(publicKey ssh.PublicKey, at time.Time) []string {
+	principals := []string{}
+	for _, entry := range a.entries {
+		if entry.certAuthority || !bytes.Equal(entry.publicKey.Marshal(), publicKey.Marshal()) {
+			continue
+		}
+		if !entry.allowsNamespace(namespaceSSHSignature) {
+			continue
+		}
+		if !entry.validAfter.IsZero() && at.Before(entry.validAfter) {
+			continue
+		}
+		if !entry.validBefore.IsZero() && at.After(entry.validBefore) {
+			continue
+		}
+
+		principals = append(principals, entry.principals...)
+	}
+
+	return principals
+}
+
+// FindPrincipalsForSignature returns the principals the key that issued the
+// SSH signature is allowed to sign Git objects as at the specified time. Nil
+// is returned if the signature isn't an SSH signature.
+func (a *AllowedSigners) FindPrincipalsForSignature(signature []byte, at time.Time) []string {
+	if !bytes.HasPrefix(signature, []byte(sshSignatureHeader)) {
+		return nil
+	}
+
+	sshSignature, err := sshsig.Unarmor(signature)
+	if err != nil {
+		return nil
+	}
+
+	return a.FindPrincipals(sshSignature.PublicKey, at)
+}
+
+// ContextWithAllowedSigners returns a copy of the context that carries the
+// specified allowed signers. When verifying SSH signatures, the signing key
+// must also be an allowed signer at the time of the signature, matching the
+// results of `git log --show-signature`.
+func ContextWithAllowedSigners(ctx context.Context, allowedSigners *AllowedSigners) context.Context {
+	return context.WithValue(ctx, allowedSignersContextKey{}, allowedSigners)
+}
+
+// AllowedSignersFromContext returns the allowed signers carried by the
+// context, if any.
+func AllowedSignersFromContext(ctx context.Context) *AllowedSigners {
+	allowedSigners, ok := ctx.Value(allowedSignersContextKey{}).(*AllowedSigners)
+	if !ok {
+		return nil
+	}
+
+	return allowedSigners
+}
+
+// checkAllowedSigner checks that the key that issued the verified SSH
+// signature is an allowed signer at the time of the signature, if the context
+// carries allowed signers.
+func checkAllowedSigner(ctx context.Context, signature []byte, at time.Time) error {
+	allowedSigners := AllowedSignersFromContext(ctx)
+	if allowedSigners == nil {
+		return nil
+	}
+
+	if len(allowedSigners.FindPrincipalsForSignature(signature, at)) == 0 {
+		return ErrSSHKeyNotAllowed
+	}
+
+	return nil
+}
+
+func parseAllowedSigner(line string) (*allowedSigner, error) {
+	principalsField, rest, err := cutAllowedSignerField(line)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, _, options, _, err := ssh.ParseAuthorizedKey([]byte(rest))
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &allowedSigner{publicKey: publicKey}
+	for _, principal := range strings.Split(principalsField, ",") {
+		if principal = strings.TrimSpace(principal); principal != "" {
+			entry.principals = append(entry.principals, principal)
+		}
+	}
+	if len(entry.principals) == 0 {
+		return nil, fmt.Errorf("no principals specified")
+	}
+
+	for _, option := range options {
+		name, value, _ := strings.Cut(option, "=")
+		value = strings.Trim(value, `"`)
+
+		switch strings.ToLower(name) {
+		case "cert-authority":
+			entry.certAuthority = true
+		case "namespaces":
+			entry.namespaces = strings.Split(value, ",")
+		case "valid-after":
+			entry.validAfter, err = parseAllowedSignerTime(value)
+		case "valid-before":
+			entry.validBefore, err = parseAllowedSignerTime(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid option '%s': %w", option, err)
+		}
+	}
+
+	return entry, nil
+}
+
+// cutAllowedSignerField returns the first field of the line, which may be
+// quoted, and the remainder of the line.
+func cutAllowedSignerField(line string) (string, string, error) {
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`)
+		if end == -1 {
+			return "", "", fmt.Errorf("unterminated quote")
+		}
+		return line[1 : end+1], strings.TrimSpace(line[end+2:]), nil
+	}
+
+	field, rest, found := strings.Cut(line, " ")
+	if !found {
+		return "", "", fmt.Errorf("no public key specified")
+	}
+	return field, strings.TrimSpace(rest), nil
+}
+
+// parseAllowedSignerTime parses the timestamps used in the valid-after and
+// valid-before options, of the form YYYYMMDD[HHMM[SS]]. Timestamps are in the
+// local time zone unless they end with "Z", indicating UTC.
+func parseAllowedSignerTime(value string) (time.Time, error) {
+	location := time.Local
+	if trimmed, isUTC := strings.CutSuffix(value, "Z"); isUTC {
+		value = trimmed
+		location = time.UTC
+	}
+
+	var layout string
+	switch len(value) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("unexpected timestamp format")
+	}
+
+	return time.ParseInLocation(layout, value, location)
+}
+
+func (e *allowedSigner) allowsNamespace(namespace string) bool {
+	if len(e.namespaces) == 0 {
+		return true
+	}
+
+	for _, allowed := range e.namespaces {
+		if strings.TrimSpace(allowed) == namespace {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestParseAllowedSigners(t *testing.T) {
+	rsaKey := strings.TrimSpace(string(artifacts.SSHRSAPublicSSH))
+	ecdsaKey := strings.TrimSpace(string(artifacts.SSHECDSAPublicSSH))
+
+	t.Run("valid entries", func(t *testing.T) {
+		contents := fmt.Sprintf(`# allowed signers
+
+jane.doe@example.com,jane@example.com %s
+"john doe@example.com" namespaces="git,file",valid-after="20230101",valid-before="20240101Z" %s
+*@example.com cert-authority %s
+`, rsaKey, ecdsaKey, rsaKey)
+
+		allowedSigners, err := ParseAllowedSigners(strings.NewReader(contents))
+		if !assert.Nil(t, err) {
+			return
+		}
+		if !assert.Len(t, allowedSigners.entries, 3) {
+			return
+		}
+
+		assert.Equal(t, []string{"jane.doe@example.com", "jane@example.com"}, allowedSigners.entries[0].principals)
+		assert.Nil(t, allowedSigners.entries[0].namespaces)
+		assert.True(t, allowedSigners.entries[0].validAfter.IsZero())
+		assert.False(t, allowedSigners.entries[0].certAuthority)
+
+		assert.Equal(t, []string{"john doe@example.com"}, allowedSigners.entries[1].principals)
+		assert.Equal(t, []string{"git", "file"}, allowedSigners.entries[1].namespaces)
+		assert.Equal(t, time.Date(2023, time.January, 1, 0, 0, 0, 0, time.Local), allowedSigners.entries[1].validAfter)
+		assert.Equal(t, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), allowedSigners.entries[1].validBefore)
+
+		assert.True(t, allowedSigners.entries[2].certAuthority)
+	})
+
+	t.Run("invalid entries", func(t *testing.T) {
+		tests := map[string]string{
+			"no public key":     "jane.doe@example.com",
+			"invalid key":       "jane.doe@example.com ssh-rsa invalid",
+			"unterminated":      fmt.Sprintf(`"jane.doe@example.com %s`, rsaKey),
+			"invalid timestamp": fmt.Sprintf(`jane.doe@example.com valid-after="2023" %s`, rsaKey),
+		}
+
+		for name, contents := range tests {
+			_, err := ParseAllowedSigners(strings.NewReader(contents))
+			assert.ErrorIs(t, err, ErrInvalidAllowedSigners, name)
+		}
+	})
+}
+
+func TestFindPrincipals(t *testing.T) {
+	rsaKey, _, _, _, err := ssh.ParseAuthorizedKey(artifacts.SSHRSAPublicSSH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, _, _, _, err := ssh.ParseAuthorizedKey(artifacts.SSHECDSAPublicSSH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := fmt.Sprintf(`jane.doe@example.com valid-after="20230101Z",valid-before="20240101Z" %s
+jane@example.com %s
+john.doe@example.com namespaces="file" %s
+*@example.com cert-authority %s
+`,
+		strings.TrimSpace(string(artifacts.SSHRSAPublicSSH)),
+		strings.TrimSpace(string(artifacts.SSHRSAPublicSSH)),
+		strings.TrimSpace(string(artifacts.SSHECDSAPublicSSH)),
+		strings.TrimSpace(string(artifacts.SSHECDSAPublicSSH)),
+	)
+	allowedSigners, err := ParseAllowedSigners(strings.NewReader(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		publicKey          ssh.PublicKey
+		at                 time.Time
+		expectedPrincipals []string
+	}{
+		"within validity period": {
+			publicKey:          rsaKey,
+			at:                 time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC),
+			expectedPrincipals: []string{"jane.doe@example.com", "jane@example.com"},
+		},
+		"before validity period": {
+			publicKey:          rsaKey,
+			at:                 time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC),
+			expectedPrincipals: []string{"jane@example.com"},
+		},
+		"after validity period": {
+			publicKey:          rsaKey,
+			at:                 time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+			expectedPrincipals: []string{"jane@example.com"},
+		},
+		"other namespace and cert authority": {
+			publicKey:          ecdsaKey,
+			at:                 time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC),
+			expectedPrincipals: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		principals := allowedSigners.FindPrincipals(test.publicKey, test.at)
+		assert.Equal(t, test.expectedPrincipals, principals, name)
+	}
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gittuf/gittuf/internal/plugin"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// backend implements the operations a Repository uses to read and write
+// objects, references, and config. By default, these operations use the Git
+// binary. When gittuf is built with the `libgit2` build tag, they use libgit2
+// instead, which removes the runtime dependency on the Git binary for
+// deployments such as servers and containers where it may not be installed.
+// They can also be provided by a storage plugin at runtime, see
+// plugin.Storage.
+type backend interface {
+	// readObject returns the type and contents of the object.
+	readObject(objectID Hash) (string, []byte, error)
+
+	// readBlobStream returns a reader that streams the contents of the blob.
+	readBlobStream(blobID Hash) (io.ReadCloser, error)
+
+	// hasObject returns true if the object exists in the repository.
+	hasObject(objectID Hash) bool
+
+	// writeObject writes an object of the specified type with the contents
+	// and returns its ID.
+	writeObject(objectType string, contents []byte) (Hash, error)
+
+	// getReference returns the ID of the object the reference points to.
+	getReference(refName string) (Hash, error)
+
+	// setReference updates the reference to point to the object.
+	setReference(refName string, objectID Hash) error
+
+	// checkAndSetReference updates the reference to point to the object if
+	// it currently points to oldObjectID, or doesn't exist if oldObjectID is
+	// the zero hash. Otherwise, ErrReferenceHasChanged is returned.
+	checkAndSetReference(refName string, objectID, oldObjectID Hash) error
+
+	// getConfig returns all the values of each key in the applicable Git
+	// config for the repository, in increasing order of precedence.
+	getConfig() (*GitConfig, error)
+
+	// setConfig sets the key to the value in the scope's config.
+	setConfig(scope ConfigScope, key, value string) error
+
+	// close releases any resources held by the backend. The backend can still
+	// be used after it is closed.
+	close() error
+}
+
+// getBackend returns the repository's backend, creating it when it's first
+// needed. If a storage plugin is set using plugin.StorageEnvKey, the plugin is
+// used as the backend.
+func (r *Repository) getBackend() backend {
+	r.backendOnce.Do(func() {
+		if name := plugin.GetStoragePluginName(); name != "" {
+			r.backend = newPluginBackend(name, r.gitDirPath)
+			return
+		}
+
+		r.backend = newBackend(r.gitDirPath)
+	})
+
+	return r.backend
+}
+
+// Close releases the resources used to read objects from the repository, such
+// as long-lived Git processes. The repository can still be used after it is
+// closed.
+func (r *Repository) Close() error {
+	return r.getBackend().close()
+}
+
+// HasObject returns true if the object exists in the repository.
+func (r *Repository) HasObject(objectID Hash) bool {
+	return r.getBackend().hasObject(objectID)
+}
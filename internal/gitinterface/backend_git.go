@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !libgit2
+
+package gitinterface
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// gitBackend implements backend using the Git binary. Objects are read using
+// long-lived `git cat-file` processes that are started when first needed and
+// stopped when the backend is closed.
+type gitBackend struct {
+	gitDirPath string
+
+	catFileMu        sync.Mutex
+	catFileProcesses map[string]*catFileProcess
+}
+
+func newBackend(gitDirPath string) backend {
+	return &gitBackend{gitDirPath: gitDirPath}
+}
+
+// ensureBackendAvailable inspects the PATH to ensure Git is installed.
+func ensureBackendAvailable() error {
+	if _, err := exec.LookPath(binary); err != nil {
+		return fmt.Errorf("unable to find Git binary, is Git installed?")
+	}
+
+	return nil
+}
+
+// discoverGitDir returns the GIT_DIR of the repository in the current working
+// directory.
+func discoverGitDir() (string, error) {
+	return (&Repository{}).executeGitCommandDirectString("rev-parse", "--git-dir")
+}
+
+func (b *gitBackend) readObject(objectID Hash) (string, []byte, error) {
+	objectType, _, contents, err := b.catFile(catFileBatch, objectID)
+	return objectType, contents, err
+}
+
+func (b *gitBackend) readBlobStream(blobID Hash) (io.ReadCloser, error) {
+	objectType, _, _, err := b.catFile(catFileBatchCheck, blobID)
+	if err != nil {
+		return nil, err
+	}
+	if objectType != "blob" {
+		return nil, fmt.Errorf("requested Git ID '%s' is not a blob object", blobID.String())
+	}
+
+	return newCatFileBlobReader(b.gitDirPath, blobID)
+}
+
+func (b *gitBackend) hasObject(objectID Hash) bool {
+	_, _, _, err := b.catFile(catFileBatchCheck, objectID)
+	return err == nil
+}
+
+func (b *gitBackend) writeObject(objectType string, contents []byte) (Hash, error) {
+	stdInBuf := bytes.NewBuffer(contents)
+	objID, err := b.repository().executeGitCommandWithStdInString(stdInBuf, "hash-object", "-t", objectType, "-w", "--stdin")
+	if err != nil {
+		return ZeroHash, err
+	}
+
+	return NewHash(objID)
+}
+
+func (b *gitBackend) getReference(refName string) (Hash, error) {
+	objID, err := b.repository().executeGitCommandString("rev-parse", "--verify", "--end-of-options", refName)
+	if err != nil {
+		return ZeroHash, err
+	}
+
+	return NewHash(objID)
+}
+
+func (b *gitBackend) setReference(refName string, objectID Hash) error {
+	_, err := b.repository().executeGitCommandString("update-ref", "--no-deref", refName, objectID.String())
+	return err
+}
+
+func (b *gitBackend) checkAndSetReference(refName string, objectID, oldObjectID Hash) error {
+	// Git treats the zero hash or an empty string as the old value of a
+	// reference that must not exist
+	_, err := b.repository().executeGitCommandString("update-ref", "--no-deref", refName, objectID.String(), oldObjectID.String())
+	if err != nil {
+		for _, message := range []string{"but expected", "reference already exists", "unable to resolve reference"} {
+			if strings.Contains(err.Error(), message) {
+				return fmt.Errorf("%w: %w", ErrReferenceHasChanged, err)
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (b *gitBackend) getConfig() (*GitConfig, error) {
+	// Entries are terminated by NUL bytes, so trimming the output doesn't
+	// modify any values
+	stdOut, err := b.repository().executeGitCommandString("config", "--list", "--show-scope", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseConfig(strings.NewReader(stdOut))
+}
+
+func (b *gitBackend) setConfig(scope ConfigScope, key, value string) error {
+	_, err := b.repository().executeGitCommandString("config", "--"+string(scope), key, value)
+	return err
+}
+
+func (b *gitBackend) close() error {
+	b.catFileMu.Lock()
+	defer b.catFileMu.Unlock()
+
+	var err error
+	for mode, process := range b.catFileProcesses {
+		err = errors.Join(err, process.close())
+		delete(b.catFileProcesses, mode)
+	}
+
+	return err
+}
+
+// catFile requests the object from the long-lived `git cat-file` process for
+// the mode, starting the process if needed. If the request fails for reasons
+// other than the object being missing, the process is stopped so that a new
+// one is started for subsequent requests.
+func (b *gitBackend) catFile(mode string, objectID Hash) (string, int, []byte, error) {
+	b.catFileMu.Lock()
+	defer b.catFileMu.Unlock()
+
+	if b.catFileProcesses == nil {
+		b.catFileProcesses = map[string]*catFileProcess{}
+	}
+
+	process, has := b.catFileProcesses[mode]
+	if !has {
+		var err error
+		process, err = newCatFileProcess(b.gitDirPath, mode)
+		if err != nil {
+			return "", 0, nil, err
+		}
+		b.catFileProcesses[mode] = process
+	}
+
+	objectType, size, contents, err := process.request(objectID)
+	if err != nil && !errors.Is(err, ErrObjectNotFound) {
+		delete(b.catFileProcesses, mode)
+		process.close() //nolint:errcheck
+	}
+
+	return objectType, size, contents, err
+}
+
+// repository returns a Repository for the backend's GIT_DIR that is used to
+// execute Git commands.
+func (b *gitBackend) repository() *Repository {
+	return &Repository{gitDirPath: b.gitDirPath}
+}
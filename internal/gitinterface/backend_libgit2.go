@@ -0,0 +1,423 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build libgit2
+
+package gitinterface
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	git2go "github.com/libgit2/git2go/v34"
+)
+
+// libgit2Backend implements backend using libgit2. The repository is opened
+// when it's first needed and freed when the backend is closed.
+type libgit2Backend struct {
+	gitDirPath string
+
+	mu   sync.Mutex
+	repo *git2go.Repository
+}
+
+func newBackend(gitDirPath string) backend {
+	return &libgit2Backend{gitDirPath: gitDirPath}
+}
+
+// ensureBackendAvailable is a no-op as libgit2 is linked into gittuf.
+func ensureBackendAvailable() error {
+	return nil
+}
+
+// discoverGitDir returns the GIT_DIR of the repository in the current working
+// directory.
+func discoverGitDir() (string, error) {
+	gitDirPath, err := git2go.Discover(".", false, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Clean(gitDirPath), nil
+}
+
+func (b *libgit2Backend) readObject(objectID Hash) (string, []byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	odb, err := b.odb()
+	if err != nil {
+		return "", nil, err
+	}
+	defer odb.Free()
+
+	object, err := odb.Read(git2go.NewOidFromBytes(objectID))
+	if err != nil {
+		if git2go.IsErrorCode(err, git2go.ErrorCodeNotFound) {
+			return "", nil, fmt.Errorf("%w: '%s'", ErrObjectNotFound, objectID.String())
+		}
+		return "", nil, err
+	}
+	defer object.Free()
+
+	// The object's data is owned by libgit2 and freed with the object
+	contents := make([]byte, object.Len())
+	copy(contents, object.Data())
+
+	return strings.ToLower(object.Type().String()), contents, nil
+}
+
+func (b *libgit2Backend) readBlobStream(blobID Hash) (io.ReadCloser, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	odb, err := b.odb()
+	if err != nil {
+		return nil, err
+	}
+	defer odb.Free()
+
+	oid := git2go.NewOidFromBytes(blobID)
+
+	// libgit2 only streams loose objects, packed objects are read into
+	// memory
+	stream, err := odb.NewReadStream(oid)
+	if err == nil {
+		if stream.Type != git2go.ObjectBlob {
+			stream.Free()
+			return nil, fmt.Errorf("requested Git ID '%s' is not a blob object", blobID.String())
+		}
+		return &libgit2BlobReader{stream: stream}, nil
+	}
+
+	object, err := odb.Read(oid)
+	if err != nil {
+		if git2go.IsErrorCode(err, git2go.ErrorCodeNotFound) {
+			return nil, fmt.Errorf("%w: '%s'", ErrObjectNotFound, blobID.String())
+		}
+		return nil, err
+	}
+	defer object.Free()
+
+	if object.Type() != git2go.ObjectBlob {
+		return nil, fmt.Errorf("requested Git ID '%s' is not a blob object", blobID.String())
+	}
+
+	contents := make([]byte, object.Len())
+	copy(contents, object.Data())
+
+	return io.NopCloser(bytes.NewReader(contents)), nil
+}
+
+func (b *libgit2Backend) hasObject(objectID Hash) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	odb, err := b.odb()
+	if err != nil {
+		return false
+	}
+	defer odb.Free()
+
+	return odb.Exists(git2go.NewOidFromBytes(objectID))
+}
+
+func (b *libgit2Backend) writeObject(objectType string, contents []byte) (Hash, error) {
+	var libgit2ObjectType git2go.ObjectType
+	switch objectType {
+	case "blob":
+		libgit2ObjectType = git2go.ObjectBlob
+	case "tree":
+		libgit2ObjectType = git2go.ObjectTree
+	case "commit":
+		libgit2ObjectType = git2go.ObjectCommit
+	case "tag":
+		libgit2ObjectType = git2go.ObjectTag
+	default:
+		return ZeroHash, fmt.Errorf("unknown object type '%s'", objectType)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	odb, err := b.odb()
+	if err != nil {
+		return ZeroHash, err
+	}
+	defer odb.Free()
+
+	oid, err := odb.Write(contents, libgit2ObjectType)
+	if err != nil {
+		return ZeroHash, err
+	}
+
+	return hashFromOid(oid), nil
+}
+
+func (b *libgit2Backend) getReference(refName string) (Hash, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	repo, err := b.open()
+	if err != nil {
+		return ZeroHash, err
+	}
+
+	ref, err := repo.References.Lookup(refName)
+	if err != nil {
+		return ZeroHash, err
+	}
+	defer ref.Free()
+
+	resolvedRef, err := ref.Resolve()
+	if err != nil {
+		return ZeroHash, err
+	}
+	defer resolvedRef.Free()
+
+	return hashFromOid(resolvedRef.Target()), nil
+}
+
+func (b *libgit2Backend) setReference(refName string, objectID Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+
+	currentRef, err := repo.References.Lookup(refName)
+	if err != nil {
+		if git2go.IsErrorCode(err, git2go.ErrorCodeNotFound) {
+			return createReference(repo, refName, objectID)
+		}
+		return err
+	}
+	defer currentRef.Free()
+
+	if currentRef.Type() == git2go.ReferenceSymbolic {
+		// Like `git update-ref --no-deref`, the symbolic reference is
+		// replaced rather than the reference it points to being updated
+		ref, err := repo.References.Create(refName, git2go.NewOidFromBytes(objectID), true, "")
+		if err != nil {
+			return err
+		}
+		ref.Free()
+
+		return nil
+	}
+
+	return setReferenceTarget(currentRef, objectID)
+}
+
+func (b *libgit2Backend) checkAndSetReference(refName string, objectID, oldObjectID Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+
+	currentRef, err := repo.References.Lookup(refName)
+	if err != nil {
+		if git2go.IsErrorCode(err, git2go.ErrorCodeNotFound) {
+			if !oldObjectID.IsZero() {
+				return fmt.Errorf("%w: '%s' doesn't exist", ErrReferenceHasChanged, refName)
+			}
+			return createReference(repo, refName, objectID)
+		}
+		return err
+	}
+	defer currentRef.Free()
+
+	if currentRef.Type() == git2go.ReferenceSymbolic || !currentRef.Target().Equal(git2go.NewOidFromBytes(oldObjectID)) {
+		return fmt.Errorf("%w: '%s' doesn't point to '%s'", ErrReferenceHasChanged, refName, oldObjectID.String())
+	}
+
+	return setReferenceTarget(currentRef, objectID)
+}
+
+// createReference creates the reference, failing if it was created by another
+// process after it was found not to exist.
+func createReference(repo *git2go.Repository, refName string, objectID Hash) error {
+	ref, err := repo.References.Create(refName, git2go.NewOidFromBytes(objectID), false, "")
+	if err != nil {
+		if git2go.IsErrorCode(err, git2go.ErrorCodeExists) {
+			return fmt.Errorf("%w: %w", ErrReferenceHasChanged, err)
+		}
+		return err
+	}
+	ref.Free()
+
+	return nil
+}
+
+// setReferenceTarget updates the reference to point to the object. libgit2
+// compares and swaps the reference's target with the target it had when it was
+// looked up, so the update fails if the reference was updated by another
+// process in the meantime.
+func setReferenceTarget(currentRef *git2go.Reference, objectID Hash) error {
+	ref, err := currentRef.SetTarget(git2go.NewOidFromBytes(objectID), "")
+	if err != nil {
+		if git2go.IsErrorCode(err, git2go.ErrorCodeModified) {
+			return fmt.Errorf("%w: %w", ErrReferenceHasChanged, err)
+		}
+		return err
+	}
+	ref.Free()
+
+	return nil
+}
+
+func (b *libgit2Backend) getConfig() (*GitConfig, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+
+	gitConfig, err := repo.Config()
+	if err != nil {
+		return nil, err
+	}
+	defer gitConfig.Free()
+
+	iterator, err := gitConfig.NewIterator()
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Free()
+
+	// Entries are iterated from the lowest to the highest precedence
+	entries := []*ConfigEntry{}
+	for {
+		entry, err := iterator.Next()
+		if err != nil {
+			if git2go.IsErrorCode(err, git2go.ErrorCodeIterOver) {
+				break
+			}
+			return nil, err
+		}
+
+		entries = append(entries, &ConfigEntry{Scope: libgit2ConfigScope(entry.Level), Key: entry.Name, Value: entry.Value})
+	}
+
+	return NewGitConfig(entries), nil
+}
+
+func (b *libgit2Backend) setConfig(scope ConfigScope, key, value string) error {
+	var level git2go.ConfigLevel
+	switch scope {
+	case ConfigScopeSystem:
+		level = git2go.ConfigLevelSystem
+	case ConfigScopeGlobal:
+		level = git2go.ConfigLevelGlobal
+	case ConfigScopeLocal:
+		level = git2go.ConfigLevelLocal
+	default:
+		return fmt.Errorf("%w: '%s' isn't supported by libgit2", ErrUnsupportedConfigScope, scope)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+
+	gitConfig, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	defer gitConfig.Free()
+
+	scopeConfig, err := gitConfig.OpenLevel(gitConfig, level)
+	if err != nil {
+		return err
+	}
+	defer scopeConfig.Free()
+
+	return scopeConfig.SetString(key, value)
+}
+
+// libgit2ConfigScope returns the scope, as named by Git, of the libgit2 config
+// level.
+func libgit2ConfigScope(level git2go.ConfigLevel) ConfigScope {
+	switch level {
+	case git2go.ConfigLevelProgramdata, git2go.ConfigLevelSystem:
+		return ConfigScopeSystem
+	case git2go.ConfigLevelXDG, git2go.ConfigLevelGlobal:
+		return ConfigScopeGlobal
+	case git2go.ConfigLevelLocal:
+		return ConfigScopeLocal
+	default:
+		return ConfigScopeCommand
+	}
+}
+
+func (b *libgit2Backend) close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.repo != nil {
+		b.repo.Free()
+		b.repo = nil
+	}
+
+	return nil
+}
+
+// open returns the libgit2 repository, opening it if needed. The caller must
+// hold the backend's lock.
+func (b *libgit2Backend) open() (*git2go.Repository, error) {
+	if b.repo == nil {
+		repo, err := git2go.OpenRepository(b.gitDirPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open repository using libgit2: %w", err)
+		}
+		b.repo = repo
+	}
+
+	return b.repo, nil
+}
+
+// odb returns the repository's object database. The caller must hold the
+// backend's lock and free the object database.
+func (b *libgit2Backend) odb() (*git2go.Odb, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.Odb()
+}
+
+// hashFromOid returns the Hash for the libgit2 object ID.
+func hashFromOid(oid *git2go.Oid) Hash {
+	hash := make(Hash, len(oid))
+	copy(hash, oid[:])
+	return hash
+}
+
+// libgit2BlobReader streams the contents of a blob from a libgit2 read stream.
+type libgit2BlobReader struct {
+	stream *git2go.OdbReadStream
+}
+
+func (r *libgit2BlobReader) Read(p []byte) (int, error) {
+	return r.stream.Read(p)
+}
+
+func (r *libgit2BlobReader) Close() error {
+	err := r.stream.Close()
+	r.stream.Free()
+	return err
+}
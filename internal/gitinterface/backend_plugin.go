@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gittuf/gittuf/internal/plugin"
+)
+
+// pluginBackend implements backend using a storage plugin, which is used in
+// place of the Git binary or libgit2 when plugin.StorageEnvKey is set. If the
+// plugin can't be found, every operation fails.
+type pluginBackend struct {
+	storage *plugin.Storage
+	err     error
+}
+
+func newPluginBackend(name, gitDirPath string) backend {
+	storage, err := plugin.LoadStorage(name, gitDirPath)
+	return &pluginBackend{storage: storage, err: err}
+}
+
+func (b *pluginBackend) readObject(objectID Hash) (string, []byte, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	objectType, contents, err := b.storage.ReadObject(context.Background(), objectID.String())
+	if err != nil {
+		return "", nil, err
+	}
+	if objectType == "" {
+		return "", nil, fmt.Errorf("%w: '%s'", ErrObjectNotFound, objectID.String())
+	}
+
+	return objectType, contents, nil
+}
+
+func (b *pluginBackend) readBlobStream(blobID Hash) (io.ReadCloser, error) {
+	objectType, contents, err := b.readObject(blobID)
+	if err != nil {
+		return nil, err
+	}
+	if objectType != "blob" {
+		return nil, fmt.Errorf("requested Git ID '%s' is not a blob object", blobID.String())
+	}
+
+	return io.NopCloser(bytes.NewReader(contents)), nil
+}
+
+func (b *pluginBackend) hasObject(objectID Hash) bool {
+	if b.err != nil {
+		return false
+	}
+
+	exists, err := b.storage.HasObject(context.Background(), objectID.String())
+	return err == nil && exists
+}
+
+func (b *pluginBackend) writeObject(objectType string, contents []byte) (Hash, error) {
+	if b.err != nil {
+		return ZeroHash, b.err
+	}
+
+	objectID, err := b.storage.WriteObject(context.Background(), objectType, contents)
+	if err != nil {
+		return ZeroHash, err
+	}
+
+	return NewHash(objectID)
+}
+
+func (b *pluginBackend) getReference(refName string) (Hash, error) {
+	if b.err != nil {
+		return ZeroHash, b.err
+	}
+
+	objectID, err := b.storage.GetReference(context.Background(), refName)
+	if err != nil {
+		return ZeroHash, err
+	}
+	if objectID == "" {
+		return ZeroHash, fmt.Errorf("%w: '%s'", ErrReferenceNotFound, refName)
+	}
+
+	return NewHash(objectID)
+}
+
+func (b *pluginBackend) setReference(refName string, objectID Hash) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	return b.storage.SetReference(context.Background(), refName, objectID.String())
+}
+
+func (b *pluginBackend) checkAndSetReference(refName string, objectID, oldObjectID Hash) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	// Storage plugins can't compare and swap references, so this is not
+	// atomic
+	currentID, err := b.storage.GetReference(context.Background(), refName)
+	if err != nil {
+		return err
+	}
+	if (currentID == "" && !oldObjectID.IsZero()) || (currentID != "" && currentID != oldObjectID.String()) {
+		return ErrReferenceHasChanged
+	}
+
+	return b.storage.SetReference(context.Background(), refName, objectID.String())
+}
+
+func (b *pluginBackend) getConfig() (*GitConfig, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	values, err := b.storage.GetConfig(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	// The plugin stores the repository's config, so every value is in the
+	// local scope. Keys are sorted so that the entries are deterministic.
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := []*ConfigEntry{}
+	for _, key := range keys {
+		for _, value := range values[key] {
+			entries = append(entries, &ConfigEntry{Scope: ConfigScopeLocal, Key: key, Value: value})
+		}
+	}
+
+	return NewGitConfig(entries), nil
+}
+
+func (b *pluginBackend) setConfig(scope ConfigScope, key, value string) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	if scope != ConfigScopeLocal {
+		return fmt.Errorf("%w: storage plugins only store the repository's local config", ErrUnsupportedConfigScope)
+	}
+
+	return b.storage.SetConfig(context.Background(), key, value)
+}
+
+// close is a no-op as the plugin is invoked for each operation.
+func (b *pluginBackend) close() error {
+	return nil
+}
@@ -0,0 +1,420 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+var ErrUnsupportedSignedObject = errors.New("only the signatures of commits and tags can be verified")
+
+// BatchVerifier verifies the signatures of Git commits and tags, reusing the
+// state needed for verification across signatures: the trusted Sigstore
+// certificates and Rekor client, the parsed public keys, the certificates
+// embedded in gitsign signatures, and the results of earlier verifications.
+// Verify verifies the signatures of independent objects, such as the entries
+// of a long RSL, concurrently. VerifyCommitSignature and VerifyTagSignature use
+// the BatchVerifier carried by their context, if any. A BatchVerifier is safe
+// for concurrent use.
+type BatchVerifier struct {
+	workers int
+
+	sigstoreOnce     sync.Once
+	sigstoreMaterial *sigstoreVerificationMaterial
+	sigstoreErr      error
+
+	mu           sync.Mutex
+	sshKeys      map[string]*parsedSSHKey
+	gpgKeyRings  map[string]*parsedGPGKeyRing
+	certificates map[plumbing.Hash]*verifiedCertificate
+	results      map[signatureResult]error
+}
+
+type parsedSSHKey struct {
+	publicKey ssh.PublicKey
+	err       error
+}
+
+type parsedGPGKeyRing struct {
+	keyRing openpgp.EntityList
+	err     error
+}
+
+type verifiedCertificate struct {
+	certificate *x509.Certificate
+	err         error
+}
+
+type signatureResult struct {
+	objectID plumbing.Hash
+	keyID    string
+}
+
+// SignatureCheck is a Git commit or tag whose signature must be issued by one
+// of the keys.
+type SignatureCheck struct {
+	Object object.Object
+	Keys   []*tuf.Key
+}
+
+// NewBatchVerifier returns a BatchVerifier that verifies up to the specified
+// number of signatures concurrently. If workers is less than one, the number
+// of CPUs available is used.
+func NewBatchVerifier(workers int) *BatchVerifier {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	return &BatchVerifier{
+		workers:      workers,
+		sshKeys:      map[string]*parsedSSHKey{},
+		gpgKeyRings:  map[string]*parsedGPGKeyRing{},
+		certificates: map[plumbing.Hash]*verifiedCertificate{},
+		results:      map[signatureResult]error{},
+	}
+}
+
+// Verify verifies the signature of each check's object using the check's keys,
+// verifying the checks concurrently. The returned errors are in the order of
+// the checks. An error is nil if the object's signature was issued by one of
+// the check's keys, and wraps ErrIncorrectVerificationKey if it wasn't.
+func (b *BatchVerifier) Verify(ctx context.Context, checks []*SignatureCheck) []error {
+	ctx = ContextWithBatchVerifier(ctx, b)
+	errs := make([]error, len(checks))
+
+	workers := min(b.workers, len(checks))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				if err := ctx.Err(); err != nil {
+					errs[index] = err
+					continue
+				}
+				errs[index] = verifySignatureCheck(ctx, checks[index])
+			}
+		}()
+	}
+
+	for index := range checks {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	return errs
+}
+
+// ContextWithBatchVerifier returns a copy of the context that carries the
+// specified BatchVerifier, which is used to verify Git signatures.
+func ContextWithBatchVerifier(ctx context.Context, verifier *BatchVerifier) context.Context {
+	return context.WithValue(ctx, batchVerifierContextKey{}, verifier)
+}
+
+// BatchVerifierFromContext returns the BatchVerifier carried by the context,
+// if any.
+func BatchVerifierFromContext(ctx context.Context) *BatchVerifier {
+	verifier, ok := ctx.Value(batchVerifierContextKey{}).(*BatchVerifier)
+	if !ok {
+		return nil
+	}
+
+	return verifier
+}
+
+type batchVerifierContextKey struct{}
+
+// verifierForContext returns the BatchVerifier carried by the context. If the
+// context doesn't carry one, a new verifier is returned so that a single
+// verification doesn't need special handling.
+func verifierForContext(ctx context.Context) *BatchVerifier {
+	if verifier := BatchVerifierFromContext(ctx); verifier != nil {
+		return verifier
+	}
+
+	return NewBatchVerifier(1)
+}
+
+// verifySignatureCheck verifies the check's object using each of its keys in
+// turn until one of them verifies the signature.
+func verifySignatureCheck(ctx context.Context, check *SignatureCheck) error {
+	for _, key := range check.Keys {
+		var err error
+		switch o := check.Object.(type) {
+		case *object.Commit:
+			err = VerifyCommitSignature(ctx, o, key)
+		case *object.Tag:
+			err = VerifyTagSignature(ctx, o, key)
+		default:
+			return fmt.Errorf("%w: %T", ErrUnsupportedSignedObject, check.Object)
+		}
+
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrIncorrectVerificationKey) && !errors.Is(err, ErrUnknownSigningMethod) {
+			return err
+		}
+	}
+
+	return ErrIncorrectVerificationKey
+}
+
+// verifySignature verifies the signature of the commit or tag using the key.
+// The allowed signers carried by the context are not checked, so that the
+// result can be reused. Results are cached unless Sigstore's state couldn't be
+// loaded, which may be a transient failure. Results are also persisted in the
+// signature cache, if enabled by SetSignatureCacheDir.
+func (b *BatchVerifier) verifySignature(ctx context.Context, gitObject object.Object, key *tuf.Key) error {
+	result := signatureResult{objectID: gitObject.ID(), keyID: key.KeyID}
+	cacheable := !result.objectID.IsZero() && key.KeyID != ""
+
+	// Results are also persisted across invocations, if enabled
+	var (
+		persistentCache *persistentSignatureCache
+		scope           string
+	)
+	if cacheable && signatureCacheable(key) {
+		persistentCache = getSignatureCache()
+		scope = signatureCacheScopeFromContext(ctx)
+	}
+
+	if cacheable {
+		b.mu.Lock()
+		err, has := b.results[result]
+		b.mu.Unlock()
+		if has {
+			// The result may have been obtained in another scope
+			persistentCache.put(scope, result.objectID, key.KeyID, err)
+			return err
+		}
+	}
+
+	var err error
+	if verified, has := persistentCache.get(scope, result.objectID, key.KeyID); has {
+		if !verified {
+			err = ErrIncorrectVerificationKey
+		}
+	} else {
+		err = b.verifySignatureUncached(ctx, gitObject, key)
+		persistentCache.put(scope, result.objectID, key.KeyID, err)
+	}
+
+	if cacheable && !errors.Is(err, ErrVerifyingSigstoreSignature) {
+		b.mu.Lock()
+		b.results[result] = err
+		b.mu.Unlock()
+	}
+
+	return err
+}
+
+func (b *BatchVerifier) verifySignatureUncached(ctx context.Context, gitObject object.Object, key *tuf.Key) error {
+	var (
+		contents    objectContents
+		gpgContents objectContents
+		signature   []byte
+	)
+	switch o := gitObject.(type) {
+	case *object.Commit:
+		contents = getCommitContents(o)
+		gpgContents = getGoGitCommitContents(o)
+		signature = []byte(o.PGPSignature)
+	case *object.Tag:
+		contents = getTagContents(o)
+		gpgContents = contents
+		signature = []byte(o.PGPSignature)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedSignedObject, gitObject)
+	}
+
+	return b.verifyContents(ctx, gitObject.ID(), key, contents, gpgContents, signature)
+}
+
+// verifyContents verifies the signature of a Git object's contents using the
+// key. GPG signatures are verified against gpgContents and other signatures
+// against contents, which are only encoded if needed. GPG and SSH signatures
+// are verified as the contents are streamed. The object's ID is used to cache
+// the certificates embedded in gitsign signatures, and may be zero.
+func (b *BatchVerifier) verifyContents(ctx context.Context, objectID plumbing.Hash, key *tuf.Key, contents, gpgContents objectContents, signature []byte) error {
+	switch key.KeyType {
+	case signerverifier.GPGKeyType:
+		keyRing, err := b.gpgKeyRing(key)
+		if err != nil {
+			return ErrIncorrectVerificationKey
+		}
+
+		reader, err := gpgContents()
+		if err != nil {
+			return ErrIncorrectVerificationKey
+		}
+		defer reader.Close() //nolint:errcheck
+
+		if _, err := openpgp.CheckArmoredDetachedSignature(keyRing, reader, bytes.NewReader(signature), nil); err != nil {
+			if errors.Is(err, pgperrors.ErrKeyRevoked) {
+				// The key issued the signature, but the key carries a
+				// revocation signature
+				return fmt.Errorf("%w: GPG key '%s' carries a revocation signature", ErrKeyRevoked, key.KeyID)
+			}
+			return ErrIncorrectVerificationKey
+		}
+
+		return nil
+	case signerverifier.RSAKeyType, signerverifier.ECDSAKeyType, signerverifier.ED25519KeyType, signerverifier.SSHSecurityKeyType:
+		publicKey, err := b.sshPublicKey(key)
+		if err != nil {
+			return errors.Join(ErrIncorrectVerificationKey, err)
+		}
+
+		if err := verifySSHSignature(publicKey, contents, signature); err != nil {
+			return errors.Join(ErrIncorrectVerificationKey, err)
+		}
+
+		return nil
+	case signerverifier.FulcioKeyType:
+		material, err := b.loadSigstoreVerificationMaterial(ctx)
+		if err != nil {
+			return errors.Join(ErrIncorrectVerificationKey, err)
+		}
+
+		verifiedCert, err := b.gitsignCertificate(ctx, material, objectID, contents, signature)
+		if err != nil {
+			return errors.Join(ErrIncorrectVerificationKey, err)
+		}
+
+		if err := checkGitsignCertificate(material, key, verifiedCert); err != nil {
+			return errors.Join(ErrIncorrectVerificationKey, err)
+		}
+
+		return nil
+	case signerverifier.SMIMEKeyType:
+		data, err := readContents(contents)
+		if err != nil {
+			return errors.Join(ErrVerifyingSMIMESignature, err)
+		}
+
+		if err := verifySMIMESignature(ctx, key, data, signature); err != nil {
+			return errors.Join(ErrIncorrectVerificationKey, err)
+		}
+
+		return nil
+	}
+
+	return ErrUnknownSigningMethod
+}
+
+// loadSigstoreVerificationMaterial loads the state used to verify gitsign
+// signatures the first time it's needed.
+func (b *BatchVerifier) loadSigstoreVerificationMaterial(ctx context.Context) (*sigstoreVerificationMaterial, error) {
+	b.sigstoreOnce.Do(func() {
+		b.sigstoreMaterial, b.sigstoreErr = loadSigstoreVerificationMaterial(ctx)
+	})
+
+	return b.sigstoreMaterial, b.sigstoreErr
+}
+
+// gitsignCertificate returns the verified certificate embedded in the gitsign
+// signature of the object. The certificate is independent of the key used to
+// verify the signature, so it's verified once for each object. The contents
+// are read in full as gitsign's verifier requires them in memory.
+func (b *BatchVerifier) gitsignCertificate(ctx context.Context, material *sigstoreVerificationMaterial, objectID plumbing.Hash, contents objectContents, signature []byte) (*x509.Certificate, error) {
+	if objectID.IsZero() {
+		return readAndVerifyGitsignCertificate(ctx, material, contents, signature)
+	}
+
+	b.mu.Lock()
+	cached, has := b.certificates[objectID]
+	b.mu.Unlock()
+	if has {
+		return cached.certificate, cached.err
+	}
+
+	certificate, err := readAndVerifyGitsignCertificate(ctx, material, contents, signature)
+
+	b.mu.Lock()
+	b.certificates[objectID] = &verifiedCertificate{certificate: certificate, err: err}
+	b.mu.Unlock()
+
+	return certificate, err
+}
+
+// readAndVerifyGitsignCertificate reads the contents and verifies the gitsign
+// signature and the certificate embedded in it.
+func readAndVerifyGitsignCertificate(ctx context.Context, material *sigstoreVerificationMaterial, contents objectContents, signature []byte) (*x509.Certificate, error) {
+	data, err := readContents(contents)
+	if err != nil {
+		return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+	}
+
+	return verifyGitsignCertificate(ctx, material, data, signature)
+}
+
+// sshPublicKey returns the SSH public key for the key, parsing it once.
+func (b *BatchVerifier) sshPublicKey(key *tuf.Key) (ssh.PublicKey, error) {
+	if key.KeyID == "" {
+		return sshPublicKeyFromTUFKey(key)
+	}
+
+	b.mu.Lock()
+	cached, has := b.sshKeys[key.KeyID]
+	b.mu.Unlock()
+	if has {
+		return cached.publicKey, cached.err
+	}
+
+	publicKey, err := sshPublicKeyFromTUFKey(key)
+
+	b.mu.Lock()
+	b.sshKeys[key.KeyID] = &parsedSSHKey{publicKey: publicKey, err: err}
+	b.mu.Unlock()
+
+	return publicKey, err
+}
+
+// gpgKeyRing returns the GPG key ring for the key, parsing it once.
+func (b *BatchVerifier) gpgKeyRing(key *tuf.Key) (openpgp.EntityList, error) {
+	if key.KeyID == "" {
+		return openpgp.ReadArmoredKeyRing(strings.NewReader(key.KeyVal.Public))
+	}
+
+	b.mu.Lock()
+	cached, has := b.gpgKeyRings[key.KeyID]
+	b.mu.Unlock()
+	if has {
+		return cached.keyRing, cached.err
+	}
+
+	keyRing, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.KeyVal.Public))
+
+	b.mu.Lock()
+	b.gpgKeyRings[key.KeyID] = &parsedGPGKeyRing{keyRing: keyRing, err: err}
+	b.mu.Unlock()
+
+	return keyRing, err
+}
+
+// getGoGitCommitContents returns the contents of the commit without its
+// signature as encoded by go-git, which GPG signatures are verified against
+// like in go-git's Commit.Verify.
+func getGoGitCommitContents(commit *object.Commit) objectContents {
+	return contentsFromGoGitEncoder(commit.EncodeWithoutSignature)
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchVerifier(t *testing.T) {
+	gpgSignedCommit := createTestSignedCommit(t)
+	sshCommits := createTestSSHSignedCommits(t)
+	gpgSignedTag := createTestSignedTag(t)
+
+	// Results are cached using the objects' IDs
+	for _, commit := range append([]*object.Commit{gpgSignedCommit}, sshCommits...) {
+		encoded := memory.NewStorage().NewEncodedObject()
+		if err := commit.Encode(encoded); err != nil {
+			t.Fatal(err)
+		}
+		commit.Hash = encoded.Hash()
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := sslibsv.LoadKey(rsaSSHPublicKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := sslibsv.LoadKey(ecdsaSSHPublicKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("verify checks", func(t *testing.T) {
+		verifier := NewBatchVerifier(2)
+
+		errs := verifier.Verify(context.Background(), []*SignatureCheck{
+			{Object: gpgSignedCommit, Keys: []*sslibsv.SSLibKey{rsaKey, gpgKey}},
+			{Object: sshCommits[0], Keys: []*sslibsv.SSLibKey{ecdsaKey, rsaKey}},
+			{Object: sshCommits[1], Keys: []*sslibsv.SSLibKey{rsaKey, gpgKey}},
+			{Object: gpgSignedTag, Keys: []*sslibsv.SSLibKey{gpgKey}},
+			{Object: sshCommits[1], Keys: []*sslibsv.SSLibKey{}},
+			{Object: &object.Blob{}, Keys: []*sslibsv.SSLibKey{rsaKey}},
+		})
+		assert.Len(t, errs, 6)
+		assert.Nil(t, errs[0])
+		assert.Nil(t, errs[1])
+		assert.ErrorIs(t, errs[2], ErrIncorrectVerificationKey)
+		assert.Nil(t, errs[3])
+		assert.ErrorIs(t, errs[4], ErrIncorrectVerificationKey)
+		assert.ErrorIs(t, errs[5], ErrUnsupportedSignedObject)
+	})
+
+	t.Run("results are cached", func(t *testing.T) {
+		verifier := NewBatchVerifier(0)
+		ctx := ContextWithBatchVerifier(context.Background(), verifier)
+
+		err := VerifyCommitSignature(ctx, sshCommits[0], rsaKey)
+		assert.Nil(t, err)
+		err = VerifyCommitSignature(ctx, sshCommits[0], ecdsaKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+
+		assert.Len(t, verifier.results, 2)
+		assert.Len(t, verifier.sshKeys, 2)
+
+		// The cached result is used for an object with the same ID
+		modifiedCommit := *sshCommits[0]
+		modifiedCommit.Message = "Modified commit"
+		err = VerifyCommitSignature(ctx, &modifiedCommit, rsaKey)
+		assert.Nil(t, err)
+
+		err = VerifyCommitSignature(context.Background(), &modifiedCommit, rsaKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("allowed signers are checked for cached results", func(t *testing.T) {
+		verifier := NewBatchVerifier(0)
+		ctx := ContextWithBatchVerifier(context.Background(), verifier)
+
+		err := VerifyCommitSignature(ctx, sshCommits[1], ecdsaKey)
+		assert.Nil(t, err)
+
+		// The commits are created at testClock's time, in 1995
+		allowedSigners, err := ParseAllowedSigners(strings.NewReader(fmt.Sprintf(
+			"john.doe@example.com valid-before=\"19950101\" %s\n",
+			strings.TrimSpace(string(artifacts.SSHECDSAPublicSSH)),
+		)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = VerifyCommitSignature(ContextWithAllowedSigners(ctx, allowedSigners), sshCommits[1], ecdsaKey)
+		assert.ErrorIs(t, err, ErrSSHKeyNotAllowed)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		errs := NewBatchVerifier(1).Verify(ctx, []*SignatureCheck{
+			{Object: sshCommits[0], Keys: []*sslibsv.SSLibKey{rsaKey}},
+		})
+		assert.ErrorIs(t, errs[0], context.Canceled)
+	})
+}
+
+func TestBatchVerifierFromContext(t *testing.T) {
+	assert.Nil(t, BatchVerifierFromContext(context.Background()))
+
+	verifier := NewBatchVerifier(4)
+	assert.Equal(t, 4, verifier.workers)
+	assert.Equal(t, verifier, BatchVerifierFromContext(ContextWithBatchVerifier(context.Background(), verifier)))
+	assert.Equal(t, verifier, verifierForContext(ContextWithBatchVerifier(context.Background(), verifier)))
+	assert.NotNil(t, verifierForContext(context.Background()))
+}
@@ -3,49 +3,105 @@
 package gitinterface
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
 
+	"github.com/gittuf/gittuf/internal/timing"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
 	"github.com/go-git/go-git/v5/storage/memory"
 )
 
-var ErrWrittenBlobLengthMismatch = errors.New("length of blob written does not match length of contents")
+// largeObjectThreshold is the size in bytes above which objects are streamed
+// from the object store rather than loaded into memory when they're read. It
+// is also the largest blob that ReadBlob loads into memory.
+const largeObjectThreshold = 32 * 1024 * 1024
 
-// ReadBlob returns the contents of a the blob referenced by blobID.
+var (
+	ErrWrittenBlobLengthMismatch = errors.New("length of blob written does not match length of contents")
+	ErrBlobTooLarge              = errors.New("blob is too large to be read into memory")
+)
+
+// enableLargeObjectStreaming configures the repository's storage to stream
+// objects larger than largeObjectThreshold from disk as they're read, rather
+// than loading them into memory, if the repository is stored on disk. This
+// must be called before the storage is wrapped by enableCaching.
+func enableLargeObjectStreaming(repo *git.Repository) {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return
+	}
+
+	repo.Storer = filesystem.NewStorageWithOptions(storage.Filesystem(), cache.NewObjectLRUDefault(), filesystem.Options{
+		LargeObjectThreshold: largeObjectThreshold,
+	})
+}
+
+// ReadBlob returns the contents of a the blob referenced by blobID. As the
+// contents are loaded into memory, this is meant for blobs such as gittuf
+// metadata and attestations. Blobs larger than 32 MiB are rejected, use
+// GetBlobReader to stream their contents instead.
 func ReadBlob(repo *git.Repository, blobID plumbing.Hash) ([]byte, error) {
+	defer timing.Start(timing.PhaseGitObjectReads)()
+
 	blob, err := GetBlob(repo, blobID)
 	if err != nil {
 		return nil, err
 	}
 
+	if blob.Size > largeObjectThreshold {
+		return nil, fmt.Errorf("%w: '%s' is %d bytes", ErrBlobTooLarge, blobID.String(), blob.Size)
+	}
+
 	reader, err := blob.Reader()
 	if err != nil {
 		return nil, err
 	}
+	defer reader.Close() //nolint:errcheck
 
 	return io.ReadAll(reader)
 }
 
+// GetBlobReader returns a reader for the contents of the blob referenced by
+// blobID. For repositories stored on disk, the contents of large blobs are
+// streamed from the object store as they're read, rather than being loaded
+// into memory. The reader must be closed once the contents are read.
+func GetBlobReader(repo *git.Repository, blobID plumbing.Hash) (io.ReadCloser, error) {
+	blob, err := GetBlob(repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return blob.Reader()
+}
+
+// GetBlobReader returns a reader that streams the contents of the blob
+// referenced by blobID. The reader must be closed once the contents are read.
+func (r *Repository) GetBlobReader(blobID Hash) (io.ReadCloser, error) {
+	reader, err := r.getBackend().readBlobStream(blobID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read blob: %w", err)
+	}
+
+	return reader, nil
+}
+
 // ReadBlob returns the contents of a the blob referenced by blobID.
 func (r *Repository) ReadBlob(blobID Hash) ([]byte, error) {
-	objType, err := r.executeGitCommandString("cat-file", "-t", blobID.String())
+	defer timing.Start(timing.PhaseGitObjectReads)()
+
+	objType, contents, err := r.getBackend().readObject(blobID)
 	if err != nil {
-		return nil, fmt.Errorf("unable to inspect if object is blob: %w", err)
+		return nil, fmt.Errorf("unable to read blob: %w", err)
 	} else if objType != "blob" {
 		return nil, fmt.Errorf("requested Git ID '%s' is not a blob object", blobID.String())
 	}
 
-	stdOut, stdErr, err := r.executeGitCommand("cat-file", "-p", blobID.String())
-	if err != nil {
-		return nil, fmt.Errorf("unable to read blob: %s", stdErr)
-	}
-
-	return io.ReadAll(stdOut)
+	return contents, nil
 }
 
 // WriteBlob creates a blob object with the specified contents and returns the
@@ -74,22 +130,18 @@ func WriteBlob(repo *git.Repository, contents []byte) (plumbing.Hash, error) {
 // WriteBlob creates a blob object with the specified contents and returns the
 // ID of the resultant blob.
 func (r *Repository) WriteBlob(contents []byte) (Hash, error) {
-	stdInBuf := bytes.NewBuffer(contents)
-	objID, err := r.executeGitCommandWithStdInString(stdInBuf, "hash-object", "-t", "blob", "-w", "--stdin")
+	hash, err := r.getBackend().writeObject("blob", contents)
 	if err != nil {
 		return ZeroHash, fmt.Errorf("unable to write blob: %w", err)
 	}
 
-	hash, err := NewHash(objID)
-	if err != nil {
-		return ZeroHash, fmt.Errorf("invalid Git ID for blob: %w", err)
-	}
-
 	return hash, nil
 }
 
 // GetBlob returns the requested blob object.
 func GetBlob(repo *git.Repository, blobID plumbing.Hash) (*object.Blob, error) {
+	defer timing.Start(timing.PhaseGitObjectReads)()
+
 	return repo.BlobObject(blobID)
 }
 
@@ -3,6 +3,7 @@
 package gitinterface
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"testing"
@@ -105,6 +106,99 @@ func TestRepositoryReadBlob(t *testing.T) {
 	})
 }
 
+func TestGetBlobReader(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := git.PlainInit(tmpDir, false); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("small blob", func(t *testing.T) {
+		contents := []byte("test file read")
+		blobID, err := WriteBlob(repo, contents)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reader, err := GetBlobReader(repo, blobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close() //nolint:errcheck
+
+		readContents, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		assert.Equal(t, contents, readContents)
+	})
+
+	t.Run("large blob", func(t *testing.T) {
+		contents := bytes.Repeat([]byte("large file contents\n"), largeObjectThreshold/20+1)
+		blobID, err := WriteBlob(repo, contents)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ReadBlob(repo, blobID)
+		assert.ErrorIs(t, err, ErrBlobTooLarge)
+
+		reader, err := GetBlobReader(repo, blobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close() //nolint:errcheck
+
+		readContents, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		assert.True(t, bytes.Equal(contents, readContents))
+	})
+
+	t.Run("nonexistent blob", func(t *testing.T) {
+		_, err := GetBlobReader(repo, plumbing.ZeroHash)
+		assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+	})
+}
+
+func TestRepositoryGetBlobReader(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := CreateTestGitRepository(t, tempDir)
+
+	contents := []byte("test file read")
+	blobID, err := repo.WriteBlob(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("read existing blob", func(t *testing.T) {
+		reader, err := repo.GetBlobReader(blobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		readContents, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		assert.Equal(t, contents, readContents)
+		assert.Nil(t, reader.Close())
+	})
+
+	t.Run("close before reading", func(t *testing.T) {
+		reader, err := repo.GetBlobReader(blobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, reader.Close())
+	})
+
+	t.Run("read non-existing blob", func(t *testing.T) {
+		_, err := repo.GetBlobReader(ZeroHash)
+		assert.ErrorIs(t, err, ErrObjectNotFound)
+	})
+}
+
 func TestWriteBlob(t *testing.T) {
 	writeContents := []byte("test file write")
 
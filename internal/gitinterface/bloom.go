@@ -0,0 +1,381 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	encbinary "encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	commitGraphSignature      = "CGPH"
+	commitGraphHeaderSize     = 8
+	commitGraphChunkEntrySize = 12
+	commitGraphSHA1Version    = 1
+	commitGraphSHA256Version  = 2
+
+	oidFanoutChunkID        = "OIDF"
+	oidLookupChunkID        = "OIDL"
+	bloomFilterIndexChunkID = "BIDX"
+	bloomFilterDataChunkID  = "BDAT"
+
+	bloomFilterDataHeaderSize = 12
+	bloomSeed0                = 0x293ae76f
+	bloomSeed1                = 0x7e646e2c
+)
+
+var ErrInvalidCommitGraph = errors.New("invalid commit-graph file")
+
+// changedPathFilters provides access to the changed-path Bloom filters Git
+// stores in the commit-graph when it's written with `--changed-paths`. Each
+// commit's filter records the paths changed relative to its first parent, as
+// well as their leading directories. A filter can show that a path was
+// definitely not changed by a commit, which avoids diffing the commit's tree.
+type changedPathFilters struct {
+	layers []*changedPathFilterLayer
+}
+
+// changedPathFilterLayer is a single commit-graph file, which may be one of
+// several layers in a commit-graph chain.
+type changedPathFilterLayer struct {
+	file         *os.File
+	objectIDSize int
+
+	fanout           [256]uint32
+	oidLookupOffset  int64
+	bloomIndexOffset int64
+	bloomDataOffset  int64
+
+	hashVersion uint32
+	numHashes   uint32
+}
+
+// openChangedPathFilters opens the changed-path Bloom filters in the
+// commit-graph of the repository at commonDir. Nil is returned if the
+// repository doesn't have a commit-graph with changed-path Bloom filters.
+func openChangedPathFilters(commonDir string) (*changedPathFilters, error) {
+	infoDir := filepath.Join(commonDir, "objects", "info")
+
+	paths := []string{}
+	if _, err := os.Stat(filepath.Join(infoDir, "commit-graph")); err == nil {
+		paths = append(paths, filepath.Join(infoDir, "commit-graph"))
+	} else {
+		chainPath := filepath.Join(infoDir, "commit-graphs", "commit-graph-chain")
+		chainFile, err := os.Open(chainPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		defer chainFile.Close() //nolint:errcheck
+
+		scanner := bufio.NewScanner(chainFile)
+		for scanner.Scan() {
+			graphHash := strings.TrimSpace(scanner.Text())
+			if graphHash == "" {
+				continue
+			}
+			paths = append(paths, filepath.Join(infoDir, "commit-graphs", fmt.Sprintf("graph-%s.graph", graphHash)))
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	filters := &changedPathFilters{}
+	for _, path := range paths {
+		layer, err := openChangedPathFilterLayer(path)
+		if err != nil {
+			filters.close() //nolint:errcheck
+			return nil, err
+		}
+		if layer != nil {
+			filters.layers = append(filters.layers, layer)
+		}
+	}
+
+	if len(filters.layers) == 0 {
+		return nil, nil
+	}
+
+	return filters, nil
+}
+
+// openChangedPathFilterLayer opens the commit-graph file at path. Nil is
+// returned if it doesn't have changed-path Bloom filters that can be used.
+func openChangedPathFilterLayer(path string) (*changedPathFilterLayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	layer, err := readChangedPathFilterLayer(file)
+	if layer == nil {
+		file.Close() //nolint:errcheck
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w '%s': %w", ErrInvalidCommitGraph, path, err)
+	}
+
+	return layer, nil
+}
+
+func readChangedPathFilterLayer(file *os.File) (*changedPathFilterLayer, error) {
+	header := make([]byte, commitGraphHeaderSize)
+	if _, err := file.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != commitGraphSignature {
+		return nil, fmt.Errorf("unexpected signature")
+	}
+
+	// The commit-graph records the object format of the repository
+	var objectIDSize int
+	switch header[5] {
+	case commitGraphSHA1Version:
+		objectIDSize = sha1.Size
+	case commitGraphSHA256Version:
+		objectIDSize = sha256.Size
+	default:
+		return nil, nil
+	}
+
+	numChunks := int(header[6])
+	chunkTable := make([]byte, (numChunks+1)*commitGraphChunkEntrySize)
+	if _, err := file.ReadAt(chunkTable, commitGraphHeaderSize); err != nil {
+		return nil, err
+	}
+
+	offsets := map[string]int64{}
+	for index := 0; index < numChunks; index++ {
+		entry := chunkTable[index*commitGraphChunkEntrySize : (index+1)*commitGraphChunkEntrySize]
+		offsets[string(entry[:4])] = int64(encbinary.BigEndian.Uint64(entry[4:])) //nolint:gosec
+	}
+
+	for _, chunkID := range []string{oidFanoutChunkID, oidLookupChunkID} {
+		if _, has := offsets[chunkID]; !has {
+			return nil, fmt.Errorf("missing chunk '%s'", chunkID)
+		}
+	}
+	for _, chunkID := range []string{bloomFilterIndexChunkID, bloomFilterDataChunkID} {
+		if _, has := offsets[chunkID]; !has {
+			// Written without changed-path Bloom filters
+			return nil, nil
+		}
+	}
+
+	layer := &changedPathFilterLayer{
+		file:             file,
+		objectIDSize:     objectIDSize,
+		oidLookupOffset:  offsets[oidLookupChunkID],
+		bloomIndexOffset: offsets[bloomFilterIndexChunkID],
+		bloomDataOffset:  offsets[bloomFilterDataChunkID] + bloomFilterDataHeaderSize,
+	}
+
+	fanout := make([]byte, 256*4)
+	if _, err := file.ReadAt(fanout, offsets[oidFanoutChunkID]); err != nil {
+		return nil, err
+	}
+	for index := range layer.fanout {
+		layer.fanout[index] = encbinary.BigEndian.Uint32(fanout[index*4:])
+	}
+
+	settings := make([]byte, bloomFilterDataHeaderSize)
+	if _, err := file.ReadAt(settings, offsets[bloomFilterDataChunkID]); err != nil {
+		return nil, err
+	}
+	layer.hashVersion = encbinary.BigEndian.Uint32(settings)
+	layer.numHashes = encbinary.BigEndian.Uint32(settings[4:])
+	if layer.hashVersion != 1 && layer.hashVersion != 2 {
+		return nil, nil
+	}
+
+	return layer, nil
+}
+
+// mayHaveChanged returns false if the commit's changed-path Bloom filter shows
+// that none of the paths were changed relative to the commit's first parent.
+// True is returned if any of the paths may have been changed or if the commit
+// doesn't have a filter.
+func (f *changedPathFilters) mayHaveChanged(commitID plumbing.Hash, paths []string) bool {
+	for _, layer := range f.layers {
+		filter, found, err := layer.getFilter(commitID[:])
+		if err != nil {
+			return true
+		}
+		if !found {
+			continue
+		}
+
+		for _, path := range paths {
+			if layer.filterContains(filter, path) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func (f *changedPathFilters) close() error {
+	var err error
+	for _, layer := range f.layers {
+		err = errors.Join(err, layer.file.Close())
+	}
+	return err
+}
+
+// getFilter returns the changed-path Bloom filter for the commit, if the
+// commit is in the layer.
+func (l *changedPathFilterLayer) getFilter(commitID []byte) ([]byte, bool, error) {
+	if len(commitID) != l.objectIDSize {
+		// The commit-graph was written for a different object format
+		return nil, false, nil
+	}
+
+	low := uint32(0)
+	if commitID[0] > 0 {
+		low = l.fanout[commitID[0]-1]
+	}
+	high := l.fanout[commitID[0]]
+
+	objectID := make([]byte, len(commitID))
+	for low < high {
+		mid := low + (high-low)/2
+		if _, err := l.file.ReadAt(objectID, l.oidLookupOffset+int64(mid)*int64(len(commitID))); err != nil {
+			return nil, false, err
+		}
+
+		switch bytes.Compare(objectID, commitID) {
+		case 0:
+			filter, err := l.readFilter(mid)
+			return filter, true, err
+		case -1:
+			low = mid + 1
+		default:
+			high = mid
+		}
+	}
+
+	return nil, false, nil
+}
+
+// readFilter reads the filter of the commit at the position in the layer. The
+// index chunk records where each commit's filter ends in the data chunk.
+func (l *changedPathFilterLayer) readFilter(position uint32) ([]byte, error) {
+	start := uint32(0)
+	if position > 0 {
+		startBytes := make([]byte, 4)
+		if _, err := l.file.ReadAt(startBytes, l.bloomIndexOffset+int64(position-1)*4); err != nil {
+			return nil, err
+		}
+		start = encbinary.BigEndian.Uint32(startBytes)
+	}
+
+	endBytes := make([]byte, 4)
+	if _, err := l.file.ReadAt(endBytes, l.bloomIndexOffset+int64(position)*4); err != nil {
+		return nil, err
+	}
+	end := encbinary.BigEndian.Uint32(endBytes)
+	if end < start {
+		return nil, fmt.Errorf("%w: invalid Bloom filter index", ErrInvalidCommitGraph)
+	}
+
+	filter := make([]byte, end-start)
+	if _, err := l.file.ReadAt(filter, l.bloomDataOffset+int64(start)); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// filterContains returns true if the path may be in the filter. Like Git, an
+// empty filter is treated as one that may contain any path.
+func (l *changedPathFilterLayer) filterContains(filter []byte, path string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	if l.hashVersion == 1 {
+		// Version 1 filters were computed with a bug that sign-extends bytes
+		// with the high bit set, so they can only be used for other paths
+		for index := 0; index < len(path); index++ {
+			if path[index] >= 0x80 {
+				return true
+			}
+		}
+	}
+
+	hash0 := murmur3(bloomSeed0, []byte(path))
+	hash1 := murmur3(bloomSeed1, []byte(path))
+
+	numBits := uint64(len(filter)) * 8
+	for index := uint32(0); index < l.numHashes; index++ {
+		bit := uint64(hash0+index*hash1) % numBits
+		if filter[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// murmur3 is the 32-bit MurmurHash3 function used by Git to compute the keys
+// of changed-path Bloom filters.
+func murmur3(seed uint32, data []byte) uint32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+
+	hash := seed
+	blocks := len(data) / 4
+	for index := 0; index < blocks; index++ {
+		k := encbinary.LittleEndian.Uint32(data[index*4:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+
+		hash ^= k
+		hash = bits.RotateLeft32(hash, 13)*5 + 0xe6546b64
+	}
+
+	tail := data[blocks*4:]
+	k := uint32(0)
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		hash ^= k
+	}
+
+	hash ^= uint32(len(data)) //nolint:gosec
+	hash ^= hash >> 16
+	hash *= 0x85ebca6b
+	hash ^= hash >> 13
+	hash *= 0xc2b2ae35
+	hash ^= hash >> 16
+
+	return hash
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMurmur3(t *testing.T) {
+	assert.Equal(t, uint32(0x00000000), murmur3(0, []byte("")))
+	assert.Equal(t, uint32(0x248bfa47), murmur3(0, []byte("hello")))
+	assert.Equal(t, uint32(0x2e4ff723), murmur3(0, []byte("The quick brown fox jumps over the lazy dog")))
+}
+
+func TestChangedPathFilters(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	runGit := func(args ...string) string {
+		t.Helper()
+
+		args = append([]string{"-C", tmpDir, "-c", "user.name=" + testName, "-c", "user.email=" + testEmail, "-c", "commit.gpgsign=false"}, args...)
+		output, err := exec.Command(binary, args...).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	commit := func(message string, files map[string]string) plumbing.Hash {
+		t.Helper()
+
+		for name, contents := range files {
+			path := filepath.Join(tmpDir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(contents), 0o644); err != nil { //nolint:gosec
+				t.Fatal(err)
+			}
+		}
+
+		runGit("add", ".")
+		runGit("commit", "--quiet", "-m", message)
+		return plumbing.NewHash(runGit("rev-parse", "HEAD"))
+	}
+
+	runGit("init", "--quiet", "-b", "main")
+	commitIDs := []plumbing.Hash{
+		commit("Add files", map[string]string{"src/a.go": "a", "docs/README.md": "readme"}),
+		commit("Update docs", map[string]string{"docs/README.md": "updated readme"}),
+		commit("Update source", map[string]string{"src/a.go": "updated a"}),
+		commit("Add nested source", map[string]string{"src/b/c.go": "c"}),
+	}
+
+	repo, err := OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commits := make([]*object.Commit, 0, len(commitIDs))
+	for _, commitID := range commitIDs {
+		commit, err := GetCommit(repo, commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, commit)
+	}
+
+	commonDir, err := GetGitCommonDirFor(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no commit-graph", func(t *testing.T) {
+		filters, err := openChangedPathFilters(commonDir)
+		assert.Nil(t, err)
+		assert.Nil(t, filters)
+
+		changes, err := GetFilePathsChangedByCommits(repo, commits, []string{"src"})
+		assert.Nil(t, err)
+		assert.Equal(t, [][]string{{"src/a.go"}, {}, {"src/a.go"}, {"src/b/c.go"}}, changes)
+	})
+
+	runGit("commit-graph", "write", "--reachable", "--changed-paths", "--no-progress")
+
+	t.Run("check filters", func(t *testing.T) {
+		filters, err := openChangedPathFilters(commonDir)
+		if !assert.Nil(t, err) || !assert.NotNil(t, filters) {
+			return
+		}
+		defer filters.close() //nolint:errcheck
+
+		assert.True(t, filters.mayHaveChanged(commitIDs[0], []string{"src"}))
+		assert.True(t, filters.mayHaveChanged(commitIDs[1], []string{"docs/README.md"}))
+		assert.False(t, filters.mayHaveChanged(commitIDs[1], []string{"src"}))
+		assert.True(t, filters.mayHaveChanged(commitIDs[2], []string{"src"}))
+		assert.False(t, filters.mayHaveChanged(commitIDs[2], []string{"docs", "src/b"}))
+		assert.True(t, filters.mayHaveChanged(commitIDs[3], []string{"docs", "src/b"}))
+
+		// Commits that aren't in the commit-graph may have changed any path
+		assert.True(t, filters.mayHaveChanged(plumbing.ZeroHash, []string{"src"}))
+	})
+
+	t.Run("changes with filters", func(t *testing.T) {
+		changes, err := GetFilePathsChangedByCommits(repo, commits, []string{"src"})
+		assert.Nil(t, err)
+		assert.Equal(t, [][]string{{"src/a.go"}, nil, {"src/a.go"}, {"src/b/c.go"}}, changes)
+
+		changes, err = GetFilePathsChangedByCommits(repo, commits, []string{"docs/README.md"})
+		assert.Nil(t, err)
+		assert.Equal(t, [][]string{{"docs/README.md"}, {"docs/README.md"}, nil, nil}, changes)
+	})
+
+	t.Run("all changes", func(t *testing.T) {
+		changes, err := GetFilePathsChangedByCommits(repo, commits, nil)
+		assert.Nil(t, err)
+
+		for index, commit := range commits {
+			expectedChanges, err := GetFilePathsChangedByCommit(repo, commit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expectedChanges, changes[index])
+		}
+	})
+}
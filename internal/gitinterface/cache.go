@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+const (
+	commitCacheSize    = 4096
+	treeCacheSize      = 1024
+	referenceCacheSize = 1024
+)
+
+// enableCaching configures the repository to cache parsed commits, parsed
+// trees, references, and commit history walks, if it's stored on disk.
+func enableCaching(repo *git.Repository) {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return
+	}
+
+	repo.Storer = &cachedStorage{
+		Storage:      storage,
+		refs:         storage,
+		commits:      newLRUCache[plumbing.Hash, *object.Commit](commitCacheSize),
+		trees:        newLRUCache[plumbing.Hash, *object.Tree](treeCacheSize),
+		references:   newLRUCache[plumbing.ReferenceName, *plumbing.Reference](referenceCacheSize),
+		reachability: newLRUCache[plumbing.Hash, *reachabilityWalk](reachabilityCacheSize),
+	}
+}
+
+// getCachedStorage returns the repository's cached storage, or nil if caching
+// isn't enabled for the repository.
+func getCachedStorage(repo *git.Repository) *cachedStorage {
+	switch storer := repo.Storer.(type) {
+	case *cachedStorage:
+		return storer
+	case *promisorStorage:
+		return storer.cachedStorage
+	default:
+		return nil
+	}
+}
+
+// cachedStorage wraps the storage of a repository on disk, caching parsed
+// commits and trees, the references read from the repository, and the walks
+// of commit histories used to check ancestry. The caches live as long as the
+// repository, which is typically a single gittuf command invocation, so that
+// objects and references read repeatedly, such as the same policy state for
+// many RSL entries, are read and parsed once.
+//
+// Commits and trees are immutable, so they're never invalidated. References
+// are invalidated when they're updated using the storage. Note that changes
+// made to existing references by other processes, such as the Git binary,
+// while the repository is open are not observed. References created by other
+// processes are observed, as missing references aren't cached.
+type cachedStorage struct {
+	*filesystem.Storage
+
+	// refs stores the repository's references, which is the filesystem
+	// storage unless the references are stored in the reftable format, see
+	// enableReftableReferences
+	refs storer.ReferenceStorer
+
+	commits *lruCache[plumbing.Hash, *object.Commit]
+	trees   *lruCache[plumbing.Hash, *object.Tree]
+
+	// references maps reference names to the reference
+	references *lruCache[plumbing.ReferenceName, *plumbing.Reference]
+	refMu      sync.Mutex
+
+	// reachability maps commit IDs to the walk of their history, which is
+	// used to check if other commits are their ancestors
+	reachability   *lruCache[plumbing.Hash, *reachabilityWalk]
+	reachabilityMu sync.Mutex
+}
+
+// Reference returns the requested reference, reading it from the repository
+// if it isn't cached.
+func (s *cachedStorage) Reference(refName plumbing.ReferenceName) (*plumbing.Reference, error) {
+	s.refMu.Lock()
+	defer s.refMu.Unlock()
+
+	if ref, has := s.references.get(refName); has {
+		return ref, nil
+	}
+
+	// Missing references aren't cached, as they may be created by other
+	// processes, such as when the RSL is fetched while gittuf is running
+	ref, err := s.refs.Reference(refName)
+	if err == nil {
+		s.references.add(refName, ref)
+	}
+
+	return ref, err
+}
+
+// SetReference updates the reference and invalidates its cached value.
+func (s *cachedStorage) SetReference(ref *plumbing.Reference) error {
+	s.refMu.Lock()
+	defer s.refMu.Unlock()
+
+	s.references.remove(ref.Name())
+	return s.refs.SetReference(ref)
+}
+
+// CheckAndSetReference updates the reference if its current value matches old
+// and invalidates its cached value.
+func (s *cachedStorage) CheckAndSetReference(ref, old *plumbing.Reference) error {
+	s.refMu.Lock()
+	defer s.refMu.Unlock()
+
+	s.references.remove(ref.Name())
+	return s.refs.CheckAndSetReference(ref, old)
+}
+
+// RemoveReference removes the reference and invalidates its cached value.
+func (s *cachedStorage) RemoveReference(refName plumbing.ReferenceName) error {
+	s.refMu.Lock()
+	defer s.refMu.Unlock()
+
+	s.references.remove(refName)
+	return s.refs.RemoveReference(refName)
+}
+
+// IterReferences returns an iterator over the repository's references. The
+// references are not cached.
+func (s *cachedStorage) IterReferences() (storer.ReferenceIter, error) {
+	return s.refs.IterReferences()
+}
+
+// CountLooseRefs returns the number of references stored as loose files.
+func (s *cachedStorage) CountLooseRefs() (int, error) {
+	return s.refs.CountLooseRefs()
+}
+
+// PackRefs packs the references stored as loose files.
+func (s *cachedStorage) PackRefs() error {
+	return s.refs.PackRefs()
+}
+
+// lruCache is a fixed size cache that evicts the least recently used entry
+// when it's full. It is safe for concurrent use.
+type lruCache[K comparable, V any] struct {
+	size    int
+	entries map[K]*list.Element
+	order   *list.List
+	mu      sync.Mutex
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func newLRUCache[K comparable, V any](size int) *lruCache[K, V] {
+	return &lruCache[K, V]{
+		size:    size,
+		entries: map[K]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// get returns the cached value for the key and marks it as the most recently
+// used entry.
+func (c *lruCache[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, has := c.entries[key]
+	if !has {
+		var zero V
+		return zero, false
+	}
+
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry[K, V]).value, true
+}
+
+// add caches the value for the key, evicting the least recently used entry if
+// the cache is full.
+func (c *lruCache[K, V]) add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, has := c.entries[key]; has {
+		element.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+// remove drops the key from the cache.
+func (c *lruCache[K, V]) remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, has := c.entries[key]; has {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
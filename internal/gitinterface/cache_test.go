@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedStorage(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := git.PlainInit(tmpDir, false); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storage := getCachedStorage(repo)
+	if !assert.NotNil(t, storage) {
+		return
+	}
+
+	treeID, err := WriteTree(repo, []object.TreeEntry{{Name: "README.md", Mode: 0o100644, Hash: EmptyBlob()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, treeID, nil, "Initial commit", testClock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("commits", func(t *testing.T) {
+		commit, err := GetCommit(repo, commitID)
+		assert.Nil(t, err)
+		assert.Equal(t, "Initial commit", commit.Message)
+
+		_, has := storage.commits.get(commitID)
+		assert.True(t, has)
+
+		// Modifying the returned commit doesn't modify the cached commit
+		commit.Message = "Modified commit"
+
+		commit, err = GetCommit(repo, commitID)
+		assert.Nil(t, err)
+		assert.Equal(t, "Initial commit", commit.Message)
+		assert.Equal(t, treeID, commit.TreeHash)
+	})
+
+	t.Run("trees", func(t *testing.T) {
+		tree, err := GetTree(repo, treeID)
+		assert.Nil(t, err)
+		assert.Len(t, tree.Entries, 1)
+
+		_, has := storage.trees.get(treeID)
+		assert.True(t, has)
+
+		tree, err = GetTree(repo, treeID)
+		assert.Nil(t, err)
+		assert.Equal(t, "README.md", tree.Entries[0].Name)
+	})
+
+	t.Run("references", func(t *testing.T) {
+		refName := "refs/heads/main"
+
+		_, err := GetTip(repo, refName)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+		// Updating the reference invalidates the cached value
+		err = repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), commitID))
+		assert.Nil(t, err)
+
+		tip, err := GetTip(repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, commitID, tip)
+
+		_, has := storage.references.get(plumbing.ReferenceName(refName))
+		assert.True(t, has)
+
+		err = repo.Storer.RemoveReference(plumbing.ReferenceName(refName))
+		assert.Nil(t, err)
+
+		_, err = GetTip(repo, refName)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := newLRUCache[string, int](2)
+
+	cache.add("a", 1)
+	cache.add("b", 2)
+
+	// Reading "a" makes "b" the least recently used entry
+	value, has := cache.get("a")
+	assert.True(t, has)
+	assert.Equal(t, 1, value)
+
+	cache.add("c", 3)
+
+	_, has = cache.get("b")
+	assert.False(t, has)
+
+	value, has = cache.get("a")
+	assert.True(t, has)
+	assert.Equal(t, 1, value)
+
+	value, has = cache.get("c")
+	assert.True(t, has)
+	assert.Equal(t, 3, value)
+
+	cache.remove("a")
+	_, has = cache.get("a")
+	assert.False(t, has)
+}
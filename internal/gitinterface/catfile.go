@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !libgit2
+
+package gitinterface
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	catFileBatch      = "--batch"
+	catFileBatchCheck = "--batch-check"
+
+	catFileMissing   = "missing"
+	catFileAmbiguous = "ambiguous"
+)
+
+// catFileProcess is a long-lived `git cat-file --batch` or `git cat-file
+// --batch-check` process. Objects are requested by writing their IDs to the
+// process's stdin, which avoids spawning a Git process per object read.
+type catFileProcess struct {
+	cmd    *exec.Cmd
+	stdIn  io.WriteCloser
+	stdOut *bufio.Reader
+	mode   string
+}
+
+// newCatFileProcess starts a `git cat-file` process in the specified mode for
+// the repository at gitDirPath.
+func newCatFileProcess(gitDirPath, mode string) (*catFileProcess, error) {
+	cmd := newGitCommand("--git-dir", gitDirPath, "cat-file", mode)
+
+	stdIn, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdOut, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("unable to start `git cat-file %s`: %w", mode, err)
+	}
+
+	return &catFileProcess{
+		cmd:    cmd,
+		stdIn:  stdIn,
+		stdOut: bufio.NewReader(stdOut),
+		mode:   mode,
+	}, nil
+}
+
+// request returns the type and size of the object. If the process was started
+// in batch mode, the object's contents are also returned.
+func (p *catFileProcess) request(objectID Hash) (string, int, []byte, error) {
+	if _, err := fmt.Fprintln(p.stdIn, objectID.String()); err != nil {
+		return "", 0, nil, fmt.Errorf("unable to request object '%s': %w", objectID.String(), err)
+	}
+
+	// The header is "<id> <type> <size>" or "<id> missing"
+	header, err := p.stdOut.ReadString('\n')
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("unable to read object '%s': %w", objectID.String(), err)
+	}
+
+	// The ID is empty when the empty hash is requested
+	fields := strings.Split(strings.TrimSuffix(header, "\n"), " ")
+	switch {
+	case len(fields) == 2 && (fields[1] == catFileMissing || fields[1] == catFileAmbiguous):
+		return "", 0, nil, fmt.Errorf("%w: '%s'", ErrObjectNotFound, objectID.String())
+	case len(fields) != 3:
+		return "", 0, nil, fmt.Errorf("unexpected response from `git cat-file %s`: '%s'", p.mode, strings.TrimSpace(header))
+	}
+
+	objectType := fields[1]
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("unexpected response from `git cat-file %s`: '%s'", p.mode, strings.TrimSpace(header))
+	}
+
+	if p.mode != catFileBatch {
+		return objectType, size, nil, nil
+	}
+
+	// The contents are followed by a newline
+	contents := make([]byte, size+1)
+	if _, err := io.ReadFull(p.stdOut, contents); err != nil {
+		return "", 0, nil, fmt.Errorf("unable to read object '%s': %w", objectID.String(), err)
+	}
+
+	return objectType, size, contents[:size], nil
+}
+
+// close stops the process by closing its stdin.
+func (p *catFileProcess) close() error {
+	if err := p.stdIn.Close(); err != nil {
+		return err
+	}
+
+	return p.cmd.Wait()
+}
+
+// catFileBlobReader streams the contents of a blob from a `git cat-file blob`
+// process started for the blob, so that the contents aren't buffered in
+// memory.
+type catFileBlobReader struct {
+	cmd    *exec.Cmd
+	stdOut io.ReadCloser
+	eof    bool
+}
+
+// newCatFileBlobReader starts a `git cat-file blob` process for the blob in
+// the repository at gitDirPath.
+func newCatFileBlobReader(gitDirPath string, blobID Hash) (*catFileBlobReader, error) {
+	cmd := newGitCommand("--git-dir", gitDirPath, "cat-file", "blob", blobID.String())
+
+	stdOut, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("unable to start `git cat-file blob`: %w", err)
+	}
+
+	return &catFileBlobReader{cmd: cmd, stdOut: stdOut}, nil
+}
+
+func (r *catFileBlobReader) Read(p []byte) (int, error) {
+	n, err := r.stdOut.Read(p)
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+// Close stops the process. It may be called before all the contents are read,
+// in which case the process exiting early is not treated as an error.
+func (r *catFileBlobReader) Close() error {
+	r.stdOut.Close() //nolint:errcheck
+
+	err := r.cmd.Wait()
+	if !r.eof {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read blob using `git cat-file blob`: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !libgit2
+
+package gitinterface
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryCatFile(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := CreateTestGitRepository(t, tempDir)
+	defer repo.Close() //nolint:errcheck
+
+	firstBlobID, err := repo.WriteBlob([]byte("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := repo.ReadBlob(firstBlobID)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("first"), contents)
+
+	// Objects written after the process is started are also found
+	secondBlobID, err := repo.WriteBlob([]byte("second\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err = repo.ReadBlob(secondBlobID)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("second\n"), contents)
+
+	backend := repo.getBackend().(*gitBackend)
+
+	// The same process is used for subsequent requests
+	assert.Len(t, backend.catFileProcesses, 1)
+
+	_, err = repo.ReadBlob(ZeroHash)
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+
+	assert.True(t, repo.HasObject(firstBlobID))
+	assert.False(t, repo.HasObject(ZeroHash))
+	assert.Len(t, backend.catFileProcesses, 2)
+
+	err = repo.Close()
+	assert.Nil(t, err)
+	assert.Empty(t, backend.catFileProcesses)
+
+	// The repository can be used after it is closed
+	contents, err = repo.ReadBlob(firstBlobID)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("first"), contents)
+}
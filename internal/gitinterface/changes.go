@@ -4,10 +4,16 @@ package gitinterface
 
 import (
 	"container/heap"
+	"errors"
 	"fmt"
+	"io"
+	"runtime"
 	"sort"
+	"strings"
+	"sync"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
@@ -15,17 +21,31 @@ import (
 // This strictly enumerates all the files recursively in the commit object's
 // tree.
 func GetCommitFilePaths(commit *object.Commit) ([]string, error) {
-	filesIter, err := commit.Files()
+	tree, err := commit.Tree()
 	if err != nil {
 		return nil, err
 	}
 
+	// Walk the tree rather than iterating over the commit's files, as the
+	// latter reads every blob, which must be fetched in a partial clone
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+
 	paths := []string{}
-	if err := filesIter.ForEach(func(f *object.File) error {
-		paths = append(paths, f.Name)
-		return nil
-	}); err != nil {
-		return nil, err
+	for {
+		name, entry, err := walker.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		if entry.Mode == filemode.Dir || entry.Mode == filemode.Submodule {
+			continue
+		}
+
+		paths = append(paths, name)
 	}
 
 	sort.Slice(paths, func(i, j int) bool {
@@ -104,6 +124,136 @@ func GetFilePathsChangedByCommit(repo *git.Repository, commit *object.Commit) ([
 	return GetDiffFilePaths(commit, parentCommit)
 }
 
+// GetFilePathsChangedByCommits returns the paths changed by each of the
+// commits, as determined by GetFilePathsChangedByCommit. The changes are
+// computed concurrently for repositories stored on disk, with each worker
+// reading objects using its own instance of the repository.
+//
+// If pathPrefixes are specified, only the changed paths that are one of the
+// prefixes or are within a directory that is one of the prefixes are returned.
+// In this case, the changed-path Bloom filters in the repository's
+// commit-graph are used when available to skip diffing commits that don't
+// change any of the prefixes.
+func GetFilePathsChangedByCommits(repo *git.Repository, commits []*object.Commit, pathPrefixes []string) ([][]string, error) {
+	changes := make([][]string, len(commits))
+
+	commonDir, err := GetGitCommonDirFor(repo)
+	if err != nil {
+		commonDir = ""
+	}
+
+	pending := make([]int, 0, len(commits))
+	if len(pathPrefixes) != 0 && commonDir != "" {
+		filters, err := openChangedPathFilters(commonDir)
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Unable to use changed-path Bloom filters: %s", err.Error()))
+		}
+
+		for index, commit := range commits {
+			// The filters record changes relative to the first parent, which
+			// isn't sufficient for merge commits
+			if filters != nil && len(commit.ParentHashes) <= 1 && !filters.mayHaveChanged(commit.Hash, pathPrefixes) {
+				continue
+			}
+			pending = append(pending, index)
+		}
+
+		if filters != nil {
+			logger.Debug(fmt.Sprintf("Skipped diffing %d of %d commits using changed-path Bloom filters", len(commits)-len(pending), len(commits)))
+			filters.close() //nolint:errcheck
+		}
+	} else {
+		for index := range commits {
+			pending = append(pending, index)
+		}
+	}
+
+	workers := min(runtime.GOMAXPROCS(0), len(pending))
+	if commonDir == "" || workers <= 1 {
+		for _, index := range pending {
+			paths, err := GetFilePathsChangedByCommit(repo, commits[index])
+			if err != nil {
+				return nil, err
+			}
+			changes[index] = filterPathsByPrefixes(paths, pathPrefixes)
+		}
+
+		return changes, nil
+	}
+
+	workerRepos := make([]*git.Repository, 0, workers)
+	for len(workerRepos) < workers {
+		workerRepo, err := openRepository(commonDir, &git.PlainOpenOptions{})
+		if err != nil {
+			return nil, err
+		}
+		workerRepos = append(workerRepos, workerRepo)
+	}
+
+	indexes := make(chan int)
+	errs := make([]error, len(commits))
+
+	var wg sync.WaitGroup
+	for _, workerRepo := range workerRepos {
+		wg.Add(1)
+		go func(workerRepo *git.Repository) {
+			defer wg.Done()
+
+			for index := range indexes {
+				// The commit is read again so that its tree is read using the
+				// worker's repository
+				commit, err := GetCommit(workerRepo, commits[index].Hash)
+				if err != nil {
+					errs[index] = err
+					continue
+				}
+
+				paths, err := GetFilePathsChangedByCommit(workerRepo, commit)
+				if err != nil {
+					errs[index] = err
+					continue
+				}
+				changes[index] = filterPathsByPrefixes(paths, pathPrefixes)
+			}
+		}(workerRepo)
+	}
+
+	for _, index := range pending {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// filterPathsByPrefixes returns the paths that are one of the prefixes or are
+// within a directory that is one of the prefixes. All paths are returned if no
+// prefixes are specified.
+func filterPathsByPrefixes(paths, pathPrefixes []string) []string {
+	if len(pathPrefixes) == 0 {
+		return paths
+	}
+
+	filteredPaths := []string{}
+	for _, path := range paths {
+		for _, prefix := range pathPrefixes {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				filteredPaths = append(filteredPaths, path)
+				break
+			}
+		}
+	}
+
+	return filteredPaths
+}
+
 // GetDiffFilePaths enumerates all the changed file paths between the two
 // commits. If one of the commits is nil, the other commit's tree is enumerated.
 func GetDiffFilePaths(commitA, commitB *object.Commit) ([]string, error) {
@@ -3,22 +3,31 @@
 package gitinterface
 
 import (
+	"bufio"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/timing"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/jonboulle/clockwork"
 )
 
+// utf8CommitEncoding is the default encoding of commit messages, which go-git
+// also uses for commits without an encoding header.
+const utf8CommitEncoding object.MessageEncoding = "UTF-8"
+
 // Commit creates a new commit in the repo and sets targetRef's HEAD to the
-// commit.
+// commit. Like Git, the commit is signed if sign is true or if commit.gpgSign
+// is enabled in the user's Git config, using the key and format configured for
+// signing.
 func Commit(repo *git.Repository, treeHash plumbing.Hash, targetRef string, message string, sign bool) (plumbing.Hash, error) {
 	gitConfig, err := getGitConfig(repo)
 	if err != nil {
@@ -45,6 +54,10 @@ func Commit(repo *git.Repository, treeHash plumbing.Hash, targetRef string, mess
 
 	commit := CreateCommitObject(gitConfig, treeHash, []plumbing.Hash{curRef.Hash()}, message, clock)
 
+	sign, err = shouldSign(sign, "commit.gpgsign")
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
 	if sign {
 		signature, err := signCommit(commit)
 		if err != nil {
@@ -88,11 +101,7 @@ func CommitUsingSpecificKey(repo *git.Repository, treeHash plumbing.Hash, target
 
 	commit := CreateCommitObject(gitConfig, treeHash, []plumbing.Hash{curRef.Hash()}, message, clock)
 
-	commitContents, err := getCommitBytesWithoutSignature(commit)
-	if err != nil {
-		return plumbing.ZeroHash, err
-	}
-	signature, err := signGitObjectUsingKey(commitContents, signingKeyPEMBytes)
+	signature, err := signGitObjectUsingKey(getCommitContents(commit), signingKeyPEMBytes)
 	if err != nil {
 		return plumbing.ZeroHash, err
 	}
@@ -125,42 +134,27 @@ func WriteCommit(repo *git.Repository, commit *object.Commit) (plumbing.Hash, er
 }
 
 // VerifyCommitSignature is used to verify a cryptographic signature associated
-// with commit using TUF public keys.
+// with commit using TUF public keys. If the context carries allowed signers,
+// SSH signatures must also be issued by an allowed signer at the time the
+// commit was committed. If the context carries revocations, ErrKeyRevoked is
+// returned if the key that issued the signature has been revoked.
 func VerifyCommitSignature(ctx context.Context, commit *object.Commit, key *tuf.Key) error {
-	switch key.KeyType {
-	case signerverifier.GPGKeyType:
-		if _, err := commit.Verify(key.KeyVal.Public); err != nil {
-			return ErrIncorrectVerificationKey
-		}
-
-		return nil
-	case signerverifier.RSAKeyType, signerverifier.ECDSAKeyType, signerverifier.ED25519KeyType:
-		commitContents, err := getCommitBytesWithoutSignature(commit)
-		if err != nil {
-			return errors.Join(ErrVerifyingSSHSignature, err)
-		}
-		commitSignature := []byte(commit.PGPSignature)
-
-		if err := verifySSHKeySignature(key, commitContents, commitSignature); err != nil {
-			return errors.Join(ErrIncorrectVerificationKey, err)
-		}
+	defer timing.Start(timing.PhaseSignatureVerification)()
 
-		return nil
-	case signerverifier.FulcioKeyType:
-		commitContents, err := getCommitBytesWithoutSignature(commit)
-		if err != nil {
-			return errors.Join(ErrVerifyingSigstoreSignature, err)
-		}
-		commitSignature := []byte(commit.PGPSignature)
+	if err := verifierForContext(ctx).verifySignature(ctx, commit, key); err != nil {
+		return err
+	}
 
-		if err := verifyGitsignSignature(ctx, key, commitContents, commitSignature); err != nil {
+	switch key.KeyType {
+	case signerverifier.RSAKeyType, signerverifier.ECDSAKeyType, signerverifier.ED25519KeyType, signerverifier.SSHSecurityKeyType:
+		if err := checkAllowedSigner(ctx, []byte(commit.PGPSignature), commit.Committer.When); err != nil {
 			return errors.Join(ErrIncorrectVerificationKey, err)
 		}
-
-		return nil
 	}
 
-	return ErrUnknownSigningMethod
+	// The key issued the signature, so if it has been revoked, verification
+	// must fail rather than moving on to other keys
+	return checkRevocation(ctx, key, []byte(commit.PGPSignature))
 }
 
 // CreateCommitObject returns a commit object using the specified parameters.
@@ -192,43 +186,140 @@ func CreateCommitObject(gitConfig *config.Config, treeHash plumbing.Hash, parent
 
 // KnowsCommit indicates if the commit under test, identified by commitID, has a
 // path to commit. If commit is the same as the commit under test or if commit
-// is an ancestor of commit under test, KnowsCommit returns true.
+// is an ancestor of commit under test, KnowsCommit returns true. The
+// repository's commit-graph is used to avoid walking the entire history of the
+// commit under test.
 func KnowsCommit(repo *git.Repository, commitID plumbing.Hash, commit *object.Commit) (bool, error) {
 	if commitID == commit.Hash {
 		return true, nil
 	}
 
-	commitUnderTest, err := GetCommit(repo, commitID)
-	if err != nil {
-		return false, err
-	}
-
-	return commit.IsAncestor(commitUnderTest)
+	return isAncestor(repo, commit.Hash, commitID)
 }
 
-// GetCommit returns the requested commit object.
+// GetCommit returns the requested commit object. If caching is enabled for the
+// repository, the commit is parsed once and subsequent requests return a copy
+// of the cached commit.
 func GetCommit(repo *git.Repository, commitID plumbing.Hash) (*object.Commit, error) {
-	return repo.CommitObject(commitID)
+	defer timing.Start(timing.PhaseGitObjectReads)()
+
+	storage := getCachedStorage(repo)
+	if storage == nil {
+		return repo.CommitObject(commitID)
+	}
+
+	commit, has := storage.commits.get(commitID)
+	if !has {
+		var err error
+		commit, err = repo.CommitObject(commitID)
+		if err != nil {
+			return nil, err
+		}
+		storage.commits.add(commitID, commit)
+	}
+
+	// Callers may modify the commit, such as when signing it
+	commitCopy := *commit
+	return &commitCopy, nil
 }
 
 func signCommit(commit *object.Commit) (string, error) {
-	commitContents, err := getCommitBytesWithoutSignature(commit)
+	return signGitObject(getCommitContents(commit))
+}
+
+// getCommitContents returns the contents of the commit that are signed, i.e.,
+// the commit without its signature. go-git's encoding of commits doesn't match
+// Git's when the commit has an encoding header, such as for commits created
+// with i18n.commitEncoding set to ISO-8859-1, so the contents are
+// reconstructed in the order Git writes them.
+func getCommitContents(commit *object.Commit) objectContents {
+	return func() (io.ReadCloser, error) {
+		includeUTF8Encoding, err := hasUTF8EncodingHeader(commit)
+		if err != nil {
+			return nil, err
+		}
+
+		return contentsFromEncoder(func(w io.Writer) error {
+			return encodeCommit(w, commit, false, includeUTF8Encoding)
+		})()
+	}
+}
+
+// hasUTF8EncodingHeader indicates if the commit records the default UTF-8
+// encoding in an encoding header. go-git doesn't distinguish commits without
+// an encoding header from those that explicitly record the default encoding,
+// which Git doesn't write but other tools may, so the commit's ID is used to
+// tell them apart.
+func hasUTF8EncodingHeader(commit *object.Commit) (bool, error) {
+	if commit.Hash.IsZero() || commit.Encoding != utf8CommitEncoding {
+		return false, nil
+	}
+
+	commitID, err := hashEncodedObject(plumbing.CommitObject, func(w io.Writer) error {
+		return encodeCommit(w, commit, true, false)
+	})
+	if err != nil {
+		return false, err
+	}
+	if commitID == commit.Hash {
+		return false, nil
+	}
+
+	commitID, err = hashEncodedObject(plumbing.CommitObject, func(w io.Writer) error {
+		return encodeCommit(w, commit, true, true)
+	})
 	if err != nil {
-		return "", err
+		return false, err
 	}
 
-	return signGitObject(commitContents)
+	return commitID == commit.Hash, nil
 }
 
-func getCommitBytesWithoutSignature(commit *object.Commit) ([]byte, error) {
-	commitEncoded := memory.NewStorage().NewEncodedObject()
-	if err := commit.EncodeWithoutSignature(commitEncoded); err != nil {
-		return nil, err
+// encodeCommit writes the commit with its headers in the order Git writes
+// them: tree, parents, author, committer, encoding, mergetag, and finally the
+// signature if includeSignature is true. The encoding header is omitted for
+// the default UTF-8 encoding unless includeUTF8Encoding is true.
+func encodeCommit(w io.Writer, commit *object.Commit, includeSignature, includeUTF8Encoding bool) error {
+	// The buffered writer records the first error encountered, which is
+	// returned when it's flushed
+	contents := bufio.NewWriter(w)
+
+	fmt.Fprintf(contents, "tree %s\n", commit.TreeHash.String())
+	for _, parentHash := range commit.ParentHashes {
+		fmt.Fprintf(contents, "parent %s\n", parentHash.String())
 	}
-	r, err := commitEncoded.Reader()
-	if err != nil {
-		return nil, err
+
+	contents.WriteString("author ") //nolint:errcheck
+	if err := commit.Author.Encode(contents); err != nil {
+		return err
+	}
+	contents.WriteString("\ncommitter ") //nolint:errcheck
+	if err := commit.Committer.Encode(contents); err != nil {
+		return err
+	}
+	contents.WriteString("\n") //nolint:errcheck
+
+	if commit.Encoding != "" && (commit.Encoding != utf8CommitEncoding || includeUTF8Encoding) {
+		fmt.Fprintf(contents, "encoding %s\n", commit.Encoding)
 	}
 
-	return io.ReadAll(r)
+	if commit.MergeTag != "" {
+		writeMultilineHeader(contents, "mergetag", commit.MergeTag)
+	}
+
+	if includeSignature && commit.PGPSignature != "" {
+		writeMultilineHeader(contents, "gpgsig", commit.PGPSignature)
+	}
+
+	contents.WriteString("\n")           //nolint:errcheck
+	contents.WriteString(commit.Message) //nolint:errcheck
+
+	return contents.Flush()
+}
+
+// writeMultilineHeader writes a header whose value spans multiple lines, with
+// each continuation line prefixed by a space.
+func writeMultilineHeader(contents io.Writer, name, value string) {
+	lines := strings.Split(strings.TrimSuffix(value, "\n"), "\n")
+	fmt.Fprintf(contents, "%s %s\n", name, strings.Join(lines, "\n "))
 }
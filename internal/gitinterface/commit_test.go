@@ -5,6 +5,7 @@ package gitinterface
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -183,6 +184,104 @@ oYBpMWLgg6AUzpxx9mITZ2EKr4c=
 		err = VerifyCommitSignature(context.Background(), sshCommits[1], rsaKey)
 		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
 	})
+
+	t.Run("use ssh signed commits with allowed signers", func(t *testing.T) {
+		// The commits are created at testClock's time, in 1995
+		allowedSigners, err := ParseAllowedSigners(strings.NewReader(fmt.Sprintf(
+			"jane.doe@example.com %s\njohn.doe@example.com valid-before=\"19950101\" %s\n",
+			strings.TrimSpace(string(artifacts.SSHRSAPublicSSH)),
+			strings.TrimSpace(string(artifacts.SSHECDSAPublicSSH)),
+		)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := ContextWithAllowedSigners(context.Background(), allowedSigners)
+
+		err = VerifyCommitSignature(ctx, sshCommits[0], rsaKey)
+		assert.Nil(t, err)
+
+		// The key is no longer allowed when the commit was created
+		err = VerifyCommitSignature(ctx, sshCommits[1], ecdsaKey)
+		assert.ErrorIs(t, err, ErrSSHKeyNotAllowed)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+
+		// GPG signatures are not affected
+		err = VerifyCommitSignature(ctx, gpgSignedCommit, gpgKey)
+		assert.Nil(t, err)
+	})
+
+	t.Run("use ssh signed commits with revoked keys", func(t *testing.T) {
+		revocations := &Revocations{}
+		if err := revocations.ParseSSHRevocationList(bytes.NewReader(artifacts.SSHECDSAPublicSSH)); err != nil {
+			t.Fatal(err)
+		}
+		ctx := ContextWithRevocations(context.Background(), revocations)
+
+		err = VerifyCommitSignature(ctx, sshCommits[0], rsaKey)
+		assert.Nil(t, err)
+
+		// The key issued the signature, but verification fails rather
+		// than reporting an incorrect key
+		err = VerifyCommitSignature(ctx, sshCommits[1], ecdsaKey)
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+		assert.NotErrorIs(t, err, ErrIncorrectVerificationKey)
+
+		// Revoked keys that didn't issue the signature are still reported
+		// as incorrect
+		err = VerifyCommitSignature(ctx, sshCommits[0], ecdsaKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+}
+
+func TestGetCommitBytesWithoutSignature(t *testing.T) {
+	key, err := sslibsv.LoadKey(artifacts.SSHED25519Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\nparent 953facd49729d866379218e08a3435584cb4ea51\nauthor J\xf6rg <jorg@example.com> 814698000 +0000\ncommitter J\xf6rg <jorg@example.com> 814698000 +0000\n"
+	mergeTag := "mergetag object 8563fd7ff19c382b296f0b7df564fa1791965af7\n type commit\n tag v1\n tagger J\xf6rg <jorg@example.com> 814698000 +0000\n \n Release caf\xe9\n"
+
+	tests := map[string]string{
+		"no encoding":                header + "\nTest commit\n",
+		"non-UTF-8 encoding":         header + "encoding ISO-8859-1\n\nCaf\xe9\n",
+		"non-UTF-8 encoding, merged": header + "encoding ISO-8859-1\n" + mergeTag + "\nMerge caf\xe9\n",
+		"explicit UTF-8 encoding":    header + "encoding UTF-8\n\nCaf\xc3\xa9\n",
+	}
+
+	for name, payload := range tests {
+		signature, err := signGitObjectUsingSSHKey(contentsFromBytes([]byte(payload)), artifacts.SSHED25519Private)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The signature is the last header
+		headers, message, _ := strings.Cut(payload, "\n\n")
+		signatureLines := strings.Split(strings.TrimSuffix(signature, "\n"), "\n")
+		contents := fmt.Sprintf("%s\ngpgsig %s\n\n%s", headers, strings.Join(signatureLines, "\n "), message)
+
+		storage := memory.NewStorage()
+		obj := storage.NewEncodedObject()
+		obj.SetType(plumbing.CommitObject)
+		writer, err := obj.Writer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+		commit, err := object.DecodeCommit(storage, obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitContents, err := readContents(getCommitContents(commit))
+		assert.Nil(t, err, name)
+		assert.Equal(t, payload, string(commitContents), name)
+
+		err = VerifyCommitSignature(context.Background(), commit, key)
+		assert.Nil(t, err, name)
+	}
 }
 
 func TestKnowsCommit(t *testing.T) {
@@ -325,7 +424,7 @@ func createTestSSHSignedCommits(t *testing.T) []*object.Commit {
 			TreeHash: EmptyTree(),
 		}
 
-		commitBytes, err := getCommitBytesWithoutSignature(testCommit)
+		commitBytes, err := readContents(getCommitContents(testCommit))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	commitgraphfmt "github.com/go-git/go-git/v5/plumbing/format/commitgraph/v2"
+	"github.com/go-git/go-git/v5/plumbing/object/commitgraph"
+)
+
+// commitGraphWriteAttempted tracks the common GIT_DIRs a commit-graph was
+// written for, so that it's attempted at most once per repository.
+var commitGraphWriteAttempted sync.Map
+
+// isAncestor returns true if the commit identified by ancestorID is reachable
+// from the commit identified by descendantID. When the commits are in the
+// repository's commit-graph, their generation numbers are used to stop
+// walking the history of the descendant as soon as the walk passes the
+// ancestor's generation, rather than walking back to the root commits. The
+// walk is cached and resumed by subsequent checks for the same descendant, see
+// getReachabilityWalk.
+func isAncestor(repo *git.Repository, ancestorID, descendantID plumbing.Hash) (bool, error) {
+	nodeIndex, closeIndex := getCommitNodeIndex(repo)
+	defer closeIndex()
+
+	ancestor, err := nodeIndex.Get(ancestorID)
+	if err != nil {
+		return false, err
+	}
+
+	// Commits that aren't in the commit-graph have the maximum generation, so
+	// no commit is skipped if the ancestor isn't in the commit-graph.
+	return getReachabilityWalk(repo, descendantID).reaches(nodeIndex, ancestorID, ancestor.Generation())
+}
+
+// getCommitNodeIndex returns an index to load commits from the repository's
+// commit-graph, falling back to the object store for commits that aren't in
+// the commit-graph. If the repository is stored on disk and doesn't have a
+// commit-graph, one is written first. The returned function must be called to
+// close the commit-graph once the index is no longer needed.
+func getCommitNodeIndex(repo *git.Repository) (commitgraph.CommitNodeIndex, func()) {
+	noop := func() {}
+
+	commonDir, err := GetGitCommonDirFor(repo)
+	if err != nil {
+		// The repository isn't stored on disk
+		return commitgraph.NewObjectCommitNodeIndex(repo.Storer), noop
+	}
+
+	fs := osfs.New(commonDir)
+	index, err := commitgraphfmt.OpenChainOrFileIndex(fs)
+	if err != nil {
+		if _, attempted := commitGraphWriteAttempted.LoadOrStore(commonDir, true); attempted {
+			return commitgraph.NewObjectCommitNodeIndex(repo.Storer), noop
+		}
+
+		logger.Debug(fmt.Sprintf("Writing commit-graph for repository at '%s'...", commonDir))
+		gitRepo := &Repository{gitDirPath: commonDir}
+		if _, err := gitRepo.executeGitCommandString("commit-graph", "write", "--reachable", "--no-progress"); err != nil {
+			logger.Debug(fmt.Sprintf("Unable to write commit-graph: %s", err.Error()))
+			return commitgraph.NewObjectCommitNodeIndex(repo.Storer), noop
+		}
+
+		index, err = commitgraphfmt.OpenChainOrFileIndex(fs)
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Unable to open commit-graph: %s", err.Error()))
+			return commitgraph.NewObjectCommitNodeIndex(repo.Storer), noop
+		}
+	}
+
+	return commitgraph.NewGraphCommitNodeIndex(index, repo.Storer), func() {
+		index.Close() //nolint:errcheck
+	}
+}
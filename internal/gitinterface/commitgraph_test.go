@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKnowsCommitUsingCommitGraph(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	runGit := func(args ...string) plumbing.Hash {
+		t.Helper()
+
+		args = append([]string{"-C", tmpDir, "-c", "user.name=" + testName, "-c", "user.email=" + testEmail, "-c", "commit.gpgsign=false"}, args...)
+		output, err := exec.Command(binary, args...).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return plumbing.NewHash(strings.TrimSpace(string(output)))
+	}
+
+	commit := func(message string) plumbing.Hash {
+		t.Helper()
+
+		runGit("commit", "--quiet", "--allow-empty", "-m", message)
+		return runGit("rev-parse", "HEAD")
+	}
+
+	runGit("init", "--quiet", "-b", "main")
+	firstID := commit("First commit")
+	secondID := commit("Second commit")
+	runGit("checkout", "--quiet", "-b", "feature", firstID.String())
+	featureID := commit("Feature commit")
+	runGit("checkout", "--quiet", "main")
+	runGit("merge", "--quiet", "--no-ff", "-m", "Merge feature", "feature")
+	mergeID := runGit("rev-parse", "HEAD")
+
+	repo, err := OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstCommit, err := GetCommit(repo, firstID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	featureCommit, err := GetCommit(repo, featureID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	knows, err := KnowsCommit(repo, mergeID, featureCommit)
+	assert.Nil(t, err)
+	assert.True(t, knows)
+
+	// The commit-graph is written when it's first needed
+	_, err = os.Stat(filepath.Join(tmpDir, ".git", "objects", "info", "commit-graph"))
+	assert.Nil(t, err)
+
+	knows, err = KnowsCommit(repo, secondID, featureCommit)
+	assert.Nil(t, err)
+	assert.False(t, knows)
+
+	// Commits created after the commit-graph was written are walked using
+	// their objects
+	latestID := commit("Latest commit")
+
+	knows, err = KnowsCommit(repo, latestID, firstCommit)
+	assert.Nil(t, err)
+	assert.True(t, knows)
+
+	latestCommit, err := GetCommit(repo, latestID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	knows, err = KnowsCommit(repo, featureID, latestCommit)
+	assert.Nil(t, err)
+	assert.False(t, knows)
+}
@@ -3,49 +3,222 @@
 package gitinterface
 
 import (
-	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 )
 
+var (
+	ErrInvalidConfigBool      = errors.New("invalid boolean value in git config")
+	ErrUnsupportedConfigScope = errors.New("unsupported git config scope")
+)
+
+// ConfigScope identifies the config file a Git config value is read from or
+// written to, using the names reported by `git config --show-scope`.
+type ConfigScope string
+
+const (
+	ConfigScopeSystem   ConfigScope = "system"
+	ConfigScopeGlobal   ConfigScope = "global"
+	ConfigScopeLocal    ConfigScope = "local"
+	ConfigScopeWorktree ConfigScope = "worktree"
+
+	// ConfigScopeCommand is the scope of values set for a single invocation
+	// of Git, using `git -c` or the GIT_CONFIG_* environment variables.
+	// Values can't be written to this scope.
+	ConfigScopeCommand ConfigScope = "command"
+)
+
+// ConfigEntry is a single value of a key in the Git config.
+type ConfigEntry struct {
+	Scope ConfigScope
+	Key   string
+	Value string
+}
+
+// GitConfig is the parsed Git config, made up of the values read from each
+// scope. Unlike a map of keys to values, it preserves every value of keys set
+// multiple times, such as remote.<name>.fetch or include.path, and the scope
+// each value was read from.
+type GitConfig struct {
+	entries []*ConfigEntry
+	values  map[string][]string
+}
+
+// NewGitConfig returns a GitConfig for the entries, which must be in
+// increasing order of precedence like in the output of `git config --list`.
+func NewGitConfig(entries []*ConfigEntry) *GitConfig {
+	values := map[string][]string{}
+	for _, entry := range entries {
+		values[entry.Key] = append(values[entry.Key], entry.Value)
+	}
+
+	return &GitConfig{entries: entries, values: values}
+}
+
+// Get returns the value of the key that takes precedence. An empty string is
+// returned if the key isn't set.
+func (c *GitConfig) Get(key string) string {
+	values := c.values[NormalizeConfigKey(key)]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[len(values)-1]
+}
+
+// Has returns true if the key is set in any scope.
+func (c *GitConfig) Has(key string) bool {
+	_, has := c.values[NormalizeConfigKey(key)]
+	return has
+}
+
+// GetAll returns all the values of the key, in increasing order of precedence.
+// Nil is returned if the key isn't set.
+func (c *GitConfig) GetAll(key string) []string {
+	return c.values[NormalizeConfigKey(key)]
+}
+
+// GetBool interprets the value of the key that takes precedence as a boolean,
+// the way Git does. False is returned if the key isn't set.
+func (c *GitConfig) GetBool(key string) (bool, error) {
+	return parseConfigBool(key, c.Get(key))
+}
+
+// GetInScope returns the values of the key set in the scope, in increasing
+// order of precedence. Nil is returned if the key isn't set in the scope.
+func (c *GitConfig) GetInScope(key string, scope ConfigScope) []string {
+	key = NormalizeConfigKey(key)
+
+	var values []string
+	for _, entry := range c.entries {
+		if entry.Scope == scope && entry.Key == key {
+			values = append(values, entry.Value)
+		}
+	}
+
+	return values
+}
+
+// Entries returns every value in the config, in increasing order of
+// precedence.
+func (c *GitConfig) Entries() []*ConfigEntry {
+	return c.entries
+}
+
+// Values returns all the values set for each key, in increasing order of
+// precedence.
+func (c *GitConfig) Values() map[string][]string {
+	return c.values
+}
+
+// NormalizeConfigKey returns the key the way Git lists it, with the section
+// and the variable name in lowercase. The subsection, if any, is case
+// sensitive and is left as is.
+func NormalizeConfigKey(key string) string {
+	firstDot := strings.Index(key, ".")
+	lastDot := strings.LastIndex(key, ".")
+	if firstDot == -1 {
+		return strings.ToLower(key)
+	}
+
+	return strings.ToLower(key[:firstDot]) + key[firstDot:lastDot] + strings.ToLower(key[lastDot:])
+}
+
 var (
 	getGitConfigFromCommand = execGitConfig // variable used to override in tests
 	getGitConfig            = getRealGitConfig
 )
 
-// GetConfig parses the user's Git config. It shells out to the Git binary
+// LoadConfig parses the user's Git config. It shells out to the Git binary
 // because go-git has difficulty combining local, global, and system configs
 // while maintaining all of their fields.
 // See: https://github.com/go-git/go-git/issues/508
-func getConfig() (map[string]string, error) {
+func LoadConfig() (*GitConfig, error) {
 	configReader, err := getGitConfigFromCommand()
 	if err != nil {
 		return nil, err
 	}
 
-	config := map[string]string{}
+	return parseConfig(configReader)
+}
 
-	s := bufio.NewScanner(configReader)
-	for s.Scan() {
-		raw := s.Text()
-		data := strings.Split(raw, " ")
-		if len(data) < 2 {
-			continue
-		}
-		config[data[0]] = strings.Join(data[1:], " ")
+// getConfig parses the user's Git config. For keys with multiple values, the
+// value that takes precedence is returned.
+func getConfig() (map[string]string, error) {
+	config, err := getConfigValues()
+	if err != nil {
+		return nil, err
 	}
 
-	return config, nil
+	return effectiveConfigValues(config), nil
+}
+
+// getConfigValues parses the user's Git config, returning all the values set
+// for each key.
+func getConfigValues() (map[string][]string, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Values(), nil
+}
+
+// GetConfigValue returns the value of the key in the user's Git config. An
+// empty string is returned if the key isn't set. Note that Git normalizes the
+// section and the variable name of keys to lowercase, but not the subsection.
+// If the key has multiple values, such as when it's set in both the global and
+// the repository's config, the value that takes precedence is returned.
+func GetConfigValue(key string) (string, error) {
+	config, err := getConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return config[key], nil
+}
+
+// GetConfigValues returns all the values of the key in the user's Git config,
+// in increasing order of precedence. Nil is returned if the key isn't set.
+func GetConfigValues(key string) ([]string, error) {
+	config, err := getConfigValues()
+	if err != nil {
+		return nil, err
+	}
+
+	return config[key], nil
+}
+
+// parseConfigBool interprets a Git config value as a boolean. Like Git, "true",
+// "yes", "on", and non-zero integers are true, while "false", "no", "off",
+// zero, and the empty string are false. Keys listed without a value are
+// already "true" in the parsed config.
+func parseConfigBool(key, value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off", "":
+		return false, nil
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("%w: '%s' for '%s'", ErrInvalidConfigBool, value, key)
+	}
+
+	return number != 0, nil
 }
 
 func execGitConfig() (io.Reader, error) {
-	cmd := exec.Command("git", "config", "--get-regexp", `.*`)
+	cmd := exec.Command("git", "config", "--list", "--show-scope", "-z")
 	stdout := &bytes.Buffer{}
 	stderr := &bytes.Buffer{}
 	cmd.Stdout = stdout
@@ -57,36 +230,111 @@ func execGitConfig() (io.Reader, error) {
 	return stdout, nil
 }
 
+// parseConfig parses the output of `git config --list --show-scope -z`. Each
+// entry is made up of its scope and its key and value, both terminated by a
+// NUL byte. The key is separated from the value by a newline, so values may
+// contain spaces and newlines. Git lists the system, global, local, worktree,
+// and command configs in that order, so the values of a key are in increasing
+// order of precedence. A key without a value, which Git treats as a boolean,
+// has the value "true".
+func parseConfig(reader io.Reader) (*GitConfig, error) {
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Split(string(contents), "\x00")
+	if fields[len(fields)-1] == "" {
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields)%2 != 0 {
+		return nil, errors.New("unable to parse git config: entry without scope")
+	}
+
+	entries := make([]*ConfigEntry, 0, len(fields)/2)
+	for index := 0; index < len(fields); index += 2 {
+		key, value, hasValue := strings.Cut(fields[index+1], "\n")
+		if !hasValue {
+			value = "true"
+		}
+
+		// Git for Windows may report values read from config files with
+		// CRLF line endings with a trailing CR
+		key = strings.TrimSuffix(key, "\r")
+		value = strings.TrimSuffix(value, "\r")
+		entries = append(entries, &ConfigEntry{Scope: ConfigScope(fields[index]), Key: key, Value: value})
+	}
+
+	return NewGitConfig(entries), nil
+}
+
+// effectiveConfigValues returns the value that takes precedence for each key.
+func effectiveConfigValues(config map[string][]string) map[string]string {
+	effectiveConfig := make(map[string]string, len(config))
+	for key, values := range config {
+		effectiveConfig[key] = values[len(values)-1]
+	}
+
+	return effectiveConfig
+}
+
 func getRealGitConfig(repo *git.Repository) (*config.Config, error) {
 	return repo.ConfigScoped(config.GlobalScope)
 }
 
+// LoadGitConfig reads the applicable Git config for a repository, including
+// the scope each value is set in.
+func (r *Repository) LoadGitConfig() (*GitConfig, error) {
+	config, err := r.getBackend().getConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read Git config: %w", err)
+	}
+
+	return config, nil
+}
+
 // GetGitConfig reads the applicable Git config for a repository and returns
-// it. The "keys" for each config are normalized to lowercase.
+// it. The section and variable name of keys are normalized to lowercase. If a
+// key has multiple values, the value that takes precedence is returned.
 func (r *Repository) GetGitConfig() (map[string]string, error) {
-	stdOut, err := r.executeGitCommandString("config", "--get-regexp", `.*`)
+	config, err := r.GetGitConfigValues()
 	if err != nil {
-		return nil, fmt.Errorf("unable to read Git config: %w", err)
+		return nil, err
 	}
 
-	config := map[string]string{}
+	return effectiveConfigValues(config), nil
+}
 
-	lines := strings.Split(strings.TrimSpace(stdOut), "\n")
-	for _, line := range lines {
-		split := strings.Split(line, " ")
-		if len(split) < 2 {
-			continue
-		}
-		config[strings.ToLower(split[0])] = strings.Join(split[1:], " ")
+// GetGitConfigValues reads the applicable Git config for a repository and
+// returns all the values set for each key, in increasing order of precedence.
+func (r *Repository) GetGitConfigValues() (map[string][]string, error) {
+	config, err := r.LoadGitConfig()
+	if err != nil {
+		return nil, err
 	}
 
-	return config, nil
+	return config.Values(), nil
 }
 
 // SetGitConfig sets the specified key to the value locally for a repository.
 func (r *Repository) SetGitConfig(key, value string) error {
-	if _, err := r.executeGitCommandString("config", "--local", key, value); err != nil {
-		return fmt.Errorf("unable to set '%s' to '%s': %w", key, value, err)
+	return r.SetGitConfigInScope(ConfigScopeLocal, key, value)
+}
+
+// SetGitConfigInScope sets the specified key to the value in the scope's
+// config. The local and worktree scopes are specific to the repository, while
+// the global and system scopes apply to all of the user's repositories.
+// Writing to the worktree scope requires Git's extensions.worktreeConfig to be
+// enabled for the repository.
+func (r *Repository) SetGitConfigInScope(scope ConfigScope, key, value string) error {
+	switch scope {
+	case ConfigScopeSystem, ConfigScopeGlobal, ConfigScopeLocal, ConfigScopeWorktree:
+	default:
+		return fmt.Errorf("%w: '%s'", ErrUnsupportedConfigScope, scope)
+	}
+
+	if err := r.getBackend().setConfig(scope, key, value); err != nil {
+		return fmt.Errorf("unable to set '%s' to '%s' in %s config: %w", key, value, scope, err)
 	}
 
 	return nil
@@ -3,6 +3,9 @@
 package gitinterface
 
 import (
+	"bytes"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,4 +20,258 @@ func TestGetGitConfig(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, testName, config["user.name"])
 	assert.Equal(t, testEmail, config["user.email"])
+
+	t.Run("values with spaces and newlines", func(t *testing.T) {
+		err := repo.SetGitConfig("test.spaces", "value with  spaces")
+		assert.Nil(t, err)
+		err = repo.SetGitConfig("test.newlines", "first line\nsecond line")
+		assert.Nil(t, err)
+
+		config, err := repo.GetGitConfig()
+		assert.Nil(t, err)
+		assert.Equal(t, "value with  spaces", config["test.spaces"])
+		assert.Equal(t, "first line\nsecond line", config["test.newlines"])
+	})
+
+	t.Run("multi-valued keys", func(t *testing.T) {
+		for _, value := range []string{"first", "second"} {
+			if _, err := repo.executeGitCommandString("config", "--local", "--add", "test.multi", value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := repo.executeGitCommandString("config", "--local", "test.Subsection.key", "value"); err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := repo.GetGitConfigValues()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"first", "second"}, config["test.multi"])
+		assert.Equal(t, []string{"value"}, config["test.Subsection.key"])
+
+		effectiveConfig, err := repo.GetGitConfig()
+		assert.Nil(t, err)
+		assert.Equal(t, "second", effectiveConfig["test.multi"])
+	})
+
+	t.Run("scoped values", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv("XDG_CONFIG_HOME", "")
+
+		err := repo.SetGitConfigInScope(ConfigScopeGlobal, "test.scoped", "global value")
+		assert.Nil(t, err)
+		err = repo.SetGitConfigInScope(ConfigScopeLocal, "test.scoped", "local value")
+		assert.Nil(t, err)
+
+		config, err := repo.LoadGitConfig()
+		assert.Nil(t, err)
+		assert.Equal(t, "local value", config.Get("test.scoped"))
+		assert.Equal(t, []string{"global value", "local value"}, config.GetAll("test.scoped"))
+		assert.Equal(t, []string{"global value"}, config.GetInScope("test.scoped", ConfigScopeGlobal))
+		assert.Equal(t, []string{"local value"}, config.GetInScope("test.scoped", ConfigScopeLocal))
+
+		err = repo.SetGitConfig("extensions.worktreeConfig", "true")
+		assert.Nil(t, err)
+		err = repo.SetGitConfigInScope(ConfigScopeWorktree, "test.scoped", "worktree value")
+		assert.Nil(t, err)
+
+		config, err = repo.LoadGitConfig()
+		assert.Nil(t, err)
+		assert.Equal(t, "worktree value", config.Get("test.scoped"))
+		assert.Equal(t, []string{"worktree value"}, config.GetInScope("test.scoped", ConfigScopeWorktree))
+
+		err = repo.SetGitConfigInScope(ConfigScopeCommand, "test.scoped", "value")
+		assert.ErrorIs(t, err, ErrUnsupportedConfigScope)
+	})
+}
+
+func TestGetConfig(t *testing.T) {
+	t.Cleanup(func() {
+		getGitConfigFromCommand = execGitConfig
+	})
+
+	// The global config is listed before the repository's config
+	output := []byte("global\x00user.name\nJane Doe\x00global\x00user.signingkey\n~/.ssh/id_ed25519\x00global\x00includeif.gitdir:~/work/.path\n~/.gitconfig-work\x00global\x00includeif.gitdir:~/oss/.path\n~/.gitconfig-oss\x00local\x00user.name\nJane Q. Doe\x00")
+	getGitConfigFromCommand = func() (io.Reader, error) {
+		return bytes.NewReader(output), nil
+	}
+
+	config, err := getConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"user.name":                     "Jane Q. Doe",
+		"user.signingkey":               "~/.ssh/id_ed25519",
+		"includeif.gitdir:~/work/.path": "~/.gitconfig-work",
+		"includeif.gitdir:~/oss/.path":  "~/.gitconfig-oss",
+	}, config)
+
+	value, err := GetConfigValue("user.name")
+	assert.Nil(t, err)
+	assert.Equal(t, "Jane Q. Doe", value)
+
+	values, err := GetConfigValues("user.name")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Jane Doe", "Jane Q. Doe"}, values)
+
+	values, err = GetConfigValues("user.email")
+	assert.Nil(t, err)
+	assert.Nil(t, values)
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := map[string]struct {
+		output          string
+		expectedEntries []*ConfigEntry
+		expectedValues  map[string][]string
+		expectedError   bool
+	}{
+		"no config": {
+			output:          "",
+			expectedEntries: []*ConfigEntry{},
+			expectedValues:  map[string][]string{},
+		},
+		"single values": {
+			output: "global\x00user.name\nJane Doe\x00local\x00gpg.format\nssh\x00",
+			expectedEntries: []*ConfigEntry{
+				{Scope: ConfigScopeGlobal, Key: "user.name", Value: "Jane Doe"},
+				{Scope: ConfigScopeLocal, Key: "gpg.format", Value: "ssh"},
+			},
+			expectedValues: map[string][]string{
+				"user.name":  {"Jane Doe"},
+				"gpg.format": {"ssh"},
+			},
+		},
+		"value with newlines": {
+			output: "global\x00alias.lg\nlog\n--oneline\x00",
+			expectedEntries: []*ConfigEntry{
+				{Scope: ConfigScopeGlobal, Key: "alias.lg", Value: "log\n--oneline"},
+			},
+			expectedValues: map[string][]string{
+				"alias.lg": {"log\n--oneline"},
+			},
+		},
+		"empty value": {
+			output: "local\x00user.signingkey\n\x00",
+			expectedEntries: []*ConfigEntry{
+				{Scope: ConfigScopeLocal, Key: "user.signingkey", Value: ""},
+			},
+			expectedValues: map[string][]string{
+				"user.signingkey": {""},
+			},
+		},
+		"key without value": {
+			output: "command\x00commit.gpgsign\x00",
+			expectedEntries: []*ConfigEntry{
+				{Scope: ConfigScopeCommand, Key: "commit.gpgsign", Value: "true"},
+			},
+			expectedValues: map[string][]string{
+				"commit.gpgsign": {"true"},
+			},
+		},
+		"CRLF line endings": {
+			output: "global\x00gpg.program\r\nC:\\Program Files\\GnuPG\\bin\\gpg.exe\r\x00global\x00commit.gpgsign\r\x00",
+			expectedEntries: []*ConfigEntry{
+				{Scope: ConfigScopeGlobal, Key: "gpg.program", Value: `C:\Program Files\GnuPG\bin\gpg.exe`},
+				{Scope: ConfigScopeGlobal, Key: "commit.gpgsign", Value: "true"},
+			},
+			expectedValues: map[string][]string{
+				"gpg.program":    {`C:\Program Files\GnuPG\bin\gpg.exe`},
+				"commit.gpgsign": {"true"},
+			},
+		},
+		"multi-valued key": {
+			output: "local\x00remote.origin.fetch\n+refs/heads/*:refs/remotes/origin/*\x00local\x00remote.origin.fetch\n+refs/gittuf/*:refs/gittuf/*\x00",
+			expectedEntries: []*ConfigEntry{
+				{Scope: ConfigScopeLocal, Key: "remote.origin.fetch", Value: "+refs/heads/*:refs/remotes/origin/*"},
+				{Scope: ConfigScopeLocal, Key: "remote.origin.fetch", Value: "+refs/gittuf/*:refs/gittuf/*"},
+			},
+			expectedValues: map[string][]string{
+				"remote.origin.fetch": {"+refs/heads/*:refs/remotes/origin/*", "+refs/gittuf/*:refs/gittuf/*"},
+			},
+		},
+		"entry without scope": {
+			output:        "user.name\nJane Doe\x00",
+			expectedError: true,
+		},
+	}
+
+	for name, test := range tests {
+		config, err := parseConfig(strings.NewReader(test.output))
+		if test.expectedError {
+			assert.NotNil(t, err, name)
+			continue
+		}
+
+		assert.Nil(t, err, name)
+		assert.Equal(t, test.expectedEntries, config.Entries(), name)
+		assert.Equal(t, test.expectedValues, config.Values(), name)
+	}
+}
+
+func TestGitConfig(t *testing.T) {
+	config := NewGitConfig([]*ConfigEntry{
+		{Scope: ConfigScopeSystem, Key: "include.path", Value: "/etc/gitconfig.d/defaults"},
+		{Scope: ConfigScopeGlobal, Key: "include.path", Value: "~/.gitconfig-work"},
+		{Scope: ConfigScopeGlobal, Key: "commit.gpgsign", Value: "yes"},
+		{Scope: ConfigScopeGlobal, Key: "gittuf.Origin.url", Value: "https://example.com/repo"},
+		{Scope: ConfigScopeLocal, Key: "commit.gpgsign", Value: "false"},
+		{Scope: ConfigScopeLocal, Key: "tag.gpgsign", Value: "maybe"},
+	})
+
+	assert.Equal(t, "false", config.Get("commit.gpgsign"))
+	assert.Equal(t, "false", config.Get("Commit.GPGSign"))
+	assert.Equal(t, "https://example.com/repo", config.Get("GITTUF.Origin.URL"))
+	assert.Equal(t, "", config.Get("gittuf.origin.url"))
+	assert.Equal(t, "", config.Get("user.name"))
+
+	assert.True(t, config.Has("include.path"))
+	assert.False(t, config.Has("user.name"))
+
+	assert.Equal(t, []string{"/etc/gitconfig.d/defaults", "~/.gitconfig-work"}, config.GetAll("include.path"))
+	assert.Nil(t, config.GetAll("user.name"))
+
+	assert.Equal(t, []string{"yes"}, config.GetInScope("commit.gpgsign", ConfigScopeGlobal))
+	assert.Equal(t, []string{"false"}, config.GetInScope("commit.gpgsign", ConfigScopeLocal))
+	assert.Equal(t, []string{"~/.gitconfig-work"}, config.GetInScope("include.path", ConfigScopeGlobal))
+	assert.Nil(t, config.GetInScope("commit.gpgsign", ConfigScopeWorktree))
+
+	enabled, err := config.GetBool("commit.gpgsign")
+	assert.Nil(t, err)
+	assert.False(t, enabled)
+
+	enabled, err = config.GetBool("push.gpgsign")
+	assert.Nil(t, err)
+	assert.False(t, enabled)
+
+	_, err = config.GetBool("tag.gpgsign")
+	assert.ErrorIs(t, err, ErrInvalidConfigBool)
+}
+
+func TestNormalizeConfigKey(t *testing.T) {
+	tests := map[string]string{
+		"user.signingKey":               "user.signingkey",
+		"Remote.Origin.Fetch":           "remote.Origin.fetch",
+		"includeIf.gitdir:~/Work/.path": "includeif.gitdir:~/Work/.path",
+		"gittuf":                        "gittuf",
+	}
+
+	for key, expectedKey := range tests {
+		assert.Equal(t, expectedKey, NormalizeConfigKey(key), key)
+	}
+}
+
+func TestParseConfigBool(t *testing.T) {
+	for _, value := range []string{"true", "TRUE", "yes", "on", "1", "-1", "42"} {
+		enabled, err := parseConfigBool("commit.gpgsign", value)
+		assert.Nil(t, err, value)
+		assert.True(t, enabled, value)
+	}
+
+	for _, value := range []string{"false", "No", "off", "0", ""} {
+		enabled, err := parseConfigBool("commit.gpgsign", value)
+		assert.Nil(t, err, value)
+		assert.False(t, enabled, value)
+	}
+
+	_, err := parseConfigBool("commit.gpgsign", "maybe")
+	assert.ErrorIs(t, err, ErrInvalidConfigBool)
 }
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var errStreamedObjectNotReadable = errors.New("streamed object can't be read")
+
+// objectContents returns a reader for the contents of a Git commit or tag that
+// are signed, i.e., the object without its signature. The contents are
+// streamed to signing programs and verifiers rather than held in memory, so
+// large objects are signed and verified using constant memory. Each call
+// returns a new reader that must be closed, as some workflows read the
+// contents more than once, such as when falling back to ssh-keygen for keys
+// held in security keys.
+type objectContents func() (io.ReadCloser, error)
+
+// contentsFromBytes returns the contents for a Git object that's already held
+// in memory.
+func contentsFromBytes(contents []byte) objectContents {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(contents)), nil
+	}
+}
+
+// contentsFromEncoder returns the contents written by encode, which is invoked
+// in a separate goroutine each time the contents are read. If the reader is
+// closed before the contents are read in full, encode fails and the goroutine
+// exits.
+func contentsFromEncoder(encode func(io.Writer) error) objectContents {
+	return func() (io.ReadCloser, error) {
+		reader, writer := io.Pipe()
+		go func() {
+			writer.CloseWithError(encode(writer)) //nolint:errcheck
+		}()
+
+		return reader, nil
+	}
+}
+
+// contentsFromGoGitEncoder returns the contents of a Git object as encoded by
+// go-git, such as using object.Commit.EncodeWithoutSignature, without storing
+// the encoded object.
+func contentsFromGoGitEncoder(encode func(plumbing.EncodedObject) error) objectContents {
+	return contentsFromEncoder(func(w io.Writer) error {
+		return encode(&streamedObject{writer: w})
+	})
+}
+
+// readContents reads the contents in full. It's used by workflows whose
+// libraries require the contents in memory, such as those for gitsign and
+// S/MIME signatures.
+func readContents(contents objectContents) ([]byte, error) {
+	reader, err := contents()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close() //nolint:errcheck
+
+	return io.ReadAll(reader)
+}
+
+// hashEncodedObject returns the ID of the Git object of the specified type
+// whose contents are written by encode. The contents are encoded twice, first
+// to determine their size, which prefixes the hashed contents, so that they
+// aren't held in memory.
+func hashEncodedObject(objectType plumbing.ObjectType, encode func(io.Writer) error) (plumbing.Hash, error) {
+	counter := &countingWriter{}
+	if err := encode(counter); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	hasher := plumbing.NewHasher(objectType, counter.size)
+	if err := encode(hasher); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return hasher.Sum(), nil
+}
+
+// countingWriter discards the bytes written to it, recording their number.
+type countingWriter struct {
+	size int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.size += int64(len(p))
+	return len(p), nil
+}
+
+// streamedObject is a plumbing.EncodedObject that writes the object encoded by
+// go-git to the wrapped writer instead of storing it.
+type streamedObject struct {
+	writer     io.Writer
+	objectType plumbing.ObjectType
+	size       int64
+}
+
+func (o *streamedObject) Hash() plumbing.Hash {
+	return plumbing.ZeroHash
+}
+
+func (o *streamedObject) Type() plumbing.ObjectType {
+	return o.objectType
+}
+
+func (o *streamedObject) SetType(objectType plumbing.ObjectType) {
+	o.objectType = objectType
+}
+
+func (o *streamedObject) Size() int64 {
+	return o.size
+}
+
+func (o *streamedObject) SetSize(size int64) {
+	o.size = size
+}
+
+func (o *streamedObject) Reader() (io.ReadCloser, error) {
+	return nil, errStreamedObjectNotReadable
+}
+
+func (o *streamedObject) Writer() (io.WriteCloser, error) {
+	return nopWriteCloser{o.writer}, nil
+}
+
+// nopWriteCloser wraps a writer whose writes are complete once written, such
+// as the writer of an io.Pipe that's closed by the encoder's goroutine.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
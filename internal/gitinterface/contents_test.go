@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+// benchmarkObjectSizes are the sizes of the synthetic objects signed and
+// verified in benchmarks. The memory allocated for each operation, reported
+// as B/op, doesn't grow with the size of the object, as the contents are
+// streamed.
+var benchmarkObjectSizes = []int64{1 << 20, 256 << 20, 512 << 20}
+
+func TestContentsFromEncoder(t *testing.T) {
+	t.Run("contents are encoded for each reader", func(t *testing.T) {
+		contents := contentsFromEncoder(func(w io.Writer) error {
+			_, err := io.WriteString(w, "test object")
+			return err
+		})
+
+		for i := 0; i < 2; i++ {
+			data, err := readContents(contents)
+			assert.Nil(t, err)
+			assert.Equal(t, "test object", string(data))
+		}
+	})
+
+	t.Run("encoder fails", func(t *testing.T) {
+		encodeErr := errors.New("unable to encode")
+		contents := contentsFromEncoder(func(w io.Writer) error {
+			if _, err := io.WriteString(w, "test"); err != nil {
+				return err
+			}
+			return encodeErr
+		})
+
+		_, err := readContents(contents)
+		assert.ErrorIs(t, err, encodeErr)
+	})
+
+	t.Run("reader closed before contents are read", func(t *testing.T) {
+		encodeErr := make(chan error, 1)
+		contents := contentsFromEncoder(func(w io.Writer) error {
+			for {
+				if _, err := w.Write(make([]byte, 1024)); err != nil {
+					encodeErr <- err
+					return err
+				}
+			}
+		})
+
+		reader, err := contents()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := reader.Read(make([]byte, 1)); err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, reader.Close())
+		assert.ErrorIs(t, <-encodeErr, io.ErrClosedPipe)
+	})
+}
+
+func TestContentsFromGoGitEncoder(t *testing.T) {
+	tag := &object.Tag{
+		Name:       "v1",
+		Tagger:     object.Signature{Name: testName, Email: testEmail, When: testClock.Now()},
+		Message:    "Test tag\n",
+		TargetType: plumbing.CommitObject,
+		Target:     plumbing.NewHash("8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"),
+	}
+
+	data, err := readContents(getTagContents(tag))
+	assert.Nil(t, err)
+	assert.Equal(t, fmt.Sprintf("object 8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f\ntype commit\ntag v1\ntagger %s <%s> %d +0000\n\nTest tag\n", testName, testEmail, testClock.Now().Unix()), string(data))
+}
+
+func TestHashEncodedObject(t *testing.T) {
+	contents := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nTest commit\n"
+
+	objectID, err := hashEncodedObject(plumbing.CommitObject, func(w io.Writer) error {
+		_, err := io.WriteString(w, contents)
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, plumbing.ComputeHash(plumbing.CommitObject, []byte(contents)), objectID)
+}
+
+func BenchmarkSignGitObject(b *testing.B) {
+	for _, size := range benchmarkObjectSizes {
+		contents := syntheticContents(size)
+
+		b.Run(fmt.Sprintf("ssh/%dMiB", size>>20), func(b *testing.B) {
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := signGitObjectUsingSSHKey(contents, artifacts.SSHED25519Private); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("gpg/%dMiB", size>>20), func(b *testing.B) {
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := signGitObjectUsingGPGKey(contents, artifacts.GPGKey1Private); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkVerifySignature(b *testing.B) {
+	sshKey, err := sslibsv.LoadKey(artifacts.SSHED25519Public)
+	if err != nil {
+		b.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(artifacts.GPGKey1Public)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	verifier := NewBatchVerifier(1)
+	for _, size := range benchmarkObjectSizes {
+		contents := syntheticContents(size)
+
+		sshSignature, err := signGitObjectUsingSSHKey(contents, artifacts.SSHED25519Private)
+		if err != nil {
+			b.Fatal(err)
+		}
+		gpgSignature, err := signGitObjectUsingGPGKey(contents, artifacts.GPGKey1Private)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(fmt.Sprintf("ssh/%dMiB", size>>20), func(b *testing.B) {
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := verifier.verifyContents(context.Background(), plumbing.ZeroHash, sshKey, contents, contents, []byte(sshSignature)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("gpg/%dMiB", size>>20), func(b *testing.B) {
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := verifier.verifyContents(context.Background(), plumbing.ZeroHash, gpgKey, contents, contents, []byte(gpgSignature)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetCommitContents(b *testing.B) {
+	for _, size := range benchmarkObjectSizes {
+		// The message is held in memory by the commit, but the contents
+		// encoded from it aren't
+		commit := &object.Commit{
+			Author:    object.Signature{Name: testName, Email: testEmail, When: testClock.Now()},
+			Committer: object.Signature{Name: testName, Email: testEmail, When: testClock.Now()},
+			TreeHash:  EmptyTree(),
+			Message:   strings.Repeat("a", int(size)),
+		}
+		contents := getCommitContents(commit)
+
+		b.Run(fmt.Sprintf("%dMiB", size>>20), func(b *testing.B) {
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				reader, err := contents()
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := io.Copy(io.Discard, reader); err != nil {
+					b.Fatal(err)
+				}
+				reader.Close() //nolint:errcheck
+			}
+		})
+	}
+}
+
+// syntheticContents returns the contents of a synthetic Git object of the
+// specified size, which are generated as they're read rather than held in
+// memory.
+func syntheticContents(size int64) objectContents {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(io.LimitReader(syntheticReader{}, size)), nil
+	}
+}
+
+type syntheticReader struct{}
+
+func (syntheticReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'a' + byte(i%26)
+	}
+	return len(p), nil
+}
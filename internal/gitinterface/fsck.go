@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+var ErrCorruptObject = errors.New("object is corrupt")
+
+// identityPattern matches the identities recorded for authors, committers, and
+// taggers, of the form `Name <email> <unix-timestamp> <timezone>`.
+var identityPattern = regexp.MustCompile(`^[^<>\n]*<[^<>\n]*> [0-9]+ [+-][0-9]{4}$`)
+
+// validTreeEntryModes are the modes Git writes for tree entries.
+var validTreeEntryModes = map[string]bool{
+	"100644": true,
+	"100755": true,
+	"120000": true,
+	"40000":  true,
+	"160000": true,
+}
+
+// CheckObject checks the integrity of the specified object in the repository,
+// similar to `git fsck`. The object's ID must match the hash of its contents,
+// and the headers of commits and tags as well as the entries of trees must be
+// well-formed. This is used to check objects fetched from a remote before
+// they're trusted, as go-git doesn't check objects when reading them.
+func CheckObject(repo *git.Repository, objectID plumbing.Hash) error {
+	obj, err := repo.Storer.EncodedObject(plumbing.AnyObject, objectID)
+	if err != nil {
+		return err
+	}
+
+	reader, err := obj.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close() //nolint:errcheck
+
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("%w: unable to read '%s': %w", ErrCorruptObject, objectID.String(), err)
+	}
+
+	if int64(len(contents)) != obj.Size() {
+		return fmt.Errorf("%w: '%s' has %d bytes, expected %d bytes", ErrCorruptObject, objectID.String(), len(contents), obj.Size())
+	}
+
+	if computedID := plumbing.ComputeHash(obj.Type(), contents); computedID != objectID {
+		return fmt.Errorf("%w: '%s' has contents with ID '%s'", ErrCorruptObject, objectID.String(), computedID.String())
+	}
+
+	switch obj.Type() {
+	case plumbing.CommitObject:
+		err = checkCommitHeaders(contents)
+	case plumbing.TagObject:
+		err = checkTagHeaders(contents)
+	case plumbing.TreeObject:
+		err = checkTreeEntries(contents)
+	case plumbing.BlobObject:
+	default:
+		err = fmt.Errorf("unknown object type '%s'", obj.Type().String())
+	}
+	if err != nil {
+		return fmt.Errorf("%w: '%s': %w", ErrCorruptObject, objectID.String(), err)
+	}
+
+	return nil
+}
+
+// CheckCommitContents checks the integrity of the specified commit, its tree,
+// and every tree and blob reachable from its tree, using CheckObject. The
+// commit's parents and the commits of submodules are not checked.
+func CheckCommitContents(repo *git.Repository, commitID plumbing.Hash) error {
+	if err := CheckObject(repo, commitID); err != nil {
+		return err
+	}
+
+	commit, err := GetCommit(repo, commitID)
+	if err != nil {
+		return err
+	}
+
+	seen := map[plumbing.Hash]bool{}
+	queue := []plumbing.Hash{commit.TreeHash}
+	for len(queue) != 0 {
+		treeID := queue[0]
+		queue = queue[1:]
+
+		if err := CheckObject(repo, treeID); err != nil {
+			return err
+		}
+
+		tree, err := GetTree(repo, treeID)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range tree.Entries {
+			if seen[entry.Hash] {
+				continue
+			}
+			seen[entry.Hash] = true
+
+			switch entry.Mode {
+			case filemode.Dir:
+				queue = append(queue, entry.Hash)
+			case filemode.Submodule:
+				continue
+			default:
+				if err := CheckObject(repo, entry.Hash); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkCommitHeaders checks that the commit starts with its tree, parents,
+// author, and committer headers, in that order.
+func checkCommitHeaders(contents []byte) error {
+	headers, err := getHeaders(contents)
+	if err != nil {
+		return err
+	}
+
+	if len(headers) == 0 || headers[0].key != "tree" || !plumbing.IsHash(headers[0].value) {
+		return errors.New("commit does not start with a valid tree header")
+	}
+
+	index := 1
+	for ; index < len(headers) && headers[index].key == "parent"; index++ {
+		if !plumbing.IsHash(headers[index].value) {
+			return fmt.Errorf("invalid parent '%s'", headers[index].value)
+		}
+	}
+
+	for _, key := range []string{"author", "committer"} {
+		if index >= len(headers) || headers[index].key != key {
+			return fmt.Errorf("commit is missing %s header", key)
+		}
+		if !identityPattern.MatchString(headers[index].value) {
+			return fmt.Errorf("invalid %s '%s'", key, headers[index].value)
+		}
+		index++
+	}
+
+	return nil
+}
+
+// checkTagHeaders checks that the tag starts with its object, type, and tag
+// name headers, followed by an optional tagger header.
+func checkTagHeaders(contents []byte) error {
+	headers, err := getHeaders(contents)
+	if err != nil {
+		return err
+	}
+
+	if len(headers) < 3 || headers[0].key != "object" || headers[1].key != "type" || headers[2].key != "tag" {
+		return errors.New("tag does not start with object, type, and tag headers")
+	}
+	if !plumbing.IsHash(headers[0].value) {
+		return fmt.Errorf("invalid object '%s'", headers[0].value)
+	}
+	if _, err := plumbing.ParseObjectType(headers[1].value); err != nil {
+		return fmt.Errorf("invalid type '%s'", headers[1].value)
+	}
+	if headers[2].value == "" {
+		return errors.New("tag name is empty")
+	}
+	if len(headers) > 3 && headers[3].key == "tagger" && !identityPattern.MatchString(headers[3].value) {
+		return fmt.Errorf("invalid tagger '%s'", headers[3].value)
+	}
+
+	return nil
+}
+
+// checkTreeEntries checks that the tree's entries have valid modes, names, and
+// object IDs, and that no two entries have the same name.
+func checkTreeEntries(contents []byte) error {
+	hashSize := len(plumbing.ZeroHash)
+
+	names := map[string]bool{}
+	for len(contents) != 0 {
+		mode, rest, found := bytes.Cut(contents, []byte(" "))
+		if !found {
+			return errors.New("truncated tree entry")
+		}
+		name, rest, found := bytes.Cut(rest, []byte{0})
+		if !found || len(rest) < hashSize {
+			return errors.New("truncated tree entry")
+		}
+		contents = rest[hashSize:]
+
+		if !validTreeEntryModes[string(mode)] {
+			return fmt.Errorf("invalid mode '%s' for entry '%s'", string(mode), string(name))
+		}
+
+		entryName := string(name)
+		switch {
+		case entryName == "", entryName == ".", entryName == "..", strings.EqualFold(entryName, ".git"), strings.Contains(entryName, "/"):
+			return fmt.Errorf("invalid entry name '%s'", entryName)
+		}
+
+		if names[entryName] {
+			return fmt.Errorf("duplicate entry '%s'", entryName)
+		}
+		names[entryName] = true
+	}
+
+	return nil
+}
+
+type objectHeader struct {
+	key   string
+	value string
+}
+
+// getHeaders returns the headers of a commit or tag, which precede the first
+// empty line. Continuation lines of multiline headers, which start with a
+// space, are not returned.
+func getHeaders(contents []byte) ([]objectHeader, error) {
+	headerBlock, _, _ := bytes.Cut(contents, []byte("\n\n"))
+
+	headers := []objectHeader{}
+	for _, line := range strings.Split(strings.TrimSuffix(string(headerBlock), "\n"), "\n") {
+		if strings.HasPrefix(line, " ") {
+			if len(headers) == 0 {
+				return nil, errors.New("object starts with a continuation line")
+			}
+			continue
+		}
+
+		key, value, found := strings.Cut(line, " ")
+		if !found || key == "" {
+			return nil, fmt.Errorf("malformed header '%s'", line)
+		}
+		headers = append(headers, objectHeader{key: key, value: value})
+	}
+
+	return headers, nil
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"testing"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckObject(t *testing.T) {
+	storage := memory.NewStorage()
+	repo, err := git.Init(storage, memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeObject := func(objectType plumbing.ObjectType, contents string) plumbing.Hash {
+		t.Helper()
+
+		obj := storage.NewEncodedObject()
+		obj.SetType(objectType)
+		writer, err := obj.Writer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+		objectID, err := storage.SetEncodedObject(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return objectID
+	}
+
+	blobID, err := WriteBlob(repo, []byte("gittuf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeID, err := WriteTree(repo, []object.TreeEntry{{Name: "file", Mode: filemode.Regular, Hash: blobID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTreeID, err := WriteTree(repo, []object.TreeEntry{
+		{Name: "dir", Mode: filemode.Dir, Hash: treeID},
+		{Name: "file.txt", Mode: filemode.Regular, Hash: blobID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, rootTreeID, []plumbing.Hash{plumbing.ZeroHash}, "Test commit", testClock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("valid objects", func(t *testing.T) {
+		for _, objectID := range []plumbing.Hash{blobID, treeID, rootTreeID, commitID} {
+			assert.Nil(t, CheckObject(repo, objectID))
+		}
+
+		tagID := writeObject(plumbing.TagObject, "object "+commitID.String()+"\ntype commit\ntag v1\ntagger Jane Doe <jane.doe@example.com> 1257894000 +0000\n\nRelease v1\n")
+		assert.Nil(t, CheckObject(repo, tagID))
+
+		assert.Nil(t, CheckCommitContents(repo, commitID))
+	})
+
+	t.Run("missing object", func(t *testing.T) {
+		err := CheckObject(repo, plumbing.NewHash("0123456789abcdef0123456789abcdef01234567"))
+		assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+	})
+
+	t.Run("object ID does not match contents", func(t *testing.T) {
+		tamperedBlobID := writeObject(plumbing.BlobObject, "tampered")
+		storage.Objects[blobID], storage.Objects[tamperedBlobID] = storage.Objects[tamperedBlobID], storage.Objects[blobID]
+		defer func() {
+			storage.Objects[blobID], storage.Objects[tamperedBlobID] = storage.Objects[tamperedBlobID], storage.Objects[blobID]
+		}()
+
+		err := CheckObject(repo, blobID)
+		assert.ErrorIs(t, err, ErrCorruptObject)
+
+		// The blob is reachable from the commit's tree
+		err = CheckCommitContents(repo, commitID)
+		assert.ErrorIs(t, err, ErrCorruptObject)
+	})
+
+	t.Run("malformed commits", func(t *testing.T) {
+		identity := "Jane Doe <jane.doe@example.com> 1257894000 +0000"
+		tests := map[string]string{
+			"no tree":          "author " + identity + "\ncommitter " + identity + "\n\nTest commit\n",
+			"invalid tree":     "tree 1234\nauthor " + identity + "\ncommitter " + identity + "\n\nTest commit\n",
+			"invalid parent":   "tree " + rootTreeID.String() + "\nparent 1234\nauthor " + identity + "\ncommitter " + identity + "\n\nTest commit\n",
+			"no committer":     "tree " + rootTreeID.String() + "\nauthor " + identity + "\n\nTest commit\n",
+			"invalid author":   "tree " + rootTreeID.String() + "\nauthor Jane Doe 1257894000 +0000\ncommitter " + identity + "\n\nTest commit\n",
+			"headers reversed": "tree " + rootTreeID.String() + "\ncommitter " + identity + "\nauthor " + identity + "\n\nTest commit\n",
+		}
+
+		for name, contents := range tests {
+			err := CheckObject(repo, writeObject(plumbing.CommitObject, contents))
+			assert.ErrorIs(t, err, ErrCorruptObject, name)
+		}
+	})
+
+	t.Run("malformed tags", func(t *testing.T) {
+		tests := map[string]string{
+			"no object":    "type commit\ntag v1\n\nRelease v1\n",
+			"invalid type": "object " + commitID.String() + "\ntype unknown\ntag v1\n\nRelease v1\n",
+			"empty name":   "object " + commitID.String() + "\ntype commit\ntag \n\nRelease v1\n",
+		}
+
+		for name, contents := range tests {
+			err := CheckObject(repo, writeObject(plumbing.TagObject, contents))
+			assert.ErrorIs(t, err, ErrCorruptObject, name)
+		}
+	})
+
+	t.Run("malformed trees", func(t *testing.T) {
+		entry := func(mode, name string) string {
+			return mode + " " + name + "\x00" + string(blobID[:])
+		}
+
+		tests := map[string]string{
+			"duplicate":      entry("100644", "a") + entry("40000", "a"),
+			"dot git":        entry("100644", ".GIT"),
+			"parent":         entry("40000", ".."),
+			"slash":          entry("100644", "a/b"),
+			"invalid mode":   entry("100664", "a"),
+			"truncated hash": entry("100644", "a")[:10],
+		}
+
+		for name, contents := range tests {
+			err := CheckObject(repo, writeObject(plumbing.TreeObject, contents))
+			assert.ErrorIs(t, err, ErrCorruptObject, name)
+		}
+	})
+}
@@ -30,15 +30,16 @@ func (h Hash) String() string {
 	return hex.EncodeToString(h)
 }
 
-// IsZero compares the hash to see if it's the zero hash for either SHA-1 or
-// SHA-256.
+// IsZero compares the hash to see if it's empty or the zero hash for either
+// SHA-1 or SHA-256.
 func (h Hash) IsZero() bool {
-	return bytes.Equal(h, zeroSHA1HashBytes[:]) || bytes.Equal(h, zeroSHA256HashBytes[:])
+	return len(h) == 0 || bytes.Equal(h, zeroSHA1HashBytes[:]) || bytes.Equal(h, zeroSHA256HashBytes[:])
 }
 
-// ZeroHash represents an empty Hash.
-// TODO: use SHA-256 zero hash for repositories that have that as the default.
-var ZeroHash = Hash(zeroSHA1HashBytes[:])
+// ZeroHash represents an empty Hash, which is treated as the zero hash of every
+// object format. Use Repository.ZeroHash for the zero hash of a repository's
+// object format.
+var ZeroHash = Hash{}
 
 // NewHash returns a Hash object after ensuring the input string is correctly
 // encoded.
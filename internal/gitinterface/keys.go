@@ -5,38 +5,196 @@ package gitinterface
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/pem"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/gittuf/gittuf/internal/timing"
 	"github.com/hiddeco/sshsig"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/gittuf/gittuf/internal/identity"
+	"github.com/gittuf/gittuf/internal/interactive"
+	"github.com/gittuf/gittuf/internal/passphrase"
+	"github.com/gittuf/gittuf/internal/rekor"
 	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpgagent"
+	"github.com/gittuf/gittuf/internal/signerverifier/kms"
+	"github.com/gittuf/gittuf/internal/signerverifier/sigstore"
+	"github.com/gittuf/gittuf/internal/signerverifier/smime"
+	"github.com/gittuf/gittuf/internal/signerverifier/sshagent"
 	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	gitsignVerifier "github.com/sigstore/gitsign/pkg/git"
 	gitsignRekor "github.com/sigstore/gitsign/pkg/rekor"
 	"github.com/sigstore/sigstore/pkg/fulcioroots"
+	sigstoretuf "github.com/sigstore/sigstore/pkg/tuf"
 	"golang.org/x/crypto/ssh"
 )
 
 var (
 	ErrSigningKeyNotSpecified     = errors.New("signing key not specified in git config")
+	ErrSigningKeyUnreadable       = errors.New("unable to read signing key specified in git config")
 	ErrUnknownSigningMethod       = errors.New("unknown signing method (not one of gpg, ssh, x509)")
 	ErrUnableToSign               = errors.New("unable to sign Git object")
 	ErrIncorrectVerificationKey   = errors.New("incorrect key provided to verify signature")
 	ErrVerifyingSigstoreSignature = errors.New("unable to verify Sigstore signature")
 	ErrVerifyingSSHSignature      = errors.New("unable to verify SSH signature")
+	ErrVerifyingSMIMESignature    = errors.New("unable to verify S/MIME signature")
+	ErrSMIMETrustStoreNotSet      = errors.New("no S/MIME trust store set, use --smime-trust-store or the smime_trust_store setting")
 	ErrInvalidSignature           = errors.New("unable to parse signature / signature has unexpected header")
+	ErrGitsignRequiresLogin       = errors.New("gitsign requires an interactive login as no ambient OIDC credentials were found")
+	ErrSSHDefaultKeyCommandFailed = errors.New("unable to find SSH signing key using gpg.ssh.defaultKeyCommand")
+	ErrUnknownSigningBackend      = errors.New("unknown signing backend")
 )
 
 type SigningMethod int
 
+// SigningOptions configure how SignGitObject signs a Git object. Options that
+// aren't set fall back to the overrides set for gittuf using SetSigningFormat,
+// SetSigningKey, and SetSigningProgram, and then to the user's Git config.
+type SigningOptions struct {
+	// Format is the format of the signature, one of "gpg", "ssh", or "x509",
+	// like Git's gpg.format option.
+	Format string
+
+	// Key is the signing key, like Git's user.signingKey option. Keys held in
+	// the SSH agent or by gpg-agent may be selected using the "ssh-agent:"
+	// and "gpg-agent:" prefixes.
+	Key string
+
+	// Program is the signing program, like Git's gpg.program option and its
+	// per-format variants.
+	Program string
+
+	// PrivateKey is a PEM encoded GPG or SSH private key that's used to sign
+	// without invoking a signing program. If it's set, the other options are
+	// ignored.
+	PrivateKey []byte
+}
+
+// These override the signing options in Git's config for gittuf if set. They
+// are set using SetSigningFormat, SetSigningKey, and SetSigningProgram.
+var (
+	signingFormatOverride  = ""
+	signingKeyOverride     = ""
+	signingProgramOverride = ""
+)
+
+// These are the backends Git objects created by gittuf can be signed with. The
+// backend is set using SetSigningBackend or Git's gittuf.signing option.
+const (
+	// SigningBackendGit signs using the signing method, key, and program set
+	// in Git's config and the overrides set for gittuf. This is the default.
+	SigningBackendGit = "git"
+
+	// SigningBackendSigstore signs without a key using the CI environment's
+	// OIDC identity, with a certificate issued by the Fulcio instance of the
+	// configured Sigstore instance, and logs the signature to its Rekor
+	// instance. Sigstore's public instance is used by default.
+	SigningBackendSigstore = "sigstore"
+
+	signingBackendConfigKey = "gittuf.signing"
+)
+
+// signingBackendOverride overrides Git's gittuf.signing option if set. It's set
+// using SetSigningBackend.
+var signingBackendOverride = ""
+
+// These cache the signer used by the Sigstore signing backend, so that the
+// Git objects created by a command are signed using the same certificate.
+var (
+	sigstoreSigner          *sigstore.Signer
+	sigstoreSignerCreatedAt time.Time
+	sigstoreSignerMu        sync.Mutex
+)
+
+// sigstoreSignerLifetime is how long the signer used by the Sigstore signing
+// backend is reused. It's shorter than the validity of Fulcio's certificates.
+const sigstoreSignerLifetime = 5 * time.Minute
+
+// These configure offline verification of Sigstore signatures. They are set
+// using SetSigstoreTrustedRoot, and the trusted root is loaded when it's first
+// needed.
+var (
+	sigstoreOffline         = false
+	sigstoreTrustedRootPath = ""
+	sigstoreTrustedRoot     *sigstore.TrustedRoot
+	sigstoreTrustedRootMu   sync.Mutex
+)
+
+// SigstoreInstance identifies the Sigstore services used to sign Git objects
+// using the Sigstore signing backend and to verify gitsign signatures online,
+// such as those of a private Sigstore deployment. Sigstore's public instance is
+// used for the options that aren't set.
+type SigstoreInstance struct {
+	// RekorURL is the URL of the Rekor instance signatures are logged to and
+	// looked up in.
+	RekorURL string
+
+	// FulcioURL is the URL of the Fulcio instance that issues signing
+	// certificates.
+	FulcioURL string
+
+	// FulcioRoots is the path to a PEM file with the root and intermediate
+	// certificates of the Fulcio instance. If it isn't set, the certificates
+	// are obtained from Sigstore's TUF repository.
+	FulcioRoots string
+
+	// TUFMirror is the URL of the TUF repository that distributes the
+	// instance's trusted root, i.e. Fulcio's certificates and the keys of
+	// Rekor and the certificate transparency log.
+	TUFMirror string
+
+	// TUFRoot is the path to the initial root.json of the TUF repository at
+	// TUFMirror. If it isn't set, the root.json of Sigstore's public TUF
+	// repository is used.
+	TUFRoot string
+}
+
+// These are the Git config options that configure the Sigstore instance used
+// by gittuf. Git normalizes the variable names to lowercase, so the options
+// can be set as gittuf.rekorURL, gittuf.fulcioURL, gittuf.fulcioRoots,
+// gittuf.sigstoreTUFMirror, and gittuf.sigstoreTUFRoot.
+const (
+	sigstoreRekorURLConfigKey    = "gittuf.rekorurl"
+	sigstoreFulcioURLConfigKey   = "gittuf.fulciourl"
+	sigstoreFulcioRootsConfigKey = "gittuf.fulcioroots"
+	sigstoreTUFMirrorConfigKey   = "gittuf.sigstoretufmirror"
+	sigstoreTUFRootConfigKey     = "gittuf.sigstoretufroot"
+)
+
+// sigstoreInstanceOverride overrides the Sigstore instance options in Git's
+// config for gittuf. It's set using SetSigstoreInstance.
+var sigstoreInstanceOverride = SigstoreInstance{}
+
+// sigstoreTUFInitialized records that Sigstore's TUF client was pointed at the
+// configured TUF mirror, which is only done once.
+var (
+	sigstoreTUFInitialized bool
+	sigstoreTUFMu          sync.Mutex
+)
+
+// These configure the trust store used to verify S/MIME signatures. The path is
+// set using SetSMIMETrustStore, and the trust store is loaded when it's first
+// needed.
+var (
+	smimeTrustStorePath = ""
+	smimeTrustStore     *x509.CertPool
+	smimeTrustStoreMu   sync.Mutex
+)
+
 const (
 	SigningMethodGPG SigningMethod = iota
 	SigningMethodSSH
@@ -47,6 +205,29 @@ const (
 	DefaultSigningProgramGPG  string = "gpg"
 	DefaultSigningProgramSSH  string = "ssh-keygen"
 	DefaultSigningProgramX509 string = "gpgsm"
+
+	// SigningProgramSSHAgent is reported as the signing program when the
+	// SSH signing key is held in the SSH agent and selected using the
+	// "ssh-agent:" prefix. gittuf signs using the agent directly, so no
+	// program is invoked.
+	SigningProgramSSHAgent string = "ssh-agent"
+
+	// SigningProgramGPGAgent is reported as the signing program when the
+	// GPG signing key is held by gpg-agent and selected using the
+	// "gpg-agent:" prefix. gittuf signs using the agent directly, so gpg is
+	// not invoked.
+	SigningProgramGPGAgent string = "gpg-agent"
+
+	// SigningProgramKMS is reported as the signing program when the SSH
+	// signing key is held in a key management service and selected using
+	// its URI, such as "awskms:///alias/gittuf". gittuf signs using the
+	// service directly, so no program is invoked.
+	SigningProgramKMS string = "kms"
+
+	// SigningProgramSigstore is reported as the signing program when the
+	// Sigstore signing backend is used. gittuf requests a certificate from
+	// Fulcio directly, so gitsign is not invoked.
+	SigningProgramSigstore string = "sigstore"
 )
 
 const (
@@ -55,37 +236,92 @@ const (
 	opensshPrivateKeyPEMHeader string = "OPENSSH PRIVATE KEY"
 	rsaPrivateKeyPEMHeader     string = "RSA PRIVATE KEY"
 	genericPrivateKeyPEMHeader string = "PRIVATE KEY"
+
+	gpgSignatureHeader  string = "-----BEGIN PGP SIGNATURE-----"
+	sshSignatureHeader  string = "-----BEGIN SSH SIGNATURE-----"
+	x509SignatureHeader string = "-----BEGIN SIGNED MESSAGE-----"
 )
 
+// GetSigningCommand returns the program and arguments used to sign Git
+// objects. In non-interactive mode, the program is invoked so that it doesn't
+// prompt for a passphrase, and signing using gitsign fails early if it would
+// require an interactive login.
 func GetSigningCommand() (string, []string, error) {
+	return getSigningCommand(SigningOptions{})
+}
+
+func getSigningCommand(opts SigningOptions) (string, []string, error) {
 	var args []string
 
-	signingMethod, keyInfo, program, err := getSigningInfo()
+	signingMethod, keyInfo, program, err := getSigningInfoWithOptions(opts)
 	if err != nil {
 		return "", nil, err
 	}
 
 	switch signingMethod {
 	case SigningMethodGPG:
+		if gpgagent.IsAgentKey(keyInfo) {
+			return SigningProgramGPGAgent, nil, nil
+		}
+
+		if interactive.InNonInteractiveMode() {
+			// Fail instead of asking for the passphrase
+			args = []string{"--batch", "--pinentry-mode", "error"}
+		}
+
 		if len(keyInfo) == 0 {
-			args = []string{
+			args = append(args,
 				"-bsa", // b -> detach-sign, s -> sign, a -> armor
-			}
+			)
 		} else {
-			args = []string{
+			args = append(args,
 				"-bsau", keyInfo, // b -> detach-sign, s -> sign, a -> armor, u -> local-user
-			}
+			)
 		}
 	case SigningMethodSSH:
 		if len(keyInfo) == 0 {
 			return "", nil, ErrSigningKeyNotSpecified
 		}
+
+		if sshagent.IsAgentKey(keyInfo) {
+			return SigningProgramSSHAgent, nil, nil
+		}
+
+		if kms.IsKMSKey(keyInfo) {
+			return SigningProgramKMS, nil, nil
+		}
+
 		args = []string{
 			"-Y", "sign",
 			"-n", "git", // Git namespace
-			"-f", keyInfo,
+		}
+		if _, isLiteral := getLiteralSSHKey(keyInfo); isLiteral {
+			// The private key is in the SSH agent, signGitObject writes the
+			// public key to a file when signing
+			args = append(args, "-U", "-f", keyInfo)
+		} else {
+			// ssh-keygen is not invoked using a shell, so the path is
+			// expanded like Git does
+			keyInfo, err = ExpandHomeDir(keyInfo)
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, "-f", keyInfo)
 		}
 	case SigningMethodX509:
+		if program == SigningProgramSigstore {
+			if !sigstore.HasAmbientCredentials() {
+				return "", nil, sigstore.ErrNoAmbientCredentials
+			}
+
+			return SigningProgramSigstore, nil, nil
+		}
+
+		if interactive.InNonInteractiveMode() && IsGitsign(program) && !sigstore.HasAmbientCredentials() {
+			// gitsign would open a browser to log in
+			return "", nil, errors.Join(interactive.ErrInteractionRequired, ErrGitsignRequiresLogin)
+		}
+
 		if len(keyInfo) == 0 {
 			args = []string{
 				"-bsa", // b -> detach-sign, s -> sign, a -> armor
@@ -102,262 +338,1358 @@ func GetSigningCommand() (string, []string, error) {
 	return program, args, nil
 }
 
-func getSigningInfo() (SigningMethod, string, string, error) {
-	gitConfig, err := getConfig()
+// CheckSigningKey checks that the SSH key configured for signing in Git can be
+// read, or, for keys selected using the "ssh-agent:" prefix, that the key is
+// in the SSH agent. For keys held in a key management service, it checks that
+// the key's public key can be fetched. For GPG keys selected using the "gpg-agent:" prefix, it
+// checks that gpg-agent holds the key. Other GPG keys and X.509 keys are
+// managed by their signing programs and are not checked.
+func CheckSigningKey() error {
+	signingMethod, keyInfo, _, err := getSigningInfo()
 	if err != nil {
-		return -1, "", "", err
+		return err
 	}
 
-	signingMethod, err := getSigningMethod(gitConfig)
-	if err != nil {
-		return -1, "", "", err
+	if signingMethod == SigningMethodGPG && gpgagent.IsAgentKey(keyInfo) {
+		return gpgagent.Check(strings.TrimPrefix(keyInfo, gpgagent.KeyPrefix))
 	}
 
-	keyInfo := getSigningKeyInfo(gitConfig)
-
-	program := getSigningProgram(gitConfig, signingMethod)
+	if signingMethod != SigningMethodSSH {
+		return nil
+	}
 
-	return signingMethod, keyInfo, program, nil
-}
+	if len(keyInfo) == 0 {
+		return ErrSigningKeyNotSpecified
+	}
+	if sshagent.IsAgentKey(keyInfo) {
+		return sshagent.WithSigner(strings.TrimPrefix(keyInfo, sshagent.KeyPrefix), func(ssh.Signer) error { return nil })
+	}
+	if kms.IsKMSKey(keyInfo) {
+		_, err := kms.NewSigner(context.Background(), keyInfo)
+		return err
+	}
+	if _, isLiteral := getLiteralSSHKey(keyInfo); isLiteral {
+		// The public key is specified literally, the private key is expected
+		// to be in the SSH agent
+		return nil
+	}
 
-func getSigningMethod(gitConfig map[string]string) (SigningMethod, error) {
-	format, ok := gitConfig["gpg.format"]
-	if !ok {
-		return SigningMethodGPG, nil
+	keyPath, err := ExpandHomeDir(keyInfo)
+	if err != nil {
+		return errors.Join(ErrSigningKeyUnreadable, err)
 	}
 
-	switch format {
-	case "gpg":
-		return SigningMethodGPG, nil
-	case "ssh":
-		return SigningMethodSSH, nil
-	case "x509":
-		return SigningMethodX509, nil
+	if _, err := os.ReadFile(keyPath); err != nil {
+		return errors.Join(ErrSigningKeyUnreadable, err)
 	}
-	return -1, ErrUnknownSigningMethod
+
+	return nil
 }
 
-func getSigningKeyInfo(gitConfig map[string]string) string {
-	keyInfo, ok := gitConfig["user.signingkey"]
-	if !ok {
-		return ""
+// ExpandHomeDir expands a leading "~" in the path to the user's home
+// directory, like Git does for paths in its config such as user.signingKey.
+// On Windows, "~\" is also expanded. Other paths are returned unchanged.
+func ExpandHomeDir(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !(runtime.GOOS == "windows" && strings.HasPrefix(path, `~\`)) {
+		return path, nil
 	}
-	return keyInfo
-}
 
-func getSigningProgram(gitConfig map[string]string, signingMethod SigningMethod) string {
-	switch signingMethod {
-	case SigningMethodSSH:
-		program, ok := gitConfig["gpg.ssh.program"]
-		if ok {
-			return program
-		}
-		return DefaultSigningProgramSSH
-	case SigningMethodX509:
-		program, ok := gitConfig["gpg.x509.program"]
-		if ok {
-			return program
-		}
-		return DefaultSigningProgramX509
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
 	}
 
-	// Default to GPG
-	program, ok := gitConfig["gpg.program"]
-	if ok {
-		return program
+	return filepath.Join(homeDir, path[1:]), nil
+}
+
+// IsBuiltInSigningProgram returns true if the signing program reported by
+// GetSigningCommand is implemented by gittuf, rather than a program that's
+// invoked.
+func IsBuiltInSigningProgram(program string) bool {
+	switch program {
+	case SigningProgramSSHAgent, SigningProgramGPGAgent, SigningProgramKMS, SigningProgramSigstore:
+		return true
 	}
 
-	return DefaultSigningProgramGPG
+	return false
 }
 
-// signGitObject signs a Git commit or tag using the user's configured Git
-// config.
-func signGitObject(contents []byte) (string, error) {
-	command, args, err := GetSigningCommand()
-	if err != nil {
-		return "", err
+// IsGitsign returns true if the signing program is gitsign.
+func IsGitsign(program string) bool {
+	name := filepath.Base(program)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(strings.ToLower(name), ".exe")
 	}
 
-	cmd := exec.Command(command, args...)
+	return name == "gitsign"
+}
+
+// GetSigningInfo returns the signing method, the signing key, and the signing
+// program used to sign Git objects created by gittuf, taking into account the
+// overrides set for gittuf.
+func GetSigningInfo() (SigningMethod, string, string, error) {
+	return getSigningInfo()
+}
 
-	stdInWriter, err := cmd.StdinPipe()
+func getSigningInfo() (SigningMethod, string, string, error) {
+	return getSigningInfoWithOptions(SigningOptions{})
+}
+
+// getSigningInfoWithOptions returns the signing method, the signing key, and
+// the signing program, preferring the options to the overrides set for gittuf
+// and to the user's Git config. If the Sigstore signing backend is used, the
+// signing method is SigningMethodX509 and the program is
+// SigningProgramSigstore.
+func getSigningInfoWithOptions(opts SigningOptions) (SigningMethod, string, string, error) {
+	gitConfig, err := getConfig()
 	if err != nil {
-		return "", err
+		return -1, "", "", err
 	}
 
-	stdOutReader, err := cmd.StdoutPipe()
+	signingBackend, err := getSigningBackend(gitConfig)
 	if err != nil {
-		return "", err
+		return -1, "", "", err
 	}
-	defer stdOutReader.Close()
+	if signingBackend == SigningBackendSigstore {
+		return SigningMethodX509, "", SigningProgramSigstore, nil
+	}
+
+	opts = opts.withOverrides()
 
-	stdErrReader, err := cmd.StderrPipe()
+	signingMethod, err := getSigningMethod(gitConfig, opts)
 	if err != nil {
-		return "", err
+		return -1, "", "", err
 	}
-	defer stdErrReader.Close()
 
-	if err = cmd.Start(); err != nil {
-		return "", err
+	keyInfo := getSigningKeyInfo(gitConfig, opts)
+	if keyInfo == "" && signingMethod == SigningMethodSSH {
+		keyInfo, err = getDefaultSSHSigningKey(gitConfig)
+		if err != nil {
+			return -1, "", "", err
+		}
 	}
 
-	if _, err := stdInWriter.Write(contents); err != nil {
-		return "", err
-	}
-	if err := stdInWriter.Close(); err != nil {
-		return "", err
+	program, err := normalizeSigningProgram(getSigningProgram(gitConfig, signingMethod, opts))
+	if err != nil {
+		return -1, "", "", err
 	}
 
-	sig, err := io.ReadAll(stdOutReader)
-	if err != nil {
-		return "", err
+	return signingMethod, keyInfo, program, nil
+}
+
+// SetSigningBackend sets the backend used to sign Git objects created by
+// gittuf, one of "git" or "sigstore", overriding Git's gittuf.signing option.
+func SetSigningBackend(backend string) error {
+	if err := checkSigningBackend(backend); err != nil {
+		return err
 	}
 
-	e, err := io.ReadAll(stdErrReader)
+	signingBackendOverride = backend
+	return nil
+}
+
+// GetSigningBackend returns the backend used to sign Git objects created by
+// gittuf, taking into account the override set using SetSigningBackend.
+func GetSigningBackend() (string, error) {
+	gitConfig, err := getConfig()
 	if err != nil {
 		return "", err
 	}
 
-	if len(e) > 0 {
-		fmt.Fprint(os.Stderr, string(e))
+	return getSigningBackend(gitConfig)
+}
+
+func getSigningBackend(gitConfig map[string]string) (string, error) {
+	if signingBackendOverride != "" {
+		return signingBackendOverride, nil
 	}
 
-	if err = cmd.Wait(); err != nil {
-		return "", err
+	backend, ok := gitConfig[signingBackendConfigKey]
+	if !ok {
+		return SigningBackendGit, nil
 	}
 
-	if len(sig) == 0 {
-		return "", ErrUnableToSign
+	if err := checkSigningBackend(backend); err != nil {
+		return "", err
 	}
 
-	return string(sig), nil
+	return backend, nil
 }
 
-func signGitObjectUsingKey(contents, pemKeyBytes []byte) (string, error) {
-	block, _ := pem.Decode(pemKeyBytes)
-	if block == nil {
-		// openpgp implements its own armor-decode method, pem.Decode considers
-		// the input invalid. We haven't tested if this is universal, so in case
-		// pem.Decode does succeed on a GPG key, we catch it below.
-		return signGitObjectUsingGPGKey(contents, pemKeyBytes)
+func checkSigningBackend(backend string) error {
+	switch backend {
+	case SigningBackendGit, SigningBackendSigstore:
+		return nil
 	}
 
-	switch block.Type {
-	case gpgPrivateKeyPEMHeader:
-		return signGitObjectUsingGPGKey(contents, pemKeyBytes)
-	case opensshPrivateKeyPEMHeader, rsaPrivateKeyPEMHeader, genericPrivateKeyPEMHeader:
-		return signGitObjectUsingSSHKey(contents, pemKeyBytes)
+	return fmt.Errorf("%w: '%s' (not one of %s, %s)", ErrUnknownSigningBackend, backend, SigningBackendGit, SigningBackendSigstore)
+}
+
+// SetSigningFormat sets the format of signatures created by gittuf, one of
+// "gpg", "ssh", or "x509", overriding Git's gpg.format option.
+func SetSigningFormat(format string) error {
+	if _, err := parseSigningFormat(format); err != nil {
+		return err
 	}
 
-	return "", ErrUnknownSigningMethod
+	signingFormatOverride = format
+	return nil
 }
 
-func signGitObjectUsingGPGKey(contents, pemKeyBytes []byte) (string, error) {
-	reader := bytes.NewReader(contents)
+// SetSigningKey sets the key used to sign Git objects created by gittuf,
+// overriding Git's user.signingKey option.
+func SetSigningKey(keyInfo string) {
+	signingKeyOverride = keyInfo
+}
 
-	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(pemKeyBytes))
-	if err != nil {
-		return "", err
-	}
+// SetSigningProgram sets the program used to sign Git objects created by
+// gittuf, overriding Git's gpg.program option and its per-format variants.
+func SetSigningProgram(program string) {
+	signingProgramOverride = program
+}
 
-	sig := new(strings.Builder)
-	if err := openpgp.ArmoredDetachSign(sig, keyring[0], reader, nil); err != nil {
-		return "", err
-	}
+// SetSigstoreTrustedRoot configures gittuf to verify Sigstore signatures
+// offline, using the Sigstore trusted root at the specified path rather than
+// contacting Sigstore's TUF repository and Rekor instance. If the path is
+// empty, the trusted root cached by Sigstore's TUF client is used.
+func SetSigstoreTrustedRoot(path string) {
+	sigstoreTrustedRootMu.Lock()
+	defer sigstoreTrustedRootMu.Unlock()
 
-	return sig.String(), nil
+	sigstoreOffline = true
+	sigstoreTrustedRootPath = path
+	sigstoreTrustedRoot = nil
 }
 
-func signGitObjectUsingSSHKey(contents, pemKeyBytes []byte) (string, error) {
-	signer, err := ssh.ParsePrivateKey(pemKeyBytes)
-	if err != nil {
-		return "", err
-	}
+// getSigstoreTrustedRoot returns the Sigstore trusted root used to verify
+// Sigstore signatures offline. It returns nil if Sigstore signatures are
+// verified online.
+func getSigstoreTrustedRoot() (*sigstore.TrustedRoot, error) {
+	sigstoreTrustedRootMu.Lock()
+	defer sigstoreTrustedRootMu.Unlock()
 
-	sshSig, err := sshsig.Sign(bytes.NewReader(contents), signer, sshsig.HashSHA512, namespaceSSHSignature)
-	if err != nil {
-		return "", err
+	if !sigstoreOffline {
+		return nil, nil
 	}
 
-	sigBytes := sshsig.Armor(sshSig)
+	if sigstoreTrustedRoot == nil {
+		trustedRoot, err := sigstore.LoadTrustedRoot(sigstoreTrustedRootPath)
+		if err != nil {
+			return nil, err
+		}
+		sigstoreTrustedRoot = trustedRoot
+	}
 
-	return string(sigBytes), nil
+	return sigstoreTrustedRoot, nil
 }
 
-// verifyGitsignSignature handles the Sigstore-specific workflow involved in
-// verifying commit or tag signatures issued by gitsign.
-func verifyGitsignSignature(ctx context.Context, key *tuf.Key, data, signature []byte) error {
-	root, err := fulcioroots.Get()
-	if err != nil {
-		return errors.Join(ErrVerifyingSigstoreSignature, err)
-	}
-	intermediate, err := fulcioroots.GetIntermediates()
-	if err != nil {
-		return errors.Join(ErrVerifyingSigstoreSignature, err)
+// SetSigstoreInstance sets the Sigstore instance used by gittuf, overriding
+// Git's gittuf.rekorURL, gittuf.fulcioURL, gittuf.fulcioRoots,
+// gittuf.sigstoreTUFMirror, and gittuf.sigstoreTUFRoot options. Options that
+// aren't set in instance keep their current values.
+func SetSigstoreInstance(instance SigstoreInstance) {
+	sigstoreInstanceOverride = SigstoreInstance{
+		RekorURL:    firstNonEmpty(instance.RekorURL, sigstoreInstanceOverride.RekorURL),
+		FulcioURL:   firstNonEmpty(instance.FulcioURL, sigstoreInstanceOverride.FulcioURL),
+		FulcioRoots: firstNonEmpty(instance.FulcioRoots, sigstoreInstanceOverride.FulcioRoots),
+		TUFMirror:   firstNonEmpty(instance.TUFMirror, sigstoreInstanceOverride.TUFMirror),
+		TUFRoot:     firstNonEmpty(instance.TUFRoot, sigstoreInstanceOverride.TUFRoot),
 	}
+}
 
-	verifier, err := gitsignVerifier.NewCertVerifier(
-		gitsignVerifier.WithRootPool(root),
-		gitsignVerifier.WithIntermediatePool(intermediate),
-	)
+// GetSigstoreInstance returns the Sigstore instance used by gittuf, taking into
+// account the overrides set using SetSigstoreInstance. The URLs of Sigstore's
+// public Rekor and Fulcio instances are used if they aren't configured.
+func GetSigstoreInstance() (*SigstoreInstance, error) {
+	gitConfig, err := getConfig()
 	if err != nil {
-		return errors.Join(ErrVerifyingSigstoreSignature, err)
+		return nil, err
 	}
 
-	verifiedCert, err := verifier.Verify(ctx, data, signature, true)
-	if err != nil {
-		return ErrIncorrectVerificationKey
-	}
+	return getSigstoreInstance(gitConfig), nil
+}
 
-	rekor, err := gitsignRekor.NewWithOptions(ctx, signerverifier.RekorServer)
-	if err != nil {
-		return errors.Join(ErrVerifyingSigstoreSignature, err)
+func getSigstoreInstance(gitConfig map[string]string) *SigstoreInstance {
+	return &SigstoreInstance{
+		RekorURL:    firstNonEmpty(sigstoreInstanceOverride.RekorURL, gitConfig[sigstoreRekorURLConfigKey], signerverifier.RekorServer),
+		FulcioURL:   firstNonEmpty(sigstoreInstanceOverride.FulcioURL, gitConfig[sigstoreFulcioURLConfigKey], sigstore.FulcioServer),
+		FulcioRoots: firstNonEmpty(sigstoreInstanceOverride.FulcioRoots, gitConfig[sigstoreFulcioRootsConfigKey]),
+		TUFMirror:   firstNonEmpty(sigstoreInstanceOverride.TUFMirror, gitConfig[sigstoreTUFMirrorConfigKey]),
+		TUFRoot:     firstNonEmpty(sigstoreInstanceOverride.TUFRoot, gitConfig[sigstoreTUFRootConfigKey]),
 	}
+}
 
-	ctPub, err := cosign.GetCTLogPubs(ctx)
-	if err != nil {
-		return errors.Join(ErrVerifyingSigstoreSignature, err)
+// initializeSigstoreTUF points Sigstore's TUF client at the instance's TUF
+// mirror, if one is configured, before the trusted root is first used.
+func initializeSigstoreTUF(ctx context.Context, instance *SigstoreInstance) error {
+	if instance.TUFMirror == "" {
+		return nil
 	}
 
-	checkOpts := &cosign.CheckOpts{
-		RekorClient:       rekor.Rekor,
-		RootCerts:         root,
-		IntermediateCerts: intermediate,
-		CTLogPubKeys:      ctPub,
-		RekorPubKeys:      rekor.PublicKeys(),
-		Identities: []cosign.Identity{{
-			Issuer:  key.KeyVal.Issuer,
-			Subject: key.KeyVal.Identity,
-		}},
+	sigstoreTUFMu.Lock()
+	defer sigstoreTUFMu.Unlock()
+
+	if sigstoreTUFInitialized {
+		return nil
 	}
 
-	if _, err := cosign.ValidateAndUnpackCert(verifiedCert, checkOpts); err != nil {
-		return errors.Join(ErrIncorrectVerificationKey, err)
+	if err := sigstore.InitializeTUF(ctx, instance.TUFMirror, instance.TUFRoot); err != nil {
+		return err
 	}
 
+	sigstoreTUFInitialized = true
 	return nil
 }
 
-// verifySSHKeySignature verifies Git signatures issued by SSH keys.
-func verifySSHKeySignature(key *tuf.Key, data, signature []byte) error {
-	verifier, err := signerverifier.NewSignerVerifierFromTUFKey(key) //nolint:staticcheck
-	if err != nil {
-		return errors.Join(ErrVerifyingSSHSignature, err)
+// SetSMIMETrustStore sets the root certificates trusted to verify S/MIME
+// signatures to those in the PEM file or the directory of PEM files at the
+// specified path. S/MIME signatures can't be verified until a trust store is
+// set, as the system's roots aren't trusted to certify signers.
+func SetSMIMETrustStore(path string) {
+	smimeTrustStoreMu.Lock()
+	defer smimeTrustStoreMu.Unlock()
+
+	smimeTrustStorePath = path
+	smimeTrustStore = nil
+}
+
+// getSMIMETrustStore returns the root certificates trusted to verify S/MIME
+// signatures.
+func getSMIMETrustStore() (*x509.CertPool, error) {
+	smimeTrustStoreMu.Lock()
+	defer smimeTrustStoreMu.Unlock()
+
+	if smimeTrustStore == nil {
+		if smimeTrustStorePath == "" {
+			return nil, ErrSMIMETrustStoreNotSet
+		}
+
+		trustStore, err := smime.LoadTrustStore(smimeTrustStorePath)
+		if err != nil {
+			return nil, err
+		}
+		smimeTrustStore = trustStore
 	}
 
-	publicKey, err := ssh.NewPublicKey(verifier.Public())
-	if err != nil {
-		return errors.Join(ErrVerifyingSSHSignature, err)
+	return smimeTrustStore, nil
+}
+
+// withOverrides returns the options, setting the options that aren't set to
+// the overrides set for gittuf.
+func (o SigningOptions) withOverrides() SigningOptions {
+	if o.Format == "" {
+		o.Format = signingFormatOverride
+	}
+	if o.Key == "" {
+		o.Key = signingKeyOverride
+	}
+	if o.Program == "" {
+		o.Program = signingProgramOverride
 	}
 
-	sshSignature, err := sshsig.Unarmor(signature)
-	if err != nil {
-		return errors.Join(ErrVerifyingSSHSignature, err)
+	return o
+}
+
+func getSigningMethod(gitConfig map[string]string, opts SigningOptions) (SigningMethod, error) {
+	if opts.Format != "" {
+		return parseSigningFormat(opts.Format)
 	}
 
-	if err := sshsig.Verify(bytes.NewReader(data), sshSignature, publicKey, sshSignature.HashAlgorithm, namespaceSSHSignature); err != nil {
-		return errors.Join(ErrIncorrectVerificationKey, err)
+	format, ok := gitConfig["gpg.format"]
+	if !ok {
+		return SigningMethodGPG, nil
 	}
 
-	return nil
+	return parseSigningFormat(format)
+}
+
+func parseSigningFormat(format string) (SigningMethod, error) {
+	switch format {
+	case "gpg":
+		return SigningMethodGPG, nil
+	case "ssh":
+		return SigningMethodSSH, nil
+	case "x509":
+		return SigningMethodX509, nil
+	}
+	return -1, ErrUnknownSigningMethod
+}
+
+func getSigningKeyInfo(gitConfig map[string]string, opts SigningOptions) string {
+	if opts.Key != "" {
+		return opts.Key
+	}
+
+	keyInfo, ok := gitConfig["user.signingkey"]
+	if !ok {
+		return ""
+	}
+	return keyInfo
+}
+
+// getDefaultSSHSigningKey returns the SSH signing key reported by the
+// command configured in gpg.ssh.defaultKeyCommand, which Git uses when
+// user.signingKey isn't set, such as to sign using the first key in the SSH
+// agent. Like Git, the first line of the command's output must be a public
+// key, which is returned prefixed with "key::". An empty string is returned if
+// no command is configured.
+func getDefaultSSHSigningKey(gitConfig map[string]string) (string, error) {
+	command, ok := gitConfig["gpg.ssh.defaultkeycommand"]
+	if !ok || strings.TrimSpace(command) == "" {
+		return "", nil
+	}
+
+	args, err := splitCommandLine(command)
+	if err != nil {
+		return "", errors.Join(ErrSSHDefaultKeyCommandFailed, err)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec
+	stdErr := &bytes.Buffer{}
+	cmd.Stderr = stdErr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.Join(ErrSSHDefaultKeyCommandFailed, fmt.Errorf("%w: %s", err, strings.TrimSpace(stdErr.String())))
+	}
+
+	firstLine, _, _ := strings.Cut(string(output), "\n")
+	publicKey, isLiteral := getLiteralSSHKey(strings.TrimSpace(firstLine))
+	if !isLiteral {
+		return "", fmt.Errorf("%w: `%s` didn't return a public key", ErrSSHDefaultKeyCommandFailed, command)
+	}
+
+	return "key::" + publicKey, nil
+}
+
+// getLiteralSSHKey returns the public key if the SSH signing key is specified
+// literally rather than as a path. Like Git, keys prefixed with "key::" and,
+// for backwards compatibility, keys starting with "ssh-" are literal keys.
+func getLiteralSSHKey(keyInfo string) (string, bool) {
+	if publicKey, isLiteral := strings.CutPrefix(keyInfo, "key::"); isLiteral {
+		return publicKey, true
+	}
+	// Keys selected using the "ssh-agent:" prefix also start with "ssh-", but
+	// aren't literal keys
+	if strings.HasPrefix(keyInfo, "ssh-") && !sshagent.IsAgentKey(keyInfo) {
+		return keyInfo, true
+	}
+
+	return "", false
+}
+
+// splitCommandLine splits the command into its arguments like Git does for
+// commands in its config, honoring single and double quotes, and backslash
+// escapes outside single quotes. The command is not run using a shell.
+func splitCommandLine(command string) ([]string, error) {
+	args := []string{}
+
+	var (
+		current   strings.Builder
+		inArg     bool
+		quote     rune
+		isEscaped bool
+	)
+	for _, char := range command {
+		switch {
+		case isEscaped:
+			current.WriteRune(char)
+			isEscaped = false
+		case char == '\\' && quote != '\'':
+			isEscaped = true
+			inArg = true
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			} else {
+				current.WriteRune(char)
+			}
+		case char == '"' || char == '\'':
+			quote = char
+			inArg = true
+		case char == ' ' || char == '\t' || char == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(char)
+			inArg = true
+		}
+	}
+
+	if quote != 0 || isEscaped {
+		return nil, fmt.Errorf("unterminated quote or escape in command '%s'", command)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+
+	return args, nil
+}
+
+func getSigningProgram(gitConfig map[string]string, signingMethod SigningMethod, opts SigningOptions) string {
+	if opts.Program != "" {
+		return opts.Program
+	}
+
+	switch signingMethod {
+	case SigningMethodSSH:
+		program, ok := gitConfig["gpg.ssh.program"]
+		if ok {
+			return program
+		}
+		return DefaultSigningProgramSSH
+	case SigningMethodX509:
+		program, ok := gitConfig["gpg.x509.program"]
+		if ok {
+			return program
+		}
+		return DefaultSigningProgramX509
+	}
+
+	// Default to GPG
+	program, ok := gitConfig["gpg.program"]
+	if ok {
+		return program
+	}
+
+	return DefaultSigningProgramGPG
+}
+
+// normalizeSigningProgram returns the path of the signing program so that it
+// can be executed directly. Paths containing spaces, such as those under
+// "C:\Program Files" on Windows, are often quoted in the Git config, but as
+// the program isn't invoked using a shell, the quotes must be removed. A
+// leading "~" is also expanded. On Windows, the program is also located the
+// way Git for Windows would find it, see resolveWindowsSigningProgram.
+func normalizeSigningProgram(program string) (string, error) {
+	program = strings.TrimSpace(program)
+	if len(program) >= 2 {
+		if (program[0] == '"' && program[len(program)-1] == '"') || (program[0] == '\'' && program[len(program)-1] == '\'') {
+			program = program[1 : len(program)-1]
+		}
+	}
+
+	program, err := ExpandHomeDir(program)
+	if err != nil {
+		return "", err
+	}
+
+	if runtime.GOOS == "windows" {
+		return resolveWindowsSigningProgram(program), nil
+	}
+
+	return program, nil
+}
+
+// resolveWindowsSigningProgram locates the signing program on Windows. Paths
+// may omit the ".exe" suffix, as Git for Windows allows. Programs specified by
+// name, such as the defaults, are often not on the PATH of shells other than
+// Git Bash, so if the program isn't found on the PATH, the programs bundled
+// with Git for Windows and common install locations of GnuPG and OpenSSH are
+// checked. The program is returned unchanged if it isn't found, so that
+// running it reports the error.
+func resolveWindowsSigningProgram(program string) string {
+	name := program
+	if filepath.Ext(name) == "" {
+		name += ".exe"
+	}
+
+	if strings.ContainsAny(program, `/\:`) {
+		if isFile(name) {
+			return filepath.Clean(name)
+		}
+		return program
+	}
+
+	if _, err := exec.LookPath(program); err == nil {
+		return program
+	}
+
+	for _, dir := range windowsSigningProgramDirs() {
+		if candidate := filepath.Join(dir, name); isFile(candidate) {
+			logger.Debug(fmt.Sprintf("Signing program '%s' not found on PATH, using '%s'", program, candidate))
+			return candidate
+		}
+	}
+
+	return program
+}
+
+// windowsSigningProgramDirs returns the directories signing programs are
+// commonly installed in on Windows, in the order they are checked. The
+// programs bundled with Git for Windows are preferred, as Git uses them by
+// default.
+func windowsSigningProgramDirs() []string {
+	dirs := []string{}
+	if gitPath, err := exec.LookPath("git"); err == nil {
+		// git.exe is in the cmd or bin directory of the Git for Windows
+		// installation, and the bundled programs are in usr\bin
+		dirs = append(dirs, filepath.Join(filepath.Dir(filepath.Dir(gitPath)), "usr", "bin"))
+	}
+
+	for _, envKey := range []string{"ProgramFiles", "ProgramFiles(x86)"} {
+		programFiles := os.Getenv(envKey)
+		if programFiles == "" {
+			continue
+		}
+
+		dirs = append(dirs,
+			filepath.Join(programFiles, "Git", "usr", "bin"),
+			filepath.Join(programFiles, "GnuPG", "bin"),
+			filepath.Join(programFiles, "OpenSSH"),
+		)
+	}
+
+	if systemRoot := os.Getenv("SystemRoot"); systemRoot != "" {
+		dirs = append(dirs, filepath.Join(systemRoot, "System32", "OpenSSH"))
+	}
+
+	return dirs
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// shouldSign returns true if a Git object created by gittuf must be signed,
+// either because the caller requested it or because one of the boolean keys,
+// such as commit.gpgSign, is enabled in the user's Git config.
+func shouldSign(sign bool, keys ...string) (bool, error) {
+	if sign {
+		return true, nil
+	}
+
+	gitConfig, err := LoadConfig()
+	if err != nil {
+		return false, err
+	}
+
+	for _, key := range keys {
+		enabled, err := gitConfig.GetBool(key)
+		if err != nil {
+			return false, err
+		}
+		if enabled {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// SignGitObject signs the contents of a Git commit or tag, which exclude the
+// signature, and returns the armored signature. The signature is created
+// using the private key in the options if it's set, and like Git does using
+// the options and the user's Git config otherwise.
+func SignGitObject(contents []byte, opts SigningOptions) (string, error) {
+	if len(opts.PrivateKey) > 0 {
+		return signGitObjectUsingKey(contentsFromBytes(contents), opts.PrivateKey)
+	}
+
+	return signGitObjectWithOptions(contentsFromBytes(contents), opts)
+}
+
+// signGitObject signs a Git commit or tag using the user's configured Git
+// config.
+func signGitObject(contents objectContents) (string, error) {
+	return signGitObjectWithOptions(contents, SigningOptions{})
+}
+
+func signGitObjectWithOptions(contents objectContents, opts SigningOptions) (string, error) {
+	command, args, err := getSigningCommand(opts)
+	if err != nil {
+		return "", err
+	}
+
+	if command == SigningProgramSSHAgent {
+		_, keyInfo, _, err := getSigningInfoWithOptions(opts)
+		if err != nil {
+			return "", err
+		}
+
+		return signGitObjectUsingSSHAgent(contents, strings.TrimPrefix(keyInfo, sshagent.KeyPrefix))
+	}
+
+	if command == SigningProgramGPGAgent {
+		_, keyInfo, _, err := getSigningInfoWithOptions(opts)
+		if err != nil {
+			return "", err
+		}
+
+		return signGitObjectUsingGPGAgent(contents, strings.TrimPrefix(keyInfo, gpgagent.KeyPrefix))
+	}
+
+	if command == SigningProgramKMS {
+		_, keyInfo, _, err := getSigningInfoWithOptions(opts)
+		if err != nil {
+			return "", err
+		}
+
+		return signGitObjectUsingKMS(contents, keyInfo)
+	}
+
+	if command == SigningProgramSigstore {
+		return signGitObjectUsingSigstore(contents)
+	}
+
+	args, cleanup, err := writeLiteralSSHKey(args)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	return runSigningProgram(command, args, contents)
+}
+
+// runSigningProgram invokes the signing program with the arguments, streaming
+// the contents to sign to its standard input, and returns the signature it
+// writes to its standard output.
+func runSigningProgram(command string, args []string, contents objectContents) (string, error) {
+	stdIn, err := contents()
+	if err != nil {
+		return "", err
+	}
+	defer stdIn.Close() //nolint:errcheck
+
+	cmd := exec.Command(command, args...)
+	if interactive.InNonInteractiveMode() {
+		// ssh-keygen asks for the passphrase of encrypted keys using the
+		// askpass program when forced to, which fails here
+		cmd.Env = append(os.Environ(), "SSH_ASKPASS_REQUIRE=force", "SSH_ASKPASS=false")
+	}
+
+	// The contents are copied to the program while its output is read, so
+	// the program doesn't block writing its output while the contents are
+	// written
+	stdOut := &bytes.Buffer{}
+	stdErr := &bytes.Buffer{}
+	cmd.Stdin = stdIn
+	cmd.Stdout = stdOut
+	cmd.Stderr = stdErr
+
+	err = cmd.Run()
+
+	if stdErr.Len() > 0 {
+		// The signing program may report details such as the key used, or
+		// why signing failed
+		logger.Info("Signing program reported output", "program", command, "output", strings.TrimSpace(stdErr.String()))
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	sig := stdOut.Bytes()
+	if len(sig) == 0 {
+		return "", ErrUnableToSign
+	}
+
+	// Signing programs on Windows may end lines with CRLF, which Git strips
+	// from signatures
+	return strings.ReplaceAll(string(sig), "\r\n", "\n"), nil
+}
+
+// writeLiteralSSHKey writes the SSH public key to a temporary file if it's
+// specified literally in the arguments for ssh-keygen, as ssh-keygen expects
+// the path of a key file. The arguments are updated to use the file, which is
+// removed by the returned cleanup function.
+func writeLiteralSSHKey(args []string) ([]string, func(), error) {
+	for index := 0; index < len(args)-1; index++ {
+		if args[index] != "-f" {
+			continue
+		}
+
+		publicKey, isLiteral := getLiteralSSHKey(args[index+1])
+		if !isLiteral {
+			break
+		}
+
+		keyFile, cleanup, err := writeTemporaryKeyFile("gittuf-ssh-signing-key-*.pub", []byte(publicKey+"\n"))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		updatedArgs := append([]string{}, args...)
+		updatedArgs[index+1] = keyFile
+		return updatedArgs, cleanup, nil
+	}
+
+	return args, func() {}, nil
+}
+
+// writeTemporaryKeyFile writes the key to a temporary file that only the user
+// can read, for programs that expect the path of a key file. It returns the
+// path and a function that removes the file.
+func writeTemporaryKeyFile(pattern string, key []byte) (string, func(), error) {
+	keyFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.Remove(keyFile.Name()) } //nolint:errcheck
+
+	if _, err := keyFile.Write(key); err != nil {
+		keyFile.Close() //nolint:errcheck
+		cleanup()
+		return "", nil, err
+	}
+	if err := keyFile.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return keyFile.Name(), cleanup, nil
+}
+
+func signGitObjectUsingKey(contents objectContents, pemKeyBytes []byte) (string, error) {
+	block, _ := pem.Decode(pemKeyBytes)
+	if block == nil {
+		// openpgp implements its own armor-decode method, pem.Decode considers
+		// the input invalid. We haven't tested if this is universal, so in case
+		// pem.Decode does succeed on a GPG key, we catch it below.
+		return signGitObjectUsingGPGKey(contents, pemKeyBytes)
+	}
+
+	switch block.Type {
+	case gpgPrivateKeyPEMHeader:
+		return signGitObjectUsingGPGKey(contents, pemKeyBytes)
+	case opensshPrivateKeyPEMHeader, rsaPrivateKeyPEMHeader, genericPrivateKeyPEMHeader:
+		return signGitObjectUsingSSHKey(contents, pemKeyBytes)
+	}
+
+	return "", ErrUnknownSigningMethod
+}
+
+func signGitObjectUsingGPGKey(contents objectContents, pemKeyBytes []byte) (string, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(pemKeyBytes))
+	if err != nil {
+		return "", err
+	}
+	if err := decryptGPGKey(keyring[0]); err != nil {
+		return "", err
+	}
+
+	reader, err := contents()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close() //nolint:errcheck
+
+	sig := new(strings.Builder)
+	if err := openpgp.ArmoredDetachSign(sig, keyring[0], reader, nil); err != nil {
+		return "", err
+	}
+
+	return sig.String(), nil
+}
+
+func signGitObjectUsingSSHKey(contents objectContents, pemKeyBytes []byte) (string, error) {
+	if publicKey, isSecurityKey := getSecurityKeyPublicKey(pemKeyBytes); isSecurityKey {
+		return signGitObjectUsingSecurityKey(contents, pemKeyBytes, publicKey)
+	}
+
+	signer, err := parseSSHPrivateKey(pemKeyBytes)
+	if err != nil {
+		return "", err
+	}
+
+	reader, err := contents()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close() //nolint:errcheck
+
+	sshSig, err := sshsig.Sign(reader, signer, sshsig.HashSHA512, namespaceSSHSignature)
+	if err != nil {
+		return "", err
+	}
+
+	sigBytes := sshsig.Armor(sshSig)
+
+	return string(sigBytes), nil
+}
+
+// parseSSHPrivateKey parses the SSH private key, obtaining its passphrase
+// using the passphrase package if it's encrypted.
+func parseSSHPrivateKey(pemKeyBytes []byte) (ssh.Signer, error) {
+	signer, err := ssh.ParsePrivateKey(pemKeyBytes)
+	var passphraseMissingErr *ssh.PassphraseMissingError
+	if !errors.As(err, &passphraseMissingErr) {
+		return signer, err
+	}
+
+	description := "SSH key"
+	if passphraseMissingErr.PublicKey != nil {
+		description += " " + ssh.FingerprintSHA256(passphraseMissingErr.PublicKey)
+	}
+
+	err = passphrase.Decrypt(description, func(keyPassphrase []byte) error {
+		var err error
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(pemKeyBytes, keyPassphrase)
+		if errors.Is(err, x509.IncorrectPasswordError) {
+			return errors.Join(passphrase.ErrIncorrectPassphrase, err)
+		}
+		return err
+	})
+	return signer, err
+}
+
+// decryptGPGKey decrypts the GPG key's private keys if they're encrypted,
+// obtaining their passphrase using the passphrase package.
+func decryptGPGKey(entity *openpgp.Entity) error {
+	encrypted := entity.PrivateKey != nil && entity.PrivateKey.Encrypted
+	for _, subkey := range entity.Subkeys {
+		encrypted = encrypted || (subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted)
+	}
+	if !encrypted {
+		return nil
+	}
+
+	description := fmt.Sprintf("GPG key %X", entity.PrimaryKey.Fingerprint)
+	return passphrase.Decrypt(description, func(keyPassphrase []byte) error {
+		if err := entity.DecryptPrivateKeys(keyPassphrase); err != nil {
+			return errors.Join(passphrase.ErrIncorrectPassphrase, err)
+		}
+		return nil
+	})
+}
+
+// signGitObjectUsingSSHAgent signs the Git object using the key in the SSH
+// agent that matches the selector, without requiring the key on disk or
+// invoking ssh-keygen.
+func signGitObjectUsingSSHAgent(contents objectContents, selector string) (string, error) {
+	var sshSig *sshsig.Signature
+	err := sshagent.WithSigner(selector, func(signer ssh.Signer) error {
+		reader, err := contents()
+		if err != nil {
+			return err
+		}
+		defer reader.Close() //nolint:errcheck
+
+		sshSig, err = sshsig.Sign(reader, signer, sshsig.HashSHA512, namespaceSSHSignature)
+		return err
+	})
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+
+	return string(sshsig.Armor(sshSig)), nil
+}
+
+// signGitObjectUsingKMS signs the Git object using the key held in the key
+// management service identified by the URI. An SSH signature is created, so
+// that it's verified like the signatures of ECDSA keys on disk.
+func signGitObjectUsingKMS(contents objectContents, uri string) (string, error) {
+	signer, err := kms.NewSigner(context.Background(), uri)
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+
+	sshSigner, err := ssh.NewSignerFromSigner(signer.CryptoSigner())
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+
+	reader, err := contents()
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+	defer reader.Close() //nolint:errcheck
+
+	sshSig, err := sshsig.Sign(reader, sshSigner, sshsig.HashSHA512, namespaceSSHSignature)
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+
+	return string(sshsig.Armor(sshSig)), nil
+}
+
+// signGitObjectUsingGPGAgent signs the Git object using the key held by
+// gpg-agent that matches the selector, without invoking gpg. In
+// non-interactive mode, the agent fails instead of asking for the key's
+// passphrase or PIN, unless it's set in the environment.
+func signGitObjectUsingGPGAgent(contents objectContents, selector string) (string, error) {
+	options := gpgagent.DefaultOptions()
+	if interactive.InNonInteractiveMode() && options.PinentryMode == "" {
+		options.PinentryMode = gpgagent.PinentryModeError
+	}
+
+	reader, err := contents()
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+	defer reader.Close() //nolint:errcheck
+
+	signature, err := gpgagent.Sign(reader, selector, options)
+	if err != nil {
+		if errors.Is(err, gpgagent.ErrPassphraseRequired) && interactive.InNonInteractiveMode() {
+			return "", errors.Join(ErrUnableToSign, interactive.ErrInteractionRequired, err)
+		}
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+
+	return signature, nil
+}
+
+// signGitObjectUsingSigstore signs the Git object without a key using the CI
+// environment's OIDC identity, with a certificate issued by Fulcio, like
+// gitsign does. The signature is logged to Rekor. The contents are read in
+// full as the signature and the Rekor entry are created from them in memory.
+func signGitObjectUsingSigstore(contents objectContents) (string, error) {
+	ctx := context.Background()
+
+	signer, err := getSigstoreSigner(ctx)
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+
+	data, err := readContents(contents)
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+
+	signature, err := signer.SignGitObject(data)
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+
+	instance, err := GetSigstoreInstance()
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+
+	entry, err := signer.LogGitObject(ctx, rekor.NewClient(instance.RekorURL), data)
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+	logger.Debug("Logged signature to Rekor", "identity", signer.Identity(), "uuid", entry.UUID)
+
+	return string(signature), nil
+}
+
+// getSigstoreSigner returns the signer used by the Sigstore signing backend,
+// requesting a new certificate from Fulcio if the cached signer is too old.
+func getSigstoreSigner(ctx context.Context) (*sigstore.Signer, error) {
+	sigstoreSignerMu.Lock()
+	defer sigstoreSignerMu.Unlock()
+
+	now := clock.Now()
+	if sigstoreSigner != nil && now.Sub(sigstoreSignerCreatedAt) < sigstoreSignerLifetime {
+		return sigstoreSigner, nil
+	}
+
+	instance, err := GetSigstoreInstance()
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := sigstore.NewAmbientSigner(ctx, instance.FulcioURL)
+	if err != nil {
+		return nil, err
+	}
+
+	sigstoreSigner = signer
+	sigstoreSignerCreatedAt = now
+	return signer, nil
+}
+
+// VerifySignature verifies the signature of the contents of a Git commit or
+// tag, which exclude the signature, using the key. If the context carries a
+// BatchVerifier, the state it holds, such as the parsed key, is reused. Unlike
+// VerifyCommitSignature, the allowed signers carried by the context aren't
+// checked, as the contents aren't parsed. An error wrapping
+// ErrIncorrectVerificationKey is returned if the signature wasn't issued by
+// the key.
+func VerifySignature(ctx context.Context, key *tuf.Key, contents, signature []byte) error {
+	defer timing.Start(timing.PhaseSignatureVerification)()
+
+	return verifierForContext(ctx).verifyContents(ctx, plumbing.ZeroHash, key, contentsFromBytes(contents), contentsFromBytes(contents), signature)
+}
+
+// sigstoreVerificationMaterial is the state used to verify gitsign
+// signatures: the trusted Fulcio certificates and the options used to check
+// the certificates they issued. It's loaded once for a BatchVerifier, as
+// loading it may contact Sigstore's services.
+type sigstoreVerificationMaterial struct {
+	certVerifier *gitsignVerifier.CertVerifier
+	checkOpts    cosign.CheckOpts
+}
+
+// loadSigstoreVerificationMaterial loads the state used to verify gitsign
+// signatures. If a Sigstore trusted root is set using SetSigstoreTrustedRoot,
+// the certificate authorities and certificate transparency logs in the trusted
+// root are used. Otherwise, the TUF repository and Rekor instance of the
+// Sigstore instance set using SetSigstoreInstance may be contacted.
+func loadSigstoreVerificationMaterial(ctx context.Context) (*sigstoreVerificationMaterial, error) {
+	defer timing.Start(timing.PhaseSigstore)()
+
+	trustedRoot, err := getSigstoreTrustedRoot()
+	if err != nil {
+		return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+	}
+
+	var instance *SigstoreInstance
+	if trustedRoot == nil {
+		instance, err = GetSigstoreInstance()
+		if err != nil {
+			return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+		}
+
+		if err := initializeSigstoreTUF(ctx, instance); err != nil {
+			return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+		}
+	}
+
+	var root, intermediate *x509.CertPool
+	switch {
+	case trustedRoot != nil:
+		root, intermediate = trustedRoot.CertificatePools()
+	case instance.FulcioRoots != "":
+		root, intermediate, err = sigstore.LoadFulcioRoots(instance.FulcioRoots)
+		if err != nil {
+			return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+		}
+	default:
+		root, err = fulcioroots.Get()
+		if err != nil {
+			return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+		}
+		intermediate, err = fulcioroots.GetIntermediates()
+		if err != nil {
+			return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+		}
+	}
+
+	certVerifier, err := gitsignVerifier.NewCertVerifier(
+		gitsignVerifier.WithRootPool(root),
+		gitsignVerifier.WithIntermediatePool(intermediate),
+	)
+	if err != nil {
+		return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+	}
+
+	checkOpts := cosign.CheckOpts{
+		RootCerts:         root,
+		IntermediateCerts: intermediate,
+	}
+
+	if trustedRoot != nil {
+		// The certificate transparency logs' keys are used to verify the
+		// signed certificate timestamp embedded in the certificate
+		ctPub := cosign.NewTrustedTransparencyLogPubKeys()
+		now := clock.Now()
+		for _, ctLog := range trustedRoot.CTLogs() {
+			status := sigstoretuf.Active
+			if ctLog.Expired(now) {
+				status = sigstoretuf.Expired
+			}
+
+			if err := ctPub.AddTransparencyLogPubKey(ctLog.PublicKeyPEM, status); err != nil {
+				return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+			}
+		}
+		checkOpts.CTLogPubKeys = &ctPub
+	} else {
+		rekor, err := gitsignRekor.NewWithOptions(ctx, instance.RekorURL)
+		if err != nil {
+			return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+		}
+
+		ctPub, err := cosign.GetCTLogPubs(ctx)
+		if err != nil {
+			return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+		}
+
+		checkOpts.RekorClient = rekor.Rekor
+		checkOpts.RekorPubKeys = rekor.PublicKeys()
+		checkOpts.CTLogPubKeys = ctPub
+	}
+
+	return &sigstoreVerificationMaterial{certVerifier: certVerifier, checkOpts: checkOpts}, nil
+}
+
+// verifyGitsignSignature handles the Sigstore-specific workflow involved in
+// verifying commit or tag signatures issued by gitsign.
+func verifyGitsignSignature(ctx context.Context, key *tuf.Key, data, signature []byte) error {
+	defer timing.Start(timing.PhaseSigstore)()
+
+	material, err := loadSigstoreVerificationMaterial(ctx)
+	if err != nil {
+		return err
+	}
+
+	verifiedCert, err := verifyGitsignCertificate(ctx, material, data, signature)
+	if err != nil {
+		return err
+	}
+
+	return checkGitsignCertificate(material, key, verifiedCert)
+}
+
+// verifyGitsignCertificate verifies the gitsign signature and the chain of the
+// certificate embedded in it, returning the certificate. The certificate's
+// identity is checked separately using checkGitsignCertificate.
+func verifyGitsignCertificate(ctx context.Context, material *sigstoreVerificationMaterial, data, signature []byte) (*x509.Certificate, error) {
+	verifiedCert, err := material.certVerifier.Verify(ctx, data, signature, true)
+	if err != nil {
+		return nil, ErrIncorrectVerificationKey
+	}
+
+	return verifiedCert, nil
+}
+
+// checkGitsignCertificate checks that the certificate of a verified gitsign
+// signature was issued to the key's identity by the key's issuer.
+func checkGitsignCertificate(material *sigstoreVerificationMaterial, key *tuf.Key, verifiedCert *x509.Certificate) error {
+	if err := identity.Check(key.KeyVal.Identity); err != nil {
+		logger.Warn("Verifying signature using a Sigstore identity that can be confused with other identities", "identity", key.KeyVal.Identity, "error", err)
+	}
+
+	checkOpts := material.checkOpts
+	checkOpts.Identities = []cosign.Identity{{
+		Issuer:  key.KeyVal.Issuer,
+		Subject: identity.Normalize(key.KeyVal.Identity),
+	}}
+
+	if _, err := cosign.ValidateAndUnpackCert(verifiedCert, &checkOpts); err != nil {
+		return errors.Join(ErrIncorrectVerificationKey, err)
+	}
+
+	return nil
+}
+
+// verifySMIMESignature verifies Git signatures issued by gpgsm or smimesign
+// using an S/MIME certificate. The certificate must chain to the trust store
+// set using SetSMIMETrustStore and must be issued for the key's identity. It's
+// checked at the trusted signing time determined using the context, see
+// ContextWithSigningTimes, and at the current time otherwise.
+func verifySMIMESignature(ctx context.Context, key *tuf.Key, data, signature []byte) error {
+	roots, err := getSMIMETrustStore()
+	if err != nil {
+		return errors.Join(ErrVerifyingSMIMESignature, err)
+	}
+
+	at, err := trustedSigningTime(ctx, signature)
+	if err != nil {
+		return errors.Join(ErrVerifyingSMIMESignature, err)
+	}
+
+	cert, err := smime.Verify(data, signature, roots, at)
+	if err != nil {
+		return errors.Join(ErrIncorrectVerificationKey, err)
+	}
+
+	if !smime.HasIdentity(cert, key.KeyVal.Identity) {
+		return fmt.Errorf("%w: certificate was issued for %s, not '%s'", ErrIncorrectVerificationKey, strings.Join(smime.Identities(cert), ", "), key.KeyVal.Identity)
+	}
+
+	return nil
+}
+
+// verifySSHKeySignature verifies Git signatures issued by SSH keys, including
+// keys held in FIDO2 security keys.
+func verifySSHKeySignature(key *tuf.Key, data, signature []byte) error {
+	publicKey, err := sshPublicKeyFromTUFKey(key)
+	if err != nil {
+		return err
+	}
+
+	return verifySSHSignature(publicKey, contentsFromBytes(data), signature)
+}
+
+// sshPublicKeyFromTUFKey returns the SSH public key for the key in gittuf
+// metadata. For keys held in security keys, the returned key also checks the
+// flags set by the security key when verifying signatures.
+func sshPublicKeyFromTUFKey(key *tuf.Key) (ssh.PublicKey, error) {
+	if key.KeyType == signerverifier.SSHSecurityKeyType {
+		publicKey, err := newSecurityKeyPublicKey(key)
+		if err != nil {
+			return nil, errors.Join(ErrVerifyingSSHSignature, err)
+		}
+
+		return publicKey, nil
+	}
+
+	verifier, err := signerverifier.NewSignerVerifierFromTUFKey(key) //nolint:staticcheck
+	if err != nil {
+		return nil, errors.Join(ErrVerifyingSSHSignature, err)
+	}
+
+	publicKey, err := ssh.NewPublicKey(verifier.Public())
+	if err != nil {
+		return nil, errors.Join(ErrVerifyingSSHSignature, err)
+	}
+
+	return publicKey, nil
+}
+
+// verifySSHSignature verifies the Git signature using the SSH public key as
+// the contents are streamed.
+func verifySSHSignature(publicKey ssh.PublicKey, contents objectContents, signature []byte) error {
+	sshSignature, err := sshsig.Unarmor(signature)
+	if err != nil {
+		return errors.Join(ErrVerifyingSSHSignature, err)
+	}
+
+	reader, err := contents()
+	if err != nil {
+		return errors.Join(ErrVerifyingSSHSignature, err)
+	}
+	defer reader.Close() //nolint:errcheck
+
+	if err := sshsig.Verify(reader, sshSignature, publicKey, sshSignature.HashAlgorithm, namespaceSSHSignature); err != nil {
+		return errors.Join(ErrIncorrectVerificationKey, err)
+	}
+
+	return nil
+}
+
+// DescribeSignature returns a human readable description of a Git object's
+// signature. Where the signature format permits, the description identifies
+// the key used to create the signature. For GPG signatures, this is the key's
+// fingerprint, which is also the key's ID in gittuf metadata. For SSH
+// signatures, this is the key's SHA256 fingerprint, as displayed by
+// `ssh-keygen -l`.
+func DescribeSignature(signature string) string {
+	switch {
+	case signature == "":
+		return "no signature"
+	case strings.HasPrefix(signature, gpgSignatureHeader):
+		block, err := armor.Decode(strings.NewReader(signature))
+		if err != nil {
+			return "malformed GPG signature"
+		}
+
+		p, err := packet.Read(block.Body)
+		if err != nil {
+			return "malformed GPG signature"
+		}
+
+		if sig, ok := p.(*packet.Signature); ok {
+			if len(sig.IssuerFingerprint) > 0 {
+				return fmt.Sprintf("GPG signature by key %x", sig.IssuerFingerprint)
+			}
+			if sig.IssuerKeyId != nil {
+				return fmt.Sprintf("GPG signature by key with ID %016x", *sig.IssuerKeyId)
+			}
+		}
+
+		return "GPG signature"
+	case strings.HasPrefix(signature, sshSignatureHeader):
+		sshSignature, err := sshsig.Unarmor([]byte(signature))
+		if err != nil {
+			return "malformed SSH signature"
+		}
+
+		return fmt.Sprintf("SSH signature by key %s", ssh.FingerprintSHA256(sshSignature.PublicKey))
+	case strings.HasPrefix(signature, x509SignatureHeader):
+		return "Sigstore signature"
+	}
+
+	return "signature in an unknown format"
 }
@@ -4,16 +4,39 @@ package gitinterface
 
 import (
 	"bytes"
+	"context"
+	"encoding/pem"
+	"fmt"
 	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/gittuf/gittuf/internal/interactive"
+	"github.com/gittuf/gittuf/internal/passphrase"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpgagent"
+	"github.com/gittuf/gittuf/internal/signerverifier/sigstore"
+	"github.com/gittuf/gittuf/internal/signerverifier/sshagent"
 	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	format "github.com/go-git/go-git/v5/plumbing/format/config"
 	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/hiddeco/sshsig"
+	"github.com/sigstore/sigstore/pkg/signature/kms/fake"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
 )
 
 var (
@@ -176,3 +199,729 @@ func TestGetSigningInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestSigningOverrides(t *testing.T) {
+	t.Cleanup(func() {
+		signingFormatOverride = ""
+		signingKeyOverride = ""
+		signingProgramOverride = ""
+	})
+
+	gitConfig := map[string]string{
+		"gpg.format":      "gpg",
+		"user.signingkey": "ABCDEF",
+		"gpg.ssh.program": "ssh-keygen",
+	}
+
+	err := SetSigningFormat("ssh")
+	assert.Nil(t, err)
+
+	signingMethod, err := getSigningMethod(gitConfig, SigningOptions{}.withOverrides())
+	assert.Nil(t, err)
+	assert.Equal(t, SigningMethodSSH, signingMethod)
+
+	err = SetSigningFormat("abcdef")
+	assert.ErrorIs(t, err, ErrUnknownSigningMethod)
+
+	// The previous override is retained
+	signingMethod, err = getSigningMethod(gitConfig, SigningOptions{}.withOverrides())
+	assert.Nil(t, err)
+	assert.Equal(t, SigningMethodSSH, signingMethod)
+
+	assert.Equal(t, "ABCDEF", getSigningKeyInfo(gitConfig, SigningOptions{}.withOverrides()))
+	SetSigningKey("~/.ssh/id_ed25519")
+	assert.Equal(t, "~/.ssh/id_ed25519", getSigningKeyInfo(gitConfig, SigningOptions{}.withOverrides()))
+
+	assert.Equal(t, "ssh-keygen", getSigningProgram(gitConfig, SigningMethodSSH, SigningOptions{}.withOverrides()))
+	SetSigningProgram("/usr/local/bin/ssh-keygen")
+	assert.Equal(t, "/usr/local/bin/ssh-keygen", getSigningProgram(gitConfig, SigningMethodSSH, SigningOptions{}.withOverrides()))
+}
+
+func TestShouldSign(t *testing.T) {
+	t.Cleanup(func() {
+		getGitConfigFromCommand = execGitConfig
+	})
+
+	tests := map[string]struct {
+		sign          bool
+		configOutput  string
+		expectedSign  bool
+		expectedError error
+	}{
+		"sign requested": {
+			sign:         true,
+			configOutput: "global\x00commit.gpgsign\nfalse\x00",
+			expectedSign: true,
+		},
+		"not set": {
+			configOutput: "global\x00user.name\nJane Doe\x00",
+			expectedSign: false,
+		},
+		"enabled": {
+			configOutput: "global\x00commit.gpgsign\ntrue\x00",
+			expectedSign: true,
+		},
+		"enabled without value": {
+			configOutput: "global\x00commit.gpgsign\x00",
+			expectedSign: true,
+		},
+		"enabled globally, disabled locally": {
+			configOutput: "global\x00commit.gpgsign\nyes\x00local\x00commit.gpgsign\nfalse\x00",
+			expectedSign: false,
+		},
+		"invalid value": {
+			configOutput:  "global\x00commit.gpgsign\nmaybe\x00",
+			expectedError: ErrInvalidConfigBool,
+		},
+	}
+
+	for name, test := range tests {
+		getGitConfigFromCommand = func() (io.Reader, error) {
+			return bytes.NewReader([]byte(test.configOutput)), nil
+		}
+
+		sign, err := shouldSign(test.sign, "commit.gpgsign")
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, name)
+		} else {
+			assert.Nil(t, err, name)
+			assert.Equal(t, test.expectedSign, sign, name)
+		}
+	}
+}
+
+func TestExpandHomeDir(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+
+	tests := map[string]struct {
+		path         string
+		expectedPath string
+	}{
+		"home directory": {
+			path:         "~",
+			expectedPath: homeDir,
+		},
+		"path in home directory": {
+			path:         "~/.ssh/id_ed25519",
+			expectedPath: filepath.Join(homeDir, ".ssh", "id_ed25519"),
+		},
+		"absolute path": {
+			path:         "/etc/ssh/id_ed25519",
+			expectedPath: "/etc/ssh/id_ed25519",
+		},
+		"relative path": {
+			path:         "keys/~/id_ed25519",
+			expectedPath: "keys/~/id_ed25519",
+		},
+		"other user's home directory": {
+			path:         "~user/.ssh/id_ed25519",
+			expectedPath: "~user/.ssh/id_ed25519",
+		},
+	}
+
+	for name, test := range tests {
+		path, err := ExpandHomeDir(test.path)
+		assert.Nil(t, err, name)
+		assert.Equal(t, test.expectedPath, path, name)
+	}
+}
+
+func TestNormalizeSigningProgram(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+
+	tests := map[string]struct {
+		program         string
+		expectedProgram string
+	}{
+		"program name": {
+			program:         "ssh-keygen",
+			expectedProgram: "ssh-keygen",
+		},
+		"double quoted path with spaces": {
+			program:         `"/opt/signing tools/ssh-keygen"`,
+			expectedProgram: "/opt/signing tools/ssh-keygen",
+		},
+		"single quoted path with spaces": {
+			program:         "'/opt/signing tools/ssh-keygen'",
+			expectedProgram: "/opt/signing tools/ssh-keygen",
+		},
+		"unquoted path with spaces": {
+			program:         "/opt/signing tools/ssh-keygen",
+			expectedProgram: "/opt/signing tools/ssh-keygen",
+		},
+		"mismatched quotes": {
+			program:         `"/opt/signing tools/ssh-keygen'`,
+			expectedProgram: `"/opt/signing tools/ssh-keygen'`,
+		},
+		"path in home directory": {
+			program:         "~/bin/gitsign",
+			expectedProgram: filepath.Join(homeDir, "bin", "gitsign"),
+		},
+	}
+
+	for name, test := range tests {
+		program, err := normalizeSigningProgram(test.program)
+		assert.Nil(t, err, name)
+		assert.Equal(t, test.expectedProgram, program, name)
+	}
+}
+
+func TestIsGitsign(t *testing.T) {
+	assert.True(t, IsGitsign("gitsign"))
+	assert.True(t, IsGitsign("/usr/local/bin/gitsign"))
+	assert.False(t, IsGitsign("gpgsm"))
+	assert.False(t, IsGitsign("/usr/local/bin/gitsign-credential-cache"))
+}
+
+func TestGetDefaultSSHSigningKey(t *testing.T) {
+	publicKey := strings.TrimSpace(string(artifacts.SSHED25519PublicSSH))
+
+	// The commands use Git to print their output so that they can be run on
+	// all platforms
+	tests := map[string]struct {
+		command       string
+		expectedKey   string
+		expectedError error
+	}{
+		"no command": {
+			command:     "",
+			expectedKey: "",
+		},
+		"literal key": {
+			command:     fmt.Sprintf(`git -c "test.key=key::%s" config --get test.key`, publicKey),
+			expectedKey: "key::" + publicKey,
+		},
+		"public key": {
+			command:     fmt.Sprintf(`git -c "test.key=%s" config --get test.key`, publicKey),
+			expectedKey: "key::" + publicKey,
+		},
+		"not a public key": {
+			command:       `git -c "test.key=/home/jane/.ssh/id_ed25519" config --get test.key`,
+			expectedError: ErrSSHDefaultKeyCommandFailed,
+		},
+		"command fails": {
+			command:       "git config --get test.missing",
+			expectedError: ErrSSHDefaultKeyCommandFailed,
+		},
+		"unterminated quote": {
+			command:       `git -c "test.key=value config --get test.key`,
+			expectedError: ErrSSHDefaultKeyCommandFailed,
+		},
+	}
+
+	for name, test := range tests {
+		gitConfig := map[string]string{}
+		if test.command != "" {
+			gitConfig["gpg.ssh.defaultkeycommand"] = test.command
+		}
+
+		keyInfo, err := getDefaultSSHSigningKey(gitConfig)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, name)
+		} else {
+			assert.Nil(t, err, name)
+			assert.Equal(t, test.expectedKey, keyInfo, name)
+		}
+	}
+
+	t.Run("signing info", func(t *testing.T) {
+		t.Cleanup(func() {
+			getGitConfigFromCommand = execGitConfig
+		})
+
+		configOutput := fmt.Sprintf("global\x00gpg.format\nssh\x00global\x00gpg.ssh.defaultkeycommand\ngit -c \"test.key=%s\" config --get test.key\x00", publicKey)
+		getGitConfigFromCommand = func() (io.Reader, error) {
+			return bytes.NewReader([]byte(configOutput)), nil
+		}
+
+		signingMethod, keyInfo, _, err := getSigningInfo()
+		assert.Nil(t, err)
+		assert.Equal(t, SigningMethodSSH, signingMethod)
+		assert.Equal(t, "key::"+publicKey, keyInfo)
+
+		_, args, err := GetSigningCommand()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"-Y", "sign", "-n", "git", "-U", "-f", "key::" + publicKey}, args)
+
+		assert.Nil(t, CheckSigningKey())
+	})
+}
+
+func TestWriteLiteralSSHKey(t *testing.T) {
+	publicKey := strings.TrimSpace(string(artifacts.SSHED25519PublicSSH))
+
+	t.Run("literal key", func(t *testing.T) {
+		args := []string{"-Y", "sign", "-n", "git", "-U", "-f", "key::" + publicKey}
+
+		updatedArgs, cleanup, err := writeLiteralSSHKey(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keyPath := updatedArgs[len(updatedArgs)-1]
+		contents, err := os.ReadFile(keyPath)
+		assert.Nil(t, err)
+		assert.Equal(t, publicKey+"\n", string(contents))
+		assert.Equal(t, args[:len(args)-1], updatedArgs[:len(updatedArgs)-1])
+
+		// The original arguments are not modified
+		assert.Equal(t, "key::"+publicKey, args[len(args)-1])
+
+		cleanup()
+		_, err = os.Stat(keyPath)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("key path", func(t *testing.T) {
+		args := []string{"-Y", "sign", "-n", "git", "-f", "/home/jane/.ssh/id_ed25519"}
+
+		updatedArgs, cleanup, err := writeLiteralSSHKey(args)
+		assert.Nil(t, err)
+		assert.Equal(t, args, updatedArgs)
+		cleanup()
+	})
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := map[string]struct {
+		command       string
+		expectedArgs  []string
+		expectedError bool
+	}{
+		"simple command": {
+			command:      "ssh-add -L",
+			expectedArgs: []string{"ssh-add", "-L"},
+		},
+		"extra whitespace": {
+			command:      "  ssh-add \t -L  ",
+			expectedArgs: []string{"ssh-add", "-L"},
+		},
+		"double quotes": {
+			command:      `"/opt/signing tools/find-key" --agent`,
+			expectedArgs: []string{"/opt/signing tools/find-key", "--agent"},
+		},
+		"single quotes": {
+			command:      `find-key 'first key' "it's"`,
+			expectedArgs: []string{"find-key", "first key", "it's"},
+		},
+		"escapes": {
+			command:      `find-key first\ key \"quoted\" '\n'`,
+			expectedArgs: []string{"find-key", "first key", `"quoted"`, `\n`},
+		},
+		"empty argument": {
+			command:      `find-key ""`,
+			expectedArgs: []string{"find-key", ""},
+		},
+		"unterminated quote": {
+			command:       `find-key "first key`,
+			expectedError: true,
+		},
+		"empty command": {
+			command:       "   ",
+			expectedError: true,
+		},
+	}
+
+	for name, test := range tests {
+		args, err := splitCommandLine(test.command)
+		if test.expectedError {
+			assert.NotNil(t, err, name)
+		} else {
+			assert.Nil(t, err, name)
+			assert.Equal(t, test.expectedArgs, args, name)
+		}
+	}
+}
+
+func TestDescribeSignature(t *testing.T) {
+	contents := []byte("test object")
+
+	gpgSignature, err := signGitObjectUsingGPGKey(contentsFromBytes(contents), artifacts.GPGKey1Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(artifacts.GPGKey1Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshSignature, err := signGitObjectUsingSSHKey(contentsFromBytes(contents), artifacts.SSHED25519Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshKey, _, _, _, err := ssh.ParseAuthorizedKey(artifacts.SSHED25519PublicSSH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		signature   string
+		description string
+	}{
+		"no signature": {
+			signature:   "",
+			description: "no signature",
+		},
+		"GPG signature": {
+			signature:   gpgSignature,
+			description: "GPG signature by key " + gpgKey.KeyID,
+		},
+		"SSH signature": {
+			signature:   sshSignature,
+			description: "SSH signature by key " + ssh.FingerprintSHA256(sshKey),
+		},
+		"Sigstore signature": {
+			signature:   "-----BEGIN SIGNED MESSAGE-----\n-----END SIGNED MESSAGE-----\n",
+			description: "Sigstore signature",
+		},
+		"malformed SSH signature": {
+			signature:   "-----BEGIN SSH SIGNATURE-----\ninvalid\n-----END SSH SIGNATURE-----\n",
+			description: "malformed SSH signature",
+		},
+		"unknown format": {
+			signature:   "signature",
+			description: "signature in an unknown format",
+		},
+	}
+
+	for name, test := range tests {
+		assert.Equal(t, test.description, DescribeSignature(test.signature), name)
+	}
+}
+
+func TestSignGitObjectUsingSSHAgent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SSH agent socket not supported on Windows")
+	}
+
+	privateKey, err := ssh.ParseRawPrivateKey(artifacts.SSHED25519Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: privateKey}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unix socket paths are limited in length, so the socket is not placed in
+	// the test's temporary directory
+	socketDir, err := os.MkdirTemp("", "gittuf-agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(socketDir) }) //nolint:errcheck
+
+	listener, err := net.Listen("unix", filepath.Join(socketDir, "agent.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() }) //nolint:errcheck
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go agent.ServeAgent(keyring, conn) //nolint:errcheck
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", listener.Addr().String())
+
+	t.Cleanup(func() {
+		signingFormatOverride = ""
+		signingKeyOverride = ""
+	})
+	if err := SetSigningFormat("ssh"); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := sslibsv.LoadKey(artifacts.SSHED25519Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := strings.TrimSpace(string(artifacts.SSHED25519PublicSSH))
+	sshPublicKey, _, _, _, err := ssh.ParseAuthorizedKey(artifacts.SSHED25519PublicSSH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := []byte("test commit contents")
+
+	for _, selector := range []string{"", ssh.FingerprintSHA256(sshPublicKey), publicKey} {
+		SetSigningKey(sshagent.KeyPrefix + selector)
+
+		program, _, err := GetSigningCommand()
+		assert.Nil(t, err)
+		assert.Equal(t, SigningProgramSSHAgent, program)
+		assert.Nil(t, CheckSigningKey())
+
+		signature, err := signGitObject(contentsFromBytes(contents))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, verifySSHKeySignature(key, contents, []byte(signature)))
+	}
+
+	SetSigningKey(sshagent.KeyPrefix + "SHA256:unknown")
+	assert.ErrorIs(t, CheckSigningKey(), sshagent.ErrKeyNotInAgent)
+	_, err = signGitObject(contentsFromBytes(contents))
+	assert.ErrorIs(t, err, sshagent.ErrKeyNotInAgent)
+}
+
+func TestSignGitObjectUsingKMS(t *testing.T) {
+	t.Cleanup(func() {
+		signingFormatOverride = ""
+		signingKeyOverride = ""
+	})
+	if err := SetSigningFormat("ssh"); err != nil {
+		t.Fatal(err)
+	}
+	SetSigningKey(fake.ReferenceScheme + "gittuf")
+
+	program, args, err := GetSigningCommand()
+	assert.Nil(t, err)
+	assert.Equal(t, SigningProgramKMS, program)
+	assert.Empty(t, args)
+	assert.Nil(t, CheckSigningKey())
+
+	contents := []byte("test commit contents")
+	signature, err := signGitObject(contentsFromBytes(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The fake KMS creates a new key each time it's used, so the signature
+	// is verified using the public key embedded in it
+	sshSignature, err := sshsig.Unarmor([]byte(signature))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cryptoPublicKey, ok := sshSignature.PublicKey.(ssh.CryptoPublicKey)
+	if !ok {
+		t.Fatal("unexpected public key type")
+	}
+	key, err := sslibsv.NewKey(cryptoPublicKey.CryptoPublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, verifySSHKeySignature(key, contents, []byte(signature)))
+}
+
+func TestSignGitObjectUsingGPGAgent(t *testing.T) {
+	t.Cleanup(func() {
+		signingFormatOverride = ""
+		signingKeyOverride = ""
+	})
+	if err := SetSigningFormat("gpg"); err != nil {
+		t.Fatal(err)
+	}
+	SetSigningKey(gpgagent.KeyPrefix + "0123456789ABCDEF")
+
+	program, args, err := GetSigningCommand()
+	assert.Nil(t, err)
+	assert.Equal(t, SigningProgramGPGAgent, program)
+	assert.Empty(t, args)
+
+	// The key is looked up in GnuPG's public keyring before contacting the
+	// agent
+	t.Setenv("GNUPGHOME", t.TempDir())
+	assert.ErrorIs(t, CheckSigningKey(), gpgagent.ErrKeyNotFound)
+	_, err = signGitObject(contentsFromBytes([]byte("test commit contents")))
+	assert.ErrorIs(t, err, ErrUnableToSign)
+	assert.ErrorIs(t, err, gpgagent.ErrKeyNotFound)
+}
+
+func TestSigningBackend(t *testing.T) {
+	t.Cleanup(func() {
+		signingBackendOverride = ""
+	})
+	t.Setenv("SIGSTORE_ID_TOKEN", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+	t.Setenv("BUILDKITE", "")
+
+	assert.ErrorIs(t, SetSigningBackend("kms"), ErrUnknownSigningBackend)
+
+	if err := SetSigningBackend(SigningBackendSigstore); err != nil {
+		t.Fatal(err)
+	}
+
+	backend, err := GetSigningBackend()
+	assert.Nil(t, err)
+	assert.Equal(t, SigningBackendSigstore, backend)
+
+	signingMethod, keyInfo, program, err := GetSigningInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, SigningMethodX509, signingMethod)
+	assert.Empty(t, keyInfo)
+	assert.Equal(t, SigningProgramSigstore, program)
+
+	// Signing fails early without ambient OIDC credentials
+	_, _, err = GetSigningCommand()
+	assert.ErrorIs(t, err, sigstore.ErrNoAmbientCredentials)
+
+	t.Setenv("SIGSTORE_ID_TOKEN", "token")
+	program, args, err := GetSigningCommand()
+	assert.Nil(t, err)
+	assert.Equal(t, SigningProgramSigstore, program)
+	assert.Empty(t, args)
+}
+
+func TestSigstoreInstance(t *testing.T) {
+	t.Cleanup(func() {
+		sigstoreInstanceOverride = SigstoreInstance{}
+	})
+
+	// Sigstore's public instance is used by default
+	instance := getSigstoreInstance(map[string]string{})
+	assert.Equal(t, &SigstoreInstance{RekorURL: signerverifier.RekorServer, FulcioURL: sigstore.FulcioServer}, instance)
+
+	gitConfig := map[string]string{
+		"gittuf.rekorurl":          "https://rekor.example.com",
+		"gittuf.fulciourl":         "https://fulcio.example.com",
+		"gittuf.fulcioroots":       "/etc/sigstore/fulcio.pem",
+		"gittuf.sigstoretufmirror": "https://tuf.example.com",
+		"gittuf.sigstoretufroot":   "/etc/sigstore/root.json",
+	}
+	instance = getSigstoreInstance(gitConfig)
+	assert.Equal(t, &SigstoreInstance{
+		RekorURL:    "https://rekor.example.com",
+		FulcioURL:   "https://fulcio.example.com",
+		FulcioRoots: "/etc/sigstore/fulcio.pem",
+		TUFMirror:   "https://tuf.example.com",
+		TUFRoot:     "/etc/sigstore/root.json",
+	}, instance)
+
+	// The overrides take precedence over Git's config, and options that
+	// aren't overridden use Git's config
+	SetSigstoreInstance(SigstoreInstance{RekorURL: "https://rekor.internal.example.com"})
+	SetSigstoreInstance(SigstoreInstance{FulcioRoots: "fulcio.pem"})
+	instance = getSigstoreInstance(gitConfig)
+	assert.Equal(t, "https://rekor.internal.example.com", instance.RekorURL)
+	assert.Equal(t, "https://fulcio.example.com", instance.FulcioURL)
+	assert.Equal(t, "fulcio.pem", instance.FulcioRoots)
+
+	// Verification fails if the Fulcio roots can't be loaded, rather than
+	// falling back to the public instance's roots
+	SetSigstoreInstance(SigstoreInstance{FulcioRoots: filepath.Join(t.TempDir(), "missing.pem")})
+	err := verifyGitsignSignature(context.Background(), &tuf.Key{}, []byte("data"), []byte("signature"))
+	assert.ErrorIs(t, err, ErrVerifyingSigstoreSignature)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestSetSigstoreTrustedRoot(t *testing.T) {
+	t.Cleanup(func() {
+		sigstoreOffline = false
+		sigstoreTrustedRootPath = ""
+		sigstoreTrustedRoot = nil
+	})
+
+	trustedRoot, err := getSigstoreTrustedRoot()
+	assert.Nil(t, err)
+	assert.Nil(t, trustedRoot)
+
+	// The trusted root cached by Sigstore's TUF client is used by default
+	t.Setenv("TUF_ROOT", t.TempDir())
+	SetSigstoreTrustedRoot("")
+	_, err = getSigstoreTrustedRoot()
+	assert.ErrorIs(t, err, sigstore.ErrTrustedRootNotCached)
+
+	// Verification fails rather than falling back to online verification
+	err = verifyGitsignSignature(context.Background(), &tuf.Key{}, []byte("data"), []byte("signature"))
+	assert.ErrorIs(t, err, ErrVerifyingSigstoreSignature)
+	assert.ErrorIs(t, err, sigstore.ErrTrustedRootNotCached)
+
+	trustedRootPath := filepath.Join(t.TempDir(), sigstore.TrustedRootFileName)
+	if err := os.WriteFile(trustedRootPath, artifacts.SigstoreTrustedRoot, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	SetSigstoreTrustedRoot(trustedRootPath)
+
+	trustedRoot, err = getSigstoreTrustedRoot()
+	assert.Nil(t, err)
+	assert.Len(t, trustedRoot.CTLogs(), 2)
+}
+
+func TestSignGitObjectUsingEncryptedKey(t *testing.T) {
+	contents := []byte("test commit contents")
+	keyPassphrase := []byte("secret")
+
+	t.Run("ssh key", func(t *testing.T) {
+		privateKey, err := ssh.ParseRawPrivateKey(artifacts.SSHED25519Private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, err := ssh.MarshalPrivateKeyWithPassphrase(privateKey, "", keyPassphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encryptedKey := pem.EncodeToMemory(block)
+
+		key, err := sslibsv.LoadKey(artifacts.SSHED25519Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv(passphrase.EnvKey, string(keyPassphrase))
+		signature, err := signGitObjectUsingKey(contentsFromBytes(contents), encryptedKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, VerifySignature(context.Background(), key, contents, []byte(signature)))
+
+		t.Setenv(passphrase.EnvKey, "incorrect")
+		_, err = signGitObjectUsingKey(contentsFromBytes(contents), encryptedKey)
+		assert.ErrorIs(t, err, passphrase.ErrIncorrectPassphrase)
+	})
+
+	t.Run("gpg key", func(t *testing.T) {
+		key, entity := createTestGPGEntity(t)
+		if err := entity.EncryptPrivateKeys(keyPassphrase, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		encryptedKey := &bytes.Buffer{}
+		writer, err := armor.Encode(encryptedKey, openpgp.PrivateKeyType, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := entity.SerializePrivateWithoutSigning(writer, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv(passphrase.EnvKey, string(keyPassphrase))
+		signature, err := signGitObjectUsingKey(contentsFromBytes(contents), encryptedKey.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, VerifySignature(context.Background(), key, contents, []byte(signature)))
+
+		t.Setenv(passphrase.EnvKey, "incorrect")
+		_, err = signGitObjectUsingKey(contentsFromBytes(contents), encryptedKey.Bytes())
+		assert.ErrorIs(t, err, passphrase.ErrIncorrectPassphrase)
+	})
+
+	t.Run("passphrase not set", func(t *testing.T) {
+		privateKey, err := ssh.ParseRawPrivateKey(artifacts.SSHED25519Private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, err := ssh.MarshalPrivateKeyWithPassphrase(privateKey, "", keyPassphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv(interactive.NonInteractiveModeKey, "1")
+		_, err = signGitObjectUsingKey(contentsFromBytes(contents), pem.EncodeToMemory(block))
+		assert.ErrorIs(t, err, passphrase.ErrPassphraseRequired)
+	})
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/hiddeco/sshsig"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestExpandHomeDirWindows(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("USERPROFILE", homeDir)
+
+	path, err := ExpandHomeDir(`~\.ssh\id_ed25519`)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(homeDir, ".ssh", "id_ed25519"), path)
+
+	path, err = ExpandHomeDir(`C:\Users\jane\.ssh\id_ed25519`)
+	assert.Nil(t, err)
+	assert.Equal(t, `C:\Users\jane\.ssh\id_ed25519`, path)
+}
+
+func TestIsGitsignWindows(t *testing.T) {
+	assert.True(t, IsGitsign(`C:\Program Files\gitsign\gitsign.exe`))
+	assert.True(t, IsGitsign(`C:\Program Files\gitsign\GITSIGN.EXE`))
+	assert.False(t, IsGitsign(`C:\Program Files\GnuPG\bin\gpgsm.exe`))
+}
+
+func TestNormalizeSigningProgramWindows(t *testing.T) {
+	programFiles := t.TempDir()
+	t.Setenv("ProgramFiles", programFiles)
+	t.Setenv("ProgramFiles(x86)", "")
+	t.Setenv("SystemRoot", "")
+	t.Setenv("PATH", t.TempDir())
+
+	gpgPath := filepath.Join(programFiles, "GnuPG", "bin", "gpg.exe")
+	sshKeygenPath := filepath.Join(programFiles, "Git", "usr", "bin", "ssh-keygen.exe")
+	for _, programPath := range []string{gpgPath, sshKeygenPath} {
+		if err := os.MkdirAll(filepath.Dir(programPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(programPath, []byte{}, 0o755); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]struct {
+		program         string
+		expectedProgram string
+	}{
+		"default gpg not on PATH": {
+			program:         DefaultSigningProgramGPG,
+			expectedProgram: gpgPath,
+		},
+		"default ssh-keygen not on PATH": {
+			program:         DefaultSigningProgramSSH,
+			expectedProgram: sshKeygenPath,
+		},
+		"program not installed": {
+			program:         DefaultSigningProgramX509,
+			expectedProgram: DefaultSigningProgramX509,
+		},
+		"quoted path": {
+			program:         fmt.Sprintf(`"%s"`, gpgPath),
+			expectedProgram: gpgPath,
+		},
+		"path without .exe": {
+			program:         strings.TrimSuffix(gpgPath, ".exe"),
+			expectedProgram: gpgPath,
+		},
+		"path with forward slashes": {
+			program:         filepath.ToSlash(gpgPath),
+			expectedProgram: gpgPath,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			program, err := normalizeSigningProgram(test.program)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedProgram, program)
+		})
+	}
+}
+
+// TestSignGitObjectWindows signs using ssh-keygen installed in a directory
+// with spaces in its path, configured the way Git for Windows users typically
+// configure it.
+func TestSignGitObjectWindows(t *testing.T) {
+	t.Cleanup(func() {
+		getGitConfigFromCommand = execGitConfig
+	})
+
+	sshKeygenPath, err := exec.LookPath(DefaultSigningProgramSSH)
+	if err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+
+	homeDir := t.TempDir()
+	t.Setenv("USERPROFILE", homeDir)
+
+	keysDir := filepath.Join(homeDir, "signing keys")
+	if err := os.Mkdir(keysDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	setupSigningKeys(t, keysDir)
+
+	programDir := filepath.Join(t.TempDir(), "Program Files", "OpenSSH")
+	if err := os.MkdirAll(programDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	programPath := filepath.Join(programDir, "ssh-keygen.exe")
+	sshKeygen, err := os.ReadFile(sshKeygenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(programPath, sshKeygen, 0o755); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+
+	configOutput := fmt.Sprintf("global\x00gpg.format\nssh\x00global\x00user.signingkey\n~\\signing keys\\key\x00global\x00gpg.ssh.program\n\"%s\"\x00", programPath)
+	getGitConfigFromCommand = func() (io.Reader, error) {
+		return bytes.NewReader([]byte(configOutput)), nil
+	}
+
+	program, args, err := GetSigningCommand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, programPath, program)
+	assert.Contains(t, args, filepath.Join(keysDir, "key"))
+
+	assert.Nil(t, CheckSigningKey())
+
+	contents := []byte("test object")
+	signature, err := signGitObject(contentsFromBytes(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(artifacts.SSHED25519PublicSSH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshSignature, err := sshsig.Unarmor([]byte(signature))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, sshsig.Verify(bytes.NewReader(contents), sshSignature, publicKey, sshSignature.HashAlgorithm, namespaceSSHSignature))
+}
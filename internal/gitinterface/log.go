@@ -38,12 +38,11 @@ func GetCommitsBetweenRange(repo *git.Repository, commitNewID, commitOldID plumb
 			return nil, err
 		}
 	} else {
-		reachableFromCommitOld, err := revlist.Objects(repo.Storer, []plumbing.Hash{commitOldID}, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		commitRange, err = revlist.Objects(repo.Storer, []plumbing.Hash{commitNewID}, reachableFromCommitOld)
+		// The objects reachable from the old commit are permitted to be
+		// missing, such as beyond the boundary of a shallow clone. If the
+		// range itself crosses the boundary, plumbing.ErrObjectNotFound is
+		// returned.
+		commitRange, err = revlist.ObjectsWithStorageForIgnores(repo.Storer, repo.Storer, []plumbing.Hash{commitNewID}, []plumbing.Hash{commitOldID})
 		if err != nil {
 			return nil, err
 		}
@@ -268,6 +268,41 @@ func TestGetCommitsBetweenRangeForMergeCommits(t *testing.T) {
 	})
 }
 
+func TestGetCommitsBetweenRangeWithMissingHistory(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	treeID, err := WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := []plumbing.Hash{}
+	parentIDs := []plumbing.Hash{}
+	for i := 0; i < 4; i++ {
+		commitID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, treeID, parentIDs, fmt.Sprintf("Test commit %v", i+1), testClock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitIDs = append(commitIDs, commitID)
+		parentIDs = []plumbing.Hash{commitID}
+	}
+
+	// Remove the first commit, as beyond the boundary of a shallow clone
+	storage := repo.Storer.(*memory.Storage)
+	delete(storage.Objects, commitIDs[0])
+	delete(storage.Commits, commitIDs[0])
+
+	commits, err := GetCommitsBetweenRange(repo, commitIDs[3], commitIDs[1])
+	assert.Nil(t, err)
+	assert.Len(t, commits, 2)
+
+	_, err = GetCommitsBetweenRange(repo, commitIDs[3], commitIDs[0])
+	assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+}
+
 func GetCommitsFromCommitIDs(commitIDs []plumbing.Hash, repo *git.Repository) ([]*object.Commit, error) {
 	allCommits := make([]*object.Commit, 0, len(commitIDs))
 	for _, commitID := range commitIDs {
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/idxfile"
+)
+
+// QuarantinePathKey is the environment variable Git sets for the pre-receive
+// hook to the object directory that holds the objects of the push until the
+// push is accepted. If the push is rejected, the directory is removed.
+const QuarantinePathKey = "GIT_QUARANTINE_PATH"
+
+// GetObjectsInObjectDir returns the IDs of the objects stored in the object
+// directory, both as loose objects and in packfiles, sorted by ID. Objects in
+// the directory's alternates are not included. This is used to inspect the
+// objects of a push, which Git stores in a quarantine directory, see
+// QuarantinePathKey.
+func GetObjectsInObjectDir(objectDir string) ([]plumbing.Hash, error) {
+	objectIDs := map[plumbing.Hash]bool{}
+
+	entries, err := os.ReadDir(objectDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		// Loose objects are stored in directories named using the first two
+		// hex characters of their IDs
+		if !entry.IsDir() || len(entry.Name()) != 2 {
+			continue
+		}
+
+		files, err := os.ReadDir(filepath.Join(objectDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			objectID := entry.Name() + file.Name()
+			if !file.IsDir() && plumbing.IsHash(objectID) {
+				objectIDs[plumbing.NewHash(objectID)] = true
+			}
+		}
+	}
+
+	indexPaths, err := filepath.Glob(filepath.Join(objectDir, "pack", "*.idx"))
+	if err != nil {
+		return nil, err
+	}
+	for _, indexPath := range indexPaths {
+		if err := readPackIndex(indexPath, objectIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	sortedObjectIDs := make([]plumbing.Hash, 0, len(objectIDs))
+	for objectID := range objectIDs {
+		sortedObjectIDs = append(sortedObjectIDs, objectID)
+	}
+	sort.Slice(sortedObjectIDs, func(i, j int) bool {
+		return bytes.Compare(sortedObjectIDs[i][:], sortedObjectIDs[j][:]) < 0
+	})
+
+	return sortedObjectIDs, nil
+}
+
+// readPackIndex adds the IDs of the objects in the packfile index at the
+// specified path to objectIDs.
+func readPackIndex(indexPath string, objectIDs map[plumbing.Hash]bool) error {
+	file, err := os.Open(indexPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close() //nolint:errcheck
+
+	index := idxfile.NewMemoryIndex()
+	if err := idxfile.NewDecoder(file).Decode(index); err != nil {
+		return err
+	}
+
+	entries, err := index.Entries()
+	if err != nil {
+		return err
+	}
+	defer entries.Close() //nolint:errcheck
+
+	for {
+		entry, err := entries.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		objectIDs[entry.Hash] = true
+	}
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetObjectsInObjectDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	objectDir := filepath.Join(tmpDir, ".git", "objects")
+
+	runGit := func(args ...string) plumbing.Hash {
+		t.Helper()
+
+		args = append([]string{"-C", tmpDir, "-c", "user.name=" + testName, "-c", "user.email=" + testEmail, "-c", "commit.gpgsign=false"}, args...)
+		output, err := exec.Command(binary, args...).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return plumbing.NewHash(strings.TrimSpace(string(output)))
+	}
+
+	runGit("init", "--quiet", "-b", "main")
+
+	objectIDs, err := GetObjectsInObjectDir(objectDir)
+	assert.Nil(t, err)
+	assert.Empty(t, objectIDs)
+
+	runGit("commit", "--quiet", "--allow-empty", "-m", "First commit")
+	firstCommitID := runGit("rev-parse", "HEAD")
+
+	objectIDs, err = GetObjectsInObjectDir(objectDir)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []plumbing.Hash{EmptyTree(), firstCommitID}, objectIDs)
+
+	// Packed objects are included along with loose objects
+	runGit("repack", "-a", "-d", "-q")
+	runGit("commit", "--quiet", "--allow-empty", "-m", "Second commit")
+	secondCommitID := runGit("rev-parse", "HEAD")
+
+	objectIDs, err = GetObjectsInObjectDir(objectDir)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []plumbing.Hash{EmptyTree(), firstCommitID, secondCommitID}, objectIDs)
+
+	_, err = GetObjectsInObjectDir(filepath.Join(tmpDir, "missing"))
+	assert.NotNil(t, err)
+}
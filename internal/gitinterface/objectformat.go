@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/hash"
+)
+
+const (
+	ObjectFormatSHA1   = "sha1"
+	ObjectFormatSHA256 = "sha256"
+
+	objectFormatKey = "extensions.objectformat"
+)
+
+var (
+	ErrUnknownObjectFormat     = errors.New("unknown object format")
+	ErrUnsupportedObjectFormat = errors.New("repository's object format is not supported by go-git in this build of gittuf")
+)
+
+// ObjectFormatOf returns the object format of the object ID, which is
+// determined by its length.
+func ObjectFormatOf(objectID Hash) string {
+	if len(objectID) == len(zeroSHA256HashBytes) {
+		return ObjectFormatSHA256
+	}
+
+	return ObjectFormatSHA1
+}
+
+// GetObjectFormat returns the object format of the repository, which is
+// recorded in extensions.objectFormat for repositories that don't use SHA-1.
+func GetObjectFormat(repo *git.Repository) (string, error) {
+	config, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeObjectFormat(config.Raw.Section("extensions").Option("objectformat"))
+}
+
+// GetObjectFormat returns the object format of the repository, which is
+// recorded in extensions.objectFormat for repositories that don't use SHA-1.
+func (r *Repository) GetObjectFormat() (string, error) {
+	config, err := r.GetGitConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeObjectFormat(config[objectFormatKey])
+}
+
+// ZeroHash returns the zero hash for the repository's object format, which Git
+// uses to indicate the absence of an object, such as when a reference is
+// created or deleted.
+func (r *Repository) ZeroHash() (Hash, error) {
+	objectFormat, err := r.GetObjectFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	if objectFormat == ObjectFormatSHA256 {
+		return Hash(zeroSHA256HashBytes[:]), nil
+	}
+	return Hash(zeroSHA1HashBytes[:]), nil
+}
+
+// CheckObjectFormat returns an error if go-git can't read the repository's
+// objects, as their IDs would otherwise be silently truncated or padded to
+// the length of plumbing.Hash. Unlike Hash, plumbing.Hash has a fixed length
+// that's selected when go-git is built, using the `sha256` build tag.
+// Repositories opened using OpenRepository are checked automatically, this
+// must be called for repositories opened using go-git directly.
+func CheckObjectFormat(repo *git.Repository) error {
+	objectFormat, err := GetObjectFormat(repo)
+	if err != nil {
+		return err
+	}
+
+	if objectFormat != goGitObjectFormat() {
+		return unsupportedObjectFormatError(objectFormat)
+	}
+
+	return nil
+}
+
+// ParseObjectID returns the plumbing.Hash of the hex encoded object ID. An
+// error wrapping ErrUnsupportedObjectFormat is returned for object IDs of the
+// object format go-git wasn't built for, rather than misparsing them.
+func ParseObjectID(objectID string) (plumbing.Hash, error) {
+	parsed, err := NewHash(objectID)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if objectFormat := ObjectFormatOf(parsed); objectFormat != goGitObjectFormat() {
+		return plumbing.ZeroHash, unsupportedObjectFormatError(objectFormat)
+	}
+
+	return plumbing.NewHash(objectID), nil
+}
+
+// goGitObjectFormat returns the object format go-git was built for.
+func goGitObjectFormat() string {
+	if hash.CryptoType == crypto.SHA256 {
+		return ObjectFormatSHA256
+	}
+
+	return ObjectFormatSHA1
+}
+
+func unsupportedObjectFormatError(objectFormat string) error {
+	rebuild := "rebuild gittuf with -tags sha256"
+	if objectFormat == ObjectFormatSHA1 {
+		rebuild = "rebuild gittuf without -tags sha256"
+	}
+
+	return fmt.Errorf("%w: found %s object IDs, go-git was built for %s object IDs, %s", ErrUnsupportedObjectFormat, objectFormat, goGitObjectFormat(), rebuild)
+}
+
+func normalizeObjectFormat(objectFormat string) (string, error) {
+	switch strings.ToLower(objectFormat) {
+	case "", ObjectFormatSHA1:
+		return ObjectFormatSHA1, nil
+	case ObjectFormatSHA256:
+		return ObjectFormatSHA256, nil
+	}
+
+	return "", fmt.Errorf("%w '%s'", ErrUnknownObjectFormat, objectFormat)
+}
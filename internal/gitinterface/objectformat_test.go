@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetObjectFormat(t *testing.T) {
+	for _, objectFormat := range []string{ObjectFormatSHA1, ObjectFormatSHA256} {
+		t.Run(objectFormat, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if output, err := exec.Command(binary, "init", "--quiet", "--object-format", objectFormat, tmpDir).CombinedOutput(); err != nil {
+				t.Fatalf("%s: %s", err, output)
+			}
+
+			repo := &Repository{gitDirPath: filepath.Join(tmpDir, ".git")}
+			defer repo.Close() //nolint:errcheck
+
+			repoObjectFormat, err := repo.GetObjectFormat()
+			assert.Nil(t, err)
+			assert.Equal(t, objectFormat, repoObjectFormat)
+
+			zeroHash, err := repo.ZeroHash()
+			assert.Nil(t, err)
+			assert.True(t, zeroHash.IsZero())
+			assert.Equal(t, objectFormat, ObjectFormatOf(zeroHash))
+
+			goGitRepo, err := OpenRepository(tmpDir)
+			if objectFormat != goGitObjectFormat() {
+				assert.ErrorIs(t, err, ErrUnsupportedObjectFormat)
+				return
+			}
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			repoObjectFormat, err = GetObjectFormat(goGitRepo)
+			assert.Nil(t, err)
+			assert.Equal(t, objectFormat, repoObjectFormat)
+		})
+	}
+}
+
+func TestNormalizeObjectFormat(t *testing.T) {
+	objectFormat, err := normalizeObjectFormat("")
+	assert.Nil(t, err)
+	assert.Equal(t, ObjectFormatSHA1, objectFormat)
+
+	objectFormat, err = normalizeObjectFormat("SHA256")
+	assert.Nil(t, err)
+	assert.Equal(t, ObjectFormatSHA256, objectFormat)
+
+	_, err = normalizeObjectFormat("md5")
+	assert.ErrorIs(t, err, ErrUnknownObjectFormat)
+}
+
+func TestObjectFormatOf(t *testing.T) {
+	assert.Equal(t, ObjectFormatSHA1, ObjectFormatOf(Hash(zeroSHA1HashBytes[:])))
+	assert.Equal(t, ObjectFormatSHA256, ObjectFormatOf(Hash(zeroSHA256HashBytes[:])))
+}
+
+func TestParseObjectID(t *testing.T) {
+	supportedID, unsupportedID := Hash(zeroSHA1HashBytes[:]), Hash(zeroSHA256HashBytes[:])
+	if goGitObjectFormat() == ObjectFormatSHA256 {
+		supportedID, unsupportedID = unsupportedID, supportedID
+	}
+	supportedID[0], unsupportedID[0] = 0xab, 0xab
+
+	objectID, err := ParseObjectID(supportedID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, plumbing.NewHash(supportedID.String()), objectID)
+
+	_, err = ParseObjectID(unsupportedID.String())
+	assert.ErrorIs(t, err, ErrUnsupportedObjectFormat)
+	assert.ErrorContains(t, err, "rebuild gittuf")
+
+	_, err = ParseObjectID("not-a-hash")
+	assert.ErrorIs(t, err, ErrInvalidHashLength)
+}
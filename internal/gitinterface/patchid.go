@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var ErrNoPatchID = errors.New("commit does not introduce changes that have a patch ID")
+
+// GetPatchID returns the patch ID of the specified commit. See GetPatchIDs for
+// more details.
+func GetPatchID(repo *git.Repository, commitID plumbing.Hash) (plumbing.Hash, error) {
+	patchIDs, err := GetPatchIDs(repo, []plumbing.Hash{commitID})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	patchID, has := patchIDs[commitID]
+	if !has {
+		return plumbing.ZeroHash, ErrNoPatchID
+	}
+
+	return patchID, nil
+}
+
+// GetPatchIDs returns the patch IDs of the specified commits, computed in a
+// single batch. A patch ID is a hash of the changes a commit introduces
+// relative to its parent, ignoring whitespace and line numbers, so a commit
+// that is cherry-picked or rebased onto a different base has the same patch ID
+// as the original commit as long as the changes apply cleanly. The patch IDs
+// match those computed by `git patch-id --stable` and used by `git cherry`.
+//
+// Merge commits and commits that don't change their parent's tree don't have a
+// patch ID, and are not included in the returned map. Computing patch IDs
+// requires Git to be installed and the repository to be stored on disk.
+func GetPatchIDs(repo *git.Repository, commitIDs []plumbing.Hash) (map[plumbing.Hash]plumbing.Hash, error) {
+	patchIDs := map[plumbing.Hash]plumbing.Hash{}
+	if len(commitIDs) == 0 {
+		return patchIDs, nil
+	}
+
+	gitDir, err := GetGitCommonDirFor(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &bytes.Buffer{}
+	for _, commitID := range commitIDs {
+		// diff-tree passes through input it doesn't recognize as a commit, so
+		// we check the commits exist first
+		if _, err := GetCommit(repo, commitID); err != nil {
+			return nil, err
+		}
+		input.WriteString(commitID.String() + "\n")
+	}
+
+	// Renames are not detected, like in `git cherry`, and binary changes are
+	// included in full so that different changes to a binary file don't
+	// have the same patch ID
+	diffTree := newGitCommand("--git-dir", gitDir, "diff-tree", "--stdin", "--root", "-p", "--binary", "--no-renames")
+	patchID := newGitCommand("patch-id", "--stable")
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		diffTreeStdErr bytes.Buffer
+		patchIDStdOut  bytes.Buffer
+		patchIDStdErr  bytes.Buffer
+	)
+
+	diffTree.Stdin = input
+	diffTree.Stdout = writer
+	diffTree.Stderr = &diffTreeStdErr
+	patchID.Stdin = reader
+	patchID.Stdout = &patchIDStdOut
+	patchID.Stderr = &patchIDStdErr
+
+	if err := diffTree.Start(); err != nil {
+		writer.Close() //nolint:errcheck
+		reader.Close() //nolint:errcheck
+		return nil, err
+	}
+	patchIDStartErr := patchID.Start()
+
+	// Both processes have their own copies of the pipe, so closing ours
+	// ensures that each sees the other exit
+	writer.Close() //nolint:errcheck
+	reader.Close() //nolint:errcheck
+
+	if patchIDStartErr != nil {
+		diffTree.Wait() //nolint:errcheck
+		return nil, patchIDStartErr
+	}
+
+	patchIDErr := patchID.Wait()
+	if err := diffTree.Wait(); err != nil {
+		return nil, fmt.Errorf("%w when executing `git diff-tree`: %s", err, strings.TrimSpace(diffTreeStdErr.String()))
+	}
+	if patchIDErr != nil {
+		return nil, fmt.Errorf("%w when executing `git patch-id`: %s", patchIDErr, strings.TrimSpace(patchIDStdErr.String()))
+	}
+
+	scanner := bufio.NewScanner(&patchIDStdOut)
+	for scanner.Scan() {
+		// Each line is of the form `<patch-id> <commit-id>`
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || !plumbing.IsHash(fields[0]) || !plumbing.IsHash(fields[1]) {
+			return nil, fmt.Errorf("unexpected output from `git patch-id`: '%s'", scanner.Text())
+		}
+		patchIDs[plumbing.NewHash(fields[1])] = plumbing.NewHash(fields[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return patchIDs, nil
+}
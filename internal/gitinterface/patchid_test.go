@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPatchIDs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	runGit := func(args ...string) string {
+		t.Helper()
+
+		args = append([]string{"-C", tmpDir, "-c", "user.name=" + testName, "-c", "user.email=" + testEmail, "-c", "commit.gpgsign=false"}, args...)
+		output, err := exec.Command(binary, args...).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	writeFile := func(name, contents string) {
+		t.Helper()
+
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(contents), 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+	}
+
+	runGit("init", "--quiet", "-b", "main")
+	writeFile("a", "1\n2\n3\n")
+	writeFile("b", "1\n")
+	runGit("add", "a", "b")
+	runGit("commit", "--quiet", "-m", "Initial commit")
+	rootCommitID := runGit("rev-parse", "HEAD")
+
+	writeFile("a", "1\n2\n3\n4\n")
+	runGit("commit", "--quiet", "-a", "-m", "Change a")
+	originalCommitID := runGit("rev-parse", "HEAD")
+
+	// Cherry-pick the change onto a different base
+	runGit("checkout", "--quiet", "-b", "feature", rootCommitID)
+	writeFile("b", "1\n2\n")
+	runGit("commit", "--quiet", "-a", "-m", "Change b")
+	otherCommitID := runGit("rev-parse", "HEAD")
+	runGit("cherry-pick", originalCommitID)
+	cherryPickedCommitID := runGit("rev-parse", "HEAD")
+
+	runGit("commit", "--quiet", "--allow-empty", "-m", "Empty commit")
+	emptyCommitID := runGit("rev-parse", "HEAD")
+
+	runGit("merge", "--quiet", "--no-ff", "-m", "Merge commit", "main")
+	mergeCommitID := runGit("rev-parse", "HEAD")
+
+	repo, err := OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := []plumbing.Hash{}
+	for _, commitID := range []string{rootCommitID, originalCommitID, otherCommitID, cherryPickedCommitID, emptyCommitID, mergeCommitID} {
+		commitIDs = append(commitIDs, plumbing.NewHash(commitID))
+	}
+
+	patchIDs, err := GetPatchIDs(repo, commitIDs)
+	assert.Nil(t, err)
+	assert.Len(t, patchIDs, 4)
+	assert.Equal(t, patchIDs[plumbing.NewHash(originalCommitID)], patchIDs[plumbing.NewHash(cherryPickedCommitID)])
+	assert.NotEqual(t, patchIDs[plumbing.NewHash(originalCommitID)], patchIDs[plumbing.NewHash(otherCommitID)])
+	assert.NotEqual(t, patchIDs[plumbing.NewHash(rootCommitID)], patchIDs[plumbing.NewHash(otherCommitID)])
+
+	// The patch IDs match those computed by Git
+	for _, commitID := range []string{rootCommitID, originalCommitID, otherCommitID} {
+		command := exec.Command(binary, "-C", tmpDir, "show", "--no-renames", commitID)
+		patch, err := command.Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		command = exec.Command(binary, "patch-id", "--stable")
+		command.Stdin = strings.NewReader(string(patch))
+		output, err := command.Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedPatchID := strings.Fields(string(output))[0]
+		assert.Equal(t, plumbing.NewHash(expectedPatchID), patchIDs[plumbing.NewHash(commitID)])
+	}
+
+	t.Run("single commit", func(t *testing.T) {
+		patchID, err := GetPatchID(repo, plumbing.NewHash(cherryPickedCommitID))
+		assert.Nil(t, err)
+		assert.Equal(t, patchIDs[plumbing.NewHash(originalCommitID)], patchID)
+
+		_, err = GetPatchID(repo, plumbing.NewHash(emptyCommitID))
+		assert.ErrorIs(t, err, ErrNoPatchID)
+
+		_, err = GetPatchID(repo, plumbing.NewHash(mergeCommitID))
+		assert.ErrorIs(t, err, ErrNoPatchID)
+	})
+
+	t.Run("no commits", func(t *testing.T) {
+		patchIDs, err := GetPatchIDs(repo, nil)
+		assert.Nil(t, err)
+		assert.Empty(t, patchIDs)
+	})
+
+	t.Run("missing commit", func(t *testing.T) {
+		_, err := GetPatchIDs(repo, []plumbing.Hash{plumbing.NewHash("0123456789abcdef0123456789abcdef01234567")})
+		assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+	})
+
+	t.Run("in-memory repository", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = GetPatchIDs(repo, commitIDs)
+		assert.ErrorIs(t, err, ErrRepositoryNotOnDisk)
+	})
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	extensionsSection       = "extensions"
+	remoteSection           = "remote"
+	partialCloneOption      = "partialclone"
+	promisorOption          = "promisor"
+	promisorFetchFilter     = "blob:none"
+	promisorFetchBatchSize  = 1000
+	promisorNegotiationNoop = "fetch.negotiationAlgorithm=noop"
+)
+
+// GetPromisorRemote returns the name of the remote that promises to provide
+// the objects missing in a partial clone, such as one created using `git clone
+// --filter=blob:none`. An empty string is returned if the repository isn't a
+// partial clone.
+func GetPromisorRemote(repo *git.Repository) (string, error) {
+	config, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+
+	if remote := config.Raw.Section(extensionsSection).Option(partialCloneOption); remote != "" {
+		return remote, nil
+	}
+
+	// Git also treats remotes that are marked as promisors as such
+	for _, subsection := range config.Raw.Section(remoteSection).Subsections {
+		if isTrue(subsection.Option(promisorOption)) {
+			return subsection.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// PrefetchObjects fetches the specified objects from the promisor remote if
+// the repository is a partial clone and they're missing locally. The objects
+// are requested in batches, which avoids a round trip to the remote per object
+// when they're needed one at a time. Objects that are still missing after
+// prefetching are fetched on demand when they're read. For repositories that
+// aren't partial clones, this is a no-op.
+func PrefetchObjects(repo *git.Repository, objectIDs []plumbing.Hash) error {
+	storage, isPromisor := repo.Storer.(*promisorStorage)
+	if !isPromisor {
+		return nil
+	}
+
+	missingIDs := []plumbing.Hash{}
+	seen := map[plumbing.Hash]bool{}
+	for _, objectID := range objectIDs {
+		if objectID.IsZero() || seen[objectID] {
+			continue
+		}
+		seen[objectID] = true
+
+		if err := storage.Storage.HasEncodedObject(objectID); err != nil {
+			if !errors.Is(err, plumbing.ErrObjectNotFound) {
+				return err
+			}
+			missingIDs = append(missingIDs, objectID)
+		}
+	}
+
+	return storage.fetch(missingIDs)
+}
+
+// enablePromisorFetching configures the repository to fetch objects missing
+// locally from the promisor remote when they're read, if it's a partial clone.
+func enablePromisorFetching(repo *git.Repository) error {
+	storage, ok := repo.Storer.(*cachedStorage)
+	if !ok {
+		return nil
+	}
+
+	remote, err := GetPromisorRemote(repo)
+	if err != nil {
+		return err
+	}
+	if remote == "" {
+		return nil
+	}
+
+	commonDir, err := GetGitCommonDirFor(repo)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Repository is a partial clone, missing objects will be fetched from '%s'", remote))
+	repo.Storer = &promisorStorage{
+		cachedStorage: storage,
+		gitRepo:       &Repository{gitDirPath: commonDir},
+		remote:        remote,
+	}
+	return nil
+}
+
+// promisorStorage wraps the storage of a partial clone, fetching objects that
+// are missing locally from the promisor remote when they're read. This mirrors
+// how Git lazily fetches missing objects.
+type promisorStorage struct {
+	*cachedStorage
+
+	gitRepo *Repository
+	remote  string
+	mu      sync.Mutex
+}
+
+// EncodedObject returns the requested object, fetching it from the promisor
+// remote if it's missing locally.
+func (s *promisorStorage) EncodedObject(objectType plumbing.ObjectType, objectID plumbing.Hash) (plumbing.EncodedObject, error) {
+	object, err := s.Storage.EncodedObject(objectType, objectID)
+	if !errors.Is(err, plumbing.ErrObjectNotFound) {
+		return object, err
+	}
+
+	if err := s.fetch([]plumbing.Hash{objectID}); err != nil {
+		return nil, err
+	}
+
+	return s.Storage.EncodedObject(objectType, objectID)
+}
+
+// fetch requests the objects from the promisor remote in batches. The fetches
+// are invoked like Git's own lazy fetches, so that the remote doesn't send
+// objects reachable from the requested objects that aren't needed.
+func (s *promisorStorage) fetch(objectIDs []plumbing.Hash) error {
+	if len(objectIDs) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for start := 0; start < len(objectIDs); start += promisorFetchBatchSize {
+		end := min(start+promisorFetchBatchSize, len(objectIDs))
+
+		stdIn := new(bytes.Buffer)
+		for _, objectID := range objectIDs[start:end] {
+			stdIn.WriteString(objectID.String() + "\n")
+		}
+
+		logger.Debug(fmt.Sprintf("Fetching %d missing object(s) from promisor remote '%s'...", end-start, s.remote))
+		if _, err := s.gitRepo.executeGitCommandWithStdInString(stdIn, "-c", promisorNegotiationNoop, "fetch", s.remote, "--no-tags", "--no-write-fetch-head", "--recurse-submodules=no", "--filter="+promisorFetchFilter, "--stdin"); err != nil {
+			return fmt.Errorf("unable to fetch missing objects from promisor remote '%s': %w", s.remote, err)
+		}
+	}
+
+	// The fetched objects are written to new packfiles
+	s.Storage.Reindex()
+	return nil
+}
+
+// isTrue returns true if the Git config value is a boolean set to true.
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromisorFetching(t *testing.T) {
+	sourceDir := t.TempDir()
+	cloneDir := t.TempDir()
+
+	runGit := func(args ...string) string {
+		t.Helper()
+
+		output, err := exec.Command(binary, args...).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	runGit("init", "-b", "main", sourceDir)
+	runGit("-C", sourceDir, "config", "uploadpack.allowFilter", "true")
+	runGit("-C", sourceDir, "config", "uploadpack.allowAnySHA1InWant", "true")
+	for name, contents := range map[string]string{"README.md": "gittuf", "LICENSE": "Apache-2.0"} {
+		if err := os.WriteFile(filepath.Join(sourceDir, name), []byte(contents), 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+	}
+	runGit("-C", sourceDir, "add", ".")
+	runGit("-C", sourceDir, "-c", "user.name="+testName, "-c", "user.email="+testEmail, "-c", "commit.gpgsign=false", "commit", "-m", "Initial commit")
+
+	readmeID := plumbing.NewHash(runGit("-C", sourceDir, "rev-parse", "HEAD:README.md"))
+	licenseID := plumbing.NewHash(runGit("-C", sourceDir, "rev-parse", "HEAD:LICENSE"))
+
+	runGit("clone", "--quiet", "--no-checkout", "--filter=blob:none", "file://"+sourceDir, cloneDir)
+
+	repo, err := OpenRepository(cloneDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote, err := GetPromisorRemote(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, "origin", remote)
+
+	storage, isPromisor := repo.Storer.(*promisorStorage)
+	if !assert.True(t, isPromisor) {
+		return
+	}
+
+	t.Run("fetch on demand", func(t *testing.T) {
+		assert.ErrorIs(t, storage.Storage.HasEncodedObject(readmeID), plumbing.ErrObjectNotFound)
+
+		contents, err := ReadBlob(repo, readmeID)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("gittuf"), contents)
+
+		assert.Nil(t, storage.Storage.HasEncodedObject(readmeID))
+	})
+
+	t.Run("prefetch", func(t *testing.T) {
+		assert.ErrorIs(t, storage.Storage.HasEncodedObject(licenseID), plumbing.ErrObjectNotFound)
+
+		err := PrefetchObjects(repo, []plumbing.Hash{licenseID, readmeID})
+		assert.Nil(t, err)
+
+		assert.Nil(t, storage.Storage.HasEncodedObject(licenseID))
+	})
+
+	t.Run("not a partial clone", func(t *testing.T) {
+		sourceRepo, err := OpenRepository(sourceDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		remote, err := GetPromisorRemote(sourceRepo)
+		assert.Nil(t, err)
+		assert.Empty(t, remote)
+
+		_, isPromisor := sourceRepo.Storer.(*promisorStorage)
+		assert.False(t, isPromisor)
+
+		err = PrefetchObjects(sourceRepo, []plumbing.Hash{readmeID})
+		assert.Nil(t, err)
+	})
+}
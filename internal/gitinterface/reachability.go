@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"container/heap"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object/commitgraph"
+)
+
+// reachabilityCacheSize is the number of commits whose history walks are
+// cached. Each walk records the commits visited so far, so this bounds the
+// memory used for the walks.
+const reachabilityCacheSize = 32
+
+// getReachabilityWalk returns the walk of the history of the commit identified
+// by descendantID. If caching is enabled for the repository, the walk is
+// shared by all ancestry checks for the commit, so that checks against the
+// same commit, such as the target of an RSL entry checked for many commits,
+// resume the walk rather than starting over.
+func getReachabilityWalk(repo *git.Repository, descendantID plumbing.Hash) *reachabilityWalk {
+	storage := getCachedStorage(repo)
+	if storage == nil {
+		return &reachabilityWalk{descendantID: descendantID}
+	}
+
+	storage.reachabilityMu.Lock()
+	defer storage.reachabilityMu.Unlock()
+
+	walk, has := storage.reachability.get(descendantID)
+	if !has {
+		walk = &reachabilityWalk{descendantID: descendantID}
+		storage.reachability.add(descendantID, walk)
+	}
+
+	return walk
+}
+
+// reachabilityWalk is a walk of the history of a commit in descending order of
+// generation numbers, which is paused once it has visited every commit whose
+// generation is at least that of the commit being checked. As a commit's
+// generation is greater than the generations of its parents, any commit that
+// hasn't been seen by then is not an ancestor. Commits are immutable, so the
+// walk never has to be invalidated.
+type reachabilityWalk struct {
+	descendantID plumbing.Hash
+
+	seen  map[plumbing.Hash]bool
+	queue generationQueue
+	mu    sync.Mutex
+}
+
+// reaches returns true if the commit identified by ancestorID with the
+// specified generation is reachable from the walk's commit, continuing the
+// walk as needed.
+func (w *reachabilityWalk) reaches(nodeIndex commitgraph.CommitNodeIndex, ancestorID plumbing.Hash, ancestorGeneration uint64) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.seen == nil {
+		descendant, err := nodeIndex.Get(w.descendantID)
+		if err != nil {
+			return false, err
+		}
+
+		w.seen = map[plumbing.Hash]bool{w.descendantID: true}
+		w.queue = generationQueue{{id: w.descendantID, generation: descendant.Generation()}}
+	}
+
+	for !w.seen[ancestorID] && len(w.queue) != 0 && w.queue[0].generation >= ancestorGeneration {
+		entry := heap.Pop(&w.queue).(generationQueueEntry)
+
+		node, err := nodeIndex.Get(entry.id)
+		if err == nil {
+			err = node.ParentNodes().ForEach(func(parent commitgraph.CommitNode) error {
+				if !w.seen[parent.ID()] {
+					w.seen[parent.ID()] = true
+					heap.Push(&w.queue, generationQueueEntry{id: parent.ID(), generation: parent.Generation()})
+				}
+				return nil
+			})
+		}
+		if err != nil {
+			// The walk is incomplete, so it's restarted on the next check
+			w.seen = nil
+			w.queue = nil
+			return false, err
+		}
+	}
+
+	return w.seen[ancestorID], nil
+}
+
+type generationQueueEntry struct {
+	id         plumbing.Hash
+	generation uint64
+}
+
+// generationQueue implements heap.Interface, with the commit with the greatest
+// generation number at the front of the queue.
+type generationQueue []generationQueueEntry
+
+func (q generationQueue) Len() int           { return len(q) }
+func (q generationQueue) Less(i, j int) bool { return q[i].generation > q[j].generation }
+func (q generationQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *generationQueue) Push(x any) {
+	*q = append(*q, x.(generationQueueEntry))
+}
+
+func (q *generationQueue) Pop() any {
+	old := *q
+	n := len(old)
+	entry := old[n-1]
+	*q = old[:n-1]
+	return entry
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReachabilityWalk(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	runGit := func(args ...string) plumbing.Hash {
+		t.Helper()
+
+		args = append([]string{"-C", tmpDir, "-c", "user.name=" + testName, "-c", "user.email=" + testEmail, "-c", "commit.gpgsign=false"}, args...)
+		output, err := exec.Command(binary, args...).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return plumbing.NewHash(strings.TrimSpace(string(output)))
+	}
+
+	commit := func(message string) plumbing.Hash {
+		t.Helper()
+
+		runGit("commit", "--quiet", "--allow-empty", "-m", message)
+		return runGit("rev-parse", "HEAD")
+	}
+
+	runGit("init", "--quiet", "-b", "main")
+	mainIDs := []plumbing.Hash{}
+	for _, message := range []string{"First commit", "Second commit", "Third commit"} {
+		mainIDs = append(mainIDs, commit(message))
+	}
+	runGit("checkout", "--quiet", "-b", "feature", mainIDs[0].String())
+	featureIDs := []plumbing.Hash{}
+	for _, message := range []string{"First feature commit", "Second feature commit"} {
+		featureIDs = append(featureIDs, commit(message))
+	}
+	runGit("checkout", "--quiet", "main")
+	runGit("merge", "--quiet", "--no-ff", "-m", "Merge feature", "feature")
+	mergeID := runGit("rev-parse", "HEAD")
+	unrelatedID := commit("Unrelated commit")
+	runGit("reset", "--quiet", "--hard", mergeID.String())
+
+	repo, err := OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The walk of the merge commit's history is resumed by each check
+	expected := map[plumbing.Hash]bool{
+		mainIDs[2]:    true,
+		featureIDs[1]: true,
+		unrelatedID:   false,
+		mainIDs[1]:    true,
+		featureIDs[0]: true,
+		mainIDs[0]:    true,
+		mergeID:       true,
+	}
+	for _, commitID := range []plumbing.Hash{mainIDs[2], featureIDs[1], unrelatedID, mainIDs[1], featureIDs[0], mainIDs[0], mergeID} {
+		reachable, err := isAncestor(repo, commitID, mergeID)
+		assert.Nil(t, err)
+		assert.Equal(t, expected[commitID], reachable, commitID.String())
+	}
+
+	walk, has := getCachedStorage(repo).reachability.get(mergeID)
+	assert.True(t, has)
+	assert.Equal(t, walk, getReachabilityWalk(repo, mergeID))
+
+	// The ancestors of a commit don't know the commit
+	for _, commitID := range append(mainIDs, featureIDs...) {
+		reachable, err := isAncestor(repo, mergeID, commitID)
+		assert.Nil(t, err)
+		assert.False(t, reachable)
+	}
+
+	_, err = isAncestor(repo, mainIDs[0], plumbing.ZeroHash)
+	assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import "fmt"
+
+// GetReference returns the ID of the object the reference points to. The
+// reference name must be fully qualified, such as `refs/heads/main`.
+func (r *Repository) GetReference(refName string) (Hash, error) {
+	refID, err := r.getBackend().getReference(refName)
+	if err != nil {
+		return ZeroHash, fmt.Errorf("unable to read reference '%s': %w", refName, err)
+	}
+
+	return refID, nil
+}
+
+// SetReference updates the reference to point to the object, creating the
+// reference if it doesn't exist.
+func (r *Repository) SetReference(refName string, objectID Hash) error {
+	if err := r.getBackend().setReference(refName, objectID); err != nil {
+		return fmt.Errorf("unable to set reference '%s' to '%s': %w", refName, objectID.String(), err)
+	}
+
+	return nil
+}
+
+// CheckAndSetReference updates the reference to point to the object if it
+// currently points to oldObjectID. If oldObjectID is the zero hash, the
+// reference must not exist. If the reference was changed, such as by another
+// process, ErrReferenceHasChanged is returned and the reference isn't updated.
+func (r *Repository) CheckAndSetReference(refName string, objectID, oldObjectID Hash) error {
+	if err := r.getBackend().checkAndSetReference(refName, objectID, oldObjectID); err != nil {
+		return fmt.Errorf("unable to set reference '%s' to '%s': %w", refName, objectID.String(), err)
+	}
+
+	return nil
+}
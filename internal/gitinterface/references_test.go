@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := CreateTestGitRepository(t, tmpDir)
+	defer repo.Close() //nolint:errcheck
+
+	refName := "refs/gittuf/test"
+
+	_, err := repo.GetReference(refName)
+	assert.NotNil(t, err)
+
+	firstBlobID, err := repo.WriteBlob([]byte("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.SetReference(refName, firstBlobID)
+	assert.Nil(t, err)
+
+	refID, err := repo.GetReference(refName)
+	assert.Nil(t, err)
+	assert.Equal(t, firstBlobID, refID)
+
+	secondBlobID, err := repo.WriteBlob([]byte("second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.SetReference(refName, secondBlobID)
+	assert.Nil(t, err)
+
+	refID, err = repo.GetReference(refName)
+	assert.Nil(t, err)
+	assert.Equal(t, secondBlobID, refID)
+}
+
+func TestRepositoryCheckAndSetReference(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := CreateTestGitRepository(t, tmpDir)
+	defer repo.Close() //nolint:errcheck
+
+	refName := "refs/gittuf/test"
+
+	firstBlobID, err := repo.WriteBlob([]byte("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondBlobID, err := repo.WriteBlob([]byte("second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("reference doesn't exist", func(t *testing.T) {
+		err := repo.CheckAndSetReference(refName, firstBlobID, secondBlobID)
+		assert.ErrorIs(t, err, ErrReferenceHasChanged)
+
+		err = repo.CheckAndSetReference(refName, firstBlobID, ZeroHash)
+		assert.Nil(t, err)
+
+		refID, err := repo.GetReference(refName)
+		assert.Nil(t, err)
+		assert.Equal(t, firstBlobID, refID)
+	})
+
+	t.Run("reference exists", func(t *testing.T) {
+		err := repo.CheckAndSetReference(refName, secondBlobID, ZeroHash)
+		assert.ErrorIs(t, err, ErrReferenceHasChanged)
+
+		err = repo.CheckAndSetReference(refName, secondBlobID, secondBlobID)
+		assert.ErrorIs(t, err, ErrReferenceHasChanged)
+
+		err = repo.CheckAndSetReference(refName, secondBlobID, firstBlobID)
+		assert.Nil(t, err)
+
+		refID, err := repo.GetReference(refName)
+		assert.Nil(t, err)
+		assert.Equal(t, secondBlobID, refID)
+	})
+}
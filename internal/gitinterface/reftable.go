@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage"
+)
+
+const (
+	refStorageOption   = "refstorage"
+	RefStorageFiles    = "files"
+	RefStorageReftable = "reftable"
+)
+
+// GetRefStorage returns the format the repository stores its references in,
+// which is recorded in extensions.refStorage for repositories that use the
+// reftable format, i.e., those created using `git init --ref-format=reftable`.
+func GetRefStorage(repo *git.Repository) (string, error) {
+	config, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+
+	refStorage := strings.ToLower(config.Raw.Section(extensionsSection).Option(refStorageOption))
+	if refStorage == "" {
+		return RefStorageFiles, nil
+	}
+
+	return refStorage, nil
+}
+
+// enableReftableReferences configures the repository to read and update
+// references using the Git binary if it stores them in the reftable format,
+// which go-git can't read. It must be called after enableCaching, as the
+// references are accessed via the cached storage.
+func enableReftableReferences(repo *git.Repository) error {
+	cache := getCachedStorage(repo)
+	if cache == nil {
+		return nil
+	}
+
+	refStorage, err := GetRefStorage(repo)
+	if err != nil {
+		return err
+	}
+	if refStorage != RefStorageReftable {
+		return nil
+	}
+
+	cache.refs = newGitReferenceStorage(cache.Filesystem().Root())
+	return nil
+}
+
+// gitReferenceStorage reads and updates the references of the repository at
+// gitDir using the Git binary, so it supports every ref storage format the
+// installed version of Git supports. Symbolic references are not dereferenced
+// by any of its methods, matching go-git's storage.
+//
+// gittuf initializes some references, such as the RSL, to the zero hash before
+// their first entry is committed. Git treats an update to the zero hash as a
+// deletion, so such references are removed and tracked in memory instead,
+// which means they appear to exist only to the instance that initialized them.
+type gitReferenceStorage struct {
+	repo *Repository
+
+	zeroRefs map[plumbing.ReferenceName]bool
+	mu       sync.Mutex
+}
+
+func newGitReferenceStorage(gitDir string) *gitReferenceStorage {
+	return &gitReferenceStorage{
+		repo:     &Repository{gitDirPath: gitDir},
+		zeroRefs: map[plumbing.ReferenceName]bool{},
+	}
+}
+
+// Reference returns the requested reference, or plumbing.ErrReferenceNotFound
+// if it doesn't exist.
+func (s *gitReferenceStorage) Reference(refName plumbing.ReferenceName) (*plumbing.Reference, error) {
+	target, err := s.repo.executeGitCommandString("symbolic-ref", "--quiet", "--no-recurse", refName.String())
+	if err == nil {
+		return plumbing.NewSymbolicReference(refName, plumbing.ReferenceName(target)), nil
+	}
+	if !isExitCode(err, 1) {
+		return nil, err
+	}
+
+	// The reference is either not symbolic or doesn't exist
+	objectID, err := s.repo.executeGitCommandString("rev-parse", "--verify", "--quiet", "--end-of-options", refName.String())
+	if err != nil {
+		if isExitCode(err, 1) {
+			if s.isZeroRef(refName) {
+				return plumbing.NewHashReference(refName, plumbing.ZeroHash), nil
+			}
+			return nil, plumbing.ErrReferenceNotFound
+		}
+		return nil, err
+	}
+
+	return plumbing.NewHashReference(refName, plumbing.NewHash(objectID)), nil
+}
+
+// SetReference creates or updates the reference.
+func (s *gitReferenceStorage) SetReference(ref *plumbing.Reference) error {
+	if ref.Type() == plumbing.SymbolicReference {
+		_, err := s.repo.executeGitCommandString("symbolic-ref", ref.Name().String(), ref.Target().String())
+		s.setZeroRef(ref.Name(), false)
+		return err
+	}
+
+	if ref.Hash().IsZero() {
+		if err := s.RemoveReference(ref.Name()); err != nil {
+			return err
+		}
+		s.setZeroRef(ref.Name(), true)
+		return nil
+	}
+
+	_, err := s.repo.executeGitCommandString("update-ref", "--no-deref", ref.Name().String(), ref.Hash().String())
+	s.setZeroRef(ref.Name(), false)
+	return err
+}
+
+// CheckAndSetReference updates the reference if the current value of old's
+// reference matches old, atomically for references that aren't symbolic. If
+// old is a hash reference with the zero hash, the reference must not exist. If
+// the current value doesn't match, storage.ErrReferenceHasChanged is returned.
+func (s *gitReferenceStorage) CheckAndSetReference(ref, old *plumbing.Reference) error {
+	if old == nil {
+		return s.SetReference(ref)
+	}
+
+	if ref.Type() == plumbing.SymbolicReference || ref.Hash().IsZero() || old.Type() == plumbing.SymbolicReference || old.Name() != ref.Name() {
+		// Git can only compare and swap the value of the reference being
+		// updated, so this is not atomic
+		current, err := s.Reference(old.Name())
+		switch {
+		case errors.Is(err, plumbing.ErrReferenceNotFound):
+			if old.Type() != plumbing.HashReference || !old.Hash().IsZero() {
+				return storage.ErrReferenceHasChanged
+			}
+		case err != nil:
+			return err
+		case current.Type() != old.Type() || current.Hash() != old.Hash() || current.Target() != old.Target():
+			return storage.ErrReferenceHasChanged
+		}
+		return s.SetReference(ref)
+	}
+
+	_, err := s.repo.executeGitCommandString("update-ref", "--no-deref", ref.Name().String(), ref.Hash().String(), old.Hash().String())
+	if err != nil {
+		for _, message := range []string{"but expected", "reference already exists", "unable to resolve reference"} {
+			if strings.Contains(err.Error(), message) {
+				return fmt.Errorf("%w: %w", storage.ErrReferenceHasChanged, err)
+			}
+		}
+		return err
+	}
+
+	s.setZeroRef(ref.Name(), false)
+	return nil
+}
+
+// RemoveReference removes the reference. Removing a reference that doesn't
+// exist is not an error.
+func (s *gitReferenceStorage) RemoveReference(refName plumbing.ReferenceName) error {
+	_, err := s.repo.executeGitCommandString("update-ref", "--no-deref", "-d", refName.String())
+	s.setZeroRef(refName, false)
+	return err
+}
+
+// IterReferences returns an iterator over HEAD and the references under refs/.
+func (s *gitReferenceStorage) IterReferences() (storer.ReferenceIter, error) {
+	refs := []*plumbing.Reference{}
+
+	head, err := s.Reference(plumbing.HEAD)
+	switch {
+	case err == nil:
+		refs = append(refs, head)
+	case !errors.Is(err, plumbing.ErrReferenceNotFound):
+		return nil, err
+	}
+
+	output, err := s.repo.executeGitCommandString("for-each-ref", "--format=%(refname) %(objectname) %(symref)")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		// The symbolic ref's target is empty for other refs, and the trailing
+		// space is trimmed from the last line
+		fields := strings.Split(strings.TrimSpace(line), " ")
+		refName := plumbing.ReferenceName(fields[0])
+		switch len(fields) {
+		case 2:
+			refs = append(refs, plumbing.NewHashReference(refName, plumbing.NewHash(fields[1])))
+		case 3:
+			refs = append(refs, plumbing.NewSymbolicReference(refName, plumbing.ReferenceName(fields[2])))
+		default:
+			return nil, fmt.Errorf("unexpected output '%s' when listing references", line)
+		}
+	}
+
+	return storer.NewReferenceSliceIter(refs), nil
+}
+
+// CountLooseRefs returns 0, as references are not stored as loose files.
+func (s *gitReferenceStorage) CountLooseRefs() (int, error) {
+	return 0, nil
+}
+
+// PackRefs is a no-op, as Git compacts the references itself.
+func (s *gitReferenceStorage) PackRefs() error {
+	return nil
+}
+
+func (s *gitReferenceStorage) isZeroRef(refName plumbing.ReferenceName) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.zeroRefs[refName]
+}
+
+func (s *gitReferenceStorage) setZeroRef(refName plumbing.ReferenceName, isZero bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if isZero {
+		s.zeroRefs[refName] = true
+	} else {
+		delete(s.zeroRefs, refName)
+	}
+}
+
+// isExitCode returns true if the error is from a Git command that exited with
+// the specified code.
+func isExitCode(err error, code int) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == code
+}
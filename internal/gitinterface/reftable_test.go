@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferenceStorage(t *testing.T) {
+	for _, refStorage := range []string{RefStorageFiles, RefStorageReftable} {
+		t.Run(refStorage, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			args := []string{"init", "--quiet", "-b", "main"}
+			if refStorage == RefStorageReftable {
+				args = append(args, "--ref-format="+RefStorageReftable)
+			}
+			args = append(args, tmpDir)
+			if output, err := exec.Command(binary, args...).CombinedOutput(); err != nil {
+				if refStorage == RefStorageReftable {
+					t.Skipf("installed version of Git doesn't support reftable: %s", string(output))
+				}
+				t.Fatal(err)
+			}
+
+			repo, err := OpenRepository(tmpDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actualRefStorage, err := GetRefStorage(repo)
+			assert.Nil(t, err)
+			assert.Equal(t, refStorage, actualRefStorage)
+
+			testReferenceOperations(t, repo, tmpDir)
+		})
+	}
+
+	t.Run("files using git binary", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		if err := exec.Command(binary, "init", "--quiet", "-b", "main", tmpDir).Run(); err != nil {
+			t.Fatal(err)
+		}
+
+		repo, err := OpenRepository(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		getCachedStorage(repo).refs = newGitReferenceStorage(filepath.Join(tmpDir, ".git"))
+
+		testReferenceOperations(t, repo, tmpDir)
+	})
+}
+
+func testReferenceOperations(t *testing.T, repo *git.Repository, repoDir string) {
+	t.Helper()
+
+	refName := plumbing.ReferenceName("refs/gittuf/reference-storage-test")
+
+	gitRevParse := func(refName plumbing.ReferenceName) string {
+		t.Helper()
+
+		output, err := exec.Command(binary, "-C", repoDir, "rev-parse", "--verify", "--quiet", refName.String()).Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	treeID, err := WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// References are initialized to the zero hash before their first commit,
+	// like the RSL
+	_, err = repo.Reference(refName, true)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+	err = repo.Storer.SetReference(plumbing.NewHashReference(refName, plumbing.ZeroHash))
+	assert.Nil(t, err)
+
+	zeroRef, err := repo.Reference(refName, true)
+	assert.Nil(t, err)
+	assert.Equal(t, plumbing.ZeroHash, zeroRef.Hash())
+
+	firstCommitID, err := ApplyCommit(repo, CreateCommitObject(testGitConfig, treeID, []plumbing.Hash{zeroRef.Hash()}, "First commit", testClock), zeroRef)
+	assert.Nil(t, err)
+	assert.Equal(t, firstCommitID.String(), gitRevParse(refName))
+
+	firstRef, err := repo.Reference(refName, true)
+	assert.Nil(t, err)
+	assert.Equal(t, firstCommitID, firstRef.Hash())
+
+	secondCommitID, err := ApplyCommit(repo, CreateCommitObject(testGitConfig, treeID, []plumbing.Hash{firstCommitID}, "Second commit", testClock), firstRef)
+	assert.Nil(t, err)
+	assert.Equal(t, secondCommitID.String(), gitRevParse(refName))
+
+	// The reference was updated since firstRef was read
+	_, err = ApplyCommit(repo, CreateCommitObject(testGitConfig, treeID, []plumbing.Hash{firstCommitID}, "Concurrent commit", testClock), firstRef)
+	assert.ErrorIs(t, err, storage.ErrReferenceHasChanged)
+	assert.Equal(t, secondCommitID.String(), gitRevParse(refName))
+
+	// The reference exists, so it can't be created
+	err = repo.Storer.CheckAndSetReference(plumbing.NewHashReference(refName, firstCommitID), plumbing.NewHashReference(refName, plumbing.ZeroHash))
+	assert.ErrorIs(t, err, storage.ErrReferenceHasChanged)
+
+	err = repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName("refs/heads/main"), firstCommitID))
+	assert.Nil(t, err)
+
+	head, err := repo.Head()
+	assert.Nil(t, err)
+	assert.Equal(t, firstCommitID, head.Hash())
+
+	refs := map[plumbing.ReferenceName]plumbing.Hash{}
+	iter, err := repo.References()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference {
+			refs[ref.Name()] = ref.Hash()
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[plumbing.ReferenceName]plumbing.Hash{
+		refName: secondCommitID,
+		plumbing.ReferenceName("refs/heads/main"): firstCommitID,
+	}, refs)
+
+	err = repo.Storer.RemoveReference(refName)
+	assert.Nil(t, err)
+	assert.Equal(t, "", gitRevParse(refName))
+
+	_, err = repo.Reference(refName, true)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+}
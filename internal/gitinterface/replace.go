@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// ReplaceRefPrefix is the default prefix of the refs Git uses to replace
+	// objects, of the form `refs/replace/<object-id>`. These refs are created
+	// using `git replace`.
+	ReplaceRefPrefix = "refs/replace/"
+
+	replaceRefBaseKey = "GIT_REPLACE_REF_BASE"
+	graftFileKey      = "GIT_GRAFT_FILE"
+	graftsFile        = "info/grafts"
+)
+
+// GetReplacedObjects returns the IDs of the objects that Git replaces with
+// other objects when reading the repository. Objects are replaced using
+// replace refs, and the parents of commits are replaced using the deprecated
+// grafts file. gittuf always reads the original objects, but other Git
+// commands such as `git log` show the replacements, so the history a user sees
+// may not be the history gittuf verified.
+func GetReplacedObjects(repo *git.Repository) ([]plumbing.Hash, error) {
+	replacedObjects := []plumbing.Hash{}
+
+	replaceRefBase := ReplaceRefPrefix
+	if base := os.Getenv(replaceRefBaseKey); base != "" {
+		replaceRefBase = strings.TrimSuffix(base, "/") + "/"
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		objectID, isReplaceRef := strings.CutPrefix(ref.Name().String(), replaceRefBase)
+		if isReplaceRef && plumbing.IsHash(objectID) {
+			replacedObjects = append(replacedObjects, plumbing.NewHash(objectID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	graftedCommits, err := getGraftedCommits(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(replacedObjects, graftedCommits...), nil
+}
+
+// getGraftedCommits returns the IDs of the commits listed in the repository's
+// grafts file. Each line of the file contains a commit's ID followed by the
+// IDs of its replacement parents.
+func getGraftedCommits(repo *git.Repository) ([]plumbing.Hash, error) {
+	graftsPath := os.Getenv(graftFileKey)
+	if graftsPath == "" {
+		commonDir, err := GetGitCommonDirFor(repo)
+		if err != nil {
+			if errors.Is(err, ErrRepositoryNotOnDisk) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		graftsPath = filepath.Join(commonDir, graftsFile)
+	}
+
+	file, err := os.Open(graftsPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	graftedCommits := []plumbing.Hash{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || !plumbing.IsHash(fields[0]) {
+			continue
+		}
+		graftedCommits = append(graftedCommits, plumbing.NewHash(fields[0]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return graftedCommits, nil
+}
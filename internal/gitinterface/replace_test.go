@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReplacedObjects(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	runGit := func(args ...string) string {
+		t.Helper()
+
+		args = append([]string{"-C", tmpDir, "-c", "user.name=" + testName, "-c", "user.email=" + testEmail, "-c", "commit.gpgsign=false"}, args...)
+		output, err := exec.Command(binary, args...).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	runGit("init", "--quiet", "-b", "main")
+	commitIDs := []string{}
+	for _, message := range []string{"First commit", "Second commit", "Third commit"} {
+		runGit("commit", "--quiet", "--allow-empty", "-m", message)
+		commitIDs = append(commitIDs, runGit("rev-parse", "HEAD"))
+	}
+
+	repo, err := OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replacedObjects, err := GetReplacedObjects(repo)
+	assert.Nil(t, err)
+	assert.Empty(t, replacedObjects)
+
+	t.Run("replace refs", func(t *testing.T) {
+		// Make the third commit appear to have the first commit as its parent
+		replacementID := runGit("commit-tree", "-p", commitIDs[0], "-m", "Replacement commit", "4b825dc642cb6eb9a060e54bf8d69288fbee4904")
+		runGit("replace", commitIDs[2], replacementID)
+
+		replacedObjects, err := GetReplacedObjects(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, []plumbing.Hash{plumbing.NewHash(commitIDs[2])}, replacedObjects)
+
+		// Git commands run by gittuf read the original objects
+		gitRepo := &Repository{gitDirPath: filepath.Join(tmpDir, ".git")}
+		defer gitRepo.Close() //nolint:errcheck
+
+		commitID, err := NewHash(commitIDs[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, contents, err := gitRepo.getBackend().readObject(commitID)
+		assert.Nil(t, err)
+		assert.Contains(t, string(contents), "Third commit")
+
+		runGit("replace", "-d", commitIDs[2])
+	})
+
+	t.Run("grafts", func(t *testing.T) {
+		graftsPath := filepath.Join(tmpDir, ".git", "info", "grafts")
+		if err := os.MkdirAll(filepath.Dir(graftsPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(graftsPath, []byte("# Hide the second commit\n"+commitIDs[2]+" "+commitIDs[0]+"\n"), 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+
+		replacedObjects, err := GetReplacedObjects(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, []plumbing.Hash{plumbing.NewHash(commitIDs[2])}, replacedObjects)
+	})
+}
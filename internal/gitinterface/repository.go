@@ -4,13 +4,18 @@ package gitinterface
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/gittuf/gittuf/internal/logging"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/filesystem"
 	"github.com/jonboulle/clockwork"
 )
 
@@ -18,19 +23,32 @@ const (
 	binary           = "git"
 	committerTimeKey = "GIT_COMMITTER_DATE"
 	authorTimeKey    = "GIT_AUTHOR_DATE"
+	commonDirFile    = "commondir"
+
+	noReplaceObjectsKey = "GIT_NO_REPLACE_OBJECTS"
 )
 
+var ErrRepositoryNotOnDisk = errors.New("repository isn't stored on disk")
+
+// logger logs the messages of the gitinterface subsystem.
+var logger = logging.For("gitinterface")
+
 // Repository is a lightweight wrapper around a Git repository. It stores the
-// location of the repository's GIT_DIR.
+// location of the repository's GIT_DIR. Objects, references, and config are
+// read and written using the backend selected at build time, see backend for
+// more details.
 type Repository struct {
 	gitDirPath string
 	clock      clockwork.Clock
+
+	backendOnce sync.Once
+	backend     backend
 }
 
 // GetGoGitRepository returns the go-git representation of a repository. We use
 // this in certain signing and verifying workflows.
 func (r *Repository) GetGoGitRepository() (*git.Repository, error) {
-	return git.PlainOpenWithOptions(r.gitDirPath, &git.PlainOpenOptions{DetectDotGit: true})
+	return OpenRepository(r.gitDirPath)
 }
 
 // GetGitDir returns the GIT_DIR path for the repository.
@@ -38,12 +56,128 @@ func (r *Repository) GetGitDir() string {
 	return r.gitDirPath
 }
 
+// GetGitCommonDir returns the path of the GIT_DIR shared by all of the
+// repository's worktrees. See GetGitCommonDirFor for more details.
+func (r *Repository) GetGitCommonDir() string {
+	return gitCommonDir(r.gitDirPath)
+}
+
+// OpenRepository opens the repository at the specified path, which may be a
+// bare repository, any directory in a repository's working tree, or a linked
+// worktree created using `git worktree add`. Like Git, refs other than HEAD,
+// objects, and config are read from the main repository's GIT_DIR when the
+// path is a linked worktree.
+//
+// Parsed commits, parsed trees, and references read from the repository are
+// cached for the lifetime of the returned repository. If the repository is a
+// partial clone, objects missing locally are fetched from the promisor remote
+// when they're read. If the repository stores its references in the reftable
+// format, which go-git doesn't support, they're read and updated using the Git
+// binary.
+func OpenRepository(path string) (*git.Repository, error) {
+	repo, err := openRepository(path, &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+	if errors.Is(err, git.ErrRepositoryNotExists) && isGitDir(path) {
+		// go-git only detects the .git directory of repositories with a
+		// working tree, searching parent directories otherwise, so bare
+		// repositories are opened directly
+		return openRepository(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	}
+
+	return repo, err
+}
+
+// isGitDir returns true if the path looks like a GIT_DIR, such as that of a
+// bare repository, i.e., it has a HEAD file and an objects directory.
+func isGitDir(path string) bool {
+	if _, err := os.Stat(filepath.Join(path, "HEAD")); err != nil {
+		return false
+	}
+
+	info, err := os.Stat(filepath.Join(path, "objects"))
+	return err == nil && info.IsDir()
+}
+
+// openRepository opens the repository at the specified path using the options,
+// enabling caching, reftable support, and promisor fetching as described in
+// OpenRepository.
+func openRepository(path string, options *git.PlainOpenOptions) (*git.Repository, error) {
+	repo, err := git.PlainOpenWithOptions(path, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckObjectFormat(repo); err != nil {
+		return nil, err
+	}
+
+	enableLargeObjectStreaming(repo)
+	enableCaching(repo)
+
+	if err := enableReftableReferences(repo); err != nil {
+		return nil, err
+	}
+
+	if err := enablePromisorFetching(repo); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// IsBare returns true if the repository doesn't have a working tree, such as
+// repositories created using `git init --bare` or `git clone --mirror`, which
+// are typically used on servers and for mirrors.
+func IsBare(repo *git.Repository) bool {
+	_, err := repo.Worktree()
+	return errors.Is(err, git.ErrIsBareRepository)
+}
+
+// GetGitCommonDirFor returns the path of the GIT_DIR shared by all of the
+// repository's worktrees, which stores the objects, refs, config, hooks, and
+// other state that is not specific to a worktree. For a linked worktree, this
+// is the main repository's GIT_DIR, and for all other repositories, it is the
+// repository's GIT_DIR.
+func GetGitCommonDirFor(repo *git.Repository) (string, error) {
+	var storage *filesystem.Storage
+	switch storer := repo.Storer.(type) {
+	case *filesystem.Storage:
+		storage = storer
+	case *cachedStorage:
+		storage = storer.Storage
+	case *promisorStorage:
+		storage = storer.Storage
+	default:
+		return "", ErrRepositoryNotOnDisk
+	}
+
+	return gitCommonDir(storage.Filesystem().Root()), nil
+}
+
+// gitCommonDir returns the common GIT_DIR recorded for a linked worktree's
+// GIT_DIR, or the GIT_DIR itself if it isn't a linked worktree.
+func gitCommonDir(gitDir string) string {
+	contents, err := os.ReadFile(filepath.Join(gitDir, commonDirFile))
+	if err != nil {
+		return gitDir
+	}
+
+	commonDir := strings.TrimSpace(string(contents))
+	if commonDir == "" {
+		return gitDir
+	}
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+
+	return filepath.Clean(commonDir)
+}
+
 // LoadRepository returns a Repository instance using the current working
-// directory. It also inspects the PATH to ensure Git is installed.
+// directory. Unless gittuf is built with libgit2, it also inspects the PATH to
+// ensure Git is installed.
 func LoadRepository() (*Repository, error) {
-	_, err := exec.LookPath(binary)
-	if err != nil {
-		return nil, fmt.Errorf("unable to find Git binary, is Git installed?")
+	if err := ensureBackendAvailable(); err != nil {
+		return nil, err
 	}
 
 	repo := &Repository{clock: clockwork.NewRealClock()}
@@ -53,7 +187,7 @@ func LoadRepository() (*Repository, error) {
 		return repo, nil
 	}
 
-	gitDirPath, err := repo.executeGitCommandDirectString("rev-parse", "--git-dir")
+	gitDirPath, err := discoverGitDir()
 	if err != nil {
 		return nil, fmt.Errorf("unable to identify GIT_DIR: %w", err)
 	}
@@ -100,11 +234,20 @@ func (r *Repository) executeGitCommandDirectString(args ...string) (string, erro
 	return strings.TrimSpace(string(stdOutContents)), nil
 }
 
+// newGitCommand returns a command that invokes the Git binary with the
+// specified arguments. Replace refs are disabled so that Git reads the same
+// objects as gittuf, see GetReplacedObjects.
+func newGitCommand(args ...string) *exec.Cmd {
+	cmd := exec.Command(binary, args...)
+	cmd.Env = append(os.Environ(), noReplaceObjectsKey+"=1")
+	return cmd
+}
+
 // executeGitCommandDirect is a helper to execute the specified command in the
 // repository. It executes in the current directory without specifying the
 // GIT_DIR explicitly.
 func (r *Repository) executeGitCommandDirect(args ...string) (io.Reader, io.Reader, error) {
-	cmd := exec.Command(binary, args...)
+	cmd := newGitCommand(args...)
 
 	var (
 		stdOut bytes.Buffer
@@ -144,7 +287,7 @@ func (r *Repository) executeGitCommandWithStdInString(stdIn *bytes.Buffer, args
 // in the repository with `stdIn` passed into the process stdin. It executes in
 // the current directory without specifying the GIT_DIR explicitly.
 func (r *Repository) executeGitCommandDirectWithStdIn(stdIn *bytes.Buffer, args ...string) (io.Reader, io.Reader, error) {
-	cmd := exec.Command(binary, args...)
+	cmd := newGitCommand(args...)
 
 	var (
 		stdOut bytes.Buffer
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenRepositoryBare(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := git.PlainInit(tmpDir, true); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := OpenRepository(tmpDir)
+	assert.Nil(t, err)
+	assert.True(t, IsBare(repo))
+
+	_, err = OpenRepository(t.TempDir())
+	assert.ErrorIs(t, err, git.ErrRepositoryNotExists)
+}
+
+func TestOpenRepositoryLinkedWorktree(t *testing.T) {
+	mainDir := t.TempDir()
+	worktreeDir := t.TempDir()
+
+	mainRepo, err := git.PlainInit(mainDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Recreate the layout of a worktree created using `git worktree add`
+	worktreeGitDir := filepath.Join(mainDir, ".git", "worktrees", "feature")
+	if err := os.MkdirAll(worktreeGitDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(worktreeGitDir, commonDirFile): "../..\n",
+		filepath.Join(worktreeGitDir, "HEAD"):        "ref: refs/heads/feature\n",
+		filepath.Join(worktreeGitDir, "gitdir"):      filepath.Join(worktreeDir, ".git") + "\n",
+		filepath.Join(worktreeDir, ".git"):           "gitdir: " + worktreeGitDir + "\n",
+	}
+	for path, contents := range files {
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+	}
+
+	repo, err := OpenRepository(worktreeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commonDir, err := GetGitCommonDirFor(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(mainDir, ".git"), commonDir)
+
+	mainCommonDir, err := GetGitCommonDirFor(mainRepo)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(mainDir, ".git"), mainCommonDir)
+
+	wrapper := &Repository{gitDirPath: worktreeGitDir}
+	assert.Equal(t, filepath.Join(mainDir, ".git"), wrapper.GetGitCommonDir())
+
+	// Objects and refs written in the worktree are visible in the main
+	// repository
+	blobID, err := WriteBlob(repo, []byte("gittuf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	refName := plumbing.ReferenceName("refs/gittuf/test")
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(refName, blobID)); err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := mainRepo.Reference(refName, true)
+	assert.Nil(t, err)
+	assert.Equal(t, blobID, ref.Hash())
+
+	contents, err := ReadBlob(mainRepo, blobID)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("gittuf"), contents)
+
+	// HEAD is specific to the worktree
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	assert.Nil(t, err)
+	assert.Equal(t, plumbing.ReferenceName("refs/heads/feature"), head.Target())
+
+	memoryRepo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = GetGitCommonDirFor(memoryRepo)
+	assert.ErrorIs(t, err, ErrRepositoryNotOnDisk)
+}
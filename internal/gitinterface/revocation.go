@@ -0,0 +1,415 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	ErrKeyRevoked                   = errors.New("signing key has been revoked")
+	ErrInvalidRevocationList        = errors.New("invalid SSH revocation list")
+	ErrInvalidRevocationCertificate = errors.New("invalid GPG revocation certificate")
+)
+
+// sshKRLMagic is the header of OpenSSH's binary key revocation lists, which
+// aren't supported.
+const sshKRLMagic = "SSHKRL\n\x00"
+
+type (
+	revocationsContextKey  struct{}
+	signingTimesContextKey struct{}
+)
+
+// SigningTimeFunc returns the trusted time at which the signature was issued,
+// such as the time of a verified timestamp of the signature. It returns the
+// zero time if no trusted time is known for the signature.
+type SigningTimeFunc func(ctx context.Context, signature []byte) (time.Time, error)
+
+// Revocations records the keys that have been revoked by their owners, which
+// are distrusted when verifying signatures even if the policy still lists
+// them. SSH keys are revoked using a revocation list, like the one Git uses
+// for the gpg.ssh.revocationFile option. GPG keys are revoked using
+// revocation certificates and, optionally, the revocation status of the keys
+// in the local GnuPG keyring.
+type Revocations struct {
+	sshKeys        [][]byte
+	gpgRevocations []*packet.Signature
+
+	// gpgProgram is the GnuPG program whose keyring is consulted for the
+	// revocation status of GPG keys. The keyring isn't consulted if it's
+	// empty.
+	gpgProgram     string
+	gpgKeyringMu   sync.Mutex
+	gpgKeyringKeys map[string]*openpgp.Entity
+}
+
+// LoadRevocationsFromConfig loads the revocation list configured in Git's
+// gpg.ssh.revocationFile option, if set, and consults the keyring of the
+// GnuPG program configured in Git's gpg.program option for the revocation
+// status of GPG keys.
+func LoadRevocationsFromConfig() (*Revocations, error) {
+	revocations := &Revocations{}
+
+	path, err := GetConfigValue("gpg.ssh.revocationfile")
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		path, err = ExpandHomeDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := revocations.LoadSSHRevocationList(path); err != nil {
+			return nil, err
+		}
+	}
+
+	program, err := GetConfigValue("gpg.program")
+	if err != nil {
+		return nil, err
+	}
+	if program == "" {
+		program = DefaultSigningProgramGPG
+	}
+	program, err = normalizeSigningProgram(program)
+	if err != nil {
+		return nil, err
+	}
+	revocations.ConsultGPGKeyring(program)
+
+	return revocations, nil
+}
+
+// LoadSSHRevocationList loads the SSH revocation list at the specified path.
+func (r *Revocations) LoadSSHRevocationList(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close() //nolint:errcheck
+
+	if err := r.ParseSSHRevocationList(file); err != nil {
+		return fmt.Errorf("%w '%s'", err, path)
+	}
+
+	return nil
+}
+
+// ParseSSHRevocationList parses a list of revoked SSH public keys, one per
+// line in the authorized keys format. Empty lines and lines starting with "#"
+// are ignored. OpenSSH's binary key revocation lists are not supported.
+func (r *Revocations) ParseSSHRevocationList(reader io.Reader) error {
+	bufferedReader := bufio.NewReader(reader)
+	if header, err := bufferedReader.Peek(len(sshKRLMagic)); err == nil && string(header) == sshKRLMagic {
+		return fmt.Errorf("%w: binary key revocation lists are not supported, list the revoked public keys instead", ErrInvalidRevocationList)
+	}
+
+	sshKeys := [][]byte{}
+	scanner := bufio.NewScanner(bufferedReader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %w", ErrInvalidRevocationList, lineNumber, err)
+		}
+		sshKeys = append(sshKeys, publicKey.Marshal())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	r.sshKeys = append(r.sshKeys, sshKeys...)
+	return nil
+}
+
+// LoadGPGRevocationCertificate loads the GPG revocation certificate at the
+// specified path.
+func (r *Revocations) LoadGPGRevocationCertificate(path string) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := r.ParseGPGRevocationCertificate(contents); err != nil {
+		return fmt.Errorf("%w '%s'", err, path)
+	}
+
+	return nil
+}
+
+// ParseGPGRevocationCertificate parses an armored GPG revocation certificate,
+// such as one created using `gpg --gen-revoke`. The certificates GnuPG stores
+// in its openpgp-revocs.d directory are also accepted, although their armor
+// header is escaped to prevent them from being imported accidentally.
+// Certificates are only trusted for the keys whose revocation they're signed
+// by, which is checked when verifying signatures.
+func (r *Revocations) ParseGPGRevocationCertificate(contents []byte) error {
+	contents = bytes.Replace(contents, []byte(":-----BEGIN PGP"), []byte("-----BEGIN PGP"), 1)
+
+	block, err := armor.Decode(bytes.NewReader(contents))
+	if err != nil {
+		return errors.Join(ErrInvalidRevocationCertificate, err)
+	}
+	if block.Type != openpgp.PublicKeyType && block.Type != openpgp.SignatureType {
+		return fmt.Errorf("%w: unexpected armor type '%s'", ErrInvalidRevocationCertificate, block.Type)
+	}
+
+	revocations := []*packet.Signature{}
+	packets := packet.NewReader(block.Body)
+	for {
+		p, err := packets.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return errors.Join(ErrInvalidRevocationCertificate, err)
+		}
+
+		if signature, isSignature := p.(*packet.Signature); isSignature && signature.SigType == packet.SigTypeKeyRevocation {
+			revocations = append(revocations, signature)
+		}
+	}
+	if len(revocations) == 0 {
+		return fmt.Errorf("%w: no key revocation signature found", ErrInvalidRevocationCertificate)
+	}
+
+	r.gpgRevocations = append(r.gpgRevocations, revocations...)
+	return nil
+}
+
+// ConsultGPGKeyring configures the revocations to also consult the keyring of
+// the GnuPG program for the revocation status of GPG keys, so that keys whose
+// revocation certificates have been imported are distrusted.
+func (r *Revocations) ConsultGPGKeyring(program string) {
+	r.gpgProgram = program
+}
+
+// ContextWithRevocations returns a copy of the context that carries the
+// specified revocations. When verifying signatures, the signing key must not
+// have been revoked.
+func ContextWithRevocations(ctx context.Context, revocations *Revocations) context.Context {
+	return context.WithValue(ctx, revocationsContextKey{}, revocations)
+}
+
+// RevocationsFromContext returns the revocations carried by the context, if
+// any.
+func RevocationsFromContext(ctx context.Context) *Revocations {
+	revocations, ok := ctx.Value(revocationsContextKey{}).(*Revocations)
+	if !ok {
+		return nil
+	}
+
+	return revocations
+}
+
+// ContextWithSigningTimes returns a copy of the context that carries the
+// function used to determine when signatures were issued. The dates of
+// commits and tags are set by their authors, so revocations of GPG keys that
+// were superseded or retired only spare signatures whose trusted signing time
+// is before the revocation. Similarly, S/MIME certificates are checked at the
+// trusted signing time, and at the current time if there's none.
+func ContextWithSigningTimes(ctx context.Context, signingTime SigningTimeFunc) context.Context {
+	return context.WithValue(ctx, signingTimesContextKey{}, signingTime)
+}
+
+// checkRevocation checks that the key that issued the verified signature
+// hasn't been revoked, if the context carries revocations. The signature is
+// checked at its trusted signing time, if the context carries a function to
+// determine it.
+func checkRevocation(ctx context.Context, key *tuf.Key, signature []byte) error {
+	revocations := RevocationsFromContext(ctx)
+	if revocations == nil {
+		return nil
+	}
+
+	at, err := trustedSigningTime(ctx, signature)
+	if err != nil {
+		return err
+	}
+
+	return revocations.Check(key, at)
+}
+
+// trustedSigningTime returns the trusted time at which the signature was
+// issued using the function carried by the context, or the zero time if the
+// context doesn't carry one.
+func trustedSigningTime(ctx context.Context, signature []byte) (time.Time, error) {
+	signingTime, ok := ctx.Value(signingTimesContextKey{}).(SigningTimeFunc)
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	return signingTime(ctx, signature)
+}
+
+// Check returns ErrKeyRevoked if the key has been revoked for signatures
+// issued at the specified time. SSH revocation lists revoke keys for all
+// signatures. GPG keys that were compromised, or were revoked without a
+// reason, are also revoked for all signatures, while keys that were
+// superseded or retired are only revoked for signatures issued after they
+// were revoked. If the time is zero, as no trusted time is known for the
+// signature, every revocation applies.
+func (r *Revocations) Check(key *tuf.Key, at time.Time) error {
+	switch key.KeyType {
+	case signerverifier.GPGKeyType:
+		keyRing, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.KeyVal.Public))
+		if err != nil {
+			return err
+		}
+
+		for _, entity := range keyRing {
+			revocations, err := r.gpgRevocationsFor(entity.PrimaryKey)
+			if err != nil {
+				return err
+			}
+
+			for _, revocation := range revocations {
+				if gpgRevocationApplies(revocation, at) {
+					return fmt.Errorf("%w: GPG key '%s' was revoked at %s", ErrKeyRevoked, entity.PrimaryKey.KeyIdString(), revocation.CreationTime.UTC().Format(time.RFC3339))
+				}
+			}
+		}
+
+	case signerverifier.RSAKeyType, signerverifier.ECDSAKeyType, signerverifier.ED25519KeyType, signerverifier.SSHSecurityKeyType:
+		if len(r.sshKeys) == 0 {
+			return nil
+		}
+
+		publicKey, err := sshPublicKeyFromTUFKey(key)
+		if err != nil {
+			return err
+		}
+
+		marshaled := publicKey.Marshal()
+		for _, revoked := range r.sshKeys {
+			if bytes.Equal(revoked, marshaled) {
+				return fmt.Errorf("%w: SSH key '%s' is in the revocation list", ErrKeyRevoked, ssh.FingerprintSHA256(publicKey))
+			}
+		}
+	}
+
+	return nil
+}
+
+// gpgRevocationsFor returns the revocation signatures for the primary key
+// found in the revocation certificates and the GnuPG keyring. Certificates
+// that aren't signed by the key are ignored.
+func (r *Revocations) gpgRevocationsFor(primaryKey *packet.PublicKey) ([]*packet.Signature, error) {
+	revocations := []*packet.Signature{}
+	for _, revocation := range r.gpgRevocations {
+		if !issuedBy(revocation, primaryKey) {
+			continue
+		}
+		if err := primaryKey.VerifyRevocationSignature(revocation); err != nil {
+			continue
+		}
+		revocations = append(revocations, revocation)
+	}
+
+	entity, err := r.gpgKeyringEntity(primaryKey)
+	if err != nil {
+		return nil, err
+	}
+	if entity != nil {
+		// The revocations of keys in the keyring are verified when the
+		// key is parsed
+		revocations = append(revocations, entity.Revocations...)
+	}
+
+	return revocations, nil
+}
+
+// gpgKeyringEntity returns the key in the GnuPG keyring with the same
+// fingerprint as the primary key, if any. Keys are exported from the keyring
+// once.
+func (r *Revocations) gpgKeyringEntity(primaryKey *packet.PublicKey) (*openpgp.Entity, error) {
+	if r.gpgProgram == "" {
+		return nil, nil
+	}
+
+	fingerprint := strings.ToUpper(hex.EncodeToString(primaryKey.Fingerprint))
+
+	r.gpgKeyringMu.Lock()
+	defer r.gpgKeyringMu.Unlock()
+
+	if r.gpgKeyringKeys == nil {
+		r.gpgKeyringKeys = map[string]*openpgp.Entity{}
+	}
+	if entity, has := r.gpgKeyringKeys[fingerprint]; has {
+		return entity, nil
+	}
+
+	output, err := exec.Command(r.gpgProgram, "--batch", "--export", fingerprint).Output() //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("unable to export GPG key '%s' from keyring: %w", fingerprint, err)
+	}
+
+	var entity *openpgp.Entity
+	if len(output) != 0 {
+		keyRing, err := openpgp.ReadKeyRing(bytes.NewReader(output))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse GPG key '%s' exported from keyring: %w", fingerprint, err)
+		}
+
+		for _, candidate := range keyRing {
+			if bytes.Equal(candidate.PrimaryKey.Fingerprint, primaryKey.Fingerprint) {
+				entity = candidate
+				break
+			}
+		}
+	}
+
+	r.gpgKeyringKeys[fingerprint] = entity
+	return entity, nil
+}
+
+// issuedBy indicates if the signature identifies the key as its issuer.
+func issuedBy(signature *packet.Signature, key *packet.PublicKey) bool {
+	if len(signature.IssuerFingerprint) != 0 {
+		return bytes.Equal(signature.IssuerFingerprint, key.Fingerprint)
+	}
+
+	return signature.IssuerKeyId != nil && *signature.IssuerKeyId == key.KeyId
+}
+
+// gpgRevocationApplies indicates if the revocation applies to signatures
+// issued at the specified time, which is zero if it isn't known.
+func gpgRevocationApplies(revocation *packet.Signature, at time.Time) bool {
+	if revocation.RevocationReason == nil || at.IsZero() {
+		return true
+	}
+
+	switch *revocation.RevocationReason {
+	case packet.NoReason, packet.KeyCompromised:
+		return true
+	default:
+		return !at.Before(revocation.CreationTime)
+	}
+}
@@ -0,0 +1,314 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSSHRevocationList(t *testing.T) {
+	t.Run("valid list", func(t *testing.T) {
+		contents := fmt.Sprintf("# revoked keys\n\n%s\n", strings.TrimSpace(string(artifacts.SSHRSAPublicSSH)))
+
+		revocations := &Revocations{}
+		err := revocations.ParseSSHRevocationList(strings.NewReader(contents))
+		assert.Nil(t, err)
+		assert.Len(t, revocations.sshKeys, 1)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		revocations := &Revocations{}
+		err := revocations.ParseSSHRevocationList(strings.NewReader("ssh-rsa invalid"))
+		assert.ErrorIs(t, err, ErrInvalidRevocationList)
+	})
+
+	t.Run("binary KRL", func(t *testing.T) {
+		revocations := &Revocations{}
+		err := revocations.ParseSSHRevocationList(strings.NewReader(sshKRLMagic + "contents"))
+		assert.ErrorIs(t, err, ErrInvalidRevocationList)
+	})
+}
+
+func TestRevocationsCheck(t *testing.T) {
+	now := time.Now()
+
+	t.Run("ssh keys", func(t *testing.T) {
+		rsaKey, err := sslibsv.LoadKey(artifacts.SSHRSAPublic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ecdsaKey, err := sslibsv.LoadKey(artifacts.SSHECDSAPublic)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		revocations := &Revocations{}
+		if err := revocations.ParseSSHRevocationList(bytes.NewReader(artifacts.SSHRSAPublicSSH)); err != nil {
+			t.Fatal(err)
+		}
+
+		err = revocations.Check(rsaKey, now)
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+
+		err = revocations.Check(ecdsaKey, now)
+		assert.Nil(t, err)
+	})
+
+	t.Run("gpg key revoked as compromised", func(t *testing.T) {
+		key, certificate := createTestGPGRevocation(t, packet.KeyCompromised)
+
+		revocations := &Revocations{}
+		if err := revocations.ParseGPGRevocationCertificate(certificate); err != nil {
+			t.Fatal(err)
+		}
+
+		// Signatures issued before the key was revoked are also rejected
+		err := revocations.Check(key, now.Add(-time.Hour))
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+	})
+
+	t.Run("gpg key retired", func(t *testing.T) {
+		key, certificate := createTestGPGRevocation(t, packet.KeyRetired)
+
+		revocations := &Revocations{}
+		if err := revocations.ParseGPGRevocationCertificate(certificate); err != nil {
+			t.Fatal(err)
+		}
+
+		err := revocations.Check(key, now.Add(-time.Hour))
+		assert.Nil(t, err)
+
+		err = revocations.Check(key, now.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+	})
+
+	t.Run("gpg revocation certificate for another key", func(t *testing.T) {
+		key, _ := createTestGPGRevocation(t, packet.KeyCompromised)
+		_, certificate := createTestGPGRevocation(t, packet.KeyCompromised)
+
+		revocations := &Revocations{}
+		if err := revocations.ParseGPGRevocationCertificate(certificate); err != nil {
+			t.Fatal(err)
+		}
+
+		err := revocations.Check(key, now)
+		assert.Nil(t, err)
+	})
+
+	t.Run("gpg revocation certificate stored by gnupg", func(t *testing.T) {
+		key, certificate := createTestGPGRevocation(t, packet.KeyCompromised)
+		certificate = append([]byte("This is a revocation certificate\n\n:"), certificate...)
+
+		revocations := &Revocations{}
+		if err := revocations.ParseGPGRevocationCertificate(certificate); err != nil {
+			t.Fatal(err)
+		}
+
+		err := revocations.Check(key, now)
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+	})
+
+	t.Run("gpg key revoked in keyring", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("test uses a shell script as the GnuPG program")
+		}
+
+		key, entity := createTestGPGEntity(t)
+		if err := entity.RevokeKey(packet.KeyCompromised, "", nil); err != nil {
+			t.Fatal(err)
+		}
+
+		exported := &bytes.Buffer{}
+		if err := entity.Serialize(exported); err != nil {
+			t.Fatal(err)
+		}
+
+		tmpDir := t.TempDir()
+		exportedPath := filepath.Join(tmpDir, "exported.gpg")
+		if err := os.WriteFile(exportedPath, exported.Bytes(), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		programPath := filepath.Join(tmpDir, "gpg")
+		if err := os.WriteFile(programPath, []byte(fmt.Sprintf("#!/bin/sh\ncat '%s'\n", exportedPath)), 0o700); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+
+		revocations := &Revocations{}
+		err := revocations.Check(key, now)
+		assert.Nil(t, err)
+
+		revocations.ConsultGPGKeyring(programPath)
+		err = revocations.Check(key, now)
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+	})
+
+	t.Run("revocations in context", func(t *testing.T) {
+		key, certificate := createTestGPGRevocation(t, packet.NoReason)
+
+		err := checkRevocation(context.Background(), key, nil)
+		assert.Nil(t, err)
+
+		revocations := &Revocations{}
+		if err := revocations.ParseGPGRevocationCertificate(certificate); err != nil {
+			t.Fatal(err)
+		}
+
+		err = checkRevocation(ContextWithRevocations(context.Background(), revocations), key, nil)
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+	})
+
+	t.Run("gpg key retired with unknown signing time", func(t *testing.T) {
+		key, certificate := createTestGPGRevocation(t, packet.KeyRetired)
+
+		revocations := &Revocations{}
+		if err := revocations.ParseGPGRevocationCertificate(certificate); err != nil {
+			t.Fatal(err)
+		}
+
+		err := revocations.Check(key, time.Time{})
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+	})
+}
+
+func TestVerifyCommitSignatureWithRetiredKey(t *testing.T) {
+	key, entity := createTestGPGEntity(t)
+
+	// The commit is dated before the key is retired, but may have been signed
+	// afterwards
+	now := time.Now()
+	commit := &object.Commit{
+		Author:    object.Signature{Name: "Jane Doe", Email: "jane.doe@example.com", When: now.Add(-24 * time.Hour)},
+		Committer: object.Signature{Name: "Jane Doe", Email: "jane.doe@example.com", When: now.Add(-24 * time.Hour)},
+		Message:   "Backdated commit\n",
+		TreeHash:  plumbing.ZeroHash,
+	}
+
+	contents, err := getCommitContents(commit)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := &strings.Builder{}
+	if err := openpgp.ArmoredDetachSign(signature, entity, contents, nil); err != nil {
+		t.Fatal(err)
+	}
+	commit.PGPSignature = signature.String()
+
+	if err := entity.RevokeKey(packet.KeyRetired, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	certificate := &bytes.Buffer{}
+	writer, err := armor.Encode(certificate, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Revocations[0].Serialize(writer); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	revocations := &Revocations{}
+	if err := revocations.ParseGPGRevocationCertificate(certificate.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	ctx := ContextWithRevocations(context.Background(), revocations)
+
+	t.Run("no trusted signing time", func(t *testing.T) {
+		err := VerifyCommitSignature(ctx, commit, key)
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+	})
+
+	t.Run("signed after key was retired", func(t *testing.T) {
+		ctx := ContextWithSigningTimes(ctx, func(_ context.Context, _ []byte) (time.Time, error) {
+			return now.Add(time.Hour), nil
+		})
+
+		err := VerifyCommitSignature(ctx, commit, key)
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+	})
+
+	t.Run("signed before key was retired", func(t *testing.T) {
+		ctx := ContextWithSigningTimes(ctx, func(_ context.Context, signature []byte) (time.Time, error) {
+			assert.Equal(t, []byte(commit.PGPSignature), signature)
+			return now.Add(-time.Hour), nil
+		})
+
+		err := VerifyCommitSignature(ctx, commit, key)
+		assert.Nil(t, err)
+	})
+}
+
+// createTestGPGEntity returns a new GPG key and the gittuf key for its public
+// key.
+func createTestGPGEntity(t *testing.T) (*tuf.Key, *openpgp.Entity) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("Jane Doe", "", "jane.doe@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey := &bytes.Buffer{}
+	writer, err := armor.Encode(publicKey, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(writer); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	key := &tuf.Key{
+		KeyType: signerverifier.GPGKeyType,
+		Scheme:  signerverifier.GPGKeyType,
+		KeyVal:  sslibsv.KeyVal{Public: publicKey.String()},
+	}
+
+	return key, entity
+}
+
+// createTestGPGRevocation returns a new GPG key and an armored revocation
+// certificate for it with the reason.
+func createTestGPGRevocation(t *testing.T, reason packet.ReasonForRevocation) (*tuf.Key, []byte) {
+	t.Helper()
+
+	key, entity := createTestGPGEntity(t)
+	if err := entity.RevokeKey(reason, "", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	certificate := &bytes.Buffer{}
+	writer, err := armor.Encode(certificate, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Revocations[0].Serialize(writer); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return key, certificate.Bytes()
+}
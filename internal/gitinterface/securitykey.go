@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"golang.org/x/crypto/ssh"
+)
+
+// These are the flags set by FIDO2 security keys in SSH signatures, see
+// PROTOCOL.u2f in OpenSSH.
+const (
+	securityKeyFlagUserPresent  byte = 0x01
+	securityKeyFlagUserVerified byte = 0x04
+)
+
+const opensshPrivateKeyMagic = "openssh-key-v1\x00"
+
+var ErrSecurityKeyFlagsNotSet = errors.New("security key did not confirm the user's presence or verify the user as required")
+
+// securityKeyPublicKey is an SSH public key held in a FIDO2 security key.
+// Besides verifying the signature, Verify checks that the security key
+// confirmed the user's presence, which ssh.PublicKey doesn't, and verified the
+// user if required by the key's options.
+type securityKeyPublicKey struct {
+	ssh.PublicKey
+	requiredFlags byte
+}
+
+// newSecurityKeyPublicKey returns the SSH public key for the key in gittuf
+// metadata, which requires the flags set by its options.
+func newSecurityKeyPublicKey(key *tuf.Key) (*securityKeyPublicKey, error) {
+	publicKey, options, err := signerverifier.ParseSSHSecurityKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	requiredFlags := securityKeyFlagUserPresent
+	if slices.Contains(options, signerverifier.SSHSecurityKeyNoTouchRequired) {
+		requiredFlags = 0
+	}
+	if slices.Contains(options, signerverifier.SSHSecurityKeyVerifyRequired) {
+		requiredFlags |= securityKeyFlagUserVerified
+	}
+
+	return &securityKeyPublicKey{PublicKey: publicKey, requiredFlags: requiredFlags}, nil
+}
+
+func (k *securityKeyPublicKey) Verify(data []byte, signature *ssh.Signature) error {
+	if err := k.PublicKey.Verify(data, signature); err != nil {
+		return err
+	}
+
+	var fields struct {
+		Flags   byte
+		Counter uint32
+	}
+	if err := ssh.Unmarshal(signature.Rest, &fields); err != nil {
+		return errors.Join(ErrInvalidSignature, err)
+	}
+
+	if fields.Flags&k.requiredFlags != k.requiredFlags {
+		return fmt.Errorf("%w: signature has flags 0x%02x, 0x%02x are required", ErrSecurityKeyFlagsNotSet, fields.Flags, k.requiredFlags)
+	}
+
+	return nil
+}
+
+// getSecurityKeyPublicKey returns the public key of an SSH private key in the
+// OpenSSH format if the private key is held in a FIDO2 security key. The
+// public key isn't encrypted, so it's returned for keys protected by a
+// passphrase as well.
+func getSecurityKeyPublicKey(pemKeyBytes []byte) (ssh.PublicKey, bool) {
+	block, _ := pem.Decode(pemKeyBytes)
+	if block == nil || block.Type != opensshPrivateKeyPEMHeader || !bytes.HasPrefix(block.Bytes, []byte(opensshPrivateKeyMagic)) {
+		return nil, false
+	}
+
+	var envelope struct {
+		CipherName   string
+		KdfName      string
+		KdfOpts      string
+		NumKeys      uint32
+		PubKey       []byte
+		PrivKeyBlock []byte
+	}
+	if err := ssh.Unmarshal(block.Bytes[len(opensshPrivateKeyMagic):], &envelope); err != nil {
+		return nil, false
+	}
+
+	publicKey, err := ssh.ParsePublicKey(envelope.PubKey)
+	if err != nil || !signerverifier.IsSSHSecurityKeyAlgorithm(publicKey.Type()) {
+		return nil, false
+	}
+
+	return publicKey, true
+}
+
+// signGitObjectUsingSecurityKey signs the Git object using an SSH key held in
+// a FIDO2 security key, as the private key on disk only references the key on
+// the security key. The SSH agent is used if it holds the key, and ssh-keygen
+// is invoked with the private key otherwise. Either may wait for the user to
+// touch the security key.
+func signGitObjectUsingSecurityKey(contents objectContents, pemKeyBytes []byte, publicKey ssh.PublicKey) (string, error) {
+	fingerprint := ssh.FingerprintSHA256(publicKey)
+
+	signature, err := signGitObjectUsingSSHAgent(contents, fingerprint)
+	if err == nil {
+		return signature, nil
+	}
+	logger.Debug(fmt.Sprintf("Unable to sign using security key '%s' in SSH agent, using %s: %s", fingerprint, DefaultSigningProgramSSH, err.Error()))
+
+	keyFile, cleanup, err := writeTemporaryKeyFile("gittuf-ssh-signing-key-*", pemKeyBytes)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	return runSigningProgram(DefaultSigningProgramSSH, []string{"-Y", "sign", "-n", namespaceSSHSignature, "-f", keyFile}, contents)
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/pem"
+	"io"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/hiddeco/sshsig"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+// testSecurityKeySigner signs like an ed25519-sk key held in a FIDO2 security
+// key that sets the flags in its signatures.
+type testSecurityKeySigner struct {
+	privateKey  ed25519.PrivateKey
+	publicKey   ssh.PublicKey
+	application string
+	flags       byte
+}
+
+func newTestSecurityKeySigner(t *testing.T, flags byte) *testSecurityKeySigner {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := ssh.ParsePublicKey(ssh.Marshal(struct {
+		Name        string
+		KeyBytes    []byte
+		Application string
+	}{ssh.KeyAlgoSKED25519, public, "ssh:"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testSecurityKeySigner{privateKey: private, publicKey: publicKey, application: "ssh:", flags: flags}
+}
+
+func (s *testSecurityKeySigner) PublicKey() ssh.PublicKey {
+	return s.publicKey
+}
+
+func (s *testSecurityKeySigner) Sign(_ io.Reader, data []byte) (*ssh.Signature, error) {
+	applicationDigest := sha256.Sum256([]byte(s.application))
+	dataDigest := sha256.Sum256(data)
+
+	// The flags are followed by the signature counter
+	fields := []byte{s.flags, 0, 0, 0, 1}
+
+	signed := append(append(applicationDigest[:], fields...), dataDigest[:]...)
+
+	return &ssh.Signature{
+		Format: ssh.KeyAlgoSKED25519,
+		Blob:   ed25519.Sign(s.privateKey, signed),
+		Rest:   fields,
+	}, nil
+}
+
+func (s *testSecurityKeySigner) sign(t *testing.T, contents []byte) []byte {
+	t.Helper()
+
+	signature, err := sshsig.Sign(bytes.NewReader(contents), s, sshsig.HashSHA512, namespaceSSHSignature)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sshsig.Armor(signature)
+}
+
+func TestVerifySSHSecurityKeySignature(t *testing.T) {
+	contents := []byte("test commit contents")
+
+	tests := map[string]struct {
+		flags   byte
+		options string
+		err     error
+	}{
+		"user present": {
+			flags: securityKeyFlagUserPresent,
+		},
+		"user not present": {
+			flags: 0,
+			err:   ErrSecurityKeyFlagsNotSet,
+		},
+		"user not present, no touch required": {
+			flags:   0,
+			options: "no-touch-required ",
+		},
+		"user present, verification required": {
+			flags:   securityKeyFlagUserPresent,
+			options: "verify-required ",
+			err:     ErrSecurityKeyFlagsNotSet,
+		},
+		"user present and verified, verification required": {
+			flags:   securityKeyFlagUserPresent | securityKeyFlagUserVerified,
+			options: "verify-required ",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			signer := newTestSecurityKeySigner(t, test.flags)
+
+			key, err := signerverifier.NewSSHSecurityKey(append([]byte(test.options), ssh.MarshalAuthorizedKey(signer.PublicKey())...))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signature := signer.sign(t, contents)
+
+			err = verifySSHKeySignature(key, contents, signature)
+			if test.err == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.err)
+			}
+
+			err = verifySSHKeySignature(key, []byte("modified contents"), signature)
+			assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+
+			method, err := GetSignatureMethod(key, signature)
+			assert.Nil(t, err)
+			assert.Equal(t, "ed25519-sk", method.Algorithm)
+		})
+	}
+}
+
+func TestGetSecurityKeyPublicKey(t *testing.T) {
+	signer := newTestSecurityKeySigner(t, securityKeyFlagUserPresent)
+
+	// The private key of keys held in security keys only contains the key's
+	// handle, which isn't read
+	privateKey := append([]byte(opensshPrivateKeyMagic), ssh.Marshal(struct {
+		CipherName   string
+		KdfName      string
+		KdfOpts      string
+		NumKeys      uint32
+		PubKey       []byte
+		PrivKeyBlock []byte
+	}{"none", "none", "", 1, signer.PublicKey().Marshal(), []byte("key handle")})...)
+	pemKeyBytes := pem.EncodeToMemory(&pem.Block{Type: opensshPrivateKeyPEMHeader, Bytes: privateKey})
+
+	publicKey, isSecurityKey := getSecurityKeyPublicKey(pemKeyBytes)
+	assert.True(t, isSecurityKey)
+	assert.Equal(t, signer.PublicKey().Marshal(), publicKey.Marshal())
+
+	_, isSecurityKey = getSecurityKeyPublicKey(artifacts.SSHED25519Private)
+	assert.False(t, isSecurityKey)
+
+	_, isSecurityKey = getSecurityKeyPublicKey(artifacts.SSHRSAPrivate)
+	assert.False(t, isSecurityKey)
+}
@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"github.com/go-git/go-git/v5"
+)
+
+// IsShallow returns true if the repository is a shallow clone, such as one
+// created using `git clone --depth`. The parents of the commits at the shallow
+// boundary are not available in such repositories.
+func IsShallow(repo *git.Repository) (bool, error) {
+	shallowCommits, err := repo.Storer.Shallow()
+	if err != nil {
+		return false, err
+	}
+
+	return len(shallowCommits) != 0, nil
+}
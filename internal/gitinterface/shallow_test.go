@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsShallow(t *testing.T) {
+	sourceDir := t.TempDir()
+	cloneDir := t.TempDir()
+
+	runGit := func(args ...string) {
+		t.Helper()
+
+		if output, err := exec.Command(binary, args...).CombinedOutput(); err != nil {
+			t.Fatalf("%s: %s", err, output)
+		}
+	}
+
+	runGit("init", "-b", "main", sourceDir)
+	for _, message := range []string{"First commit", "Second commit"} {
+		runGit("-C", sourceDir, "-c", "user.name="+testName, "-c", "user.email="+testEmail, "-c", "commit.gpgsign=false", "commit", "--allow-empty", "-m", message)
+	}
+
+	runGit("clone", "--quiet", "--depth", "1", "file://"+sourceDir, cloneDir)
+
+	sourceRepo, err := OpenRepository(sourceDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	isShallow, err := IsShallow(sourceRepo)
+	assert.Nil(t, err)
+	assert.False(t, isShallow)
+
+	cloneRepo, err := OpenRepository(cloneDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	isShallow, err = IsShallow(cloneRepo)
+	assert.Nil(t, err)
+	assert.True(t, isShallow)
+}
@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// signatureCacheFileName is the name of the file in the signature cache
+// directory that results are appended to.
+const signatureCacheFileName = "signatures"
+
+var (
+	signatureCache   *persistentSignatureCache
+	signatureCacheMu sync.Mutex
+)
+
+// persistentSignatureCache stores the results of verifying Git signatures in a
+// file, one JSON encoded result per line. Results are only ever appended, so
+// that concurrent invocations of gittuf can share the cache, and lines that
+// can't be parsed, such as those left by an interrupted write, are ignored.
+type persistentSignatureCache struct {
+	path string
+
+	loadOnce sync.Once
+	mu       sync.Mutex
+	results  map[cachedSignatureKey]bool
+}
+
+type cachedSignatureKey struct {
+	Scope    string `json:"scope"`
+	ObjectID string `json:"object_id"`
+	KeyID    string `json:"key_id"`
+}
+
+type cachedSignatureResult struct {
+	cachedSignatureKey
+	Verified bool `json:"verified"`
+}
+
+type signatureCacheScopeContextKey struct{}
+
+// SetSignatureCacheDir persists the results of verifying the signatures of
+// Git commits and tags in the specified directory, typically the repository's
+// .git/gittuf/cache, so that later invocations of gittuf, such as repeated
+// verification of a ref in CI, don't verify the same signatures again. Results
+// are only persisted for verifications whose context carries a cache scope,
+// and are reused only in the same scope. Only the results of GPG and SSH keys
+// are persisted, as the results for Sigstore and S/MIME keys also depend on
+// the trusted certificates. The cache isn't authenticated, so anyone who can
+// write to the directory can make signatures appear verified; it must only be
+// enabled when they're trusted. If the directory is empty, results aren't
+// persisted, which is the default.
+func SetSignatureCacheDir(dir string) {
+	signatureCacheMu.Lock()
+	defer signatureCacheMu.Unlock()
+
+	if dir == "" {
+		signatureCache = nil
+		return
+	}
+
+	signatureCache = &persistentSignatureCache{path: filepath.Join(dir, signatureCacheFileName)}
+}
+
+// SignatureCacheEnabled returns true if the results of verifying signatures are
+// persisted, so that callers can skip determining cache scopes otherwise.
+func SignatureCacheEnabled() bool {
+	return getSignatureCache() != nil
+}
+
+// ContextWithSignatureCacheScope returns a copy of the context that carries the
+// specified cache scope, which identifies the state the trust in the keys used
+// for verification is derived from, such as the ID of a policy state. Results
+// of verifying signatures using the context are persisted for the scope.
+func ContextWithSignatureCacheScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, signatureCacheScopeContextKey{}, scope)
+}
+
+// signatureCacheScopeFromContext returns the cache scope carried by the
+// context, if any.
+func signatureCacheScopeFromContext(ctx context.Context) string {
+	scope, _ := ctx.Value(signatureCacheScopeContextKey{}).(string)
+	return scope
+}
+
+// getSignatureCache returns the persistent signature cache, or nil if results
+// aren't persisted.
+func getSignatureCache() *persistentSignatureCache {
+	signatureCacheMu.Lock()
+	defer signatureCacheMu.Unlock()
+
+	return signatureCache
+}
+
+// signatureCacheable returns true if the result of verifying a signature using
+// the key depends only on the signed object and the key.
+func signatureCacheable(key *tuf.Key) bool {
+	switch key.KeyType {
+	case signerverifier.GPGKeyType, signerverifier.RSAKeyType, signerverifier.ECDSAKeyType, signerverifier.ED25519KeyType, signerverifier.SSHSecurityKeyType:
+		return true
+	default:
+		return false
+	}
+}
+
+// get returns the persisted result of verifying the object's signature using
+// the key in the scope, which indicates if the signature was issued by the key,
+// and whether a result was persisted.
+func (c *persistentSignatureCache) get(scope string, objectID plumbing.Hash, keyID string) (bool, bool) {
+	if c == nil || scope == "" {
+		return false, false
+	}
+
+	c.loadOnce.Do(c.load)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	verified, has := c.results[cachedSignatureKey{Scope: scope, ObjectID: objectID.String(), KeyID: keyID}]
+	return verified, has
+}
+
+// put persists the result of verifying the object's signature using the key in
+// the scope. Only definitive results are persisted: the signature was either
+// issued by the key or it wasn't.
+func (c *persistentSignatureCache) put(scope string, objectID plumbing.Hash, keyID string, err error) {
+	if c == nil || scope == "" {
+		return
+	}
+	if err != nil && !errors.Is(err, ErrIncorrectVerificationKey) {
+		return
+	}
+
+	result := cachedSignatureResult{
+		cachedSignatureKey: cachedSignatureKey{Scope: scope, ObjectID: objectID.String(), KeyID: keyID},
+		Verified:           err == nil,
+	}
+
+	c.loadOnce.Do(c.load)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, has := c.results[result.cachedSignatureKey]; has {
+		return
+	}
+	c.results[result.cachedSignatureKey] = result.Verified
+
+	if err := c.append(&result); err != nil {
+		// Verification isn't affected, the result just has to be obtained
+		// again by the next invocation
+		logger.Debug(fmt.Sprintf("Unable to persist signature verification result: %s", err.Error()))
+	}
+}
+
+// load reads the results persisted by earlier invocations.
+func (c *persistentSignatureCache) load() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.results = map[cachedSignatureKey]bool{}
+
+	f, err := os.Open(c.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Debug(fmt.Sprintf("Unable to load signature cache: %s", err.Error()))
+		}
+		return
+	}
+	defer f.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		result := cachedSignatureResult{}
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			continue
+		}
+		c.results[result.cachedSignatureKey] = result.Verified
+	}
+}
+
+// append writes the result to the end of the cache file.
+func (c *persistentSignatureCache) append(result *cachedSignatureResult) error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o750); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(c.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	// A single write, so that lines written concurrently by other
+	// invocations aren't interleaved
+	_, err = f.Write(append(line, '\n'))
+	return errors.Join(err, f.Close())
+}
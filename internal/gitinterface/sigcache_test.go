@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignatureCache(t *testing.T) {
+	commit := createTestSignedCommit(t)
+	encoded := memory.NewStorage().NewEncodedObject()
+	if err := commit.Encode(encoded); err != nil {
+		t.Fatal(err)
+	}
+	commit.Hash = encoded.Hash()
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cacheDir := t.TempDir()
+	SetSignatureCacheDir(cacheDir)
+	t.Cleanup(func() { SetSignatureCacheDir("") })
+
+	cachePath := filepath.Join(cacheDir, signatureCacheFileName)
+	verify := func(ctx context.Context, commit *object.Commit) error {
+		// A new verifier, as for a new invocation of gittuf
+		return VerifyCommitSignature(ContextWithBatchVerifier(ctx, NewBatchVerifier(1)), commit, gpgKey)
+	}
+
+	t.Run("results persisted in scope", func(t *testing.T) {
+		ctx := ContextWithSignatureCacheScope(context.Background(), "policy-1")
+
+		assert.Nil(t, verify(ctx, commit))
+
+		contents, err := os.ReadFile(cachePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, strings.Count(string(contents), "\n"))
+		assert.Contains(t, string(contents), commit.Hash.String())
+
+		// Reused by the next invocation without verifying again
+		SetSignatureCacheDir(cacheDir)
+		modified := *commit
+		modified.PGPSignature = ""
+		assert.Nil(t, verify(ctx, &modified))
+	})
+
+	t.Run("results not reused in other scopes", func(t *testing.T) {
+		ctx := ContextWithSignatureCacheScope(context.Background(), "policy-2")
+
+		modified := *commit
+		modified.PGPSignature = ""
+		assert.NotNil(t, verify(ctx, &modified))
+	})
+
+	t.Run("results not persisted without scope", func(t *testing.T) {
+		SetSignatureCacheDir(t.TempDir())
+
+		assert.Nil(t, verify(context.Background(), commit))
+		assert.NoFileExists(t, getSignatureCache().path)
+	})
+
+	t.Run("malformed lines ignored", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, signatureCacheFileName), []byte("{\"scope\":\n"), 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+		SetSignatureCacheDir(dir)
+
+		ctx := ContextWithSignatureCacheScope(context.Background(), "policy-1")
+		assert.Nil(t, verify(ctx, commit))
+	})
+}
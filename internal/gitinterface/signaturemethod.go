@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/smime"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/hiddeco/sshsig"
+	"golang.org/x/crypto/ssh"
+)
+
+// These are the formats of Git signatures reported in SignatureMethod.
+const (
+	SignatureFormatGPG      = "gpg"
+	SignatureFormatSSH      = "ssh"
+	SignatureFormatSigstore = "sigstore"
+	SignatureFormatSMIME    = "smime"
+)
+
+// SignatureFormatX509 is reported by GetSignatureFormat for X.509 signatures,
+// which are Sigstore or S/MIME signatures depending on the key that issued them.
+const SignatureFormatX509 = "x509"
+
+// SignatureMethod describes the mechanism used to create a Git signature: the
+// signature's format, the algorithm of the signing key, such as "ed25519" or
+// "rsa", and the hash algorithm, such as "sha512". For Sigstore signatures,
+// Issuer is the OIDC issuer that authenticated the signer.
+type SignatureMethod struct {
+	Format    string
+	Algorithm string
+	Hash      string
+	Issuer    string
+}
+
+func (m *SignatureMethod) String() string {
+	details := []string{m.Algorithm, m.Hash}
+	if m.Issuer != "" {
+		details = append(details, fmt.Sprintf("issuer %s", m.Issuer))
+	}
+
+	return fmt.Sprintf("%s (%s)", m.Format, strings.Join(details, ", "))
+}
+
+// GetSignatureMethod returns the mechanism used to create the Git signature,
+// which must have been verified using the key. Sigstore and S/MIME signatures
+// use the same encoding, so they are told apart using the key. The signature is
+// not verified, and the Issuer of Sigstore signatures is the key's issuer,
+// which verification checks the signing certificate against.
+func GetSignatureMethod(key *tuf.Key, signature []byte) (*SignatureMethod, error) {
+	switch key.KeyType {
+	case signerverifier.GPGKeyType:
+		return getGPGSignatureMethod(signature)
+	case signerverifier.RSAKeyType, signerverifier.ECDSAKeyType, signerverifier.ED25519KeyType, signerverifier.SSHSecurityKeyType:
+		return getSSHSignatureMethod(signature)
+	case signerverifier.FulcioKeyType:
+		method, err := getX509SignatureMethod(SignatureFormatSigstore, signature)
+		if err != nil {
+			return nil, err
+		}
+		method.Issuer = key.KeyVal.Issuer
+
+		return method, nil
+	case signerverifier.SMIMEKeyType:
+		return getX509SignatureMethod(SignatureFormatSMIME, signature)
+	}
+
+	return nil, ErrUnknownSigningMethod
+}
+
+// GetSignatureFormat returns the format of the Git signature based on its
+// armor, without a key to verify it with. It returns SignatureFormatX509 for
+// Sigstore and S/MIME signatures, and an empty string if the format is unknown.
+func GetSignatureFormat(signature []byte) string {
+	switch {
+	case bytes.HasPrefix(signature, []byte(gpgSignatureHeader)):
+		return SignatureFormatGPG
+	case bytes.HasPrefix(signature, []byte(sshSignatureHeader)):
+		return SignatureFormatSSH
+	case bytes.HasPrefix(signature, []byte(x509SignatureHeader)):
+		return SignatureFormatX509
+	}
+
+	return ""
+}
+
+func getGPGSignatureMethod(signature []byte) (*SignatureMethod, error) {
+	block, err := armor.Decode(strings.NewReader(string(signature)))
+	if err != nil {
+		return nil, errors.Join(ErrInvalidSignature, err)
+	}
+
+	p, err := packet.Read(block.Body)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidSignature, err)
+	}
+	sig, ok := p.(*packet.Signature)
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	var algorithm string
+	switch sig.PubKeyAlgo {
+	case packet.PubKeyAlgoRSA, packet.PubKeyAlgoRSASignOnly:
+		algorithm = "rsa"
+	case packet.PubKeyAlgoDSA:
+		algorithm = "dsa"
+	case packet.PubKeyAlgoECDSA:
+		algorithm = "ecdsa"
+	case packet.PubKeyAlgoEdDSA:
+		algorithm = "ed25519"
+	default:
+		algorithm = fmt.Sprintf("algorithm-%d", sig.PubKeyAlgo)
+	}
+
+	return &SignatureMethod{Format: SignatureFormatGPG, Algorithm: algorithm, Hash: hashName(sig.Hash)}, nil
+}
+
+func getSSHSignatureMethod(signature []byte) (*SignatureMethod, error) {
+	sshSignature, err := sshsig.Unarmor(signature)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidSignature, err)
+	}
+
+	// Keys held in security keys are reported like ssh-keygen's key types,
+	// so that policies can require them
+	var algorithm string
+	switch sshSignature.PublicKey.Type() {
+	case ssh.KeyAlgoRSA:
+		algorithm = "rsa"
+	case ssh.KeyAlgoDSA:
+		algorithm = "dsa"
+	case ssh.KeyAlgoECDSA256, ssh.KeyAlgoECDSA384, ssh.KeyAlgoECDSA521:
+		algorithm = "ecdsa"
+	case ssh.KeyAlgoSKECDSA256:
+		algorithm = "ecdsa-sk"
+	case ssh.KeyAlgoED25519:
+		algorithm = "ed25519"
+	case ssh.KeyAlgoSKED25519:
+		algorithm = "ed25519-sk"
+	default:
+		algorithm = sshSignature.PublicKey.Type()
+	}
+
+	return &SignatureMethod{Format: SignatureFormatSSH, Algorithm: algorithm, Hash: string(sshSignature.HashAlgorithm)}, nil
+}
+
+func getX509SignatureMethod(format string, signature []byte) (*SignatureMethod, error) {
+	cert, hash, err := smime.Inspect(signature)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidSignature, err)
+	}
+
+	var algorithm string
+	switch cert.PublicKeyAlgorithm {
+	case x509.RSA:
+		algorithm = "rsa"
+	case x509.DSA:
+		algorithm = "dsa"
+	case x509.ECDSA:
+		algorithm = "ecdsa"
+	case x509.Ed25519:
+		algorithm = "ed25519"
+	default:
+		algorithm = strings.ToLower(cert.PublicKeyAlgorithm.String())
+	}
+
+	return &SignatureMethod{Format: format, Algorithm: algorithm, Hash: hashName(hash)}, nil
+}
+
+// hashName returns the name of the hash algorithm as used by ssh-keygen, such
+// as "sha256" or "sha512".
+func hashName(hash crypto.Hash) string {
+	return strings.ToLower(strings.Replace(hash.String(), "SHA-", "SHA", 1))
+}
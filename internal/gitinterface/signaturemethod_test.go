@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	cms "github.com/github/smimesign/ietf-cms"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSignatureMethod(t *testing.T) {
+	contents := []byte("test commit contents")
+
+	t.Run("GPG signature", func(t *testing.T) {
+		key, err := gpg.LoadGPGKeyFromBytes(artifacts.GPGKey1Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature, err := signGitObjectUsingGPGKey(contentsFromBytes(contents), artifacts.GPGKey1Private)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		method, err := GetSignatureMethod(key, []byte(signature))
+		assert.Nil(t, err)
+		assert.Equal(t, &SignatureMethod{Format: SignatureFormatGPG, Algorithm: "rsa", Hash: "sha256"}, method)
+		assert.Equal(t, "gpg (rsa, sha256)", method.String())
+	})
+
+	t.Run("SSH signature", func(t *testing.T) {
+		key, err := tuf.LoadKeyFromBytes(artifacts.SSHED25519Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature, err := signGitObjectUsingSSHKey(contentsFromBytes(contents), artifacts.SSHED25519Private)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		method, err := GetSignatureMethod(key, []byte(signature))
+		assert.Nil(t, err)
+		assert.Equal(t, &SignatureMethod{Format: SignatureFormatSSH, Algorithm: "ed25519", Hash: "sha512"}, method)
+	})
+
+	t.Run("signature in another format", func(t *testing.T) {
+		key, err := tuf.LoadKeyFromBytes(artifacts.SSHED25519Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature, err := signGitObjectUsingGPGKey(contentsFromBytes(contents), artifacts.GPGKey1Private)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = GetSignatureMethod(key, []byte(signature))
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("unsupported key type", func(t *testing.T) {
+		_, err := GetSignatureMethod(&tuf.Key{KeyType: "unknown"}, nil)
+		assert.ErrorIs(t, err, ErrUnknownSigningMethod)
+	})
+
+	t.Run("X.509 signatures", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template := &x509.Certificate{
+			SerialNumber:   big.NewInt(1),
+			EmailAddresses: []string{"jane.doe@example.com"},
+			NotBefore:      time.Now().Add(-time.Hour),
+			NotAfter:       time.Now().Add(time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signature, err := cms.SignDetached(contents, []*x509.Certificate{cert}, privateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = pem.EncodeToMemory(&pem.Block{Type: "SIGNED MESSAGE", Bytes: signature})
+
+		sigstoreKey := &tuf.Key{
+			KeyType: signerverifier.FulcioKeyType,
+			KeyVal:  sslibsv.KeyVal{Identity: "jane.doe@example.com", Issuer: "https://github.com/login/oauth"},
+		}
+		method, err := GetSignatureMethod(sigstoreKey, signature)
+		assert.Nil(t, err)
+		assert.Equal(t, &SignatureMethod{Format: SignatureFormatSigstore, Algorithm: "ecdsa", Hash: "sha256", Issuer: "https://github.com/login/oauth"}, method)
+		assert.Equal(t, "sigstore (ecdsa, sha256, issuer https://github.com/login/oauth)", method.String())
+
+		smimeKey := &tuf.Key{
+			KeyType: signerverifier.SMIMEKeyType,
+			KeyVal:  sslibsv.KeyVal{Identity: "jane.doe@example.com"},
+		}
+		method, err = GetSignatureMethod(smimeKey, signature)
+		assert.Nil(t, err)
+		assert.Equal(t, &SignatureMethod{Format: SignatureFormatSMIME, Algorithm: "ecdsa", Hash: "sha256"}, method)
+	})
+}
+
+func TestGetSignatureFormat(t *testing.T) {
+	contents := []byte("test commit contents")
+
+	gpgSignature, err := signGitObjectUsingGPGKey(contentsFromBytes(contents), artifacts.GPGKey1Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshSignature, err := signGitObjectUsingSSHKey(contentsFromBytes(contents), artifacts.SSHED25519Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, SignatureFormatGPG, GetSignatureFormat([]byte(gpgSignature)))
+	assert.Equal(t, SignatureFormatSSH, GetSignatureFormat([]byte(sshSignature)))
+	assert.Equal(t, SignatureFormatX509, GetSignatureFormat([]byte("-----BEGIN SIGNED MESSAGE-----\n")))
+	assert.Equal(t, "", GetSignatureFormat(nil))
+}
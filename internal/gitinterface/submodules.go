@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	gitModulesFile = ".gitmodules"
+	modulesDir     = "modules"
+)
+
+var (
+	ErrSubmoduleNotCloned   = errors.New("submodule repository not found, has it been initialized?")
+	ErrInvalidSubmoduleName = errors.New("invalid submodule name")
+)
+
+// GetSubmoduleUpdatesByCommit returns the submodule pointers changed by the
+// commit relative to its parents, mapping each submodule's path to the
+// submodule commit recorded by the commit. Removed submodules are mapped to
+// the zero hash. Like GetFilePathsChangedByCommit, the changes relative to
+// each parent of a merge commit are combined, unless the commit's tree matches
+// one of its parents.
+func GetSubmoduleUpdatesByCommit(repo *git.Repository, commit *object.Commit) (map[string]plumbing.Hash, error) {
+	tree, err := GetTree(repo, commit.TreeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	parentTrees := []*object.Tree{}
+	for _, parentID := range commit.ParentHashes {
+		parent, err := GetCommit(repo, parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.TreeHash == commit.TreeHash {
+			return map[string]plumbing.Hash{}, nil
+		}
+
+		parentTree, err := GetTree(repo, parent.TreeHash)
+		if err != nil {
+			return nil, err
+		}
+		parentTrees = append(parentTrees, parentTree)
+	}
+	if len(parentTrees) == 0 {
+		// Compare with an empty tree for the root commit
+		parentTrees = append(parentTrees, nil)
+	}
+
+	updates := map[string]plumbing.Hash{}
+	for _, parentTree := range parentTrees {
+		changes, err := object.DiffTree(parentTree, tree)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, change := range changes {
+			switch {
+			case change.To.Name != "" && change.To.TreeEntry.Mode == filemode.Submodule:
+				updates[change.To.Name] = change.To.TreeEntry.Hash
+			case change.From.Name != "" && change.From.TreeEntry.Mode == filemode.Submodule:
+				if _, has := updates[change.From.Name]; !has {
+					updates[change.From.Name] = plumbing.ZeroHash
+				}
+			}
+		}
+	}
+
+	return updates, nil
+}
+
+// GetSubmoduleName returns the name of the submodule at the specified path, as
+// recorded in the .gitmodules file of the commit. The name identifies the
+// submodule's repository in the superproject's GIT_DIR. If the submodule isn't
+// listed, its path is returned, as Git uses the path as the name by default.
+func GetSubmoduleName(repo *git.Repository, commit *object.Commit, path string) (string, error) {
+	tree, err := GetTree(repo, commit.TreeHash)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := tree.File(gitModulesFile)
+	if err != nil {
+		if errors.Is(err, object.ErrFileNotFound) {
+			return path, nil
+		}
+		return "", err
+	}
+
+	contents, err := file.Contents()
+	if err != nil {
+		return "", err
+	}
+
+	modules := config.NewModules()
+	if err := modules.Unmarshal([]byte(contents)); err != nil {
+		return "", err
+	}
+
+	for name, submodule := range modules.Submodules {
+		if submodule.Path == path {
+			return name, nil
+		}
+	}
+
+	return path, nil
+}
+
+// OpenSubmoduleRepository opens the repository of the submodule with the
+// specified name. Git stores the repositories of initialized submodules in the
+// "modules" directory of the superproject's common GIT_DIR, so the superproject
+// must be stored on disk.
+func OpenSubmoduleRepository(repo *git.Repository, name string) (*git.Repository, error) {
+	commonDir, err := GetGitCommonDirFor(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Git rejects submodule names that could escape the modules directory
+	for _, component := range strings.Split(filepath.ToSlash(name), "/") {
+		if component == "" || component == "." || component == ".." {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidSubmoduleName, name)
+		}
+	}
+
+	submoduleDir := filepath.Join(commonDir, modulesDir, filepath.FromSlash(name))
+	if _, err := os.Stat(submoduleDir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: '%s'", ErrSubmoduleNotCloned, name)
+		}
+		return nil, err
+	}
+
+	return openRepository(submoduleDir, &git.PlainOpenOptions{})
+}
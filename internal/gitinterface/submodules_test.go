@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSubmoduleUpdatesByCommit(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	submoduleCommitA := plumbing.NewHash("a94a8fe5ccb19ba61c4c0873d391e987982fbbd3")
+	submoduleCommitB := plumbing.NewHash("e69de29bb2d1d6434b8b29ae775ad8c2e48c5391")
+
+	blobID, err := WriteBlob(repo, []byte("README"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeCommit := func(entries []object.TreeEntry, parents []plumbing.Hash) *object.Commit {
+		t.Helper()
+
+		treeID, err := WriteTree(repo, entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, treeID, parents, "Test commit", testClock))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commit, err := GetCommit(repo, commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return commit
+	}
+
+	rootCommit := writeCommit([]object.TreeEntry{
+		{Name: "README.md", Mode: filemode.Regular, Hash: blobID},
+		{Name: "lib", Mode: filemode.Submodule, Hash: submoduleCommitA},
+	}, nil)
+
+	updates, err := GetSubmoduleUpdatesByCommit(repo, rootCommit)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]plumbing.Hash{"lib": submoduleCommitA}, updates)
+
+	bumpCommit := writeCommit([]object.TreeEntry{
+		{Name: "README.md", Mode: filemode.Regular, Hash: blobID},
+		{Name: "lib", Mode: filemode.Submodule, Hash: submoduleCommitB},
+	}, []plumbing.Hash{rootCommit.Hash})
+
+	updates, err = GetSubmoduleUpdatesByCommit(repo, bumpCommit)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]plumbing.Hash{"lib": submoduleCommitB}, updates)
+
+	fileCommit := writeCommit([]object.TreeEntry{
+		{Name: "README.md", Mode: filemode.Regular, Hash: EmptyBlob()},
+		{Name: "lib", Mode: filemode.Submodule, Hash: submoduleCommitB},
+	}, []plumbing.Hash{bumpCommit.Hash})
+
+	updates, err = GetSubmoduleUpdatesByCommit(repo, fileCommit)
+	assert.Nil(t, err)
+	assert.Empty(t, updates)
+
+	removeCommit := writeCommit([]object.TreeEntry{
+		{Name: "README.md", Mode: filemode.Regular, Hash: EmptyBlob()},
+	}, []plumbing.Hash{fileCommit.Hash})
+
+	updates, err = GetSubmoduleUpdatesByCommit(repo, removeCommit)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]plumbing.Hash{"lib": plumbing.ZeroHash}, updates)
+}
+
+func TestGetSubmoduleName(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gitModules := "[submodule \"vendored-lib\"]\n\tpath = third_party/lib\n\turl = https://example.com/lib.git\n"
+	blobID, err := WriteBlob(repo, []byte(gitModules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	treeID, err := WriteTree(repo, []object.TreeEntry{{Name: gitModulesFile, Mode: filemode.Regular, Hash: blobID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, treeID, nil, "Test commit", testClock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := GetCommit(repo, commitID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := GetSubmoduleName(repo, commit, "third_party/lib")
+	assert.Nil(t, err)
+	assert.Equal(t, "vendored-lib", name)
+
+	// Submodules not listed use their path as the name
+	name, err = GetSubmoduleName(repo, commit, "other")
+	assert.Nil(t, err)
+	assert.Equal(t, "other", name)
+}
+
+func TestOpenSubmoduleRepository(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	repo, err := git.PlainInit(tmpDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = OpenSubmoduleRepository(repo, "lib")
+	assert.ErrorIs(t, err, ErrSubmoduleNotCloned)
+
+	_, err = OpenSubmoduleRepository(repo, "../lib")
+	assert.ErrorIs(t, err, ErrInvalidSubmoduleName)
+
+	if _, err := git.PlainInit(filepath.Join(tmpDir, ".git", "modules", "lib"), true); err != nil {
+		t.Fatal(err)
+	}
+
+	submoduleRepo, err := OpenSubmoduleRepository(repo, "lib")
+	assert.Nil(t, err)
+	assert.NotNil(t, submoduleRepo)
+
+	memoryRepo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = OpenSubmoduleRepository(memoryRepo, "lib")
+	assert.ErrorIs(t, err, ErrRepositoryNotOnDisk)
+}
@@ -5,7 +5,9 @@ package gitinterface
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
@@ -17,6 +19,14 @@ import (
 
 const DefaultRemoteName = "origin"
 
+// mirrorRefSpecs fetch the remote's branches, tags, and gittuf refs into a
+// mirror.
+var mirrorRefSpecs = []config.RefSpec{
+	"+refs/heads/*:refs/heads/*",
+	"+refs/tags/*:refs/tags/*",
+	"+refs/gittuf/*:refs/gittuf/*",
+}
+
 // PushRefSpec pushes from repo to the specified remote using pre-constructed
 // refspecs. For more information on the Git refspec, please consult:
 // https://git-scm.com/book/en/v2/Git-Internals-The-Refspec.
@@ -110,9 +120,13 @@ func Fetch(ctx context.Context, repo *git.Repository, remoteName string, refs []
 }
 
 // CloneAndFetch clones a repository using the specified URL and additionally
-// fetches the specified refs.
+// fetches the specified refs. The working tree is not populated so that the
+// clone can be verified first, CheckoutHead must be used to populate it.
 func CloneAndFetch(ctx context.Context, remoteURL, dir, initialBranch string, refs []string) (*git.Repository, error) {
-	repo, err := git.PlainCloneContext(ctx, dir, false, createCloneOptions(remoteURL, initialBranch))
+	cloneOptions := createCloneOptions(remoteURL, initialBranch)
+	cloneOptions.NoCheckout = true
+
+	repo, err := git.PlainCloneContext(ctx, dir, false, cloneOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -120,6 +134,22 @@ func CloneAndFetch(ctx context.Context, remoteURL, dir, initialBranch string, re
 	return fetchRefs(ctx, repo, refs, true)
 }
 
+// CheckoutHead populates the index and the working tree of the repository with
+// the contents of the commit HEAD points to.
+func CheckoutHead(repo *git.Repository) error {
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	return worktree.Reset(&git.ResetOptions{Commit: head.Hash(), Mode: git.HardReset})
+}
+
 // CloneAndFetchToMemory clones an in-memory repository using the specified URL
 // and additionally fetches the specified refs.
 func CloneAndFetchToMemory(ctx context.Context, remoteURL, initialBranch string, refs []string) (*git.Repository, error) {
@@ -131,6 +161,64 @@ func CloneAndFetchToMemory(ctx context.Context, remoteURL, initialBranch string,
 	return fetchRefs(ctx, repo, refs, true)
 }
 
+// FetchMirror updates the bare mirror of the repository at remoteURL stored at
+// path, creating the mirror if needed. The mirror contains the remote's
+// branches, tags, and gittuf refs, and refs deleted on the remote are removed.
+func FetchMirror(ctx context.Context, path, remoteURL string, auth transport.AuthMethod) (*git.Repository, error) {
+	repo, err := git.PlainOpen(path)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			return nil, err
+		}
+
+		repo, err = git.PlainInit(path, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := repo.CreateRemote(&config.RemoteConfig{Name: DefaultRemoteName, URLs: []string{remoteURL}}); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := CheckObjectFormat(repo); err != nil {
+		return nil, err
+	}
+
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: DefaultRemoteName,
+		RemoteURL:  remoteURL,
+		RefSpecs:   mirrorRefSpecs,
+		Auth:       auth,
+		Force:      true,
+		Prune:      true,
+	})
+	if errors.Is(err, git.NoErrAlreadyUpToDate) || errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return repo, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// SyncMirror updates the bare mirror of the repository at remoteURL stored at
+// path, creating the mirror if needed, as FetchMirror does. It's the sync
+// function used by the services that verify mirrors of repositories.
+func SyncMirror(ctx context.Context, path, remoteURL string, auth transport.AuthMethod) error {
+	_, err := FetchMirror(ctx, path, remoteURL, auth)
+	return err
+}
+
+// IsRemoteLocation returns true if the location of a repository is a URL
+// rather than a path.
+func IsRemoteLocation(location string) bool {
+	return strings.Contains(location, "://") || strings.HasPrefix(location, "git@")
+}
+
 func createCloneOptions(remoteURL, initialBranch string) *git.CloneOptions {
 	cloneOptions := &git.CloneOptions{
 		URL:      remoteURL,
@@ -153,3 +241,25 @@ func fetchRefs(ctx context.Context, repo *git.Repository, refs []string, fastFor
 
 	return repo, nil
 }
+
+// PushMirror pushes the specified refs of a mirror created using FetchMirror to
+// the remote at remoteURL. The refs are pushed atomically, and only if they
+// fast-forward the remote's refs.
+func PushMirror(ctx context.Context, repo *git.Repository, remoteURL string, refs []string, auth transport.AuthMethod) error {
+	refSpecs := make([]config.RefSpec, 0, len(refs))
+	for _, refName := range refs {
+		refSpecs = append(refSpecs, config.RefSpec(fmt.Sprintf("%s:%s", refName, refName)))
+	}
+
+	err := repo.PushContext(ctx, &git.PushOptions{
+		RemoteName: DefaultRemoteName,
+		RemoteURL:  remoteURL,
+		RefSpecs:   refSpecs,
+		Auth:       auth,
+		Atomic:     true,
+	})
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	return err
+}
@@ -5,12 +5,15 @@ package gitinterface
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
@@ -652,6 +655,58 @@ func TestCloneAndFetch(t *testing.T) {
 	})
 }
 
+func TestCheckoutHead(t *testing.T) {
+	remoteTmpDir := t.TempDir()
+	localTmpDir := t.TempDir()
+	refName := "refs/heads/main"
+
+	remoteRepo, err := git.PlainInit(remoteTmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blobID, err := WriteBlob(remoteRepo, []byte("Hello, world!\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeHash, err := WriteTree(remoteRepo, []object.TreeEntry{{Name: "README.md", Mode: filemode.Regular, Hash: blobID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Commit(remoteRepo, treeHash, refName, "Initial commit", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := remoteRepo.Storer.SetReference(plumbing.NewSymbolicReference("HEAD", plumbing.ReferenceName(refName))); err != nil {
+		t.Fatal(err)
+	}
+
+	localRepo, err := CloneAndFetch(context.Background(), remoteTmpDir, localTmpDir, refName, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The working tree isn't populated until HEAD is checked out
+	_, err = os.Stat(filepath.Join(localTmpDir, "README.md"))
+	assert.True(t, os.IsNotExist(err))
+
+	err = CheckoutHead(localRepo)
+	assert.Nil(t, err)
+
+	contents, err := os.ReadFile(filepath.Join(localTmpDir, "README.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, "Hello, world!\n", string(contents))
+
+	worktree, err := localRepo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, status.IsClean())
+}
+
 func TestCloneAndFetchToMemory(t *testing.T) {
 	refName := "refs/heads/main"
 	anotherRefName := "refs/heads/feature"
@@ -793,3 +848,74 @@ func assertLocalRefAndRemoteTrackerRef(t *testing.T, repo *git.Repository, refNa
 	}
 	assert.Equal(t, expectedCommitID, localRemoteTrackerRef.Hash())
 }
+
+func TestFetchMirror(t *testing.T) {
+	refName := "refs/heads/main"
+	anotherRefName := "refs/heads/feature"
+	gittufRefName := "refs/gittuf/reference-state-log"
+
+	remoteTmpDir := t.TempDir()
+	mirrorTmpDir := filepath.Join(t.TempDir(), "mirror.git")
+
+	remoteRepo, err := git.PlainInit(remoteTmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emptyTreeHash, err := WriteTree(remoteRepo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitIDs := map[string]plumbing.Hash{}
+	for _, name := range []string{refName, anotherRefName, gittufRefName} {
+		commitID, err := Commit(remoteRepo, emptyTreeHash, name, "Test commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitIDs[name] = commitID
+	}
+
+	// Mirror is created
+	mirror, err := FetchMirror(context.Background(), mirrorTmpDir, remoteTmpDir, nil)
+	assert.Nil(t, err)
+	for name, commitID := range commitIDs {
+		ref, err := mirror.Reference(plumbing.ReferenceName(name), true)
+		assert.Nil(t, err)
+		assert.Equal(t, commitID, ref.Hash())
+	}
+
+	// Mirror is updated, pruning deleted refs
+	if err := remoteRepo.Storer.RemoveReference(plumbing.ReferenceName(anotherRefName)); err != nil {
+		t.Fatal(err)
+	}
+	newCommitID, err := Commit(remoteRepo, emptyTreeHash, refName, "Another test commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mirror, err = FetchMirror(context.Background(), mirrorTmpDir, remoteTmpDir, nil)
+	assert.Nil(t, err)
+	ref, err := mirror.Reference(plumbing.ReferenceName(refName), true)
+	assert.Nil(t, err)
+	assert.Equal(t, newCommitID, ref.Hash())
+	_, err = mirror.Reference(plumbing.ReferenceName(anotherRefName), true)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+	// Mirror is already up to date
+	_, err = FetchMirror(context.Background(), mirrorTmpDir, remoteTmpDir, nil)
+	assert.Nil(t, err)
+}
+
+func TestIsRemoteLocation(t *testing.T) {
+	tests := map[string]bool{
+		"https://github.com/gittuf/gittuf":   true,
+		"ssh://git@github.com/gittuf/gittuf": true,
+		"git@github.com:gittuf/gittuf.git":   true,
+		"/srv/git/gittuf.git":                false,
+		"gittuf":                             false,
+	}
+
+	for location, expected := range tests {
+		assert.Equal(t, expected, IsRemoteLocation(location), location)
+	}
+}
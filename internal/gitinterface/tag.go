@@ -5,16 +5,15 @@ package gitinterface
 import (
 	"context"
 	"errors"
-	"io"
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/timing"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/jonboulle/clockwork"
 )
 
@@ -36,6 +35,8 @@ func IsTag(repo *git.Repository, target string) bool {
 }
 
 // Tag creates a new tag in the repository pointing to the specified target.
+// Like Git, the tag is signed if sign is true or if tag.gpgSign or
+// tag.forceSignAnnotated is enabled in the user's Git config.
 func Tag(repo *git.Repository, target plumbing.Hash, name, message string, sign bool) (plumbing.Hash, error) {
 	gitConfig, err := getGitConfig(repo)
 	if err != nil {
@@ -54,7 +55,18 @@ func Tag(repo *git.Repository, target plumbing.Hash, name, message string, sign
 
 	tag := CreateTagObject(gitConfig, targetObj, name, message, clock)
 
+	sign, err = shouldSign(sign, "tag.gpgsign", "tag.forcesignannotated")
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
 	if sign {
+		// The signature is appended to the message, so, like Git, the message
+		// must end with a newline for the signature to be found when the tag
+		// is read
+		if !strings.HasSuffix(tag.Message, "\n") {
+			tag.Message += "\n"
+		}
+
 		signature, err := signTag(tag)
 		if err != nil {
 			return plumbing.ZeroHash, err
@@ -104,67 +116,42 @@ func CreateTagObject(gitConfig *config.Config, targetObj object.Object, name, me
 }
 
 // VerifyTagSignature is used to verify a cryptographic signature associated
-// with tag using TUF public keys.
+// with tag using TUF public keys. If the context carries allowed signers,
+// SSH signatures must also be issued by an allowed signer at the time the
+// tag was created. If the context carries revocations, ErrKeyRevoked is
+// returned if the key that issued the signature has been revoked.
 func VerifyTagSignature(ctx context.Context, tag *object.Tag, key *tuf.Key) error {
-	switch key.KeyType {
-	case signerverifier.GPGKeyType:
-		if _, err := tag.Verify(key.KeyVal.Public); err != nil {
-			return ErrIncorrectVerificationKey
-		}
-
-		return nil
-	case signerverifier.RSAKeyType, signerverifier.ECDSAKeyType, signerverifier.ED25519KeyType:
-		tagContents, err := getTagBytesWithoutSignature(tag)
-		if err != nil {
-			return errors.Join(ErrVerifyingSSHSignature, err)
-		}
-		tagSignature := []byte(tag.PGPSignature)
-
-		if err := verifySSHKeySignature(key, tagContents, tagSignature); err != nil {
-			return errors.Join(ErrIncorrectVerificationKey, err)
-		}
+	defer timing.Start(timing.PhaseSignatureVerification)()
 
-		return nil
-	case signerverifier.FulcioKeyType:
-		tagContents, err := getTagBytesWithoutSignature(tag)
-		if err != nil {
-			return errors.Join(ErrVerifyingSigstoreSignature, err)
-		}
-		tagSignature := []byte(tag.PGPSignature)
+	if err := verifierForContext(ctx).verifySignature(ctx, tag, key); err != nil {
+		return err
+	}
 
-		if err := verifyGitsignSignature(ctx, key, tagContents, tagSignature); err != nil {
+	switch key.KeyType {
+	case signerverifier.RSAKeyType, signerverifier.ECDSAKeyType, signerverifier.ED25519KeyType, signerverifier.SSHSecurityKeyType:
+		if err := checkAllowedSigner(ctx, []byte(tag.PGPSignature), tag.Tagger.When); err != nil {
 			return errors.Join(ErrIncorrectVerificationKey, err)
 		}
-
-		return nil
 	}
 
-	return ErrUnknownSigningMethod
+	// The key issued the signature, so if it has been revoked, verification
+	// must fail rather than moving on to other keys
+	return checkRevocation(ctx, key, []byte(tag.PGPSignature))
 }
 
 // GetTag returns the requested tag object.
 func GetTag(repo *git.Repository, tagID plumbing.Hash) (*object.Tag, error) {
+	defer timing.Start(timing.PhaseGitObjectReads)()
+
 	return repo.TagObject(tagID)
 }
 
 func signTag(tag *object.Tag) (string, error) {
-	tagContents, err := getTagBytesWithoutSignature(tag)
-	if err != nil {
-		return "", err
-	}
-
-	return signGitObject(tagContents)
+	return signGitObject(getTagContents(tag))
 }
 
-func getTagBytesWithoutSignature(tag *object.Tag) ([]byte, error) {
-	tagEncoded := memory.NewStorage().NewEncodedObject()
-	if err := tag.EncodeWithoutSignature(tagEncoded); err != nil {
-		return nil, err
-	}
-	r, err := tagEncoded.Reader()
-	if err != nil {
-		return nil, err
-	}
-
-	return io.ReadAll(r)
+// getTagContents returns the contents of the tag that are signed, i.e., the
+// tag without its signature.
+func getTagContents(tag *object.Tag) objectContents {
+	return contentsFromGoGitEncoder(tag.EncodeWithoutSignature)
 }
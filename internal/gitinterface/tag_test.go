@@ -5,12 +5,16 @@ package gitinterface
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
 	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
@@ -69,6 +73,35 @@ func TestTag(t *testing.T) {
 	// Try to create a tag with the same name, expect error
 	_, err = Tag(repo, commitID, tagName, tagName, false)
 	assert.ErrorIs(t, err, ErrTagAlreadyExists)
+
+	t.Run("sign tag using tag.gpgsign", func(t *testing.T) {
+		t.Cleanup(func() {
+			getGitConfigFromCommand = execGitConfig
+		})
+
+		keysDir := t.TempDir()
+		setupSigningKeys(t, keysDir)
+
+		configOutput := fmt.Sprintf("global\x00gpg.format\nssh\x00global\x00user.signingkey\n%s\x00global\x00tag.gpgsign\ntrue\x00", filepath.Join(keysDir, "key"))
+		getGitConfigFromCommand = func() (io.Reader, error) {
+			return bytes.NewReader([]byte(configOutput)), nil
+		}
+
+		tagHash, err := Tag(repo, commitID, "v0.2.0", "v0.2.0", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tag, err := GetTag(repo, tagHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := sslibsv.LoadKey(artifacts.SSHED25519Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, VerifyTagSignature(context.Background(), tag, key))
+	})
 }
 
 func TestVerifyTagSignature(t *testing.T) {
@@ -5,12 +5,12 @@ package gitinterface
 import (
 	"errors"
 	"io"
-	"os/exec"
 	"path"
 	"sort"
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/timing"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/filemode"
@@ -34,9 +34,29 @@ func WriteTree(repo *git.Repository, entries []object.TreeEntry) (plumbing.Hash,
 	return repo.Storer.SetEncodedObject(obj)
 }
 
-// GetTree returns the requested tree object.
+// GetTree returns the requested tree object. If caching is enabled for the
+// repository, the tree is parsed once and subsequent requests return a copy of
+// the cached tree.
 func GetTree(repo *git.Repository, treeID plumbing.Hash) (*object.Tree, error) {
-	return repo.TreeObject(treeID)
+	defer timing.Start(timing.PhaseGitObjectReads)()
+
+	storage := getCachedStorage(repo)
+	if storage == nil {
+		return repo.TreeObject(treeID)
+	}
+
+	tree, has := storage.trees.get(treeID)
+	if !has {
+		var err error
+		tree, err = repo.TreeObject(treeID)
+		if err != nil {
+			return nil, err
+		}
+		storage.trees.add(treeID, tree)
+	}
+
+	treeCopy := *tree
+	return &treeCopy, nil
 }
 
 // EmptyTree returns the hash of an empty tree in a Git repository.
@@ -101,7 +121,7 @@ func GetMergeTree(repo *git.Repository, commitAID, commitBID string) (string, er
 	}
 
 	// go-git does not support three way merges
-	command := exec.Command("git", "merge-tree", commitAID, commitBID) //nolint:gosec
+	command := newGitCommand("merge-tree", commitAID, commitBID)
 	stdOut, err := command.Output()
 	if err != nil {
 		return "", err
@@ -6,11 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage"
 	"github.com/jonboulle/clockwork"
 )
 
@@ -19,10 +21,17 @@ const (
 	BranchRefPrefix = "refs/heads/"
 	TagRefPrefix    = "refs/tags/"
 	RemoteRefPrefix = "refs/remotes/"
+
+	// GerritChangeRefPrefix is the prefix of the refs Gerrit creates for the
+	// patch sets and metadata of each change under review, of the form
+	// `refs/changes/<shard>/<change-number>/<patch-set>` and
+	// `refs/changes/<shard>/<change-number>/meta`.
+	GerritChangeRefPrefix = "refs/changes/"
 )
 
 var (
-	ErrReferenceNotFound = plumbing.ErrReferenceNotFound
+	ErrReferenceNotFound   = plumbing.ErrReferenceNotFound
+	ErrReferenceHasChanged = storage.ErrReferenceHasChanged
 
 	clock = clockwork.NewRealClock()
 )
@@ -39,8 +48,18 @@ func GetTip(repo *git.Repository, refName string) (plumbing.Hash, error) {
 
 // ResetCommit sets a Git reference with the name refName to the commit
 // specified by its hash as commitID. Note that the commit must already be in
-// the repository's object store.
+// the repository's object store. The working tree is only updated if the
+// reference is checked out, so this can be used in bare repositories.
 func ResetCommit(repo *git.Repository, refName string, commitID plumbing.Hash) error {
+	head, err := repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return err
+	}
+
+	if IsBare(repo) || head.Type() != plumbing.SymbolicReference || head.Target().String() != refName {
+		return repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), commitID))
+	}
+
 	currentHEAD, err := repo.Head()
 	if err != nil {
 		return err
@@ -175,3 +194,46 @@ func RemoteRef(refName, remoteName string) string {
 
 	return remotePath
 }
+
+// GerritChangeRef returns the ref identifying the Gerrit change that the
+// specified patch set or metadata ref belongs to, of the form
+// `refs/changes/<change-number>`. The shard directory and the patch set are
+// dropped so that policies can refer to all the refs of a change, or of every
+// change, without knowing them in advance. The second return value is false if
+// refName is not a Gerrit change ref.
+func GerritChangeRef(refName string) (string, bool) {
+	if !strings.HasPrefix(refName, GerritChangeRefPrefix) {
+		return "", false
+	}
+
+	components := strings.Split(strings.TrimPrefix(refName, GerritChangeRefPrefix), "/")
+	if len(components) != 3 {
+		return "", false
+	}
+
+	changeNumber, err := strconv.Atoi(components[1])
+	if err != nil || changeNumber <= 0 {
+		return "", false
+	}
+
+	// Gerrit shards change refs using the last two digits of the change number
+	if components[0] != fmt.Sprintf("%02d", changeNumber%100) {
+		return "", false
+	}
+
+	if _, err := strconv.Atoi(components[2]); err != nil && components[2] != "meta" {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s%d", GerritChangeRefPrefix, changeNumber), true
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
 )
@@ -159,3 +160,107 @@ func TestRefSpec(t *testing.T) {
 		assert.Equal(t, test.expectedRefSpec, refSpec, fmt.Sprintf("unexpected refspec returned in test '%s'", name))
 	}
 }
+
+func TestGerritChangeRef(t *testing.T) {
+	tests := map[string]struct {
+		refName           string
+		expectedChangeRef string
+		expectedOk        bool
+	}{
+		"patch set": {
+			refName:           "refs/changes/34/1234/2",
+			expectedChangeRef: "refs/changes/1234",
+			expectedOk:        true,
+		},
+		"change metadata": {
+			refName:           "refs/changes/34/1234/meta",
+			expectedChangeRef: "refs/changes/1234",
+			expectedOk:        true,
+		},
+		"single digit change": {
+			refName:           "refs/changes/07/7/1",
+			expectedChangeRef: "refs/changes/7",
+			expectedOk:        true,
+		},
+		"wrong shard": {
+			refName: "refs/changes/12/1234/2",
+		},
+		"missing patch set": {
+			refName: "refs/changes/34/1234",
+		},
+		"invalid patch set": {
+			refName: "refs/changes/34/1234/latest",
+		},
+		"branch": {
+			refName: "refs/heads/main",
+		},
+	}
+
+	for name, test := range tests {
+		changeRef, ok := GerritChangeRef(test.refName)
+		assert.Equal(t, test.expectedOk, ok, fmt.Sprintf("unexpected result in test '%s'", name))
+		assert.Equal(t, test.expectedChangeRef, changeRef, fmt.Sprintf("unexpected change ref in test '%s'", name))
+	}
+}
+
+func TestResetCommit(t *testing.T) {
+	refName := "refs/heads/main"
+
+	t.Run("bare repository", func(t *testing.T) {
+		repo, err := git.PlainInit(t.TempDir(), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, IsBare(repo))
+
+		emptyTreeHash, err := WriteTree(repo, []object.TreeEntry{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		firstCommitID, err := Commit(repo, emptyTreeHash, refName, "First commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Commit(repo, emptyTreeHash, refName, "Second commit", false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = ResetCommit(repo, refName, firstCommitID)
+		assert.Nil(t, err)
+
+		tip, err := GetTip(repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, firstCommitID, tip)
+	})
+
+	t.Run("repository with worktree", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.False(t, IsBare(repo))
+
+		emptyTreeHash, err := WriteTree(repo, []object.TreeEntry{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		otherRefName := "refs/heads/feature"
+		firstCommitID, err := Commit(repo, emptyTreeHash, otherRefName, "First commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Commit(repo, emptyTreeHash, otherRefName, "Second commit", false); err != nil {
+			t.Fatal(err)
+		}
+
+		// The ref isn't checked out, so the worktree is untouched
+		err = ResetCommit(repo, otherRefName, firstCommitID)
+		assert.Nil(t, err)
+
+		tip, err := GetTip(repo, otherRefName)
+		assert.Nil(t, err)
+		assert.Equal(t, firstCommitID, tip)
+	})
+}
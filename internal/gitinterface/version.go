@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// MinimumGitVersion is the oldest version of Git that gittuf supports. Git
+// 2.38 introduced the non-trivial mode of `git merge-tree` that gittuf uses
+// to compute the result of merges.
+const MinimumGitVersion = "2.38.0"
+
+var ErrUnknownGitVersion = errors.New("unable to parse Git version")
+
+// GetGitVersion returns the version of the Git binary installed on the
+// system, such as "2.43.0".
+func GetGitVersion() (string, error) {
+	output, err := exec.Command(binary, "--version").Output()
+	if err != nil {
+		return "", err
+	}
+
+	return parseGitVersion(string(output))
+}
+
+// IsSupportedGitVersion indicates if the specified version of Git is
+// MinimumGitVersion or newer.
+func IsSupportedGitVersion(version string) (bool, error) {
+	current, err := splitVersion(version)
+	if err != nil {
+		return false, err
+	}
+
+	minimum, err := splitVersion(MinimumGitVersion)
+	if err != nil {
+		return false, err
+	}
+
+	for index := range minimum {
+		if current[index] != minimum[index] {
+			return current[index] > minimum[index], nil
+		}
+	}
+
+	return true, nil
+}
+
+// parseGitVersion extracts the version from the output of `git --version`,
+// e.g. "git version 2.39.3 (Apple Git-145)" or "git version
+// 2.41.0.windows.1".
+func parseGitVersion(output string) (string, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[0] != "git" || fields[1] != "version" {
+		return "", fmt.Errorf("%w from '%s'", ErrUnknownGitVersion, strings.TrimSpace(output))
+	}
+
+	components := strings.Split(fields[2], ".")
+	if len(components) > 3 {
+		components = components[:3]
+	}
+	version := strings.Join(components, ".")
+
+	if _, err := splitVersion(version); err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
+
+// splitVersion returns the major, minor, and patch components of the version.
+// Missing components are treated as zero.
+func splitVersion(version string) ([3]int, error) {
+	components := [3]int{}
+
+	for index, component := range strings.SplitN(version, ".", 3) {
+		value, err := strconv.Atoi(component)
+		if err != nil {
+			return components, fmt.Errorf("%w from '%s'", ErrUnknownGitVersion, version)
+		}
+		components[index] = value
+	}
+
+	return components, nil
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGitVersion(t *testing.T) {
+	tests := map[string]struct {
+		output          string
+		expectedVersion string
+		expectedError   error
+	}{
+		"linux": {
+			output:          "git version 2.43.0\n",
+			expectedVersion: "2.43.0",
+		},
+		"macOS": {
+			output:          "git version 2.39.3 (Apple Git-145)\n",
+			expectedVersion: "2.39.3",
+		},
+		"windows": {
+			output:          "git version 2.41.0.windows.1\n",
+			expectedVersion: "2.41.0",
+		},
+		"unexpected output": {
+			output:        "not git\n",
+			expectedError: ErrUnknownGitVersion,
+		},
+		"unexpected version": {
+			output:        "git version two\n",
+			expectedError: ErrUnknownGitVersion,
+		},
+	}
+
+	for name, test := range tests {
+		version, err := parseGitVersion(test.output)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, name)
+		} else {
+			assert.Nil(t, err, name)
+			assert.Equal(t, test.expectedVersion, version, name)
+		}
+	}
+}
+
+func TestIsSupportedGitVersion(t *testing.T) {
+	tests := map[string]struct {
+		version   string
+		supported bool
+	}{
+		"minimum":     {version: MinimumGitVersion, supported: true},
+		"newer patch": {version: "2.38.1", supported: true},
+		"newer minor": {version: "2.43.0", supported: true},
+		"newer major": {version: "3.0", supported: true},
+		"older minor": {version: "2.34.1", supported: false},
+		"older major": {version: "1.9.5", supported: false},
+	}
+
+	for name, test := range tests {
+		supported, err := IsSupportedGitVersion(test.version)
+		assert.Nil(t, err, name)
+		assert.Equal(t, test.supported, supported, name)
+	}
+
+	_, err := IsSupportedGitVersion("unknown")
+	assert.ErrorIs(t, err, ErrUnknownGitVersion)
+}
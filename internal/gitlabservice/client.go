@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitlabservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/credentials"
+)
+
+var ErrUnexpectedResponse = errors.New("unexpected response from GitLab")
+
+// client is a minimal client for the parts of GitLab's REST API used by the
+// service.
+type client struct {
+	baseURL     string
+	credentials credentials.Source
+	httpClient  *http.Client
+}
+
+type commitStatus struct {
+	State       string `json:"state"`
+	Name        string `json:"name"`
+	Ref         string `json:"ref,omitempty"`
+	Description string `json:"description"`
+}
+
+type mergeRequest struct {
+	IID    int64  `json:"iid"`
+	WebURL string `json:"web_url"`
+}
+
+type note struct {
+	Body string `json:"body"`
+}
+
+// setCommitStatus sets the status of the commit in the project.
+func (c *client) setCommitStatus(ctx context.Context, projectID int64, commitID string, status *commitStatus) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("projects/%d/statuses/%s", projectID, url.PathEscape(commitID)), status, nil)
+}
+
+// listOpenMergeRequests returns the open merge requests in the project from
+// the specified source branch.
+func (c *client) listOpenMergeRequests(ctx context.Context, projectID int64, sourceBranch string) ([]*mergeRequest, error) {
+	query := url.Values{}
+	query.Set("state", "opened")
+	query.Set("source_branch", sourceBranch)
+
+	mergeRequests := []*mergeRequest{}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("projects/%d/merge_requests?%s", projectID, query.Encode()), nil, &mergeRequests); err != nil {
+		return nil, err
+	}
+
+	return mergeRequests, nil
+}
+
+// addMergeRequestNote comments on the merge request.
+func (c *client) addMergeRequestNote(ctx context.Context, projectID, mergeRequestIID int64, body string) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("projects/%d/merge_requests/%d/notes", projectID, mergeRequestIID), &note{Body: body}, nil)
+}
+
+func (c *client) do(ctx context.Context, method, path string, body, response any) error {
+	var requestBody io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		requestBody = bytes.NewReader(bodyBytes)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.baseURL, "/")+"/api/v4/"+path, requestBody)
+	if err != nil {
+		return err
+	}
+	token, err := c.credentials.Token(ctx)
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return ErrMissingToken
+	}
+	if token.JobToken {
+		request.Header.Set("JOB-TOKEN", token.Value)
+	} else {
+		request.Header.Set("PRIVATE-TOKEN", token.Value)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: %s %s returned %s: %s", ErrUnexpectedResponse, method, path, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(response)
+}
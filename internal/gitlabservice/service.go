@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitlabservice
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/gittuf/gittuf/internal/webhook"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+const (
+	// DefaultURL is the URL of GitLab.com. Self-managed instances are used by
+	// specifying their URL instead.
+	DefaultURL = "https://gitlab.com"
+
+	// StatusName is the name of the commit statuses set by the service.
+	// Merge request approval rules and pipelines can require this status to
+	// block unverified changes from being merged.
+	StatusName = "gittuf"
+
+	// Commit status states used by the service.
+	stateRunning = "running"
+	stateSuccess = "success"
+	stateFailed  = "failed"
+
+	// maxDescriptionLength is the maximum length of a commit status's
+	// description accepted by GitLab.
+	maxDescriptionLength = 255
+
+	// Webhook headers and event types sent by GitLab.
+	tokenHeader      = "X-Gitlab-Token"
+	eventHeader      = "X-Gitlab-Event"
+	pushHookEvent    = "Push Hook"
+	tagPushHookEvent = "Tag Push Hook"
+
+	branchRefPrefix       = "refs/heads/"
+	gittufNamespacePrefix = "refs/gittuf/"
+)
+
+var (
+	ErrMissingToken         = errors.New("GitLab access token must be set") //nolint:stylecheck
+	ErrMissingWebhookSecret = errors.New("GitLab webhook secret must be set")
+)
+
+var (
+	syncRepository   = gitinterface.SyncMirror
+	verifyRepository = webhook.VerifyRef
+)
+
+// PushEvent is the payload of GitLab's push and tag push webhooks.
+type PushEvent struct {
+	ObjectKind  string `json:"object_kind"`
+	Ref         string `json:"ref"`
+	Before      string `json:"before"`
+	After       string `json:"after"`
+	CheckoutSHA string `json:"checkout_sha"`
+	ProjectID   int64  `json:"project_id"`
+	Project     struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+		GitHTTPURL        string `json:"git_http_url"`
+	} `json:"project"`
+}
+
+// Config contains the settings of the service.
+type Config struct {
+	// URL is the URL of the GitLab instance, such as DefaultURL.
+	URL string
+
+	// Credentials is the source of GitLab access tokens with the api scope
+	// for the projects the service verifies, used to fetch the projects and to
+	// report results.
+	Credentials credentials.Source
+
+	// WebhookSecret is the secret token configured for the projects' webhooks.
+	WebhookSecret string
+
+	// CacheDir is the directory the projects are mirrored to for
+	// verification.
+	CacheDir string
+
+	// Metrics is the registry the service's verification metrics are
+	// registered with. If nil, metrics are not recorded.
+	Metrics *metrics.Registry
+}
+
+// Service verifies every push to the GitLab projects that send it push
+// webhooks against the project's gittuf policy. The result is reported as a
+// commit status on the pushed commit, and verification failures are also
+// noted on the open merge requests from the pushed branch.
+type Service struct {
+	client        *client
+	credentials   credentials.Source
+	webhookSecret string
+	verifier      *webhook.Verifier
+}
+
+// NewService returns a Service for the specified configuration.
+func NewService(config *Config) (*Service, error) {
+	if config.Credentials == nil {
+		return nil, ErrMissingToken
+	}
+	if config.WebhookSecret == "" {
+		return nil, ErrMissingWebhookSecret
+	}
+
+	baseURL := config.URL
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+
+	return &Service{
+		client: &client{
+			baseURL:     baseURL,
+			credentials: config.Credentials,
+			httpClient:  &http.Client{Timeout: 30 * time.Second},
+		},
+		credentials:   config.Credentials,
+		webhookSecret: config.WebhookSecret,
+		verifier:      webhook.NewVerifier(config.CacheDir, config.Metrics, verifyRepository),
+	}, nil
+}
+
+// ServeHTTP handles webhook deliveries. Push events are acknowledged
+// immediately and verified in the background, as verification may take longer
+// than GitLab waits for a response. Other events are ignored.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(tokenHeader)), []byte(s.webhookSecret)) != 1 {
+		http.Error(w, "invalid webhook token", http.StatusUnauthorized)
+		return
+	}
+
+	eventType := r.Header.Get(eventHeader)
+	if eventType != pushHookEvent && eventType != tagPushHookEvent {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	event := &PushEvent{}
+	if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+		http.Error(w, "invalid push event", http.StatusBadRequest)
+		return
+	}
+
+	s.verifier.Go(event.Project.PathWithNamespace, event.Ref, func(ctx context.Context) error {
+		return s.HandlePush(ctx, event)
+	})
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Wait blocks until the verification of all acknowledged pushes completes.
+func (s *Service) Wait() {
+	s.verifier.Wait()
+}
+
+// HandlePush verifies the ref updated by a push event against the project's
+// gittuf policy, and sets the commit status of the pushed commit to the
+// result. If verification fails, a note is added to each open merge request
+// from the pushed branch. Deleted refs and gittuf's own refs are not verified.
+func (s *Service) HandlePush(ctx context.Context, event *PushEvent) error {
+	if plumbing.NewHash(event.After).IsZero() || strings.HasPrefix(event.Ref, gittufNamespacePrefix) {
+		return nil
+	}
+
+	// Statuses are set on commits, so annotated tags use the tagged commit
+	commitID := event.CheckoutSHA
+	if commitID == "" {
+		commitID = event.After
+	}
+
+	setStatus := func(state, description string) error {
+		if len(description) > maxDescriptionLength {
+			description = description[:maxDescriptionLength-3] + "..."
+		}
+
+		return s.client.setCommitStatus(ctx, event.ProjectID, commitID, &commitStatus{
+			State:       state,
+			Name:        StatusName,
+			Ref:         strings.TrimPrefix(event.Ref, branchRefPrefix),
+			Description: description,
+		})
+	}
+
+	if err := setStatus(stateRunning, fmt.Sprintf("Verifying %s", event.Ref)); err != nil {
+		return err
+	}
+
+	token, err := s.credentials.Token(ctx)
+	if err == nil && token == nil {
+		err = ErrMissingToken
+	}
+	if err != nil {
+		return errors.Join(err, setStatus(stateFailed, "Unable to authenticate to GitLab"))
+	}
+
+	verifyErr := s.verifier.VerifyRef(ctx, event.Project.PathWithNamespace, fmt.Sprintf("%d.git", event.ProjectID), event.Ref, func(ctx context.Context, path string) error {
+		return syncRepository(ctx, path, event.Project.GitHTTPURL, tokenAuth(token))
+	})
+	switch {
+	case errors.Is(verifyErr, webhook.ErrSyncFailed):
+		return errors.Join(verifyErr, setStatus(stateFailed, "Unable to fetch repository"))
+	case verifyErr == nil:
+		return setStatus(stateSuccess, fmt.Sprintf("gittuf verification succeeded for %s", event.Ref))
+	}
+
+	if err := setStatus(stateFailed, fmt.Sprintf("gittuf verification failed: %s", verifyErr.Error())); err != nil {
+		return err
+	}
+
+	return s.noteMergeRequests(ctx, event, commitID, verifyErr)
+}
+
+// noteMergeRequests adds a note describing the verification failure to each
+// open merge request from the pushed branch.
+func (s *Service) noteMergeRequests(ctx context.Context, event *PushEvent, commitID string, verifyErr error) error {
+	if !strings.HasPrefix(event.Ref, branchRefPrefix) {
+		return nil
+	}
+
+	mergeRequests, err := s.client.listOpenMergeRequests(ctx, event.ProjectID, strings.TrimPrefix(event.Ref, branchRefPrefix))
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(":x: gittuf verification failed for `%s` at %s:\n\n```\n%s\n```\n", event.Ref, commitID, verifyErr.Error())
+	for _, mergeRequest := range mergeRequests {
+		if err := s.client.addMergeRequestNote(ctx, event.ProjectID, mergeRequest.IID, body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tokenAuth returns the auth method used to fetch projects with the service's
+// access token.
+func tokenAuth(token *credentials.Token) transport.AuthMethod {
+	// GitLab identifies the kind of token using the username
+	username := "oauth2"
+	if token.JobToken {
+		username = "gitlab-ci-token"
+	}
+
+	return &githttp.BasicAuth{Username: username, Password: token.Value}
+}
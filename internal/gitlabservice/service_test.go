@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitlabservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/credentials"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/webhook"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testToken         = "access-token"
+	testWebhookSecret = "webhook-secret"
+	testCommitID      = "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"
+)
+
+// fakeGitLab is a minimal GitLab API that records the commit statuses set and
+// the notes added to merge requests.
+type fakeGitLab struct {
+	mu       sync.Mutex
+	statuses []*commitStatus
+	notes    map[int64][]string
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("PRIVATE-TOKEN") != testToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/15/statuses/"+testCommitID:
+		status := &commitStatus{}
+		if err := json.NewDecoder(r.Body).Decode(status); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.statuses = append(f.statuses, status)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("{}")) //nolint:errcheck
+
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/15/merge_requests":
+		mergeRequests := []*mergeRequest{}
+		if r.URL.Query().Get("state") == "opened" && r.URL.Query().Get("source_branch") == "feature" {
+			mergeRequests = append(mergeRequests, &mergeRequest{IID: 3}, &mergeRequest{IID: 4})
+		}
+		json.NewEncoder(w).Encode(mergeRequests) //nolint:errcheck
+
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/v4/projects/15/merge_requests/"):
+		n := &note{}
+		if err := json.NewDecoder(r.Body).Decode(n); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var iid int64
+		switch r.URL.Path {
+		case "/api/v4/projects/15/merge_requests/3/notes":
+			iid = 3
+		case "/api/v4/projects/15/merge_requests/4/notes":
+			iid = 4
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.notes[iid] = append(f.notes[iid], n.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("{}")) //nolint:errcheck
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeGitLab) states() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	states := []string{}
+	for _, status := range f.statuses {
+		states = append(states, status.State)
+	}
+	return states
+}
+
+func TestService(t *testing.T) {
+	var verifyErr error
+	verifiedRefs := []string{}
+	syncRepository = func(_ context.Context, _, cloneURL string, auth transport.AuthMethod) error {
+		assert.Equal(t, "https://gitlab.example.com/group/repo.git", cloneURL)
+		assert.Equal(t, testToken, auth.(*githttp.BasicAuth).Password)
+		return nil
+	}
+	verifyRepository = func(_ context.Context, _, refName string) error {
+		verifiedRefs = append(verifiedRefs, refName)
+		return verifyErr
+	}
+	t.Cleanup(func() {
+		syncRepository = gitinterface.SyncMirror
+		verifyRepository = webhook.VerifyRef
+	})
+
+	newService := func(t *testing.T) (*Service, *fakeGitLab) {
+		t.Helper()
+
+		api := &fakeGitLab{notes: map[int64][]string{}}
+		server := httptest.NewServer(api)
+		t.Cleanup(server.Close)
+
+		service, err := NewService(&Config{
+			URL:           server.URL,
+			Credentials:   credentials.Static(testToken),
+			WebhookSecret: testWebhookSecret,
+			CacheDir:      t.TempDir(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return service, api
+	}
+
+	newPushEvent := func(refName string) *PushEvent {
+		event := &PushEvent{
+			ObjectKind:  "push",
+			Ref:         refName,
+			After:       testCommitID,
+			CheckoutSHA: testCommitID,
+			ProjectID:   15,
+		}
+		event.Project.PathWithNamespace = "group/repo"
+		event.Project.GitHTTPURL = "https://gitlab.example.com/group/repo.git"
+		return event
+	}
+
+	t.Run("missing settings", func(t *testing.T) {
+		_, err := NewService(&Config{WebhookSecret: testWebhookSecret})
+		assert.ErrorIs(t, err, ErrMissingToken)
+
+		_, err = NewService(&Config{Credentials: credentials.Static(testToken)})
+		assert.ErrorIs(t, err, ErrMissingWebhookSecret)
+	})
+
+	t.Run("verified push", func(t *testing.T) {
+		service, api := newService(t)
+		verifyErr = nil
+		verifiedRefs = []string{}
+
+		assert.Nil(t, service.HandlePush(context.Background(), newPushEvent("refs/heads/feature")))
+		assert.Equal(t, []string{"refs/heads/feature"}, verifiedRefs)
+		assert.Equal(t, []string{stateRunning, stateSuccess}, api.states())
+		assert.Equal(t, StatusName, api.statuses[1].Name)
+		assert.Equal(t, "feature", api.statuses[1].Ref)
+		assert.Empty(t, api.notes)
+	})
+
+	t.Run("unverified push", func(t *testing.T) {
+		service, api := newService(t)
+		verifyErr = errors.New("verifying Git namespace policies failed, unauthorized signature")
+
+		assert.Nil(t, service.HandlePush(context.Background(), newPushEvent("refs/heads/feature")))
+		assert.Equal(t, []string{stateRunning, stateFailed}, api.states())
+		assert.Len(t, api.notes[3], 1)
+		assert.Len(t, api.notes[4], 1)
+		assert.Contains(t, api.notes[3][0], "unauthorized signature")
+	})
+
+	t.Run("unverified push without merge requests", func(t *testing.T) {
+		service, api := newService(t)
+		verifyErr = errors.New(strings.Repeat("unauthorized signature ", 20))
+
+		assert.Nil(t, service.HandlePush(context.Background(), newPushEvent("refs/heads/main")))
+		assert.Equal(t, []string{stateRunning, stateFailed}, api.states())
+		assert.Len(t, api.statuses[1].Description, maxDescriptionLength)
+		assert.Empty(t, api.notes)
+	})
+
+	t.Run("ignored pushes", func(t *testing.T) {
+		service, api := newService(t)
+		verifiedRefs = []string{}
+
+		assert.Nil(t, service.HandlePush(context.Background(), newPushEvent("refs/gittuf/reference-state-log")))
+
+		deletion := newPushEvent("refs/heads/feature")
+		deletion.After = plumbing.ZeroHash.String()
+		assert.Nil(t, service.HandlePush(context.Background(), deletion))
+
+		assert.Empty(t, verifiedRefs)
+		assert.Empty(t, api.states())
+	})
+
+	t.Run("webhook deliveries", func(t *testing.T) {
+		service, api := newService(t)
+		verifyErr = nil
+
+		payload, err := json.Marshal(newPushEvent("refs/heads/main"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		deliver := func(eventType string, payload []byte, secret string) int {
+			request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+			request.Header.Set(eventHeader, eventType)
+			request.Header.Set(tokenHeader, secret)
+
+			recorder := httptest.NewRecorder()
+			service.ServeHTTP(recorder, request)
+			return recorder.Code
+		}
+
+		assert.Equal(t, http.StatusUnauthorized, deliver(pushHookEvent, payload, "wrong-secret"))
+		assert.Equal(t, http.StatusNoContent, deliver("Issue Hook", []byte("{}"), testWebhookSecret))
+		assert.Equal(t, http.StatusBadRequest, deliver(pushHookEvent, []byte("not json"), testWebhookSecret))
+
+		assert.Equal(t, http.StatusAccepted, deliver(pushHookEvent, payload, testWebhookSecret))
+		service.Wait()
+		assert.Equal(t, []string{stateRunning, stateSuccess}, api.states())
+	})
+}
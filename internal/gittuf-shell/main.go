@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/gittuf/gittuf/internal/i18n"
+	"github.com/gittuf/gittuf/internal/sshshell"
+)
+
+// gittuf-shell is set as the forced command of the SSH keys allowed to access
+// the repositories on a Git server, e.g. in authorized_keys:
+//
+//	command="gittuf-shell --root /srv/git",restrict ssh-ed25519 AAAA...
+//
+// The command requested by the client is read from SSH_ORIGINAL_COMMAND.
+// Pushes are verified using gittuf before git-receive-pack updates any ref.
+func main() {
+	root := flag.String("root", ".", "directory the repositories are served from")
+	gittufPath := flag.String("gittuf", "gittuf", "gittuf binary used to verify pushes")
+	flag.Parse()
+
+	path, err := exec.LookPath(*gittufPath)
+	if err == nil {
+		err = sshshell.New(*root, path).Run(context.Background(), os.Getenv("SSH_ORIGINAL_COMMAND"), os.Stdin, os.Stdout, os.Stderr)
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+
+		fmt.Fprintf(os.Stderr, "%s %s\n", i18n.T("Error:"), i18n.TranslateError(err))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package i18n
+
+// deMessages contains the German translations of gittuf's messages.
+var deMessages = map[string]string{
+	"Error:": "Fehler:",
+	"ok":     "ok",
+	"fail":   "Fehler",
+	"No RSL entries failed verification, the error below was encountered elsewhere in the verification workflow": "Kein RSL-Eintrag ist bei der Prüfung durchgefallen, der folgende Fehler trat an anderer Stelle der Prüfung auf",
+	"RSL entry %s for %s (target %s) failed verification\n":                                                      "RSL-Eintrag %s für %s (Ziel %s) hat die Prüfung nicht bestanden\n",
+	"  Error: %s\n":                               "  Fehler: %s\n",
+	"\n  %s Check for %s\n":                       "\n  %s Prüfung für %s\n",
+	"    Object:                  %s\n":           "    Objekt:                %s\n",
+	"    Signature:               %s\n":           "    Signatur:              %s\n",
+	"    Principals:              %s\n":           "    Prinzipale:            %s\n",
+	"    Reference authorization: not found":      "    Referenzautorisierung: nicht gefunden",
+	"    Reference authorization: signed by %s\n": "    Referenzautorisierung: signiert von %s\n",
+	"    No rules protect this namespace":         "    Keine Regel schützt diesen Namensraum",
+	"    Rule %s\n":                               "    Regel %s\n",
+	"      Threshold:     %d\n":                   "      Schwellenwert:               %d\n",
+	"      Trusted keys:":                         "      Vertrauenswürdige Schlüssel:",
+	"      Result:        conditions met":         "      Ergebnis:                    Bedingungen erfüllt",
+	"      Result:        rejected, %s\n":         "      Ergebnis:                    abgelehnt, %s\n",
+	"Repository is a shallow clone, %s was verified after RSL entry %s (target %s)\n": "Repository ist ein flacher Klon, %s wurde nach RSL-Eintrag %s (Ziel %s) geprüft\n",
+	"%s %s was built from %s\n": "%s %s wurde aus %s gebaut\n",
+}
+
+// deErrors contains the German translations of gittuf's error messages.
+var deErrors = map[string]string{
+	// Verification errors
+	"unauthorized signature":                                                "nicht autorisierte Signatur",
+	"invalid entry found not marked as skipped":                             "ungültiger Eintrag gefunden, der nicht als übersprungen markiert ist",
+	"entry expected to be unskipped is marked as skipped":                   "Eintrag, der nicht übersprungen sein sollte, ist als übersprungen markiert",
+	"unknown object type passed to verify signature":                        "unbekannter Objekttyp zur Signaturprüfung übergeben",
+	"verifier has invalid parameters (is threshold 0?)":                     "Prüfer hat ungültige Parameter (ist der Schwellenwert 0?)",
+	"verifier's key and threshold constraints not met":                      "Schlüssel- und Schwellenwertanforderungen des Prüfers nicht erfüllt",
+	"required hook was not executed successfully":                           "erforderlicher Hook wurde nicht erfolgreich ausgeführt",
+	"required artifact was not reproduced by enough rebuilders":             "erforderliches Artefakt wurde nicht von genügend Rebuildern reproduziert",
+	"release artifact does not match any artifact attested for the release": "Release-Artefakt entspricht keinem für das Release attestierten Artefakt",
+	"required rule evaluator did not allow the change":                      "erforderlicher Regelauswerter hat die Änderung nicht zugelassen",
+	"attestation's predicate is invalid":                                    "Prädikat der Attestierung ist ungültig",
+	"attestation was not logged to Rekor":                                   "Attestierung wurde nicht in Rekor protokolliert",
+	"no signature found":                                                    "keine Signatur gefunden",
+	"Git object was not signed by any key in the policy":                    "Git-Objekt wurde mit keinem Schlüssel der Richtlinie signiert",
+	"identity can be confused with other identities":                        "Identität kann mit anderen Identitäten verwechselt werden",
+	"verifying Git namespace policies failed":                               "Prüfung der Richtlinien für den Git-Namensraum fehlgeschlagen",
+	"verifying file namespace policies failed":                              "Prüfung der Richtlinien für den Datei-Namensraum fehlgeschlagen",
+	"verifying RSL entry failed":                                            "Prüfung des RSL-Eintrags fehlgeschlagen",
+	"tag reference set to unexpected target":                                "Tag-Referenz zeigt auf ein unerwartetes Ziel",
+	"verifying tag object's signature failed":                               "Prüfung der Signatur des Tag-Objekts fehlgeschlagen",
+	"no attestation found for hook":                                         "keine Attestierung gefunden für Hook",
+	"no rebuild attestations found for artifact":                            "keine Rebuild-Attestierungen gefunden für Artefakt",
+	"no Git object or reference authorization to verify":                    "kein Git-Objekt und keine Referenzautorisierung zu prüfen",
+	"Git signature was not issued by any of the rule's keys":                "Git-Signatur wurde von keinem der Schlüssel der Regel ausgestellt",
+	"Git reference's current state does not match latest RSL entry":         "aktueller Zustand der Git-Referenz stimmt nicht mit dem neuesten RSL-Eintrag überein",
+	"Git replaces objects in the repository using replace refs or grafts, the history Git shows may not be the verified history": "Git ersetzt Objekte im Repository mithilfe von Replace-Refs oder Grafts, der von Git angezeigte Verlauf ist möglicherweise nicht der verifizierte Verlauf",
+	"object is corrupt": "Objekt ist beschädigt",
+	"no RSL entry for the reference can be verified using the history available in the shallow clone, fetch more history using 'git fetch --deepen'": "kein RSL-Eintrag für die Referenz kann mit der im flachen Klon verfügbaren Historie geprüft werden, weitere Historie mit 'git fetch --deepen' abrufen",
+
+	// Policy errors
+	"unable to find requested metadata file; has it been initialized?":     "angeforderte Metadatendatei nicht gefunden; wurde sie initialisiert?",
+	"invalid policy tree structure":                                        "ungültige Struktur des Richtlinienbaums",
+	"unreachable targets metadata found":                                   "unerreichbare Targets-Metadaten gefunden",
+	"RSL entry expected, annotation found instead":                         "RSL-Eintrag erwartet, stattdessen Annotation gefunden",
+	"required delegation entry not found":                                  "erforderlicher Delegationseintrag nicht gefunden",
+	"cannot find policy":                                                   "Richtlinie nicht gefunden",
+	"two rules with same name found in policy":                             "zwei Regeln mit gleichem Namen in der Richtlinie gefunden",
+	"unable to match root public keys, gittuf policy is in a broken state": "Root-Schlüssel stimmen nicht überein, die gittuf-Richtlinie ist in einem defekten Zustand",
+	"policy change is not accompanied by a justification":                  "Richtlinienänderung ist nicht begründet",
+	"unable to verify roots of trust for policy states":                    "Vertrauensanker der Richtlinienzustände konnten nicht geprüft werden",
+	"requested state has invalidly signed metadata":                        "angeforderter Zustand hat ungültig signierte Metadaten",
+	"staged policy is invalid":                                             "vorgemerkte Richtlinie ist ungültig",
+	"unauthorized key presented when updating gittuf metadata":             "nicht autorisierter Schlüssel beim Aktualisieren der gittuf-Metadaten verwendet",
+	"unsupported compression algorithm":                                    "nicht unterstützter Komprimierungsalgorithmus",
+
+	// RSL errors
+	"unable to find RSL entry":                                      "RSL-Eintrag nicht gefunden",
+	"potential RSL branch detected, entry has more than one parent": "mögliche Verzweigung des RSL erkannt, Eintrag hat mehr als einen Vorgänger",
+	"RSL entry has invalid format or is of unexpected type":         "RSL-Eintrag hat ein ungültiges Format oder einen unerwarteten Typ",
+	"RSL entry does not match requested ref":                        "RSL-Eintrag passt nicht zur angeforderten Referenz",
+	"commit has not been encountered before":                        "Commit wurde zuvor nicht gesehen",
+
+	// Signing errors
+	"signing key not specified in git config":                                        "Signaturschlüssel nicht in der Git-Konfiguration angegeben",
+	"unable to read signing key specified in git config":                             "in der Git-Konfiguration angegebener Signaturschlüssel kann nicht gelesen werden",
+	"unknown signing method (not one of gpg, ssh, x509)":                             "unbekanntes Signaturverfahren (nicht gpg, ssh oder x509)",
+	"unable to sign Git object":                                                      "Git-Objekt kann nicht signiert werden",
+	"incorrect key provided to verify signature":                                     "falscher Schlüssel zur Signaturprüfung angegeben",
+	"unable to verify Sigstore signature":                                            "Sigstore-Signatur kann nicht geprüft werden",
+	"unable to verify SSH signature":                                                 "SSH-Signatur kann nicht geprüft werden",
+	"SSH key is not an allowed signer at the time of the signature":                  "SSH-Schlüssel ist zum Zeitpunkt der Signatur kein erlaubter Signierer",
+	"unable to parse signature / signature has unexpected header":                    "Signatur kann nicht gelesen werden / Signatur hat unerwarteten Header",
+	"operation requires user interaction, which is disabled in non-interactive mode": "Vorgang erfordert Benutzereingaben, die im nicht-interaktiven Modus deaktiviert sind",
+
+	// Logging errors
+	"invalid log level":  "ungültige Protokollstufe",
+	"invalid log format": "ungültiges Protokollformat",
+	"invalid subsystem log level, expected <subsystem>=<level>": "ungültige Protokollstufe für Subsystem, erwartet <Subsystem>=<Stufe>",
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// LocaleKey is the environment variable that selects the locale of
+	// gittuf's messages. It takes precedence over LC_ALL, LC_MESSAGES, and
+	// LANG.
+	LocaleKey = "GITTUF_LANG"
+
+	// DefaultLocale is the locale of gittuf's messages as written in the
+	// source code.
+	DefaultLocale = "en"
+)
+
+// catalog contains the translations of gittuf's messages for a locale.
+// Messages are identified by their English text, so that messages without a
+// translation are shown in English.
+type catalog struct {
+	// messages contains the translations of messages and format strings
+	// passed to T and Sprintf. Format strings must be translated keeping
+	// their verbs in the same order.
+	messages map[string]string
+
+	// errors contains the translations of error messages, which are replaced
+	// wherever they're found in the text of errors passed to
+	// TranslateError.
+	errors map[string]string
+}
+
+// catalogs maps each supported locale other than DefaultLocale to its
+// translations.
+var catalogs = map[string]*catalog{
+	"de": {messages: deMessages, errors: deErrors},
+}
+
+var localeOverride = ""
+
+// SetLocale selects the locale of gittuf's messages, overriding the locale
+// set in the environment. An empty locale restores the use of the
+// environment.
+func SetLocale(locale string) {
+	localeOverride = locale
+}
+
+// Locale returns the supported locale that gittuf's messages are shown in. The
+// locale is selected using SetLocale, or using the environment variables
+// GITTUF_LANG, LC_ALL, LC_MESSAGES, and LANG, in that order. DefaultLocale is
+// returned if the selected locale isn't supported.
+func Locale() string {
+	candidates := []string{localeOverride, os.Getenv(LocaleKey), os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+
+		// The first locale set is used even if it isn't supported, similar
+		// to how the C library picks the locale
+		locale := normalize(candidate)
+		if _, has := catalogs[locale]; has {
+			return locale
+		}
+		return DefaultLocale
+	}
+
+	return DefaultLocale
+}
+
+// SupportedLocales returns the locales gittuf's messages can be shown in,
+// sorted by name.
+func SupportedLocales() []string {
+	locales := []string{DefaultLocale}
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+// T returns the translation of the message in the current locale, or the
+// message as is if it hasn't been translated.
+func T(message string) string {
+	catalog, has := catalogs[Locale()]
+	if !has {
+		return message
+	}
+
+	if translation, has := catalog.messages[message]; has {
+		return translation
+	}
+
+	return message
+}
+
+// Sprintf formats the translation of the format string in the current locale.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// TranslateError returns the text of the error in the current locale.
+func TranslateError(err error) string {
+	return TranslateErrorText(err.Error())
+}
+
+// TranslateErrorText returns the text of an error in the current locale. As
+// errors are often wrapped, each translated error message found in the text is
+// replaced with its translation, longest messages first.
+func TranslateErrorText(text string) string {
+	catalog, has := catalogs[Locale()]
+	if !has {
+		return text
+	}
+
+	messages := make([]string, 0, len(catalog.errors))
+	for message := range catalog.errors {
+		if strings.Contains(text, message) {
+			messages = append(messages, message)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return len(messages[i]) > len(messages[j])
+	})
+
+	// Replace all messages in a single pass, so that translations aren't
+	// translated again
+	replacements := make([]string, 0, 2*len(messages))
+	for _, message := range messages {
+		replacements = append(replacements, message, catalog.errors[message])
+	}
+
+	return strings.NewReplacer(replacements...).Replace(text)
+}
+
+// normalize returns the language of a POSIX locale such as "de_DE.UTF-8".
+func normalize(locale string) string {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, "_-.@"); i >= 0 {
+		locale = locale[:i]
+	}
+
+	if locale == "c" || locale == "posix" {
+		return DefaultLocale
+	}
+
+	return locale
+}
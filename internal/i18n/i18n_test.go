@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocale(t *testing.T) {
+	tests := map[string]struct {
+		env            map[string]string
+		override       string
+		expectedLocale string
+	}{
+		"no locale set": {
+			expectedLocale: DefaultLocale,
+		},
+		"LANG": {
+			env:            map[string]string{"LANG": "de_DE.UTF-8"},
+			expectedLocale: "de",
+		},
+		"LC_ALL takes precedence over LANG": {
+			env:            map[string]string{"LC_ALL": "en_US.UTF-8", "LANG": "de_DE.UTF-8"},
+			expectedLocale: DefaultLocale,
+		},
+		"GITTUF_LANG takes precedence": {
+			env:            map[string]string{LocaleKey: "de", "LC_ALL": "C"},
+			expectedLocale: "de",
+		},
+		"override takes precedence": {
+			env:            map[string]string{LocaleKey: "de"},
+			override:       "en",
+			expectedLocale: DefaultLocale,
+		},
+		"unsupported locale": {
+			env:            map[string]string{"LANG": "fr_FR.UTF-8"},
+			expectedLocale: DefaultLocale,
+		},
+		"POSIX locale": {
+			env:            map[string]string{"LC_MESSAGES": "POSIX"},
+			expectedLocale: DefaultLocale,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, key := range []string{LocaleKey, "LC_ALL", "LC_MESSAGES", "LANG"} {
+				t.Setenv(key, test.env[key])
+			}
+			SetLocale(test.override)
+			defer SetLocale("")
+
+			assert.Equal(t, test.expectedLocale, Locale())
+		})
+	}
+}
+
+func TestSupportedLocales(t *testing.T) {
+	assert.Equal(t, []string{"de", "en"}, SupportedLocales())
+}
+
+func TestTranslate(t *testing.T) {
+	defer SetLocale("")
+
+	t.Run("default locale", func(t *testing.T) {
+		SetLocale(DefaultLocale)
+
+		assert.Equal(t, "Error:", T("Error:"))
+		assert.Equal(t, "    Rule protect-main\n", Sprintf("    Rule %s\n", "protect-main"))
+
+		err := fmt.Errorf("verifying Git namespace policies failed, %w", errors.New("unauthorized signature"))
+		assert.Equal(t, err.Error(), TranslateError(err))
+	})
+
+	t.Run("translated locale", func(t *testing.T) {
+		SetLocale("de")
+
+		assert.Equal(t, "Fehler:", T("Error:"))
+		assert.Equal(t, "    Regel protect-main\n", Sprintf("    Rule %s\n", "protect-main"))
+		assert.Equal(t, "not translated", T("not translated"))
+
+		err := fmt.Errorf("verifying Git namespace policies failed, %w", errors.New("unauthorized signature"))
+		assert.Equal(t, "Prüfung der Richtlinien für den Git-Namensraum fehlgeschlagen, nicht autorisierte Signatur", TranslateError(err))
+
+		// Longer messages are translated before the messages they contain
+		err = errors.New("unable to find RSL entry: cannot find policy")
+		assert.Equal(t, "RSL-Eintrag nicht gefunden: Richtlinie nicht gefunden", TranslateError(err))
+	})
+}
+
+func TestCatalogs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+
+	for locale, catalog := range catalogs {
+		for message, translation := range catalog.messages {
+			assert.Equal(t, verbs.FindAllString(message, -1), verbs.FindAllString(translation, -1), "verbs of translation of '%s' to '%s' don't match", message, locale)
+		}
+
+		for message := range catalog.errors {
+			assert.NotContains(t, message, "%", "error message '%s' translated to '%s' must not be a format string", message, locale)
+		}
+	}
+}
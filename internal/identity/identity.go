@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package identity normalizes the identities used in gittuf policies, such as
+// email addresses, Sigstore subjects, and ref names, and detects identities
+// that can be confused with others. A policy that trusts a look-alike of a
+// developer's identity, such as one using a Cyrillic "а" instead of a Latin
+// "a", can be approved by reviewers who believe it trusts the developer.
+package identity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var ErrConfusableIdentity = errors.New("identity can be confused with other identities")
+
+// confusables maps Greek and Cyrillic letters to the Latin letters they are
+// visually indistinguishable from in common fonts. Fullwidth and other
+// compatibility forms are handled by NFKC normalization instead.
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'с': 'c', 'ԁ': 'd', 'е': 'e', 'һ': 'h', 'і': 'i',
+	'ј': 'j', 'к': 'k', 'ӏ': 'l', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'ԛ': 'q', 'ѕ': 's', 'т': 't', 'у': 'y', 'ԝ': 'w', 'х': 'x', 'ү': 'y',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'μ': 'u',
+	'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x', 'γ': 'y',
+	'ζ': 'z',
+}
+
+// Normalize returns the NFC normalized form of the identity, so that
+// identities that are canonically equivalent are represented identically.
+func Normalize(identity string) string {
+	return norm.NFC.String(identity)
+}
+
+// Skeleton returns the form of the identity used to detect look-alikes: two
+// identities with the same skeleton can be confused with each other. The
+// skeleton is the NFKC normalized, lowercase form of the identity with
+// confusable letters replaced by the Latin letters they resemble.
+func Skeleton(identity string) string {
+	skeleton := strings.Builder{}
+	for _, r := range strings.ToLower(norm.NFKC.String(identity)) {
+		if latin, has := confusables[r]; has {
+			r = latin
+		}
+		skeleton.WriteRune(r)
+	}
+
+	return skeleton.String()
+}
+
+// Confusable returns true if the identities are distinct but can be confused
+// with each other.
+func Confusable(identity, other string) bool {
+	return Normalize(identity) != Normalize(other) && Skeleton(identity) == Skeleton(other)
+}
+
+// Check returns an error wrapping ErrConfusableIdentity if the identity
+// contains invisible formatting characters, such as zero width spaces and
+// bidirectional overrides, if a word in the identity mixes Latin, Greek, and
+// Cyrillic letters, or if a Greek or Cyrillic word consists entirely of
+// letters that resemble Latin letters.
+func Check(identity string) error {
+	for _, r := range identity {
+		if unicode.Is(unicode.Cf, r) {
+			return fmt.Errorf("%w: '%s' contains the invisible character %U", ErrConfusableIdentity, identity, r)
+		}
+	}
+
+	words := strings.FieldsFunc(Normalize(identity), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r)
+	})
+	for _, word := range words {
+		hasLatin, hasGreek, hasCyrillic := false, false, false
+		allConfusable := true
+		for _, r := range word {
+			switch {
+			case unicode.Is(unicode.Latin, r):
+				hasLatin = true
+			case unicode.Is(unicode.Greek, r):
+				hasGreek = true
+			case unicode.Is(unicode.Cyrillic, r):
+				hasCyrillic = true
+			}
+
+			if _, has := confusables[unicode.ToLower(r)]; !has && !unicode.IsMark(r) {
+				allConfusable = false
+			}
+		}
+
+		switch {
+		case (hasLatin && (hasGreek || hasCyrillic)) || (hasGreek && hasCyrillic):
+			return fmt.Errorf("%w: '%s' mixes letters from different scripts in '%s'", ErrConfusableIdentity, identity, word)
+		case (hasGreek || hasCyrillic) && allConfusable:
+			return fmt.Errorf("%w: '%s' resembles '%s'", ErrConfusableIdentity, identity, Skeleton(identity))
+		}
+	}
+
+	return nil
+}
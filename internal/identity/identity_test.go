@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	// "é" as a single code point and as "e" followed by a combining accent
+	assert.Equal(t, "jos\u00e9@example.com", Normalize("jose\u0301@example.com"))
+	assert.Equal(t, "alice@example.com", Normalize("alice@example.com"))
+}
+
+func TestConfusable(t *testing.T) {
+	tests := map[string]struct {
+		identity   string
+		other      string
+		confusable bool
+	}{
+		"same identity": {
+			identity: "alice@example.com",
+			other:    "alice@example.com",
+		},
+		"canonically equivalent identities": {
+			identity: "jose\u0301@example.com",
+			other:    "jos\u00e9@example.com",
+		},
+		"different identities": {
+			identity: "alice@example.com",
+			other:    "bob@example.com",
+		},
+		"cyrillic look-alike": {
+			identity:   "аlice@example.com",
+			other:      "alice@example.com",
+			confusable: true,
+		},
+		"fullwidth look-alike": {
+			identity:   "ａlice@example.com",
+			other:      "alice@example.com",
+			confusable: true,
+		},
+		"different case": {
+			identity:   "Alice@example.com",
+			other:      "alice@example.com",
+			confusable: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.confusable, Confusable(test.identity, test.other))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := map[string]struct {
+		identity string
+		valid    bool
+	}{
+		"ascii email": {
+			identity: "alice@example.com",
+			valid:    true,
+		},
+		"accented email": {
+			identity: "josé@example.com",
+			valid:    true,
+		},
+		"cyrillic email": {
+			identity: "иван@example.com",
+			valid:    true,
+		},
+		"ref name": {
+			identity: "git:refs/heads/main",
+			valid:    true,
+		},
+		"mixed scripts": {
+			identity: "аlice@example.com",
+		},
+		"mixed scripts in ref name": {
+			identity: "git:refs/heads/maіn",
+		},
+		"whole script confusable": {
+			identity: "аррӏе@example.com",
+		},
+		"zero width space": {
+			identity: "alice\u200b@example.com",
+		},
+		"bidirectional override": {
+			identity: "alice@\u202eexample.com",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Check(test.identity)
+			if test.valid {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrConfusableIdentity)
+			}
+		})
+	}
+}
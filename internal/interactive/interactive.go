@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package interactive
+
+import (
+	"errors"
+	"os"
+)
+
+const (
+	// NonInteractiveModeKey is the environment variable that enables
+	// non-interactive mode when set to 1, which is convenient in CI.
+	NonInteractiveModeKey = "GITTUF_NON_INTERACTIVE"
+
+	// ExitCode is the exit code of gittuf when an operation fails because it
+	// requires user interaction in non-interactive mode.
+	ExitCode = 3
+)
+
+var ErrInteractionRequired = errors.New("operation requires user interaction, which is disabled in non-interactive mode")
+
+var nonInteractive = false
+
+// SetNonInteractive enables or disables non-interactive mode. In
+// non-interactive mode, gittuf never prompts the user, and operations that
+// would prompt fail with ErrInteractionRequired instead.
+func SetNonInteractive(enabled bool) {
+	nonInteractive = enabled
+}
+
+// InNonInteractiveMode returns true if gittuf is currently in non-interactive
+// mode, either because it was enabled using SetNonInteractive or using the
+// environment.
+func InNonInteractiveMode() bool {
+	return nonInteractive || os.Getenv(NonInteractiveModeKey) == "1"
+}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package interactive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInNonInteractiveMode(t *testing.T) {
+	t.Cleanup(func() { SetNonInteractive(false) })
+	t.Setenv(NonInteractiveModeKey, "")
+
+	assert.False(t, InNonInteractiveMode())
+
+	SetNonInteractive(true)
+	assert.True(t, InNonInteractiveMode())
+
+	SetNonInteractive(false)
+	t.Setenv(NonInteractiveModeKey, "1")
+	assert.True(t, InNonInteractiveMode())
+}
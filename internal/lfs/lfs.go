@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package lfs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	// PointerVersion is the version of the pointer file format written by Git
+	// LFS.
+	PointerVersion = "https://git-lfs.github.com/spec/v1"
+
+	// legacyPointerVersion is the version written by pre-release versions of
+	// Git LFS, which Git LFS continues to accept.
+	legacyPointerVersion = "https://hawser.github.com/spec/v1"
+
+	// maxPointerSize is the maximum size of a pointer file. Larger files are
+	// not parsed.
+	maxPointerSize = 1024
+
+	oidPrefix = "sha256:"
+)
+
+var (
+	ErrNotPointer     = errors.New("file is not a Git LFS pointer")
+	ErrObjectNotFound = errors.New("Git LFS object not found")                  //nolint:stylecheck
+	ErrObjectMismatch = errors.New("Git LFS object does not match its pointer") //nolint:stylecheck
+)
+
+// Pointer identifies a Git LFS object referenced by a file tracked in the
+// repository.
+type Pointer struct {
+	// Path is the path of the pointer file in the repository's tree.
+	Path string `json:"path"`
+
+	// OID is the hex encoded SHA-256 digest of the object's contents.
+	OID string `json:"oid"`
+
+	// Size is the size of the object in bytes.
+	Size int64 `json:"size"`
+}
+
+// ParsePointer parses the contents of a Git LFS pointer file. ErrNotPointer is
+// returned if the contents are not a valid pointer.
+func ParsePointer(contents []byte) (*Pointer, error) {
+	if len(contents) > maxPointerSize || !bytes.HasPrefix(contents, []byte("version ")) {
+		return nil, ErrNotPointer
+	}
+
+	values := map[string]string{}
+	for i, line := range strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n") {
+		key, value, found := strings.Cut(line, " ")
+		if !found || key == "" {
+			return nil, ErrNotPointer
+		}
+		if _, has := values[key]; has {
+			return nil, ErrNotPointer
+		}
+		if i == 0 && key != "version" {
+			return nil, ErrNotPointer
+		}
+
+		values[key] = value
+	}
+
+	if version := values["version"]; version != PointerVersion && version != legacyPointerVersion {
+		return nil, ErrNotPointer
+	}
+
+	oid, found := strings.CutPrefix(values["oid"], oidPrefix)
+	if !found || len(oid) != sha256.Size*2 {
+		return nil, ErrNotPointer
+	}
+	if _, err := hex.DecodeString(oid); err != nil {
+		return nil, ErrNotPointer
+	}
+
+	size, err := strconv.ParseInt(values["size"], 10, 64)
+	if err != nil || size < 0 {
+		return nil, ErrNotPointer
+	}
+
+	return &Pointer{OID: oid, Size: size}, nil
+}
+
+// FindPointers returns the Git LFS pointers in the tree, sorted by path.
+func FindPointers(repo *git.Repository, treeID plumbing.Hash) ([]*Pointer, error) {
+	tree, err := repo.TreeObject(treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	pointers := []*Pointer{}
+	err = tree.Files().ForEach(func(file *object.File) error {
+		if file.Size > maxPointerSize || !file.Mode.IsFile() {
+			return nil
+		}
+
+		contents, err := file.Contents()
+		if err != nil {
+			return err
+		}
+
+		pointer, err := ParsePointer([]byte(contents))
+		if err != nil {
+			if errors.Is(err, ErrNotPointer) {
+				return nil
+			}
+			return err
+		}
+
+		pointer.Path = file.Name
+		pointers = append(pointers, pointer)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pointers, func(i, j int) bool {
+		return pointers[i].Path < pointers[j].Path
+	})
+
+	return pointers, nil
+}
+
+// ObjectPath returns the path of the object in the Git LFS storage directory,
+// typically the "lfs" directory in the repository's GIT_DIR.
+func ObjectPath(storageDir, oid string) string {
+	return filepath.Join(storageDir, "objects", oid[0:2], oid[2:4], oid)
+}
+
+// VerifyObjects checks that the objects referenced by the pointers exist in
+// the Git LFS storage directory, and that their contents match the digest and
+// size recorded in the pointers.
+func VerifyObjects(storageDir string, pointers []*Pointer) error {
+	for _, pointer := range pointers {
+		if err := verifyObject(storageDir, pointer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func verifyObject(storageDir string, pointer *Pointer) error {
+	object, err := os.Open(ObjectPath(storageDir, pointer.OID))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: '%s' (%s)", ErrObjectNotFound, pointer.Path, pointer.OID)
+		}
+		return err
+	}
+	defer object.Close() //nolint:errcheck
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, object)
+	if err != nil {
+		return err
+	}
+
+	if size != pointer.Size || hex.EncodeToString(hash.Sum(nil)) != pointer.OID {
+		return fmt.Errorf("%w: '%s' (%s)", ErrObjectMismatch, pointer.Path, pointer.OID)
+	}
+
+	return nil
+}
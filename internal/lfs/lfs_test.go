@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package lfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testContents = "large file"
+	testOID      = "f43b1d9eb5d6a3f0bf0a6ca3e32c0c3a2fd6b6dd45e78ea4e1f3dbcb47db0a3d"
+)
+
+func TestParsePointer(t *testing.T) {
+	tests := map[string]struct {
+		contents        string
+		expectedPointer *Pointer
+		expectedError   error
+	}{
+		"pointer": {
+			contents:        fmt.Sprintf("version %s\noid sha256:%s\nsize 10\n", PointerVersion, testOID),
+			expectedPointer: &Pointer{OID: testOID, Size: 10},
+		},
+		"pointer with extension": {
+			contents:        fmt.Sprintf("version %s\next-0-foo sha256:%s\noid sha256:%s\nsize 10\n", PointerVersion, testOID, testOID),
+			expectedPointer: &Pointer{OID: testOID, Size: 10},
+		},
+		"legacy pointer": {
+			contents:        fmt.Sprintf("version %s\noid sha256:%s\nsize 10\n", legacyPointerVersion, testOID),
+			expectedPointer: &Pointer{OID: testOID, Size: 10},
+		},
+		"regular file": {
+			contents:      "gittuf",
+			expectedError: ErrNotPointer,
+		},
+		"unknown version": {
+			contents:      fmt.Sprintf("version https://example.com/spec/v2\noid sha256:%s\nsize 10\n", testOID),
+			expectedError: ErrNotPointer,
+		},
+		"invalid oid": {
+			contents:      fmt.Sprintf("version %s\noid sha256:abcd\nsize 10\n", PointerVersion),
+			expectedError: ErrNotPointer,
+		},
+		"unsupported hash algorithm": {
+			contents:      fmt.Sprintf("version %s\noid sha512:%s\nsize 10\n", PointerVersion, testOID),
+			expectedError: ErrNotPointer,
+		},
+		"missing size": {
+			contents:      fmt.Sprintf("version %s\noid sha256:%s\n", PointerVersion, testOID),
+			expectedError: ErrNotPointer,
+		},
+		"duplicate key": {
+			contents:      fmt.Sprintf("version %s\noid sha256:%s\noid sha256:%s\nsize 10\n", PointerVersion, testOID, testOID),
+			expectedError: ErrNotPointer,
+		},
+	}
+
+	for name, test := range tests {
+		pointer, err := ParsePointer([]byte(test.contents))
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, fmt.Sprintf("unexpected error in test '%s'", name))
+			continue
+		}
+
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		assert.Equal(t, test.expectedPointer, pointer, fmt.Sprintf("unexpected pointer in test '%s'", name))
+	}
+}
+
+func TestFindPointers(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pointerContents := fmt.Sprintf("version %s\noid sha256:%s\nsize 10\n", PointerVersion, testOID)
+	pointerID, err := gitinterface.WriteBlob(repo, []byte(pointerContents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	readmeID, err := gitinterface.WriteBlob(repo, []byte("gittuf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	treeBuilder := gitinterface.NewTreeBuilder(repo)
+	treeID, err := treeBuilder.WriteRootTreeFromBlobIDs(map[string]plumbing.Hash{
+		"README.md":        readmeID,
+		"assets/logo.png":  pointerID,
+		"assets/video.mp4": pointerID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pointers, err := FindPointers(repo, treeID)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Pointer{
+		{Path: "assets/logo.png", OID: testOID, Size: 10},
+		{Path: "assets/video.mp4", OID: testOID, Size: 10},
+	}, pointers)
+
+	emptyTreeID, err := gitinterface.WriteTree(repo, []object.TreeEntry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pointers, err = FindPointers(repo, emptyTreeID)
+	assert.Nil(t, err)
+	assert.Empty(t, pointers)
+}
+
+func TestVerifyObjects(t *testing.T) {
+	storageDir := t.TempDir()
+
+	writeObject := func(t *testing.T, oid, contents string) {
+		t.Helper()
+
+		objectPath := ObjectPath(storageDir, oid)
+		if err := os.MkdirAll(filepath.Dir(objectPath), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(objectPath, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	digest := sha256.Sum256([]byte(testContents))
+	oid := hex.EncodeToString(digest[:])
+	writeObject(t, oid, testContents)
+
+	// Stored at the location of testOID, but doesn't match it
+	writeObject(t, testOID, "tampered")
+
+	tests := map[string]struct {
+		pointers      []*Pointer
+		expectedError error
+	}{
+		"matching object": {
+			pointers: []*Pointer{{Path: "large.bin", OID: oid, Size: int64(len(testContents))}},
+		},
+		"no pointers": {
+			pointers: []*Pointer{},
+		},
+		"missing object": {
+			pointers:      []*Pointer{{Path: "missing.bin", OID: "0000000000000000000000000000000000000000000000000000000000000000", Size: 1}},
+			expectedError: ErrObjectNotFound,
+		},
+		"object with different contents": {
+			pointers:      []*Pointer{{Path: "tampered.bin", OID: testOID, Size: int64(len("tampered"))}},
+			expectedError: ErrObjectMismatch,
+		},
+		"object with different size": {
+			pointers:      []*Pointer{{Path: "large.bin", OID: oid, Size: 1}},
+			expectedError: ErrObjectMismatch,
+		},
+	}
+
+	for name, test := range tests {
+		err := VerifyObjects(storageDir, test.pointers)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		}
+	}
+}
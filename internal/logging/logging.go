@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package logging configures gittuf's structured logging, built on log/slog.
+// Subsystems, such as policy verification, log using the logger returned by
+// For, which records the subsystem with each message so that its verbosity can
+// be configured independently of the rest of gittuf.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+
+	// SubsystemKey is the attribute that records the subsystem that logged a
+	// message.
+	SubsystemKey = "subsystem"
+)
+
+var (
+	ErrInvalidLevel          = errors.New("invalid log level")
+	ErrInvalidFormat         = errors.New("invalid log format")
+	ErrInvalidSubsystemLevel = errors.New("invalid subsystem log level, expected <subsystem>=<level>")
+)
+
+// Options configures how messages are logged.
+type Options struct {
+	// Level is the minimum level of messages that are logged.
+	Level slog.Level
+
+	// Format is the format messages are written in, either FormatText or
+	// FormatJSON. FormatText is used if it is empty.
+	Format string
+
+	// SubsystemLevels overrides Level for the messages logged by specific
+	// subsystems.
+	SubsystemLevels map[string]slog.Level
+}
+
+type config struct {
+	handler         slog.Handler
+	level           slog.Level
+	subsystemLevels map[string]slog.Level
+}
+
+func (c *config) levelFor(subsystem string) slog.Level {
+	if level, has := c.subsystemLevels[subsystem]; has {
+		return level
+	}
+	return c.level
+}
+
+// current is the configuration set using Setup. Until Setup is called,
+// messages are passed to slog's default logger.
+var current atomic.Pointer[config]
+
+// Setup configures gittuf's logging, writing messages to w. It also replaces
+// slog's default logger, so messages logged using the top level functions of
+// slog honor the configuration.
+func Setup(w io.Writer, opts Options) error {
+	// The underlying handler must accept messages at the lowest level any
+	// subsystem is configured for, levels are checked per subsystem before
+	// messages are passed to it
+	minLevel := opts.Level
+	for _, level := range opts.SubsystemLevels {
+		if level < minLevel {
+			minLevel = level
+		}
+	}
+	handlerOptions := &slog.HandlerOptions{Level: minLevel}
+
+	var handler slog.Handler
+	switch opts.Format {
+	case "", FormatText:
+		handler = slog.NewTextHandler(w, handlerOptions)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, handlerOptions)
+	default:
+		return fmt.Errorf("%w: '%s'", ErrInvalidFormat, opts.Format)
+	}
+
+	current.Store(&config{
+		handler:         handler,
+		level:           opts.Level,
+		subsystemLevels: opts.SubsystemLevels,
+	})
+	slog.SetDefault(slog.New(&subsystemHandler{}))
+
+	return nil
+}
+
+// For returns the logger for the specified subsystem. The logger can be
+// created before Setup is called, such as in a package level variable.
+func For(subsystem string) *slog.Logger {
+	return slog.New(&subsystemHandler{subsystem: subsystem})
+}
+
+// ParseLevel returns the level with the specified name, one of debug, info,
+// warn, or error.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("%w: '%s'", ErrInvalidLevel, name)
+	}
+	return level, nil
+}
+
+// ParseSubsystemLevels parses values of the form <subsystem>=<level> into the
+// level set for each subsystem.
+func ParseSubsystemLevels(values []string) (map[string]slog.Level, error) {
+	levels := map[string]slog.Level{}
+	for _, value := range values {
+		subsystem, name, found := strings.Cut(value, "=")
+		if !found || subsystem == "" {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidSubsystemLevel, value)
+		}
+
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		levels[subsystem] = level
+	}
+
+	return levels, nil
+}
+
+// subsystemHandler filters messages using the level configured for its
+// subsystem. The underlying handler is looked up for each message, so that
+// loggers created before Setup is called honor the configuration.
+type subsystemHandler struct {
+	subsystem string
+
+	// transforms record the attributes and groups added to the handler, to
+	// be applied to the underlying handler in order.
+	transforms []func(slog.Handler) slog.Handler
+}
+
+func (h *subsystemHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	c := current.Load()
+	if c == nil {
+		return slog.Default().Handler().Enabled(ctx, level)
+	}
+	return level >= c.levelFor(h.subsystem)
+}
+
+func (h *subsystemHandler) Handle(ctx context.Context, record slog.Record) error {
+	var handler slog.Handler
+	if c := current.Load(); c != nil {
+		handler = c.handler
+	} else {
+		handler = slog.Default().Handler()
+	}
+
+	if h.subsystem != "" {
+		handler = handler.WithAttrs([]slog.Attr{slog.String(SubsystemKey, h.subsystem)})
+	}
+	for _, transform := range h.transforms {
+		handler = transform(handler)
+	}
+
+	return handler.Handle(ctx, record)
+}
+
+func (h *subsystemHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler {
+		return handler.WithAttrs(attrs)
+	})
+}
+
+func (h *subsystemHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler {
+		return handler.WithGroup(name)
+	})
+}
+
+func (h *subsystemHandler) with(transform func(slog.Handler) slog.Handler) *subsystemHandler {
+	transforms := make([]func(slog.Handler) slog.Handler, 0, len(h.transforms)+1)
+	transforms = append(transforms, h.transforms...)
+	transforms = append(transforms, transform)
+
+	return &subsystemHandler{subsystem: h.subsystem, transforms: transforms}
+}
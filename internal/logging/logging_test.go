@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() {
+		current.Store(nil)
+		slog.SetDefault(defaultLogger)
+	})
+
+	// Loggers created before Setup honor the configuration
+	logger := For("policy")
+
+	t.Run("text format", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := Setup(&buf, Options{Level: slog.LevelInfo})
+		assert.Nil(t, err)
+
+		logger.Debug("not logged")
+		logger.Info("verifying entry", "entry", "abc")
+		slog.Debug("not logged either")
+
+		output := buf.String()
+		assert.NotContains(t, output, "not logged")
+		assert.Contains(t, output, `msg="verifying entry" subsystem=policy entry=abc`)
+	})
+
+	t.Run("json format", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := Setup(&buf, Options{Level: slog.LevelInfo, Format: FormatJSON})
+		assert.Nil(t, err)
+
+		logger.With("ref", "refs/heads/main").Warn("verification failed")
+
+		record := map[string]any{}
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "WARN", record["level"])
+		assert.Equal(t, "verification failed", record["msg"])
+		assert.Equal(t, "policy", record[SubsystemKey])
+		assert.Equal(t, "refs/heads/main", record["ref"])
+	})
+
+	t.Run("subsystem levels", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := Setup(&buf, Options{
+			Level:           slog.LevelWarn,
+			SubsystemLevels: map[string]slog.Level{"policy": slog.LevelDebug},
+		})
+		assert.Nil(t, err)
+
+		logger.Debug("policy debug")
+		For("repository").Info("repository info")
+		slog.Info("default info")
+		slog.Warn("default warning")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if assert.Equal(t, 2, len(lines)) {
+			assert.Contains(t, lines[0], "policy debug")
+			assert.Contains(t, lines[1], "default warning")
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		err := Setup(&bytes.Buffer{}, Options{Format: "xml"})
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("debug")
+	assert.Nil(t, err)
+	assert.Equal(t, slog.LevelDebug, level)
+
+	level, err = ParseLevel("WARN")
+	assert.Nil(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+
+	_, err = ParseLevel("verbose")
+	assert.ErrorIs(t, err, ErrInvalidLevel)
+}
+
+func TestParseSubsystemLevels(t *testing.T) {
+	levels, err := ParseSubsystemLevels([]string{"policy=debug", "gitinterface=error"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]slog.Level{"policy": slog.LevelDebug, "gitinterface": slog.LevelError}, levels)
+
+	_, err = ParseSubsystemLevels([]string{"policy"})
+	assert.ErrorIs(t, err, ErrInvalidSubsystemLevel)
+
+	_, err = ParseSubsystemLevels([]string{"=debug"})
+	assert.ErrorIs(t, err, ErrInvalidSubsystemLevel)
+
+	_, err = ParseSubsystemLevels([]string{"policy=verbose"})
+	assert.ErrorIs(t, err, ErrInvalidLevel)
+}
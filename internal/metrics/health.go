@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// MetricsPath is the path metrics are served at.
+	MetricsPath = "/metrics"
+
+	// LivenessPath is the path of the liveness endpoint, which reports that the
+	// process is running.
+	LivenessPath = "/healthz"
+
+	// ReadinessPath is the path of the readiness endpoint, which reports
+	// whether the service is ready to handle requests.
+	ReadinessPath = "/readyz"
+)
+
+// ReadinessCheck returns an error if a service is not ready to handle
+// requests.
+type ReadinessCheck func(ctx context.Context) error
+
+// Health tracks the readiness of a service using the checks added to it. A
+// service with no checks is always ready.
+type Health struct {
+	checks map[string]ReadinessCheck
+	mu     sync.Mutex
+}
+
+// NewHealth returns a Health with no readiness checks.
+func NewHealth() *Health {
+	return &Health{checks: map[string]ReadinessCheck{}}
+}
+
+// AddReadinessCheck adds a named check that must pass for the service to be
+// ready.
+func (h *Health) AddReadinessCheck(name string, check ReadinessCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks[name] = check
+}
+
+// Ready runs the readiness checks, and returns an error describing the checks
+// that failed, if any.
+func (h *Health) Ready(ctx context.Context) error {
+	h.mu.Lock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	checks := make(map[string]ReadinessCheck, len(h.checks))
+	for name, check := range h.checks {
+		checks[name] = check
+	}
+	h.mu.Unlock()
+
+	sort.Strings(names)
+
+	failures := []string{}
+	for _, name := range names {
+		if err := checks[name](ctx); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, err.Error()))
+		}
+	}
+
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+// Handler returns a handler that serves the registry's metrics, and the
+// liveness and readiness endpoints using health, before passing all other
+// requests to next. These endpoints are served without the authentication
+// next may apply, so that they can be used by Prometheus and orchestrators.
+// If registry or health is nil, the corresponding endpoints are not served.
+func Handler(registry *Registry, health *Health, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			switch {
+			case r.URL.Path == MetricsPath && registry != nil:
+				registry.ServeHTTP(w, r)
+				return
+			case r.URL.Path == LivenessPath && health != nil:
+				writeStatus(w, http.StatusOK, "ok")
+				return
+			case r.URL.Path == ReadinessPath && health != nil:
+				if err := health.Ready(r.Context()); err != nil {
+					writeStatus(w, http.StatusServiceUnavailable, fmt.Sprintf("not ready: %s", err.Error()))
+					return
+				}
+				writeStatus(w, http.StatusOK, "ok")
+				return
+			}
+		}
+
+		if next == nil {
+			http.NotFound(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeStatus(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintln(w, message)
+}
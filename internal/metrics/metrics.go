@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics implements the Prometheus metrics and the health endpoints
+// exposed by gittuf's long running services, such as `gittuf serve`, the
+// webhook services, and the monitor. Metrics are exposed in the Prometheus text
+// exposition format, see
+// https://prometheus.io/docs/instrumenting/exposition_formats/.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	kindCounter   = "counter"
+	kindGauge     = "gauge"
+	kindHistogram = "histogram"
+)
+
+// DefaultDurationBuckets are the upper bounds in seconds of the histogram
+// buckets used for durations, such as the time taken to verify a ref.
+var DefaultDurationBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// Registry holds the metrics of a service and writes them in the Prometheus
+// text exposition format.
+type Registry struct {
+	families []*family
+	mu       sync.Mutex
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Counter is a metric whose value only increases, such as the number of
+// verifications performed.
+type Counter struct {
+	family *family
+}
+
+// Add increases the counter for the label values by value, which must not be
+// negative.
+func (c *Counter) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		panic("counter cannot decrease")
+	}
+	c.family.update(labelValues, func(s *series) {
+		s.value += value
+	})
+}
+
+// Inc increases the counter for the label values by one.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Gauge is a metric whose value can be set arbitrarily, such as the number of
+// entries a remote's RSL lags behind.
+type Gauge struct {
+	family *family
+}
+
+// Set sets the gauge for the label values to value.
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.family.update(labelValues, func(s *series) {
+		s.value = value
+	})
+}
+
+// Histogram is a metric that counts observations in buckets, such as the
+// time taken by verifications.
+type Histogram struct {
+	family *family
+}
+
+// Observe adds an observation of value for the label values.
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.family.update(labelValues, func(s *series) {
+		for i, upperBound := range h.family.buckets {
+			if value <= upperBound {
+				s.bucketCounts[i]++
+			}
+		}
+		s.sum += value
+		s.count++
+	})
+}
+
+// NewCounter registers a counter with the specified name, help text, and
+// label names.
+func (r *Registry) NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{family: r.register(name, help, kindCounter, nil, labelNames)}
+}
+
+// NewGauge registers a gauge with the specified name, help text, and label
+// names.
+func (r *Registry) NewGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{family: r.register(name, help, kindGauge, nil, labelNames)}
+}
+
+// NewHistogram registers a histogram with the specified name, help text,
+// bucket upper bounds in increasing order, and label names.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	return &Histogram{family: r.register(name, help, kindHistogram, buckets, labelNames)}
+}
+
+// Write writes the registered metrics to w in the Prometheus text exposition
+// format.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]*family{}, r.families...)
+	r.mu.Unlock()
+
+	output := &strings.Builder{}
+	for _, f := range families {
+		f.write(output)
+	}
+
+	_, err := io.WriteString(w, output.String())
+	return err
+}
+
+// ServeHTTP writes the registered metrics as the response.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.Write(w) //nolint:errcheck
+}
+
+func (r *Registry) register(name, help, kind string, buckets []float64, labelNames []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range r.families {
+		if f.name == name {
+			panic(fmt.Sprintf("metric '%s' is already registered", name))
+		}
+	}
+
+	f := &family{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		buckets:    buckets,
+		labelNames: labelNames,
+		series:     map[string]*series{},
+	}
+	r.families = append(r.families, f)
+
+	return f
+}
+
+// family is a metric along with the series recorded for each combination of
+// label values.
+type family struct {
+	name       string
+	help       string
+	kind       string
+	buckets    []float64
+	labelNames []string
+
+	series map[string]*series
+	mu     sync.Mutex
+}
+
+type series struct {
+	labelValues []string
+
+	// value is the value of counters and gauges
+	value float64
+
+	// bucketCounts, sum, and count are the state of histograms
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+func (f *family) update(labelValues []string, update func(*series)) {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metric '%s' expects %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := strings.Join(labelValues, "\x00")
+	s, has := f.series[key]
+	if !has {
+		s = &series{
+			labelValues:  append([]string{}, labelValues...),
+			bucketCounts: make([]uint64, len(f.buckets)),
+		}
+		f.series[key] = s
+	}
+
+	update(s)
+}
+
+func (f *family) write(output *strings.Builder) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fmt.Fprintf(output, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, f.kind)
+
+	// Metrics without labels are always reported, so that they're present
+	// before the first update
+	if len(f.labelNames) == 0 && len(f.series) == 0 {
+		f.series[""] = &series{bucketCounts: make([]uint64, len(f.buckets))}
+	}
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+
+		switch f.kind {
+		case kindHistogram:
+			for i, upperBound := range f.buckets {
+				fmt.Fprintf(output, "%s_bucket%s %d\n", f.name, f.labels(s.labelValues, "le", formatValue(upperBound)), s.bucketCounts[i])
+			}
+			fmt.Fprintf(output, "%s_bucket%s %d\n", f.name, f.labels(s.labelValues, "le", "+Inf"), s.count)
+			fmt.Fprintf(output, "%s_sum%s %s\n", f.name, f.labels(s.labelValues), formatValue(s.sum))
+			fmt.Fprintf(output, "%s_count%s %d\n", f.name, f.labels(s.labelValues), s.count)
+		default:
+			fmt.Fprintf(output, "%s%s %s\n", f.name, f.labels(s.labelValues), formatValue(s.value))
+		}
+	}
+}
+
+// labels formats the label values, followed by the optional extra label name
+// and value.
+func (f *family) labels(labelValues []string, extra ...string) string {
+	pairs := []string{}
+	for i, name := range f.labelNames {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapeLabelValue(labelValues[i])))
+	}
+	if len(extra) == 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extra[0], extra[1]))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := registry.NewCounter("test_total", "Number of tests.", "result")
+	gauge := registry.NewGauge("test_lag", "Lag of the \"test\".")
+	histogram := registry.NewHistogram("test_duration_seconds", "Duration of tests.", []float64{1, 5}, "name")
+
+	counter.Inc("success")
+	counter.Add(2, "failure")
+	counter.Inc("success")
+	histogram.Observe(0.5, `with"quote`)
+	histogram.Observe(3, `with"quote`)
+	histogram.Observe(10, `with"quote`)
+
+	expectedOutput := `# HELP test_total Number of tests.
+# TYPE test_total counter
+test_total{result="failure"} 2
+test_total{result="success"} 2
+# HELP test_lag Lag of the "test".
+# TYPE test_lag gauge
+test_lag 0
+# HELP test_duration_seconds Duration of tests.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{name="with\"quote",le="1"} 1
+test_duration_seconds_bucket{name="with\"quote",le="5"} 2
+test_duration_seconds_bucket{name="with\"quote",le="+Inf"} 3
+test_duration_seconds_sum{name="with\"quote"} 13.5
+test_duration_seconds_count{name="with\"quote"} 3
+`
+
+	output := &bytes.Buffer{}
+	err := registry.Write(output)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOutput, output.String())
+
+	gauge.Set(4)
+	output.Reset()
+	err = registry.Write(output)
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), "test_lag 4\n")
+
+	assert.Panics(t, func() { counter.Inc() })
+	assert.Panics(t, func() { counter.Add(-1, "success") })
+	assert.Panics(t, func() { registry.NewGauge("test_lag", "Duplicate.") })
+}
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("test_total", "Number of tests.").Inc()
+
+	health := NewHealth()
+	ready := false
+	health.AddReadinessCheck("test", func(_ context.Context) error {
+		if !ready {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	get := func(handler http.Handler, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	t.Run("metrics", func(t *testing.T) {
+		response := get(Handler(registry, health, next), MetricsPath)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), "test_total 1\n")
+	})
+
+	t.Run("liveness", func(t *testing.T) {
+		response := get(Handler(registry, health, next), LivenessPath)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("readiness", func(t *testing.T) {
+		ready = false
+		response := get(Handler(registry, health, next), ReadinessPath)
+		assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+		assert.Contains(t, response.Body.String(), "test: not yet")
+
+		ready = true
+		response = get(Handler(registry, health, next), ReadinessPath)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("other requests are passed on", func(t *testing.T) {
+		response := get(Handler(registry, health, next), "/v1/repositories")
+		assert.Equal(t, http.StatusTeapot, response.Code)
+
+		recorder := httptest.NewRecorder()
+		Handler(registry, health, next).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, MetricsPath, nil))
+		assert.Equal(t, http.StatusTeapot, recorder.Code)
+	})
+
+	t.Run("disabled endpoints", func(t *testing.T) {
+		response := get(Handler(nil, health, next), MetricsPath)
+		assert.Equal(t, http.StatusTeapot, response.Code)
+
+		response = get(Handler(registry, nil, nil), ReadinessPath)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+)
+
+// Results of verifications, used as the value of the result label.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// ReasonOther is the failure reason reported for errors that do not match a
+// more specific reason.
+const ReasonOther = "other"
+
+// failureReasons maps the errors returned by verification to the reasons
+// reported in metrics. The first matching error is used.
+var failureReasons = []struct {
+	err    error
+	reason string
+}{
+	{policy.ErrUnsignedObject, "unsigned_object"},
+	{policy.ErrUnknownSigningKey, "unknown_key"},
+	{policy.ErrUnauthorizedSignature, "unauthorized_signature"},
+	{policy.ErrVerifierConditionsUnmet, "threshold_not_met"},
+	{policy.ErrRequiredHookNotPassed, "required_hook"},
+	{policy.ErrRequiredRebuildsNotMet, "required_rebuilds"},
+	{policy.ErrRequiredEvaluatorNotPassed, "required_evaluator"},
+	{policy.ErrRequiredPredicatesNotMet, "required_predicates"},
+	{policy.ErrRequiredApprovalsNotMet, "required_approvals"},
+	{policy.ErrInvalidPredicate, "invalid_predicate"},
+	{policy.ErrAttestationNotInRekor, "attestation_not_in_rekor"},
+	{policy.ErrLFSObjectsNotAttested, "lfs_objects_not_attested"},
+	{policy.ErrSubmoduleNotVerified, "submodule"},
+	{policy.ErrPolicyJustificationMissing, "policy_justification_missing"},
+	{policy.ErrInvalidEntryNotSkipped, "invalid_entry"},
+	{policy.ErrShallowAnchorNotFound, "shallow_history"},
+	{policy.ErrPolicyNotFound, "policy_not_found"},
+	{rsl.ErrRSLBranchDetected, "rsl_branch"},
+	{rsl.ErrRSLEntryNotFound, "rsl_entry_not_found"},
+}
+
+// FailureReason returns the reason reported in metrics for a verification
+// error.
+func FailureReason(err error) string {
+	for _, failureReason := range failureReasons {
+		if errors.Is(err, failureReason.err) {
+			return failureReason.reason
+		}
+	}
+
+	return ReasonOther
+}
+
+// Verifications records the verifications performed by a service. A nil
+// Verifications records nothing, so services can use it unconditionally.
+type Verifications struct {
+	total        *Counter
+	failures     *Counter
+	duration     *Histogram
+	syncFailures *Counter
+}
+
+// NewVerifications registers the verification metrics with the registry. If
+// registry is nil, nil is returned.
+func NewVerifications(registry *Registry) *Verifications {
+	if registry == nil {
+		return nil
+	}
+
+	return &Verifications{
+		total:        registry.NewCounter("gittuf_verifications_total", "Number of verifications performed, by repository and result.", "repository", "result"),
+		failures:     registry.NewCounter("gittuf_verification_failures_total", "Number of failed verifications, by repository and reason.", "repository", "reason"),
+		duration:     registry.NewHistogram("gittuf_verification_duration_seconds", "Time taken to verify a ref, in seconds.", DefaultDurationBuckets, "repository"),
+		syncFailures: registry.NewCounter("gittuf_repository_sync_failures_total", "Number of times a repository could not be fetched for verification.", "repository"),
+	}
+}
+
+// Observe records a verification of the repository that took duration and
+// returned err.
+func (v *Verifications) Observe(repository string, duration time.Duration, err error) {
+	if v == nil {
+		return
+	}
+
+	v.duration.Observe(duration.Seconds(), repository)
+	if err == nil {
+		v.total.Inc(repository, ResultSuccess)
+		return
+	}
+
+	v.total.Inc(repository, ResultFailure)
+	v.failures.Inc(repository, FailureReason(err))
+}
+
+// ObserveSyncFailure records that the repository could not be fetched for
+// verification.
+func (v *Verifications) ObserveSyncFailure(repository string) {
+	if v == nil {
+		return
+	}
+
+	v.syncFailures.Inc(repository)
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailureReason(t *testing.T) {
+	assert.Equal(t, "unauthorized_signature", FailureReason(fmt.Errorf("verifying entry: %w", policy.ErrUnauthorizedSignature)))
+	assert.Equal(t, "threshold_not_met", FailureReason(policy.ErrVerifierConditionsUnmet))
+	assert.Equal(t, ReasonOther, FailureReason(errors.New("unknown")))
+}
+
+func TestVerifications(t *testing.T) {
+	t.Run("records verifications", func(t *testing.T) {
+		registry := NewRegistry()
+		verifications := NewVerifications(registry)
+
+		verifications.Observe("repo", 2*time.Second, nil)
+		verifications.Observe("repo", time.Second, policy.ErrUnauthorizedSignature)
+		verifications.ObserveSyncFailure("repo")
+
+		output := &bytes.Buffer{}
+		if err := registry.Write(output); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Contains(t, output.String(), "gittuf_verifications_total{repository=\"repo\",result=\"success\"} 1\n")
+		assert.Contains(t, output.String(), "gittuf_verifications_total{repository=\"repo\",result=\"failure\"} 1\n")
+		assert.Contains(t, output.String(), "gittuf_verification_failures_total{repository=\"repo\",reason=\"unauthorized_signature\"} 1\n")
+		assert.Contains(t, output.String(), "gittuf_verification_duration_seconds_sum{repository=\"repo\"} 3\n")
+		assert.Contains(t, output.String(), "gittuf_repository_sync_failures_total{repository=\"repo\"} 1\n")
+	})
+
+	t.Run("nil registry", func(t *testing.T) {
+		verifications := NewVerifications(nil)
+		assert.Nil(t, verifications)
+
+		// Recording with a nil Verifications is a no-op
+		verifications.Observe("repo", time.Second, nil)
+		verifications.ObserveSyncFailure("repo")
+	})
+}
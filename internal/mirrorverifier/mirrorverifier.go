@@ -0,0 +1,375 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mirrorverifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// logger logs the messages of the mirrorverifier subsystem.
+var logger = logging.For("mirrorverifier")
+
+var (
+	ErrNoMirrors          = errors.New("at least one mirror must be configured")
+	ErrInvalidMirrorName  = errors.New("invalid mirror name")
+	ErrDuplicateMirror    = errors.New("mirror is configured more than once")
+	ErrInvalidMirrorSpec  = errors.New("mirror must be specified as <name>=<URL>")
+	ErrVerificationFailed = errors.New("one or more refs failed verification")
+)
+
+var (
+	syncRepository   = gitinterface.SyncMirror
+	verifyRepository = verifyRefs
+)
+
+// Mirror is a repository the daemon verifies.
+type Mirror struct {
+	// Name identifies the mirror on the status page and in metrics.
+	Name string
+
+	// URL is the URL of the repository, or a path to it on disk.
+	URL string
+}
+
+// ParseMirror parses a mirror specified as <name>=<URL>.
+func ParseMirror(spec string) (*Mirror, error) {
+	name, location, found := strings.Cut(spec, "=")
+	if !found || name == "" || location == "" {
+		return nil, fmt.Errorf("%w, got '%s'", ErrInvalidMirrorSpec, spec)
+	}
+
+	return &Mirror{Name: name, URL: location}, nil
+}
+
+// Config contains the settings of the daemon.
+type Config struct {
+	// Mirrors are the repositories the daemon verifies.
+	Mirrors []*Mirror
+
+	// Refs are the refs verified in every mirror. If empty, all of a mirror's
+	// branches and tags are verified.
+	Refs []string
+
+	// MaxAge is how long a successful verification is considered fresh.
+	MaxAge time.Duration
+
+	// CacheDir is the directory the mirrors are fetched to.
+	CacheDir string
+}
+
+// Status is the verification status of a mirror.
+type Status struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+
+	// Verified is true if all of the mirror's refs passed the latest
+	// verification.
+	Verified bool `json:"verified"`
+
+	// Fresh is true if the mirror was verified successfully within the
+	// configured maximum age.
+	Fresh bool `json:"fresh"`
+
+	LastCheck    time.Time `json:"last_check"`
+	LastVerified time.Time `json:"last_verified"`
+	Error        string    `json:"error,omitempty"`
+
+	// Refs contains the result of verifying each ref in the latest
+	// verification, with an empty string for refs that were verified.
+	Refs map[string]string `json:"refs,omitempty"`
+
+	// Failures is the number of checks of the mirror that failed since the
+	// daemon started.
+	Failures int `json:"failures"`
+}
+
+// Daemon periodically fetches and fully verifies a set of mirrors, and serves
+// a status page, the statuses as JSON, and Prometheus metrics describing
+// which mirrors are verified and fresh.
+//
+//	GET /          status page
+//	GET /status    statuses of the mirrors as JSON
+//	GET /metrics   metrics in the Prometheus text format
+type Daemon struct {
+	mirrors  []*Mirror
+	refs     []string
+	maxAge   time.Duration
+	cacheDir string
+
+	statuses map[string]*Status
+	mu       sync.Mutex
+	mux      *http.ServeMux
+}
+
+// New returns a Daemon for the specified configuration.
+func New(config *Config) (*Daemon, error) {
+	if len(config.Mirrors) == 0 {
+		return nil, ErrNoMirrors
+	}
+
+	statuses := map[string]*Status{}
+	for _, mirror := range config.Mirrors {
+		if mirror.Name == "" || mirror.Name != url.PathEscape(mirror.Name) || mirror.Name == "." || mirror.Name == ".." {
+			return nil, fmt.Errorf("%w '%s'", ErrInvalidMirrorName, mirror.Name)
+		}
+		if _, has := statuses[mirror.Name]; has {
+			return nil, fmt.Errorf("%w: '%s'", ErrDuplicateMirror, mirror.Name)
+		}
+		statuses[mirror.Name] = &Status{Name: mirror.Name, URL: mirror.URL}
+	}
+
+	d := &Daemon{
+		mirrors:  config.Mirrors,
+		refs:     config.Refs,
+		maxAge:   config.MaxAge,
+		cacheDir: config.CacheDir,
+		statuses: statuses,
+	}
+
+	d.mux = http.NewServeMux()
+	d.mux.HandleFunc("GET /{$}", d.handleStatusPage)
+	d.mux.HandleFunc("GET /status", d.handleStatus)
+	d.mux.HandleFunc("GET /metrics", d.handleMetrics)
+
+	return d, nil
+}
+
+// Run checks the mirrors every interval until the context is cancelled.
+func (d *Daemon) Run(ctx context.Context, interval time.Duration) {
+	for {
+		if err := d.Check(ctx); err != nil {
+			logger.Debug(fmt.Sprintf("Verification of mirrors failed: %s", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Check fetches and verifies every mirror once, updating their statuses. The
+// errors of mirrors that could not be fetched or failed verification are
+// returned, after all mirrors are checked.
+func (d *Daemon) Check(ctx context.Context) error {
+	var errs []error
+	for _, mirror := range d.mirrors {
+		if err := d.checkMirror(ctx, mirror); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", mirror.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (d *Daemon) checkMirror(ctx context.Context, mirror *Mirror) error {
+	now := time.Now()
+
+	logger.Debug(fmt.Sprintf("Fetching '%s'...", mirror.Name))
+	path := filepath.Join(d.cacheDir, mirror.Name+".git")
+	var refErrs map[string]error
+	// The user's Git credentials are not used, so mirrors must be readable
+	// anonymously
+	err := syncRepository(ctx, path, mirror.URL, nil)
+	if err == nil {
+		logger.Debug(fmt.Sprintf("Verifying '%s'...", mirror.Name))
+		refErrs, err = verifyRepository(ctx, path, d.refs)
+	}
+	if err == nil {
+		for _, refErr := range refErrs {
+			if refErr != nil {
+				err = ErrVerificationFailed
+				break
+			}
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	status := d.statuses[mirror.Name]
+	status.LastCheck = now
+	status.Verified = err == nil
+	status.Error = ""
+	status.Refs = nil
+	if refErrs != nil {
+		status.Refs = map[string]string{}
+		for refName, refErr := range refErrs {
+			status.Refs[refName] = ""
+			if refErr != nil {
+				status.Refs[refName] = refErr.Error()
+			}
+		}
+	}
+
+	if err != nil {
+		status.Error = err.Error()
+		status.Failures++
+		return err
+	}
+
+	status.LastVerified = now
+	return nil
+}
+
+// Statuses returns the statuses of the mirrors, sorted by name.
+func (d *Daemon) Statuses() []*Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]*Status, 0, len(d.statuses))
+	for _, status := range d.statuses {
+		statusCopy := *status
+		statusCopy.Fresh = !status.LastVerified.IsZero() && now.Sub(status.LastVerified) <= d.maxAge
+		statuses = append(statuses, &statusCopy)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
+
+// ServeHTTP routes the request to the matching endpoint.
+func (d *Daemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+var statusPage = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html>
+<head><title>gittuf mirror verification</title></head>
+<body>
+<h1>gittuf mirror verification</h1>
+<table>
+<tr><th>Mirror</th><th>Verified</th><th>Fresh</th><th>Last verified</th><th>Last check</th><th>Error</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.Verified}}</td><td>{{.Fresh}}</td><td>{{if not .LastVerified.IsZero}}{{.LastVerified.Format "2006-01-02T15:04:05Z07:00"}}{{end}}</td><td>{{if not .LastCheck.IsZero}}{{.LastCheck.Format "2006-01-02T15:04:05Z07:00"}}{{end}}</td><td>{{.Error}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func (d *Daemon) handleStatusPage(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := statusPage.Execute(w, d.Statuses()); err != nil {
+		logger.Error("Unable to write status page", "error", err)
+	}
+}
+
+func (d *Daemon) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(d.Statuses()); err != nil {
+		logger.Error("Unable to write response", "error", err)
+	}
+}
+
+func (d *Daemon) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	statuses := d.Statuses()
+
+	metrics := &strings.Builder{}
+	writeMetric := func(name, help, metricType string, value func(*Status) float64) {
+		fmt.Fprintf(metrics, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+		for _, status := range statuses {
+			fmt.Fprintf(metrics, "%s{mirror=%q} %g\n", name, status.Name, value(status))
+		}
+	}
+
+	writeMetric("gittuf_mirror_verified", "Whether all of the mirror's refs passed the latest verification.", "gauge", func(status *Status) float64 {
+		return boolValue(status.Verified)
+	})
+	writeMetric("gittuf_mirror_fresh", "Whether the mirror was verified successfully within the maximum age.", "gauge", func(status *Status) float64 {
+		return boolValue(status.Fresh)
+	})
+	writeMetric("gittuf_mirror_last_verified_timestamp_seconds", "Time of the mirror's latest successful verification.", "gauge", func(status *Status) float64 {
+		return timestampValue(status.LastVerified)
+	})
+	writeMetric("gittuf_mirror_last_check_timestamp_seconds", "Time of the mirror's latest check.", "gauge", func(status *Status) float64 {
+		return timestampValue(status.LastCheck)
+	})
+	writeMetric("gittuf_mirror_check_failures_total", "Number of checks of the mirror that failed.", "counter", func(status *Status) float64 {
+		return float64(status.Failures)
+	})
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if _, err := w.Write([]byte(metrics.String())); err != nil {
+		logger.Error("Unable to write response", "error", err)
+	}
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func timestampValue(value time.Time) float64 {
+	if value.IsZero() {
+		return 0
+	}
+	return float64(value.Unix())
+}
+
+// verifyRefs fully verifies the refs in the repository at the specified path
+// against the repository's gittuf policy, returning the verification error of
+// each ref. If no refs are specified, all branches and tags are verified,
+// except for the temporary branches of merge queues.
+func verifyRefs(ctx context.Context, path string, refNames []string) (map[string]error, error) {
+	if len(refNames) == 0 {
+		r, err := git.PlainOpen(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := gitinterface.CheckObjectFormat(r); err != nil {
+			return nil, err
+		}
+
+		refs, err := r.References()
+		if err != nil {
+			return nil, err
+		}
+		if err := refs.ForEach(func(ref *plumbing.Reference) error {
+			if policy.IsMergeQueueRef(ref.Name().String()) {
+				// Temporary branches of merge queues are not verified
+				return nil
+			}
+			if ref.Name().IsBranch() || ref.Name().IsTag() {
+				refNames = append(refNames, ref.Name().String())
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	repo, err := repository.LoadRepositoryAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	refErrs := map[string]error{}
+	for _, refName := range refNames {
+		refErrs[refName] = repo.VerifyRef(ctx, refName, false)
+	}
+
+	return refErrs, nil
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mirrorverifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaemon(t *testing.T) {
+	syncErrs := map[string]error{}
+	refErrs := map[string]map[string]error{}
+	syncRepository = func(_ context.Context, _, remoteURL string, _ transport.AuthMethod) error {
+		return syncErrs[remoteURL]
+	}
+	verifyRepository = func(_ context.Context, path string, refNames []string) (map[string]error, error) {
+		assert.Equal(t, []string{"refs/heads/main"}, refNames)
+		return refErrs[path], nil
+	}
+	t.Cleanup(func() {
+		syncRepository = gitinterface.SyncMirror
+		verifyRepository = verifyRefs
+	})
+
+	cacheDir := t.TempDir()
+	daemon, err := New(&Config{
+		Mirrors: []*Mirror{
+			{Name: "upstream", URL: "https://example.com/upstream.git"},
+			{Name: "fork", URL: "https://example.com/fork.git"},
+			{Name: "offline", URL: "https://example.com/offline.git"},
+		},
+		Refs:     []string{"refs/heads/main"},
+		MaxAge:   time.Hour,
+		CacheDir: cacheDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	syncErrs["https://example.com/offline.git"] = errors.New("network unreachable")
+	refErrs[cacheDir+"/upstream.git"] = map[string]error{"refs/heads/main": nil}
+	refErrs[cacheDir+"/fork.git"] = map[string]error{"refs/heads/main": errors.New("unauthorized signature")}
+
+	err = daemon.Check(context.Background())
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+
+	statuses := daemon.Statuses()
+	assert.Equal(t, []string{"fork", "offline", "upstream"}, []string{statuses[0].Name, statuses[1].Name, statuses[2].Name})
+
+	assert.False(t, statuses[0].Verified)
+	assert.False(t, statuses[0].Fresh)
+	assert.Equal(t, "unauthorized signature", statuses[0].Refs["refs/heads/main"])
+	assert.Equal(t, 1, statuses[0].Failures)
+
+	assert.False(t, statuses[1].Verified)
+	assert.Contains(t, statuses[1].Error, "network unreachable")
+
+	assert.True(t, statuses[2].Verified)
+	assert.True(t, statuses[2].Fresh)
+	assert.Equal(t, "", statuses[2].Refs["refs/heads/main"])
+
+	// A failed check doesn't change when the mirror was last verified, so it
+	// remains fresh until the maximum age passes
+	lastVerified := statuses[2].LastVerified
+	refErrs[cacheDir+"/upstream.git"] = map[string]error{"refs/heads/main": errors.New("unauthorized signature")}
+	assert.NotNil(t, daemon.Check(context.Background()))
+	statuses = daemon.Statuses()
+	assert.False(t, statuses[2].Verified)
+	assert.True(t, statuses[2].Fresh)
+	assert.Equal(t, lastVerified, statuses[2].LastVerified)
+
+	t.Run("status", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		daemon.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		statuses := []*Status{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &statuses); err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, statuses, 3)
+
+		recorder = httptest.NewRecorder()
+		daemon.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "upstream")
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		daemon.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		metrics := recorder.Body.String()
+		assert.Contains(t, metrics, "# TYPE gittuf_mirror_verified gauge\n")
+		assert.Contains(t, metrics, `gittuf_mirror_verified{mirror="upstream"} 0`)
+		assert.Contains(t, metrics, `gittuf_mirror_fresh{mirror="upstream"} 1`)
+		assert.Contains(t, metrics, `gittuf_mirror_fresh{mirror="fork"} 0`)
+		assert.Contains(t, metrics, `gittuf_mirror_check_failures_total{mirror="offline"} 2`)
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		_, err := New(&Config{})
+		assert.ErrorIs(t, err, ErrNoMirrors)
+
+		_, err = New(&Config{Mirrors: []*Mirror{{Name: "a/b", URL: "https://example.com"}}})
+		assert.ErrorIs(t, err, ErrInvalidMirrorName)
+
+		_, err = New(&Config{Mirrors: []*Mirror{{Name: "a", URL: "https://example.com"}, {Name: "a", URL: "https://example.org"}}})
+		assert.ErrorIs(t, err, ErrDuplicateMirror)
+
+		_, err = ParseMirror("https://example.com")
+		assert.ErrorIs(t, err, ErrInvalidMirrorSpec)
+	})
+}
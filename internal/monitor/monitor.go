@@ -0,0 +1,435 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/gittuf/gittuf/internal/notify"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// logger logs the messages of the monitor subsystem.
+var logger = logging.For("monitor")
+
+// monitorRefPrefix is the prefix of the refs the monitor records the states
+// of each remote's refs in. The objects of witnessed states are kept
+// reachable so that later states can be compared with them.
+const monitorRefPrefix = "refs/gittuf-monitor/"
+
+// watchedRefs are the refs of each remote that are monitored.
+var watchedRefs = []string{rsl.Ref, policy.PolicyRef}
+
+var ErrNoRemotes = errors.New("no remotes or repositories specified to monitor")
+
+// Monitor watches the RSL and policy refs of one or more remotes and raises
+// alerts when a remote rolls back or rewrites a ref it previously served,
+// when remotes serve diverging RSLs, and when a remote's policy changes. It
+// acts as a witness that is independent of developers' clients: the states of
+// the refs it has witnessed are recorded in its own repository. The monitor
+// can also verify the refs of repositories against their gittuf policies, for
+// repository hosts that cannot run verification in hooks.
+type Monitor struct {
+	repo         *git.Repository
+	stateDir     string
+	remotes      []string
+	repositories []*Repository
+	notifier     *notify.Notifier
+
+	// verified records the state of each repository's refs when they were
+	// last verified, so that only refs that changed are verified again.
+	verified map[string]*repositoryState
+
+	// alerted records the alerts that were raised, so that an alert is not
+	// raised again while a remote keeps serving the same state.
+	alerted map[string]bool
+
+	metrics       *monitorMetrics
+	verifications *metrics.Verifications
+}
+
+// monitorMetrics are the metrics recorded by the monitor once registered using
+// RegisterMetrics.
+type monitorMetrics struct {
+	checks        *metrics.Counter
+	alerts        *metrics.Counter
+	lastCheckTime *metrics.Gauge
+	rslLag        *metrics.Gauge
+}
+
+// New returns a Monitor for the remotes and repositories that records the
+// witnessed states of the remotes' refs in a bare repository at stateDir,
+// which is created if needed. Remote repositories are mirrored in stateDir
+// too. Alerts are delivered using the notifier, which may be nil.
+func New(remotes []string, repositories []*Repository, stateDir string, notifier *notify.Notifier) (*Monitor, error) {
+	if len(remotes) == 0 && len(repositories) == 0 {
+		return nil, ErrNoRemotes
+	}
+	if err := validateRepositories(repositories); err != nil {
+		return nil, err
+	}
+
+	repo, err := git.PlainOpen(stateDir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		if err := os.MkdirAll(stateDir, 0o755); err != nil {
+			return nil, err
+		}
+
+		repo, err = git.PlainInit(stateDir, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if notifier == nil {
+		notifier = notify.NewNotifier("", "")
+	}
+
+	return &Monitor{
+		repo:         repo,
+		stateDir:     stateDir,
+		remotes:      remotes,
+		repositories: repositories,
+		notifier:     notifier,
+		verified:     map[string]*repositoryState{},
+		alerted:      map[string]bool{},
+	}, nil
+}
+
+// RegisterMetrics registers the monitor's metrics with the registry, so that
+// they're recorded by subsequent checks. The metrics include the number of
+// checks and alerts raised, the time of the last check, and the number of RSL
+// entries each remote lags behind the remote with the latest RSL. The
+// verifications of the repositories' refs are recorded as well.
+func (m *Monitor) RegisterMetrics(registry *metrics.Registry) {
+	m.metrics = &monitorMetrics{
+		checks:        registry.NewCounter("gittuf_monitor_checks_total", "Number of checks of the remotes performed, by result.", "result"),
+		alerts:        registry.NewCounter("gittuf_monitor_alerts_total", "Number of alerts raised, by event.", "event"),
+		lastCheckTime: registry.NewGauge("gittuf_monitor_last_check_timestamp_seconds", "Time the last check of the remotes completed, in seconds since the Unix epoch."),
+		rslLag:        registry.NewGauge("gittuf_monitor_rsl_lag_entries", "Number of RSL entries a remote lags behind the remote with the latest RSL.", "remote"),
+	}
+	m.verifications = metrics.NewVerifications(registry)
+}
+
+// Check fetches the watched refs of every remote once, compares them with the
+// states witnessed earlier and with each other, and verifies the refs of each
+// repository that changed since the last check. It returns the alerts that
+// were raised. Alerts are logged and delivered using the notifier; delivery
+// errors are returned along with the alerts, as are errors fetching from
+// remotes and repositories, in which case the rest are still checked.
+func (m *Monitor) Check(ctx context.Context) ([]*notify.Notification, error) {
+	alerts := []*notify.Notification{}
+	rslTips := map[string]plumbing.Hash{}
+	var errs []error
+
+	for _, remote := range m.remotes {
+		logger.Debug(fmt.Sprintf("Fetching gittuf refs from '%s'...", remote))
+		tips, err := m.fetch(ctx, remote)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to fetch from '%s': %w", remote, err))
+			continue
+		}
+
+		for _, refName := range watchedRefs {
+			alert, err := m.checkRef(remote, refName, tips[refName])
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if alert != nil {
+				alerts = append(alerts, alert)
+			}
+		}
+
+		if tip, has := tips[rsl.Ref]; has {
+			rslTips[remote] = tip
+		}
+	}
+
+	splitViewAlerts, err := m.checkSplitView(rslTips)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	alerts = append(alerts, splitViewAlerts...)
+
+	if m.metrics != nil {
+		if err := m.recordRSLLag(rslTips); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, repo := range m.repositories {
+		verificationAlerts, err := m.checkRepository(ctx, repo)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		alerts = append(alerts, verificationAlerts...)
+	}
+
+	for _, alert := range alerts {
+		logger.Warn(alert.Error, "event", alert.Event, "repository", alert.Repository, "ref", alert.Ref)
+		if err := m.notifier.Notify(ctx, alert); err != nil {
+			errs = append(errs, fmt.Errorf("unable to send notification: %w", err))
+		}
+	}
+
+	err = errors.Join(errs...)
+	if m.metrics != nil {
+		result := metrics.ResultSuccess
+		if err != nil {
+			result = metrics.ResultFailure
+		}
+		m.metrics.checks.Inc(result)
+		for _, alert := range alerts {
+			m.metrics.alerts.Inc(alert.Event)
+		}
+		m.metrics.lastCheckTime.Set(float64(time.Now().Unix()))
+	}
+
+	return alerts, err
+}
+
+// fetch fetches the watched refs that exist on the remote, returning their
+// tips.
+func (m *Monitor) fetch(ctx context.Context, remoteURL string) (map[string]plumbing.Hash, error) {
+	remote := git.NewRemote(m.repo.Storer, &config.RemoteConfig{Name: remoteKey(remoteURL), URLs: []string{remoteURL}})
+
+	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return nil, err
+	}
+
+	tips := map[string]plumbing.Hash{}
+	refSpecs := []config.RefSpec{}
+	for _, ref := range refs {
+		for _, refName := range watchedRefs {
+			if ref.Name().String() == refName {
+				tips[refName] = ref.Hash()
+				refSpecs = append(refSpecs, config.RefSpec(fmt.Sprintf("+%s:%s", refName, fetchedRef(remoteURL, refName))))
+			}
+		}
+	}
+
+	if len(refSpecs) == 0 {
+		return tips, nil
+	}
+
+	err = remote.FetchContext(ctx, &git.FetchOptions{RefSpecs: refSpecs, Force: true})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, err
+	}
+
+	return tips, nil
+}
+
+// checkRef compares the remote's current tip for the ref with the tip
+// witnessed earlier. The ref may only move forward: if the current tip is an
+// ancestor of the witnessed tip, the remote rolled the ref back, and if
+// neither is an ancestor of the other, the remote rewrote the ref's history.
+// Otherwise, the current tip is recorded as witnessed.
+func (m *Monitor) checkRef(remote, refName string, tip plumbing.Hash) (*notify.Notification, error) {
+	witnessedRefName := plumbing.ReferenceName(witnessedRef(remote, refName))
+
+	witnessed, err := m.repo.Reference(witnessedRefName, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		if tip.IsZero() {
+			return nil, nil
+		}
+
+		logger.Debug(fmt.Sprintf("Witnessing '%s' of '%s' for the first time at '%s'...", refName, remote, tip.String()))
+		return nil, m.repo.Storer.SetReference(plumbing.NewHashReference(witnessedRefName, tip))
+	} else if err != nil {
+		return nil, err
+	}
+
+	witnessedTip := witnessed.Hash()
+	details := map[string]string{"witnessed": witnessedTip.String(), "current": tip.String()}
+
+	switch {
+	case tip == witnessedTip:
+		return nil, nil
+	case tip.IsZero():
+		return m.alert(notify.EventRollback, remote, refName, "ref was deleted from the remote after it was witnessed", details), nil
+	}
+
+	witnessedCommit, err := gitinterface.GetCommit(m.repo, witnessedTip)
+	if err != nil {
+		return nil, err
+	}
+	isForward, err := gitinterface.KnowsCommit(m.repo, tip, witnessedCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isForward {
+		currentCommit, err := gitinterface.GetCommit(m.repo, tip)
+		if err != nil {
+			return nil, err
+		}
+		isRollback, err := gitinterface.KnowsCommit(m.repo, witnessedTip, currentCommit)
+		if err != nil {
+			return nil, err
+		}
+
+		if isRollback {
+			return m.alert(notify.EventRollback, remote, refName, "ref was rolled back to an earlier state than the one witnessed", details), nil
+		}
+		return m.alert(notify.EventFork, remote, refName, "ref's history was rewritten and no longer contains the state witnessed", details), nil
+	}
+
+	logger.Debug(fmt.Sprintf("Witnessing '%s' of '%s' at '%s'...", refName, remote, tip.String()))
+	if err := m.repo.Storer.SetReference(plumbing.NewHashReference(witnessedRefName, tip)); err != nil {
+		return nil, err
+	}
+
+	if refName == policy.PolicyRef {
+		return m.alert(notify.EventPolicyChanged, remote, refName, "policy was changed", details), nil
+	}
+
+	return nil, nil
+}
+
+// checkSplitView compares the RSLs served by the remotes with one another. As
+// the remotes serve the same repository, each RSL must be an ancestor of or
+// the same as the others, though some remotes may lag behind.
+func (m *Monitor) checkSplitView(rslTips map[string]plumbing.Hash) ([]*notify.Notification, error) {
+	remotes := make([]string, 0, len(rslTips))
+	for remote := range rslTips {
+		remotes = append(remotes, remote)
+	}
+	sort.Strings(remotes)
+
+	alerts := []*notify.Notification{}
+	for i := range remotes {
+		for _, other := range remotes[i+1:] {
+			tip, otherTip := rslTips[remotes[i]], rslTips[other]
+			if tip == otherTip {
+				continue
+			}
+
+			commit, err := gitinterface.GetCommit(m.repo, tip)
+			if err != nil {
+				return nil, err
+			}
+			otherCommit, err := gitinterface.GetCommit(m.repo, otherTip)
+			if err != nil {
+				return nil, err
+			}
+
+			otherIsAhead, err := gitinterface.KnowsCommit(m.repo, otherTip, commit)
+			if err != nil {
+				return nil, err
+			}
+			isAhead, err := gitinterface.KnowsCommit(m.repo, tip, otherCommit)
+			if err != nil {
+				return nil, err
+			}
+			if otherIsAhead || isAhead {
+				continue
+			}
+
+			details := map[string]string{remotes[i]: tip.String(), other: otherTip.String()}
+			if alert := m.alert(notify.EventSplitView, remotes[i]+", "+other, rsl.Ref, "remotes serve diverging RSLs", details); alert != nil {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+
+	return alerts, nil
+}
+
+// recordRSLLag records the number of RSL entries each remote lags behind the
+// remotes whose RSLs contain its RSL. Remotes whose RSLs diverge from the
+// others are reported by checkSplitView instead, and do not contribute to the
+// lag.
+func (m *Monitor) recordRSLLag(rslTips map[string]plumbing.Hash) error {
+	for remote, tip := range rslTips {
+		lag := 0
+		for other, otherTip := range rslTips {
+			if other == remote {
+				continue
+			}
+
+			entries, found, err := m.countEntriesSince(otherTip, tip)
+			if err != nil {
+				return err
+			}
+			if found && entries > lag {
+				lag = entries
+			}
+		}
+
+		m.metrics.rslLag.Set(float64(lag), remote)
+	}
+
+	return nil
+}
+
+// countEntriesSince returns the number of RSL entries from tip back to but
+// not including ancestor, walking the RSL's linear history. If ancestor is not
+// in the history of tip, false is returned.
+func (m *Monitor) countEntriesSince(tip, ancestor plumbing.Hash) (int, bool, error) {
+	entries := 0
+	for current := tip; current != ancestor; entries++ {
+		commit, err := m.repo.CommitObject(current)
+		if err != nil {
+			return 0, false, err
+		}
+		if len(commit.ParentHashes) == 0 {
+			return 0, false, nil
+		}
+
+		current = commit.ParentHashes[0]
+	}
+
+	return entries, true, nil
+}
+
+// alert returns a notification for the alert, unless the same alert was
+// already raised.
+func (m *Monitor) alert(event, remote, refName, message string, details map[string]string) *notify.Notification {
+	key := fmt.Sprintf("%s %s %s %v", event, remote, refName, details)
+	if m.alerted[key] {
+		return nil
+	}
+	m.alerted[key] = true
+
+	return &notify.Notification{
+		Event:      event,
+		Time:       time.Now(),
+		Repository: remote,
+		Ref:        refName,
+		Error:      message,
+		Details:    details,
+	}
+}
+
+// remoteKey identifies the remote in the names of the monitor's refs.
+func remoteKey(remoteURL string) string {
+	hash := sha256.Sum256([]byte(remoteURL))
+	return hex.EncodeToString(hash[:8])
+}
+
+func fetchedRef(remoteURL, refName string) string {
+	return monitorRefPrefix + remoteKey(remoteURL) + "/fetched/" + refName
+}
+
+func witnessedRef(remoteURL, refName string) string {
+	return monitorRefPrefix + remoteKey(remoteURL) + "/witnessed/" + refName
+}
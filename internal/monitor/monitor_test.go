@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/gittuf/gittuf/internal/notify"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRemote(t *testing.T) (string, *git.Repository) {
+	t.Helper()
+
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir, r
+}
+
+// addRSLEntry records an RSL entry for a distinct target in the remote,
+// returning the new tip of the RSL.
+func addRSLEntry(t *testing.T, r *git.Repository, target int) plumbing.Hash {
+	t.Helper()
+
+	targetID := plumbing.NewHash(fmt.Sprintf("%040x", target))
+	if err := rsl.NewReferenceEntry("refs/heads/main", targetID).Commit(r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := r.Reference(rsl.Ref, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref.Hash()
+}
+
+func setRef(t *testing.T, r *git.Repository, refName string, tip plumbing.Hash) {
+	t.Helper()
+
+	if err := r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), tip)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func events(alerts []*notify.Notification) []string {
+	names := []string{}
+	for _, alert := range alerts {
+		names = append(names, alert.Event)
+	}
+	return names
+}
+
+func TestMonitor(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("forward changes", func(t *testing.T) {
+		remoteDir, remote := newTestRemote(t)
+		addRSLEntry(t, remote, 1)
+
+		monitor, err := New([]string{remoteDir}, nil, t.TempDir(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		alerts, err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, alerts)
+
+		addRSLEntry(t, remote, 2)
+		alerts, err = monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, alerts)
+
+		treeID, err := gitinterface.WriteTree(remote, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gitinterface.Commit(remote, treeID, policy.PolicyRef, "Initial policy", false); err != nil {
+			t.Fatal(err)
+		}
+		alerts, err = monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, alerts)
+
+		if _, err := gitinterface.Commit(remote, treeID, policy.PolicyRef, "Update policy", false); err != nil {
+			t.Fatal(err)
+		}
+		alerts, err = monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{notify.EventPolicyChanged}, events(alerts))
+		assert.Equal(t, policy.PolicyRef, alerts[0].Ref)
+		assert.Equal(t, remoteDir, alerts[0].Repository)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		remoteDir, remote := newTestRemote(t)
+		firstTip := addRSLEntry(t, remote, 1)
+		secondTip := addRSLEntry(t, remote, 2)
+
+		monitor, err := New([]string{remoteDir}, nil, t.TempDir(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := monitor.Check(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		setRef(t, remote, rsl.Ref, firstTip)
+		alerts, err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{notify.EventRollback}, events(alerts))
+
+		// The alert is not raised again for the same state
+		alerts, err = monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, alerts)
+
+		// The rolled back state isn't witnessed, so restoring the RSL doesn't
+		// raise an alert
+		setRef(t, remote, rsl.Ref, secondTip)
+		alerts, err = monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, alerts)
+	})
+
+	t.Run("fork", func(t *testing.T) {
+		remoteDir, remote := newTestRemote(t)
+		firstTip := addRSLEntry(t, remote, 1)
+		addRSLEntry(t, remote, 2)
+
+		monitor, err := New([]string{remoteDir}, nil, t.TempDir(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := monitor.Check(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		setRef(t, remote, rsl.Ref, firstTip)
+		addRSLEntry(t, remote, 3)
+		alerts, err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{notify.EventFork}, events(alerts))
+	})
+
+	t.Run("split view", func(t *testing.T) {
+		remoteDir, remote := newTestRemote(t)
+		otherRemoteDir, otherRemote := newTestRemote(t)
+		addRSLEntry(t, remote, 1)
+		addRSLEntry(t, otherRemote, 2)
+
+		monitor, err := New([]string{remoteDir, otherRemoteDir}, nil, t.TempDir(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		alerts, err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{notify.EventSplitView}, events(alerts))
+	})
+
+	t.Run("no remotes", func(t *testing.T) {
+		_, err := New(nil, nil, t.TempDir(), nil)
+		assert.ErrorIs(t, err, ErrNoRemotes)
+	})
+}
+
+func TestMonitorMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	remoteDir, remote := newTestRemote(t)
+	addRSLEntry(t, remote, 1)
+
+	laggingRemoteDir := t.TempDir()
+	if err := gitinterface.SyncMirror(ctx, laggingRemoteDir, remoteDir, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	addRSLEntry(t, remote, 2)
+	addRSLEntry(t, remote, 3)
+
+	monitor, err := New([]string{remoteDir, laggingRemoteDir}, nil, t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := metrics.NewRegistry()
+	monitor.RegisterMetrics(registry)
+
+	alerts, err := monitor.Check(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, alerts)
+
+	output := &bytes.Buffer{}
+	if err := registry.Write(output); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Contains(t, output.String(), fmt.Sprintf("gittuf_monitor_rsl_lag_entries{remote=\"%s\"} 0\n", remoteDir))
+	assert.Contains(t, output.String(), fmt.Sprintf("gittuf_monitor_rsl_lag_entries{remote=\"%s\"} 2\n", laggingRemoteDir))
+	assert.Contains(t, output.String(), "gittuf_monitor_checks_total{result=\"success\"} 1\n")
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/gittuf/gittuf/internal/notify"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var (
+	ErrInvalidRepositorySpec = errors.New("repository must be specified as <name>=<path or URL>")
+	ErrInvalidRepositoryName = errors.New("invalid repository name")
+	ErrDuplicateRepository   = errors.New("repository is specified more than once")
+)
+
+var (
+	syncRepository   = gitinterface.SyncMirror
+	verifyRepository = verifyRef
+)
+
+// mirrorsDir is the directory in the state directory that remote repositories
+// are mirrored to.
+const mirrorsDir = "mirrors"
+
+// Repository is a repository whose refs the monitor verifies.
+type Repository struct {
+	// Name identifies the repository in metrics and logs.
+	Name string
+
+	// Location is the path to the repository on disk or the URL of a remote
+	// repository. Remote repositories are mirrored before each check.
+	Location string
+}
+
+// ParseRepository parses a repository specified as <name>=<path or URL>.
+func ParseRepository(spec string) (*Repository, error) {
+	name, location, found := strings.Cut(spec, "=")
+	if !found || name == "" || location == "" {
+		return nil, fmt.Errorf("%w, got '%s'", ErrInvalidRepositorySpec, spec)
+	}
+
+	return &Repository{Name: name, Location: location}, nil
+}
+
+// repositoryState is the state of a repository's refs when they were last
+// verified.
+type repositoryState struct {
+	rslTip plumbing.Hash
+	tips   map[string]plumbing.Hash
+}
+
+// checkRepository updates the repository's mirror if it's a remote repository,
+// and verifies the refs that changed since they were last verified. All refs
+// are verified when the RSL changes, as new RSL entries may affect refs whose
+// tips are unchanged. An alert is raised for each ref that fails verification.
+func (m *Monitor) checkRepository(ctx context.Context, repo *Repository) ([]*notify.Notification, error) {
+	path := repo.Location
+	if gitinterface.IsRemoteLocation(repo.Location) {
+		logger.Debug(fmt.Sprintf("Fetching '%s'...", repo.Name))
+		path = filepath.Join(m.stateDir, mirrorsDir, repo.Name+".git")
+		// The user's Git credentials are not used, so remote repositories
+		// must be accessible anonymously
+		if err := syncRepository(ctx, path, repo.Location, nil); err != nil {
+			m.verifications.ObserveSyncFailure(repo.Name)
+			return nil, fmt.Errorf("unable to fetch '%s': %w", repo.Name, err)
+		}
+	}
+
+	current, err := getRepositoryState(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read refs of '%s': %w", repo.Name, err)
+	}
+
+	previous, has := m.verified[repo.Name]
+	if !has {
+		previous = &repositoryState{tips: map[string]plumbing.Hash{}}
+	}
+
+	refNames := make([]string, 0, len(current.tips))
+	for refName, tip := range current.tips {
+		if previousTip, has := previous.tips[refName]; has && previousTip == tip && previous.rslTip == current.rslTip {
+			continue
+		}
+		refNames = append(refNames, refName)
+	}
+	slices.Sort(refNames)
+
+	alerts := []*notify.Notification{}
+	for _, refName := range refNames {
+		logger.Debug(fmt.Sprintf("Verifying '%s' of '%s'...", refName, repo.Name))
+		start := time.Now()
+		verificationErr := verifyRepository(ctx, path, refName)
+		m.verifications.Observe(repo.Name, time.Since(start), verificationErr)
+		if verificationErr == nil {
+			continue
+		}
+
+		event := notify.EventVerificationFailed
+		message := "ref failed verification"
+		if errors.Is(verificationErr, repository.ErrRefStateDoesNotMatchRSL) {
+			event = notify.EventUnauthorizedChange
+			message = "ref was changed without a corresponding RSL entry"
+		}
+
+		details := map[string]string{
+			"tip":    current.tips[refName].String(),
+			"reason": metrics.FailureReason(verificationErr),
+			"error":  verificationErr.Error(),
+		}
+		if alert := m.alert(event, repo.Location, refName, message, details); alert != nil {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	// Refs that failed verification are not verified again until they or the
+	// RSL change, as the result would be the same
+	m.verified[repo.Name] = current
+
+	return alerts, nil
+}
+
+// getRepositoryState returns the tips of the repository's branches and tags,
+// except for the temporary branches of merge queues, along with the tip of
+// its RSL.
+func getRepositoryState(path string) (*repositoryState, error) {
+	r, err := gitinterface.OpenRepository(path)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &repositoryState{tips: map[string]plumbing.Hash{}}
+
+	refs, err := r.References()
+	if err != nil {
+		return nil, err
+	}
+	if err := refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		refName := ref.Name().String()
+		switch {
+		case refName == rsl.Ref:
+			state.rslTip = ref.Hash()
+		case policy.IsMergeQueueRef(refName):
+			// Temporary branches of merge queues are not verified
+		case ref.Name().IsBranch() || ref.Name().IsTag():
+			state.tips[refName] = ref.Hash()
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// validateRepositories checks that the repositories have distinct names that
+// can be used as directory names.
+func validateRepositories(repositories []*Repository) error {
+	names := map[string]bool{}
+	for _, repo := range repositories {
+		if repo.Name == "" || repo.Name != url.PathEscape(repo.Name) || repo.Name == "." || repo.Name == ".." {
+			return fmt.Errorf("%w '%s'", ErrInvalidRepositoryName, repo.Name)
+		}
+		if names[repo.Name] {
+			return fmt.Errorf("%w: '%s'", ErrDuplicateRepository, repo.Name)
+		}
+		names[repo.Name] = true
+	}
+
+	return nil
+}
+
+// verifyRef fully verifies the ref in the repository at the specified path
+// against the repository's gittuf policy.
+func verifyRef(ctx context.Context, path, refName string) error {
+	repo, err := repository.LoadRepositoryAt(path)
+	if err != nil {
+		return err
+	}
+
+	return repo.VerifyRef(ctx, refName, false)
+}
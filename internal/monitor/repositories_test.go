@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/notify"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRepository(t *testing.T) {
+	repo, err := ParseRepository("gittuf=https://github.com/gittuf/gittuf")
+	assert.Nil(t, err)
+	assert.Equal(t, &Repository{Name: "gittuf", Location: "https://github.com/gittuf/gittuf"}, repo)
+
+	for _, spec := range []string{"gittuf", "=/tmp/gittuf", "gittuf="} {
+		_, err := ParseRepository(spec)
+		assert.ErrorIs(t, err, ErrInvalidRepositorySpec)
+	}
+}
+
+func TestMonitorRepositories(t *testing.T) {
+	ctx := context.Background()
+	errTest := errors.New("test error")
+
+	// stubVerification records the refs that are verified and fails
+	// verification of the refs in failures
+	stubVerification := func(t *testing.T, failures map[string]error) *[]string {
+		t.Helper()
+
+		verified := []string{}
+		original := verifyRepository
+		verifyRepository = func(_ context.Context, _, refName string) error {
+			verified = append(verified, refName)
+			return failures[refName]
+		}
+		t.Cleanup(func() { verifyRepository = original })
+
+		return &verified
+	}
+
+	t.Run("changed refs are verified", func(t *testing.T) {
+		repoDir, r := newTestRemote(t)
+		main := setCommit(t, r, "refs/heads/main")
+		setCommit(t, r, "refs/tags/v1")
+		setCommit(t, r, "refs/heads/gh-readonly-queue/main/pr-1-abc")
+		addRSLEntry(t, r, 1)
+
+		verified := stubVerification(t, map[string]error{"refs/heads/main": repository.ErrRefStateDoesNotMatchRSL})
+
+		monitor, err := New(nil, []*Repository{{Name: "test", Location: repoDir}}, t.TempDir(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		alerts, err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"refs/heads/main", "refs/tags/v1"}, *verified)
+		assert.Equal(t, []string{notify.EventUnauthorizedChange}, events(alerts))
+		require.Len(t, alerts, 1)
+		assert.Equal(t, repoDir, alerts[0].Repository)
+		assert.Equal(t, "refs/heads/main", alerts[0].Ref)
+		assert.Equal(t, main.String(), alerts[0].Details.(map[string]string)["tip"])
+
+		// Unchanged refs are not verified again
+		*verified = []string{}
+		alerts, err = monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, *verified)
+		assert.Empty(t, alerts)
+
+		setCommit(t, r, "refs/tags/v2")
+		alerts, err = monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"refs/tags/v2"}, *verified)
+		assert.Empty(t, alerts)
+
+		// All refs are verified when the RSL changes, and the alert for the
+		// unchanged ref is not raised again
+		*verified = []string{}
+		addRSLEntry(t, r, 2)
+		alerts, err = monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"refs/heads/main", "refs/tags/v1", "refs/tags/v2"}, *verified)
+		assert.Empty(t, alerts)
+	})
+
+	t.Run("verification failure", func(t *testing.T) {
+		repoDir, r := newTestRemote(t)
+		setCommit(t, r, "refs/heads/main")
+
+		stubVerification(t, map[string]error{"refs/heads/main": errTest})
+
+		monitor, err := New(nil, []*Repository{{Name: "test", Location: repoDir}}, t.TempDir(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		alerts, err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{notify.EventVerificationFailed}, events(alerts))
+	})
+
+	t.Run("remote repository", func(t *testing.T) {
+		remoteDir, r := newTestRemote(t)
+		setCommit(t, r, "refs/heads/main")
+
+		verified := stubVerification(t, nil)
+
+		mirrored := ""
+		original := syncRepository
+		syncRepository = func(ctx context.Context, path, _ string, auth transport.AuthMethod) error {
+			mirrored = path
+			return original(ctx, path, remoteDir, auth)
+		}
+		t.Cleanup(func() { syncRepository = original })
+
+		stateDir := t.TempDir()
+		monitor, err := New(nil, []*Repository{{Name: "test", Location: "https://example.com/test"}}, stateDir, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		alerts, err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, alerts)
+		assert.Contains(t, mirrored, stateDir)
+		assert.Equal(t, []string{"refs/heads/main"}, *verified)
+
+		syncRepository = func(_ context.Context, _, _ string, _ transport.AuthMethod) error {
+			return errTest
+		}
+		_, err = monitor.Check(ctx)
+		assert.ErrorIs(t, err, errTest)
+	})
+
+	t.Run("invalid repositories", func(t *testing.T) {
+		_, err := New(nil, []*Repository{{Name: "../test", Location: t.TempDir()}}, t.TempDir(), nil)
+		assert.ErrorIs(t, err, ErrInvalidRepositoryName)
+
+		_, err = New(nil, []*Repository{{Name: "test", Location: t.TempDir()}, {Name: "test", Location: t.TempDir()}}, t.TempDir(), nil)
+		assert.ErrorIs(t, err, ErrDuplicateRepository)
+	})
+}
+
+// setCommit creates a commit in the repository and sets the ref to it,
+// returning the commit's ID.
+func setCommit(t *testing.T, r *git.Repository, refName string) plumbing.Hash {
+	t.Helper()
+
+	commitID, err := gitinterface.Commit(r, gitinterface.EmptyTree(), refName, "Test commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return commitID
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// EventVerificationFailed is the event of notifications sent when a ref
+	// fails verification.
+	EventVerificationFailed = "verification_failed"
+
+	// EventRollback is the event of notifications sent when a remote serves
+	// an earlier state of a ref than one it served before.
+	EventRollback = "rollback"
+
+	// EventFork is the event of notifications sent when a remote rewrites
+	// the history of a ref it served before.
+	EventFork = "fork"
+
+	// EventSplitView is the event of notifications sent when remotes of the
+	// same repository serve diverging RSLs.
+	EventSplitView = "split_view"
+
+	// EventPolicyChanged is the event of notifications sent when a remote's
+	// policy changes.
+	EventPolicyChanged = "policy_changed"
+
+	// EventUnauthorizedChange is the event of notifications sent when a ref
+	// is changed without a corresponding RSL entry.
+	EventUnauthorizedChange = "unauthorized_change"
+)
+
+var ErrUnexpectedResponse = errors.New("unexpected response from webhook")
+
+// Notification is the JSON payload sent to webhooks and commands.
+type Notification struct {
+	Event string    `json:"event"`
+	Time  time.Time `json:"time"`
+
+	// Repository is the path of the repository's Git directory, or the URL of
+	// the remote the notification is about.
+	Repository string `json:"repository"`
+
+	// Ref is the ref the notification is about, such as the ref that failed
+	// verification.
+	Ref string `json:"ref"`
+
+	// Error describes the problem, such as the verification error.
+	Error string `json:"error"`
+
+	// Details contains more information about the problem, such as the RSL
+	// entries that failed verification.
+	Details any `json:"details,omitempty"`
+}
+
+// Notifier delivers notifications to a webhook and to a command. A Notifier
+// with neither set delivers nothing.
+type Notifier struct {
+	// webhookURL is the URL notifications are POSTed to as JSON.
+	webhookURL string
+
+	// command is a shell command that is invoked for each notification with
+	// the JSON payload on stdin.
+	command string
+
+	httpClient *http.Client
+}
+
+// NewNotifier returns a Notifier for the specified webhook URL and command,
+// either of which may be empty.
+func NewNotifier(webhookURL, command string) *Notifier {
+	return &Notifier{
+		webhookURL: webhookURL,
+		command:    command,
+		httpClient: http.DefaultClient,
+	}
+}
+
+// Enabled returns true if the notifier delivers notifications anywhere.
+func (n *Notifier) Enabled() bool {
+	return n.webhookURL != "" || n.command != ""
+}
+
+// Notify delivers the notification to the webhook and the command. Delivery
+// is attempted for both even if one fails.
+func (n *Notifier) Notify(ctx context.Context, notification *Notification) error {
+	if !n.Enabled() {
+		return nil
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	var webhookErr, commandErr error
+	if n.webhookURL != "" {
+		webhookErr = n.postWebhook(ctx, payload)
+	}
+	if n.command != "" {
+		commandErr = n.runCommand(ctx, payload)
+	}
+
+	return errors.Join(webhookErr, commandErr)
+}
+
+func (n *Notifier) postWebhook(ctx context.Context, payload []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		responseBytes, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("%w: status %d: %s", ErrUnexpectedResponse, response.StatusCode, strings.TrimSpace(string(responseBytes)))
+	}
+
+	return nil
+}
+
+func (n *Notifier) runCommand(ctx context.Context, payload []byte) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", n.command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = os.Stderr // keep the command's output out of gittuf's output
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("notification command failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifier(t *testing.T) {
+	notification := &Notification{
+		Event:      EventVerificationFailed,
+		Time:       time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Repository: "/tmp/repo/.git",
+		Ref:        "refs/heads/main",
+		Error:      "verifying Git namespace policies failed",
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		notifier := NewNotifier("", "")
+		assert.False(t, notifier.Enabled())
+		assert.Nil(t, notifier.Notify(context.Background(), notification))
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		var received *Notification
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			body, err := io.ReadAll(r.Body)
+			require.Nil(t, err)
+			received = &Notification{}
+			require.Nil(t, json.Unmarshal(body, received))
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		notifier := NewNotifier(server.URL, "")
+		assert.True(t, notifier.Enabled())
+		assert.Nil(t, notifier.Notify(context.Background(), notification))
+		assert.Equal(t, notification, received)
+	})
+
+	t.Run("webhook fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "bad token", http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		err := NewNotifier(server.URL, "").Notify(context.Background(), notification)
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+		assert.ErrorContains(t, err, "bad token")
+	})
+
+	t.Run("command", func(t *testing.T) {
+		outputFile := filepath.Join(t.TempDir(), "payload")
+
+		notifier := NewNotifier("", "cat > "+outputFile)
+		assert.Nil(t, notifier.Notify(context.Background(), notification))
+
+		payload, err := os.ReadFile(outputFile)
+		require.Nil(t, err)
+		received := &Notification{}
+		require.Nil(t, json.Unmarshal(payload, received))
+		assert.Equal(t, notification, received)
+	})
+
+	t.Run("command fails", func(t *testing.T) {
+		err := NewNotifier("", "exit 1").Notify(context.Background(), notification)
+		assert.ErrorContains(t, err, "notification command failed")
+	})
+}
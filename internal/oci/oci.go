@@ -0,0 +1,539 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	// ManifestMediaType is the media type of the manifests of the artifacts
+	// pushed to and pulled from registries.
+	ManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+
+	// EmptyConfigMediaType is the media type of the config of artifacts that
+	// are not container images.
+	EmptyConfigMediaType = "application/vnd.oci.empty.v1+json"
+
+	defaultTag          = "latest"
+	dockerHubRegistry   = "docker.io"
+	dockerHubAPIHost    = "registry-1.docker.io"
+	dockerHubNamespace  = "library"
+	sha256DigestPrefix  = "sha256:"
+	maxManifestSize     = 4 << 20
+	maxBlobSize         = 64 << 20
+	bearerChallengeType = "bearer"
+)
+
+// emptyConfig is the contents of the config blob of artifacts that are not
+// container images, as recommended by the OCI image spec.
+var emptyConfig = []byte("{}")
+
+var (
+	ErrInvalidReference       = errors.New("invalid OCI artifact reference")
+	ErrUnexpectedResponse     = errors.New("unexpected response from registry")
+	ErrUnexpectedArtifactType = errors.New("unexpected OCI artifact type")
+	ErrInvalidManifest        = errors.New("invalid OCI artifact manifest")
+	ErrDigestMismatch         = errors.New("digest of fetched content does not match expected digest")
+)
+
+// Reference identifies an artifact in a registry, such as
+// `ghcr.io/gittuf/policy:latest` or `ghcr.io/gittuf/policy@sha256:...`.
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses the reference of an artifact. The reference must
+// include the registry, and the tag defaults to `latest` if neither a tag nor
+// a digest is specified.
+func ParseReference(reference string) (*Reference, error) {
+	registry, rest, found := strings.Cut(reference, "/")
+	if !found || registry == "" || rest == "" {
+		return nil, fmt.Errorf("%w: '%s' must include the registry", ErrInvalidReference, reference)
+	}
+	if !strings.ContainsAny(registry, ".:") && registry != "localhost" {
+		return nil, fmt.Errorf("%w: '%s' must include the registry", ErrInvalidReference, reference)
+	}
+
+	ref := &Reference{Registry: registry}
+
+	if repository, digest, hasDigest := strings.Cut(rest, "@"); hasDigest {
+		if !strings.HasPrefix(digest, sha256DigestPrefix) || len(digest) != len(sha256DigestPrefix)+sha256.Size*2 {
+			return nil, fmt.Errorf("%w: unsupported digest '%s'", ErrInvalidReference, digest)
+		}
+		ref.Digest = digest
+		rest = repository
+	}
+
+	if index := strings.LastIndex(rest, ":"); index > strings.LastIndex(rest, "/") {
+		ref.Tag = rest[index+1:]
+		rest = rest[:index]
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+
+	if rest == "" || rest != strings.ToLower(rest) {
+		return nil, fmt.Errorf("%w: invalid repository in '%s'", ErrInvalidReference, reference)
+	}
+	if registry == dockerHubRegistry && !strings.Contains(rest, "/") {
+		rest = dockerHubNamespace + "/" + rest
+	}
+	ref.Repository = rest
+
+	return ref, nil
+}
+
+// String returns the reference in its canonical form.
+func (r *Reference) String() string {
+	reference := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		reference += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		reference += "@" + r.Digest
+	}
+	return reference
+}
+
+// WithDigest returns a copy of the reference that identifies the artifact by
+// the specified digest.
+func (r *Reference) WithDigest(digest string) *Reference {
+	return &Reference{Registry: r.Registry, Repository: r.Repository, Digest: digest}
+}
+
+func (r *Reference) manifestReference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// Descriptor describes content stored in a registry.
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest is an OCI image manifest describing an artifact.
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// Artifact is a single file stored in a registry. It's pushed as an OCI image
+// manifest with an empty config and the file as its only layer, which allows
+// it to be signed and verified using tools such as cosign.
+type Artifact struct {
+	// ArtifactType identifies the kind of artifact.
+	ArtifactType string
+
+	// MediaType is the media type of the contents.
+	MediaType string
+
+	Contents    []byte
+	Annotations map[string]string
+}
+
+// Client pushes artifacts to and pulls artifacts from registries implementing
+// the OCI distribution spec.
+type Client struct {
+	username   string
+	password   string
+	httpClient *http.Client
+
+	tokensMu sync.Mutex
+	tokens   map[string]string
+}
+
+// NewClient returns a Client that authenticates with registries using the
+// specified credentials, if any. Registries that use token authentication
+// are supported as well.
+func NewClient(username, password string) *Client {
+	return &Client{
+		username:   username,
+		password:   password,
+		httpClient: http.DefaultClient,
+		tokens:     map[string]string{},
+	}
+}
+
+// Push uploads the artifact to the registry, and tags it if the reference has
+// a tag. The digest of the artifact's manifest is returned.
+func (c *Client) Push(ctx context.Context, ref *Reference, artifact *Artifact) (string, error) {
+	scope := fmt.Sprintf("repository:%s:pull,push", ref.Repository)
+
+	config := newDescriptor(EmptyConfigMediaType, emptyConfig)
+	layer := newDescriptor(artifact.MediaType, artifact.Contents)
+
+	for _, blob := range []struct {
+		descriptor Descriptor
+		contents   []byte
+	}{{config, emptyConfig}, {layer, artifact.Contents}} {
+		if err := c.uploadBlob(ctx, ref, scope, blob.descriptor.Digest, blob.contents); err != nil {
+			return "", err
+		}
+	}
+
+	manifest := &Manifest{
+		SchemaVersion: 2,
+		MediaType:     ManifestMediaType,
+		ArtifactType:  artifact.ArtifactType,
+		Config:        config,
+		Layers:        []Descriptor{layer},
+		Annotations:   artifact.Annotations,
+	}
+	manifestContents, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+	digest := computeDigest(manifestContents)
+
+	manifestRef := ref.manifestReference()
+	if ref.Digest != "" && ref.Digest != digest {
+		return "", fmt.Errorf("%w: artifact has digest '%s', not '%s'", ErrDigestMismatch, digest, ref.Digest)
+	}
+
+	response, err := c.do(ctx, http.MethodPut, ref.url("manifests/"+manifestRef), scope, map[string]string{"Content-Type": ManifestMediaType}, manifestContents)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	if response.StatusCode != http.StatusCreated {
+		return "", responseError(response)
+	}
+
+	return digest, nil
+}
+
+// Pull fetches the artifact identified by the reference from the registry,
+// checking that it's of the expected type. The digest of the artifact's
+// manifest is also returned.
+func (c *Client) Pull(ctx context.Context, ref *Reference, artifactType string) (*Artifact, string, error) {
+	scope := fmt.Sprintf("repository:%s:pull", ref.Repository)
+
+	manifestContents, err := c.fetch(ctx, ref.url("manifests/"+ref.manifestReference()), scope, ManifestMediaType, maxManifestSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	digest := computeDigest(manifestContents)
+	if ref.Digest != "" && ref.Digest != digest {
+		return nil, "", fmt.Errorf("%w: manifest has digest '%s', not '%s'", ErrDigestMismatch, digest, ref.Digest)
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(manifestContents, manifest); err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+
+	// Registries that predate artifactType store the type as the config's
+	// media type instead
+	manifestArtifactType := manifest.ArtifactType
+	if manifestArtifactType == "" {
+		manifestArtifactType = manifest.Config.MediaType
+	}
+	if manifestArtifactType != artifactType {
+		return nil, "", fmt.Errorf("%w: expected '%s', got '%s'", ErrUnexpectedArtifactType, artifactType, manifestArtifactType)
+	}
+	if len(manifest.Layers) != 1 {
+		return nil, "", fmt.Errorf("%w: expected one layer, found %d", ErrInvalidManifest, len(manifest.Layers))
+	}
+
+	layer := manifest.Layers[0]
+	if layer.Size > maxBlobSize {
+		return nil, "", fmt.Errorf("%w: layer is larger than %d bytes", ErrInvalidManifest, maxBlobSize)
+	}
+
+	contents, err := c.fetch(ctx, ref.url("blobs/"+layer.Digest), scope, "", maxBlobSize)
+	if err != nil {
+		return nil, "", err
+	}
+	if computeDigest(contents) != layer.Digest {
+		return nil, "", fmt.Errorf("%w: layer '%s'", ErrDigestMismatch, layer.Digest)
+	}
+
+	return &Artifact{
+		ArtifactType: manifestArtifactType,
+		MediaType:    layer.MediaType,
+		Contents:     contents,
+		Annotations:  manifest.Annotations,
+	}, digest, nil
+}
+
+// uploadBlob uploads the blob using a monolithic upload, unless the registry
+// already has it.
+func (c *Client) uploadBlob(ctx context.Context, ref *Reference, scope, digest string, contents []byte) error {
+	response, err := c.do(ctx, http.MethodHead, ref.url("blobs/"+digest), scope, nil, nil)
+	if err != nil {
+		return err
+	}
+	response.Body.Close() //nolint:errcheck
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	response, err = c.do(ctx, http.MethodPost, ref.url("blobs/uploads/"), scope, nil, nil)
+	if err != nil {
+		return err
+	}
+	response.Body.Close() //nolint:errcheck
+	if response.StatusCode != http.StatusAccepted {
+		return responseError(response)
+	}
+
+	location, err := response.Request.URL.Parse(response.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("%w: invalid upload location: %w", ErrUnexpectedResponse, err)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	response, err = c.do(ctx, http.MethodPut, location.String(), scope, map[string]string{"Content-Type": "application/octet-stream"}, contents)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	if response.StatusCode != http.StatusCreated {
+		return responseError(response)
+	}
+
+	return nil
+}
+
+func (c *Client) fetch(ctx context.Context, requestURL, scope, accept string, maxSize int64) ([]byte, error) {
+	headers := map[string]string{}
+	if accept != "" {
+		headers["Accept"] = accept
+	}
+
+	response, err := c.do(ctx, http.MethodGet, requestURL, scope, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	if response.StatusCode != http.StatusOK {
+		return nil, responseError(response)
+	}
+
+	contents, err := io.ReadAll(io.LimitReader(response.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(contents)) > maxSize {
+		return nil, fmt.Errorf("%w: response is larger than %d bytes", ErrUnexpectedResponse, maxSize)
+	}
+
+	return contents, nil
+}
+
+// do sends the request, authenticating with the registry's token service and
+// retrying if the registry requests a bearer token for the scope.
+func (c *Client) do(ctx context.Context, method, requestURL, scope string, headers map[string]string, body []byte) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		request, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		if token := c.token(scope); token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		} else if c.username != "" {
+			request.SetBasicAuth(c.username, c.password)
+		}
+
+		return c.httpClient.Do(request)
+	}
+
+	response, err := send()
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusUnauthorized {
+		return response, nil
+	}
+
+	challengeType, params := parseChallenge(response.Header.Get("WWW-Authenticate"))
+	response.Body.Close() //nolint:errcheck
+	if challengeType != bearerChallengeType || params["realm"] == "" {
+		return nil, fmt.Errorf("%w: authentication failed for '%s'", ErrUnexpectedResponse, requestURL)
+	}
+
+	if err := c.requestToken(ctx, params["realm"], params["service"], scope); err != nil {
+		return nil, err
+	}
+
+	return send()
+}
+
+func (c *Client) token(scope string) string {
+	c.tokensMu.Lock()
+	defer c.tokensMu.Unlock()
+
+	return c.tokens[scope]
+}
+
+// requestToken fetches a bearer token for the scope from the registry's token
+// service.
+func (c *Client) requestToken(ctx context.Context, realm, service, scope string) error {
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return fmt.Errorf("%w: invalid token realm: %w", ErrUnexpectedResponse, err)
+	}
+	query := tokenURL.Query()
+	if service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	if c.username != "" {
+		request.SetBasicAuth(c.username, c.password)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	if response.StatusCode != http.StatusOK {
+		return responseError(response)
+	}
+
+	tokenResponse := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&tokenResponse); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnexpectedResponse, err)
+	}
+
+	token := tokenResponse.Token
+	if token == "" {
+		token = tokenResponse.AccessToken
+	}
+	if token == "" {
+		return fmt.Errorf("%w: token service did not return a token", ErrUnexpectedResponse)
+	}
+
+	c.tokensMu.Lock()
+	defer c.tokensMu.Unlock()
+	c.tokens[scope] = token
+
+	return nil
+}
+
+// url returns the URL of the registry API endpoint for the reference's
+// repository. Registries on the loopback interface are accessed over plain
+// HTTP, all others must use HTTPS.
+func (r *Reference) url(endpoint string) string {
+	host := r.Registry
+	if host == dockerHubRegistry {
+		host = dockerHubAPIHost
+	}
+
+	scheme := "https"
+	if isLoopback(host) {
+		scheme = "http"
+	}
+
+	return fmt.Sprintf("%s://%s/v2/%s/%s", scheme, host, r.Repository, endpoint)
+}
+
+func isLoopback(host string) bool {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// parseChallenge parses the WWW-Authenticate header, returning the lowercased
+// challenge type and its parameters.
+func parseChallenge(header string) (string, map[string]string) {
+	challengeType, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := map[string]string{}
+
+	for rest = strings.TrimSpace(rest); rest != ""; {
+		key, value, found := strings.Cut(rest, "=")
+		if !found {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end == -1 {
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = value[end+2:]
+		} else {
+			value, rest, _ = strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(value)
+		}
+
+		rest = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(rest), ","))
+	}
+
+	return strings.ToLower(challengeType), params
+}
+
+func newDescriptor(mediaType string, contents []byte) Descriptor {
+	return Descriptor{
+		MediaType: mediaType,
+		Digest:    computeDigest(contents),
+		Size:      int64(len(contents)),
+	}
+}
+
+func computeDigest(contents []byte) string {
+	hash := sha256.Sum256(contents)
+	return sha256DigestPrefix + hex.EncodeToString(hash[:])
+}
+
+func responseError(response *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		message = response.Status
+	}
+
+	return fmt.Errorf("%w: %s %s: %s", ErrUnexpectedResponse, response.Request.Method, response.Request.URL.Redacted(), message)
+}
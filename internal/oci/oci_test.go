@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testArtifactType = "application/vnd.gittuf.test.v1+json"
+
+// fakeRegistry is an in-memory registry implementing the parts of the OCI
+// distribution spec used by the client. If token is set, requests must use it
+// as a bearer token, which is issued by the registry's token endpoint.
+type fakeRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	token     string
+	scopes    []string
+}
+
+func newFakeRegistry(t *testing.T, token string) (*fakeRegistry, string) {
+	t.Helper()
+
+	registry := &fakeRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}, token: token}
+	server := httptest.NewServer(registry)
+	t.Cleanup(server.Close)
+
+	return registry, strings.TrimPrefix(server.URL, "http://")
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		f.scopes = append(f.scopes, r.URL.Query().Get("scope"))
+		fmt.Fprintf(w, `{"token": "%s"}`, f.token)
+		return
+	}
+
+	if f.token != "" && r.Header.Get("Authorization") != "Bearer "+f.token {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="fake"`, r.Host))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/gittuf/policy/")
+	switch {
+	case r.Method == http.MethodPost && path == "blobs/uploads/":
+		w.Header().Set("Location", "/v2/gittuf/policy/blobs/uploads/session")
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodPut && path == "blobs/uploads/session":
+		contents, _ := io.ReadAll(r.Body)
+		hash := sha256.Sum256(contents)
+		if digest := "sha256:" + hex.EncodeToString(hash[:]); digest != r.URL.Query().Get("digest") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.blobs[r.URL.Query().Get("digest")] = contents
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasPrefix(path, "blobs/"):
+		contents, has := f.blobs[strings.TrimPrefix(path, "blobs/")]
+		if !has {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(contents) //nolint:errcheck
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "manifests/"):
+		contents, _ := io.ReadAll(r.Body)
+		f.manifests[strings.TrimPrefix(path, "manifests/")] = contents
+		f.manifests[computeDigest(contents)] = contents
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "manifests/"):
+		contents, has := f.manifests[strings.TrimPrefix(path, "manifests/")]
+		if !has {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ManifestMediaType)
+		w.Write(contents) //nolint:errcheck
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestClient(t *testing.T) {
+	artifact := &Artifact{
+		ArtifactType: testArtifactType,
+		MediaType:    "application/json",
+		Contents:     []byte(`{"version": 1}`),
+		Annotations:  map[string]string{"org.opencontainers.image.source": "https://github.com/gittuf/gittuf"},
+	}
+
+	t.Run("push and pull", func(t *testing.T) {
+		_, host := newFakeRegistry(t, "")
+		ref, err := ParseReference(host + "/gittuf/policy:v1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client := NewClient("", "")
+		digest, err := client.Push(context.Background(), ref, artifact)
+		assert.Nil(t, err)
+
+		pulled, pulledDigest, err := client.Pull(context.Background(), ref, testArtifactType)
+		assert.Nil(t, err)
+		assert.Equal(t, digest, pulledDigest)
+		assert.Equal(t, artifact, pulled)
+
+		pulled, _, err = client.Pull(context.Background(), ref.WithDigest(digest), testArtifactType)
+		assert.Nil(t, err)
+		assert.Equal(t, artifact.Contents, pulled.Contents)
+	})
+
+	t.Run("token authentication", func(t *testing.T) {
+		registry, host := newFakeRegistry(t, "registry-token")
+		ref, err := ParseReference(host + "/gittuf/policy")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client := NewClient("", "")
+		_, err = client.Push(context.Background(), ref, artifact)
+		assert.Nil(t, err)
+
+		_, _, err = client.Pull(context.Background(), ref, testArtifactType)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"repository:gittuf/policy:pull,push", "repository:gittuf/policy:pull"}, registry.scopes)
+	})
+
+	t.Run("unexpected artifact type", func(t *testing.T) {
+		_, host := newFakeRegistry(t, "")
+		ref, err := ParseReference(host + "/gittuf/policy")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client := NewClient("", "")
+		if _, err := client.Push(context.Background(), ref, artifact); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, err = client.Pull(context.Background(), ref, "application/vnd.example")
+		assert.ErrorIs(t, err, ErrUnexpectedArtifactType)
+	})
+
+	t.Run("missing artifact", func(t *testing.T) {
+		_, host := newFakeRegistry(t, "")
+		ref, err := ParseReference(host + "/gittuf/policy")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, err = NewClient("", "").Pull(context.Background(), ref, testArtifactType)
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+}
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	tests := map[string]struct {
+		reference         string
+		expectedReference *Reference
+		expectedError     error
+	}{
+		"tag": {
+			reference:         "ghcr.io/gittuf/policy:v1",
+			expectedReference: &Reference{Registry: "ghcr.io", Repository: "gittuf/policy", Tag: "v1"},
+		},
+		"default tag": {
+			reference:         "localhost:5000/policy",
+			expectedReference: &Reference{Registry: "localhost:5000", Repository: "policy", Tag: "latest"},
+		},
+		"digest": {
+			reference:         "ghcr.io/gittuf/policy@" + digest,
+			expectedReference: &Reference{Registry: "ghcr.io", Repository: "gittuf/policy", Digest: digest},
+		},
+		"docker hub": {
+			reference:         "docker.io/policy:v1",
+			expectedReference: &Reference{Registry: "docker.io", Repository: "library/policy", Tag: "v1"},
+		},
+		"missing registry": {
+			reference:     "gittuf/policy:v1",
+			expectedError: ErrInvalidReference,
+		},
+		"invalid digest": {
+			reference:     "ghcr.io/gittuf/policy@sha256:abc",
+			expectedError: ErrInvalidReference,
+		},
+		"uppercase repository": {
+			reference:     "ghcr.io/gittuf/Policy",
+			expectedError: ErrInvalidReference,
+		},
+	}
+
+	for name, test := range tests {
+		ref, err := ParseReference(test.reference)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+			assert.Equal(t, test.expectedReference, ref, fmt.Sprintf("unexpected reference in test '%s'", name))
+		}
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	challengeType, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/policy:pull,push"`)
+	assert.Equal(t, "bearer", challengeType)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/policy:pull,push",
+	}, params)
+
+	challengeType, params = parseChallenge(`Basic realm=registry`)
+	assert.Equal(t, "basic", challengeType)
+	assert.Equal(t, map[string]string{"realm": "registry"}, params)
+}
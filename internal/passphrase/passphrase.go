@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package passphrase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/interactive"
+	"golang.org/x/term"
+)
+
+const (
+	// EnvKey is the environment variable used to provide the passphrase of
+	// encrypted private keys instead of prompting for it, such as in CI.
+	EnvKey = "GITTUF_KEY_PASSPHRASE"
+
+	// maxAttempts is the number of times the user is prompted for the
+	// passphrase of a key before giving up.
+	maxAttempts = 3
+)
+
+var (
+	ErrPassphraseRequired  = errors.New("private key is encrypted, its passphrase must be set in GITTUF_KEY_PASSPHRASE or using --passphrase-file")
+	ErrIncorrectPassphrase = errors.New("incorrect passphrase for private key")
+)
+
+var (
+	passphraseFile string
+
+	// entered records the passphrases entered at the prompt for each key, so
+	// that the user is prompted once per key even if it's used many times.
+	entered   = map[string][]byte{}
+	enteredMu sync.Mutex
+
+	// The terminal is accessed using these so that tests can replace it.
+	isTerminal             = func() bool { return term.IsTerminal(int(os.Stdin.Fd())) }
+	readPassword           = func() ([]byte, error) { return term.ReadPassword(int(os.Stdin.Fd())) }
+	promptOutput io.Writer = os.Stderr
+)
+
+// SetFile sets the file that the passphrase of encrypted private keys is read
+// from, unless it's set in EnvKey. A trailing newline in the file is ignored.
+func SetFile(path string) {
+	passphraseFile = path
+}
+
+// Decrypt obtains the passphrase of the encrypted private key identified by
+// the description and calls decrypt with it. The passphrase is read from
+// EnvKey or from the file set using SetFile, and otherwise the user is
+// prompted for it on the terminal, where they may try again as long as
+// decrypt returns ErrIncorrectPassphrase. ErrPassphraseRequired is returned
+// if the passphrase isn't set and the user can't be prompted, either because
+// there's no terminal or because gittuf is in non-interactive mode.
+func Decrypt(description string, decrypt func(passphrase []byte) error) error {
+	if passphrase, has := os.LookupEnv(EnvKey); has {
+		return decrypt([]byte(passphrase))
+	}
+
+	if passphraseFile != "" {
+		contents, err := os.ReadFile(passphraseFile)
+		if err != nil {
+			return fmt.Errorf("unable to read passphrase file: %w", err)
+		}
+
+		contents = bytes.TrimSuffix(contents, []byte("\n"))
+		contents = bytes.TrimSuffix(contents, []byte("\r"))
+		return decrypt(contents)
+	}
+
+	enteredMu.Lock()
+	defer enteredMu.Unlock()
+
+	if passphrase, has := entered[description]; has {
+		return decrypt(passphrase)
+	}
+
+	if interactive.InNonInteractiveMode() {
+		return errors.Join(ErrPassphraseRequired, interactive.ErrInteractionRequired)
+	}
+	if !isTerminal() {
+		return ErrPassphraseRequired
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		fmt.Fprintf(promptOutput, "Enter passphrase for %s: ", description)
+		passphrase, readErr := readPassword()
+		fmt.Fprintln(promptOutput)
+		if readErr != nil {
+			return readErr
+		}
+
+		err = decrypt(passphrase)
+		if err == nil {
+			entered[description] = passphrase
+			return nil
+		}
+		if !errors.Is(err, ErrIncorrectPassphrase) {
+			return err
+		}
+	}
+
+	return err
+}
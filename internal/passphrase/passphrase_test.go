@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package passphrase
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/interactive"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecrypt(t *testing.T) {
+	// decryptWith returns a decrypt function that accepts the passphrase and
+	// records the passphrases it's called with
+	decryptWith := func(expected string, calls *[]string) func([]byte) error {
+		return func(passphrase []byte) error {
+			*calls = append(*calls, string(passphrase))
+			if string(passphrase) != expected {
+				return ErrIncorrectPassphrase
+			}
+			return nil
+		}
+	}
+
+	// useTerminal replaces the terminal with one where the passphrases are
+	// entered in order
+	useTerminal := func(t *testing.T, passphrases ...string) *bytes.Buffer {
+		t.Helper()
+
+		originalIsTerminal, originalReadPassword, originalPromptOutput := isTerminal, readPassword, promptOutput
+		t.Cleanup(func() {
+			isTerminal, readPassword, promptOutput = originalIsTerminal, originalReadPassword, originalPromptOutput
+			entered = map[string][]byte{}
+		})
+
+		output := &bytes.Buffer{}
+		isTerminal = func() bool { return true }
+		readPassword = func() ([]byte, error) {
+			if len(passphrases) == 0 {
+				return nil, errors.New("no more input")
+			}
+			passphrase := passphrases[0]
+			passphrases = passphrases[1:]
+			return []byte(passphrase), nil
+		}
+		promptOutput = output
+
+		return output
+	}
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(EnvKey, "secret")
+
+		calls := []string{}
+		err := Decrypt("test key", decryptWith("secret", &calls))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"secret"}, calls)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "passphrase")
+		if err := os.WriteFile(path, []byte("secret\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		SetFile(path)
+		t.Cleanup(func() { SetFile("") })
+
+		calls := []string{}
+		err := Decrypt("test key", decryptWith("secret", &calls))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"secret"}, calls)
+
+		// An incorrect passphrase in the file is not retried
+		calls = []string{}
+		err = Decrypt("test key", decryptWith("other", &calls))
+		assert.ErrorIs(t, err, ErrIncorrectPassphrase)
+		assert.Len(t, calls, 1)
+	})
+
+	t.Run("prompt", func(t *testing.T) {
+		output := useTerminal(t, "wrong", "secret")
+
+		calls := []string{}
+		err := Decrypt("test key", decryptWith("secret", &calls))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"wrong", "secret"}, calls)
+		assert.Contains(t, output.String(), "Enter passphrase for test key: ")
+
+		// The passphrase entered is reused for the same key
+		calls = []string{}
+		err = Decrypt("test key", decryptWith("secret", &calls))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"secret"}, calls)
+	})
+
+	t.Run("prompt, too many attempts", func(t *testing.T) {
+		useTerminal(t, "wrong", "wrong", "wrong", "secret")
+
+		calls := []string{}
+		err := Decrypt("test key", decryptWith("secret", &calls))
+		assert.ErrorIs(t, err, ErrIncorrectPassphrase)
+		assert.Len(t, calls, maxAttempts)
+	})
+
+	t.Run("no terminal", func(t *testing.T) {
+		useTerminal(t)
+		isTerminal = func() bool { return false }
+
+		err := Decrypt("test key", decryptWith("secret", &[]string{}))
+		assert.ErrorIs(t, err, ErrPassphraseRequired)
+	})
+
+	t.Run("non-interactive mode", func(t *testing.T) {
+		useTerminal(t, "secret")
+		t.Setenv(interactive.NonInteractiveModeKey, "1")
+
+		err := Decrypt("test key", decryptWith("secret", &[]string{}))
+		assert.ErrorIs(t, err, ErrPassphraseRequired)
+		assert.ErrorIs(t, err, interactive.ErrInteractionRequired)
+	})
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+)
+
+const ruleEvaluatorMethodEvaluate = "evaluate"
+
+// Evaluation describes a change to a reference protected by a rule that
+// requires a rule evaluator plugin.
+type Evaluation struct {
+	// GitDir is the GIT_DIR of the repository the change was made in.
+	GitDir string `json:"git_dir"`
+
+	// RuleName is the name of the rule that requires the evaluator.
+	RuleName string `json:"rule_name"`
+
+	// RefName is the reference that was changed.
+	RefName string `json:"ref_name"`
+
+	// FromID is the ID the reference pointed to before the change. It is
+	// empty if the reference was created.
+	FromID string `json:"from_id,omitempty"`
+
+	// TargetID is the ID the reference points to after the change.
+	TargetID string `json:"target_id"`
+}
+
+// EvaluationResult is the result of a rule evaluator plugin's evaluation of a
+// change.
+type EvaluationResult struct {
+	// Allowed is true if the change passes the evaluator's checks.
+	Allowed bool `json:"allowed"`
+
+	// Reason explains why the change failed the checks.
+	Reason string `json:"reason,omitempty"`
+}
+
+// RuleEvaluator is backed by a rule evaluator plugin, which performs checks in
+// addition to gittuf's for changes protected by rules that require it, such as
+// checking the change against an external approval system. Rule evaluator
+// plugins implement the `evaluate` method, which takes an Evaluation and
+// returns an EvaluationResult.
+type RuleEvaluator struct {
+	plugin *Plugin
+}
+
+// LoadRuleEvaluator returns a RuleEvaluator for the rule evaluator plugin of
+// the specified name.
+func LoadRuleEvaluator(name string) (*RuleEvaluator, error) {
+	plugin, err := Find(KindRuleEvaluator, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RuleEvaluator{plugin: plugin}, nil
+}
+
+// Evaluate requests the plugin's evaluation of the change.
+func (e *RuleEvaluator) Evaluate(ctx context.Context, evaluation *Evaluation) (*EvaluationResult, error) {
+	result := &EvaluationResult{}
+	if err := e.plugin.Call(ctx, ruleEvaluatorMethodEvaluate, evaluation, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package plugin implements the extension points that let downstream users
+// extend gittuf without forking it. A plugin is an executable on the PATH named
+// `gittuf-<kind>-<name>`, such as `gittuf-signer-kms`, that gittuf invokes for
+// each request. The request is written to the plugin's stdin as a JSON object,
+// and the plugin writes its response to stdout as a JSON object, similar to
+// Git's credential helpers. Anything the plugin writes to stderr is passed
+// through to the user.
+//
+// Requests have the form:
+//
+//	{"protocol_version": 1, "kind": "signer", "method": "sign", "params": {...}}
+//
+// and responses have the form:
+//
+//	{"protocol_version": 1, "result": {...}}
+//
+// or, if the request failed:
+//
+//	{"protocol_version": 1, "error": "..."}
+//
+// The methods and their params and results are defined for each kind of
+// plugin. The protocol version is incremented for changes that aren't
+// backwards compatible, and plugins that respond using a different version are
+// rejected.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ProtocolVersion is the version of the protocol gittuf uses to communicate
+// with plugins.
+const ProtocolVersion = 1
+
+// Prefix is the prefix of the name of a plugin executable.
+const Prefix = "gittuf-"
+
+// Kind identifies an extension point, which determines the methods a plugin
+// must implement.
+type Kind string
+
+const (
+	// KindSigner plugins sign gittuf metadata and attestations using keys
+	// gittuf can't access directly, such as keys in a KMS or an HSM. See
+	// Signer.
+	KindSigner Kind = "signer"
+
+	// KindRuleEvaluator plugins perform additional checks for changes
+	// protected by a rule that requires them. See RuleEvaluator.
+	KindRuleEvaluator Kind = "rule-evaluator"
+
+	// KindPredicate plugins validate the predicates of attestations of a
+	// custom predicate type. See PredicateValidator.
+	KindPredicate Kind = "predicate"
+
+	// KindStorage plugins store the objects, references, and config of a
+	// repository in place of the Git binary. See Storage.
+	KindStorage Kind = "storage"
+)
+
+var (
+	ErrPluginNotFound             = errors.New("plugin not found")
+	ErrInvalidPluginName          = errors.New("invalid plugin name")
+	ErrUnsupportedProtocolVersion = errors.New("plugin uses an unsupported protocol version")
+	ErrPluginFailed               = errors.New("plugin failed")
+	ErrUnexpectedPluginResponse   = errors.New("unexpected response from plugin")
+)
+
+// Plugin is an executable that implements an extension point.
+type Plugin struct {
+	Kind Kind
+	Name string
+	Path string
+}
+
+// Find returns the plugin of the specified kind and name, looking up its
+// executable on the PATH.
+func Find(kind Kind, name string) (*Plugin, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, "-") {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidPluginName, name)
+	}
+
+	path, err := exec.LookPath(ExecutableName(kind, name))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s plugin '%s'", ErrPluginNotFound, kind, name)
+	}
+
+	return &Plugin{Kind: kind, Name: name, Path: path}, nil
+}
+
+// ExecutableName returns the name of the executable of the plugin of the
+// specified kind and name.
+func ExecutableName(kind Kind, name string) string {
+	return Prefix + string(kind) + "-" + name
+}
+
+type request struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	Kind            Kind   `json:"kind"`
+	Method          string `json:"method"`
+	Params          any    `json:"params,omitempty"`
+}
+
+type response struct {
+	ProtocolVersion int             `json:"protocol_version"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	Error           string          `json:"error,omitempty"`
+}
+
+// Call invokes the method of the plugin with the params, and unmarshals the
+// result of the method into result, which may be nil if the method doesn't
+// return a result.
+func (p *Plugin) Call(ctx context.Context, method string, params, result any) error {
+	input, err := json.Marshal(&request{
+		ProtocolVersion: ProtocolVersion,
+		Kind:            p.Kind,
+		Method:          method,
+		Params:          params,
+	})
+	if err != nil {
+		return err
+	}
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Path) //nolint:gosec
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &output
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s plugin '%s' (method '%s'): %w", ErrPluginFailed, p.Kind, p.Name, method, err)
+	}
+
+	resp := &response{}
+	if err := json.Unmarshal(output.Bytes(), resp); err != nil {
+		return fmt.Errorf("%w: %s plugin '%s' (method '%s'): %w", ErrUnexpectedPluginResponse, p.Kind, p.Name, method, err)
+	}
+
+	if resp.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("%w: %s plugin '%s' uses version %d, expected version %d", ErrUnsupportedProtocolVersion, p.Kind, p.Name, resp.ProtocolVersion, ProtocolVersion)
+	}
+
+	if resp.Error != "" {
+		return fmt.Errorf("%w: %s plugin '%s' (method '%s'): %s", ErrPluginFailed, p.Kind, p.Name, method, resp.Error)
+	}
+
+	if result == nil {
+		return nil
+	}
+	if len(resp.Result) == 0 {
+		return fmt.Errorf("%w: %s plugin '%s' (method '%s') returned no result", ErrUnexpectedPluginResponse, p.Kind, p.Name, method)
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("%w: %s plugin '%s' (method '%s'): %w", ErrUnexpectedPluginResponse, p.Kind, p.Name, method, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFind(t *testing.T) {
+	pluginDir := t.TempDir()
+	writePlugin(t, pluginDir, KindSigner, "kms", "#!/bin/sh\n")
+	t.Setenv("PATH", pluginDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	plugin, err := Find(KindSigner, "kms")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(pluginDir, "gittuf-signer-kms"), plugin.Path)
+
+	_, err = Find(KindRuleEvaluator, "kms")
+	assert.ErrorIs(t, err, ErrPluginNotFound)
+
+	for _, name := range []string{"", "../kms", "-kms"} {
+		_, err = Find(KindSigner, name)
+		assert.ErrorIs(t, err, ErrInvalidPluginName)
+	}
+}
+
+func TestCall(t *testing.T) {
+	pluginDir := t.TempDir()
+	writePlugin(t, pluginDir, KindRuleEvaluator, "echo", "#!/bin/sh\necho \"{\\\"protocol_version\\\": 1, \\\"result\\\": $(cat)}\"\n")
+	writePlugin(t, pluginDir, KindRuleEvaluator, "error", "#!/bin/sh\ncat > /dev/null\necho '{\"protocol_version\": 1, \"error\": \"no approval\"}'\n")
+	writePlugin(t, pluginDir, KindRuleEvaluator, "old", "#!/bin/sh\ncat > /dev/null\necho '{\"protocol_version\": 0, \"result\": {}}'\n")
+	writePlugin(t, pluginDir, KindRuleEvaluator, "invalid", "#!/bin/sh\ncat > /dev/null\necho 'not json'\n")
+	writePlugin(t, pluginDir, KindRuleEvaluator, "fail", "#!/bin/sh\ncat > /dev/null\nexit 3\n")
+	t.Setenv("PATH", pluginDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	t.Run("successful call", func(t *testing.T) {
+		plugin, err := Find(KindRuleEvaluator, "echo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result := &request{}
+		err = plugin.Call(context.Background(), "evaluate", map[string]string{"rule_name": "protect-main"}, result)
+		assert.Nil(t, err)
+		assert.Equal(t, ProtocolVersion, result.ProtocolVersion)
+		assert.Equal(t, KindRuleEvaluator, result.Kind)
+		assert.Equal(t, "evaluate", result.Method)
+		assert.Equal(t, map[string]any{"rule_name": "protect-main"}, result.Params)
+	})
+
+	tests := map[string]struct {
+		name          string
+		expectedError error
+	}{
+		"plugin returns error": {
+			name:          "error",
+			expectedError: ErrPluginFailed,
+		},
+		"plugin uses different protocol version": {
+			name:          "old",
+			expectedError: ErrUnsupportedProtocolVersion,
+		},
+		"plugin returns invalid response": {
+			name:          "invalid",
+			expectedError: ErrUnexpectedPluginResponse,
+		},
+		"plugin exits with error": {
+			name:          "fail",
+			expectedError: ErrPluginFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			plugin, err := Find(KindRuleEvaluator, test.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = plugin.Call(context.Background(), "evaluate", nil, &EvaluationResult{})
+			assert.ErrorIs(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestRuleEvaluator(t *testing.T) {
+	pluginDir := t.TempDir()
+	// The evaluator only allows changes to the main branch
+	writePlugin(t, pluginDir, KindRuleEvaluator, "main-only", `#!/bin/sh
+if grep -q '"ref_name":"refs/heads/main"'; then
+	echo '{"protocol_version": 1, "result": {"allowed": true}}'
+else
+	echo '{"protocol_version": 1, "result": {"allowed": false, "reason": "not main"}}'
+fi
+`)
+	t.Setenv("PATH", pluginDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	evaluator, err := LoadRuleEvaluator("main-only")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := evaluator.Evaluate(context.Background(), &Evaluation{RuleName: "protect-main", RefName: "refs/heads/main", TargetID: "abc"})
+	assert.Nil(t, err)
+	assert.True(t, result.Allowed)
+
+	result, err = evaluator.Evaluate(context.Background(), &Evaluation{RuleName: "protect-main", RefName: "refs/heads/feature", TargetID: "abc"})
+	assert.Nil(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, "not main", result.Reason)
+}
+
+func TestPredicateValidator(t *testing.T) {
+	pluginDir := t.TempDir()
+	writePlugin(t, pluginDir, KindPredicate, "valid", "#!/bin/sh\ncat > /dev/null\necho '{\"protocol_version\": 1, \"result\": {\"valid\": true}}'\n")
+	t.Setenv("PATH", pluginDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	validator, err := LoadPredicateValidator("valid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := validator.Validate(context.Background(), []byte(`{"predicate": {}}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid)
+
+	_, err = LoadPredicateValidator("unknown")
+	assert.ErrorIs(t, err, ErrPluginNotFound)
+}
+
+func writePlugin(t *testing.T, dir string, kind Kind, name, contents string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, ExecutableName(kind, name)), []byte(contents), 0o755); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const predicateMethodValidate = "validate"
+
+type predicateValidateParams struct {
+	Statement json.RawMessage `json:"statement"`
+}
+
+// ValidationResult is the result of a predicate plugin's validation of an
+// attestation.
+type ValidationResult struct {
+	// Valid is true if the attestation's predicate is valid.
+	Valid bool `json:"valid"`
+
+	// Reason explains why the predicate is invalid.
+	Reason string `json:"reason,omitempty"`
+}
+
+// PredicateValidator is backed by a predicate plugin, which validates the
+// contents of attestations of a custom predicate type, beyond the signature
+// checks gittuf performs using the predicate policy. Predicate plugins
+// implement the `validate` method, which takes `{"statement": <statement>}`,
+// where the statement is the in-toto statement of the attestation, and returns
+// a ValidationResult.
+type PredicateValidator struct {
+	plugin *Plugin
+}
+
+// LoadPredicateValidator returns a PredicateValidator for the predicate plugin
+// of the specified name.
+func LoadPredicateValidator(name string) (*PredicateValidator, error) {
+	plugin, err := Find(KindPredicate, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PredicateValidator{plugin: plugin}, nil
+}
+
+// Validate requests the plugin's validation of the in-toto statement.
+func (v *PredicateValidator) Validate(ctx context.Context, statement []byte) (*ValidationResult, error) {
+	result := &ValidationResult{}
+	if err := v.plugin.Call(ctx, predicateMethodValidate, &predicateValidateParams{Statement: statement}, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
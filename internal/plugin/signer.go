@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	signerMethodPublicKey = "public-key"
+	signerMethodSign      = "sign"
+)
+
+type signerPublicKeyResult struct {
+	PublicKey string `json:"public_key"`
+}
+
+type signerSignParams struct {
+	Data []byte `json:"data"`
+}
+
+type signerSignResult struct {
+	Signature []byte `json:"signature"`
+}
+
+// Signer is a dsse.SignerVerifier backed by a signer plugin. Signer plugins
+// implement two methods:
+//
+//   - `public-key`, which takes no params and returns `{"public_key": <key>}`,
+//     where the key is the PEM encoded public key, and
+//   - `sign`, which takes `{"data": <base64 data>}` and returns
+//     `{"signature": <base64 signature>}`.
+//
+// The public key must be an ED25519, ECDSA, or RSA key, so that signatures
+// created using the plugin can be verified without it.
+type Signer struct {
+	plugin   *Plugin
+	key      *tuf.Key
+	verifier dsse.Verifier
+}
+
+// LoadSigner returns a Signer for the signer plugin of the specified name,
+// requesting its public key.
+func LoadSigner(ctx context.Context, name string) (*Signer, error) {
+	plugin, err := Find(KindSigner, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &signerPublicKeyResult{}
+	if err := plugin.Call(ctx, signerMethodPublicKey, nil, result); err != nil {
+		return nil, err
+	}
+
+	key, err := tuf.LoadKeyFromBytes([]byte(result.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("%w: signer plugin '%s' returned invalid public key: %w", ErrUnexpectedPluginResponse, name, err)
+	}
+
+	verifier, err := signerverifier.NewSignerVerifierFromTUFKey(key) //nolint:staticcheck
+	if err != nil {
+		return nil, fmt.Errorf("unable to use public key of signer plugin '%s': %w", name, err)
+	}
+
+	return &Signer{plugin: plugin, key: key, verifier: verifier}, nil
+}
+
+// Sign signs the data using the plugin.
+func (s *Signer) Sign(ctx context.Context, data []byte) ([]byte, error) {
+	result := &signerSignResult{}
+	if err := s.plugin.Call(ctx, signerMethodSign, &signerSignParams{Data: data}, result); err != nil {
+		return nil, err
+	}
+
+	return result.Signature, nil
+}
+
+// Verify verifies the signature using the plugin's public key.
+func (s *Signer) Verify(ctx context.Context, data, sig []byte) error {
+	return s.verifier.Verify(ctx, data, sig)
+}
+
+// KeyID returns the ID of the plugin's public key.
+func (s *Signer) KeyID() (string, error) {
+	return s.key.KeyID, nil
+}
+
+// Public returns the plugin's public key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.verifier.Public()
+}
+
+// PublicKey returns the plugin's public key as used in gittuf metadata.
+func (s *Signer) PublicKey() *tuf.Key {
+	return s.key
+}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"os"
+)
+
+// StorageEnvKey is set to the name of the storage plugin gittuf uses to read
+// and write the objects, references, and config of repositories, in place of
+// the Git binary.
+const StorageEnvKey = "GITTUF_STORAGE_PLUGIN"
+
+const (
+	storageMethodReadObject   = "read-object"
+	storageMethodHasObject    = "has-object"
+	storageMethodWriteObject  = "write-object"
+	storageMethodGetReference = "get-reference"
+	storageMethodSetReference = "set-reference"
+	storageMethodGetConfig    = "get-config"
+	storageMethodSetConfig    = "set-config"
+)
+
+type storageParams struct {
+	GitDir   string `json:"git_dir"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Contents []byte `json:"contents,omitempty"`
+	RefName  string `json:"ref_name,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+type storageObjectResult struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Contents []byte `json:"contents"`
+}
+
+type storageHasObjectResult struct {
+	Exists bool `json:"exists"`
+}
+
+type storageConfigResult struct {
+	Config map[string][]string `json:"config"`
+}
+
+// Storage is backed by a storage plugin, which stores the objects, references,
+// and config of repositories, such as in a database or an object store.
+// Storage plugins implement the following methods, each of which takes the
+// `git_dir` of the repository in its params:
+//
+//   - `read-object`, which takes the object's `id` and returns its `type` and
+//     base64 `contents`, or an empty `type` if the object doesn't exist,
+//   - `has-object`, which takes the object's `id` and returns `exists`,
+//   - `write-object`, which takes the object's `type` and base64 `contents`
+//     and returns its `id`,
+//   - `get-reference`, which takes the `ref_name` and returns the `id` it
+//     points to, or an empty `id` if the reference doesn't exist,
+//   - `set-reference`, which takes the `ref_name` and the `id` to point it to,
+//   - `get-config`, which returns the `config` as a map of each key to its
+//     values in increasing order of precedence, and
+//   - `set-config`, which takes the `key` and `value` to set.
+type Storage struct {
+	plugin *Plugin
+	gitDir string
+}
+
+// LoadStorage returns a Storage for the repository at gitDir using the storage
+// plugin of the specified name.
+func LoadStorage(name, gitDir string) (*Storage, error) {
+	plugin, err := Find(KindStorage, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Storage{plugin: plugin, gitDir: gitDir}, nil
+}
+
+// GetStoragePluginName returns the name of the storage plugin set using
+// StorageEnvKey, or an empty string if no storage plugin is set.
+func GetStoragePluginName() string {
+	return os.Getenv(StorageEnvKey)
+}
+
+// ReadObject returns the type and contents of the object. The type is empty if
+// the object doesn't exist.
+func (s *Storage) ReadObject(ctx context.Context, objectID string) (string, []byte, error) {
+	result := &storageObjectResult{}
+	if err := s.plugin.Call(ctx, storageMethodReadObject, &storageParams{GitDir: s.gitDir, ID: objectID}, result); err != nil {
+		return "", nil, err
+	}
+
+	return result.Type, result.Contents, nil
+}
+
+// HasObject returns true if the object exists.
+func (s *Storage) HasObject(ctx context.Context, objectID string) (bool, error) {
+	result := &storageHasObjectResult{}
+	if err := s.plugin.Call(ctx, storageMethodHasObject, &storageParams{GitDir: s.gitDir, ID: objectID}, result); err != nil {
+		return false, err
+	}
+
+	return result.Exists, nil
+}
+
+// WriteObject writes an object of the specified type with the contents and
+// returns its ID.
+func (s *Storage) WriteObject(ctx context.Context, objectType string, contents []byte) (string, error) {
+	result := &storageObjectResult{}
+	if err := s.plugin.Call(ctx, storageMethodWriteObject, &storageParams{GitDir: s.gitDir, Type: objectType, Contents: contents}, result); err != nil {
+		return "", err
+	}
+
+	return result.ID, nil
+}
+
+// GetReference returns the ID of the object the reference points to. The ID is
+// empty if the reference doesn't exist.
+func (s *Storage) GetReference(ctx context.Context, refName string) (string, error) {
+	result := &storageObjectResult{}
+	if err := s.plugin.Call(ctx, storageMethodGetReference, &storageParams{GitDir: s.gitDir, RefName: refName}, result); err != nil {
+		return "", err
+	}
+
+	return result.ID, nil
+}
+
+// SetReference updates the reference to point to the object.
+func (s *Storage) SetReference(ctx context.Context, refName, objectID string) error {
+	return s.plugin.Call(ctx, storageMethodSetReference, &storageParams{GitDir: s.gitDir, RefName: refName, ID: objectID}, nil)
+}
+
+// GetConfig returns all the values of each key in the repository's config.
+func (s *Storage) GetConfig(ctx context.Context) (map[string][]string, error) {
+	result := &storageConfigResult{}
+	if err := s.plugin.Call(ctx, storageMethodGetConfig, &storageParams{GitDir: s.gitDir}, result); err != nil {
+		return nil, err
+	}
+
+	return result.Config, nil
+}
+
+// SetConfig sets the key to the value in the repository's config.
+func (s *Storage) SetConfig(ctx context.Context, key, value string) error {
+	return s.plugin.Call(ctx, storageMethodSetConfig, &storageParams{GitDir: s.gitDir, Key: key, Value: value}, nil)
+}
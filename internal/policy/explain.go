@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/report"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+type explanationContextKey struct{}
+
+// Explanation records how verification reached its decision for each RSL
+// entry that failed verification. It is used to explain failures to users in
+// more detail than the returned errors. A nil Explanation is valid and records
+// nothing.
+type Explanation struct {
+	// FailedEntries contains the RSL entries that failed verification, in the
+	// order they were verified.
+	FailedEntries []*EntryExplanation `json:"failed_entries"`
+
+	// ShallowAnchor identifies the RSL entry verification started after when
+	// the repository is a shallow clone. It is nil if verification started
+	// from the first entry in the RSL.
+	ShallowAnchor *AnchorExplanation `json:"shallow_anchor,omitempty"`
+}
+
+// AnchorExplanation identifies an RSL entry verification of a ref started
+// after, as the commits introduced until the entry aren't available locally.
+type AnchorExplanation struct {
+	RefName  string `json:"ref_name"`
+	EntryID  string `json:"entry_id"`
+	TargetID string `json:"target_id"`
+}
+
+// EntryExplanation records the checks performed to verify an RSL entry.
+type EntryExplanation struct {
+	RefName  string `json:"ref_name"`
+	EntryID  string `json:"entry_id"`
+	TargetID string `json:"target_id"`
+
+	allowedSigners *gitinterface.AllowedSigners
+
+	// Checks contains the signature checks performed for the entry, in the
+	// order they were performed.
+	Checks []*CheckExplanation `json:"checks"`
+
+	// Error is the error that caused the entry to fail verification.
+	Error string `json:"error"`
+}
+
+// CheckExplanation records the verification of a Git object's signatures
+// against the rules that protect a namespace.
+type CheckExplanation struct {
+	// Namespace is the protected namespace that was checked, such as
+	// "git:refs/heads/main" or "file:README.md".
+	Namespace string `json:"namespace"`
+
+	// ObjectID identifies the commit or tag whose signatures were checked.
+	ObjectID string `json:"object_id"`
+
+	// Signature describes the signature found on the Git object.
+	Signature string `json:"signature"`
+
+	// SignatureKeyID is the ID of the key that verified the Git object's
+	// signature, if any of the rules' keys did.
+	SignatureKeyID string `json:"signature_key_id,omitempty"`
+
+	// Principals contains the identities the key that issued the SSH
+	// signature is allowed to sign as, if verification used an allowed
+	// signers file.
+	Principals []string `json:"principals,omitempty"`
+
+	// AttestationKeyIDs contains the key IDs of the signatures on the
+	// reference authorization found for the change, if any.
+	AttestationKeyIDs []string `json:"attestation_key_ids,omitempty"`
+
+	// Rules contains the rules that protect the namespace, in the order they
+	// were evaluated. Evaluation stops at the first rule whose conditions are
+	// met.
+	Rules []*RuleExplanation `json:"rules"`
+
+	kind            string
+	signature       []byte
+	signatureFormat string
+	signedAt        time.Time
+}
+
+// RuleExplanation records the evaluation of a single rule.
+type RuleExplanation struct {
+	Name      string   `json:"name"`
+	KeyIDs    []string `json:"key_ids"`
+	Threshold int      `json:"threshold"`
+
+	// Rejection explains why the rule's conditions weren't met. It is empty if
+	// the rule's conditions were met.
+	Rejection string `json:"rejection,omitempty"`
+}
+
+// ContextWithExplanation returns a copy of the context that carries the
+// specified explanation. Verification workflows record their decisions in the
+// explanation.
+func ContextWithExplanation(ctx context.Context, explanation *Explanation) context.Context {
+	return context.WithValue(ctx, explanationContextKey{}, explanation)
+}
+
+// ExplanationFromContext returns the explanation carried by the context, if
+// any.
+func ExplanationFromContext(ctx context.Context) *Explanation {
+	explanation, ok := ctx.Value(explanationContextKey{}).(*Explanation)
+	if !ok {
+		return nil
+	}
+
+	return explanation
+}
+
+// Verified indicates if the Git object's signatures met the conditions of one
+// of the rules protecting the namespace. A namespace that isn't protected by
+// any rules is always verified.
+func (c *CheckExplanation) Verified() bool {
+	if len(c.Rules) == 0 {
+		return true
+	}
+
+	return c.Rules[len(c.Rules)-1].Rejection == ""
+}
+
+// startEntry begins the explanation for the specified entry. The returned
+// EntryExplanation must be passed to finishEntry once verification of the
+// entry completes. If allowedSigners is set, the principals of the SSH
+// signatures checked for the entry are recorded.
+func (e *Explanation) startEntry(entry *rsl.ReferenceEntry, allowedSigners *gitinterface.AllowedSigners) *EntryExplanation {
+	if e == nil {
+		return nil
+	}
+
+	return newEntryExplanation(entry, allowedSigners)
+}
+
+// newEntryExplanation returns an EntryExplanation for the entry that isn't
+// recorded in an Explanation, which is used to report the signatures checked
+// for the entry.
+func newEntryExplanation(entry *rsl.ReferenceEntry, allowedSigners *gitinterface.AllowedSigners) *EntryExplanation {
+	return &EntryExplanation{
+		RefName:        entry.RefName,
+		EntryID:        entry.ID.String(),
+		TargetID:       entry.TargetID.String(),
+		allowedSigners: allowedSigners,
+	}
+}
+
+// finishEntry records the entry's explanation if the entry failed
+// verification.
+func (e *Explanation) finishEntry(entryExplanation *EntryExplanation, err error) {
+	if e == nil || entryExplanation == nil || err == nil {
+		return
+	}
+
+	entryExplanation.Error = err.Error()
+	e.FailedEntries = append(e.FailedEntries, entryExplanation)
+}
+
+// setShallowAnchor records the entry verification started after in a shallow
+// clone.
+func (e *Explanation) setShallowAnchor(entry *rsl.ReferenceEntry) {
+	if e == nil {
+		return
+	}
+
+	e.ShallowAnchor = &AnchorExplanation{
+		RefName:  entry.RefName,
+		EntryID:  entry.ID.String(),
+		TargetID: entry.TargetID.String(),
+	}
+}
+
+// addCheck records a check of the Git object's signatures and of the
+// signatures on the reference authorization against the rules for the
+// namespace.
+func (e *EntryExplanation) addCheck(namespace string, gitObject object.Object, env *sslibdsse.Envelope) *CheckExplanation {
+	if e == nil {
+		return nil
+	}
+
+	check := &CheckExplanation{Namespace: namespace}
+
+	switch o := gitObject.(type) {
+	case *object.Commit:
+		check.ObjectID = o.Hash.String()
+		check.Signature = gitinterface.DescribeSignature(o.PGPSignature)
+		check.kind = report.KindCommit
+		if check.ObjectID == e.EntryID {
+			check.kind = report.KindRSLEntry
+		}
+		check.signature = []byte(o.PGPSignature)
+		check.signatureFormat = gitinterface.GetSignatureFormat(check.signature)
+		check.signedAt = o.Committer.When
+		if e.allowedSigners != nil {
+			check.Principals = e.allowedSigners.FindPrincipalsForSignature([]byte(o.PGPSignature), o.Committer.When)
+		}
+	case *object.Tag:
+		check.ObjectID = o.Hash.String()
+		check.Signature = gitinterface.DescribeSignature(o.PGPSignature)
+		check.kind = report.KindTag
+		check.signature = []byte(o.PGPSignature)
+		check.signatureFormat = gitinterface.GetSignatureFormat(check.signature)
+		check.signedAt = o.Tagger.When
+		if e.allowedSigners != nil {
+			check.Principals = e.allowedSigners.FindPrincipalsForSignature([]byte(o.PGPSignature), o.Tagger.When)
+		}
+	}
+
+	if env != nil {
+		for _, signature := range env.Signatures {
+			check.AttestationKeyIDs = append(check.AttestationKeyIDs, signature.KeyID)
+		}
+	}
+
+	e.Checks = append(e.Checks, check)
+	return check
+}
+
+// addRule records the result of evaluating the verifier's rule. keyID is the ID
+// of the rule's key that verified the Git object's signature, if any.
+func (c *CheckExplanation) addRule(verifier *Verifier, keyID string, err error) {
+	if c == nil {
+		return
+	}
+
+	if keyID != "" && c.SignatureKeyID == "" {
+		c.SignatureKeyID = keyID
+		for _, key := range verifier.Keys() {
+			if key.KeyID != keyID {
+				continue
+			}
+
+			// The key tells Sigstore and S/MIME signatures apart
+			if method, err := gitinterface.GetSignatureMethod(key, c.signature); err == nil {
+				c.signatureFormat = method.Format
+			}
+			break
+		}
+	}
+
+	rule := &RuleExplanation{
+		Name:      verifier.Name(),
+		KeyIDs:    make([]string, 0, len(verifier.Keys())),
+		Threshold: verifier.Threshold(),
+	}
+	for _, key := range verifier.Keys() {
+		rule.KeyIDs = append(rule.KeyIDs, key.KeyID)
+	}
+
+	if err != nil {
+		rule.Rejection = err.Error()
+		if errors.Is(err, ErrVerifierConditionsUnmet) {
+			// The reason follows the sentinel error's message
+			rule.Rejection = strings.TrimPrefix(rule.Rejection, ErrVerifierConditionsUnmet.Error()+": ")
+		}
+	}
+
+	c.Rules = append(c.Rules, rule)
+}
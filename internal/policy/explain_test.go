@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplanation(t *testing.T) {
+	refName := "refs/heads/main"
+
+	t.Run("successful verification", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		explanation := &Explanation{}
+		ctx := ContextWithExplanation(context.Background(), explanation)
+
+		err := verifyEntry(ctx, repo, state, nil, entry)
+		assert.Nil(t, err)
+		assert.Empty(t, explanation.FailedEntries)
+	})
+
+	t.Run("unmet threshold", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
+
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		approverKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		explanation := &Explanation{}
+		ctx := ContextWithExplanation(context.Background(), explanation)
+
+		err = verifyEntry(ctx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		if !assert.Len(t, explanation.FailedEntries, 1) {
+			return
+		}
+		entryExplanation := explanation.FailedEntries[0]
+		assert.Equal(t, refName, entryExplanation.RefName)
+		assert.Equal(t, entryID.String(), entryExplanation.EntryID)
+		assert.Equal(t, commitIDs[0].String(), entryExplanation.TargetID)
+		assert.Equal(t, err.Error(), entryExplanation.Error)
+
+		if !assert.Len(t, entryExplanation.Checks, 1) {
+			return
+		}
+		check := entryExplanation.Checks[0]
+		assert.False(t, check.Verified())
+		assert.Equal(t, "git:refs/heads/main", check.Namespace)
+		assert.Equal(t, entryID.String(), check.ObjectID)
+		assert.Equal(t, "GPG signature by key "+gpgKey.KeyID, check.Signature)
+		assert.Empty(t, check.AttestationKeyIDs)
+		assert.Equal(t, []*RuleExplanation{{
+			Name:      "protect-main",
+			KeyIDs:    []string{gpgKey.KeyID, approverKey.KeyID},
+			Threshold: 2,
+			Rejection: "threshold is 2 but no reference authorization was found, so at most one signature is available",
+		}}, check.Rules)
+	})
+
+	t.Run("no explanation in context", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(context.Background(), repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+		assert.Nil(t, ExplanationFromContext(context.Background()))
+	})
+}
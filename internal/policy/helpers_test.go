@@ -3,15 +3,30 @@
 package policy
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
+	"github.com/github/smimesign/ietf-cms/oid"
+	"github.com/github/smimesign/ietf-cms/protocol"
+	tsp "github.com/github/smimesign/ietf-cms/timestamp"
 	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/timestamp"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
@@ -32,6 +47,8 @@ var (
 	gpgUnauthorizedKeyBytes = artifacts.GPGKey2Private
 )
 
+const testCodeReviewPredicateType = "https://gittuf.dev/code-review/v0.1"
+
 func createTestRepository(t *testing.T, stateCreator func(*testing.T) *State) (*git.Repository, *State) {
 	t.Helper()
 
@@ -52,7 +69,7 @@ func createTestRepository(t *testing.T, stateCreator func(*testing.T) *State) (*
 		t.Fatal(err)
 	}
 
-	if err := state.Commit(repo, "Create test state", false); err != nil {
+	if err := state.Commit(testCtx, repo, "Create test state", false); err != nil {
 		t.Fatal(err)
 	}
 	if err := Apply(testCtx, repo, false); err != nil {
@@ -306,6 +323,165 @@ func createTestStateWithThresholdPolicy(t *testing.T) *State {
 	return state
 }
 
+func createTestStateWithRequiredHooks(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetRequiredHooks(targetsMetadata, "protect-main", []string{"secret-scan"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	return state
+}
+
+func createTestStateWithRequiredPredicates(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	reviewerKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetRequiredPredicates(targetsMetadata, "protect-main", []string{testCodeReviewPredicateType})
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = SetPredicatePolicy(targetsMetadata, testCodeReviewPredicateType, []*tuf.Key{reviewerKey}, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	return state
+}
+
+func createTestStateWithRequiredApprovals(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	reviewerKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetRequiredApprovals(targetsMetadata, "protect-main", "protect-main", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = SetPredicatePolicy(targetsMetadata, attestations.ReviewApprovalPredicateType, []*tuf.Key{reviewerKey}, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	return state
+}
+
+func createTestStateWithSubmodulePolicy(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-lib", []*tuf.Key{gpgKey}, []string{"submodule:lib"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = SetRequireVerifiedSubmodule(targetsMetadata, "protect-lib", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	if err := state.loadRuleNames(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
 func createTestStateWithTagPolicy(t *testing.T) *State {
 	t.Helper()
 
@@ -344,6 +520,49 @@ func createTestStateWithTagPolicy(t *testing.T) *State {
 	return state
 }
 
+func createTestStateWithRequiredRebuilds(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithTagPolicy(t)
+
+	rebuilder1Key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilder2Key, err := tuf.LoadKeyFromBytes(targets2PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = SetRequiredRebuilds(targetsMetadata, "protect-tags", []string{"gittuf-linux-amd64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = SetPredicatePolicy(targetsMetadata, attestations.RebuildPredicateType, []*tuf.Key{rebuilder1Key, rebuilder2Key}, 2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	return state
+}
+
 func createTestStateWithTagPolicyForUnauthorizedTest(t *testing.T) *State {
 	t.Helper()
 
@@ -381,3 +600,159 @@ func createTestStateWithTagPolicyForUnauthorizedTest(t *testing.T) *State {
 
 	return state
 }
+
+func createTestStateWithThresholdTagPolicy(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approverKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-tags", []*tuf.Key{gpgKey, approverKey}, []string{"git:refs/tags/*"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	if err := state.loadRuleNames(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+// testTimestampAuthority issues RFC 3161 timestamp tokens for signatures at
+// arbitrary times, so that tests can create timestamps for past or future
+// times.
+type testTimestampAuthority struct {
+	certificate *x509.Certificate
+	privateKey  *ecdsa.PrivateKey
+}
+
+func newTestTimestampAuthority(t *testing.T) *testTimestampAuthority {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gittuf test TSA"},
+		NotBefore:             time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(certificateBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testTimestampAuthority{certificate: certificate, privateKey: privateKey}
+}
+
+func (a *testTimestampAuthority) certificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: a.certificate.Raw})
+}
+
+// timestamp returns a timestamp for the signature issued at genTime.
+func (a *testTimestampAuthority) timestamp(t *testing.T, signature []byte, genTime time.Time) *timestamp.Timestamp {
+	t.Helper()
+
+	messageImprint, err := tsp.NewMessageImprint(crypto.SHA256, bytes.NewReader(signature))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	infoBytes, err := asn1.Marshal(tsp.Info{
+		Version:        1,
+		Policy:         asn1.ObjectIdentifier{1, 2, 3},
+		SerialNumber:   big.NewInt(1),
+		GenTime:        genTime,
+		MessageImprint: messageImprint,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eci, err := protocol.NewEncapsulatedContentInfo(oid.ContentTypeTSTInfo, infoBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedData, err := protocol.NewSignedData(eci)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := signedData.AddSignerInfo([]*x509.Certificate{a.certificate}, a.privateKey); err != nil {
+		t.Fatal(err)
+	}
+	contentInfo, err := signedData.ContentInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := asn1.Marshal(contentInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &timestamp.Timestamp{RFC3161Token: token}
+}
+
+// trustTimestampAuthority adds the timestamp authority to the state's root
+// metadata.
+func trustTimestampAuthority(t *testing.T, state *State, authority *testTimestampAuthority) {
+	t.Helper()
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = AddTimestampAuthority(rootMetadata, authority.certificatePEM())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(context.Background(), rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.RootEnvelope = rootEnv
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/lfs"
+	"github.com/go-git/go-git/v5"
+)
+
+var ErrLFSObjectsNotAttested = errors.New("Git LFS objects do not match the objects attested for the commit") //nolint:stylecheck
+
+// VerifyLFSObjectsAttestation checks the Git LFS objects attestation recorded
+// for the commit, if any. The attestation must be signed by a threshold of the
+// keys in the predicate policy for Git LFS objects attestations, and must list
+// exactly the specified pointers. Commits without an attestation are not
+// checked.
+func VerifyLFSObjectsAttestation(ctx context.Context, repo *git.Repository, commitID string, pointers []*lfs.Pointer) error {
+	attestationsState, err := attestations.LoadCurrentAttestations(repo)
+	if err != nil {
+		return err
+	}
+
+	env, err := attestationsState.GetLFSObjectsAttestationFor(repo, commitID)
+	if err != nil {
+		if errors.Is(err, attestations.ErrLFSObjectsNotFound) {
+			logger.Debug(fmt.Sprintf("No Git LFS objects attestation found for '%s'", commitID))
+			return nil
+		}
+		return err
+	}
+
+	state, err := LoadCurrentState(ctx, repo, PolicyRef)
+	if err != nil {
+		return err
+	}
+
+	verifier, err := state.FindVerifierForPredicateType(attestations.LFSObjectsPredicateType)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyRekorInclusion(ctx, repo, attestationsState, env); err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Verifying Git LFS objects attestation for '%s'...", commitID))
+	if err := verifier.Verify(ctx, nil, env); err != nil {
+		return err
+	}
+
+	attested, err := attestations.GetLFSObjects(env)
+	if err != nil {
+		return err
+	}
+
+	if len(attested.Objects) != len(pointers) {
+		return ErrLFSObjectsNotAttested
+	}
+	for i, pointer := range pointers {
+		if attested.Objects[i] == nil || *attested.Objects[i] != *pointer {
+			return fmt.Errorf("%w: '%s'", ErrLFSObjectsNotAttested, pointer.Path)
+		}
+	}
+
+	return nil
+}
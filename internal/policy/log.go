@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/identity"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// LogEntry describes a change to a Git reference recorded in the RSL, along
+// with the commits it introduced and the principals who authorized it under
+// the policy applicable at the time.
+type LogEntry struct {
+	RSLEntry *rsl.ReferenceEntry
+
+	// Skipped indicates if the entry was marked as skipped by an annotation.
+	Skipped bool
+
+	// Commits lists the commits introduced by the entry, latest first.
+	Commits []*object.Commit
+
+	// Signer is the ID of the key that signed the RSL entry. It is empty if
+	// the entry isn't signed by a key trusted in the policy.
+	Signer string
+
+	// Approvers lists the IDs of the trusted keys that signed a reference
+	// authorization for the change.
+	Approvers []string
+
+	// Rule is the name of the rule that authorized the change. It is empty if
+	// the reference isn't protected by a rule or if no rule's requirements
+	// were met.
+	Rule string
+
+	// VerificationError is set if the entry fails verification.
+	VerificationError error
+}
+
+// Log returns the history of the target ref as recorded in the RSL, starting
+// with the latest entry. Each entry is verified using the policy and
+// attestations applicable when it was created.
+func Log(ctx context.Context, repo *git.Repository, target string) ([]*LogEntry, error) {
+	firstEntry, _, err := rsl.GetFirstReferenceEntryForRef(repo, target)
+	if err != nil {
+		return nil, err
+	}
+	lastEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, target)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, annotations, err := rsl.GetReferenceEntriesInRangeForRef(repo, firstEntry.ID, lastEntry.ID, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		policyStates      = map[plumbing.Hash]*State{}
+		attestationStates = map[plumbing.Hash]*attestations.Attestations{}
+		log               = []*LogEntry{}
+	)
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.RefName != target {
+			// The range includes gittuf namespace entries
+			continue
+		}
+
+		logEntry, _, err := newLogEntry(ctx, repo, entry, annotations[entry.ID], policyStates, attestationStates)
+		if err != nil {
+			return nil, err
+		}
+		log = append(log, logEntry)
+	}
+
+	return log, nil
+}
+
+// LogForSigner returns the RSL entries attributable to the signer, starting
+// with the latest entry. The signer is matched against the IDs of the keys
+// trusted in the policy applicable when each entry was created, and against
+// the identities of trusted Sigstore keys. An entry is attributable to the
+// signer if it was signed or approved by the signer, or if it introduced
+// commits signed by the signer. Only the commits signed by the signer are
+// included in each entry's Commits. If target is set, only the entries for the
+// target ref are searched, otherwise the entries for all refs outside the
+// gittuf namespace are searched.
+func LogForSigner(ctx context.Context, repo *git.Repository, signer, target string) ([]*LogEntry, error) {
+	firstEntry, _, err := rsl.GetFirstEntry(repo)
+	if err != nil {
+		return nil, err
+	}
+	lastEntry, err := rsl.GetLatestEntry(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, annotations, err := rsl.GetReferenceEntriesInRange(repo, firstEntry.ID, lastEntry.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		policyStates      = map[plumbing.Hash]*State{}
+		attestationStates = map[plumbing.Hash]*attestations.Attestations{}
+		log               = []*LogEntry{}
+	)
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if strings.HasPrefix(entry.RefName, "refs/gittuf/") || (target != "" && entry.RefName != target) {
+			continue
+		}
+
+		logEntry, state, err := newLogEntry(ctx, repo, entry, annotations[entry.ID], policyStates, attestationStates)
+		if err != nil {
+			return nil, err
+		}
+		if state == nil {
+			// Without a policy, no keys are trusted
+			continue
+		}
+
+		signerKeys, err := findKeysForSigner(state, signer)
+		if err != nil {
+			return nil, err
+		}
+		if len(signerKeys) == 0 {
+			continue
+		}
+
+		attributable := false
+		for _, key := range signerKeys {
+			if logEntry.Signer == key.KeyID || slices.Contains(logEntry.Approvers, key.KeyID) {
+				attributable = true
+			}
+		}
+
+		signedCommits := []*object.Commit{}
+		for _, commit := range logEntry.Commits {
+			if commit.PGPSignature == "" {
+				continue
+			}
+
+			for _, key := range signerKeys {
+				if err := gitinterface.VerifyCommitSignature(ctx, commit, key); err == nil {
+					signedCommits = append(signedCommits, commit)
+					break
+				}
+			}
+		}
+		logEntry.Commits = signedCommits
+
+		if attributable || len(signedCommits) > 0 {
+			log = append(log, logEntry)
+		}
+	}
+
+	return log, nil
+}
+
+// newLogEntry creates the log entry for the RSL entry, verifying it using the
+// policy and attestations applicable when it was created. The policy state is
+// also returned, it is nil if no policy was applicable. The caches of policy
+// and attestations states are updated as they're loaded.
+func newLogEntry(ctx context.Context, repo *git.Repository, entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry, policyStates map[plumbing.Hash]*State, attestationStates map[plumbing.Hash]*attestations.Attestations) (*LogEntry, *State, error) {
+	logEntry := &LogEntry{
+		RSLEntry: entry,
+		Skipped:  entry.SkippedBy(annotations),
+	}
+
+	var err error
+	logEntry.Commits, err = getCommits(repo, entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(logEntry.Commits, func(i, j int) bool {
+		return logEntry.Commits[i].Committer.When.After(logEntry.Commits[j].Committer.When)
+	})
+
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, entry.ID)
+	if err != nil {
+		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil, nil, err
+		}
+
+		logEntry.VerificationError = ErrPolicyNotFound
+		return logEntry, nil, nil
+	}
+
+	state, has := policyStates[policyEntry.ID]
+	if !has {
+		state, err = LoadState(ctx, repo, policyEntry)
+		if err != nil {
+			return nil, nil, err
+		}
+		policyStates[policyEntry.ID] = state
+	}
+
+	var attestationsState *attestations.Attestations
+	attestationsEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, attestations.Ref, entry.ID)
+	if err == nil {
+		attestationsState, has = attestationStates[attestationsEntry.ID]
+		if !has {
+			attestationsState, err = attestations.LoadAttestationsForEntry(repo, attestationsEntry)
+			if err != nil {
+				return nil, nil, err
+			}
+			attestationStates[attestationsEntry.ID] = attestationsState
+		}
+	} else if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return nil, nil, err
+	}
+
+	logEntry.VerificationError = verifyEntry(ctx, repo, state, attestationsState, entry)
+
+	if err := identifyAuthorization(ctx, repo, state, attestationsState, logEntry); err != nil {
+		return nil, nil, err
+	}
+
+	return logEntry, state, nil
+}
+
+// findKeysForSigner returns the keys trusted in the policy state whose ID or
+// Sigstore identity matches the signer. Key IDs are compared case
+// insensitively, as GPG fingerprints are often written in upper case.
+func findKeysForSigner(state *State, signer string) ([]*tuf.Key, error) {
+	publicKeys, err := state.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []*tuf.Key{}
+	for _, key := range publicKeys {
+		if strings.EqualFold(key.KeyID, signer) || (key.KeyType == signerverifier.FulcioKeyType && identity.Normalize(key.KeyVal.Identity) == identity.Normalize(signer)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].KeyID < keys[j].KeyID
+	})
+
+	return keys, nil
+}
+
+// identifyAuthorization records the signer of the log entry's RSL entry, the
+// approvers of the change, and the rule that authorized it.
+func identifyAuthorization(ctx context.Context, repo *git.Repository, state *State, attestationsState *attestations.Attestations, logEntry *LogEntry) error {
+	entry := logEntry.RSLEntry
+
+	publicKeys, err := state.PublicKeys()
+	if err != nil {
+		return err
+	}
+	keys := make([]*tuf.Key, 0, len(publicKeys))
+	for _, key := range publicKeys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].KeyID < keys[j].KeyID
+	})
+
+	entryCommit, err := gitinterface.GetCommit(repo, entry.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := gitinterface.VerifyCommitSignature(ctx, entryCommit, key); err == nil {
+			logEntry.Signer = key.KeyID
+			break
+		}
+	}
+
+	var authorizationAttestation *sslibdsse.Envelope
+	if !strings.HasPrefix(entry.RefName, gitinterface.TagRefPrefix) {
+		authorizationAttestation, err = getAuthorizationAttestation(ctx, repo, state, attestationsState, entry, entryCommit)
+		if err != nil {
+			return err
+		}
+	}
+
+	if authorizationAttestation != nil {
+		for _, key := range keys {
+			verifier, err := signerverifier.NewSignerVerifierFromTUFKey(key) //nolint:staticcheck
+			if err != nil {
+				// Only keys that can sign DSSE envelopes are relevant
+				continue
+			}
+
+			if err := dsse.VerifyEnvelope(ctx, authorizationAttestation, []sslibdsse.Verifier{verifier}, 1); err == nil {
+				logEntry.Approvers = append(logEntry.Approvers, key.KeyID)
+			}
+		}
+	}
+
+	verifiers, err := state.FindVerifiersForPath(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName))
+	if err != nil {
+		return err
+	}
+
+	for _, verifier := range verifiers {
+		if err := verifier.Verify(ctx, entryCommit, authorizationAttestation); err == nil {
+			logEntry.Rule = verifier.Name()
+			break
+		} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authorizedCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, authorizedCommitIDs[1])
+	authorizedEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	// Policy violation
+	unauthorizedCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgUnauthorizedKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, unauthorizedCommitIDs[0])
+	unauthorizedEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgUnauthorizedKeyBytes)
+
+	log, err := Log(testCtx, repo, refName)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(log))
+
+	assert.Equal(t, unauthorizedEntryID, log[0].RSLEntry.ID)
+	assert.False(t, log[0].Skipped)
+	assert.Equal(t, 1, len(log[0].Commits))
+	assert.Equal(t, unauthorizedCommitIDs[0], log[0].Commits[0].Hash)
+	assert.Empty(t, log[0].Signer)
+	assert.Empty(t, log[0].Rule)
+	assert.ErrorIs(t, log[0].VerificationError, ErrUnauthorizedSignature)
+
+	assert.Equal(t, authorizedEntryID, log[1].RSLEntry.ID)
+	assert.ElementsMatch(t, authorizedCommitIDs, []plumbing.Hash{log[1].Commits[0].Hash, log[1].Commits[1].Hash})
+	assert.Equal(t, gpgKey.KeyID, log[1].Signer)
+	assert.Empty(t, log[1].Approvers)
+	assert.Equal(t, "protect-main", log[1].Rule)
+	assert.Nil(t, log[1].VerificationError)
+
+	_, err = Log(testCtx, repo, "refs/heads/unknown")
+	assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+}
+
+func TestLogForSigner(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authorizedCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, authorizedCommitIDs[1])
+	authorizedEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	unauthorizedCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgUnauthorizedKeyBytes)
+	entry = rsl.NewReferenceEntry(refName, unauthorizedCommitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgUnauthorizedKeyBytes)
+
+	t.Run("entries signed by key", func(t *testing.T) {
+		log, err := LogForSigner(testCtx, repo, gpgKey.KeyID, "")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(log))
+
+		assert.Equal(t, authorizedEntryID, log[0].RSLEntry.ID)
+		assert.Equal(t, gpgKey.KeyID, log[0].Signer)
+		assert.ElementsMatch(t, authorizedCommitIDs, []plumbing.Hash{log[0].Commits[0].Hash, log[0].Commits[1].Hash})
+	})
+
+	t.Run("key ID in upper case", func(t *testing.T) {
+		log, err := LogForSigner(testCtx, repo, strings.ToUpper(gpgKey.KeyID), refName)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(log))
+	})
+
+	t.Run("other ref", func(t *testing.T) {
+		log, err := LogForSigner(testCtx, repo, gpgKey.KeyID, "refs/heads/feature")
+		assert.Nil(t, err)
+		assert.Empty(t, log)
+	})
+
+	t.Run("unknown signer", func(t *testing.T) {
+		log, err := LogForSigner(testCtx, repo, "jane.doe@example.com", "")
+		assert.Nil(t, err)
+		assert.Empty(t, log)
+	})
+}
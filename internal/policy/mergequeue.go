@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/trace"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// MergeQueueRefPrefix is the prefix of the temporary branches GitHub's merge
+// queue creates to test pull requests before merging them, such as
+// refs/heads/gh-readonly-queue/main/pr-123-<head commit ID>.
+const MergeQueueRefPrefix = "refs/heads/gh-readonly-queue/"
+
+// maxMergeQueueCommits is the maximum number of merge queue commits a single
+// RSL entry may advance a branch by.
+const maxMergeQueueCommits = 100
+
+// IsMergeQueueRef indicates if the ref is a temporary branch created by a
+// merge queue. Such branches are created and deleted by the platform, so their
+// RSL entries are not verified against the policy.
+func IsMergeQueueRef(refName string) bool {
+	_, isMergeQueueRef := mergeQueueBaseRef(refName)
+	return isMergeQueueRef
+}
+
+// mergeQueueBaseRef returns the branch the merge queue ref's pull request is
+// merged into.
+func mergeQueueBaseRef(refName string) (string, bool) {
+	queue, found := strings.CutPrefix(refName, MergeQueueRefPrefix)
+	if !found {
+		return "", false
+	}
+
+	index := strings.LastIndex(queue, "/pr-")
+	if index <= 0 {
+		return "", false
+	}
+
+	return gitinterface.BranchRefPrefix + queue[:index], true
+}
+
+// verifyMergeQueueEntry verifies an entry that advanced the branch to commits
+// created by a merge queue. A merge queue combines pull requests with the
+// latest state of the branch, so the commits it creates don't match the
+// changes approved in reference authorizations when the branch moved after the
+// approvals. Instead, each commit between the branch's prior and new states
+// must have been recorded in the RSL for one of the queue's refs, and must
+// introduce exactly the changes of a reference authorization for the branch.
+// The commit's signature, typically by the merge queue's identity, and the
+// signatures on the reference authorization must then meet one of the
+// verifiers. The verifier met by the last commit is returned, or nil if the
+// entry isn't verified this way.
+func verifyMergeQueueEntry(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry, entryCommit *object.Commit, namespace string, verifiers []*Verifier, entryExplanation *EntryExplanation) (*Verifier, error) {
+	if attestationsState == nil || len(verifiers) == 0 {
+		return nil, nil
+	}
+
+	priorEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, entry.RefName, entry.ID)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	queueCommitIDs, err := findMergeQueueCommits(repo, entry)
+	if err != nil {
+		return nil, err
+	}
+	if len(queueCommitIDs) == 0 {
+		return nil, nil
+	}
+
+	commits := []*object.Commit{}
+	for commitID := entry.TargetID; commitID != priorEntry.TargetID; {
+		if len(commits) == maxMergeQueueCommits || !queueCommitIDs[commitID] {
+			return nil, nil
+		}
+
+		commit, err := gitinterface.GetCommit(repo, commitID)
+		if err != nil {
+			return nil, err
+		}
+		if len(commit.ParentHashes) == 0 {
+			return nil, nil
+		}
+
+		commits = append(commits, commit)
+		commitID = commit.ParentHashes[0]
+	}
+
+	logger.Debug(fmt.Sprintf("Verifying %d merge queue commit(s) for '%s'...", len(commits), entry.RefName))
+	var verifiedUsing *Verifier
+	for i := len(commits) - 1; i >= 0; i-- {
+		verifier, err := verifyMergeQueueCommit(ctx, repo, policy, attestationsState, entry, entryCommit, namespace, verifiers, commits[i], entryExplanation)
+		if err != nil || verifier == nil {
+			return nil, err
+		}
+		verifiedUsing = verifier
+	}
+
+	return verifiedUsing, nil
+}
+
+// findMergeQueueCommits returns the commits recorded in the RSL for the merge
+// queue refs of the entry's branch since the branch's entry before the prior
+// one, as queue commits may be created before the prior entry is recorded.
+func findMergeQueueCommits(repo *git.Repository, entry *rsl.ReferenceEntry) (map[plumbing.Hash]bool, error) {
+	queueCommitIDs := map[plumbing.Hash]bool{}
+	branchEntries := 0
+
+	var current rsl.Entry = entry
+	for {
+		parent, err := rsl.GetParentForEntry(repo, current)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				return queueCommitIDs, nil
+			}
+			return nil, err
+		}
+		current = parent
+
+		referenceEntry, isReferenceEntry := parent.(*rsl.ReferenceEntry)
+		if !isReferenceEntry {
+			continue
+		}
+
+		if referenceEntry.RefName == entry.RefName {
+			branchEntries++
+			if branchEntries == 2 {
+				return queueCommitIDs, nil
+			}
+			continue
+		}
+
+		if baseRef, isMergeQueueRef := mergeQueueBaseRef(referenceEntry.RefName); isMergeQueueRef && baseRef == entry.RefName {
+			queueCommitIDs[referenceEntry.TargetID] = true
+		}
+	}
+}
+
+// verifyMergeQueueCommit looks for a reference authorization for the branch
+// that approved the changes introduced by the merge queue commit, and checks
+// the commit and the authorization against the verifiers. The verifier that is
+// met is returned, or nil if none are.
+func verifyMergeQueueCommit(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry, entryCommit *object.Commit, namespace string, verifiers []*Verifier, commit *object.Commit, entryExplanation *EntryExplanation) (*Verifier, error) {
+	parent, err := gitinterface.GetCommit(repo, commit.ParentHashes[0])
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := getTreeChanges(repo, parent.TreeHash, commit.TreeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range attestationsState.ListReferenceAuthorizationsFor(entry.RefName) {
+		fromTreeID := plumbing.ZeroHash
+		if fromID := plumbing.NewHash(change.FromRevisionID); !fromID.IsZero() {
+			fromCommit, err := gitinterface.GetCommit(repo, fromID)
+			if err != nil {
+				// The approved change may be based on a commit that isn't in
+				// the repository, such as one that was rewritten
+				logger.Debug(fmt.Sprintf("Ignoring reference authorization from '%s': %s", change.FromRevisionID, err.Error()))
+				continue
+			}
+			fromTreeID = fromCommit.TreeHash
+		}
+
+		approvedChanges, err := getTreeChanges(repo, fromTreeID, plumbing.NewHash(change.TargetTreeID))
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Ignoring reference authorization for tree '%s': %s", change.TargetTreeID, err.Error()))
+			continue
+		}
+		if !maps.Equal(changes, approvedChanges) {
+			continue
+		}
+
+		authorization, err := findReferenceAuthorization(ctx, repo, policy, attestationsState, entry.RefName, change.FromRevisionID, change.TargetTreeID, entryCommit)
+		if err != nil {
+			return nil, err
+		}
+		if authorization == nil {
+			continue
+		}
+
+		check := entryExplanation.addCheck(namespace, commit, authorization)
+		for _, verifier := range verifiers {
+			keyID, err := verifier.verify(ctx, commit, authorization)
+			check.addRule(verifier, keyID, err)
+			traceRule(trace.TracerFromContext(ctx), entry, namespace, commit.Hash, verifier, err)
+			if err == nil {
+				return verifier, nil
+			} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// getTreeChanges returns the mode and blob of each path changed between the
+// two trees. Paths removed in the second tree are mapped to an empty entry. A
+// zero tree ID is treated as an empty tree.
+func getTreeChanges(repo *git.Repository, fromTreeID, toTreeID plumbing.Hash) (map[string]object.TreeEntry, error) {
+	var fromTree *object.Tree
+	if !fromTreeID.IsZero() {
+		var err error
+		fromTree, err = repo.TreeObject(fromTreeID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	toTree, err := repo.TreeObject(toTreeID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]object.TreeEntry{}
+	for _, change := range diff {
+		if change.To.Name == "" {
+			changes[change.From.Name] = object.TreeEntry{}
+			continue
+		}
+
+		changes[change.To.Name] = object.TreeEntry{Mode: change.To.TreeEntry.Mode, Hash: change.To.TreeEntry.Hash}
+	}
+
+	return changes, nil
+}
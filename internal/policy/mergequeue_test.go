@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMergeQueueRef(t *testing.T) {
+	tests := map[string]struct {
+		refName         string
+		expectedQueue   bool
+		expectedBaseRef string
+	}{
+		"merge queue ref": {
+			refName:         "refs/heads/gh-readonly-queue/main/pr-123-8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f",
+			expectedQueue:   true,
+			expectedBaseRef: "refs/heads/main",
+		},
+		"merge queue ref for nested branch": {
+			refName:         "refs/heads/gh-readonly-queue/release/v1/pr-7-8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f",
+			expectedQueue:   true,
+			expectedBaseRef: "refs/heads/release/v1",
+		},
+		"merge queue prefix without pull request": {
+			refName: "refs/heads/gh-readonly-queue/main",
+		},
+		"regular branch": {
+			refName: "refs/heads/main",
+		},
+	}
+
+	for name, test := range tests {
+		assert.Equal(t, test.expectedQueue, IsMergeQueueRef(test.refName), fmt.Sprintf("unexpected result in test '%s'", name))
+
+		baseRef, _ := mergeQueueBaseRef(test.refName)
+		assert.Equal(t, test.expectedBaseRef, baseRef, fmt.Sprintf("unexpected base ref in test '%s'", name))
+	}
+}
+
+func TestVerifyMergeQueueEntry(t *testing.T) {
+	refName := "refs/heads/main"
+	queueRefName := "refs/heads/gh-readonly-queue/main/pr-1-8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"
+
+	// setup creates a branch that was approved to gain a "feature" file, but
+	// moved on with an "other" file before the merge queue merged the pull
+	// request on top of it.
+	setup := func(t *testing.T, queueFiles map[string]string, recordQueueRef bool) (*git.Repository, *State, *rsl.ReferenceEntry) {
+		t.Helper()
+
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
+
+		baseCommitID := writeTestCommit(t, repo, map[string]string{"README": "gittuf"})
+		approvedTreeID := writeTestTree(t, repo, map[string]string{"README": "gittuf", "feature": "feature"})
+		otherCommitID := writeTestCommit(t, repo, map[string]string{"README": "gittuf", "other": "other"}, baseCommitID)
+
+		queueCommitID := writeTestCommit(t, repo, queueFiles, otherCommitID)
+
+		authorization, err := attestations.NewReferenceAuthorization(refName, baseCommitID.String(), approvedTreeID.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.SetReferenceAuthorization(repo, env, refName, baseCommitID.String(), approvedTreeID.String()); err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.Commit(repo, "Add authorization", false); err != nil {
+			t.Fatal(err)
+		}
+
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, baseCommitID), gpgKeyBytes)
+		if recordQueueRef {
+			common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(queueRefName, queueCommitID), gpgKeyBytes)
+		}
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, otherCommitID), gpgKeyBytes)
+
+		entry := rsl.NewReferenceEntry(refName, queueCommitID)
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		return repo, state, entry
+	}
+
+	t.Run("queue commit with approved changes", func(t *testing.T) {
+		repo, state, entry := setup(t, map[string]string{"README": "gittuf", "other": "other", "feature": "feature"}, true)
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		assert.Nil(t, err)
+	})
+
+	t.Run("queue commit with unapproved changes", func(t *testing.T) {
+		repo, state, entry := setup(t, map[string]string{"README": "changed", "other": "other", "feature": "feature"}, true)
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("commit not created by merge queue", func(t *testing.T) {
+		repo, state, entry := setup(t, map[string]string{"README": "gittuf", "other": "other", "feature": "feature"}, false)
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("merge queue ref is not verified", func(t *testing.T) {
+		repo, state, _ := setup(t, map[string]string{"README": "gittuf"}, true)
+
+		queueEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, queueRefName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = verifyEntry(testCtx, repo, state, nil, queueEntry)
+		assert.Nil(t, err)
+	})
+}
+
+func writeTestTree(t *testing.T, repo *git.Repository, files map[string]string) plumbing.Hash {
+	t.Helper()
+
+	entries := make([]object.TreeEntry, 0, len(files))
+	for name, contents := range files {
+		blobID, err := gitinterface.WriteBlob(repo, []byte(contents))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, object.TreeEntry{Name: name, Hash: blobID})
+	}
+
+	treeID, err := gitinterface.WriteTree(repo, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return treeID
+}
+
+func writeTestCommit(t *testing.T, repo *git.Repository, files map[string]string, parentIDs ...plumbing.Hash) plumbing.Hash {
+	t.Helper()
+
+	commit := gitinterface.CreateCommitObject(testGitConfig, writeTestTree(t, repo, files), parentIDs, "Test commit", testClock)
+	commit = common.SignTestCommit(t, repo, commit, gpgKeyBytes)
+
+	commitID, err := gitinterface.WriteCommit(repo, commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return commitID
+}
@@ -7,14 +7,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
+	"path"
 	"reflect"
 	"sort"
 	"strings"
 
+	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/compression"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -44,6 +48,7 @@ const (
 
 	gitReferenceRuleScheme = "git"
 	fileRuleScheme         = "file"
+	submoduleRuleScheme    = "submodule"
 )
 
 var (
@@ -57,8 +62,14 @@ var (
 	ErrDuplicatedRuleName         = errors.New("two rules with same name found in policy")
 	ErrUnableToMatchRootKeys      = errors.New("unable to match root public keys, gittuf policy is in a broken state")
 	ErrNotAncestor                = errors.New("cannot apply changes since policy is not an ancestor of the policy staging")
+	ErrPolicyJustificationMissing = errors.New("policy change is not accompanied by a justification")
+	ErrPolicyStateNotFound        = errors.New("requested policy state was never applied")
+	ErrPolicyStateAlreadyActive   = errors.New("requested policy state is already active")
 )
 
+// logger logs the messages of the policy subsystem.
+var logger = logging.For("policy")
+
 // InitializeNamespace creates a git ref for the policy. Initially, the entry
 // has a zero hash.
 func InitializeNamespace(repo *git.Repository) error {
@@ -89,6 +100,15 @@ type State struct {
 
 	verifiersCache map[string][]*Verifier
 	ruleNames      *set.Set[string]
+
+	// signatureCacheScope identifies the state's metadata in the signature
+	// cache. It's computed when first needed.
+	signatureCacheScope string
+
+	// repository is the repository the state was loaded from or committed
+	// to, which records the timestamps of signatures used to check the
+	// validity of keys.
+	repository *git.Repository
 }
 
 type DelegationWithDepth struct {
@@ -224,6 +244,25 @@ func (s *State) PublicKeys() (map[string]*tuf.Key, error) {
 	return allKeys, nil
 }
 
+// KeyLabels returns human readable labels for the public keys associated with
+// the state, indexed by key ID. Keys for which no label can be derived are
+// omitted.
+func (s *State) KeyLabels() (map[string]string, error) {
+	keys, err := s.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := map[string]string{}
+	for keyID, key := range keys {
+		if label := signerverifier.KeyLabel(key); label != "" {
+			labels[keyID] = label
+		}
+	}
+
+	return labels, nil
+}
+
 // FindPublicKeysForPath identifies the trusted keys for the path. If the path
 // protected in gittuf policy, the trusted keys are returned.
 //
@@ -251,7 +290,7 @@ func (s *State) FindPublicKeysForPath(ctx context.Context, path string) ([]*tuf.
 		delegation := delegationsQueue[0]
 		delegationsQueue = delegationsQueue[1:]
 
-		if delegation.Matches(path) {
+		if delegationMatches(&delegation, path) {
 			for _, keyID := range delegation.KeyIDs {
 				key := allPublicKeys[keyID]
 				trustedKeys = append(trustedKeys, key)
@@ -287,16 +326,66 @@ func (s *State) FindPublicKeysForPath(ctx context.Context, path string) ([]*tuf.
 	}
 }
 
+// FindVerifierForPredicateType identifies the verifier for attestations of the
+// specified predicate type using the predicate policies in the top level
+// Targets role. ErrPredicatePolicyNotFound is returned if the policy does not
+// specify which keys are trusted for the predicate type.
+func (s *State) FindVerifierForPredicateType(predicateType string) (*Verifier, error) {
+	predicatePolicy, targetsMetadata, err := s.findPredicatePolicy(predicateType)
+	if err != nil {
+		return nil, err
+	}
+
+	keyValidity, err := s.getKeyValidity()
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := &Verifier{
+		name:        predicateType,
+		keys:        make([]*tuf.Key, 0, len(predicatePolicy.KeyIDs)),
+		threshold:   predicatePolicy.Threshold,
+		keyValidity: keyValidity,
+	}
+	for _, keyID := range predicatePolicy.KeyIDs {
+		verifier.keys = append(verifier.keys, targetsMetadata.Delegations.Keys[keyID])
+	}
+
+	return verifier, nil
+}
+
+// findPredicatePolicy returns the policy for the predicate type in the top
+// level Targets role, along with the role's metadata.
+func (s *State) findPredicatePolicy(predicateType string) (*tuf.PredicatePolicy, *tuf.TargetsMetadata, error) {
+	if !s.HasTargetsRole(TargetsRoleName) {
+		// No policies exist
+		return nil, nil, ErrMetadataNotFound
+	}
+
+	targetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for index := range targetsMetadata.PredicatePolicies {
+		if targetsMetadata.PredicatePolicies[index].PredicateType == predicateType {
+			return &targetsMetadata.PredicatePolicies[index], targetsMetadata, nil
+		}
+	}
+
+	return nil, nil, ErrPredicatePolicyNotFound
+}
+
 // FindVerifiersForPath identifies the trusted set of verifiers for the
 // specified path. While walking the delegation graph for the path, signatures
 // for delegated metadata files are verified using the verifier context.
 func (s *State) FindVerifiersForPath(path string) ([]*Verifier, error) {
 	if s.verifiersCache == nil {
-		slog.Debug("Initializing path cache in policy...")
+		logger.Debug("Initializing path cache in policy...")
 		s.verifiersCache = map[string][]*Verifier{}
 	} else if verifiers, cacheHit := s.verifiersCache[path]; cacheHit {
 		// Cache hit for this path in this policy
-		slog.Debug(fmt.Sprintf("Found cached verifiers for path '%s'", path))
+		logger.Debug(fmt.Sprintf("Found cached verifiers for path '%s'", path))
 		return verifiers, nil
 	}
 
@@ -320,6 +409,11 @@ func (s *State) FindVerifiersForPath(path string) ([]*Verifier, error) {
 
 	seenRoles := map[string]bool{TargetsRoleName: true}
 
+	keyValidity, err := s.getKeyValidity()
+	if err != nil {
+		return nil, err
+	}
+
 	var currentDelegationGroup []tuf.Delegation
 	verifiers := []*Verifier{}
 	for {
@@ -331,6 +425,10 @@ func (s *State) FindVerifiersForPath(path string) ([]*Verifier, error) {
 		currentDelegationGroup = groupedDelegations[0]
 		groupedDelegations = groupedDelegations[1:]
 
+		// The approvers required by a rule are identified using another
+		// rule in the same group
+		allDelegationsInGroup := currentDelegationGroup
+
 		for {
 			if len(currentDelegationGroup) <= 1 {
 				// Only allow rule found in the current group
@@ -340,16 +438,32 @@ func (s *State) FindVerifiersForPath(path string) ([]*Verifier, error) {
 			delegation := currentDelegationGroup[0]
 			currentDelegationGroup = currentDelegationGroup[1:]
 
-			if delegation.Matches(path) {
+			if delegationMatches(&delegation, path) {
 				verifier := &Verifier{
-					name:      delegation.Name,
-					keys:      make([]*tuf.Key, 0, len(delegation.KeyIDs)),
-					threshold: delegation.Threshold,
+					name:                     delegation.Name,
+					keys:                     make([]*tuf.Key, 0, len(delegation.KeyIDs)),
+					threshold:                delegation.Threshold,
+					requiredHooks:            delegation.RequiredHooks,
+					requiredRebuilds:         delegation.RequiredRebuilds,
+					requiredEvaluators:       delegation.RequiredEvaluators,
+					requiredPredicates:       delegation.RequiredPredicates,
+					requireVerifiedSubmodule: delegation.RequireVerifiedSubmodule,
+					allowedSignatureMethods:  delegation.AllowedSignatureMethods,
+					requiredApprovals:        delegation.RequiredApprovals,
+					keyValidity:              keyValidity,
 				}
 				for _, keyID := range delegation.KeyIDs {
 					key := allPublicKeys[keyID]
 					verifier.keys = append(verifier.keys, key)
 				}
+				if delegation.RequiredApprovals != nil {
+					for _, approversDelegation := range allDelegationsInGroup {
+						if approversDelegation.Name == delegation.RequiredApprovals.Role {
+							verifier.approvers = approversDelegation.KeyIDs
+							break
+						}
+					}
+				}
 				verifiers = append(verifiers, verifier)
 
 				if _, seen := seenRoles[delegation.Name]; seen {
@@ -383,6 +497,24 @@ func (s *State) FindVerifiersForPath(path string) ([]*Verifier, error) {
 	}
 }
 
+// delegationMatches checks if any of the delegation's patterns match the path.
+// Gerrit change refs are also matched using the ref identifying their change,
+// so that a rule for `git:refs/changes/1234` protects every patch set of
+// change 1234, and a rule for `git:refs/changes/*` protects every change.
+func delegationMatches(delegation *tuf.Delegation, path string) bool {
+	if delegation.Matches(path) {
+		return true
+	}
+
+	refName, isRef := strings.CutPrefix(path, gitReferenceRuleScheme+":")
+	if !isRef {
+		return false
+	}
+
+	changeRef, isChangeRef := gitinterface.GerritChangeRef(refName)
+	return isChangeRef && delegation.Matches(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, changeRef))
+}
+
 // Verify verifies the contents of the State for internal consistency.
 // Specifically, it checks that the root keys in the root role match the ones
 // stored on disk in the state. Further, it also verifies the signatures of the
@@ -424,6 +556,11 @@ func (s *State) Verify(ctx context.Context) error {
 		return err
 	}
 
+	keyValidity, err := s.getKeyValidity()
+	if err != nil {
+		return err
+	}
+
 	reachedDelegations := map[string]bool{}
 	for delegatedRoleName := range s.DelegationEnvelopes {
 		reachedDelegations[delegatedRoleName] = false
@@ -452,9 +589,10 @@ func (s *State) Verify(ctx context.Context) error {
 			}
 
 			verifier := &Verifier{
-				name:      delegation.Name,
-				keys:      keys,
-				threshold: delegation.Threshold,
+				name:        delegation.Name,
+				keys:        keys,
+				threshold:   delegation.Threshold,
+				keyValidity: keyValidity,
 			}
 
 			if err := verifier.Verify(ctx, nil, env); err != nil {
@@ -484,7 +622,7 @@ func (s *State) Verify(ctx context.Context) error {
 
 // Commit verifies and writes the State to the policy-staging namespace. It also creates
 // an RSL entry recording the new tip of the policy-staging namespace.
-func (s *State) Commit(repo *git.Repository, commitMessage string, signCommit bool) error {
+func (s *State) Commit(ctx context.Context, repo *git.Repository, commitMessage string, signCommit bool) error {
 	if len(commitMessage) == 0 {
 		commitMessage = DefaultCommitMessage
 	}
@@ -501,6 +639,25 @@ func (s *State) Commit(repo *git.Repository, commitMessage string, signCommit bo
 		}
 	}
 
+	rootMetadata, err := s.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	// The metadata's signatures are timestamped before the state is
+	// committed, if signatures are timestamped
+	envelopes := make([]*sslibdsse.Envelope, 0, len(metadata))
+	for _, env := range metadata {
+		envelopes = append(envelopes, env)
+	}
+	signatures, err := envelopeSignatures(envelopes...)
+	if err != nil {
+		return err
+	}
+	if err := RecordTimestamps(ctx, repo, signatures, signCommit); err != nil {
+		return err
+	}
+
 	metadataEntries := []object.TreeEntry{}
 	for name, env := range metadata {
 		metadataContents, err := json.Marshal(env)
@@ -508,6 +665,15 @@ func (s *State) Commit(repo *git.Repository, commitMessage string, signCommit bo
 			return err
 		}
 
+		// The root metadata is never compressed, so that it can always be
+		// read to find the compression in use
+		if name != RootRoleName {
+			metadataContents, err = compression.Compress(rootMetadata.MetadataCompression, metadataContents)
+			if err != nil {
+				return err
+			}
+		}
+
 		blobID, err := gitinterface.WriteBlob(repo, metadataContents)
 		if err != nil {
 			return err
@@ -580,6 +746,9 @@ func (s *State) Commit(repo *git.Repository, commitMessage string, signCommit bo
 		return gitinterface.ResetDueToError(err, repo, PolicyStagingRef, originalCommitID)
 	}
 
+	// The state's signatures are now timestamped in the repository
+	s.repository = repo
+
 	return nil
 }
 
@@ -636,6 +805,22 @@ func Apply(ctx context.Context, repo *git.Repository, signRSLEntry bool) error {
 		return fmt.Errorf("staged policy is invalid: %w", err)
 	}
 
+	// The policy being replaced, if any, may require a justification even if
+	// the staged policy doesn't
+	currentState, err := LoadCurrentState(ctx, repo, PolicyRef)
+	if err != nil && !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return fmt.Errorf("failed to load current state: %w", err)
+	}
+
+	attestationsState, err := attestations.LoadCurrentAttestations(repo)
+	if err != nil {
+		return fmt.Errorf("failed to load current attestations: %w", err)
+	}
+
+	if err := verifyPolicyJustification(ctx, repo, currentState, state, attestationsState, policyStagingRef.Hash()); err != nil {
+		return err
+	}
+
 	// Update the reference for the base to point to the new commit
 	newPolicyRef := plumbing.NewHashReference(PolicyRef, policyStagingRef.Hash())
 	if err := repo.Storer.SetReference(newPolicyRef); err != nil {
@@ -649,6 +834,123 @@ func Apply(ctx context.Context, repo *git.Repository, signRSLEntry bool) error {
 	return nil
 }
 
+// Rollback reverts the active policy to a policy state that was previously
+// applied in the specified policy commit. The policy history is not rewritten:
+// the prior state is committed to the policy staging ref as a new state, which
+// is then applied like any other policy change. Any changes staged but not yet
+// applied are discarded. If no policy commit is specified, the policy state
+// applied before the active one is used.
+//
+// The prior state must be trusted by the root of trust of the active policy.
+// If the staged state cannot be applied, for example because a justification
+// is required, it is left on the policy staging ref.
+func Rollback(ctx context.Context, repo *git.Repository, policyCommitID plumbing.Hash, signCommit bool) error {
+	currentEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+	if err != nil {
+		return err
+	}
+	if currentEntry.TargetID == policyCommitID {
+		return fmt.Errorf("%w: '%s'", ErrPolicyStateAlreadyActive, policyCommitID.String())
+	}
+
+	logger.Debug("Identifying requested policy state...")
+	entry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, currentEntry.ID)
+	for err == nil {
+		if policyCommitID.IsZero() && entry.TargetID != currentEntry.TargetID {
+			break
+		}
+		if entry.TargetID == policyCommitID {
+			break
+		}
+
+		entry, _, err = rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, entry.ID)
+	}
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			if policyCommitID.IsZero() {
+				return ErrPolicyStateNotFound
+			}
+			return fmt.Errorf("%w: '%s'", ErrPolicyStateNotFound, policyCommitID.String())
+		}
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Loading policy state '%s'...", entry.TargetID.String()))
+	state, err := LoadState(ctx, repo, entry)
+	if err != nil {
+		return err
+	}
+
+	currentState, err := LoadState(ctx, repo, currentEntry)
+	if err != nil {
+		return err
+	}
+
+	// Apply doesn't check the staged root against the active root, but
+	// verification of the RSL does
+	if err := currentState.VerifyNewState(ctx, state); err != nil {
+		return fmt.Errorf("policy state '%s' is not trusted by the active root of trust: %w", entry.TargetID.String(), err)
+	}
+
+	logger.Debug("Staging policy state...")
+	if err := state.Commit(ctx, repo, fmt.Sprintf("Roll back policy to '%s'", entry.TargetID.String()), signCommit); err != nil {
+		return err
+	}
+
+	logger.Debug("Applying policy state...")
+	return Apply(ctx, repo, signCommit)
+}
+
+// verifyPolicyJustification checks that the policy commit is accompanied by a
+// justification attestation if either the current or the new policy requires
+// it. The justification must be signed by keys trusted to issue attestations
+// in the current policy, or the new policy if there is no current policy.
+func verifyPolicyJustification(ctx context.Context, repo *git.Repository, currentPolicy, newPolicy *State, attestationsState *attestations.Attestations, policyCommitID plumbing.Hash) error {
+	required := false
+	for _, state := range []*State{currentPolicy, newPolicy} {
+		if state == nil {
+			continue
+		}
+
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			return err
+		}
+		required = required || rootMetadata.RequirePolicyJustifications
+	}
+	if !required {
+		return nil
+	}
+
+	if attestationsState == nil {
+		return fmt.Errorf("%w: '%s'", ErrPolicyJustificationMissing, policyCommitID.String())
+	}
+
+	env, err := attestationsState.GetPolicyJustificationFor(repo, policyCommitID.String())
+	if err != nil {
+		if errors.Is(err, attestations.ErrPolicyJustificationNotFound) {
+			return fmt.Errorf("%w: '%s'", ErrPolicyJustificationMissing, policyCommitID.String())
+		}
+
+		return err
+	}
+
+	trustedPolicy := currentPolicy
+	if trustedPolicy == nil {
+		trustedPolicy = newPolicy
+	}
+
+	if err := trustedPolicy.VerifyAttestationSignatures(ctx, env); err != nil {
+		return fmt.Errorf("verifying justification for policy '%s' failed: %w", policyCommitID.String(), err)
+	}
+
+	if err := verifyRekorInclusion(ctx, repo, attestationsState, env); err != nil {
+		return fmt.Errorf("verifying justification for policy '%s' failed: %w", policyCommitID.String(), err)
+	}
+
+	return nil
+}
+
 func (s *State) GetRootKeys() ([]*tuf.Key, error) {
 	rootMetadata, err := s.GetRootMetadata()
 	if err != nil {
@@ -838,6 +1140,19 @@ func ListRules(ctx context.Context, repo *git.Repository, targetRef string) ([]*
 // return true even if the role in question is not reachable for some path (or
 // at all).
 func (s *State) hasFileRule() (bool, error) {
+	return s.hasRuleWithScheme(fileRuleScheme)
+}
+
+// hasSubmoduleRule returns true if the policy state has a single rule in any
+// targets role with the submodule namespace scheme. Like hasFileRule, this
+// function has no concept of role reachability.
+func (s *State) hasSubmoduleRule() (bool, error) {
+	return s.hasRuleWithScheme(submoduleRuleScheme)
+}
+
+// hasRuleWithScheme returns true if the policy state has a single rule in any
+// targets role with a pattern in the specified namespace scheme.
+func (s *State) hasRuleWithScheme(scheme string) (bool, error) {
 	if s.TargetsEnvelope == nil {
 		// No top level targets, we don't need to check for delegated roles
 		return false, nil
@@ -867,7 +1182,7 @@ func (s *State) hasFileRule() (bool, error) {
 			}
 
 			for _, path := range delegation.Paths {
-				if strings.HasPrefix(path, "file:") {
+				if strings.HasPrefix(path, scheme+":") {
 					return true, nil
 				}
 			}
@@ -877,15 +1192,90 @@ func (s *State) hasFileRule() (bool, error) {
 	return false, nil
 }
 
+// getFileRulePathPrefixes returns the path prefixes that file rules in any
+// targets role can match, i.e., the paths that are one of the prefixes or are
+// within a directory that is one of the prefixes. Changes to other paths can't
+// match any file rule. Nil is returned if a rule may match any path, such as
+// `file:*.go`. Like hasFileRule, this function has no concept of role
+// reachability.
+func (s *State) getFileRulePathPrefixes() ([]string, error) {
+	if s.TargetsEnvelope == nil {
+		return nil, nil
+	}
+
+	targetsRole, err := s.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		return nil, err
+	}
+
+	rolesToCheck := []*tuf.TargetsMetadata{targetsRole}
+	for roleName := range s.DelegationEnvelopes {
+		delegatedRole, err := s.GetTargetsMetadata(roleName)
+		if err != nil {
+			return nil, err
+		}
+		rolesToCheck = append(rolesToCheck, delegatedRole)
+	}
+
+	prefixes := set.NewSet[string]()
+	for _, role := range rolesToCheck {
+		for _, delegation := range role.Delegations.Roles {
+			if delegation.Name == AllowRuleName {
+				continue
+			}
+
+			for _, pattern := range delegation.Paths {
+				// The literal part of the pattern before its first special
+				// character
+				literal := pattern
+				if index := strings.IndexAny(pattern, `*?[\`); index >= 0 {
+					literal = pattern[:index]
+				}
+
+				filePath, isFilePattern := strings.CutPrefix(literal, fileRuleScheme+":")
+				if !isFilePattern {
+					if strings.HasPrefix(fileRuleScheme+":", literal) && literal != pattern {
+						// The pattern may match file rule paths, e.g., `*`
+						return nil, nil
+					}
+					continue
+				}
+
+				if literal != pattern {
+					// A pattern like `file:src/*.go` matches paths within the
+					// `src` directory
+					index := strings.LastIndex(filePath, "/")
+					if index <= 0 {
+						return nil, nil
+					}
+					filePath = filePath[:index]
+				}
+
+				prefixes.Add(filePath)
+			}
+		}
+	}
+
+	prefixesList := prefixes.Contents()
+	sort.Strings(prefixesList)
+	return prefixesList, nil
+}
+
 func (s *State) getRootVerifier() (*Verifier, error) {
 	rootMetadata, err := s.GetRootMetadata()
 	if err != nil {
 		return nil, err
 	}
 
+	keyValidity, err := s.getKeyValidity()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Verifier{
-		keys:      s.RootPublicKeys,
-		threshold: rootMetadata.Roles[RootRoleName].Threshold,
+		keys:        s.RootPublicKeys,
+		threshold:   rootMetadata.Roles[RootRoleName].Threshold,
+		keyValidity: keyValidity,
 	}, nil
 }
 
@@ -895,7 +1285,15 @@ func (s *State) getTargetsVerifier() (*Verifier, error) {
 		return nil, err
 	}
 
-	verifier := &Verifier{keys: make([]*tuf.Key, 0, len(rootMetadata.Roles[TargetsRoleName].KeyIDs))}
+	keyValidity, err := s.getKeyValidity()
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := &Verifier{
+		keys:        make([]*tuf.Key, 0, len(rootMetadata.Roles[TargetsRoleName].KeyIDs)),
+		keyValidity: keyValidity,
+	}
 	for _, keyID := range rootMetadata.Roles[TargetsRoleName].KeyIDs {
 		verifier.keys = append(verifier.keys, rootMetadata.Keys[keyID])
 	}
@@ -955,7 +1353,7 @@ func verifySuccessiveRootsAndLoadLatestPolicyState(ctx context.Context, repo *gi
 		return nil, err
 	}
 
-	slog.Debug(fmt.Sprintf("Trusting root of trust for initial policy '%s'...", firstPolicyEntry.ID))
+	logger.Debug(fmt.Sprintf("Trusting root of trust for initial policy '%s'...", firstPolicyEntry.ID))
 	verifiedState := initialPolicyState
 	for _, entry := range allPolicyEntries[1:] {
 		if entry.RefName != PolicyRef {
@@ -968,7 +1366,7 @@ func verifySuccessiveRootsAndLoadLatestPolicyState(ctx context.Context, repo *gi
 			return nil, err
 		}
 
-		slog.Debug(fmt.Sprintf("Verifying root of trust for policy '%s'...", entry.ID))
+		logger.Debug(fmt.Sprintf("Verifying root of trust for policy '%s'...", entry.ID))
 		if err := verifiedState.VerifyNewState(ctx, underTestState); err != nil {
 			return nil, err
 		}
@@ -1019,7 +1417,7 @@ func loadStateForEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (*State,
 		}
 	}
 
-	state := &State{}
+	state := &State{repository: repo}
 
 	metadataTree, err := gitinterface.GetTree(repo, metadataTreeID)
 	if err != nil {
@@ -1031,12 +1429,26 @@ func loadStateForEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (*State,
 		return nil, err
 	}
 
+	// In a partial clone, fetch all the metadata and keys at once
+	blobIDs := []plumbing.Hash{}
+	for _, entry := range append(metadataTree.Entries, keysTree.Entries...) {
+		blobIDs = append(blobIDs, entry.Hash)
+	}
+	if err := gitinterface.PrefetchObjects(repo, blobIDs); err != nil {
+		return nil, err
+	}
+
 	for _, entry := range metadataTree.Entries {
 		contents, err := gitinterface.ReadBlob(repo, entry.Hash)
 		if err != nil {
 			return nil, err
 		}
 
+		contents, err = compression.Decompress(contents)
+		if err != nil {
+			return nil, err
+		}
+
 		env := &sslibdsse.Envelope{}
 		if err := json.Unmarshal(contents, env); err != nil {
 			return nil, err
@@ -1081,6 +1493,51 @@ func loadStateForEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (*State,
 	return state, nil
 }
 
+// LoadMetadataContents returns the contents of the metadata files in the
+// policy commit, keyed by their paths in the commit's tree, such as
+// metadata/root.json. Unlike when states are loaded, the contents are only
+// decompressed and not parsed, so that they can be inspected even if they are
+// not valid metadata.
+func LoadMetadataContents(repo *git.Repository, commitID plumbing.Hash) (map[string][]byte, error) {
+	policyCommit, err := gitinterface.GetCommit(repo, commitID)
+	if err != nil {
+		return nil, err
+	}
+
+	policyRootTree, err := gitinterface.GetTree(repo, policyCommit.TreeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := map[string][]byte{}
+	for _, e := range policyRootTree.Entries {
+		if e.Name != metadataTreeEntryName {
+			continue
+		}
+
+		metadataTree, err := gitinterface.GetTree(repo, e.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range metadataTree.Entries {
+			blobContents, err := gitinterface.ReadBlob(repo, entry.Hash)
+			if err != nil {
+				return nil, err
+			}
+
+			blobContents, err = compression.Decompress(blobContents)
+			if err != nil {
+				return nil, err
+			}
+
+			contents[path.Join(metadataTreeEntryName, entry.Name)] = blobContents
+		}
+	}
+
+	return contents, nil
+}
+
 func verifyRootKeysMatch(keys1, keys2 []*tuf.Key) bool {
 	if len(keys1) != len(keys2) {
 		return false
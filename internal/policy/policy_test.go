@@ -3,10 +3,13 @@
 package policy
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/compression"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
@@ -111,7 +114,7 @@ func TestLoadState(t *testing.T) {
 
 		state.TargetsEnvelope = env
 
-		if err := state.Commit(repo, "", false); err != nil {
+		if err := state.Commit(testCtx, repo, "", false); err != nil {
 			t.Fatal(err)
 		}
 
@@ -137,7 +140,7 @@ func TestLoadState(t *testing.T) {
 
 		state.TargetsEnvelope = env
 
-		if err := state.Commit(repo, "", false); err != nil {
+		if err := state.Commit(testCtx, repo, "", false); err != nil {
 			t.Fatal(err)
 		}
 
@@ -201,7 +204,7 @@ func TestLoadState(t *testing.T) {
 
 		state.TargetsEnvelope = env
 
-		if err := state.Commit(repo, "", false); err != nil {
+		if err := state.Commit(testCtx, repo, "", false); err != nil {
 			t.Fatal(err)
 		}
 
@@ -232,7 +235,7 @@ func TestLoadState(t *testing.T) {
 
 		state.TargetsEnvelope = env
 
-		if err := state.Commit(repo, "", false); err != nil {
+		if err := state.Commit(testCtx, repo, "", false); err != nil {
 			t.Fatal(err)
 		}
 
@@ -300,7 +303,7 @@ func TestLoadFirstState(t *testing.T) {
 		t.Fatal(err)
 	}
 	secondState.TargetsEnvelope = targetsEnv
-	if err := secondState.Commit(repo, "Second state", false); err != nil {
+	if err := secondState.Commit(testCtx, repo, "Second state", false); err != nil {
 		t.Fatal(err)
 	}
 
@@ -349,6 +352,27 @@ func TestStateKeys(t *testing.T) {
 	assert.Equal(t, expectedKeys, keys)
 }
 
+func TestStateKeyLabels(t *testing.T) {
+	state := createTestStateWithPolicy(t)
+
+	rootKey, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels, err := state.KeyLabels()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		rootKey.KeyID: signerverifier.KeyLabel(rootKey),
+		gpgKey.KeyID:  "gittuf Test Key (Test key for gittuf) <gittuf@saky.in>",
+	}, labels)
+	assert.NotEmpty(t, labels[rootKey.KeyID])
+}
+
 func TestStateVerify(t *testing.T) {
 	t.Run("only root", func(t *testing.T) {
 		state := createTestStateWithOnlyRoot(t)
@@ -403,6 +427,102 @@ func TestStateCommit(t *testing.T) {
 	assert.Equal(t, entry.TargetID, policyRef.Hash())
 }
 
+func TestStateCommitWithCompression(t *testing.T) {
+	repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata.MetadataCompression = compression.AlgorithmZstd
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.RootEnvelope = rootEnv
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add enough rules for the targets metadata to be compressed
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		targetsMetadata, err = AddDelegation(targetsMetadata, fmt.Sprintf("protect-branch-%d", i), []*tuf.Key{gpgKey}, []string{fmt.Sprintf("git:refs/heads/branch-%d", i)}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(testCtx, targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	if err := state.Commit(testCtx, repo, "Compress metadata", false); err != nil {
+		t.Fatal(err)
+	}
+
+	policyStagingRef, err := repo.Reference(PolicyStagingRef, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := gitinterface.GetCommit(repo, policyStagingRef.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootEntry, err := tree.FindEntry(fmt.Sprintf("%s/%s.json", metadataTreeEntryName, RootRoleName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootContents, err := gitinterface.ReadBlob(repo, rootEntry.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, bytes.HasPrefix(rootContents, []byte("gittuf-compression: ")))
+
+	targetsEntry, err := tree.FindEntry(fmt.Sprintf("%s/%s.json", metadataTreeEntryName, TargetsRoleName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsContents, err := gitinterface.ReadBlob(repo, targetsEntry.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, bytes.HasPrefix(targetsContents, []byte("gittuf-compression: zstd\n")))
+
+	stagedState, err := LoadCurrentState(testCtx, repo, PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, state.RootEnvelope, stagedState.RootEnvelope)
+	assert.Equal(t, state.TargetsEnvelope, stagedState.TargetsEnvelope)
+}
+
 func TestStateGetRootMetadata(t *testing.T) {
 	state := createTestStateWithOnlyRoot(t)
 
@@ -467,6 +587,53 @@ func TestStateFindVerifiersForPath(t *testing.T) {
 	})
 }
 
+func TestDelegationMatches(t *testing.T) {
+	allChanges := &tuf.Delegation{Paths: []string{"git:refs/changes/*"}}
+	oneChange := &tuf.Delegation{Paths: []string{"git:refs/changes/1234"}}
+	mainBranch := &tuf.Delegation{Paths: []string{"git:refs/heads/main"}}
+
+	tests := map[string]struct {
+		delegation *tuf.Delegation
+		path       string
+		expected   bool
+	}{
+		"patch set matches rule for all changes": {
+			delegation: allChanges,
+			path:       "git:refs/changes/34/1234/2",
+			expected:   true,
+		},
+		"change metadata matches rule for all changes": {
+			delegation: allChanges,
+			path:       "git:refs/changes/34/1234/meta",
+			expected:   true,
+		},
+		"patch set matches rule for its change": {
+			delegation: oneChange,
+			path:       "git:refs/changes/34/1234/2",
+			expected:   true,
+		},
+		"patch set does not match rule for another change": {
+			delegation: oneChange,
+			path:       "git:refs/changes/35/1235/1",
+			expected:   false,
+		},
+		"branch matches rule for branch": {
+			delegation: mainBranch,
+			path:       "git:refs/heads/main",
+			expected:   true,
+		},
+		"file does not match rule for change": {
+			delegation: oneChange,
+			path:       "file:refs/changes/34/1234/2",
+			expected:   false,
+		},
+	}
+
+	for name, test := range tests {
+		assert.Equal(t, test.expected, delegationMatches(test.delegation, test.path), fmt.Sprintf("unexpected result in test '%s'", name))
+	}
+}
+
 func TestStateFindPublicKeysForPath(t *testing.T) {
 	state := createTestStateWithPolicy(t)
 
@@ -581,7 +748,7 @@ func TestGetStateForCommit(t *testing.T) {
 		t.Fatal(err)
 	}
 	secondState.TargetsEnvelope = targetsEnv
-	if err := secondState.Commit(repo, "Second state", false); err != nil {
+	if err := secondState.Commit(testCtx, repo, "Second state", false); err != nil {
 		t.Fatal(err)
 	}
 	if err := Apply(context.Background(), repo, false); err != nil {
@@ -728,6 +895,32 @@ func TestStateHasFileRule(t *testing.T) {
 	})
 }
 
+func TestStateGetFileRulePathPrefixes(t *testing.T) {
+	t.Run("with file rules", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		prefixes, err := state.getFileRulePathPrefixes()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"1", "2"}, prefixes)
+	})
+
+	t.Run("with delegated file rules", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+
+		prefixes, err := state.getFileRulePathPrefixes()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"1", "1/subpath1", "1/subpath2", "2"}, prefixes)
+	})
+
+	t.Run("with no file rules", func(t *testing.T) {
+		state := createTestStateWithOnlyRoot(t)
+
+		prefixes, err := state.getFileRulePathPrefixes()
+		assert.Nil(t, err)
+		assert.Nil(t, prefixes)
+	})
+}
+
 func TestApply(t *testing.T) {
 	t.Run("single addition", func(t *testing.T) {
 		repo, state := createTestRepository(t, createTestStateWithOnlyRoot)
@@ -762,7 +955,7 @@ func TestApply(t *testing.T) {
 
 		state.RootEnvelope = rootEnv
 
-		if err := state.Commit(repo, "Added target key to root", false); err != nil {
+		if err := state.Commit(testCtx, repo, "Added target key to root", false); err != nil {
 			t.Fatal(err)
 		}
 
@@ -799,4 +992,201 @@ func TestApply(t *testing.T) {
 
 		assert.Equal(t, staging, policy)
 	})
+
+	t.Run("justification required", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rootMetadata.RequirePolicyJustifications = true
+
+		rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootEnv, err = dsse.SignEnvelope(context.Background(), rootEnv, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		state.RootEnvelope = rootEnv
+
+		if err := state.Commit(testCtx, repo, "Require policy justifications", false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = Apply(testCtx, repo, false)
+		assert.ErrorIs(t, err, ErrPolicyJustificationMissing)
+
+		policyStagingRef, err := repo.Reference(PolicyStagingRef, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		statement, err := attestations.NewPolicyJustificationAttestation(&attestations.PolicyJustification{
+			PolicyCommitID: policyStagingRef.Hash().String(),
+			Reason:         "Require justifications for future policy changes",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := dsse.CreateEnvelope(statement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(context.Background(), env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		allAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := allAttestations.SetPolicyJustification(repo, env, policyStagingRef.Hash().String()); err != nil {
+			t.Fatal(err)
+		}
+		if err := allAttestations.Commit(repo, "Add policy justification", false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = Apply(testCtx, repo, false)
+		assert.Nil(t, err)
+
+		policyRef, err := repo.Reference(PolicyRef, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, policyStagingRef.Hash(), policyRef.Hash())
+	})
+}
+
+func TestRollback(t *testing.T) {
+	createRepositoryWithTwoStates := func(t *testing.T) (*git.Repository, plumbing.Hash, plumbing.Hash) {
+		t.Helper()
+
+		repo, state := createTestRepository(t, createTestStateWithOnlyRoot)
+
+		firstPolicyRef, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootMetadata, err = AddTargetsKey(rootMetadata, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.RootEnvelope = rootEnv
+
+		if err := state.Commit(testCtx, repo, "Added target key to root", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := Apply(testCtx, repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		secondPolicyRef, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return repo, firstPolicyRef.Hash(), secondPolicyRef.Hash()
+	}
+
+	t.Run("roll back to previous state", func(t *testing.T) {
+		repo, firstPolicyCommitID, secondPolicyCommitID := createRepositoryWithTwoStates(t)
+
+		err := Rollback(testCtx, repo, plumbing.ZeroHash, false)
+		assert.Nil(t, err)
+
+		policyRef, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The rollback is a new policy state on top of the history
+		policyCommit, err := gitinterface.GetCommit(repo, policyRef.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []plumbing.Hash{secondPolicyCommitID}, policyCommit.ParentHashes)
+		assert.Contains(t, policyCommit.Message, firstPolicyCommitID.String())
+
+		firstEntry, _, err := rsl.GetFirstReferenceEntryForRef(repo, PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		firstState, err := LoadState(testCtx, repo, firstEntry)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		currentState, err := LoadCurrentState(testCtx, repo, PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, firstState.RootEnvelope, currentState.RootEnvelope)
+	})
+
+	t.Run("roll back to specified state", func(t *testing.T) {
+		repo, firstPolicyCommitID, _ := createRepositoryWithTwoStates(t)
+
+		err := Rollback(testCtx, repo, firstPolicyCommitID, false)
+		assert.Nil(t, err)
+
+		// Rolling back again returns to the same state
+		err = Rollback(testCtx, repo, firstPolicyCommitID, false)
+		assert.Nil(t, err)
+	})
+
+	t.Run("requested state is active", func(t *testing.T) {
+		repo, _, secondPolicyCommitID := createRepositoryWithTwoStates(t)
+
+		err := Rollback(testCtx, repo, secondPolicyCommitID, false)
+		assert.ErrorIs(t, err, ErrPolicyStateAlreadyActive)
+	})
+
+	t.Run("requested state was never applied", func(t *testing.T) {
+		repo, _, _ := createRepositoryWithTwoStates(t)
+
+		err := Rollback(testCtx, repo, plumbing.NewHash("abcdef12345678"), false)
+		assert.ErrorIs(t, err, ErrPolicyStateNotFound)
+	})
+
+	t.Run("no previous state", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
+
+		err := Rollback(testCtx, repo, plumbing.ZeroHash, false)
+		assert.ErrorIs(t, err, ErrPolicyStateNotFound)
+	})
 }
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/go-git/go-git/v5"
+)
+
+var ErrReleaseArtifactNotAttested = errors.New("release artifact does not match any artifact attested for the release")
+
+// VerifyReleaseArtifact checks that the artifact with the specified name and
+// SHA-256 digest was built from the commit. The rebuild attestation for the
+// artifact's digest must be signed by a threshold of the keys in the predicate
+// policy for rebuild attestations. If the attestations record a different
+// digest for the artifact, the digests are included in the error.
+func VerifyReleaseArtifact(ctx context.Context, repo *git.Repository, commitID, artifactName, artifactDigest string) error {
+	attestationsState, err := attestations.LoadCurrentAttestations(repo)
+	if err != nil {
+		return err
+	}
+
+	state, err := LoadCurrentState(ctx, repo, PolicyRef)
+	if err != nil {
+		return err
+	}
+
+	verifier, err := state.FindVerifierForPredicateType(attestations.RebuildPredicateType)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Loading rebuild attestations for '%s' built from '%s'...", artifactName, commitID))
+	rebuilds, err := attestationsState.GetRebuildAttestationsFor(repo, commitID, artifactName)
+	if err != nil {
+		return err
+	}
+
+	env, has := rebuilds[artifactDigest]
+	if !has {
+		if len(rebuilds) == 0 {
+			return fmt.Errorf("%w: no attestations found for '%s'", ErrReleaseArtifactNotAttested, artifactName)
+		}
+
+		attestedDigests := make([]string, 0, len(rebuilds))
+		for digest := range rebuilds {
+			attestedDigests = append(attestedDigests, digest)
+		}
+		sort.Strings(attestedDigests)
+
+		return fmt.Errorf("%w: '%s' has digest '%s', attested digests are '%s'", ErrReleaseArtifactNotAttested, artifactName, artifactDigest, strings.Join(attestedDigests, "', '"))
+	}
+
+	if err := verifyRekorInclusion(ctx, repo, attestationsState, env); err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Verifying rebuild attestation for '%s' with digest '%s'...", artifactName, artifactDigest))
+	if err := verifier.Verify(ctx, nil, env); err != nil {
+		return fmt.Errorf("%w: %w", ErrReleaseArtifactNotAttested, err)
+	}
+
+	return nil
+}
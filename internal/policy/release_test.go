@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyReleaseArtifact(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithRequiredRebuilds)
+	commitID := "abcdef12345678900987654321fedcbaabcdef12"
+	artifactName := "gittuf-linux-amd64"
+
+	addRebuild := func(artifactDigest string, keyBytes []byte) {
+		t.Helper()
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env, err := currentAttestations.GetRebuildAttestationFor(repo, commitID, artifactName, artifactDigest)
+		if err != nil {
+			rebuild, err := attestations.NewRebuildAttestation(&attestations.Rebuild{CommitID: commitID, ArtifactName: artifactName, ArtifactSHA256: artifactDigest})
+			if err != nil {
+				t.Fatal(err)
+			}
+			env, err = dsse.CreateEnvelope(rebuild)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := currentAttestations.SetRebuildAttestation(repo, env, commitID, artifactName, artifactDigest); err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.Commit(repo, "Add rebuild", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// No rebuilds have been recorded yet
+	err := VerifyReleaseArtifact(testCtx, repo, commitID, artifactName, "aaaa")
+	assert.ErrorIs(t, err, ErrReleaseArtifactNotAttested)
+
+	// Only one of the two trusted rebuilders attested to the digest
+	addRebuild("aaaa", targets1KeyBytes)
+	err = VerifyReleaseArtifact(testCtx, repo, commitID, artifactName, "aaaa")
+	assert.ErrorIs(t, err, ErrReleaseArtifactNotAttested)
+	assert.ErrorIs(t, err, ErrVerifierConditionsUnmet)
+
+	// Both trusted rebuilders agree
+	addRebuild("aaaa", targets2KeyBytes)
+	err = VerifyReleaseArtifact(testCtx, repo, commitID, artifactName, "aaaa")
+	assert.Nil(t, err)
+
+	// The artifact being verified doesn't match the attested artifact
+	err = VerifyReleaseArtifact(testCtx, repo, commitID, artifactName, "bbbb")
+	assert.ErrorIs(t, err, ErrReleaseArtifactNotAttested)
+	assert.Contains(t, err.Error(), "aaaa")
+
+	// The artifact was not built from another commit
+	err = VerifyReleaseArtifact(testCtx, repo, "1234567890abcdef1234567890abcdef12345678", artifactName, "aaaa")
+	assert.ErrorIs(t, err, ErrReleaseArtifactNotAttested)
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/report"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// reportEntry records the signatures checked for an RSL entry in the report.
+// If the entry failed verification for a reason other than a check of its
+// signatures, the failure is recorded as a result for the entry itself.
+func reportEntry(r *report.Report, entryExplanation *EntryExplanation, err error) {
+	if r == nil || entryExplanation == nil {
+		return
+	}
+
+	checkFailed := false
+	for _, check := range entryExplanation.Checks {
+		result := newCheckResult(check)
+		result.RefName = entryExplanation.RefName
+		result.EntryID = entryExplanation.EntryID
+		if !result.Verified {
+			checkFailed = true
+		}
+
+		r.Add(result)
+	}
+
+	if err != nil && !checkFailed {
+		r.Add(&report.Result{
+			Kind:     report.KindRSLEntry,
+			ObjectID: entryExplanation.EntryID,
+			RefName:  entryExplanation.RefName,
+			EntryID:  entryExplanation.EntryID,
+			Error:    err.Error(),
+		})
+	}
+}
+
+// newCheckResult returns the result of a check of a Git object's signatures.
+func newCheckResult(check *CheckExplanation) *report.Result {
+	result := &report.Result{
+		Kind:      check.kind,
+		ObjectID:  check.ObjectID,
+		Namespace: check.Namespace,
+		Verified:  check.Verified(),
+	}
+	if !check.signedAt.IsZero() {
+		signedAt := check.signedAt.UTC()
+		result.SignedAt = &signedAt
+	}
+
+	var matchedRule *RuleExplanation
+	if len(check.Rules) > 0 {
+		lastRule := check.Rules[len(check.Rules)-1]
+		if result.Verified {
+			matchedRule = lastRule
+			result.Rule = lastRule.Name
+		} else {
+			result.Error = lastRule.Rejection
+		}
+	}
+
+	if len(check.signature) > 0 {
+		result.Signatures = append(result.Signatures, &report.Signature{
+			Source:   report.SourceGit,
+			KeyID:    check.SignatureKeyID,
+			Format:   check.signatureFormat,
+			Verified: check.SignatureKeyID != "",
+		})
+	}
+
+	// The signatures on the reference authorization are verified together
+	// against the rule's threshold, so they're considered verified if the
+	// matched rule trusts their keys
+	for _, keyID := range check.AttestationKeyIDs {
+		result.Signatures = append(result.Signatures, &report.Signature{
+			Source:   report.SourceReferenceAuthorization,
+			KeyID:    keyID,
+			Format:   report.FormatDSSE,
+			Verified: matchedRule != nil && slices.Contains(matchedRule.KeyIDs, keyID),
+		})
+	}
+
+	return result
+}
+
+// newCommitResult returns the result of verifying the signature of the commit
+// identified by id, as by VerifyCommit. key is the key that verified the
+// commit's signature, and commit is nil if id couldn't be resolved to a
+// commit.
+func newCommitResult(id string, commit *object.Commit, key *tuf.Key, status string) *report.Result {
+	result := &report.Result{Kind: report.KindCommit, ObjectID: id, Verified: key != nil}
+	if !result.Verified {
+		result.Error = status
+	}
+	if commit == nil {
+		return result
+	}
+
+	result.ObjectID = commit.Hash.String()
+	signedAt := commit.Committer.When.UTC()
+	result.SignedAt = &signedAt
+
+	if commit.PGPSignature != "" {
+		signature := &report.Signature{
+			Source: report.SourceGit,
+			Format: gitinterface.GetSignatureFormat([]byte(commit.PGPSignature)),
+		}
+		if key != nil {
+			signature.KeyID = key.KeyID
+			signature.Verified = true
+			if method, err := gitinterface.GetSignatureMethod(key, []byte(commit.PGPSignature)); err == nil {
+				signature.Format = method.Format
+			}
+		}
+		result.Signatures = append(result.Signatures, signature)
+	}
+
+	return result
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/report"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	refName := "refs/heads/main"
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("successful verification", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		verificationReport := report.New("verify-ref")
+		ctx := report.ContextWithReport(context.Background(), verificationReport)
+
+		err := verifyEntry(ctx, repo, state, nil, entry)
+		assert.Nil(t, err)
+
+		if !assert.NotEmpty(t, verificationReport.Results) {
+			return
+		}
+		result := verificationReport.Results[0]
+		assert.True(t, result.Verified)
+		assert.Equal(t, report.KindRSLEntry, result.Kind)
+		assert.Equal(t, entryID.String(), result.ObjectID)
+		assert.Equal(t, entryID.String(), result.EntryID)
+		assert.Equal(t, refName, result.RefName)
+		assert.Equal(t, "git:refs/heads/main", result.Namespace)
+		assert.Equal(t, "protect-main", result.Rule)
+		assert.NotNil(t, result.SignedAt)
+		assert.False(t, result.VerifiedAt.IsZero())
+		assert.Equal(t, []*report.Signature{{
+			Source:   report.SourceGit,
+			KeyID:    gpgKey.KeyID,
+			Format:   gitinterface.SignatureFormatGPG,
+			Verified: true,
+		}}, result.Signatures)
+
+		for _, result := range verificationReport.Results[1:] {
+			assert.True(t, result.Verified)
+			assert.Equal(t, report.KindCommit, result.Kind)
+			assert.Equal(t, commitIDs[0].String(), result.ObjectID)
+		}
+	})
+
+	t.Run("unmet threshold", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		verificationReport := report.New("verify-ref")
+		ctx := report.ContextWithReport(context.Background(), verificationReport)
+
+		err := verifyEntry(ctx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+		verificationReport.Finish(err)
+
+		assert.False(t, verificationReport.Verified)
+		if !assert.Len(t, verificationReport.Results, 1) {
+			return
+		}
+		result := verificationReport.Results[0]
+		assert.False(t, result.Verified)
+		assert.Empty(t, result.Rule)
+		assert.Equal(t, "threshold is 2 but no reference authorization was found, so at most one signature is available", result.Error)
+
+		// The rule's threshold can't be met, so the Git signature isn't
+		// verified
+		assert.Equal(t, []*report.Signature{{
+			Source: report.SourceGit,
+			Format: gitinterface.SignatureFormatGPG,
+		}}, result.Signatures)
+	})
+
+	t.Run("commits", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		verificationReport := report.New("verify-commit")
+		ctx := report.ContextWithReport(testCtx, verificationReport)
+
+		VerifyCommit(ctx, repo, commitIDs[0].String(), "unknown")
+
+		if !assert.Len(t, verificationReport.Results, 2) {
+			return
+		}
+		result := verificationReport.Results[0]
+		assert.True(t, result.Verified)
+		assert.Equal(t, report.KindCommit, result.Kind)
+		assert.Equal(t, commitIDs[0].String(), result.ObjectID)
+		assert.NotNil(t, result.SignedAt)
+		assert.Equal(t, []*report.Signature{{
+			Source:   report.SourceGit,
+			KeyID:    gpgKey.KeyID,
+			Format:   gitinterface.SignatureFormatGPG,
+			Verified: true,
+		}}, result.Signatures)
+
+		result = verificationReport.Results[1]
+		assert.False(t, result.Verified)
+		assert.Equal(t, "unknown", result.ObjectID)
+		assert.Equal(t, unableToResolveRevisionMessage, result.Error)
+	})
+}
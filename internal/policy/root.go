@@ -3,7 +3,11 @@
 package policy
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/gittuf/gittuf/internal/tuf"
@@ -16,6 +20,11 @@ var (
 	ErrTargetsMetadataNil  = errors.New("targetsMetadata not found")
 	ErrTargetsKeyNil       = errors.New("targetsKey is nil")
 	ErrKeyIDEmpty          = errors.New("keyID is empty")
+
+	ErrInvalidValidityWindow      = errors.New("invalid key validity window")
+	ErrInvalidTimestampAuthority  = errors.New("invalid timestamp authority certificate")
+	ErrTimestampAuthorityNotFound = errors.New("timestamp authority not found")
+	ErrKeyNotRevoked              = errors.New("key is not revoked")
 )
 
 // InitializeRootMetadata initializes a new instance of tuf.RootMetadata with
@@ -180,3 +189,154 @@ func UpdateTargetsThreshold(rootMetadata *tuf.RootMetadata, threshold int) (*tuf
 
 	return rootMetadata, nil
 }
+
+// SetKeyValidity sets the period in which the key may issue signatures. The
+// bounds are RFC 3339 timestamps, and either may be empty to leave the window
+// open on that side. If both are empty, the key's validity window is removed.
+func SetKeyValidity(rootMetadata *tuf.RootMetadata, keyID, notBefore, notAfter string) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+	if keyID == "" {
+		return nil, ErrKeyIDEmpty
+	}
+
+	if notBefore == "" && notAfter == "" {
+		delete(rootMetadata.KeyValidity, keyID)
+		if len(rootMetadata.KeyValidity) == 0 {
+			rootMetadata.KeyValidity = nil
+		}
+		return rootMetadata, nil
+	}
+
+	var notBeforeTime, notAfterTime time.Time
+	if notBefore != "" {
+		t, err := time.Parse(time.RFC3339, notBefore)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidValidityWindow, err)
+		}
+		notBeforeTime = t
+	}
+	if notAfter != "" {
+		t, err := time.Parse(time.RFC3339, notAfter)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidValidityWindow, err)
+		}
+		notAfterTime = t
+	}
+	if notBefore != "" && notAfter != "" && !notBeforeTime.Before(notAfterTime) {
+		return nil, fmt.Errorf("%w: key must become valid before it stops being valid", ErrInvalidValidityWindow)
+	}
+
+	if rootMetadata.KeyValidity == nil {
+		rootMetadata.KeyValidity = map[string]*tuf.ValidityWindow{}
+	}
+	rootMetadata.KeyValidity[keyID] = &tuf.ValidityWindow{NotBefore: notBefore, NotAfter: notAfter}
+
+	return rootMetadata, nil
+}
+
+// RevokeKey records that the key has been revoked, so that signatures issued
+// by it are rejected regardless of when they were issued. The reason is
+// optional. Unlike a key validity window, a revocation also invalidates the
+// key's prior signatures, so it's meant for keys that have been compromised.
+func RevokeKey(rootMetadata *tuf.RootMetadata, keyID, reason string) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+	if keyID == "" {
+		return nil, ErrKeyIDEmpty
+	}
+
+	if rootMetadata.RevokedKeys == nil {
+		rootMetadata.RevokedKeys = map[string]*tuf.KeyRevocation{}
+	}
+	rootMetadata.RevokedKeys[keyID] = &tuf.KeyRevocation{Reason: reason}
+
+	return rootMetadata, nil
+}
+
+// UnrevokeKey removes the revocation of the key recorded using RevokeKey.
+func UnrevokeKey(rootMetadata *tuf.RootMetadata, keyID string) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+	if keyID == "" {
+		return nil, ErrKeyIDEmpty
+	}
+
+	if _, isRevoked := rootMetadata.RevokedKeys[keyID]; !isRevoked {
+		return nil, ErrKeyNotRevoked
+	}
+
+	delete(rootMetadata.RevokedKeys, keyID)
+	if len(rootMetadata.RevokedKeys) == 0 {
+		rootMetadata.RevokedKeys = nil
+	}
+
+	return rootMetadata, nil
+}
+
+// AddTimestampAuthority adds the PEM encoded root certificate of an RFC 3161
+// timestamp authority to the authorities trusted to timestamp signatures.
+func AddTimestampAuthority(rootMetadata *tuf.RootMetadata, certificatePEM []byte) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+
+	certificate, err := parseTimestampAuthority(certificatePEM)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, existing := range rootMetadata.TimestampAuthorities {
+		if existingCertificate, err := parseTimestampAuthority([]byte(existing)); err == nil && existingCertificate.Equal(certificate) {
+			return rootMetadata, nil
+		}
+	}
+
+	rootMetadata.TimestampAuthorities = append(rootMetadata.TimestampAuthorities, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})))
+	return rootMetadata, nil
+}
+
+// RemoveTimestampAuthority removes the root certificate of a timestamp
+// authority from the authorities trusted to timestamp signatures.
+func RemoveTimestampAuthority(rootMetadata *tuf.RootMetadata, certificatePEM []byte) (*tuf.RootMetadata, error) {
+	if rootMetadata == nil {
+		return nil, ErrRootMetadataNil
+	}
+
+	certificate, err := parseTimestampAuthority(certificatePEM)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(rootMetadata.TimestampAuthorities, func(existing string) bool {
+		existingCertificate, err := parseTimestampAuthority([]byte(existing))
+		return err == nil && existingCertificate.Equal(certificate)
+	})
+	if index == -1 {
+		return nil, ErrTimestampAuthorityNotFound
+	}
+
+	rootMetadata.TimestampAuthorities = slices.Delete(rootMetadata.TimestampAuthorities, index, index+1)
+	if len(rootMetadata.TimestampAuthorities) == 0 {
+		rootMetadata.TimestampAuthorities = nil
+	}
+
+	return rootMetadata, nil
+}
+
+func parseTimestampAuthority(certificatePEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certificatePEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, ErrInvalidTimestampAuthority
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTimestampAuthority, err)
+	}
+
+	return certificate, nil
+}
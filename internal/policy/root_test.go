@@ -3,7 +3,15 @@
 package policy
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/stretchr/testify/assert"
@@ -134,3 +142,116 @@ func TestDeleteTargetsKey(t *testing.T) {
 	assert.ErrorIs(t, err, ErrCannotMeetThreshold)
 	assert.Nil(t, rootMetadata)
 }
+
+func TestSetKeyValidity(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	t.Run("set window", func(t *testing.T) {
+		rootMetadata, err := SetKeyValidity(rootMetadata, key.KeyID, "2024-01-01T00:00:00Z", "2025-01-01T00:00:00Z")
+		assert.Nil(t, err)
+		assert.Equal(t, &tuf.ValidityWindow{NotBefore: "2024-01-01T00:00:00Z", NotAfter: "2025-01-01T00:00:00Z"}, rootMetadata.KeyValidity[key.KeyID])
+
+		rootMetadata, err = SetKeyValidity(rootMetadata, key.KeyID, "", "2025-01-01T00:00:00Z")
+		assert.Nil(t, err)
+		assert.Equal(t, &tuf.ValidityWindow{NotAfter: "2025-01-01T00:00:00Z"}, rootMetadata.KeyValidity[key.KeyID])
+	})
+
+	t.Run("remove window", func(t *testing.T) {
+		rootMetadata, err := SetKeyValidity(rootMetadata, key.KeyID, "", "")
+		assert.Nil(t, err)
+		assert.Nil(t, rootMetadata.KeyValidity)
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		_, err := SetKeyValidity(rootMetadata, key.KeyID, "2025-01-01T00:00:00Z", "2024-01-01T00:00:00Z")
+		assert.ErrorIs(t, err, ErrInvalidValidityWindow)
+
+		_, err = SetKeyValidity(rootMetadata, key.KeyID, "2024-01-01", "")
+		assert.ErrorIs(t, err, ErrInvalidValidityWindow)
+
+		_, err = SetKeyValidity(rootMetadata, "", "2024-01-01T00:00:00Z", "")
+		assert.ErrorIs(t, err, ErrKeyIDEmpty)
+	})
+}
+
+func TestRevokeAndUnrevokeKey(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	rootMetadata, err = RevokeKey(rootMetadata, key.KeyID, "compromised")
+	assert.Nil(t, err)
+	assert.Equal(t, &tuf.KeyRevocation{Reason: "compromised"}, rootMetadata.RevokedKeys[key.KeyID])
+
+	_, err = RevokeKey(rootMetadata, "", "")
+	assert.ErrorIs(t, err, ErrKeyIDEmpty)
+
+	rootMetadata, err = UnrevokeKey(rootMetadata, key.KeyID)
+	assert.Nil(t, err)
+	assert.Nil(t, rootMetadata.RevokedKeys)
+
+	_, err = UnrevokeKey(rootMetadata, key.KeyID)
+	assert.ErrorIs(t, err, ErrKeyNotRevoked)
+}
+
+func TestAddAndRemoveTimestampAuthority(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	certificatePEM := newTestCertificatePEM(t)
+	otherCertificatePEM := newTestCertificatePEM(t)
+
+	rootMetadata, err = AddTimestampAuthority(rootMetadata, certificatePEM)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{string(certificatePEM)}, rootMetadata.TimestampAuthorities)
+
+	// Adding the same authority again is a no-op
+	rootMetadata, err = AddTimestampAuthority(rootMetadata, certificatePEM)
+	assert.Nil(t, err)
+	assert.Len(t, rootMetadata.TimestampAuthorities, 1)
+
+	_, err = AddTimestampAuthority(rootMetadata, []byte("certificate"))
+	assert.ErrorIs(t, err, ErrInvalidTimestampAuthority)
+
+	_, err = RemoveTimestampAuthority(rootMetadata, otherCertificatePEM)
+	assert.ErrorIs(t, err, ErrTimestampAuthorityNotFound)
+
+	rootMetadata, err = RemoveTimestampAuthority(rootMetadata, certificatePEM)
+	assert.Nil(t, err)
+	assert.Nil(t, rootMetadata.TimestampAuthorities)
+}
+
+func newTestCertificatePEM(t *testing.T) []byte {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "gittuf test TSA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateBytes})
+}
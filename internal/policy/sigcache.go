@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+)
+
+// contextWithSignatureCacheScope returns a copy of the context that scopes the
+// signature verification results persisted in the repository's signature cache
+// to the policy state, so that they're reused only as long as the policy
+// doesn't change. The state is identified by a hash of its metadata. The
+// context is returned unchanged if results aren't persisted.
+func (s *State) contextWithSignatureCacheScope(ctx context.Context) context.Context {
+	if !gitinterface.SignatureCacheEnabled() {
+		return ctx
+	}
+
+	if s.signatureCacheScope == "" {
+		scope, err := s.hash()
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Unable to identify policy state for signature cache: %s", err.Error()))
+			return ctx
+		}
+		s.signatureCacheScope = scope
+	}
+
+	return gitinterface.ContextWithSignatureCacheScope(ctx, s.signatureCacheScope)
+}
+
+// hash returns the hex encoded SHA-256 hash of the state's metadata envelopes.
+func (s *State) hash() (string, error) {
+	digest := sha256.New()
+	encoder := json.NewEncoder(digest)
+
+	if err := encoder.Encode(s.RootEnvelope); err != nil {
+		return "", err
+	}
+	if err := encoder.Encode(s.TargetsEnvelope); err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(s.DelegationEnvelopes))
+	for name := range s.DelegationEnvelopes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := encoder.Encode(name); err != nil {
+			return "", err
+		}
+		if err := encoder.Encode(s.DelegationEnvelopes[name]); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateSignatureCacheScope(t *testing.T) {
+	state := createTestStateWithPolicy(t)
+	rootOnlyState := createTestStateWithOnlyRoot(t)
+
+	t.Run("cache disabled", func(t *testing.T) {
+		ctx := context.Background()
+		assert.Equal(t, ctx, state.contextWithSignatureCacheScope(ctx))
+		assert.Empty(t, state.signatureCacheScope)
+	})
+
+	t.Run("cache enabled", func(t *testing.T) {
+		gitinterface.SetSignatureCacheDir(t.TempDir())
+		t.Cleanup(func() { gitinterface.SetSignatureCacheDir("") })
+
+		ctx := context.Background()
+		assert.NotEqual(t, ctx, state.contextWithSignatureCacheScope(ctx))
+		assert.NotEmpty(t, state.signatureCacheScope)
+
+		rootOnlyState.contextWithSignatureCacheScope(ctx)
+		assert.NotEqual(t, state.signatureCacheScope, rootOnlyState.signatureCacheScope)
+
+		hash, err := state.hash()
+		assert.Nil(t, err)
+		assert.Equal(t, state.signatureCacheScope, hash)
+	})
+}
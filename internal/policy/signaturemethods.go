@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+var ErrInvalidSignatureMethod = errors.New("invalid signature method")
+
+// ParseSignatureMethod parses a signature method specified as a signature
+// format, one of "gpg", "ssh", "sigstore", or "smime", optionally followed by
+// a colon and a comma separated list of parameters restricting it. The
+// parameters "algorithm" and "hash" restrict the algorithm of the signing key
+// and the hash algorithm, and "issuer" the OIDC issuer of Sigstore signatures.
+// Parameters may be repeated to accept several values, for example
+// "ssh:algorithm=ed25519,algorithm=ed25519-sk" or
+// "sigstore:issuer=https://token.actions.githubusercontent.com".
+func ParseSignatureMethod(spec string) (tuf.SignatureMethod, error) {
+	format, params, hasParams := strings.Cut(strings.TrimSpace(spec), ":")
+
+	method := tuf.SignatureMethod{Format: strings.ToLower(format)}
+	switch method.Format {
+	case gitinterface.SignatureFormatGPG, gitinterface.SignatureFormatSSH, gitinterface.SignatureFormatSigstore, gitinterface.SignatureFormatSMIME:
+	default:
+		return tuf.SignatureMethod{}, fmt.Errorf("%w: unknown signature format '%s' in '%s'", ErrInvalidSignatureMethod, format, spec)
+	}
+
+	if !hasParams {
+		return method, nil
+	}
+
+	for _, param := range strings.Split(params, ",") {
+		name, value, ok := strings.Cut(param, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return tuf.SignatureMethod{}, fmt.Errorf("%w: expected parameters of the form name=value in '%s'", ErrInvalidSignatureMethod, spec)
+		}
+
+		switch name {
+		case "algorithm":
+			method.Algorithms = append(method.Algorithms, strings.ToLower(value))
+		case "hash":
+			method.Hashes = append(method.Hashes, strings.ToLower(value))
+		case "issuer":
+			if method.Format != gitinterface.SignatureFormatSigstore {
+				return tuf.SignatureMethod{}, fmt.Errorf("%w: issuers can only be set for Sigstore signatures in '%s'", ErrInvalidSignatureMethod, spec)
+			}
+			method.Issuers = append(method.Issuers, value)
+		default:
+			return tuf.SignatureMethod{}, fmt.Errorf("%w: unknown parameter '%s' in '%s'", ErrInvalidSignatureMethod, name, spec)
+		}
+	}
+
+	return method, nil
+}
+
+// FormatSignatureMethod returns the signature method in the form accepted by
+// ParseSignatureMethod.
+func FormatSignatureMethod(method tuf.SignatureMethod) string {
+	params := []string{}
+	for _, algorithm := range method.Algorithms {
+		params = append(params, "algorithm="+algorithm)
+	}
+	for _, hash := range method.Hashes {
+		params = append(params, "hash="+hash)
+	}
+	for _, issuer := range method.Issuers {
+		params = append(params, "issuer="+issuer)
+	}
+
+	if len(params) == 0 {
+		return method.Format
+	}
+
+	return method.Format + ":" + strings.Join(params, ",")
+}
+
+// signatureMethodAllowed returns true if the mechanism used to create a Git
+// signature matches one of the allowed signature methods, or if no methods
+// are specified.
+func signatureMethodAllowed(allowedMethods []tuf.SignatureMethod, method *gitinterface.SignatureMethod) bool {
+	if len(allowedMethods) == 0 {
+		return true
+	}
+
+	for _, allowed := range allowedMethods {
+		if allowed.Format != method.Format {
+			continue
+		}
+		if len(allowed.Algorithms) > 0 && !slices.Contains(allowed.Algorithms, method.Algorithm) {
+			continue
+		}
+		if len(allowed.Hashes) > 0 && !slices.Contains(allowed.Hashes, method.Hash) {
+			continue
+		}
+		if len(allowed.Issuers) > 0 && !slices.Contains(allowed.Issuers, method.Issuer) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSignatureMethod(t *testing.T) {
+	tests := map[string]struct {
+		spec           string
+		expectedMethod tuf.SignatureMethod
+		expectedError  error
+	}{
+		"format only": {
+			spec:           "ssh",
+			expectedMethod: tuf.SignatureMethod{Format: "ssh"},
+		},
+		"repeated algorithms": {
+			spec:           "ssh:algorithm=ed25519,algorithm=ED25519-SK",
+			expectedMethod: tuf.SignatureMethod{Format: "ssh", Algorithms: []string{"ed25519", "ed25519-sk"}},
+		},
+		"GPG hashes": {
+			spec:           "gpg:hash=sha256, hash=sha512",
+			expectedMethod: tuf.SignatureMethod{Format: "gpg", Hashes: []string{"sha256", "sha512"}},
+		},
+		"Sigstore issuer": {
+			spec:           "sigstore:issuer=https://token.actions.githubusercontent.com",
+			expectedMethod: tuf.SignatureMethod{Format: "sigstore", Issuers: []string{"https://token.actions.githubusercontent.com"}},
+		},
+		"unknown format": {
+			spec:          "x509",
+			expectedError: ErrInvalidSignatureMethod,
+		},
+		"unknown parameter": {
+			spec:          "ssh:bits=4096",
+			expectedError: ErrInvalidSignatureMethod,
+		},
+		"parameter without value": {
+			spec:          "ssh:algorithm",
+			expectedError: ErrInvalidSignatureMethod,
+		},
+		"issuer for SSH signatures": {
+			spec:          "ssh:issuer=https://github.com/login/oauth",
+			expectedError: ErrInvalidSignatureMethod,
+		},
+	}
+
+	for name, test := range tests {
+		method, err := ParseSignatureMethod(test.spec)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, "unexpected error in test '%s'", name)
+			continue
+		}
+
+		assert.Nil(t, err, "unexpected error in test '%s'", name)
+		assert.Equal(t, test.expectedMethod, method, "unexpected method in test '%s'", name)
+
+		reparsed, err := ParseSignatureMethod(FormatSignatureMethod(method))
+		assert.Nil(t, err, "unexpected error in test '%s'", name)
+		assert.Equal(t, method, reparsed, "formatted method doesn't round trip in test '%s'", name)
+	}
+}
+
+func TestSignatureMethodAllowed(t *testing.T) {
+	allowedMethods := []tuf.SignatureMethod{
+		{Format: "ssh", Algorithms: []string{"ed25519", "ed25519-sk"}},
+		{Format: "sigstore", Issuers: []string{"https://token.actions.githubusercontent.com"}},
+		{Format: "gpg", Hashes: []string{"sha256", "sha512"}},
+	}
+
+	tests := map[string]struct {
+		method  *gitinterface.SignatureMethod
+		allowed bool
+	}{
+		"allowed SSH algorithm": {
+			method:  &gitinterface.SignatureMethod{Format: "ssh", Algorithm: "ed25519-sk", Hash: "sha512"},
+			allowed: true,
+		},
+		"disallowed SSH algorithm": {
+			method: &gitinterface.SignatureMethod{Format: "ssh", Algorithm: "rsa", Hash: "sha512"},
+		},
+		"allowed Sigstore issuer": {
+			method:  &gitinterface.SignatureMethod{Format: "sigstore", Algorithm: "ecdsa", Hash: "sha256", Issuer: "https://token.actions.githubusercontent.com"},
+			allowed: true,
+		},
+		"disallowed Sigstore issuer": {
+			method: &gitinterface.SignatureMethod{Format: "sigstore", Algorithm: "ecdsa", Hash: "sha256", Issuer: "https://github.com/login/oauth"},
+		},
+		"disallowed GPG hash": {
+			method: &gitinterface.SignatureMethod{Format: "gpg", Algorithm: "rsa", Hash: "sha1"},
+		},
+		"disallowed format": {
+			method: &gitinterface.SignatureMethod{Format: "smime", Algorithm: "rsa", Hash: "sha256"},
+		},
+	}
+
+	for name, test := range tests {
+		assert.Equal(t, test.allowed, signatureMethodAllowed(allowedMethods, test.method), "unexpected result in test '%s'", name)
+	}
+
+	assert.True(t, signatureMethodAllowed(nil, &gitinterface.SignatureMethod{Format: "smime"}))
+}
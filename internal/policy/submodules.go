@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/trace"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var ErrSubmoduleNotVerified = errors.New("submodule commit could not be verified using the submodule's gittuf policy")
+
+// verifySubmoduleUpdates checks that the submodule pointers updated by the
+// commits introduced in the entry were updated by principals trusted for the
+// submodule's path in the submodule namespace. If the rule used to authorize an
+// update requires it, the new submodule commit must also pass verification
+// using the gittuf metadata of the submodule's repository.
+func verifySubmoduleUpdates(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry, authorizationAttestation *sslibdsse.Envelope, entryExplanation *EntryExplanation) error {
+	commits, err := getCommits(repo, entry)
+	if err != nil {
+		return err
+	}
+
+	for _, commit := range commits {
+		updates, err := gitinterface.GetSubmoduleUpdatesByCommit(repo, commit)
+		if err != nil {
+			return err
+		}
+
+		for path, submoduleCommitID := range updates {
+			namespace := fmt.Sprintf("%s:%s", submoduleRuleScheme, path)
+			verifiers, err := policy.FindVerifiersForPath(namespace)
+			if err != nil {
+				return err
+			}
+
+			if len(verifiers) == 0 {
+				continue
+			}
+
+			var submoduleVerifier *Verifier
+			check := entryExplanation.addCheck(namespace, commit, authorizationAttestation)
+			for _, verifier := range verifiers {
+				keyID, err := verifier.verify(ctx, commit, authorizationAttestation)
+				check.addRule(verifier, keyID, err)
+				traceRule(trace.TracerFromContext(ctx), entry, namespace, commit.Hash, verifier, err)
+				if err == nil {
+					submoduleVerifier = verifier
+					break
+				} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
+					// Unexpected error
+					return err
+				}
+			}
+
+			if submoduleVerifier == nil {
+				return fmt.Errorf("verifying submodule namespace policies failed for '%s', %w", path, ErrUnauthorizedSignature)
+			}
+
+			if !submoduleVerifier.RequireVerifiedSubmodule() || submoduleCommitID.IsZero() {
+				continue
+			}
+
+			if err := verifySubmoduleCommit(ctx, repo, commit, path, submoduleCommitID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifySubmoduleCommit checks that the submodule commit recorded at the path
+// is reachable from a ref in the submodule's repository that passes gittuf
+// verification using the submodule's own policy.
+func verifySubmoduleCommit(ctx context.Context, repo *git.Repository, commit *object.Commit, path string, submoduleCommitID plumbing.Hash) error {
+	name, err := gitinterface.GetSubmoduleName(repo, commit, path)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Verifying commit '%s' of submodule '%s'...", submoduleCommitID.String(), name))
+	submoduleRepo, err := gitinterface.OpenSubmoduleRepository(repo, name)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSubmoduleNotVerified, err.Error())
+	}
+
+	submoduleCommit, err := gitinterface.GetCommit(submoduleRepo, submoduleCommitID)
+	if err != nil {
+		return fmt.Errorf("%w: commit '%s' not found in submodule '%s'", ErrSubmoduleNotVerified, submoduleCommitID.String(), name)
+	}
+
+	entry, _, err := rsl.GetLatestNonGittufReferenceEntry(submoduleRepo)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSubmoduleNotVerified, err.Error())
+	}
+
+	verifiedRefs := map[string]bool{}
+	for {
+		if _, seen := verifiedRefs[entry.RefName]; !seen {
+			knowsCommit, err := gitinterface.KnowsCommit(submoduleRepo, entry.TargetID, submoduleCommit)
+			if err != nil {
+				return err
+			}
+
+			if knowsCommit {
+				verifiedRefs[entry.RefName] = true
+
+				logger.Debug(fmt.Sprintf("Verifying '%s' in submodule '%s'...", entry.RefName, name))
+				latestTargetID, err := VerifyRefFull(ctx, submoduleRepo, entry.RefName)
+				if err == nil {
+					// The ref must still contain the commit after verification
+					knowsCommit, err := gitinterface.KnowsCommit(submoduleRepo, latestTargetID, submoduleCommit)
+					if err != nil {
+						return err
+					}
+					if knowsCommit {
+						return nil
+					}
+				} else {
+					logger.Debug(fmt.Sprintf("Verification of '%s' in submodule '%s' failed: %s", entry.RefName, name, err.Error()))
+				}
+			}
+		}
+
+		entry, _, err = rsl.GetNonGittufParentReferenceEntryForEntry(submoduleRepo, entry)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				return fmt.Errorf("%w: commit '%s' of submodule '%s'", ErrSubmoduleNotVerified, submoduleCommitID.String(), name)
+			}
+			return err
+		}
+	}
+}
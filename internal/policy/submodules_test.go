@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySubmoduleUpdates(t *testing.T) {
+	refName := "refs/heads/feature"
+	submoduleCommitID := plumbing.NewHash("a94a8fe5ccb19ba61c4c0873d391e987982fbbd3")
+
+	createEntry := func(t *testing.T, repo *git.Repository, entries []object.TreeEntry, keyBytes []byte) *rsl.ReferenceEntry {
+		t.Helper()
+
+		treeID, err := gitinterface.WriteTree(repo, entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitID, err := gitinterface.CommitUsingSpecificKey(repo, treeID, refName, "Update submodules", keyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := rsl.NewReferenceEntry(refName, commitID)
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		return entry
+	}
+
+	t.Run("unprotected submodule", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithSubmodulePolicy)
+
+		entry := createEntry(t, repo, []object.TreeEntry{{Name: "other", Mode: filemode.Submodule, Hash: submoduleCommitID}}, gpgUnauthorizedKeyBytes)
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unauthorized submodule update", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithSubmodulePolicy)
+
+		entry := createEntry(t, repo, []object.TreeEntry{{Name: "lib", Mode: filemode.Submodule, Hash: submoduleCommitID}}, gpgUnauthorizedKeyBytes)
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("authorized submodule update that cannot be verified", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithSubmodulePolicy)
+
+		// The submodule's repository is not available in memory
+		entry := createEntry(t, repo, []object.TreeEntry{{Name: "lib", Mode: filemode.Submodule, Hash: submoduleCommitID}}, gpgKeyBytes)
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrSubmoduleNotVerified)
+	})
+
+	t.Run("authorized submodule removal", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithSubmodulePolicy)
+
+		createEntry(t, repo, []object.TreeEntry{{Name: "other", Mode: filemode.Submodule, Hash: submoduleCommitID}}, gpgKeyBytes)
+
+		entry := createEntry(t, repo, []object.TreeEntry{{Name: "lib", Mode: filemode.Submodule, Hash: submoduleCommitID}}, gpgKeyBytes)
+		assert.ErrorIs(t, verifyEntry(testCtx, repo, state, nil, entry), ErrSubmoduleNotVerified)
+
+		entry = createEntry(t, repo, []object.TreeEntry{{Name: "other", Mode: filemode.Submodule, Hash: submoduleCommitID}}, gpgKeyBytes)
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.Nil(t, err)
+	})
+}
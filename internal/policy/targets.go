@@ -11,7 +11,10 @@ import (
 
 const AllowRuleName = "gittuf-allow-rule"
 
-var ErrCannotManipulateAllowRule = errors.New("cannot change in-built gittuf-allow-rule")
+var (
+	ErrCannotManipulateAllowRule = errors.New("cannot change in-built gittuf-allow-rule")
+	ErrPredicatePolicyNotFound   = errors.New("no policy found for predicate type")
+)
 
 // InitializeTargetsMetadata creates a new instance of TargetsMetadata.
 func InitializeTargetsMetadata() *tuf.TargetsMetadata {
@@ -125,6 +128,228 @@ func AddKeyToTargets(targetsMetadata *tuf.TargetsMetadata, authorizedKeys []*tuf
 	return targetsMetadata, nil
 }
 
+// SetPredicatePolicy records the keys trusted to issue attestations of the
+// specified predicate type and the threshold of signatures required. If a
+// validator is specified, the predicate plugin of that name must also accept
+// the contents of the attestations. An existing policy for the predicate type
+// is replaced.
+func SetPredicatePolicy(targetsMetadata *tuf.TargetsMetadata, predicateType string, authorizedKeys []*tuf.Key, threshold int, validator string) (*tuf.TargetsMetadata, error) {
+	if threshold < 1 || len(authorizedKeys) < threshold {
+		return nil, ErrCannotMeetThreshold
+	}
+
+	authorizedKeyIDs := []string{}
+	for _, key := range authorizedKeys {
+		targetsMetadata.Delegations.AddKey(key)
+
+		authorizedKeyIDs = append(authorizedKeyIDs, key.KeyID)
+	}
+
+	newPredicatePolicy := tuf.PredicatePolicy{
+		PredicateType: predicateType,
+		Role: tuf.Role{
+			KeyIDs:    authorizedKeyIDs,
+			Threshold: threshold,
+		},
+		Validator: validator,
+	}
+
+	for index, predicatePolicy := range targetsMetadata.PredicatePolicies {
+		if predicatePolicy.PredicateType == predicateType {
+			targetsMetadata.PredicatePolicies[index] = newPredicatePolicy
+			return targetsMetadata, nil
+		}
+	}
+
+	targetsMetadata.PredicatePolicies = append(targetsMetadata.PredicatePolicies, newPredicatePolicy)
+	return targetsMetadata, nil
+}
+
+// RemovePredicatePolicy deletes the policy for the specified predicate type
+// from TargetsMetadata.
+func RemovePredicatePolicy(targetsMetadata *tuf.TargetsMetadata, predicateType string) (*tuf.TargetsMetadata, error) {
+	updatedPredicatePolicies := []tuf.PredicatePolicy{}
+	for _, predicatePolicy := range targetsMetadata.PredicatePolicies {
+		if predicatePolicy.PredicateType != predicateType {
+			updatedPredicatePolicies = append(updatedPredicatePolicies, predicatePolicy)
+		}
+	}
+
+	if len(updatedPredicatePolicies) == len(targetsMetadata.PredicatePolicies) {
+		return nil, ErrPredicatePolicyNotFound
+	}
+
+	targetsMetadata.PredicatePolicies = updatedPredicatePolicies
+	return targetsMetadata, nil
+}
+
+// SetRequiredHooks records the hooks that must have been executed successfully
+// for a change to be authorized using the specified rule. An empty list of
+// hooks removes the requirement.
+func SetRequiredHooks(targetsMetadata *tuf.TargetsMetadata, ruleName string, hookNames []string) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	for index, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			if len(hookNames) == 0 {
+				hookNames = nil
+			}
+			targetsMetadata.Delegations.Roles[index].RequiredHooks = hookNames
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetRequiredRebuilds records the artifacts that must have been reproduced by
+// independent rebuilders for a tag to be authorized using the specified rule.
+// The rebuilders and the number of them that must agree are set using the
+// predicate policy for rebuild attestations. An empty list of artifacts
+// removes the requirement.
+func SetRequiredRebuilds(targetsMetadata *tuf.TargetsMetadata, ruleName string, artifactNames []string) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	for index, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			if len(artifactNames) == 0 {
+				artifactNames = nil
+			}
+			targetsMetadata.Delegations.Roles[index].RequiredRebuilds = artifactNames
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetRequiredEvaluators records the rule evaluator plugins that must allow a
+// change for it to be authorized using the specified rule. An empty list of
+// evaluators removes the requirement.
+func SetRequiredEvaluators(targetsMetadata *tuf.TargetsMetadata, ruleName string, evaluatorNames []string) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	for index, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			if len(evaluatorNames) == 0 {
+				evaluatorNames = nil
+			}
+			targetsMetadata.Delegations.Roles[index].RequiredEvaluators = evaluatorNames
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetRequiredPredicates records the predicate types of the in-toto statements
+// that must have been attached to a change for it to be authorized using the
+// specified rule. The keys trusted to sign statements of each type are set
+// using the predicate policy for the type. An empty list of predicate types
+// removes the requirement.
+func SetRequiredPredicates(targetsMetadata *tuf.TargetsMetadata, ruleName string, predicateTypes []string) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	for index, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			if len(predicateTypes) == 0 {
+				predicateTypes = nil
+			}
+			targetsMetadata.Delegations.Roles[index].RequiredPredicates = predicateTypes
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetRequiredApprovals records that a change must have been approved in code
+// review by a threshold of the principals authorized by the approvers rule for
+// it to be authorized using the specified rule. The approvers rule must be in
+// the same metadata file. A threshold of zero removes the requirement.
+func SetRequiredApprovals(targetsMetadata *tuf.TargetsMetadata, ruleName, approversRuleName string, threshold int) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	var requirement *tuf.ApprovalRequirement
+	if threshold > 0 {
+		approversRuleFound := false
+		for _, delegation := range targetsMetadata.Delegations.Roles {
+			if delegation.Name != approversRuleName || delegation.Name == AllowRuleName {
+				continue
+			}
+
+			if len(delegation.KeyIDs) < threshold {
+				return nil, ErrCannotMeetThreshold
+			}
+			approversRuleFound = true
+			break
+		}
+		if !approversRuleFound {
+			return nil, ErrDelegationNotFound
+		}
+
+		requirement = &tuf.ApprovalRequirement{Role: approversRuleName, Threshold: threshold}
+	}
+
+	for index, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			targetsMetadata.Delegations.Roles[index].RequiredApprovals = requirement
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetRequireVerifiedSubmodule records whether submodule pointers protected by
+// the specified rule may only be updated to commits that pass verification
+// using the submodule repository's gittuf metadata.
+func SetRequireVerifiedSubmodule(targetsMetadata *tuf.TargetsMetadata, ruleName string, requireVerified bool) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	for index, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			targetsMetadata.Delegations.Roles[index].RequireVerifiedSubmodule = requireVerified
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetAllowedSignatureMethods records the mechanisms of Git signatures that
+// count towards the threshold of the specified rule. An empty list of methods
+// removes the restriction.
+func SetAllowedSignatureMethods(targetsMetadata *tuf.TargetsMetadata, ruleName string, methods []tuf.SignatureMethod) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	for index, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			if len(methods) == 0 {
+				methods = nil
+			}
+			targetsMetadata.Delegations.Roles[index].AllowedSignatureMethods = methods
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
 // AllowRule returns the default, last rule for all policy files.
 func AllowRule() tuf.Delegation {
 	return tuf.Delegation{
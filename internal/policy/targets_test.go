@@ -145,6 +145,273 @@ func TestAddKeyToTargets(t *testing.T) {
 	})
 }
 
+func TestSetAndRemovePredicatePolicy(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key1, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := tuf.LoadKeyFromBytes(targets2PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	predicateType := "https://slsa.dev/provenance/v1"
+
+	_, err = SetPredicatePolicy(targetsMetadata, predicateType, []*tuf.Key{key1}, 2, "")
+	assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+
+	targetsMetadata, err = SetPredicatePolicy(targetsMetadata, predicateType, []*tuf.Key{key1}, 1, "")
+	assert.Nil(t, err)
+	assert.Equal(t, key1, targetsMetadata.Delegations.Keys[key1.KeyID])
+	assert.Equal(t, []tuf.PredicatePolicy{{
+		PredicateType: predicateType,
+		Role:          tuf.Role{KeyIDs: []string{key1.KeyID}, Threshold: 1},
+	}}, targetsMetadata.PredicatePolicies)
+
+	// Setting the policy again replaces it
+	targetsMetadata, err = SetPredicatePolicy(targetsMetadata, predicateType, []*tuf.Key{key1, key2}, 2, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []tuf.PredicatePolicy{{
+		PredicateType: predicateType,
+		Role:          tuf.Role{KeyIDs: []string{key1.KeyID, key2.KeyID}, Threshold: 2},
+	}}, targetsMetadata.PredicatePolicies)
+
+	targetsMetadata, err = RemovePredicatePolicy(targetsMetadata, predicateType)
+	assert.Nil(t, err)
+	assert.Empty(t, targetsMetadata.PredicatePolicies)
+
+	_, err = RemovePredicatePolicy(targetsMetadata, predicateType)
+	assert.ErrorIs(t, err, ErrPredicatePolicyNotFound)
+}
+
+func TestSetRequiredHooks(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-main", []*tuf.Key{key}, []string{"git:refs/heads/main"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetRequiredHooks(targetsMetadata, "protect-main", []string{"secret-scan"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"secret-scan"}, targetsMetadata.Delegations.Roles[0].RequiredHooks)
+
+	// Updating the rule retains its required hooks
+	targetsMetadata, err = UpdateDelegation(targetsMetadata, "protect-main", []*tuf.Key{key}, []string{"git:refs/heads/*"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"secret-scan"}, targetsMetadata.Delegations.Roles[0].RequiredHooks)
+
+	targetsMetadata, err = SetRequiredHooks(targetsMetadata, "protect-main", []string{})
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].RequiredHooks)
+
+	_, err = SetRequiredHooks(targetsMetadata, "unknown-rule", []string{"secret-scan"})
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetRequiredHooks(targetsMetadata, AllowRuleName, []string{"secret-scan"})
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetRequiredEvaluators(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-main", []*tuf.Key{key}, []string{"git:refs/heads/main"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetRequiredEvaluators(targetsMetadata, "protect-main", []string{"change-approval"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"change-approval"}, targetsMetadata.Delegations.Roles[0].RequiredEvaluators)
+
+	targetsMetadata, err = SetRequiredEvaluators(targetsMetadata, "protect-main", []string{})
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].RequiredEvaluators)
+
+	_, err = SetRequiredEvaluators(targetsMetadata, "unknown-rule", []string{"change-approval"})
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetRequiredEvaluators(targetsMetadata, AllowRuleName, []string{"change-approval"})
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetRequiredPredicates(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-main", []*tuf.Key{key}, []string{"git:refs/heads/main"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	predicateTypes := []string{"https://example.com/code-review/v1", "https://slsa.dev/verification_summary/v1"}
+	targetsMetadata, err = SetRequiredPredicates(targetsMetadata, "protect-main", predicateTypes)
+	assert.Nil(t, err)
+	assert.Equal(t, predicateTypes, targetsMetadata.Delegations.Roles[0].RequiredPredicates)
+
+	targetsMetadata, err = SetRequiredPredicates(targetsMetadata, "protect-main", []string{})
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].RequiredPredicates)
+
+	_, err = SetRequiredPredicates(targetsMetadata, "unknown-rule", predicateTypes)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetRequiredPredicates(targetsMetadata, AllowRuleName, predicateTypes)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetRequiredApprovals(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key1, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := tuf.LoadKeyFromBytes(targets2PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-main", []*tuf.Key{key1}, []string{"git:refs/heads/main"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = AddDelegation(targetsMetadata, "reviewers", []*tuf.Key{key1, key2}, []string{"git:refs/heads/reviewers-only"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetRequiredApprovals(targetsMetadata, "protect-main", "reviewers", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, &tuf.ApprovalRequirement{Role: "reviewers", Threshold: 2}, targetsMetadata.Delegations.Roles[0].RequiredApprovals)
+
+	_, err = SetRequiredApprovals(targetsMetadata, "protect-main", "reviewers", 3)
+	assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+
+	_, err = SetRequiredApprovals(targetsMetadata, "protect-main", "unknown-rule", 1)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetRequiredApprovals(targetsMetadata, "unknown-rule", "reviewers", 1)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetRequiredApprovals(targetsMetadata, AllowRuleName, "reviewers", 1)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+
+	targetsMetadata, err = SetRequiredApprovals(targetsMetadata, "protect-main", "", 0)
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].RequiredApprovals)
+}
+
+func TestSetRequiredRebuilds(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-tags", []*tuf.Key{key}, []string{"git:refs/tags/*"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetRequiredRebuilds(targetsMetadata, "protect-tags", []string{"gittuf-linux-amd64"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"gittuf-linux-amd64"}, targetsMetadata.Delegations.Roles[0].RequiredRebuilds)
+
+	// Updating the rule retains its required rebuilds
+	targetsMetadata, err = UpdateDelegation(targetsMetadata, "protect-tags", []*tuf.Key{key}, []string{"git:refs/tags/v*"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"gittuf-linux-amd64"}, targetsMetadata.Delegations.Roles[0].RequiredRebuilds)
+
+	targetsMetadata, err = SetRequiredRebuilds(targetsMetadata, "protect-tags", []string{})
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].RequiredRebuilds)
+
+	_, err = SetRequiredRebuilds(targetsMetadata, "unknown-rule", []string{"gittuf-linux-amd64"})
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetRequiredRebuilds(targetsMetadata, AllowRuleName, []string{"gittuf-linux-amd64"})
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetRequireVerifiedSubmodule(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-lib", []*tuf.Key{key}, []string{"submodule:lib"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetRequireVerifiedSubmodule(targetsMetadata, "protect-lib", true)
+	assert.Nil(t, err)
+	assert.True(t, targetsMetadata.Delegations.Roles[0].RequireVerifiedSubmodule)
+
+	targetsMetadata, err = SetRequireVerifiedSubmodule(targetsMetadata, "protect-lib", false)
+	assert.Nil(t, err)
+	assert.False(t, targetsMetadata.Delegations.Roles[0].RequireVerifiedSubmodule)
+
+	_, err = SetRequireVerifiedSubmodule(targetsMetadata, "unknown-rule", true)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetRequireVerifiedSubmodule(targetsMetadata, AllowRuleName, true)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetAllowedSignatureMethods(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddDelegation(targetsMetadata, "protect-main", []*tuf.Key{key}, []string{"git:refs/heads/main"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	methods := []tuf.SignatureMethod{{Format: "ssh", Algorithms: []string{"ed25519"}}}
+	targetsMetadata, err = SetAllowedSignatureMethods(targetsMetadata, "protect-main", methods)
+	assert.Nil(t, err)
+	assert.Equal(t, methods, targetsMetadata.Delegations.Roles[0].AllowedSignatureMethods)
+
+	targetsMetadata, err = SetAllowedSignatureMethods(targetsMetadata, "protect-main", []tuf.SignatureMethod{})
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].AllowedSignatureMethods)
+
+	_, err = SetAllowedSignatureMethods(targetsMetadata, "unknown-rule", methods)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetAllowedSignatureMethods(targetsMetadata, AllowRuleName, methods)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
 func TestAllowRule(t *testing.T) {
 	allowRule := AllowRule()
 	assert.Equal(t, AllowRuleName, allowRule.Name)
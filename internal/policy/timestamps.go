@@ -0,0 +1,319 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/timestamp"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var ErrKeyValidityUnmet = errors.New("signature was not timestamped while the key that issued it was valid")
+
+// RecordTimestamps obtains timestamps for the signatures using the timestamper
+// set using timestamp.SetTimestamper and records them in the attestations
+// namespace. Signatures that are timestamped already are skipped. It does
+// nothing if no timestamper is set.
+func RecordTimestamps(ctx context.Context, repo *git.Repository, signatures [][]byte, signCommit bool) error {
+	timestamper := timestamp.GetTimestamper()
+	if timestamper == nil || len(signatures) == 0 {
+		return nil
+	}
+
+	allAttestations, err := attestations.LoadCurrentAttestations(repo)
+	if err != nil {
+		return err
+	}
+
+	recorded := 0
+	for _, signature := range signatures {
+		if allAttestations.HasTimestampFor(signature) {
+			continue
+		}
+
+		logger.Debug(fmt.Sprintf("Timestamping signature '%s'...", attestations.TimestampPath(signature)))
+		ts, err := timestamper.Timestamp(ctx, signature)
+		if err != nil {
+			return fmt.Errorf("unable to timestamp signature: %w", err)
+		}
+
+		if err := allAttestations.SetTimestamp(repo, signature, ts); err != nil {
+			return err
+		}
+		recorded++
+	}
+
+	if recorded == 0 {
+		return nil
+	}
+
+	if _, err := repo.Reference(plumbing.ReferenceName(attestations.Ref), true); errors.Is(err, plumbing.ErrReferenceNotFound) {
+		if err := attestations.InitializeNamespace(repo); err != nil {
+			return err
+		}
+	}
+
+	return allAttestations.Commit(repo, fmt.Sprintf("Record timestamps for %d signature(s)", recorded), signCommit)
+}
+
+// getTrustedSigningTime returns the time at which the signature, typically of
+// an RSL entry, was timestamped. The timestamp is verified using the timestamp
+// authorities trusted in the policy, or using the Rekor instance carried by
+// the context for timestamps recorded in Rekor. The zero time is returned if
+// the signature isn't timestamped or its timestamp can't be verified, as the
+// dates of commits are set by their authors and can't be trusted.
+func (s *State) getTrustedSigningTime(ctx context.Context, repo *git.Repository, signature []byte) (time.Time, error) {
+	if len(signature) == 0 {
+		return time.Time{}, nil
+	}
+
+	allAttestations, err := attestations.LoadCurrentAttestations(repo)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	ts, err := allAttestations.GetTimestampFor(repo, signature)
+	if err != nil {
+		if errors.Is(err, attestations.ErrTimestampNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	rootMetadata, err := s.GetRootMetadata()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	roots, err := timestamp.LoadAuthorityRoots(rootMetadata.TimestampAuthorities)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var rekorPublicKey crypto.PublicKey
+	if client := rekor.ClientFromContext(ctx); client != nil && ts.RekorEntry != nil {
+		rekorPublicKey, err = client.PublicKey(ctx)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	timestampTime, err := ts.Verify(signature, roots, rekorPublicKey)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Ignoring timestamp of signature '%s': %s", attestations.TimestampPath(signature), err.Error()))
+		return time.Time{}, nil
+	}
+
+	return timestampTime, nil
+}
+
+// ContextWithTrustedSigningTimes returns a copy of the context that determines
+// when Git objects were signed using the timestamps recorded for their
+// signatures in the repository, verified using the timestamp authorities
+// trusted in the repository's current policy. They're used to check if the
+// revocations carried by the context apply to signatures, and to check S/MIME
+// certificates. The policy is only loaded if a signing time is needed.
+func ContextWithTrustedSigningTimes(ctx context.Context, repo *git.Repository) context.Context {
+	var (
+		stateOnce sync.Once
+		state     *State
+		stateErr  error
+	)
+	return gitinterface.ContextWithSigningTimes(ctx, func(ctx context.Context, signature []byte) (time.Time, error) {
+		stateOnce.Do(func() {
+			state, stateErr = LoadCurrentState(ctx, repo, PolicyRef)
+		})
+		if stateErr != nil {
+			return time.Time{}, stateErr
+		}
+
+		return state.getTrustedSigningTime(ctx, repo, signature)
+	})
+}
+
+// envelopeSignatures returns the decoded signatures of the envelopes.
+func envelopeSignatures(envelopes ...*sslibdsse.Envelope) ([][]byte, error) {
+	signatures := [][]byte{}
+	for _, env := range envelopes {
+		if env == nil {
+			continue
+		}
+
+		for _, sig := range env.Signatures {
+			signature, err := base64.StdEncoding.DecodeString(sig.Sig)
+			if err != nil {
+				return nil, err
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+
+	return signatures, nil
+}
+
+// keyValidity checks that the signatures issued by keys with validity windows
+// in the policy were timestamped within the windows, and that keys revoked in
+// the policy aren't trusted. The timestamps are loaded from the latest
+// attestations state, as they're bound to the signatures rather than to the
+// changes the signatures are for.
+type keyValidity struct {
+	repo    *git.Repository
+	windows map[string]*tuf.ValidityWindow
+	revoked map[string]*tuf.KeyRevocation
+	roots   *x509.CertPool
+
+	attestationsOnce  sync.Once
+	attestationsState *attestations.Attestations
+	attestationsErr   error
+}
+
+// getKeyValidity returns the key validity checker for the state. It returns nil
+// if the policy doesn't restrict the validity of any keys or revoke any keys.
+// The checker isn't stored in the state, so that states with the same metadata
+// are equal regardless of which verifiers were created for them.
+func (s *State) getKeyValidity() (*keyValidity, error) {
+	rootMetadata, err := s.GetRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rootMetadata.KeyValidity) == 0 && len(rootMetadata.RevokedKeys) == 0 {
+		return nil, nil
+	}
+
+	roots, err := timestamp.LoadAuthorityRoots(rootMetadata.TimestampAuthorities)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyValidity{
+		repo:    s.repository,
+		windows: rootMetadata.KeyValidity,
+		revoked: rootMetadata.RevokedKeys,
+		roots:   roots,
+	}, nil
+}
+
+// restricts indicates if signatures issued by the key must be timestamped
+// within the key's validity window, or are rejected as the key is revoked.
+func (k *keyValidity) restricts(keyID string) bool {
+	if k == nil {
+		return false
+	}
+
+	_, hasWindow := k.windows[keyID]
+	_, isRevoked := k.revoked[keyID]
+	return hasWindow || isRevoked
+}
+
+// check verifies the timestamp of the signature issued by the key, if the key
+// has a validity window, and checks that the signature was timestamped within
+// the window. Timestamps recorded in Rekor are verified using the Rekor
+// instance carried by the context. If the key is revoked, an error wrapping
+// gitinterface.ErrKeyRevoked is returned.
+func (k *keyValidity) check(ctx context.Context, keyID string, signature []byte) error {
+	if !k.restricts(keyID) {
+		return nil
+	}
+
+	if revocation, isRevoked := k.revoked[keyID]; isRevoked {
+		if revocation != nil && revocation.Reason != "" {
+			return fmt.Errorf("%w: key '%s' is revoked in the policy: %s", gitinterface.ErrKeyRevoked, keyID, revocation.Reason)
+		}
+		return fmt.Errorf("%w: key '%s' is revoked in the policy", gitinterface.ErrKeyRevoked, keyID)
+	}
+
+	window, hasWindow := k.windows[keyID]
+	if !hasWindow {
+		return nil
+	}
+
+	ts, err := k.getTimestamp(signature)
+	if err != nil {
+		if errors.Is(err, attestations.ErrTimestampNotFound) {
+			return fmt.Errorf("%w: signature issued by key '%s' is not timestamped", ErrKeyValidityUnmet, keyID)
+		}
+		return err
+	}
+
+	var rekorPublicKey crypto.PublicKey
+	if client := rekor.ClientFromContext(ctx); client != nil && ts.RekorEntry != nil {
+		rekorPublicKey, err = client.PublicKey(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	timestampTime, err := ts.Verify(signature, k.roots, rekorPublicKey)
+	if err != nil {
+		return fmt.Errorf("%w: unable to verify timestamp of signature issued by key '%s': %w", ErrKeyValidityUnmet, keyID, err)
+	}
+
+	if window.NotBefore != "" {
+		notBefore, err := time.Parse(time.RFC3339, window.NotBefore)
+		if err != nil {
+			return err
+		}
+		if timestampTime.Before(notBefore) {
+			return fmt.Errorf("%w: signature issued by key '%s' was timestamped at %s, before the key became valid at %s", ErrKeyValidityUnmet, keyID, timestampTime.Format(time.RFC3339), window.NotBefore)
+		}
+	}
+
+	if window.NotAfter != "" {
+		notAfter, err := time.Parse(time.RFC3339, window.NotAfter)
+		if err != nil {
+			return err
+		}
+		if timestampTime.After(notAfter) {
+			return fmt.Errorf("%w: signature issued by key '%s' was timestamped at %s, after the key stopped being valid at %s", ErrKeyValidityUnmet, keyID, timestampTime.Format(time.RFC3339), window.NotAfter)
+		}
+	}
+
+	return nil
+}
+
+func (k *keyValidity) getTimestamp(signature []byte) (*timestamp.Timestamp, error) {
+	if k.repo == nil {
+		return nil, attestations.ErrTimestampNotFound
+	}
+
+	k.attestationsOnce.Do(func() {
+		k.attestationsState, k.attestationsErr = attestations.LoadCurrentAttestations(k.repo)
+	})
+	if k.attestationsErr != nil {
+		return nil, k.attestationsErr
+	}
+
+	return k.attestationsState.GetTimestampFor(k.repo, signature)
+}
+
+// keyValidityVerifier wraps the DSSE verifier for a key with a validity window,
+// so that only signatures timestamped within the window are accepted. If the
+// key is revoked, none of its signatures are accepted.
+type keyValidityVerifier struct {
+	sslibdsse.Verifier
+	keyID       string
+	keyValidity *keyValidity
+}
+
+func (v *keyValidityVerifier) Verify(ctx context.Context, data, sig []byte) error {
+	if err := v.Verifier.Verify(ctx, data, sig); err != nil {
+		return err
+	}
+
+	return v.keyValidity.check(ctx, v.keyID, sig)
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/trace"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// traceEntry records an event of the specified type for the RSL entry.
+func traceEntry(tracer *trace.Tracer, eventType, target string, entry *rsl.ReferenceEntry) {
+	tracer.Record(trace.Event{
+		Type:     eventType,
+		Ref:      target,
+		EntryID:  entry.ID.String(),
+		TargetID: entry.TargetID.String(),
+	})
+}
+
+// traceEntryVerified records the result of verifying the RSL entry.
+func traceEntryVerified(tracer *trace.Tracer, entry *rsl.ReferenceEntry, err error) {
+	event := trace.Event{
+		Type:     trace.EventEntryVerified,
+		Ref:      entry.RefName,
+		EntryID:  entry.ID.String(),
+		TargetID: entry.TargetID.String(),
+	}
+	event.Result, event.Error = trace.ResultOf(err)
+
+	tracer.Record(event)
+}
+
+// tracePolicy records that the policy state recorded in the RSL entry is used
+// to verify the target. err indicates if the state failed verification against
+// the previous policy state.
+func tracePolicy(tracer *trace.Tracer, target string, policyEntry *rsl.ReferenceEntry, err error) {
+	event := trace.Event{
+		Type:          trace.EventPolicyLoaded,
+		Ref:           target,
+		PolicyEntryID: policyEntry.ID.String(),
+		PolicyID:      policyEntry.TargetID.String(),
+	}
+	event.Result, event.Error = trace.ResultOf(err)
+
+	tracer.Record(event)
+}
+
+// traceRule records the evaluation of the verifier's rule for the Git object
+// in the namespace.
+func traceRule(tracer *trace.Tracer, entry *rsl.ReferenceEntry, namespace string, objectID plumbing.Hash, verifier *Verifier, err error) {
+	if tracer == nil {
+		return
+	}
+
+	event := trace.Event{
+		Type:      trace.EventRuleEvaluated,
+		Ref:       entry.RefName,
+		EntryID:   entry.ID.String(),
+		Namespace: namespace,
+		ObjectID:  objectID.String(),
+		Rule:      verifier.Name(),
+		KeyIDs:    make([]string, 0, len(verifier.Keys())),
+		Threshold: verifier.Threshold(),
+	}
+	for _, key := range verifier.Keys() {
+		event.KeyIDs = append(event.KeyIDs, key.KeyID)
+	}
+	event.Result, event.Error = trace.ResultOf(err)
+
+	tracer.Record(event)
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/trace"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	refName := "refs/heads/main"
+
+	readEvents := func(t *testing.T, buf *bytes.Buffer) []trace.Event {
+		t.Helper()
+
+		events := []trace.Event{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			event := trace.Event{}
+			if err := json.Unmarshal([]byte(line), &event); err != nil {
+				t.Fatal(err)
+			}
+			events = append(events, event)
+		}
+
+		return events
+	}
+
+	t.Run("successful verification", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		buf := &bytes.Buffer{}
+		ctx := trace.ContextWithTracer(context.Background(), trace.NewTracer(buf))
+
+		err := verifyEntry(ctx, repo, state, nil, entry)
+		assert.Nil(t, err)
+
+		events := readEvents(t, buf)
+		if !assert.Len(t, events, 3) {
+			return
+		}
+
+		assert.Equal(t, trace.EventRuleEvaluated, events[0].Type)
+		assert.Equal(t, "git:refs/heads/main", events[0].Namespace)
+		assert.Equal(t, entryID.String(), events[0].ObjectID)
+		assert.Equal(t, "protect-main", events[0].Rule)
+		assert.Equal(t, 1, events[0].Threshold)
+		assert.Equal(t, trace.ResultPass, events[0].Result)
+
+		// The commit modifies file 1, which is protected by a file rule
+		assert.Equal(t, trace.EventRuleEvaluated, events[1].Type)
+		assert.Equal(t, "file:1", events[1].Namespace)
+		assert.Equal(t, commitIDs[0].String(), events[1].ObjectID)
+		assert.Equal(t, "protect-files-1-and-2", events[1].Rule)
+		assert.Equal(t, trace.ResultPass, events[1].Result)
+
+		assert.Equal(t, trace.EventEntryVerified, events[2].Type)
+		assert.Equal(t, refName, events[2].Ref)
+		assert.Equal(t, entryID.String(), events[2].EntryID)
+		assert.Equal(t, commitIDs[0].String(), events[2].TargetID)
+		assert.Equal(t, trace.ResultPass, events[2].Result)
+	})
+
+	t.Run("unmet threshold", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		buf := &bytes.Buffer{}
+		ctx := trace.ContextWithTracer(context.Background(), trace.NewTracer(buf))
+
+		err := verifyEntry(ctx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		events := readEvents(t, buf)
+		if !assert.Len(t, events, 2) {
+			return
+		}
+
+		assert.Equal(t, trace.EventRuleEvaluated, events[0].Type)
+		assert.Equal(t, 2, events[0].Threshold)
+		assert.Equal(t, trace.ResultFail, events[0].Result)
+		assert.Contains(t, events[0].Error, "no reference authorization was found")
+
+		assert.Equal(t, trace.EventEntryVerified, events[1].Type)
+		assert.Equal(t, trace.ResultFail, events[1].Result)
+		assert.Equal(t, err.Error(), events[1].Error)
+	})
+	t.Run("full verification workflow", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		buf := &bytes.Buffer{}
+		ctx := trace.ContextWithTracer(context.Background(), trace.NewTracer(buf))
+
+		_, err := VerifyRefFull(ctx, repo, refName)
+		assert.Nil(t, err)
+
+		events := readEvents(t, buf)
+		eventTypes := []string{}
+		for _, event := range events {
+			eventTypes = append(eventTypes, event.Type)
+		}
+		// The initial policy is loaded from the first entry, for the policy
+		// staging ref, and loaded again from the entry for the policy ref
+		assert.Equal(t, []string{
+			trace.EventPolicyLoaded,
+			trace.EventVerificationStarted,
+			trace.EventPolicyLoaded,
+			trace.EventRuleEvaluated,
+			trace.EventRuleEvaluated,
+			trace.EventEntryVerified,
+			trace.EventVerificationFinished,
+		}, eventTypes)
+
+		assert.NotEmpty(t, events[0].PolicyEntryID)
+		assert.NotEmpty(t, events[0].PolicyID)
+		assert.Equal(t, trace.ResultPass, events[len(events)-1].Result)
+	})
+}
@@ -4,21 +4,30 @@ package policy
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
 	"strings"
 
+	"github.com/gittuf/gittuf/internal/archivista"
 	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common/set"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/identity"
+	"github.com/gittuf/gittuf/internal/plugin"
+	"github.com/gittuf/gittuf/internal/progress"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/report"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/common"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/trace"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	ita "github.com/in-toto/attestation/go/v1"
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
@@ -29,6 +38,7 @@ const (
 	noPublicKeyMessage                = "no public key found for Git object"
 	unableToLoadPolicyMessageFmt      = "unable to load applicable gittuf policy: %s"
 	unableToFindPolicyMessage         = "unable to find applicable gittuf policy"
+	goodSignatureMessagePrefix        = "good signature"
 	goodSignatureMessageFmt           = "good signature from key '%s:%s'"
 	goodTagSignatureMessage           = "good signature for RSL entry and tag"
 	goodSignatureMessageForRSLEntry   = "good signature for RSL entry"
@@ -40,12 +50,22 @@ const (
 )
 
 var (
-	ErrUnauthorizedSignature   = errors.New("unauthorized signature")
-	ErrInvalidEntryNotSkipped  = errors.New("invalid entry found not marked as skipped")
-	ErrLastGoodEntryIsSkipped  = errors.New("entry expected to be unskipped is marked as skipped")
-	ErrUnknownObjectType       = errors.New("unknown object type passed to verify signature")
-	ErrInvalidVerifier         = errors.New("verifier has invalid parameters (is threshold 0?)")
-	ErrVerifierConditionsUnmet = errors.New("verifier's key and threshold constraints not met")
+	ErrUnauthorizedSignature      = errors.New("unauthorized signature")
+	ErrInvalidEntryNotSkipped     = errors.New("invalid entry found not marked as skipped")
+	ErrLastGoodEntryIsSkipped     = errors.New("entry expected to be unskipped is marked as skipped")
+	ErrUnknownObjectType          = errors.New("unknown object type passed to verify signature")
+	ErrInvalidVerifier            = errors.New("verifier has invalid parameters (is threshold 0?)")
+	ErrVerifierConditionsUnmet    = errors.New("verifier's key and threshold constraints not met")
+	ErrRequiredHookNotPassed      = errors.New("required hook was not executed successfully")
+	ErrRequiredRebuildsNotMet     = errors.New("required artifact was not reproduced by enough rebuilders")
+	ErrRequiredEvaluatorNotPassed = errors.New("required rule evaluator did not allow the change")
+	ErrRequiredPredicatesNotMet   = errors.New("required statement was not attested to by enough signers")
+	ErrRequiredApprovalsNotMet    = errors.New("change was not approved in code review by enough principals")
+	ErrInvalidPredicate           = errors.New("attestation's predicate is invalid")
+	ErrAttestationNotInRekor      = errors.New("attestation was not logged to Rekor")
+	ErrShallowAnchorNotFound      = errors.New("no RSL entry for the reference can be verified using the history available in the shallow clone, fetch more history using 'git fetch --deepen'")
+	ErrUnsignedObject             = errors.New(noSignatureMessage)
+	ErrUnknownSigningKey          = errors.New("Git object was not signed by any key in the policy") //nolint:stylecheck
 )
 
 // VerifyRef verifies the signature on the latest RSL entry for the target ref
@@ -53,27 +73,27 @@ var (
 // entry is returned if the policy verification is successful.
 func VerifyRef(ctx context.Context, repo *git.Repository, target string) (plumbing.Hash, error) {
 	// Get latest policy entry
-	slog.Debug("Loading policy...")
+	logger.Debug("Loading policy...")
 	policyState, err := LoadCurrentState(ctx, repo, PolicyRef)
 	if err != nil {
 		return plumbing.ZeroHash, err
 	}
 
 	// Find latest entry for target
-	slog.Debug(fmt.Sprintf("Identifying latest RSL entry for '%s'...", target))
+	logger.Debug(fmt.Sprintf("Identifying latest RSL entry for '%s'...", target))
 	latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, target)
 	if err != nil {
 		return plumbing.ZeroHash, err
 	}
 
 	// Find latest set of attestations
-	slog.Debug("Loading current set of attestations...")
+	logger.Debug("Loading current set of attestations...")
 	attestationsState, err := attestations.LoadCurrentAttestations(repo)
 	if err != nil {
 		return plumbing.ZeroHash, err
 	}
 
-	slog.Debug("Verifying entry...")
+	logger.Debug("Verifying entry...")
 	return latestEntry.TargetID, verifyEntry(ctx, repo, policyState, attestationsState, latestEntry)
 }
 
@@ -82,14 +102,14 @@ func VerifyRef(ctx context.Context, repo *git.Repository, target string) (plumbi
 // the policy verification is successful.
 func VerifyRefFull(ctx context.Context, repo *git.Repository, target string) (plumbing.Hash, error) {
 	// Trace RSL back to the start
-	slog.Debug("Identifying first RSL entry...")
+	logger.Debug("Identifying first RSL entry...")
 	firstEntry, _, err := rsl.GetFirstEntry(repo)
 	if err != nil {
 		return plumbing.ZeroHash, err
 	}
 
 	// Find latest entry for target
-	slog.Debug(fmt.Sprintf("Identifying latest RSL entry for '%s'...", target))
+	logger.Debug(fmt.Sprintf("Identifying latest RSL entry for '%s'...", target))
 	latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, target)
 	if err != nil {
 		return plumbing.ZeroHash, err
@@ -97,16 +117,57 @@ func VerifyRefFull(ctx context.Context, repo *git.Repository, target string) (pl
 
 	// Do a relative verify from start entry to the latest entry (firstEntry here == policyEntry)
 	// Also, attestations is initially nil because we haven't seen any yet
-	slog.Debug("Verifying all entries...")
+	logger.Debug("Verifying all entries...")
 	return latestEntry.TargetID, VerifyRelativeForRef(ctx, repo, firstEntry, nil, firstEntry, latestEntry, target)
 }
 
+// VerifyRefFromShallowAnchor verifies the RSL for the target ref in a shallow
+// clone. As the commits introduced by older entries for the ref aren't
+// available, the entries for the ref are verified after an anchor entry, the
+// latest entry for the ref whose target commit is within the shallow boundary
+// but whose introduced commits are not. All entries for the policy are still
+// verified from the first entry in the RSL. The expected Git ID for the ref in
+// the latest RSL entry is returned with the anchor entry if the policy
+// verification is successful. The anchor is nil if the history of all the
+// entries for the ref is available.
+func VerifyRefFromShallowAnchor(ctx context.Context, repo *git.Repository, target string) (plumbing.Hash, *rsl.ReferenceEntry, error) {
+	logger.Debug("Identifying first RSL entry...")
+	firstEntry, _, err := rsl.GetFirstEntry(repo)
+	if err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("Identifying latest RSL entry for '%s'...", target))
+	latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, target)
+	if err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+
+	logger.Debug("Identifying anchor entry within shallow boundary...")
+	anchorEntry, err := findShallowAnchor(repo, latestEntry)
+	if err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+	if anchorEntry != nil {
+		if anchorEntry.ID == latestEntry.ID {
+			// Nothing would be verified for the ref
+			return plumbing.ZeroHash, nil, ErrShallowAnchorNotFound
+		}
+
+		logger.Debug(fmt.Sprintf("Verifying entries after anchor entry '%s'...", anchorEntry.ID.String()))
+		ExplanationFromContext(ctx).setShallowAnchor(anchorEntry)
+	}
+
+	logger.Debug("Verifying all entries...")
+	return latestEntry.TargetID, anchorEntry, verifyRelativeForRef(ctx, repo, firstEntry, nil, firstEntry, latestEntry, target, anchorEntry)
+}
+
 // VerifyRefFromEntry performs verification for the reference from a specific
 // RSL entry. The expected Git ID for the ref in the latest RSL entry is
 // returned if the policy verification is successful.
 func VerifyRefFromEntry(ctx context.Context, repo *git.Repository, target string, entryID plumbing.Hash) (plumbing.Hash, error) {
 	// Load starting point entry
-	slog.Debug("Identifying starting RSL entry...")
+	logger.Debug("Identifying starting RSL entry...")
 	fromEntryT, err := rsl.GetEntry(repo, entryID)
 	if err != nil {
 		return plumbing.ZeroHash, err
@@ -120,20 +181,20 @@ func VerifyRefFromEntry(ctx context.Context, repo *git.Repository, target string
 	}
 
 	// Find latest entry for target
-	slog.Debug(fmt.Sprintf("Identifying latest RSL entry for '%s'...", target))
+	logger.Debug(fmt.Sprintf("Identifying latest RSL entry for '%s'...", target))
 	latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, target)
 	if err != nil {
 		return plumbing.ZeroHash, err
 	}
 
 	// Find policy entry before the starting point entry
-	slog.Debug("Identifying applicable policy entry...")
+	logger.Debug("Identifying applicable policy entry...")
 	policyEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, fromEntry.GetID())
 	if err != nil {
 		return plumbing.ZeroHash, err
 	}
 
-	slog.Debug("Identifying applicable attestations entry...")
+	logger.Debug("Identifying applicable attestations entry...")
 	var attestationsEntry *rsl.ReferenceEntry
 	attestationsEntry, _, err = rsl.GetLatestReferenceEntryForRefBefore(repo, attestations.Ref, fromEntry.GetID())
 	if err != nil {
@@ -143,7 +204,7 @@ func VerifyRefFromEntry(ctx context.Context, repo *git.Repository, target string
 	}
 
 	// Do a relative verify from start entry to the latest entry
-	slog.Debug("Verifying all entries...")
+	logger.Debug("Verifying all entries...")
 	return latestEntry.TargetID, VerifyRelativeForRef(ctx, repo, policyEntry, attestationsEntry, fromEntry, latestEntry, target)
 }
 
@@ -152,36 +213,65 @@ func VerifyRefFromEntry(ctx context.Context, repo *git.Repository, target string
 //
 // TODO: should the policy entry be inferred from the specified first entry?
 func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPolicyEntry, initialAttestationsEntry, firstEntry, lastEntry *rsl.ReferenceEntry, target string) error {
+	return verifyRelativeForRef(ctx, repo, initialPolicyEntry, initialAttestationsEntry, firstEntry, lastEntry, target, nil)
+}
+
+// verifyRelativeForRef implements VerifyRelativeForRef. If an anchor entry is
+// specified, the entries for the target ref up to and including the anchor
+// are not verified.
+func verifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPolicyEntry, initialAttestationsEntry, firstEntry, lastEntry *rsl.ReferenceEntry, target string, anchorEntry *rsl.ReferenceEntry) (err error) {
 	var (
 		currentPolicy       *State
 		currentAttestations *attestations.Attestations
 	)
 
+	tracer := trace.TracerFromContext(ctx)
+	defer func() {
+		event := trace.Event{Type: trace.EventVerificationFinished, Ref: target, EntryID: lastEntry.ID.String()}
+		event.Result, event.Error = trace.ResultOf(err)
+		tracer.Record(event)
+	}()
+
 	// Load policy applicable at firstEntry
-	slog.Debug("Loading initial policy...")
+	logger.Debug("Loading initial policy...")
 	state, err := LoadState(ctx, repo, initialPolicyEntry)
 	if err != nil {
 		return err
 	}
 	currentPolicy = state
+	tracePolicy(tracer, target, initialPolicyEntry, nil)
 
 	if initialAttestationsEntry != nil {
-		slog.Debug("Loading attestations...")
+		logger.Debug("Loading attestations...")
 		attestationsState, err := attestations.LoadAttestationsForEntry(repo, initialAttestationsEntry)
 		if err != nil {
 			return err
 		}
 		currentAttestations = attestationsState
+		traceEntry(tracer, trace.EventAttestationsLoaded, target, initialAttestationsEntry)
 	}
 
 	// Enumerate RSL entries between firstEntry and lastEntry, ignoring irrelevant ones
-	slog.Debug("Identifying all entries in range...")
+	logger.Debug("Identifying all entries in range...")
 	entries, annotations, err := rsl.GetReferenceEntriesInRangeForRef(repo, firstEntry.ID, lastEntry.ID, target)
 	if err != nil {
 		return err
 	}
 
+	tracer.Record(trace.Event{Type: trace.EventVerificationStarted, Ref: target, EntryID: firstEntry.ID.String(), Count: len(entries)})
+
+	reporter := progress.ReporterFromContext(ctx)
+	reporter.Start(target, len(entries))
+	defer reporter.Finish()
+
+	if gitinterface.BatchVerifierFromContext(ctx) == nil {
+		ctx = gitinterface.ContextWithBatchVerifier(ctx, gitinterface.NewBatchVerifier(0))
+	}
+	logger.Debug("Verifying entry signatures concurrently...")
+	prefetchEntrySignatures(ctx, repo, currentPolicy, entries)
+
 	// Verify each entry, looking for a fix when an invalid entry is encountered
+	anchorReached := anchorEntry == nil
 	var invalidEntry *rsl.ReferenceEntry
 	var verificationErr error
 	for len(entries) != 0 {
@@ -189,14 +279,16 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 			// Pop entry from queue
 			entry := entries[0]
 			entries = entries[1:]
+			reporter.Increment()
 
-			slog.Debug(fmt.Sprintf("Verifying entry '%s'...", entry.ID.String()))
+			logger.Debug(fmt.Sprintf("Verifying entry '%s'...", entry.ID.String()))
 
-			slog.Debug("Checking if entry is for policy staging reference...")
+			logger.Debug("Checking if entry is for policy staging reference...")
 			if entry.RefName == PolicyStagingRef {
+				traceEntry(tracer, trace.EventEntrySkipped, target, entry)
 				continue
 			}
-			slog.Debug("Checking if entry is for policy reference...")
+			logger.Debug("Checking if entry is for policy reference...")
 			if entry.RefName == PolicyRef {
 				// TODO: this is repetition if the firstEntry is for policy
 				newPolicy, err := loadStateForEntry(repo, entry)
@@ -204,17 +296,25 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 					return err
 				}
 
-				slog.Debug("Verifying new policy using current policy...")
+				logger.Debug("Verifying new policy using current policy...")
 				if err := currentPolicy.VerifyNewState(ctx, newPolicy); err != nil {
+					tracePolicy(tracer, target, entry, err)
+					return err
+				}
+
+				logger.Debug("Verifying justification for new policy...")
+				if err := verifyPolicyJustification(ctx, repo, currentPolicy, newPolicy, currentAttestations, entry.TargetID); err != nil {
+					tracePolicy(tracer, target, entry, err)
 					return err
 				}
 
-				slog.Debug("Updating current policy...")
+				logger.Debug("Updating current policy...")
 				currentPolicy = newPolicy
+				tracePolicy(tracer, target, entry, nil)
 				continue
 			}
 
-			slog.Debug("Checking if entry is for attestations reference...")
+			logger.Debug("Checking if entry is for attestations reference...")
 			if entry.RefName == attestations.Ref {
 				newAttestationsState, err := attestations.LoadAttestationsForEntry(repo, entry)
 				if err != nil {
@@ -222,12 +322,21 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 				}
 
 				currentAttestations = newAttestationsState
+				traceEntry(tracer, trace.EventAttestationsLoaded, target, entry)
+				continue
+			}
+
+			if !anchorReached && entry.RefName == target {
+				// The commits introduced by this entry aren't available
+				logger.Debug("Entry precedes anchor entry, skipping...")
+				anchorReached = entry.ID == anchorEntry.ID
+				traceEntry(tracer, trace.EventEntrySkipped, target, entry)
 				continue
 			}
 
-			slog.Debug("Verifying changes...")
+			logger.Debug("Verifying changes...")
 			if err := verifyEntry(ctx, repo, currentPolicy, currentAttestations, entry); err != nil {
-				slog.Debug("Violation found, checking if entry has been revoked...")
+				logger.Debug("Violation found, checking if entry has been revoked...")
 				// If the invalid entry is never marked as skipped, we return err
 				if !entry.SkippedBy(annotations[entry.ID]) {
 					return err
@@ -235,7 +344,7 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 
 				// The invalid entry's been marked as skipped but we still need
 				// to see if another entry fixed state for non-gittuf users
-				slog.Debug("Entry has been revoked, searching for fix entry...")
+				logger.Debug("Entry has been revoked, searching for fix entry...")
 				invalidEntry = entry
 				verificationErr = err
 
@@ -264,12 +373,12 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 		// are processed even when an invalid state is reached.
 
 		// 1. What's the last good state?
-		slog.Debug("Identifying last valid state...")
+		logger.Debug("Identifying last valid state...")
 		lastGoodEntry, lastGoodEntryAnnotations, err := rsl.GetLatestUnskippedReferenceEntryForRefBefore(repo, invalidEntry.RefName, invalidEntry.ID)
 		if err != nil {
 			return err
 		}
-		slog.Debug("Verifying identified last valid entry has not been revoked...")
+		logger.Debug("Verifying identified last valid entry has not been revoked...")
 		if lastGoodEntry.SkippedBy(lastGoodEntryAnnotations) {
 			return ErrLastGoodEntryIsSkipped
 		}
@@ -292,31 +401,33 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 			newEntry := entries[0]
 			entries = entries[1:]
 
-			slog.Debug(fmt.Sprintf("Inspecting entry '%s' to see if it's a fix entry...", newEntry.ID.String()))
+			logger.Debug(fmt.Sprintf("Inspecting entry '%s' to see if it's a fix entry...", newEntry.ID.String()))
 
-			slog.Debug("Checking if entry is for the affected reference...")
+			logger.Debug("Checking if entry is for the affected reference...")
 			if newEntry.RefName != invalidEntry.RefName {
 				// Unrelated entry that must be processed in the outer loop
 				// Currently this is just policy entries
 				newEntryQueue = append(newEntryQueue, newEntry)
 				continue
 			}
+			reporter.Increment()
 
 			newEntryCommit, err := gitinterface.GetCommit(repo, newEntry.TargetID)
 			if err != nil {
 				return err
 			}
 
-			slog.Debug("Checking if entry is tree-same with last valid state...")
+			logger.Debug("Checking if entry is tree-same with last valid state...")
 			if newEntryCommit.TreeHash == lastGoodTreeID {
 				// Fix found, we append the rest of the current verification set
 				// to the new entry queue
 				// But first, we must check that this fix hasn't been skipped
 				// If it has been skipped, it's not actually a fix and we need
 				// to keep looking
-				slog.Debug("Verifying potential fix entry has not been revoked...")
+				logger.Debug("Verifying potential fix entry has not been revoked...")
 				if !newEntry.SkippedBy(annotations[newEntry.ID]) {
-					slog.Debug("Fix entry found, proceeding with regular verification workflow...")
+					logger.Debug("Fix entry found, proceeding with regular verification workflow...")
+					traceEntry(tracer, trace.EventFixEntryFound, target, newEntry)
 					fixed = true
 					newEntryQueue = append(newEntryQueue, entries...)
 					break
@@ -325,7 +436,7 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 
 			// newEntry is not tree-same / commit-same, so it is automatically
 			// invalid, check that it's been marked as revoked
-			slog.Debug("Checking non-fix entry has been revoked as well...")
+			logger.Debug("Checking non-fix entry has been revoked as well...")
 			if !newEntry.SkippedBy(annotations[newEntry.ID]) {
 				invalidIntermediateEntries = append(invalidIntermediateEntries, newEntry)
 			}
@@ -353,6 +464,12 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 	return nil
 }
 
+// IsVerifiedStatus returns true if the status returned by VerifyCommit or
+// VerifyTag for an object indicates that the object was verified.
+func IsVerifiedStatus(status string) bool {
+	return strings.HasPrefix(status, goodSignatureMessagePrefix)
+}
+
 // VerifyCommit verifies the signature on the specified commits (identified by
 // their hash or via a reference that is resolved). For each commit, the policy
 // applicable when the commit was first recorded (directly or indirectly) in the
@@ -361,10 +478,12 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 // have an entry in the returned status. The status is currently meant to be
 // consumed directly by the user, as this is used for a special, user-invoked
 // workflow. gittuf's other verification workflows are currently not expected to
-// use this function.
+// use this function. If the context carries a Report, the signature checked
+// for each commit is recorded in it.
 func VerifyCommit(ctx context.Context, repo *git.Repository, ids ...string) map[string]string {
 	status := make(map[string]string, len(ids))
 	commits := make(map[string]*object.Commit, len(ids))
+	verifiedKeys := make(map[string]*tuf.Key, len(ids))
 
 	for _, id := range ids {
 		if gitinterface.IsTag(repo, id) {
@@ -418,10 +537,13 @@ func VerifyCommit(ctx context.Context, repo *git.Repository, ids ...string) map[
 			status[id] = fmt.Sprintf(unableToLoadPolicyMessageFmt, err.Error())
 			continue
 		}
+
+		commitCtx := commitPolicy.contextWithSignatureCacheScope(ctx)
 		for _, key := range keys {
-			err = gitinterface.VerifyCommitSignature(ctx, commit, key)
+			err = gitinterface.VerifyCommitSignature(commitCtx, commit, key)
 			if err == nil {
 				verified = true
+				verifiedKeys[id] = key
 				status[id] = fmt.Sprintf(goodSignatureMessageFmt, key.KeyType, key.KeyID)
 				break
 			}
@@ -442,15 +564,24 @@ func VerifyCommit(ctx context.Context, repo *git.Repository, ids ...string) map[
 		}
 	}
 
+	if verificationReport := report.FromContext(ctx); verificationReport != nil {
+		for _, id := range ids {
+			verificationReport.Add(newCommitResult(id, commits[id], verifiedKeys[id], status[id]))
+		}
+	}
+
 	return status
 }
 
 // VerifyTag verifies the signature on the RSL entries for the specified tags.
 // In addition, each tag object's signature is also verified using the same set
 // of trusted keys. If the tag is not protected by policy, then all keys in the
-// applicable policy are used to verify the signatures.
+// applicable policy are used to verify the signatures. If the context carries a
+// Report, the signatures checked for each tag are recorded in it.
 func VerifyTag(ctx context.Context, repo *git.Repository, ids []string) map[string]string {
 	status := make(map[string]string, len(ids))
+	verificationReport := report.FromContext(ctx)
+	reported := make(map[string]bool, len(ids))
 
 	for _, id := range ids {
 		// Check if id is tag name or hash of tag obj
@@ -499,13 +630,41 @@ func VerifyTag(ctx context.Context, repo *git.Repository, ids []string) map[stri
 			continue
 		}
 
-		if err := verifyTagEntry(ctx, repo, policy, entry); err == nil {
+		var attestationsState *attestations.Attestations
+		attestationsEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, attestations.Ref, entry.ID)
+		if err == nil {
+			attestationsState, err = attestations.LoadAttestationsForEntry(repo, attestationsEntry)
+			if err != nil {
+				status[id] = err.Error()
+				continue
+			}
+		} else if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			status[id] = err.Error()
+			continue
+		}
+
+		var entryExplanation *EntryExplanation
+		if verificationReport != nil {
+			entryExplanation = newEntryExplanation(entry, gitinterface.AllowedSignersFromContext(ctx))
+		}
+
+		err = verifyTagEntry(policy.contextWithSignatureCacheScope(ctx), repo, policy, attestationsState, entry, entryExplanation)
+		reportEntry(verificationReport, entryExplanation, err)
+		reported[id] = true
+		if err == nil {
 			status[id] = goodTagSignatureMessage
 		} else {
 			status[id] = err.Error()
 		}
 	}
 
+	for _, id := range ids {
+		if !reported[id] {
+			// The tag's RSL entry wasn't verified
+			verificationReport.Add(&report.Result{Kind: report.KindTag, ObjectID: id, Error: status[id]})
+		}
+	}
+
 	return status
 }
 
@@ -520,19 +679,177 @@ func (s *State) VerifyNewState(ctx context.Context, newPolicy *State) error {
 	return rootVerifier.Verify(ctx, nil, newPolicy.RootEnvelope)
 }
 
+// VerifyAttestationSignatures checks that the attestation is signed by the
+// keys trusted to issue attestations of its predicate type, meeting the
+// threshold set in the policy. If the predicate policy specifies a validator,
+// the predicate plugin must also accept the attestation's contents. If the
+// policy does not specify the keys trusted for the predicate type, the
+// attestation must be signed by at least one key trusted in the policy. It
+// does not check whether the key is trusted for the change described in the
+// attestation, that happens when the attestation is used during verification.
+func (s *State) VerifyAttestationSignatures(ctx context.Context, env *sslibdsse.Envelope) error {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return err
+	}
+
+	statement := &ita.Statement{}
+	if err := json.Unmarshal(payload, statement); err != nil {
+		return err
+	}
+
+	predicateVerifier, err := s.FindVerifierForPredicateType(statement.PredicateType)
+	if err == nil {
+		if err := predicateVerifier.Verify(ctx, nil, env); err != nil {
+			return err
+		}
+
+		return s.validatePredicate(ctx, statement.PredicateType, payload)
+	} else if !errors.Is(err, ErrPredicatePolicyNotFound) {
+		return err
+	}
+
+	publicKeys, err := s.PublicKeys()
+	if err != nil {
+		return err
+	}
+
+	keyValidity, err := s.getKeyValidity()
+	if err != nil {
+		return err
+	}
+
+	verifier := &Verifier{
+		name:        "attestations",
+		keys:        make([]*tuf.Key, 0, len(publicKeys)),
+		threshold:   1,
+		keyValidity: keyValidity,
+	}
+	for _, key := range publicKeys {
+		if _, err := signerverifier.NewSignerVerifierFromTUFKey(key); err != nil { //nolint:staticcheck
+			// Only keys that can sign DSSE envelopes are relevant
+			continue
+		}
+		verifier.keys = append(verifier.keys, key)
+	}
+
+	return verifier.Verify(ctx, nil, env)
+}
+
+// validatePredicate checks that the predicate plugin set as the validator in
+// the policy for the predicate type, if any, accepts the attestation's in-toto
+// statement.
+func (s *State) validatePredicate(ctx context.Context, predicateType string, statement []byte) error {
+	predicatePolicy, _, err := s.findPredicatePolicy(predicateType)
+	if err != nil {
+		return err
+	}
+	if predicatePolicy.Validator == "" {
+		return nil
+	}
+
+	validator, err := plugin.LoadPredicateValidator(predicatePolicy.Validator)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPredicate, err)
+	}
+
+	result, err := validator.Validate(ctx, statement)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPredicate, err)
+	}
+	if !result.Valid {
+		return fmt.Errorf("%w: predicate plugin '%s' rejected the attestation: %s", ErrInvalidPredicate, predicatePolicy.Validator, result.Reason)
+	}
+
+	return nil
+}
+
+// signatureFailureReason explains why the Git object's signature was not
+// authorized: ErrUnsignedObject is returned if the object is not signed, and
+// ErrUnknownSigningKey if it was not signed by any key in the policy. nil is
+// returned if the object was signed by a key in the policy that isn't trusted
+// for the change, or if the reason cannot be determined.
+func signatureFailureReason(ctx context.Context, policy *State, gitObject object.Object) error {
+	var verify func(*tuf.Key) error
+	switch o := gitObject.(type) {
+	case *object.Commit:
+		if len(o.PGPSignature) == 0 {
+			return ErrUnsignedObject
+		}
+		verify = func(key *tuf.Key) error {
+			return gitinterface.VerifyCommitSignature(ctx, o, key)
+		}
+	case *object.Tag:
+		if len(o.PGPSignature) == 0 {
+			return ErrUnsignedObject
+		}
+		verify = func(key *tuf.Key) error {
+			return gitinterface.VerifyTagSignature(ctx, o, key)
+		}
+	default:
+		return nil
+	}
+
+	allKeys, err := policy.PublicKeys()
+	if err != nil || len(allKeys) == 0 {
+		return nil
+	}
+
+	for _, key := range allKeys {
+		err := verify(key)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) && !errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+			return nil
+		}
+	}
+
+	return ErrUnknownSigningKey
+}
+
 // verifyEntry is a helper to verify an entry's signature using the specified
 // policy. The specified policy is used for the RSL entry itself. However, for
 // commit signatures, verifyEntry checks when the commit was first introduced
 // via the RSL across all refs. Then, it uses the policy applicable at the
 // commit's first entry into the repository. If the commit is brand new to the
-// repository, the specified policy is used.
+// repository, the specified policy is used. If the context carries an
+// Explanation, the checks performed for an entry that fails verification are
+// recorded in it. If the context carries a Report, the signatures checked for
+// the entry are recorded in it. If the context carries a Tracer, the rules
+// evaluated and the result are recorded as events.
 func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry) error {
-	if entry.RefName == PolicyRef || entry.RefName == attestations.Ref {
+	if entry.RefName == PolicyRef || entry.RefName == attestations.Ref || IsMergeQueueRef(entry.RefName) {
 		return nil
 	}
 
+	if err := identity.Check(entry.RefName); err != nil {
+		// Rules protecting the ref the name resembles don't apply to it
+		logger.Warn("RSL entry records a ref whose name can be confused with other ref names", "ref", entry.RefName, "error", err)
+	}
+
+	ctx = policy.contextWithSignatureCacheScope(ctx)
+
+	explanation := ExplanationFromContext(ctx)
+	verificationReport := report.FromContext(ctx)
+	entryExplanation := explanation.startEntry(entry, gitinterface.AllowedSignersFromContext(ctx))
+	if entryExplanation == nil && verificationReport != nil {
+		entryExplanation = newEntryExplanation(entry, gitinterface.AllowedSignersFromContext(ctx))
+	}
+
+	err := verifyEntryWithExplanation(ctx, repo, policy, attestationsState, entry, entryExplanation)
+	explanation.finishEntry(entryExplanation, err)
+	reportEntry(verificationReport, entryExplanation, err)
+	traceEntryVerified(trace.TracerFromContext(ctx), entry, err)
+
+	return err
+}
+
+// verifyEntryWithExplanation implements verifyEntry, recording the checks
+// performed in entryExplanation.
+func verifyEntryWithExplanation(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry, entryExplanation *EntryExplanation) error {
 	if strings.HasPrefix(entry.RefName, gitinterface.TagRefPrefix) {
-		return verifyTagEntry(ctx, repo, policy, entry)
+		return verifyTagEntry(ctx, repo, policy, attestationsState, entry, entryExplanation)
 	}
 
 	var (
@@ -541,7 +858,8 @@ func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, attes
 	)
 
 	// Find authorized verifiers for entry's ref
-	verifiers, err := policy.FindVerifiersForPath(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName))
+	namespace := fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName)
+	verifiers, err := policy.FindVerifiersForPath(namespace)
 	if err != nil {
 		return err
 	}
@@ -557,20 +875,22 @@ func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, attes
 		return err
 	}
 
-	var authorizationAttestation *sslibdsse.Envelope
-	if attestationsState != nil {
-		authorizationAttestation, err = getAuthorizationAttestation(repo, attestationsState, entry)
-		if err != nil {
-			return err
-		}
+	authorizationAttestation, err := getAuthorizationAttestation(ctx, repo, policy, attestationsState, entry, commitObj)
+	if err != nil {
+		return err
 	}
 
 	// Use each verifier to verify signature
+	check := entryExplanation.addCheck(namespace, commitObj, authorizationAttestation)
+	var gitNamespaceVerifier *Verifier
 	for _, verifier := range verifiers {
-		err := verifier.Verify(ctx, commitObj, authorizationAttestation)
+		keyID, err := verifier.verify(ctx, commitObj, authorizationAttestation)
+		check.addRule(verifier, keyID, err)
+		traceRule(trace.TracerFromContext(ctx), entry, namespace, commitObj.Hash, verifier, err)
 		if err == nil {
 			// Signature verification succeeded
 			gitNamespaceVerified = true
+			gitNamespaceVerifier = verifier
 			break
 		} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
 			// Unexpected error
@@ -580,9 +900,51 @@ func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, attes
 	}
 
 	if !gitNamespaceVerified {
+		// The entry may have advanced the branch to commits created by a merge
+		// queue, which don't match the reference authorizations
+		gitNamespaceVerifier, err = verifyMergeQueueEntry(ctx, repo, policy, attestationsState, entry, commitObj, namespace, verifiers, entryExplanation)
+		if err != nil {
+			return err
+		}
+		gitNamespaceVerified = gitNamespaceVerifier != nil
+	}
+
+	if !gitNamespaceVerified {
+		if reason := signatureFailureReason(ctx, policy, commitObj); reason != nil {
+			return fmt.Errorf("verifying Git namespace policies failed, %w: %w", ErrUnauthorizedSignature, reason)
+		}
 		return fmt.Errorf("verifying Git namespace policies failed, %w", ErrUnauthorizedSignature)
 	}
 
+	if gitNamespaceVerifier != nil {
+		if err := verifyRequiredHooks(ctx, repo, policy, attestationsState, entry, gitNamespaceVerifier.RequiredHooks()); err != nil {
+			return err
+		}
+
+		if err := verifyRequiredPredicates(ctx, repo, policy, attestationsState, entry.RefName, entry.TargetID.String(), gitNamespaceVerifier.RequiredPredicates()); err != nil {
+			return err
+		}
+
+		if err := verifyRequiredEvaluators(ctx, repo, entry, gitNamespaceVerifier.Name(), gitNamespaceVerifier.RequiredEvaluators()); err != nil {
+			return err
+		}
+
+		if err := verifyRequiredApprovals(ctx, repo, policy, attestationsState, entry.RefName, entry.TargetID.String(), gitNamespaceVerifier); err != nil {
+			return err
+		}
+	}
+
+	hasSubmoduleRule, err := policy.hasSubmoduleRule()
+	if err != nil {
+		return err
+	}
+
+	if hasSubmoduleRule {
+		if err := verifySubmoduleUpdates(ctx, repo, policy, entry, authorizationAttestation, entryExplanation); err != nil {
+			return err
+		}
+	}
+
 	hasFileRule, err := policy.hasFileRule()
 	if err != nil {
 		return err
@@ -600,21 +962,30 @@ func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, attes
 		return err
 	}
 
+	// Only changes to paths that file rules can match need to be verified
+	pathPrefixes, err := policy.getFileRulePathPrefixes()
+	if err != nil {
+		return err
+	}
+
+	commitsPaths, err := gitinterface.GetFilePathsChangedByCommits(repo, commits, pathPrefixes)
+	if err != nil {
+		return err
+	}
+
 	commitsVerified := make([]bool, len(commits))
 	for i, commit := range commits {
 		// Assume the commit's paths are verified, if a path is left unverified,
 		// we flip this later.
 		commitsVerified[i] = true
 
-		paths, err := gitinterface.GetFilePathsChangedByCommit(repo, commit)
-		if err != nil {
-			return err
-		}
+		paths := commitsPaths[i]
 
 		pathsVerified := make([]bool, len(paths))
 		verifiedUsing := "" // this will be set after one successful verification of the commit to avoid repeated signature verification
 		for j, path := range paths {
-			verifiers, err := policy.FindVerifiersForPath(fmt.Sprintf("%s:%s", fileRuleScheme, path))
+			namespace := fmt.Sprintf("%s:%s", fileRuleScheme, path)
+			verifiers, err := policy.FindVerifiersForPath(namespace)
 			if err != nil {
 				return err
 			}
@@ -646,8 +1017,11 @@ func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, attes
 				continue
 			}
 
+			check := entryExplanation.addCheck(namespace, commit, authorizationAttestation)
 			for _, verifier := range verifiers {
-				err := verifier.Verify(ctx, commit, authorizationAttestation)
+				keyID, err := verifier.verify(ctx, commit, authorizationAttestation)
+				check.addRule(verifier, keyID, err)
+				traceRule(trace.TracerFromContext(ctx), entry, namespace, commit.Hash, verifier, err)
 				if err == nil {
 					// Signature verification succeeded
 					pathsVerified[j] = true
@@ -686,115 +1060,454 @@ func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, attes
 	return nil
 }
 
-func verifyTagEntry(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
-	// 1. Find authorized public keys for tag's RSL entry
-	trustedKeys, err := policy.FindPublicKeysForPath(ctx, fmt.Sprintf("git:%s", entry.RefName))
-	if err != nil {
-		return err
-	}
+// verifyRequiredHooks checks that each of the specified hooks was executed
+// successfully for the entry's target, as recorded in hook execution
+// attestations. Each attestation must be signed by keys trusted by the policy
+// to issue hook execution attestations.
+func verifyRequiredHooks(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry, hookNames []string) error {
+	for _, hookName := range hookNames {
+		if attestationsState == nil {
+			return fmt.Errorf("%w: no attestation found for hook '%s'", ErrRequiredHookNotPassed, hookName)
+		}
 
-	if len(trustedKeys) == 0 {
-		allKeys, err := policy.PublicKeys()
+		env, err := attestationsState.GetHookExecutionAttestationFor(repo, entry.RefName, entry.TargetID.String(), hookName)
 		if err != nil {
+			if errors.Is(err, attestations.ErrHookExecutionNotFound) {
+				return fmt.Errorf("%w: no attestation found for hook '%s'", ErrRequiredHookNotPassed, hookName)
+			}
+
 			return err
 		}
 
-		// FIXME: decide if we want to pass around map or slice for these APIs
-		for _, key := range allKeys {
-			trustedKeys = append(trustedKeys, key)
+		result, err := attestations.GetHookExecutionResult(env)
+		if err != nil {
+			return err
 		}
-	}
-
-	// 2. Find commit object for the RSL entry
-	commitObj, err := gitinterface.GetCommit(repo, entry.ID)
-	if err != nil {
-		return err
-	}
-
-	// 3. Use each trusted key to verify signature
-	rslEntryVerified := false
-	for _, key := range trustedKeys {
-		err := gitinterface.VerifyCommitSignature(ctx, commitObj, key)
-		if err == nil {
-			// Signature verification succeeded
-			rslEntryVerified = true
-			break
+		if result != attestations.HookResultPass {
+			return fmt.Errorf("%w: hook '%s' reported result '%s'", ErrRequiredHookNotPassed, hookName, result)
 		}
-		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
-			// We encounter this for key types that can be used for gittuf
-			// policy metadata but not Git objects
-			continue
+
+		if err := policy.VerifyAttestationSignatures(ctx, env); err != nil {
+			return fmt.Errorf("verifying attestation for hook '%s' failed: %w", hookName, err)
 		}
-		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
-			// Unexpected error
-			return err
+
+		if err := verifyRekorInclusion(ctx, repo, attestationsState, env); err != nil {
+			return fmt.Errorf("verifying attestation for hook '%s' failed: %w", hookName, err)
 		}
-		// Haven't found a valid key, continue with next key
 	}
 
-	if !rslEntryVerified {
-		return fmt.Errorf("verifying RSL entry failed, %w", ErrUnauthorizedSignature)
-	}
+	return nil
+}
 
-	// 4. Verify tag object
-	tagObjVerified := false
-	tagObj, err := gitinterface.GetTag(repo, entry.TargetID)
+// VerifyRequiredPredicatesForRef checks the in-toto statements recorded for
+// the reference's change to the target against the latest policy, so that the
+// change can be checked before it is recorded in the RSL. The statements must
+// meet the requirements of at least one of the rules protecting the reference
+// that require statements. If no such rule exists, there is nothing to check.
+func VerifyRequiredPredicatesForRef(ctx context.Context, repo *git.Repository, refName, targetID string) error {
+	policy, err := LoadCurrentState(ctx, repo, PolicyRef)
 	if err != nil {
-		// Likely indicates the ref is not pointing to a tag object
-		// What about lightweight tags?
 		return err
 	}
 
-	entryTagRef, err := repo.Reference(plumbing.ReferenceName(entry.RefName), true)
+	attestationsState, err := attestations.LoadCurrentAttestations(repo)
 	if err != nil {
 		return err
 	}
 
-	if entry.TargetID != entryTagRef.Hash() && entry.TargetID != tagObj.Target {
-		return fmt.Errorf("verifying RSL entry failed, tag reference set to unexpected target")
-	}
-
-	if len(tagObj.PGPSignature) == 0 {
-		return fmt.Errorf(noSignatureMessage)
+	verifiers, err := policy.FindVerifiersForPath(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, refName))
+	if err != nil {
+		return err
 	}
 
-	for _, key := range trustedKeys {
-		err := gitinterface.VerifyTagSignature(ctx, tagObj, key)
-		if err == nil {
-			// Signature verification succeeded
-			tagObjVerified = true
-			break
-		}
-		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
-			// We encounter this for key types that can be used for gittuf
-			// policy metadata but not Git objects
+	var verificationErr error
+	for _, verifier := range verifiers {
+		if len(verifier.RequiredPredicates()) == 0 {
 			continue
 		}
-		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
-			// Unexpected error
+
+		err := verifyRequiredPredicates(ctx, repo, policy, attestationsState, refName, targetID, verifier.RequiredPredicates())
+		if err == nil {
+			return nil
+		} else if !errors.Is(err, ErrRequiredPredicatesNotMet) {
 			return err
 		}
-		// Haven't found a valid key, continue with next key
-	}
 
-	if !tagObjVerified {
-		return fmt.Errorf("verifying tag object's signature failed, %w", ErrUnauthorizedSignature)
+		logger.Debug(fmt.Sprintf("Statements do not meet rule '%s': %s", verifier.Name(), err.Error()))
+		if verificationErr == nil {
+			verificationErr = err
+		}
 	}
 
-	return nil
+	return verificationErr
 }
 
-func getAuthorizationAttestation(repo *git.Repository, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry) (*sslibdsse.Envelope, error) {
-	firstEntry := false
+// verifyRequiredPredicates checks that the change of the reference to the
+// target has an in-toto statement of each of the specified predicate types.
+// For each predicate type, the signers of one of the statements must meet the
+// threshold set in the predicate policy for the type.
+func verifyRequiredPredicates(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, refName, targetID string, predicateTypes []string) error {
+	for _, predicateType := range predicateTypes {
+		if attestationsState == nil {
+			return fmt.Errorf("%w: no statements found with predicate type '%s'", ErrRequiredPredicatesNotMet, predicateType)
+		}
 
-	priorRefEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, entry.RefName, entry.ID)
-	if err != nil {
-		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
-			return nil, err
+		verifier, err := policy.FindVerifierForPredicateType(predicateType)
+		if err != nil {
+			return fmt.Errorf("%w: predicate type '%s': %w", ErrRequiredPredicatesNotMet, predicateType, err)
 		}
 
-		firstEntry = true
-	}
+		statements, err := attestationsState.GetStatementAttestationsFor(repo, refName, targetID, predicateType)
+		if err != nil {
+			return err
+		}
+
+		predicateVerified := false
+		for _, env := range statements {
+			if err := verifyRekorInclusion(ctx, repo, attestationsState, env); err != nil {
+				if errors.Is(err, ErrAttestationNotInRekor) {
+					logger.Debug(fmt.Sprintf("Ignoring statement with predicate type '%s': %s", predicateType, err.Error()))
+					continue
+				}
+
+				return err
+			}
+
+			err := verifier.Verify(ctx, nil, env)
+			if err == nil {
+				payload, err := env.DecodeB64Payload()
+				if err != nil {
+					return err
+				}
+				if err := policy.validatePredicate(ctx, predicateType, payload); err != nil {
+					return err
+				}
+
+				predicateVerified = true
+				break
+			} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
+				return err
+			}
+		}
+
+		if !predicateVerified {
+			return fmt.Errorf("%w: predicate type '%s'", ErrRequiredPredicatesNotMet, predicateType)
+		}
+	}
+
+	return nil
+}
+
+// verifyRequiredApprovals checks that the change of the reference to the target
+// was approved in code review by the threshold of principals required by the
+// verifier, as recorded in a review approval attestation. The attestation must
+// be signed by keys trusted by the policy to issue review approval
+// attestations. Only approvals of the reviewed change's latest commit by
+// principals in the approvers rule count, and the change's author can't
+// approve it.
+func verifyRequiredApprovals(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, refName, targetID string, verifier *Verifier) error {
+	requirement := verifier.RequiredApprovals()
+	if requirement == nil {
+		return nil
+	}
+
+	if attestationsState == nil {
+		return fmt.Errorf("%w: no review approval attestation found", ErrRequiredApprovalsNotMet)
+	}
+
+	env, err := attestationsState.GetReviewApprovalAttestationFor(repo, refName, targetID)
+	if err != nil {
+		if errors.Is(err, attestations.ErrReviewApprovalNotFound) {
+			return fmt.Errorf("%w: no review approval attestation found", ErrRequiredApprovalsNotMet)
+		}
+
+		return err
+	}
+
+	if err := policy.VerifyAttestationSignatures(ctx, env); err != nil {
+		return fmt.Errorf("verifying review approval attestation failed: %w", err)
+	}
+
+	if err := verifyRekorInclusion(ctx, repo, attestationsState, env); err != nil {
+		return fmt.Errorf("verifying review approval attestation failed: %w", err)
+	}
+
+	review, err := attestations.GetReview(env)
+	if err != nil {
+		return err
+	}
+
+	approvers := set.NewSet[string]()
+	for _, principal := range verifier.approvers {
+		approvers.Add(principal)
+	}
+
+	approved := 0
+	for _, principal := range review.ApprovingPrincipals() {
+		if approvers.Has(principal) {
+			approved++
+		}
+	}
+
+	if approved < requirement.Threshold {
+		return fmt.Errorf("%w: %d of %d approvals required from rule '%s' found for %s", ErrRequiredApprovalsNotMet, approved, requirement.Threshold, requirement.Role, review.URL)
+	}
+
+	return nil
+}
+
+// verifyRequiredEvaluators checks that each of the specified rule evaluator
+// plugins allows the change recorded in the entry. The plugins must be
+// installed wherever the entry is verified, verification fails otherwise.
+func verifyRequiredEvaluators(ctx context.Context, repo *git.Repository, entry *rsl.ReferenceEntry, ruleName string, evaluatorNames []string) error {
+	if len(evaluatorNames) == 0 {
+		return nil
+	}
+
+	gitDir, err := gitinterface.GetGitCommonDirFor(repo)
+	if err != nil {
+		return err
+	}
+
+	evaluation := &plugin.Evaluation{
+		GitDir:   gitDir,
+		RuleName: ruleName,
+		RefName:  entry.RefName,
+		TargetID: entry.TargetID.String(),
+	}
+
+	priorRefEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, entry.RefName, entry.ID)
+	if err == nil {
+		evaluation.FromID = priorRefEntry.TargetID.String()
+	} else if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return err
+	}
+
+	for _, evaluatorName := range evaluatorNames {
+		evaluator, err := plugin.LoadRuleEvaluator(evaluatorName)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrRequiredEvaluatorNotPassed, err)
+		}
+
+		result, err := evaluator.Evaluate(ctx, evaluation)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrRequiredEvaluatorNotPassed, err)
+		}
+		if !result.Allowed {
+			return fmt.Errorf("%w: rule evaluator '%s' rejected the change: %s", ErrRequiredEvaluatorNotPassed, evaluatorName, result.Reason)
+		}
+	}
+
+	return nil
+}
+
+// verifyTagEntry is a helper to verify a tag's RSL entry and the tag object it
+// points to. If the tag is protected by policy, the tag object must meet the
+// threshold of one of the applicable verifiers. The threshold may be met using
+// a combination of the tag object's signature and signatures on a reference
+// authorization for the tag's creation, allowing the required principals to
+// approve the tag asynchronously ahead of time.
+func verifyTagEntry(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry, entryExplanation *EntryExplanation) error {
+	// 1. Find authorized public keys for tag's RSL entry
+	trustedKeys, err := policy.FindPublicKeysForPath(ctx, fmt.Sprintf("git:%s", entry.RefName))
+	if err != nil {
+		return err
+	}
+
+	tagIsProtected := len(trustedKeys) > 0
+
+	if !tagIsProtected {
+		allKeys, err := policy.PublicKeys()
+		if err != nil {
+			return err
+		}
+
+		// FIXME: decide if we want to pass around map or slice for these APIs
+		for _, key := range allKeys {
+			trustedKeys = append(trustedKeys, key)
+		}
+	}
+
+	// 2. Find commit object for the RSL entry
+	commitObj, err := gitinterface.GetCommit(repo, entry.ID)
+	if err != nil {
+		return err
+	}
+
+	// 3. Use each trusted key to verify signature
+	rslEntryVerified := false
+	for _, key := range trustedKeys {
+		err := gitinterface.VerifyCommitSignature(ctx, commitObj, key)
+		if err == nil {
+			// Signature verification succeeded
+			rslEntryVerified = true
+			break
+		}
+		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+			// We encounter this for key types that can be used for gittuf
+			// policy metadata but not Git objects
+			continue
+		}
+		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+			// Unexpected error
+			return err
+		}
+		// Haven't found a valid key, continue with next key
+	}
+
+	if !rslEntryVerified {
+		if reason := signatureFailureReason(ctx, policy, commitObj); reason != nil {
+			return fmt.Errorf("verifying RSL entry failed, %w: %w", ErrUnauthorizedSignature, reason)
+		}
+		return fmt.Errorf("verifying RSL entry failed, %w", ErrUnauthorizedSignature)
+	}
+
+	// 4. Verify tag object
+	tagObjVerified := false
+	var tagVerifier *Verifier
+	tagObj, err := gitinterface.GetTag(repo, entry.TargetID)
+	if err != nil {
+		// Likely indicates the ref is not pointing to a tag object
+		// What about lightweight tags?
+		return err
+	}
+
+	entryTagRef, err := repo.Reference(plumbing.ReferenceName(entry.RefName), true)
+	if err != nil {
+		return err
+	}
+
+	if entry.TargetID != entryTagRef.Hash() && entry.TargetID != tagObj.Target {
+		return fmt.Errorf("verifying RSL entry failed, tag reference set to unexpected target")
+	}
+
+	if len(tagObj.PGPSignature) == 0 {
+		return ErrUnsignedObject
+	}
+
+	if tagIsProtected {
+		namespace := fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName)
+		verifiers, err := policy.FindVerifiersForPath(namespace)
+		if err != nil {
+			return err
+		}
+
+		authorizationAttestation, err := getTagAuthorizationAttestation(ctx, repo, policy, attestationsState, entry.RefName, tagObj, commitObj)
+		if err != nil {
+			return err
+		}
+
+		check := entryExplanation.addCheck(namespace, tagObj, authorizationAttestation)
+		for _, verifier := range verifiers {
+			keyID, err := verifier.verify(ctx, tagObj, authorizationAttestation)
+			check.addRule(verifier, keyID, err)
+			traceRule(trace.TracerFromContext(ctx), entry, namespace, tagObj.Hash, verifier, err)
+			if err == nil {
+				// Signature verification succeeded
+				tagObjVerified = true
+				tagVerifier = verifier
+				break
+			} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
+				// Unexpected error
+				return err
+			}
+			// Haven't found a valid verifier, continue with next
+		}
+	} else {
+		for _, key := range trustedKeys {
+			err := gitinterface.VerifyTagSignature(ctx, tagObj, key)
+			if err == nil {
+				// Signature verification succeeded
+				tagObjVerified = true
+				break
+			}
+			if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+				// We encounter this for key types that can be used for gittuf
+				// policy metadata but not Git objects
+				continue
+			}
+			if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+				// Unexpected error
+				return err
+			}
+			// Haven't found a valid key, continue with next key
+		}
+	}
+
+	if !tagObjVerified {
+		if reason := signatureFailureReason(ctx, policy, tagObj); reason != nil {
+			return fmt.Errorf("verifying tag object's signature failed, %w: %w", ErrUnauthorizedSignature, reason)
+		}
+		return fmt.Errorf("verifying tag object's signature failed, %w", ErrUnauthorizedSignature)
+	}
+
+	// 5. Verify the tagged commit was reproduced by rebuilders, if required
+	if tagVerifier != nil && len(tagVerifier.RequiredRebuilds()) > 0 {
+		return verifyRequiredRebuilds(ctx, repo, policy, attestationsState, tagObj.Target.String(), tagVerifier.RequiredRebuilds())
+	}
+
+	return nil
+}
+
+// verifyRequiredRebuilds checks that each of the specified artifacts was
+// reproduced from the commit by independent rebuilders. For each artifact,
+// the rebuilders that agree on its digest must meet the threshold set in the
+// predicate policy for rebuild attestations.
+func verifyRequiredRebuilds(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, commitID string, artifactNames []string) error {
+	verifier, err := policy.FindVerifierForPredicateType(attestations.RebuildPredicateType)
+	if err != nil {
+		return err
+	}
+
+	for _, artifactName := range artifactNames {
+		if attestationsState == nil {
+			return fmt.Errorf("%w: no rebuild attestations found for artifact '%s'", ErrRequiredRebuildsNotMet, artifactName)
+		}
+
+		rebuilds, err := attestationsState.GetRebuildAttestationsFor(repo, commitID, artifactName)
+		if err != nil {
+			return err
+		}
+
+		artifactVerified := false
+		for digest, env := range rebuilds {
+			if err := verifyRekorInclusion(ctx, repo, attestationsState, env); err != nil {
+				if errors.Is(err, ErrAttestationNotInRekor) {
+					logger.Debug(fmt.Sprintf("Ignoring rebuild attestation for artifact '%s' with digest '%s': %s", artifactName, digest, err.Error()))
+					continue
+				}
+
+				return err
+			}
+
+			err := verifier.Verify(ctx, nil, env)
+			if err == nil {
+				artifactVerified = true
+				break
+			} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
+				return err
+			}
+		}
+
+		if !artifactVerified {
+			return fmt.Errorf("%w: artifact '%s'", ErrRequiredRebuildsNotMet, artifactName)
+		}
+	}
+
+	return nil
+}
+
+func getAuthorizationAttestation(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry, entryCommit *object.Commit) (*sslibdsse.Envelope, error) {
+	firstEntry := false
+
+	priorRefEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, entry.RefName, entry.ID)
+	if err != nil {
+		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil, err
+		}
+
+		firstEntry = true
+	}
 
 	fromID := plumbing.ZeroHash
 	if !firstEntry {
@@ -806,9 +1519,78 @@ func getAuthorizationAttestation(repo *git.Repository, attestationsState *attest
 		return nil, err
 	}
 
-	attestation, err := attestationsState.GetReferenceAuthorizationFor(repo, entry.RefName, fromID.String(), currentCommit.TreeHash.String())
+	return findReferenceAuthorization(ctx, repo, policy, attestationsState, entry.RefName, fromID.String(), currentCommit.TreeHash.String(), entryCommit)
+}
+
+// getTagAuthorizationAttestation returns the reference authorization, if any,
+// for creating the tag `tagRef` pointing to the tag object's target.
+func getTagAuthorizationAttestation(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, tagRef string, tagObj *object.Tag, entryCommit *object.Commit) (*sslibdsse.Envelope, error) {
+	return findReferenceAuthorization(ctx, repo, policy, attestationsState, tagRef, plumbing.ZeroHash.String(), tagObj.Target.String(), entryCommit)
+}
+
+// findReferenceAuthorization looks up the reference authorization for the
+// specified parameters in the repository's attestations. If it isn't found
+// and the context carries an Archivista client, the Archivista instance is
+// searched instead. An authorization that declares a validity period is only
+// used if the signature of the RSL entry being verified was timestamped within
+// that period, as the entry's own dates are set by the pusher. If no usable
+// authorization is found, nil is returned.
+func findReferenceAuthorization(ctx context.Context, repo *git.Repository, policy *State, attestationsState *attestations.Attestations, refName, fromID, toID string, entryCommit *object.Commit) (*sslibdsse.Envelope, error) {
+	var attestation *sslibdsse.Envelope
+
+	if attestationsState != nil {
+		var err error
+		attestation, err = attestationsState.GetReferenceAuthorizationFor(repo, refName, fromID, toID)
+		if err != nil && !errors.Is(err, attestations.ErrAuthorizationNotFound) {
+			return nil, err
+		}
+	}
+
+	if attestation == nil {
+		client := archivista.ClientFromContext(ctx)
+		if client == nil {
+			return nil, nil
+		}
+
+		logger.Debug(fmt.Sprintf("Searching Archivista instance '%s' for reference authorization...", client.URL()))
+		var err error
+		attestation, err = attestations.FetchReferenceAuthorizationFromArchivista(ctx, client, refName, fromID, toID)
+		if err != nil {
+			if errors.Is(err, attestations.ErrAuthorizationNotFound) {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+	}
+
+	hasValidityPeriod, err := attestations.HasValidityPeriod(attestation)
 	if err != nil {
-		if errors.Is(err, attestations.ErrAuthorizationNotFound) {
+		return nil, err
+	}
+	if hasValidityPeriod {
+		signedAt, err := policy.getTrustedSigningTime(ctx, repo, []byte(entryCommit.PGPSignature))
+		if err != nil {
+			return nil, err
+		}
+		if signedAt.IsZero() {
+			logger.Debug(fmt.Sprintf("Ignoring reference authorization for '%s': RSL entry '%s' is not timestamped", refName, entryCommit.Hash.String()))
+			return nil, nil
+		}
+
+		if err := attestations.VerifyValidityPeriod(attestation, signedAt); err != nil {
+			if errors.Is(err, attestations.ErrAttestationNotYetValid) || errors.Is(err, attestations.ErrAttestationExpired) {
+				logger.Debug(fmt.Sprintf("Ignoring reference authorization for '%s': %s", refName, err.Error()))
+				return nil, nil
+			}
+
+			return nil, err
+		}
+	}
+
+	if err := verifyRekorInclusion(ctx, repo, attestationsState, attestation); err != nil {
+		if errors.Is(err, ErrAttestationNotInRekor) {
+			logger.Debug(fmt.Sprintf("Ignoring reference authorization for '%s': %s", refName, err.Error()))
 			return nil, nil
 		}
 
@@ -818,9 +1600,64 @@ func getAuthorizationAttestation(repo *git.Repository, attestationsState *attest
 	return attestation, nil
 }
 
+// verifyRekorInclusion checks that the attestation was logged to the Rekor
+// instance carried by the context. The log entries recorded in the
+// attestations state are checked first. If none of them are valid, the Rekor
+// instance is searched for entries recording the attestation. If the context
+// does not carry a Rekor client, this check is skipped.
+func verifyRekorInclusion(ctx context.Context, repo *git.Repository, attestationsState *attestations.Attestations, env *sslibdsse.Envelope) error {
+	client := rekor.ClientFromContext(ctx)
+	if client == nil {
+		return nil
+	}
+
+	publicKey, err := client.PublicKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	if attestationsState != nil {
+		entries, err := attestationsState.GetRekorEntriesFor(repo, env)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := entry.VerifyForEnvelope(env, publicKey); err == nil {
+				return nil
+			}
+		}
+	}
+
+	payloadDigest, err := rekor.PayloadDigest(env)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Searching Rekor instance '%s' for attestation...", client.URL()))
+	uuids, err := client.SearchByPayloadDigest(ctx, payloadDigest)
+	if err != nil {
+		return err
+	}
+
+	for _, uuid := range uuids {
+		entry, err := client.GetEntry(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		if err := entry.VerifyForEnvelope(env, publicKey); err == nil {
+			return nil
+		}
+	}
+
+	return ErrAttestationNotInRekor
+}
+
 // getCommits identifies the commits introduced to the entry's ref since the
 // last RSL entry for the same ref. These commits are then verified for file
-// policies.
+// policies. In a partial clone, the trees of the commits are fetched at once
+// so they aren't fetched one at a time during verification.
 func getCommits(repo *git.Repository, entry *rsl.ReferenceEntry) ([]*object.Commit, error) {
 	firstEntry := false
 
@@ -833,11 +1670,110 @@ func getCommits(repo *git.Repository, entry *rsl.ReferenceEntry) ([]*object.Comm
 		firstEntry = true
 	}
 
+	var commits []*object.Commit
 	if firstEntry {
-		return gitinterface.GetCommitsBetweenRange(repo, entry.TargetID, plumbing.ZeroHash)
+		commits, err = gitinterface.GetCommitsBetweenRange(repo, entry.TargetID, plumbing.ZeroHash)
+	} else {
+		commits, err = gitinterface.GetCommitsBetweenRange(repo, entry.TargetID, priorRefEntry.TargetID)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return gitinterface.GetCommitsBetweenRange(repo, entry.TargetID, priorRefEntry.TargetID)
+	treeIDs := make([]plumbing.Hash, 0, len(commits))
+	for _, commit := range commits {
+		treeIDs = append(treeIDs, commit.TreeHash)
+	}
+	if err := gitinterface.PrefetchObjects(repo, treeIDs); err != nil {
+		return nil, err
+	}
+
+	return commits, nil
+}
+
+// prefetchEntrySignatures verifies the signatures of the RSL entries for
+// branches concurrently, using the keys of the rules that protect the branches
+// in the specified policy. The results are cached by the context's
+// BatchVerifier, so verifying the entries in order, which must be done
+// sequentially as the policy may change, doesn't verify the signatures again.
+// Errors are reported when the entries are verified in order, so they're
+// ignored here.
+func prefetchEntrySignatures(ctx context.Context, repo *git.Repository, policy *State, entries []*rsl.ReferenceEntry) {
+	verifier := gitinterface.BatchVerifierFromContext(ctx)
+	if verifier == nil {
+		return
+	}
+
+	checks := []*gitinterface.SignatureCheck{}
+	for _, entry := range entries {
+		if entry.RefName == PolicyRef || entry.RefName == PolicyStagingRef || entry.RefName == attestations.Ref || IsMergeQueueRef(entry.RefName) || strings.HasPrefix(entry.RefName, gitinterface.TagRefPrefix) {
+			continue
+		}
+
+		verifiers, err := policy.FindVerifiersForPath(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName))
+		if err != nil || len(verifiers) == 0 {
+			continue
+		}
+
+		// The keys are in the order the verifiers try them
+		keys := []*tuf.Key{}
+		keyIDs := set.NewSet[string]()
+		for _, ruleVerifier := range verifiers {
+			for _, key := range ruleVerifier.Keys() {
+				if !keyIDs.Has(key.KeyID) {
+					keyIDs.Add(key.KeyID)
+					keys = append(keys, key)
+				}
+			}
+		}
+
+		commit, err := gitinterface.GetCommit(repo, entry.ID)
+		if err != nil {
+			continue
+		}
+
+		checks = append(checks, &gitinterface.SignatureCheck{Object: commit, Keys: keys})
+	}
+
+	verifier.Verify(policy.contextWithSignatureCacheScope(ctx), checks)
+}
+
+// findShallowAnchor identifies the entry for the ref that verification must
+// start after in a shallow clone. Starting from the latest entry, the entries
+// for the ref are walked back until one is found whose introduced commits are
+// not all available. If that entry's target commit is available, it's the
+// anchor. Otherwise, the entry after it is the anchor. Nil is returned if the
+// commits introduced by all the entries are available.
+func findShallowAnchor(repo *git.Repository, latestEntry *rsl.ReferenceEntry) (*rsl.ReferenceEntry, error) {
+	var nextEntry *rsl.ReferenceEntry
+	entry := latestEntry
+	for {
+		_, err := getCommits(repo, entry)
+		if err != nil {
+			if !errors.Is(err, plumbing.ErrObjectNotFound) {
+				return nil, err
+			}
+
+			if _, err := gitinterface.GetCommit(repo, entry.TargetID); err == nil {
+				return entry, nil
+			}
+			if nextEntry == nil {
+				return nil, ErrShallowAnchorNotFound
+			}
+			return nextEntry, nil
+		}
+
+		priorEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, entry.RefName, entry.ID)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		nextEntry = entry
+		entry = priorEntry
+	}
 }
 
 // getChangedPaths identifies the paths of all the files changed using the
@@ -877,9 +1813,26 @@ func getChangedPaths(repo *git.Repository, entry *rsl.ReferenceEntry) ([]string,
 }
 
 type Verifier struct {
-	name      string
-	keys      []*tuf.Key
-	threshold int
+	name                     string
+	keys                     []*tuf.Key
+	threshold                int
+	requiredHooks            []string
+	requiredRebuilds         []string
+	requiredEvaluators       []string
+	requiredPredicates       []string
+	requireVerifiedSubmodule bool
+	allowedSignatureMethods  []tuf.SignatureMethod
+	requiredApprovals        *tuf.ApprovalRequirement
+
+	// approvers are the principals of the rule named in requiredApprovals,
+	// who may approve changes authorized by the verifier in code review.
+	approvers []string
+
+	// keyValidity checks that signatures issued by keys with validity
+	// windows were timestamped within the windows, and rejects signatures
+	// issued by revoked keys. It's nil if the policy doesn't restrict the
+	// validity of any keys or revoke any keys.
+	keyValidity *keyValidity
 }
 
 func (v *Verifier) Name() string {
@@ -894,85 +1847,138 @@ func (v *Verifier) Threshold() int {
 	return v.threshold
 }
 
+func (v *Verifier) RequiredHooks() []string {
+	return v.requiredHooks
+}
+
+func (v *Verifier) RequiredRebuilds() []string {
+	return v.requiredRebuilds
+}
+
+func (v *Verifier) RequiredEvaluators() []string {
+	return v.requiredEvaluators
+}
+
+func (v *Verifier) RequiredPredicates() []string {
+	return v.requiredPredicates
+}
+
+func (v *Verifier) RequireVerifiedSubmodule() bool {
+	return v.requireVerifiedSubmodule
+}
+
+func (v *Verifier) AllowedSignatureMethods() []tuf.SignatureMethod {
+	return v.allowedSignatureMethods
+}
+
+func (v *Verifier) RequiredApprovals() *tuf.ApprovalRequirement {
+	return v.requiredApprovals
+}
+
 // Verify is used to check for a threshold of signatures using the verifier. The
 // threshold of signatures may be met using a combination of at most one Git
 // signature and signatures embedded in a DSSE envelope. Verify does not inspect
 // the envelope's payload, but instead only verifies the signatures. The caller
 // must ensure the validity of the envelope's contents.
 func (v *Verifier) Verify(ctx context.Context, gitObject object.Object, env *sslibdsse.Envelope) error {
+	_, err := v.verify(ctx, gitObject, env)
+	return err
+}
+
+// verify implements Verify. It also returns the ID of the key that verified the
+// Git object's signature, if any, even if the threshold isn't met.
+func (v *Verifier) verify(ctx context.Context, gitObject object.Object, env *sslibdsse.Envelope) (string, error) {
 	if v.threshold < 1 || len(v.keys) < 1 {
-		return ErrInvalidVerifier
+		return "", ErrInvalidVerifier
 	}
 
 	if gitObject == nil {
 		if env == nil {
 			// Nothing to verify, but fail closed
-			return ErrVerifierConditionsUnmet
+			return "", fmt.Errorf("%w: no Git object or reference authorization to verify", ErrVerifierConditionsUnmet)
 		} else if len(env.Signatures) < v.threshold {
 			// Envelope doesn't have enough signatures to meet threshold
-			return ErrVerifierConditionsUnmet
+			return "", fmt.Errorf("%w: reference authorization has %d signature(s), threshold is %d", ErrVerifierConditionsUnmet, len(env.Signatures), v.threshold)
 		}
 	} else {
 		if env == nil {
 			if v.threshold > 1 {
 				// Single valid signature at most, so cannot meet threshold
-				return ErrVerifierConditionsUnmet
+				return "", fmt.Errorf("%w: threshold is %d but no reference authorization was found, so at most one signature is available", ErrVerifierConditionsUnmet, v.threshold)
 			}
 		} else {
 			if (1 + len(env.Signatures)) < v.threshold {
 				// Combining the attestation and the git object we still do not
 				// have sufficient signatures
-				return ErrVerifierConditionsUnmet
+				return "", fmt.Errorf("%w: threshold is %d but only %d signature(s) are available", ErrVerifierConditionsUnmet, v.threshold, 1+len(env.Signatures))
 			}
 		}
 	}
 
-	var keyIDUsed string
-	gitObjectVerified := false
+	var (
+		keyIDUsed         string
+		gitObjectVerified bool
+		rejectedSignature error
+	)
 
 	// First, verify the gitObject's signature if one is presented
 	if gitObject != nil {
+		var (
+			verify    func(*tuf.Key) error
+			signature string
+		)
 		switch o := gitObject.(type) {
 		case *object.Commit:
-			for _, key := range v.keys {
-				err := gitinterface.VerifyCommitSignature(ctx, o, key)
-				if err == nil {
-					// Signature verification succeeded
-					keyIDUsed = key.KeyID
-					gitObjectVerified = true
-					break
-				}
-				if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
-					continue
-				}
-				if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
-					return err
-				}
+			verify = func(key *tuf.Key) error {
+				return gitinterface.VerifyCommitSignature(ctx, o, key)
 			}
+			signature = o.PGPSignature
 		case *object.Tag:
-			for _, key := range v.keys {
-				err := gitinterface.VerifyTagSignature(ctx, o, key)
-				if err == nil {
-					// Signature verification succeeded
-					keyIDUsed = key.KeyID
-					gitObjectVerified = true
-					break
-				}
-				if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+			verify = func(key *tuf.Key) error {
+				return gitinterface.VerifyTagSignature(ctx, o, key)
+			}
+			signature = o.PGPSignature
+		default:
+			return "", ErrUnknownObjectType
+		}
+
+		for _, key := range v.keys {
+			err := verify(key)
+			if err == nil {
+				if err := v.checkSignatureMethod(key, []byte(signature)); err != nil {
+					// The key issued the signature, but using a mechanism
+					// the rule doesn't allow, so it doesn't count
+					rejectedSignature = err
 					continue
 				}
-				if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
-					return err
+				if err := v.keyValidity.check(ctx, key.KeyID, []byte(signature)); err != nil {
+					if !errors.Is(err, ErrKeyValidityUnmet) {
+						return "", err
+					}
+
+					// The key issued the signature, but the signature
+					// wasn't timestamped while the key was valid
+					rejectedSignature = err
+					continue
 				}
+
+				// Signature verification succeeded
+				keyIDUsed = key.KeyID
+				gitObjectVerified = true
+				break
+			}
+			if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+				continue
+			}
+			if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+				return "", err
 			}
-		default:
-			return ErrUnknownObjectType
 		}
 	}
 
 	// If threshold is 1 and the Git signature is verified, we can return
 	if v.threshold == 1 && gitObjectVerified {
-		return nil
+		return keyIDUsed, nil
 	}
 
 	// Second, verify signatures on the attestation, subtracting the threshold
@@ -992,13 +1998,53 @@ func (v *Verifier) Verify(ctx context.Context, gitObject object.Object, env *ssl
 
 		verifier, err := signerverifier.NewSignerVerifierFromTUFKey(key) //nolint:staticcheck
 		if err != nil && !errors.Is(err, common.ErrUnknownKeyType) {
-			return err
+			return "", err
+		}
+		if verifier == nil {
+			// Keys such as GPG keys can't verify the attestation
+			continue
+		}
+		if v.keyValidity.restricts(key.KeyID) {
+			verifiers = append(verifiers, &keyValidityVerifier{Verifier: verifier, keyID: key.KeyID, keyValidity: v.keyValidity})
+			continue
 		}
 		verifiers = append(verifiers, verifier)
 	}
 
 	if err := dsse.VerifyEnvelope(ctx, env, verifiers, envelopeThreshold); err != nil {
-		return ErrVerifierConditionsUnmet
+		if gitObject != nil && !gitObjectVerified {
+			reason := "Git signature was not issued by any of the rule's keys"
+			if rejectedSignature != nil {
+				reason = rejectedSignature.Error()
+			}
+			if env == nil {
+				return "", fmt.Errorf("%w: %s", ErrVerifierConditionsUnmet, reason)
+			}
+			return "", fmt.Errorf("%w: %s, and the reference authorization's signatures don't meet the threshold of %d", ErrVerifierConditionsUnmet, reason, v.threshold)
+		}
+		if gitObjectVerified {
+			return keyIDUsed, fmt.Errorf("%w: Git signature was verified, but the reference authorization's signatures don't meet the remaining threshold of %d", ErrVerifierConditionsUnmet, envelopeThreshold)
+		}
+		return "", fmt.Errorf("%w: reference authorization's signatures don't meet the threshold of %d", ErrVerifierConditionsUnmet, envelopeThreshold)
+	}
+
+	return keyIDUsed, nil
+}
+
+// checkSignatureMethod checks that the Git signature, which was verified using
+// the key, was created using one of the signature methods the rule allows.
+func (v *Verifier) checkSignatureMethod(key *tuf.Key, signature []byte) error {
+	if len(v.allowedSignatureMethods) == 0 {
+		return nil
+	}
+
+	method, err := gitinterface.GetSignatureMethod(key, signature)
+	if err != nil {
+		return fmt.Errorf("unable to determine the signature method of the Git signature issued by key '%s': %w", key.KeyID, err)
+	}
+
+	if !signatureMethodAllowed(v.allowedSignatureMethods, method) {
+		return fmt.Errorf("Git signature was issued by key '%s' using %s, which the rule doesn't allow", key.KeyID, method) //nolint:stylecheck
 	}
 
 	return nil
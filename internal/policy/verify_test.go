@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/codereview"
 	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/rsl"
@@ -117,6 +118,78 @@ func TestVerifyRefFromEntry(t *testing.T) {
 	assert.Equal(t, commitIDs[1], currentTip)
 }
 
+func TestVerifyRefFromShallowAnchor(t *testing.T) {
+	refName := "refs/heads/main"
+
+	// removeCommits simulates a shallow clone by removing the commits beyond
+	// the shallow boundary
+	removeCommits := func(t *testing.T, repo *git.Repository, commitIDs []plumbing.Hash, boundaryID plumbing.Hash) {
+		t.Helper()
+
+		storage := repo.Storer.(*memory.Storage)
+		for _, commitID := range commitIDs {
+			delete(storage.Objects, commitID)
+			delete(storage.Commits, commitID)
+		}
+
+		if err := storage.SetShallow([]plumbing.Hash{boundaryID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	createRepository := func(t *testing.T) (*git.Repository, [][]plumbing.Hash, []plumbing.Hash) {
+		t.Helper()
+
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := [][]plumbing.Hash{}
+		entryIDs := []plumbing.Hash{}
+		for _, keyBytes := range [][]byte{gpgUnauthorizedKeyBytes, gpgKeyBytes, gpgKeyBytes} {
+			ids := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, keyBytes)
+			entry := rsl.NewReferenceEntry(refName, ids[1])
+			entryIDs = append(entryIDs, common.CreateTestRSLReferenceEntryCommit(t, repo, entry, keyBytes))
+			commitIDs = append(commitIDs, ids)
+		}
+
+		return repo, commitIDs, entryIDs
+	}
+
+	t.Run("history of policy violation not available", func(t *testing.T) {
+		repo, commitIDs, entryIDs := createRepository(t)
+		removeCommits(t, repo, commitIDs[0], commitIDs[1][0])
+
+		explanation := &Explanation{}
+		ctx := ContextWithExplanation(testCtx, explanation)
+
+		currentTip, anchorEntry, err := VerifyRefFromShallowAnchor(ctx, repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[2][1], currentTip)
+		assert.Equal(t, entryIDs[1], anchorEntry.ID)
+		assert.Equal(t, entryIDs[1].String(), explanation.ShallowAnchor.EntryID)
+		assert.Equal(t, commitIDs[1][1].String(), explanation.ShallowAnchor.TargetID)
+	})
+
+	t.Run("history of all entries available", func(t *testing.T) {
+		repo, _, _ := createRepository(t)
+
+		// The first entry violates the policy and is verified
+		_, anchorEntry, err := VerifyRefFromShallowAnchor(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+		assert.Nil(t, anchorEntry)
+	})
+
+	t.Run("history of latest entry not available", func(t *testing.T) {
+		repo, commitIDs, _ := createRepository(t)
+		removeCommits(t, repo, append(commitIDs[0], commitIDs[1]...), commitIDs[2][0])
+
+		_, _, err := VerifyRefFromShallowAnchor(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrShallowAnchorNotFound)
+	})
+}
+
 func TestVerifyRelativeForRef(t *testing.T) {
 	t.Run("no recovery", func(t *testing.T) {
 		repo, _ := createTestRepository(t, createTestStateWithPolicy)
@@ -730,6 +803,15 @@ func TestVerifyRelativeForRef(t *testing.T) {
 	})
 }
 
+func TestIsVerifiedStatus(t *testing.T) {
+	assert.True(t, IsVerifiedStatus(fmt.Sprintf(goodSignatureMessageFmt, "gpg", "keyid")))
+	assert.True(t, IsVerifiedStatus(goodTagSignatureMessage))
+	assert.True(t, IsVerifiedStatus(goodSignatureMessageForRSLEntry))
+	assert.False(t, IsVerifiedStatus(badSignatureMessageForRSLEntry))
+	assert.False(t, IsVerifiedStatus(noSignatureMessage))
+	assert.False(t, IsVerifiedStatus(fmt.Sprintf(errorVerifyingSignatureMessageFmt, "gpg", "keyid", "invalid signature")))
+}
+
 func TestVerifyCommit(t *testing.T) {
 	repo, _ := createTestRepository(t, createTestStateWithPolicy)
 	refName := "refs/heads/main"
@@ -886,6 +968,38 @@ func TestVerifyEntry(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("unsigned entry", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		if err := entry.Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		latestEntry, err := rsl.GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.ID = latestEntry.GetID()
+
+		err = verifyEntry(context.Background(), repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+		assert.ErrorIs(t, err, ErrUnsignedObject)
+	})
+
+	t.Run("entry signed by key not in policy", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgUnauthorizedKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgUnauthorizedKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(context.Background(), repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+		assert.ErrorIs(t, err, ErrUnknownSigningKey)
+	})
+
 	t.Run("successful verification with higher threshold", func(t *testing.T) {
 		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
 
@@ -940,6 +1054,434 @@ func TestVerifyEntry(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("verification with higher threshold and validity period", func(t *testing.T) {
+		entryTime := common.TestClock.Now()
+		authority := newTestTimestampAuthority(t)
+
+		tests := map[string]struct {
+			notBefore     time.Time
+			notAfter      time.Time
+			timestampedAt time.Time
+			expectedError error
+		}{
+			"authorization valid when entry is created": {
+				notBefore:     entryTime.Add(-time.Hour),
+				notAfter:      entryTime.Add(14 * 24 * time.Hour),
+				timestampedAt: entryTime,
+			},
+			"authorization expired when entry is created": {
+				notBefore:     entryTime.Add(-30 * 24 * time.Hour),
+				notAfter:      entryTime.Add(-16 * 24 * time.Hour),
+				timestampedAt: entryTime,
+				expectedError: ErrUnauthorizedSignature,
+			},
+			"authorization not yet valid when entry is created": {
+				notBefore:     entryTime.Add(time.Hour),
+				notAfter:      entryTime.Add(14 * 24 * time.Hour),
+				timestampedAt: entryTime,
+				expectedError: ErrUnauthorizedSignature,
+			},
+			"backdated entry created after authorization expired": {
+				notBefore:     entryTime.Add(-time.Hour),
+				notAfter:      entryTime.Add(14 * 24 * time.Hour),
+				timestampedAt: entryTime.Add(30 * 24 * time.Hour),
+				expectedError: ErrUnauthorizedSignature,
+			},
+			"entry not timestamped": {
+				notBefore:     entryTime.Add(-time.Hour),
+				notAfter:      entryTime.Add(14 * 24 * time.Hour),
+				expectedError: ErrUnauthorizedSignature,
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				repo, state := createTestRepository(t, func(t *testing.T) *State {
+					t.Helper()
+
+					state := createTestStateWithThresholdPolicy(t)
+					trustTimestampAuthority(t, state, authority)
+					return state
+				})
+
+				currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+				commit, err := gitinterface.GetCommit(repo, commitIDs[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				authorization, err := attestations.NewReferenceAuthorization(refName, plumbing.ZeroHash.String(), commit.TreeHash.String())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := attestations.SetValidityPeriod(authorization, test.notBefore, test.notAfter); err != nil {
+					t.Fatal(err)
+				}
+
+				signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+				if err != nil {
+					t.Fatal(err)
+				}
+				env, err := dsse.CreateEnvelope(authorization)
+				if err != nil {
+					t.Fatal(err)
+				}
+				env, err = dsse.SignEnvelope(testCtx, env, signer)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := currentAttestations.SetReferenceAuthorization(repo, env, refName, plumbing.ZeroHash.String(), commit.TreeHash.String()); err != nil {
+					t.Fatal(err)
+				}
+				if err := currentAttestations.Commit(repo, "Add authorization", false); err != nil {
+					t.Fatal(err)
+				}
+
+				currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+				entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+				entry.ID = entryID
+
+				// The entry's commit is dated at entryTime, but only the
+				// timestamp of its signature is trusted
+				if !test.timestampedAt.IsZero() {
+					entryCommit, err := gitinterface.GetCommit(repo, entryID)
+					if err != nil {
+						t.Fatal(err)
+					}
+					signature := []byte(entryCommit.PGPSignature)
+
+					if err := currentAttestations.SetTimestamp(repo, signature, authority.timestamp(t, signature, test.timestampedAt)); err != nil {
+						t.Fatal(err)
+					}
+					if err := currentAttestations.Commit(repo, "Add timestamp", false); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+				if test.expectedError == nil {
+					assert.Nil(t, err)
+				} else {
+					assert.ErrorIs(t, err, test.expectedError)
+				}
+			})
+		}
+	})
+
+	t.Run("verification with required hooks", func(t *testing.T) {
+		tests := map[string]struct {
+			hookName      string
+			result        string
+			signingKey    []byte
+			expectedError error
+		}{
+			"passing hook signed by trusted key": {
+				hookName:   "secret-scan",
+				result:     attestations.HookResultPass,
+				signingKey: rootKeyBytes,
+			},
+			"failing hook": {
+				hookName:      "secret-scan",
+				result:        attestations.HookResultFail,
+				signingKey:    rootKeyBytes,
+				expectedError: ErrRequiredHookNotPassed,
+			},
+			"different hook": {
+				hookName:      "lint",
+				result:        attestations.HookResultPass,
+				signingKey:    rootKeyBytes,
+				expectedError: ErrRequiredHookNotPassed,
+			},
+			"passing hook signed by untrusted key": {
+				hookName:      "secret-scan",
+				result:        attestations.HookResultPass,
+				signingKey:    targets2KeyBytes,
+				expectedError: ErrVerifierConditionsUnmet,
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				repo, state := createTestRepository(t, createTestStateWithRequiredHooks)
+
+				currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+				hookExecution, err := attestations.NewHookExecutionAttestation(&attestations.HookExecution{
+					HookName: test.hookName,
+					RefName:  refName,
+					TargetID: commitIDs[0].String(),
+					Result:   test.result,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(test.signingKey) //nolint:staticcheck
+				if err != nil {
+					t.Fatal(err)
+				}
+				env, err := dsse.CreateEnvelope(hookExecution)
+				if err != nil {
+					t.Fatal(err)
+				}
+				env, err = dsse.SignEnvelope(testCtx, env, signer)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := currentAttestations.SetHookExecutionAttestation(repo, env, refName, commitIDs[0].String(), test.hookName); err != nil {
+					t.Fatal(err)
+				}
+				if err := currentAttestations.Commit(repo, "Add hook execution", false); err != nil {
+					t.Fatal(err)
+				}
+
+				currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+				entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+				entry.ID = entryID
+
+				err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+				if test.expectedError == nil {
+					assert.Nil(t, err)
+				} else {
+					assert.ErrorIs(t, err, test.expectedError)
+				}
+			})
+		}
+	})
+
+	t.Run("required hooks without attestations", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithRequiredHooks)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrRequiredHookNotPassed)
+	})
+
+	t.Run("verification with required predicates", func(t *testing.T) {
+		tests := map[string]struct {
+			predicateType string
+			signingKey    []byte
+			expectedError error
+		}{
+			"statement signed by trusted key": {
+				predicateType: testCodeReviewPredicateType,
+				signingKey:    targets1KeyBytes,
+			},
+			"statement of different type": {
+				predicateType: "https://in-toto.io/attestation/test-result/v0.1",
+				signingKey:    targets1KeyBytes,
+				expectedError: ErrRequiredPredicatesNotMet,
+			},
+			"statement signed by untrusted key": {
+				predicateType: testCodeReviewPredicateType,
+				signingKey:    targets2KeyBytes,
+				expectedError: ErrRequiredPredicatesNotMet,
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				repo, state := createTestRepository(t, createTestStateWithRequiredPredicates)
+
+				currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+				statement, err := attestations.NewStatementAttestation(refName, commitIDs[0].String(), test.predicateType, map[string]any{"approved": true})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(test.signingKey) //nolint:staticcheck
+				if err != nil {
+					t.Fatal(err)
+				}
+				env, err := dsse.CreateEnvelope(statement)
+				if err != nil {
+					t.Fatal(err)
+				}
+				env, err = dsse.SignEnvelope(testCtx, env, signer)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := currentAttestations.SetStatementAttestation(repo, env, refName, commitIDs[0].String()); err != nil {
+					t.Fatal(err)
+				}
+				if err := currentAttestations.Commit(repo, "Add statement", false); err != nil {
+					t.Fatal(err)
+				}
+
+				currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+				entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+				entry.ID = entryID
+
+				err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+				if test.expectedError == nil {
+					assert.Nil(t, err)
+				} else {
+					assert.ErrorIs(t, err, test.expectedError)
+				}
+			})
+		}
+	})
+
+	t.Run("required predicates without attestations", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithRequiredPredicates)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrRequiredPredicatesNotMet)
+	})
+
+	t.Run("required approvals", func(t *testing.T) {
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := map[string]struct {
+			approver      string
+			signingKey    []byte
+			expectedError error
+		}{
+			"approved by principal of approvers rule": {
+				approver:   gpgKey.KeyID,
+				signingKey: targets1KeyBytes,
+			},
+			"approved by unknown principal": {
+				approver:      "jane@example.com",
+				signingKey:    targets1KeyBytes,
+				expectedError: ErrRequiredApprovalsNotMet,
+			},
+			"attestation signed by untrusted key": {
+				approver:      gpgKey.KeyID,
+				signingKey:    targets2KeyBytes,
+				expectedError: ErrVerifierConditionsUnmet,
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				repo, state := createTestRepository(t, createTestStateWithRequiredApprovals)
+
+				currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+				review := &codereview.Review{
+					Platform:   codereview.PlatformGitHub,
+					URL:        "https://github.com/gittuf/gittuf/pull/7",
+					Repository: "gittuf/gittuf",
+					Number:     7,
+					State:      "merged",
+					RefName:    refName,
+					HeadCommit: commitIDs[0].String(),
+					TargetID:   commitIDs[0].String(),
+					Author:     codereview.User{Name: "john"},
+					Approvals: []codereview.Approval{
+						{Reviewer: codereview.User{Name: "alice", Principal: test.approver}, CommitID: commitIDs[0].String()},
+					},
+				}
+
+				statement, err := attestations.NewReviewApprovalAttestation(review)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(test.signingKey) //nolint:staticcheck
+				if err != nil {
+					t.Fatal(err)
+				}
+				env, err := dsse.CreateEnvelope(statement)
+				if err != nil {
+					t.Fatal(err)
+				}
+				env, err = dsse.SignEnvelope(testCtx, env, signer)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := currentAttestations.SetReviewApprovalAttestation(repo, env, refName, commitIDs[0].String()); err != nil {
+					t.Fatal(err)
+				}
+				if err := currentAttestations.Commit(repo, "Add review approval", false); err != nil {
+					t.Fatal(err)
+				}
+
+				currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+				entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+				entry.ID = entryID
+
+				err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+				if test.expectedError == nil {
+					assert.Nil(t, err)
+				} else {
+					assert.ErrorIs(t, err, test.expectedError)
+				}
+			})
+		}
+	})
+
+	t.Run("required approvals without attestations", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithRequiredApprovals)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(testCtx, repo, state, nil, entry)
+		assert.ErrorIs(t, err, ErrRequiredApprovalsNotMet)
+	})
+
 	// FIXME: test for file policy passing for situations where a commit is seen
 	// by the RSL before its signing key is rotated out. This commit should be
 	// trusted for merges under the new policy because it predates the policy
@@ -965,7 +1507,7 @@ func TestVerifyTagEntry(t *testing.T) {
 		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err := verifyTagEntry(context.Background(), repo, policy, entry)
+		err := verifyTagEntry(context.Background(), repo, policy, nil, entry, nil)
 		assert.Nil(t, err)
 	})
 
@@ -985,7 +1527,7 @@ func TestVerifyTagEntry(t *testing.T) {
 		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err := verifyTagEntry(context.Background(), repo, policy, entry)
+		err := verifyTagEntry(context.Background(), repo, policy, nil, entry, nil)
 		assert.Nil(t, err)
 	})
 
@@ -1005,9 +1547,161 @@ func TestVerifyTagEntry(t *testing.T) {
 		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err := verifyTagEntry(context.Background(), repo, policy, entry)
+		err := verifyTagEntry(context.Background(), repo, policy, nil, entry, nil)
 		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
 	})
+
+	t.Run("with tag specific threshold policy", func(t *testing.T) {
+		repo, policy := createTestRepository(t, createTestStateWithThresholdTagPolicy)
+		refName := "refs/heads/main"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[len(commitIDs)-1])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		tagName := "v1"
+		tagRef := string(plumbing.NewTagReferenceName(tagName))
+		tagID := common.CreateTestSignedTag(t, repo, tagName, commitIDs[len(commitIDs)-1], gpgKeyBytes)
+
+		entry = rsl.NewReferenceEntry(tagRef, tagID)
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		// Without an approval, only the tag's signature is available
+		err := verifyTagEntry(context.Background(), repo, policy, nil, entry, nil)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		// Create authorization for the tag
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		authorization, err := attestations.NewReferenceAuthorizationForTag(tagRef, commitIDs[len(commitIDs)-1].String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := currentAttestations.SetReferenceAuthorization(repo, env, tagRef, plumbing.ZeroHash.String(), commitIDs[len(commitIDs)-1].String()); err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.Commit(repo, "Add authorization", false); err != nil {
+			t.Fatal(err)
+		}
+
+		currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = verifyTagEntry(context.Background(), repo, policy, currentAttestations, entry, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("with tag specific policy and required rebuilds", func(t *testing.T) {
+		repo, policy := createTestRepository(t, createTestStateWithRequiredRebuilds)
+		refName := "refs/heads/main"
+		artifactName := "gittuf-linux-amd64"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[len(commitIDs)-1])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		tagName := "v1"
+		tagID := common.CreateTestSignedTag(t, repo, tagName, commitIDs[len(commitIDs)-1], gpgKeyBytes)
+
+		entry = rsl.NewReferenceEntry(string(plumbing.NewTagReferenceName(tagName)), tagID)
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		// No rebuilds have been recorded yet
+		err := verifyTagEntry(context.Background(), repo, policy, nil, entry, nil)
+		assert.ErrorIs(t, err, ErrRequiredRebuildsNotMet)
+
+		addRebuild := func(artifactDigest string, keyBytes []byte) {
+			t.Helper()
+
+			currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			commitID := commitIDs[len(commitIDs)-1].String()
+			env, err := currentAttestations.GetRebuildAttestationFor(repo, commitID, artifactName, artifactDigest)
+			if err != nil {
+				rebuild, err := attestations.NewRebuildAttestation(&attestations.Rebuild{CommitID: commitID, ArtifactName: artifactName, ArtifactSHA256: artifactDigest})
+				if err != nil {
+					t.Fatal(err)
+				}
+				env, err = dsse.CreateEnvelope(rebuild)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes) //nolint:staticcheck
+			if err != nil {
+				t.Fatal(err)
+			}
+			env, err = dsse.SignEnvelope(testCtx, env, signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := currentAttestations.SetRebuildAttestation(repo, env, commitID, artifactName, artifactDigest); err != nil {
+				t.Fatal(err)
+			}
+			if err := currentAttestations.Commit(repo, "Add rebuild", false); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Rebuilders disagree about the artifact's digest
+		addRebuild("aaaa", targets1KeyBytes)
+		addRebuild("bbbb", targets2KeyBytes)
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = verifyTagEntry(context.Background(), repo, policy, currentAttestations, entry, nil)
+		assert.ErrorIs(t, err, ErrRequiredRebuildsNotMet)
+
+		// An untrusted rebuilder agreeing does not count towards the threshold
+		addRebuild("aaaa", rootKeyBytes)
+
+		currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = verifyTagEntry(context.Background(), repo, policy, currentAttestations, entry, nil)
+		assert.ErrorIs(t, err, ErrRequiredRebuildsNotMet)
+
+		// Both trusted rebuilders agree
+		addRebuild("aaaa", targets2KeyBytes)
+
+		currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = verifyTagEntry(context.Background(), repo, policy, currentAttestations, entry, nil)
+		assert.Nil(t, err)
+	})
 }
 
 func TestGetCommits(t *testing.T) {
@@ -1132,6 +1826,159 @@ func TestStateVerifyNewState(t *testing.T) {
 	})
 }
 
+func TestStateVerifyAttestationSignatures(t *testing.T) {
+	state := createTestStateWithThresholdPolicy(t)
+
+	authorization, err := attestations.NewReferenceAuthorization("refs/heads/main", plumbing.ZeroHash.String(), plumbing.ZeroHash.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("signed by trusted key", func(t *testing.T) {
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(context.Background(), env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = state.VerifyAttestationSignatures(context.Background(), env)
+		assert.Nil(t, err)
+	})
+
+	t.Run("signed by untrusted key", func(t *testing.T) {
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets2KeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(context.Background(), env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = state.VerifyAttestationSignatures(context.Background(), env)
+		assert.ErrorIs(t, err, ErrVerifierConditionsUnmet)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = state.VerifyAttestationSignatures(context.Background(), env)
+		assert.ErrorIs(t, err, ErrVerifierConditionsUnmet)
+	})
+
+	t.Run("with predicate policy", func(t *testing.T) {
+		state := createTestStateWithThresholdPolicy(t)
+
+		key1, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key2, err := tuf.LoadKeyFromBytes(targets2PubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = SetPredicatePolicy(targetsMetadata, attestations.ReferenceAuthorizationPredicateType, []*tuf.Key{key1, key2}, 2, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, rootSigner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope = targetsEnv
+
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signer1, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(context.Background(), env, signer1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// One trusted signature is insufficient for the predicate's threshold
+		err = state.VerifyAttestationSignatures(context.Background(), env)
+		assert.ErrorIs(t, err, ErrVerifierConditionsUnmet)
+
+		signer2, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets2KeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(context.Background(), env, signer2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = state.VerifyAttestationSignatures(context.Background(), env)
+		assert.Nil(t, err)
+	})
+}
+
+func TestStateFindVerifierForPredicateType(t *testing.T) {
+	state := createTestStateWithPolicy(t)
+
+	_, err := state.FindVerifierForPredicateType(attestations.ReferenceAuthorizationPredicateType)
+	assert.ErrorIs(t, err, ErrPredicatePolicyNotFound)
+
+	key, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = SetPredicatePolicy(targetsMetadata, attestations.ReferenceAuthorizationPredicateType, []*tuf.Key{key}, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope, err = dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := state.FindVerifierForPredicateType(attestations.ReferenceAuthorizationPredicateType)
+	assert.Nil(t, err)
+	assert.Equal(t, attestations.ReferenceAuthorizationPredicateType, verifier.Name())
+	assert.Equal(t, []*tuf.Key{key}, verifier.Keys())
+	assert.Equal(t, 1, verifier.Threshold())
+}
+
 func TestVerifier(t *testing.T) {
 	repo, err := git.Init(memory.NewStorage(), memfs.New())
 	if err != nil {
@@ -1210,11 +2057,13 @@ func TestVerifier(t *testing.T) {
 	}
 
 	tests := map[string]struct {
-		keys          []*tuf.Key
-		threshold     int
-		gitObject     object.Object
-		attestation   *sslibdsse.Envelope
-		expectedError error
+		keys                    []*tuf.Key
+		threshold               int
+		allowedSignatureMethods []tuf.SignatureMethod
+		revokedKeys             map[string]*tuf.KeyRevocation
+		gitObject               object.Object
+		attestation             *sslibdsse.Envelope
+		expectedError           error
 	}{
 		"commit, no attestation, valid key, threshold 1": {
 			keys:      []*tuf.Key{gpgKey},
@@ -1288,10 +2137,71 @@ func TestVerifier(t *testing.T) {
 			gitObject:   tag,
 			attestation: attestationWithTwoSigs,
 		},
+		"commit, no attestation, valid key, allowed signature method": {
+			keys:                    []*tuf.Key{gpgKey},
+			threshold:               1,
+			allowedSignatureMethods: []tuf.SignatureMethod{{Format: "ssh"}, {Format: "gpg", Algorithms: []string{"rsa"}}},
+			gitObject:               commit,
+		},
+		"commit, no attestation, valid key, disallowed signature format": {
+			keys:                    []*tuf.Key{gpgKey},
+			threshold:               1,
+			allowedSignatureMethods: []tuf.SignatureMethod{{Format: "ssh", Algorithms: []string{"ed25519"}}},
+			gitObject:               commit,
+			expectedError:           ErrVerifierConditionsUnmet,
+		},
+		"tag, no attestation, valid key, disallowed hash": {
+			keys:                    []*tuf.Key{gpgKey},
+			threshold:               1,
+			allowedSignatureMethods: []tuf.SignatureMethod{{Format: "gpg", Hashes: []string{"sha512"}}},
+			gitObject:               tag,
+			expectedError:           ErrVerifierConditionsUnmet,
+		},
+		"commit, attestation, valid keys, disallowed signature method, threshold 1": {
+			keys:                    []*tuf.Key{gpgKey, rootPubKey},
+			threshold:               1,
+			allowedSignatureMethods: []tuf.SignatureMethod{{Format: "ssh"}},
+			gitObject:               commit,
+			attestation:             attestation,
+		},
+		"commit, attestation, valid keys, disallowed signature method, threshold 2": {
+			keys:                    []*tuf.Key{gpgKey, rootPubKey},
+			threshold:               2,
+			allowedSignatureMethods: []tuf.SignatureMethod{{Format: "ssh"}},
+			gitObject:               commit,
+			attestation:             attestation,
+			expectedError:           ErrVerifierConditionsUnmet,
+		},
+		"commit, no attestation, revoked key, threshold 1": {
+			keys:          []*tuf.Key{gpgKey},
+			threshold:     1,
+			revokedKeys:   map[string]*tuf.KeyRevocation{gpgKey.KeyID: {Reason: "compromised"}},
+			gitObject:     commit,
+			expectedError: gitinterface.ErrKeyRevoked,
+		},
+		"tag, attestation, revoked key, threshold 1": {
+			keys:          []*tuf.Key{gpgKey, rootPubKey},
+			threshold:     1,
+			revokedKeys:   map[string]*tuf.KeyRevocation{gpgKey.KeyID: {}},
+			gitObject:     tag,
+			attestation:   attestation,
+			expectedError: gitinterface.ErrKeyRevoked,
+		},
+		"commit, attestation, revoked attestation key, threshold 2": {
+			keys:          []*tuf.Key{gpgKey, rootPubKey},
+			threshold:     2,
+			revokedKeys:   map[string]*tuf.KeyRevocation{rootPubKey.KeyID: {}},
+			gitObject:     commit,
+			attestation:   attestation,
+			expectedError: ErrVerifierConditionsUnmet,
+		},
 	}
 
 	for name, test := range tests {
-		verifier := Verifier{name: "test-verifier", keys: test.keys, threshold: test.threshold}
+		verifier := Verifier{name: "test-verifier", keys: test.keys, threshold: test.threshold, allowedSignatureMethods: test.allowedSignatureMethods}
+		if test.revokedKeys != nil {
+			verifier.keyValidity = &keyValidity{revoked: test.revokedKeys}
+		}
 		err := verifier.Verify(context.Background(), test.gitObject, test.attestation)
 		if test.expectedError == nil {
 			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// defaultInterval is the minimum time between two progress updates. The final
+// update for a ref is always written.
+const defaultInterval = 500 * time.Millisecond
+
+type contextKey struct{}
+
+// Reporter writes the progress of a verification workflow, i.e. the number of
+// RSL entries verified out of the total, the ref being verified, and an
+// estimate of the time remaining. A nil Reporter is valid and reports nothing.
+type Reporter struct {
+	w        io.Writer
+	interval time.Duration
+	now      func() time.Time
+
+	ref         string
+	total       int
+	done        int
+	startedAt   time.Time
+	lastWritten time.Time
+}
+
+// NewReporter returns a Reporter that writes progress updates to w.
+func NewReporter(w io.Writer) *Reporter {
+	return &Reporter{w: w, interval: defaultInterval, now: time.Now}
+}
+
+// ContextWithReporter returns a copy of the context that carries the specified
+// reporter. This is used to surface progress during verification.
+func ContextWithReporter(ctx context.Context, reporter *Reporter) context.Context {
+	return context.WithValue(ctx, contextKey{}, reporter)
+}
+
+// ReporterFromContext returns the reporter carried by the context, if any.
+func ReporterFromContext(ctx context.Context) *Reporter {
+	reporter, ok := ctx.Value(contextKey{}).(*Reporter)
+	if !ok {
+		return nil
+	}
+
+	return reporter
+}
+
+// Start resets the reporter to track the verification of total entries for
+// the specified ref.
+func (r *Reporter) Start(ref string, total int) {
+	if r == nil {
+		return
+	}
+
+	r.ref = ref
+	r.total = total
+	r.done = 0
+	r.startedAt = r.now()
+	r.lastWritten = time.Time{}
+
+	r.write()
+}
+
+// Increment records that another entry has been verified.
+func (r *Reporter) Increment() {
+	if r == nil {
+		return
+	}
+
+	r.done++
+	if r.done == r.total || r.now().Sub(r.lastWritten) >= r.interval {
+		r.write()
+	}
+}
+
+// Finish writes the final update for the current ref. It must be called once
+// verification ends, whether or not it succeeded.
+func (r *Reporter) Finish() {
+	if r == nil || r.ref == "" {
+		return
+	}
+
+	if r.done != r.total {
+		r.write()
+	}
+	fmt.Fprintln(r.w)
+	r.ref = ""
+}
+
+func (r *Reporter) write() {
+	r.lastWritten = r.now()
+
+	percent := 100
+	if r.total != 0 {
+		percent = r.done * 100 / r.total
+	}
+
+	line := fmt.Sprintf("Verifying %s: %d/%d entries (%d%%)", r.ref, r.done, r.total, percent)
+	if r.done != 0 && r.done < r.total {
+		elapsed := r.lastWritten.Sub(r.startedAt)
+		remaining := elapsed / time.Duration(r.done) * time.Duration(r.total-r.done)
+		line += fmt.Sprintf(", ETA %s", remaining.Round(time.Second))
+	}
+
+	// The carriage return and trailing padding overwrite the previous update
+	// on terminals
+	fmt.Fprintf(r.w, "\r%-72s", line)
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package progress
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReporter(t *testing.T) {
+	t.Run("updates and ETA", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		reporter := NewReporter(buf)
+
+		current := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		reporter.now = func() time.Time { return current }
+
+		reporter.Start("refs/heads/main", 4)
+		assert.Contains(t, buf.String(), "Verifying refs/heads/main: 0/4 entries (0%)")
+
+		current = current.Add(10 * time.Second)
+		reporter.Increment()
+		assert.Contains(t, buf.String(), "1/4 entries (25%), ETA 30s")
+
+		// Updates within the interval are suppressed
+		reporter.Increment()
+		assert.NotContains(t, buf.String(), "2/4")
+
+		current = current.Add(10 * time.Second)
+		reporter.Increment()
+		assert.Contains(t, buf.String(), "3/4 entries (75%), ETA 7s")
+
+		// The last entry is always written
+		reporter.Increment()
+		assert.Contains(t, buf.String(), "4/4 entries (100%)")
+
+		reporter.Finish()
+		assert.True(t, strings.HasSuffix(buf.String(), "\n"))
+		assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	})
+
+	t.Run("finish before all entries are verified", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		reporter := NewReporter(buf)
+
+		reporter.Start("refs/heads/main", 4)
+		reporter.Increment()
+		reporter.Finish()
+		assert.Contains(t, buf.String(), "1/4 entries (25%)")
+		assert.True(t, strings.HasSuffix(buf.String(), "\n"))
+
+		// Finishing twice doesn't write anything else
+		written := buf.Len()
+		reporter.Finish()
+		assert.Equal(t, written, buf.Len())
+	})
+
+	t.Run("nil reporter", func(t *testing.T) {
+		var reporter *Reporter
+		assert.NotPanics(t, func() {
+			reporter.Start("refs/heads/main", 1)
+			reporter.Increment()
+			reporter.Finish()
+		})
+	})
+}
+
+func TestReporterFromContext(t *testing.T) {
+	assert.Nil(t, ReporterFromContext(context.Background()))
+
+	reporter := NewReporter(&bytes.Buffer{})
+	ctx := ContextWithReporter(context.Background(), reporter)
+	assert.Equal(t, reporter, ReporterFromContext(ctx))
+}
@@ -0,0 +1,409 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package recordservice
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// logger logs the messages of the recordservice subsystem.
+var logger = logging.For("recordservice")
+
+const (
+	// Platforms the service receives push webhooks from.
+	PlatformGitHub = "github"
+	PlatformGitLab = "gitlab"
+	PlatformGitea  = "gitea"
+
+	githubEventHeader      = "X-GitHub-Event"
+	githubSignatureHeader  = "X-Hub-Signature-256"
+	githubSignaturePrefix  = "sha256="
+	githubPushEventType    = "push"
+	gitlabEventHeader      = "X-Gitlab-Event"
+	gitlabTokenHeader      = "X-Gitlab-Token"
+	gitlabPushEventType    = "Push Hook"
+	gitlabTagPushEventType = "Tag Push Hook"
+	giteaEventHeader       = "X-Gitea-Event"
+	giteaSignatureHeader   = "X-Gitea-Signature"
+	forgejoEventHeader     = "X-Forgejo-Event"
+	forgejoSignatureHeader = "X-Forgejo-Signature"
+	giteaPushEventType     = "push"
+
+	// maxPayloadSize is the maximum size of the webhook payloads accepted.
+	maxPayloadSize = 25 << 20
+
+	// maxPushAttempts is the number of times the service attempts to record
+	// and push an RSL entry for a push, as the remote's RSL may be updated
+	// concurrently by others.
+	maxPushAttempts = 3
+
+	gittufNamespacePrefix = "refs/gittuf/"
+)
+
+var (
+	ErrMissingToken         = errors.New("access token must be set")
+	ErrMissingWebhookSecret = errors.New("webhook secret must be set")
+
+	errInvalidSignature = errors.New("invalid webhook signature")
+)
+
+var (
+	syncRepository = gitinterface.SyncMirror
+	recordPush     = recordEntry
+	pushRepository = pushRefs
+)
+
+// PushEvent is a push made on a hosting platform, normalized from the
+// platform's push webhook payload.
+type PushEvent struct {
+	// Platform is the platform the push was made on, such as PlatformGitHub.
+	Platform string
+
+	// Repository is the full name of the repository on the platform.
+	Repository string
+
+	// CloneURL is the HTTP(S) URL of the repository.
+	CloneURL string
+
+	// Ref is the ref updated by the push.
+	Ref string
+
+	// After is the ref's target after the push, zero if the ref was deleted.
+	After string
+
+	// Pusher is the platform account that made the push.
+	Pusher string
+}
+
+// githubPushEvent is the payload of GitHub's push webhooks. Gitea and Forgejo
+// send compatible payloads.
+type githubPushEvent struct {
+	Ref        string `json:"ref"`
+	After      string `json:"after"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		CloneURL string `json:"clone_url"`
+	} `json:"repository"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+// gitlabPushEvent is the payload of GitLab's push and tag push webhooks.
+type gitlabPushEvent struct {
+	Ref          string `json:"ref"`
+	After        string `json:"after"`
+	UserUsername string `json:"user_username"`
+	Project      struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+		GitHTTPURL        string `json:"git_http_url"`
+	} `json:"project"`
+}
+
+// Config contains the settings of the service.
+type Config struct {
+	// Token is an access token with write access to the repositories the
+	// service records pushes for, used to fetch the repositories and to push
+	// their RSLs.
+	Token string
+
+	// WebhookSecret is the secret configured for the repositories' webhooks.
+	// For GitLab, it's the webhooks' secret token.
+	WebhookSecret []byte
+
+	// CacheDir is the directory the repositories are mirrored to.
+	CacheDir string
+
+	// Signer, if set, signs push event attestations that record the platform
+	// and the account that made each push.
+	Signer sslibdsse.SignerVerifier
+}
+
+// Service records RSL entries for pushes made on hosting platforms, such as
+// commits created using a platform's UI and merges made by a platform, so that
+// such pushes don't leave gaps in the repositories' RSLs. The entries are
+// signed using the bot identity configured for Git signing on the service's
+// host.
+type Service struct {
+	token         string
+	webhookSecret []byte
+	cacheDir      string
+	signer        sslibdsse.SignerVerifier
+
+	// repositoryLocks serializes the recording of pushes to the same
+	// repository, as they share a mirror.
+	repositoryLocks map[string]*sync.Mutex
+	mu              sync.Mutex
+	pending         sync.WaitGroup
+}
+
+// NewService returns a Service for the specified configuration.
+func NewService(config *Config) (*Service, error) {
+	switch {
+	case config.Token == "":
+		return nil, ErrMissingToken
+	case len(config.WebhookSecret) == 0:
+		return nil, ErrMissingWebhookSecret
+	}
+
+	return &Service{
+		token:           config.Token,
+		webhookSecret:   config.WebhookSecret,
+		cacheDir:        config.CacheDir,
+		signer:          config.Signer,
+		repositoryLocks: map[string]*sync.Mutex{},
+	}, nil
+}
+
+// ServeHTTP handles webhook deliveries from GitHub, GitLab, Gitea, and
+// Forgejo. Push events are acknowledged immediately and recorded in the
+// background. Other events are ignored.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "unable to read payload", http.StatusBadRequest)
+		return
+	}
+
+	event, err := s.parseDelivery(r.Header, payload)
+	switch {
+	case errors.Is(err, errInvalidSignature):
+		http.Error(w, "invalid webhook signature", http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, "invalid push event", http.StatusBadRequest)
+		return
+	case event == nil:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+
+		if err := s.HandlePush(context.Background(), event); err != nil {
+			logger.Error("Unable to record push", "ref", event.Ref, "repository", event.Repository, "error", err)
+		}
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Wait blocks until the recording of all acknowledged pushes completes.
+func (s *Service) Wait() {
+	s.pending.Wait()
+}
+
+// HandlePush records an RSL entry for the ref updated by a push event in the
+// repository's RSL, unless the RSL already records the pushed target, such as
+// when the push was made by a gittuf client. gittuf's own refs are not
+// recorded. If the remote's RSL is updated while the entry is recorded, the
+// service fetches it again and retries.
+func (s *Service) HandlePush(ctx context.Context, event *PushEvent) error {
+	if strings.HasPrefix(event.Ref, gittufNamespacePrefix) {
+		return nil
+	}
+
+	lock := s.repositoryLock(event.Platform + "/" + event.Repository)
+	lock.Lock()
+	defer lock.Unlock()
+
+	repoPath := filepath.Join(s.cacheDir, event.Platform, filepath.FromSlash(event.Repository)+".git")
+	auth := s.auth(event.Platform)
+
+	refs := []string{rsl.Ref}
+	if s.signer != nil {
+		refs = append(refs, attestations.Ref)
+	}
+
+	var pushErr error
+	for attempt := 1; attempt <= maxPushAttempts; attempt++ {
+		logger.Debug(fmt.Sprintf("Fetching '%s'...", event.Repository))
+		if err := syncRepository(ctx, repoPath, event.CloneURL, auth); err != nil {
+			return err
+		}
+
+		logger.Debug(fmt.Sprintf("Recording push to '%s' in '%s' by '%s'...", event.Ref, event.Repository, event.Pusher))
+		recorded, err := recordPush(ctx, repoPath, s.signer, event)
+		if err != nil {
+			return err
+		}
+		if !recorded {
+			logger.Debug(fmt.Sprintf("RSL of '%s' already records push to '%s'", event.Repository, event.Ref))
+			return nil
+		}
+
+		pushErr = pushRepository(ctx, repoPath, event.CloneURL, refs, auth)
+		if pushErr == nil {
+			return nil
+		}
+		logger.Debug(fmt.Sprintf("Unable to push RSL of '%s' (attempt %d of %d): %s", event.Repository, attempt, maxPushAttempts, pushErr.Error()))
+	}
+
+	return fmt.Errorf("unable to push RSL: %w", pushErr)
+}
+
+// parseDelivery authenticates a webhook delivery and returns the push event it
+// contains, or nil if the delivery is not a push event.
+func (s *Service) parseDelivery(header http.Header, payload []byte) (*PushEvent, error) {
+	// Gitea and Forgejo also send GitHub's headers, so they're checked first
+	giteaEventType := header.Get(forgejoEventHeader)
+	if giteaEventType == "" {
+		giteaEventType = header.Get(giteaEventHeader)
+	}
+
+	switch {
+	case giteaEventType != "":
+		signature := header.Get(forgejoSignatureHeader)
+		if signature == "" {
+			signature = header.Get(giteaSignatureHeader)
+		}
+		if !s.validSignature(payload, signature) {
+			return nil, errInvalidSignature
+		}
+		if giteaEventType != giteaPushEventType {
+			return nil, nil
+		}
+
+		return parseGitHubPushEvent(PlatformGitea, payload)
+
+	case header.Get(gitlabEventHeader) != "":
+		if subtle.ConstantTimeCompare([]byte(header.Get(gitlabTokenHeader)), s.webhookSecret) != 1 {
+			return nil, errInvalidSignature
+		}
+		if eventType := header.Get(gitlabEventHeader); eventType != gitlabPushEventType && eventType != gitlabTagPushEventType {
+			return nil, nil
+		}
+
+		event := &gitlabPushEvent{}
+		if err := json.Unmarshal(payload, event); err != nil {
+			return nil, err
+		}
+		return &PushEvent{
+			Platform:   PlatformGitLab,
+			Repository: event.Project.PathWithNamespace,
+			CloneURL:   event.Project.GitHTTPURL,
+			Ref:        event.Ref,
+			After:      event.After,
+			Pusher:     event.UserUsername,
+		}, nil
+
+	default:
+		signature, hasPrefix := strings.CutPrefix(header.Get(githubSignatureHeader), githubSignaturePrefix)
+		if !hasPrefix || !s.validSignature(payload, signature) {
+			return nil, errInvalidSignature
+		}
+		if header.Get(githubEventHeader) != githubPushEventType {
+			return nil, nil
+		}
+
+		return parseGitHubPushEvent(PlatformGitHub, payload)
+	}
+}
+
+func parseGitHubPushEvent(platform string, payload []byte) (*PushEvent, error) {
+	event := &githubPushEvent{}
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, err
+	}
+
+	return &PushEvent{
+		Platform:   platform,
+		Repository: event.Repository.FullName,
+		CloneURL:   event.Repository.CloneURL,
+		Ref:        event.Ref,
+		After:      event.After,
+		Pusher:     event.Sender.Login,
+	}, nil
+}
+
+// validSignature returns true if the signature is the hex encoded HMAC-SHA256
+// of the payload using the webhook secret.
+func (s *Service) validSignature(payload []byte, signature string) bool {
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, s.webhookSecret)
+	mac.Write(payload)
+
+	return hmac.Equal(mac.Sum(nil), signatureBytes)
+}
+
+// auth returns the credentials used to fetch from and push to repositories on
+// the platform, which expect different usernames with access tokens.
+func (s *Service) auth(platform string) transport.AuthMethod {
+	username := "gittuf"
+	switch platform {
+	case PlatformGitHub:
+		username = "x-access-token"
+	case PlatformGitLab:
+		username = "oauth2"
+	}
+
+	return &githttp.BasicAuth{Username: username, Password: s.token}
+}
+
+func (s *Service) repositoryLock(name string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, has := s.repositoryLocks[name]
+	if !has {
+		lock = &sync.Mutex{}
+		s.repositoryLocks[name] = lock
+	}
+
+	return lock
+}
+
+// recordEntry records an RSL entry for the push in the mirror of the
+// repository at the specified path, returning false if the RSL already
+// records it.
+func recordEntry(ctx context.Context, path string, signer sslibdsse.SignerVerifier, event *PushEvent) (bool, error) {
+	repo, err := repository.LoadRepositoryAt(path)
+	if err != nil {
+		return false, err
+	}
+
+	return repo.RecordRSLEntryForPlatformPush(ctx, signer, event.Ref, plumbing.NewHash(event.After), event.Platform, event.Pusher, true)
+}
+
+// pushRefs pushes the refs of the mirror of the repository at the specified
+// path to the repository.
+func pushRefs(ctx context.Context, path, cloneURL string, refs []string, auth transport.AuthMethod) error {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return err
+	}
+	if err := gitinterface.CheckObjectFormat(repo); err != nil {
+		return err
+	}
+
+	return gitinterface.PushMirror(ctx, repo, cloneURL, refs, auth)
+}
@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package recordservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testToken    = "access-token"
+	testCommitID = "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"
+)
+
+var testWebhookSecret = []byte("webhook-secret")
+
+func TestService(t *testing.T) {
+	var (
+		recorded    bool
+		recordedFor []*PushEvent
+		pushErrs    []error
+		pushedRefs  []string
+		usernames   []string
+	)
+	syncRepository = func(_ context.Context, _, _ string, auth transport.AuthMethod) error {
+		usernames = append(usernames, auth.(*githttp.BasicAuth).Username)
+		return nil
+	}
+	recordPush = func(_ context.Context, _ string, _ sslibdsse.SignerVerifier, event *PushEvent) (bool, error) {
+		recordedFor = append(recordedFor, event)
+		return recorded, nil
+	}
+	pushRepository = func(_ context.Context, _, _ string, refs []string, _ transport.AuthMethod) error {
+		pushedRefs = refs
+		if len(pushErrs) == 0 {
+			return nil
+		}
+		err := pushErrs[0]
+		pushErrs = pushErrs[1:]
+		return err
+	}
+	t.Cleanup(func() {
+		syncRepository = gitinterface.SyncMirror
+		recordPush = recordEntry
+		pushRepository = pushRefs
+	})
+
+	reset := func() {
+		recorded = true
+		recordedFor = nil
+		pushErrs = nil
+		pushedRefs = nil
+		usernames = nil
+	}
+
+	newService := func(t *testing.T) *Service {
+		t.Helper()
+
+		service, err := NewService(&Config{
+			Token:         testToken,
+			WebhookSecret: testWebhookSecret,
+			CacheDir:      t.TempDir(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return service
+	}
+
+	newPushEvent := func(refName string) *PushEvent {
+		return &PushEvent{
+			Platform:   PlatformGitHub,
+			Repository: "owner/repo",
+			CloneURL:   "https://github.com/owner/repo.git",
+			Ref:        refName,
+			After:      testCommitID,
+			Pusher:     "web-flow",
+		}
+	}
+
+	t.Run("missing settings", func(t *testing.T) {
+		_, err := NewService(&Config{WebhookSecret: testWebhookSecret})
+		assert.ErrorIs(t, err, ErrMissingToken)
+
+		_, err = NewService(&Config{Token: testToken})
+		assert.ErrorIs(t, err, ErrMissingWebhookSecret)
+	})
+
+	t.Run("recorded push", func(t *testing.T) {
+		reset()
+
+		assert.Nil(t, newService(t).HandlePush(context.Background(), newPushEvent("refs/heads/main")))
+		assert.Len(t, recordedFor, 1)
+		assert.Equal(t, []string{rsl.Ref}, pushedRefs)
+		assert.Equal(t, []string{"x-access-token"}, usernames)
+	})
+
+	t.Run("push already recorded", func(t *testing.T) {
+		reset()
+		recorded = false
+
+		assert.Nil(t, newService(t).HandlePush(context.Background(), newPushEvent("refs/heads/main")))
+		assert.Len(t, recordedFor, 1)
+		assert.Nil(t, pushedRefs)
+	})
+
+	t.Run("retried push", func(t *testing.T) {
+		reset()
+		pushErrs = []error{errors.New("non-fast-forward update")}
+
+		assert.Nil(t, newService(t).HandlePush(context.Background(), newPushEvent("refs/heads/main")))
+		assert.Len(t, recordedFor, 2)
+
+		reset()
+		pushErrs = []error{errors.New("non-fast-forward update"), errors.New("non-fast-forward update"), errors.New("non-fast-forward update")}
+
+		assert.NotNil(t, newService(t).HandlePush(context.Background(), newPushEvent("refs/heads/main")))
+		assert.Len(t, recordedFor, maxPushAttempts)
+	})
+
+	t.Run("ignored pushes", func(t *testing.T) {
+		reset()
+
+		assert.Nil(t, newService(t).HandlePush(context.Background(), newPushEvent(rsl.Ref)))
+		assert.Empty(t, recordedFor)
+	})
+
+	t.Run("webhook deliveries", func(t *testing.T) {
+		tests := map[string]struct {
+			headers          map[string]string
+			payload          string
+			sign             func(payload []byte) string
+			signatureHeader  string
+			expectedCode     int
+			expectedPlatform string
+			expectedPusher   string
+			expectedUsername string
+		}{
+			"GitHub push": {
+				headers:          map[string]string{githubEventHeader: githubPushEventType},
+				payload:          `{"ref": "refs/heads/main", "after": "` + testCommitID + `", "repository": {"full_name": "owner/repo", "clone_url": "https://github.com/owner/repo.git"}, "sender": {"login": "web-flow"}}`,
+				sign:             func(payload []byte) string { return githubSignaturePrefix + sign(payload, testWebhookSecret) },
+				signatureHeader:  githubSignatureHeader,
+				expectedCode:     http.StatusAccepted,
+				expectedPlatform: PlatformGitHub,
+				expectedPusher:   "web-flow",
+				expectedUsername: "x-access-token",
+			},
+			"GitHub push with invalid signature": {
+				headers:         map[string]string{githubEventHeader: githubPushEventType},
+				payload:         `{}`,
+				sign:            func(payload []byte) string { return sign(payload, testWebhookSecret) },
+				signatureHeader: githubSignatureHeader,
+				expectedCode:    http.StatusUnauthorized,
+			},
+			"GitHub other event": {
+				headers:         map[string]string{githubEventHeader: "issues"},
+				payload:         `{}`,
+				sign:            func(payload []byte) string { return githubSignaturePrefix + sign(payload, testWebhookSecret) },
+				signatureHeader: githubSignatureHeader,
+				expectedCode:    http.StatusNoContent,
+			},
+			"GitLab push": {
+				headers:          map[string]string{gitlabEventHeader: gitlabPushEventType},
+				payload:          `{"ref": "refs/heads/main", "after": "` + testCommitID + `", "user_username": "merger", "project": {"path_with_namespace": "group/repo", "git_http_url": "https://gitlab.com/group/repo.git"}}`,
+				sign:             func(_ []byte) string { return string(testWebhookSecret) },
+				signatureHeader:  gitlabTokenHeader,
+				expectedCode:     http.StatusAccepted,
+				expectedPlatform: PlatformGitLab,
+				expectedPusher:   "merger",
+				expectedUsername: "oauth2",
+			},
+			"GitLab push with invalid token": {
+				headers:         map[string]string{gitlabEventHeader: gitlabPushEventType},
+				payload:         `{}`,
+				sign:            func(_ []byte) string { return "wrong-secret" },
+				signatureHeader: gitlabTokenHeader,
+				expectedCode:    http.StatusUnauthorized,
+			},
+			"Forgejo push": {
+				headers:          map[string]string{forgejoEventHeader: giteaPushEventType, githubEventHeader: githubPushEventType},
+				payload:          `{"ref": "refs/heads/main", "after": "` + testCommitID + `", "repository": {"full_name": "owner/repo", "clone_url": "https://codeberg.org/owner/repo.git"}, "sender": {"login": "editor"}}`,
+				sign:             func(payload []byte) string { return sign(payload, testWebhookSecret) },
+				signatureHeader:  forgejoSignatureHeader,
+				expectedCode:     http.StatusAccepted,
+				expectedPlatform: PlatformGitea,
+				expectedPusher:   "editor",
+				expectedUsername: "gittuf",
+			},
+		}
+
+		for name, test := range tests {
+			reset()
+			service := newService(t)
+
+			payload := []byte(test.payload)
+			request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+			for key, value := range test.headers {
+				request.Header.Set(key, value)
+			}
+			request.Header.Set(test.signatureHeader, test.sign(payload))
+
+			recorder := httptest.NewRecorder()
+			service.ServeHTTP(recorder, request)
+			service.Wait()
+
+			assert.Equal(t, test.expectedCode, recorder.Code, fmt.Sprintf("unexpected status code in test '%s'", name))
+			if test.expectedCode != http.StatusAccepted {
+				assert.Empty(t, recordedFor, fmt.Sprintf("unexpected recorded push in test '%s'", name))
+				continue
+			}
+
+			if assert.Len(t, recordedFor, 1, fmt.Sprintf("unexpected recorded pushes in test '%s'", name)) {
+				assert.Equal(t, test.expectedPlatform, recordedFor[0].Platform, fmt.Sprintf("unexpected platform in test '%s'", name))
+				assert.Equal(t, test.expectedPusher, recordedFor[0].Pusher, fmt.Sprintf("unexpected pusher in test '%s'", name))
+				assert.Equal(t, "refs/heads/main", recordedFor[0].Ref, fmt.Sprintf("unexpected ref in test '%s'", name))
+			}
+			assert.Equal(t, []string{test.expectedUsername}, usernames, fmt.Sprintf("unexpected username in test '%s'", name))
+		}
+	})
+}
+
+func sign(payload, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
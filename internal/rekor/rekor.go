@@ -0,0 +1,613 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rekor
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/timing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	entriesEndpoint   = "api/v1/log/entries"
+	retrieveEndpoint  = "api/v1/index/retrieve"
+	publicKeyEndpoint = "api/v1/log/publicKey"
+
+	dsseKind       = "dsse"
+	dsseAPIVersion = "0.0.1"
+
+	hashedRekordKind       = "hashedrekord"
+	hashedRekordAPIVersion = "0.0.1"
+)
+
+var (
+	ErrUnexpectedResponse     = errors.New("unexpected response from Rekor")
+	ErrInvalidLogEntry        = errors.New("Rekor log entry does not match envelope or artifact") //nolint:stylecheck
+	ErrInvalidInclusionProof  = errors.New("inclusion proof of Rekor log entry is invalid")
+	ErrInvalidSignedTimestamp = errors.New("signed entry timestamp of Rekor log entry is invalid")
+	ErrInvalidCheckpoint      = errors.New("checkpoint of Rekor log entry is invalid")
+)
+
+type contextKey struct{}
+
+// Client is used to interact with a Rekor instance, a transparency log that
+// records signed artifacts such as DSSE envelopes.
+type Client struct {
+	url        string
+	httpClient *http.Client
+
+	publicKeyOnce sync.Once
+	publicKey     crypto.PublicKey
+	publicKeyErr  error
+}
+
+// NewClient returns a Client for the Rekor instance at the specified URL.
+func NewClient(rekorURL string) *Client {
+	return &Client{
+		url:        strings.TrimSuffix(rekorURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+}
+
+// ContextWithClient returns a copy of the context that carries the specified
+// client. This is used to log attestations to a Rekor instance when they are
+// created, and to verify they were logged during verification.
+func ContextWithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, contextKey{}, client)
+}
+
+// ClientFromContext returns the client carried by the context, if any.
+func ClientFromContext(ctx context.Context) *Client {
+	client, ok := ctx.Value(contextKey{}).(*Client)
+	if !ok {
+		return nil
+	}
+
+	return client
+}
+
+// URL returns the location of the Rekor instance.
+func (c *Client) URL() string {
+	return c.url
+}
+
+// LogEntry is an entry in Rekor's log, along with the proof that it was
+// included in the log.
+type LogEntry struct {
+	UUID           string        `json:"uuid"`
+	Body           string        `json:"body"`
+	IntegratedTime int64         `json:"integratedTime"`
+	LogID          string        `json:"logID"`
+	LogIndex       int64         `json:"logIndex"`
+	Verification   *Verification `json:"verification"`
+}
+
+// Verification contains the information needed to verify a log entry offline.
+// The signed entry timestamp is Rekor's signature over the entry and the time
+// it was integrated into the log.
+type Verification struct {
+	InclusionProof       *InclusionProof `json:"inclusionProof"`
+	SignedEntryTimestamp string          `json:"signedEntryTimestamp"`
+}
+
+// InclusionProof is a Merkle tree inclusion proof for a log entry, along with
+// Rekor's signed checkpoint for the tree.
+type InclusionProof struct {
+	Checkpoint string   `json:"checkpoint"`
+	Hashes     []string `json:"hashes"`
+	LogIndex   int64    `json:"logIndex"`
+	RootHash   string   `json:"rootHash"`
+	TreeSize   int64    `json:"treeSize"`
+}
+
+// Upload logs the envelope to Rekor and returns the resulting log entry. The
+// verifiers are the PEM encoded public keys or certificates that Rekor uses to
+// verify the envelope's signatures. If the envelope was logged previously, the
+// existing entry is returned.
+func (c *Client) Upload(ctx context.Context, env *sslibdsse.Envelope, verifiers [][]byte) (*LogEntry, error) {
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedVerifiers := make([]string, 0, len(verifiers))
+	for _, verifier := range verifiers {
+		encodedVerifiers = append(encodedVerifiers, base64.StdEncoding.EncodeToString(verifier))
+	}
+
+	proposedEntry := map[string]any{
+		"kind":       dsseKind,
+		"apiVersion": dsseAPIVersion,
+		"spec": map[string]any{
+			"proposedContent": map[string]any{
+				"envelope":  string(envBytes),
+				"verifiers": encodedVerifiers,
+			},
+		},
+	}
+
+	return c.createEntry(ctx, proposedEntry)
+}
+
+// UploadHashedRekord logs the signature over an artifact with the specified
+// SHA-256 digest to Rekor and returns the resulting log entry. The verifier is
+// the PEM encoded public key or certificate that Rekor uses to verify the
+// signature. If the signature was logged previously, the existing entry is
+// returned.
+func (c *Client) UploadHashedRekord(ctx context.Context, digest, signature, verifier []byte) (*LogEntry, error) {
+	proposedEntry := map[string]any{
+		"kind":       hashedRekordKind,
+		"apiVersion": hashedRekordAPIVersion,
+		"spec": map[string]any{
+			"signature": map[string]any{
+				"content": base64.StdEncoding.EncodeToString(signature),
+				"publicKey": map[string]any{
+					"content": base64.StdEncoding.EncodeToString(verifier),
+				},
+			},
+			"data": map[string]any{
+				"hash": map[string]any{
+					"algorithm": "sha256",
+					"value":     hex.EncodeToString(digest),
+				},
+			},
+		},
+	}
+
+	return c.createEntry(ctx, proposedEntry)
+}
+
+// createEntry proposes the entry to Rekor and returns the resulting log entry,
+// or the existing entry if it was logged previously.
+func (c *Client) createEntry(ctx context.Context, proposedEntry map[string]any) (*LogEntry, error) {
+	proposedEntryBytes, err := json.Marshal(proposedEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	status, location, responseBytes, err := c.do(ctx, http.MethodPost, entriesEndpoint, proposedEntryBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case http.StatusCreated:
+		return parseLogEntries(responseBytes)
+	case http.StatusConflict:
+		// The entry was logged previously, Rekor returns the location of the
+		// existing entry
+		uuid := entryUUIDFromLocation(location)
+		if uuid == "" {
+			return nil, fmt.Errorf("%w: status %d: %s", ErrUnexpectedResponse, status, strings.TrimSpace(string(responseBytes)))
+		}
+		return c.GetEntry(ctx, uuid)
+	default:
+		return nil, fmt.Errorf("%w: status %d: %s", ErrUnexpectedResponse, status, strings.TrimSpace(string(responseBytes)))
+	}
+}
+
+// GetEntry fetches the log entry identified by the UUID from Rekor.
+func (c *Client) GetEntry(ctx context.Context, uuid string) (*LogEntry, error) {
+	status, _, responseBytes, err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%s", entriesEndpoint, url.PathEscape(uuid)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d: %s", ErrUnexpectedResponse, status, strings.TrimSpace(string(responseBytes)))
+	}
+
+	return parseLogEntries(responseBytes)
+}
+
+// SearchByPayloadDigest returns the UUIDs of the log entries for DSSE envelopes
+// whose payload has the specified SHA-256 digest.
+func (c *Client) SearchByPayloadDigest(ctx context.Context, payloadDigest string) ([]string, error) {
+	queryBytes, err := json.Marshal(map[string]string{"hash": "sha256:" + payloadDigest})
+	if err != nil {
+		return nil, err
+	}
+
+	status, _, responseBytes, err := c.do(ctx, http.MethodPost, retrieveEndpoint, queryBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d: %s", ErrUnexpectedResponse, status, strings.TrimSpace(string(responseBytes)))
+	}
+
+	uuids := []string{}
+	if err := json.Unmarshal(responseBytes, &uuids); err != nil {
+		return nil, errors.Join(ErrUnexpectedResponse, err)
+	}
+
+	return uuids, nil
+}
+
+// PublicKey returns the public key Rekor uses to sign checkpoints and entry
+// timestamps. The key is fetched once and reused for subsequent calls.
+func (c *Client) PublicKey(ctx context.Context) (crypto.PublicKey, error) {
+	c.publicKeyOnce.Do(func() {
+		status, _, responseBytes, err := c.do(ctx, http.MethodGet, publicKeyEndpoint, nil)
+		if err != nil {
+			c.publicKeyErr = err
+			return
+		}
+
+		if status != http.StatusOK {
+			c.publicKeyErr = fmt.Errorf("%w: status %d: %s", ErrUnexpectedResponse, status, strings.TrimSpace(string(responseBytes)))
+			return
+		}
+
+		c.publicKey, c.publicKeyErr = ParsePublicKey(responseBytes)
+	})
+
+	return c.publicKey, c.publicKeyErr
+}
+
+// ParsePublicKey parses a PEM encoded public key.
+func ParsePublicKey(publicKeyBytes []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(publicKeyBytes)
+	if block == nil {
+		return nil, fmt.Errorf("%w: public key is not PEM encoded", ErrUnexpectedResponse)
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// PayloadDigest returns the hex encoded SHA-256 digest of the envelope's
+// payload, which Rekor indexes DSSE entries by.
+func PayloadDigest(env *sslibdsse.Envelope) (string, error) {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(payload)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// VerifyForEnvelope checks that the log entry records the envelope's payload,
+// that Rekor signed the entry's timestamp, and that the entry is included in
+// the tree committed to by Rekor's signed checkpoint. The public key is the key
+// of the Rekor instance.
+func (e *LogEntry) VerifyForEnvelope(env *sslibdsse.Envelope, publicKey crypto.PublicKey) error {
+	if err := e.MatchesEnvelope(env); err != nil {
+		return err
+	}
+
+	return e.verify(publicKey)
+}
+
+// VerifyForDigest checks that the log entry records a signature over an
+// artifact with the specified SHA-256 digest, that Rekor signed the entry's
+// timestamp, and that the entry is included in the tree committed to by
+// Rekor's signed checkpoint. The public key is the key of the Rekor instance.
+func (e *LogEntry) VerifyForDigest(digest []byte, publicKey crypto.PublicKey) error {
+	body, err := base64.StdEncoding.DecodeString(e.Body)
+	if err != nil {
+		return errors.Join(ErrInvalidLogEntry, err)
+	}
+
+	entryBody := struct {
+		Kind string `json:"kind"`
+		Spec struct {
+			Data struct {
+				Hash struct {
+					Algorithm string `json:"algorithm"`
+					Value     string `json:"value"`
+				} `json:"hash"`
+			} `json:"data"`
+		} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(body, &entryBody); err != nil {
+		return errors.Join(ErrInvalidLogEntry, err)
+	}
+
+	if entryBody.Kind != hashedRekordKind || entryBody.Spec.Data.Hash.Algorithm != "sha256" || entryBody.Spec.Data.Hash.Value != hex.EncodeToString(digest) {
+		return ErrInvalidLogEntry
+	}
+
+	return e.verify(publicKey)
+}
+
+// verify checks the entry's signed timestamp, its inclusion proof, and the
+// checkpoint of the inclusion proof.
+func (e *LogEntry) verify(publicKey crypto.PublicKey) error {
+	if e.Verification == nil || e.Verification.InclusionProof == nil {
+		return ErrInvalidInclusionProof
+	}
+
+	if err := e.verifySignedEntryTimestamp(publicKey); err != nil {
+		return err
+	}
+
+	body, err := base64.StdEncoding.DecodeString(e.Body)
+	if err != nil {
+		return errors.Join(ErrInvalidLogEntry, err)
+	}
+
+	if err := e.verifyInclusionProof(body); err != nil {
+		return err
+	}
+
+	return e.verifyCheckpoint(publicKey)
+}
+
+// MatchesEnvelope checks that the log entry records a DSSE envelope with the
+// same payload as the specified envelope. The envelopes' signatures may
+// differ.
+func (e *LogEntry) MatchesEnvelope(env *sslibdsse.Envelope) error {
+	payloadDigest, err := PayloadDigest(env)
+	if err != nil {
+		return err
+	}
+
+	body, err := base64.StdEncoding.DecodeString(e.Body)
+	if err != nil {
+		return errors.Join(ErrInvalidLogEntry, err)
+	}
+
+	entryBody := struct {
+		Kind string `json:"kind"`
+		Spec struct {
+			PayloadHash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"payloadHash"`
+		} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(body, &entryBody); err != nil {
+		return errors.Join(ErrInvalidLogEntry, err)
+	}
+
+	if entryBody.Kind != dsseKind || entryBody.Spec.PayloadHash.Algorithm != "sha256" || entryBody.Spec.PayloadHash.Value != payloadDigest {
+		return ErrInvalidLogEntry
+	}
+
+	return nil
+}
+
+func (e *LogEntry) verifySignedEntryTimestamp(publicKey crypto.PublicKey) error {
+	signature, err := base64.StdEncoding.DecodeString(e.Verification.SignedEntryTimestamp)
+	if err != nil {
+		return errors.Join(ErrInvalidSignedTimestamp, err)
+	}
+
+	// The signed entry timestamp is computed over the canonical JSON encoding
+	// of these fields, which is the encoding of the struct as the fields are
+	// declared in lexicographic order
+	signedFields, err := json.Marshal(struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogID          string `json:"logID"`
+		LogIndex       int64  `json:"logIndex"`
+	}{
+		Body:           e.Body,
+		IntegratedTime: e.IntegratedTime,
+		LogID:          e.LogID,
+		LogIndex:       e.LogIndex,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := verifySignature(publicKey, signedFields, signature); err != nil {
+		return errors.Join(ErrInvalidSignedTimestamp, err)
+	}
+
+	return nil
+}
+
+// verifyInclusionProof checks the Merkle tree inclusion proof for the entry as
+// described in RFC 9162, section 2.1.3.2.
+func (e *LogEntry) verifyInclusionProof(body []byte) error {
+	proof := e.Verification.InclusionProof
+	if proof.LogIndex < 0 || proof.LogIndex >= proof.TreeSize {
+		return ErrInvalidInclusionProof
+	}
+
+	rootHash, err := hex.DecodeString(proof.RootHash)
+	if err != nil {
+		return errors.Join(ErrInvalidInclusionProof, err)
+	}
+
+	fn := uint64(proof.LogIndex)
+	sn := uint64(proof.TreeSize - 1)
+	r := hashLeaf(body)
+
+	for _, encodedHash := range proof.Hashes {
+		p, err := hex.DecodeString(encodedHash)
+		if err != nil {
+			return errors.Join(ErrInvalidInclusionProof, err)
+		}
+
+		if sn == 0 {
+			return ErrInvalidInclusionProof
+		}
+
+		if fn&1 == 1 || fn == sn {
+			r = hashChildren(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = hashChildren(r, p)
+		}
+
+		fn >>= 1
+		sn >>= 1
+	}
+
+	if sn != 0 || !bytes.Equal(r, rootHash) {
+		return ErrInvalidInclusionProof
+	}
+
+	return nil
+}
+
+// verifyCheckpoint checks that Rekor signed the checkpoint recorded in the
+// inclusion proof, and that the checkpoint commits to the tree the proof is
+// for. The checkpoint is a signed note, where the first lines are the origin,
+// tree size, and root hash, followed by a blank line and signature lines.
+func (e *LogEntry) verifyCheckpoint(publicKey crypto.PublicKey) error {
+	proof := e.Verification.InclusionProof
+
+	note, signatures, found := strings.Cut(proof.Checkpoint, "\n\n")
+	if !found {
+		return ErrInvalidCheckpoint
+	}
+	note += "\n"
+
+	lines := strings.Split(note, "\n")
+	if len(lines) < 4 {
+		return ErrInvalidCheckpoint
+	}
+
+	treeSize, err := strconv.ParseInt(lines[1], 10, 64)
+	if err != nil || treeSize != proof.TreeSize {
+		return ErrInvalidCheckpoint
+	}
+
+	rootHash, err := base64.StdEncoding.DecodeString(lines[2])
+	if err != nil || hex.EncodeToString(rootHash) != proof.RootHash {
+		return ErrInvalidCheckpoint
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(signatures), "\n") {
+		// Each signature line is of the form "— <name> <signature>", where
+		// the signature is prefixed by a four byte key hint
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(fields[2])
+		if err != nil || len(signature) <= 4 {
+			continue
+		}
+
+		if err := verifySignature(publicKey, []byte(note), signature[4:]); err == nil {
+			return nil
+		}
+	}
+
+	return ErrInvalidCheckpoint
+}
+
+func (c *Client) do(ctx context.Context, method, endpoint string, body []byte) (int, string, []byte, error) {
+	defer timing.Start(timing.PhaseSigstore)()
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", c.url, endpoint), bodyReader)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	responseBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, "", nil, err
+	}
+
+	return response.StatusCode, response.Header.Get("Location"), responseBytes, nil
+}
+
+// parseLogEntries parses Rekor's response for a single log entry, which is a
+// map of the entry's UUID to the entry.
+func parseLogEntries(responseBytes []byte) (*LogEntry, error) {
+	entries := map[string]*LogEntry{}
+	if err := json.Unmarshal(responseBytes, &entries); err != nil {
+		return nil, errors.Join(ErrUnexpectedResponse, err)
+	}
+
+	if len(entries) != 1 {
+		return nil, fmt.Errorf("%w: expected one log entry, got %d", ErrUnexpectedResponse, len(entries))
+	}
+
+	for uuid, entry := range entries {
+		entry.UUID = uuid
+		return entry, nil
+	}
+
+	return nil, ErrUnexpectedResponse // unreachable
+}
+
+// entryUUIDFromLocation returns the UUID of the log entry at the location,
+// which is of the form `/api/v1/log/entries/<uuid>`.
+func entryUUIDFromLocation(location string) string {
+	_, uuid, found := strings.Cut(location, entriesEndpoint+"/")
+	if !found {
+		return ""
+	}
+
+	return uuid
+}
+
+func hashLeaf(leaf []byte) []byte {
+	hash := sha256.Sum256(append([]byte{0x00}, leaf...))
+	return hash[:]
+}
+
+func hashChildren(left, right []byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte{0x01}) //nolint:errcheck
+	hash.Write(left)         //nolint:errcheck
+	hash.Write(right)        //nolint:errcheck
+	return hash.Sum(nil)
+}
+
+func verifySignature(publicKey crypto.PublicKey, data, signature []byte) error {
+	digest := sha256.Sum256(data)
+
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return errors.New("ecdsa signature verification failed")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, signature) {
+			return errors.New("ed25519 signature verification failed")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
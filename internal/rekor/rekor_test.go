@@ -0,0 +1,430 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rekor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(server.URL + "/")
+	assert.Equal(t, server.URL, client.URL())
+
+	env := &sslibdsse.Envelope{
+		PayloadType: "application/vnd.in-toto+json",
+		Payload:     base64.StdEncoding.EncodeToString([]byte(`{"predicateType": "https://gittuf.dev/test/v0.1"}`)),
+		Signatures:  []sslibdsse.Signature{{KeyID: "key-1", Sig: "c2lnbmF0dXJl"}},
+	}
+	payloadDigest, err := PayloadDigest(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := client.PublicKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := client.Upload(ctx, env, [][]byte{[]byte("verifier")})
+	assert.Nil(t, err)
+	assert.Nil(t, entry.VerifyForEnvelope(env, publicKey))
+
+	// Uploading the same envelope returns the existing entry
+	existingEntry, err := client.Upload(ctx, env, [][]byte{[]byte("verifier")})
+	assert.Nil(t, err)
+	assert.Equal(t, entry.UUID, existingEntry.UUID)
+
+	// A different signature on the same payload is logged separately
+	counterSignedEnv := &sslibdsse.Envelope{
+		PayloadType: env.PayloadType,
+		Payload:     env.Payload,
+		Signatures:  []sslibdsse.Signature{{KeyID: "key-2", Sig: "c2lnbmF0dXJlLTI="}},
+	}
+	counterSignedEntry, err := client.Upload(ctx, counterSignedEnv, [][]byte{[]byte("verifier")})
+	assert.Nil(t, err)
+	assert.NotEqual(t, entry.UUID, counterSignedEntry.UUID)
+	assert.Nil(t, counterSignedEntry.VerifyForEnvelope(env, publicKey))
+
+	uuids, err := client.SearchByPayloadDigest(ctx, payloadDigest)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{entry.UUID, counterSignedEntry.UUID}, uuids)
+
+	uuids, err = client.SearchByPayloadDigest(ctx, strings.Repeat("0", 64))
+	assert.Nil(t, err)
+	assert.Empty(t, uuids)
+
+	// The entry fetched later has a proof for the larger tree
+	fetchedEntry, err := client.GetEntry(ctx, entry.UUID)
+	assert.Nil(t, err)
+	assert.Equal(t, entry.UUID, fetchedEntry.UUID)
+	assert.NotEqual(t, entry.Verification.InclusionProof.TreeSize, fetchedEntry.Verification.InclusionProof.TreeSize)
+	assert.Nil(t, fetchedEntry.VerifyForEnvelope(env, publicKey))
+
+	_, err = client.GetEntry(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrUnexpectedResponse)
+}
+
+func TestLogEntryVerifyForEnvelope(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(server.URL)
+
+	env := &sslibdsse.Envelope{
+		PayloadType: "application/vnd.in-toto+json",
+		Payload:     base64.StdEncoding.EncodeToString([]byte(`{"predicateType": "https://gittuf.dev/test/v0.1"}`)),
+		Signatures:  []sslibdsse.Signature{{KeyID: "key-1", Sig: "c2lnbmF0dXJl"}},
+	}
+
+	publicKey, err := client.PublicKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getEntry := func() *LogEntry {
+		t.Helper()
+
+		entry, err := client.Upload(ctx, env, [][]byte{[]byte("verifier")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entry
+	}
+
+	t.Run("valid entry", func(t *testing.T) {
+		entry := getEntry()
+		assert.Nil(t, entry.VerifyForEnvelope(env, publicKey))
+	})
+
+	t.Run("entry for different envelope", func(t *testing.T) {
+		otherEnv := &sslibdsse.Envelope{
+			PayloadType: env.PayloadType,
+			Payload:     base64.StdEncoding.EncodeToString([]byte(`{}`)),
+			Signatures:  env.Signatures,
+		}
+
+		entry := getEntry()
+		assert.ErrorIs(t, entry.VerifyForEnvelope(otherEnv, publicKey), ErrInvalidLogEntry)
+	})
+
+	t.Run("modified integrated time", func(t *testing.T) {
+		entry := getEntry()
+		entry.IntegratedTime++
+		assert.ErrorIs(t, entry.VerifyForEnvelope(env, publicKey), ErrInvalidSignedTimestamp)
+	})
+
+	t.Run("different Rekor key", func(t *testing.T) {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := getEntry()
+		assert.ErrorIs(t, entry.VerifyForEnvelope(env, otherKey.Public()), ErrInvalidSignedTimestamp)
+	})
+
+	t.Run("modified inclusion proof", func(t *testing.T) {
+		entry := getEntry()
+		entry.Verification.InclusionProof.Hashes[0] = strings.Repeat("0", 64)
+		assert.ErrorIs(t, entry.VerifyForEnvelope(env, publicKey), ErrInvalidInclusionProof)
+	})
+
+	t.Run("missing inclusion proof", func(t *testing.T) {
+		entry := getEntry()
+		entry.Verification.InclusionProof = nil
+		assert.ErrorIs(t, entry.VerifyForEnvelope(env, publicKey), ErrInvalidInclusionProof)
+	})
+
+	t.Run("modified checkpoint", func(t *testing.T) {
+		entry := getEntry()
+		note, _, _ := strings.Cut(entry.Verification.InclusionProof.Checkpoint, "\n\n")
+		entry.Verification.InclusionProof.Checkpoint = fmt.Sprintf("%s\n\n— rekor.test %s\n", note, base64.StdEncoding.EncodeToString([]byte("hintsignature")))
+		assert.ErrorIs(t, entry.VerifyForEnvelope(env, publicKey), ErrInvalidCheckpoint)
+	})
+}
+
+func TestClientUploadHashedRekord(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(server.URL)
+
+	publicKey, err := client.PublicKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256([]byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nInitial commit\n"))
+
+	entry, err := client.UploadHashedRekord(ctx, digest[:], []byte("signature"), []byte("verifier"))
+	assert.Nil(t, err)
+	assert.Nil(t, entry.VerifyForDigest(digest[:], publicKey))
+
+	// Uploading the same signature returns the existing entry
+	existingEntry, err := client.UploadHashedRekord(ctx, digest[:], []byte("signature"), []byte("verifier"))
+	assert.Nil(t, err)
+	assert.Equal(t, entry.UUID, existingEntry.UUID)
+
+	otherDigest := sha256.Sum256([]byte("other"))
+	assert.ErrorIs(t, entry.VerifyForDigest(otherDigest[:], publicKey), ErrInvalidLogEntry)
+
+	// Entries for envelopes don't record a signature over an artifact
+	env := &sslibdsse.Envelope{
+		PayloadType: "application/vnd.in-toto+json",
+		Payload:     base64.StdEncoding.EncodeToString([]byte(`{"predicateType": "https://gittuf.dev/test/v0.1"}`)),
+		Signatures:  []sslibdsse.Signature{{KeyID: "key-1", Sig: "c2lnbmF0dXJl"}},
+	}
+	envEntry, err := client.Upload(ctx, env, [][]byte{[]byte("verifier")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.ErrorIs(t, envEntry.VerifyForDigest(digest[:], publicKey), ErrInvalidLogEntry)
+	assert.ErrorIs(t, entry.VerifyForEnvelope(env, publicKey), ErrInvalidLogEntry)
+}
+
+func TestContextWithClient(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, ClientFromContext(ctx))
+
+	client := NewClient("http://localhost:3000")
+	ctx = ContextWithClient(ctx, client)
+	assert.Equal(t, client, ClientFromContext(ctx))
+}
+
+// newTestServer returns a minimal Rekor instance that logs DSSE envelopes and
+// hashed artifacts.
+// The log is seeded with a few entries so that inclusion proofs are not
+// trivial.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(data []byte) string {
+		digest := sha256.Sum256(data)
+		signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.StdEncoding.EncodeToString(signature)
+	}
+
+	leaves := [][]byte{[]byte("seed-1"), []byte("seed-2")}
+	uuids := map[string]int{}
+	payloadDigests := map[string][]string{}
+
+	getEntry := func(uuid string) map[string]*LogEntry {
+		index := uuids[uuid]
+		body := base64.StdEncoding.EncodeToString(leaves[index])
+
+		leafHashes := make([][]byte, 0, len(leaves))
+		for _, leaf := range leaves {
+			leafHashes = append(leafHashes, hashLeaf(leaf))
+		}
+		rootHash := testMerkleTreeHash(leafHashes)
+		hashes := []string{}
+		for _, hash := range testAuditPath(index, leafHashes) {
+			hashes = append(hashes, hex.EncodeToString(hash))
+		}
+
+		note := fmt.Sprintf("rekor.test - 1\n%d\n%s\n", len(leaves), base64.StdEncoding.EncodeToString(rootHash))
+		noteSignature, err := base64.StdEncoding.DecodeString(sign([]byte(note)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkpoint := fmt.Sprintf("%s\n— rekor.test %s\n", note, base64.StdEncoding.EncodeToString(append([]byte("hint"), noteSignature...)))
+
+		entry := &LogEntry{
+			Body:           body,
+			IntegratedTime: 1700000000 + int64(index),
+			LogID:          "test-log-id",
+			LogIndex:       int64(index),
+		}
+		signedFields, err := json.Marshal(map[string]any{
+			"body":           entry.Body,
+			"integratedTime": entry.IntegratedTime,
+			"logID":          entry.LogID,
+			"logIndex":       entry.LogIndex,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.Verification = &Verification{
+			SignedEntryTimestamp: sign(signedFields),
+			InclusionProof: &InclusionProof{
+				Checkpoint: checkpoint,
+				Hashes:     hashes,
+				LogIndex:   int64(index),
+				RootHash:   hex.EncodeToString(rootHash),
+				TreeSize:   int64(len(leaves)),
+			},
+		}
+
+		return map[string]*LogEntry{uuid: entry}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/"+publicKeyEndpoint:
+			w.Write(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})) //nolint:errcheck
+		case r.Method == http.MethodPost && r.URL.Path == "/"+entriesEndpoint:
+			proposedEntry := struct {
+				Kind string          `json:"kind"`
+				Spec json.RawMessage `json:"spec"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&proposedEntry); err != nil {
+				t.Fatal(err)
+			}
+
+			var (
+				body          []byte
+				payloadDigest string
+				err           error
+			)
+			switch proposedEntry.Kind {
+			case dsseKind:
+				spec := struct {
+					ProposedContent struct {
+						Envelope string `json:"envelope"`
+					} `json:"proposedContent"`
+				}{}
+				if err := json.Unmarshal(proposedEntry.Spec, &spec); err != nil {
+					t.Fatal(err)
+				}
+
+				env := &sslibdsse.Envelope{}
+				if err := json.Unmarshal([]byte(spec.ProposedContent.Envelope), env); err != nil {
+					t.Fatal(err)
+				}
+				payloadDigest, err = PayloadDigest(env)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				body, err = json.Marshal(map[string]any{
+					"apiVersion": dsseAPIVersion,
+					"kind":       dsseKind,
+					"spec": map[string]any{
+						"payloadHash": map[string]string{"algorithm": "sha256", "value": payloadDigest},
+						"signatures":  env.Signatures,
+					},
+				})
+			case hashedRekordKind:
+				body, err = json.Marshal(map[string]any{
+					"apiVersion": hashedRekordAPIVersion,
+					"kind":       hashedRekordKind,
+					"spec":       proposedEntry.Spec,
+				})
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			uuid := hex.EncodeToString(hashLeaf(body))
+
+			if _, has := uuids[uuid]; has {
+				w.Header().Set("Location", "/"+entriesEndpoint+"/"+uuid)
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+
+			uuids[uuid] = len(leaves)
+			leaves = append(leaves, body)
+			if payloadDigest != "" {
+				payloadDigests[payloadDigest] = append(payloadDigests[payloadDigest], uuid)
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(getEntry(uuid)) //nolint:errcheck
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/"+entriesEndpoint+"/"):
+			uuid := strings.TrimPrefix(r.URL.Path, "/"+entriesEndpoint+"/")
+			if _, has := uuids[uuid]; !has {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			json.NewEncoder(w).Encode(getEntry(uuid)) //nolint:errcheck
+		case r.Method == http.MethodPost && r.URL.Path == "/"+retrieveEndpoint:
+			query := map[string]string{}
+			if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+				t.Fatal(err)
+			}
+
+			matches := payloadDigests[strings.TrimPrefix(query["hash"], "sha256:")]
+			if matches == nil {
+				matches = []string{}
+			}
+			json.NewEncoder(w).Encode(matches) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return server
+}
+
+// testMerkleTreeHash computes the root of the Merkle tree with the specified
+// leaf hashes as described in RFC 9162, section 2.1.1.
+func testMerkleTreeHash(leafHashes [][]byte) []byte {
+	if len(leafHashes) == 1 {
+		return leafHashes[0]
+	}
+
+	k := testSplitPoint(len(leafHashes))
+	return hashChildren(testMerkleTreeHash(leafHashes[:k]), testMerkleTreeHash(leafHashes[k:]))
+}
+
+// testAuditPath computes the inclusion proof for the leaf at the specified
+// index as described in RFC 9162, section 2.1.3.1.
+func testAuditPath(index int, leafHashes [][]byte) [][]byte {
+	if len(leafHashes) == 1 {
+		return nil
+	}
+
+	k := testSplitPoint(len(leafHashes))
+	if index < k {
+		return append(testAuditPath(index, leafHashes[:k]), testMerkleTreeHash(leafHashes[k:]))
+	}
+	return append(testAuditPath(index-k, leafHashes[k:]), testMerkleTreeHash(leafHashes[:k]))
+}
+
+// testSplitPoint returns the largest power of two smaller than n.
+func testSplitPoint(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
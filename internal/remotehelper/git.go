@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package remotehelper
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// peeledSuffix is the suffix of the lines listing the objects annotated tags
+// point to in the output of git ls-remote.
+const peeledSuffix = "^{}"
+
+// gitTransport uses the git binary to communicate with the remote. Git runs
+// remote helpers with GIT_DIR set to the local repository, which the git
+// commands inherit.
+type gitTransport struct {
+	url string
+}
+
+func (g *gitTransport) list(ctx context.Context) (map[string]plumbing.Hash, string, error) {
+	output, err := g.output(ctx, "ls-remote", "--symref", g.url)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return parseLsRemote(output)
+}
+
+func (g *gitTransport) fetch(ctx context.Context, refNames []string) error {
+	if len(refNames) == 0 {
+		return nil
+	}
+
+	// Refs are fetched without destinations so that only the objects are
+	// fetched, Git updates the refs once they're verified
+	args := append([]string{"fetch", "--quiet", "--no-tags", "--no-write-fetch-head", g.url}, refNames...)
+	return g.run(ctx, args...)
+}
+
+func (g *gitTransport) push(ctx context.Context, refSpecs []string) error {
+	args := append([]string{"push", "--quiet", "--atomic", g.url}, refSpecs...)
+	return g.run(ctx, args...)
+}
+
+func (g *gitTransport) resolve(ctx context.Context, refName string) (plumbing.Hash, error) {
+	output, err := g.output(ctx, "rev-parse", "--verify", refName)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return plumbing.NewHash(strings.TrimSpace(string(output))), nil
+}
+
+// run runs the git command. Its output is written to stderr, as stdout is used
+// to respond to Git.
+func (g *gitTransport) run(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w when executing `git %s`", err, strings.Join(args, " "))
+	}
+
+	return nil
+}
+
+func (g *gitTransport) output(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w when executing `git %s`: %s", err, strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+
+	return output, nil
+}
+
+// parseLsRemote parses the output of git ls-remote --symref, returning the
+// tips of the remote's refs and the ref its HEAD points to. If the remote's
+// HEAD is detached, it's included in the tips instead.
+func parseLsRemote(output []byte) (map[string]plumbing.Hash, string, error) {
+	tips := map[string]plumbing.Hash{}
+	head := ""
+	headID := plumbing.ZeroHash
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		value, refName, found := strings.Cut(scanner.Text(), "\t")
+		if !found {
+			continue
+		}
+
+		if target, isSymref := strings.CutPrefix(value, "ref: "); isSymref {
+			if refName == plumbing.HEAD.String() {
+				head = target
+			}
+			continue
+		}
+
+		switch {
+		case refName == plumbing.HEAD.String():
+			headID = plumbing.NewHash(value)
+		case !strings.HasSuffix(refName, peeledSuffix):
+			tips[refName] = plumbing.NewHash(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", err
+	}
+
+	// A detached HEAD is listed by its ID instead
+	if head == "" && !headID.IsZero() {
+		tips[plumbing.HEAD.String()] = headID
+	}
+
+	return tips, head, nil
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package remotehelper
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLsRemote(t *testing.T) {
+	mainID := "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"
+	tagID := "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e"
+
+	t.Run("symbolic HEAD", func(t *testing.T) {
+		output := "ref: refs/heads/main\tHEAD\n" +
+			mainID + "\tHEAD\n" +
+			mainID + "\trefs/heads/main\n" +
+			tagID + "\trefs/tags/v1\n" +
+			mainID + "\trefs/tags/v1^{}\n"
+
+		tips, head, err := parseLsRemote([]byte(output))
+		assert.Nil(t, err)
+		assert.Equal(t, "refs/heads/main", head)
+		assert.Equal(t, map[string]plumbing.Hash{
+			"refs/heads/main": plumbing.NewHash(mainID),
+			"refs/tags/v1":    plumbing.NewHash(tagID),
+		}, tips)
+	})
+
+	t.Run("detached HEAD", func(t *testing.T) {
+		tips, head, err := parseLsRemote([]byte(mainID + "\tHEAD\n"))
+		assert.Nil(t, err)
+		assert.Equal(t, "", head)
+		assert.Equal(t, map[string]plumbing.Hash{"HEAD": plumbing.NewHash(mainID)}, tips)
+	})
+}
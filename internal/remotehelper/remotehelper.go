@@ -0,0 +1,326 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package remotehelper
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// logger logs the messages of the remotehelper subsystem.
+var logger = logging.For("remotehelper")
+
+// URLPrefix is the prefix of remote URLs that Git hands to gittuf's remote
+// helper, such as gittuf::https://github.com/gittuf/gittuf.
+const URLPrefix = "gittuf::"
+
+var (
+	ErrUnknownCommand  = errors.New("unknown remote helper command")
+	ErrInvalidPushSpec = errors.New("invalid push refspec")
+	ErrFetchRejected   = errors.New("fetched refs failed gittuf verification")
+)
+
+// transport moves objects and refs between the local repository and the
+// remote.
+type transport interface {
+	// list returns the tips of the remote's refs and the ref its HEAD points
+	// to, if any.
+	list(ctx context.Context) (map[string]plumbing.Hash, string, error)
+
+	// fetch fetches the objects of the remote's refs without updating any
+	// local refs.
+	fetch(ctx context.Context, refNames []string) error
+
+	// push pushes the refspecs to the remote atomically.
+	push(ctx context.Context, refSpecs []string) error
+
+	// resolve returns the ID of the object a local ref points to.
+	resolve(ctx context.Context, refName string) (plumbing.Hash, error)
+}
+
+// Helper implements Git's remote helper protocol for remotes with gittuf::
+// URLs. Fetched refs are verified against the remote's RSL and policy before
+// Git updates any local refs, and pushed refs are recorded in the RSL, which is
+// pushed along with them.
+type Helper struct {
+	repo       *repository.Repository
+	transport  transport
+	signCommit bool
+
+	remoteTips map[string]plumbing.Hash
+}
+
+// New returns a Helper for the repository and the remote at url, which is the
+// remote's URL with the gittuf:: prefix removed. Git is used to communicate
+// with the remote, so that the user's Git configuration, such as credential
+// helpers, applies.
+func New(repo *repository.Repository, url string) *Helper {
+	return &Helper{
+		repo:       repo,
+		transport:  &gitTransport{url: strings.TrimPrefix(url, URLPrefix)},
+		signCommit: true,
+	}
+}
+
+// Run reads commands from Git on in and writes responses to out until Git
+// closes the connection.
+func (h *Helper) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			// Git sends an empty line when it's done
+			return nil
+
+		case line == "capabilities":
+			if _, err := io.WriteString(out, "fetch\npush\n\n"); err != nil {
+				return err
+			}
+
+		case line == "list" || line == "list for-push":
+			if err := h.list(ctx, out); err != nil {
+				return err
+			}
+
+		case strings.HasPrefix(line, "fetch "):
+			batch, err := readBatch(scanner, line)
+			if err != nil {
+				return err
+			}
+			if err := h.fetch(ctx, batch, out); err != nil {
+				return err
+			}
+
+		case strings.HasPrefix(line, "push "):
+			batch, err := readBatch(scanner, line)
+			if err != nil {
+				return err
+			}
+			if err := h.push(ctx, batch, out); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("%w '%s'", ErrUnknownCommand, line)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// list writes the remote's refs, and records their tips for verifying fetches
+// and pushes.
+func (h *Helper) list(ctx context.Context, out io.Writer) error {
+	remoteTips, head, err := h.transport.list(ctx)
+	if err != nil {
+		return err
+	}
+	h.remoteTips = remoteTips
+
+	refNames := make([]string, 0, len(remoteTips))
+	for refName := range remoteTips {
+		refNames = append(refNames, refName)
+	}
+	sort.Strings(refNames)
+
+	response := &strings.Builder{}
+	for _, refName := range refNames {
+		fmt.Fprintf(response, "%s %s\n", remoteTips[refName].String(), refName)
+	}
+	if head != "" {
+		fmt.Fprintf(response, "@%s HEAD\n", head)
+	}
+	response.WriteString("\n")
+
+	_, err = io.WriteString(out, response.String())
+	return err
+}
+
+// fetch fetches the objects of the requested refs along with the remote's
+// gittuf refs, and verifies the requested refs before Git updates the local
+// refs.
+func (h *Helper) fetch(ctx context.Context, batch []string, out io.Writer) error {
+	if h.remoteTips == nil {
+		if err := h.list(ctx, io.Discard); err != nil {
+			return err
+		}
+	}
+
+	refNames := []string{}
+	for _, line := range batch {
+		// Each line is "fetch <id> <ref>"
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return fmt.Errorf("%w '%s'", ErrUnknownCommand, line)
+		}
+		refNames = append(refNames, fields[2])
+	}
+
+	if err := h.fetchGittufRefs(ctx, refNames); err != nil {
+		return err
+	}
+
+	logger.Debug("Verifying fetched refs...")
+	rejected, err := h.repo.VerifyFetch(ctx, h.remoteTips, refNames)
+	if err != nil {
+		return err
+	}
+	if err := rejectedError(rejected); err != nil {
+		return errors.Join(ErrFetchRejected, err)
+	}
+
+	_, err = io.WriteString(out, "\n")
+	return err
+}
+
+// push records RSL entries for the pushed refs on top of the remote's RSL and
+// pushes the refs along with the RSL. If the push fails, the RSL entries are
+// discarded.
+func (h *Helper) push(ctx context.Context, batch []string, out io.Writer) error {
+	if h.remoteTips == nil {
+		if err := h.list(ctx, io.Discard); err != nil {
+			return err
+		}
+	}
+
+	refSpecs := []string{}
+	dstRefNames := []string{}
+	updates := []*repository.RefUpdate{}
+	for _, line := range batch {
+		refSpec := strings.TrimPrefix(line, "push ")
+		src, dst, err := parsePushSpec(refSpec)
+		if err != nil {
+			return err
+		}
+
+		update := &repository.RefUpdate{OldID: h.remoteTips[dst], RefName: dst}
+		if src != "" {
+			update.NewID, err = h.transport.resolve(ctx, src)
+			if err != nil {
+				return err
+			}
+		}
+
+		refSpecs = append(refSpecs, refSpec)
+		dstRefNames = append(dstRefNames, dst)
+		updates = append(updates, update)
+	}
+
+	pushErr := h.pushWithRSL(ctx, refSpecs, updates)
+
+	response := &strings.Builder{}
+	for _, dst := range dstRefNames {
+		if pushErr != nil {
+			fmt.Fprintf(response, "error %s %s\n", dst, strings.ReplaceAll(pushErr.Error(), "\n", " "))
+		} else {
+			fmt.Fprintf(response, "ok %s\n", dst)
+		}
+	}
+	response.WriteString("\n")
+
+	_, err := io.WriteString(out, response.String())
+	return err
+}
+
+func (h *Helper) pushWithRSL(ctx context.Context, refSpecs []string, updates []*repository.RefUpdate) error {
+	// The remote's RSL is fetched first so that the new entries are recorded
+	// on top of it
+	if !h.remoteTips[rsl.Ref].IsZero() {
+		if err := h.fetchGittufRefs(ctx, nil); err != nil {
+			return err
+		}
+
+		rejected, err := h.repo.VerifyFetch(ctx, h.remoteTips, nil)
+		if err != nil {
+			return err
+		}
+		if err := rejectedError(rejected); err != nil {
+			return err
+		}
+	}
+
+	restore, err := h.repo.RecordRSLEntriesForPush(updates, h.signCommit)
+	if err != nil {
+		return err
+	}
+
+	refSpecs = append(refSpecs, fmt.Sprintf("%s:%s", rsl.Ref, rsl.Ref))
+	if err := h.transport.push(ctx, refSpecs); err != nil {
+		return errors.Join(err, restore())
+	}
+
+	return nil
+}
+
+// fetchGittufRefs fetches the objects of the specified refs and of the remote's
+// gittuf refs.
+func (h *Helper) fetchGittufRefs(ctx context.Context, refNames []string) error {
+	for refName := range h.remoteTips {
+		if strings.HasPrefix(refName, "refs/gittuf/") {
+			refNames = append(refNames, refName)
+		}
+	}
+	sort.Strings(refNames)
+
+	logger.Debug("Fetching refs from remote...")
+	return h.transport.fetch(ctx, refNames)
+}
+
+// parsePushSpec returns the source and destination of a refspec passed to the
+// push command, which is of the form [+]<src>:<dst>. The source is empty for
+// deletions.
+func parsePushSpec(refSpec string) (string, string, error) {
+	src, dst, found := strings.Cut(strings.TrimPrefix(refSpec, "+"), ":")
+	if !found || dst == "" {
+		return "", "", fmt.Errorf("%w '%s'", ErrInvalidPushSpec, refSpec)
+	}
+
+	return src, dst, nil
+}
+
+// readBatch reads the remaining commands of a batch, which Git terminates with
+// an empty line.
+func readBatch(scanner *bufio.Scanner, first string) ([]string, error) {
+	batch := []string{first}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			return batch, nil
+		}
+		batch = append(batch, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// rejectedError returns an error describing each rejected ref, or nil if no
+// refs were rejected.
+func rejectedError(rejected map[string]error) error {
+	refNames := make([]string, 0, len(rejected))
+	for refName := range rejected {
+		refNames = append(refNames, refName)
+	}
+	sort.Strings(refNames)
+
+	errs := make([]error, 0, len(refNames))
+	for _, refName := range refNames {
+		errs = append(errs, fmt.Errorf("%s: %w", refName, rejected[refName]))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package remotehelper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTransport struct {
+	tips     map[string]plumbing.Hash
+	head     string
+	local    map[string]plumbing.Hash
+	pushErr  error
+	fetched  []string
+	refSpecs []string
+}
+
+func (f *fakeTransport) list(_ context.Context) (map[string]plumbing.Hash, string, error) {
+	return f.tips, f.head, nil
+}
+
+func (f *fakeTransport) fetch(_ context.Context, refNames []string) error {
+	f.fetched = append(f.fetched, refNames...)
+	return nil
+}
+
+func (f *fakeTransport) push(_ context.Context, refSpecs []string) error {
+	f.refSpecs = refSpecs
+	return f.pushErr
+}
+
+func (f *fakeTransport) resolve(_ context.Context, refName string) (plumbing.Hash, error) {
+	return f.local[refName], nil
+}
+
+func TestHelperList(t *testing.T) {
+	mainID := plumbing.NewHash("8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f")
+	rslID := plumbing.NewHash("3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e")
+
+	helper := &Helper{transport: &fakeTransport{
+		tips: map[string]plumbing.Hash{"refs/heads/main": mainID, rsl.Ref: rslID},
+		head: "refs/heads/main",
+	}}
+
+	out := &bytes.Buffer{}
+	err := helper.Run(context.Background(), strings.NewReader("capabilities\nlist\n\n"), out)
+	assert.Nil(t, err)
+	assert.Equal(t, "fetch\npush\n\n"+rslID.String()+" refs/gittuf/reference-state-log\n"+mainID.String()+" refs/heads/main\n@refs/heads/main HEAD\n\n", out.String())
+
+	err = helper.Run(context.Background(), strings.NewReader("connect git-upload-pack\n"), &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrUnknownCommand)
+}
+
+func TestHelperPush(t *testing.T) {
+	refName := "refs/heads/main"
+
+	newHelper := func(t *testing.T) (*Helper, *fakeTransport, *git.Repository) {
+		t.Helper()
+
+		tmpDir := t.TempDir()
+		r, err := git.PlainInit(tmpDir, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		treeID, err := gitinterface.WriteTree(r, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitID, err := gitinterface.Commit(r, treeID, refName, "Initial commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		repo, err := repository.LoadRepositoryAt(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		transport := &fakeTransport{
+			tips:  map[string]plumbing.Hash{},
+			local: map[string]plumbing.Hash{refName: commitID},
+		}
+		return &Helper{repo: repo, transport: transport}, transport, r
+	}
+
+	t.Run("successful push", func(t *testing.T) {
+		helper, transport, r := newHelper(t)
+
+		out := &bytes.Buffer{}
+		err := helper.Run(context.Background(), strings.NewReader("list for-push\npush refs/heads/main:refs/heads/main\n\n\n"), out)
+		assert.Nil(t, err)
+		assert.True(t, strings.HasSuffix(out.String(), "ok refs/heads/main\n\n"))
+		assert.Equal(t, []string{"refs/heads/main:refs/heads/main", "refs/gittuf/reference-state-log:refs/gittuf/reference-state-log"}, transport.refSpecs)
+
+		entry, err := rsl.GetLatestEntry(r)
+		assert.Nil(t, err)
+		assert.Equal(t, refName, entry.(*rsl.ReferenceEntry).RefName)
+		assert.Equal(t, transport.local[refName], entry.(*rsl.ReferenceEntry).TargetID)
+	})
+
+	t.Run("failed push", func(t *testing.T) {
+		helper, transport, r := newHelper(t)
+		transport.pushErr = errors.New("rejected")
+
+		out := &bytes.Buffer{}
+		err := helper.Run(context.Background(), strings.NewReader("push +refs/heads/main:refs/heads/main\n\n\n"), out)
+		assert.Nil(t, err)
+		assert.Equal(t, "error refs/heads/main rejected\n\n", out.String())
+
+		// The RSL entry is discarded
+		_, err = r.Reference(rsl.Ref, true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+}
+
+func TestParsePushSpec(t *testing.T) {
+	src, dst, err := parsePushSpec("+refs/heads/feature:refs/heads/main")
+	assert.Nil(t, err)
+	assert.Equal(t, "refs/heads/feature", src)
+	assert.Equal(t, "refs/heads/main", dst)
+
+	src, dst, err = parsePushSpec(":refs/heads/feature")
+	assert.Nil(t, err)
+	assert.Equal(t, "", src)
+	assert.Equal(t, "refs/heads/feature", dst)
+
+	_, _, err = parsePushSpec("refs/heads/main")
+	assert.ErrorIs(t, err, ErrInvalidPushSpec)
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Kinds of Git objects whose signatures are reported.
+const (
+	KindRSLEntry = "rsl_entry"
+	KindCommit   = "commit"
+	KindTag      = "tag"
+)
+
+// Sources of the signatures reported for a Git object.
+const (
+	SourceGit                    = "git"
+	SourceReferenceAuthorization = "reference_authorization"
+)
+
+// FormatDSSE is the format reported for the signatures on reference
+// authorizations. Git signatures are reported using the formats of
+// gitinterface.SignatureMethod.
+const FormatDSSE = "dsse"
+
+type contextKey struct{}
+
+// Report records the outcome of checking the signatures of each commit, tag,
+// and RSL entry during a verification workflow, so that automation can
+// consume the results without parsing error messages. A nil Report is valid
+// and records nothing.
+type Report struct {
+	// Command is the gittuf command that performed verification, such as
+	// "verify-ref".
+	Command string `json:"command"`
+
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+
+	// Verified indicates if verification succeeded overall, and Error is the
+	// reason it failed.
+	Verified bool   `json:"verified"`
+	Error    string `json:"error,omitempty"`
+
+	// Results contains the result for each Git object checked, in the order
+	// they were checked.
+	Results []*Result `json:"results"`
+
+	now func() time.Time
+}
+
+// Result records the check of a Git object's signatures.
+type Result struct {
+	Kind     string `json:"kind"`
+	ObjectID string `json:"object_id"`
+
+	// RefName and EntryID identify the RSL entry the object was checked for,
+	// if any.
+	RefName string `json:"ref_name,omitempty"`
+	EntryID string `json:"entry_id,omitempty"`
+
+	// Namespace is the protected namespace the object was checked against,
+	// such as "git:refs/heads/main" or "file:README.md", and Rule is the
+	// policy rule whose conditions the object's signatures met.
+	Namespace string `json:"namespace,omitempty"`
+	Rule      string `json:"rule,omitempty"`
+
+	// Signatures contains the Git signature and the signatures on the
+	// reference authorization found for the object.
+	Signatures []*Signature `json:"signatures"`
+
+	// SignedAt is the time the object claims to have been signed at, as
+	// recorded by its committer or tagger.
+	SignedAt *time.Time `json:"signed_at,omitempty"`
+
+	// VerifiedAt is the time the result was recorded.
+	VerifiedAt time.Time `json:"verified_at"`
+
+	Verified bool   `json:"verified"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Signature records a single signature on a Git object or its reference
+// authorization.
+type Signature struct {
+	Source string `json:"source"`
+
+	// KeyID is the ID of the key the signature was verified with. For Git
+	// signatures, it is empty if no trusted key verified the signature.
+	KeyID string `json:"key_id,omitempty"`
+
+	// Format is the signature's format, such as "gpg", "ssh", "sigstore", or
+	// "dsse".
+	Format string `json:"format,omitempty"`
+
+	// Verified indicates that the signature was issued by a key trusted by
+	// the policy.
+	Verified bool `json:"verified"`
+}
+
+// New returns a Report for the specified command, setting its start time.
+func New(command string) *Report {
+	r := &Report{Command: command, Results: []*Result{}, now: time.Now}
+	r.StartedAt = r.now().UTC()
+	return r
+}
+
+// ContextWithReport returns a copy of the context that carries the specified
+// report. Verification workflows record the signatures they check in the
+// report.
+func ContextWithReport(ctx context.Context, r *Report) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// FromContext returns the report carried by the context, if any.
+func FromContext(ctx context.Context) *Report {
+	r, ok := ctx.Value(contextKey{}).(*Report)
+	if !ok {
+		return nil
+	}
+
+	return r
+}
+
+// Add records the result, setting the time it was verified at.
+func (r *Report) Add(result *Result) {
+	if r == nil {
+		return
+	}
+
+	result.VerifiedAt = r.now().UTC()
+	if result.Signatures == nil {
+		result.Signatures = []*Signature{}
+	}
+	r.Results = append(r.Results, result)
+}
+
+// Finish records the outcome of verification and the time it finished at.
+// Verification is considered successful if err is nil and every recorded
+// result was verified.
+func (r *Report) Finish(err error) {
+	if r == nil {
+		return
+	}
+
+	r.FinishedAt = r.now().UTC()
+	r.Verified = err == nil
+	if err != nil {
+		r.Error = err.Error()
+	}
+	for _, result := range r.Results {
+		if !result.Verified {
+			r.Verified = false
+		}
+	}
+}
+
+// WriteJSON writes the JSON encoding of the report to w.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestReport() *Report {
+	current := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	r := New("verify-ref")
+	r.now = func() time.Time { return current }
+	r.StartedAt = current
+	return r
+}
+
+func TestReport(t *testing.T) {
+	t.Run("results recorded", func(t *testing.T) {
+		r := newTestReport()
+		r.Add(&Result{Kind: KindCommit, ObjectID: "abc", Verified: true})
+		r.Finish(nil)
+
+		assert.True(t, r.Verified)
+		assert.Empty(t, r.Error)
+		if !assert.Len(t, r.Results, 1) {
+			return
+		}
+		assert.Equal(t, r.StartedAt, r.Results[0].VerifiedAt)
+		assert.Equal(t, []*Signature{}, r.Results[0].Signatures)
+	})
+
+	t.Run("unverified result", func(t *testing.T) {
+		r := newTestReport()
+		r.Add(&Result{Kind: KindCommit, ObjectID: "abc", Error: "no signature"})
+		r.Finish(nil)
+
+		assert.False(t, r.Verified)
+	})
+
+	t.Run("verification error", func(t *testing.T) {
+		r := newTestReport()
+		r.Finish(errors.New("unable to find RSL entry"))
+
+		assert.False(t, r.Verified)
+		assert.Equal(t, "unable to find RSL entry", r.Error)
+	})
+
+	t.Run("nil report", func(t *testing.T) {
+		var r *Report
+		r.Add(&Result{})
+		r.Finish(nil)
+		assert.Nil(t, FromContext(context.Background()))
+	})
+
+	t.Run("report in context", func(t *testing.T) {
+		r := newTestReport()
+		assert.Equal(t, r, FromContext(ContextWithReport(context.Background(), r)))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		r := newTestReport()
+		r.Add(&Result{
+			Kind:      KindRSLEntry,
+			ObjectID:  "abc",
+			RefName:   "refs/heads/main",
+			EntryID:   "abc",
+			Namespace: "git:refs/heads/main",
+			Rule:      "protect-main",
+			Signatures: []*Signature{{
+				Source:   SourceGit,
+				KeyID:    "key",
+				Format:   "ssh",
+				Verified: true,
+			}},
+			Verified: true,
+		})
+		r.Finish(nil)
+
+		buf := &bytes.Buffer{}
+		assert.Nil(t, r.WriteJSON(buf))
+
+		decoded := &Report{}
+		if err := json.Unmarshal(buf.Bytes(), decoded); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, r.Command, decoded.Command)
+		assert.True(t, decoded.Verified)
+		assert.Equal(t, r.Results, decoded.Results)
+	})
+}
+
+func TestWriteSARIF(t *testing.T) {
+	t.Run("failed results", func(t *testing.T) {
+		r := newTestReport()
+		r.Add(&Result{Kind: KindCommit, ObjectID: "abc", RefName: "refs/heads/main", Verified: true})
+		r.Add(&Result{
+			Kind:      KindCommit,
+			ObjectID:  "def",
+			RefName:   "refs/heads/main",
+			Namespace: "file:README.md",
+			Error:     "Git signature was not issued by any of the rule's keys",
+		})
+		r.Finish(nil)
+
+		log := writeSARIF(t, r)
+		assert.Equal(t, sarifVersion, log.Version)
+		if !assert.Len(t, log.Runs, 1) {
+			return
+		}
+		run := log.Runs[0]
+		assert.Equal(t, "gittuf", run.Tool.Driver.Name)
+		assert.False(t, run.Invocations[0].ExecutionSuccessful)
+
+		if !assert.Len(t, run.Results, 1) {
+			return
+		}
+		result := run.Results[0]
+		assert.Equal(t, RuleUnverifiedSignature, result.RuleID)
+		assert.Equal(t, "error", result.Level)
+		assert.Equal(t, "commit def failed verification: Git signature was not issued by any of the rule's keys", result.Message.Text)
+		assert.Equal(t, "refs/heads/main@def", result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+		assert.Equal(t, "file:README.md", result.Properties.Namespace)
+	})
+
+	t.Run("verification error", func(t *testing.T) {
+		r := newTestReport()
+		r.Finish(errors.New("unable to find RSL entry"))
+
+		log := writeSARIF(t, r)
+		if !assert.Len(t, log.Runs[0].Results, 1) {
+			return
+		}
+		assert.Equal(t, RuleVerificationFailed, log.Runs[0].Results[0].RuleID)
+		assert.Equal(t, "unable to find RSL entry", log.Runs[0].Results[0].Message.Text)
+	})
+
+	t.Run("successful verification", func(t *testing.T) {
+		r := newTestReport()
+		r.Add(&Result{Kind: KindTag, ObjectID: "abc", Verified: true})
+		r.Finish(nil)
+
+		log := writeSARIF(t, r)
+		assert.True(t, log.Runs[0].Invocations[0].ExecutionSuccessful)
+		assert.Empty(t, log.Runs[0].Results)
+	})
+}
+
+func writeSARIF(t *testing.T, r *Report) *sarifLog {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	if err := r.WriteSARIF(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	log := &sarifLog{}
+	if err := json.Unmarshal(buf.Bytes(), log); err != nil {
+		t.Fatal(err)
+	}
+
+	return log
+}
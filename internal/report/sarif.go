@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// RuleUnverifiedSignature identifies SARIF results for Git objects whose
+	// signatures don't meet the policy's rules.
+	RuleUnverifiedSignature = "gittuf/unverified-signature"
+
+	// RuleVerificationFailed identifies SARIF results for failures that
+	// aren't attributed to the signatures of a single Git object, such as a
+	// ref that couldn't be resolved.
+	RuleVerificationFailed = "gittuf/verification-failed"
+)
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool          `json:"tool"`
+	Invocations []*sarifInvocation `json:"invocations"`
+	Results     []*sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Version        string       `json:"version"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifInvocation struct {
+	CommandLine         string    `json:"commandLine"`
+	ExecutionSuccessful bool      `json:"executionSuccessful"`
+	StartTimeUTC        time.Time `json:"startTimeUtc"`
+	EndTimeUTC          time.Time `json:"endTimeUtc"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Kind                string            `json:"kind"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []*sarifLocation  `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          *Result           `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []*sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes the report to w as a SARIF log, so that verification
+// failures can be uploaded to code scanning dashboards. Only results that
+// failed verification are included, each with the details recorded in the
+// report as its properties.
+func (r *Report) WriteSARIF(w io.Writer) error {
+	run := &sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gittuf",
+			InformationURI: "https://gittuf.dev",
+			Version:        version.GetVersion(),
+			Rules: []*sarifRule{
+				{ID: RuleUnverifiedSignature, ShortDescription: sarifMessage{Text: "Git object's signatures don't meet the rules of the gittuf policy"}},
+				{ID: RuleVerificationFailed, ShortDescription: sarifMessage{Text: "gittuf verification failed"}},
+			},
+		}},
+		Invocations: []*sarifInvocation{{
+			CommandLine:         "gittuf " + r.Command,
+			ExecutionSuccessful: r.Verified,
+			StartTimeUTC:        r.StartedAt,
+			EndTimeUTC:          r.FinishedAt,
+		}},
+		Results: []*sarifResult{},
+	}
+
+	for _, result := range r.Results {
+		if result.Verified {
+			continue
+		}
+
+		run.Results = append(run.Results, newSARIFResult(RuleUnverifiedSignature, result))
+	}
+
+	if len(run.Results) == 0 && r.Error != "" {
+		// Verification failed before any signatures were checked
+		run.Results = append(run.Results, newSARIFResult(RuleVerificationFailed, &Result{Error: r.Error, Signatures: []*Signature{}}))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(&sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []*sarifRun{run}})
+}
+
+func newSARIFResult(ruleID string, result *Result) *sarifResult {
+	message := result.Error
+	if result.ObjectID != "" {
+		message = fmt.Sprintf("%s %s failed verification: %s", result.Kind, result.ObjectID, result.Error)
+	}
+
+	name := result.ObjectID
+	if name == "" {
+		name = result.RefName
+	}
+	qualifiedName := name
+	if result.RefName != "" && result.RefName != name {
+		qualifiedName = result.RefName + "@" + name
+	}
+
+	fingerprint := fmt.Sprintf("%s:%s:%s", result.Kind, result.ObjectID, result.Namespace)
+
+	return &sarifResult{
+		RuleID:  ruleID,
+		Kind:    "fail",
+		Level:   "error",
+		Message: sarifMessage{Text: message},
+		Locations: []*sarifLocation{{
+			LogicalLocations: []*sarifLogicalLocation{{Name: name, FullyQualifiedName: qualifiedName, Kind: "object"}},
+		}},
+		PartialFingerprints: map[string]string{"gittufObject/v1": fingerprint},
+		Properties:          result,
+	}
+}
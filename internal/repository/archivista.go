@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/archivista"
+	"github.com/gittuf/gittuf/internal/attestations"
+)
+
+// MirrorAttestationsToArchivista uploads all the attestations in the
+// repository's current attestations state to the Archivista instance. The
+// gitoid assigned by Archivista to each attestation is returned, keyed by the
+// attestation's path in the attestations namespace.
+func (r *Repository) MirrorAttestationsToArchivista(ctx context.Context, archivistaURL string) (map[string]string, error) {
+	client := archivista.NewClient(archivistaURL)
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	envelopes, err := allAttestations.Envelopes(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	gitoids := make(map[string]string, len(envelopes))
+	for attestationPath, env := range envelopes {
+		logger.Debug(fmt.Sprintf("Uploading attestation '%s' to '%s'...", attestationPath, client.URL()))
+		gitoid, err := client.Store(ctx, env)
+		if err != nil {
+			return nil, fmt.Errorf("unable to upload attestation '%s': %w", attestationPath, err)
+		}
+
+		gitoids[attestationPath] = gitoid
+	}
+
+	return gitoids, nil
+}
@@ -6,107 +6,811 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
-	"os"
+	"strings"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/bitbucket"
+	"github.com/gittuf/gittuf/internal/ci"
+	"github.com/gittuf/gittuf/internal/codereview"
+	"github.com/gittuf/gittuf/internal/credentials"
 	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/gerrit"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/google/go-github/v61/github"
+	ita "github.com/in-toto/attestation/go/v1"
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
-var ErrNotSigningKey = errors.New("expected signing key")
+var (
+	ErrNotSigningKey    = errors.New("expected signing key")
+	ErrTagAlreadyExists = errors.New("tag already exists in the RSL")
+)
+
+// AddReferenceAuthorization adds a reference authorization attestation to the
+// repository for the specified target ref. The from ID is identified using the
+// last RSL entry for the target ref. The to ID is that of the expected Git tree
+// created by merging the feature ref into the target ref. The commit used to
+// calculate the merge tree ID is identified using the RSL for the feature ref.
+// Currently, this is limited to developer mode.
+func (r *Repository) AddReferenceAuthorization(ctx context.Context, signer sslibdsse.SignerVerifier, targetRef, featureRef string, signCommit bool) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	targetRef, fromID, toID, err := r.identifyReferenceAuthorizationChange(targetRef, featureRef)
+	if err != nil {
+		return err
+	}
+
+	return r.signReferenceAuthorization(ctx, signer, targetRef, fromID, toID, 0, signCommit)
+}
+
+// AddReferenceAuthorizationForTag adds a reference authorization attestation
+// to the repository for creating the tag `tagRef` pointing to the object
+// identified by `targetRevision`. As the tag may not exist yet, `tagRef` may be
+// specified either as the tag's name or its absolute path. Multiple principals
+// can invoke this independently to collect the approvals required by policy
+// before the tag is created. Currently, this is limited to developer mode.
+func (r *Repository) AddReferenceAuthorizationForTag(ctx context.Context, signer sslibdsse.SignerVerifier, tagRef, targetRevision string, signCommit bool) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	tagRef, fromID, toID, err := r.identifyTagAuthorizationChange(tagRef, targetRevision)
+	if err != nil {
+		return err
+	}
+
+	return r.signReferenceAuthorization(ctx, signer, tagRef, fromID, toID, 0, signCommit)
+}
+
+// RequestApproval records a reference authorization for the proposed change
+// without any signatures. The change is identified in the same way as
+// AddReferenceAuthorization, or AddReferenceAuthorizationForTag if the target
+// ref is a tag. Approvers can subsequently add their signatures using Approve,
+// allowing a threshold to be met by principals who never push to the
+// repository. If `expiresIn` is non-zero, the approvals can only be used to
+// authorize the change within that duration. The from and to IDs that
+// identify the authorization are returned.
+func (r *Repository) RequestApproval(ctx context.Context, targetRef, featureRef string, expiresIn time.Duration, signCommit bool) (string, string, error) {
+	targetRef, fromID, toID, err := r.identifyChangeForApproval(targetRef, featureRef)
+	if err != nil {
+		return "", "", err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	if _, err := allAttestations.GetReferenceAuthorizationFor(r.r, targetRef, fromID, toID); err == nil {
+		logger.Debug("Found existing reference authorization...")
+		return fromID, toID, nil
+	} else if !errors.Is(err, attestations.ErrAuthorizationNotFound) {
+		return "", "", err
+	}
+
+	logger.Debug("Creating new reference authorization...")
+	env, err := createReferenceAuthorizationEnvelope(targetRef, fromID, toID, expiresIn)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := allAttestations.SetReferenceAuthorization(r.r, env, targetRef, fromID, toID); err != nil {
+		return "", "", err
+	}
+
+	commitMessage := fmt.Sprintf("Request approval for '%s' from '%s' to '%s'", targetRef, fromID, toID)
+
+	logger.Debug("Committing attestations...")
+	if err := allAttestations.Commit(r.r, commitMessage, signCommit); err != nil {
+		return "", "", err
+	}
+
+	return fromID, toID, nil
+}
+
+// Approve adds the signer's approval for the proposed change to the target ref
+// to the corresponding reference authorization. If the target ref is a branch,
+// the feature ref is the ref whose changes are merged into the target ref. If
+// the target ref is a tag, the feature ref is the revision the tag is expected
+// to point to. The reference authorization is created if it doesn't exist yet,
+// in which case `expiresIn`, if non-zero, limits how long it can be used to
+// authorize the change.
+func (r *Repository) Approve(ctx context.Context, signer sslibdsse.SignerVerifier, targetRef, featureRef string, expiresIn time.Duration, signCommit bool) error {
+	targetRef, fromID, toID, err := r.identifyChangeForApproval(targetRef, featureRef)
+	if err != nil {
+		return err
+	}
+
+	return r.signReferenceAuthorization(ctx, signer, targetRef, fromID, toID, expiresIn, signCommit)
+}
+
+// RemoveReferenceAuthorization removes a previously issued authorization for
+// the specified parameters. The issuer of the authorization is identified using
+// their key. Currently, this is limited to developer mode.
+func (r *Repository) RemoveReferenceAuthorization(ctx context.Context, signer sslibdsse.SignerVerifier, targetRef, fromID, toID string, signCommit bool) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	// Ensure only the key that created a reference authorization can remove it
+	logger.Debug("Evaluating if key can sign...")
+	_, err := signer.Sign(ctx, nil)
+	if err != nil {
+		return errors.Join(ErrNotSigningKey, err)
+	}
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	targetRef, err = gitinterface.AbsoluteReference(r.r, targetRef)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading reference authorization...")
+	env, err := allAttestations.GetReferenceAuthorizationFor(r.r, targetRef, fromID, toID)
+	if err != nil {
+		if errors.Is(err, attestations.ErrAuthorizationNotFound) {
+			// No reference authorization at all
+			return nil
+		}
+		return err
+	}
+
+	logger.Debug("Removing signature...")
+	newSignatures := []sslibdsse.Signature{}
+	for _, signature := range env.Signatures {
+		// This handles cases where the envelope may unintentionally have
+		// multiple signatures from the same key
+		if signature.KeyID != keyID {
+			newSignatures = append(newSignatures, signature)
+		}
+	}
+
+	if len(newSignatures) == 0 {
+		// No signatures, we can remove the ReferenceAuthorization altogether
+		if err := allAttestations.RemoveReferenceAuthorization(targetRef, fromID, toID); err != nil {
+			return err
+		}
+	} else {
+		// We still have other signatures, so set the ReferenceAuthorization
+		// envelope
+		env.Signatures = newSignatures
+		if err := allAttestations.SetReferenceAuthorization(r.r, env, targetRef, fromID, toID); err != nil {
+			return err
+		}
+	}
+
+	commitMessage := fmt.Sprintf("Remove reference authorization for '%s' from '%s' to '%s' by '%s'", targetRef, fromID, toID, keyID)
+
+	logger.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// CounterSignAttestation adds the signer's signature to the existing
+// attestation at the specified path in the attestations namespace. This allows
+// multiple principals to sign the same statement rather than each creating
+// their own copy of it. If the attestation already has a signature from the
+// signer, it is replaced so that each signer is counted only once.
+func (r *Repository) CounterSignAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, attestationPath string, signCommit bool) error {
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Loading attestation '%s'...", attestationPath))
+	env, err := allAttestations.GetEnvelope(r.r, attestationPath)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetEnvelope(r.r, attestationPath, env); err != nil {
+		return err
+	}
+
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add signature from '%s' to attestation '%s'", keyID, attestationPath)
+
+	logger.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// AddPushEventAttestation records a push event attestation for the latest RSL
+// entry of the specified ref. The source IP address, client hostname, and CI
+// run URL are optional and are omitted from the attestation when empty. Only
+// the network range of the source IP address is recorded.
+func (r *Repository) AddPushEventAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, refName, sourceIP, clientHostname, ciRunURL string, signCommit bool) error {
+	refName, err := gitinterface.AbsoluteReference(r.r, refName)
+	if err != nil {
+		return err
+	}
+
+	pushEvent := &attestations.PushEvent{
+		ClientHostname: clientHostname,
+		CIRunURL:       ciRunURL,
+	}
+
+	if sourceIP != "" {
+		pushEvent.SourceIPRange, pushEvent.SourceIPClass, err = attestations.SourceIPRangeAndClass(sourceIP)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.addPushEventAttestation(ctx, signer, refName, pushEvent, signCommit)
+}
+
+// addPushEventAttestation records the push event, whose context fields must be
+// set, as an attestation for the latest RSL entry of the absolute ref.
+func (r *Repository) addPushEventAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, refName string, pushEvent *attestations.PushEvent, signCommit bool) error {
+	logger.Debug("Identifying RSL entry for push...")
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, refName)
+	if err != nil {
+		return err
+	}
+
+	pushEvent.RSLEntryID = entry.ID.String()
+	pushEvent.RefName = refName
+	pushEvent.TargetID = entry.TargetID.String()
+
+	logger.Debug("Creating push event attestation...")
+	statement, err := attestations.NewPushEventAttestation(pushEvent)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	// If the push has already been attested to with the same details, add
+	// our signature to the existing attestation rather than replacing it
+	existingEnv, err := allAttestations.GetPushEventAttestationFor(r.r, pushEvent.RSLEntryID)
+	if err == nil {
+		if dsse.EqualPayloads(existingEnv, env) {
+			logger.Debug("Found existing push event attestation...")
+			env = existingEnv
+		}
+	} else if !errors.Is(err, attestations.ErrPushEventNotFound) {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing push event attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetPushEventAttestation(r.r, env, pushEvent.RSLEntryID); err != nil {
+		return err
+	}
+
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add push event attestation for '%s' at RSL entry '%s'", refName, pushEvent.RSLEntryID)
+
+	logger.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// AddCIRunAttestation records an attestation describing the CI run for the
+// commit it was triggered for. The signing certificate is optional and is
+// recorded when the signer's identity is certified by Fulcio.
+func (r *Repository) AddCIRunAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, ciEnv *ci.Environment, signingCertificate string, signCommit bool) error {
+	logger.Debug(fmt.Sprintf("Creating attestation for %s run '%s'...", ciEnv.Provider, ciEnv.RunID))
+	statement, err := attestations.NewCIRunAttestation(ciEnv, signingCertificate)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing CI run attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetCIRunAttestation(r.r, env, ciEnv.CommitID, ciEnv.Provider, ciEnv.RunID); err != nil {
+		return err
+	}
+
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add attestation for %s run '%s' of '%s'", ciEnv.Provider, ciEnv.RunID, ciEnv.CommitID)
+
+	logger.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// AddGerritChangeAttestation records the Gerrit change identified by changeID,
+// and the approvals it received, in an attestation for the commit the change
+// was submitted as. The change is fetched using the Gerrit client, so that the
+// attestation reflects the approvals recorded by Gerrit at submit time.
+func (r *Repository) AddGerritChangeAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, client *gerrit.Client, changeID, commitID string, signCommit bool) error {
+	if _, err := gitinterface.GetCommit(r.r, plumbing.NewHash(commitID)); err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Inspecting Gerrit change '%s'...", changeID))
+	change, err := client.GetChange(ctx, changeID)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Creating Gerrit change attestation...")
+	statement, err := attestations.NewGerritChangeAttestation(change, commitID)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing Gerrit change attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetGerritChangeAttestation(r.r, env, change.RefName(), commitID); err != nil {
+		return err
+	}
+
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add Gerrit change attestation for '%s' at '%s'\n\nSource: %s\n", change.RefName(), commitID, change.URL)
+
+	logger.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// AddBitbucketPullRequestAttestation records the Bitbucket Data Center pull
+// request and the approvals it received in an attestation for the commit it was
+// merged as. If commitID is empty, the pull request's merge commit is used. The
+// Bitbucket users who authored and approved the pull request are mapped to
+// gittuf principals using the identity map.
+func (r *Repository) AddBitbucketPullRequestAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, client *bitbucket.Client, identities bitbucket.IdentityMap, projectKey, repositorySlug string, pullRequestID int, commitID string, signCommit bool) error {
+	logger.Debug(fmt.Sprintf("Inspecting Bitbucket pull request '%s/%s#%d'...", projectKey, repositorySlug, pullRequestID))
+	pullRequest, err := client.GetPullRequest(ctx, projectKey, repositorySlug, pullRequestID)
+	if err != nil {
+		return err
+	}
+
+	if commitID == "" {
+		if pullRequest.MergeCommit == "" {
+			return fmt.Errorf("pull request '%s/%s#%d' has no merge commit, specify the commit to record the pull request for", projectKey, repositorySlug, pullRequestID)
+		}
+		commitID = pullRequest.MergeCommit
+	}
+
+	if _, err := gitinterface.GetCommit(r.r, plumbing.NewHash(commitID)); err != nil {
+		return err
+	}
+
+	pullRequest.MapIdentities(identities)
+
+	logger.Debug("Creating Bitbucket pull request attestation...")
+	statement, err := attestations.NewBitbucketPullRequestAttestation(pullRequest, commitID)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing Bitbucket pull request attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetBitbucketPullRequestAttestation(r.r, env, pullRequest.RefName(), commitID); err != nil {
+		return err
+	}
+
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add Bitbucket pull request attestation for '%s' at '%s'\n\nSource: %s\n", pullRequest.RefName(), commitID, pullRequest.URL)
+
+	logger.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// AddReviewApprovalAttestation records the approvals a change received on a
+// code review platform in an attestation for the change's target branch and
+// target commit. The users who authored and approved the change are mapped to
+// gittuf principals using the identity map, so that policy can require
+// approvals from the principals trusted in a rule.
+func (r *Repository) AddReviewApprovalAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, review *codereview.Review, identities codereview.IdentityMap, signCommit bool) error {
+	if review.TargetID == "" {
+		return fmt.Errorf("review '%s' has no target commit, specify the commit to record the approvals for", review.URL)
+	}
+
+	if _, err := gitinterface.GetCommit(r.r, plumbing.NewHash(review.TargetID)); err != nil {
+		return err
+	}
+
+	review.MapIdentities(identities)
+
+	logger.Debug("Creating review approval attestation...")
+	statement, err := attestations.NewReviewApprovalAttestation(review)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing review approval attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetReviewApprovalAttestation(r.r, env, review.RefName, review.TargetID); err != nil {
+		return err
+	}
+
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add review approval attestation for '%s' at '%s'\n\nSource: %s\n", review.RefName, review.TargetID, review.URL)
+
+	logger.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// AddHookExecutionAttestation records the result of executing the specified
+// hook for the current state of the ref. The hook's digest identifies the
+// version of the hook that was executed. Policy rules can require a passing
+// result for a hook before a change to the ref is authorized.
+func (r *Repository) AddHookExecutionAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, refName, hookName, hookDigest string, exitCode int, signCommit bool) error {
+	refName, err := gitinterface.AbsoluteReference(r.r, refName)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Loading current state of '%s'...", refName))
+	ref, err := r.r.Reference(plumbing.ReferenceName(refName), true)
+	if err != nil {
+		return err
+	}
+
+	hookExecution := &attestations.HookExecution{
+		HookName:   hookName,
+		HookDigest: hookDigest,
+		RefName:    refName,
+		TargetID:   ref.Hash().String(),
+		ExitCode:   exitCode,
+		Result:     attestations.HookResultPass,
+	}
+	if exitCode != 0 {
+		hookExecution.Result = attestations.HookResultFail
+	}
+
+	logger.Debug("Creating hook execution attestation...")
+	statement, err := attestations.NewHookExecutionAttestation(hookExecution)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing hook execution attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetHookExecutionAttestation(r.r, env, refName, hookExecution.TargetID, hookName); err != nil {
+		return err
+	}
+
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add execution of hook '%s' for '%s' at '%s'", hookName, refName, hookExecution.TargetID)
+
+	logger.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// AddRebuildAttestation records that the signer independently built the
+// artifact from the specified revision and obtained the artifact digest. If
+// other rebuilders have already attested to the same digest, the signer's
+// signature is added to the existing attestation so that agreeing rebuilders
+// can be counted towards a threshold.
+func (r *Repository) AddRebuildAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, revision, artifactName, artifactDigest string, signCommit bool) error {
+	logger.Debug(fmt.Sprintf("Identifying commit for '%s'...", revision))
+	commitID, err := r.r.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Creating rebuild attestation...")
+	statement, err := attestations.NewRebuildAttestation(&attestations.Rebuild{
+		CommitID:       commitID.String(),
+		ArtifactName:   artifactName,
+		ArtifactSHA256: artifactDigest,
+	})
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	existingEnv, err := allAttestations.GetRebuildAttestationFor(r.r, commitID.String(), artifactName, artifactDigest)
+	if err == nil {
+		logger.Debug("Found existing rebuild attestation...")
+		env = existingEnv
+	} else if !errors.Is(err, attestations.ErrRebuildNotFound) {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing rebuild attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetRebuildAttestation(r.r, env, commitID.String(), artifactName, artifactDigest); err != nil {
+		return err
+	}
+
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add rebuild of '%s' from '%s' by '%s'", artifactName, commitID.String(), keyID)
+
+	logger.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
 
-var githubClient *github.Client
+// AddLFSObjectsAttestation records that the Git LFS objects referenced by the
+// specified revision exist and match their pointers, typically for a release.
+// The objects are checked in the local Git LFS storage before the attestation
+// is signed. If the objects were already attested to, the signer's signature
+// is added to the existing attestation.
+func (r *Repository) AddLFSObjectsAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, revision string, signCommit bool) error {
+	commitID, pointers, err := r.verifyLocalLFSObjects(revision)
+	if err != nil {
+		return err
+	}
 
-// AddReferenceAuthorization adds a reference authorization attestation to the
-// repository for the specified target ref. The from ID is identified using the
-// last RSL entry for the target ref. The to ID is that of the expected Git tree
-// created by merging the feature ref into the target ref. The commit used to
-// calculate the merge tree ID is identified using the RSL for the feature ref.
-// Currently, this is limited to developer mode.
-func (r *Repository) AddReferenceAuthorization(ctx context.Context, signer sslibdsse.SignerVerifier, targetRef, featureRef string, signCommit bool) error {
-	if !dev.InDevMode() {
-		return dev.ErrNotInDevMode
+	logger.Debug("Creating Git LFS objects attestation...")
+	statement, err := attestations.NewLFSObjectsAttestation(&attestations.LFSObjects{
+		CommitID: commitID.String(),
+		Objects:  pointers,
+	})
+	if err != nil {
+		return err
 	}
 
-	var err error
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
 
-	targetRef, err = gitinterface.AbsoluteReference(r.r, targetRef)
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
 	if err != nil {
 		return err
 	}
 
-	featureRef, err = gitinterface.AbsoluteReference(r.r, featureRef)
+	existingEnv, err := allAttestations.GetLFSObjectsAttestationFor(r.r, commitID.String())
+	if err == nil {
+		logger.Debug("Found existing Git LFS objects attestation...")
+		env = existingEnv
+	} else if !errors.Is(err, attestations.ErrLFSObjectsNotFound) {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
 	if err != nil {
 		return err
 	}
 
-	var (
-		fromID          string
-		featureCommitID string
-		toID            string
-	)
+	logger.Debug(fmt.Sprintf("Signing Git LFS objects attestation using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
 
-	slog.Debug("Identifying current status of target Git reference...")
-	latestTargetEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, targetRef)
-	if err == nil {
-		fromID = latestTargetEntry.TargetID.String()
-	} else {
-		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
-			return err
-		}
-		fromID = plumbing.ZeroHash.String()
+	if err := allAttestations.SetLFSObjectsAttestation(r.r, env, commitID.String()); err != nil {
+		return err
 	}
 
-	slog.Debug("Identifying current status of feature Git reference...")
-	latestFeatureEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, featureRef)
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add Git LFS objects of '%s' by '%s'", commitID.String(), keyID)
+
+	logger.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// AddStatementAttestation records an in-toto statement with the predicate of
+// the specified type, such as a code review approval or test results, for the
+// current state of the ref. If the same statement was already recorded, the
+// signer's signature is added to it. Policy rules can require statements of
+// specific predicate types before a change to the ref is authorized.
+func (r *Repository) AddStatementAttestation(ctx context.Context, signer sslibdsse.SignerVerifier, refName, predicateType string, predicate map[string]any, signCommit bool) error {
+	refName, err := gitinterface.AbsoluteReference(r.r, refName)
 	if err != nil {
-		// We don't have an RSL entry for the feature ref to use to approve the
-		// merge
 		return err
 	}
-	featureCommitID = latestFeatureEntry.TargetID.String()
 
-	slog.Debug("Computing expected merge tree...")
-	mergeTreeID, err := gitinterface.GetMergeTree(r.r, fromID, featureCommitID)
+	logger.Debug(fmt.Sprintf("Loading current state of '%s'...", refName))
+	ref, err := r.r.Reference(plumbing.ReferenceName(refName), true)
 	if err != nil {
 		return err
 	}
-	toID = mergeTreeID
+	targetID := ref.Hash().String()
 
-	slog.Debug("Loading current set of attestations...")
-	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	logger.Debug("Creating statement...")
+	statement, err := attestations.NewStatementAttestation(refName, targetID, predicateType, predicate)
 	if err != nil {
 		return err
 	}
 
-	// Does a reference authorization already exist for the parameters?
-	hasAuthorization := false
-	env, err := allAttestations.GetReferenceAuthorizationFor(r.r, targetRef, fromID, toID)
-	if err == nil {
-		slog.Debug("Found existing reference authorization...")
-		hasAuthorization = true
-	} else if !errors.Is(err, attestations.ErrAuthorizationNotFound) {
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
 		return err
 	}
 
-	if !hasAuthorization {
-		// Create a new reference authorization and embed in env
-		slog.Debug("Creating new reference authorization...")
-		statement, err := attestations.NewReferenceAuthorization(targetRef, fromID, toID)
-		if err != nil {
-			return err
-		}
+	payloadDigest, err := attestations.GetStatementPayloadDigest(env)
+	if err != nil {
+		return err
+	}
 
-		env, err = dsse.CreateEnvelope(statement)
-		if err != nil {
-			return err
-		}
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	existingEnv, err := allAttestations.GetStatementAttestationFor(r.r, refName, targetID, payloadDigest)
+	if err == nil {
+		logger.Debug("Found existing statement...")
+		env = existingEnv
+	} else if !errors.Is(err, attestations.ErrStatementNotFound) {
+		return err
 	}
 
 	keyID, err := signer.KeyID()
@@ -114,104 +818,293 @@ func (r *Repository) AddReferenceAuthorization(ctx context.Context, signer sslib
 		return err
 	}
 
-	slog.Debug(fmt.Sprintf("Signing reference authorization using '%s'...", keyID))
+	logger.Debug(fmt.Sprintf("Signing statement using '%s'...", keyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
 		return err
 	}
 
-	if err := allAttestations.SetReferenceAuthorization(r.r, env, targetRef, fromID, toID); err != nil {
+	if err := allAttestations.SetStatementAttestation(r.r, env, refName, targetID); err != nil {
 		return err
 	}
 
-	commitMessage := fmt.Sprintf("Add reference authorization for '%s' from '%s' to '%s'", targetRef, fromID, toID)
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add statement of type '%s' for '%s' at '%s' by '%s'", predicateType, refName, targetID, keyID)
 
-	slog.Debug("Committing attestations...")
+	logger.Debug("Committing attestations...")
 	return allAttestations.Commit(r.r, commitMessage, signCommit)
 }
 
-// RemoveReferenceAuthorization removes a previously issued authorization for
-// the specified parameters. The issuer of the authorization is identified using
-// their key. Currently, this is limited to developer mode.
-func (r *Repository) RemoveReferenceAuthorization(ctx context.Context, signer sslibdsse.SignerVerifier, targetRef, fromID, toID string, signCommit bool) error {
-	if !dev.InDevMode() {
-		return dev.ErrNotInDevMode
+// VerifyStatements checks that the statements recorded for the current state
+// of the ref meet the statements required by the latest policy for changes to
+// the ref. This allows a change to be checked before it is pushed and recorded
+// in the RSL.
+func (r *Repository) VerifyStatements(ctx context.Context, refName string) error {
+	refName, err := gitinterface.AbsoluteReference(r.r, refName)
+	if err != nil {
+		return err
 	}
 
-	// Ensure only the key that created a reference authorization can remove it
-	slog.Debug("Evaluating if key can sign...")
-	_, err := signer.Sign(ctx, nil)
+	logger.Debug(fmt.Sprintf("Loading current state of '%s'...", refName))
+	ref, err := r.r.Reference(plumbing.ReferenceName(refName), true)
 	if err != nil {
-		return errors.Join(ErrNotSigningKey, err)
+		return err
+	}
+
+	logger.Debug("Verifying statements against policy...")
+	return policy.VerifyRequiredPredicatesForRef(ctx, r.r, refName, ref.Hash().String())
+}
+
+// FindPrunableAttestations returns the attestations that would be removed by
+// PruneAttestations, without modifying the attestations namespace.
+func (r *Repository) FindPrunableAttestations() ([]*attestations.PrunedAttestation, error) {
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Identifying superseded and unreachable attestations...")
+	return allAttestations.Prune(r.r)
+}
+
+// PruneAttestations removes superseded attestations and attestations about
+// unreachable objects from the current state of the attestations namespace.
+// A tombstone listing the pruned attestations is signed and recorded in the
+// namespace. The pruned attestations are returned.
+func (r *Repository) PruneAttestations(ctx context.Context, signer sslibdsse.SignerVerifier, signCommit bool) ([]*attestations.PrunedAttestation, error) {
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attestationsRef, err := r.r.Reference(plumbing.ReferenceName(attestations.Ref), true)
+	if err != nil {
+		return nil, err
+	}
+	prunedFrom := attestationsRef.Hash().String()
+
+	logger.Debug("Identifying superseded and unreachable attestations...")
+	pruned, err := allAttestations.Prune(r.r)
+	if err != nil {
+		return nil, err
+	}
+	if len(pruned) == 0 {
+		logger.Debug("No attestations to prune")
+		return pruned, nil
 	}
+
+	logger.Debug("Creating tombstone for pruned attestations...")
+	statement, err := attestations.NewTombstone(prunedFrom, pruned)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return nil, err
+	}
+
 	keyID, err := signer.KeyID()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	targetRef, err = gitinterface.AbsoluteReference(r.r, targetRef)
+	logger.Debug(fmt.Sprintf("Signing tombstone using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	slog.Debug("Loading current set of attestations...")
-	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err := allAttestations.SetTombstone(r.r, env, prunedFrom); err != nil {
+		return nil, err
+	}
+
+	commitMessage := fmt.Sprintf("Prune %d attestations from '%s'", len(pruned), prunedFrom)
+
+	logger.Debug("Committing attestations...")
+	if err := allAttestations.Commit(r.r, commitMessage, signCommit); err != nil {
+		return nil, err
+	}
+
+	return pruned, nil
+}
+
+// identifyChangeForApproval identifies the change proposed to the target ref,
+// using the feature ref as the source of changes for branches and as the
+// expected target for tags.
+func (r *Repository) identifyChangeForApproval(targetRef, featureRef string) (string, string, string, error) {
+	if strings.HasPrefix(targetRef, gitinterface.TagRefPrefix) {
+		return r.identifyTagAuthorizationChange(targetRef, featureRef)
+	}
+
+	return r.identifyReferenceAuthorizationChange(targetRef, featureRef)
+}
+
+// identifyReferenceAuthorizationChange returns the absolute target ref as well
+// as the from and to IDs for merging the feature ref into the target ref.
+func (r *Repository) identifyReferenceAuthorizationChange(targetRef, featureRef string) (string, string, string, error) {
+	var err error
+
+	targetRef, err = gitinterface.AbsoluteReference(r.r, targetRef)
 	if err != nil {
-		return err
+		return "", "", "", err
 	}
 
-	slog.Debug("Loading reference authorization...")
-	env, err := allAttestations.GetReferenceAuthorizationFor(r.r, targetRef, fromID, toID)
+	featureRef, err = gitinterface.AbsoluteReference(r.r, featureRef)
 	if err != nil {
-		if errors.Is(err, attestations.ErrAuthorizationNotFound) {
-			// No reference authorization at all
-			return nil
-		}
-		return err
+		return "", "", "", err
 	}
 
-	slog.Debug("Removing signature...")
-	newSignatures := []sslibdsse.Signature{}
-	for _, signature := range env.Signatures {
-		// This handles cases where the envelope may unintentionally have
-		// multiple signatures from the same key
-		if signature.KeyID != keyID {
-			newSignatures = append(newSignatures, signature)
+	var fromID string
+
+	logger.Debug("Identifying current status of target Git reference...")
+	latestTargetEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, targetRef)
+	if err == nil {
+		fromID = latestTargetEntry.TargetID.String()
+	} else {
+		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return "", "", "", err
 		}
+		fromID = plumbing.ZeroHash.String()
 	}
 
-	if len(newSignatures) == 0 {
-		// No signatures, we can remove the ReferenceAuthorization altogether
-		if err := allAttestations.RemoveReferenceAuthorization(targetRef, fromID, toID); err != nil {
+	logger.Debug("Identifying current status of feature Git reference...")
+	latestFeatureEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, featureRef)
+	if err != nil {
+		// We don't have an RSL entry for the feature ref to use to approve the
+		// merge
+		return "", "", "", err
+	}
+	featureCommitID := latestFeatureEntry.TargetID.String()
+
+	logger.Debug("Computing expected merge tree...")
+	mergeTreeID, err := gitinterface.GetMergeTree(r.r, fromID, featureCommitID)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return targetRef, fromID, mergeTreeID, nil
+}
+
+// identifyTagAuthorizationChange returns the absolute tag ref as well as the
+// from and to IDs for creating the tag pointing to the target revision.
+func (r *Repository) identifyTagAuthorizationChange(tagRef, targetRevision string) (string, string, string, error) {
+	if !strings.HasPrefix(tagRef, gitinterface.TagRefPrefix) {
+		tagRef = plumbing.NewTagReferenceName(tagRef).String()
+	}
+
+	logger.Debug("Checking if tag already exists...")
+	if _, _, err := rsl.GetLatestReferenceEntryForRef(r.r, tagRef); err == nil {
+		return "", "", "", ErrTagAlreadyExists
+	} else if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return "", "", "", err
+	}
+
+	logger.Debug("Identifying target of tag...")
+	targetID, err := r.r.ResolveRevision(plumbing.Revision(targetRevision))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return tagRef, plumbing.ZeroHash.String(), targetID.String(), nil
+}
+
+// signReferenceAuthorization adds the signer's signature to the reference
+// authorization for the specified parameters, creating it if necessary.
+func (r *Repository) signReferenceAuthorization(ctx context.Context, signer sslibdsse.SignerVerifier, targetRef, fromID, toID string, expiresIn time.Duration, signCommit bool) error {
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Does a reference authorization already exist for the parameters?
+	env, err := allAttestations.GetReferenceAuthorizationFor(r.r, targetRef, fromID, toID)
+	if err == nil {
+		logger.Debug("Found existing reference authorization...")
+	} else {
+		if !errors.Is(err, attestations.ErrAuthorizationNotFound) {
 			return err
 		}
-	} else {
-		// We still have other signatures, so set the ReferenceAuthorization
-		// envelope
-		env.Signatures = newSignatures
-		if err := allAttestations.SetReferenceAuthorization(r.r, env, targetRef, fromID, toID); err != nil {
+
+		// Create a new reference authorization and embed in env
+		logger.Debug("Creating new reference authorization...")
+		env, err = createReferenceAuthorizationEnvelope(targetRef, fromID, toID, expiresIn)
+		if err != nil {
 			return err
 		}
 	}
 
-	commitMessage := fmt.Sprintf("Remove reference authorization for '%s' from '%s' to '%s' by '%s'", targetRef, fromID, toID, keyID)
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing reference authorization using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetReferenceAuthorization(r.r, env, targetRef, fromID, toID); err != nil {
+		return err
+	}
+
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add reference authorization for '%s' from '%s' to '%s'", targetRef, fromID, toID)
 
-	slog.Debug("Committing attestations...")
+	logger.Debug("Committing attestations...")
 	return allAttestations.Commit(r.r, commitMessage, signCommit)
 }
 
+func createReferenceAuthorizationEnvelope(targetRef, fromID, toID string, expiresIn time.Duration) (*sslibdsse.Envelope, error) {
+	var (
+		statement *ita.Statement
+		err       error
+	)
+
+	if strings.HasPrefix(targetRef, gitinterface.TagRefPrefix) {
+		statement, err = attestations.NewReferenceAuthorizationForTag(targetRef, toID)
+	} else {
+		statement, err = attestations.NewReferenceAuthorization(targetRef, fromID, toID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresIn != 0 {
+		now := time.Now()
+		if err := attestations.SetValidityPeriod(statement, now, now.Add(expiresIn)); err != nil {
+			return nil, err
+		}
+	}
+
+	return dsse.CreateEnvelope(statement)
+}
+
 // AddGitHubPullRequestAttestationForCommit identifies the pull request for a
 // specified commit ID and triggers AddGitHubPullRequestAttestationForNumber for
-// that pull request. Currently, the authentication token for the GitHub API is
-// read from the GITHUB_TOKEN environment variable.
-func (r *Repository) AddGitHubPullRequestAttestationForCommit(ctx context.Context, signer sslibdsse.SignerVerifier, owner, repository, commitID, baseBranch string, signCommit bool) error {
+// that pull request. The GitHub API is accessed using the tokens obtained from
+// tokenSource.
+func (r *Repository) AddGitHubPullRequestAttestationForCommit(ctx context.Context, signer sslibdsse.SignerVerifier, tokenSource credentials.Source, owner, repository, commitID, baseBranch string, signCommit bool) error {
 	if !dev.InDevMode() {
 		return dev.ErrNotInDevMode
 	}
 
-	client := getGitHubClient()
+	client, err := getGitHubClient(ctx, tokenSource)
+	if err != nil {
+		return err
+	}
 
-	slog.Debug("Identifying GitHub pull requests for commit...")
+	logger.Debug("Identifying GitHub pull requests for commit...")
 	pullRequests, _, err := client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repository, commitID, nil)
 	if err != nil {
 		return err
@@ -223,7 +1116,7 @@ func (r *Repository) AddGitHubPullRequestAttestationForCommit(ctx context.Contex
 	}
 
 	for _, pullRequest := range pullRequests {
-		slog.Debug(fmt.Sprintf("Inspecting GitHub pull request %d...", *pullRequest.Number))
+		logger.Debug(fmt.Sprintf("Inspecting GitHub pull request %d...", *pullRequest.Number))
 		pullRequestBranch := plumbing.NewBranchReferenceName(*pullRequest.Base.Ref).String()
 
 		// pullRequest.Merged is not set on this endpoint for some reason
@@ -237,16 +1130,19 @@ func (r *Repository) AddGitHubPullRequestAttestationForCommit(ctx context.Contex
 
 // AddGitHubPullRequestAttestationForNumber wraps the API response for the
 // specified pull request in an in-toto attestation. `pullRequestID` must be the
-// number of the pull request. Currently, the authentication token for the
-// GitHub API is read from the GITHUB_TOKEN environment variable.
-func (r *Repository) AddGitHubPullRequestAttestationForNumber(ctx context.Context, signer sslibdsse.SignerVerifier, owner, repository string, pullRequestNumber int, signCommit bool) error {
+// number of the pull request. The GitHub API is accessed using the tokens
+// obtained from tokenSource.
+func (r *Repository) AddGitHubPullRequestAttestationForNumber(ctx context.Context, signer sslibdsse.SignerVerifier, tokenSource credentials.Source, owner, repository string, pullRequestNumber int, signCommit bool) error {
 	if !dev.InDevMode() {
 		return dev.ErrNotInDevMode
 	}
 
-	client := getGitHubClient()
+	client, err := getGitHubClient(ctx, tokenSource)
+	if err != nil {
+		return err
+	}
 
-	slog.Debug(fmt.Sprintf("Inspecting GitHub pull request %d...", pullRequestNumber))
+	logger.Debug(fmt.Sprintf("Inspecting GitHub pull request %d...", pullRequestNumber))
 	pullRequest, _, err := client.PullRequests.Get(ctx, owner, repository, pullRequestNumber)
 	if err != nil {
 		return err
@@ -271,7 +1167,7 @@ func (r *Repository) addGitHubPullRequestAttestation(ctx context.Context, signer
 		targetCommitID = *pullRequest.MergeCommitSHA
 	}
 
-	slog.Debug("Creating GitHub pull request attestation...")
+	logger.Debug("Creating GitHub pull request attestation...")
 	statement, err := attestations.NewGitHubPullRequestAttestation(owner, repository, *pullRequest.Number, targetCommitID, pullRequest)
 	if err != nil {
 		return err
@@ -287,13 +1183,13 @@ func (r *Repository) addGitHubPullRequestAttestation(ctx context.Context, signer
 		return err
 	}
 
-	slog.Debug(fmt.Sprintf("Signing GitHub pull request attestation using '%s'...", keyID))
+	logger.Debug(fmt.Sprintf("Signing GitHub pull request attestation using '%s'...", keyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
 		return err
 	}
 
-	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
 	if err != nil {
 		return err
 	}
@@ -304,14 +1200,53 @@ func (r *Repository) addGitHubPullRequestAttestation(ctx context.Context, signer
 
 	commitMessage := fmt.Sprintf("Add GitHub pull request attestation for '%s' at '%s'\n\nSource: https://github.com/%s/%s/pull/%d\n", targetRef, targetCommitID, owner, repository, *pullRequest.Number)
 
-	slog.Debug("Committing attestations...")
+	logger.Debug("Committing attestations...")
 	return allAttestations.Commit(r.r, commitMessage, signCommit)
 }
 
-func getGitHubClient() *github.Client {
-	if githubClient == nil {
-		githubClient = github.NewClient(nil).WithAuthToken(os.Getenv("GITHUB_TOKEN"))
+// getGitHubClient returns a GitHub API client authenticated with a token from
+// tokenSource, or an anonymous client if no token is available.
+func getGitHubClient(ctx context.Context, tokenSource credentials.Source) (*github.Client, error) {
+	client := github.NewClient(nil)
+	if tokenSource == nil {
+		return client, nil
+	}
+
+	token, err := tokenSource.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return client, nil
+	}
+
+	return client.WithAuthToken(token.Value), nil
+}
+
+// loadCurrentAttestationsForUpdate loads the current attestations, configured
+// to compress the attestations that are added as required by the repository's
+// root of trust.
+func (r *Repository) loadCurrentAttestationsForUpdate(ctx context.Context) (*attestations.Attestations, error) {
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) || errors.Is(err, plumbing.ErrReferenceNotFound) {
+			// Attestations can be recorded before the policy is applied,
+			// in which case they're not compressed
+			return allAttestations, nil
+		}
+		return nil, err
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return nil, err
 	}
 
-	return githubClient
+	allAttestations.SetCompression(rootMetadata.MetadataCompression)
+	return allAttestations, nil
 }
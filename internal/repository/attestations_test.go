@@ -4,15 +4,26 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
 	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/bitbucket"
+	"github.com/gittuf/gittuf/internal/ci"
+	"github.com/gittuf/gittuf/internal/codereview"
 	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/gerrit"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -131,3 +142,592 @@ func TestAddAndRemoveReferenceAuthorization(t *testing.T) {
 	assert.Len(t, env.Signatures, 1)
 	assert.Equal(t, firstKeyID, env.Signatures[0].KeyID)
 }
+
+func TestAddReferenceAuthorizationForTag(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, "refs/heads/main", 1, gpgKeyBytes)
+	targetID := commitIDs[0].String()
+	tagRef := "refs/tags/v1"
+
+	firstSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tag name is used instead of the absolute path
+	err = repo.AddReferenceAuthorizationForTag(testCtx, firstSigner, "v1", targetID, false)
+	assert.Nil(t, err)
+
+	err = repo.AddReferenceAuthorizationForTag(testCtx, secondSigner, tagRef, targetID, false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := allAttestations.GetReferenceAuthorizationFor(r, tagRef, plumbing.ZeroHash.String(), targetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, env.Signatures, 2)
+
+	// Once the tag is recorded in the RSL, it cannot be authorized anymore
+	tagID := common.CreateTestSignedTag(t, r, "v1", commitIDs[0], gpgKeyBytes)
+	if err := rsl.NewReferenceEntry(tagRef, tagID).Commit(r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.AddReferenceAuthorizationForTag(testCtx, firstSigner, tagRef, targetID, false)
+	assert.ErrorIs(t, err, ErrTagAlreadyExists)
+}
+
+func TestRequestApprovalAndApprove(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, "refs/heads/main", 1, gpgKeyBytes)
+	targetID := commitIDs[0].String()
+	tagRef := "refs/tags/v1"
+
+	fromID, toID, err := repo.RequestApproval(testCtx, tagRef, targetID, 0, false)
+	assert.Nil(t, err)
+	assert.Equal(t, plumbing.ZeroHash.String(), fromID)
+	assert.Equal(t, targetID, toID)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := allAttestations.GetReferenceAuthorizationFor(r, tagRef, fromID, toID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, env.Signatures)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID, err := signer.KeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.Approve(testCtx, signer, tagRef, targetID, 0, false)
+	assert.Nil(t, err)
+
+	allAttestations, err = attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err = allAttestations.GetReferenceAuthorizationFor(r, tagRef, fromID, toID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, env.Signatures, 1)
+	assert.Equal(t, keyID, env.Signatures[0].KeyID)
+}
+
+func TestAddPushEventAttestation(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+	if err := rsl.NewReferenceEntry(refName, commitIDs[0]).Commit(r, false); err != nil {
+		t.Fatal(err)
+	}
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r, refName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.AddPushEventAttestation(testCtx, signer, refName, "not-an-address", "", "", false)
+	assert.ErrorIs(t, err, attestations.ErrInvalidIPAddress)
+
+	err = repo.AddPushEventAttestation(testCtx, signer, refName, "192.0.2.113", "build-01", "https://ci.example.com/runs/1", false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := allAttestations.GetPushEventAttestationFor(r, entry.ID.String())
+	assert.Nil(t, err)
+	assert.Len(t, env.Signatures, 1)
+
+	// Attesting to the same details with another key adds a signature to the
+	// existing attestation
+	anotherSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.AddPushEventAttestation(testCtx, anotherSigner, refName, "192.0.2.7", "build-01", "https://ci.example.com/runs/1", false)
+	assert.Nil(t, err)
+
+	allAttestations, err = attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err = allAttestations.GetPushEventAttestationFor(r, entry.ID.String())
+	assert.Nil(t, err)
+	assert.Len(t, env.Signatures, 2)
+}
+
+func TestAddCIRunAttestation(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciEnv := &ci.Environment{
+		Provider: ci.GitHubActionsProvider,
+		Workflow: "gittuf/gittuf/.github/workflows/ci.yml@refs/heads/main",
+		RunID:    "1234-1",
+		Trigger:  "push",
+		RefName:  refName,
+		CommitID: commitIDs[0].String(),
+	}
+
+	err = repo.AddCIRunAttestation(testCtx, signer, &ci.Environment{Provider: ci.GitHubActionsProvider, RunID: "1234-1"}, "", false)
+	assert.ErrorIs(t, err, attestations.ErrInvalidCIRun)
+
+	err = repo.AddCIRunAttestation(testCtx, signer, ciEnv, "", false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := allAttestations.GetCIRunAttestationFor(r, ciEnv.CommitID, ciEnv.Provider, ciEnv.RunID)
+	assert.Nil(t, err)
+	assert.Len(t, env.Signatures, 1)
+}
+
+func TestAddGerritChangeAttestation(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/changes/1234" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprintf(w, `)]}'
+{"project": "gittuf", "branch": "main", "_number": 1234, "status": "MERGED", "current_revision": "%s", "labels": {"Code-Review": {"all": [{"value": 2, "username": "john"}]}}}`, commitIDs[0].String())
+	}))
+	defer server.Close()
+
+	client := gerrit.NewClient(server.URL, "", "")
+
+	err = repo.AddGerritChangeAttestation(testCtx, signer, client, "5678", commitIDs[0].String(), false)
+	assert.ErrorIs(t, err, gerrit.ErrUnexpectedResponse)
+
+	err = repo.AddGerritChangeAttestation(testCtx, signer, client, "1234", plumbing.ZeroHash.String(), false)
+	assert.NotNil(t, err)
+
+	err = repo.AddGerritChangeAttestation(testCtx, signer, client, "1234", commitIDs[0].String(), false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := allAttestations.GetGerritChangeAttestationFor(r, refName, commitIDs[0].String())
+	assert.Nil(t, err)
+	assert.Len(t, env.Signatures, 1)
+}
+
+func TestAddBitbucketPullRequestAttestation(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/latest/projects/PRJ/repos/repo/pull-requests/1":
+			fmt.Fprintf(w, `{"id": 1, "state": "MERGED", "toRef": {"id": "refs/heads/main"}, "author": {"user": {"name": "jane"}}, "reviewers": [{"user": {"name": "john"}, "status": "APPROVED"}], "properties": {"mergeCommit": {"id": "%s"}}}`, commitIDs[0].String())
+		case "/rest/api/latest/projects/PRJ/repos/repo/pull-requests/2":
+			fmt.Fprint(w, `{"id": 2, "state": "OPEN", "toRef": {"id": "refs/heads/main"}, "author": {"user": {"name": "jane"}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := bitbucket.NewClient(server.URL, nil)
+	identities := bitbucket.IdentityMap{"john": "john@example.com"}
+
+	err = repo.AddBitbucketPullRequestAttestation(testCtx, signer, client, identities, "PRJ", "repo", 3, "", false)
+	assert.ErrorIs(t, err, bitbucket.ErrUnexpectedResponse)
+
+	err = repo.AddBitbucketPullRequestAttestation(testCtx, signer, client, identities, "PRJ", "repo", 2, "", false)
+	assert.NotNil(t, err)
+
+	err = repo.AddBitbucketPullRequestAttestation(testCtx, signer, client, identities, "PRJ", "repo", 1, plumbing.ZeroHash.String(), false)
+	assert.NotNil(t, err)
+
+	err = repo.AddBitbucketPullRequestAttestation(testCtx, signer, client, identities, "PRJ", "repo", 1, "", false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := allAttestations.GetBitbucketPullRequestAttestationFor(r, refName, commitIDs[0].String())
+	assert.Nil(t, err)
+	assert.Len(t, env.Signatures, 1)
+}
+
+func TestAddReviewApprovalAttestation(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	review := &codereview.Review{
+		Platform:   codereview.PlatformGitHub,
+		URL:        "https://github.com/gittuf/gittuf/pull/1",
+		RefName:    refName,
+		HeadCommit: commitIDs[0].String(),
+		Author:     codereview.User{Name: "jane"},
+		Approvals: []codereview.Approval{
+			{Reviewer: codereview.User{Name: "john"}, CommitID: commitIDs[0].String()},
+		},
+	}
+	identities := codereview.IdentityMap{"john": "john@example.com"}
+
+	err = repo.AddReviewApprovalAttestation(testCtx, signer, review, identities, false)
+	assert.NotNil(t, err)
+
+	review.TargetID = plumbing.ZeroHash.String()
+	err = repo.AddReviewApprovalAttestation(testCtx, signer, review, identities, false)
+	assert.NotNil(t, err)
+
+	review.TargetID = commitIDs[0].String()
+	err = repo.AddReviewApprovalAttestation(testCtx, signer, review, identities, false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := allAttestations.GetReviewApprovalAttestationFor(r, refName, commitIDs[0].String())
+	assert.Nil(t, err)
+	assert.Len(t, env.Signatures, 1)
+
+	storedReview, err := attestations.GetReview(env)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"john@example.com"}, storedReview.ApprovingPrincipals())
+}
+
+func TestAddHookExecutionAttestation(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hookDigest := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	err = repo.AddHookExecutionAttestation(testCtx, signer, "main", "secret-scan", hookDigest, 0, false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := allAttestations.GetHookExecutionAttestationFor(r, refName, commitIDs[0].String(), "secret-scan")
+	assert.Nil(t, err)
+	result, err := attestations.GetHookExecutionResult(env)
+	assert.Nil(t, err)
+	assert.Equal(t, attestations.HookResultPass, result)
+
+	// A failing execution replaces the earlier result
+	err = repo.AddHookExecutionAttestation(testCtx, signer, refName, "secret-scan", hookDigest, 2, false)
+	assert.Nil(t, err)
+
+	allAttestations, err = attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err = allAttestations.GetHookExecutionAttestationFor(r, refName, commitIDs[0].String(), "secret-scan")
+	assert.Nil(t, err)
+	result, err = attestations.GetHookExecutionResult(env)
+	assert.Nil(t, err)
+	assert.Equal(t, attestations.HookResultFail, result)
+}
+
+func TestAddRebuildAttestation(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+
+	rebuilder1, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilder2, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artifactName := "gittuf-linux-amd64"
+	artifactDigest := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	err = repo.AddRebuildAttestation(testCtx, rebuilder1, "main", artifactName, artifactDigest, false)
+	assert.Nil(t, err)
+
+	// A second rebuilder obtaining the same digest signs the same attestation
+	err = repo.AddRebuildAttestation(testCtx, rebuilder2, refName, artifactName, artifactDigest, false)
+	assert.Nil(t, err)
+
+	// A rebuilder obtaining a different digest creates a separate attestation
+	err = repo.AddRebuildAttestation(testCtx, rebuilder2, refName, artifactName, "aaaa", false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rebuilds, err := allAttestations.GetRebuildAttestationsFor(r, commitIDs[0].String(), artifactName)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rebuilds))
+	assert.Equal(t, 2, len(rebuilds[artifactDigest].Signatures))
+	assert.Equal(t, 1, len(rebuilds["aaaa"].Signatures))
+
+	err = repo.AddRebuildAttestation(testCtx, rebuilder1, refName, "dist/gittuf", artifactDigest, false)
+	assert.ErrorIs(t, err, attestations.ErrInvalidRebuild)
+}
+
+func TestPruneAttestations(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+
+	if err := repo.AddHookExecutionAttestation(testCtx, signer, refName, "secret-scan", "", 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing to prune while main is at the commit the hook was executed for
+	pruned, err := repo.PruneAttestations(testCtx, signer, false)
+	assert.Nil(t, err)
+	assert.Empty(t, pruned)
+
+	commitIDs = append(commitIDs, common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)...)
+	common.CreateTestRSLReferenceEntryCommit(t, r, rsl.NewReferenceEntry(refName, commitIDs[1]), gpgKeyBytes)
+
+	expectedPath := "hook-executions/" + attestations.HookExecutionPath(refName, commitIDs[0].String(), "secret-scan")
+
+	pruned, err = repo.FindPrunableAttestations()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(pruned))
+	assert.Equal(t, expectedPath, pruned[0].Path)
+	assert.Equal(t, attestations.PruneReasonSuperseded, pruned[0].Reason)
+
+	attestationsRef, err := r.Reference(plumbing.ReferenceName(attestations.Ref), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err = repo.PruneAttestations(testCtx, signer, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(pruned))
+	assert.Equal(t, expectedPath, pruned[0].Path)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = allAttestations.GetHookExecutionAttestationFor(r, refName, commitIDs[0].String(), "secret-scan")
+	assert.ErrorIs(t, err, attestations.ErrHookExecutionNotFound)
+
+	tombstone, err := allAttestations.GetTombstoneFor(r, attestationsRef.Hash().String())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(tombstone.Signatures))
+}
+
+func TestCounterSignAttestation(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := repo.InitializeNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+	if err := rsl.NewReferenceEntry(refName, commitIDs[0]).Commit(r, false); err != nil {
+		t.Fatal(err)
+	}
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r, refName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	counterSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.AddPushEventAttestation(testCtx, signer, refName, "", "build-01", "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	attestationPath := "push-events/" + entry.ID.String()
+
+	err = repo.CounterSignAttestation(testCtx, counterSigner, "push-events/"+plumbing.ZeroHash.String(), false)
+	assert.ErrorIs(t, err, attestations.ErrAttestationNotFound)
+
+	err = repo.CounterSignAttestation(testCtx, counterSigner, attestationPath, false)
+	assert.Nil(t, err)
+
+	// Counter-signing again with the same key does not add another signature
+	err = repo.CounterSignAttestation(testCtx, counterSigner, attestationPath, false)
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := allAttestations.GetEnvelope(r, attestationPath)
+	assert.Nil(t, err)
+	assert.Len(t, env.Signatures, 2)
+}
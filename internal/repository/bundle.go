@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/policy"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// AttestationsBundleVersion is the version of the bundle format produced by
+// ExportAttestationsBundle.
+const AttestationsBundleVersion = 1
+
+var (
+	ErrInvalidAttestationsBundle = errors.New("invalid attestations bundle")
+	ErrUnsupportedBundleVersion  = errors.New("unsupported attestations bundle version")
+)
+
+// AttestationsBundle is a portable set of attestations along with the policy
+// needed to verify them. It allows attestations to be carried across networks
+// that are not connected to one another, such as for review in an air-gapped
+// environment.
+type AttestationsBundle struct {
+	Version int `json:"version"`
+
+	// Policy is the policy state the attestations were exported with.
+	Policy *policy.State `json:"policy"`
+
+	// Attestations maps each attestation's path in the attestations namespace
+	// to its envelope.
+	Attestations map[string]*sslibdsse.Envelope `json:"attestations"`
+}
+
+// ExportAttestationsBundle creates a bundle with the repository's current
+// attestations and policy. If paths are specified, only the attestations at
+// those paths are included.
+func (r *Repository) ExportAttestationsBundle(ctx context.Context, paths ...string) ([]byte, error) {
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	envelopes, err := allAttestations.Envelopes(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) != 0 {
+		selected := make(map[string]*sslibdsse.Envelope, len(paths))
+		for _, attestationPath := range paths {
+			env, has := envelopes[attestationPath]
+			if !has {
+				return nil, fmt.Errorf("%w: '%s'", attestations.ErrUnknownAttestationPath, attestationPath)
+			}
+
+			selected[attestationPath] = env
+		}
+		envelopes = selected
+	}
+
+	bundle := &AttestationsBundle{
+		Version:      AttestationsBundleVersion,
+		Policy:       state,
+		Attestations: envelopes,
+	}
+
+	return json.MarshalIndent(bundle, "", "  ")
+}
+
+// VerifyAttestationsBundle checks that the bundle's policy is rooted in the
+// repository's current policy, and that every attestation in the bundle is
+// valid for its path and signed by a key trusted in the bundle's policy. The
+// paths of the verified attestations are returned.
+func (r *Repository) VerifyAttestationsBundle(ctx context.Context, bundleContents []byte) ([]string, error) {
+	bundle, err := r.loadAndVerifyAttestationsBundle(ctx, bundleContents)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(bundle.Attestations))
+	for attestationPath := range bundle.Attestations {
+		paths = append(paths, attestationPath)
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// ImportAttestationsBundle verifies the bundle and records its attestations in
+// the repository's attestations namespace. If an attestation already exists at
+// the same path with the same contents, the signatures are combined. Otherwise,
+// the existing attestation is replaced. The paths of the imported attestations
+// are returned.
+func (r *Repository) ImportAttestationsBundle(ctx context.Context, bundleContents []byte, signCommit bool) ([]string, error) {
+	bundle, err := r.loadAndVerifyAttestationsBundle(ctx, bundleContents)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existingEnvelopes, err := allAttestations.Envelopes(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(bundle.Attestations))
+	for attestationPath := range bundle.Attestations {
+		paths = append(paths, attestationPath)
+	}
+	sort.Strings(paths)
+
+	for _, attestationPath := range paths {
+		env := bundle.Attestations[attestationPath]
+		if existingEnv, has := existingEnvelopes[attestationPath]; has && existingEnv.Payload == env.Payload {
+			env = combineEnvelopeSignatures(existingEnv, env)
+		}
+
+		logger.Debug(fmt.Sprintf("Importing attestation '%s'...", attestationPath))
+		if err := allAttestations.SetEnvelope(r.r, attestationPath, env); err != nil {
+			return nil, err
+		}
+	}
+
+	commitMessage := fmt.Sprintf("Import %d attestations from bundle", len(paths))
+
+	logger.Debug("Committing attestations...")
+	if err := allAttestations.Commit(r.r, commitMessage, signCommit); err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+func (r *Repository) loadAndVerifyAttestationsBundle(ctx context.Context, bundleContents []byte) (*AttestationsBundle, error) {
+	bundle := &AttestationsBundle{}
+	if err := json.Unmarshal(bundleContents, bundle); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAttestationsBundle, err)
+	}
+
+	if bundle.Version != AttestationsBundleVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedBundleVersion, bundle.Version)
+	}
+
+	if bundle.Policy == nil || bundle.Policy.RootEnvelope == nil {
+		return nil, fmt.Errorf("%w: bundle does not contain policy", ErrInvalidAttestationsBundle)
+	}
+
+	logger.Debug("Loading current policy...")
+	currentState, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Verifying bundle's policy...")
+	if err := currentState.VerifyNewState(ctx, bundle.Policy); err != nil {
+		return nil, fmt.Errorf("bundle's policy is not trusted by repository's policy: %w", err)
+	}
+	if err := bundle.Policy.Verify(ctx); err != nil {
+		return nil, fmt.Errorf("bundle's policy has invalidly signed metadata: %w", err)
+	}
+
+	for attestationPath, env := range bundle.Attestations {
+		logger.Debug(fmt.Sprintf("Verifying attestation '%s'...", attestationPath))
+		if env == nil {
+			return nil, fmt.Errorf("%w: attestation '%s' is empty", ErrInvalidAttestationsBundle, attestationPath)
+		}
+
+		if err := attestations.ValidateEnvelope(attestationPath, env); err != nil {
+			return nil, fmt.Errorf("unable to validate attestation '%s': %w", attestationPath, err)
+		}
+
+		if err := bundle.Policy.VerifyAttestationSignatures(ctx, env); err != nil {
+			return nil, fmt.Errorf("unable to verify signatures of attestation '%s': %w", attestationPath, err)
+		}
+	}
+
+	return bundle, nil
+}
+
+// combineEnvelopeSignatures returns a copy of the first envelope with the
+// signatures from the second envelope that it does not already have.
+func combineEnvelopeSignatures(env, other *sslibdsse.Envelope) *sslibdsse.Envelope {
+	combined := &sslibdsse.Envelope{
+		PayloadType: env.PayloadType,
+		Payload:     env.Payload,
+		Signatures:  append([]sslibdsse.Signature{}, env.Signatures...),
+	}
+
+	for _, signature := range other.Signatures {
+		found := false
+		for _, existing := range combined.Signatures {
+			if existing.KeyID == signature.KeyID && existing.Sig == signature.Sig {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			combined.Signatures = append(combined.Signatures, signature)
+		}
+	}
+
+	return combined
+}
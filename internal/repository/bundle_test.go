@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttestationsBundle(t *testing.T) {
+	refName := "refs/heads/main"
+	testID := plumbing.ZeroHash.String()
+	authPath := "reference-authorizations/" + attestations.ReferenceAuthorizationPath(refName, testID, testID)
+
+	createAuthorization := func(t *testing.T, signingKeyBytes []byte) *Repository {
+		t.Helper()
+
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		authorization, err := attestations.NewReferenceAuthorization(refName, testID, testID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(signingKeyBytes) //nolint:staticcheck
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		allAttestations, err := attestations.LoadCurrentAttestations(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := allAttestations.SetReferenceAuthorization(repo.r, env, refName, testID, testID); err != nil {
+			t.Fatal(err)
+		}
+		if err := allAttestations.Commit(repo.r, "Add authorization", false); err != nil {
+			t.Fatal(err)
+		}
+
+		return repo
+	}
+
+	t.Run("export and import", func(t *testing.T) {
+		sourceRepo := createAuthorization(t, targetsKeyBytes)
+
+		bundleContents, err := sourceRepo.ExportAttestationsBundle(testCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		destinationRepo := createTestRepositoryWithPolicy(t, "")
+
+		paths, err := destinationRepo.VerifyAttestationsBundle(testCtx, bundleContents)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{authPath}, paths)
+
+		paths, err = destinationRepo.ImportAttestationsBundle(testCtx, bundleContents, false)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{authPath}, paths)
+
+		allAttestations, err := attestations.LoadCurrentAttestations(destinationRepo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := allAttestations.GetReferenceAuthorizationFor(destinationRepo.r, refName, testID, testID)
+		assert.Nil(t, err)
+		assert.Len(t, env.Signatures, 1)
+
+		// Importing the same bundle again does not duplicate signatures
+		_, err = destinationRepo.ImportAttestationsBundle(testCtx, bundleContents, false)
+		assert.Nil(t, err)
+
+		allAttestations, err = attestations.LoadCurrentAttestations(destinationRepo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = allAttestations.GetReferenceAuthorizationFor(destinationRepo.r, refName, testID, testID)
+		assert.Nil(t, err)
+		assert.Len(t, env.Signatures, 1)
+	})
+
+	t.Run("export selected attestations", func(t *testing.T) {
+		sourceRepo := createAuthorization(t, targetsKeyBytes)
+
+		bundleContents, err := sourceRepo.ExportAttestationsBundle(testCtx, authPath)
+		assert.Nil(t, err)
+
+		bundle := &AttestationsBundle{}
+		if err := json.Unmarshal(bundleContents, bundle); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, AttestationsBundleVersion, bundle.Version)
+		assert.Len(t, bundle.Attestations, 1)
+
+		_, err = sourceRepo.ExportAttestationsBundle(testCtx, "reference-authorizations/refs/heads/unknown")
+		assert.ErrorIs(t, err, attestations.ErrUnknownAttestationPath)
+	})
+
+	t.Run("attestation signed by untrusted key", func(t *testing.T) {
+		sourceRepo := createAuthorization(t, artifacts.SSLibKey3Private)
+
+		bundleContents, err := sourceRepo.ExportAttestationsBundle(testCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = sourceRepo.VerifyAttestationsBundle(testCtx, bundleContents)
+		assert.ErrorIs(t, err, policy.ErrVerifierConditionsUnmet)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		_, err := repo.VerifyAttestationsBundle(testCtx, []byte(`{"version": 2}`))
+		assert.ErrorIs(t, err, ErrUnsupportedBundleVersion)
+
+		_, err = repo.VerifyAttestationsBundle(testCtx, []byte(`not a bundle`))
+		assert.ErrorIs(t, err, ErrInvalidAttestationsBundle)
+	})
+}
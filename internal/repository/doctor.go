@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/sigstore"
+)
+
+// Diagnostic is the result of one of the checks performed by Doctor.
+type Diagnostic struct {
+	// Check names what was checked.
+	Check string
+
+	// Problem describes what's wrong. It is empty if the check passed.
+	Problem string
+
+	// Fix suggests how to resolve the problem.
+	Fix string
+}
+
+// OK indicates if the check passed.
+func (d *Diagnostic) OK() bool {
+	return d.Problem == ""
+}
+
+// Doctor checks the environment and the repository for common problems that
+// prevent gittuf from working as expected. The local RSL is compared against
+// the last fetched state of the specified remote's RSL.
+func (r *Repository) Doctor(ctx context.Context, remoteName string) ([]*Diagnostic, error) {
+	diagnostics := []*Diagnostic{
+		checkGitVersion(),
+		checkSigningProgram(),
+		checkSigningKey(),
+		checkSigstoreTrustedRoot(ctx),
+	}
+
+	status, err := r.Status(ctx, remoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !status.Enabled {
+		diagnostics = append(diagnostics, &Diagnostic{
+			Check:   "gittuf",
+			Problem: "the repository doesn't have an RSL and an applied policy",
+			Fix:     "Set up gittuf with 'gittuf trust init' and 'gittuf policy init', or pull the RSL and policy from a remote that uses gittuf with 'gittuf rsl remote pull' and 'gittuf policy remote pull'",
+		})
+		return diagnostics, nil
+	}
+
+	diagnostics = append(diagnostics,
+		checkExpiry("Root of trust expiry", status.RootExpires, "Ask the root key holders to issue updated root of trust metadata"),
+		checkExpiry("Policy expiry", status.PolicyExpires, "Ask the policy key holders to issue updated policy metadata"),
+		checkRSLSync(status),
+		checkUnrecordedRefs(status),
+	)
+
+	return diagnostics, nil
+}
+
+func checkGitVersion() *Diagnostic {
+	logger.Debug("Checking Git version...")
+	diagnostic := &Diagnostic{Check: "Git version"}
+
+	version, err := gitinterface.GetGitVersion()
+	if err != nil {
+		diagnostic.Problem = fmt.Sprintf("unable to identify Git version: %s", err)
+		diagnostic.Fix = "Install Git and make sure it's in your PATH"
+		return diagnostic
+	}
+
+	supported, err := gitinterface.IsSupportedGitVersion(version)
+	if err != nil {
+		diagnostic.Problem = err.Error()
+		diagnostic.Fix = fmt.Sprintf("Install Git %s or newer", gitinterface.MinimumGitVersion)
+		return diagnostic
+	}
+	if !supported {
+		diagnostic.Problem = fmt.Sprintf("Git %s is older than the minimum supported version %s", version, gitinterface.MinimumGitVersion)
+		diagnostic.Fix = fmt.Sprintf("Install Git %s or newer", gitinterface.MinimumGitVersion)
+	}
+
+	return diagnostic
+}
+
+func checkSigningProgram() *Diagnostic {
+	logger.Debug("Checking signing program...")
+	diagnostic := &Diagnostic{Check: "Signing program"}
+
+	program, _, err := gitinterface.GetSigningCommand()
+	if err == nil && !gitinterface.IsBuiltInSigningProgram(program) {
+		_, err = exec.LookPath(program)
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, gitinterface.ErrSigningKeyNotSpecified):
+		diagnostic.Problem = err.Error()
+		diagnostic.Fix = "Set the signing key with 'git config --global user.signingkey <key>'"
+	case errors.Is(err, gitinterface.ErrUnknownSigningMethod):
+		diagnostic.Problem = err.Error()
+		diagnostic.Fix = "Set gpg.format to one of 'gpg', 'ssh', or 'x509' with 'git config --global gpg.format <format>'"
+	case errors.Is(err, exec.ErrNotFound):
+		diagnostic.Problem = fmt.Sprintf("signing program '%s' not found", program)
+		diagnostic.Fix = fmt.Sprintf("Install '%s', or point Git to it by setting gpg.program, gpg.ssh.program, or gpg.x509.program", program)
+	default:
+		diagnostic.Problem = err.Error()
+		diagnostic.Fix = "Check the signing configuration with 'git config --get-regexp \"^(gpg|user)\\.\"'"
+	}
+
+	return diagnostic
+}
+
+func checkSigningKey() *Diagnostic {
+	logger.Debug("Checking signing key...")
+	diagnostic := &Diagnostic{Check: "Signing key"}
+
+	err := gitinterface.CheckSigningKey()
+	switch {
+	case err == nil:
+	case errors.Is(err, gitinterface.ErrSigningKeyUnreadable):
+		diagnostic.Problem = err.Error()
+		diagnostic.Fix = "Check that user.signingkey points to an existing key file that you can read"
+	case errors.Is(err, gitinterface.ErrSigningKeyNotSpecified), errors.Is(err, gitinterface.ErrUnknownSigningMethod):
+		// Already reported by the signing program check
+	default:
+		diagnostic.Problem = err.Error()
+		diagnostic.Fix = "Check the signing key configured with user.signingkey"
+	}
+
+	return diagnostic
+}
+
+func checkSigstoreTrustedRoot(ctx context.Context) *Diagnostic {
+	logger.Debug("Checking Sigstore trusted root...")
+	diagnostic := &Diagnostic{Check: "Sigstore trusted root"}
+
+	err := sigstore.CheckTrustedRoot(ctx)
+	switch {
+	case err == nil, errors.Is(err, sigstore.ErrTrustedRootNotCached):
+		// The trusted root is fetched when Sigstore is first used
+	default:
+		diagnostic.Problem = err.Error()
+		diagnostic.Fix = fmt.Sprintf("Check your network connection and try again, or remove the cache at '%s' so it's fetched again", sigstore.TrustedRootCacheDir())
+	}
+
+	return diagnostic
+}
+
+func checkExpiry(check string, expires time.Time, fix string) *Diagnostic {
+	diagnostic := &Diagnostic{Check: check}
+
+	if !expires.IsZero() && expires.Before(time.Now()) {
+		diagnostic.Problem = fmt.Sprintf("metadata expired on %s", expires.Format(time.RFC3339))
+		diagnostic.Fix = fix
+	}
+
+	return diagnostic
+}
+
+func checkRSLSync(status *Status) *Diagnostic {
+	diagnostic := &Diagnostic{Check: "RSL"}
+
+	switch status.RSLSync {
+	case RSLSyncStatusBehind:
+		diagnostic.Problem = fmt.Sprintf("the local RSL is behind '%s'", status.Remote)
+		diagnostic.Fix = fmt.Sprintf("Run 'gittuf rsl remote pull %s'", status.Remote)
+	case RSLSyncStatusDiverged:
+		diagnostic.Problem = fmt.Sprintf("the local RSL has diverged from '%s'", status.Remote)
+		diagnostic.Fix = fmt.Sprintf("Reset the local RSL to the remote's with 'git update-ref %s %s', then record local changes again with 'gittuf rsl record <ref>'", rsl.Ref, rsl.RemoteTrackerRef(status.Remote))
+	}
+
+	return diagnostic
+}
+
+func checkUnrecordedRefs(status *Status) *Diagnostic {
+	diagnostic := &Diagnostic{Check: "Unrecorded changes"}
+
+	if len(status.UnrecordedRefs) > 0 {
+		diagnostic.Problem = fmt.Sprintf("changes to %s aren't recorded in the RSL", strings.Join(status.UnrecordedRefs, ", "))
+		diagnostic.Fix = "Run 'gittuf rsl record <ref>' for each ref"
+	}
+
+	return diagnostic
+}
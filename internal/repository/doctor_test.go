@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoctor(t *testing.T) {
+	remoteName := "origin"
+
+	findDiagnostic := func(t *testing.T, diagnostics []*Diagnostic, check string) *Diagnostic {
+		t.Helper()
+
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Check == check {
+				return diagnostic
+			}
+		}
+
+		t.Fatalf("check '%s' not performed", check)
+		return nil
+	}
+
+	t.Run("repository without gittuf", func(t *testing.T) {
+		r, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo := &Repository{r: r}
+
+		diagnostics, err := repo.Doctor(testCtx, remoteName)
+		assert.Nil(t, err)
+
+		diagnostic := findDiagnostic(t, diagnostics, "gittuf")
+		assert.False(t, diagnostic.OK())
+		assert.NotEmpty(t, diagnostic.Fix)
+
+		// Environment checks are performed regardless
+		findDiagnostic(t, diagnostics, "Git version")
+		findDiagnostic(t, diagnostics, "Signing program")
+	})
+
+	t.Run("repository with gittuf", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		diagnostics, err := repo.Doctor(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.True(t, findDiagnostic(t, diagnostics, "Root of trust expiry").OK())
+		assert.True(t, findDiagnostic(t, diagnostics, "Policy expiry").OK())
+		assert.True(t, findDiagnostic(t, diagnostics, "RSL").OK())
+		assert.True(t, findDiagnostic(t, diagnostics, "Unrecorded changes").OK())
+
+		rslTip, err := repo.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Simulate the remote's RSL diverging from the local RSL
+		refName := "refs/heads/main"
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), rslTip.Hash())); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+		remoteRSLTip, err := repo.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.RemoteTrackerRef(remoteName)), remoteRSLTip.Hash())); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(rsl.Ref, rslTip.Hash())); err != nil {
+			t.Fatal(err)
+		}
+		otherRefName := "refs/heads/feature"
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(otherRefName), rslTip.Hash())); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.RecordRSLEntryForReference(otherRefName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		diagnostics, err = repo.Doctor(testCtx, remoteName)
+		assert.Nil(t, err)
+
+		diagnostic := findDiagnostic(t, diagnostics, "RSL")
+		assert.False(t, diagnostic.OK())
+		assert.Contains(t, diagnostic.Problem, "diverged")
+		assert.Contains(t, diagnostic.Fix, rsl.RemoteTrackerRef(remoteName))
+	})
+}
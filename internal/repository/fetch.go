@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var (
+	ErrRemoteRSLNotFound = errors.New("remote does not have an RSL")
+	ErrRSLDiverged       = errors.New("local and remote RSLs have diverged")
+)
+
+// rslTrackedRefs are the gittuf refs whose states are recorded in the RSL, and
+// which are therefore updated along with the RSL when fetching from a remote.
+var rslTrackedRefs = []string{rsl.Ref, policy.PolicyRef, attestations.Ref}
+
+// VerifyFetch verifies the tips of refs fetched from a remote against the
+// remote's RSL and policy before they're used. The fetched objects must already
+// be in the repository, but the refs need not be updated. remoteTips contains
+// the tips of the remote's refs, including its gittuf refs. The remote's RSL
+// must be an extension of the local RSL or vice versa, so the remote cannot
+// roll back or rewrite the RSL. Refs that aren't recorded in the remote's RSL
+// are only rejected if they're protected by policy, while gittuf refs that
+// aren't recorded in it are neither verified nor updated. If all refs are
+// verified and the remote's RSL is ahead of the local RSL, the local gittuf
+// refs are updated to the remote's. The returned map contains the verification
+// error for each rejected ref.
+func (r *Repository) VerifyFetch(ctx context.Context, remoteTips map[string]plumbing.Hash, refNames []string) (map[string]error, error) {
+	remoteRSLTip := remoteTips[rsl.Ref]
+	if remoteRSLTip.IsZero() {
+		return nil, ErrRemoteRSLNotFound
+	}
+
+	remoteAhead, err := r.isRemoteRSLAhead(remoteRSLTip)
+	if err != nil {
+		return nil, err
+	}
+
+	if remoteAhead {
+		localRSLTip, err := gitinterface.GetTip(r.r, rsl.Ref)
+		if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil, err
+		}
+
+		logger.Debug("Checking integrity of fetched objects...")
+		if err := r.checkFetchedObjects(localRSLTip, remoteRSLTip); err != nil {
+			return nil, err
+		}
+	}
+
+	updates := []*RefUpdate{}
+	for _, refName := range rslTrackedRefs {
+		if tip, has := remoteTips[refName]; has {
+			updates = append(updates, &RefUpdate{NewID: tip, RefName: refName})
+		}
+	}
+	for _, refName := range refNames {
+		if refName == plumbing.HEAD.String() || strings.HasPrefix(refName, gittufNamespacePrefix) {
+			continue
+		}
+		updates = append(updates, &RefUpdate{NewID: remoteTips[refName], RefName: refName})
+	}
+
+	logger.Debug("Loading remote state of repository...")
+	candidate, cleanup, err := r.loadProposedRepository(updates)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup() //nolint:errcheck
+
+	rejected := map[string]error{}
+	unrecorded := map[string]bool{}
+	for _, update := range updates {
+		if update.RefName == rsl.Ref {
+			continue
+		}
+
+		var err error
+		if strings.HasPrefix(update.RefName, gittufNamespacePrefix) {
+			var recorded bool
+			recorded, err = candidate.isRecordedInRSL(update.RefName)
+			if err != nil {
+				return nil, err
+			}
+			if !recorded {
+				logger.Debug(fmt.Sprintf("'%s' is not recorded in the remote's RSL, skipping verification...", update.RefName))
+				unrecorded[update.RefName] = true
+				continue
+			}
+
+			logger.Debug(fmt.Sprintf("Verifying '%s' at '%s'...", update.RefName, update.NewID.String()))
+			err = candidate.verifyGittufRefUpdate(ctx, update)
+		} else {
+			logger.Debug(fmt.Sprintf("Verifying '%s' at '%s'...", update.RefName, update.NewID.String()))
+			err = candidate.verifyFetchedRef(ctx, update.RefName)
+		}
+		if err != nil {
+			rejected[update.RefName] = err
+		}
+	}
+
+	if len(rejected) > 0 || !remoteAhead {
+		return rejected, nil
+	}
+
+	logger.Debug("Updating gittuf refs to remote's state...")
+	for _, update := range updates {
+		if !strings.HasPrefix(update.RefName, gittufNamespacePrefix) || unrecorded[update.RefName] {
+			continue
+		}
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(update.RefName), update.NewID)); err != nil {
+			return nil, err
+		}
+	}
+
+	return rejected, nil
+}
+
+// verifyFetchedRef verifies a ref fetched from a remote. Refs that aren't
+// recorded in the RSL are accepted unless they're protected by policy.
+func (r *Repository) verifyFetchedRef(ctx context.Context, refName string) error {
+	if _, _, err := rsl.GetLatestReferenceEntryForRef(r.r, refName); errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		verifiers, err := r.findVerifiersForPath(ctx, policy.PolicyRef, fmt.Sprintf("git:%s", refName))
+		if err != nil {
+			return err
+		}
+		if len(verifiers) > 0 {
+			return ErrRefUpdateNotRecordedRSL
+		}
+
+		logger.Debug(fmt.Sprintf("'%s' is not recorded in the RSL or protected by policy, skipping verification...", refName))
+		return nil
+	}
+
+	return r.VerifyRef(ctx, refName, false)
+}
+
+// isRecordedInRSL returns true if the RSL has an entry for the ref.
+func (r *Repository) isRecordedInRSL(refName string) (bool, error) {
+	_, _, err := rsl.GetLatestReferenceEntryForRef(r.r, refName)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, rsl.ErrRSLEntryNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// isRemoteRSLAhead returns true if the remote's RSL extends the local RSL, and
+// false if the local RSL extends or is the same as the remote's. If neither
+// extends the other, ErrRSLDiverged is returned.
+func (r *Repository) isRemoteRSLAhead(remoteRSLTip plumbing.Hash) (bool, error) {
+	localRSLTip, err := gitinterface.GetTip(r.r, rsl.Ref)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	if localRSLTip == remoteRSLTip {
+		return false, nil
+	}
+
+	localCommit, err := gitinterface.GetCommit(r.r, localRSLTip)
+	if err != nil {
+		return false, err
+	}
+	knows, err := gitinterface.KnowsCommit(r.r, remoteRSLTip, localCommit)
+	if err != nil {
+		return false, err
+	}
+	if knows {
+		return true, nil
+	}
+
+	remoteCommit, err := gitinterface.GetCommit(r.r, remoteRSLTip)
+	if err != nil {
+		return false, err
+	}
+	knows, err = gitinterface.KnowsCommit(r.r, localRSLTip, remoteCommit)
+	if err != nil {
+		return false, err
+	}
+	if knows {
+		return false, nil
+	}
+
+	return false, ErrRSLDiverged
+}
+
+// checkFetchedObjects checks the integrity of the RSL entries after oldRSLTip
+// up to newRSLTip, which were fetched from a remote, and the objects they
+// record before verification reads them, see gitinterface.CheckObject. The
+// full contents of the states of gittuf refs are checked, while only the
+// target objects of other refs are checked. Recorded objects are only checked
+// if they were fetched, as the RSL may be fetched without the refs it records.
+func (r *Repository) checkFetchedObjects(oldRSLTip, newRSLTip plumbing.Hash) error {
+	entryCommits, err := gitinterface.GetCommitsBetweenRange(r.r, newRSLTip, oldRSLTip)
+	if err != nil {
+		return err
+	}
+
+	checked := map[plumbing.Hash]bool{}
+	for _, entryCommit := range entryCommits {
+		if err := gitinterface.CheckCommitContents(r.r, entryCommit.Hash); err != nil {
+			return err
+		}
+
+		entry, err := rsl.GetEntry(r.r, entryCommit.Hash)
+		if err != nil {
+			return err
+		}
+		referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+		if !isReferenceEntry || referenceEntry.TargetID.IsZero() || checked[referenceEntry.TargetID] {
+			continue
+		}
+		checked[referenceEntry.TargetID] = true
+
+		if r.r.Storer.HasEncodedObject(referenceEntry.TargetID) != nil {
+			continue
+		}
+
+		if strings.HasPrefix(referenceEntry.RefName, gittufNamespacePrefix) {
+			err = gitinterface.CheckCommitContents(r.r, referenceEntry.TargetID)
+		} else {
+			err = gitinterface.CheckObject(r.r, referenceEntry.TargetID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyFetch(t *testing.T) {
+	refName := "refs/heads/main"
+
+	// remoteUpdate creates a commit for refName and a matching RSL entry, and
+	// then restores refName and the RSL to their prior state, as if the
+	// objects had been fetched from a remote whose refs are returned
+	remoteUpdate := func(t *testing.T, repo *Repository, keyBytes []byte) map[string]plumbing.Hash {
+		t.Helper()
+
+		localTips, err := repo.getTips([]string{refName, rsl.Ref})
+		if err != nil {
+			t.Fatal(err)
+		}
+		remoteTips, err := repo.getTips(rslTrackedRefs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, keyBytes)
+		remoteTips[refName] = commitIDs[0]
+		remoteTips[rsl.Ref] = common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), keyBytes)
+
+		if err := repo.restoreTips(localTips, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		return remoteTips
+	}
+
+	t.Run("authorized update", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, t.TempDir())
+		remoteTips := remoteUpdate(t, repo, gpgKeyBytes)
+
+		rejected, err := repo.VerifyFetch(testCtx, remoteTips, []string{refName})
+		assert.Nil(t, err)
+		assert.Empty(t, rejected)
+
+		// The local RSL is updated, but not the fetched ref
+		tip, err := repo.r.Reference(rsl.Ref, true)
+		assert.Nil(t, err)
+		assert.Equal(t, remoteTips[rsl.Ref], tip.Hash())
+		_, err = repo.r.Reference(plumbing.ReferenceName(refName), true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+
+	t.Run("unauthorized update", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, t.TempDir())
+		localTip, err := repo.r.Reference(rsl.Ref, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		remoteTips := remoteUpdate(t, repo, gpgUnauthorizedKeyBytes)
+
+		rejected, err := repo.VerifyFetch(testCtx, remoteTips, []string{refName})
+		assert.Nil(t, err)
+		assert.Len(t, rejected, 1)
+		assert.NotNil(t, rejected[refName])
+
+		// The local RSL is not updated
+		tip, err := repo.r.Reference(rsl.Ref, true)
+		assert.Nil(t, err)
+		assert.Equal(t, localTip.Hash(), tip.Hash())
+	})
+
+	t.Run("unprotected ref not recorded in RSL", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, t.TempDir())
+		remoteTips, err := repo.getTips(rslTrackedRefs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, "refs/heads/feature", 1, gpgKeyBytes)
+		remoteTips["refs/heads/feature"] = commitIDs[0]
+
+		rejected, err := repo.VerifyFetch(testCtx, remoteTips, []string{"refs/heads/feature"})
+		assert.Nil(t, err)
+		assert.Empty(t, rejected)
+	})
+
+	t.Run("diverged RSL", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, t.TempDir())
+		remoteTips := remoteUpdate(t, repo, gpgKeyBytes)
+
+		// A different entry is recorded locally
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, "refs/heads/feature", 1, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry("refs/heads/feature", commitIDs[0]), gpgKeyBytes)
+
+		_, err := repo.VerifyFetch(testCtx, remoteTips, []string{refName})
+		assert.ErrorIs(t, err, ErrRSLDiverged)
+	})
+
+	t.Run("corrupt fetched object", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo := createTestRepositoryWithPolicy(t, tmpDir)
+		localTip, err := repo.r.Reference(rsl.Ref, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		remoteTips := remoteUpdate(t, repo, gpgKeyBytes)
+
+		// Replace the fetched commit with the contents of a different object
+		objectPath := func(objectID plumbing.Hash) string {
+			return filepath.Join(tmpDir, "objects", objectID.String()[:2], objectID.String()[2:])
+		}
+		contents, err := os.ReadFile(objectPath(localTip.Hash()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(objectPath(remoteTips[refName]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(objectPath(remoteTips[refName]), contents, 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+
+		repo, err = LoadRepositoryAt(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = repo.VerifyFetch(testCtx, remoteTips, []string{refName})
+		assert.ErrorIs(t, err, gitinterface.ErrCorruptObject)
+
+		// The local RSL is not updated
+		tip, err := repo.r.Reference(rsl.Ref, true)
+		assert.Nil(t, err)
+		assert.Equal(t, localTip.Hash(), tip.Hash())
+	})
+
+	t.Run("remote without RSL", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, t.TempDir())
+
+		_, err := repo.VerifyFetch(testCtx, map[string]plumbing.Hash{refName: plumbing.ZeroHash}, []string{refName})
+		assert.ErrorIs(t, err, ErrRemoteRSLNotFound)
+	})
+}
@@ -6,9 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
-	"log/slog"
 	"os"
 	"path"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
 )
 
 type ErrHookExists struct {
@@ -21,33 +22,42 @@ func (e *ErrHookExists) Error() string {
 
 type HookType string
 
-var HookPrePush = HookType("pre-push")
+var (
+	HookPrePush    = HookType("pre-push")
+	HookPreReceive = HookType("pre-receive")
+)
 
-// UpdateHook updates a git hook in the repositorie's .git/hooks folder.
-// Existing hook files are not overwritten, unless force flag is set.
-func (r *Repository) UpdateHook(hookType HookType, content []byte, force bool) error {
-	// TODO: rely on go-git to find .git folder, once
-	// https://github.com/go-git/go-git/issues/977 is available.
-	// Note, until then gittuf does not support separate git dir.
+// hookScriptName is the name of gittuf's script in a hook's ".d" directory.
+const hookScriptName = "gittuf"
 
-	slog.Debug("Adding gittuf hooks...")
+// UpdateHook updates a git hook in the repositorie's .git/hooks folder, or in
+// the hooks folder of a bare repository such as one hosted on a server. If the
+// hook has a ".d" directory, as Gitea and Forgejo create for server-side hooks
+// they manage, the hook is added to that directory instead. Existing hook
+// files are not overwritten, unless force flag is set.
+func (r *Repository) UpdateHook(hookType HookType, content []byte, force bool) error {
+	logger.Debug("Adding gittuf hooks...")
 
-	slog.Debug("Loading repository worktree...")
-	tree, err := r.r.Worktree()
+	// Hooks are shared by all worktrees of the repository
+	commonDir, err := gitinterface.GetGitCommonDirFor(r.r)
 	if err != nil {
-		return fmt.Errorf("reading worktree: %w", err)
-	}
-	if tree == nil {
-		return fmt.Errorf("worktree is nil, can't update hooks")
+		return fmt.Errorf("repository is not on disk, can't update hooks")
 	}
+	hookFolder := path.Join(commonDir, "hooks")
 
-	repoRoot := tree.Filesystem.Root()
-	hookFolder := path.Join(repoRoot, ".git", "hooks")
 	if err := os.MkdirAll(hookFolder, 0o750); err != nil {
 		return fmt.Errorf("making sure folder exist: %w", err)
 	}
 
 	hookFile := path.Join(hookFolder, string(hookType))
+	hasHookDir, err := doesFileExist(hookFile + ".d")
+	if err != nil {
+		return fmt.Errorf("checking if hook directory '%s.d' exists: %w", hookFile, err)
+	}
+	if hasHookDir {
+		hookFile = path.Join(hookFile+".d", hookScriptName)
+	}
+
 	hookExists, err := doesFileExist(hookFile)
 	if err != nil {
 		return fmt.Errorf("checking if hookFile '%s' exists: %w", hookFile, err)
@@ -59,7 +69,7 @@ func (r *Repository) UpdateHook(hookType HookType, content []byte, force bool) e
 		}
 	}
 
-	slog.Debug("Writing hooks...")
+	logger.Debug("Writing hooks...")
 	if err := os.WriteFile(hookFile, content, 0o700); err != nil { // nolint:gosec
 		return fmt.Errorf("writing %s hook: %w", hookType, err)
 	}
@@ -72,3 +72,53 @@ func TestUpdatePrePushHook(t *testing.T) {
 		assert.Equal(t, []byte("new hook script"), content)
 	})
 }
+
+func TestUpdatePreReceiveHook(t *testing.T) {
+	t.Run("write hook in bare repository", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		repo, err := git.PlainInit(tmpDir, true)
+		require.NoError(t, err)
+		r := &Repository{r: repo}
+
+		err = r.UpdateHook(HookPreReceive, []byte("some content"), false)
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(path.Join(tmpDir, "hooks", "pre-receive"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("some content"), content)
+	})
+
+	t.Run("write hook in hook directory", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		repo, err := git.PlainInit(tmpDir, true)
+		require.NoError(t, err)
+		r := &Repository{r: repo}
+
+		// Gitea and Forgejo manage the hook itself, running the scripts in
+		// the hook directory
+		hookDir := path.Join(tmpDir, "hooks", "pre-receive.d")
+		err = os.MkdirAll(hookDir, 0o750)
+		require.NoError(t, err)
+		err = os.WriteFile(path.Join(tmpDir, "hooks", "pre-receive"), []byte("managed hook script"), 0o700) // nolint:gosec
+		require.NoError(t, err)
+
+		err = r.UpdateHook(HookPreReceive, []byte("some content"), false)
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(path.Join(hookDir, "gittuf"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("some content"), content)
+
+		content, err = os.ReadFile(path.Join(tmpDir, "hooks", "pre-receive"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("managed hook script"), content)
+
+		err = r.UpdateHook(HookPreReceive, []byte("new content"), false)
+		var hookErr *ErrHookExists
+		if assert.ErrorAs(t, err, &hookErr) {
+			assert.Equal(t, HookPreReceive, hookErr.HookType)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/identity"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+// checkIdentities returns an error wrapping identity.ErrConfusableIdentity if
+// the Sigstore or S/MIME identities of the keys or the rule patterns being
+// added to the policy can be confused with other identities, either on their
+// own or with the identities already in the policy. Otherwise, reviewers of the policy
+// may mistake a look-alike identity for the one it resembles.
+func checkIdentities(state *policy.State, keys []*tuf.Key, rulePatterns []string) error {
+	allKeys, err := state.PublicKeys()
+	if err != nil {
+		return err
+	}
+
+	existingIdentities := []string{}
+	for _, key := range allKeys {
+		if hasIdentity(key) {
+			existingIdentities = append(existingIdentities, key.KeyVal.Identity)
+		}
+	}
+
+	for _, key := range keys {
+		if !hasIdentity(key) {
+			continue
+		}
+
+		if err := identity.Check(key.KeyVal.Identity); err != nil {
+			return err
+		}
+		for _, existingIdentity := range existingIdentities {
+			if identity.Confusable(key.KeyVal.Identity, existingIdentity) {
+				return fmt.Errorf("%w: '%s' resembles '%s', which is already in the policy", identity.ErrConfusableIdentity, key.KeyVal.Identity, existingIdentity)
+			}
+		}
+	}
+
+	for _, rulePattern := range rulePatterns {
+		if err := identity.Check(rulePattern); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasIdentity returns true if the key is a Sigstore or S/MIME identity rather
+// than a public key.
+func hasIdentity(key *tuf.Key) bool {
+	return key.KeyType == signerverifier.FulcioKeyType || key.KeyType == signerverifier.SMIMEKeyType
+}
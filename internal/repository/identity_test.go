@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/identity"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddKeyToTargetsConfusableIdentity(t *testing.T) {
+	fulcioKey := func(keyIdentity string) *tuf.Key {
+		return &tuf.Key{
+			KeyID:   keyIdentity + "::https://github.com/login/oauth",
+			KeyType: signerverifier.FulcioKeyType,
+			Scheme:  signerverifier.FulcioKeyScheme,
+			KeyVal:  sslibsv.KeyVal{Identity: keyIdentity, Issuer: "https://github.com/login/oauth"},
+		}
+	}
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("distinct identity", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		err := r.AddKeyToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []*tuf.Key{fulcioKey("alice@example.com")}, false)
+		assert.Nil(t, err)
+
+		err = r.AddKeyToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []*tuf.Key{fulcioKey("bob@example.com")}, false)
+		assert.Nil(t, err)
+	})
+
+	t.Run("look-alike of identity in policy", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		err := r.AddKeyToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []*tuf.Key{fulcioKey("alice@example.com")}, false)
+		assert.Nil(t, err)
+
+		// Cyrillic "а" in place of the Latin "a"
+		err = r.AddKeyToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []*tuf.Key{fulcioKey("\u0430lice@example.com")}, false)
+		assert.ErrorIs(t, err, identity.ErrConfusableIdentity)
+	})
+
+	t.Run("invisible characters in identity", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		err := r.AddKeyToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []*tuf.Key{fulcioKey("alice\u200b@example.com")}, false)
+		assert.ErrorIs(t, err, identity.ErrConfusableIdentity)
+	})
+}
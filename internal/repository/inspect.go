@@ -0,0 +1,300 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const gittufNamespacePrefix = "refs/gittuf/"
+
+// PolicyRole describes a top level role in the applied policy.
+type PolicyRole struct {
+	Name      string
+	KeyIDs    []string
+	Threshold int
+
+	// Expires is the expiry date of the role's metadata. It is zero if the
+	// role's metadata hasn't been initialized.
+	Expires time.Time
+}
+
+// ListPolicyRoles returns the root of trust and the top level policy roles
+// declared in the policy at the specified ref, such as "policy" or
+// "policy-staging".
+func (r *Repository) ListPolicyRoles(ctx context.Context, targetRef string) ([]*PolicyRole, error) {
+	if !strings.HasPrefix(targetRef, gittufNamespacePrefix) {
+		targetRef = gittufNamespacePrefix + targetRef
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r, targetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []*PolicyRole{}
+	for _, roleName := range []string{policy.RootRoleName, policy.TargetsRoleName} {
+		role, has := rootMetadata.Roles[roleName]
+		if !has {
+			continue
+		}
+
+		policyRole := &PolicyRole{Name: roleName, KeyIDs: append([]string{}, role.KeyIDs...), Threshold: role.Threshold}
+		sort.Strings(policyRole.KeyIDs)
+
+		expires := ""
+		switch {
+		case roleName == policy.RootRoleName:
+			expires = rootMetadata.Expires
+		case state.TargetsEnvelope != nil:
+			targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+			if err != nil {
+				return nil, err
+			}
+			expires = targetsMetadata.Expires
+		}
+		if expires != "" {
+			policyRole.Expires, err = time.Parse(time.RFC3339, expires)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		roles = append(roles, policyRole)
+	}
+
+	return roles, nil
+}
+
+// PolicyMetadata contains the root of trust and the policy files of a policy
+// state.
+type PolicyMetadata struct {
+	Root *tuf.RootMetadata
+
+	// Targets maps the names of the policy files that have been initialized
+	// to their metadata.
+	Targets map[string]*tuf.TargetsMetadata
+}
+
+// GetPolicyMetadata returns the metadata of the policy at the specified ref,
+// such as "policy" or "policy-staging".
+func (r *Repository) GetPolicyMetadata(ctx context.Context, targetRef string) (*PolicyMetadata, error) {
+	if !strings.HasPrefix(targetRef, gittufNamespacePrefix) {
+		targetRef = gittufNamespacePrefix + targetRef
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r, targetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &PolicyMetadata{Root: rootMetadata, Targets: map[string]*tuf.TargetsMetadata{}}
+
+	roleNames := []string{}
+	if state.TargetsEnvelope != nil {
+		roleNames = append(roleNames, policy.TargetsRoleName)
+	}
+	for roleName := range state.DelegationEnvelopes {
+		roleNames = append(roleNames, roleName)
+	}
+
+	for _, roleName := range roleNames {
+		targetsMetadata, err := state.GetTargetsMetadata(roleName)
+		if err != nil {
+			return nil, err
+		}
+		metadata.Targets[roleName] = targetsMetadata
+	}
+
+	return metadata, nil
+}
+
+// FindVerifiersForRef returns the rules of the policy at the specified ref,
+// such as "policy" or "policy-staging", that authorize changes to refName.
+// Changes that meet any one of the rules are authorized. No rules are returned
+// if refName is not protected by the policy. refName is assumed to be a branch
+// if it is not a fully qualified ref that exists in the repository.
+func (r *Repository) FindVerifiersForRef(ctx context.Context, targetRef, refName string) ([]*policy.Verifier, error) {
+	absRefName, err := gitinterface.AbsoluteReference(r.r, refName)
+	if err != nil {
+		if !errors.Is(err, gitinterface.ErrReferenceNotFound) {
+			return nil, err
+		}
+		absRefName = plumbing.NewBranchReferenceName(refName).String()
+	}
+
+	return r.findVerifiersForPath(ctx, targetRef, fmt.Sprintf("git:%s", absRefName))
+}
+
+// FindVerifiersForFile returns the rules of the policy at the specified ref
+// that authorize changes to the file at filePath, relative to the root of the
+// repository. Changes that meet any one of the rules are authorized. No rules
+// are returned if the file is not protected by the policy.
+func (r *Repository) FindVerifiersForFile(ctx context.Context, targetRef, filePath string) ([]*policy.Verifier, error) {
+	return r.findVerifiersForPath(ctx, targetRef, fmt.Sprintf("file:%s", filePath))
+}
+
+func (r *Repository) findVerifiersForPath(ctx context.Context, targetRef, path string) ([]*policy.Verifier, error) {
+	if !strings.HasPrefix(targetRef, gittufNamespacePrefix) {
+		targetRef = gittufNamespacePrefix + targetRef
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r, targetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiers, err := state.FindVerifiersForPath(path)
+	if err != nil {
+		if errors.Is(err, policy.ErrMetadataNotFound) {
+			// No rules have been added to the policy yet
+			return []*policy.Verifier{}, nil
+		}
+		return nil, err
+	}
+
+	return verifiers, nil
+}
+
+// ListRSLEntries returns up to limit entries of the RSL, starting with the
+// latest one. All entries are returned if limit is not positive. No entries
+// are returned if the repository doesn't have an RSL yet.
+func (r *Repository) ListRSLEntries(limit int) ([]rsl.Entry, error) {
+	entries := []rsl.Entry{}
+
+	entry, err := rsl.GetLatestEntry(r.r)
+	for err == nil && (limit <= 0 || len(entries) < limit) {
+		entries = append(entries, entry)
+		entry, err = rsl.GetParentForEntry(r.r, entry)
+	}
+	if err != nil && !errors.Is(err, rsl.ErrRSLEntryNotFound) && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ListAttestations returns the attestations in the current state of the
+// repository's attestations namespace, keyed by their paths in the namespace.
+// If the repository doesn't have an RSL yet, no attestations are returned.
+func (r *Repository) ListAttestations() (map[string]*sslibdsse.Envelope, error) {
+	currentAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return map[string]*sslibdsse.Envelope{}, nil
+		}
+		return nil, err
+	}
+
+	return currentAttestations.Envelopes(r.r)
+}
+
+// ListRecordedRefs returns the refs outside the gittuf namespace that have
+// entries in the RSL, sorted by name.
+func (r *Repository) ListRecordedRefs() ([]string, error) {
+	recordedTargets, err := r.latestRecordedTargets()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []string{}
+	for refName := range recordedTargets {
+		if !strings.HasPrefix(refName, gittufNamespacePrefix) {
+			refs = append(refs, refName)
+		}
+	}
+
+	sort.Strings(refs)
+	return refs, nil
+}
+
+// ListLocalRefs returns the local branches and tags in the repository, sorted
+// by name.
+func (r *Repository) ListLocalRefs() ([]string, error) {
+	refs, err := r.r.References()
+	if err != nil {
+		return nil, err
+	}
+	defer refs.Close()
+
+	refNames := []string{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Name().IsBranch() || ref.Name().IsTag() {
+			refNames = append(refNames, ref.Name().String())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(refNames)
+	return refNames, nil
+}
+
+// ListRemotes returns the names of the remotes configured for the repository,
+// sorted by name.
+func (r *Repository) ListRemotes() ([]string, error) {
+	remotes, err := r.r.Remotes()
+	if err != nil {
+		return nil, err
+	}
+
+	remoteNames := make([]string, 0, len(remotes))
+	for _, remote := range remotes {
+		remoteNames = append(remoteNames, remote.Config().Name)
+	}
+
+	sort.Strings(remoteNames)
+	return remoteNames, nil
+}
+
+// KeyLabels returns human readable labels for the keys in the applied and the
+// staged policies, indexed by key ID. A policy that doesn't exist yet is
+// skipped.
+func (r *Repository) KeyLabels(ctx context.Context) (map[string]string, error) {
+	labels := map[string]string{}
+
+	for _, policyRef := range []string{policy.PolicyRef, policy.PolicyStagingRef} {
+		state, err := policy.LoadCurrentState(ctx, r.r, policyRef)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) || errors.Is(err, plumbing.ErrReferenceNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		stateLabels, err := state.KeyLabels()
+		if err != nil {
+			return nil, err
+		}
+		for keyID, label := range stateLabels {
+			labels[keyID] = label
+		}
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListPolicyRoles(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	roles, err := repo.ListPolicyRoles(testCtx, "policy")
+	assert.Nil(t, err)
+	assert.Len(t, roles, 2)
+
+	assert.Equal(t, policy.RootRoleName, roles[0].Name)
+	assert.Len(t, roles[0].KeyIDs, 1)
+	assert.Equal(t, 1, roles[0].Threshold)
+	assert.False(t, roles[0].Expires.IsZero())
+
+	assert.Equal(t, policy.TargetsRoleName, roles[1].Name)
+	assert.Len(t, roles[1].KeyIDs, 1)
+	assert.Equal(t, 1, roles[1].Threshold)
+	assert.False(t, roles[1].Expires.IsZero())
+}
+
+func TestGetPolicyMetadata(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	metadata, err := repo.GetPolicyMetadata(testCtx, "policy")
+	assert.Nil(t, err)
+
+	assert.Len(t, metadata.Root.Roles[policy.RootRoleName].KeyIDs, 1)
+	assert.Len(t, metadata.Targets, 1)
+
+	targetsMetadata := metadata.Targets[policy.TargetsRoleName]
+	assert.Equal(t, "protect-main", targetsMetadata.Delegations.Roles[0].Name)
+	assert.Equal(t, []string{"git:refs/heads/main"}, targetsMetadata.Delegations.Roles[0].Paths)
+
+	_, err = repo.GetPolicyMetadata(testCtx, "does-not-exist")
+	assert.NotNil(t, err)
+}
+
+func TestFindVerifiersForRef(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	verifiers, err := repo.FindVerifiersForRef(testCtx, "policy", "main")
+	assert.Nil(t, err)
+	assert.Len(t, verifiers, 1)
+	assert.Equal(t, "protect-main", verifiers[0].Name())
+	assert.Len(t, verifiers[0].Keys(), 1)
+	assert.Equal(t, 1, verifiers[0].Threshold())
+
+	verifiers, err = repo.FindVerifiersForRef(testCtx, "policy", "refs/heads/main")
+	assert.Nil(t, err)
+	assert.Len(t, verifiers, 1)
+
+	verifiers, err = repo.FindVerifiersForRef(testCtx, "policy", "feature")
+	assert.Nil(t, err)
+	assert.Empty(t, verifiers)
+
+	_, err = repo.FindVerifiersForRef(testCtx, "does-not-exist", "main")
+	assert.NotNil(t, err)
+}
+
+func TestFindVerifiersForFile(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	verifiers, err := repo.FindVerifiersForFile(testCtx, "policy", "README.md")
+	assert.Nil(t, err)
+	assert.Empty(t, verifiers)
+}
+
+func TestListRSLEntries(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	allEntries, err := repo.ListRSLEntries(0)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, allEntries)
+
+	latestEntry, err := rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, latestEntry.GetID(), allEntries[0].GetID())
+
+	entries, err := repo.ListRSLEntries(1)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, latestEntry.GetID(), entries[0].GetID())
+}
+
+func TestListAttestations(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	envelopes, err := repo.ListAttestations()
+	assert.Nil(t, err)
+	assert.Empty(t, envelopes)
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyBytes)
+	if err := rsl.NewReferenceEntry(refName, commitIDs[0]).Commit(repo.r, false); err != nil {
+		t.Fatal(err)
+	}
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(repo.r, refName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddPushEventAttestation(testCtx, signer, refName, "192.0.2.113", "", "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	envelopes, err = repo.ListAttestations()
+	assert.Nil(t, err)
+	assert.Len(t, envelopes, 1)
+	assert.Contains(t, envelopes, "push-events/"+entry.ID.String())
+}
+
+func TestListRecordedRefs(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refs, err := repo.ListRecordedRefs()
+	assert.Nil(t, err)
+	assert.Empty(t, refs)
+
+	latestEntry, err := rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, refName := range []string{"refs/heads/main", "refs/heads/feature"} {
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), latestEntry.GetID())); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	refs, err = repo.ListRecordedRefs()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"refs/heads/feature", "refs/heads/main"}, refs)
+}
+
+func TestListLocalRefs(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refs, err := repo.ListLocalRefs()
+	assert.Nil(t, err)
+	assert.Empty(t, refs)
+
+	latestEntry, err := rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, refName := range []string{"refs/tags/v1", "refs/heads/main", "refs/gittuf/other"} {
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), latestEntry.GetID())); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	refs, err = repo.ListLocalRefs()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"refs/heads/main", "refs/tags/v1"}, refs)
+}
+
+func TestListRemotes(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	remotes, err := repo.ListRemotes()
+	assert.Nil(t, err)
+	assert.Empty(t, remotes)
+
+	for _, remoteName := range []string{"upstream", "origin"} {
+		if _, err := repo.r.CreateRemote(&config.RemoteConfig{Name: remoteName, URLs: []string{"https://example.com/repo.git"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	remotes, err = repo.ListRemotes()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"origin", "upstream"}, remotes)
+}
+
+func TestKeyLabels(t *testing.T) {
+	t.Run("with policy", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		labels, err := repo.KeyLabels(testCtx)
+		assert.Nil(t, err)
+		assert.Equal(t, "gittuf Test Key (Test key for gittuf) <gittuf@saky.in>", labels[gpgKey.KeyID])
+	})
+
+	t.Run("without applied policy", func(t *testing.T) {
+		repo, _ := createTestRepositoryWithRoot(t, "")
+
+		labels, err := repo.KeyLabels(testCtx)
+		assert.Nil(t, err)
+		assert.NotNil(t, labels)
+	})
+}
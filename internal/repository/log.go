@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+)
+
+// Log returns the history of the specified ref as recorded in the RSL,
+// interleaving the commits introduced by each RSL entry with the rule and the
+// principals that authorized the change.
+func (r *Repository) Log(ctx context.Context, target string) ([]*policy.LogEntry, error) {
+	logger.Debug("Identifying absolute reference path...")
+	target, err := gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("Loading history of '%s'...", target))
+	return policy.Log(ctx, r.r, target)
+}
+
+// LogForSigner returns the RSL entries attributable to the specified key ID or
+// Sigstore identity, along with the commits they introduced that are signed by
+// it. If target is specified, only the history of the target ref is searched.
+func (r *Repository) LogForSigner(ctx context.Context, signer, target string) ([]*policy.LogEntry, error) {
+	if target != "" {
+		logger.Debug("Identifying absolute reference path...")
+		absTarget, err := gitinterface.AbsoluteReference(r.r, target)
+		if err != nil {
+			return nil, err
+		}
+		target = absTarget
+	}
+
+	logger.Debug(fmt.Sprintf("Searching history for changes by '%s'...", signer))
+	return policy.LogForSigner(ctx, r.r, signer, target)
+}
@@ -6,12 +6,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
 	"strings"
 
+	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
 var (
@@ -24,7 +27,7 @@ var (
 // Note that this also pushes the RSL as the policy cannot change without an
 // update to the RSL.
 func (r *Repository) PushPolicy(ctx context.Context, remoteName string) error {
-	slog.Debug(fmt.Sprintf("Pushing policy and RSL references to %s...", remoteName))
+	logger.Debug(fmt.Sprintf("Pushing policy and RSL references to %s...", remoteName))
 	if err := gitinterface.Push(ctx, r.r, remoteName, []string{policy.PolicyRef, policy.PolicyStagingRef, rsl.Ref}); err != nil {
 		return errors.Join(ErrPushingPolicy, err)
 	}
@@ -34,13 +37,35 @@ func (r *Repository) PushPolicy(ctx context.Context, remoteName string) error {
 
 // PullPolicy fetches gittuf policy from the specified remote. The fetches is
 // marked as fast forward only to detect divergence. Note that this also fetches
-// the RSL as the policy must be updated in sync with the RSL.
+// the RSL as the policy must be updated in sync with the RSL. If the fetched
+// objects are corrupt, the refs are restored to their prior states.
 func (r *Repository) PullPolicy(ctx context.Context, remoteName string) error {
-	slog.Debug(fmt.Sprintf("Pulling policy and RSL references from %s...", remoteName))
-	if err := gitinterface.Fetch(ctx, r.r, remoteName, []string{policy.PolicyRef, policy.PolicyStagingRef, rsl.Ref}, true); err != nil {
+	refNames := []string{policy.PolicyRef, policy.PolicyStagingRef, rsl.Ref}
+	tips, err := r.getTips(refNames)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Pulling policy and RSL references from %s...", remoteName))
+	if err := gitinterface.Fetch(ctx, r.r, remoteName, refNames, true); err != nil {
 		return errors.Join(ErrPullingPolicy, err)
 	}
 
+	if err := r.checkFetchedRSL(tips[rsl.Ref]); err != nil {
+		return r.restoreTips(tips, errors.Join(ErrPullingPolicy, err))
+	}
+
+	// The policy staging ref isn't recorded in the RSL
+	stagingTip, err := gitinterface.GetTip(r.r, policy.PolicyStagingRef)
+	if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return err
+	}
+	if !stagingTip.IsZero() && stagingTip != tips[policy.PolicyStagingRef] {
+		if err := gitinterface.CheckCommitContents(r.r, stagingTip); err != nil {
+			return r.restoreTips(tips, errors.Join(ErrPullingPolicy, err))
+		}
+	}
+
 	return nil
 }
 
@@ -48,9 +73,171 @@ func (r *Repository) ApplyPolicy(ctx context.Context, signRSLEntry bool) error {
 	return policy.Apply(ctx, r.r, signRSLEntry)
 }
 
+// RollbackPolicy reverts the active policy to the policy state applied in the
+// specified policy commit by applying it as a new policy state. If no policy
+// commit is specified, the policy state applied before the active one is used.
+func (r *Repository) RollbackPolicy(ctx context.Context, policyCommitID string, signCommit bool) error {
+	commitID := plumbing.ZeroHash
+	if policyCommitID != "" {
+		if !plumbing.IsHash(policyCommitID) {
+			return fmt.Errorf("%w: '%s'", policy.ErrPolicyStateNotFound, policyCommitID)
+		}
+		commitID = plumbing.NewHash(policyCommitID)
+	}
+
+	return policy.Rollback(ctx, r.r, commitID, signCommit)
+}
+
 func (r *Repository) ListRules(ctx context.Context, targetRef string) ([]*policy.DelegationWithDepth, error) {
 	if strings.HasPrefix(targetRef, "refs/gittuf/") {
 		return policy.ListRules(ctx, r.r, targetRef)
 	}
 	return policy.ListRules(ctx, r.r, "refs/gittuf/"+targetRef)
 }
+
+// AddPolicyJustification records an attestation justifying the changes in the
+// latest staged policy. The ticket and approver are optional. If the staged
+// policy has already been justified with the same details, the signer's
+// signature is added to the existing attestation.
+func (r *Repository) AddPolicyJustification(ctx context.Context, signer sslibdsse.SignerVerifier, reason, ticket, approver string, signCommit bool) error {
+	logger.Debug("Identifying latest staged policy...")
+	policyStagingRef, err := r.r.Reference(plumbing.ReferenceName(policy.PolicyStagingRef), true)
+	if err != nil {
+		return err
+	}
+
+	justification := &attestations.PolicyJustification{
+		PolicyCommitID: policyStagingRef.Hash().String(),
+		Reason:         reason,
+		Ticket:         ticket,
+		Approver:       approver,
+	}
+
+	logger.Debug("Creating policy justification attestation...")
+	statement, err := attestations.NewPolicyJustificationAttestation(justification)
+	if err != nil {
+		return err
+	}
+
+	env, err := dsse.CreateEnvelope(statement)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := r.loadCurrentAttestationsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	existingEnv, err := allAttestations.GetPolicyJustificationFor(r.r, justification.PolicyCommitID)
+	if err == nil {
+		if dsse.EqualPayloads(existingEnv, env) {
+			logger.Debug("Found existing policy justification...")
+			env = existingEnv
+		}
+	} else if !errors.Is(err, attestations.ErrPolicyJustificationNotFound) {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing policy justification using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if err := allAttestations.SetPolicyJustification(r.r, env, justification.PolicyCommitID); err != nil {
+		return err
+	}
+
+	if err := r.logAttestationToRekor(ctx, allAttestations, env, signer); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add justification for policy '%s'", justification.PolicyCommitID)
+
+	logger.Debug("Committing attestations...")
+	return allAttestations.Commit(r.r, commitMessage, signCommit)
+}
+
+// PolicyLogEntry describes a policy state that was applied, along with the
+// justification recorded for it, if any.
+type PolicyLogEntry struct {
+	RSLEntryID     string
+	PolicyCommitID string
+	Message        string
+
+	// Justification is nil if no justification was recorded for the policy.
+	Justification *attestations.PolicyJustification
+
+	// JustificationSigners lists the IDs of the keys that signed the
+	// justification.
+	JustificationSigners []string
+}
+
+// PolicyLog returns the history of applied policy states, starting with the
+// latest one, along with the justification recorded for each state.
+func (r *Repository) PolicyLog() ([]*PolicyLogEntry, error) {
+	logger.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	log := []*PolicyLogEntry{}
+
+	logger.Debug("Identifying applied policy states...")
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, policy.PolicyRef)
+	for err == nil {
+		var logEntry *PolicyLogEntry
+		logEntry, err = r.getPolicyLogEntry(allAttestations, entry)
+		if err != nil {
+			return nil, err
+		}
+		log = append(log, logEntry)
+
+		entry, _, err = rsl.GetLatestReferenceEntryForRefBefore(r.r, policy.PolicyRef, entry.ID)
+	}
+	if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return nil, err
+	}
+
+	return log, nil
+}
+
+// getPolicyLogEntry returns the policy log entry for the RSL entry that
+// applied a policy state, including the state's justification if one was
+// recorded.
+func (r *Repository) getPolicyLogEntry(allAttestations *attestations.Attestations, entry *rsl.ReferenceEntry) (*PolicyLogEntry, error) {
+	commit, err := gitinterface.GetCommit(r.r, entry.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	logEntry := &PolicyLogEntry{
+		RSLEntryID:     entry.ID.String(),
+		PolicyCommitID: entry.TargetID.String(),
+		Message:        strings.TrimSpace(commit.Message),
+	}
+
+	env, err := allAttestations.GetPolicyJustificationFor(r.r, logEntry.PolicyCommitID)
+	if err == nil {
+		logEntry.Justification, err = attestations.GetPolicyJustification(env)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, signature := range env.Signatures {
+			logEntry.JustificationSigners = append(logEntry.JustificationSigners, signature.KeyID)
+		}
+	} else if !errors.Is(err, attestations.ErrPolicyJustificationNotFound) {
+		return nil, err
+	}
+
+	return logEntry, nil
+}
@@ -6,8 +6,10 @@ import (
 	"context"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
@@ -149,3 +151,110 @@ func TestPullPolicy(t *testing.T) {
 		assert.ErrorIs(t, err, ErrPullingPolicy)
 	})
 }
+
+func TestAddPolicyJustification(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.SetRequirePolicyJustifications(testCtx, rootSigner, true, false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = policy.Apply(testCtx, r.r, false)
+	assert.ErrorIs(t, err, policy.ErrPolicyJustificationMissing)
+
+	err = r.AddPolicyJustification(testCtx, targetsSigner, "Require justifications", "https://example.com/tickets/1", "Alice", false)
+	assert.Nil(t, err)
+
+	// A second signature on the same justification is added to the existing
+	// attestation
+	err = r.AddPolicyJustification(testCtx, rootSigner, "Require justifications", "https://example.com/tickets/1", "Alice", false)
+	assert.Nil(t, err)
+
+	policyStagingRef, err := r.r.Reference(policy.PolicyStagingRef, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := allAttestations.GetPolicyJustificationFor(r.r, policyStagingRef.Hash().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(env.Signatures))
+
+	justification, err := attestations.GetPolicyJustification(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &attestations.PolicyJustification{
+		PolicyCommitID: policyStagingRef.Hash().String(),
+		Reason:         "Require justifications",
+		Ticket:         "https://example.com/tickets/1",
+		Approver:       "Alice",
+	}, justification)
+
+	err = policy.Apply(testCtx, r.r, false)
+	assert.Nil(t, err)
+}
+
+func TestPolicyLog(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	log, err := r.PolicyLog()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(log))
+	for _, entry := range log {
+		assert.Nil(t, entry.Justification)
+		assert.Empty(t, entry.JustificationSigners)
+	}
+
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootKeyID, err := rootSigner.KeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.SetRequirePolicyJustifications(testCtx, rootSigner, true, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddPolicyJustification(testCtx, rootSigner, "Require justifications", "", "", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Apply(testCtx, r.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	policyRef, err := r.r.Reference(policy.PolicyRef, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log, err = r.PolicyLog()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(log))
+
+	assert.Equal(t, policyRef.Hash().String(), log[0].PolicyCommitID)
+	assert.Equal(t, "Require justifications for policy changes", log[0].Message)
+	assert.Equal(t, "Require justifications", log[0].Justification.Reason)
+	assert.Equal(t, []string{rootKeyID}, log[0].JustificationSigners)
+
+	assert.Nil(t, log[1].Justification)
+	assert.Nil(t, log[2].Justification)
+	assert.Equal(t, "Initialize root of trust", log[2].Message)
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// PolicyArtifactVersion is the version of the policy artifact format
+	// produced by ExportPolicyArtifact.
+	PolicyArtifactVersion = 1
+
+	// PolicyArtifactType is the OCI artifact type used when distributing
+	// policy artifacts through registries.
+	PolicyArtifactType = "application/vnd.gittuf.policy.v1+json"
+
+	// PolicyArtifactMediaType is the media type of policy artifacts.
+	PolicyArtifactMediaType = "application/vnd.gittuf.policy.state.v1+json"
+)
+
+var (
+	ErrInvalidPolicyArtifact          = errors.New("invalid policy artifact")
+	ErrUnsupportedPolicyArtifact      = errors.New("unsupported policy artifact version")
+	ErrPolicyArtifactRootKeysMismatch = errors.New("root keys in policy artifact do not match the expected keys")
+)
+
+// PolicyArtifact is a portable copy of a repository's trust root and policy.
+// It allows the policy to be distributed outside of Git, such as through OCI
+// registries, to bootstrap and update gittuf's root of trust in environments
+// that cannot fetch the repository's gittuf refs directly.
+type PolicyArtifact struct {
+	Version int           `json:"version"`
+	Policy  *policy.State `json:"policy"`
+}
+
+// ExportPolicyArtifact creates a policy artifact with the repository's current
+// policy.
+func (r *Repository) ExportPolicyArtifact(ctx context.Context) ([]byte, error) {
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(&PolicyArtifact{Version: PolicyArtifactVersion, Policy: state}, "", "  ")
+}
+
+// ImportPolicyArtifact verifies the policy artifact and applies its policy to
+// the repository. If the repository has a policy, the artifact's policy must
+// be trusted by it. Otherwise, the repository's root of trust is bootstrapped
+// from the artifact. If expectedRootKeys are specified, the artifact's root
+// keys must match them, otherwise the artifact's root keys are trusted on
+// first use. It returns false if the artifact's policy is already the
+// repository's current policy.
+func (r *Repository) ImportPolicyArtifact(ctx context.Context, artifactContents []byte, expectedRootKeys []*tuf.Key, signCommit bool) (bool, error) {
+	artifact := &PolicyArtifact{}
+	if err := json.Unmarshal(artifactContents, artifact); err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidPolicyArtifact, err)
+	}
+
+	if artifact.Version != PolicyArtifactVersion {
+		return false, fmt.Errorf("%w: %d", ErrUnsupportedPolicyArtifact, artifact.Version)
+	}
+
+	if artifact.Policy == nil || artifact.Policy.RootEnvelope == nil {
+		return false, fmt.Errorf("%w: artifact does not contain policy", ErrInvalidPolicyArtifact)
+	}
+	state := artifact.Policy
+
+	logger.Debug("Verifying artifact's policy...")
+	if err := state.Verify(ctx); err != nil {
+		return false, fmt.Errorf("artifact's policy has invalidly signed metadata: %w", err)
+	}
+
+	if len(expectedRootKeys) > 0 {
+		logger.Debug("Verifying if root keys are expected root keys...")
+		match, err := rootKeysMatch(state, expectedRootKeys)
+		if err != nil {
+			return false, err
+		}
+		if !match {
+			return false, ErrPolicyArtifactRootKeysMismatch
+		}
+	}
+
+	logger.Debug("Loading current policy...")
+	currentState, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	switch {
+	case err == nil:
+		logger.Debug("Verifying artifact's policy against current policy...")
+		if isSamePolicy(currentState, state) {
+			return false, nil
+		}
+
+		if err := currentState.VerifyNewState(ctx, state); err != nil {
+			return false, fmt.Errorf("artifact's policy is not trusted by repository's policy: %w", err)
+		}
+	case errors.Is(err, rsl.ErrRSLEntryNotFound), errors.Is(err, plumbing.ErrReferenceNotFound):
+		logger.Debug("Repository has no policy, bootstrapping root of trust from artifact...")
+		if err := policy.InitializeNamespace(r.r); err != nil && !errors.Is(err, policy.ErrPolicyExists) {
+			return false, err
+		}
+	default:
+		return false, err
+	}
+
+	logger.Debug("Committing policy...")
+	if err := state.Commit(ctx, r.r, "Import policy from artifact", signCommit); err != nil {
+		return false, err
+	}
+
+	logger.Debug("Applying policy...")
+	if err := policy.Apply(ctx, r.r, signCommit); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// isSamePolicy checks whether both policy states have the same metadata.
+func isSamePolicy(state1, state2 *policy.State) bool {
+	contents1, err := json.Marshal(state1)
+	if err != nil {
+		return false
+	}
+	contents2, err := json.Marshal(state2)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(contents1, contents2)
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyArtifact(t *testing.T) {
+	rootPublicKey, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sourceRepo := createTestRepositoryWithPolicy(t, "")
+	artifactContents, err := sourceRepo.ExportPolicyArtifact(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRepository := func(t *testing.T) *Repository {
+		t.Helper()
+
+		r, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &Repository{r: r}
+	}
+
+	t.Run("bootstrap and update", func(t *testing.T) {
+		repo := newRepository(t)
+
+		imported, err := repo.ImportPolicyArtifact(testCtx, artifactContents, []*tuf.Key{rootPublicKey}, false)
+		assert.Nil(t, err)
+		assert.True(t, imported)
+
+		state, err := policy.LoadCurrentState(testCtx, repo.r, policy.PolicyRef)
+		assert.Nil(t, err)
+		sourceState, err := policy.LoadCurrentState(testCtx, sourceRepo.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, isSamePolicy(sourceState, state))
+
+		// Importing the same policy again is a no-op
+		imported, err = repo.ImportPolicyArtifact(testCtx, artifactContents, nil, false)
+		assert.Nil(t, err)
+		assert.False(t, imported)
+	})
+
+	t.Run("unexpected root keys", func(t *testing.T) {
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = newRepository(t).ImportPolicyArtifact(testCtx, artifactContents, []*tuf.Key{gpgKey}, false)
+		assert.ErrorIs(t, err, ErrPolicyArtifactRootKeysMismatch)
+	})
+
+	t.Run("invalid artifact", func(t *testing.T) {
+		repo := newRepository(t)
+
+		_, err := repo.ImportPolicyArtifact(testCtx, []byte("not json"), nil, false)
+		assert.ErrorIs(t, err, ErrInvalidPolicyArtifact)
+
+		_, err = repo.ImportPolicyArtifact(testCtx, []byte(`{"version": 2}`), nil, false)
+		assert.ErrorIs(t, err, ErrUnsupportedPolicyArtifact)
+
+		_, err = repo.ImportPolicyArtifact(testCtx, []byte(`{"version": 1}`), nil, false)
+		assert.ErrorIs(t, err, ErrInvalidPolicyArtifact)
+	})
+}
@@ -0,0 +1,396 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+var (
+	ErrInvalidRefUpdate        = errors.New("invalid ref update, expected '<old-id> <new-id> <ref>'")
+	ErrGittufRefDeleted        = errors.New("gittuf refs cannot be deleted")
+	ErrRSLNotFastForward       = errors.New("RSL update is not a fast-forward of the current RSL")
+	ErrProtectedRefDeleted     = errors.New("ref is protected by policy and cannot be deleted")
+	ErrRefUpdateNotRecordedRSL = errors.New("ref update is not recorded in the RSL")
+	ErrUnreferencedPushObjects = errors.New("push contains objects that aren't reachable from the updated refs")
+	ErrUnknownGittufRef        = errors.New("ref in the gittuf namespace is not known to gittuf")
+)
+
+// RefUpdate is a proposed update to a ref in a push, as passed to Git's
+// pre-receive hook.
+type RefUpdate struct {
+	OldID   plumbing.Hash
+	NewID   plumbing.Hash
+	RefName string
+}
+
+// IsDeletion returns true if the update deletes the ref.
+func (u *RefUpdate) IsDeletion() bool {
+	return u.NewID.IsZero()
+}
+
+// ParseRefUpdates reads the ref updates of a push in the format Git passes
+// them to the pre-receive hook, one "<old-id> <new-id> <ref>" per line.
+func ParseRefUpdates(reader io.Reader) ([]*RefUpdate, error) {
+	updates := []*RefUpdate{}
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidRefUpdate, line)
+		}
+
+		oldID, err := gitinterface.ParseObjectID(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: '%s': %w", ErrInvalidRefUpdate, line, err)
+		}
+		newID, err := gitinterface.ParseObjectID(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: '%s': %w", ErrInvalidRefUpdate, line, err)
+		}
+
+		updates = append(updates, &RefUpdate{
+			OldID:   oldID,
+			NewID:   newID,
+			RefName: fields[2],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+// VerifyPush verifies the proposed ref updates of a push against the
+// repository's policy before they are accepted. It's intended to be used by
+// the pre-receive hook of a Git server, where the pushed objects are only
+// available in the quarantine object directory set in the environment. The
+// pushed objects are checked first, see checkPushObjects, and an error is
+// returned if they can't be accepted. The updates are then verified as if they
+// had been applied, including the RSL entries pushed alongside them. The
+// returned map contains the verification error for each rejected ref, and is
+// empty if the push can be accepted.
+func (r *Repository) VerifyPush(ctx context.Context, updates []*RefUpdate) (map[string]error, error) {
+	logger.Debug("Loading proposed state of repository...")
+	candidate, cleanup, err := r.loadProposedRepository(updates)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup() //nolint:errcheck
+
+	if quarantineDir := os.Getenv(gitinterface.QuarantinePathKey); quarantineDir != "" {
+		logger.Debug("Checking pushed objects...")
+		if err := r.checkPushObjects(candidate, quarantineDir, updates); err != nil {
+			return nil, err
+		}
+	} else {
+		logger.Debug("Pushed objects are not quarantined, skipping checks of pushed objects...")
+	}
+
+	rejected := map[string]error{}
+	for _, update := range updates {
+		logger.Debug(fmt.Sprintf("Verifying update of '%s' from '%s' to '%s'...", update.RefName, update.OldID.String(), update.NewID.String()))
+
+		var err error
+		if strings.HasPrefix(update.RefName, gittufNamespacePrefix) {
+			err = candidate.verifyGittufRefUpdate(ctx, update)
+		} else {
+			err = candidate.verifyRefUpdate(ctx, update)
+		}
+		if err != nil {
+			rejected[update.RefName] = err
+		}
+	}
+
+	return rejected, nil
+}
+
+// checkPushObjects checks the objects of a push, which Git stores in the
+// quarantine directory until the push is accepted, rather than only the
+// objects reachable from the proposed refs. Each object must be intact, see
+// gitinterface.CheckObject, and each object that isn't already in the
+// repository must be reachable from the proposed tip of an updated ref in the
+// candidate repository. Objects that aren't reachable from an update aren't
+// verified along with the updates, so they're rejected rather than being added
+// to the repository where they may be referenced later. Note that Git adds the
+// existing objects that deltas in a thin pack are based on to the pack.
+func (r *Repository) checkPushObjects(candidate *Repository, quarantineDir string, updates []*RefUpdate) error {
+	objectIDs, err := gitinterface.GetObjectsInObjectDir(quarantineDir)
+	if err != nil {
+		return err
+	}
+
+	unreferenced := make(map[plumbing.Hash]bool, len(objectIDs))
+	for _, objectID := range objectIDs {
+		if err := gitinterface.CheckObject(candidate.r, objectID); err != nil {
+			return err
+		}
+		if r.r.Storer.HasEncodedObject(objectID) != nil {
+			unreferenced[objectID] = true
+		}
+	}
+
+	// The walk stops at objects that were already in the repository, as the
+	// objects reachable from them must be too
+	queue := []plumbing.Hash{}
+	for _, update := range updates {
+		if !update.IsDeletion() {
+			queue = append(queue, update.NewID)
+		}
+	}
+	for len(queue) != 0 && len(unreferenced) != 0 {
+		objectID := queue[0]
+		queue = queue[1:]
+
+		if !unreferenced[objectID] {
+			continue
+		}
+		delete(unreferenced, objectID)
+
+		obj, err := candidate.r.Storer.EncodedObject(plumbing.AnyObject, objectID)
+		if err != nil {
+			return err
+		}
+
+		switch obj.Type() {
+		case plumbing.CommitObject:
+			commit, err := gitinterface.GetCommit(candidate.r, objectID)
+			if err != nil {
+				return err
+			}
+			queue = append(queue, commit.TreeHash)
+			queue = append(queue, commit.ParentHashes...)
+		case plumbing.TreeObject:
+			tree, err := gitinterface.GetTree(candidate.r, objectID)
+			if err != nil {
+				return err
+			}
+			for _, entry := range tree.Entries {
+				if entry.Mode != filemode.Submodule {
+					queue = append(queue, entry.Hash)
+				}
+			}
+		case plumbing.TagObject:
+			tag, err := gitinterface.GetTag(candidate.r, objectID)
+			if err != nil {
+				return err
+			}
+			queue = append(queue, tag.Target)
+		}
+	}
+
+	if len(unreferenced) != 0 {
+		return fmt.Errorf("%w: %d objects", ErrUnreferencedPushObjects, len(unreferenced))
+	}
+
+	return nil
+}
+
+// verifyRefUpdate verifies an update to a ref outside the gittuf namespace.
+// The proposed tip of the ref must be recorded in the RSL and must pass
+// verification. A ref protected by policy may not be deleted.
+func (r *Repository) verifyRefUpdate(ctx context.Context, update *RefUpdate) error {
+	if update.IsDeletion() {
+		verifiers, err := r.findVerifiersForPath(ctx, policy.PolicyRef, fmt.Sprintf("git:%s", update.RefName))
+		if err != nil {
+			return err
+		}
+		if len(verifiers) > 0 {
+			return ErrProtectedRefDeleted
+		}
+
+		return nil
+	}
+
+	return r.VerifyRef(ctx, update.RefName, false)
+}
+
+// verifyGittufRefUpdate verifies an update to a ref in the gittuf namespace.
+// gittuf refs may not be deleted, and the RSL may not be rewritten. Updates to
+// the policy and attestations must be recorded in the RSL, and the policy must
+// be verifiable from its root of trust. The policy staging ref is not verified,
+// as it holds policy changes that haven't been applied yet. Updates to other
+// refs in the gittuf namespace are rejected.
+func (r *Repository) verifyGittufRefUpdate(ctx context.Context, update *RefUpdate) error {
+	if update.IsDeletion() {
+		return ErrGittufRefDeleted
+	}
+
+	switch update.RefName {
+	case rsl.Ref:
+		if update.OldID.IsZero() {
+			return nil
+		}
+
+		oldTip, err := gitinterface.GetCommit(r.r, update.OldID)
+		if err != nil {
+			return err
+		}
+
+		knows, err := gitinterface.KnowsCommit(r.r, update.NewID, oldTip)
+		if err != nil {
+			return err
+		}
+		if !knows {
+			return ErrRSLNotFastForward
+		}
+
+		return nil
+
+	case policy.PolicyRef:
+		if _, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef); err != nil {
+			return err
+		}
+
+		return r.verifyRefUpdateRecorded(update)
+
+	case policy.PolicyStagingRef:
+		return nil
+
+	case attestations.Ref:
+		return r.verifyRefUpdateRecorded(update)
+	}
+
+	return fmt.Errorf("%w: '%s'", ErrUnknownGittufRef, update.RefName)
+}
+
+func (r *Repository) verifyRefUpdateRecorded(update *RefUpdate) error {
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, update.RefName)
+	if err != nil {
+		return err
+	}
+
+	if entry.TargetID != update.NewID {
+		return ErrRefUpdateNotRecordedRSL
+	}
+
+	return nil
+}
+
+// loadProposedRepository returns a view of the repository with the proposed
+// ref updates applied. The refs are stored in a temporary directory that uses
+// the repository's object directories as alternates, including the quarantine
+// object directory Git uses for the pushed objects during the pre-receive
+// hook. The returned function removes the temporary directory.
+func (r *Repository) loadProposedRepository(updates []*RefUpdate) (*Repository, func() error, error) {
+	commonDir, err := gitinterface.GetGitCommonDirFor(r.r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to verify push in repository that isn't stored on disk")
+	}
+
+	objectDirs := []string{}
+	if objectDir := os.Getenv("GIT_OBJECT_DIRECTORY"); objectDir != "" {
+		objectDirs = append(objectDirs, objectDir)
+	}
+	if alternateDirs := os.Getenv("GIT_ALTERNATE_OBJECT_DIRECTORIES"); alternateDirs != "" {
+		objectDirs = append(objectDirs, filepath.SplitList(alternateDirs)...)
+	}
+	objectDirs = append(objectDirs, filepath.Join(commonDir, "objects"))
+
+	tmpDir, err := os.MkdirTemp("", "gittuf-verify-push-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() error {
+		return os.RemoveAll(tmpDir)
+	}
+
+	candidate, err := createProposedRepository(tmpDir, objectDirs, r.r, updates)
+	if err != nil {
+		cleanup() //nolint:errcheck
+		return nil, nil, err
+	}
+
+	return &Repository{r: candidate}, cleanup, nil
+}
+
+func createProposedRepository(dir string, objectDirs []string, repo *git.Repository, updates []*RefUpdate) (*git.Repository, error) {
+	// Alternates are expected to be named "objects", so each object directory
+	// is linked into the temporary directory under that name
+	alternates := []string{}
+	for i, objectDir := range objectDirs {
+		objectDir, err := filepath.Abs(objectDir)
+		if err != nil {
+			return nil, err
+		}
+
+		linkDir := filepath.Join(dir, "alternates", strconv.Itoa(i))
+		if err := os.MkdirAll(linkDir, 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.Symlink(objectDir, filepath.Join(linkDir, "objects")); err != nil {
+			return nil, err
+		}
+		alternates = append(alternates, filepath.Join(linkDir, "objects"))
+	}
+
+	infoDir := filepath.Join(dir, "objects", "info")
+	if err := os.MkdirAll(infoDir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(infoDir, "alternates"), []byte(strings.Join(alternates, "\n")+"\n"), 0o600); err != nil {
+		return nil, err
+	}
+
+	storage := filesystem.NewStorageWithOptions(osfs.New(dir), cache.NewObjectLRUDefault(), filesystem.Options{AlternatesFS: osfs.New("/")})
+
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.SetReference(head); err != nil {
+		return nil, err
+	}
+
+	refs, err := repo.Storer.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+	if err := refs.ForEach(func(ref *plumbing.Reference) error {
+		return storage.SetReference(ref)
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, update := range updates {
+		refName := plumbing.ReferenceName(update.RefName)
+		if update.IsDeletion() {
+			if err := storage.RemoveReference(refName); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := storage.SetReference(plumbing.NewHashReference(refName, update.NewID)); err != nil {
+			return nil, err
+		}
+	}
+
+	return git.Open(storage, nil)
+}
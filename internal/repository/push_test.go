@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRefUpdates(t *testing.T) {
+	oldID := "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"
+	newID := "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e"
+
+	t.Run("valid updates", func(t *testing.T) {
+		input := strings.Join([]string{
+			oldID + " " + newID + " refs/heads/main",
+			"",
+			plumbing.ZeroHash.String() + " " + newID + " refs/gittuf/reference-state-log",
+		}, "\n")
+
+		updates, err := ParseRefUpdates(strings.NewReader(input))
+		assert.Nil(t, err)
+		assert.Equal(t, []*RefUpdate{
+			{OldID: plumbing.NewHash(oldID), NewID: plumbing.NewHash(newID), RefName: "refs/heads/main"},
+			{OldID: plumbing.ZeroHash, NewID: plumbing.NewHash(newID), RefName: "refs/gittuf/reference-state-log"},
+		}, updates)
+		assert.False(t, updates[0].IsDeletion())
+	})
+
+	t.Run("deletion", func(t *testing.T) {
+		updates, err := ParseRefUpdates(strings.NewReader(oldID + " " + plumbing.ZeroHash.String() + " refs/heads/feature\n"))
+		assert.Nil(t, err)
+		assert.True(t, updates[0].IsDeletion())
+	})
+
+	t.Run("invalid update", func(t *testing.T) {
+		_, err := ParseRefUpdates(strings.NewReader(oldID + " refs/heads/main\n"))
+		assert.ErrorIs(t, err, ErrInvalidRefUpdate)
+
+		_, err = ParseRefUpdates(strings.NewReader("not-a-hash " + newID + " refs/heads/main\n"))
+		assert.ErrorIs(t, err, ErrInvalidRefUpdate)
+	})
+
+	t.Run("unsupported object format", func(t *testing.T) {
+		sha256ID := strings.Repeat("ab", 32)
+		_, err := ParseRefUpdates(strings.NewReader(oldID + " " + sha256ID + " refs/heads/main\n"))
+		assert.ErrorIs(t, err, ErrInvalidRefUpdate)
+		assert.ErrorIs(t, err, gitinterface.ErrUnsupportedObjectFormat)
+	})
+}
+
+func TestVerifyPush(t *testing.T) {
+	refName := "refs/heads/main"
+
+	// pushUpdates creates commits for refName and a matching RSL entry, and
+	// then restores refName and the RSL to their prior state, as if the
+	// objects had been pushed but the refs not yet updated
+	pushUpdates := func(t *testing.T, repo *Repository, keyBytes []byte) []*RefUpdate {
+		t.Helper()
+
+		rslTip, err := repo.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, keyBytes)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), keyBytes)
+
+		if err := repo.r.Storer.RemoveReference(plumbing.ReferenceName(refName)); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.r.Storer.SetReference(rslTip); err != nil {
+			t.Fatal(err)
+		}
+
+		return []*RefUpdate{
+			{OldID: plumbing.ZeroHash, NewID: commitIDs[0], RefName: refName},
+			{OldID: rslTip.Hash(), NewID: entryID, RefName: rsl.Ref},
+		}
+	}
+
+	// quarantine moves the loose objects created since existingObjectIDs were
+	// listed into a separate object directory, as Git stores the objects of a
+	// push in a quarantine directory until the pre-receive hook accepts it
+	quarantine := func(t *testing.T, repoDir string, existingObjectIDs []plumbing.Hash) string {
+		t.Helper()
+
+		objectIDs, err := gitinterface.GetObjectsInObjectDir(filepath.Join(repoDir, "objects"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		quarantineDir := t.TempDir()
+		for _, objectID := range objectIDs {
+			if slices.Contains(existingObjectIDs, objectID) {
+				continue
+			}
+
+			objectPath := filepath.Join(objectID.String()[:2], objectID.String()[2:])
+			if err := os.MkdirAll(filepath.Join(quarantineDir, filepath.Dir(objectPath)), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Rename(filepath.Join(repoDir, "objects", objectPath), filepath.Join(quarantineDir, objectPath)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		t.Setenv(gitinterface.QuarantinePathKey, quarantineDir)
+		t.Setenv("GIT_OBJECT_DIRECTORY", quarantineDir)
+
+		return quarantineDir
+	}
+
+	t.Run("quarantined push", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo := createTestRepositoryWithPolicy(t, tmpDir)
+		existingObjectIDs, err := gitinterface.GetObjectsInObjectDir(filepath.Join(tmpDir, "objects"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		updates := pushUpdates(t, repo, gpgKeyBytes)
+		quarantine(t, tmpDir, existingObjectIDs)
+
+		rejected, err := repo.VerifyPush(testCtx, updates)
+		assert.Nil(t, err)
+		assert.Empty(t, rejected)
+	})
+
+	t.Run("quarantined push with unreferenced object", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo := createTestRepositoryWithPolicy(t, tmpDir)
+		existingObjectIDs, err := gitinterface.GetObjectsInObjectDir(filepath.Join(tmpDir, "objects"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		updates := pushUpdates(t, repo, gpgKeyBytes)
+		if _, err := gitinterface.WriteBlob(repo.r, []byte("unreferenced")); err != nil {
+			t.Fatal(err)
+		}
+		quarantine(t, tmpDir, existingObjectIDs)
+
+		_, err = repo.VerifyPush(testCtx, updates)
+		assert.ErrorIs(t, err, ErrUnreferencedPushObjects)
+	})
+
+	t.Run("quarantined push with corrupt object", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo := createTestRepositoryWithPolicy(t, tmpDir)
+		existingObjectIDs, err := gitinterface.GetObjectsInObjectDir(filepath.Join(tmpDir, "objects"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		updates := pushUpdates(t, repo, gpgKeyBytes)
+		quarantineDir := quarantine(t, tmpDir, existingObjectIDs)
+
+		// Replace the pushed commit with the contents of an existing object
+		existingObjectPath := filepath.Join(tmpDir, "objects", existingObjectIDs[0].String()[:2], existingObjectIDs[0].String()[2:])
+		contents, err := os.ReadFile(existingObjectPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pushedObjectPath := filepath.Join(quarantineDir, updates[0].NewID.String()[:2], updates[0].NewID.String()[2:])
+		if err := os.Chmod(pushedObjectPath, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(pushedObjectPath, contents, 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+
+		_, err = repo.VerifyPush(testCtx, updates)
+		assert.ErrorIs(t, err, gitinterface.ErrCorruptObject)
+	})
+
+	t.Run("authorized push", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, t.TempDir())
+		updates := pushUpdates(t, repo, gpgKeyBytes)
+
+		rejected, err := repo.VerifyPush(testCtx, updates)
+		assert.Nil(t, err)
+		assert.Empty(t, rejected)
+
+		// The repository's refs are not updated
+		_, err = repo.r.Reference(plumbing.ReferenceName(refName), true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+
+	t.Run("unauthorized push", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, t.TempDir())
+		updates := pushUpdates(t, repo, gpgUnauthorizedKeyBytes)
+
+		rejected, err := repo.VerifyPush(testCtx, updates)
+		assert.Nil(t, err)
+		assert.Len(t, rejected, 1)
+		assert.NotNil(t, rejected[refName])
+	})
+
+	t.Run("push without RSL entry", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, t.TempDir())
+		updates := pushUpdates(t, repo, gpgKeyBytes)
+
+		rejected, err := repo.VerifyPush(testCtx, updates[:1])
+		assert.Nil(t, err)
+		assert.ErrorIs(t, rejected[refName], rsl.ErrRSLEntryNotFound)
+	})
+
+	t.Run("RSL rewritten", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, t.TempDir())
+
+		rslTip, err := repo.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rslTipCommit, err := gitinterface.GetCommit(repo.r, rslTip.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rejected, err := repo.VerifyPush(testCtx, []*RefUpdate{
+			{OldID: rslTip.Hash(), NewID: rslTipCommit.ParentHashes[0], RefName: rsl.Ref},
+		})
+		assert.Nil(t, err)
+		assert.ErrorIs(t, rejected[rsl.Ref], ErrRSLNotFastForward)
+	})
+
+	t.Run("unknown gittuf ref", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, t.TempDir())
+		updates := pushUpdates(t, repo, gpgKeyBytes)
+
+		rejected, err := repo.VerifyPush(testCtx, []*RefUpdate{
+			{OldID: plumbing.ZeroHash, NewID: updates[0].NewID, RefName: "refs/gittuf/unknown"},
+		})
+		assert.Nil(t, err)
+		assert.ErrorIs(t, rejected["refs/gittuf/unknown"], ErrUnknownGittufRef)
+	})
+
+	t.Run("deletions", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, t.TempDir())
+		updates := pushUpdates(t, repo, gpgKeyBytes)
+
+		rslTip, err := repo.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), updates[0].NewID)); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference("refs/heads/feature", updates[0].NewID)); err != nil {
+			t.Fatal(err)
+		}
+
+		rejected, err := repo.VerifyPush(testCtx, []*RefUpdate{
+			{OldID: updates[0].NewID, NewID: plumbing.ZeroHash, RefName: refName},
+			{OldID: updates[0].NewID, NewID: plumbing.ZeroHash, RefName: "refs/heads/feature"},
+		})
+		assert.Nil(t, err)
+		assert.Len(t, rejected, 1)
+		assert.ErrorIs(t, rejected[refName], ErrProtectedRefDeleted)
+
+		rejected, err = repo.VerifyPush(testCtx, []*RefUpdate{
+			{OldID: rslTip.Hash(), NewID: plumbing.ZeroHash, RefName: rsl.Ref},
+		})
+		assert.Nil(t, err)
+		assert.ErrorIs(t, rejected[rsl.Ref], ErrGittufRefDeleted)
+	})
+}
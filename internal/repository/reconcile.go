@@ -0,0 +1,363 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var (
+	ErrReconcilingRSL = errors.New("unable to reconcile local and remote RSLs")
+	ErrRSLConflicts   = errors.New("local RSL entries conflict with the remote's RSL and must be resolved manually")
+)
+
+// RSLReconciliation describes how the local RSL was reconciled with a remote's
+// RSL.
+type RSLReconciliation struct {
+	// Remote is the remote the local RSL was reconciled with.
+	Remote string
+
+	// Status is how the local RSL related to the remote's RSL before it was
+	// reconciled. Entries are only replayed if the two had diverged.
+	Status RSLSyncStatus
+
+	// PreviousTip and RemoteTip are the tips of the local and remote RSLs
+	// before reconciliation. The local entries after the two RSLs diverged
+	// remain reachable from PreviousTip.
+	PreviousTip plumbing.Hash
+	RemoteTip   plumbing.Hash
+
+	// Replayed lists the local entries that were recorded again on top of
+	// the remote's RSL, in the order they were recorded.
+	Replayed []*ReplayedRSLEntry
+
+	// Conflicts lists the local entries that were not recorded again and
+	// must be resolved manually.
+	Conflicts []*RSLConflict
+}
+
+// ReplayedRSLEntry is a local RSL entry that was recorded again on top of a
+// remote's RSL.
+type ReplayedRSLEntry struct {
+	// OriginalID is the ID of the local entry and NewID is the ID of the entry
+	// that replaced it. If the remote's RSL already records the same change,
+	// NewID is the ID of the remote's entry.
+	OriginalID plumbing.Hash
+	NewID      plumbing.Hash
+
+	// RefName is the ref the entry is for, and is empty for annotations.
+	RefName string
+}
+
+// RSLConflict is a local RSL entry that could not be recorded again on top of
+// a remote's RSL.
+type RSLConflict struct {
+	EntryID plumbing.Hash
+
+	// RefName is the ref the entry is for, and is empty for annotations.
+	RefName string
+
+	// Reason explains why the entry was not recorded again.
+	Reason error
+}
+
+var (
+	errRefUpdatedRemotely    = errors.New("the ref was also updated in the remote's RSL")
+	errEarlierEntryConflicts = errors.New("an earlier local entry for the ref conflicts")
+	errAnnotatedEntryDropped = errors.New("the annotation refers to a local entry that could not be replayed")
+)
+
+// ReconcileRSL fetches the remote's RSL and reconciles the local RSL with it.
+// If the remote's RSL is ahead, the local RSL is fast-forwarded to it. If the
+// two have diverged, the local RSL is reset to the remote's and the local
+// entries that the remote doesn't have are recorded again on top of it,
+// signed using the user's key. A local entry for a ref is not recorded again
+// if the remote's RSL also records changes to the ref, or if the new entry
+// doesn't meet the policy, and is instead reported as a conflict for the user
+// to resolve. Later local entries for the same ref and annotations of entries
+// that are not recorded again are reported as conflicts too. The local RSL is
+// not pushed to the remote.
+func (r *Repository) ReconcileRSL(ctx context.Context, remoteName string, signCommit bool) (*RSLReconciliation, error) {
+	// The remote's policy and attestations are fetched along with its RSL as
+	// they're needed to verify the replayed entries
+	logger.Debug(fmt.Sprintf("Fetching gittuf refs from '%s'...", remoteName))
+	trackerRefSpec := config.RefSpec(fmt.Sprintf("+%s:refs/remotes/%s/gittuf/*", gittufRefs, remoteName))
+	if err := gitinterface.FetchRefSpec(ctx, r.r, remoteName, []config.RefSpec{trackerRefSpec}); err != nil {
+		return nil, errors.Join(ErrReconcilingRSL, err)
+	}
+
+	remoteTip, err := gitinterface.GetTip(r.r, rsl.RemoteTrackerRef(remoteName))
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil, ErrRemoteRSLNotFound
+		}
+		return nil, err
+	}
+	if remoteTip.IsZero() {
+		return nil, ErrRemoteRSLNotFound
+	}
+
+	tips, err := r.getTips([]string{rsl.Ref})
+	if err != nil {
+		return nil, err
+	}
+	reconciliation := &RSLReconciliation{
+		Remote:      remoteName,
+		PreviousTip: tips[rsl.Ref],
+		RemoteTip:   remoteTip,
+	}
+
+	remoteAhead, err := r.isRemoteRSLAhead(remoteTip)
+	switch {
+	case errors.Is(err, ErrRSLDiverged):
+		reconciliation.Status = RSLSyncStatusDiverged
+	case err != nil:
+		return nil, err
+	case remoteAhead:
+		reconciliation.Status = RSLSyncStatusBehind
+	case tips[rsl.Ref] == remoteTip:
+		reconciliation.Status = RSLSyncStatusUpToDate
+		return reconciliation, nil
+	default:
+		// The remote can be updated by pushing the local RSL
+		reconciliation.Status = RSLSyncStatusAhead
+		return reconciliation, nil
+	}
+
+	logger.Debug("Checking integrity of fetched objects...")
+	if err := r.checkFetchedObjects(tips[rsl.Ref], remoteTip); err != nil {
+		return nil, errors.Join(ErrReconcilingRSL, err)
+	}
+
+	if err := r.r.Storer.SetReference(plumbing.NewHashReference(rsl.Ref, remoteTip)); err != nil {
+		return nil, err
+	}
+
+	if reconciliation.Status == RSLSyncStatusDiverged {
+		if err := r.replayRSLEntries(ctx, reconciliation, signCommit); err != nil {
+			return nil, r.restoreTips(tips, errors.Join(ErrReconcilingRSL, err))
+		}
+	}
+
+	if err := r.updateGittufRefsToRSL(); err != nil {
+		return nil, r.restoreTips(tips, err)
+	}
+
+	return reconciliation, nil
+}
+
+// replayRSLEntries records the local entries after the local and remote RSLs
+// diverged on top of the remote's RSL, which the local RSL must already be
+// reset to.
+func (r *Repository) replayRSLEntries(ctx context.Context, reconciliation *RSLReconciliation, signCommit bool) error {
+	localEntries, err := r.getEntriesUnknownTo(reconciliation.PreviousTip, reconciliation.RemoteTip)
+	if err != nil {
+		return err
+	}
+	remoteEntries, err := r.getEntriesUnknownTo(reconciliation.RemoteTip, reconciliation.PreviousTip)
+	if err != nil {
+		return err
+	}
+
+	// The latest entry for each ref updated in the remote's RSL after the two
+	// RSLs diverged
+	remoteUpdates := map[string]*rsl.ReferenceEntry{}
+	for _, entry := range remoteEntries {
+		if referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry); isReferenceEntry {
+			remoteUpdates[referenceEntry.RefName] = referenceEntry
+		}
+	}
+
+	replacedIDs := map[plumbing.Hash]plumbing.Hash{}
+	droppedIDs := map[plumbing.Hash]bool{}
+	conflictedRefs := map[string]bool{}
+
+	for _, entry := range localEntries {
+		switch entry := entry.(type) {
+		case *rsl.ReferenceEntry:
+			var reason error
+			switch remoteUpdate, updatedRemotely := remoteUpdates[entry.RefName]; {
+			case conflictedRefs[entry.RefName]:
+				reason = errEarlierEntryConflicts
+			case updatedRemotely && remoteUpdate.TargetID == entry.TargetID:
+				logger.Debug(fmt.Sprintf("Remote RSL already records '%s' at '%s', skipping entry '%s'...", entry.RefName, entry.TargetID.String(), entry.ID.String()))
+				replacedIDs[entry.ID] = remoteUpdate.ID
+				reconciliation.Replayed = append(reconciliation.Replayed, &ReplayedRSLEntry{OriginalID: entry.ID, NewID: remoteUpdate.ID, RefName: entry.RefName})
+				continue
+			case updatedRemotely:
+				reason = errRefUpdatedRemotely
+			default:
+				newID, err := r.replayReferenceEntry(ctx, entry, signCommit)
+				if err != nil {
+					var verificationErr *replayVerificationError
+					if !errors.As(err, &verificationErr) {
+						return err
+					}
+					reason = verificationErr.err
+					break
+				}
+
+				replacedIDs[entry.ID] = newID
+				reconciliation.Replayed = append(reconciliation.Replayed, &ReplayedRSLEntry{OriginalID: entry.ID, NewID: newID, RefName: entry.RefName})
+				continue
+			}
+
+			logger.Debug(fmt.Sprintf("Unable to replay entry '%s' for '%s': %s", entry.ID.String(), entry.RefName, reason.Error()))
+			conflictedRefs[entry.RefName] = true
+			droppedIDs[entry.ID] = true
+			reconciliation.Conflicts = append(reconciliation.Conflicts, &RSLConflict{EntryID: entry.ID, RefName: entry.RefName, Reason: reason})
+
+		case *rsl.AnnotationEntry:
+			entryIDs := make([]plumbing.Hash, 0, len(entry.RSLEntryIDs))
+			dropped := false
+			for _, entryID := range entry.RSLEntryIDs {
+				if droppedIDs[entryID] {
+					dropped = true
+					break
+				}
+				if newID, replaced := replacedIDs[entryID]; replaced {
+					entryID = newID
+				}
+				entryIDs = append(entryIDs, entryID)
+			}
+			if dropped {
+				droppedIDs[entry.ID] = true
+				reconciliation.Conflicts = append(reconciliation.Conflicts, &RSLConflict{EntryID: entry.ID, Reason: errAnnotatedEntryDropped})
+				continue
+			}
+
+			logger.Debug(fmt.Sprintf("Replaying annotation '%s'...", entry.ID.String()))
+			if err := rsl.NewAnnotationEntry(entryIDs, entry.Skip, entry.Message).Commit(r.r, signCommit); err != nil {
+				return err
+			}
+			if err := r.timestampLatestRSLEntry(signCommit); err != nil {
+				return err
+			}
+
+			latestEntry, err := rsl.GetLatestEntry(r.r)
+			if err != nil {
+				return err
+			}
+			replacedIDs[entry.ID] = latestEntry.GetID()
+			reconciliation.Replayed = append(reconciliation.Replayed, &ReplayedRSLEntry{OriginalID: entry.ID, NewID: latestEntry.GetID()})
+		}
+	}
+
+	return nil
+}
+
+// replayVerificationError is returned by replayReferenceEntry when the
+// replayed entry doesn't meet the policy.
+type replayVerificationError struct {
+	err error
+}
+
+func (e *replayVerificationError) Error() string {
+	return e.err.Error()
+}
+
+// replayReferenceEntry records the reference entry again at the tip of the
+// local RSL and verifies it against the policy, if the repository has one. If
+// verification fails, the new entry is removed.
+func (r *Repository) replayReferenceEntry(ctx context.Context, entry *rsl.ReferenceEntry, signCommit bool) (plumbing.Hash, error) {
+	tips, err := r.getTips([]string{rsl.Ref})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	logger.Debug(fmt.Sprintf("Replaying entry '%s' for '%s'...", entry.ID.String(), entry.RefName))
+	if err := rsl.NewReferenceEntry(entry.RefName, entry.TargetID).Commit(r.r, signCommit); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if _, _, err := rsl.GetLatestReferenceEntryForRef(r.r, policy.PolicyRef); err == nil {
+		logger.Debug(fmt.Sprintf("Verifying replayed entry for '%s'...", entry.RefName))
+		if _, err := policy.VerifyRef(ctx, r.r, entry.RefName); err != nil {
+			return plumbing.ZeroHash, r.restoreTips(tips, &replayVerificationError{err: err})
+		}
+	} else if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return plumbing.ZeroHash, r.restoreTips(tips, err)
+	}
+
+	if err := r.timestampLatestRSLEntry(signCommit); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return gitinterface.GetTip(r.r, rsl.Ref)
+}
+
+// getEntriesUnknownTo returns the entries of the RSL at tip that the RSL at
+// otherTip doesn't have, in the order they were recorded.
+func (r *Repository) getEntriesUnknownTo(tip, otherTip plumbing.Hash) ([]rsl.Entry, error) {
+	entries := []rsl.Entry{}
+	entry, err := rsl.GetEntry(r.r, tip)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		entryCommit, err := gitinterface.GetCommit(r.r, entry.GetID())
+		if err != nil {
+			return nil, err
+		}
+		known, err := gitinterface.KnowsCommit(r.r, otherTip, entryCommit)
+		if err != nil {
+			return nil, err
+		}
+		if known {
+			break
+		}
+		entries = append(entries, entry)
+
+		entry, err = rsl.GetParentForEntry(r.r, entry)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				// The first entry was reached, so the RSLs share no entries
+				break
+			}
+			return nil, err
+		}
+	}
+
+	slices.Reverse(entries)
+	return entries, nil
+}
+
+// updateGittufRefsToRSL updates the policy and attestations refs to the states
+// recorded for them in the latest RSL entries, so that they match the RSL after
+// it's reconciled with a remote's RSL.
+func (r *Repository) updateGittufRefsToRSL() error {
+	for _, refName := range []string{policy.PolicyRef, attestations.Ref} {
+		entry, _, err := rsl.GetLatestUnskippedReferenceEntryForRef(r.r, refName)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				continue
+			}
+			return err
+		}
+
+		currentTip, err := gitinterface.GetTip(r.r, refName)
+		if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return err
+		}
+		if currentTip == entry.TargetID {
+			continue
+		}
+
+		logger.Debug(fmt.Sprintf("Updating '%s' to '%s'...", refName, entry.TargetID.String()))
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), entry.TargetID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
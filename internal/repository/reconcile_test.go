@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileRSL(t *testing.T) {
+	remoteName := "origin"
+	refName := "refs/heads/main"
+	anotherRefName := "refs/heads/feature"
+
+	// createRepositories returns a remote repository with an RSL entry for
+	// refName and a clone of it
+	createRepositories := func(t *testing.T) (*Repository, *Repository) {
+		t.Helper()
+
+		tmpDir := t.TempDir()
+
+		remoteR, err := git.PlainInit(tmpDir, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		remoteRepo := &Repository{r: remoteR}
+
+		if err := rsl.InitializeNamespace(remoteRepo.r); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := gitinterface.Commit(remoteRepo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		localR, err := gitinterface.CloneAndFetchToMemory(context.Background(), tmpDir, refName, []string{rsl.Ref})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return remoteRepo, &Repository{r: localR}
+	}
+
+	recordCommit := func(t *testing.T, repo *Repository, refName string) {
+		t.Helper()
+
+		if _, err := gitinterface.Commit(repo.r, gitinterface.EmptyTree(), refName, "Test commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	getRSLTip := func(t *testing.T, repo *Repository) string {
+		t.Helper()
+
+		tip, err := gitinterface.GetTip(repo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tip.String()
+	}
+
+	t.Run("up to date", func(t *testing.T) {
+		_, localRepo := createRepositories(t)
+		localTip := getRSLTip(t, localRepo)
+
+		reconciliation, err := localRepo.ReconcileRSL(context.Background(), remoteName, false)
+		assert.Nil(t, err)
+		assert.Equal(t, RSLSyncStatusUpToDate, reconciliation.Status)
+		assert.Equal(t, localTip, getRSLTip(t, localRepo))
+	})
+
+	t.Run("local is ahead", func(t *testing.T) {
+		_, localRepo := createRepositories(t)
+		recordCommit(t, localRepo, refName)
+		localTip := getRSLTip(t, localRepo)
+
+		reconciliation, err := localRepo.ReconcileRSL(context.Background(), remoteName, false)
+		assert.Nil(t, err)
+		assert.Equal(t, RSLSyncStatusAhead, reconciliation.Status)
+		assert.Equal(t, localTip, getRSLTip(t, localRepo))
+	})
+
+	t.Run("remote is ahead", func(t *testing.T) {
+		remoteRepo, localRepo := createRepositories(t)
+		recordCommit(t, remoteRepo, refName)
+
+		reconciliation, err := localRepo.ReconcileRSL(context.Background(), remoteName, false)
+		assert.Nil(t, err)
+		assert.Equal(t, RSLSyncStatusBehind, reconciliation.Status)
+		assert.Empty(t, reconciliation.Replayed)
+		assert.Equal(t, getRSLTip(t, remoteRepo), getRSLTip(t, localRepo))
+	})
+
+	t.Run("diverged, local entries replayed", func(t *testing.T) {
+		remoteRepo, localRepo := createRepositories(t)
+		recordCommit(t, remoteRepo, refName)
+
+		recordCommit(t, localRepo, anotherRefName)
+		localEntry, err := rsl.GetLatestEntry(localRepo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := localRepo.RecordRSLAnnotation([]string{localEntry.GetID().String()}, false, "test annotation", false); err != nil {
+			t.Fatal(err)
+		}
+		localTip := getRSLTip(t, localRepo)
+
+		reconciliation, err := localRepo.ReconcileRSL(context.Background(), remoteName, false)
+		assert.Nil(t, err)
+		assert.Equal(t, RSLSyncStatusDiverged, reconciliation.Status)
+		assert.Equal(t, localTip, reconciliation.PreviousTip.String())
+		assert.Equal(t, getRSLTip(t, remoteRepo), reconciliation.RemoteTip.String())
+		assert.Empty(t, reconciliation.Conflicts)
+		assert.Len(t, reconciliation.Replayed, 2)
+
+		// The annotation is replayed last, referring to the replayed entry
+		latestEntry, err := rsl.GetLatestEntry(localRepo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		annotation, isAnnotation := latestEntry.(*rsl.AnnotationEntry)
+		if !isAnnotation {
+			t.Fatal("expected annotation entry")
+		}
+		assert.Equal(t, "test annotation", annotation.Message)
+		assert.Equal(t, reconciliation.Replayed[0].NewID, annotation.RSLEntryIDs[0])
+
+		replayedEntry, err := rsl.GetParentForEntry(localRepo.r, annotation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, reconciliation.Replayed[0].NewID, replayedEntry.GetID())
+		assert.Equal(t, anotherRefName, replayedEntry.(*rsl.ReferenceEntry).RefName)
+
+		// The replayed entries are recorded on top of the remote's RSL
+		remoteEntry, err := rsl.GetParentForEntry(localRepo.r, replayedEntry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, reconciliation.RemoteTip, remoteEntry.GetID())
+	})
+
+	t.Run("diverged, conflicting entries", func(t *testing.T) {
+		remoteRepo, localRepo := createRepositories(t)
+
+		// The remote's commit must differ from the local one, which may
+		// otherwise be identical if they're created within the same second
+		if _, err := gitinterface.Commit(remoteRepo.r, gitinterface.EmptyTree(), refName, "Remote commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		recordCommit(t, localRepo, refName)
+		conflictingEntry, err := rsl.GetLatestEntry(localRepo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recordCommit(t, localRepo, refName)
+		if err := localRepo.RecordRSLAnnotation([]string{conflictingEntry.GetID().String()}, true, "test annotation", false); err != nil {
+			t.Fatal(err)
+		}
+
+		reconciliation, err := localRepo.ReconcileRSL(context.Background(), remoteName, false)
+		assert.Nil(t, err)
+		assert.Equal(t, RSLSyncStatusDiverged, reconciliation.Status)
+		assert.Empty(t, reconciliation.Replayed)
+		assert.Len(t, reconciliation.Conflicts, 3)
+		assert.Equal(t, conflictingEntry.GetID(), reconciliation.Conflicts[0].EntryID)
+		assert.ErrorIs(t, reconciliation.Conflicts[0].Reason, errRefUpdatedRemotely)
+		assert.ErrorIs(t, reconciliation.Conflicts[1].Reason, errEarlierEntryConflicts)
+		assert.ErrorIs(t, reconciliation.Conflicts[2].Reason, errAnnotatedEntryDropped)
+
+		// The local RSL matches the remote's, as no entries were replayed
+		assert.Equal(t, getRSLTip(t, remoteRepo), getRSLTip(t, localRepo))
+	})
+}
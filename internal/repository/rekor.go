@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/rekor"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// logAttestationToRekor logs the signer's signature on the attestation to the
+// Rekor instance carried by the context, and records the resulting log entry
+// in the attestations state. If the context does not carry a Rekor client,
+// this is a no-op.
+func (r *Repository) logAttestationToRekor(ctx context.Context, allAttestations *attestations.Attestations, env *sslibdsse.Envelope, signer sslibdsse.SignerVerifier) error {
+	client := rekor.ClientFromContext(ctx)
+	if client == nil {
+		return nil
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	// Rekor requires a verifier for every signature in the envelope, so only
+	// the signer's signature is logged. Each signer of an attestation logs
+	// their signature separately.
+	signedEnv := &sslibdsse.Envelope{
+		PayloadType: env.PayloadType,
+		Payload:     env.Payload,
+		Signatures:  []sslibdsse.Signature{},
+	}
+	for _, signature := range env.Signatures {
+		if signature.KeyID == keyID {
+			signedEnv.Signatures = append(signedEnv.Signatures, signature)
+		}
+	}
+
+	verifier, err := rekorVerifierForSigner(signer)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Logging attestation to Rekor instance '%s'...", client.URL()))
+	entry, err := client.Upload(ctx, signedEnv, [][]byte{verifier})
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := client.PublicKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Verifying Rekor log entry '%s'...", entry.UUID))
+	if err := entry.VerifyForEnvelope(env, publicKey); err != nil {
+		return err
+	}
+
+	return allAttestations.SetRekorEntry(r.r, env, entry)
+}
+
+// rekorVerifierForSigner returns the PEM encoded verifier Rekor uses to check
+// the signer's signature. For signers certified by Fulcio, this is the
+// signer's certificate, otherwise it is the signer's public key.
+func rekorVerifierForSigner(signer sslibdsse.SignerVerifier) ([]byte, error) {
+	if certifiedSigner, ok := signer.(interface{ CertificateChain() []byte }); ok {
+		if block, _ := pem.Decode(certifiedSigner.CertificateChain()); block != nil {
+			return pem.EncodeToMemory(block), nil
+		}
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}), nil
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ReleaseTagName returns the name of the tag for the release version. Release
+// tags are prefixed with "v", which may be omitted from the version.
+func ReleaseTagName(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
+
+// VerifyRelease checks the release version's tag against the repository's
+// gittuf policy and RSL, and then checks that each of the artifacts, such as
+// downloaded binaries, was attested to as built from the tagged commit. An
+// artifact is identified in attestations by its file name.
+func (r *Repository) VerifyRelease(ctx context.Context, version string, artifactPaths []string) error {
+	tagRef := string(plumbing.NewTagReferenceName(ReleaseTagName(version)))
+
+	logger.Debug(fmt.Sprintf("Verifying release tag '%s'...", tagRef))
+	if err := r.VerifyRef(ctx, tagRef, false); err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Identifying commit for '%s'...", tagRef))
+	commitID, err := r.r.ResolveRevision(plumbing.Revision(tagRef))
+	if err != nil {
+		return err
+	}
+
+	for _, artifactPath := range artifactPaths {
+		artifactName := filepath.Base(artifactPath)
+
+		logger.Debug(fmt.Sprintf("Computing digest of '%s'...", artifactPath))
+		artifactDigest, err := sha256File(artifactPath)
+		if err != nil {
+			return err
+		}
+
+		if err := policy.VerifyReleaseArtifact(ctx, r.r, commitID.String(), artifactName, artifactDigest); err != nil {
+			return err
+		}
+	}
+
+	logger.Debug("Verification successful!")
+	return nil
+}
+
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close() //nolint:errcheck
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
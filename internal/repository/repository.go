@@ -5,9 +5,11 @@ package repository
 import (
 	"errors"
 	"fmt"
-	"log/slog"
+	"os"
 
 	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/go-git/go-git/v5"
@@ -18,14 +20,31 @@ var (
 	ErrCannotReinitialize = errors.New("cannot reinitialize metadata, it exists already")
 )
 
+// logger logs the messages of the repository subsystem.
+var logger = logging.For("repository")
+
 type Repository struct {
 	r *git.Repository
 }
 
+// LoadRepository returns the repository in the current working directory.
+// Like Git, GIT_DIR is used instead if it's set, such as when gittuf is invoked
+// from hooks in a bare repository.
 func LoadRepository() (*Repository, error) {
-	slog.Debug("Loading Git repository...")
+	if gitDir := os.Getenv("GIT_DIR"); gitDir != "" {
+		return LoadRepositoryAt(gitDir)
+	}
+
+	return LoadRepositoryAt(".")
+}
+
+// LoadRepositoryAt returns the repository at the specified path, which may be
+// a bare repository, any directory in a repository's working tree, or a linked
+// worktree.
+func LoadRepositoryAt(path string) (*Repository, error) {
+	logger.Debug("Loading Git repository...")
 
-	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true})
+	repo, err := gitinterface.OpenRepository(path)
 	if err != nil {
 		return nil, err
 	}
@@ -36,17 +55,17 @@ func LoadRepository() (*Repository, error) {
 }
 
 func (r *Repository) InitializeNamespaces() error {
-	slog.Debug(fmt.Sprintf("Initializing RSL reference '%s'...", rsl.Ref))
+	logger.Debug(fmt.Sprintf("Initializing RSL reference '%s'...", rsl.Ref))
 	if err := rsl.InitializeNamespace(r.r); err != nil {
 		return err
 	}
 
-	slog.Debug(fmt.Sprintf("Initializing attestations reference '%s'...", attestations.Ref))
+	logger.Debug(fmt.Sprintf("Initializing attestations reference '%s'...", attestations.Ref))
 	if err := attestations.InitializeNamespace(r.r); err != nil {
 		return err
 	}
 
-	slog.Debug(fmt.Sprintf("Initializing policy reference '%s'...", policy.PolicyRef))
+	logger.Debug(fmt.Sprintf("Initializing policy reference '%s'...", policy.PolicyRef))
 	return policy.InitializeNamespace(r.r)
 }
 
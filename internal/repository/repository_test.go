@@ -4,12 +4,17 @@ package repository
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
 )
@@ -20,6 +25,57 @@ func TestLoadRepository(t *testing.T) {
 	assert.NotNil(t, repository.r)
 }
 
+func TestLoadRepositoryAtLinkedWorktree(t *testing.T) {
+	mainDir := t.TempDir()
+	worktreeDir := t.TempDir()
+
+	if _, err := git.PlainInit(mainDir, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Recreate the layout of a worktree created using `git worktree add`
+	worktreeGitDir := filepath.Join(mainDir, ".git", "worktrees", "feature")
+	if err := os.MkdirAll(worktreeGitDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(worktreeGitDir, "commondir"): "../..\n",
+		filepath.Join(worktreeGitDir, "HEAD"):      "ref: refs/heads/feature\n",
+		filepath.Join(worktreeGitDir, "gitdir"):    filepath.Join(worktreeDir, ".git") + "\n",
+		filepath.Join(worktreeDir, ".git"):         "gitdir: " + worktreeGitDir + "\n",
+	}
+	for path, contents := range files {
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+	}
+
+	r, err := LoadRepositoryAt(worktreeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.InitializeNamespaces()
+	assert.Nil(t, err)
+
+	err = r.UpdateHook(HookPrePush, []byte("some content"), false)
+	assert.Nil(t, err)
+
+	// The namespaces and hooks are shared with the main worktree
+	mainRepo, err := LoadRepositoryAt(mainDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, refName := range []string{rsl.Ref, policy.PolicyRef} {
+		_, err := mainRepo.r.Reference(plumbing.ReferenceName(refName), true)
+		assert.Nil(t, err)
+	}
+
+	hookContents, err := os.ReadFile(filepath.Join(mainDir, ".git", "hooks", string(HookPrePush)))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("some content"), hookContents)
+}
+
 func TestInitializeNamespaces(t *testing.T) {
 	repo, err := git.Init(memory.NewStorage(), memfs.New())
 	if err != nil {
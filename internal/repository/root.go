@@ -7,8 +7,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"log/slog"
 
+	"github.com/gittuf/gittuf/internal/compression"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
@@ -29,7 +29,7 @@ func (r *Repository) InitializeRoot(ctx context.Context, signer sslibdsse.Signer
 		return err
 	}
 
-	slog.Debug("Creating initial root metadata...")
+	logger.Debug("Creating initial root metadata...")
 	rootMetadata := policy.InitializeRootMetadata(publicKey)
 
 	env, err := dsse.CreateEnvelope(rootMetadata)
@@ -37,7 +37,7 @@ func (r *Repository) InitializeRoot(ctx context.Context, signer sslibdsse.Signer
 		return nil
 	}
 
-	slog.Debug(fmt.Sprintf("Signing initial root metadata using '%s'...", publicKey.KeyID))
+	logger.Debug(fmt.Sprintf("Signing initial root metadata using '%s'...", publicKey.KeyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
 		return nil
@@ -50,8 +50,8 @@ func (r *Repository) InitializeRoot(ctx context.Context, signer sslibdsse.Signer
 
 	commitMessage := "Initialize root of trust"
 
-	slog.Debug("Committing policy...")
-	return state.Commit(r.r, commitMessage, signCommit)
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
 
 // AddRootKey is the interface for the user to add an authorized key
@@ -62,18 +62,23 @@ func (r *Repository) AddRootKey(ctx context.Context, signer sslibdsse.SignerVeri
 		return err
 	}
 
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
 	}
 
+	logger.Debug("Checking identities for look-alikes...")
+	if err := checkIdentities(state, []*tuf.Key{newRootKey}, nil); err != nil {
+		return err
+	}
+
 	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug("Adding root key...")
+	logger.Debug("Adding root key...")
 	rootMetadata = policy.AddRootKey(rootMetadata, newRootKey)
 
 	found := false
@@ -99,7 +104,7 @@ func (r *Repository) RemoveRootKey(ctx context.Context, signer sslibdsse.SignerV
 		return err
 	}
 
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
@@ -110,7 +115,7 @@ func (r *Repository) RemoveRootKey(ctx context.Context, signer sslibdsse.SignerV
 		return err
 	}
 
-	slog.Debug("Removing root key...")
+	logger.Debug("Removing root key...")
 	rootMetadata, err = policy.DeleteRootKey(rootMetadata, keyID)
 	if err != nil {
 		return err
@@ -136,18 +141,23 @@ func (r *Repository) AddTopLevelTargetsKey(ctx context.Context, signer sslibdsse
 		return err
 	}
 
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
 	}
 
+	logger.Debug("Checking identities for look-alikes...")
+	if err := checkIdentities(state, []*tuf.Key{targetsKey}, nil); err != nil {
+		return err
+	}
+
 	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug("Adding policy key...")
+	logger.Debug("Adding policy key...")
 	rootMetadata, err = policy.AddTargetsKey(rootMetadata, targetsKey)
 	if err != nil {
 		return fmt.Errorf("failed to add policy key: %w", err)
@@ -165,7 +175,7 @@ func (r *Repository) RemoveTopLevelTargetsKey(ctx context.Context, signer sslibd
 		return err
 	}
 
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
@@ -176,7 +186,7 @@ func (r *Repository) RemoveTopLevelTargetsKey(ctx context.Context, signer sslibd
 		return err
 	}
 
-	slog.Debug("Removing policy key...")
+	logger.Debug("Removing policy key...")
 	rootMetadata, err = policy.DeleteTargetsKey(rootMetadata, targetsKeyID)
 	if err != nil {
 		return err
@@ -194,7 +204,7 @@ func (r *Repository) UpdateRootThreshold(ctx context.Context, signer sslibdsse.S
 		return err
 	}
 
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
@@ -205,7 +215,7 @@ func (r *Repository) UpdateRootThreshold(ctx context.Context, signer sslibdsse.S
 		return err
 	}
 
-	slog.Debug("Updating root threshold...")
+	logger.Debug("Updating root threshold...")
 	rootMetadata, err = policy.UpdateRootThreshold(rootMetadata, threshold)
 	if err != nil {
 		return err
@@ -223,7 +233,7 @@ func (r *Repository) UpdateTopLevelTargetsThreshold(ctx context.Context, signer
 		return err
 	}
 
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
@@ -234,7 +244,7 @@ func (r *Repository) UpdateTopLevelTargetsThreshold(ctx context.Context, signer
 		return err
 	}
 
-	slog.Debug("Updating policy threshold...")
+	logger.Debug("Updating policy threshold...")
 	rootMetadata, err = policy.UpdateTargetsThreshold(rootMetadata, threshold)
 	if err != nil {
 		return err
@@ -244,10 +254,217 @@ func (r *Repository) UpdateTopLevelTargetsThreshold(ctx context.Context, signer
 	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
 }
 
+// SetRequirePolicyJustifications sets whether each policy state must be
+// accompanied by a signed justification attestation before it can be applied.
+func (r *Repository) SetRequirePolicyJustifications(ctx context.Context, signer sslibdsse.SignerVerifier, require bool, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Updating policy justification requirement...")
+	rootMetadata.RequirePolicyJustifications = require
+
+	commitMessage := "Require justifications for policy changes"
+	if !require {
+		commitMessage = "Stop requiring justifications for policy changes"
+	}
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
+// SetMetadataCompression sets the algorithm used to compress large policy
+// metadata and attestation blobs. Compression is disabled if algorithm is
+// empty.
+func (r *Repository) SetMetadataCompression(ctx context.Context, signer sslibdsse.SignerVerifier, algorithm string, signCommit bool) error {
+	if algorithm != "" && !compression.IsSupported(algorithm) {
+		return fmt.Errorf("%w: '%s'", compression.ErrUnsupportedAlgorithm, algorithm)
+	}
+
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Updating metadata compression...")
+	rootMetadata.MetadataCompression = algorithm
+
+	commitMessage := fmt.Sprintf("Compress metadata using %s", algorithm)
+	if algorithm == "" {
+		commitMessage = "Stop compressing metadata"
+	}
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
+// SetKeyValidity sets the period in which the key may issue signatures. The
+// bounds are RFC 3339 timestamps, either of which may be empty. If both are
+// empty, the key's validity window is removed.
+func (r *Repository) SetKeyValidity(ctx context.Context, signer sslibdsse.SignerVerifier, keyID, notBefore, notAfter string, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Updating validity window of key '%s'...", keyID))
+	rootMetadata, err = policy.SetKeyValidity(rootMetadata, keyID, notBefore, notAfter)
+	if err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Set validity window of key '%s'", keyID)
+	if notBefore == "" && notAfter == "" {
+		commitMessage = fmt.Sprintf("Remove validity window of key '%s'", keyID)
+	}
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, signCommit)
+}
+
+// RevokeKey records that the key has been revoked, so that signatures issued
+// by it are rejected. The reason is optional.
+func (r *Repository) RevokeKey(ctx context.Context, signer sslibdsse.SignerVerifier, keyID, reason string, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Revoking key '%s'...", keyID))
+	rootMetadata, err = policy.RevokeKey(rootMetadata, keyID, reason)
+	if err != nil {
+		return err
+	}
+
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, fmt.Sprintf("Revoke key '%s'", keyID), signCommit)
+}
+
+// UnrevokeKey removes the revocation of the key.
+func (r *Repository) UnrevokeKey(ctx context.Context, signer sslibdsse.SignerVerifier, keyID string, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Removing revocation of key '%s'...", keyID))
+	rootMetadata, err = policy.UnrevokeKey(rootMetadata, keyID)
+	if err != nil {
+		return err
+	}
+
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, fmt.Sprintf("Remove revocation of key '%s'", keyID), signCommit)
+}
+
+// AddTimestampAuthority adds the PEM encoded root certificate of an RFC 3161
+// timestamp authority to the authorities trusted to timestamp signatures.
+func (r *Repository) AddTimestampAuthority(ctx context.Context, signer sslibdsse.SignerVerifier, certificatePEM []byte, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Adding timestamp authority...")
+	rootMetadata, err = policy.AddTimestampAuthority(rootMetadata, certificatePEM)
+	if err != nil {
+		return err
+	}
+
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, "Add timestamp authority", signCommit)
+}
+
+// RemoveTimestampAuthority removes the PEM encoded root certificate of a
+// timestamp authority from the authorities trusted to timestamp signatures.
+func (r *Repository) RemoveTimestampAuthority(ctx context.Context, signer sslibdsse.SignerVerifier, certificatePEM []byte, signCommit bool) error {
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Removing timestamp authority...")
+	rootMetadata, err = policy.RemoveTimestampAuthority(rootMetadata, certificatePEM)
+	if err != nil {
+		return err
+	}
+
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, "Remove timestamp authority", signCommit)
+}
+
 // SignRoot adds a signature to the Root envelope. Note that the metadata itself
 // is not modified, so its version remains the same.
 func (r *Repository) SignRoot(ctx context.Context, signer sslibdsse.SignerVerifier, signCommit bool) error {
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
@@ -260,7 +477,7 @@ func (r *Repository) SignRoot(ctx context.Context, signer sslibdsse.SignerVerifi
 
 	env := state.RootEnvelope
 
-	slog.Debug(fmt.Sprintf("Signing root metadata using '%s'...", keyID))
+	logger.Debug(fmt.Sprintf("Signing root metadata using '%s'...", keyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
 		return err
@@ -270,12 +487,12 @@ func (r *Repository) SignRoot(ctx context.Context, signer sslibdsse.SignerVerifi
 
 	commitMessage := fmt.Sprintf("Add signature from key '%s' to root metadata", keyID)
 
-	slog.Debug("Committing policy...")
-	return state.Commit(r.r, commitMessage, signCommit)
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
 
 func (r *Repository) loadRootMetadata(state *policy.State, keyID string) (*tuf.RootMetadata, error) {
-	slog.Debug("Loading current root metadata...")
+	logger.Debug("Loading current root metadata...")
 	rootMetadata, err := state.GetRootMetadata()
 	if err != nil {
 		return nil, err
@@ -299,7 +516,7 @@ func (r *Repository) updateRootMetadata(ctx context.Context, state *policy.State
 	env.Signatures = []sslibdsse.Signature{}
 	env.Payload = base64.StdEncoding.EncodeToString(rootMetadataBytes)
 
-	slog.Debug("Signing updated root metadata...")
+	logger.Debug("Signing updated root metadata...")
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
 		return err
@@ -307,6 +524,6 @@ func (r *Repository) updateRootMetadata(ctx context.Context, state *policy.State
 
 	state.RootEnvelope = env
 
-	slog.Debug("Committing policy...")
-	return state.Commit(r.r, commitMessage, signCommit)
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
@@ -5,6 +5,7 @@ package repository
 import (
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/compression"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
@@ -395,3 +396,88 @@ func TestSignRoot(t *testing.T) {
 
 	assert.Equal(t, 2, len(state.RootEnvelope.Signatures))
 }
+
+func TestSetRequirePolicyJustifications(t *testing.T) {
+	r, _ := createTestRepositoryWithRoot(t, "")
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetRequirePolicyJustifications(testCtx, signer, true, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, rootMetadata.RequirePolicyJustifications)
+
+	err = r.SetRequirePolicyJustifications(testCtx, signer, false, false)
+	assert.Nil(t, err)
+
+	state, err = policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, rootMetadata.RequirePolicyJustifications)
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetRequirePolicyJustifications(testCtx, targetsSigner, true, false)
+	assert.ErrorIs(t, err, ErrUnauthorizedKey)
+}
+
+func TestSetMetadataCompression(t *testing.T) {
+	r, _ := createTestRepositoryWithRoot(t, "")
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetMetadataCompression(testCtx, signer, compression.AlgorithmZstd, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, compression.AlgorithmZstd, rootMetadata.MetadataCompression)
+
+	err = r.SetMetadataCompression(testCtx, signer, "", false)
+	assert.Nil(t, err)
+
+	state, err = policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, rootMetadata.MetadataCompression)
+
+	err = r.SetMetadataCompression(testCtx, signer, "gzip", false)
+	assert.ErrorIs(t, err, compression.ErrUnsupportedAlgorithm)
+}
@@ -6,14 +6,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
 
+	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/timestamp"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
 var (
@@ -25,19 +28,19 @@ var (
 // RecordRSLEntryForReference is the interface for the user to add an RSL entry
 // for the specified Git reference.
 func (r *Repository) RecordRSLEntryForReference(refName string, signCommit bool) error {
-	slog.Debug("Identifying absolute reference path...")
+	logger.Debug("Identifying absolute reference path...")
 	absRefName, err := gitinterface.AbsoluteReference(r.r, refName)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug(fmt.Sprintf("Loading current state of '%s'...", absRefName))
+	logger.Debug(fmt.Sprintf("Loading current state of '%s'...", absRefName))
 	ref, err := r.r.Reference(plumbing.ReferenceName(absRefName), true)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug("Checking for existing entry for reference with same target...")
+	logger.Debug("Checking for existing entry for reference with same target...")
 	isDuplicate, err := r.isDuplicateEntry(absRefName, ref.Hash())
 	if err != nil {
 		return err
@@ -49,8 +52,68 @@ func (r *Repository) RecordRSLEntryForReference(refName string, signCommit bool)
 	// TODO: once policy verification is in place, the signing key used by
 	// signCommit must be verified for the refName in the delegation tree.
 
-	slog.Debug("Creating RSL reference entry...")
-	return rsl.NewReferenceEntry(absRefName, ref.Hash()).Commit(r.r, signCommit)
+	logger.Debug("Creating RSL reference entry...")
+	if err := rsl.NewReferenceEntry(absRefName, ref.Hash()).Commit(r.r, signCommit); err != nil {
+		return err
+	}
+
+	return r.timestampLatestRSLEntry(signCommit)
+}
+
+// RecordRSLEntryForPlatformPush records an RSL entry for a push made on a
+// hosting platform rather than by a gittuf client, such as a commit created
+// using the platform's UI or a merge made by the platform. The entry is signed
+// using the configured signing key, typically a bot's. If a signer is
+// specified, a push event attestation signed by it also records the platform
+// and the platform account that made the push. A zero target records the
+// deletion of the ref. If the RSL already records the target for the ref,
+// nothing is recorded and false is returned.
+func (r *Repository) RecordRSLEntryForPlatformPush(ctx context.Context, signer sslibdsse.SignerVerifier, refName string, targetID plumbing.Hash, platform, pusher string, signCommit bool) (bool, error) {
+	if !targetID.IsZero() {
+		if _, err := gitinterface.GetCommit(r.r, targetID); err != nil {
+			if _, tagErr := gitinterface.GetTag(r.r, targetID); tagErr != nil {
+				return false, fmt.Errorf("unable to find target '%s' of '%s': %w", targetID.String(), refName, err)
+			}
+		}
+	}
+
+	logger.Debug("Checking for existing entry for reference with same target...")
+	isDuplicate, err := r.isDuplicateEntry(refName, targetID)
+	if err != nil {
+		return false, err
+	}
+	if isDuplicate {
+		return false, nil
+	}
+
+	logger.Debug(fmt.Sprintf("Creating RSL reference entry for '%s' pushed on %s by '%s'...", refName, platform, pusher))
+	if err := rsl.NewReferenceEntry(refName, targetID).Commit(r.r, signCommit); err != nil {
+		return false, err
+	}
+
+	if err := r.timestampLatestRSLEntry(signCommit); err != nil {
+		return false, err
+	}
+
+	if signer == nil {
+		return true, nil
+	}
+
+	if _, err := r.r.Reference(plumbing.ReferenceName(attestations.Ref), true); errors.Is(err, plumbing.ErrReferenceNotFound) {
+		if err := attestations.InitializeNamespace(r.r); err != nil {
+			return false, err
+		}
+	}
+
+	pushEvent := &attestations.PushEvent{
+		Platform: platform,
+		Pusher:   pusher,
+	}
+	if err := r.addPushEventAttestation(ctx, signer, refName, pushEvent, signCommit); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 // RecordRSLEntryForReferenceAtTarget is a special version of
@@ -62,7 +125,7 @@ func (r *Repository) RecordRSLEntryForReferenceAtTarget(refName string, targetID
 		return dev.ErrNotInDevMode
 	}
 
-	slog.Debug("Identifying absolute reference path...")
+	logger.Debug("Identifying absolute reference path...")
 	absRefName, err := gitinterface.AbsoluteReference(r.r, refName)
 	if err != nil {
 		return err
@@ -71,7 +134,7 @@ func (r *Repository) RecordRSLEntryForReferenceAtTarget(refName string, targetID
 	// TODO: once policy verification is in place, the signing key used by
 	// signCommit must be verified for the refName in the delegation tree.
 
-	slog.Debug("Creating RSL reference entry...")
+	logger.Debug("Creating RSL reference entry...")
 	return rsl.NewReferenceEntry(absRefName, plumbing.NewHash(targetID)).CommitUsingSpecificKey(r.r, signingKeyBytes)
 }
 
@@ -86,7 +149,7 @@ func (r *Repository) RecordRSLAnnotation(rslEntryIDs []string, skip bool, messag
 	// TODO: once policy verification is in place, the signing key used by
 	// signCommit must be verified for the refNames of the rslEntryIDs.
 
-	slog.Debug("Creating RSL annotation entry...")
+	logger.Debug("Creating RSL annotation entry...")
 	return rsl.NewAnnotationEntry(rslEntryHashes, skip, message).Commit(r.r, signCommit)
 }
 
@@ -101,7 +164,7 @@ func (r *Repository) CheckRemoteRSLForUpdates(ctx context.Context, remoteName st
 	trackerRef := rsl.RemoteTrackerRef(remoteName)
 	rslRemoteRefSpec := []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", rsl.Ref, trackerRef))}
 
-	slog.Debug("Updating remote RSL tracker...")
+	logger.Debug("Updating remote RSL tracker...")
 	if err := gitinterface.FetchRefSpec(ctx, r.r, remoteName, rslRemoteRefSpec); err != nil {
 		if errors.Is(err, transport.ErrEmptyRemoteRepository) {
 			// Check if remote is empty and exit appropriately
@@ -124,13 +187,13 @@ func (r *Repository) CheckRemoteRSLForUpdates(ctx context.Context, remoteName st
 	if localRefState.Hash().IsZero() {
 		// Local RSL has not been populated but remote is not zero
 		// So there are updates the local can pull
-		slog.Debug("Local RSL has not been initialized but remote RSL exists")
+		logger.Debug("Local RSL has not been initialized but remote RSL exists")
 		return true, false, nil
 	}
 
 	// Check if equal and exit early if true
 	if remoteRefState.Hash() == localRefState.Hash() {
-		slog.Debug("Local and remote RSLs have same state")
+		logger.Debug("Local and remote RSLs have same state")
 		return false, false, nil
 	}
 
@@ -149,7 +212,7 @@ func (r *Repository) CheckRemoteRSLForUpdates(ctx context.Context, remoteName st
 		return false, false, err
 	}
 	if knows {
-		slog.Debug("Remote RSL is ahead of local RSL")
+		logger.Debug("Remote RSL is ahead of local RSL")
 		return true, false, nil
 	}
 
@@ -161,18 +224,18 @@ func (r *Repository) CheckRemoteRSLForUpdates(ctx context.Context, remoteName st
 		return false, false, err
 	}
 	if knows {
-		slog.Debug("Local RSL is ahead of remote RSL")
+		logger.Debug("Local RSL is ahead of remote RSL")
 		return false, false, nil
 	}
 
-	slog.Debug("Local and remote RSLs have diverged")
+	logger.Debug("Local and remote RSLs have diverged")
 	return true, true, nil
 }
 
 // PushRSL pushes the local RSL to the specified remote. As this push defaults
 // to fast-forward only, divergent RSL states are detected.
 func (r *Repository) PushRSL(ctx context.Context, remoteName string) error {
-	slog.Debug(fmt.Sprintf("Pushing RSL reference to '%s'...", remoteName))
+	logger.Debug(fmt.Sprintf("Pushing RSL reference to '%s'...", remoteName))
 	if err := gitinterface.Push(ctx, r.r, remoteName, []string{rsl.Ref}); err != nil {
 		return errors.Join(ErrPushingRSL, err)
 	}
@@ -181,13 +244,52 @@ func (r *Repository) PushRSL(ctx context.Context, remoteName string) error {
 }
 
 // PullRSL pulls RSL contents from the specified remote to the local RSL. The
-// fetch is marked as fast forward only to detect RSL divergence.
+// fetch is marked as fast forward only to detect RSL divergence. If the
+// fetched RSL entries or the objects they record are corrupt, the RSL is
+// restored to its prior state.
 func (r *Repository) PullRSL(ctx context.Context, remoteName string) error {
-	slog.Debug(fmt.Sprintf("Pulling RSL reference from '%s'...", remoteName))
+	tips, err := r.getTips([]string{rsl.Ref})
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Pulling RSL reference from '%s'...", remoteName))
 	if err := gitinterface.Fetch(ctx, r.r, remoteName, []string{rsl.Ref}, true); err != nil {
 		return errors.Join(ErrPullingRSL, err)
 	}
 
+	if err := r.checkFetchedRSL(tips[rsl.Ref]); err != nil {
+		return r.restoreTips(tips, errors.Join(ErrPullingRSL, err))
+	}
+
+	return nil
+}
+
+// timestampLatestRSLEntry timestamps the signature of the latest RSL entry,
+// which was just recorded, if signatures are timestamped.
+func (r *Repository) timestampLatestRSLEntry(signCommit bool) error {
+	if !signCommit || timestamp.GetTimestamper() == nil {
+		return nil
+	}
+
+	entry, err := rsl.GetLatestEntry(r.r)
+	if err != nil {
+		return err
+	}
+
+	entryCommit, err := gitinterface.GetCommit(r.r, entry.GetID())
+	if err != nil {
+		return err
+	}
+	if entryCommit.PGPSignature == "" {
+		return nil
+	}
+
+	logger.Debug(fmt.Sprintf("Timestamping RSL entry '%s'...", entry.GetID().String()))
+	if err := policy.RecordTimestamps(context.Background(), r.r, [][]byte{[]byte(entryCommit.PGPSignature)}, signCommit); err != nil {
+		return fmt.Errorf("RSL entry '%s' was recorded, but %w", entry.GetID().String(), err)
+	}
+
 	return nil
 }
 
@@ -197,7 +299,9 @@ func (r *Repository) PullRSL(ctx context.Context, remoteName string) error {
 func (r *Repository) isDuplicateEntry(refName string, targetID plumbing.Hash) (bool, error) {
 	latestUnskippedEntry, _, err := rsl.GetLatestUnskippedReferenceEntryForRef(r.r, refName)
 	if err != nil {
-		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		// The RSL may not have been created yet, such as when pushing to a
+		// remote for the first time
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) || errors.Is(err, plumbing.ErrReferenceNotFound) {
 			return false, nil
 		}
 		return false, err
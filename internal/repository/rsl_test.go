@@ -4,14 +4,18 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
@@ -103,6 +107,68 @@ func TestRecordRSLEntryForReference(t *testing.T) {
 	assert.Equal(t, entry.GetID(), entryType.GetID())
 }
 
+func TestRecordRSLEntryForPlatformPush(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &Repository{r: r}
+	if err := rsl.InitializeNamespace(r); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, refName, 1, gpgKeyBytes)
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := repo.RecordRSLEntryForPlatformPush(testCtx, signer, refName, commitIDs[0], "github", "alice", false)
+	assert.Nil(t, err)
+	assert.True(t, recorded)
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(r, refName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, commitIDs[0], entry.TargetID)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := allAttestations.GetPushEventAttestationFor(r, entry.ID.String())
+	assert.Nil(t, err)
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement := struct {
+		Predicate *attestations.PushEvent `json:"predicate"`
+	}{}
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "github", statement.Predicate.Platform)
+	assert.Equal(t, "alice", statement.Predicate.Pusher)
+
+	// The push is already recorded
+	recorded, err = repo.RecordRSLEntryForPlatformPush(testCtx, signer, refName, commitIDs[0], "github", "alice", false)
+	assert.Nil(t, err)
+	assert.False(t, recorded)
+
+	// Deletions are recorded without an attestation when no signer is
+	// specified
+	recorded, err = repo.RecordRSLEntryForPlatformPush(testCtx, nil, refName, plumbing.ZeroHash, "github", "alice", false)
+	assert.Nil(t, err)
+	assert.True(t, recorded)
+
+	_, err = repo.RecordRSLEntryForPlatformPush(testCtx, nil, refName, plumbing.NewHash("abcdef1234567890"), "github", "alice", false)
+	assert.NotNil(t, err)
+}
+
 func TestRecordRSLEntryForReferenceAtTarget(t *testing.T) {
 	t.Setenv(dev.DevModeKey, "1")
 
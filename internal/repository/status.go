@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// RSLSyncStatus describes how the local RSL relates to a remote's RSL, as of
+// the last time it was fetched.
+type RSLSyncStatus string
+
+const (
+	RSLSyncStatusUnknown  RSLSyncStatus = "unknown"
+	RSLSyncStatusUpToDate RSLSyncStatus = "up to date"
+	RSLSyncStatusAhead    RSLSyncStatus = "ahead"
+	RSLSyncStatusBehind   RSLSyncStatus = "behind"
+	RSLSyncStatusDiverged RSLSyncStatus = "diverged"
+)
+
+// Status summarizes the state of gittuf in a repository.
+type Status struct {
+	// Enabled indicates if the repository has the RSL and an applied policy.
+	// If it doesn't, only the signing configuration is reported.
+	Enabled bool
+
+	// RootExpires and PolicyExpires are the expiry dates of the root of trust
+	// and the top level policy file in the applied policy. PolicyExpires is
+	// zero if the top level policy file hasn't been initialized.
+	RootExpires   time.Time
+	PolicyExpires time.Time
+
+	// HasStagedPolicyChanges indicates if the policy staging area contains
+	// changes that haven't been applied yet.
+	HasStagedPolicyChanges bool
+
+	RSLTip       plumbing.Hash
+	Remote       string
+	RemoteRSLTip plumbing.Hash
+	RSLSync      RSLSyncStatus
+
+	// UnrecordedRefs lists the local branches and tags whose current state
+	// isn't recorded in the RSL.
+	UnrecordedRefs []string
+
+	// SigningProgram is the program used to sign commits as configured in
+	// Git. SigningError is set if signing isn't viable.
+	SigningProgram string
+	SigningError   error
+}
+
+// Status returns a summary of the state of gittuf in the repository. The local
+// RSL is compared against the last fetched state of the specified remote's
+// RSL; the remote itself is not contacted.
+func (r *Repository) Status(ctx context.Context, remoteName string) (*Status, error) {
+	status := &Status{Remote: remoteName, RSLSync: RSLSyncStatusUnknown}
+
+	logger.Debug("Checking signing configuration...")
+	status.SigningProgram, _, status.SigningError = gitinterface.GetSigningCommand()
+	if status.SigningError == nil && !gitinterface.IsBuiltInSigningProgram(status.SigningProgram) {
+		if _, err := exec.LookPath(status.SigningProgram); err != nil {
+			status.SigningError = err
+		}
+	}
+
+	logger.Debug("Checking if repository uses gittuf...")
+	rslTip, err := gitinterface.GetTip(r.r, rsl.Ref)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return status, nil
+		}
+		return nil, err
+	}
+
+	policyState, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return status, nil
+		}
+		return nil, err
+	}
+
+	status.Enabled = true
+	status.RSLTip = rslTip
+
+	logger.Debug("Checking policy expiry...")
+	rootMetadata, err := policyState.GetRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+	status.RootExpires, err = time.Parse(time.RFC3339, rootMetadata.Expires)
+	if err != nil {
+		return nil, err
+	}
+
+	if policyState.TargetsEnvelope != nil {
+		targetsMetadata, err := policyState.GetTargetsMetadata(policy.TargetsRoleName)
+		if err != nil {
+			return nil, err
+		}
+		status.PolicyExpires, err = time.Parse(time.RFC3339, targetsMetadata.Expires)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Debug("Checking for staged policy changes...")
+	policyTip, err := gitinterface.GetTip(r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+	policyStagingTip, err := gitinterface.GetTip(r.r, policy.PolicyStagingRef)
+	if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, err
+	}
+	status.HasStagedPolicyChanges = !policyStagingTip.IsZero() && policyStagingTip != policyTip
+
+	logger.Debug("Comparing RSL with remote...")
+	status.RemoteRSLTip, status.RSLSync, err = r.compareRSLWithRemote(rslTip, remoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Checking for changes not recorded in the RSL...")
+	status.UnrecordedRefs, err = r.findUnrecordedRefs()
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func (r *Repository) compareRSLWithRemote(rslTip plumbing.Hash, remoteName string) (plumbing.Hash, RSLSyncStatus, error) {
+	remoteRSLTip, err := gitinterface.GetTip(r.r, rsl.RemoteTrackerRef(remoteName))
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return plumbing.ZeroHash, RSLSyncStatusUnknown, nil
+		}
+		return plumbing.ZeroHash, "", err
+	}
+
+	if remoteRSLTip == rslTip {
+		return remoteRSLTip, RSLSyncStatusUpToDate, nil
+	}
+
+	remoteRSLTipCommit, err := gitinterface.GetCommit(r.r, remoteRSLTip)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	ahead, err := gitinterface.KnowsCommit(r.r, rslTip, remoteRSLTipCommit)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	if ahead {
+		return remoteRSLTip, RSLSyncStatusAhead, nil
+	}
+
+	rslTipCommit, err := gitinterface.GetCommit(r.r, rslTip)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	behind, err := gitinterface.KnowsCommit(r.r, remoteRSLTip, rslTipCommit)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	if behind {
+		return remoteRSLTip, RSLSyncStatusBehind, nil
+	}
+
+	return remoteRSLTip, RSLSyncStatusDiverged, nil
+}
+
+// findUnrecordedRefs returns the local branches and tags whose tips don't
+// match the targets of their latest RSL entries.
+func (r *Repository) findUnrecordedRefs() ([]string, error) {
+	recordedTargets, err := r.latestRecordedTargets()
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := r.r.References()
+	if err != nil {
+		return nil, err
+	}
+	defer refs.Close()
+
+	unrecordedRefs := []string{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		refName := ref.Name().String()
+		if ref.Type() != plumbing.HashReference || !(strings.HasPrefix(refName, gitinterface.BranchRefPrefix) || strings.HasPrefix(refName, gitinterface.TagRefPrefix)) {
+			return nil
+		}
+
+		if recordedTargets[refName] != ref.Hash() {
+			unrecordedRefs = append(unrecordedRefs, refName)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(unrecordedRefs)
+	return unrecordedRefs, nil
+}
+
+// latestRecordedTargets returns the target of the latest RSL entry for each
+// ref recorded in the RSL. Entries skipped by an annotation are ignored, as they
+// are during verification.
+func (r *Repository) latestRecordedTargets() (map[string]plumbing.Hash, error) {
+	recordedTargets := map[string]plumbing.Hash{}
+	annotations := []*rsl.AnnotationEntry{}
+
+	// Annotations are always recorded after the entries they refer to, so
+	// they're seen first when walking back from the latest entry
+	entry, err := rsl.GetLatestEntry(r.r)
+	for err == nil {
+		switch entry := entry.(type) {
+		case *rsl.AnnotationEntry:
+			annotations = append(annotations, entry)
+		case *rsl.ReferenceEntry:
+			if _, has := recordedTargets[entry.RefName]; !has && !entry.SkippedBy(annotations) {
+				recordedTargets[entry.RefName] = entry.TargetID
+			}
+		}
+
+		entry, err = rsl.GetParentForEntry(r.r, entry)
+	}
+	if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return nil, err
+	}
+
+	return recordedTargets, nil
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	remoteName := "origin"
+
+	t.Run("repository without gittuf", func(t *testing.T) {
+		r, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo := &Repository{r: r}
+
+		status, err := repo.Status(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.False(t, status.Enabled)
+		assert.True(t, status.RootExpires.IsZero())
+	})
+
+	t.Run("repository with gittuf", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		status, err := repo.Status(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.True(t, status.Enabled)
+		assert.False(t, status.RootExpires.IsZero())
+		assert.False(t, status.PolicyExpires.IsZero())
+		assert.False(t, status.HasStagedPolicyChanges)
+		assert.Equal(t, RSLSyncStatusUnknown, status.RSLSync)
+		assert.Empty(t, status.UnrecordedRefs)
+
+		// Simulate fetching the remote's RSL
+		rslTip := status.RSLTip
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.RemoteTrackerRef(remoteName)), rslTip)); err != nil {
+			t.Fatal(err)
+		}
+
+		status, err = repo.Status(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.Equal(t, RSLSyncStatusUpToDate, status.RSLSync)
+
+		refName := "refs/heads/main"
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), rslTip)); err != nil {
+			t.Fatal(err)
+		}
+
+		status, err = repo.Status(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{refName}, status.UnrecordedRefs)
+
+		if err := repo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		status, err = repo.Status(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.Empty(t, status.UnrecordedRefs)
+		assert.Equal(t, RSLSyncStatusAhead, status.RSLSync)
+		assert.Equal(t, rslTip, status.RemoteRSLTip)
+
+		// Skipped entries don't count as recording the ref
+		latestEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.NewAnnotationEntry([]plumbing.Hash{latestEntry.GetID()}, true, "skip").Commit(repo.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		status, err = repo.Status(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{refName}, status.UnrecordedRefs)
+
+		if err := repo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		// Simulate the remote's RSL moving ahead
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.RemoteTrackerRef(remoteName)), status.RSLTip)); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(rsl.Ref, rslTip)); err != nil {
+			t.Fatal(err)
+		}
+
+		status, err = repo.Status(testCtx, remoteName)
+		assert.Nil(t, err)
+		assert.Equal(t, RSLSyncStatusBehind, status.RSLSync)
+	})
+}
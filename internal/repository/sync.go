@@ -6,30 +6,45 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
 	"os"
 	"reflect"
 	"sort"
 	"strings"
 
+	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/timestamp"
 	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 )
 
+// gittufRefs matches all of gittuf's refs when fetching.
+const gittufRefs = "refs/gittuf/*"
+
+// maxPushAttempts is the number of times Push records RSL entries and pushes
+// when the remote RSL is updated concurrently.
+const maxPushAttempts = 3
+
 var (
 	ErrCloningRepository          = errors.New("unable to clone repository")
 	ErrDirExists                  = errors.New("directory exists")
 	ErrExpectedRootKeysDoNotMatch = errors.Join(ErrCloningRepository, errors.New("cloned root keys do not match the expected keys"))
+	ErrRemoteRSLUpdatedRepeatedly = errors.New("remote RSL was updated by others during every push attempt")
+	ErrWorktreeNotClean           = errors.New("working tree has uncommitted changes")
 )
 
 // Clone wraps a typical git clone invocation, fetching gittuf refs in addition
 // to the standard refs. It performs a verification of the RSL against the
-// specified HEAD after cloning the repository.
-// TODO: resolve how root keys are trusted / bootstrapped.
+// specified HEAD after cloning the repository. If expectedRootKeys are
+// specified, the root keys in the repository's first policy state must match
+// them, otherwise the first policy state is trusted on first use. The working
+// tree is only checked out once verification succeeds. If verification fails,
+// the cloned repository is removed.
 func Clone(ctx context.Context, remoteURL, dir, initialBranch string, expectedRootKeys []*tuf.Key) (*Repository, error) {
-	slog.Debug(fmt.Sprintf("Cloning from '%s'...", remoteURL))
+	logger.Debug(fmt.Sprintf("Cloning from '%s'...", remoteURL))
 
 	if dir == "" {
 		// FIXME: my understanding is backslashes are not used in URLs but I haven't dived into the RFCs to check yet
@@ -40,7 +55,7 @@ func Clone(ctx context.Context, remoteURL, dir, initialBranch string, expectedRo
 		dir = strings.TrimSuffix(split[len(split)-1], ".git")
 	}
 
-	slog.Debug("Checking if local directory exists for repository...")
+	logger.Debug("Checking if local directory exists for repository...")
 	_, err := os.Stat(dir)
 	if err == nil {
 		return nil, errors.Join(ErrCloningRepository, ErrDirExists)
@@ -52,9 +67,9 @@ func Clone(ctx context.Context, remoteURL, dir, initialBranch string, expectedRo
 		return nil, errors.Join(ErrCloningRepository, err)
 	}
 
-	refs := []string{"refs/gittuf/*"}
+	refs := []string{gittufRefs}
 
-	slog.Debug("Cloning repository...")
+	logger.Debug("Cloning repository...")
 	r, err := gitinterface.CloneAndFetch(ctx, remoteURL, dir, initialBranch, refs)
 	if err != nil {
 		if e := os.RemoveAll(dir); e != nil {
@@ -62,42 +77,334 @@ func Clone(ctx context.Context, remoteURL, dir, initialBranch string, expectedRo
 		}
 		return nil, errors.Join(ErrCloningRepository, err)
 	}
-	head, err := r.Reference(plumbing.HEAD, false)
-	if err != nil {
+
+	repository := &Repository{r: r}
+
+	if err := verifyClone(ctx, repository, expectedRootKeys); err != nil {
+		if e := os.RemoveAll(dir); e != nil {
+			return nil, errors.Join(err, e)
+		}
+		return nil, err
+	}
+
+	logger.Debug("Checking out working tree...")
+	if err := gitinterface.CheckoutHead(r); err != nil {
 		return nil, errors.Join(ErrCloningRepository, err)
 	}
 
-	repository := &Repository{r: r}
+	return repository, nil
+}
+
+// verifyClone checks the root keys of the cloned repository against the
+// expected root keys, if any, and verifies the RSL against HEAD.
+func verifyClone(ctx context.Context, repository *Repository, expectedRootKeys []*tuf.Key) error {
+	head, err := repository.r.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return errors.Join(ErrCloningRepository, err)
+	}
+
+	if err := repository.checkFetchedRSL(plumbing.ZeroHash); err != nil {
+		return errors.Join(ErrCloningRepository, err)
+	}
 
 	if len(expectedRootKeys) > 0 {
-		slog.Debug("Verifying if root keys are expected root keys...")
+		logger.Debug("Verifying if root keys are expected root keys...")
+
+		state, err := policy.LoadFirstState(ctx, repository.r)
+		if err != nil {
+			return errors.Join(ErrCloningRepository, err)
+		}
+		match, err := rootKeysMatch(state, expectedRootKeys)
+		if err != nil {
+			return errors.Join(ErrCloningRepository, err)
+		}
+		if !match {
+			return ErrExpectedRootKeysDoNotMatch
+		}
+	}
+
+	logger.Debug("Verifying HEAD...")
+	return repository.VerifyRef(ctx, head.Target().String(), false)
+}
+
+// rootKeysMatch checks whether the root keys in the policy state are the
+// expected root keys.
+func rootKeysMatch(state *policy.State, expectedRootKeys []*tuf.Key) (bool, error) {
+	rootKeys, err := state.GetRootKeys()
+	if err != nil {
+		return false, err
+	}
+
+	// We sort the root keys so that we can check if the root keys array match's the expected root key array
+	sort.Slice(expectedRootKeys, func(i, j int) bool {
+		return expectedRootKeys[i].KeyID < expectedRootKeys[j].KeyID
+	})
+	sort.Slice(rootKeys, func(i, j int) bool {
+		return rootKeys[i].KeyID < rootKeys[j].KeyID
+	})
+
+	if len(rootKeys) != len(expectedRootKeys) {
+		return false, nil
+	}
+	return reflect.DeepEqual(rootKeys, expectedRootKeys), nil
+}
+
+// Push pushes the specified refs to the remote along with RSL entries recording
+// their new states. The remote's RSL is pulled before the entries are recorded.
+// If the remote's RSL is updated by someone else before the push completes, the
+// recorded entries are discarded and the process is repeated.
+func (r *Repository) Push(ctx context.Context, remoteName string, refNames []string, signCommit bool) error {
+	absRefNames, err := r.absoluteReferences(refNames)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; attempt <= maxPushAttempts; attempt++ {
+		logger.Debug(fmt.Sprintf("Pulling RSL from '%s'...", remoteName))
+		if err := r.PullRSL(ctx, remoteName); err != nil && !errors.Is(err, git.NoMatchingRefSpecError{}) {
+			return err
+		}
 
-		sort.Slice(expectedRootKeys, func(i, j int) bool {
-			return expectedRootKeys[i].KeyID < expectedRootKeys[j].KeyID
-		})
+		// Timestamps for the entries are recorded in the attestations
+		// namespace, which is pushed along with the RSL
+		gittufRefNames := []string{rsl.Ref}
+		if timestamp.GetTimestamper() != nil && signCommit {
+			gittufRefNames = append(gittufRefNames, attestations.Ref)
+		}
 
-		state, err := policy.LoadFirstState(ctx, r)
+		tips, err := r.getTips(gittufRefNames)
 		if err != nil {
-			return repository, errors.Join(ErrCloningRepository, err)
+			return err
 		}
-		rootKeys, err := state.GetRootKeys()
+
+		for _, refName := range absRefNames {
+			logger.Debug(fmt.Sprintf("Recording RSL entry for '%s'...", refName))
+			if err := r.RecordRSLEntryForReference(refName, signCommit); err != nil {
+				return r.restoreTips(tips, err)
+			}
+		}
+
+		logger.Debug(fmt.Sprintf("Pushing to '%s'...", remoteName))
+		pushErr := gitinterface.Push(ctx, r.r, remoteName, append(absRefNames, gittufRefNames...))
+		if pushErr == nil {
+			return nil
+		}
+
+		// The push is atomic, so if the remote's RSL moved, the refs weren't
+		// updated either and the local entries can be recorded again on top
+		// of the remote's RSL
+		hasUpdates, _, err := r.CheckRemoteRSLForUpdates(ctx, remoteName)
 		if err != nil {
-			return repository, errors.Join(ErrCloningRepository, err)
+			return r.restoreTips(tips, errors.Join(ErrPushingRSL, pushErr, err))
+		}
+		if err := r.restoreTips(tips, nil); err != nil {
+			return err
 		}
+		if !hasUpdates {
+			return errors.Join(ErrPushingRSL, pushErr)
+		}
+
+		logger.Debug(fmt.Sprintf("Remote RSL was updated during attempt %d, retrying...", attempt))
+	}
+
+	return errors.Join(ErrPushingRSL, ErrRemoteRSLUpdatedRepeatedly)
+}
+
+// RecordRSLEntriesForPush records RSL entries for the ref updates of a push
+// before the refs and the RSL are pushed, for pushes that aren't made by Push,
+// such as those made through gittuf's Git remote helper. Deletions and updates
+// to gittuf refs are not recorded. The returned function restores the RSL to
+// its prior state, and is used if the push fails.
+func (r *Repository) RecordRSLEntriesForPush(updates []*RefUpdate, signCommit bool) (func() error, error) {
+	tips, err := r.getTips([]string{rsl.Ref})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, update := range updates {
+		if update.IsDeletion() || strings.HasPrefix(update.RefName, gittufNamespacePrefix) {
+			continue
+		}
+
+		isDuplicate, err := r.isDuplicateEntry(update.RefName, update.NewID)
+		if err != nil {
+			return nil, r.restoreTips(tips, err)
+		}
+		if isDuplicate {
+			continue
+		}
+
+		logger.Debug(fmt.Sprintf("Recording RSL entry for '%s'...", update.RefName))
+		if err := rsl.NewReferenceEntry(update.RefName, update.NewID).Commit(r.r, signCommit); err != nil {
+			return nil, r.restoreTips(tips, err)
+		}
+	}
 
-		// We sort the root keys so that we can check if the root keys array match's the expected root key array
-		sort.Slice(rootKeys, func(i, j int) bool {
-			return rootKeys[i].KeyID < rootKeys[j].KeyID
-		})
+	return func() error {
+		return r.restoreTips(tips, nil)
+	}, nil
+}
+
+// Pull fetches the specified refs and the RSL from the remote, and verifies the
+// new states of the refs against the policy. If verification fails, the refs
+// and the RSL are restored to their prior states. If the checked out ref is
+// updated in a repository with a working tree, the working tree is updated as
+// well, which requires it to not have uncommitted changes. Only fast-forward
+// updates are fetched.
+func (r *Repository) Pull(ctx context.Context, remoteName string, refNames []string) error {
+	absRefNames, err := r.absoluteReferences(refNames)
+	if err != nil {
+		return err
+	}
+
+	head, err := r.r.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return err
+	}
+	checkedOutRef := ""
+	if !gitinterface.IsBare(r.r) {
+		for _, refName := range absRefNames {
+			if head.Type() == plumbing.SymbolicReference && head.Target().String() == refName {
+				checkedOutRef = refName
+			}
+		}
+	}
+	if checkedOutRef != "" {
+		if err := r.checkWorktreeClean(); err != nil {
+			return err
+		}
+	}
+
+	tips, err := r.getTips(append([]string{rsl.Ref}, absRefNames...))
+	if err != nil {
+		return err
+	}
+
+	// The policy and attestations are fetched along with the RSL as they're
+	// needed to verify the refs
+	logger.Debug(fmt.Sprintf("Fetching from '%s'...", remoteName))
+	if err := gitinterface.Fetch(ctx, r.r, remoteName, append([]string{gittufRefs}, absRefNames...), true); err != nil {
+		return errors.Join(ErrPullingRSL, err)
+	}
+
+	if err := r.checkFetchedRSL(tips[rsl.Ref]); err != nil {
+		return r.restoreTips(tips, err)
+	}
+
+	for _, refName := range absRefNames {
+		logger.Debug(fmt.Sprintf("Verifying '%s'...", refName))
+		if err := r.VerifyRef(ctx, refName, false); err != nil {
+			return r.restoreTips(tips, err)
+		}
+	}
+
+	if checkedOutRef != "" {
+		newTip, err := gitinterface.GetTip(r.r, checkedOutRef)
+		if err != nil {
+			return err
+		}
+		if newTip != tips[checkedOutRef] {
+			logger.Debug("Updating working tree...")
+			return gitinterface.CheckoutHead(r.r)
+		}
+	}
+
+	return nil
+}
+
+// checkFetchedRSL checks the integrity of the RSL entries fetched after
+// oldRSLTip and the objects they record, see checkFetchedObjects.
+func (r *Repository) checkFetchedRSL(oldRSLTip plumbing.Hash) error {
+	newRSLTip, err := gitinterface.GetTip(r.r, rsl.Ref)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil
+		}
+		return err
+	}
+	if newRSLTip == oldRSLTip {
+		return nil
+	}
+
+	logger.Debug("Checking integrity of fetched objects...")
+	return r.checkFetchedObjects(oldRSLTip, newRSLTip)
+}
+
+// absoluteReferences returns the fully qualified names of the refs, defaulting
+// to the checked out ref if none are specified.
+func (r *Repository) absoluteReferences(refNames []string) ([]string, error) {
+	if len(refNames) == 0 {
+		refNames = []string{plumbing.HEAD.String()}
+	}
+
+	absRefNames := make([]string, 0, len(refNames))
+	for _, refName := range refNames {
+		absRefName, err := gitinterface.AbsoluteReference(r.r, refName)
+		if err != nil {
+			return nil, err
+		}
+		absRefNames = append(absRefNames, absRefName)
+	}
+
+	return absRefNames, nil
+}
+
+// getTips returns the tips of the specified refs. Refs that don't exist have
+// a zero hash.
+func (r *Repository) getTips(refNames []string) (map[string]plumbing.Hash, error) {
+	tips := make(map[string]plumbing.Hash, len(refNames))
+	for _, refName := range refNames {
+		tip, err := gitinterface.GetTip(r.r, refName)
+		if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil, err
+		}
+		tips[refName] = tip
+	}
+
+	return tips, nil
+}
+
+// restoreTips resets the refs to the specified tips, removing refs with a zero
+// hash. It returns the cause, if any, joined with errors encountered while
+// restoring.
+func (r *Repository) restoreTips(tips map[string]plumbing.Hash, cause error) error {
+	for refName, tip := range tips {
+		var err error
+		if tip.IsZero() {
+			err = r.r.Storer.RemoveReference(plumbing.ReferenceName(refName))
+		} else {
+			err = r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), tip))
+		}
+		if err != nil {
+			cause = errors.Join(cause, fmt.Errorf("unable to restore '%s' to '%s': %w", refName, tip.String(), err))
+		}
+	}
+
+	return cause
+}
+
+// checkWorktreeClean returns ErrWorktreeNotClean if tracked files in the
+// working tree or the index have changes. Untracked files are ignored as
+// they're not touched when the working tree is updated.
+func (r *Repository) checkWorktreeClean() error {
+	worktree, err := r.r.Worktree()
+	if err != nil {
+		return err
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return err
+	}
 
-		if len(rootKeys) != len(expectedRootKeys) {
-			return repository, ErrExpectedRootKeysDoNotMatch
+	for _, fileStatus := range status {
+		if fileStatus.Worktree == git.Untracked {
+			continue
 		}
-		if !reflect.DeepEqual(rootKeys, expectedRootKeys) {
-			return repository, ErrExpectedRootKeysDoNotMatch
+		if fileStatus.Staging != git.Unmodified || fileStatus.Worktree != git.Unmodified {
+			return ErrWorktreeNotClean
 		}
 	}
 
-	slog.Debug("Verifying HEAD...")
-	return repository, repository.VerifyRef(ctx, head.Target().String(), false)
+	return nil
 }
@@ -5,6 +5,7 @@ package repository
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
@@ -13,8 +14,13 @@ import (
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -257,5 +263,223 @@ func TestClone(t *testing.T) {
 
 		_, err = Clone(context.Background(), remoteTmpDir, "", "", []*tuf.Key{rootPublicKey, badPublicKey})
 		assert.ErrorIs(t, ErrExpectedRootKeysDoNotMatch, err)
+
+		// The clone must be removed
+		_, err = os.Stat(filepath.Base(remoteTmpDir))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("unsuccessful clone of repository without gittuf metadata", func(t *testing.T) {
+		localTmpDir := t.TempDir()
+
+		if err := os.Chdir(localTmpDir); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(currentDir) //nolint:errcheck
+
+		plainRemoteTmpDir := t.TempDir()
+		plainRemoteRepo, err := git.PlainInit(plainRemoteTmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plainEmptyTreeHash, err := gitinterface.WriteTree(plainRemoteRepo, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gitinterface.Commit(plainRemoteRepo, plainEmptyTreeHash, refName, "Initial commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := plainRemoteRepo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(refName))); err != nil {
+			t.Fatal(err)
+		}
+
+		dirName := "myRepo"
+		repo, err := Clone(context.Background(), plainRemoteTmpDir, dirName, "", nil)
+		assert.NotNil(t, err)
+		assert.Nil(t, repo)
+
+		// The clone must be removed
+		_, err = os.Stat(dirName)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestPush(t *testing.T) {
+	remoteName := "origin"
+	refName := "refs/heads/feature"
+
+	t.Run("successful push, remote RSL updated by others", func(t *testing.T) {
+		remoteTmpDir := t.TempDir()
+		remoteRepo := createTestRepositoryWithPolicy(t, remoteTmpDir)
+
+		localRepo := createTestLocalRepositoryWithRemote(t, remoteName, remoteTmpDir)
+		if err := gitinterface.Fetch(context.Background(), localRepo.r, remoteName, []string{gittufRefs}, true); err != nil {
+			t.Fatal(err)
+		}
+
+		// Someone else records an entry in the remote's RSL
+		emptyTreeHash, err := gitinterface.WriteTree(remoteRepo.r, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gitinterface.Commit(remoteRepo.r, emptyTreeHash, "refs/heads/other", "Other commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLEntryForReference("refs/heads/other", false); err != nil {
+			t.Fatal(err)
+		}
+		otherEntry, err := rsl.GetLatestEntry(remoteRepo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		localEmptyTreeHash, err := gitinterface.WriteTree(localRepo.r, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitID, err := gitinterface.Commit(localRepo.r, localEmptyTreeHash, refName, "Initial commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = localRepo.Push(context.Background(), remoteName, []string{refName}, false)
+		assert.Nil(t, err)
+
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, rsl.Ref)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, refName)
+
+		latestEntry, err := rsl.GetLatestEntry(remoteRepo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, refName, latestEntry.(*rsl.ReferenceEntry).RefName)
+		assert.Equal(t, commitID, latestEntry.(*rsl.ReferenceEntry).TargetID)
+
+		parentEntry, err := rsl.GetParentForEntry(remoteRepo.r, latestEntry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, otherEntry.GetID(), parentEntry.GetID())
+
+		// No updates, successful push
+		err = localRepo.Push(context.Background(), remoteName, []string{refName}, false)
+		assert.Nil(t, err)
 	})
+
+	t.Run("divergent RSLs, unsuccessful push", func(t *testing.T) {
+		remoteTmpDir := t.TempDir()
+		remoteRepo := createTestRepositoryWithPolicy(t, remoteTmpDir)
+
+		localRepo := createTestRepositoryWithPolicy(t, "")
+
+		// The two RSLs may otherwise be identical if they're created within
+		// the same second
+		if err := rsl.NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(remoteRepo.r, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash).Commit(localRepo.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := localRepo.r.CreateRemote(&config.RemoteConfig{
+			Name: remoteName,
+			URLs: []string{remoteTmpDir},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		err := localRepo.Push(context.Background(), remoteName, []string{policy.PolicyRef}, false)
+		assert.ErrorIs(t, err, ErrPullingRSL)
+	})
+}
+
+func TestPull(t *testing.T) {
+	remoteName := "origin"
+
+	t.Run("successful pull of checked out branch", func(t *testing.T) {
+		refName := "refs/heads/feature"
+
+		remoteTmpDir := t.TempDir()
+		remoteRepo := createTestRepositoryWithPolicy(t, remoteTmpDir)
+
+		blobID, err := gitinterface.WriteBlob(remoteRepo.r, []byte("Hello, world!\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		treeHash, err := gitinterface.WriteTree(remoteRepo.r, []object.TreeEntry{{Name: "README.md", Mode: filemode.Regular, Hash: blobID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gitinterface.Commit(remoteRepo.r, treeHash, refName, "Initial commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		localRepo := createTestLocalRepositoryWithRemote(t, remoteName, remoteTmpDir)
+		if err := localRepo.r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(refName))); err != nil {
+			t.Fatal(err)
+		}
+
+		err = localRepo.Pull(context.Background(), remoteName, nil)
+		assert.Nil(t, err)
+
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, rsl.Ref)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, refName)
+
+		worktree, err := localRepo.r.Worktree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = worktree.Filesystem.Stat("README.md")
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful pull, verification fails", func(t *testing.T) {
+		refName := "refs/heads/main"
+
+		remoteTmpDir := t.TempDir()
+		remoteRepo := createTestRepositoryWithPolicy(t, remoteTmpDir)
+
+		// The commit isn't signed by the key trusted for main
+		emptyTreeHash, err := gitinterface.WriteTree(remoteRepo.r, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gitinterface.Commit(remoteRepo.r, emptyTreeHash, refName, "Initial commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		localRepo := createTestLocalRepositoryWithRemote(t, remoteName, remoteTmpDir)
+
+		err = localRepo.Pull(context.Background(), remoteName, []string{refName})
+		assert.NotNil(t, err)
+
+		// The refs are restored
+		_, err = localRepo.r.Reference(plumbing.ReferenceName(refName), true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+		_, err = localRepo.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+}
+
+func createTestLocalRepositoryWithRemote(t *testing.T, remoteName, remoteURL string) *Repository {
+	t.Helper()
+
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.CreateRemote(&config.RemoteConfig{
+		Name: remoteName,
+		URLs: []string{remoteURL},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	return &Repository{r: r}
 }
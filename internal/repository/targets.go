@@ -6,7 +6,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
 
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
@@ -28,7 +27,7 @@ func (r *Repository) InitializeTargets(ctx context.Context, signer sslibdsse.Sig
 		return nil
 	}
 
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
@@ -42,7 +41,7 @@ func (r *Repository) InitializeTargets(ctx context.Context, signer sslibdsse.Sig
 	// assume which role is the delegating role (diamond delegations are legal).
 	// See: https://github.com/gittuf/gittuf/issues/246.
 
-	slog.Debug("Creating initial rule file...")
+	logger.Debug("Creating initial rule file...")
 	targetsMetadata := policy.InitializeTargetsMetadata()
 
 	env, err := dsse.CreateEnvelope(targetsMetadata)
@@ -50,7 +49,7 @@ func (r *Repository) InitializeTargets(ctx context.Context, signer sslibdsse.Sig
 		return nil
 	}
 
-	slog.Debug(fmt.Sprintf("Signing initial rule file using '%s'...", keyID))
+	logger.Debug(fmt.Sprintf("Signing initial rule file using '%s'...", keyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
 		return nil
@@ -67,8 +66,8 @@ func (r *Repository) InitializeTargets(ctx context.Context, signer sslibdsse.Sig
 
 	commitMessage := fmt.Sprintf("Initialize policy '%s'", targetsRoleName)
 
-	slog.Debug("Committing policy...")
-	return state.Commit(r.r, commitMessage, signCommit)
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
 
 // AddDelegation is the interface for the user to add a new rule to gittuf
@@ -83,18 +82,23 @@ func (r *Repository) AddDelegation(ctx context.Context, signer sslibdsse.SignerV
 		return nil
 	}
 
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug("Checking if rule with same name exists...")
+	logger.Debug("Checking identities for look-alikes...")
+	if err := checkIdentities(state, authorizedKeys, rulePatterns); err != nil {
+		return err
+	}
+
+	logger.Debug("Checking if rule with same name exists...")
 	if state.HasRuleName(ruleName) {
 		return policy.ErrDuplicatedRuleName
 	}
 
-	slog.Debug("Loading current rule file...")
+	logger.Debug("Loading current rule file...")
 	if !state.HasTargetsRole(targetsRoleName) {
 		return policy.ErrMetadataNotFound
 	}
@@ -109,7 +113,7 @@ func (r *Repository) AddDelegation(ctx context.Context, signer sslibdsse.SignerV
 		return err
 	}
 
-	slog.Debug("Adding rule to rule file...")
+	logger.Debug("Adding rule to rule file...")
 	targetsMetadata, err = policy.AddDelegation(targetsMetadata, ruleName, authorizedKeys, rulePatterns, threshold)
 	if err != nil {
 		return err
@@ -122,7 +126,7 @@ func (r *Repository) AddDelegation(ctx context.Context, signer sslibdsse.SignerV
 		return nil
 	}
 
-	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
 		return nil
@@ -136,8 +140,8 @@ func (r *Repository) AddDelegation(ctx context.Context, signer sslibdsse.SignerV
 
 	commitMessage := fmt.Sprintf("Add rule '%s' to policy '%s'", ruleName, targetsRoleName)
 
-	slog.Debug("Committing policy...")
-	return state.Commit(r.r, commitMessage, signCommit)
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
 
 // UpdateDelegation is the interface for the user to update a rule to gittuf
@@ -152,13 +156,18 @@ func (r *Repository) UpdateDelegation(ctx context.Context, signer sslibdsse.Sign
 		return nil
 	}
 
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug("Loading current rule file...")
+	logger.Debug("Checking identities for look-alikes...")
+	if err := checkIdentities(state, authorizedKeys, rulePatterns); err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current rule file...")
 	if !state.HasTargetsRole(targetsRoleName) {
 		return policy.ErrMetadataNotFound
 	}
@@ -173,7 +182,7 @@ func (r *Repository) UpdateDelegation(ctx context.Context, signer sslibdsse.Sign
 		return err
 	}
 
-	slog.Debug("Updating rule in rule file...")
+	logger.Debug("Updating rule in rule file...")
 	targetsMetadata, err = policy.UpdateDelegation(targetsMetadata, ruleName, authorizedKeys, rulePatterns, threshold)
 	if err != nil {
 		return err
@@ -186,7 +195,7 @@ func (r *Repository) UpdateDelegation(ctx context.Context, signer sslibdsse.Sign
 		return nil
 	}
 
-	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
 		return nil
@@ -200,8 +209,8 @@ func (r *Repository) UpdateDelegation(ctx context.Context, signer sslibdsse.Sign
 
 	commitMessage := fmt.Sprintf("Update rule '%s' in policy '%s'", ruleName, targetsRoleName)
 
-	slog.Debug("Committing policy...")
-	return state.Commit(r.r, commitMessage, signCommit)
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
 
 // RemoveDelegation is the interface for a user to remove a rule from gittuf
@@ -212,13 +221,13 @@ func (r *Repository) RemoveDelegation(ctx context.Context, signer sslibdsse.Sign
 		return nil
 	}
 
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug("Loading current rule file...")
+	logger.Debug("Loading current rule file...")
 	if !state.HasTargetsRole(targetsRoleName) {
 		return policy.ErrMetadataNotFound
 	}
@@ -233,7 +242,7 @@ func (r *Repository) RemoveDelegation(ctx context.Context, signer sslibdsse.Sign
 		return err
 	}
 
-	slog.Debug("Removing rule from rule file...")
+	logger.Debug("Removing rule from rule file...")
 	targetsMetadata, err = policy.RemoveDelegation(targetsMetadata, ruleName)
 	if err != nil {
 		return err
@@ -246,7 +255,7 @@ func (r *Repository) RemoveDelegation(ctx context.Context, signer sslibdsse.Sign
 		return nil
 	}
 
-	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
 		return nil
@@ -260,8 +269,8 @@ func (r *Repository) RemoveDelegation(ctx context.Context, signer sslibdsse.Sign
 
 	commitMessage := fmt.Sprintf("Remove rule '%s' from policy '%s'", ruleName, targetsRoleName)
 
-	slog.Debug("Committing policy...")
-	return state.Commit(r.r, commitMessage, signCommit)
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
 
 // AddKeyToTargets is the interface for a user to add a trusted key to the
@@ -272,11 +281,16 @@ func (r *Repository) AddKeyToTargets(ctx context.Context, signer sslibdsse.Signe
 		return nil
 	}
 
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
 	}
+
+	logger.Debug("Checking identities for look-alikes...")
+	if err := checkIdentities(state, authorizedKeys, nil); err != nil {
+		return err
+	}
 	if !state.HasTargetsRole(targetsRoleName) {
 		return policy.ErrMetadataNotFound
 	}
@@ -291,13 +305,13 @@ func (r *Repository) AddKeyToTargets(ctx context.Context, signer sslibdsse.Signe
 		keyIDs += fmt.Sprintf("\n%s:%s", key.KeyType, key.KeyID)
 	}
 
-	slog.Debug("Loading current rule file...")
+	logger.Debug("Loading current rule file...")
 	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug("Adding rule to rule file...")
+	logger.Debug("Adding rule to rule file...")
 	targetsMetadata, err = policy.AddKeyToTargets(targetsMetadata, authorizedKeys)
 	if err != nil {
 		return err
@@ -310,7 +324,7 @@ func (r *Repository) AddKeyToTargets(ctx context.Context, signer sslibdsse.Signe
 		return err
 	}
 
-	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
 		return err
@@ -324,14 +338,534 @@ func (r *Repository) AddKeyToTargets(ctx context.Context, signer sslibdsse.Signe
 
 	commitMessage := fmt.Sprintf("Add keys to policy '%s'\n%s", targetsRoleName, keyIDs)
 
-	slog.Debug("Committing policy...")
-	return state.Commit(r.r, commitMessage, signCommit)
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// SetPredicatePolicy is the interface for a user to set the keys trusted to
+// issue attestations of the specified predicate type, and the threshold of
+// signatures required from them. If a validator is specified, the predicate
+// plugin of that name must also accept the contents of the attestations.
+// Predicate policies are recorded in the top level Targets role.
+func (r *Repository) SetPredicatePolicy(ctx context.Context, signer sslibdsse.SignerVerifier, predicateType string, authorizedKeys []*tuf.Key, threshold int, validator string, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Checking identities for look-alikes...")
+	if err := checkIdentities(state, authorizedKeys, nil); err != nil {
+		return err
+	}
+	if !state.HasTargetsRole(policy.TargetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	logger.Debug("Loading current rule file...")
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Setting predicate policy in rule file...")
+	targetsMetadata, err = policy.SetPredicatePolicy(targetsMetadata, predicateType, authorizedKeys, threshold, validator)
+	if err != nil {
+		return err
+	}
+
+	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	state.TargetsEnvelope = env
+
+	commitMessage := fmt.Sprintf("Set policy for predicate type '%s'", predicateType)
+
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// RemovePredicatePolicy is the interface for a user to remove the policy for
+// the specified predicate type from the top level Targets role.
+func (r *Repository) RemovePredicatePolicy(ctx context.Context, signer sslibdsse.SignerVerifier, predicateType string, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+	if !state.HasTargetsRole(policy.TargetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	logger.Debug("Loading current rule file...")
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Removing predicate policy from rule file...")
+	targetsMetadata, err = policy.RemovePredicatePolicy(targetsMetadata, predicateType)
+	if err != nil {
+		return err
+	}
+
+	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	state.TargetsEnvelope = env
+
+	commitMessage := fmt.Sprintf("Remove policy for predicate type '%s'", predicateType)
+
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// SetRequiredHooks is the interface for a user to set the hooks that must have
+// been executed successfully for a change to be authorized using the specified
+// rule. An empty list of hooks removes the requirement.
+func (r *Repository) SetRequiredHooks(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, ruleName string, hookNames []string, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Setting required hooks for rule...")
+	targetsMetadata, err = policy.SetRequiredHooks(targetsMetadata, ruleName, hookNames)
+	if err != nil {
+		return err
+	}
+
+	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Set required hooks for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// SetRequiredEvaluators is the interface for a user to set the rule evaluator
+// plugins that must allow a change for it to be authorized using the specified
+// rule. An empty list of evaluators removes the requirement.
+func (r *Repository) SetRequiredEvaluators(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, ruleName string, evaluatorNames []string, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Setting required rule evaluators for rule...")
+	targetsMetadata, err = policy.SetRequiredEvaluators(targetsMetadata, ruleName, evaluatorNames)
+	if err != nil {
+		return err
+	}
+
+	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Set required rule evaluators for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// SetRequiredPredicates is the interface for a user to set the predicate types
+// of the in-toto statements that a change must have for it to be authorized
+// using the specified rule. An empty list of predicate types removes the
+// requirement.
+func (r *Repository) SetRequiredPredicates(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, ruleName string, predicateTypes []string, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Setting required predicate types for rule...")
+	targetsMetadata, err = policy.SetRequiredPredicates(targetsMetadata, ruleName, predicateTypes)
+	if err != nil {
+		return err
+	}
+
+	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Set required predicate types for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// SetRequiredApprovals is the interface for a user to require that a change is
+// approved in code review by a threshold of the principals authorized by the
+// approvers rule before it is authorized using the specified rule. A threshold
+// of zero removes the requirement.
+func (r *Repository) SetRequiredApprovals(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, ruleName, approversRuleName string, threshold int, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Setting required approvals for rule...")
+	targetsMetadata, err = policy.SetRequiredApprovals(targetsMetadata, ruleName, approversRuleName, threshold)
+	if err != nil {
+		return err
+	}
+
+	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Set required approvals for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// SetRequiredRebuilds is the interface for a user to set the artifacts that
+// must have been reproduced by independent rebuilders for a tag to be
+// authorized using the specified rule. An empty list of artifacts removes the
+// requirement.
+func (r *Repository) SetRequiredRebuilds(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, ruleName string, artifactNames []string, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Setting required rebuilds for rule...")
+	targetsMetadata, err = policy.SetRequiredRebuilds(targetsMetadata, ruleName, artifactNames)
+	if err != nil {
+		return err
+	}
+
+	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Set required rebuilds for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// SetRequireVerifiedSubmodule is the interface for a user to set whether
+// submodule pointers protected by the specified rule may only be updated to
+// commits that pass verification using the submodule's gittuf metadata.
+func (r *Repository) SetRequireVerifiedSubmodule(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, ruleName string, requireVerified bool, signCommit bool) error {
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Setting submodule verification requirement for rule...")
+	targetsMetadata, err = policy.SetRequireVerifiedSubmodule(targetsMetadata, ruleName, requireVerified)
+	if err != nil {
+		return err
+	}
+
+	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Require verified submodule commits for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	if !requireVerified {
+		commitMessage = fmt.Sprintf("Remove verified submodule commit requirement for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	}
+
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// SetAllowedSignatureMethods is the interface for a user to set the mechanisms
+// of Git signatures that count towards the threshold of the specified rule.
+// Each method is specified in the form accepted by
+// policy.ParseSignatureMethod, such as "ssh:algorithm=ed25519". An empty list
+// of methods removes the restriction.
+func (r *Repository) SetAllowedSignatureMethods(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, ruleName string, methodSpecs []string, signCommit bool) error {
+	methods := make([]tuf.SignatureMethod, 0, len(methodSpecs))
+	for _, spec := range methodSpecs {
+		method, err := policy.ParseSignatureMethod(spec)
+		if err != nil {
+			return err
+		}
+		methods = append(methods, method)
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Loading current rule file...")
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Setting allowed signature methods for rule...")
+	targetsMetadata, err = policy.SetAllowedSignatureMethods(targetsMetadata, ruleName, methods)
+	if err != nil {
+		return err
+	}
+
+	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.TargetsEnvelope = env
+	} else {
+		state.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Set allowed signature methods for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	if len(methods) == 0 {
+		commitMessage = fmt.Sprintf("Remove allowed signature methods restriction for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+	}
+
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
 
 // SignTargets adds a signature to specified Targets role's envelope. Note that
 // the metadata itself is not modified, so its version remains the same.
 func (r *Repository) SignTargets(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, signCommit bool) error {
-	slog.Debug("Loading current policy...")
+	logger.Debug("Loading current policy...")
 	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
 	if err != nil {
 		return err
@@ -352,7 +886,7 @@ func (r *Repository) SignTargets(ctx context.Context, signer sslibdsse.SignerVer
 		env = state.DelegationEnvelopes[targetsRoleName]
 	}
 
-	slog.Debug(fmt.Sprintf("Signing rule file using '%s'...", keyID))
+	logger.Debug(fmt.Sprintf("Signing rule file using '%s'...", keyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
 		return err
@@ -366,6 +900,6 @@ func (r *Repository) SignTargets(ctx context.Context, signer sslibdsse.SignerVer
 
 	commitMessage := fmt.Sprintf("Add signature from key '%s' to policy '%s'", keyID, targetsRoleName)
 
-	slog.Debug("Committing policy...")
-	return state.Commit(r.r, commitMessage, signCommit)
+	logger.Debug("Committing policy...")
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
@@ -264,6 +264,182 @@ func TestAddKeyToTargets(t *testing.T) {
 	assert.Equal(t, 2, len(targetsMetadata.Delegations.Keys))
 }
 
+func TestSetAndRemovePredicatePolicy(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsPubKey, err := tuf.LoadKeyFromBytes(targetsPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	predicateType := "https://slsa.dev/provenance/v1"
+
+	err = r.SetPredicatePolicy(testCtx, targetsSigner, predicateType, []*tuf.Key{targetsPubKey}, 1, "", false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := state.FindVerifierForPredicateType(predicateType)
+	assert.Nil(t, err)
+	assert.Equal(t, []*tuf.Key{targetsPubKey}, verifier.Keys())
+	assert.Equal(t, 1, verifier.Threshold())
+
+	err = r.RemovePredicatePolicy(testCtx, targetsSigner, predicateType, false)
+	assert.Nil(t, err)
+
+	state, err = policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = state.FindVerifierForPredicateType(predicateType)
+	assert.ErrorIs(t, err, policy.ErrPredicatePolicyNotFound)
+
+	err = r.RemovePredicatePolicy(testCtx, targetsSigner, predicateType, false)
+	assert.ErrorIs(t, err, policy.ErrPredicatePolicyNotFound)
+}
+
+func TestSetRequiredHooks(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetRequiredHooks(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", []string{"secret-scan"}, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiers, err := state.FindVerifiersForPath("git:refs/heads/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"secret-scan"}, verifiers[0].RequiredHooks())
+
+	err = r.SetRequiredHooks(testCtx, targetsSigner, policy.TargetsRoleName, "unknown-rule", []string{"secret-scan"}, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestSetRequiredApprovals(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetRequiredApprovals(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", "protect-main", 1, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiers, err := state.FindVerifiersForPath("git:refs/heads/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &tuf.ApprovalRequirement{Role: "protect-main", Threshold: 1}, verifiers[0].RequiredApprovals())
+
+	err = r.SetRequiredApprovals(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", "unknown-rule", 1, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestSetRequiredRebuilds(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetRequiredRebuilds(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", []string{"gittuf-linux-amd64"}, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiers, err := state.FindVerifiersForPath("git:refs/heads/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"gittuf-linux-amd64"}, verifiers[0].RequiredRebuilds())
+
+	err = r.SetRequiredRebuilds(testCtx, targetsSigner, policy.TargetsRoleName, "unknown-rule", []string{"gittuf-linux-amd64"}, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestSetRequireVerifiedSubmodule(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetRequireVerifiedSubmodule(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", true, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiers, err := state.FindVerifiersForPath("git:refs/heads/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, verifiers[0].RequireVerifiedSubmodule())
+
+	err = r.SetRequireVerifiedSubmodule(testCtx, targetsSigner, policy.TargetsRoleName, "unknown-rule", true, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestSetAllowedSignatureMethods(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetAllowedSignatureMethods(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", []string{"ssh:algorithm=ed25519", "gpg"}, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiers, err := state.FindVerifiersForPath("git:refs/heads/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []tuf.SignatureMethod{{Format: "ssh", Algorithms: []string{"ed25519"}}, {Format: "gpg"}}, verifiers[0].AllowedSignatureMethods())
+
+	err = r.SetAllowedSignatureMethods(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", []string{"x509"}, false)
+	assert.ErrorIs(t, err, policy.ErrInvalidSignatureMethod)
+
+	err = r.SetAllowedSignatureMethods(testCtx, targetsSigner, policy.TargetsRoleName, "unknown-rule", []string{"gpg"}, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
 func TestSignTargets(t *testing.T) {
 	r := createTestRepositoryWithPolicy(t, "")
 
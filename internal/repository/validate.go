@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/schema"
+)
+
+// ValidationProblem is a deviation from the published schemas found in gittuf
+// metadata.
+type ValidationProblem struct {
+	// Source identifies the document with the problem, such as the policy
+	// file, the RSL entry, or the attestation.
+	Source string `json:"source"`
+
+	// Path is the JSON pointer to the value with the problem in the document.
+	Path string `json:"path"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// String returns the problem as a single line.
+func (p *ValidationProblem) String() string {
+	if p.Path == "" {
+		return fmt.Sprintf("%s: %s", p.Source, p.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.Source, p.Path, p.Message)
+}
+
+// ValidateMetadata checks the policy metadata in the policy and policy staging
+// references, every entry in the RSL, and the current attestations against
+// the published schemas. Problems are reported for documents that are
+// malformed even if they would otherwise be ignored or rejected during
+// verification.
+func (r *Repository) ValidateMetadata(_ context.Context) ([]*ValidationProblem, error) {
+	problems := []*ValidationProblem{}
+
+	for _, refName := range []string{policy.PolicyRef, policy.PolicyStagingRef} {
+		commitID, err := gitinterface.GetTip(r.r, refName)
+		if err != nil {
+			if errors.Is(err, gitinterface.ErrReferenceNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		if commitID.IsZero() {
+			continue
+		}
+
+		contents, err := policy.LoadMetadataContents(r.r, commitID)
+		if err != nil {
+			return nil, err
+		}
+
+		documentProblems, err := validateDocuments(refName, contents)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, documentProblems...)
+	}
+
+	rslProblems, err := r.validateRSL()
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, rslProblems...)
+
+	currentAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := currentAttestations.EnvelopeContents(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	documentProblems, err := validateDocuments(attestations.Ref, contents)
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, documentProblems...)
+
+	return problems, nil
+}
+
+// ValidateFiles checks the specified files against the published schemas. Each
+// file may contain root or targets metadata, an in-toto statement, a DSSE
+// envelope, or the message of an RSL entry.
+func ValidateFiles(paths []string) ([]*ValidationProblem, error) {
+	problems := []*ValidationProblem{}
+
+	for _, filePath := range paths {
+		contents, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		var documentProblems []*schema.Problem
+		text := strings.TrimSpace(string(contents))
+		if strings.HasPrefix(text, rsl.ReferenceEntryHeader) || strings.HasPrefix(text, rsl.AnnotationEntryHeader) {
+			documentProblems, err = validateRSLEntry(text)
+		} else {
+			documentProblems, err = schema.ValidateDocument(contents)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		problems = append(problems, toValidationProblems(filePath, documentProblems)...)
+	}
+
+	return problems, nil
+}
+
+// validateRSL walks the RSL from its tip and checks every entry against the
+// published schema for RSL entries.
+func (r *Repository) validateRSL() ([]*ValidationProblem, error) {
+	problems := []*ValidationProblem{}
+
+	commitID, err := gitinterface.GetTip(r.r, rsl.Ref)
+	if err != nil {
+		if errors.Is(err, gitinterface.ErrReferenceNotFound) {
+			return problems, nil
+		}
+		return nil, err
+	}
+
+	for !commitID.IsZero() {
+		commit, err := gitinterface.GetCommit(r.r, commitID)
+		if err != nil {
+			return nil, err
+		}
+
+		entryProblems, err := validateRSLEntry(commit.Message)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, toValidationProblems(fmt.Sprintf("RSL entry %s", commitID.String()), entryProblems)...)
+
+		if len(commit.ParentHashes) == 0 {
+			break
+		}
+		commitID = commit.ParentHashes[0]
+	}
+
+	return problems, nil
+}
+
+// validateRSLEntry checks the message of an RSL entry against the published
+// schema for RSL entries. A message that can't be parsed is reported as a
+// problem.
+func validateRSLEntry(message string) ([]*schema.Problem, error) {
+	document, err := rsl.EntryDocument(message)
+	if err != nil {
+		return []*schema.Problem{{Message: err.Error()}}, nil
+	}
+
+	return schema.ValidateValue(schema.RSLEntry, document)
+}
+
+// validateDocuments checks each of the documents stored in refName against the
+// published schemas. The documents are checked in the order of their paths.
+func validateDocuments(refName string, contents map[string][]byte) ([]*ValidationProblem, error) {
+	paths := make([]string, 0, len(contents))
+	for documentPath := range contents {
+		paths = append(paths, documentPath)
+	}
+	sort.Strings(paths)
+
+	problems := []*ValidationProblem{}
+	for _, documentPath := range paths {
+		documentProblems, err := schema.ValidateDocument(contents[documentPath])
+		if err != nil {
+			return nil, err
+		}
+
+		problems = append(problems, toValidationProblems(fmt.Sprintf("%s:%s", refName, documentPath), documentProblems)...)
+	}
+
+	return problems, nil
+}
+
+func toValidationProblems(source string, documentProblems []*schema.Problem) []*ValidationProblem {
+	problems := make([]*ValidationProblem, 0, len(documentProblems))
+	for _, problem := range documentProblems {
+		problems = append(problems, &ValidationProblem{
+			Source:  source,
+			Path:    problem.Path,
+			Message: problem.Message,
+		})
+	}
+	return problems
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	t.Run("valid metadata", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		problems, err := repo.ValidateMetadata(testCtx)
+		assert.Nil(t, err)
+		assert.Empty(t, problems)
+	})
+
+	t.Run("malformed RSL entry", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		if err := rsl.NewReferenceEntry("main", plumbing.ZeroHash).Commit(repo.r, false); err != nil {
+			t.Fatal(err)
+		}
+		entryID, err := gitinterface.GetTip(repo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		problems, err := repo.ValidateMetadata(testCtx)
+		assert.Nil(t, err)
+		assert.Equal(t, []*ValidationProblem{
+			{Source: "RSL entry " + entryID.String(), Path: "/ref", Message: "'main' does not match pattern '^refs/[^\\s]+$'"},
+		}, problems)
+	})
+}
+
+func TestValidateFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	entryPath := filepath.Join(tmpDir, "entry")
+	if err := os.WriteFile(entryPath, []byte("RSL Reference Entry\n\nref: refs/heads/main\ntargetID: abcd\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	documentPath := filepath.Join(tmpDir, "document.json")
+	if err := os.WriteFile(documentPath, []byte(`{"type": "snapshot"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := ValidateFiles([]string{entryPath, documentPath})
+	assert.Nil(t, err)
+	assert.Equal(t, []*ValidationProblem{
+		{Source: entryPath, Path: "/targetID", Message: "'abcd' does not match pattern '^([0-9a-f]{40}|[0-9a-f]{64})$'"},
+		{Source: documentPath, Path: "/type", Message: `unknown document type "snapshot", expected "root", "targets", or "https://in-toto.io/Statement/v1"`},
+	}, problems)
+
+	_, err = ValidateFiles([]string{filepath.Join(tmpDir, "missing")})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
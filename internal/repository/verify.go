@@ -6,11 +6,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
+	"path/filepath"
+	"strings"
 
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/lfs"
 	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/go-git/go-git/v5/plumbing"
 )
 
@@ -23,35 +26,61 @@ import (
 // another is to create a new RSL entry for the current state.
 var ErrRefStateDoesNotMatchRSL = errors.New("Git reference's current state does not match latest RSL entry") //nolint:stylecheck
 
+// ErrReplacedObjects is returned when Git replaces objects in the repository
+// using replace refs or grafts. gittuf verifies the original objects, while
+// Git commands such as `git log` show the replacements, so the history shown
+// to the user may not be the history that was verified. Replace refs can be
+// deleted using `git replace -d`, and grafts by removing the grafts file.
+var ErrReplacedObjects = errors.New("Git replaces objects in the repository using replace refs or grafts, the history Git shows may not be the verified history") //nolint:stylecheck
+
 func (r *Repository) VerifyRef(ctx context.Context, target string, latestOnly bool) error {
 	var (
 		expectedTip plumbing.Hash
 		err         error
 	)
 
-	slog.Debug("Identifying absolute reference path...")
+	logger.Debug("Identifying absolute reference path...")
 	target, err = gitinterface.AbsoluteReference(r.r, target)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug(fmt.Sprintf("Verifying gittuf policies for '%s'", target))
+	logger.Debug(fmt.Sprintf("Verifying gittuf policies for '%s'", target))
+
+	if err := r.checkReplacedObjects(); err != nil {
+		return err
+	}
 
-	if latestOnly {
+	isShallow, err := gitinterface.IsShallow(r.r)
+	if err != nil {
+		return err
+	}
+
+	ctx = policy.ContextWithTrustedSigningTimes(ctx, r.r)
+	switch {
+	case latestOnly:
 		expectedTip, err = policy.VerifyRef(ctx, r.r, target)
-	} else {
+	case isShallow:
+		// The commits introduced by older entries may not be available
+		logger.Debug("Repository is a shallow clone, identifying anchor entry to verify from...")
+		var anchorEntry *rsl.ReferenceEntry
+		expectedTip, anchorEntry, err = policy.VerifyRefFromShallowAnchor(ctx, r.r, target)
+		if err == nil && anchorEntry != nil {
+			logger.Debug(fmt.Sprintf("Verified '%s' from anchor entry '%s' for commit '%s'", target, anchorEntry.ID.String(), anchorEntry.TargetID.String()))
+		}
+	default:
 		expectedTip, err = policy.VerifyRefFull(ctx, r.r, target)
 	}
 	if err != nil {
 		return err
 	}
 
-	slog.Debug("Verifying if tip of reference matches expected value from RSL...")
+	logger.Debug("Verifying if tip of reference matches expected value from RSL...")
 	if err := r.verifyRefTip(target, expectedTip); err != nil {
 		return err
 	}
 
-	slog.Debug("Verification successful!")
+	logger.Debug("Verification successful!")
 	return nil
 }
 
@@ -62,35 +91,109 @@ func (r *Repository) VerifyRefFromEntry(ctx context.Context, target, entryID str
 
 	var err error
 
-	slog.Debug("Identifying absolute reference path...")
+	logger.Debug("Identifying absolute reference path...")
 	target, err = gitinterface.AbsoluteReference(r.r, target)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug(fmt.Sprintf("Verifying gittuf policies for '%s' from entry '%s'", target, entryID))
+	logger.Debug(fmt.Sprintf("Verifying gittuf policies for '%s' from entry '%s'", target, entryID))
+	if err := r.checkReplacedObjects(); err != nil {
+		return err
+	}
+
+	ctx = policy.ContextWithTrustedSigningTimes(ctx, r.r)
 	expectedTip, err := policy.VerifyRefFromEntry(ctx, r.r, target, plumbing.NewHash(entryID))
 	if err != nil {
 		return err
 	}
 
-	slog.Debug("Verifying if tip of reference matches expected value from RSL...")
+	logger.Debug("Verifying if tip of reference matches expected value from RSL...")
 	if err := r.verifyRefTip(target, expectedTip); err != nil {
 		return err
 	}
 
-	slog.Debug("Verification successful!")
+	logger.Debug("Verification successful!")
 	return nil
 }
 
+// checkReplacedObjects returns an error if Git replaces any objects in the
+// repository, see gitinterface.GetReplacedObjects.
+func (r *Repository) checkReplacedObjects() error {
+	replacedObjects, err := gitinterface.GetReplacedObjects(r.r)
+	if err != nil {
+		return err
+	}
+	if len(replacedObjects) == 0 {
+		return nil
+	}
+
+	objectIDs := make([]string, 0, len(replacedObjects))
+	for _, objectID := range replacedObjects {
+		objectIDs = append(objectIDs, objectID.String())
+	}
+
+	return fmt.Errorf("%w: %s", ErrReplacedObjects, strings.Join(objectIDs, ", "))
+}
+
 func (r *Repository) VerifyCommit(ctx context.Context, ids ...string) map[string]string {
-	slog.Debug("Verifying commit signature...")
-	return policy.VerifyCommit(ctx, r.r, ids...)
+	logger.Debug("Verifying commit signature...")
+	return policy.VerifyCommit(policy.ContextWithTrustedSigningTimes(ctx, r.r), r.r, ids...)
 }
 
 func (r *Repository) VerifyTag(ctx context.Context, ids []string) map[string]string {
-	slog.Debug("Verifying tag signature...")
-	return policy.VerifyTag(ctx, r.r, ids)
+	logger.Debug("Verifying tag signature...")
+	return policy.VerifyTag(policy.ContextWithTrustedSigningTimes(ctx, r.r), r.r, ids)
+}
+
+// VerifyLFSObjects checks that the Git LFS objects referenced by the commit
+// the ref points to exist in the local Git LFS storage and match their
+// pointers. If the commit's objects were attested to, for example for a
+// release, the attestation must also be signed as required by the policy and
+// list the same objects.
+func (r *Repository) VerifyLFSObjects(ctx context.Context, target string) error {
+	commitID, pointers, err := r.verifyLocalLFSObjects(target)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Verifying Git LFS objects attestation...")
+	return policy.VerifyLFSObjectsAttestation(ctx, r.r, commitID.String(), pointers)
+}
+
+// verifyLocalLFSObjects identifies the Git LFS objects referenced by the
+// revision's commit, and checks that they exist in the local Git LFS storage
+// and match their pointers. The commit's ID is returned with the objects.
+func (r *Repository) verifyLocalLFSObjects(revision string) (plumbing.Hash, []*lfs.Pointer, error) {
+	// Git LFS stores objects in the GIT_DIR shared by all worktrees
+	commonDir, err := gitinterface.GetGitCommonDirFor(r.r)
+	if err != nil {
+		return plumbing.ZeroHash, nil, fmt.Errorf("unable to verify Git LFS objects in repository that isn't stored on disk")
+	}
+
+	logger.Debug(fmt.Sprintf("Identifying commit for '%s'...", revision))
+	commitID, err := r.r.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+
+	commit, err := gitinterface.GetCommit(r.r, *commitID)
+	if err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("Identifying Git LFS objects referenced by '%s'...", commitID.String()))
+	pointers, err := lfs.FindPointers(r.r, commit.TreeHash)
+	if err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("Verifying %d Git LFS object(s)...", len(pointers)))
+	if err := lfs.VerifyObjects(filepath.Join(commonDir, "lfs"), pointers); err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+
+	return *commitID, pointers, nil
 }
 
 func (r *Repository) verifyRefTip(target string, expectedTip plumbing.Hash) error {
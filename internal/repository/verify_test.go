@@ -4,13 +4,22 @@ package repository
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/lfs"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/stretchr/testify/assert"
 )
@@ -71,6 +80,14 @@ func TestVerifyRef(t *testing.T) {
 	assert.ErrorIs(t, err, ErrRefStateDoesNotMatchRSL)
 	err = repo.VerifyRef(context.Background(), refName, false)
 	assert.ErrorIs(t, err, ErrRefStateDoesNotMatchRSL)
+
+	// Replace the verified commit
+	replaceRefName := plumbing.ReferenceName(gitinterface.ReplaceRefPrefix + commitIDs[0].String())
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(replaceRefName, commitIDs[0])); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.VerifyRef(context.Background(), refName, false)
+	assert.ErrorIs(t, err, ErrReplacedObjects)
 }
 
 func TestVerifyRefFromEntry(t *testing.T) {
@@ -147,3 +164,74 @@ func TestVerifyRefFromEntry(t *testing.T) {
 	err = repo.VerifyRefFromEntry(testCtx, refName, violatingEntryID.String())
 	assert.ErrorIs(t, err, policy.ErrUnauthorizedSignature)
 }
+
+func TestVerifyLFSObjects(t *testing.T) {
+	location := t.TempDir()
+	repo := createTestRepositoryWithPolicy(t, location)
+
+	contents := []byte("large file")
+	digest := sha256.Sum256(contents)
+	oid := hex.EncodeToString(digest[:])
+
+	pointerID, err := gitinterface.WriteBlob(repo.r, []byte(fmt.Sprintf("version %s\noid sha256:%s\nsize %d\n", lfs.PointerVersion, oid, len(contents))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeID, err := gitinterface.NewTreeBuilder(repo.r).WriteRootTreeFromBlobIDs(map[string]plumbing.Hash{"assets/large.bin": pointerID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	if _, err := gitinterface.Commit(repo.r, treeID, refName, "Add large file", false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.VerifyLFSObjects(testCtx, refName)
+	assert.ErrorIs(t, err, lfs.ErrObjectNotFound)
+
+	objectPath := lfs.ObjectPath(filepath.Join(location, "lfs"), oid)
+	if err := os.MkdirAll(filepath.Dir(objectPath), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(objectPath, contents, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The objects weren't attested to
+	err = repo.VerifyLFSObjects(testCtx, refName)
+	assert.Nil(t, err)
+
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsPubKey, err := tuf.LoadKeyFromBytes(targetsPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.AddLFSObjectsAttestation(testCtx, targetsSigner, refName, false)
+	assert.Nil(t, err)
+
+	// The attestation's signatures can't be verified without a predicate
+	// policy
+	err = repo.VerifyLFSObjects(testCtx, refName)
+	assert.ErrorIs(t, err, policy.ErrPredicatePolicyNotFound)
+
+	if err := repo.SetPredicatePolicy(testCtx, targetsSigner, attestations.LFSObjectsPredicateType, []*tuf.Key{targetsPubKey}, 1, "", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Apply(testCtx, repo.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.VerifyLFSObjects(testCtx, refName)
+	assert.Nil(t, err)
+
+	if err := os.WriteFile(objectPath, []byte("tampered"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.VerifyLFSObjects(testCtx, refName)
+	assert.ErrorIs(t, err, lfs.ErrObjectMismatch)
+}
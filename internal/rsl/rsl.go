@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/timing"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
@@ -227,6 +228,8 @@ func (a *AnnotationEntry) createCommitMessage() (string, error) {
 
 // GetEntry returns the entry corresponding to entryID.
 func GetEntry(repo *git.Repository, entryID plumbing.Hash) (Entry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	commitObj, err := gitinterface.GetCommit(repo, entryID)
 	if err != nil {
 		return nil, ErrRSLEntryNotFound
@@ -237,6 +240,8 @@ func GetEntry(repo *git.Repository, entryID plumbing.Hash) (Entry, error) {
 
 // GetParentForEntry returns the entry's parent RSL entry.
 func GetParentForEntry(repo *git.Repository, entry Entry) (Entry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	commitObj, err := gitinterface.GetCommit(repo, entry.GetID())
 	if err != nil {
 		return nil, err
@@ -257,6 +262,8 @@ func GetParentForEntry(repo *git.Repository, entry Entry) (Entry, error) {
 // entry starting from the specified entry's parent that is not for the gittuf
 // namespace.
 func GetNonGittufParentReferenceEntryForEntry(repo *git.Repository, entry Entry) (*ReferenceEntry, []*AnnotationEntry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	it, err := GetLatestEntry(repo)
 	if err != nil {
 		return nil, nil, err
@@ -313,6 +320,8 @@ func GetNonGittufParentReferenceEntryForEntry(repo *git.Repository, entry Entry)
 
 // GetLatestEntry returns the latest entry available locally in the RSL.
 func GetLatestEntry(repo *git.Repository) (Entry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	ref, err := repo.Reference(plumbing.ReferenceName(Ref), true)
 	if err != nil {
 		return nil, err
@@ -329,6 +338,8 @@ func GetLatestEntry(repo *git.Repository) (Entry, error) {
 // GetLatestNonGittufReferenceEntry returns the first reference entry that is
 // not for the gittuf namespace.
 func GetLatestNonGittufReferenceEntry(repo *git.Repository) (*ReferenceEntry, []*AnnotationEntry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	it, err := GetLatestEntry(repo)
 	if err != nil {
 		return nil, nil, err
@@ -366,6 +377,8 @@ func GetLatestNonGittufReferenceEntry(repo *git.Repository) (*ReferenceEntry, []
 // GetLatestReferenceEntryForRef returns the latest reference entry available
 // locally in the RSL for the specified refName.
 func GetLatestReferenceEntryForRef(repo *git.Repository, refName string) (*ReferenceEntry, []*AnnotationEntry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	return GetLatestReferenceEntryForRefBefore(repo, refName, plumbing.ZeroHash)
 }
 
@@ -373,6 +386,8 @@ func GetLatestReferenceEntryForRef(repo *git.Repository, refName string) (*Refer
 // available locally in the RSL for the specified refName before the specified
 // anchor.
 func GetLatestReferenceEntryForRefBefore(repo *git.Repository, refName string, anchor plumbing.Hash) (*ReferenceEntry, []*AnnotationEntry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	allAnnotations := []*AnnotationEntry{}
 
 	iteratorT, err := GetLatestEntry(repo)
@@ -439,6 +454,8 @@ func GetLatestReferenceEntryForRefBefore(repo *git.Repository, refName string, a
 // are searched from the latest entry in the RSL to include new annotations for
 // each reference entry tested for the ref.
 func GetLatestUnskippedReferenceEntryForRef(repo *git.Repository, refName string) (*ReferenceEntry, []*AnnotationEntry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	return GetLatestUnskippedReferenceEntryForRefBefore(repo, refName, plumbing.ZeroHash)
 }
 
@@ -450,6 +467,8 @@ func GetLatestUnskippedReferenceEntryForRef(repo *git.Repository, refName string
 // the anchor entry in the RSL. Of these, the latest reference entry that is not
 // skipped by an annotation (before or after the anchor) is returned.
 func GetLatestUnskippedReferenceEntryForRefBefore(repo *git.Repository, refName string, anchor plumbing.Hash) (*ReferenceEntry, []*AnnotationEntry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	for {
 		latestEntry, annotations, err := GetLatestReferenceEntryForRefBefore(repo, refName, anchor)
 		if err != nil {
@@ -467,6 +486,8 @@ func GetLatestUnskippedReferenceEntryForRefBefore(repo *git.Repository, refName
 // GetFirstEntry returns the very first entry in the RSL. It is expected to be
 // a reference entry as the first entry in the RSL cannot be an annotation.
 func GetFirstEntry(repo *git.Repository) (*ReferenceEntry, []*AnnotationEntry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	return GetFirstReferenceEntryForRef(repo, "")
 }
 
@@ -474,6 +495,8 @@ func GetFirstEntry(repo *git.Repository) (*ReferenceEntry, []*AnnotationEntry, e
 // specified ref. It is expected to be a reference entry as the first entry in
 // the RSL for a reference cannot be an annotation.
 func GetFirstReferenceEntryForRef(repo *git.Repository, targetRef string) (*ReferenceEntry, []*AnnotationEntry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	iteratorT, err := GetLatestEntry(repo)
 	if err != nil {
 		return nil, nil, err
@@ -519,6 +542,8 @@ func GetFirstReferenceEntryForRef(repo *git.Repository, targetRef string) (*Refe
 // of the ref it was associated with, and we can infer things like the active
 // developers who could have signed the commit.
 func GetFirstReferenceEntryForCommit(repo *git.Repository, commit *object.Commit) (*ReferenceEntry, []*AnnotationEntry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	// We check entries in pairs. In the initial case, we have the latest entry
 	// and its parent. At all times, the parent in the pair is being tested.
 	// If the latest entry is a descendant of the target commit, we start
@@ -569,6 +594,8 @@ func GetFirstReferenceEntryForCommit(repo *git.Repository, commit *object.Commit
 // with the value being a list of annotations that apply to that reference
 // entry.
 func GetReferenceEntriesInRange(repo *git.Repository, firstID, lastID plumbing.Hash) ([]*ReferenceEntry, map[plumbing.Hash][]*AnnotationEntry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	return GetReferenceEntriesInRangeForRef(repo, firstID, lastID, "")
 }
 
@@ -578,6 +605,8 @@ func GetReferenceEntriesInRange(repo *git.Repository, firstID, lastID plumbing.H
 // reference entry, with the value being a list of annotations that apply to
 // that reference entry.
 func GetReferenceEntriesInRangeForRef(repo *git.Repository, firstID, lastID plumbing.Hash, refName string) ([]*ReferenceEntry, map[plumbing.Hash][]*AnnotationEntry, error) {
+	defer timing.Start(timing.PhaseRSLWalk)()
+
 	// We have to iterate from latest to get the annotations that refer to the
 	// last requested entry
 	iterator, err := GetLatestEntry(repo)
@@ -669,6 +698,59 @@ func GetReferenceEntriesInRangeForRef(repo *git.Repository, firstID, lastID plum
 	return allEntries, annotationMap, nil
 }
 
+// EntryDocument returns the contents of the RSL entry recorded in the commit
+// message as a JSON compatible document, so that the entry can be checked
+// against the published schema for RSL entries. Unlike when entries are loaded,
+// every key and value is recorded as it appears in the message, so that
+// unexpected keys and malformed values can be reported. The entry IDs of
+// annotations, and any other keys that are repeated, are recorded as arrays.
+func EntryDocument(text string) (map[string]any, error) {
+	text = strings.TrimSpace(text)
+	lines := strings.Split(text, "\n")
+
+	document := map[string]any{}
+	switch strings.TrimSpace(lines[0]) {
+	case ReferenceEntryHeader:
+		document["type"] = "reference"
+	case AnnotationEntryHeader:
+		document["type"] = "annotation"
+		document[EntryIDKey] = []any{}
+
+		if messageBlock, _ := pem.Decode([]byte(text)); messageBlock != nil {
+			document["message"] = string(messageBlock.Bytes)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown header '%s'", ErrInvalidRSLEntry, lines[0])
+	}
+
+	for _, l := range lines[1:] {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		if l == BeginMessage {
+			break
+		}
+
+		key, value, found := strings.Cut(l, ":")
+		if !found {
+			return nil, fmt.Errorf("%w: line '%s' is not a key and value", ErrInvalidRSLEntry, l)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch existing := document[key].(type) {
+		case nil:
+			document[key] = value
+		case []any:
+			document[key] = append(existing, value)
+		default:
+			document[key] = []any{existing, value}
+		}
+	}
+
+	return document, nil
+}
+
 func parseRSLEntryText(id plumbing.Hash, text string) (Entry, error) {
 	text = strings.TrimSpace(text)
 	if strings.HasPrefix(text, AnnotationEntryHeader) {
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -1287,6 +1288,13 @@ func TestReferenceEntryCreateCommitMessage(t *testing.T) {
 			},
 			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef12345678900987654321fedcbaabcdef12"),
 		},
+		"entry, test vector": {
+			entry: &ReferenceEntry{
+				RefName:  "refs/heads/main",
+				TargetID: plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12"),
+			},
+			expectedMessage: string(artifacts.RSLReferenceEntryVector),
+		},
 	}
 
 	for name, test := range tests {
@@ -1344,6 +1352,14 @@ func TestAnnotationEntryCreateCommitMessage(t *testing.T) {
 			},
 			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "false"),
 		},
+		"annotation, test vector": {
+			entry: &AnnotationEntry{
+				RSLEntryIDs: []plumbing.Hash{plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12"), plumbing.ZeroHash},
+				Skip:        true,
+				Message:     "Revoking entry after\nkey compromise",
+			},
+			expectedMessage: string(artifacts.RSLAnnotationEntryVector),
+		},
 	}
 
 	for name, test := range tests {
@@ -1468,3 +1484,58 @@ func assertAnnotationsReferToEntry(t *testing.T, entry *ReferenceEntry, annotati
 		assert.Equal(t, annotationMessage, annotation.Message)
 	}
 }
+
+func TestEntryDocument(t *testing.T) {
+	tests := map[string]struct {
+		message          string
+		expectedDocument map[string]any
+		expectedError    error
+	}{
+		"reference entry": {
+			message: string(artifacts.RSLReferenceEntryVector),
+			expectedDocument: map[string]any{
+				"type":      "reference",
+				RefKey:      "refs/heads/main",
+				TargetIDKey: "abcdef12345678900987654321fedcbaabcdef12",
+			},
+		},
+		"annotation entry": {
+			message: string(artifacts.RSLAnnotationEntryVector),
+			expectedDocument: map[string]any{
+				"type":     "annotation",
+				EntryIDKey: []any{"abcdef12345678900987654321fedcbaabcdef12", "0000000000000000000000000000000000000000"},
+				SkipKey:    "true",
+				"message":  "Revoking entry after\nkey compromise",
+			},
+		},
+		"reference entry with unexpected and repeated keys": {
+			message: fmt.Sprintf("%s\n\n%s: refs/heads/main\n%s: refs/heads/feature\n%s: abcd\nnumber: 1", ReferenceEntryHeader, RefKey, RefKey, TargetIDKey),
+			expectedDocument: map[string]any{
+				"type":      "reference",
+				RefKey:      []any{"refs/heads/main", "refs/heads/feature"},
+				TargetIDKey: "abcd",
+				"number":    "1",
+			},
+		},
+		"unknown header": {
+			message:       "RSL Propagation Entry\n\nref: refs/heads/main",
+			expectedError: ErrInvalidRSLEntry,
+		},
+		"line without key": {
+			message:       fmt.Sprintf("%s\n\nrefs/heads/main", ReferenceEntryHeader),
+			expectedError: ErrInvalidRSLEntry,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			document, err := EntryDocument(test.message)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expectedDocument, document)
+			}
+		})
+	}
+}
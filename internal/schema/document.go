@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+const statementType = "https://in-toto.io/Statement/v1"
+
+// ValidateDocument identifies the type of the JSON document and checks it
+// against the corresponding schema. The document may be root or targets
+// metadata, an in-toto statement, or a DSSE envelope containing any of them.
+// The payload of an envelope is also validated, and problems in it are
+// reported with paths prefixed with /payload.
+func ValidateDocument(document []byte) ([]*Problem, error) {
+	value, err := decode(document)
+	if err != nil {
+		return []*Problem{{Message: fmt.Sprintf("invalid JSON: %s", err.Error())}}, nil
+	}
+
+	object, isObject := value.(map[string]any)
+	if !isObject {
+		return []*Problem{{Message: fmt.Sprintf("must be of type object, found %s", typeOf(value))}}, nil
+	}
+
+	_, hasPayloadType := object["payloadType"]
+	_, hasPayload := object["payload"]
+	if !hasPayloadType && !hasPayload {
+		return validatePayload(object, "")
+	}
+
+	problems, err := ValidateValue(Envelope, object)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedPayload, isString := object["payload"].(string)
+	if !isString {
+		// The envelope's problems include the missing or invalid payload
+		return problems, nil
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		// The DSSE specification allows either base64 encoding
+		payload, err = base64.URLEncoding.DecodeString(encodedPayload)
+		if err != nil {
+			return append(problems, &Problem{Path: "/payload", Message: "payload is not base64 encoded"}), nil
+		}
+	}
+
+	payloadValue, err := decode(payload)
+	if err != nil {
+		return append(problems, &Problem{Path: "/payload", Message: fmt.Sprintf("payload is not valid JSON: %s", err.Error())}), nil
+	}
+
+	payloadProblems, err := validatePayload(payloadValue, "/payload")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(problems, payloadProblems...), nil
+}
+
+// validatePayload identifies the type of the payload using its type property
+// and checks it against the corresponding schema. The paths of the problems
+// found are prefixed with pointer.
+func validatePayload(value any, pointer string) ([]*Problem, error) {
+	object, isObject := value.(map[string]any)
+	if !isObject {
+		return []*Problem{{Path: pointer, Message: fmt.Sprintf("must be of type object, found %s", typeOf(value))}}, nil
+	}
+
+	var name string
+	switch object["type"] {
+	case "root":
+		name = RootMetadata
+	case "targets":
+		name = TargetsMetadata
+	case statementType:
+		name = Statement
+	default:
+		return []*Problem{{Path: pointer + "/type", Message: fmt.Sprintf("unknown document type %s, expected \"root\", \"targets\", or %q", describe(object["type"]), statementType)}}, nil
+	}
+
+	problems, err := ValidateValue(name, object)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, problem := range problems {
+		problem.Path = pointer + problem.Path
+	}
+
+	return problems, nil
+}
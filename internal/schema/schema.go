@@ -0,0 +1,578 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schema validates gittuf's policy metadata, RSL entries, and
+// attestations against the JSON schemas published in the schemas directory.
+// The schemas use the subset of JSON Schema (draft 2020-12) implemented here:
+//
+//   - the $ref, allOf, if, and then applicators, where $ref refers to a
+//     definition in the same schema or, when prefixed with a schema's file
+//     name, in another published schema,
+//   - the type, const, and enum assertions,
+//   - properties, required, additionalProperties, and maxProperties for
+//     objects,
+//   - items and minItems for arrays,
+//   - minLength, pattern, and the date-time format for strings, and
+//   - minimum for numbers.
+//
+// Other keywords, such as $id, title, and description, are annotations and are
+// ignored. Problems are reported using JSON Pointers (RFC 6901) to the values
+// that do not match the schema.
+package schema
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The names of the published schemas, which are the names of their files in
+// the schemas directory without the extension.
+const (
+	RootMetadata    = "root-metadata"
+	TargetsMetadata = "targets-metadata"
+	Envelope        = "dsse-envelope"
+	Statement       = "in-toto-statement"
+	RSLEntry        = "rsl-entry"
+)
+
+var ErrUnknownSchema = errors.New("unknown schema")
+
+//go:embed schemas/*.json
+var schemasFS embed.FS
+
+var (
+	loadSchemasOnce sync.Once
+	loadedSchemas   map[string]map[string]any
+	loadSchemasErr  error
+
+	patternsMu sync.Mutex
+	patterns   = map[string]*regexp.Regexp{}
+)
+
+// Problem describes a value in a document that does not match its schema.
+type Problem struct {
+	// Path is the JSON Pointer to the value in the document. The pointer to
+	// the document itself is empty.
+	Path string `json:"path"`
+
+	// Message describes how the value does not match the schema.
+	Message string `json:"message"`
+}
+
+func (p *Problem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return fmt.Sprintf("%s: %s", p.Path, p.Message)
+}
+
+// Names returns the names of the published schemas.
+func Names() ([]string, error) {
+	schemas, err := loadSchemas()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Get returns the contents of the published schema.
+func Get(name string) ([]byte, error) {
+	contents, err := schemasFS.ReadFile(path.Join("schemas", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: '%s'", ErrUnknownSchema, name)
+	}
+
+	return contents, nil
+}
+
+// Validate checks the JSON document against the schema, returning the
+// problems found. A document that is not valid JSON is reported as a single
+// problem.
+func Validate(name string, document []byte) ([]*Problem, error) {
+	value, err := decode(document)
+	if err != nil {
+		return []*Problem{{Message: fmt.Sprintf("invalid JSON: %s", err.Error())}}, nil
+	}
+
+	return ValidateValue(name, value)
+}
+
+// ValidateValue checks the decoded JSON value against the schema, returning
+// the problems found. Numbers in the value must be decoded as json.Number.
+func ValidateValue(name string, value any) ([]*Problem, error) {
+	schemas, err := loadSchemas()
+	if err != nil {
+		return nil, err
+	}
+
+	schema, has := schemas[name]
+	if !has {
+		return nil, fmt.Errorf("%w: '%s'", ErrUnknownSchema, name)
+	}
+
+	v := &validator{schemas: schemas}
+	if err := v.validate(name, schema, value, ""); err != nil {
+		return nil, err
+	}
+
+	return v.problems, nil
+}
+
+// decode parses the JSON document, preserving the representation of numbers
+// so that integers can be told apart from other numbers.
+func decode(document []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+
+	return value, nil
+}
+
+func loadSchemas() (map[string]map[string]any, error) {
+	loadSchemasOnce.Do(func() {
+		entries, err := schemasFS.ReadDir("schemas")
+		if err != nil {
+			loadSchemasErr = err
+			return
+		}
+
+		loadedSchemas = map[string]map[string]any{}
+		for _, entry := range entries {
+			contents, err := schemasFS.ReadFile(path.Join("schemas", entry.Name()))
+			if err != nil {
+				loadSchemasErr = err
+				return
+			}
+
+			schema := map[string]any{}
+			if err := json.Unmarshal(contents, &schema); err != nil {
+				loadSchemasErr = fmt.Errorf("unable to load schema '%s': %w", entry.Name(), err)
+				return
+			}
+
+			loadedSchemas[strings.TrimSuffix(entry.Name(), ".json")] = schema
+		}
+	})
+
+	return loadedSchemas, loadSchemasErr
+}
+
+// validator records the problems found while validating a document.
+type validator struct {
+	schemas  map[string]map[string]any
+	problems []*Problem
+}
+
+func (v *validator) report(pointer, format string, a ...any) {
+	v.problems = append(v.problems, &Problem{Path: pointer, Message: fmt.Sprintf(format, a...)})
+}
+
+// matches returns true if the value matches the schema, without reporting
+// any problems. It is used to evaluate the condition of an if keyword.
+func (v *validator) matches(schemaName string, schema map[string]any, value any) (bool, error) {
+	conditionValidator := &validator{schemas: v.schemas}
+	if err := conditionValidator.validate(schemaName, schema, value, ""); err != nil {
+		return false, err
+	}
+
+	return len(conditionValidator.problems) == 0, nil
+}
+
+// validate checks the value at pointer against the schema, which is a part of
+// the named schema. If the value doesn't have the expected type, the other
+// keywords are not evaluated. Applicators are evaluated last, so that problems
+// found using the schema's own keywords are reported first. Errors are returned
+// only for invalid schemas.
+func (v *validator) validate(schemaName string, schema map[string]any, value any, pointer string) error {
+	if expected, has := schema["const"]; has {
+		if !equal(expected, value) {
+			v.report(pointer, "must be %s, found %s", describe(expected), describe(value))
+			return nil
+		}
+	}
+
+	if enum, has := schema["enum"].([]any); has {
+		found := false
+		for _, expected := range enum {
+			if equal(expected, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			options := make([]string, 0, len(enum))
+			for _, expected := range enum {
+				options = append(options, describe(expected))
+			}
+			v.report(pointer, "must be one of %s, found %s", strings.Join(options, ", "), describe(value))
+			return nil
+		}
+	}
+
+	if types, has := schema["type"]; has {
+		if !matchesType(types, value) {
+			v.report(pointer, "must be of type %s, found %s", describeTypes(types), typeOf(value))
+			return nil
+		}
+	}
+
+	var err error
+	switch value := value.(type) {
+	case map[string]any:
+		err = v.validateObject(schemaName, schema, value, pointer)
+	case []any:
+		err = v.validateArray(schemaName, schema, value, pointer)
+	case string:
+		err = v.validateString(schemaName, schema, value, pointer)
+	case json.Number:
+		v.validateNumber(schema, value, pointer)
+	}
+	if err != nil {
+		return err
+	}
+
+	if ref, has := schema["$ref"].(string); has {
+		refSchemaName, refSchema, err := v.resolve(schemaName, ref)
+		if err != nil {
+			return err
+		}
+		if err := v.validate(refSchemaName, refSchema, value, pointer); err != nil {
+			return err
+		}
+	}
+
+	if allOf, has := schema["allOf"].([]any); has {
+		for _, subschema := range allOf {
+			subschema, ok := subschema.(map[string]any)
+			if !ok {
+				return fmt.Errorf("invalid schema '%s': allOf must contain schemas", schemaName)
+			}
+			if err := v.validate(schemaName, subschema, value, pointer); err != nil {
+				return err
+			}
+		}
+	}
+
+	if condition, has := schema["if"].(map[string]any); has {
+		matches, err := v.matches(schemaName, condition, value)
+		if err != nil {
+			return err
+		}
+		if then, has := schema["then"].(map[string]any); has && matches {
+			if err := v.validate(schemaName, then, value, pointer); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) validateObject(schemaName string, schema map[string]any, value map[string]any, pointer string) error {
+	properties, _ := schema["properties"].(map[string]any)
+
+	if required, has := schema["required"].([]any); has {
+		for _, name := range required {
+			name, _ := name.(string)
+			if _, has := value[name]; !has {
+				v.report(appendPointer(pointer, name), "required property is missing")
+			}
+		}
+	}
+
+	if maxProperties, has := schema["maxProperties"].(float64); has && float64(len(value)) > maxProperties {
+		v.report(pointer, "must have at most %d properties, found %d", int(maxProperties), len(value))
+	}
+
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertyPointer := appendPointer(pointer, name)
+
+		if propertySchema, has := properties[name]; has {
+			propertySchema, ok := propertySchema.(map[string]any)
+			if !ok {
+				return fmt.Errorf("invalid schema '%s': property '%s' must be a schema", schemaName, name)
+			}
+			if err := v.validate(schemaName, propertySchema, value[name], propertyPointer); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch additionalProperties := schema["additionalProperties"].(type) {
+		case bool:
+			if !additionalProperties {
+				v.report(propertyPointer, "unexpected property")
+			}
+		case map[string]any:
+			if err := v.validate(schemaName, additionalProperties, value[name], propertyPointer); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) validateArray(schemaName string, schema map[string]any, value []any, pointer string) error {
+	if minItems, has := schema["minItems"].(float64); has && float64(len(value)) < minItems {
+		v.report(pointer, "must have at least %d item(s), found %d", int(minItems), len(value))
+	}
+
+	if items, has := schema["items"].(map[string]any); has {
+		for index, item := range value {
+			if err := v.validate(schemaName, items, item, fmt.Sprintf("%s/%d", pointer, index)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) validateString(schemaName string, schema map[string]any, value string, pointer string) error {
+	if minLength, has := schema["minLength"].(float64); has && float64(len([]rune(value))) < minLength {
+		if minLength == 1 {
+			v.report(pointer, "must not be empty")
+		} else {
+			v.report(pointer, "must be at least %d characters long", int(minLength))
+		}
+	}
+
+	if pattern, has := schema["pattern"].(string); has {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid schema '%s': %w", schemaName, err)
+		}
+		if !re.MatchString(value) {
+			v.report(pointer, "'%s' does not match pattern '%s'", value, pattern)
+		}
+	}
+
+	if format, has := schema["format"].(string); has && format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			v.report(pointer, "'%s' is not an RFC 3339 date and time", value)
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) validateNumber(schema map[string]any, value json.Number, pointer string) {
+	minimum, has := schema["minimum"].(float64)
+	if !has {
+		return
+	}
+
+	number, ok := new(big.Float).SetString(value.String())
+	if ok && number.Cmp(big.NewFloat(minimum)) < 0 {
+		v.report(pointer, "must be at least %s, found %s", big.NewFloat(minimum).String(), value.String())
+	}
+}
+
+// resolve returns the schema a $ref refers to, along with the name of the
+// schema it is defined in.
+func (v *validator) resolve(schemaName, ref string) (string, map[string]any, error) {
+	file, fragment, _ := strings.Cut(ref, "#")
+	if file != "" {
+		schemaName = strings.TrimSuffix(file, ".json")
+	}
+
+	var current any = v.schemas[schemaName]
+	if current == nil {
+		return "", nil, fmt.Errorf("invalid schema reference '%s': %w", ref, ErrUnknownSchema)
+	}
+
+	if fragment != "" {
+		for _, token := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+			object, ok := current.(map[string]any)
+			if !ok {
+				return "", nil, fmt.Errorf("invalid schema reference '%s'", ref)
+			}
+			current = object[unescapePointerToken(token)]
+		}
+	}
+
+	resolved, ok := current.(map[string]any)
+	if !ok {
+		return "", nil, fmt.Errorf("invalid schema reference '%s'", ref)
+	}
+
+	return schemaName, resolved, nil
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+
+	if re, has := patterns[pattern]; has {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns[pattern] = re
+
+	return re, nil
+}
+
+// matchesType returns true if the value has one of the JSON types, which may
+// be a single type or a list of types.
+func matchesType(types any, value any) bool {
+	switch types := types.(type) {
+	case string:
+		return hasType(types, value)
+	case []any:
+		for _, t := range types {
+			if t, ok := t.(string); ok && hasType(t, value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func hasType(t string, value any) bool {
+	actual := typeOf(value)
+	if t == "number" && actual == "integer" {
+		return true
+	}
+	return t == actual
+}
+
+// typeOf returns the JSON type of the decoded value. Numbers without a fraction
+// are reported as integers.
+func typeOf(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case json.Number:
+		number, ok := new(big.Float).SetString(value.String())
+		if ok && number.IsInt() {
+			return "integer"
+		}
+		return "number"
+	case float64:
+		if value == float64(int64(value)) {
+			return "integer"
+		}
+		return "number"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+func describeTypes(types any) string {
+	if list, ok := types.([]any); ok {
+		names := make([]string, 0, len(list))
+		for _, t := range list {
+			names = append(names, fmt.Sprint(t))
+		}
+		return strings.Join(names, " or ")
+	}
+
+	return fmt.Sprint(types)
+}
+
+// describe returns the JSON representation of the value, used in problem
+// messages.
+func describe(value any) string {
+	if _, ok := value.(map[string]any); ok {
+		return "object"
+	}
+	if _, ok := value.([]any); ok {
+		return "array"
+	}
+
+	contents, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(contents)
+}
+
+// equal compares the JSON values, which may have been decoded with or without
+// json.Number.
+func equal(expected, actual any) bool {
+	expectedNumber, expectedIsNumber := asNumber(expected)
+	actualNumber, actualIsNumber := asNumber(actual)
+	if expectedIsNumber || actualIsNumber {
+		return expectedIsNumber && actualIsNumber && expectedNumber.Cmp(actualNumber) == 0
+	}
+
+	switch expected := expected.(type) {
+	case map[string]any, []any:
+		expectedBytes, err := json.Marshal(expected)
+		if err != nil {
+			return false
+		}
+		actualBytes, err := json.Marshal(actual)
+		if err != nil {
+			return false
+		}
+		return bytes.Equal(expectedBytes, actualBytes)
+	default:
+		return expected == actual
+	}
+}
+
+func asNumber(value any) (*big.Float, bool) {
+	switch value := value.(type) {
+	case json.Number:
+		return new(big.Float).SetString(value.String())
+	case float64:
+		return big.NewFloat(value), true
+	}
+
+	return nil, false
+}
+
+func appendPointer(pointer, token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	token = strings.ReplaceAll(token, "/", "~1")
+	return pointer + "/" + token
+}
+
+func unescapePointerToken(token string) string {
+	token = strings.ReplaceAll(token, "~1", "/")
+	return strings.ReplaceAll(token, "~0", "~")
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemas(t *testing.T) {
+	names, err := Names()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"definitions", Envelope, Statement, RootMetadata, RSLEntry, TargetsMetadata}, names)
+
+	for _, name := range names {
+		contents, err := Get(name)
+		assert.Nil(t, err)
+		assert.True(t, json.Valid(contents))
+	}
+
+	_, err = Get("unknown")
+	assert.ErrorIs(t, err, ErrUnknownSchema)
+
+	_, err = Validate("unknown", []byte("{}"))
+	assert.ErrorIs(t, err, ErrUnknownSchema)
+}
+
+func TestValidateDocument(t *testing.T) {
+	t.Run("test vectors", func(t *testing.T) {
+		vectors := map[string][]byte{
+			"root metadata":                    artifacts.RootMetadataVector,
+			"root metadata envelope":           artifacts.RootMetadataEnvelopeVector,
+			"targets metadata":                 artifacts.TargetsMetadataVector,
+			"targets metadata envelope":        artifacts.TargetsMetadataEnvelopeVector,
+			"reference authorization":          artifacts.ReferenceAuthorizationVector,
+			"reference authorization envelope": artifacts.ReferenceAuthorizationEnvelopeVector,
+			"policy justification":             artifacts.PolicyJustificationVector,
+			"policy justification envelope":    artifacts.PolicyJustificationEnvelopeVector,
+		}
+
+		for name, vector := range vectors {
+			t.Run(name, func(t *testing.T) {
+				problems, err := ValidateDocument(vector)
+				assert.Nil(t, err)
+				assert.Empty(t, problems)
+			})
+		}
+	})
+
+	tests := map[string]struct {
+		document string
+		problems []*Problem
+	}{
+		"invalid JSON": {
+			document: `{"type": "root"`,
+			problems: []*Problem{{Message: "invalid JSON: unexpected EOF"}},
+		},
+		"unknown document type": {
+			document: `{"type": "snapshot"}`,
+			problems: []*Problem{{Path: "/type", Message: `unknown document type "snapshot", expected "root", "targets", or "https://in-toto.io/Statement/v1"`}},
+		},
+		"root metadata with problems": {
+			document: `{"type": "root", "spec_version": "1.0", "consistent_snapshot": true, "version": 0, "expires": "2030-01-01", "keys": {"abcd": {"keyid": "abcd", "keytype": "ssh", "scheme": "ssh-ed25519", "keyval": {"public": "key", "private": "key"}}}, "roles": {"root": {"keyids": ["abcd"], "threshold": 1.5}}, "extra": true}`,
+			problems: []*Problem{
+				{Path: "/expires", Message: "'2030-01-01' is not an RFC 3339 date and time"},
+				{Path: "/extra", Message: "unexpected property"},
+				{Path: "/keys/abcd/keyval/private", Message: "unexpected property"},
+				{Path: "/roles/root/threshold", Message: "must be of type integer, found number"},
+				{Path: "/version", Message: "must be at least 1, found 0"},
+			},
+		},
+		"targets metadata with problems": {
+			document: `{"type": "targets", "spec_version": "1.0", "version": 1, "expires": "2030-01-01T00:00:00Z", "targets": {"file": {}}, "delegations": {"keys": null, "roles": [{"name": "", "paths": ["git:refs/heads/main"], "terminating": "no", "keyids": [], "threshold": 0}]}}`,
+			problems: []*Problem{
+				{Path: "/delegations/roles/0/name", Message: "must not be empty"},
+				{Path: "/delegations/roles/0/terminating", Message: "must be of type boolean, found string"},
+				{Path: "/delegations/roles/0/threshold", Message: "must be at least 1, found 0"},
+				{Path: "/targets", Message: "must have at most 0 properties, found 1"},
+			},
+		},
+		"statement with invalid predicate": {
+			document: `{"type": "https://in-toto.io/Statement/v1", "subject": [{"digest": {"gitCommit": "abcdef12345678900987654321fedcbaabcdef12"}}], "predicate_type": "https://gittuf.dev/rebuild/v0.1", "predicate": {"commitID": "abcdef12345678900987654321fedcbaabcdef12", "artifactName": "gittuf/linux", "notAfter": "tomorrow"}}`,
+			problems: []*Problem{
+				{Path: "/predicate/notAfter", Message: "'tomorrow' is not an RFC 3339 date and time"},
+				{Path: "/predicate/artifactSHA256", Message: "required property is missing"},
+				{Path: "/predicate/artifactName", Message: "'gittuf/linux' does not match pattern '^[^/]+$'"},
+			},
+		},
+		"statement with unknown predicate": {
+			document: `{"type": "https://in-toto.io/Statement/v1", "subject": [], "predicate_type": "https://example.com/predicate", "predicate": {"anything": 1}}`,
+			problems: []*Problem{
+				{Path: "/subject", Message: "must have at least 1 item(s), found 0"},
+			},
+		},
+		"envelope with invalid payload": {
+			document: `{"payloadType": "application/vnd.gittuf+json", "payload": "eyJ0eXBlIjoidGFyZ2V0cyJ9", "signatures": [{"keyid": "abcd"}]}`,
+			problems: []*Problem{
+				{Path: "/signatures/0/sig", Message: "required property is missing"},
+				{Path: "/payload/spec_version", Message: "required property is missing"},
+				{Path: "/payload/version", Message: "required property is missing"},
+				{Path: "/payload/expires", Message: "required property is missing"},
+				{Path: "/payload/targets", Message: "required property is missing"},
+				{Path: "/payload/delegations", Message: "required property is missing"},
+			},
+		},
+		"envelope with undecodable payload": {
+			document: `{"payloadType": "application/json", "payload": "not base64!", "signatures": []}`,
+			problems: []*Problem{
+				{Path: "/payload", Message: "'not base64!' does not match pattern '^[A-Za-z0-9+/_-]*={0,2}$'"},
+				{Path: "/payloadType", Message: `must be one of "application/vnd.gittuf+json", "application/vnd.in-toto+json", found "application/json"`},
+				{Path: "/payload", Message: "payload is not base64 encoded"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			problems, err := ValidateDocument([]byte(test.document))
+			assert.Nil(t, err)
+			assert.Equal(t, test.problems, problems)
+		})
+	}
+}
+
+func TestValidateRSLEntry(t *testing.T) {
+	tests := map[string]struct {
+		entry    map[string]any
+		problems []*Problem
+	}{
+		"reference entry": {
+			entry: map[string]any{"type": "reference", "ref": "refs/heads/main", "targetID": "abcdef12345678900987654321fedcbaabcdef12"},
+		},
+		"annotation entry": {
+			entry: map[string]any{"type": "annotation", "entryID": []any{"abcdef12345678900987654321fedcbaabcdef12"}, "skip": "true", "message": "revoked"},
+		},
+		"reference entry with problems": {
+			entry: map[string]any{"type": "reference", "ref": "main", "targetID": "xyz", "number": "1"},
+			problems: []*Problem{
+				{Path: "/number", Message: "unexpected property"},
+				{Path: "/ref", Message: "'main' does not match pattern '^refs/[^\\s]+$'"},
+				{Path: "/targetID", Message: "'xyz' does not match pattern '^([0-9a-f]{40}|[0-9a-f]{64})$'"},
+			},
+		},
+		"annotation entry with problems": {
+			entry: map[string]any{"type": "annotation", "entryID": []any{}, "skip": "yes"},
+			problems: []*Problem{
+				{Path: "/entryID", Message: "must have at least 1 item(s), found 0"},
+				{Path: "/skip", Message: `must be one of "true", "false", found "yes"`},
+			},
+		},
+		"unknown entry type": {
+			entry: map[string]any{"type": "propagation"},
+			problems: []*Problem{
+				{Path: "/type", Message: `must be one of "reference", "annotation", found "propagation"`},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			problems, err := ValidateValue(RSLEntry, test.entry)
+			assert.Nil(t, err)
+			assert.Equal(t, test.problems, problems)
+		})
+	}
+}
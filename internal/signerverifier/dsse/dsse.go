@@ -3,11 +3,13 @@
 package dsse
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
-	"encoding/json"
 
+	"github.com/gittuf/gittuf/internal/canonicaljson"
 	"github.com/gittuf/gittuf/internal/signerverifier/common"
+	"github.com/gittuf/gittuf/internal/timing"
 	"github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
@@ -15,9 +17,11 @@ const PayloadType = "application/vnd.gittuf+json"
 
 // CreateEnvelope is an opinionated interface to create a DSSE envelope. It
 // accepts instances of tuf.RootMetadata, tuf.TargetsMetadata, etc. and marshals
-// the input prior to storing it as the envelope's payload.
+// the input using the canonical JSON serialization prior to storing it as the
+// envelope's payload, so that the signed bytes are the same regardless of the
+// implementation that created them.
 func CreateEnvelope(v any) (*dsse.Envelope, error) {
-	b, err := json.Marshal(v)
+	b, err := canonicaljson.Marshal(v)
 	if err != nil {
 		return nil, err
 	}
@@ -29,6 +33,39 @@ func CreateEnvelope(v any) (*dsse.Envelope, error) {
 	}, nil
 }
 
+// EqualPayloads returns true if the envelopes' payloads are the same JSON
+// document. Payloads are compared using their canonical serialization, so that
+// an envelope created by an older version of gittuf, which didn't canonicalize
+// payloads, is considered equal to one with the canonicalized payload. Note
+// that signatures are computed over the exact bytes of the payload, so they
+// can't be copied between envelopes whose payloads are only equal in this
+// sense.
+func EqualPayloads(a, b *dsse.Envelope) bool {
+	if a.Payload == b.Payload {
+		return true
+	}
+
+	payloadA, err := a.DecodeB64Payload()
+	if err != nil {
+		return false
+	}
+	payloadB, err := b.DecodeB64Payload()
+	if err != nil {
+		return false
+	}
+
+	canonicalA, err := canonicaljson.Canonicalize(payloadA)
+	if err != nil {
+		return false
+	}
+	canonicalB, err := canonicaljson.Canonicalize(payloadB)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(canonicalA, canonicalB)
+}
+
 // SignEnvelope is an opinionated API to sign DSSE envelopes. It's opinionated
 // because it assumes the payload is Base 64 encoded, which is the expectation
 // for gittuf metadata. If one or more signatures from the provided signing key
@@ -76,6 +113,8 @@ func SignEnvelope(ctx context.Context, envelope *dsse.Envelope, signer dsse.Sign
 // a slice of verifiers passed into it. Threshold indicates the number of
 // providers that must validate the envelope.
 func VerifyEnvelope(ctx context.Context, envelope *dsse.Envelope, verifiers []dsse.Verifier, threshold int) error {
+	defer timing.Start(timing.PhaseSignatureVerification)()
+
 	if threshold < 1 || threshold > len(verifiers) {
 		return common.ErrInvalidThreshold
 	}
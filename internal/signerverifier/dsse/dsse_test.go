@@ -5,13 +5,17 @@ package dsse
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/canonicaljson"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
 	"github.com/gittuf/gittuf/internal/tuf"
+	ita "github.com/in-toto/attestation/go/v1"
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 var (
@@ -24,7 +28,83 @@ func TestCreateEnvelope(t *testing.T) {
 	env, err := CreateEnvelope(rootMetadata)
 	assert.Nil(t, err)
 	assert.Equal(t, PayloadType, env.PayloadType)
-	assert.Equal(t, "eyJ0eXBlIjoicm9vdCIsInNwZWNfdmVyc2lvbiI6IjEuMCIsImNvbnNpc3RlbnRfc25hcHNob3QiOnRydWUsInZlcnNpb24iOjAsImV4cGlyZXMiOiIiLCJrZXlzIjpudWxsLCJyb2xlcyI6bnVsbH0=", env.Payload)
+	assert.Equal(t, "eyJjb25zaXN0ZW50X3NuYXBzaG90Ijp0cnVlLCJleHBpcmVzIjoiIiwia2V5cyI6bnVsbCwicm9sZXMiOm51bGwsInNwZWNfdmVyc2lvbiI6IjEuMCIsInR5cGUiOiJyb290IiwidmVyc2lvbiI6MH0=", env.Payload)
+}
+
+func TestCanonicalPayloadVectors(t *testing.T) {
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(signingKeyBytes) //nolint:staticcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		payload          any
+		expectedPayload  []byte
+		expectedEnvelope []byte
+	}{
+		"root metadata": {
+			payload:          vectorRootMetadata(t),
+			expectedPayload:  artifacts.RootMetadataVector,
+			expectedEnvelope: artifacts.RootMetadataEnvelopeVector,
+		},
+		"targets metadata": {
+			payload:          vectorTargetsMetadata(t),
+			expectedPayload:  artifacts.TargetsMetadataVector,
+			expectedEnvelope: artifacts.TargetsMetadataEnvelopeVector,
+		},
+		"reference authorization": {
+			payload:          vectorReferenceAuthorization(t),
+			expectedPayload:  artifacts.ReferenceAuthorizationVector,
+			expectedEnvelope: artifacts.ReferenceAuthorizationEnvelopeVector,
+		},
+		"policy justification": {
+			payload:          vectorPolicyJustification(t),
+			expectedPayload:  artifacts.PolicyJustificationVector,
+			expectedEnvelope: artifacts.PolicyJustificationEnvelopeVector,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// The vectors must themselves be canonical
+			canonical, err := canonicaljson.Canonicalize(test.expectedPayload)
+			assert.Nil(t, err)
+			assert.Equal(t, string(test.expectedPayload), string(canonical))
+
+			env, err := CreateEnvelope(test.payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			payload, err := env.DecodeB64Payload()
+			assert.Nil(t, err)
+			assert.Equal(t, string(test.expectedPayload), string(payload))
+
+			env, err = SignEnvelope(context.Background(), env, signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedEnv := &sslibdsse.Envelope{}
+			if err := json.Unmarshal(test.expectedEnvelope, expectedEnv); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expectedEnv, env)
+		})
+	}
+}
+
+func TestEqualPayloads(t *testing.T) {
+	canonicalEnv := &sslibdsse.Envelope{Payload: base64.StdEncoding.EncodeToString([]byte(`{"a":1,"b":"\u003c"}`))}
+	legacyEnv := &sslibdsse.Envelope{Payload: base64.StdEncoding.EncodeToString([]byte(`{"b": "<", "a": 1}`))}
+	differentEnv := &sslibdsse.Envelope{Payload: base64.StdEncoding.EncodeToString([]byte(`{"a":2,"b":"<"}`))}
+	textEnv := &sslibdsse.Envelope{Payload: base64.StdEncoding.EncodeToString([]byte("test payload"))}
+
+	assert.True(t, EqualPayloads(canonicalEnv, canonicalEnv))
+	assert.True(t, EqualPayloads(canonicalEnv, legacyEnv))
+	assert.False(t, EqualPayloads(canonicalEnv, differentEnv))
+	assert.False(t, EqualPayloads(canonicalEnv, textEnv))
+	assert.True(t, EqualPayloads(textEnv, textEnv))
 }
 
 func TestSignEnvelope(t *testing.T) {
@@ -87,3 +167,97 @@ func createSignedEnvelope() (*sslibdsse.Envelope, error) {
 
 	return env, nil
 }
+
+func vectorRootMetadata(t *testing.T) *tuf.RootMetadata {
+	t.Helper()
+
+	key, err := tuf.LoadKeyFromBytes(publicKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := tuf.NewRootMetadata()
+	rootMetadata.SetVersion(1)
+	rootMetadata.SetExpires("2030-01-01T00:00:00Z")
+	rootMetadata.AddKey(key)
+	rootMetadata.AddRole("root", tuf.Role{KeyIDs: []string{key.KeyID}, Threshold: 1})
+	rootMetadata.AddRole("targets", tuf.Role{KeyIDs: []string{key.KeyID}, Threshold: 1})
+
+	return rootMetadata
+}
+
+func vectorTargetsMetadata(t *testing.T) *tuf.TargetsMetadata {
+	t.Helper()
+
+	key, err := tuf.LoadKeyFromBytes(publicKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata := tuf.NewTargetsMetadata()
+	targetsMetadata.SetVersion(1)
+	targetsMetadata.SetExpires("2030-01-01T00:00:00Z")
+	targetsMetadata.Delegations.AddKey(key)
+	targetsMetadata.Delegations.AddDelegation(tuf.Delegation{
+		Name:          "protect-main",
+		Paths:         []string{"git:refs/heads/main"},
+		Terminating:   true,
+		Role:          tuf.Role{KeyIDs: []string{key.KeyID}, Threshold: 1},
+		RequiredHooks: []string{"secret-scan"},
+	})
+	targetsMetadata.Delegations.AddDelegation(tuf.Delegation{
+		Name:  "gittuf-allow-rule",
+		Paths: []string{"*"},
+		Role:  tuf.Role{KeyIDs: []string{}, Threshold: 1},
+	})
+	targetsMetadata.PredicatePolicies = []tuf.PredicatePolicy{
+		{
+			PredicateType: "https://slsa.dev/provenance/v1",
+			Role:          tuf.Role{KeyIDs: []string{key.KeyID}, Threshold: 1},
+		},
+	}
+
+	return targetsMetadata
+}
+
+func vectorReferenceAuthorization(t *testing.T) *ita.Statement {
+	t.Helper()
+
+	predicate, err := structpb.NewStruct(map[string]any{
+		"targetRef":      "refs/heads/main",
+		"fromRevisionID": "0000000000000000000000000000000000000000",
+		"targetTreeID":   "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &ita.Statement{
+		Type:          ita.StatementTypeUri,
+		Subject:       []*ita.ResourceDescriptor{{Digest: map[string]string{"gitTree": "4b825dc642cb6eb9a060e54bf8d69288fbee4904"}}},
+		PredicateType: "https://gittuf.dev/reference-authorization/v0.1",
+		Predicate:     predicate,
+	}
+}
+
+func vectorPolicyJustification(t *testing.T) *ita.Statement {
+	t.Helper()
+
+	// The reason contains characters that encoding/json escapes by default,
+	// and non-ASCII characters, which must be emitted as is
+	predicate, err := structpb.NewStruct(map[string]any{
+		"policyCommitID": "abcdef12345678900987654321fedcbaabcdef12",
+		"reason":         "Rotate keys after <incident> & review\nApproved by Zoë",
+		"ticket":         "https://example.com/tickets/42",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &ita.Statement{
+		Type:          ita.StatementTypeUri,
+		Subject:       []*ita.ResourceDescriptor{{Digest: map[string]string{"gitCommit": "abcdef12345678900987654321fedcbaabcdef12"}}},
+		PredicateType: "https://gittuf.dev/policy-justification/v0.1",
+		Predicate:     predicate,
+	}
+}
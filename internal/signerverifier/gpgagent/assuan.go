@@ -0,0 +1,377 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gpgagent
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1" //nolint:gosec
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	agentSocketName = "S.gpg-agent"
+
+	// assuanRedirectHeader starts socket files that point to the socket's
+	// actual location.
+	assuanRedirectHeader = "%Assuan%\n"
+
+	// assuanMaxLineLength is the maximum length of a line in the Assuan
+	// protocol, including the trailing newline.
+	assuanMaxLineLength = 1000
+
+	// Error codes returned by the agent, as defined by libgpg-error. The
+	// agent's error values also encode the error's source, so only the low
+	// 16 bits are compared.
+	gpgErrBadPassphrase = 11
+	gpgErrNoSecretKey   = 17
+	gpgErrNoPinentry    = 85
+	gpgErrCanceled      = 99
+	gpgErrCodeMask      = 0xffff
+)
+
+// dial connects to gpg-agent for the GnuPG home directory. It is overridden in
+// tests.
+var dial = func(homeDir string) (net.Conn, error) {
+	for _, path := range socketPaths(homeDir) {
+		conn, err := dialSocket(path, true)
+		if err == nil {
+			return conn, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: unable to connect to '%s': %w", ErrAgentNotAvailable, path, err)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no socket found for GnuPG home directory '%s', start the agent using 'gpg-connect-agent /bye'", ErrAgentNotAvailable, homeDir)
+}
+
+// socketPaths returns the locations where gpg-agent may have created its
+// socket, in the order GnuPG prefers them. The agent uses a directory in the
+// user's runtime directory if it exists, with a subdirectory derived from the
+// home directory if a home directory other than the default is used.
+// Otherwise, the socket is in the home directory.
+func socketPaths(homeDir string) []string {
+	var socketDirs []string
+
+	if runtime.GOOS == "windows" {
+		if localAppData := os.Getenv("LOCALAPPDATA"); localAppData != "" {
+			socketDirs = append(socketDirs, filepath.Join(localAppData, "gnupg"))
+		}
+	} else {
+		uid := strconv.Itoa(os.Getuid())
+		socketDirs = append(socketDirs,
+			filepath.Join("/run/user", uid, "gnupg"),
+			filepath.Join("/var/run/user", uid, "gnupg"),
+		)
+	}
+
+	if defaultDir, err := defaultHomeDir(); err != nil || !sameDir(homeDir, defaultDir) {
+		suffix := homeDirSocketSuffix(homeDir)
+		for i := range socketDirs {
+			socketDirs[i] = filepath.Join(socketDirs[i], suffix)
+		}
+	}
+
+	socketDirs = append(socketDirs, homeDir)
+
+	paths := make([]string, 0, len(socketDirs))
+	for _, dir := range socketDirs {
+		paths = append(paths, filepath.Join(dir, agentSocketName))
+	}
+	return paths
+}
+
+// homeDirSocketSuffix returns the name of the socket subdirectory GnuPG uses
+// for a home directory other than the default: the z-base-32 encoding of the
+// first 120 bits of the SHA-1 digest of the home directory's path.
+func homeDirSocketSuffix(homeDir string) string {
+	if absDir, err := filepath.Abs(homeDir); err == nil {
+		homeDir = absDir
+	}
+
+	digest := sha1.Sum([]byte(homeDir)) //nolint:gosec
+	return "d." + zbase32Encode(digest[:15])
+}
+
+// zbase32Encode encodes the data using the human-oriented base-32 encoding
+// used by GnuPG. The data's length in bits must be a multiple of five.
+func zbase32Encode(data []byte) string {
+	const alphabet = "ybndrfg8ejkmcpqxot1uwisza345h769"
+
+	var (
+		encoded strings.Builder
+		buffer  uint
+		bits    uint
+	)
+	for _, b := range data {
+		buffer = buffer<<8 | uint(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			encoded.WriteByte(alphabet[(buffer>>bits)&0x1f])
+		}
+	}
+
+	return encoded.String()
+}
+
+// dialSocket connects to the socket at the path. Besides Unix domain sockets,
+// the files gpg-agent uses to redirect to another socket and to emulate
+// sockets using TCP on Windows are supported.
+func dialSocket(path string, followRedirect bool) (net.Conn, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode()&os.ModeSocket != 0 {
+		return net.Dial("unix", path)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if redirect, isRedirect := bytes.CutPrefix(contents, []byte(assuanRedirectHeader)); isRedirect {
+		if !followRedirect {
+			return nil, fmt.Errorf("socket file '%s' redirects to another redirect", path)
+		}
+
+		for _, line := range strings.Split(string(redirect), "\n") {
+			if target, isSocket := strings.CutPrefix(line, "socket="); isSocket {
+				return dialSocket(os.ExpandEnv(target), false)
+			}
+		}
+		return nil, fmt.Errorf("socket file '%s' does not specify a socket", path)
+	}
+
+	// The socket is emulated using a TCP connection to the local host. The
+	// file contains the port, followed by a nonce that authenticates the
+	// client
+	port, nonce, found := bytes.Cut(contents, []byte("\n"))
+	if !found || len(nonce) != 16 {
+		return nil, fmt.Errorf("socket file '%s' is malformed", path)
+	}
+	if _, err := strconv.ParseUint(string(port), 10, 16); err != nil {
+		return nil, fmt.Errorf("socket file '%s' is malformed: %w", path, err)
+	}
+
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", string(port)))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(nonce); err != nil {
+		conn.Close() //nolint:errcheck
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// sameDir returns true if both paths refer to the same directory.
+func sameDir(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+// inquireFunc returns the data requested by the agent using INQUIRE. It
+// returns false if the client can't provide the data.
+type inquireFunc func(keyword string) ([]byte, bool)
+
+// client is a client of the Assuan protocol used by gpg-agent.
+type client struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// newClient returns a client for the connection, after reading the agent's
+// greeting.
+func newClient(conn net.Conn) (*client, error) {
+	c := &client{conn: conn, reader: bufio.NewReader(conn)}
+	if _, _, err := c.readResponse(nil); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close closes the connection to the agent.
+func (c *client) Close() error {
+	return c.conn.Close()
+}
+
+// transact sends the command to the agent and returns the data and status
+// lines sent in response.
+func (c *client) transact(command string, inquire inquireFunc) ([]byte, []string, error) {
+	if err := c.writeLine(command); err != nil {
+		return nil, nil, err
+	}
+
+	return c.readResponse(inquire)
+}
+
+// readResponse reads lines sent by the agent until the final OK or ERR line.
+// Inquiries are answered using inquire.
+func (c *client) readResponse(inquire inquireFunc) ([]byte, []string, error) {
+	var (
+		data   []byte
+		status []string
+	)
+
+	for {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to read response from gpg-agent: %w", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if strings.HasPrefix(line, "#") {
+			// Comments are ignored
+			continue
+		}
+
+		keyword, args, _ := strings.Cut(line, " ")
+		switch keyword {
+		case "OK":
+			return data, status, nil
+		case "ERR":
+			return nil, nil, parseAgentError(args)
+		case "D":
+			data = append(data, percentUnescape(args)...)
+		case "S":
+			status = append(status, args)
+		case "INQUIRE":
+			inquiryKeyword, _, _ := strings.Cut(args, " ")
+			if err := c.answerInquiry(inquiryKeyword, inquire); err != nil {
+				return nil, nil, err
+			}
+		default:
+			return nil, nil, fmt.Errorf("unexpected response from gpg-agent: '%s'", line)
+		}
+	}
+}
+
+// answerInquiry sends the requested data to the agent, or cancels the
+// inquiry if it can't be answered.
+func (c *client) answerInquiry(keyword string, inquire inquireFunc) error {
+	var (
+		data []byte
+		ok   bool
+	)
+	if inquire != nil {
+		data, ok = inquire(keyword)
+	}
+	if !ok {
+		return c.writeLine("CAN")
+	}
+
+	escaped := percentEscape(data)
+	// Data lines are split so that they don't exceed the maximum length,
+	// without splitting escape sequences
+	maxDataLength := assuanMaxLineLength - len("D \n")
+	for len(escaped) > 0 {
+		end := min(maxDataLength, len(escaped))
+		if i := strings.LastIndexByte(escaped[:end], '%'); i >= 0 && i+3 > end {
+			end = i
+		}
+
+		if err := c.writeLine("D " + escaped[:end]); err != nil {
+			return err
+		}
+		escaped = escaped[end:]
+	}
+
+	return c.writeLine("END")
+}
+
+func (c *client) writeLine(line string) error {
+	if len(line)+1 > assuanMaxLineLength {
+		return fmt.Errorf("line sent to gpg-agent exceeds the maximum length")
+	}
+
+	if _, err := c.conn.Write([]byte(line + "\n")); err != nil {
+		return fmt.Errorf("unable to send command to gpg-agent: %w", err)
+	}
+	return nil
+}
+
+// parseAgentError returns the error for the arguments of an ERR line, which
+// are the error's code and its description.
+func parseAgentError(args string) error {
+	codeString, description, _ := strings.Cut(args, " ")
+
+	code, err := strconv.ParseUint(codeString, 10, 32)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrAgent, args)
+	}
+
+	switch code & gpgErrCodeMask {
+	case gpgErrNoSecretKey:
+		return fmt.Errorf("%w: %s", ErrKeyNotInAgent, description)
+	case gpgErrNoPinentry:
+		return fmt.Errorf("%w: %s", ErrPassphraseRequired, description)
+	case gpgErrBadPassphrase, gpgErrCanceled:
+		return fmt.Errorf("%w: %s", ErrPassphraseNotAccepted, description)
+	default:
+		return fmt.Errorf("%w: %s", ErrAgent, description)
+	}
+}
+
+// percentEscape escapes the data for use in a data line. The percent sign and
+// line breaks must be escaped.
+func percentEscape(data []byte) string {
+	var escaped strings.Builder
+	for _, b := range data {
+		switch b {
+		case '%', '\r', '\n':
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		default:
+			escaped.WriteByte(b)
+		}
+	}
+	return escaped.String()
+}
+
+// percentUnescape reverses the escaping of a data line.
+func percentUnescape(data string) []byte {
+	unescaped := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] == '%' && i+2 < len(data) {
+			if b, err := strconv.ParseUint(data[i+1:i+3], 16, 8); err == nil {
+				unescaped = append(unescaped, byte(b))
+				i += 2
+				continue
+			}
+		}
+		unescaped = append(unescaped, data[i])
+	}
+	return unescaped
+}
+
+// plusEscape escapes the string for use as a command's argument, where spaces
+// are encoded as plus signs.
+func plusEscape(s string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(s) {
+		switch {
+		case b == ' ':
+			escaped.WriteByte('+')
+		case b == '+' || b == '%' || b < 0x20:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		default:
+			escaped.WriteByte(b)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,616 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gpgagent implements signing using OpenPGP keys whose secret keys are
+// held by gpg-agent, such as keys on a smartcard, without invoking gpg. gittuf
+// talks to the agent directly using the Assuan protocol. Keys are selected
+// using their fingerprint or long key ID, and their public keys are read from
+// the public keyring in GnuPG's home directory.
+package gpgagent
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/ecdsa"
+	"github.com/ProtonMail/go-crypto/openpgp/eddsa"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+const (
+	// KeyPrefix identifies signing keys held by gpg-agent, such as
+	// "gpg-agent:<fingerprint>".
+	KeyPrefix = "gpg-agent:"
+
+	// PassphraseEnvKey is the environment variable used to provide the key's
+	// passphrase or the smartcard's PIN to the agent instead of using its
+	// pinentry program, such as in CI.
+	PassphraseEnvKey = "GITTUF_GPG_PASSPHRASE"
+
+	// PinentryModeError makes the agent fail instead of asking for a
+	// passphrase or PIN, and PinentryModeLoopback makes the agent ask gittuf
+	// for it.
+	PinentryModeError    = "error"
+	PinentryModeLoopback = "loopback"
+
+	homeDirEnvKey = "GNUPGHOME"
+
+	keyboxFileName  = "pubring.kbx"
+	keyringFileName = "pubring.gpg"
+
+	signatureHeader = "PGP SIGNATURE"
+	publicKeyHeader = "PGP PUBLIC KEY BLOCK"
+)
+
+var (
+	ErrAgentNotAvailable     = errors.New("gpg-agent is not available")
+	ErrAgent                 = errors.New("gpg-agent returned an error")
+	ErrKeyNotFound           = errors.New("key not found in GnuPG public keyring")
+	ErrKeyNotInAgent         = errors.New("secret key not found in gpg-agent")
+	ErrInvalidKeySelector    = errors.New("gpg-agent key must be selected using its fingerprint or long key ID")
+	ErrUnsupportedKeyType    = errors.New("gpg-agent key cannot be used to sign, only RSA, ECDSA, and EdDSA keys are supported")
+	ErrPassphraseRequired    = errors.New("gpg-agent requires the key's passphrase or PIN")
+	ErrPassphraseNotAccepted = errors.New("passphrase or PIN was not accepted by gpg-agent")
+)
+
+// Options configure how gpg-agent asks for the key's passphrase or PIN when it
+// is needed to sign.
+type Options struct {
+	// PinentryMode is set as the agent's pinentry mode if it isn't empty,
+	// such as PinentryModeError.
+	PinentryMode string
+
+	// Passphrase is provided to the agent when the pinentry mode is
+	// PinentryModeLoopback.
+	Passphrase string
+
+	// TTYName, TTYType, and Display tell the agent where to show its
+	// pinentry program.
+	TTYName string
+	TTYType string
+	Display string
+}
+
+// DefaultOptions returns the options set in the environment, like gpg does.
+// The agent's pinentry uses the terminal in GPG_TTY, and if the passphrase is
+// set in PassphraseEnvKey, it is provided to the agent using loopback.
+func DefaultOptions() *Options {
+	options := &Options{
+		TTYName: os.Getenv("GPG_TTY"),
+		TTYType: os.Getenv("TERM"),
+		Display: os.Getenv("DISPLAY"),
+	}
+
+	if passphrase, has := os.LookupEnv(PassphraseEnvKey); has {
+		options.PinentryMode = PinentryModeLoopback
+		options.Passphrase = passphrase
+	}
+
+	return options
+}
+
+// IsAgentKey returns true if the signing key identifies a key held by
+// gpg-agent.
+func IsAgentKey(key string) bool {
+	return strings.HasPrefix(key, KeyPrefix)
+}
+
+// Check checks that the key selected using its fingerprint or long key ID is
+// in GnuPG's public keyring, and that gpg-agent holds its secret key.
+func Check(selector string) error {
+	_, publicKey, err := findKey(selector)
+	if err != nil {
+		return err
+	}
+
+	return withAgent(&Options{}, func(c *client) error {
+		_, err := findKeygrip(c, publicKey)
+		return err
+	})
+}
+
+// ExportPublicKey returns the armored public key of the key selected using its
+// fingerprint or long key ID, as exported by `gpg --export --armor`.
+func ExportPublicKey(selector string) ([]byte, error) {
+	entity, _, err := findKey(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := new(bytes.Buffer)
+	writer, err := armor.Encode(exported, publicKeyHeader, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := entity.Serialize(writer); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return exported.Bytes(), nil
+}
+
+// Sign creates an armored detached OpenPGP signature of the contents, as
+// created by `gpg -bsa`, using the key selected using its fingerprint or long
+// key ID. If the primary key is selected, its newest signing subkey is used
+// like gpg does. The secret key is used by gpg-agent, which may ask for its
+// passphrase or PIN as configured in options. The contents are hashed as they
+// are read, so they aren't held in memory.
+func Sign(contents io.Reader, selector string, options *Options) (string, error) {
+	_, publicKey, err := findKey(selector)
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := hashForKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	var signature []byte
+	err = withAgent(options, func(c *client) error {
+		keygrip, err := findKeygrip(c, publicKey)
+		if err != nil {
+			return err
+		}
+
+		signature, err = newSignature(contents, publicKey, hash, time.Now(), func(digest []byte) ([][]byte, error) {
+			return pkSign(c, options, publicKey, keygrip, hash, digest)
+		})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	armored := new(bytes.Buffer)
+	writer, err := armor.Encode(armored, signatureHeader, nil)
+	if err != nil {
+		return "", err
+	}
+	if _, err := writer.Write(signature); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	armored.WriteString("\n")
+
+	return armored.String(), nil
+}
+
+// withAgent connects to gpg-agent, sets the options, and invokes fn. The
+// connection to the agent is closed when fn returns.
+func withAgent(options *Options, fn func(*client) error) error {
+	homeDir, err := gnupgHomeDir()
+	if err != nil {
+		return err
+	}
+
+	conn, err := dial(homeDir)
+	if err != nil {
+		return err
+	}
+
+	c, err := newClient(conn)
+	if err != nil {
+		conn.Close() //nolint:errcheck
+		return err
+	}
+	defer c.Close() //nolint:errcheck
+
+	agentOptions := []struct{ name, value string }{
+		{"ttyname", options.TTYName},
+		{"ttytype", options.TTYType},
+		{"display", options.Display},
+		{"pinentry-mode", options.PinentryMode},
+	}
+	for _, option := range agentOptions {
+		if option.value == "" {
+			continue
+		}
+		if _, _, err := c.transact(fmt.Sprintf("OPTION %s=%s", option.name, plusEscape(option.value)), nil); err != nil {
+			return fmt.Errorf("unable to set gpg-agent option '%s': %w", option.name, err)
+		}
+	}
+
+	return fn(c)
+}
+
+// findKeygrip returns the keygrip the agent uses to identify the public key's
+// secret key. Instead of computing the keygrip, the public keys of the agent's
+// keys are compared with the public key.
+func findKeygrip(c *client, publicKey *packet.PublicKey) (string, error) {
+	_, status, err := c.transact("KEYINFO --list", nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to list keys in gpg-agent: %w", err)
+	}
+
+	for _, line := range status {
+		// Lines are of the form "KEYINFO <keygrip> <type> ..."
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "KEYINFO" {
+			continue
+		}
+		keygrip := fields[1]
+
+		data, _, err := c.transact("READKEY "+keygrip, nil)
+		if err != nil {
+			// Keys whose public key can't be read can't be compared
+			continue
+		}
+
+		agentKey, err := parseSExpression(data)
+		if err != nil {
+			return "", err
+		}
+		if publicKeyMatches(agentKey, publicKey) {
+			return keygrip, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrKeyNotInAgent, strings.ToUpper(hex.EncodeToString(publicKey.Fingerprint)))
+}
+
+// publicKeyMatches returns true if the public key read from the agent is the
+// OpenPGP public key.
+func publicKeyMatches(agentKey *sexp, publicKey *packet.PublicKey) bool {
+	switch key := publicKey.PublicKey.(type) {
+	case *rsa.PublicKey:
+		n, err := agentKey.value("n")
+		if err != nil {
+			return false
+		}
+		return new(big.Int).SetBytes(n).Cmp(key.N) == 0
+	case *ecdsa.PublicKey:
+		q, err := agentKey.value("q")
+		if err != nil {
+			return false
+		}
+		return bytes.Equal(q, key.MarshalPoint())
+	case *eddsa.PublicKey:
+		q, err := agentKey.value("q")
+		if err != nil {
+			return false
+		}
+		// The point may be prefixed with 0x40 to indicate its native
+		// encoding
+		if len(q) == len(key.X)+1 && q[0] == 0x40 {
+			q = q[1:]
+		}
+		return bytes.Equal(q, key.X)
+	default:
+		return false
+	}
+}
+
+// pkSign signs the digest using the agent's key, returning the values of the
+// signature in the order they're encoded in OpenPGP signatures.
+func pkSign(c *client, options *Options, publicKey *packet.PublicKey, keygrip string, hash crypto.Hash, digest []byte) ([][]byte, error) {
+	hashID, ok := libgcryptHashIDs[hash]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash algorithm '%s'", hash)
+	}
+
+	description := fmt.Sprintf("gittuf needs the passphrase or PIN to sign using the OpenPGP key %s.", strings.ToUpper(hex.EncodeToString(publicKey.Fingerprint)))
+	commands := []string{
+		"SIGKEY " + keygrip,
+		"SETKEYDESC " + plusEscape(description),
+		fmt.Sprintf("SETHASH %d %s", hashID, hex.EncodeToString(digest)),
+	}
+	for _, command := range commands {
+		if _, _, err := c.transact(command, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	data, _, err := c.transact("PKSIGN", func(keyword string) ([]byte, bool) {
+		if options.PinentryMode != PinentryModeLoopback {
+			return nil, false
+		}
+
+		switch keyword {
+		case "PASSPHRASE", "PIN":
+			return []byte(options.Passphrase), true
+		default:
+			return nil, false
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign using gpg-agent: %w", err)
+	}
+
+	signature, err := parseSExpression(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	switch publicKey.PubKeyAlgo {
+	case packet.PubKeyAlgoRSA, packet.PubKeyAlgoRSASignOnly:
+		names = []string{"s"}
+	default:
+		names = []string{"r", "s"}
+	}
+
+	values := make([][]byte, 0, len(names))
+	for _, name := range names {
+		value, err := signature.value(name)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// libgcryptHashIDs maps hash algorithms to their IDs used by the agent, which
+// are the same as the IDs used in OpenPGP.
+var libgcryptHashIDs = map[crypto.Hash]uint8{
+	crypto.SHA256: 8,
+	crypto.SHA384: 9,
+	crypto.SHA512: 10,
+}
+
+// hashForKey returns the hash algorithm used to sign using the key. Like gpg,
+// ECDSA keys use a digest of the curve's size, and other keys use SHA-512.
+func hashForKey(publicKey *packet.PublicKey) (crypto.Hash, error) {
+	switch key := publicKey.PublicKey.(type) {
+	case *rsa.PublicKey, *eddsa.PublicKey:
+		return crypto.SHA512, nil
+	case *ecdsa.PublicKey:
+		// The point is encoded as 0x04 followed by both coordinates
+		switch coordinateSize := (len(key.MarshalPoint()) - 1) / 2; {
+		case coordinateSize <= 32:
+			return crypto.SHA256, nil
+		case coordinateSize <= 48:
+			return crypto.SHA384, nil
+		default:
+			return crypto.SHA512, nil
+		}
+	default:
+		return 0, fmt.Errorf("%w: algorithm %d", ErrUnsupportedKeyType, publicKey.PubKeyAlgo)
+	}
+}
+
+// newSignature returns a version 4 OpenPGP signature packet of the contents
+// treated as binary data, as described in RFC 4880, section 5.2.3. The digest
+// is signed by sign, which returns the signature's values.
+func newSignature(contents io.Reader, publicKey *packet.PublicKey, hash crypto.Hash, creationTime time.Time, sign func([]byte) ([][]byte, error)) ([]byte, error) {
+	hashID, ok := libgcryptHashIDs[hash]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash algorithm '%s'", hash)
+	}
+
+	hashedSubpackets := new(bytes.Buffer)
+	// Signature creation time
+	writeSubpacket(hashedSubpackets, 2, binary.BigEndian.AppendUint32(nil, uint32(creationTime.Unix())))
+	// Issuer fingerprint, prefixed with the key's version
+	writeSubpacket(hashedSubpackets, 33, append([]byte{4}, publicKey.Fingerprint...))
+
+	unhashedSubpackets := new(bytes.Buffer)
+	// Issuer key ID
+	writeSubpacket(unhashedSubpackets, 16, binary.BigEndian.AppendUint64(nil, publicKey.KeyId))
+
+	hashedPart := []byte{4, byte(packet.SigTypeBinary), byte(publicKey.PubKeyAlgo), hashID}
+	hashedPart = binary.BigEndian.AppendUint16(hashedPart, uint16(hashedSubpackets.Len()))
+	hashedPart = append(hashedPart, hashedSubpackets.Bytes()...)
+
+	hasher := hash.New()
+	if _, err := io.Copy(hasher, contents); err != nil {
+		return nil, err
+	}
+	hasher.Write(hashedPart)
+	// The trailer includes the length of the hashed part
+	hasher.Write(binary.BigEndian.AppendUint32([]byte{4, 0xff}, uint32(len(hashedPart))))
+	digest := hasher.Sum(nil)
+
+	values, err := sign(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	body := bytes.NewBuffer(hashedPart)
+	body.Write(binary.BigEndian.AppendUint16(nil, uint16(unhashedSubpackets.Len())))
+	body.Write(unhashedSubpackets.Bytes())
+	body.Write(digest[:2])
+	for _, value := range values {
+		writeMPI(body, value)
+	}
+
+	// The packet uses the new format header, with the tag of signature
+	// packets
+	signature := []byte{0xc0 | 2}
+	switch length := body.Len(); {
+	case length < 192:
+		signature = append(signature, byte(length))
+	case length < 8384:
+		length -= 192
+		signature = append(signature, byte(length>>8)+192, byte(length))
+	default:
+		signature = append(signature, 0xff)
+		signature = binary.BigEndian.AppendUint32(signature, uint32(length))
+	}
+
+	return append(signature, body.Bytes()...), nil
+}
+
+// writeSubpacket writes a signature subpacket whose contents are shorter than
+// 191 bytes.
+func writeSubpacket(w *bytes.Buffer, subpacketType byte, contents []byte) {
+	w.WriteByte(byte(len(contents) + 1))
+	w.WriteByte(subpacketType)
+	w.Write(contents)
+}
+
+// writeMPI writes the big-endian integer as an OpenPGP multiprecision
+// integer, prefixed with its length in bits.
+func writeMPI(w *bytes.Buffer, value []byte) {
+	value = bytes.TrimLeft(value, "\x00")
+	w.Write(binary.BigEndian.AppendUint16(nil, uint16(new(big.Int).SetBytes(value).BitLen())))
+	w.Write(value)
+}
+
+// findKey returns the entity in GnuPG's public keyring that contains the key
+// selected using its fingerprint or long key ID, along with the key used to
+// sign.
+func findKey(selector string) (*openpgp.Entity, *packet.PublicKey, error) {
+	// Like gpg, the selector may be prefixed with "0x" and suffixed with
+	// "!", and fingerprints may contain spaces
+	selector = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(selector), " ", ""))
+	selector = strings.TrimSuffix(strings.TrimPrefix(selector, "0X"), "!")
+	if _, err := hex.DecodeString(selector); err != nil || (len(selector) != 16 && len(selector) != 40) {
+		return nil, nil, fmt.Errorf("%w: '%s'", ErrInvalidKeySelector, selector)
+	}
+
+	homeDir, err := gnupgHomeDir()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyring, err := readKeyring(homeDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	for _, entity := range keyring {
+		if keyMatchesSelector(entity.PrimaryKey, selector) {
+			key, ok := entity.SigningKey(now)
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: '%s' has no valid signing key", ErrKeyNotFound, selector)
+			}
+			return entity, key.PublicKey, nil
+		}
+
+		for _, subkey := range entity.Subkeys {
+			if keyMatchesSelector(subkey.PublicKey, selector) {
+				key, ok := entity.SigningKeyById(now, subkey.PublicKey.KeyId)
+				if !ok {
+					return nil, nil, fmt.Errorf("%w: subkey '%s' is not a valid signing key", ErrKeyNotFound, selector)
+				}
+				return entity, key.PublicKey, nil
+			}
+		}
+	}
+
+	return nil, nil, fmt.Errorf("%w: '%s'", ErrKeyNotFound, selector)
+}
+
+func keyMatchesSelector(publicKey *packet.PublicKey, selector string) bool {
+	if len(selector) == 16 {
+		return publicKey.KeyIdString() == selector
+	}
+	return strings.ToUpper(hex.EncodeToString(publicKey.Fingerprint)) == selector
+}
+
+// readKeyring reads the public keys in the GnuPG home directory, which are
+// stored in the keybox format since GnuPG 2.1, and in an OpenPGP keyring by
+// older versions.
+func readKeyring(homeDir string) (openpgp.EntityList, error) {
+	keybox, err := os.ReadFile(filepath.Join(homeDir, keyboxFileName))
+	if err == nil {
+		return parseKeybox(keybox)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	keyring, err := os.Open(filepath.Join(homeDir, keyringFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: no public keyring in '%s'", ErrKeyNotFound, homeDir)
+		}
+		return nil, err
+	}
+	defer keyring.Close() //nolint:errcheck
+
+	return openpgp.ReadKeyRing(keyring)
+}
+
+// parseKeybox returns the OpenPGP keys in a keybox file. The file is a
+// sequence of blobs, each starting with its length and type. OpenPGP blobs
+// contain the offset and length of the key within the blob. Keys that can't
+// be parsed, such as keys using unsupported algorithms, are skipped.
+func parseKeybox(keybox []byte) (openpgp.EntityList, error) {
+	const (
+		blobHeaderLength = 16
+		blobTypeOpenPGP  = 2
+	)
+
+	var keyring openpgp.EntityList
+	for len(keybox) > 0 {
+		if len(keybox) < 5 {
+			return nil, fmt.Errorf("keybox is truncated")
+		}
+
+		blobLength := binary.BigEndian.Uint32(keybox)
+		if blobLength < 5 || uint64(blobLength) > uint64(len(keybox)) {
+			return nil, fmt.Errorf("keybox contains blob with invalid length %d", blobLength)
+		}
+		blob := keybox[:blobLength]
+		keybox = keybox[blobLength:]
+
+		if blob[4] != blobTypeOpenPGP {
+			continue
+		}
+		if len(blob) < blobHeaderLength {
+			return nil, fmt.Errorf("keybox contains truncated OpenPGP blob")
+		}
+
+		keyOffset := binary.BigEndian.Uint32(blob[8:])
+		keyLength := binary.BigEndian.Uint32(blob[12:])
+		if uint64(keyOffset)+uint64(keyLength) > uint64(len(blob)) {
+			return nil, fmt.Errorf("keybox contains OpenPGP blob with invalid key offset")
+		}
+
+		entities, err := openpgp.ReadKeyRing(bytes.NewReader(blob[keyOffset : keyOffset+keyLength]))
+		if err != nil {
+			continue
+		}
+		keyring = append(keyring, entities...)
+	}
+
+	return keyring, nil
+}
+
+// gnupgHomeDir returns GnuPG's home directory, which is set using GNUPGHOME.
+func gnupgHomeDir() (string, error) {
+	if homeDir := os.Getenv(homeDirEnvKey); homeDir != "" {
+		return homeDir, nil
+	}
+
+	return defaultHomeDir()
+}
+
+// defaultHomeDir returns the home directory GnuPG uses if GNUPGHOME isn't set.
+func defaultHomeDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "gnupg"), nil
+		}
+	}
+
+	userHomeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(userHomeDir, ".gnupg"), nil
+}
@@ -0,0 +1,419 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gpgagent
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/eddsa"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAgent implements the subset of gpg-agent's Assuan commands used to sign.
+// Keys are identified using their fingerprints instead of keygrips.
+type fakeAgent struct {
+	keys map[string]*packet.PrivateKey
+
+	// passphrase is required to sign if it's set, and is only accepted
+	// using loopback
+	passphrase string
+}
+
+func (a *fakeAgent) serve(conn net.Conn) {
+	defer conn.Close() //nolint:errcheck
+
+	reader := bufio.NewReader(conn)
+	readLine := func() (string, bool) {
+		line, err := reader.ReadString('\n')
+		return strings.TrimSuffix(line, "\n"), err == nil
+	}
+	writeLine := func(line string) {
+		conn.Write([]byte(line + "\n")) //nolint:errcheck
+	}
+
+	writeLine("OK Pleased to meet you")
+
+	var (
+		pinentryMode string
+		keygrip      string
+		digest       []byte
+	)
+	for {
+		line, ok := readLine()
+		if !ok {
+			return
+		}
+
+		command, args, _ := strings.Cut(line, " ")
+		switch command {
+		case "OPTION":
+			if mode, isMode := strings.CutPrefix(args, "pinentry-mode="); isMode {
+				pinentryMode = mode
+			}
+			writeLine("OK")
+		case "KEYINFO":
+			for keygrip := range a.keys {
+				writeLine(fmt.Sprintf("S KEYINFO %s D - - - P - - -", keygrip))
+			}
+			writeLine("OK")
+		case "READKEY":
+			key, has := a.keys[args]
+			if !has {
+				writeLine("ERR 67108881 No secret key <GPG Agent>")
+				continue
+			}
+			writeLine("D " + percentEscape(publicKeySExpression(key)))
+			writeLine("OK")
+		case "SIGKEY":
+			keygrip = args
+			writeLine("OK")
+		case "SETKEYDESC":
+			writeLine("OK")
+		case "SETHASH":
+			_, hexDigest, _ := strings.Cut(args, " ")
+			digest, _ = hex.DecodeString(hexDigest)
+			writeLine("OK")
+		case "PKSIGN":
+			if a.passphrase != "" {
+				if pinentryMode != PinentryModeLoopback {
+					writeLine("ERR 67108949 No pinentry <GPG Agent>")
+					continue
+				}
+
+				writeLine("INQUIRE PASSPHRASE")
+				var passphrase []byte
+				for {
+					line, ok := readLine()
+					if !ok {
+						return
+					}
+					if data, isData := strings.CutPrefix(line, "D "); isData {
+						passphrase = append(passphrase, percentUnescape(data)...)
+						continue
+					}
+					break
+				}
+				if string(passphrase) != a.passphrase {
+					writeLine("ERR 67108875 Bad passphrase <GPG Agent>")
+					continue
+				}
+			}
+
+			writeLine("D " + percentEscape(signatureSExpression(a.keys[keygrip], digest)))
+			writeLine("OK")
+		default:
+			writeLine("ERR 536871187 Unknown IPC command <User defined source 1>")
+		}
+	}
+}
+
+func atom(value []byte) string {
+	return fmt.Sprintf("%d:%s", len(value), value)
+}
+
+func publicKeySExpression(key *packet.PrivateKey) []byte {
+	switch privateKey := key.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		e := binary.BigEndian.AppendUint32(nil, uint32(privateKey.E))
+		return []byte(fmt.Sprintf("(10:public-key(3:rsa(1:n%s)(1:e%s)))", atom(privateKey.N.Bytes()), atom(bytes.TrimLeft(e, "\x00"))))
+	case *eddsa.PrivateKey:
+		return []byte(fmt.Sprintf("(10:public-key(3:ecc(5:curve7:Ed25519)(5:flags5:eddsa)(1:q%s)))", atom(append([]byte{0x40}, privateKey.X...))))
+	default:
+		return nil
+	}
+}
+
+func signatureSExpression(key *packet.PrivateKey, digest []byte) []byte {
+	switch privateKey := key.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		signature, err := rsa.SignPKCS1v15(nil, privateKey, crypto.SHA512, digest)
+		if err != nil {
+			return nil
+		}
+		return []byte(fmt.Sprintf("(7:sig-val(3:rsa(1:s%s)))", atom(signature)))
+	case *eddsa.PrivateKey:
+		signature := ed25519.Sign(ed25519.NewKeyFromSeed(privateKey.D), digest)
+		return []byte(fmt.Sprintf("(7:sig-val(5:eddsa(1:r%s)(1:s%s)))", atom(signature[:32]), atom(signature[32:])))
+	default:
+		return nil
+	}
+}
+
+func useFakeAgent(t *testing.T, agent *fakeAgent) {
+	t.Helper()
+
+	originalDial := dial
+	dial = func(string) (net.Conn, error) {
+		clientConn, agentConn := net.Pipe()
+		go agent.serve(agentConn)
+		return clientConn, nil
+	}
+	t.Cleanup(func() { dial = originalDial })
+}
+
+// setupHomeDir creates a GnuPG home directory whose public keyring contains
+// the entities.
+func setupHomeDir(t *testing.T, entities ...*openpgp.Entity) {
+	t.Helper()
+
+	homeDir := t.TempDir()
+	t.Setenv(homeDirEnvKey, homeDir)
+
+	keyring, err := os.Create(filepath.Join(homeDir, keyringFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer keyring.Close() //nolint:errcheck
+
+	for _, entity := range entities {
+		if err := entity.Serialize(keyring); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func fingerprint(entity *openpgp.Entity) string {
+	return strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint))
+}
+
+func TestSign(t *testing.T) {
+	rsaEntity, err := openpgp.NewEntity("Jane Doe", "", "jane.doe@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoRSA, RSABits: 2048})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eddsaEntity, err := openpgp.NewEntity("John Doe", "", "john.doe@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	setupHomeDir(t, rsaEntity, eddsaEntity)
+
+	contents := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nInitial commit\n")
+
+	verify := func(t *testing.T, entity *openpgp.Entity, signature string) {
+		t.Helper()
+
+		assert.True(t, strings.HasPrefix(signature, "-----BEGIN PGP SIGNATURE-----"))
+		signer, err := openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{entity}, bytes.NewReader(contents), strings.NewReader(signature), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.PrimaryKey.KeyId, signer.PrimaryKey.KeyId)
+	}
+
+	useFakeAgent(t, &fakeAgent{keys: map[string]*packet.PrivateKey{
+		fingerprint(rsaEntity):   rsaEntity.PrivateKey,
+		fingerprint(eddsaEntity): eddsaEntity.PrivateKey,
+	}})
+
+	t.Run("RSA key selected using fingerprint", func(t *testing.T) {
+		signature, err := Sign(bytes.NewReader(contents), fingerprint(rsaEntity), &Options{})
+		assert.Nil(t, err)
+		verify(t, rsaEntity, signature)
+	})
+
+	t.Run("EdDSA key selected using key ID", func(t *testing.T) {
+		signature, err := Sign(bytes.NewReader(contents), "0x"+eddsaEntity.PrimaryKey.KeyIdString(), &Options{})
+		assert.Nil(t, err)
+		verify(t, eddsaEntity, signature)
+	})
+
+	t.Run("key not in keyring", func(t *testing.T) {
+		_, err := Sign(bytes.NewReader(contents), "0123456789ABCDEF", &Options{})
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	})
+
+	t.Run("invalid selector", func(t *testing.T) {
+		_, err := Sign(bytes.NewReader(contents), "jane.doe@example.com", &Options{})
+		assert.ErrorIs(t, err, ErrInvalidKeySelector)
+	})
+
+	t.Run("key not in agent", func(t *testing.T) {
+		useFakeAgent(t, &fakeAgent{keys: map[string]*packet.PrivateKey{
+			fingerprint(rsaEntity): rsaEntity.PrivateKey,
+		}})
+
+		_, err := Sign(bytes.NewReader(contents), fingerprint(eddsaEntity), &Options{})
+		assert.ErrorIs(t, err, ErrKeyNotInAgent)
+	})
+
+	t.Run("passphrase", func(t *testing.T) {
+		useFakeAgent(t, &fakeAgent{
+			keys:       map[string]*packet.PrivateKey{fingerprint(eddsaEntity): eddsaEntity.PrivateKey},
+			passphrase: "correct horse battery staple",
+		})
+
+		_, err := Sign(bytes.NewReader(contents), fingerprint(eddsaEntity), &Options{PinentryMode: PinentryModeError})
+		assert.ErrorIs(t, err, ErrPassphraseRequired)
+
+		_, err = Sign(bytes.NewReader(contents), fingerprint(eddsaEntity), &Options{PinentryMode: PinentryModeLoopback, Passphrase: "incorrect"})
+		assert.ErrorIs(t, err, ErrPassphraseNotAccepted)
+
+		t.Setenv(PassphraseEnvKey, "correct horse battery staple")
+		signature, err := Sign(bytes.NewReader(contents), fingerprint(eddsaEntity), DefaultOptions())
+		assert.Nil(t, err)
+		verify(t, eddsaEntity, signature)
+	})
+}
+
+func TestCheck(t *testing.T) {
+	entity, err := openpgp.NewEntity("Jane Doe", "", "jane.doe@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	setupHomeDir(t, entity)
+
+	useFakeAgent(t, &fakeAgent{keys: map[string]*packet.PrivateKey{fingerprint(entity): entity.PrivateKey}})
+	assert.Nil(t, Check(fingerprint(entity)))
+
+	useFakeAgent(t, &fakeAgent{keys: map[string]*packet.PrivateKey{}})
+	assert.ErrorIs(t, Check(fingerprint(entity)), ErrKeyNotInAgent)
+
+	exported, err := ExportPublicKey(fingerprint(entity))
+	assert.Nil(t, err)
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(exported))
+	assert.Nil(t, err)
+	assert.Equal(t, entity.PrimaryKey.Fingerprint, keyring[0].PrimaryKey.Fingerprint)
+}
+
+func TestParseKeybox(t *testing.T) {
+	entity, err := openpgp.NewEntity("Jane Doe", "", "jane.doe@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := new(bytes.Buffer)
+	if err := entity.Serialize(key); err != nil {
+		t.Fatal(err)
+	}
+
+	// The keybox starts with a header blob, followed by an OpenPGP blob
+	keybox := binary.BigEndian.AppendUint32(nil, 32)
+	keybox = append(keybox, 1, 1)
+	keybox = append(keybox, make([]byte, 26)...)
+	keybox = binary.BigEndian.AppendUint32(keybox, uint32(16+key.Len()))
+	keybox = append(keybox, 2, 1, 0, 0)
+	keybox = binary.BigEndian.AppendUint32(keybox, 16)
+	keybox = binary.BigEndian.AppendUint32(keybox, uint32(key.Len()))
+	keybox = append(keybox, key.Bytes()...)
+
+	keyring, err := parseKeybox(keybox)
+	assert.Nil(t, err)
+	if assert.Len(t, keyring, 1) {
+		assert.Equal(t, entity.PrimaryKey.Fingerprint, keyring[0].PrimaryKey.Fingerprint)
+	}
+
+	_, err = parseKeybox(keybox[:len(keybox)-1])
+	assert.NotNil(t, err)
+}
+
+func TestSocketPaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runtime directories are not used on Windows")
+	}
+
+	// The suffix matches `gpgconf --list-dirs socketdir` when GNUPGHOME is
+	// set to /tmp/gh
+	assert.Equal(t, "d.ffabiqijjfckceggnzrykjtw", homeDirSocketSuffix("/tmp/gh"))
+
+	paths := socketPaths("/tmp/gh")
+	assert.Equal(t, filepath.Join("/tmp/gh", agentSocketName), paths[len(paths)-1])
+	assert.Contains(t, paths, filepath.Join("/run/user", fmt.Sprint(os.Getuid()), "gnupg", "d.ffabiqijjfckceggnzrykjtw", agentSocketName))
+}
+
+func TestDialSocket(t *testing.T) {
+	t.Run("emulated socket", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close() //nolint:errcheck
+
+		nonce := []byte("0123456789abcdef")
+		received := make(chan []byte, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				received <- nil
+				return
+			}
+			defer conn.Close() //nolint:errcheck
+
+			buffer := make([]byte, len(nonce))
+			_, err = conn.Read(buffer)
+			if err != nil {
+				received <- nil
+				return
+			}
+			received <- buffer
+		}()
+
+		socketPath := filepath.Join(t.TempDir(), agentSocketName)
+		port := listener.Addr().(*net.TCPAddr).Port
+		if err := os.WriteFile(socketPath, append([]byte(fmt.Sprintf("%d\n", port)), nonce...), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		conn, err := dialSocket(socketPath, true)
+		assert.Nil(t, err)
+		defer conn.Close() //nolint:errcheck
+		assert.Equal(t, nonce, <-received)
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), agentSocketName)
+		if err := os.WriteFile(socketPath, []byte(assuanRedirectHeader+"socket="+socketPath+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Redirects to other redirects aren't followed
+		_, err := dialSocket(socketPath, true)
+		assert.ErrorContains(t, err, "redirects to another redirect")
+	})
+
+	t.Run("missing socket", func(t *testing.T) {
+		_, err := dialSocket(filepath.Join(t.TempDir(), agentSocketName), true)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestPercentEscape(t *testing.T) {
+	data := []byte("100% (\n)\r")
+	escaped := percentEscape(data)
+	assert.Equal(t, "100%25 (%0A)%0D", escaped)
+	assert.Equal(t, data, percentUnescape(escaped))
+
+	assert.Equal(t, "gittuf+needs+a%2Bb%25", plusEscape("gittuf needs a+b%"))
+}
+
+func TestParseSExpression(t *testing.T) {
+	expression, err := parseSExpression([]byte("(7:sig-val(5:ecdsa(1:r2:ab)(1:s3:c)d)))"))
+	assert.Nil(t, err)
+
+	r, err := expression.value("r")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("ab"), r)
+	s, err := expression.value("s")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("c)d"), s)
+
+	_, err = expression.value("n")
+	assert.ErrorIs(t, err, ErrInvalidSExpression)
+
+	for _, invalid := range []string{"", "3:abc", "(1:s", "(1:s5:abc)", "(1:s)extra"} {
+		_, err := parseSExpression([]byte(invalid))
+		assert.ErrorIs(t, err, ErrInvalidSExpression, invalid)
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gpgagent
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var ErrInvalidSExpression = errors.New("invalid S-expression returned by gpg-agent")
+
+// sexp is a node of an S-expression in the canonical encoding used by
+// gpg-agent for keys and signatures, such as "(7:sig-val(3:rsa(1:s3:...)))".
+// A node is either an atom or a list.
+type sexp struct {
+	atom []byte
+	list []*sexp
+}
+
+// parseSExpression parses an S-expression in the canonical encoding.
+func parseSExpression(data []byte) (*sexp, error) {
+	node, rest, err := parseSExpressionNode(data)
+	if err != nil {
+		return nil, err
+	}
+	if node.list == nil || len(rest) != 0 {
+		return nil, ErrInvalidSExpression
+	}
+
+	return node, nil
+}
+
+func parseSExpressionNode(data []byte) (*sexp, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, ErrInvalidSExpression
+	}
+
+	if data[0] == '(' {
+		node := &sexp{list: []*sexp{}}
+		data = data[1:]
+		for {
+			if len(data) == 0 {
+				return nil, nil, ErrInvalidSExpression
+			}
+			if data[0] == ')' {
+				return node, data[1:], nil
+			}
+
+			child, rest, err := parseSExpressionNode(data)
+			if err != nil {
+				return nil, nil, err
+			}
+			node.list = append(node.list, child)
+			data = rest
+		}
+	}
+
+	// Atoms are prefixed with their length in decimal
+	i := 0
+	for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+		i++
+	}
+	if i == 0 || i == len(data) || data[i] != ':' {
+		return nil, nil, ErrInvalidSExpression
+	}
+
+	length, err := strconv.Atoi(string(data[:i]))
+	if err != nil || length > len(data)-i-1 {
+		return nil, nil, ErrInvalidSExpression
+	}
+
+	data = data[i+1:]
+	return &sexp{atom: data[:length]}, data[length:], nil
+}
+
+// name returns the atom that starts the list, if any.
+func (s *sexp) name() string {
+	if len(s.list) == 0 || s.list[0].list != nil {
+		return ""
+	}
+	return string(s.list[0].atom)
+}
+
+// find returns the first list, searching depth first, whose name matches.
+func (s *sexp) find(name string) *sexp {
+	if s.name() == name {
+		return s
+	}
+
+	for _, child := range s.list {
+		if child.list == nil {
+			continue
+		}
+		if found := child.find(name); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// value returns the atom that follows the name in the first list whose name
+// matches, such as the modulus in "(1:n3:...)".
+func (s *sexp) value(name string) ([]byte, error) {
+	found := s.find(name)
+	if found == nil || len(found.list) < 2 || found.list[1].list != nil {
+		return nil, fmt.Errorf("%w: '%s' not found", ErrInvalidSExpression, name)
+	}
+
+	return found.list[1].atom, nil
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package kms implements signing using keys held in a key management service,
+// so that CI systems can sign without local key material. Keys are identified
+// by the URIs used by Sigstore's tools, such as "awskms:///alias/gittuf-root"
+// for AWS KMS and "hashivault://gittuf-root" for HashiCorp Vault's transit
+// secrets engine. The providers' credentials are obtained from the
+// environment, as described in their documentation.
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	sigkms "github.com/sigstore/sigstore/pkg/signature/kms"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+
+	// The supported providers register themselves
+	_ "github.com/sigstore/sigstore/pkg/signature/kms/aws"
+	_ "github.com/sigstore/sigstore/pkg/signature/kms/hashivault"
+)
+
+var (
+	ErrUnsupportedKeyType = errors.New("KMS key cannot be used with gittuf, only ECDSA keys are supported")
+	ErrUnknownProvider    = errors.New("unknown KMS provider")
+)
+
+// IsKMSKey returns true if the signing key is the URI of a key held in one of
+// the supported key management services.
+func IsKMSKey(key string) bool {
+	for _, scheme := range sigkms.SupportedProviders() {
+		if strings.HasPrefix(key, scheme) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SupportedSchemes returns the URI schemes of the supported key management
+// services.
+func SupportedSchemes() []string {
+	schemes := sigkms.SupportedProviders()
+	sort.Strings(schemes)
+	return schemes
+}
+
+// Signer is a dsse.SignerVerifier backed by a key held in a key management
+// service.
+type Signer struct {
+	signer   crypto.Signer
+	hash     crypto.Hash
+	key      *tuf.Key
+	verifier dsse.Verifier
+}
+
+// NewSigner returns a Signer for the key identified by the URI. The key must
+// be an ECDSA key, so that signatures created using the key management
+// service can be verified like those of ECDSA keys on disk.
+func NewSigner(ctx context.Context, uri string) (*Signer, error) {
+	if !IsKMSKey(uri) {
+		return nil, fmt.Errorf("%w: '%s', expected one of %s", ErrUnknownProvider, uri, strings.Join(SupportedSchemes(), ", "))
+	}
+
+	signerVerifier, err := sigkms.Get(ctx, uri, crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load KMS key '%s': %w", uri, err)
+	}
+
+	publicKey, err := signerVerifier.PublicKey(options.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch public key of KMS key '%s': %w", uri, err)
+	}
+
+	ecdsaPublicKey, isECDSA := publicKey.(*ecdsa.PublicKey)
+	if !isECDSA {
+		return nil, fmt.Errorf("%w: '%s' is a %T", ErrUnsupportedKeyType, uri, publicKey)
+	}
+
+	signer, _, err := signerVerifier.CryptoSigner(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load KMS key '%s': %w", uri, err)
+	}
+
+	key, err := sslibsv.NewKey(ecdsaPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := sslibsv.NewVerifierFromSSLibKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{
+		signer:   signer,
+		hash:     hashForCurve(ecdsaPublicKey),
+		key:      key,
+		verifier: verifier,
+	}, nil
+}
+
+// Sign signs the data using the key management service. The data is hashed
+// locally using the hash securesystemslib uses for the key's curve, and the
+// ASN.1 encoded signature returned by the service is used as is.
+func (s *Signer) Sign(_ context.Context, data []byte) ([]byte, error) {
+	hash := s.hash.New()
+	hash.Write(data) //nolint:errcheck
+
+	return s.signer.Sign(rand.Reader, hash.Sum(nil), s.hash)
+}
+
+// Verify verifies the signature using the key's public key.
+func (s *Signer) Verify(ctx context.Context, data, sig []byte) error {
+	return s.verifier.Verify(ctx, data, sig)
+}
+
+// KeyID returns the ID of the key.
+func (s *Signer) KeyID() (string, error) {
+	return s.key.KeyID, nil
+}
+
+// Public returns the key's public key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.verifier.Public()
+}
+
+// PublicKey returns the key's public key as used in gittuf metadata.
+func (s *Signer) PublicKey() *tuf.Key {
+	return s.key
+}
+
+// CryptoSigner returns a crypto.Signer that signs digests using the key
+// management service.
+func (s *Signer) CryptoSigner() crypto.Signer {
+	return &cryptoSigner{signer: s.signer, public: s.Public()}
+}
+
+// cryptoSigner wraps the provider's crypto.Signer, returning the public key
+// that was fetched when the Signer was created.
+type cryptoSigner struct {
+	signer crypto.Signer
+	public crypto.PublicKey
+}
+
+func (c *cryptoSigner) Public() crypto.PublicKey {
+	return c.public
+}
+
+func (c *cryptoSigner) Sign(random io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return c.signer.Sign(random, digest, opts)
+}
+
+// hashForCurve returns the hash securesystemslib uses to verify signatures of
+// ECDSA keys on the curve of the public key.
+func hashForCurve(publicKey *ecdsa.PublicKey) crypto.Hash {
+	switch bitSize := publicKey.Curve.Params().BitSize; {
+	case bitSize <= 256:
+		return crypto.SHA256
+	case bitSize <= 384:
+		return crypto.SHA384
+	default:
+		return crypto.SHA512
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package kms
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/sigstore/sigstore/pkg/signature/kms/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+const testKeyURI = fake.ReferenceScheme + "gittuf"
+
+func TestIsKMSKey(t *testing.T) {
+	assert.True(t, IsKMSKey("awskms:///alias/gittuf"))
+	assert.True(t, IsKMSKey("hashivault://gittuf"))
+	assert.False(t, IsKMSKey("ssh-agent:SHA256:NvYdWkHnZrp9uVtdQpnZBYK7S1pQrM3mZqkPfbOZq8c"))
+	assert.False(t, IsKMSKey("/home/jane/.ssh/id_ecdsa"))
+}
+
+func TestSigner(t *testing.T) {
+	data := []byte("gittuf metadata")
+
+	for name, curve := range map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384()} {
+		t.Run(name, func(t *testing.T) {
+			privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.WithValue(context.Background(), fake.KmsCtxKey{}, privateKey)
+
+			signer, err := NewSigner(ctx, testKeyURI)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signature, err := signer.Sign(ctx, data)
+			assert.Nil(t, err)
+			assert.Nil(t, signer.Verify(ctx, data, signature))
+
+			// Signatures are verified like those of ECDSA keys on disk
+			expectedKey, err := sslibsv.NewKey(&privateKey.PublicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expectedKey, signer.PublicKey())
+			verifier, err := sslibsv.NewVerifierFromSSLibKey(expectedKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Nil(t, verifier.Verify(ctx, data, signature))
+
+			keyID, err := signer.KeyID()
+			assert.Nil(t, err)
+			assert.Equal(t, expectedKey.KeyID, keyID)
+			assert.Equal(t, &privateKey.PublicKey, signer.CryptoSigner().Public())
+		})
+	}
+
+	t.Run("unsupported key type", func(t *testing.T) {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), fake.KmsCtxKey{}, privateKey)
+
+		_, err = NewSigner(ctx, testKeyURI)
+		assert.ErrorIs(t, err, ErrUnsupportedKeyType)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := NewSigner(context.Background(), "examplekms://gittuf")
+		assert.ErrorIs(t, err, ErrUnknownProvider)
+	})
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package signerverifier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"golang.org/x/crypto/ssh"
+)
+
+// KeyLabel returns a human readable label for the key, derived from the key
+// itself. For GPG keys, this is the name and email of the key's primary
+// identity. For Sigstore keys, this is the identity and the issuer, and for
+// S/MIME keys, this is the email address certificates are issued for. For RSA,
+// ECDSA, and ED25519 keys, and SSH keys held in security keys, this is the
+// key's SHA256 fingerprint as displayed by `ssh-keygen -l`. An empty string is
+// returned if no label can be derived.
+func KeyLabel(key *tuf.Key) string {
+	switch key.KeyType {
+	case GPGKeyType:
+		keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.KeyVal.Public))
+		if err != nil || len(keyring) == 0 {
+			return ""
+		}
+
+		identity := keyring[0].PrimaryIdentity()
+		if identity == nil {
+			return ""
+		}
+
+		return identity.Name
+	case FulcioKeyType:
+		if key.KeyVal.Identity == "" {
+			return ""
+		}
+
+		return fmt.Sprintf("%s via %s", key.KeyVal.Identity, key.KeyVal.Issuer)
+	case SMIMEKeyType:
+		return key.KeyVal.Identity
+	case RSAKeyType, ECDSAKeyType, ED25519KeyType:
+		verifier, err := NewSignerVerifierFromTUFKey(key) //nolint:staticcheck
+		if err != nil {
+			return ""
+		}
+
+		publicKey, err := ssh.NewPublicKey(verifier.Public())
+		if err != nil {
+			return ""
+		}
+
+		return ssh.FingerprintSHA256(publicKey)
+	case SSHSecurityKeyType:
+		publicKey, _, err := ParseSSHSecurityKey(key)
+		if err != nil {
+			return ""
+		}
+
+		return ssh.FingerprintSHA256(publicKey)
+	}
+
+	return ""
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package signerverifier
+
+import (
+	"testing"
+
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestKeyLabel(t *testing.T) {
+	rsaKey, err := tuf.LoadKeyFromBytes(artifacts.SSHRSAPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaSSHKey, _, _, _, err := ssh.ParseAuthorizedKey(artifacts.SSHRSAPublicSSH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		key   *tuf.Key
+		label string
+	}{
+		"GPG key": {
+			key: &tuf.Key{
+				KeyType: GPGKeyType,
+				KeyVal:  sslibsv.KeyVal{Public: string(artifacts.GPGKey1Public)},
+			},
+			label: "gittuf Test Key (Test key for gittuf) <gittuf@saky.in>",
+		},
+		"malformed GPG key": {
+			key: &tuf.Key{
+				KeyType: GPGKeyType,
+				KeyVal:  sslibsv.KeyVal{Public: "not a key"},
+			},
+			label: "",
+		},
+		"Sigstore key": {
+			key: &tuf.Key{
+				KeyType: FulcioKeyType,
+				KeyVal:  sslibsv.KeyVal{Identity: "jane.doe@example.com", Issuer: "https://github.com/login/oauth"},
+			},
+			label: "jane.doe@example.com via https://github.com/login/oauth",
+		},
+		"S/MIME key": {
+			key: &tuf.Key{
+				KeyType: SMIMEKeyType,
+				KeyVal:  sslibsv.KeyVal{Identity: "jane.doe@example.com"},
+			},
+			label: "jane.doe@example.com",
+		},
+		"RSA key": {
+			key:   rsaKey,
+			label: ssh.FingerprintSHA256(rsaSSHKey),
+		},
+		"unknown key type": {
+			key:   &tuf.Key{KeyType: "unknown"},
+			label: "",
+		},
+	}
+
+	for name, test := range tests {
+		assert.Equal(t, test.label, KeyLabel(test.key), name)
+	}
+}
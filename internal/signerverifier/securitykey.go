@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package signerverifier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// SSHSecurityKeyType is the type of SSH keys held in FIDO2 security keys,
+	// which can only be used to issue Git signatures.
+	SSHSecurityKeyType = "ssh-sk"
+
+	// SSHSecurityKeyNoTouchRequired is the authorized_keys option that
+	// accepts signatures created without touching the security key.
+	SSHSecurityKeyNoTouchRequired = "no-touch-required"
+
+	// SSHSecurityKeyVerifyRequired is the authorized_keys option that
+	// requires the security key to have verified the user, such as using a
+	// PIN, when creating signatures.
+	SSHSecurityKeyVerifyRequired = "verify-required"
+)
+
+var ErrNotSSHSecurityKey = errors.New("not an SSH key held in a security key")
+
+// IsSSHSecurityKeyAlgorithm returns true if the SSH key algorithm is used for
+// keys held in FIDO2 security keys, such as "sk-ssh-ed25519@openssh.com".
+func IsSSHSecurityKeyAlgorithm(algorithm string) bool {
+	return algorithm == ssh.KeyAlgoSKED25519 || algorithm == ssh.KeyAlgoSKECDSA256
+}
+
+// NewSSHSecurityKey returns the key for an SSH public key held in a FIDO2
+// security key, such as a key created using `ssh-keygen -t ed25519-sk`. The
+// public key is in the authorized_keys format and may be prefixed with the
+// options "no-touch-required" and "verify-required", which are honored when
+// verifying signatures like OpenSSH does. The key's comment is dropped, and
+// its ID is the SHA256 digest of the public key.
+func NewSSHSecurityKey(authorizedKey []byte) (*tuf.Key, error) {
+	publicKey, _, options, _, err := ssh.ParseAuthorizedKey(authorizedKey)
+	if err != nil {
+		return nil, err
+	}
+	if !IsSSHSecurityKeyAlgorithm(publicKey.Type()) {
+		return nil, fmt.Errorf("%w: %s", ErrNotSSHSecurityKey, publicKey.Type())
+	}
+
+	for _, option := range options {
+		if option != SSHSecurityKeyNoTouchRequired && option != SSHSecurityKeyVerifyRequired {
+			return nil, fmt.Errorf("%w: unsupported option '%s'", ErrNotSSHSecurityKey, option)
+		}
+	}
+	slices.Sort(options)
+	options = slices.Compact(options)
+
+	public := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey)))
+	if len(options) > 0 {
+		public = strings.Join(options, ",") + " " + public
+	}
+
+	keyID := sha256.Sum256(publicKey.Marshal())
+
+	return &tuf.Key{
+		KeyID:   hex.EncodeToString(keyID[:]),
+		KeyType: SSHSecurityKeyType,
+		Scheme:  publicKey.Type(),
+		KeyVal:  sslibsv.KeyVal{Public: public},
+	}, nil
+}
+
+// ParseSSHSecurityKey returns the SSH public key and the options of a key
+// returned by NewSSHSecurityKey.
+func ParseSSHSecurityKey(key *tuf.Key) (ssh.PublicKey, []string, error) {
+	if key.KeyType != SSHSecurityKeyType {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotSSHSecurityKey, key.KeyType)
+	}
+
+	publicKey, _, options, _, err := ssh.ParseAuthorizedKey([]byte(key.KeyVal.Public))
+	if err != nil {
+		return nil, nil, errors.Join(ErrNotSSHSecurityKey, err)
+	}
+	if !IsSSHSecurityKeyAlgorithm(publicKey.Type()) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotSSHSecurityKey, publicKey.Type())
+	}
+
+	return publicKey, options, nil
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package signerverifier
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestSSHSecurityKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := ssh.ParsePublicKey(ssh.Marshal(struct {
+		Name        string
+		KeyBytes    []byte
+		Application string
+	}{ssh.KeyAlgoSKED25519, public, "ssh:"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return publicKey
+}
+
+func TestNewSSHSecurityKey(t *testing.T) {
+	publicKey := newTestSSHSecurityKey(t)
+	authorizedKey := ssh.MarshalAuthorizedKey(publicKey)
+
+	t.Run("public key", func(t *testing.T) {
+		key, err := NewSSHSecurityKey(append(authorizedKey[:len(authorizedKey)-1], []byte(" jane@example.com\n")...))
+		assert.Nil(t, err)
+		assert.Equal(t, SSHSecurityKeyType, key.KeyType)
+		assert.Equal(t, ssh.KeyAlgoSKED25519, key.Scheme)
+		assert.Len(t, key.KeyID, 64)
+
+		parsedKey, options, err := ParseSSHSecurityKey(key)
+		assert.Nil(t, err)
+		assert.Equal(t, publicKey.Marshal(), parsedKey.Marshal())
+		assert.Empty(t, options)
+		assert.Equal(t, ssh.FingerprintSHA256(publicKey), KeyLabel(key))
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		key, err := NewSSHSecurityKey(append([]byte("verify-required,no-touch-required "), authorizedKey...))
+		assert.Nil(t, err)
+
+		otherKey, err := NewSSHSecurityKey(authorizedKey)
+		assert.Nil(t, err)
+		assert.Equal(t, otherKey.KeyID, key.KeyID)
+
+		_, options, err := ParseSSHSecurityKey(key)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{SSHSecurityKeyNoTouchRequired, SSHSecurityKeyVerifyRequired}, options)
+	})
+
+	t.Run("unsupported option", func(t *testing.T) {
+		_, err := NewSSHSecurityKey(append([]byte("cert-authority "), authorizedKey...))
+		assert.ErrorIs(t, err, ErrNotSSHSecurityKey)
+	})
+
+	t.Run("not a security key", func(t *testing.T) {
+		_, err := NewSSHSecurityKey(artifacts.SSHED25519PublicSSH)
+		assert.ErrorIs(t, err, ErrNotSSHSecurityKey)
+
+		key, err := tuf.LoadKeyFromBytes(artifacts.SSHED25519Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, err = ParseSSHSecurityKey(key)
+		assert.ErrorIs(t, err, ErrNotSSHSecurityKey)
+	})
+}
@@ -20,6 +20,8 @@ const (
 	GPGKeyType      = "gpg"
 	FulcioKeyType   = "sigstore-oidc"
 	FulcioKeyScheme = "fulcio"
+	SMIMEKeyType    = "smime"
+	SMIMEKeyScheme  = "x509"
 	RekorServer     = "https://rekor.sigstore.dev"
 )
 
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sigstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	sigstoretuf "github.com/sigstore/sigstore/pkg/tuf"
+)
+
+var (
+	ErrTrustedRootNotCached = errors.New("Sigstore trusted root has not been cached") //nolint:stylecheck
+	ErrTrustedRootStale     = errors.New("Sigstore trusted root is stale")            //nolint:stylecheck
+	ErrInvalidFulcioRoots   = errors.New("invalid Fulcio root certificates")          //nolint:stylecheck
+)
+
+// TrustedRootCacheDir returns the directory in which the TUF metadata for
+// Sigstore's trusted root, i.e. Fulcio's and Rekor's keys, is cached.
+func TrustedRootCacheDir() string {
+	if cacheDir := os.Getenv(sigstoretuf.TufRootEnv); cacheDir != "" {
+		return cacheDir
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = ""
+	}
+	return filepath.Join(homeDir, ".sigstore", "root")
+}
+
+// CheckTrustedRoot checks that the cached TUF metadata for Sigstore's trusted
+// root hasn't expired. Expired metadata is refreshed if Sigstore's TUF
+// repository is reachable, so ErrTrustedRootStale indicates that the refresh
+// failed or that the repository itself serves expired metadata.
+func CheckTrustedRoot(ctx context.Context) error {
+	if _, err := os.Stat(TrustedRootCacheDir()); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrTrustedRootNotCached
+		}
+		return err
+	}
+
+	status, err := sigstoretuf.GetRootStatus(ctx)
+	if err != nil {
+		return errors.Join(ErrTrustedRootStale, err)
+	}
+
+	now := time.Now()
+	for role, metadata := range status.Metadata {
+		if metadata.Error != "" {
+			return fmt.Errorf("%w: %s: %s", ErrTrustedRootStale, role, metadata.Error)
+		}
+
+		// The expiry is reported in RFC 822 format
+		expires, err := time.Parse(time.RFC822, metadata.Expiration)
+		if err != nil {
+			return err
+		}
+		if expires.Before(now) {
+			return fmt.Errorf("%w: %s expired on %s", ErrTrustedRootStale, role, metadata.Expiration)
+		}
+	}
+
+	return nil
+}
+
+// InitializeTUF points Sigstore's TUF client at the TUF repository served at
+// the mirror, such as the repository of a private Sigstore deployment, and
+// refreshes the cached trusted root from it. The root.json at rootPath is
+// trusted initially if it's set. Otherwise, the root.json embedded in
+// Sigstore's TUF client is trusted, which is only suitable for mirrors of
+// Sigstore's public TUF repository. Like `cosign initialize`, the mirror is
+// recorded in the cache, so later uses of the cached trusted root also use the
+// mirror.
+func InitializeTUF(ctx context.Context, mirror, rootPath string) error {
+	var root []byte
+	if rootPath != "" {
+		var err error
+		root, err = os.ReadFile(rootPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return sigstoretuf.Initialize(ctx, mirror, root)
+}
+
+// LoadFulcioRoots loads the certificates of a Fulcio instance, such as that of
+// a private Sigstore deployment, from the PEM file at the specified path. The
+// self-signed certificates are returned as the root certificates and the
+// others as the intermediate certificates used to verify signing certificates.
+func LoadFulcioRoots(path string) (*x509.CertPool, *x509.CertPool, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ParseFulcioRoots(contents)
+}
+
+// ParseFulcioRoots parses the PEM encoded certificates of a Fulcio instance,
+// returning the self-signed certificates as the root certificates and the
+// others as the intermediate certificates.
+func ParseFulcioRoots(contents []byte) (*x509.CertPool, *x509.CertPool, error) {
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+	hasRoot := false
+
+	for {
+		var block *pem.Block
+		block, contents = pem.Decode(contents)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidFulcioRoots, err)
+		}
+
+		if bytes.Equal(certificate.RawIssuer, certificate.RawSubject) && certificate.CheckSignatureFrom(certificate) == nil {
+			roots.AddCert(certificate)
+			hasRoot = true
+		} else {
+			intermediates.AddCert(certificate)
+		}
+	}
+
+	if !hasRoot {
+		return nil, nil, fmt.Errorf("%w: no self-signed root certificate found", ErrInvalidFulcioRoots)
+	}
+
+	return roots, intermediates, nil
+}
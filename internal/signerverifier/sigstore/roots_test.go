@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sigstore
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrustedRootCacheDir(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("TUF_ROOT", cacheDir)
+	assert.Equal(t, cacheDir, TrustedRootCacheDir())
+
+	t.Setenv("TUF_ROOT", "")
+	t.Setenv("HOME", cacheDir)
+	assert.Equal(t, filepath.Join(cacheDir, ".sigstore", "root"), TrustedRootCacheDir())
+}
+
+func TestCheckTrustedRoot(t *testing.T) {
+	t.Setenv("TUF_ROOT", filepath.Join(t.TempDir(), "missing"))
+
+	err := CheckTrustedRoot(context.Background())
+	assert.ErrorIs(t, err, ErrTrustedRootNotCached)
+}
+
+func TestParseFulcioRoots(t *testing.T) {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-fulcio-root"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootBytes, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootCert, err := x509.ParseCertificate(rootBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	intermediateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intermediateTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "test-fulcio-intermediate"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	intermediateBytes, err := x509.CreateCertificate(rand.Reader, intermediateTemplate, rootCert, &intermediateKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intermediateCert, err := x509.ParseCertificate(intermediateBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber:   big.NewInt(3),
+		NotBefore:      time.Now().Add(-time.Minute),
+		NotAfter:       time.Now().Add(10 * time.Minute),
+		EmailAddresses: []string{testIdentity},
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+	}
+	leafBytes, err := x509.CreateCertificate(rand.Reader, leafTemplate, intermediateCert, &leafKey.PublicKey, intermediateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafCert, err := x509.ParseCertificate(leafBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootBytes})
+	intermediatePEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: intermediateBytes})
+
+	t.Run("root and intermediate", func(t *testing.T) {
+		roots, intermediates, err := ParseFulcioRoots(append(intermediatePEM, rootPEM...))
+		assert.Nil(t, err)
+
+		_, err = leafCert.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("no root", func(t *testing.T) {
+		_, _, err := ParseFulcioRoots(intermediatePEM)
+		assert.ErrorIs(t, err, ErrInvalidFulcioRoots)
+	})
+
+	t.Run("invalid certificate", func(t *testing.T) {
+		_, _, err := ParseFulcioRoots(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("not a certificate")}))
+		assert.ErrorIs(t, err, ErrInvalidFulcioRoots)
+	})
+}
@@ -0,0 +1,475 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sigstore
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	cms "github.com/github/smimesign/ietf-cms"
+	"github.com/gittuf/gittuf/internal/rekor"
+	"github.com/gittuf/gittuf/internal/signerverifier/common"
+	"github.com/gittuf/gittuf/internal/timing"
+)
+
+const (
+	// FulcioServer is the public good instance of Fulcio.
+	FulcioServer = "https://fulcio.sigstore.dev"
+
+	oidcAudience = "sigstore"
+
+	gitSignaturePEMType = "SIGNED MESSAGE"
+)
+
+var (
+	ErrNoAmbientCredentials     = errors.New("no ambient OIDC credentials found in environment")
+	ErrInvalidIdentityToken     = errors.New("invalid OIDC identity token")
+	ErrUnexpectedFulcioResponse = errors.New("unexpected response from Fulcio")
+	ErrCertificateMissingFields = errors.New("Fulcio certificate does not record signer's identity") //nolint:stylecheck
+)
+
+var (
+	// Fulcio's certificate extensions for the OIDC issuer, see
+	// https://github.com/sigstore/fulcio/blob/main/docs/oid-info.md
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// AmbientToken returns an OIDC identity token for Sigstore using the
+// credentials made available by the CI environment. The token is read from
+// SIGSTORE_ID_TOKEN if set (e.g., using GitLab CI's `id_tokens`), requested
+// from GitHub Actions if the workflow has the `id-token: write` permission, or
+// requested from the Buildkite agent.
+func AmbientToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("SIGSTORE_ID_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	return RequestAmbientToken(ctx, oidcAudience)
+}
+
+// RequestAmbientToken requests an OIDC identity token for the specified
+// audience from the CI environment, from GitHub Actions if the workflow has the
+// `id-token: write` permission, or from the Buildkite agent.
+func RequestAmbientToken(ctx context.Context, audience string) (string, error) {
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if requestURL != "" && requestToken != "" {
+		return requestGitHubActionsToken(ctx, requestURL, requestToken, audience)
+	}
+
+	if os.Getenv("BUILDKITE") == "true" {
+		output, err := exec.CommandContext(ctx, "buildkite-agent", "oidc", "request-token", "--audience", audience).Output()
+		if err != nil {
+			return "", errors.Join(ErrNoAmbientCredentials, err)
+		}
+
+		return strings.TrimSpace(string(output)), nil
+	}
+
+	return "", ErrNoAmbientCredentials
+}
+
+// HasAmbientCredentials returns true if the CI environment makes credentials
+// available that AmbientToken can use to obtain an OIDC identity token,
+// without requesting the token.
+func HasAmbientCredentials() bool {
+	if os.Getenv("SIGSTORE_ID_TOKEN") != "" {
+		return true
+	}
+
+	if os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL") != "" && os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN") != "" {
+		return true
+	}
+
+	return os.Getenv("BUILDKITE") == "true"
+}
+
+// Signer signs using an ephemeral key whose certificate is issued by Fulcio
+// for the identity in an OIDC token. The key ID of the signer is of the form
+// `<identity>::<issuer>`, matching how Sigstore identities are recorded in
+// gittuf policy.
+type Signer struct {
+	privateKey       *ecdsa.PrivateKey
+	certificateChain []byte
+	identity         string
+	issuer           string
+}
+
+// NewSigner creates an ephemeral key and requests a certificate for it from
+// the Fulcio instance using the OIDC identity token.
+func NewSigner(ctx context.Context, fulcioURL, token string) (*Signer, error) {
+	subject, err := subjectFromToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})
+
+	// Fulcio requires proof that we hold the private key for the certificate
+	subjectDigest := sha256.Sum256([]byte(subject))
+	proofOfPossession, err := ecdsa.SignASN1(rand.Reader, privateKey, subjectDigest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	certificateChain, err := requestCertificate(ctx, fulcioURL, token, publicKeyPEM, proofOfPossession)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, issuer, err := identityFromCertificateChain(certificateChain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{
+		privateKey:       privateKey,
+		certificateChain: certificateChain,
+		identity:         identity,
+		issuer:           issuer,
+	}, nil
+}
+
+// NewAmbientSigner creates a Signer for the CI environment's OIDC identity,
+// obtaining the identity token using AmbientToken. This allows signing in CI
+// without a key or an interactive login.
+func NewAmbientSigner(ctx context.Context, fulcioURL string) (*Signer, error) {
+	token, err := AmbientToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSigner(ctx, fulcioURL, token)
+}
+
+// Sign signs the data using the ephemeral key.
+func (s *Signer) Sign(_ context.Context, data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+	return ecdsa.SignASN1(rand.Reader, s.privateKey, digest[:])
+}
+
+// Verify verifies the signature using the ephemeral key.
+func (s *Signer) Verify(_ context.Context, data, sig []byte) error {
+	digest := sha256.Sum256(data)
+	if !ecdsa.VerifyASN1(&s.privateKey.PublicKey, digest[:], sig) {
+		return common.ErrSignatureVerificationFailed
+	}
+
+	return nil
+}
+
+// KeyID returns the signer's identity and issuer as `<identity>::<issuer>`.
+func (s *Signer) KeyID() (string, error) {
+	return fmt.Sprintf("%s::%s", s.identity, s.issuer), nil
+}
+
+// Public returns the ephemeral public key.
+func (s *Signer) Public() crypto.PublicKey {
+	return &s.privateKey.PublicKey
+}
+
+// Identity returns the signer's identity as recorded by Fulcio.
+func (s *Signer) Identity() string {
+	return s.identity
+}
+
+// Issuer returns the OIDC issuer of the signer's identity.
+func (s *Signer) Issuer() string {
+	return s.issuer
+}
+
+// CertificateChain returns the PEM encoded certificate chain issued by Fulcio
+// for the ephemeral key, starting with the leaf certificate.
+func (s *Signer) CertificateChain() []byte {
+	return s.certificateChain
+}
+
+// SignGitObject signs the contents of a Git commit or tag, which exclude the
+// signature, and returns the armored signature. Like signatures created by
+// gitsign, the signature is a detached CMS signature that embeds the
+// certificate issued by Fulcio.
+func (s *Signer) SignGitObject(contents []byte) ([]byte, error) {
+	certificate, _, err := s.leafCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := cms.SignDetached(contents, []*x509.Certificate{certificate}, s.privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: gitSignaturePEMType, Bytes: signature}), nil
+}
+
+// LogGitObject logs the signer's signature over the contents of a Git commit or
+// tag to the Rekor instance, so that the use of the signer's identity is
+// publicly recorded. Rekor verifies the signature using the certificate issued
+// by Fulcio, and the returned log entry is verified using Rekor's public key.
+func (s *Signer) LogGitObject(ctx context.Context, client *rekor.Client, contents []byte) (*rekor.LogEntry, error) {
+	_, certificatePEM, err := s.leafCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.Sign(ctx, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(contents)
+	entry, err := client.UploadHashedRekord(ctx, digest[:], signature, certificatePEM)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := client.PublicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := entry.VerifyForDigest(digest[:], publicKey); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// leafCertificate returns the certificate issued by Fulcio for the ephemeral
+// key, both parsed and PEM encoded.
+func (s *Signer) leafCertificate() (*x509.Certificate, []byte, error) {
+	block, _ := pem.Decode(s.certificateChain)
+	if block == nil {
+		return nil, nil, fmt.Errorf("%w: invalid certificate", ErrUnexpectedFulcioResponse)
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, errors.Join(ErrUnexpectedFulcioResponse, err)
+	}
+
+	return certificate, pem.EncodeToMemory(block), nil
+}
+
+func requestGitHubActionsToken(ctx context.Context, requestURL, requestToken, audience string) (string, error) {
+	defer timing.Start(timing.PhaseSigstore)()
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("audience", audience)
+	u.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", requestToken))
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: GitHub Actions returned status %d", ErrNoAmbientCredentials, response.StatusCode)
+	}
+
+	tokenResponse := struct {
+		Value string `json:"value"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&tokenResponse); err != nil {
+		return "", err
+	}
+
+	return tokenResponse.Value, nil
+}
+
+// subjectFromToken returns the subject Fulcio expects the proof of possession
+// to be computed over, which is the email for tokens that have one and the
+// subject otherwise. The token's signature is verified by Fulcio.
+func subjectFromToken(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidIdentityToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.Join(ErrInvalidIdentityToken, err)
+	}
+
+	claims := struct {
+		Subject string `json:"sub"`
+		Email   string `json:"email"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", errors.Join(ErrInvalidIdentityToken, err)
+	}
+
+	if claims.Email != "" {
+		return claims.Email, nil
+	}
+	if claims.Subject == "" {
+		return "", ErrInvalidIdentityToken
+	}
+
+	return claims.Subject, nil
+}
+
+func requestCertificate(ctx context.Context, fulcioURL, token string, publicKeyPEM, proofOfPossession []byte) ([]byte, error) {
+	defer timing.Start(timing.PhaseSigstore)()
+
+	type publicKey struct {
+		Algorithm string `json:"algorithm"`
+		Content   string `json:"content"`
+	}
+	type publicKeyRequest struct {
+		PublicKey         publicKey `json:"publicKey"`
+		ProofOfPossession []byte    `json:"proofOfPossession"`
+	}
+	type credentials struct {
+		OIDCIdentityToken string `json:"oidcIdentityToken"`
+	}
+	requestBody, err := json.Marshal(struct {
+		Credentials      credentials      `json:"credentials"`
+		PublicKeyRequest publicKeyRequest `json:"publicKeyRequest"`
+	}{
+		Credentials: credentials{OIDCIdentityToken: token},
+		PublicKeyRequest: publicKeyRequest{
+			PublicKey:         publicKey{Algorithm: "ECDSA", Content: string(publicKeyPEM)},
+			ProofOfPossession: proofOfPossession,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(fulcioURL, "/")+"/api/v2/signingCert", bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("%w: status %d: %s", ErrUnexpectedFulcioResponse, response.StatusCode, strings.TrimSpace(string(responseBody)))
+	}
+
+	type chain struct {
+		Certificates []string `json:"certificates"`
+	}
+	type signedCertificate struct {
+		Chain chain `json:"chain"`
+	}
+	certificateResponse := struct {
+		EmbeddedSCT *signedCertificate `json:"signedCertificateEmbeddedSct"`
+		DetachedSCT *signedCertificate `json:"signedCertificateDetachedSct"`
+	}{}
+	if err := json.Unmarshal(responseBody, &certificateResponse); err != nil {
+		return nil, errors.Join(ErrUnexpectedFulcioResponse, err)
+	}
+
+	var certificates []string
+	switch {
+	case certificateResponse.EmbeddedSCT != nil:
+		certificates = certificateResponse.EmbeddedSCT.Chain.Certificates
+	case certificateResponse.DetachedSCT != nil:
+		certificates = certificateResponse.DetachedSCT.Chain.Certificates
+	}
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("%w: no certificates returned", ErrUnexpectedFulcioResponse)
+	}
+
+	certificateChain := []byte{}
+	for _, certificate := range certificates {
+		certificateChain = append(certificateChain, []byte(strings.TrimSpace(certificate)+"\n")...)
+	}
+
+	return certificateChain, nil
+}
+
+// identityFromCertificateChain returns the identity and issuer recorded in the
+// leaf certificate of the chain issued by Fulcio.
+func identityFromCertificateChain(certificateChain []byte) (string, string, error) {
+	block, _ := pem.Decode(certificateChain)
+	if block == nil {
+		return "", "", fmt.Errorf("%w: invalid certificate", ErrUnexpectedFulcioResponse)
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", "", errors.Join(ErrUnexpectedFulcioResponse, err)
+	}
+
+	var identity string
+	switch {
+	case len(certificate.EmailAddresses) > 0:
+		identity = certificate.EmailAddresses[0]
+	case len(certificate.URIs) > 0:
+		identity = certificate.URIs[0].String()
+	default:
+		return "", "", ErrCertificateMissingFields
+	}
+
+	var issuer string
+	for _, extension := range certificate.Extensions {
+		switch {
+		case extension.Id.Equal(oidIssuerV2):
+			if _, err := asn1.Unmarshal(extension.Value, &issuer); err != nil {
+				return "", "", errors.Join(ErrUnexpectedFulcioResponse, err)
+			}
+		case extension.Id.Equal(oidIssuerV1):
+			if issuer == "" {
+				issuer = string(extension.Value)
+			}
+		}
+	}
+	if issuer == "" {
+		return "", "", ErrCertificateMissingFields
+	}
+
+	return identity, issuer, nil
+}
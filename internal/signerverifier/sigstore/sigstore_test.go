@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sigstore
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cms "github.com/github/smimesign/ietf-cms"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testIdentity = "jane.doe@example.com"
+	testIssuer   = "https://oauth2.sigstore.dev/auth"
+)
+
+func TestAmbientToken(t *testing.T) {
+	t.Setenv("SIGSTORE_ID_TOKEN", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+	t.Setenv("BUILDKITE", "")
+
+	t.Run("no credentials", func(t *testing.T) {
+		_, err := AmbientToken(context.Background())
+		assert.ErrorIs(t, err, ErrNoAmbientCredentials)
+	})
+
+	t.Run("GitHub Actions", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer request-token" || r.URL.Query().Get("audience") != oidcAudience {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Write([]byte(`{"value":"github-token"}`)) //nolint:errcheck
+		}))
+		defer server.Close()
+
+		t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", server.URL+"/token?api-version=2.0")
+		t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+
+		token, err := AmbientToken(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, "github-token", token)
+	})
+
+	t.Run("explicit token", func(t *testing.T) {
+		t.Setenv("SIGSTORE_ID_TOKEN", "explicit-token")
+
+		token, err := AmbientToken(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, "explicit-token", token)
+	})
+}
+
+func TestNewSigner(t *testing.T) {
+	server := newTestFulcio(t)
+	defer server.Close()
+
+	t.Run("valid token", func(t *testing.T) {
+		signer, err := NewSigner(context.Background(), server.URL, createTestToken(t, testIdentity))
+		assert.Nil(t, err)
+
+		keyID, err := signer.KeyID()
+		assert.Nil(t, err)
+		assert.Equal(t, testIdentity+"::"+testIssuer, keyID)
+		assert.Equal(t, testIdentity, signer.Identity())
+		assert.Equal(t, testIssuer, signer.Issuer())
+
+		block, _ := pem.Decode(signer.CertificateChain())
+		assert.NotNil(t, block)
+
+		sig, err := signer.Sign(context.Background(), []byte("test data"))
+		assert.Nil(t, err)
+		assert.Nil(t, signer.Verify(context.Background(), []byte("test data"), sig))
+		assert.NotNil(t, signer.Verify(context.Background(), []byte("other data"), sig))
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := NewSigner(context.Background(), server.URL, "not-a-token")
+		assert.ErrorIs(t, err, ErrInvalidIdentityToken)
+	})
+
+	t.Run("rejected token", func(t *testing.T) {
+		_, err := NewSigner(context.Background(), server.URL, createTestToken(t, "unknown@example.com"))
+		assert.ErrorIs(t, err, ErrUnexpectedFulcioResponse)
+	})
+}
+
+func TestSignerSignGitObject(t *testing.T) {
+	server := newTestFulcio(t)
+	defer server.Close()
+
+	signer, err := NewSigner(context.Background(), server.URL, createTestToken(t, testIdentity))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second certificate in the chain is the test Fulcio's root
+	_, rest := pem.Decode(signer.CertificateChain())
+	rootBlock, _ := pem.Decode(rest)
+	root, err := x509.ParseCertificate(rootBlock.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	contents := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nInitial commit\n")
+	signature, err := signer.SignGitObject(contents)
+	assert.Nil(t, err)
+
+	block, _ := pem.Decode(signature)
+	if !assert.NotNil(t, block) {
+		return
+	}
+	assert.Equal(t, gitSignaturePEMType, block.Type)
+
+	signedData, err := cms.ParseSignedData(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}
+
+	chains, err := signedData.VerifyDetached(contents, opts)
+	assert.Nil(t, err)
+	if assert.Len(t, chains, 1) {
+		assert.Equal(t, []string{testIdentity}, chains[0][0][0].EmailAddresses)
+	}
+
+	_, err = signedData.VerifyDetached([]byte("modified contents"), opts)
+	assert.NotNil(t, err)
+}
+
+// newTestFulcio returns a server that issues certificates for testIdentity
+// after checking the proof of possession, in the manner of Fulcio.
+func newTestFulcio(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-fulcio"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caBytes, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuerExtension, err := asn1.Marshal(testIssuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := struct {
+			Credentials struct {
+				OIDCIdentityToken string `json:"oidcIdentityToken"`
+			} `json:"credentials"`
+			PublicKeyRequest struct {
+				PublicKey struct {
+					Content string `json:"content"`
+				} `json:"publicKey"`
+				ProofOfPossession []byte `json:"proofOfPossession"`
+			} `json:"publicKeyRequest"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		subject, err := subjectFromToken(request.Credentials.OIDCIdentityToken)
+		if err != nil || subject != testIdentity {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		block, _ := pem.Decode([]byte(request.PublicKeyRequest.PublicKey.Content))
+		if block == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		signer := &Signer{privateKey: &ecdsa.PrivateKey{PublicKey: *publicKey.(*ecdsa.PublicKey)}}
+		if err := signer.Verify(r.Context(), []byte(subject), request.PublicKeyRequest.ProofOfPossession); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		leafTemplate := &x509.Certificate{
+			SerialNumber:    big.NewInt(2),
+			NotBefore:       time.Now().Add(-time.Minute),
+			NotAfter:        time.Now().Add(10 * time.Minute),
+			EmailAddresses:  []string{subject},
+			KeyUsage:        x509.KeyUsageDigitalSignature,
+			ExtraExtensions: []pkix.Extension{{Id: oidIssuerV2, Value: issuerExtension}},
+		}
+		leafBytes, err := x509.CreateCertificate(rand.Reader, leafTemplate, caCert, publicKey, caKey)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		response := map[string]any{
+			"signedCertificateEmbeddedSct": map[string]any{
+				"chain": map[string]any{
+					"certificates": []string{
+						string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafBytes})),
+						string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caBytes})),
+					},
+				},
+			},
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(response) //nolint:errcheck
+	}))
+}
+
+// createTestToken returns an unsigned JWT for the email address. The token's
+// signature is only checked by Fulcio.
+func createTestToken(t *testing.T, email string) string {
+	t.Helper()
+
+	claims, err := json.Marshal(map[string]string{"iss": testIssuer, "sub": "1234", "email": email})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	return header + "." + base64.RawURLEncoding.EncodeToString(claims) + ".sig"
+}
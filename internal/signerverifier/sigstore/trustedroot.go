@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sigstore
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TrustedRootFileName is the name of the TUF target that contains Sigstore's
+// trusted root.
+const TrustedRootFileName = "trusted_root.json"
+
+const trustedRootMediaType = "application/vnd.dev.sigstore.trustedroot+json"
+
+var ErrInvalidTrustedRoot = errors.New("invalid Sigstore trusted root")
+
+// TrustedRoot contains the certificate authorities and transparency logs
+// trusted to verify Sigstore signatures. It is parsed from the trusted root
+// format defined by Sigstore's protobuf specification, as distributed using
+// Sigstore's TUF repository, so that signatures can be verified without
+// contacting Sigstore's services.
+type TrustedRoot struct {
+	certificateAuthorities []*CertificateAuthority
+	ctLogs                 []*TransparencyLog
+	tlogs                  []*TransparencyLog
+}
+
+// CertificateAuthority is a Fulcio instance trusted to issue signing
+// certificates.
+type CertificateAuthority struct {
+	URI string
+
+	// Root is the self-signed root of the certificate chain, and
+	// Intermediates are the other certificates in the chain.
+	Root          *x509.Certificate
+	Intermediates []*x509.Certificate
+
+	// ValidFrom and ValidUntil bound the period in which the authority
+	// issued certificates. ValidUntil is zero if the authority is active.
+	ValidFrom  time.Time
+	ValidUntil time.Time
+}
+
+// TransparencyLog is a certificate transparency log or a Rekor instance trusted
+// to record signatures.
+type TransparencyLog struct {
+	BaseURL string
+
+	// LogID is the hex encoded SHA-256 digest of the log's DER encoded
+	// public key.
+	LogID string
+
+	PublicKey crypto.PublicKey
+
+	// PublicKeyPEM is the log's PEM encoded public key.
+	PublicKeyPEM []byte
+
+	// ValidFrom and ValidUntil bound the period in which the log's key was
+	// used. ValidUntil is zero if the key is active.
+	ValidFrom  time.Time
+	ValidUntil time.Time
+}
+
+// Expired indicates if the log's key is no longer used to sign entries.
+func (l *TransparencyLog) Expired(now time.Time) bool {
+	return !l.ValidUntil.IsZero() && l.ValidUntil.Before(now)
+}
+
+type trustedRootDocument struct {
+	MediaType              string `json:"mediaType"`
+	CertificateAuthorities []struct {
+		URI       string `json:"uri"`
+		CertChain struct {
+			Certificates []struct {
+				RawBytes []byte `json:"rawBytes"`
+			} `json:"certificates"`
+		} `json:"certChain"`
+		ValidFor validityPeriod `json:"validFor"`
+	} `json:"certificateAuthorities"`
+	CTLogs []transparencyLogDocument `json:"ctlogs"`
+	TLogs  []transparencyLogDocument `json:"tlogs"`
+}
+
+type transparencyLogDocument struct {
+	BaseURL   string `json:"baseUrl"`
+	PublicKey struct {
+		RawBytes []byte         `json:"rawBytes"`
+		ValidFor validityPeriod `json:"validFor"`
+	} `json:"publicKey"`
+}
+
+type validityPeriod struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// CachedTrustedRootPath returns the location of Sigstore's trusted root in the
+// cache of Sigstore's TUF client. The trusted root is cached along with the
+// other TUF targets when the cache is refreshed, such as when Sigstore
+// signatures are verified online.
+func CachedTrustedRootPath() string {
+	return filepath.Join(TrustedRootCacheDir(), "targets", TrustedRootFileName)
+}
+
+// LoadTrustedRoot loads Sigstore's trusted root from the specified file. If no
+// file is specified, the trusted root cached by Sigstore's TUF client is used.
+func LoadTrustedRoot(path string) (*TrustedRoot, error) {
+	if path == "" {
+		path = CachedTrustedRootPath()
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: '%s' not found", ErrTrustedRootNotCached, path)
+		}
+		return nil, err
+	}
+
+	return ParseTrustedRoot(contents)
+}
+
+// ParseTrustedRoot parses Sigstore's trusted root from its JSON encoding.
+func ParseTrustedRoot(contents []byte) (*TrustedRoot, error) {
+	document := &trustedRootDocument{}
+	if err := json.Unmarshal(contents, document); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTrustedRoot, err)
+	}
+	if !strings.HasPrefix(document.MediaType, trustedRootMediaType) {
+		return nil, fmt.Errorf("%w: unexpected media type '%s'", ErrInvalidTrustedRoot, document.MediaType)
+	}
+
+	trustedRoot := &TrustedRoot{}
+	for _, authority := range document.CertificateAuthorities {
+		certificates := authority.CertChain.Certificates
+		if len(certificates) == 0 {
+			return nil, fmt.Errorf("%w: certificate authority '%s' has no certificates", ErrInvalidTrustedRoot, authority.URI)
+		}
+
+		// The chain is ordered from the issuing certificate to the root
+		chain := make([]*x509.Certificate, 0, len(certificates))
+		for _, certificate := range certificates {
+			parsedCertificate, err := x509.ParseCertificate(certificate.RawBytes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: certificate authority '%s': %w", ErrInvalidTrustedRoot, authority.URI, err)
+			}
+			chain = append(chain, parsedCertificate)
+		}
+
+		trustedRoot.certificateAuthorities = append(trustedRoot.certificateAuthorities, &CertificateAuthority{
+			URI:           authority.URI,
+			Root:          chain[len(chain)-1],
+			Intermediates: chain[:len(chain)-1],
+			ValidFrom:     authority.ValidFor.Start,
+			ValidUntil:    authority.ValidFor.End,
+		})
+	}
+
+	var err error
+	trustedRoot.ctLogs, err = parseTransparencyLogs(document.CTLogs)
+	if err != nil {
+		return nil, err
+	}
+	trustedRoot.tlogs, err = parseTransparencyLogs(document.TLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(trustedRoot.certificateAuthorities) == 0 {
+		return nil, fmt.Errorf("%w: no certificate authorities", ErrInvalidTrustedRoot)
+	}
+
+	return trustedRoot, nil
+}
+
+// CertificateAuthorities returns the Fulcio instances in the trusted root.
+func (t *TrustedRoot) CertificateAuthorities() []*CertificateAuthority {
+	return t.certificateAuthorities
+}
+
+// CertificatePools returns the pools of root and intermediate certificates of
+// the trusted certificate authorities, used to verify signing certificates.
+func (t *TrustedRoot) CertificatePools() (*x509.CertPool, *x509.CertPool) {
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+	for _, authority := range t.certificateAuthorities {
+		roots.AddCert(authority.Root)
+		for _, intermediate := range authority.Intermediates {
+			intermediates.AddCert(intermediate)
+		}
+	}
+
+	return roots, intermediates
+}
+
+// CTLogs returns the certificate transparency logs in the trusted root, used
+// to verify the signed certificate timestamps embedded in signing
+// certificates.
+func (t *TrustedRoot) CTLogs() []*TransparencyLog {
+	return t.ctLogs
+}
+
+// TLogs returns the Rekor instances in the trusted root.
+func (t *TrustedRoot) TLogs() []*TransparencyLog {
+	return t.tlogs
+}
+
+func parseTransparencyLogs(documents []transparencyLogDocument) ([]*TransparencyLog, error) {
+	logs := make([]*TransparencyLog, 0, len(documents))
+	for _, document := range documents {
+		publicKey, err := x509.ParsePKIXPublicKey(document.PublicKey.RawBytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: transparency log '%s': %w", ErrInvalidTrustedRoot, document.BaseURL, err)
+		}
+
+		logID := sha256.Sum256(document.PublicKey.RawBytes)
+		logs = append(logs, &TransparencyLog{
+			BaseURL:      document.BaseURL,
+			LogID:        hex.EncodeToString(logID[:]),
+			PublicKey:    publicKey,
+			PublicKeyPEM: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: document.PublicKey.RawBytes}),
+			ValidFrom:    document.PublicKey.ValidFor.Start,
+			ValidUntil:   document.PublicKey.ValidFor.End,
+		})
+	}
+
+	return logs, nil
+}
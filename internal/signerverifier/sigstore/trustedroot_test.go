@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sigstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrustedRoot(t *testing.T) {
+	trustedRoot, err := ParseTrustedRoot(artifacts.SigstoreTrustedRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authorities := trustedRoot.CertificateAuthorities()
+	assert.Len(t, authorities, 2)
+	assert.Equal(t, "https://fulcio.sigstore.dev", authorities[1].URI)
+	assert.Equal(t, "sigstore", authorities[1].Root.Subject.CommonName)
+	assert.Len(t, authorities[1].Intermediates, 1)
+	assert.True(t, authorities[1].ValidUntil.IsZero())
+
+	roots, intermediates := trustedRoot.CertificatePools()
+	assert.True(t, authorities[1].Intermediates[0].CheckSignatureFrom(authorities[1].Root) == nil)
+	assert.NotNil(t, roots)
+	assert.NotNil(t, intermediates)
+
+	ctLogs := trustedRoot.CTLogs()
+	assert.Len(t, ctLogs, 2)
+	assert.Equal(t, "https://ctfe.sigstore.dev/2022", ctLogs[1].BaseURL)
+	// The log ID matches the key ID in the trusted root
+	assert.Equal(t, "dd3d306ac6c7113263191e1c99673702a24a5eb8de3cadff878a72802f29ee8e", ctLogs[1].LogID)
+	assert.True(t, ctLogs[0].Expired(time.Now()))
+	assert.False(t, ctLogs[1].Expired(time.Now()))
+	assert.Contains(t, string(ctLogs[1].PublicKeyPEM), "-----BEGIN PUBLIC KEY-----")
+
+	tlogs := trustedRoot.TLogs()
+	assert.Len(t, tlogs, 1)
+	assert.Equal(t, "https://rekor.sigstore.dev", tlogs[0].BaseURL)
+
+	_, err = ParseTrustedRoot([]byte(`{"mediaType": "application/json"}`))
+	assert.ErrorIs(t, err, ErrInvalidTrustedRoot)
+
+	_, err = ParseTrustedRoot([]byte(`{"mediaType": "application/vnd.dev.sigstore.trustedroot+json;version=0.1"}`))
+	assert.ErrorIs(t, err, ErrInvalidTrustedRoot)
+}
+
+func TestLoadTrustedRoot(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("TUF_ROOT", cacheDir)
+
+	_, err := LoadTrustedRoot("")
+	assert.ErrorIs(t, err, ErrTrustedRootNotCached)
+
+	if err := os.MkdirAll(filepath.Join(cacheDir, "targets"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(CachedTrustedRootPath(), artifacts.SigstoreTrustedRoot, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	trustedRoot, err := LoadTrustedRoot("")
+	assert.Nil(t, err)
+	assert.Len(t, trustedRoot.CTLogs(), 2)
+
+	trustedRootPath := filepath.Join(t.TempDir(), TrustedRootFileName)
+	if err := os.WriteFile(trustedRootPath, artifacts.SigstoreTrustedRoot, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	trustedRoot, err = LoadTrustedRoot(trustedRootPath)
+	assert.Nil(t, err)
+	assert.Len(t, trustedRoot.TLogs(), 1)
+}
@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package smime verifies S/MIME signatures of Git objects, as created by gpgsm
+// or smimesign when Git's gpg.format is set to x509. Signatures are detached
+// CMS signatures that embed the signer's certificate, which must chain to one
+// of the roots in a trust store of certificate authorities.
+package smime
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cms "github.com/github/smimesign/ietf-cms"
+	"github.com/github/smimesign/ietf-cms/protocol"
+)
+
+var (
+	ErrInvalidTrustStore = errors.New("invalid S/MIME trust store")
+	ErrInvalidSignature  = errors.New("invalid S/MIME signature")
+)
+
+// emailAddressOID identifies the emailAddress attribute in the subject of
+// certificates that predate the use of subject alternative names.
+var emailAddressOID = []int{1, 2, 840, 113549, 1, 9, 1}
+
+// LoadTrustStore returns the root certificates in the PEM file at the path, or
+// in the PEM files in the directory at the path, such as a directory prepared
+// for OpenSSL using c_rehash. Files in the directory that contain no
+// certificates are skipped. The system's roots are never used, as they include
+// every public certificate authority, any of which could issue a certificate
+// for a signer's email address.
+func LoadTrustStore(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: no path specified", ErrInvalidTrustStore)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTrustStore, err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTrustStore, err)
+		}
+
+		files = files[:0]
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	roots := x509.NewCertPool()
+	found := false
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTrustStore, err)
+		}
+
+		if roots.AppendCertsFromPEM(contents) {
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: no PEM encoded certificates found in '%s'", ErrInvalidTrustStore, path)
+	}
+
+	return roots, nil
+}
+
+// Verify verifies the detached S/MIME signature of the contents, which is
+// either PEM or DER encoded, and returns the signer's certificate. The
+// certificate must be valid for email protection and chain to one of the
+// roots. Its validity is checked at the specified time, which must be trusted,
+// such as the time of a verified timestamp of the signature, or at the current
+// time if it's zero. The signing time recorded in the signature is set by the
+// signer, so it's not used. Revocation is not checked.
+func Verify(contents, signature []byte, roots *x509.CertPool, at time.Time) (*x509.Certificate, error) {
+	der, _, err := parseSignedData(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	// The current time is set explicitly, as timestamps embedded in the
+	// signature would otherwise be used, which are issued by authorities
+	// that aren't trusted for timestamps
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:       roots,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection},
+		CurrentTime: at,
+	}
+
+	parsedSignature, err := cms.ParseSignedData(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	chains, err := parsedSignature.VerifyDetached(contents, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if len(chains) == 0 || len(chains[0]) == 0 || len(chains[0][0]) == 0 {
+		return nil, fmt.Errorf("%w: no verified certificate chain", ErrInvalidSignature)
+	}
+
+	return chains[0][0][0], nil
+}
+
+// Inspect returns the signer's certificate and the digest algorithm of the
+// S/MIME signature, which is either PEM or DER encoded. The signature is not
+// verified, so the results must only be relied on for signatures that were
+// verified using Verify.
+func Inspect(signature []byte) (*x509.Certificate, crypto.Hash, error) {
+	_, signedData, err := parseSignedData(signature)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	certs, err := signedData.X509Certificates()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	cert, err := signedData.SignerInfos[0].FindCertificate(certs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	hash, err := signedData.SignerInfos[0].Hash()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	return cert, hash, nil
+}
+
+// parseSignedData decodes the S/MIME signature, which is either PEM or DER
+// encoded, and returns the DER encoded signature and its signed data, which
+// must have exactly one signer.
+func parseSignedData(signature []byte) ([]byte, *protocol.SignedData, error) {
+	der := signature
+	if block, _ := pem.Decode(signature); block != nil {
+		der = block.Bytes
+	}
+
+	contentInfo, err := protocol.ParseContentInfo(der)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	signedData, err := contentInfo.SignedDataContent()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if len(signedData.SignerInfos) != 1 {
+		return nil, nil, fmt.Errorf("%w: expected one signer, found %d", ErrInvalidSignature, len(signedData.SignerInfos))
+	}
+
+	return der, signedData, nil
+}
+
+// HasIdentity returns true if the certificate was issued for the email
+// address, which is compared case insensitively. The certificate's subject
+// alternative names and the emailAddress attribute of its subject are checked.
+func HasIdentity(cert *x509.Certificate, identity string) bool {
+	for _, email := range Identities(cert) {
+		if strings.EqualFold(email, identity) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Identities returns the email addresses the certificate was issued for.
+func Identities(cert *x509.Certificate) []string {
+	identities := append([]string{}, cert.EmailAddresses...)
+	for _, name := range cert.Subject.Names {
+		if !name.Type.Equal(emailAddressOID) {
+			continue
+		}
+		if email, ok := name.Value.(string); ok {
+			identities = append(identities, email)
+		}
+	}
+
+	return identities
+}
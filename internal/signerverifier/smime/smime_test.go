@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package smime
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cms "github.com/github/smimesign/ietf-cms"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCertificate struct {
+	cert       *x509.Certificate
+	privateKey *ecdsa.PrivateKey
+}
+
+func newTestCertificate(t *testing.T, template *x509.Certificate, issuer *testCertificate) *testCertificate {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	if template.NotAfter.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Hour)
+		template.NotAfter = time.Now().Add(time.Hour)
+	}
+
+	parent, signer := template, privateKey
+	if issuer != nil {
+		parent, signer = issuer.cert, issuer.privateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &privateKey.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{cert: cert, privateKey: privateKey}
+}
+
+func newTestCA(t *testing.T, name string) *testCertificate {
+	t.Helper()
+
+	return newTestCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+}
+
+func sign(t *testing.T, contents []byte, signer *testCertificate) []byte {
+	t.Helper()
+
+	signature, err := cms.SignDetached(contents, []*x509.Certificate{signer.cert}, signer.privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signature
+}
+
+func TestVerify(t *testing.T) {
+	ca := newTestCA(t, "Example CA")
+	otherCA := newTestCA(t, "Other CA")
+
+	signer := newTestCertificate(t, &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "Jane Doe"},
+		EmailAddresses: []string{"jane.doe@example.com"},
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection},
+	}, ca)
+	codeSigner := newTestCertificate(t, &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "John Doe"},
+		EmailAddresses: []string{"john.doe@example.com"},
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}, ca)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherCA.cert)
+
+	contents := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nInitial commit\n")
+	signature := sign(t, contents, signer)
+	pemSignature := pem.EncodeToMemory(&pem.Block{Type: "SIGNED MESSAGE", Bytes: signature})
+
+	t.Run("PEM encoded signature", func(t *testing.T) {
+		cert, err := Verify(contents, pemSignature, roots, time.Time{})
+		assert.Nil(t, err)
+		assert.True(t, HasIdentity(cert, "Jane.Doe@example.com"))
+		assert.False(t, HasIdentity(cert, "john.doe@example.com"))
+	})
+
+	t.Run("DER encoded signature", func(t *testing.T) {
+		cert, err := Verify(contents, signature, roots, time.Time{})
+		assert.Nil(t, err)
+		assert.Equal(t, signer.cert.Raw, cert.Raw)
+	})
+
+	t.Run("modified contents", func(t *testing.T) {
+		_, err := Verify([]byte("modified"), pemSignature, roots, time.Time{})
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("untrusted certificate authority", func(t *testing.T) {
+		_, err := Verify(contents, pemSignature, otherRoots, time.Time{})
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("certificate not valid for email protection", func(t *testing.T) {
+		_, err := Verify(contents, sign(t, contents, codeSigner), roots, time.Time{})
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("expired certificate", func(t *testing.T) {
+		expiredAt := time.Now().Add(-time.Minute)
+		expiredSigner := newTestCertificate(t, &x509.Certificate{
+			Subject:        pkix.Name{CommonName: "Jane Doe"},
+			EmailAddresses: []string{"jane.doe@example.com"},
+			KeyUsage:       x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection},
+			NotBefore:      expiredAt.Add(-time.Hour),
+			NotAfter:       expiredAt,
+		}, ca)
+
+		signature := sign(t, contents, expiredSigner)
+
+		// Without a trusted signing time, the certificate is checked at the
+		// current time
+		_, err := Verify(contents, signature, roots, time.Time{})
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+
+		// A trusted signing time before the certificate expired is used
+		cert, err := Verify(contents, signature, roots, expiredAt.Add(-time.Minute))
+		assert.Nil(t, err)
+		assert.Equal(t, expiredSigner.cert.Raw, cert.Raw)
+	})
+
+	t.Run("not an S/MIME signature", func(t *testing.T) {
+		_, err := Verify(contents, []byte("-----BEGIN PGP SIGNATURE-----\n\n-----END PGP SIGNATURE-----\n"), roots, time.Time{})
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+}
+
+func TestInspect(t *testing.T) {
+	ca := newTestCA(t, "Example CA")
+	signer := newTestCertificate(t, &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "Jane Doe"},
+		EmailAddresses: []string{"jane.doe@example.com"},
+	}, ca)
+
+	cert, hash, err := Inspect(sign(t, []byte("contents"), signer))
+	assert.Nil(t, err)
+	assert.Equal(t, signer.cert.Raw, cert.Raw)
+	assert.Equal(t, crypto.SHA256, hash)
+
+	_, _, err = Inspect([]byte("not a signature"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestIdentities(t *testing.T) {
+	ca := newTestCA(t, "Example CA")
+	cert := newTestCertificate(t, &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName: "Jane Doe",
+			ExtraNames: []pkix.AttributeTypeAndValue{{Type: emailAddressOID, Value: "jane@example.com"}},
+		},
+		EmailAddresses: []string{"jane.doe@example.com"},
+	}, ca)
+
+	assert.Equal(t, []string{"jane.doe@example.com", "jane@example.com"}, Identities(cert.cert))
+	assert.True(t, HasIdentity(cert.cert, "jane@example.com"))
+}
+
+func TestLoadTrustStore(t *testing.T) {
+	ca := newTestCA(t, "Example CA")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})
+
+	tmpDir := t.TempDir()
+	caFile := filepath.Join(tmpDir, "ca.pem")
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "README"), []byte("Trusted roots"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{caFile, tmpDir} {
+		roots, err := LoadTrustStore(path)
+		assert.Nil(t, err)
+		_, err = ca.cert.Verify(x509.VerifyOptions{Roots: roots})
+		assert.Nil(t, err)
+	}
+
+	_, err := LoadTrustStore("")
+	assert.ErrorIs(t, err, ErrInvalidTrustStore)
+
+	_, err = LoadTrustStore(t.TempDir())
+	assert.ErrorIs(t, err, ErrInvalidTrustStore)
+
+	_, err = LoadTrustStore(filepath.Join(tmpDir, "missing.pem"))
+	assert.ErrorIs(t, err, ErrInvalidTrustStore)
+}
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sshagent implements signing using keys held in an SSH agent, such as
+// keys that are only available on a hardware token. Keys in the agent are
+// selected using their SHA256 fingerprint, as displayed by `ssh-keygen -l`, or
+// their public key in the authorized_keys format.
+package sshagent
+
+import (
+	"context"
+	"crypto"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+const (
+	// KeyPrefix identifies signing keys held in the SSH agent, such as
+	// "ssh-agent:SHA256:<fingerprint>". If nothing follows the prefix, the
+	// agent's first key is used.
+	KeyPrefix = "ssh-agent:"
+
+	socketEnvKey = "SSH_AUTH_SOCK"
+)
+
+var (
+	ErrAgentNotAvailable  = errors.New("SSH agent is not available, SSH_AUTH_SOCK is not set")
+	ErrNoKeysInAgent      = errors.New("SSH agent has no keys")
+	ErrKeyNotInAgent      = errors.New("key not found in SSH agent")
+	ErrUnsupportedKeyType = errors.New("SSH agent key cannot be used to sign gittuf metadata, only ED25519 and ECDSA keys are supported")
+)
+
+// dial connects to the SSH agent. It is overridden in tests.
+var dial = func() (agent.ExtendedAgent, io.Closer, error) {
+	socket := os.Getenv(socketEnvKey)
+	if socket == "" {
+		return nil, nil, ErrAgentNotAvailable
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to connect to SSH agent: %w", err)
+	}
+
+	return agent.NewClient(conn), conn, nil
+}
+
+// IsAgentKey returns true if the signing key identifies a key held in the SSH
+// agent.
+func IsAgentKey(key string) bool {
+	return strings.HasPrefix(key, KeyPrefix)
+}
+
+// WithSigner connects to the SSH agent and invokes fn with the agent's key that
+// matches the selector. The selector is either the key's SHA256 fingerprint or
+// its public key, optionally prefixed with "key::" like in Git's
+// user.signingKey option. If the selector is empty, the agent's first key is
+// used. The connection to the agent is closed when fn returns.
+func WithSigner(selector string, fn func(ssh.Signer) error) error {
+	sshAgent, conn, err := dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close() //nolint:errcheck
+
+	signers, err := sshAgent.Signers()
+	if err != nil {
+		return fmt.Errorf("unable to list keys in SSH agent: %w", err)
+	}
+
+	signer, err := findSigner(signers, selector)
+	if err != nil {
+		return err
+	}
+
+	return fn(signer)
+}
+
+// Signer is a dsse.SignerVerifier backed by a key held in the SSH agent.
+type Signer struct {
+	selector string
+	key      *tuf.Key
+	verifier dsse.Verifier
+}
+
+// NewSigner returns a Signer for the agent's key that matches the selector, as
+// described for WithSigner. The key must be an ED25519 or ECDSA key so that
+// signatures created using the agent can be verified without it.
+func NewSigner(selector string) (*Signer, error) {
+	var publicKey ssh.PublicKey
+	if err := WithSigner(selector, func(signer ssh.Signer) error {
+		publicKey = signer.PublicKey()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	switch publicKey.Type() {
+	case ssh.KeyAlgoED25519, ssh.KeyAlgoECDSA256, ssh.KeyAlgoECDSA384, ssh.KeyAlgoECDSA521:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, publicKey.Type())
+	}
+
+	cryptoPublicKey, ok := publicKey.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, publicKey.Type())
+	}
+
+	key, err := sslibsv.NewKey(cryptoPublicKey.CryptoPublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := sslibsv.NewVerifierFromSSLibKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// The selected key is pinned so that the same key is used even if the
+	// agent's keys change
+	return &Signer{selector: ssh.FingerprintSHA256(publicKey), key: key, verifier: verifier}, nil
+}
+
+// Sign signs the data using the agent. The signature is converted to the
+// encoding used for the key type in gittuf metadata.
+func (s *Signer) Sign(_ context.Context, data []byte) ([]byte, error) {
+	var signature []byte
+	err := WithSigner(s.selector, func(signer ssh.Signer) error {
+		sshSignature, err := signer.Sign(nil, data)
+		if err != nil {
+			return fmt.Errorf("unable to sign using SSH agent: %w", err)
+		}
+
+		signature, err = convertSignature(sshSignature)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signature, nil
+}
+
+// Verify verifies the signature using the key's public key.
+func (s *Signer) Verify(ctx context.Context, data, sig []byte) error {
+	return s.verifier.Verify(ctx, data, sig)
+}
+
+// KeyID returns the ID of the key.
+func (s *Signer) KeyID() (string, error) {
+	return s.key.KeyID, nil
+}
+
+// Public returns the key's public key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.verifier.Public()
+}
+
+// PublicKey returns the key's public key as used in gittuf metadata.
+func (s *Signer) PublicKey() *tuf.Key {
+	return s.key
+}
+
+// findSigner returns the signer that matches the selector, as described for
+// WithSigner.
+func findSigner(signers []ssh.Signer, selector string) (ssh.Signer, error) {
+	if len(signers) == 0 {
+		return nil, ErrNoKeysInAgent
+	}
+
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return signers[0], nil
+	}
+
+	if strings.HasPrefix(selector, "SHA256:") {
+		for _, signer := range signers {
+			if ssh.FingerprintSHA256(signer.PublicKey()) == selector {
+				return signer, nil
+			}
+		}
+	} else {
+		publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimPrefix(selector, "key::")))
+		if err != nil {
+			return nil, fmt.Errorf("%w: '%s' is neither a SHA256 fingerprint nor a public key", ErrKeyNotInAgent, selector)
+		}
+
+		for _, signer := range signers {
+			if string(signer.PublicKey().Marshal()) == string(publicKey.Marshal()) {
+				return signer, nil
+			}
+		}
+	}
+
+	fingerprints := make([]string, 0, len(signers))
+	for _, signer := range signers {
+		fingerprints = append(fingerprints, ssh.FingerprintSHA256(signer.PublicKey()))
+	}
+	return nil, fmt.Errorf("%w: '%s', the agent has %s", ErrKeyNotInAgent, selector, strings.Join(fingerprints, ", "))
+}
+
+// convertSignature returns the signature created by the agent in the encoding
+// expected by securesystemslib's verifiers. ED25519 signatures are used as is,
+// and ECDSA signatures are converted from SSH's encoding to ASN.1.
+func convertSignature(signature *ssh.Signature) ([]byte, error) {
+	switch signature.Format {
+	case ssh.KeyAlgoED25519:
+		return signature.Blob, nil
+	case ssh.KeyAlgoECDSA256, ssh.KeyAlgoECDSA384, ssh.KeyAlgoECDSA521:
+		var ecdsaSignature struct {
+			R *big.Int
+			S *big.Int
+		}
+		if err := ssh.Unmarshal(signature.Blob, &ecdsaSignature); err != nil {
+			return nil, fmt.Errorf("unable to parse ECDSA signature from SSH agent: %w", err)
+		}
+
+		return asn1.Marshal(ecdsaSignature)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, signature.Format)
+	}
+}
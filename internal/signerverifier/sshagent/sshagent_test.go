@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sshagent
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"testing"
+
+	sslibsv "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/signerverifier"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+func TestSigner(t *testing.T) {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyring := agent.NewKeyring()
+	for _, key := range []any{ed25519Key, ecdsaKey, rsaKey} {
+		if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	originalDial := dial
+	dial = func() (agent.ExtendedAgent, io.Closer, error) {
+		return keyring.(agent.ExtendedAgent), nopCloser{}, nil
+	}
+	t.Cleanup(func() { dial = originalDial })
+
+	ecdsaPublicKey, err := ssh.NewPublicKey(&ecdsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519PublicKey, err := ssh.NewPublicKey(ed25519Key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPublicKey, err := ssh.NewPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		selector  string
+		publicKey any
+	}{
+		"first key": {
+			selector:  "",
+			publicKey: ed25519Key.Public(),
+		},
+		"fingerprint": {
+			selector:  ssh.FingerprintSHA256(ecdsaPublicKey),
+			publicKey: &ecdsaKey.PublicKey,
+		},
+		"public key": {
+			selector:  "key::" + string(ssh.MarshalAuthorizedKey(ed25519PublicKey)),
+			publicKey: ed25519Key.Public(),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			signer, err := NewSigner(test.selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedKey, err := sslibsv.NewKey(test.publicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expectedKey, signer.PublicKey())
+
+			keyID, err := signer.KeyID()
+			assert.Nil(t, err)
+			assert.Equal(t, expectedKey.KeyID, keyID)
+
+			data := []byte("gittuf")
+			signature, err := signer.Sign(context.Background(), data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The signature must be verifiable without the agent
+			verifier, err := sslibsv.NewVerifierFromSSLibKey(expectedKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Nil(t, verifier.Verify(context.Background(), data, signature))
+			assert.NotNil(t, verifier.Verify(context.Background(), []byte("not gittuf"), signature))
+		})
+	}
+
+	t.Run("unsupported key type", func(t *testing.T) {
+		_, err := NewSigner(ssh.FingerprintSHA256(rsaPublicKey))
+		assert.ErrorIs(t, err, ErrUnsupportedKeyType)
+	})
+
+	t.Run("key not in agent", func(t *testing.T) {
+		_, err := NewSigner("SHA256:unknown")
+		assert.ErrorIs(t, err, ErrKeyNotInAgent)
+
+		_, err = NewSigner("not a key")
+		assert.ErrorIs(t, err, ErrKeyNotInAgent)
+	})
+
+	t.Run("no keys in agent", func(t *testing.T) {
+		dial = func() (agent.ExtendedAgent, io.Closer, error) {
+			return agent.NewKeyring().(agent.ExtendedAgent), nopCloser{}, nil
+		}
+		t.Cleanup(func() {
+			dial = func() (agent.ExtendedAgent, io.Closer, error) {
+				return keyring.(agent.ExtendedAgent), nopCloser{}, nil
+			}
+		})
+
+		_, err := NewSigner("")
+		assert.ErrorIs(t, err, ErrNoKeysInAgent)
+	})
+}
+
+func TestAgentNotAvailable(t *testing.T) {
+	t.Setenv(socketEnvKey, "")
+
+	err := WithSigner("", func(ssh.Signer) error { return nil })
+	assert.ErrorIs(t, err, ErrAgentNotAvailable)
+}
@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sshshell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/logging"
+)
+
+// logger logs the messages of the sshshell subsystem.
+var logger = logging.For("sshshell")
+
+const (
+	ServiceUploadPack    = "git-upload-pack"
+	ServiceUploadArchive = "git-upload-archive"
+	ServiceReceivePack   = "git-receive-pack"
+)
+
+// preReceiveHook is the name of the hook that verifies pushes.
+const preReceiveHook = "pre-receive"
+
+var (
+	ErrInteractiveShell      = errors.New("interactive shell access is not allowed")
+	ErrUnsupportedCommand    = errors.New("unsupported command")
+	ErrInvalidRepositoryPath = errors.New("invalid repository path")
+	ErrRepositoryNotFound    = errors.New("repository not found")
+)
+
+// runGit is a seam for tests, so that the git binary isn't invoked.
+var runGit = execGit
+
+// Command is a Git command requested by an SSH client.
+type Command struct {
+	// Service is the Git program the client requested, such as
+	// git-receive-pack.
+	Service string
+
+	// Path is the path of the repository as requested by the client.
+	Path string
+}
+
+// ParseCommand parses the command an SSH client requested, as found in
+// SSH_ORIGINAL_COMMAND. Git clients request commands of the form
+// "git-receive-pack '<path>'", quoting the path for a POSIX shell.
+func ParseCommand(original string) (*Command, error) {
+	original = strings.TrimSpace(original)
+	if original == "" {
+		return nil, ErrInteractiveShell
+	}
+
+	service, path, _ := strings.Cut(original, " ")
+	// Some clients use the "git <program>" form instead
+	if service == "git" {
+		program, rest, _ := strings.Cut(strings.TrimSpace(path), " ")
+		service, path = "git-"+program, rest
+	}
+
+	switch service {
+	case ServiceUploadPack, ServiceUploadArchive, ServiceReceivePack:
+	default:
+		return nil, fmt.Errorf("%w '%s'", ErrUnsupportedCommand, service)
+	}
+
+	path, err := unquote(strings.TrimSpace(path))
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return nil, fmt.Errorf("%w: no repository specified", ErrInvalidRepositoryPath)
+	}
+
+	return &Command{Service: service, Path: path}, nil
+}
+
+// unquote removes the quoting Git applies to paths it passes to the remote
+// shell. Git encloses the path in single quotes, with single quotes and
+// exclamation marks escaped as \' and \! outside of them.
+func unquote(quoted string) (string, error) {
+	if !strings.HasPrefix(quoted, "'") {
+		if strings.ContainsAny(quoted, " '\"\\") {
+			return "", fmt.Errorf("%w '%s'", ErrInvalidRepositoryPath, quoted)
+		}
+		return quoted, nil
+	}
+
+	unquoted := &strings.Builder{}
+	for len(quoted) > 0 {
+		switch {
+		case quoted[0] == '\'':
+			end := strings.IndexByte(quoted[1:], '\'')
+			if end < 0 {
+				return "", fmt.Errorf("%w: unterminated quote", ErrInvalidRepositoryPath)
+			}
+			unquoted.WriteString(quoted[1 : end+1])
+			quoted = quoted[end+2:]
+		case strings.HasPrefix(quoted, `\'`), strings.HasPrefix(quoted, `\!`):
+			unquoted.WriteByte(quoted[1])
+			quoted = quoted[2:]
+		default:
+			return "", fmt.Errorf("%w: unexpected characters outside quotes", ErrInvalidRepositoryPath)
+		}
+	}
+
+	return unquoted.String(), nil
+}
+
+// Shell is used as the forced command of SSH keys allowed to access the
+// repositories on a Git server. It serves fetches as usual, and runs the real
+// git-receive-pack for pushes with a pre-receive hook that verifies the
+// pushed refs and RSL entries using gittuf before any ref is updated.
+type Shell struct {
+	root       string
+	gittufPath string
+}
+
+// New returns a Shell that serves the repositories in the root directory, and
+// verifies pushes using the gittuf binary at gittufPath.
+func New(root, gittufPath string) *Shell {
+	return &Shell{root: root, gittufPath: gittufPath}
+}
+
+// Run runs the command requested by the SSH client, connected to the client's
+// stdin, stdout, and stderr.
+func (s *Shell) Run(ctx context.Context, original string, stdin io.Reader, stdout, stderr io.Writer) error {
+	command, err := ParseCommand(original)
+	if err != nil {
+		return err
+	}
+
+	repoPath, err := s.resolveRepository(command.Path)
+	if err != nil {
+		return err
+	}
+
+	program := strings.TrimPrefix(command.Service, "git-")
+	if command.Service != ServiceReceivePack {
+		return runGit(ctx, []string{program, repoPath}, stdin, stdout, stderr)
+	}
+
+	hooksDir, err := os.MkdirTemp("", "gittuf-shell-hooks-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(hooksDir) //nolint:errcheck
+
+	if err := s.writeHooks(hooksDir, filepath.Join(repoPath, "hooks")); err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("Receiving push to '%s'...", repoPath))
+	return runGit(ctx, []string{"-c", "core.hooksPath=" + hooksDir, program, repoPath}, stdin, stdout, stderr)
+}
+
+// resolveRepository returns the path of the repository requested by the
+// client. The path is interpreted relative to the root directory and must not
+// leave it. The ".git" suffix of bare repositories may be omitted.
+func (s *Shell) resolveRepository(path string) (string, error) {
+	path = strings.TrimPrefix(path, "~/")
+	for _, component := range strings.Split(path, "/") {
+		if component == ".." {
+			return "", fmt.Errorf("%w '%s'", ErrInvalidRepositoryPath, path)
+		}
+	}
+	cleaned := filepath.Clean("/" + path)
+	if cleaned == "/" {
+		return "", fmt.Errorf("%w '%s'", ErrInvalidRepositoryPath, path)
+	}
+
+	root, err := filepath.Abs(s.root)
+	if err != nil {
+		return "", err
+	}
+
+	candidate := filepath.Join(root, cleaned)
+	for _, repoPath := range []string{candidate, candidate + ".git"} {
+		info, err := os.Stat(repoPath)
+		if err == nil && info.IsDir() {
+			return repoPath, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: '%s'", ErrRepositoryNotFound, path)
+}
+
+// writeHooks populates the hooks directory used while receiving a push. The
+// pre-receive hook verifies the push using gittuf and then runs the
+// repository's own pre-receive hook, if any, and the repository's other hooks
+// are linked so that they continue to run.
+func (s *Shell) writeHooks(hooksDir, repoHooksDir string) error {
+	entries, err := os.ReadDir(repoHooksDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == preReceiveHook || entry.IsDir() || strings.HasSuffix(name, ".sample") {
+			continue
+		}
+		if err := os.Symlink(filepath.Join(repoHooksDir, name), filepath.Join(hooksDir, name)); err != nil {
+			return err
+		}
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+set -e
+
+updates=$(cat)
+printf '%%s\n' "$updates" | %s --non-interactive verify-push
+
+if [ -x %s ]
+then
+    printf '%%s\n' "$updates" | %s
+fi
+`, quote(s.gittufPath), quote(filepath.Join(repoHooksDir, preReceiveHook)), quote(filepath.Join(repoHooksDir, preReceiveHook)))
+
+	return os.WriteFile(filepath.Join(hooksDir, preReceiveHook), []byte(script), 0o700) //nolint:gosec
+}
+
+// quote quotes the value for a POSIX shell.
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func execGit(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	return cmd.Run()
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sshshell
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := map[string]struct {
+		original        string
+		expectedCommand *Command
+		expectedError   error
+	}{
+		"receive-pack": {
+			original:        "git-receive-pack '/repo.git'",
+			expectedCommand: &Command{Service: ServiceReceivePack, Path: "/repo.git"},
+		},
+		"upload-pack": {
+			original:        "git-upload-pack 'group/repo.git'",
+			expectedCommand: &Command{Service: ServiceUploadPack, Path: "group/repo.git"},
+		},
+		"git program form": {
+			original:        "git upload-archive 'repo.git'",
+			expectedCommand: &Command{Service: ServiceUploadArchive, Path: "repo.git"},
+		},
+		"escaped quote": {
+			original:        `git-receive-pack 'it'\''s'\!'.git'`,
+			expectedCommand: &Command{Service: ServiceReceivePack, Path: "it's!.git"},
+		},
+		"unquoted path": {
+			original:        "git-receive-pack repo.git",
+			expectedCommand: &Command{Service: ServiceReceivePack, Path: "repo.git"},
+		},
+		"interactive shell": {
+			original:      "",
+			expectedError: ErrInteractiveShell,
+		},
+		"unsupported command": {
+			original:      "rm -rf /",
+			expectedError: ErrUnsupportedCommand,
+		},
+		"unterminated quote": {
+			original:      "git-receive-pack 'repo.git",
+			expectedError: ErrInvalidRepositoryPath,
+		},
+		"extra arguments": {
+			original:      "git-receive-pack 'repo.git' 'other.git'",
+			expectedError: ErrInvalidRepositoryPath,
+		},
+		"missing path": {
+			original:      "git-receive-pack",
+			expectedError: ErrInvalidRepositoryPath,
+		},
+	}
+
+	for name, test := range tests {
+		command, err := ParseCommand(test.original)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, fmt.Sprintf("unexpected error in test '%s'", name))
+			continue
+		}
+
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		assert.Equal(t, test.expectedCommand, command, fmt.Sprintf("unexpected command in test '%s'", name))
+	}
+}
+
+func TestShell(t *testing.T) {
+	root := t.TempDir()
+	repoPath := filepath.Join(root, "repo.git")
+	if err := os.MkdirAll(filepath.Join(repoPath, "hooks"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoPath, "hooks", "post-receive"), []byte("#!/bin/sh\n"), 0o700); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoPath, "hooks", "update.sample"), []byte("#!/bin/sh\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		invokedArgs []string
+		hooks       []string
+		preReceive  string
+	)
+	runGit = func(_ context.Context, args []string, _ io.Reader, _, _ io.Writer) error {
+		invokedArgs = args
+		hooks = nil
+		preReceive = ""
+
+		if hooksPath, isReceivePack := strings.CutPrefix(args[1], "core.hooksPath="); isReceivePack {
+			entries, err := os.ReadDir(hooksPath)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				hooks = append(hooks, entry.Name())
+			}
+
+			contents, err := os.ReadFile(filepath.Join(hooksPath, preReceiveHook))
+			if err != nil {
+				return err
+			}
+			preReceive = string(contents)
+		}
+		return nil
+	}
+	t.Cleanup(func() {
+		runGit = execGit
+	})
+
+	shell := New(root, "/usr/local/bin/gittuf")
+
+	t.Run("fetch", func(t *testing.T) {
+		err := shell.Run(context.Background(), "git-upload-pack '/repo.git'", nil, io.Discard, io.Discard)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"upload-pack", repoPath}, invokedArgs)
+	})
+
+	t.Run("push", func(t *testing.T) {
+		err := shell.Run(context.Background(), "git-receive-pack 'repo'", nil, io.Discard, io.Discard)
+		assert.Nil(t, err)
+		assert.Equal(t, "receive-pack", invokedArgs[2])
+		assert.Equal(t, repoPath, invokedArgs[3])
+		assert.Equal(t, []string{"post-receive", preReceiveHook}, hooks)
+		assert.Contains(t, preReceive, "'/usr/local/bin/gittuf' --non-interactive verify-push")
+		assert.Contains(t, preReceive, quote(filepath.Join(repoPath, "hooks", preReceiveHook)))
+	})
+
+	t.Run("repository outside root", func(t *testing.T) {
+		invokedArgs = nil
+
+		err := shell.Run(context.Background(), "git-receive-pack '../repo.git'", nil, io.Discard, io.Discard)
+		assert.ErrorIs(t, err, ErrInvalidRepositoryPath)
+		assert.Nil(t, invokedArgs)
+	})
+
+	t.Run("missing repository", func(t *testing.T) {
+		err := shell.Run(context.Background(), "git-upload-pack 'other.git'", nil, io.Discard, io.Discard)
+		assert.ErrorIs(t, err, ErrRepositoryNotFound)
+	})
+}
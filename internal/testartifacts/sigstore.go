@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts
+
+import _ "embed"
+
+// SigstoreTrustedRoot is the trusted root distributed using Sigstore's TUF
+// repository for the public good instance.
+//
+//go:embed testdata/sigstore/trusted_root.json
+var SigstoreTrustedRoot []byte
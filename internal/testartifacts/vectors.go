@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts
+
+import _ "embed"
+
+// The test vectors for gittuf's serialization of signed payloads are described
+// in testdata/vectors/README.md.
+
+//go:embed testdata/vectors/canonical-json.json
+var CanonicalJSONVectors []byte
+
+//go:embed testdata/vectors/root-metadata.json
+var RootMetadataVector []byte
+
+//go:embed testdata/vectors/root-metadata.dsse.json
+var RootMetadataEnvelopeVector []byte
+
+//go:embed testdata/vectors/targets-metadata.json
+var TargetsMetadataVector []byte
+
+//go:embed testdata/vectors/targets-metadata.dsse.json
+var TargetsMetadataEnvelopeVector []byte
+
+//go:embed testdata/vectors/reference-authorization.json
+var ReferenceAuthorizationVector []byte
+
+//go:embed testdata/vectors/reference-authorization.dsse.json
+var ReferenceAuthorizationEnvelopeVector []byte
+
+//go:embed testdata/vectors/policy-justification.json
+var PolicyJustificationVector []byte
+
+//go:embed testdata/vectors/policy-justification.dsse.json
+var PolicyJustificationEnvelopeVector []byte
+
+//go:embed testdata/vectors/rsl-reference-entry.txt
+var RSLReferenceEntryVector []byte
+
+//go:embed testdata/vectors/rsl-annotation-entry.txt
+var RSLAnnotationEntryVector []byte
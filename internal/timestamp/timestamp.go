@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package timestamp obtains and verifies evidence that a signature existed at a
+// point in time. The evidence is either a token issued by an RFC 3161 timestamp
+// authority (TSA) or an entry in a Rekor transparency log, and is used to check
+// that a signature was issued while the key that issued it was valid.
+package timestamp
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	cms "github.com/github/smimesign/ietf-cms"
+	"github.com/github/smimesign/ietf-cms/protocol"
+	tsp "github.com/github/smimesign/ietf-cms/timestamp"
+	"github.com/gittuf/gittuf/internal/rekor"
+)
+
+const (
+	contentTypeTimestampQuery = "application/timestamp-query"
+	contentTypeTimestampReply = "application/timestamp-reply"
+)
+
+var (
+	ErrUnexpectedResponse = errors.New("unexpected response from timestamp authority")
+	ErrInvalidTimestamp   = errors.New("invalid timestamp")
+)
+
+var (
+	timestamper     Timestamper
+	timestamperLock sync.RWMutex
+)
+
+// Timestamp is the evidence that a signature existed at a point in time. Only
+// one of its fields is set.
+type Timestamp struct {
+	// RFC3161Token is the DER encoded timestamp token issued by an RFC 3161
+	// timestamp authority for the SHA-256 digest of the signature.
+	RFC3161Token []byte `json:"rfc3161Token,omitempty"`
+
+	// RekorEntry is the Rekor log entry that records a signature over the
+	// signature. The entry's integrated time is when it was logged.
+	RekorEntry *rekor.LogEntry `json:"rekorEntry,omitempty"`
+}
+
+// Timestamper obtains timestamps for signatures.
+type Timestamper interface {
+	Timestamp(ctx context.Context, signature []byte) (*Timestamp, error)
+}
+
+// SetTimestamper sets the timestamper used to timestamp the signatures of RSL
+// entries and policy metadata when they're created. Signatures aren't
+// timestamped if it's nil, which is the default.
+func SetTimestamper(t Timestamper) {
+	timestamperLock.Lock()
+	defer timestamperLock.Unlock()
+
+	timestamper = t
+}
+
+// GetTimestamper returns the timestamper set using SetTimestamper, if any.
+func GetTimestamper() Timestamper {
+	timestamperLock.RLock()
+	defer timestamperLock.RUnlock()
+
+	return timestamper
+}
+
+// AuthorityClient is used to request timestamp tokens from an RFC 3161
+// timestamp authority.
+type AuthorityClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewAuthorityClient returns an AuthorityClient for the timestamp authority at
+// the specified URL.
+func NewAuthorityClient(url string) *AuthorityClient {
+	return &AuthorityClient{url: url, httpClient: http.DefaultClient}
+}
+
+// Timestamp requests a timestamp token for the SHA-256 digest of the signature.
+// The token includes the authority's certificate so that it can be verified
+// using the authority's root certificate alone.
+func (c *AuthorityClient) Timestamp(ctx context.Context, signature []byte) (*Timestamp, error) {
+	messageImprint, err := tsp.NewMessageImprint(crypto.SHA256, bytes.NewReader(signature))
+	if err != nil {
+		return nil, err
+	}
+
+	request := tsp.Request{
+		Version:        1,
+		MessageImprint: messageImprint,
+		Nonce:          tsp.GenerateNonce(),
+		CertReq:        true,
+	}
+	requestBytes, err := asn1.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", contentTypeTimestampQuery)
+
+	httpResponse, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close() //nolint:errcheck
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrUnexpectedResponse, httpResponse.StatusCode)
+	}
+	if contentType := httpResponse.Header.Get("Content-Type"); contentType != contentTypeTimestampReply {
+		return nil, fmt.Errorf("%w: content type '%s'", ErrUnexpectedResponse, contentType)
+	}
+
+	responseBytes, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := tsp.ParseResponse(responseBytes)
+	if err != nil {
+		return nil, errors.Join(ErrUnexpectedResponse, err)
+	}
+
+	info, err := response.Info()
+	if err != nil {
+		return nil, errors.Join(ErrUnexpectedResponse, err)
+	}
+	if !request.Matches(info) {
+		return nil, fmt.Errorf("%w: token does not match request", ErrUnexpectedResponse)
+	}
+
+	token, err := asn1.Marshal(response.TimeStampToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Timestamp{RFC3161Token: token}, nil
+}
+
+// RekorTimestamper timestamps signatures by logging them to a Rekor instance.
+// Rekor only logs signatures it can verify, so the signature is logged as the
+// artifact of a hashedrekord entry signed using an ephemeral key. The entry's
+// integrated time, which Rekor signs, shows that the signature existed when
+// it was logged.
+type RekorTimestamper struct {
+	client *rekor.Client
+}
+
+// NewRekorTimestamper returns a RekorTimestamper that logs signatures to the
+// Rekor instance of the client.
+func NewRekorTimestamper(client *rekor.Client) *RekorTimestamper {
+	return &RekorTimestamper{client: client}
+}
+
+// Timestamp logs the signature to the Rekor instance and returns the resulting
+// log entry.
+func (t *RekorTimestamper) Timestamp(ctx context.Context, signature []byte) (*Timestamp, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})
+
+	digest := sha256.Sum256(signature)
+	entrySignature, err := ecdsa.SignASN1(rand.Reader, privateKey, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := t.client.UploadHashedRekord(ctx, digest[:], entrySignature, publicKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Timestamp{RekorEntry: entry}, nil
+}
+
+// Verify checks that the timestamp is for the signature and returns the latest
+// time at which the signature could have been timestamped. Timestamp tokens
+// must be issued by an authority whose certificate chains to one of the roots,
+// and Rekor log entries must be signed by the Rekor instance with the public
+// key. Timestamps of a kind that can't be verified because the roots or the
+// public key are not provided are rejected.
+func (t *Timestamp) Verify(signature []byte, roots *x509.CertPool, rekorPublicKey crypto.PublicKey) (time.Time, error) {
+	switch {
+	case len(t.RFC3161Token) != 0:
+		if roots == nil {
+			return time.Time{}, fmt.Errorf("%w: no timestamp authorities are trusted", ErrInvalidTimestamp)
+		}
+		return verifyToken(t.RFC3161Token, signature, roots)
+
+	case t.RekorEntry != nil:
+		if rekorPublicKey == nil {
+			return time.Time{}, fmt.Errorf("%w: no Rekor instance is configured to verify the log entry", ErrInvalidTimestamp)
+		}
+
+		digest := sha256.Sum256(signature)
+		if err := t.RekorEntry.VerifyForDigest(digest[:], rekorPublicKey); err != nil {
+			return time.Time{}, errors.Join(ErrInvalidTimestamp, err)
+		}
+		return time.Unix(t.RekorEntry.IntegratedTime, 0), nil
+
+	default:
+		return time.Time{}, ErrInvalidTimestamp
+	}
+}
+
+func verifyToken(token, signature []byte, roots *x509.CertPool) (time.Time, error) {
+	contentInfo, err := protocol.ParseContentInfo(token)
+	if err != nil {
+		return time.Time{}, errors.Join(ErrInvalidTimestamp, err)
+	}
+	signedData, err := contentInfo.SignedDataContent()
+	if err != nil {
+		return time.Time{}, errors.Join(ErrInvalidTimestamp, err)
+	}
+	info, err := tsp.ParseInfo(signedData.EncapContentInfo)
+	if err != nil {
+		return time.Time{}, errors.Join(ErrInvalidTimestamp, err)
+	}
+
+	messageImprint, err := tsp.NewMessageImprint(crypto.SHA256, bytes.NewReader(signature))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !messageImprint.Equal(info.MessageImprint) {
+		return time.Time{}, fmt.Errorf("%w: token is for a different signature", ErrInvalidTimestamp)
+	}
+
+	cmsSignedData, err := cms.ParseSignedData(token)
+	if err != nil {
+		return time.Time{}, errors.Join(ErrInvalidTimestamp, err)
+	}
+
+	// The authority's certificate must have been valid when it issued the
+	// token, rather than now
+	if _, err := cmsSignedData.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: info.GenTime,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}); err != nil {
+		return time.Time{}, errors.Join(ErrInvalidTimestamp, err)
+	}
+
+	return info.GenTime.Add(info.Accuracy.Duration()), nil
+}
+
+// LoadAuthorityRoots returns a certificate pool with the PEM encoded root
+// certificates of trusted timestamp authorities. It returns nil if no
+// certificates are specified.
+func LoadAuthorityRoots(certificates []string) (*x509.CertPool, error) {
+	if len(certificates) == 0 {
+		return nil, nil
+	}
+
+	roots := x509.NewCertPool()
+	for _, certificate := range certificates {
+		if !roots.AppendCertsFromPEM([]byte(certificate)) {
+			return nil, fmt.Errorf("%w: unable to parse timestamp authority certificate", ErrInvalidTimestamp)
+		}
+	}
+
+	return roots, nil
+}
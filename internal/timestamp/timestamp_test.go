@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package timestamp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/github/smimesign/ietf-cms/oid"
+	"github.com/github/smimesign/ietf-cms/protocol"
+	tsp "github.com/github/smimesign/ietf-cms/timestamp"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAuthority struct {
+	certificate *x509.Certificate
+	privateKey  *ecdsa.PrivateKey
+	genTime     time.Time
+}
+
+func newTestAuthority(t *testing.T) *testAuthority {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gittuf test TSA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(certificateBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testAuthority{certificate: certificate, privateKey: privateKey, genTime: time.Now().Truncate(time.Second)}
+}
+
+func (a *testAuthority) roots() *x509.CertPool {
+	roots := x509.NewCertPool()
+	roots.AddCert(a.certificate)
+	return roots
+}
+
+func (a *testAuthority) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	request := tsp.Request{}
+	if _, err := asn1.Unmarshal(requestBytes, &request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	infoBytes, err := asn1.Marshal(tsp.Info{
+		Version:        1,
+		Policy:         asn1.ObjectIdentifier{1, 2, 3},
+		SerialNumber:   big.NewInt(1),
+		GenTime:        a.genTime,
+		MessageImprint: request.MessageImprint,
+		Nonce:          request.Nonce,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	eci, err := protocol.NewEncapsulatedContentInfo(oid.ContentTypeTSTInfo, infoBytes)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	signedData, err := protocol.NewSignedData(eci)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err := signedData.AddSignerInfo([]*x509.Certificate{a.certificate}, a.privateKey); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	contentInfo, err := signedData.ContentInfo()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	responseBytes, err := asn1.Marshal(tsp.Response{
+		Status:         tsp.PKIStatusInfo{Status: 0},
+		TimeStampToken: contentInfo,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeTimestampReply)
+	w.Write(responseBytes) //nolint:errcheck
+}
+
+func TestAuthorityClient(t *testing.T) {
+	authority := newTestAuthority(t)
+	server := httptest.NewServer(authority)
+	defer server.Close()
+
+	signature := []byte("signature")
+
+	ts, err := NewAuthorityClient(server.URL).Timestamp(context.Background(), signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, ts.RFC3161Token)
+	assert.Nil(t, ts.RekorEntry)
+
+	t.Run("valid token", func(t *testing.T) {
+		timestampTime, err := ts.Verify(signature, authority.roots(), nil)
+		assert.Nil(t, err)
+		assert.True(t, timestampTime.Equal(authority.genTime))
+	})
+
+	t.Run("different signature", func(t *testing.T) {
+		_, err := ts.Verify([]byte("other signature"), authority.roots(), nil)
+		assert.ErrorIs(t, err, ErrInvalidTimestamp)
+	})
+
+	t.Run("untrusted authority", func(t *testing.T) {
+		_, err := ts.Verify(signature, newTestAuthority(t).roots(), nil)
+		assert.ErrorIs(t, err, ErrInvalidTimestamp)
+
+		_, err = ts.Verify(signature, nil, nil)
+		assert.ErrorIs(t, err, ErrInvalidTimestamp)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		_, err := (&Timestamp{RFC3161Token: []byte("token")}).Verify(signature, authority.roots(), nil)
+		assert.ErrorIs(t, err, ErrInvalidTimestamp)
+	})
+
+	t.Run("empty timestamp", func(t *testing.T) {
+		_, err := (&Timestamp{}).Verify(signature, authority.roots(), nil)
+		assert.ErrorIs(t, err, ErrInvalidTimestamp)
+	})
+}
+
+func TestAuthorityClientUnexpectedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("not a timestamp")) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	_, err := NewAuthorityClient(server.URL).Timestamp(context.Background(), []byte("signature"))
+	assert.ErrorIs(t, err, ErrUnexpectedResponse)
+}
+
+func TestLoadAuthorityRoots(t *testing.T) {
+	authority := newTestAuthority(t)
+	certificatePEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: authority.certificate.Raw}))
+
+	roots, err := LoadAuthorityRoots(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, roots)
+
+	roots, err = LoadAuthorityRoots([]string{certificatePEM})
+	assert.Nil(t, err)
+	assert.True(t, roots.Equal(authority.roots()))
+
+	_, err = LoadAuthorityRoots([]string{"certificate"})
+	assert.ErrorIs(t, err, ErrInvalidTimestamp)
+}
+
+func TestSetTimestamper(t *testing.T) {
+	assert.Nil(t, GetTimestamper())
+
+	client := NewAuthorityClient("https://tsa.example.com")
+	SetTimestamper(client)
+	defer SetTimestamper(nil)
+
+	assert.Equal(t, client, GetTimestamper())
+}
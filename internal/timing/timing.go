@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package timing
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Phases of gittuf operations that are timed. Phases may overlap, for example,
+// reading Git objects is also part of walking the RSL.
+const (
+	PhaseRSLWalk               = "RSL walk"
+	PhaseSignatureVerification = "signature verification"
+	PhaseSigstore              = "Sigstore network calls"
+	PhaseGitObjectReads        = "Git object reads"
+)
+
+type phase struct {
+	name    string
+	calls   int
+	total   time.Duration
+	depth   int
+	started time.Time
+}
+
+var (
+	mu      sync.Mutex
+	enabled = false
+	started time.Time
+	phases  = map[string]*phase{}
+	now     = time.Now
+)
+
+// Enable starts recording the time spent in each phase. Until Enable is
+// called, Start is a no-op.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	enabled = true
+	started = now()
+	phases = map[string]*phase{}
+}
+
+// Enabled returns true if timing is enabled.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return enabled
+}
+
+// Start records the start of the specified phase and returns a function that
+// records its end. It's intended to be used as `defer timing.Start(phase)()`.
+// Nested calls for the same phase, such as an RSL query that invokes another
+// RSL query, are counted once.
+func Start(name string) func() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !enabled {
+		return func() {}
+	}
+
+	p, has := phases[name]
+	if !has {
+		p = &phase{name: name}
+		phases[name] = p
+	}
+
+	if p.depth == 0 {
+		p.calls++
+		p.started = now()
+	}
+	p.depth++
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		p.depth--
+		if p.depth == 0 {
+			p.total += now().Sub(p.started)
+		}
+	}
+}
+
+// Report writes the time spent in each phase, from the most to the least
+// time consuming, and the total time elapsed since timing was enabled.
+func Report(w io.Writer) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !enabled {
+		return nil
+	}
+
+	sortedPhases := make([]*phase, 0, len(phases))
+	for _, p := range phases {
+		sortedPhases = append(sortedPhases, p)
+	}
+	sort.Slice(sortedPhases, func(i, j int) bool {
+		if sortedPhases[i].total == sortedPhases[j].total {
+			return sortedPhases[i].name < sortedPhases[j].name
+		}
+		return sortedPhases[i].total > sortedPhases[j].total
+	})
+
+	elapsed := now().Sub(started)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Phase\tCalls\tTime\tShare\t")
+	for _, p := range sortedPhases {
+		share := 0.0
+		if elapsed > 0 {
+			share = float64(p.total) / float64(elapsed) * 100
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%.1f%%\t\n", p.name, p.calls, p.total.Round(time.Microsecond), share)
+	}
+	fmt.Fprintf(tw, "Total\t\t%s\t\t\n", elapsed.Round(time.Microsecond))
+
+	return tw.Flush()
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package timing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTiming(t *testing.T) {
+	current := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	t.Cleanup(func() {
+		now = time.Now
+		enabled = false
+		phases = map[string]*phase{}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		stop := Start(PhaseRSLWalk)
+		current = current.Add(time.Second)
+		stop()
+
+		assert.False(t, Enabled())
+		assert.Empty(t, phases)
+
+		buf := &bytes.Buffer{}
+		assert.Nil(t, Report(buf))
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		Enable()
+		assert.True(t, Enabled())
+
+		// Nested calls for the same phase are counted once
+		stopOuter := Start(PhaseRSLWalk)
+		stopInner := Start(PhaseRSLWalk)
+		stopRead := Start(PhaseGitObjectReads)
+		current = current.Add(time.Second)
+		stopRead()
+		current = current.Add(time.Second)
+		stopInner()
+		stopOuter()
+
+		stop := Start(PhaseRSLWalk)
+		current = current.Add(time.Second)
+		stop()
+
+		current = current.Add(time.Second)
+
+		assert.Equal(t, 2, phases[PhaseRSLWalk].calls)
+		assert.Equal(t, 3*time.Second, phases[PhaseRSLWalk].total)
+		assert.Equal(t, 1, phases[PhaseGitObjectReads].calls)
+		assert.Equal(t, time.Second, phases[PhaseGitObjectReads].total)
+
+		buf := &bytes.Buffer{}
+		assert.Nil(t, Report(buf))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 4)
+		assert.Equal(t, []string{"Phase", "Calls", "Time", "Share"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"RSL", "walk", "2", "3s", "75.0%"}, strings.Fields(lines[1]))
+		assert.Equal(t, []string{"Git", "object", "reads", "1", "1s", "25.0%"}, strings.Fields(lines[2]))
+		assert.Equal(t, []string{"Total", "4s"}, strings.Fields(lines[3]))
+	})
+}
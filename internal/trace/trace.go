@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Event types recorded during verification.
+const (
+	EventVerificationStarted  = "verification_started"
+	EventVerificationFinished = "verification_finished"
+	EventPolicyLoaded         = "policy_loaded"
+	EventAttestationsLoaded   = "attestations_loaded"
+	EventEntrySkipped         = "entry_skipped"
+	EventEntryVerified        = "entry_verified"
+	EventRuleEvaluated        = "rule_evaluated"
+	EventFixEntryFound        = "fix_entry_found"
+)
+
+// Results recorded in events.
+const (
+	ResultPass = "pass"
+	ResultFail = "fail"
+)
+
+type contextKey struct{}
+
+// Event is a single step of a verification workflow. Only the fields relevant
+// to the event's type are set.
+type Event struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+
+	// Ref is the ref being verified.
+	Ref string `json:"ref,omitempty"`
+
+	// EntryID and TargetID identify the RSL entry the event is for and the
+	// object it records.
+	EntryID  string `json:"entry_id,omitempty"`
+	TargetID string `json:"target_id,omitempty"`
+
+	// PolicyEntryID and PolicyID identify the RSL entry of the policy state
+	// in use and the policy commit it records.
+	PolicyEntryID string `json:"policy_entry_id,omitempty"`
+	PolicyID      string `json:"policy_id,omitempty"`
+
+	// Namespace, ObjectID, Rule, KeyIDs, and Threshold describe the
+	// evaluation of a rule for a Git object.
+	Namespace string   `json:"namespace,omitempty"`
+	ObjectID  string   `json:"object_id,omitempty"`
+	Rule      string   `json:"rule,omitempty"`
+	KeyIDs    []string `json:"key_ids,omitempty"`
+	Threshold int      `json:"threshold,omitempty"`
+
+	// Count is the number of RSL entries to be verified.
+	Count int `json:"count,omitempty"`
+
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Tracer records the steps of verification workflows as JSON encoded events,
+// one per line, so that verification can be debugged after the fact. A nil
+// Tracer is valid and records nothing.
+type Tracer struct {
+	encoder *json.Encoder
+	now     func() time.Time
+	err     error
+}
+
+// NewTracer returns a Tracer that writes events to w.
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{encoder: json.NewEncoder(w), now: time.Now}
+}
+
+// ContextWithTracer returns a copy of the context that carries the specified
+// tracer. Verification workflows record their steps using the tracer.
+func ContextWithTracer(ctx context.Context, tracer *Tracer) context.Context {
+	return context.WithValue(ctx, contextKey{}, tracer)
+}
+
+// TracerFromContext returns the tracer carried by the context, if any.
+func TracerFromContext(ctx context.Context) *Tracer {
+	tracer, ok := ctx.Value(contextKey{}).(*Tracer)
+	if !ok {
+		return nil
+	}
+
+	return tracer
+}
+
+// Record writes the event, setting its time. Once writing an event fails, no
+// further events are written, and the error is returned by Err.
+func (t *Tracer) Record(event Event) {
+	if t == nil || t.err != nil {
+		return
+	}
+
+	event.Time = t.now().UTC()
+	t.err = t.encoder.Encode(event)
+}
+
+// Err returns the error encountered when writing events, if any.
+func (t *Tracer) Err() error {
+	if t == nil {
+		return nil
+	}
+
+	return t.err
+}
+
+// ResultOf returns the result and error message to record for err.
+func ResultOf(err error) (string, string) {
+	if err != nil {
+		return ResultFail, err.Error()
+	}
+
+	return ResultPass, ""
+}
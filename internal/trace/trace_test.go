@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("events written as JSON lines", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		tracer := NewTracer(buf)
+
+		current := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		tracer.now = func() time.Time { return current }
+
+		tracer.Record(Event{Type: EventVerificationStarted, Ref: "refs/heads/main", Count: 2})
+		result, message := ResultOf(errors.New("unauthorized signature"))
+		tracer.Record(Event{Type: EventEntryVerified, Ref: "refs/heads/main", EntryID: "abc", Result: result, Error: message})
+		assert.Nil(t, tracer.Err())
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if !assert.Len(t, lines, 2) {
+			return
+		}
+
+		event := Event{}
+		if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, Event{Time: current, Type: EventEntryVerified, Ref: "refs/heads/main", EntryID: "abc", Result: ResultFail, Error: "unauthorized signature"}, event)
+		assert.Equal(t, `{"time":"2024-01-01T00:00:00Z","type":"verification_started","ref":"refs/heads/main","count":2}`, lines[0])
+	})
+
+	t.Run("write failure", func(t *testing.T) {
+		tracer := NewTracer(failingWriter{})
+
+		tracer.Record(Event{Type: EventVerificationStarted})
+		assert.ErrorContains(t, tracer.Err(), "disk full")
+	})
+
+	t.Run("nil tracer", func(t *testing.T) {
+		var tracer *Tracer
+
+		assert.NotPanics(t, func() {
+			tracer.Record(Event{Type: EventVerificationStarted})
+		})
+		assert.Nil(t, tracer.Err())
+	})
+
+	t.Run("context", func(t *testing.T) {
+		assert.Nil(t, TracerFromContext(context.Background()))
+
+		tracer := NewTracer(&bytes.Buffer{})
+		ctx := ContextWithTracer(context.Background(), tracer)
+		assert.Equal(t, tracer, TracerFromContext(ctx))
+	})
+}
+
+func TestResultOf(t *testing.T) {
+	result, message := ResultOf(nil)
+	assert.Equal(t, ResultPass, result)
+	assert.Empty(t, message)
+}
@@ -90,6 +90,46 @@ type RootMetadata struct {
 	Expires            string          `json:"expires"`
 	Keys               map[string]*Key `json:"keys"`
 	Roles              map[string]Role `json:"roles"`
+
+	// RequirePolicyJustifications indicates that each policy state must be
+	// accompanied by a signed justification attestation before it is
+	// applied.
+	RequirePolicyJustifications bool `json:"require_policy_justifications,omitempty"`
+
+	// MetadataCompression is the algorithm used to compress large policy
+	// metadata and attestation blobs stored in the repository. Blobs are
+	// stored uncompressed if it is empty.
+	MetadataCompression string `json:"metadata_compression,omitempty"`
+
+	// KeyValidity records the period in which each key in the policy may
+	// issue signatures, keyed by the key's ID. A signature issued by a key
+	// with a validity window is only trusted if it's timestamped within the
+	// window, so that a key can be retired without invalidating its prior
+	// signatures.
+	KeyValidity map[string]*ValidityWindow `json:"key_validity,omitempty"`
+
+	// TimestampAuthorities contains the PEM encoded root certificates of the
+	// RFC 3161 timestamp authorities trusted to timestamp signatures.
+	TimestampAuthorities []string `json:"timestamp_authorities,omitempty"`
+
+	// RevokedKeys records the keys that have been revoked, keyed by the
+	// key's ID. Signatures issued by a revoked key are rejected regardless
+	// of when they were issued, even if the key is still listed in the
+	// policy.
+	RevokedKeys map[string]*KeyRevocation `json:"revoked_keys,omitempty"`
+}
+
+// KeyRevocation records why a key was revoked.
+type KeyRevocation struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// ValidityWindow is the period in which a key may issue signatures. The bounds
+// are RFC 3339 timestamps, and either may be empty to leave the window open on
+// that side.
+type ValidityWindow struct {
+	NotBefore string `json:"not_before,omitempty"`
+	NotAfter  string `json:"not_after,omitempty"`
 }
 
 // NewRootMetadata returns a new instance of RootMetadata.
@@ -138,6 +178,10 @@ type TargetsMetadata struct {
 	Expires     string         `json:"expires"`
 	Targets     map[string]any `json:"targets"`
 	Delegations *Delegations   `json:"delegations"`
+
+	// PredicatePolicies records the principals trusted to issue attestations
+	// of each predicate type. This is only used in the top level Targets role.
+	PredicatePolicies []PredicatePolicy `json:"predicate_policies,omitempty"`
 }
 
 // NewTargetsMetadata returns a new instance of TargetsMetadata.
@@ -212,4 +256,71 @@ type Delegation struct {
 	Terminating bool             `json:"terminating"`
 	Custom      *json.RawMessage `json:"custom,omitempty"`
 	Role
+
+	// RequiredHooks lists the hooks that must have been executed successfully
+	// for a change to be authorized using this delegation.
+	RequiredHooks []string `json:"required_hooks,omitempty"`
+
+	// RequiredRebuilds lists the artifacts that independent rebuilders must
+	// have reproduced from the target commit for a tag to be authorized using
+	// this delegation.
+	RequiredRebuilds []string `json:"required_rebuilds,omitempty"`
+
+	// RequiredEvaluators lists the rule evaluator plugins that must allow a
+	// change for it to be authorized using this delegation.
+	RequiredEvaluators []string `json:"required_evaluators,omitempty"`
+
+	// RequiredPredicates lists the predicate types of the in-toto statements
+	// that must have been attached to a change for it to be authorized using
+	// this delegation. Each statement must be signed by keys trusted by the
+	// predicate policy for its type.
+	RequiredPredicates []string `json:"required_predicates,omitempty"`
+
+	// RequiredApprovals requires a change to have been approved on a code
+	// review platform by principals trusted in another rule before it is
+	// authorized using this delegation.
+	RequiredApprovals *ApprovalRequirement `json:"required_approvals,omitempty"`
+
+	// RequireVerifiedSubmodule indicates that a submodule pointer matched by
+	// this delegation may only be updated to a commit that passes verification
+	// using the submodule repository's own gittuf metadata.
+	RequireVerifiedSubmodule bool `json:"require_verified_submodule,omitempty"`
+
+	// AllowedSignatureMethods restricts the mechanisms of Git signatures that
+	// count towards the delegation's threshold. If it's empty, Git signatures
+	// made using any mechanism supported for the delegation's keys count.
+	AllowedSignatureMethods []SignatureMethod `json:"allowed_signature_methods,omitempty"`
+}
+
+// SignatureMethod describes a mechanism accepted for Git signatures. Format is
+// one of "gpg", "ssh", "sigstore", or "smime". Algorithms lists the accepted
+// algorithms of signing keys, Hashes the accepted hash algorithms, and Issuers
+// the accepted OIDC issuers of Sigstore signatures. A list that is empty
+// accepts any value.
+type SignatureMethod struct {
+	Format     string   `json:"format"`
+	Algorithms []string `json:"algorithms,omitempty"`
+	Hashes     []string `json:"hashes,omitempty"`
+	Issuers    []string `json:"issuers,omitempty"`
+}
+
+// ApprovalRequirement requires Threshold distinct principals authorized by the
+// rule named Role to have approved a change in code review, as recorded in
+// review approval attestations. The rule must be in the same metadata file as
+// the rule that has the requirement.
+type ApprovalRequirement struct {
+	Role      string `json:"role"`
+	Threshold int    `json:"threshold"`
+}
+
+// PredicatePolicy defines the keys trusted to issue attestations of a predicate
+// type and the threshold of signatures required from them. The keys are stored
+// in the delegations of the Targets role the policy is recorded in.
+type PredicatePolicy struct {
+	PredicateType string `json:"predicate_type"`
+	Role
+
+	// Validator is the name of the predicate plugin that must accept the
+	// contents of attestations of the predicate type.
+	Validator string `json:"validator,omitempty"`
 }
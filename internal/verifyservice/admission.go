@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifyservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	// Annotations identifying the source an object was applied from. The
+	// repository is the name it's configured with in the service.
+	RepositoryAnnotation = "gittuf.dev/repository"
+	RefAnnotation        = "gittuf.dev/ref"
+	RevisionAnnotation   = "gittuf.dev/revision"
+
+	ReasonRevisionNotRecorded = "RevisionNotRecorded"
+
+	admissionReviewAPIVersion = "admission.k8s.io/v1"
+	admissionReviewKind       = "AdmissionReview"
+	deleteOperation           = "DELETE"
+)
+
+var errInvalidAdmissionReview = errors.New("invalid admission review")
+
+var recordedRevisions = getRecordedRevisions
+
+// AdmissionReview is the subset of Kubernetes' admission.k8s.io/v1
+// AdmissionReview used by the service.
+type AdmissionReview struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Request    *AdmissionRequest  `json:"request,omitempty"`
+	Response   *AdmissionResponse `json:"response,omitempty"`
+}
+
+// AdmissionRequest is the request of an AdmissionReview.
+type AdmissionRequest struct {
+	UID       string          `json:"uid"`
+	Name      string          `json:"name,omitempty"`
+	Namespace string          `json:"namespace,omitempty"`
+	Operation string          `json:"operation"`
+	Object    json.RawMessage `json:"object,omitempty"`
+}
+
+// AdmissionResponse is the response of an AdmissionReview.
+type AdmissionResponse struct {
+	UID     string           `json:"uid"`
+	Allowed bool             `json:"allowed"`
+	Status  *AdmissionStatus `json:"status,omitempty"`
+}
+
+// AdmissionStatus is the status returned to the Kubernetes API server when an
+// object is denied.
+type AdmissionStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// CheckRevision verifies the ref in the named repository, and checks that the
+// revision was recorded for the ref in an RSL entry that wasn't skipped. As
+// every such entry is verified, this asserts that the revision is a
+// gittuf-verified state of the ref, though not necessarily its latest state.
+// For annotated tags, the ID of the tagged commit is also accepted.
+func (s *Service) CheckRevision(ctx context.Context, name, refName, revision string) (*SourceStatus, error) {
+	if refName == "" {
+		return nil, errMissingRef
+	}
+
+	path, unlock, err := s.prepareRepository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	report, err := s.verifyAt(ctx, name, path, refName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SourceStatus{ReportID: report.ID}
+	if !report.Verified {
+		status.Reason = ReasonVerificationFailed
+		status.Message = fmt.Sprintf("gittuf verification of %s failed: %s", refName, report.Error)
+		return status, nil
+	}
+
+	absoluteRefName, revisions, err := recordedRevisions(path, refName)
+	if err != nil {
+		return nil, err
+	}
+
+	commitID := strings.ToLower(parseRevision(revision))
+	status.Revision = formatRevision(absoluteRefName, commitID)
+	if !revisions[commitID] {
+		status.Reason = ReasonRevisionNotRecorded
+		status.Message = fmt.Sprintf("revision %s is not a verified state of %s", commitID, absoluteRefName)
+		return status, nil
+	}
+
+	status.Ready = true
+	status.Reason = ReasonVerified
+	status.Message = fmt.Sprintf("revision %s is a verified state of %s", commitID, absoluteRefName)
+	return status, nil
+}
+
+// handleAdmissionReview responds to admission reviews sent by the Kubernetes
+// API server for validating admission webhooks. Objects are allowed if the
+// revision they're annotated with is a verified state of the annotated ref in
+// the annotated repository. Deletions are always allowed.
+func (s *Service) handleAdmissionReview(w http.ResponseWriter, r *http.Request) {
+	review := &AdmissionReview{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxPayloadSize)).Decode(review); err != nil || review.Request == nil {
+		writeError(w, http.StatusBadRequest, errInvalidAdmissionReview)
+		return
+	}
+
+	response := s.reviewAdmission(r.Context(), review.Request)
+	writeJSON(w, http.StatusOK, &AdmissionReview{
+		APIVersion: admissionReviewAPIVersion,
+		Kind:       admissionReviewKind,
+		Response:   response,
+	})
+}
+
+func (s *Service) reviewAdmission(ctx context.Context, request *AdmissionRequest) *AdmissionResponse {
+	response := &AdmissionResponse{UID: request.UID}
+	deny := func(code int, message string) *AdmissionResponse {
+		response.Status = &AdmissionStatus{Code: code, Message: message}
+		return response
+	}
+
+	if request.Operation == deleteOperation {
+		response.Allowed = true
+		return response
+	}
+
+	object := &struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(request.Object, object); err != nil {
+		return deny(http.StatusBadRequest, "unable to parse object")
+	}
+
+	annotations := object.Metadata.Annotations
+	name, refName, revision := annotations[RepositoryAnnotation], annotations[RefAnnotation], annotations[RevisionAnnotation]
+	if name == "" || refName == "" || revision == "" {
+		return deny(http.StatusForbidden, fmt.Sprintf("object must be annotated with its source using %s, %s, and %s", RepositoryAnnotation, RefAnnotation, RevisionAnnotation))
+	}
+
+	status, err := s.CheckRevision(ctx, name, refName, revision)
+	if err != nil {
+		logger.Error("Unable to check revision", "revision", revision, "ref", refName, "repository", name, "error", err)
+		return deny(errorStatus(err), fmt.Sprintf("unable to verify source: %s", err.Error()))
+	}
+	if !status.Ready {
+		return deny(http.StatusForbidden, fmt.Sprintf("%s (gittuf report %s)", status.Message, status.ReportID))
+	}
+
+	response.Allowed = true
+	return response
+}
+
+// getRecordedRevisions returns the absolute name of the ref in the repository
+// at the specified path, along with the IDs recorded for it in RSL entries that
+// weren't skipped. For annotated tags, the IDs of the tagged commits are also
+// included.
+func getRecordedRevisions(path, refName string) (string, map[string]bool, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := gitinterface.CheckObjectFormat(repo); err != nil {
+		return "", nil, err
+	}
+
+	absoluteRefName, err := gitinterface.AbsoluteReference(repo, refName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	revisions := map[string]bool{}
+	entry, _, err := rsl.GetLatestUnskippedReferenceEntryForRef(repo, absoluteRefName)
+	for err == nil {
+		revisions[entry.TargetID.String()] = true
+		if tag, tagErr := gitinterface.GetTag(repo, entry.TargetID); tagErr == nil {
+			revisions[tag.Target.String()] = true
+		}
+
+		entry, _, err = rsl.GetLatestUnskippedReferenceEntryForRefBefore(repo, absoluteRefName, entry.ID)
+	}
+	if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return "", nil, err
+	}
+	delete(revisions, plumbing.ZeroHash.String())
+
+	return absoluteRefName, revisions, nil
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifyservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdmissionReview(t *testing.T) {
+	recordedID := "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"
+	unrecordedID := "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e"
+
+	verifyRepository = func(_ context.Context, _, refName string, _ bool) (*policy.Explanation, error) {
+		if refName == "refs/heads/unverified" {
+			return &policy.Explanation{}, errors.New("unauthorized")
+		}
+		return &policy.Explanation{}, nil
+	}
+	recordedRevisions = func(_, refName string) (string, map[string]bool, error) {
+		return refName, map[string]bool{recordedID: true}, nil
+	}
+
+	review := func(operation string, annotations map[string]string) string {
+		object, err := json.Marshal(map[string]any{"metadata": map[string]any{"annotations": annotations}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		request, err := json.Marshal(&AdmissionReview{
+			APIVersion: admissionReviewAPIVersion,
+			Kind:       admissionReviewKind,
+			Request:    &AdmissionRequest{UID: "uid", Operation: operation, Object: object},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(request)
+	}
+
+	source := func(refName, revision string) map[string]string {
+		return map[string]string{RepositoryAnnotation: "local", RefAnnotation: refName, RevisionAnnotation: revision}
+	}
+
+	tests := map[string]struct {
+		operation   string
+		annotations map[string]string
+		allowed     bool
+	}{
+		"recorded revision": {
+			operation:   "CREATE",
+			annotations: source("refs/heads/main", recordedID),
+			allowed:     true,
+		},
+		"recorded revision as Flux revision": {
+			operation:   "UPDATE",
+			annotations: source("refs/heads/main", "main@sha1:"+recordedID),
+			allowed:     true,
+		},
+		"unrecorded revision": {
+			operation:   "CREATE",
+			annotations: source("refs/heads/main", unrecordedID),
+		},
+		"unverified ref": {
+			operation:   "CREATE",
+			annotations: source("refs/heads/unverified", recordedID),
+		},
+		"unknown repository": {
+			operation:   "CREATE",
+			annotations: map[string]string{RepositoryAnnotation: "unknown", RefAnnotation: "refs/heads/main", RevisionAnnotation: recordedID},
+		},
+		"missing annotations": {
+			operation: "CREATE",
+		},
+		"deletion": {
+			operation: "DELETE",
+			allowed:   true,
+		},
+	}
+
+	service := newTestService(t, testToken)
+	for name, test := range tests {
+		response := &AdmissionReview{}
+		status := doRequest(t, service, http.MethodPost, "/v1/admission", review(test.operation, test.annotations), response)
+		assert.Equal(t, http.StatusOK, status, fmt.Sprintf("unexpected status code in test '%s'", name))
+		assert.Equal(t, admissionReviewAPIVersion, response.APIVersion, fmt.Sprintf("unexpected API version in test '%s'", name))
+		assert.Equal(t, "uid", response.Response.UID, fmt.Sprintf("unexpected UID in test '%s'", name))
+		assert.Equal(t, test.allowed, response.Response.Allowed, fmt.Sprintf("unexpected result in test '%s'", name))
+		if !test.allowed {
+			assert.NotEmpty(t, response.Response.Status.Message, fmt.Sprintf("missing message in test '%s'", name))
+		}
+	}
+
+	status := doRequest(t, service, http.MethodPost, "/v1/admission", `{}`, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifyservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var ErrUnexpectedResponse = errors.New("unexpected response from gittuf API")
+
+// Client is a client for the HTTP API exposed by Service.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client for the API at baseURL. If token is set, it's
+// presented as a bearer token.
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// CheckSource checks that the revision is the verified tip of the ref in the
+// named repository. See Service.CheckSource.
+func (c *Client) CheckSource(ctx context.Context, name, refName, revision string) (*SourceStatus, error) {
+	query := url.Values{}
+	query.Set("ref", refName)
+	if revision != "" {
+		query.Set("revision", revision)
+	}
+
+	statusURL := fmt.Sprintf("%s/v1/repositories/%s/source-status?%s", c.baseURL, url.PathEscape(name), query.Encode())
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPreconditionFailed {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%w: checking source returned %s: %s", ErrUnexpectedResponse, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	status := &SourceStatus{}
+	if err := json.NewDecoder(resp.Body).Decode(status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifyservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/go-git/go-git/v5"
+)
+
+// Reasons reported in source statuses, following the conventions of
+// Kubernetes conditions.
+const (
+	ReasonVerified           = "Verified"
+	ReasonVerificationFailed = "VerificationFailed"
+	ReasonRevisionMismatch   = "RevisionMismatch"
+)
+
+var resolveRevisions = getRefRevisions
+
+// SourceStatus is the result of checking that a revision of a Git source a
+// GitOps controller is about to sync is the verified state of the ref it
+// tracks. Its fields follow the conventions of Kubernetes conditions, and the
+// revision is formatted like Flux's source revisions, <ref>@sha1:<id>, or
+// <ref>@sha256:<id> for repositories that use SHA-256 object IDs.
+type SourceStatus struct {
+	Ready    bool   `json:"ready"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Revision string `json:"revision"`
+	ReportID string `json:"report_id"`
+}
+
+// CheckSource verifies the ref in the named repository, and checks that the
+// specified revision is the ref's verified tip. Revisions can be specified as
+// commit IDs, such as those Argo CD passes to plugins, or as Flux source
+// revisions. If the revision is empty, only the ref is verified. For annotated
+// tags, both the tag's ID and the ID of the tagged commit are accepted.
+func (s *Service) CheckSource(ctx context.Context, name, refName, revision string) (*SourceStatus, error) {
+	if refName == "" {
+		return nil, errMissingRef
+	}
+
+	path, unlock, err := s.prepareRepository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	report, err := s.verifyAt(ctx, name, path, refName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SourceStatus{ReportID: report.ID}
+	if !report.Verified {
+		status.Reason = ReasonVerificationFailed
+		status.Message = fmt.Sprintf("gittuf verification of %s failed: %s", refName, report.Error)
+		return status, nil
+	}
+
+	absoluteRefName, revisions, err := resolveRevisions(path, refName)
+	if err != nil {
+		return nil, err
+	}
+	status.Revision = formatRevision(absoluteRefName, revisions[0])
+
+	if revision != "" {
+		commitID := parseRevision(revision)
+		matched := false
+		for _, candidate := range revisions {
+			if strings.EqualFold(candidate, commitID) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			logger.Debug(fmt.Sprintf("Revision '%s' of '%s' in '%s' does not match verified tip '%s'", revision, refName, name, revisions[0]))
+			status.Reason = ReasonRevisionMismatch
+			status.Message = fmt.Sprintf("revision %s is not the verified tip of %s", commitID, absoluteRefName)
+			return status, nil
+		}
+	}
+
+	status.Ready = true
+	status.Reason = ReasonVerified
+	status.Message = fmt.Sprintf("gittuf verification of %s succeeded", absoluteRefName)
+	return status, nil
+}
+
+// handleSourceStatus responds with the status of the source, using 200 if it's
+// ready to be synced and 412 otherwise, so that controllers and hooks can gate
+// on the status code alone.
+func (s *Service) handleSourceStatus(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	status, err := s.CheckSource(r.Context(), r.PathValue("name"), query.Get("ref"), query.Get("revision"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	if !status.Ready {
+		writeJSON(w, http.StatusPreconditionFailed, status)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+// parseRevision returns the commit ID in a revision specified as a commit ID or
+// as a Flux source revision, such as main@sha1:<id> or sha256:<id>.
+func parseRevision(revision string) string {
+	for _, objectFormat := range []string{gitinterface.ObjectFormatSHA1, gitinterface.ObjectFormatSHA256} {
+		if _, commitID, found := strings.Cut(revision, objectFormat+":"); found {
+			return commitID
+		}
+	}
+
+	return revision
+}
+
+// formatRevision formats the commit ID of the ref as a Flux source revision,
+// using the object format of the commit ID.
+func formatRevision(refName, commitID string) string {
+	objectFormat := gitinterface.ObjectFormatSHA1
+	if objectID, err := gitinterface.NewHash(commitID); err == nil {
+		objectFormat = gitinterface.ObjectFormatOf(objectID)
+	}
+
+	return fmt.Sprintf("%s@%s:%s", refName, objectFormat, commitID)
+}
+
+// getRefRevisions returns the absolute name of the ref in the repository at
+// the specified path, along with the IDs its tip can be referred to by. The
+// first ID is the tip itself, followed by the tagged commit for annotated tags.
+func getRefRevisions(path, refName string) (string, []string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := gitinterface.CheckObjectFormat(repo); err != nil {
+		return "", nil, err
+	}
+
+	absoluteRefName, err := gitinterface.AbsoluteReference(repo, refName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tipID, err := gitinterface.GetTip(repo, absoluteRefName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	revisions := []string{tipID.String()}
+	if tag, err := gitinterface.GetTag(repo, tipID); err == nil {
+		revisions = append(revisions, tag.Target.String())
+	}
+
+	return absoluteRefName, revisions, nil
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifyservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSource(t *testing.T) {
+	tipID := "8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f"
+	commitID := "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e"
+
+	verifyRepository = func(_ context.Context, _, refName string, _ bool) (*policy.Explanation, error) {
+		if refName == "refs/heads/unverified" {
+			return &policy.Explanation{}, errors.New("unauthorized")
+		}
+		return &policy.Explanation{}, nil
+	}
+	resolveRevisions = func(_, refName string) (string, []string, error) {
+		if refName == "v1.0.0" {
+			return "refs/tags/v1.0.0", []string{tipID, commitID}, nil
+		}
+		return "refs/heads/main", []string{tipID}, nil
+	}
+
+	server := httptest.NewServer(newTestService(t, testToken))
+	defer server.Close()
+	client := NewClient(server.URL, testToken)
+
+	tests := map[string]struct {
+		refName  string
+		revision string
+		ready    bool
+		reason   string
+	}{
+		"verified tip": {
+			refName:  "main",
+			revision: tipID,
+			ready:    true,
+			reason:   ReasonVerified,
+		},
+		"verified tip as Flux revision": {
+			refName:  "main",
+			revision: "main@sha1:" + tipID,
+			ready:    true,
+			reason:   ReasonVerified,
+		},
+		"no revision": {
+			refName: "main",
+			ready:   true,
+			reason:  ReasonVerified,
+		},
+		"commit of annotated tag": {
+			refName:  "v1.0.0",
+			revision: commitID,
+			ready:    true,
+			reason:   ReasonVerified,
+		},
+		"revision mismatch": {
+			refName:  "main",
+			revision: commitID,
+			reason:   ReasonRevisionMismatch,
+		},
+		"verification failed": {
+			refName:  "refs/heads/unverified",
+			revision: tipID,
+			reason:   ReasonVerificationFailed,
+		},
+	}
+
+	for name, test := range tests {
+		status, err := client.CheckSource(context.Background(), "local", test.refName, test.revision)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		assert.Equal(t, test.ready, status.Ready, fmt.Sprintf("unexpected result in test '%s'", name))
+		assert.Equal(t, test.reason, status.Reason, fmt.Sprintf("unexpected reason in test '%s'", name))
+		assert.NotEmpty(t, status.ReportID, fmt.Sprintf("missing report in test '%s'", name))
+	}
+
+	status, err := client.CheckSource(context.Background(), "local", "main", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "refs/heads/main@sha1:"+tipID, status.Revision)
+
+	_, err = client.CheckSource(context.Background(), "unknown", "main", "")
+	assert.ErrorIs(t, err, ErrUnexpectedResponse)
+
+	_, err = NewClient(server.URL, "").CheckSource(context.Background(), "local", "main", "")
+	assert.ErrorIs(t, err, ErrUnexpectedResponse)
+}
+
+func TestParseRevision(t *testing.T) {
+	commitID := "8563fd7ff19c382b296f0b7df564fa1791965af7"
+	assert.Equal(t, commitID, parseRevision(commitID))
+	assert.Equal(t, commitID, parseRevision("main@sha1:"+commitID))
+	assert.Equal(t, commitID, parseRevision("sha1:"+commitID))
+
+	commitID = "39c4d97504d031d508430af8a97d9350b20a9fdefd731fa5fff399021de0fcf4"
+	assert.Equal(t, commitID, parseRevision("main@sha256:"+commitID))
+	assert.Equal(t, commitID, parseRevision("sha256:"+commitID))
+}
+
+func TestFormatRevision(t *testing.T) {
+	commitID := "8563fd7ff19c382b296f0b7df564fa1791965af7"
+	assert.Equal(t, "refs/heads/main@sha1:"+commitID, formatRevision("refs/heads/main", commitID))
+
+	commitID = "39c4d97504d031d508430af8a97d9350b20a9fdefd731fa5fff399021de0fcf4"
+	assert.Equal(t, "refs/heads/main@sha256:"+commitID, formatRevision("refs/heads/main", commitID))
+}
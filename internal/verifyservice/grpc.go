@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifyservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	gittufv1 "github.com/gittuf/gittuf/internal/verifyservice/proto/gittuf/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+//go:generate protoc -I proto --go_out=proto --go_opt=paths=source_relative --go-grpc_out=proto --go-grpc_opt=paths=source_relative gittuf/v1/verification.proto
+
+// NewGRPCServer returns a gRPC server exposing the service's gittuf.v1 API,
+// configured with the specified options. If a token is configured, clients
+// must present it as a bearer token in the authorization metadata of each call.
+func (s *Service) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(append(opts, grpc.UnaryInterceptor(s.authorizeCall))...)
+	gittufv1.RegisterVerificationServiceServer(server, &grpcService{service: s})
+	return server
+}
+
+func (s *Service) authorizeCall(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	authorization := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+
+	if !s.authorized(authorization) {
+		return nil, status.Error(codes.Unauthenticated, errUnauthorized.Error())
+	}
+
+	return handler(ctx, req)
+}
+
+// grpcService implements the gittuf.v1 API using the service.
+type grpcService struct {
+	gittufv1.UnimplementedVerificationServiceServer
+
+	service *Service
+}
+
+func (g *grpcService) VerifyRef(ctx context.Context, req *gittufv1.VerifyRefRequest) (*gittufv1.VerifyRefResponse, error) {
+	report, err := g.service.Verify(ctx, req.GetRepository(), req.GetRef(), req.GetLatestOnly())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	response := &gittufv1.VerifyRefResponse{Report: &gittufv1.Report{
+		Id:         report.ID,
+		Repository: report.Repository,
+		Ref:        report.Ref,
+		LatestOnly: report.LatestOnly,
+		Verified:   report.Verified,
+		Error:      report.Error,
+		Time:       timestamppb.New(report.Time),
+	}}
+	for _, entry := range report.Explanation {
+		response.Report.FailedEntries = append(response.Report.FailedEntries, &gittufv1.FailedEntry{
+			RefName:  entry.RefName,
+			EntryId:  entry.EntryID,
+			TargetId: entry.TargetID,
+			Error:    entry.Error,
+		})
+	}
+
+	return response, nil
+}
+
+func (g *grpcService) GetPolicy(ctx context.Context, req *gittufv1.GetPolicyRequest) (*gittufv1.GetPolicyResponse, error) {
+	state, err := g.service.Policy(ctx, req.GetRepository())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	response := &gittufv1.GetPolicyResponse{}
+	for _, role := range state.Roles {
+		policyRole := &gittufv1.PolicyRole{
+			Name:      role.Name,
+			KeyIds:    role.KeyIDs,
+			Threshold: int32(role.Threshold), //nolint:gosec
+		}
+		if role.Expires != nil {
+			policyRole.Expires = timestamppb.New(*role.Expires)
+		}
+		response.Roles = append(response.Roles, policyRole)
+	}
+	for _, rule := range state.Rules {
+		response.Rules = append(response.Rules, &gittufv1.PolicyRule{
+			Name:      rule.Name,
+			Paths:     rule.Paths,
+			KeyIds:    rule.KeyIDs,
+			Threshold: int32(rule.Threshold), //nolint:gosec
+			Depth:     int32(rule.Depth),     //nolint:gosec
+		})
+	}
+
+	return response, nil
+}
+
+func (g *grpcService) ListRSLEntries(ctx context.Context, req *gittufv1.ListRSLEntriesRequest) (*gittufv1.ListRSLEntriesResponse, error) {
+	entries, err := g.service.RSLEntries(ctx, req.GetRepository(), int(req.GetLimit()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	response := &gittufv1.ListRSLEntriesResponse{}
+	for _, entry := range entries {
+		switch entry := entry.(type) {
+		case *rsl.ReferenceEntry:
+			response.Entries = append(response.Entries, &gittufv1.RSLEntry{
+				Id: entry.ID.String(),
+				Entry: &gittufv1.RSLEntry_Reference{Reference: &gittufv1.ReferenceEntry{
+					RefName:  entry.RefName,
+					TargetId: entry.TargetID.String(),
+				}},
+			})
+		case *rsl.AnnotationEntry:
+			annotation := &gittufv1.AnnotationEntry{Skip: entry.Skip, Message: entry.Message}
+			for _, id := range entry.RSLEntryIDs {
+				annotation.RslEntryIds = append(annotation.RslEntryIds, id.String())
+			}
+			response.Entries = append(response.Entries, &gittufv1.RSLEntry{
+				Id:    entry.ID.String(),
+				Entry: &gittufv1.RSLEntry_Annotation{Annotation: annotation},
+			})
+		}
+	}
+
+	return response, nil
+}
+
+func (g *grpcService) GetAttestations(ctx context.Context, req *gittufv1.GetAttestationsRequest) (*gittufv1.GetAttestationsResponse, error) {
+	envelopes, err := g.service.Attestations(ctx, req.GetRepository())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	paths := make([]string, 0, len(envelopes))
+	for path := range envelopes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	response := &gittufv1.GetAttestationsResponse{}
+	for _, path := range paths {
+		envelope, err := json.Marshal(envelopes[path])
+		if err != nil {
+			return nil, grpcError(err)
+		}
+		response.Attestations = append(response.Attestations, &gittufv1.Attestation{Path: path, Envelope: envelope})
+	}
+
+	return response, nil
+}
+
+// grpcError returns the gRPC status for errors returned by the service.
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, errRepositoryNotFound), errors.Is(err, errReportNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, errMissingRef):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errUnableToSyncRepository):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
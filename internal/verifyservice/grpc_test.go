@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifyservice
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	gittufv1 "github.com/gittuf/gittuf/internal/verifyservice/proto/gittuf/v1"
+	"github.com/go-git/go-git/v5/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestGRPCClient(t *testing.T, service *Service) gittufv1.VerificationServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := service.NewGRPCServer()
+	go server.Serve(listener) //nolint:errcheck
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() }) //nolint:errcheck
+
+	return gittufv1.NewVerificationServiceClient(conn)
+}
+
+func TestGRPCService(t *testing.T) {
+	entryID := plumbing.NewHash("3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e")
+	targetID := plumbing.NewHash("8a5e2b8bb2d9e33e0a5ad1e8c1b8c5ad1a7b2e3f")
+
+	verifyRepository = func(_ context.Context, _, _ string, _ bool) (*policy.Explanation, error) {
+		return &policy.Explanation{}, nil
+	}
+	loadPolicyState = func(_ context.Context, _ string) (*PolicyState, error) {
+		return &PolicyState{
+			Roles: []*PolicyRole{{Name: "root", KeyIDs: []string{"key"}, Threshold: 1}},
+			Rules: []*PolicyRule{{Name: "protect-main", Paths: []string{"git:refs/heads/main"}, Threshold: 1, Depth: 0}},
+		}, nil
+	}
+	listRSLEntries = func(_ string, limit int) ([]rsl.Entry, error) {
+		assert.Equal(t, 10, limit)
+		return []rsl.Entry{
+			&rsl.AnnotationEntry{ID: entryID, RSLEntryIDs: []plumbing.Hash{targetID}, Message: "message"},
+			&rsl.ReferenceEntry{ID: targetID, RefName: "refs/heads/main", TargetID: targetID},
+		}, nil
+	}
+	listAttestations = func(_ string) (map[string]*sslibdsse.Envelope, error) {
+		return map[string]*sslibdsse.Envelope{"push-events/" + entryID.String(): {PayloadType: "application/vnd.in-toto+json"}}, nil
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testToken)
+
+	t.Run("calls", func(t *testing.T) {
+		client := newTestGRPCClient(t, newTestService(t, testToken))
+
+		verifyResponse, err := client.VerifyRef(ctx, &gittufv1.VerifyRefRequest{Repository: "local", Ref: "refs/heads/main"})
+		assert.Nil(t, err)
+		assert.True(t, verifyResponse.GetReport().GetVerified())
+		assert.NotEmpty(t, verifyResponse.GetReport().GetId())
+
+		policyResponse, err := client.GetPolicy(ctx, &gittufv1.GetPolicyRequest{Repository: "local"})
+		assert.Nil(t, err)
+		assert.Equal(t, "root", policyResponse.GetRoles()[0].GetName())
+		assert.Nil(t, policyResponse.GetRoles()[0].GetExpires())
+		assert.Equal(t, "protect-main", policyResponse.GetRules()[0].GetName())
+
+		rslResponse, err := client.ListRSLEntries(ctx, &gittufv1.ListRSLEntriesRequest{Repository: "local", Limit: 10})
+		assert.Nil(t, err)
+		assert.Len(t, rslResponse.GetEntries(), 2)
+		assert.Equal(t, []string{targetID.String()}, rslResponse.GetEntries()[0].GetAnnotation().GetRslEntryIds())
+		assert.Equal(t, "refs/heads/main", rslResponse.GetEntries()[1].GetReference().GetRefName())
+
+		attestationsResponse, err := client.GetAttestations(ctx, &gittufv1.GetAttestationsRequest{Repository: "local"})
+		assert.Nil(t, err)
+		assert.Len(t, attestationsResponse.GetAttestations(), 1)
+		assert.Equal(t, "push-events/"+entryID.String(), attestationsResponse.GetAttestations()[0].GetPath())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		client := newTestGRPCClient(t, newTestService(t, testToken))
+
+		_, err := client.VerifyRef(ctx, &gittufv1.VerifyRefRequest{Repository: "unknown", Ref: "refs/heads/main"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		_, err = client.VerifyRef(ctx, &gittufv1.VerifyRefRequest{Repository: "local"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.GetPolicy(context.Background(), &gittufv1.GetPolicyRequest{Repository: "local"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
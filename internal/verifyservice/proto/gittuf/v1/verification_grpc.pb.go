@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.3
+// source: gittuf/v1/verification.proto
+
+package gittufv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	VerificationService_VerifyRef_FullMethodName       = "/gittuf.v1.VerificationService/VerifyRef"
+	VerificationService_GetPolicy_FullMethodName       = "/gittuf.v1.VerificationService/GetPolicy"
+	VerificationService_ListRSLEntries_FullMethodName  = "/gittuf.v1.VerificationService/ListRSLEntries"
+	VerificationService_GetAttestations_FullMethodName = "/gittuf.v1.VerificationService/GetAttestations"
+)
+
+// VerificationServiceClient is the client API for VerificationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type VerificationServiceClient interface {
+	// VerifyRef verifies a ref against the repository's policy. A failed
+	// verification is reported in the response rather than as an error.
+	VerifyRef(ctx context.Context, in *VerifyRefRequest, opts ...grpc.CallOption) (*VerifyRefResponse, error)
+	// GetPolicy returns the roles and rules of the repository's policy.
+	GetPolicy(ctx context.Context, in *GetPolicyRequest, opts ...grpc.CallOption) (*GetPolicyResponse, error)
+	// ListRSLEntries returns the repository's RSL entries, starting with the
+	// latest.
+	ListRSLEntries(ctx context.Context, in *ListRSLEntriesRequest, opts ...grpc.CallOption) (*ListRSLEntriesResponse, error)
+	// GetAttestations returns the repository's current attestations.
+	GetAttestations(ctx context.Context, in *GetAttestationsRequest, opts ...grpc.CallOption) (*GetAttestationsResponse, error)
+}
+
+type verificationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVerificationServiceClient(cc grpc.ClientConnInterface) VerificationServiceClient {
+	return &verificationServiceClient{cc}
+}
+
+func (c *verificationServiceClient) VerifyRef(ctx context.Context, in *VerifyRefRequest, opts ...grpc.CallOption) (*VerifyRefResponse, error) {
+	out := new(VerifyRefResponse)
+	err := c.cc.Invoke(ctx, VerificationService_VerifyRef_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *verificationServiceClient) GetPolicy(ctx context.Context, in *GetPolicyRequest, opts ...grpc.CallOption) (*GetPolicyResponse, error) {
+	out := new(GetPolicyResponse)
+	err := c.cc.Invoke(ctx, VerificationService_GetPolicy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *verificationServiceClient) ListRSLEntries(ctx context.Context, in *ListRSLEntriesRequest, opts ...grpc.CallOption) (*ListRSLEntriesResponse, error) {
+	out := new(ListRSLEntriesResponse)
+	err := c.cc.Invoke(ctx, VerificationService_ListRSLEntries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *verificationServiceClient) GetAttestations(ctx context.Context, in *GetAttestationsRequest, opts ...grpc.CallOption) (*GetAttestationsResponse, error) {
+	out := new(GetAttestationsResponse)
+	err := c.cc.Invoke(ctx, VerificationService_GetAttestations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VerificationServiceServer is the server API for VerificationService service.
+// All implementations must embed UnimplementedVerificationServiceServer
+// for forward compatibility
+type VerificationServiceServer interface {
+	// VerifyRef verifies a ref against the repository's policy. A failed
+	// verification is reported in the response rather than as an error.
+	VerifyRef(context.Context, *VerifyRefRequest) (*VerifyRefResponse, error)
+	// GetPolicy returns the roles and rules of the repository's policy.
+	GetPolicy(context.Context, *GetPolicyRequest) (*GetPolicyResponse, error)
+	// ListRSLEntries returns the repository's RSL entries, starting with the
+	// latest.
+	ListRSLEntries(context.Context, *ListRSLEntriesRequest) (*ListRSLEntriesResponse, error)
+	// GetAttestations returns the repository's current attestations.
+	GetAttestations(context.Context, *GetAttestationsRequest) (*GetAttestationsResponse, error)
+	mustEmbedUnimplementedVerificationServiceServer()
+}
+
+// UnimplementedVerificationServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedVerificationServiceServer struct {
+}
+
+func (UnimplementedVerificationServiceServer) VerifyRef(context.Context, *VerifyRefRequest) (*VerifyRefResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyRef not implemented")
+}
+func (UnimplementedVerificationServiceServer) GetPolicy(context.Context, *GetPolicyRequest) (*GetPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPolicy not implemented")
+}
+func (UnimplementedVerificationServiceServer) ListRSLEntries(context.Context, *ListRSLEntriesRequest) (*ListRSLEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRSLEntries not implemented")
+}
+func (UnimplementedVerificationServiceServer) GetAttestations(context.Context, *GetAttestationsRequest) (*GetAttestationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAttestations not implemented")
+}
+func (UnimplementedVerificationServiceServer) mustEmbedUnimplementedVerificationServiceServer() {}
+
+// UnsafeVerificationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VerificationServiceServer will
+// result in compilation errors.
+type UnsafeVerificationServiceServer interface {
+	mustEmbedUnimplementedVerificationServiceServer()
+}
+
+func RegisterVerificationServiceServer(s grpc.ServiceRegistrar, srv VerificationServiceServer) {
+	s.RegisterService(&VerificationService_ServiceDesc, srv)
+}
+
+func _VerificationService_VerifyRef_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRefRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServiceServer).VerifyRef(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VerificationService_VerifyRef_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServiceServer).VerifyRef(ctx, req.(*VerifyRefRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VerificationService_GetPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServiceServer).GetPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VerificationService_GetPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServiceServer).GetPolicy(ctx, req.(*GetPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VerificationService_ListRSLEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRSLEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServiceServer).ListRSLEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VerificationService_ListRSLEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServiceServer).ListRSLEntries(ctx, req.(*ListRSLEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VerificationService_GetAttestations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAttestationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServiceServer).GetAttestations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VerificationService_GetAttestations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServiceServer).GetAttestations(ctx, req.(*GetAttestationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VerificationService_ServiceDesc is the grpc.ServiceDesc for VerificationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VerificationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gittuf.v1.VerificationService",
+	HandlerType: (*VerificationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "VerifyRef",
+			Handler:    _VerificationService_VerifyRef_Handler,
+		},
+		{
+			MethodName: "GetPolicy",
+			Handler:    _VerificationService_GetPolicy_Handler,
+		},
+		{
+			MethodName: "ListRSLEntries",
+			Handler:    _VerificationService_ListRSLEntries_Handler,
+		},
+		{
+			MethodName: "GetAttestations",
+			Handler:    _VerificationService_GetAttestations_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gittuf/v1/verification.proto",
+}
@@ -0,0 +1,574 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifyservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/rsl"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// logger logs the messages of the verifyservice subsystem.
+var logger = logging.For("verifyservice")
+
+const (
+	// maxReports is the number of verification reports the service retains.
+	// Once exceeded, the oldest reports are discarded.
+	maxReports = 1000
+
+	// maxRequestSize is the maximum size of the request bodies accepted.
+	maxRequestSize = 1 << 20
+
+	// maxPayloadSize is the maximum size of the admission reviews accepted,
+	// which include the objects being admitted.
+	maxPayloadSize = 25 << 20
+
+	policyRef = "policy"
+)
+
+var (
+	ErrNoRepositories         = errors.New("at least one repository must be configured")
+	ErrInvalidRepositoryName  = errors.New("invalid repository name")
+	ErrDuplicateRepository    = errors.New("repository is configured more than once")
+	ErrInvalidRepositorySpec  = errors.New("repository must be specified as <name>=<path or URL>")
+	errRepositoryNotFound     = errors.New("repository not found")
+	errReportNotFound         = errors.New("report not found")
+	errMissingRef             = errors.New("ref must be set")
+	errUnauthorized           = errors.New("missing or invalid bearer token")
+	errInvalidVerifyRequest   = errors.New("invalid verification request")
+	errUnableToSyncRepository = errors.New("unable to fetch repository")
+)
+
+var (
+	syncRepository   = gitinterface.SyncMirror
+	verifyRepository = verifyRef
+	loadPolicyState  = getPolicyState
+	listRSLEntries   = getRSLEntries
+	listAttestations = getAttestations
+)
+
+// Repository is a repository the service verifies.
+type Repository struct {
+	// Name identifies the repository in the API.
+	Name string
+
+	// Location is the path to the repository on disk or the URL of a remote
+	// repository. Remote repositories are mirrored before each verification.
+	Location string
+}
+
+// ParseRepository parses a repository specified as <name>=<path or URL>.
+func ParseRepository(spec string) (*Repository, error) {
+	name, location, found := strings.Cut(spec, "=")
+	if !found || name == "" || location == "" {
+		return nil, fmt.Errorf("%w, got '%s'", ErrInvalidRepositorySpec, spec)
+	}
+
+	return &Repository{Name: name, Location: location}, nil
+}
+
+// Config contains the settings of the service.
+type Config struct {
+	// Repositories are the repositories the service verifies. Only these
+	// repositories can be verified, so that the service cannot be used to
+	// fetch arbitrary URLs.
+	Repositories []*Repository
+
+	// Token is the bearer token clients must present. If empty, requests are
+	// not authenticated.
+	Token string
+
+	// CacheDir is the directory remote repositories are mirrored to.
+	CacheDir string
+
+	// Metrics is the registry the service's verification metrics are
+	// registered with. If nil, metrics are not recorded.
+	Metrics *metrics.Registry
+}
+
+// VerifyRequest is the body of a verification request.
+type VerifyRequest struct {
+	Ref        string `json:"ref"`
+	LatestOnly bool   `json:"latest_only,omitempty"`
+}
+
+// Report is the result of verifying a ref.
+type Report struct {
+	ID         string    `json:"id"`
+	Repository string    `json:"repository"`
+	Ref        string    `json:"ref"`
+	LatestOnly bool      `json:"latest_only"`
+	Verified   bool      `json:"verified"`
+	Error      string    `json:"error,omitempty"`
+	Time       time.Time `json:"time"`
+
+	// Explanation contains the RSL entries that failed verification, if
+	// any.
+	Explanation []*policy.EntryExplanation `json:"explanation,omitempty"`
+}
+
+// PolicyState describes the policy applied in a repository.
+type PolicyState struct {
+	Roles []*PolicyRole `json:"roles"`
+	Rules []*PolicyRule `json:"rules"`
+}
+
+// PolicyRole describes a top level role in the policy.
+type PolicyRole struct {
+	Name      string     `json:"name"`
+	KeyIDs    []string   `json:"keyids"`
+	Threshold int        `json:"threshold"`
+	Expires   *time.Time `json:"expires,omitempty"`
+}
+
+// PolicyRule describes a rule in the policy. Depth is the rule's delegation
+// depth, with zero for rules declared in the primary rule file.
+type PolicyRule struct {
+	Name      string   `json:"name"`
+	Paths     []string `json:"paths"`
+	KeyIDs    []string `json:"keyids"`
+	Threshold int      `json:"threshold"`
+	Depth     int      `json:"depth"`
+}
+
+// Service exposes an HTTP API to verify refs in the configured repositories,
+// query their policies, and retrieve the reports of earlier verifications.
+//
+//	GET  /v1/repositories                 lists the configured repositories
+//	POST /v1/repositories/{name}/verify   verifies a ref and returns the report
+//	GET  /v1/repositories/{name}/policy   returns the repository's policy
+//	GET  /v1/repositories/{name}/source-status
+//	                                      checks a GitOps source revision
+//	GET  /v1/reports                      lists the retained reports
+//	GET  /v1/reports/{id}                 returns a report
+//	POST /v1/admission                    reviews Kubernetes admissions
+//
+// The service can also be exposed over gRPC using NewGRPCServer.
+type Service struct {
+	repositories map[string]*Repository
+	token        string
+	cacheDir     string
+	mux          *http.ServeMux
+
+	verifications *metrics.Verifications
+
+	// repositoryLocks serializes operations on the same repository, as
+	// remote repositories share a mirror.
+	repositoryLocks map[string]*sync.Mutex
+
+	reports     map[string]*Report
+	reportOrder []string
+	mu          sync.Mutex
+}
+
+// NewService returns a Service for the specified configuration.
+func NewService(config *Config) (*Service, error) {
+	if len(config.Repositories) == 0 {
+		return nil, ErrNoRepositories
+	}
+
+	repositories := map[string]*Repository{}
+	for _, repo := range config.Repositories {
+		if repo.Name == "" || repo.Name != url.PathEscape(repo.Name) || repo.Name == "." || repo.Name == ".." {
+			return nil, fmt.Errorf("%w '%s'", ErrInvalidRepositoryName, repo.Name)
+		}
+		if _, has := repositories[repo.Name]; has {
+			return nil, fmt.Errorf("%w: '%s'", ErrDuplicateRepository, repo.Name)
+		}
+		repositories[repo.Name] = repo
+	}
+
+	s := &Service{
+		repositories:    repositories,
+		token:           config.Token,
+		cacheDir:        config.CacheDir,
+		verifications:   metrics.NewVerifications(config.Metrics),
+		repositoryLocks: map[string]*sync.Mutex{},
+		reports:         map[string]*Report{},
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /v1/repositories", s.handleListRepositories)
+	s.mux.HandleFunc("POST /v1/repositories/{name}/verify", s.handleVerify)
+	s.mux.HandleFunc("GET /v1/repositories/{name}/policy", s.handlePolicy)
+	s.mux.HandleFunc("GET /v1/repositories/{name}/source-status", s.handleSourceStatus)
+	s.mux.HandleFunc("GET /v1/reports", s.handleListReports)
+	s.mux.HandleFunc("GET /v1/reports/{id}", s.handleGetReport)
+	s.mux.HandleFunc("POST /v1/admission", s.handleAdmissionReview)
+
+	return s, nil
+}
+
+// ServeHTTP authenticates the request if a token is configured, and routes it
+// to the matching endpoint.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r.Header.Get("Authorization")) {
+		writeError(w, http.StatusUnauthorized, errUnauthorized)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized returns true if no token is configured, or if the authorization
+// header presents the configured token as a bearer token.
+func (s *Service) authorized(authorization string) bool {
+	if s.token == "" {
+		return true
+	}
+
+	token, found := strings.CutPrefix(authorization, "Bearer ")
+	return found && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// Verify verifies the ref in the named repository, retains the report, and
+// returns it. A failed verification is recorded in the report rather than
+// returned as an error.
+func (s *Service) Verify(ctx context.Context, name, refName string, latestOnly bool) (*Report, error) {
+	if refName == "" {
+		return nil, errMissingRef
+	}
+
+	path, unlock, err := s.prepareRepository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return s.verifyAt(ctx, name, path, refName, latestOnly)
+}
+
+// verifyAt verifies the ref in the named repository, which has been prepared
+// at the specified path, and retains the report.
+func (s *Service) verifyAt(ctx context.Context, name, path, refName string, latestOnly bool) (*Report, error) {
+	id, err := newReportID()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug(fmt.Sprintf("Verifying '%s' in '%s'...", refName, name))
+	start := time.Now()
+	explanation, err := verifyRepository(ctx, path, refName, latestOnly)
+	s.verifications.Observe(name, time.Since(start), err)
+
+	report := &Report{
+		ID:         id,
+		Repository: name,
+		Ref:        refName,
+		LatestOnly: latestOnly,
+		Verified:   err == nil,
+		Time:       time.Now().UTC(),
+	}
+	if err != nil {
+		report.Error = err.Error()
+		if explanation != nil {
+			report.Explanation = explanation.FailedEntries
+		}
+	}
+
+	s.storeReport(report)
+	return report, nil
+}
+
+// Policy returns the state of the policy applied in the named repository.
+func (s *Service) Policy(ctx context.Context, name string) (*PolicyState, error) {
+	path, unlock, err := s.prepareRepository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return loadPolicyState(ctx, path)
+}
+
+// RSLEntries returns up to limit entries of the named repository's RSL,
+// starting with the latest one. All entries are returned if limit is not
+// positive.
+func (s *Service) RSLEntries(ctx context.Context, name string, limit int) ([]rsl.Entry, error) {
+	path, unlock, err := s.prepareRepository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return listRSLEntries(path, limit)
+}
+
+// Attestations returns the current attestations of the named repository,
+// keyed by their paths in the attestations namespace.
+func (s *Service) Attestations(ctx context.Context, name string) (map[string]*sslibdsse.Envelope, error) {
+	path, unlock, err := s.prepareRepository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return listAttestations(path)
+}
+
+// Report returns the retained report with the specified ID.
+func (s *Service) Report(id string) (*Report, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report, has := s.reports[id]
+	if !has {
+		return nil, errReportNotFound
+	}
+
+	return report, nil
+}
+
+// Reports returns the retained reports, optionally filtered by repository and
+// ref, starting with the latest.
+func (s *Service) Reports(name, refName string) []*Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reports := []*Report{}
+	for i := len(s.reportOrder) - 1; i >= 0; i-- {
+		report := s.reports[s.reportOrder[i]]
+		if (name == "" || report.Repository == name) && (refName == "" || report.Ref == refName) {
+			reports = append(reports, report)
+		}
+	}
+
+	return reports
+}
+
+func (s *Service) handleListRepositories(w http.ResponseWriter, _ *http.Request) {
+	names := make([]string, 0, len(s.repositories))
+	for name := range s.repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writeJSON(w, http.StatusOK, map[string][]string{"repositories": names})
+}
+
+func (s *Service) handleVerify(w http.ResponseWriter, r *http.Request) {
+	request := &VerifyRequest{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(request); err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidVerifyRequest)
+		return
+	}
+
+	report, err := s.Verify(r.Context(), r.PathValue("name"), request.Ref, request.LatestOnly)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *Service) handlePolicy(w http.ResponseWriter, r *http.Request) {
+	state, err := s.Policy(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, state)
+}
+
+func (s *Service) handleListReports(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	writeJSON(w, http.StatusOK, map[string][]*Report{"reports": s.Reports(query.Get("repository"), query.Get("ref"))})
+}
+
+func (s *Service) handleGetReport(w http.ResponseWriter, r *http.Request) {
+	report, err := s.Report(r.PathValue("id"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// prepareRepository returns the path to the named repository, after updating
+// its mirror if it's a remote repository. The returned function must be called
+// once the caller is done with the repository.
+func (s *Service) prepareRepository(ctx context.Context, name string) (string, func(), error) {
+	repo, has := s.repositories[name]
+	if !has {
+		return "", nil, errRepositoryNotFound
+	}
+
+	lock := s.repositoryLock(name)
+	lock.Lock()
+
+	if !gitinterface.IsRemoteLocation(repo.Location) {
+		return repo.Location, lock.Unlock, nil
+	}
+
+	logger.Debug(fmt.Sprintf("Fetching '%s'...", name))
+	path := filepath.Join(s.cacheDir, name+".git")
+	// The user's Git credentials are not used, so remote repositories must be
+	// accessible anonymously
+	if err := syncRepository(ctx, path, repo.Location, nil); err != nil {
+		lock.Unlock()
+		s.verifications.ObserveSyncFailure(name)
+		logger.Error("Unable to fetch repository", "repository", name, "error", err)
+		return "", nil, errUnableToSyncRepository
+	}
+
+	return path, lock.Unlock, nil
+}
+
+func (s *Service) storeReport(report *Report) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reports[report.ID] = report
+	s.reportOrder = append(s.reportOrder, report.ID)
+	for len(s.reportOrder) > maxReports {
+		delete(s.reports, s.reportOrder[0])
+		s.reportOrder = s.reportOrder[1:]
+	}
+}
+
+func (s *Service) repositoryLock(name string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, has := s.repositoryLocks[name]
+	if !has {
+		lock = &sync.Mutex{}
+		s.repositoryLocks[name] = lock
+	}
+
+	return lock
+}
+
+// errorStatus returns the HTTP status code for errors returned by the service.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, errRepositoryNotFound), errors.Is(err, errReportNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errMissingRef):
+		return http.StatusBadRequest
+	case errors.Is(err, errUnableToSyncRepository):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		logger.Error("Unable to write response", "error", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func newReportID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// verifyRef verifies the ref in the repository at the specified path against
+// the repository's gittuf policy, returning an explanation of the RSL entries
+// that failed verification.
+func verifyRef(ctx context.Context, path, refName string, latestOnly bool) (*policy.Explanation, error) {
+	repo, err := repository.LoadRepositoryAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &policy.Explanation{}
+	ctx = policy.ContextWithExplanation(ctx, explanation)
+
+	return explanation, repo.VerifyRef(ctx, refName, latestOnly)
+}
+
+// getPolicyState returns the roles and rules of the policy applied in the
+// repository at the specified path.
+func getPolicyState(ctx context.Context, path string) (*PolicyState, error) {
+	repo, err := repository.LoadRepositoryAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := repo.ListPolicyRoles(ctx, policyRef)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := repo.ListRules(ctx, policyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &PolicyState{Roles: []*PolicyRole{}, Rules: []*PolicyRule{}}
+	for _, role := range roles {
+		policyRole := &PolicyRole{Name: role.Name, KeyIDs: role.KeyIDs, Threshold: role.Threshold}
+		if !role.Expires.IsZero() {
+			expires := role.Expires
+			policyRole.Expires = &expires
+		}
+		state.Roles = append(state.Roles, policyRole)
+	}
+	for _, rule := range rules {
+		state.Rules = append(state.Rules, &PolicyRule{
+			Name:      rule.Delegation.Name,
+			Paths:     rule.Delegation.Paths,
+			KeyIDs:    rule.Delegation.KeyIDs,
+			Threshold: rule.Delegation.Threshold,
+			Depth:     rule.Depth,
+		})
+	}
+
+	return state, nil
+}
+
+// getRSLEntries returns up to limit entries of the RSL of the repository at the
+// specified path.
+func getRSLEntries(path string, limit int) ([]rsl.Entry, error) {
+	repo, err := repository.LoadRepositoryAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.ListRSLEntries(limit)
+}
+
+// getAttestations returns the current attestations of the repository at the
+// specified path.
+func getAttestations(path string) (map[string]*sslibdsse.Envelope, error) {
+	repo, err := repository.LoadRepositoryAt(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.ListAttestations()
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package verifyservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+const testToken = "api-token"
+
+func newTestService(t *testing.T, token string) *Service {
+	t.Helper()
+
+	service, err := NewService(&Config{
+		Repositories: []*Repository{
+			{Name: "local", Location: "/srv/git/local.git"},
+			{Name: "remote", Location: "https://git.example.com/remote.git"},
+		},
+		Token:    token,
+		CacheDir: "/cache",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return service
+}
+
+func doRequest(t *testing.T, service *Service, method, target, body string, response any) int {
+	t.Helper()
+
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer "+testToken)
+	recorder := httptest.NewRecorder()
+	service.ServeHTTP(recorder, request)
+
+	if response != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return recorder.Code
+}
+
+func TestService(t *testing.T) {
+	synced := []string{}
+	syncRepository = func(_ context.Context, path, remoteURL string, _ transport.AuthMethod) error {
+		assert.Equal(t, "/cache/remote.git", path)
+		synced = append(synced, remoteURL)
+		return nil
+	}
+	verifyRepository = func(_ context.Context, path, refName string, _ bool) (*policy.Explanation, error) {
+		if refName == "refs/heads/main" {
+			return &policy.Explanation{}, nil
+		}
+		return &policy.Explanation{FailedEntries: []*policy.EntryExplanation{{RefName: refName, Error: "unauthorized"}}}, errors.New("verifying " + path + " failed")
+	}
+	loadPolicyState = func(_ context.Context, path string) (*PolicyState, error) {
+		return &PolicyState{Rules: []*PolicyRule{{Name: path}}}, nil
+	}
+
+	t.Run("verify and retrieve reports", func(t *testing.T) {
+		service := newTestService(t, testToken)
+
+		report := &Report{}
+		status := doRequest(t, service, http.MethodPost, "/v1/repositories/local/verify", `{"ref": "refs/heads/main"}`, report)
+		assert.Equal(t, http.StatusOK, status)
+		assert.True(t, report.Verified)
+		assert.Equal(t, "local", report.Repository)
+		assert.Empty(t, synced)
+
+		failedReport := &Report{}
+		status = doRequest(t, service, http.MethodPost, "/v1/repositories/remote/verify", `{"ref": "refs/heads/feature"}`, failedReport)
+		assert.Equal(t, http.StatusOK, status)
+		assert.False(t, failedReport.Verified)
+		assert.Equal(t, "verifying /cache/remote.git failed", failedReport.Error)
+		assert.Len(t, failedReport.Explanation, 1)
+		assert.Equal(t, []string{"https://git.example.com/remote.git"}, synced)
+
+		retrieved := &Report{}
+		status = doRequest(t, service, http.MethodGet, "/v1/reports/"+report.ID, "", retrieved)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, report.ID, retrieved.ID)
+
+		reports := map[string][]*Report{}
+		status = doRequest(t, service, http.MethodGet, "/v1/reports", "", &reports)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Len(t, reports["reports"], 2)
+		assert.Equal(t, failedReport.ID, reports["reports"][0].ID)
+
+		reports = map[string][]*Report{}
+		status = doRequest(t, service, http.MethodGet, "/v1/reports?repository=local", "", &reports)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Len(t, reports["reports"], 1)
+
+		status = doRequest(t, service, http.MethodGet, "/v1/reports/unknown", "", nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		service := newTestService(t, testToken)
+
+		state := &PolicyState{}
+		status := doRequest(t, service, http.MethodGet, "/v1/repositories/local/policy", "", state)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "/srv/git/local.git", state.Rules[0].Name)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		service := newTestService(t, testToken)
+
+		status := doRequest(t, service, http.MethodPost, "/v1/repositories/unknown/verify", `{"ref": "refs/heads/main"}`, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+
+		status = doRequest(t, service, http.MethodPost, "/v1/repositories/local/verify", `{}`, nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status = doRequest(t, service, http.MethodPost, "/v1/repositories/local/verify", `not json`, nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		service := newTestService(t, "other-token")
+
+		status := doRequest(t, service, http.MethodGet, "/v1/repositories", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("failed sync", func(t *testing.T) {
+		service := newTestService(t, testToken)
+		syncRepository = func(_ context.Context, _, _ string, _ transport.AuthMethod) error {
+			return errors.New("connection refused")
+		}
+
+		status := doRequest(t, service, http.MethodPost, "/v1/repositories/remote/verify", `{"ref": "refs/heads/main"}`, nil)
+		assert.Equal(t, http.StatusBadGateway, status)
+		assert.Empty(t, service.Reports("", ""))
+	})
+}
+
+func TestNewService(t *testing.T) {
+	_, err := NewService(&Config{})
+	assert.ErrorIs(t, err, ErrNoRepositories)
+
+	_, err = NewService(&Config{Repositories: []*Repository{{Name: "a/b", Location: "."}}})
+	assert.ErrorIs(t, err, ErrInvalidRepositoryName)
+
+	_, err = NewService(&Config{Repositories: []*Repository{{Name: "a", Location: "."}, {Name: "a", Location: ".."}}})
+	assert.ErrorIs(t, err, ErrDuplicateRepository)
+}
+
+func TestParseRepository(t *testing.T) {
+	repo, err := ParseRepository("gittuf=https://github.com/gittuf/gittuf")
+	assert.Nil(t, err)
+	assert.Equal(t, &Repository{Name: "gittuf", Location: "https://github.com/gittuf/gittuf"}, repo)
+
+	_, err = ParseRepository("gittuf")
+	assert.ErrorIs(t, err, ErrInvalidRepositorySpec)
+}
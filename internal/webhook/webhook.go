@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webhook implements the verification of pushes shared by gittuf's
+// webhook services for repository hosts, such as the GitHub App and the GitLab
+// and Gitea services. The services learn of pushes from the hosts' webhooks,
+// and report the results using the hosts' APIs.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/metrics"
+	"github.com/gittuf/gittuf/internal/repository"
+)
+
+// logger logs the messages of the webhook subsystem.
+var logger = logging.For("webhook")
+
+var ErrSyncFailed = errors.New("unable to fetch repository")
+
+// SyncFunc updates the mirror of a repository at the specified path.
+type SyncFunc func(ctx context.Context, path string) error
+
+// VerifyFunc verifies the ref in the repository at the specified path.
+type VerifyFunc func(ctx context.Context, path, refName string) error
+
+// Verifier verifies the refs pushed to repositories. Each repository is
+// mirrored in a cache directory before its refs are verified. Pushes can be
+// acknowledged immediately and verified in the background, as verification
+// may take longer than repository hosts wait for a response to a webhook
+// delivery.
+type Verifier struct {
+	cacheDir      string
+	verify        VerifyFunc
+	verifications *metrics.Verifications
+
+	// mirrorLocks serializes the verification of pushes to the same
+	// repository, as they share a mirror.
+	mirrorLocks map[string]*sync.Mutex
+	mu          sync.Mutex
+	pending     sync.WaitGroup
+}
+
+// NewVerifier returns a Verifier that mirrors repositories in cacheDir and
+// verifies their refs using verify, typically VerifyRef. Verifications are
+// recorded in the registry's metrics, unless it's nil.
+func NewVerifier(cacheDir string, registry *metrics.Registry, verify VerifyFunc) *Verifier {
+	return &Verifier{
+		cacheDir:      cacheDir,
+		verify:        verify,
+		verifications: metrics.NewVerifications(registry),
+		mirrorLocks:   map[string]*sync.Mutex{},
+	}
+}
+
+// Go handles the push to the ref of the repository in the background. Errors
+// are logged rather than returned, as the push was already acknowledged.
+func (v *Verifier) Go(repositoryName, refName string, handle func(ctx context.Context) error) {
+	v.pending.Add(1)
+	go func() {
+		defer v.pending.Done()
+
+		if err := handle(context.Background()); err != nil {
+			logger.Error("Unable to verify push", "ref", refName, "repository", repositoryName, "error", err)
+		}
+	}()
+}
+
+// Wait blocks until the handling of all pushes started using Go completes.
+func (v *Verifier) Wait() {
+	v.pending.Wait()
+}
+
+// VerifyRef updates the repository's mirror at the path relative to the cache
+// directory using sync, and verifies the ref in it. If the mirror cannot be
+// updated, the returned error wraps ErrSyncFailed. Otherwise, the error
+// returned by verification is returned.
+func (v *Verifier) VerifyRef(ctx context.Context, repositoryName, mirrorPath, refName string, sync SyncFunc) error {
+	lock := v.mirrorLock(mirrorPath)
+	lock.Lock()
+	defer lock.Unlock()
+
+	logger.Debug(fmt.Sprintf("Fetching '%s'...", repositoryName))
+	repoPath := filepath.Join(v.cacheDir, mirrorPath)
+	if err := sync(ctx, repoPath); err != nil {
+		v.verifications.ObserveSyncFailure(repositoryName)
+		return errors.Join(ErrSyncFailed, err)
+	}
+
+	logger.Debug(fmt.Sprintf("Verifying '%s' in '%s'...", refName, repositoryName))
+	start := time.Now()
+	err := v.verify(ctx, repoPath, refName)
+	v.verifications.Observe(repositoryName, time.Since(start), err)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Verification of '%s' in '%s' failed: %s", refName, repositoryName, err.Error()))
+	}
+
+	return err
+}
+
+func (v *Verifier) mirrorLock(mirrorPath string) *sync.Mutex {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	lock, has := v.mirrorLocks[mirrorPath]
+	if !has {
+		lock = &sync.Mutex{}
+		v.mirrorLocks[mirrorPath] = lock
+	}
+
+	return lock
+}
+
+// VerifyRef verifies the ref in the repository at the specified path against
+// the repository's gittuf policy.
+func VerifyRef(ctx context.Context, path, refName string) error {
+	repo, err := repository.LoadRepositoryAt(path)
+	if err != nil {
+		return err
+	}
+
+	return repo.VerifyRef(ctx, refName, false)
+}
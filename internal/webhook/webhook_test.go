@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifier(t *testing.T) {
+	errTest := errors.New("test error")
+
+	var verifyErr error
+	verifiedPaths := []string{}
+	verify := func(_ context.Context, path, refName string) error {
+		assert.Equal(t, "refs/heads/main", refName)
+		verifiedPaths = append(verifiedPaths, path)
+		return verifyErr
+	}
+
+	t.Run("verified ref", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		verifier := NewVerifier(cacheDir, nil, verify)
+		verifiedPaths = []string{}
+		verifyErr = nil
+
+		syncedPath := ""
+		err := verifier.VerifyRef(context.Background(), "owner/repo", "owner/repo.git", "refs/heads/main", func(_ context.Context, path string) error {
+			syncedPath = path
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, filepath.Join(cacheDir, "owner/repo.git"), syncedPath)
+		assert.Equal(t, []string{syncedPath}, verifiedPaths)
+	})
+
+	t.Run("unverified ref", func(t *testing.T) {
+		verifier := NewVerifier(t.TempDir(), nil, verify)
+		verifyErr = errTest
+
+		err := verifier.VerifyRef(context.Background(), "owner/repo", "owner/repo.git", "refs/heads/main", func(_ context.Context, _ string) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, errTest)
+		assert.NotErrorIs(t, err, ErrSyncFailed)
+	})
+
+	t.Run("unable to fetch", func(t *testing.T) {
+		verifier := NewVerifier(t.TempDir(), nil, verify)
+		verifiedPaths = []string{}
+
+		err := verifier.VerifyRef(context.Background(), "owner/repo", "owner/repo.git", "refs/heads/main", func(_ context.Context, _ string) error {
+			return errTest
+		})
+		assert.ErrorIs(t, err, ErrSyncFailed)
+		assert.ErrorIs(t, err, errTest)
+		assert.Empty(t, verifiedPaths)
+	})
+
+	t.Run("background verification", func(t *testing.T) {
+		verifier := NewVerifier(t.TempDir(), nil, verify)
+
+		handled := 0
+		for i := 0; i < 3; i++ {
+			verifier.Go("owner/repo", "refs/heads/main", func(_ context.Context) error {
+				handled++
+				return errTest
+			})
+			verifier.Wait()
+		}
+		assert.Equal(t, 3, handled)
+	})
+}
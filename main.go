@@ -3,12 +3,18 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"runtime/debug"
 
+	"github.com/gittuf/gittuf/internal/cmd/plugin"
 	"github.com/gittuf/gittuf/internal/cmd/profile"
 	"github.com/gittuf/gittuf/internal/cmd/root"
+	"github.com/gittuf/gittuf/internal/i18n"
+	"github.com/gittuf/gittuf/internal/interactive"
+	"github.com/gittuf/gittuf/internal/timing"
 )
 
 func main() {
@@ -27,7 +33,40 @@ func main() {
 	}()
 
 	rootCmd := root.New()
-	if err := rootCmd.Execute(); err != nil {
+
+	// Invoke gittuf-<name> on the PATH for `gittuf <name>` if <name> isn't a
+	// built-in command
+	if path, ok := plugin.Find(rootCmd, os.Args[1:]); ok {
+		if err := plugin.Run(path, os.Args[2:]); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode()) //nolint:gocritic
+			}
+
+			fmt.Fprintf(os.Stderr, "%s %s\n", i18n.T("Error:"), i18n.TranslateError(err))
+			os.Exit(1) //nolint:gocritic
+		}
+		return
+	}
+
+	err := rootCmd.Execute()
+	if err != nil {
+		// Errors are printed here rather than by cobra so that they're
+		// translated
+		fmt.Fprintf(os.Stderr, "%s %s\n", i18n.T("Error:"), i18n.TranslateError(err))
+	}
+
+	// Report timing even if the command failed, as failed verifications are
+	// worth profiling too
+	if reportErr := timing.Report(os.Stderr); reportErr != nil {
+		fmt.Fprintf(os.Stderr, "unexpected timing error: %s\n", reportErr.Error())
+	}
+
+	if err != nil {
+		if errors.Is(err, interactive.ErrInteractionRequired) {
+			os.Exit(interactive.ExitCode) //nolint:gocritic
+		}
+
 		// We can ignore the linter here (deferred functions are not executed
 		// when os.Exit is invoked) because if we do have an error, we don't
 		// have a panic, which is what the deferred function is looking for.